@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var (
+	artifactDescription = `Manage OCI artifacts that are not container images, such as models,
+   WASM modules or configuration bundles.  Artifacts are made available to
+   containers with --mount type=artifact.`
+	artifactSubCommands = []cli.Command{
+		artifactInspectCommand,
+		artifactListCommand,
+		artifactPullCommand,
+		artifactRmCommand,
+	}
+	artifactCommand = cli.Command{
+		Name:                   "artifact",
+		Usage:                  "Manage OCI artifacts",
+		Description:            artifactDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            artifactSubCommands,
+	}
+)
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/artifact"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// artifactInspectData is the output of podman artifact inspect
+type artifactInspectData struct {
+	*artifact.Artifact
+	ID    string          `json:"id"`
+	Files []artifact.File `json:"files"`
+}
+
+var (
+	artifactInspectDescription = `Displays the manifest of an artifact, and the files its layers are
+   mounted as.`
+	artifactInspectCommand = cli.Command{
+		Name:        "inspect",
+		Usage:       "Display information about an artifact",
+		Description: artifactInspectDescription,
+		Action:      artifactInspectCmd,
+		ArgsUsage:   "ARTIFACT",
+	}
+)
+
+func artifactInspectCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.Errorf("you must provide exactly one artifact name or ID")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ArtifactStore()
+	if err != nil {
+		return err
+	}
+	a, err := store.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	files, err := store.Files(a)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(artifactInspectData{Artifact: a, ID: a.ID(), Files: files}, "", "     ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
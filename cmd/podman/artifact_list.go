@@ -0,0 +1,152 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/artifact"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// artifactListTemplateParams stores info about each artifact
+type artifactListTemplateParams struct {
+	ID    string
+	Name  string
+	Type  string
+	Files int
+	Size  string
+}
+
+var (
+	artifactListFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "Change the output to JSON or a Go template",
+		},
+		cli.BoolFlag{
+			Name:  "noheading, n",
+			Usage: "Do not print column headings",
+		},
+		cli.BoolFlag{
+			Name:  "no-trunc, notruncate",
+			Usage: "Do not truncate the output",
+		},
+		cli.BoolFlag{
+			Name:  "quiet, q",
+			Usage: "Display only artifact IDs",
+		},
+	}
+	artifactListDescription = "Lists the artifacts in local storage."
+	artifactListCommand     = cli.Command{
+		Name:                   "ls",
+		Aliases:                []string{"list"},
+		Usage:                  "List artifacts",
+		Description:            artifactListDescription,
+		Flags:                  artifactListFlags,
+		Action:                 artifactListCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func artifactListCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman artifact ls does not take any arguments")
+	}
+	if err := validateFlags(c, artifactListFlags); err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ArtifactStore()
+	if err != nil {
+		return err
+	}
+	artifacts, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	var out formats.Writer
+	format := genArtifactListFormat(c.String("format"), c.Bool("quiet"), c.Bool("noheading"))
+	if format == formats.JSONString {
+		out = formats.JSONStructArray{Output: artifactsToGeneric(artifacts)}
+	} else {
+		params := getArtifactListTemplateOutput(artifacts, c.Bool("no-trunc"))
+		out = formats.StdoutTemplateArray{Output: artifactParamsToGeneric(params), Template: format, Fields: params[0].headerMap()}
+	}
+	return formats.Writer(out).Out()
+}
+
+func genArtifactListFormat(format string, quiet, noHeading bool) string {
+	if format != "" {
+		// "\t" from the command line is not being recognized as a tab
+		// replacing the string "\t" to a tab character if the user passes in "\t"
+		return strings.Replace(format, `\t`, "\t", -1)
+	}
+	if quiet {
+		return formats.IDString
+	}
+	format = "{{.ID}}\t{{.Name}}\t{{.Type}}\t{{.Files}}\t{{.Size}}\t"
+	if noHeading {
+		return format
+	}
+	return "table " + format
+}
+
+// getArtifactListTemplateOutput returns the artifacts in the format of the
+// default table
+func getArtifactListTemplateOutput(artifacts []*artifact.Artifact, noTrunc bool) []artifactListTemplateParams {
+	params := make([]artifactListTemplateParams, 0, len(artifacts))
+	for _, a := range artifacts {
+		id := a.ID()
+		if !noTrunc {
+			id = shortID(id)
+		}
+		params = append(params, artifactListTemplateParams{
+			ID:    id,
+			Name:  a.Name,
+			Type:  a.Type(),
+			Files: len(a.Manifest.Layers),
+			Size:  units.HumanSizeWithPrecision(float64(a.Size()), 3),
+		})
+	}
+	return params
+}
+
+func artifactsToGeneric(artifacts []*artifact.Artifact) (genericParams []interface{}) {
+	for _, a := range artifacts {
+		genericParams = append(genericParams, interface{}(a))
+	}
+	return
+}
+
+func artifactParamsToGeneric(params []artifactListTemplateParams) (genericParams []interface{}) {
+	for _, p := range params {
+		genericParams = append(genericParams, interface{}(p))
+	}
+	return
+}
+
+// generate the header based on the template provided
+func (a *artifactListTemplateParams) headerMap() map[string]string {
+	v := reflect.Indirect(reflect.ValueOf(a))
+	values := make(map[string]string)
+	for i := 0; i < v.NumField(); i++ {
+		key := v.Type().Field(i).Name
+		values[key] = strings.ToUpper(splitCamelCase(key))
+	}
+	return values
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/containers/image/signature"
+	"github.com/containers/image/types"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	artifactPullFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "authfile",
+			Usage: "Path of the authentication file. Default is ${XDG_RUNTIME_DIR}/containers/auth.json",
+		},
+		cli.StringFlag{
+			Name:  "cert-dir",
+			Usage: "`pathname` of a directory containing TLS certificates and keys",
+		},
+		cli.StringFlag{
+			Name:  "creds",
+			Usage: "`credentials` (USERNAME:PASSWORD) to use for authenticating to a registry",
+		},
+		cli.BoolFlag{
+			Name:  "quiet, q",
+			Usage: "Suppress output information when pulling artifacts",
+		},
+		cli.StringFlag{
+			Name:  "signature-policy",
+			Usage: "`pathname` of signature policy file (not usually used)",
+		},
+		cli.BoolTFlag{
+			Name:  "tls-verify",
+			Usage: "require HTTPS and verify certificates when contacting registries (default: true)",
+		},
+	}
+	artifactPullDescription = `
+   Pulls an OCI artifact from a registry, or from another transport such as
+   oci: or dir:, and stores it locally.  Pulling an artifact with the name of
+   an artifact already stored replaces it.
+`
+	artifactPullCommand = cli.Command{
+		Name:        "pull",
+		Usage:       "Pull an OCI artifact",
+		Description: artifactPullDescription,
+		Flags:       artifactPullFlags,
+		Action:      artifactPullCmd,
+		ArgsUsage:   "ARTIFACT",
+	}
+)
+
+func artifactPullCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("an artifact name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.Errorf("too many arguments. Requires exactly 1")
+	}
+	if err := validateFlags(c, artifactPullFlags); err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ArtifactStore()
+	if err != nil {
+		return err
+	}
+
+	ref, err := image.ParseDestination(args[0], image.DefaultTransport)
+	if err != nil {
+		return err
+	}
+
+	var registryCreds *types.DockerAuthConfig
+	if c.IsSet("creds") {
+		registryCreds, err = util.ParseRegistryCreds(c.String("creds"))
+		if err != nil {
+			return err
+		}
+	}
+	dockerRegistryOptions := image.DockerRegistryOptions{
+		DockerRegistryCreds:         registryCreds,
+		DockerCertPath:              c.String("cert-dir"),
+		DockerInsecureSkipTLSVerify: !c.BoolT("tls-verify"),
+	}
+	sc := dockerRegistryOptions.GetSystemContext(image.GetSystemContext(c.String("signature-policy"), c.String("authfile"), false), nil)
+
+	policy, err := signature.DefaultPolicy(sc)
+	if err != nil {
+		return errors.Wrapf(err, "error obtaining the signature policy")
+	}
+	policyContext, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return err
+	}
+	defer policyContext.Destroy()
+
+	var writer io.Writer
+	if !c.Bool("quiet") {
+		writer = os.Stderr
+	}
+	artifact, err := store.Pull(getContext(), ref, sc, policyContext, writer)
+	if err != nil {
+		return errors.Wrapf(err, "error pulling artifact %q", args[0])
+	}
+	fmt.Println(artifact.ID())
+	return nil
+}
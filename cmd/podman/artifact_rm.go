@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	artifactRmDescription = `Removes one or more artifacts from local storage.  Blobs that no other
+   artifact refers to are removed with them.  Artifacts mounted by containers are not removed.`
+	artifactRmCommand = cli.Command{
+		Name:        "rm",
+		Usage:       "Remove one or more artifacts",
+		Description: artifactRmDescription,
+		Action:      artifactRmCmd,
+		ArgsUsage:   "ARTIFACT [ARTIFACT ...]",
+	}
+)
+
+func artifactRmCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("artifact name or ID must be specified")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ArtifactStore()
+	if err != nil {
+		return err
+	}
+
+	var lastError error
+	for _, name := range args {
+		artifact, err := store.Remove(name)
+		if err != nil {
+			if lastError != nil {
+				fmt.Fprintln(os.Stderr, lastError)
+			}
+			lastError = errors.Wrapf(err, "failed to remove artifact %q", name)
+			continue
+		}
+		fmt.Println(artifact.ID())
+	}
+	return lastError
+}
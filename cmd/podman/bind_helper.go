@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/containers/libpod/libpod/shutdown"
+	"github.com/containers/libpod/pkg/bindhelper"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	bindHelperFlags = []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "allow",
+			Usage: "Allow `USER=PORTS` to be forwarded for a user, such as alice=80,443/tcp,8000-8080 (can be repeated)",
+		},
+		cli.StringFlag{
+			Name:  "group",
+			Usage: "Only allow the members of `GROUP` to use the helper",
+			Value: bindhelper.DefaultGroup,
+		},
+		cli.StringFlag{
+			Name:  "socket",
+			Usage: "Path of the socket rootless podman connects to",
+			Value: bindhelper.DefaultSocketPath,
+		},
+	}
+	bindHelperDescription = `
+	Forwards the privileged TCP ports published by rootless containers on
+	behalf of their users, who can not bind them.
+`
+	bindHelperCommand = cli.Command{
+		Name:        "bind-helper",
+		Usage:       "Run the helper forwarding privileged ports for rootless containers",
+		Description: bindHelperDescription,
+		Flags:       bindHelperFlags,
+		Action:      bindHelperCmd,
+		ArgsUsage:   "",
+	}
+)
+
+// lookupGID returns the ID of a group given by name or ID
+func lookupGID(group string) (int, error) {
+	if gid, err := strconv.Atoi(group); err == nil {
+		return gid, nil
+	}
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return -1, errors.Wrapf(err, "cannot find group %s", group)
+	}
+	return strconv.Atoi(g.Gid)
+}
+
+// lookupUID returns the ID of a user given by name or ID
+func lookupUID(name string) (int, error) {
+	if uid, err := strconv.Atoi(name); err == nil {
+		return uid, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return -1, errors.Wrapf(err, "cannot find user %s", name)
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+// parseAllowedPorts parses the --allow flags, USER=PORTS, into the ports each
+// user is allowed to bind
+func parseAllowedPorts(allow []string) (map[int][]bindhelper.PortRange, error) {
+	allowed := make(map[int][]bindhelper.PortRange)
+	for _, entry := range allow {
+		split := strings.SplitN(entry, "=", 2)
+		if len(split) != 2 || split[0] == "" || split[1] == "" {
+			return nil, errors.Errorf("invalid --allow %q, must be USER=PORTS", entry)
+		}
+		uid, err := lookupUID(split[0])
+		if err != nil {
+			return nil, err
+		}
+		ranges, err := bindhelper.ParsePortRanges(split[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid --allow %q", entry)
+		}
+		allowed[uid] = append(allowed[uid], ranges...)
+	}
+	return allowed, nil
+}
+
+func bindHelperCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("bind-helper does not accept any arguments")
+	}
+	if err := validateFlags(c, bindHelperFlags); err != nil {
+		return err
+	}
+	if os.Geteuid() != 0 {
+		return errors.Errorf("bind-helper must run as root")
+	}
+
+	gid, err := lookupGID(c.String("group"))
+	if err != nil {
+		return errors.Wrapf(err, "the socket of the helper must be owned by a group, create it or set --group")
+	}
+	if len(c.StringSlice("allow")) == 0 {
+		return errors.Errorf("no user is allowed to forward ports, set --allow")
+	}
+	allowed, err := parseAllowedPorts(c.StringSlice("allow"))
+	if err != nil {
+		return err
+	}
+
+	// Serve until terminated
+	ctx, cancel := context.WithCancel(getContext())
+	defer cancel()
+	shutdown.Start()
+	done := make(chan struct{})
+	if err := shutdown.Register("bind-helper", func(os.Signal) error {
+		cancel()
+		<-done
+		return nil
+	}); err != nil {
+		return err
+	}
+	defer close(done)
+
+	return bindhelper.NewHelper(allowed).Serve(ctx, c.String("socket"), gid)
+}
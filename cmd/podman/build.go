@@ -1,13 +1,22 @@
 package main
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
 	"strings"
 
+	"github.com/containers/image/types"
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/manifests"
+	"github.com/containers/libpod/pkg/buildcontext"
 	"github.com/containers/libpod/pkg/rootless"
+	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/projectatomic/buildah"
 	"github.com/projectatomic/buildah/imagebuildah"
@@ -20,11 +29,21 @@ import (
 var (
 	buildDescription = "Builds an OCI or Docker image using instructions from one\n" +
 		"or more Dockerfiles and a specified build context directory."
+	buildFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "platform",
+			Usage: "Build for `OS/ARCH`, or build one image for each platform of a comma-separated list",
+		},
+		cli.StringSliceFlag{
+			Name:  "secret",
+			Usage: "Secret file mounted by RUN instructions, as `id=ID,src=PATH` (default [])",
+		},
+	}
 	buildCommand = cli.Command{
 		Name:           "build",
 		Usage:          "Build an image using instructions from Dockerfiles",
 		Description:    buildDescription,
-		Flags:          append(buildahcli.BudFlags, buildahcli.FromAndBudFlags...),
+		Flags:          append(append(buildahcli.BudFlags, buildahcli.FromAndBudFlags...), buildFlags...),
 		Action:         buildCmd,
 		ArgsUsage:      "CONTEXT-DIRECTORY | URL",
 		SkipArgReorder: true,
@@ -43,6 +62,199 @@ func getDockerfiles(files []string) []string {
 	return dockerfiles
 }
 
+// buildContextFile returns the context directory and the Containerfile of a
+// build context given to create or run containers, either a directory with a
+// Containerfile or a Dockerfile, or the path of a Containerfile in its context
+// directory
+func buildContextFile(path string) (string, string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "error determining path to %q", path)
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "invalid build context %q", path)
+	}
+	if !info.IsDir() {
+		return filepath.Dir(absPath), absPath, nil
+	}
+	for _, name := range []string{"Containerfile", "Dockerfile"} {
+		file := filepath.Join(absPath, name)
+		if _, err := os.Stat(file); err == nil {
+			return absPath, file, nil
+		}
+	}
+	return "", "", errors.Errorf("no Containerfile or Dockerfile in build context %q", path)
+}
+
+// buildEphemeralImage builds the image of a container from a build context,
+// and returns the ID of the image, which is removed with the container
+func buildEphemeralImage(c *cli.Context, runtime *libpod.Runtime, path string) (string, error) {
+	if c.Bool("rootfs") {
+		return "", errors.Errorf("--build-context and --rootfs cannot be used together")
+	}
+	if c.IsSet("image-lock") {
+		return "", errors.Errorf("--build-context and --image-lock cannot be used together")
+	}
+	contextDir, containerfile, err := buildContextFile(path)
+	if err != nil {
+		return "", err
+	}
+	options := imagebuildah.BuildOptions{
+		ContextDirectory:       contextDir,
+		PullPolicy:             imagebuildah.PullIfMissing,
+		Compression:            imagebuildah.Gzip,
+		SignaturePolicyPath:    runtime.GetConfig().SignaturePolicyPath,
+		Out:                    os.Stderr,
+		Err:                    os.Stderr,
+		ReportWriter:           os.Stderr,
+		OutputFormat:           imagebuildah.OCIv1ImageFormat,
+		CommonBuildOpts:        &buildah.CommonBuildOptions{},
+		DefaultMountsFilePath:  c.GlobalString("default-mounts-file"),
+		RemoveIntermediateCtrs: true,
+	}
+	if rootless.IsRootless() {
+		options.Isolation = buildah.IsolationOCIRootless
+	}
+	return runtime.BuildEphemeral(getContext(), options, containerfile)
+}
+
+// buildPlatform is a platform an image is built for
+type buildPlatform struct {
+	os, arch string
+}
+
+// String returns the platform as OS/ARCH
+func (p buildPlatform) String() string {
+	return p.os + "/" + p.arch
+}
+
+// parseBuildPlatforms parses the comma-separated list of OS/ARCH platforms
+// of --platform
+func parseBuildPlatforms(value string) ([]buildPlatform, error) {
+	var platforms []buildPlatform
+	seen := make(map[buildPlatform]bool)
+	for _, platform := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(platform), "/")
+		if len(parts) == 3 {
+			return nil, errors.Errorf("invalid platform %q, variants are not supported", platform)
+		}
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid platform %q, should be os/arch", platform)
+		}
+		p := buildPlatform{os: parts[0], arch: parts[1]}
+		if !seen[p] {
+			seen[p] = true
+			platforms = append(platforms, p)
+		}
+	}
+	return platforms, nil
+}
+
+// platformTag returns the name an image built for one of several platforms
+// is tagged with: the tag of name, latest by default, suffixed with the
+// platform
+func platformTag(name string, platform buildPlatform) string {
+	if name == "" {
+		return ""
+	}
+	if i := strings.LastIndex(name, ":"); i <= strings.LastIndex(name, "/") {
+		name += ":latest"
+	}
+	return fmt.Sprintf("%s-%s-%s", name, platform.os, platform.arch)
+}
+
+// parseBuildSecrets parses the secrets of --secret, id=ID,src=PATH, and
+// returns their files by ID
+func parseBuildSecrets(values []string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	for _, value := range values {
+		var id, src string
+		for _, option := range strings.Split(value, ",") {
+			kv := strings.SplitN(option, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.Errorf("invalid secret %q, options must be key=value", value)
+			}
+			switch kv[0] {
+			case "id":
+				id = kv[1]
+			case "src", "source":
+				src = kv[1]
+			case "type":
+				if kv[1] != "file" {
+					return nil, errors.Errorf("invalid secret %q, only file secrets are supported", value)
+				}
+			default:
+				return nil, errors.Errorf("invalid secret %q, unknown option %q", value, kv[0])
+			}
+		}
+		if id == "" || src == "" {
+			return nil, errors.Errorf("invalid secret %q, must be id=ID,src=PATH", value)
+		}
+		path, err := filepath.Abs(src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error determining path to secret %q", id)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, errors.Wrapf(err, "invalid secret %q", id)
+		}
+		secrets[id] = path
+	}
+	return secrets, nil
+}
+
+// buildNetNS returns the network namespace configured by slirp4netns for the
+// RUN instructions of rootless builds, which can not use CNI networks, or nil
+// if the --network of the build needs none
+func buildNetNS(c *cli.Context, runtime *libpod.Runtime) (*libpod.BuildNetNS, error) {
+	network := c.String("network")
+	_, networkOptions := cc.ParseNetworkMode(network)
+	if !rootless.IsRootless() {
+		if networkOptions != nil {
+			return nil, errors.Errorf("the slirp4netns network mode is only supported by rootless builds")
+		}
+		return nil, nil
+	}
+	switch {
+	case network == "none" || network == "host" || filepath.IsAbs(network):
+		return nil, nil
+	case network == "" || network == "container":
+		// The network of rootless builds by default
+		netNS, err := runtime.NewBuildNetNS(nil)
+		if err != nil {
+			logrus.Warnf("RUN instructions will have no network: %v", err)
+			return nil, nil
+		}
+		return netNS, nil
+	case networkOptions != nil:
+		return runtime.NewBuildNetNS(networkOptions[libpod.Slirp4netnsNetworkOptions])
+	}
+	return nil, errors.Errorf("rootless builds can not use the CNI networks %q, only slirp4netns, none or host", network)
+}
+
+// qemuArchitectures are the names qemu-user uses for the architectures Go
+// names differently
+var qemuArchitectures = map[string]string{
+	"386":   "i386",
+	"amd64": "x86_64",
+	"arm64": "aarch64",
+}
+
+// checkEmulation warns if RUN instructions can not run on the architecture,
+// neither the host's nor emulated by a qemu-user binfmt_misc handler
+func checkEmulation(arch string) {
+	if arch == goruntime.GOARCH {
+		return
+	}
+	qemuArch, ok := qemuArchitectures[arch]
+	if !ok {
+		qemuArch = arch
+	}
+	if _, err := os.Stat(filepath.Join("/proc/sys/fs/binfmt_misc", "qemu-"+qemuArch)); err != nil {
+		logrus.Warnf("no qemu-user emulation is registered for %s, RUN instructions will fail", arch)
+	}
+}
+
 func buildCmd(c *cli.Context) error {
 	// The following was taken directly from projectatomic/buildah/cmd/bud.go
 	// TODO Find a away to vendor more of this in rather than copy from bud
@@ -91,28 +303,8 @@ func buildCmd(c *cli.Context) error {
 	contextDir := ""
 	cliArgs := c.Args()
 	if len(cliArgs) > 0 {
-		// The context directory could be a URL.  Try to handle that.
-		tempDir, subDir, err := imagebuildah.TempDirForURL("", "buildah", cliArgs[0])
-		if err != nil {
-			return errors.Wrapf(err, "error prepping temporary context directory")
-		}
-		if tempDir != "" {
-			// We had to download it to a temporary directory.
-			// Delete it later.
-			defer func() {
-				if err = os.RemoveAll(tempDir); err != nil {
-					logrus.Errorf("error removing temporary directory %q: %v", contextDir, err)
-				}
-			}()
-			contextDir = filepath.Join(tempDir, subDir)
-		} else {
-			// Nope, it was local.  Use it as is.
-			absDir, err := filepath.Abs(cliArgs[0])
-			if err != nil {
-				return errors.Wrapf(err, "error determining path to directory %q", cliArgs[0])
-			}
-			contextDir = absDir
-		}
+		// The context directory can also be a URL or a git repository
+		contextDir = cliArgs[0]
 		cliArgs = cliArgs.Tail()
 	} else {
 		// No context directory or URL was specified.  Try to use the
@@ -139,12 +331,32 @@ func buildCmd(c *cli.Context) error {
 	if contextDir == "" {
 		return errors.Errorf("no context directory specified, and no dockerfile specified")
 	}
+	// Remote contexts are fetched, and the files excluded by the ignore
+	// file of the context left out
+	keep := dockerfiles
+	if len(keep) == 0 {
+		keep = []string{"Dockerfile"}
+	}
+	buildContext, err := buildcontext.New(contextDir, keep)
+	if err != nil {
+		return errors.Wrapf(err, "error preparing build context %q", contextDir)
+	}
+	defer func() {
+		if err := buildContext.Cleanup(); err != nil {
+			logrus.Errorf("%v", err)
+		}
+	}()
+	contextDir = buildContext.Dir
 	if len(dockerfiles) == 0 {
 		dockerfiles = append(dockerfiles, filepath.Join(contextDir, "Dockerfile"))
 	}
 	if err := parse.ValidateFlags(c, buildahcli.BudFlags); err != nil {
 		return err
 	}
+	secrets, err := parseBuildSecrets(c.StringSlice("secret"))
+	if err != nil {
+		return err
+	}
 
 	runtimeFlags := []string{}
 	for _, arg := range c.StringSlice("runtime-flag") {
@@ -188,11 +400,26 @@ func buildCmd(c *cli.Context) error {
 	if err != nil {
 		return errors.Wrapf(err, "error parsing namespace-related options")
 	}
-	usernsOption, idmappingOptions, err := parse.IDMappingOptions(c)
+	usernsOption, idmappingOptions, err := buildIDMappingOptions(c, runtime)
 	if err != nil {
 		return errors.Wrapf(err, "error parsing ID mapping options")
 	}
 	namespaceOptions.AddOrReplace(usernsOption...)
+	netNS, err := buildNetNS(c, runtime)
+	if err != nil {
+		return errors.Wrapf(err, "error setting up the build network")
+	}
+	if netNS != nil {
+		defer func() {
+			if err := netNS.Close(); err != nil {
+				logrus.Errorf("%v", err)
+			}
+		}()
+		namespaceOptions.AddOrReplace(buildah.NamespaceOption{
+			Name: string(specs.NetworkNamespace),
+			Path: netNS.Path(),
+		})
+	}
 
 	options := imagebuildah.BuildOptions{
 		ContextDirectory:        contextDir,
@@ -235,5 +462,113 @@ func buildCmd(c *cli.Context) error {
 		options.Isolation = buildah.IsolationOCIRootless
 	}
 
-	return runtime.Build(getContext(), options, dockerfiles...)
+	if !c.IsSet("platform") {
+		return runtime.Build(getContext(), options, secrets, dockerfiles...)
+	}
+	platforms, err := parseBuildPlatforms(c.String("platform"))
+	if err != nil {
+		return err
+	}
+	if len(platforms) > 1 && options.IIDFile != "" {
+		return errors.Errorf("--iidfile can not be used when building for more than one platform")
+	}
+	for _, platform := range platforms {
+		checkEmulation(platform.arch)
+		platformOptions := options
+		platformContext := *systemContext
+		platformContext.OSChoice = platform.os
+		platformContext.ArchitectureChoice = platform.arch
+		platformOptions.SystemContext = &platformContext
+		// Local images are named the same whatever their platform, the
+		// base images of other platforms are pulled each time
+		if platform.os != goruntime.GOOS || platform.arch != goruntime.GOARCH {
+			platformOptions.PullPolicy = imagebuildah.PullAlways
+		}
+		if len(platforms) > 1 {
+			platformOptions.Output = platformTag(output, platform)
+			platformOptions.AdditionalTags = nil
+			for _, tag := range tags {
+				platformOptions.AdditionalTags = append(platformOptions.AdditionalTags, platformTag(tag, platform))
+			}
+		}
+		if !c.Bool("quiet") {
+			fmt.Fprintf(reporter, "Building for %s\n", platform)
+		}
+		if err := runtime.Build(getContext(), platformOptions, secrets, dockerfiles...); err != nil {
+			return errors.Wrapf(err, "error building for %s", platform)
+		}
+	}
+	if len(platforms) > 1 && output != "" {
+		for _, name := range append([]string{output}, tags...) {
+			if err := buildManifestList(runtime, systemContext, name, platforms); err != nil {
+				return err
+			}
+			if !c.Bool("quiet") {
+				fmt.Fprintf(reporter, "Created manifest list %s\n", manifests.ListName(name))
+			}
+		}
+	}
+	return nil
+}
+
+// buildManifestList creates the manifest list name of the images built for
+// several platforms, tagged with name suffixed with their platform. A list
+// with the same name is replaced.
+func buildManifestList(runtime *libpod.Runtime, sys *types.SystemContext, name string, platforms []buildPlatform) error {
+	store, err := runtime.ManifestStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Remove(name); err != nil && errors.Cause(err) != manifests.ErrNoSuchList {
+		return err
+	}
+	list, err := store.Create(name)
+	if err != nil {
+		return err
+	}
+	for _, platform := range platforms {
+		imageName := platformTag(name, platform)
+		ref, err := manifestImageReference(runtime, imageName)
+		if err == nil {
+			_, err = list.Add(getContext(), sys, ref, false)
+		}
+		if err != nil {
+			// The list is not left behind half built
+			if rmErr := store.Remove(list.Name); rmErr != nil {
+				logrus.Errorf("unable to remove manifest list %s: %v", list.Name, rmErr)
+			}
+			return errors.Wrapf(err, "error adding %s to manifest list %s", imageName, list.Name)
+		}
+	}
+	return store.Save(list)
+}
+
+// buildIDMappingOptions returns the user namespace and ID mappings of the
+// build. With --userns=auto, the runtime allocates the ID mappings, so that
+// the RUN instructions of builds of root run as unprivileged users.
+func buildIDMappingOptions(c *cli.Context, runtime *libpod.Runtime) (buildah.NamespaceOptions, *buildah.IDMappingOptions, error) {
+	if c.String("userns") != util.AutoUserNS {
+		return parse.IDMappingOptions(c)
+	}
+	for _, flag := range []string{"userns-uid-map", "userns-gid-map", "userns-uid-map-user", "userns-gid-map-group"} {
+		if c.IsSet(flag) {
+			return nil, nil, errors.Errorf("--userns=auto can not be used with --%s", flag)
+		}
+	}
+	mappings, err := runtime.AllocateUserNS()
+	if err != nil {
+		return nil, nil, err
+	}
+	idmappingOptions := &buildah.IDMappingOptions{}
+	for _, m := range mappings.UIDMap {
+		idmappingOptions.UIDMap = append(idmappingOptions.UIDMap, specs.LinuxIDMapping{ContainerID: uint32(m.ContainerID), HostID: uint32(m.HostID), Size: uint32(m.Size)})
+	}
+	for _, m := range mappings.GIDMap {
+		idmappingOptions.GIDMap = append(idmappingOptions.GIDMap, specs.LinuxIDMapping{ContainerID: uint32(m.ContainerID), HostID: uint32(m.HostID), Size: uint32(m.Size)})
+	}
+	usernsOptions := buildah.NamespaceOptions{{Name: string(specs.UserNamespace)}}
+	if !c.IsSet("net") {
+		usernsOptions = append(usernsOptions, buildah.NamespaceOption{Name: string(specs.NetworkNamespace)})
+	}
+	return usernsOptions, idmappingOptions, nil
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestParseBuildPlatforms(t *testing.T) {
+	platforms, err := parseBuildPlatforms("linux/amd64, linux/arm64,linux/amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, []buildPlatform{{"linux", "amd64"}, {"linux", "arm64"}}, platforms)
+
+	for _, value := range []string{"", "linux", "linux/", "/amd64", "linux/arm/v7", "linux/amd64,"} {
+		_, err := parseBuildPlatforms(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestPlatformTag(t *testing.T) {
+	arm64 := buildPlatform{"linux", "arm64"}
+	assert.Equal(t, "", platformTag("", arm64))
+	assert.Equal(t, "myapp:latest-linux-arm64", platformTag("myapp", arm64))
+	assert.Equal(t, "myapp:1.0-linux-arm64", platformTag("myapp:1.0", arm64))
+	assert.Equal(t, "localhost:5000/myapp:latest-linux-arm64", platformTag("localhost:5000/myapp", arm64))
+	assert.Equal(t, "localhost:5000/myapp:1.0-linux-arm64", platformTag("localhost:5000/myapp:1.0", arm64))
+}
+
+func TestParseBuildSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	token := filepath.Join(dir, "token")
+	require.NoError(t, ioutil.WriteFile(token, []byte("secret"), 0600))
+
+	secrets, err := parseBuildSecrets([]string{"id=token,src=" + token, "type=file,id=other,source=" + token})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"token": token, "other": token}, secrets)
+
+	for _, value := range []string{
+		"id=token",
+		"src=" + token,
+		"id=token,src=" + filepath.Join(dir, "missing"),
+		"type=env,id=token,src=" + token,
+		"id=token,src=" + token + ",mode=0400",
+		"token",
+	} {
+		_, err := parseBuildSecrets([]string{value})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestBuildNetNSRoot(t *testing.T) {
+	skipTestIfNotRoot(t)
+	if rootless.IsRootless() {
+		t.Skip("running in a rootless user namespace")
+	}
+	for _, tc := range []struct {
+		network string
+		valid   bool
+	}{
+		{"", true},
+		{"none", true},
+		{"host", true},
+		{"internal,default", true},
+		{"slirp4netns", false},
+		{"slirp4netns:mtu=1500", false},
+	} {
+		set := flag.NewFlagSet("test", 0)
+		set.String("network", tc.network, "")
+		netNS, err := buildNetNS(cli.NewContext(nil, set, nil), nil)
+		assert.Nil(t, netNS, tc.network)
+		if tc.valid {
+			assert.NoError(t, err, tc.network)
+		} else {
+			assert.Error(t, err, tc.network)
+		}
+	}
+}
+
+func TestBuildContextFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "context")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, _, err = buildContextFile(dir)
+	assert.Error(t, err, "context without a Containerfile")
+
+	dockerfile := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, ioutil.WriteFile(dockerfile, []byte("FROM scratch\n"), 0644))
+	contextDir, file, err := buildContextFile(dir)
+	require.NoError(t, err)
+	assert.Equal(t, dir, contextDir)
+	assert.Equal(t, dockerfile, file)
+
+	containerfile := filepath.Join(dir, "Containerfile")
+	require.NoError(t, ioutil.WriteFile(containerfile, []byte("FROM scratch\n"), 0644))
+	_, file, err = buildContextFile(dir)
+	require.NoError(t, err)
+	assert.Equal(t, containerfile, file)
+
+	other := filepath.Join(dir, "Containerfile.test")
+	require.NoError(t, ioutil.WriteFile(other, []byte("FROM scratch\n"), 0644))
+	contextDir, file, err = buildContextFile(other)
+	require.NoError(t, err)
+	assert.Equal(t, dir, contextDir)
+	assert.Equal(t, other, file)
+
+	_, _, err = buildContextFile(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	builderDescription = `Manage the build cache, the intermediate images podman build --layers
+   commits for the steps of Dockerfiles and reuses in later builds.  They are
+   not listed by podman images without --all.  The cache mounts of RUN
+   instructions are part of the cache too.`
+	builderSubCommands = []cli.Command{
+		builderInspectCommand,
+		builderPruneCommand,
+	}
+	builderCommand = cli.Command{
+		Name:                   "builder",
+		Usage:                  "Manage the build cache",
+		Description:            builderDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            builderSubCommands,
+	}
+	builderFilterFlag = cli.StringSliceFlag{
+		Name:  "filter, f",
+		Usage: "Filter the build cache images, with until=TIMESTAMP or DURATION (default [])",
+	}
+)
+
+// parseBuildCacheFilters returns the image filters of the --filter options
+// of the builder commands, and the earliest of their until times, zero if
+// there is none
+func parseBuildCacheFilters(filters []string) ([]image.ResultFilter, time.Time, error) {
+	var (
+		filterFuncs []image.ResultFilter
+		earliest    time.Time
+	)
+	for _, filter := range filters {
+		splitFilter := strings.SplitN(filter, "=", 2)
+		if len(splitFilter) != 2 {
+			return nil, earliest, errors.Errorf("invalid filter %q, it must be key=value", filter)
+		}
+		switch splitFilter[0] {
+		case "until":
+			until, err := util.ParseInputTime(splitFilter[1])
+			if err != nil {
+				return nil, earliest, err
+			}
+			filterFuncs = append(filterFuncs, image.CreatedBeforeFilter(until))
+			if earliest.IsZero() || until.Before(earliest) {
+				earliest = until
+			}
+		default:
+			return nil, earliest, errors.Errorf("invalid filter %s", splitFilter[0])
+		}
+	}
+	return filterFuncs, earliest, nil
+}
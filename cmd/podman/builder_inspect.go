@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/image"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	builderInspectFlags = []cli.Flag{
+		builderFilterFlag,
+	}
+	builderInspectDescription = `Displays the images of the build cache: their parent, when they were
+   created, the size of the layer their step added and the number of containers
+   using them.`
+	builderInspectCommand = cli.Command{
+		Name:                   "inspect",
+		Usage:                  "Display the images of the build cache",
+		Description:            builderInspectDescription,
+		Flags:                  builderInspectFlags,
+		Action:                 builderInspectCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func builderInspectCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman builder inspect takes no arguments")
+	}
+	if err := validateFlags(c, builderInspectFlags); err != nil {
+		return err
+	}
+	filters, _, err := parseBuildCacheFilters(c.StringSlice("filter"))
+	if err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	images, err := runtime.ImageRuntime().GetImages()
+	if err != nil {
+		return errors.Wrapf(err, "unable to get images")
+	}
+	entries := []*image.BuildCacheEntry{}
+	for _, img := range image.FilterImages(images, append([]image.ResultFilter{image.BuildCacheFilter()}, filters...)) {
+		entry, err := img.BuildCacheEntry()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	b, err := json.MarshalIndent(entries, "", "     ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	builderPruneFlags = []cli.Flag{
+		builderFilterFlag,
+	}
+	builderPruneDescription = `Removes the images of the build cache, unless containers use them or
+   they are the parents of other images.  Images that were named since they
+   were built are not part of the cache anymore and are kept.  The cache
+   mounts of RUN instructions are removed too.`
+	builderPruneCommand = cli.Command{
+		Name:                   "prune",
+		Usage:                  "Remove the images of the build cache",
+		Description:            builderPruneDescription,
+		Flags:                  builderPruneFlags,
+		Action:                 builderPruneCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func builderPruneCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman builder prune takes no arguments")
+	}
+	if err := validateFlags(c, builderPruneFlags); err != nil {
+		return err
+	}
+	filters, until, err := parseBuildCacheFilters(c.StringSlice("filter"))
+	if err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	removed, reclaimed, err := runtime.PruneBuildCache(getContext(), filters)
+	for _, id := range removed {
+		fmt.Println(id)
+	}
+	if err != nil {
+		return err
+	}
+	mountsReclaimed, err := runtime.PruneBuildCacheMounts(until)
+	if err != nil {
+		return err
+	}
+	reclaimed += mountsReclaimed
+	fmt.Printf("Total reclaimed space: %s\n", units.HumanSize(float64(reclaimed)))
+	return nil
+}
@@ -125,6 +125,9 @@ func commitCmd(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println(newImage.ID())
+	// Images committed to other transports are not in local storage
+	if newImage != nil {
+		fmt.Println(newImage.ID())
+	}
 	return nil
 }
@@ -99,6 +99,10 @@ var createFlags = []cli.Flag{
 		Name:  "blkio-weight-device",
 		Usage: "Block IO weight (relative device weight, format: `DEVICE_NAME:WEIGHT`)",
 	},
+	cli.BoolFlag{
+		Name:  "build-context",
+		Usage: "The first argument is not an image but the build context of a Containerfile the image is built from, which is removed with the container",
+	},
 	cli.StringSliceFlag{
 		Name:  "cap-add",
 		Usage: "Add capabilities to the container",
@@ -215,15 +219,59 @@ var createFlags = []cli.Flag{
 		Name:  "group-add",
 		Usage: "Add additional groups to join (default [])",
 	},
+	cli.StringFlag{
+		Name:  "health-cmd",
+		Usage: "Command to run to check the health of the container, or \"none\" to disable the healthcheck of the image",
+	},
+	cli.StringFlag{
+		Name:  "health-interval",
+		Usage: "Time between running the healthcheck (default 30s)",
+	},
+	cli.UintFlag{
+		Name:  "health-retries",
+		Usage: "Number of consecutive failures of the healthcheck making the container unhealthy (default 3)",
+	},
+	cli.StringFlag{
+		Name:  "health-startup-cmd",
+		Usage: "Command to run until it passes once after the container starts, before its healthcheck runs and it counts as started",
+	},
+	cli.StringFlag{
+		Name:  "health-startup-interval",
+		Usage: "Time between running the startup healthcheck (default 30s)",
+	},
+	cli.UintFlag{
+		Name:  "health-startup-retries",
+		Usage: "Number of consecutive failures of the startup healthcheck restarting the container (default 0, retry forever)",
+	},
+	cli.StringFlag{
+		Name:  "health-startup-timeout",
+		Usage: "Time after which a running startup healthcheck fails (default 30s)",
+	},
+	cli.StringFlag{
+		Name:  "health-timeout",
+		Usage: "Time after which a running healthcheck fails (default 30s)",
+	},
 	cli.StringFlag{
 		Name:  "hostname",
 		Usage: "Set container hostname",
 	},
+	cli.StringSliceFlag{
+		Name:  "hugetlb",
+		Usage: "Limit the hugepages of a size the container may use (format: `PAGESIZE:LIMIT`, e.g. --hugetlb=2MB:1g)",
+	},
+	cli.StringFlag{
+		Name:  "image-lock",
+		Usage: "Create the container in locked mode, only from an image pinned by digest by the lockfile or by its reference",
+	},
 	cli.StringFlag{
 		Name:  "image-volume, builtin-volume",
 		Usage: "Tells podman how to handle the builtin image volumes. The options are: 'bind', 'tmpfs', or 'ignore' (default 'bind')",
 		Value: "bind",
 	},
+	cli.StringFlag{
+		Name:  "init-ctr",
+		Usage: "Make the container an init container of its pod, run to completion when the pod starts: 'once' or 'always'",
+	},
 	cli.BoolFlag{
 		Name:  "interactive, i",
 		Usage: "Keep STDIN open even if not attached",
@@ -244,6 +292,10 @@ var createFlags = []cli.Flag{
 		Name:  "label-file",
 		Usage: "Read in a line delimited file of labels (default [])",
 	},
+	cli.StringFlag{
+		Name:  "locale",
+		Usage: "Set the locale of the container through the LANG environment variable",
+	},
 	cli.StringFlag{
 		Name:  "log-driver",
 		Usage: "Logging driver for the container",
@@ -273,15 +325,27 @@ var createFlags = []cli.Flag{
 		Usage: "Tune container memory swappiness (0 to 100) (default -1)",
 		Value: -1,
 	},
+	cli.StringSliceFlag{
+		Name:  "mount",
+		Usage: "Attach a filesystem mount to the container, only `type=artifact,src=ARTIFACT,dst=DIR` is supported (default [])",
+	},
 	cli.StringFlag{
 		Name:  "name",
 		Usage: "Assign a name to the container",
 	},
+	cli.StringFlag{
+		Name:  "name-reservation",
+		Usage: "Token of the reservation of the name of the container, from `podman name reserve`",
+	},
 	cli.StringFlag{
 		Name:  "net, network",
 		Usage: "Connect a container to a network",
 		Value: "bridge",
 	},
+	cli.BoolFlag{
+		Name:  "no-healthcheck",
+		Usage: "Disable the healthcheck of the image",
+	},
 	cli.BoolFlag{
 		Name:  "oom-kill-disable",
 		Usage: "Disable OOM Killer",
@@ -298,6 +362,10 @@ var createFlags = []cli.Flag{
 		Name:  "pids-limit",
 		Usage: "Tune container pids limit (set -1 for unlimited)",
 	},
+	cli.StringFlag{
+		Name:  "platform",
+		Usage: "Pull and run the image of the `OS/ARCH` platform, such as wasi/wasm32 to run a WASM module",
+	},
 	cli.StringFlag{
 		Name:  "pod",
 		Usage: "Run container in an existing pod",
@@ -318,10 +386,22 @@ var createFlags = []cli.Flag{
 		Name:  "quiet, q",
 		Usage: "Suppress output information when pulling images",
 	},
+	cli.StringFlag{
+		Name:  "rdt-class",
+		Usage: "Assign the container to a resctrl class of the Intel RDT cache allocation",
+	},
 	cli.BoolFlag{
 		Name:  "read-only",
 		Usage: "Make containers root filesystem read-only",
 	},
+	cli.UintFlag{
+		Name:  "resource-wait-timeout",
+		Usage: "Timeout (in seconds) to wait at start for the networks of the container to be available. Default is 0, not waiting",
+	},
+	cli.StringFlag{
+		Name:  "restart",
+		Usage: "Restart policy to apply when a container exits (no, on-failure[:max-retries], always, unless-stopped)",
+	},
 	cli.BoolFlag{
 		Name:  "rm",
 		Usage: "Remove container (and pod if created) after exit",
@@ -373,13 +453,17 @@ var createFlags = []cli.Flag{
 		Name:  "tty, t",
 		Usage: "Allocate a pseudo-TTY for container",
 	},
+	cli.StringFlag{
+		Name:  "tz",
+		Usage: "Set the timezone of the container, a zone of the host's zoneinfo database or 'local' for the timezone of the host",
+	},
 	cli.StringSliceFlag{
 		Name:  "uidmap",
 		Usage: "UID map to use for the user namespace",
 	},
 	cli.StringSliceFlag{
 		Name:  "ulimit",
-		Usage: "Ulimit options (default [])",
+		Usage: "Ulimit options, TYPE=SOFT:HARD or host (default [])",
 	},
 	cli.StringFlag{
 		Name:  "user, u",
@@ -31,7 +31,7 @@ var (
 		topCommand,
 		umountCommand,
 		unpauseCommand,
-		//		updateCommand,
+		updateCommand,
 		waitCommand,
 	}
 
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/containers/image/manifest"
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/libpod/image"
@@ -79,7 +80,12 @@ func createCmd(c *cli.Context) error {
 		rootfs = c.Args()[0]
 	}
 
-	mappings, err := util.ParseIDMapping(c.StringSlice("uidmap"), c.StringSlice("gidmap"), c.String("subuidmap"), c.String("subgidmap"))
+	dockerRegistryOptions, err := platformRegistryOptions(c)
+	if err != nil {
+		return err
+	}
+
+	mappings, err := util.ParseIDMapping(c.String("userns"), c.StringSlice("uidmap"), c.StringSlice("gidmap"), c.String("subuidmap"), c.String("subgidmap"))
 	if err != nil {
 		return err
 	}
@@ -102,12 +108,25 @@ func createCmd(c *cli.Context) error {
 	imageName := ""
 	var data *inspect.ImageData = nil
 	if rootfs == "" {
-		newImage, err := runtime.ImageRuntime().New(ctx, c.Args()[0], rtc.SignaturePolicyPath, "", os.Stderr, nil, image.SigningOptions{}, false, false)
+		var imageRef string
+		if c.Bool("build-context") {
+			imageRef, err = buildEphemeralImage(c, runtime, c.Args()[0])
+		} else {
+			imageRef, err = lockedImageName(c, c.Args()[0])
+		}
+		if err != nil {
+			return err
+		}
+		newImage, err := runtime.ImageRuntime().New(ctx, imageRef, rtc.SignaturePolicyPath, "", os.Stderr, dockerRegistryOptions, image.SigningOptions{}, false, false)
 		if err != nil {
 			return err
 		}
 		data, err = newImage.Inspect(ctx)
-		imageName = newImage.Names()[0]
+		if len(newImage.Names()) < 1 {
+			imageName = newImage.ID()
+		} else {
+			imageName = newImage.Names()[0]
+		}
 	}
 	createConfig, err := parseCreateOpts(ctx, c, runtime, imageName, data)
 	if err != nil {
@@ -224,8 +243,10 @@ func loadAppArmor(config *cc.CreateConfig) error {
 
 func parseSecurityOpt(config *cc.CreateConfig, securityOpts []string) error {
 	var (
-		labelOpts []string
-		err       error
+		labelOpts      []string
+		privilegedKeep []string
+		nestedPreset   bool
+		err            error
 	)
 
 	if config.PidMode.IsHost() {
@@ -251,6 +272,8 @@ func parseSecurityOpt(config *cc.CreateConfig, securityOpts []string) error {
 	for _, opt := range securityOpts {
 		if opt == "no-new-privileges" {
 			config.NoNewPrivs = true
+		} else if opt == "nested" {
+			nestedPreset = true
 		} else {
 			con := strings.SplitN(opt, "=", 2)
 			if len(con) != 2 {
@@ -264,36 +287,55 @@ func parseSecurityOpt(config *cc.CreateConfig, securityOpts []string) error {
 				config.ApparmorProfile = con[1]
 			case "seccomp":
 				config.SeccompProfilePath = con[1]
+			case "privileged-keep":
+				privilegedKeep = append(privilegedKeep, strings.Split(con[1], ",")...)
 			default:
 				return fmt.Errorf("Invalid --security-opt 2: %q", opt)
 			}
 		}
 	}
 
-	if err := loadAppArmor(config); err != nil {
-		return err
+	if config.Privileged {
+		if config.PrivilegedProfile, err = cc.NewPrivilegedProfile(privilegedKeep); err != nil {
+			return err
+		}
+	} else if len(privilegedKeep) > 0 {
+		return errors.Errorf("--security-opt privileged-keep requires --privileged")
 	}
 
-	if config.SeccompProfilePath == "" {
-		if _, err := os.Stat(libpod.SeccompOverridePath); err == nil {
-			config.SeccompProfilePath = libpod.SeccompOverridePath
-		} else {
-			if !os.IsNotExist(err) {
-				return errors.Wrapf(err, "can't check if %q exists", libpod.SeccompOverridePath)
-			}
-			if _, err := os.Stat(libpod.SeccompDefaultPath); err != nil {
-				if !os.IsNotExist(err) {
-					return errors.Wrapf(err, "can't check if %q exists", libpod.SeccompDefaultPath)
-				}
-			} else {
-				config.SeccompProfilePath = libpod.SeccompDefaultPath
-			}
-		}
+	if nestedPreset {
+		applyNestedPreset(config)
+		labelOpts = append(labelOpts, label.DisableSecOpt()...)
 	}
+
+	if config.PrivilegedProfile.Label {
+		config.ApparmorProfile = ""
+		labelOpts = label.DisableSecOpt()
+	} else if err := loadAppArmor(config); err != nil {
+		return err
+	}
+
 	config.ProcessLabel, config.MountLabel, err = label.InitLabels(labelOpts)
 	return err
 }
 
+// applyNestedPreset relaxes the confinement of a container so podman can run
+// in it: SELinux, AppArmor and seccomp would block the mounts and the user
+// namespaces it creates, and the masked kernel filesystems keep it from
+// mounting /proc. /dev/fuse is added for fuse-overlayfs.
+func applyNestedPreset(config *cc.CreateConfig) {
+	if config.ApparmorProfile == "" {
+		config.ApparmorProfile = "unconfined"
+	}
+	if config.SeccompProfilePath == "" {
+		config.SeccompProfilePath = "unconfined"
+	}
+	config.PrivilegedProfile.Unmask = true
+	if _, err := os.Stat("/dev/fuse"); err == nil && !util.StringInSlice("/dev/fuse", config.Devices) {
+		config.Devices = append(config.Devices, "/dev/fuse")
+	}
+}
+
 // isPortInPortBindings determines if an exposed host port is in user
 // provided ports
 func isPortInPortBindings(pb map[nat.Port][]nat.PortBinding, port nat.Port) bool {
@@ -336,6 +378,19 @@ func configureEntrypoint(c *cli.Context, data *inspect.ImageData) []string {
 	return entrypoint
 }
 
+// platformRegistryOptions returns the options pulling the image of the
+// platform given with --platform, or nil if it is not set
+func platformRegistryOptions(c *cli.Context) (*image.DockerRegistryOptions, error) {
+	if !c.IsSet("platform") {
+		return nil, nil
+	}
+	platformOS, platformArch, err := cc.ParsePlatform(c.String("platform"))
+	if err != nil {
+		return nil, err
+	}
+	return &image.DockerRegistryOptions{OSChoice: platformOS, ArchitectureChoice: platformArch}, nil
+}
+
 // Parses CLI options related to container creation into a config which can be
 // parsed into an OCI runtime spec
 func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtime, imageName string, data *inspect.ImageData) (*cc.CreateConfig, error) {
@@ -344,20 +399,27 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		memoryLimit, memoryReservation, memorySwap, memoryKernel int64
 		blkioWeight                                              uint16
 	)
-	idmappings, err := util.ParseIDMapping(c.StringSlice("uidmap"), c.StringSlice("gidmap"), c.String("subuidname"), c.String("subgidname"))
+	idmappings, err := util.ParseIDMapping(c.String("userns"), c.StringSlice("uidmap"), c.StringSlice("gidmap"), c.String("subuidname"), c.String("subgidname"))
 	if err != nil {
 		return nil, err
 	}
+	if c.String("userns") == util.AutoUserNS {
+		if idmappings, err = runtime.AllocateUserNS(); err != nil {
+			return nil, err
+		}
+	}
 
 	if c.String("mac-address") != "" {
 		return nil, errors.Errorf("--mac-address option not currently supported")
 	}
 
 	imageID := ""
+	var imageLabels map[string]string
 
 	inputCommand = c.Args()[1:]
 	if data != nil {
 		imageID = data.ID
+		imageLabels = data.ContainerConfig.Labels
 	}
 
 	rootfs := ""
@@ -365,6 +427,21 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		rootfs = c.Args()[0]
 	}
 
+	// Run WASM modules with the WASM-capable runtime
+	runtimeHandler := ""
+	if c.IsSet("platform") {
+		platformOS, platformArch, err := cc.ParsePlatform(c.String("platform"))
+		if err != nil {
+			return nil, err
+		}
+		if cc.IsWasmPlatform(platformOS, platformArch) {
+			runtimeHandler = libpod.WasmRuntimeHandler
+		}
+	}
+	if data != nil && cc.IsWasmImage(data.Os, data.Architecture, data.Annotations) {
+		runtimeHandler = libpod.WasmRuntimeHandler
+	}
+
 	sysctl, err := validateSysctl(c.StringSlice("sysctl"))
 	if err != nil {
 		return nil, errors.Wrapf(err, "invalid value for sysctl")
@@ -382,7 +459,9 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 			return nil, errors.Wrapf(err, "invalid value for memory-reservation")
 		}
 	}
-	if c.String("memory-swap") != "" {
+	if c.String("memory-swap") == "-1" {
+		memorySwap = -1
+	} else if c.String("memory-swap") != "" {
 		memorySwap, err = units.RAMInBytes(c.String("memory-swap"))
 		if err != nil {
 			return nil, errors.Wrapf(err, "invalid value for memory-swap")
@@ -410,14 +489,31 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		return nil, err
 	}
 
+	if err = parseMounts(c.StringSlice("mount")); err != nil {
+		return nil, err
+	}
+
 	tty := c.Bool("tty")
 
 	if c.Bool("detach") && c.Bool("rm") {
 		return nil, errors.Errorf("--rm and --detach can not be specified together")
 	}
+	var restartPolicy string
+	var restartRetries uint
+	if c.IsSet("restart") {
+		if c.Bool("rm") {
+			return nil, errors.Errorf("--rm and --restart can not be specified together")
+		}
+		if restartPolicy, restartRetries, err = parseRestartPolicy(c.String("restart")); err != nil {
+			return nil, err
+		}
+	}
 	if c.Int64("cpu-period") != 0 && c.Float64("cpus") > 0 {
 		return nil, errors.Errorf("--cpu-period and --cpus cannot be set together")
 	}
+	if c.IsSet("name-reservation") && c.String("name") == "" {
+		return nil, errors.Errorf("--name-reservation requires --name")
+	}
 	if c.Int64("cpu-quota") != 0 && c.Float64("cpus") > 0 {
 		return nil, errors.Errorf("--cpu-quota and --cpus cannot be set together")
 	}
@@ -445,7 +541,7 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		usernsModeStr = cc.POD
 	}
 	usernsMode := container.UsernsMode(usernsModeStr)
-	if !cc.Valid(string(usernsMode), usernsMode) {
+	if usernsModeStr != util.KeepIDUserNS && usernsModeStr != util.AutoUserNS && !cc.Valid(string(usernsMode), usernsMode) {
 		return nil, errors.Errorf("--userns %q is not valid", c.String("userns"))
 	}
 
@@ -467,9 +563,16 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		return nil, errors.Errorf("--ipc %q is not valid", ipcMode)
 	}
 	netModeStr := c.String("network")
-	if !c.IsSet("network") && pod != nil && pod.SharesNet() {
-		netModeStr = cc.POD
+	if !c.IsSet("network") {
+		if pod != nil && pod.SharesNet() {
+			netModeStr = cc.POD
+		} else if runtime != nil {
+			if rtc := runtime.GetConfig(); rtc != nil && rtc.NetworkMode != "" {
+				netModeStr = rtc.NetworkMode
+			}
+		}
 	}
+	netModeStr, networkOptions := cc.ParseNetworkMode(netModeStr)
 	// Make sure if network is set to container namespace, port binding is not also being asked for
 	netMode := container.NetworkMode(netModeStr)
 	if netMode.IsContainer() || cc.IsPod(netModeStr) {
@@ -492,7 +595,10 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 	// USER
 	user := c.String("user")
 	if user == "" {
-		if data == nil {
+		if usernsMode == util.KeepIDUserNS {
+			// The process runs as the user it keeps the IDs of
+			user = fmt.Sprintf("%d:%d", rootless.GetRootlessUID(), rootless.GetRootlessGID())
+		} else if data == nil {
 			user = "0"
 		} else {
 			user = data.ContainerConfig.User
@@ -532,16 +638,19 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 	}
 
 	// LABEL VARIABLES
+	// Labels are applied in order of increasing precedence: labels of the
+	// image, then labels read from --label-file in the order given, then
+	// labels given with --label
 	labels, err := getAllLabels(c.StringSlice("label-file"), c.StringSlice("label"))
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to process labels")
 	}
 	if data != nil {
-		for key, val := range data.ContainerConfig.Labels {
-			if _, ok := labels[key]; !ok {
-				labels[key] = val
-			}
-		}
+		labels = cc.MergeLabels(data.ContainerConfig.Labels, labels)
+	}
+	// The runtime class label selects the runtime handler explicitly
+	if class := labels[libpod.RuntimeClassLabel]; class != "" {
+		runtimeHandler = class
 	}
 
 	// ANNOTATIONS
@@ -640,52 +749,74 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		return nil, errors.Errorf("invalid image-volume type %q. Pick one of bind, tmpfs, or ignore", c.String("image-volume"))
 	}
 
+	var imageHealthCheck *manifest.Schema2HealthConfig
+	if data != nil {
+		imageHealthCheck = data.HealthCheck
+	}
+	healthCheck, err := parseHealthCheck(c, imageHealthCheck)
+	if err != nil {
+		return nil, err
+	}
+	startupHealthCheck, err := parseStartupHealthCheck(c)
+	if err != nil {
+		return nil, err
+	}
+
 	config := &cc.CreateConfig{
-		Runtime:           runtime,
-		Annotations:       annotations,
-		BuiltinImgVolumes: ImageVolumes,
-		ConmonPidFile:     c.String("conmon-pidfile"),
-		ImageVolumeType:   c.String("image-volume"),
-		CapAdd:            c.StringSlice("cap-add"),
-		CapDrop:           c.StringSlice("cap-drop"),
-		CgroupParent:      c.String("cgroup-parent"),
-		Command:           command,
-		Detach:            c.Bool("detach"),
-		Devices:           c.StringSlice("device"),
-		DNSOpt:            c.StringSlice("dns-opt"),
-		DNSSearch:         c.StringSlice("dns-search"),
-		DNSServers:        c.StringSlice("dns"),
-		Entrypoint:        entrypoint,
-		Env:               env,
+		Runtime:            runtime,
+		Annotations:        annotations,
+		BuiltinImgVolumes:  ImageVolumes,
+		ConmonPidFile:      c.String("conmon-pidfile"),
+		CreateCommand:      os.Args,
+		ImageVolumeType:    c.String("image-volume"),
+		CapAdd:             c.StringSlice("cap-add"),
+		CapDrop:            c.StringSlice("cap-drop"),
+		CgroupParent:       c.String("cgroup-parent"),
+		Command:            command,
+		Detach:             c.Bool("detach"),
+		Devices:            c.StringSlice("device"),
+		HealthCheck:        healthCheck,
+		StartupHealthCheck: startupHealthCheck,
+		DNSOpt:             c.StringSlice("dns-opt"),
+		DNSSearch:          c.StringSlice("dns-search"),
+		DNSServers:         c.StringSlice("dns"),
+		Entrypoint:         entrypoint,
+		Env:                env,
 		//ExposedPorts:   ports,
-		GroupAdd:       c.StringSlice("group-add"),
-		Hostname:       c.String("hostname"),
-		HostAdd:        c.StringSlice("add-host"),
-		IDMappings:     idmappings,
-		Image:          imageName,
-		ImageID:        imageID,
-		Interactive:    c.Bool("interactive"),
-		IP6Address:     c.String("ipv6"),
-		IPAddress:      c.String("ip"),
-		Labels:         labels,
-		LinkLocalIP:    c.StringSlice("link-local-ip"),
-		LogDriver:      c.String("log-driver"),
-		LogDriverOpt:   c.StringSlice("log-opt"),
-		MacAddress:     c.String("mac-address"),
-		Name:           c.String("name"),
-		Network:        c.String("network"),
-		NetworkAlias:   c.StringSlice("network-alias"),
-		IpcMode:        ipcMode,
-		NetMode:        netMode,
-		UtsMode:        utsMode,
-		PidMode:        pidMode,
-		Pod:            c.String("pod"),
-		Privileged:     c.Bool("privileged"),
-		Publish:        c.StringSlice("publish"),
-		PublishAll:     c.Bool("publish-all"),
-		PortBindings:   portBindings,
-		Quiet:          c.Bool("quiet"),
-		ReadOnlyRootfs: c.Bool("read-only"),
+		GroupAdd:        c.StringSlice("group-add"),
+		Hostname:        c.String("hostname"),
+		HostAdd:         c.StringSlice("add-host"),
+		IDMappings:      idmappings,
+		Image:           imageName,
+		ImageID:         imageID,
+		ImageLabels:     imageLabels,
+		Interactive:     c.Bool("interactive"),
+		IP6Address:      c.String("ipv6"),
+		IPAddress:       c.String("ip"),
+		Labels:          labels,
+		LinkLocalIP:     c.StringSlice("link-local-ip"),
+		Locale:          c.String("locale"),
+		LogDriver:       c.String("log-driver"),
+		LogDriverOpt:    c.StringSlice("log-opt"),
+		MacAddress:      c.String("mac-address"),
+		Mounts:          c.StringSlice("mount"),
+		Name:            c.String("name"),
+		NameReservation: c.String("name-reservation"),
+		Network:         netModeStr,
+		NetworkAlias:    c.StringSlice("network-alias"),
+		IpcMode:         ipcMode,
+		NetMode:         netMode,
+		NetworkOptions:  networkOptions,
+		UtsMode:         utsMode,
+		PidMode:         pidMode,
+		Pod:             c.String("pod"),
+		Privileged:      c.Bool("privileged"),
+		Publish:         c.StringSlice("publish"),
+		PublishAll:      c.Bool("publish-all"),
+		PortBindings:    portBindings,
+		Quiet:           c.Bool("quiet"),
+		ReadOnlyRootfs:  c.Bool("read-only"),
+		RuntimeHandler:  runtimeHandler,
 		Resources: cc.CreateResourceConfig{
 			BlkioWeight:       blkioWeight,
 			BlkioWeightDevice: c.StringSlice("blkio-weight-device"),
@@ -702,6 +833,7 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 			DeviceWriteBps:    c.StringSlice("device-write-bps"),
 			DeviceWriteIOps:   c.StringSlice("device-write-iops"),
 			DisableOomKiller:  c.Bool("oom-kill-disable"),
+			HugetlbLimits:     c.StringSlice("hugetlb"),
 			ShmSize:           shmSize,
 			Memory:            memoryLimit,
 			MemoryReservation: memoryReservation,
@@ -711,6 +843,7 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 			OomScoreAdj:       c.Int("oom-score-adj"),
 
 			PidsLimit: c.Int64("pids-limit"),
+			RDTClass:  c.String("rdt-class"),
 			Ulimit:    c.StringSlice("ulimit"),
 		},
 		Rm:          c.Bool("rm"),
@@ -718,6 +851,7 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		StopSignal:  stopSignal,
 		StopTimeout: c.Uint("stop-timeout"),
 		Sysctl:      sysctl,
+		Timezone:    c.String("tz"),
 		Tmpfs:       c.StringSlice("tmpfs"),
 		Tty:         tty,
 		User:        user,
@@ -728,12 +862,14 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		VolumesFrom: c.StringSlice("volumes-from"),
 	}
 
-	if !config.Privileged {
-		if err := parseSecurityOpt(config, c.StringSlice("security-opt")); err != nil {
-			return nil, err
-		}
+	if err := parseSecurityOpt(config, c.StringSlice("security-opt")); err != nil {
+		return nil, err
 	}
 	config.SecurityOpts = c.StringSlice("security-opt")
+	config.ResourceTimeout = c.Uint("resource-wait-timeout")
+	config.InitContainerType = c.String("init-ctr")
+	config.RestartPolicy = restartPolicy
+	config.RestartRetries = restartRetries
 	warnings, err := verifyContainerResources(config, false)
 	if err != nil {
 		return nil, err
@@ -1,20 +1,34 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/containers/image/manifest"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/cgroups"
+	"github.com/containers/libpod/pkg/rootless"
 	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/docker/docker/pkg/parsers"
 	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
 )
 
 const (
 	// It's not kernel limit, we want this 4M limit to supply a reasonable functional container
 	linuxMinMemory = 4194304
+
+	onlineCPUsPath  = "/sys/devices/system/cpu/online"
+	onlineNodesPath = "/sys/devices/system/node/online"
 )
 
 func getAllLabels(labelFile, inputLabels []string) (map[string]string, error) {
@@ -95,6 +109,15 @@ func parseVolumes(volumes []string) error {
 	return nil
 }
 
+func parseMounts(mounts []string) error {
+	for _, mount := range mounts {
+		if _, err := cc.ParseMount(mount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func parseVolumesFrom(volumesFrom []string) error {
 	for _, vol := range volumesFrom {
 		arr := strings.SplitN(vol, ":", 2)
@@ -154,20 +177,118 @@ func validateVolumeOpts(option string) error {
 	return nil
 }
 
+// cgroupV2Controllers returns the controllers of the unified cgroup v2
+// hierarchy, or nil if the host does not use it. Rootless containers can only
+// use the controllers delegated to the systemd user instance.
+func cgroupV2Controllers() []string {
+	if rootless.IsRootless() {
+		return rootless.CgroupV2Controllers()
+	}
+	return cgroups.Controllers("/")
+}
+
+// checkCgroupV2Memory returns an error for the memory settings the unified
+// cgroup v2 hierarchy can not represent
+func checkCgroupV2Memory(resources *cc.CreateResourceConfig) error {
+	if resources.MemorySwappiness != -1 {
+		return errors.Errorf("memory swappiness can not be set on the unified cgroup v2 hierarchy, it has no per cgroup swappiness")
+	}
+	if resources.KernelMemory != 0 {
+		return errors.Errorf("kernel memory limits can not be set on the unified cgroup v2 hierarchy, kernel memory is accounted in the memory limit")
+	}
+	if resources.MemorySwap > 0 && resources.Memory == 0 {
+		return errors.Errorf("the swap limit can not be set without a memory limit on the unified cgroup v2 hierarchy, which limits swap separately from memory")
+	}
+	return nil
+}
+
+// resourceLimitsSet returns whether any resource limit the cgroups of a
+// container would enforce is set
+func resourceLimitsSet(resources *cc.CreateResourceConfig) bool {
+	return resources.Memory != 0 || resources.MemoryReservation != 0 || resources.MemorySwap > 0 ||
+		resources.KernelMemory != 0 || resources.MemorySwappiness != -1 || resources.DisableOomKiller ||
+		resources.CPUShares != 0 || resources.CPUPeriod != 0 || resources.CPUQuota != 0 || resources.CPUs != 0 ||
+		resources.CPURtPeriod != 0 || resources.CPURtRuntime != 0 ||
+		resources.CPUsetCPUs != "" || resources.CPUsetMems != "" || resources.PidsLimit != 0 ||
+		resources.BlkioWeight != 0 || len(resources.BlkioWeightDevice) > 0 ||
+		len(resources.DeviceReadBps) > 0 || len(resources.DeviceWriteBps) > 0 ||
+		len(resources.DeviceReadIOps) > 0 || len(resources.DeviceWriteIOps) > 0 ||
+		len(resources.HugetlbLimits) > 0
+}
+
+// hugetlbController returns whether the hugetlb cgroup controller is
+// available, either mounted on the legacy hierarchy or enabled on the
+// unified one
+func hugetlbController(v2Controllers []string) bool {
+	if util.StringInSlice("hugetlb", v2Controllers) {
+		return true
+	}
+	_, err := os.Stat(cgroups.V1Dir("hugetlb", ""))
+	return err == nil
+}
+
+// readOnlineList returns the list of online CPUs or memory nodes in the
+// given sysfs file. Kernels without NUMA support have no list of nodes, their
+// memory is on node 0.
+func readOnlineList(path string) string {
+	online, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "0"
+	}
+	return strings.TrimSpace(string(online))
+}
+
+// cpusetAvailable returns whether all the CPUs or memory nodes of a cpuset
+// list are in a list of online ones
+func cpusetAvailable(requested, online string) (bool, error) {
+	parsedRequested, err := parsers.ParseUintList(requested)
+	if err != nil {
+		return false, err
+	}
+	parsedOnline, err := parsers.ParseUintList(online)
+	if err != nil {
+		return false, err
+	}
+	for k := range parsedRequested {
+		if !parsedOnline[k] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func verifyContainerResources(config *cc.CreateConfig, update bool) ([]string, error) {
 	warnings := []string{}
 	sysInfo := sysinfo.New(true)
+	v2Controllers := cgroupV2Controllers()
+
+	// Rootless containers only get cgroups when the systemd user instance
+	// can create them on the unified cgroup v2 hierarchy
+	if rootless.IsRootless() && v2Controllers == nil {
+		if resourceLimitsSet(&config.Resources) {
+			warnings = addWarning(warnings, "Rootless containers can only have resource limits on cgroup v2 through a systemd user session. Limitations discarded.")
+		}
+		return warnings, nil
+	}
 
-	// memory subsystem checks and adjustments
+	// memory subsystem checks and adjustments. On the unified cgroup v2
+	// hierarchy the memory controller provides the limit as memory.max, the
+	// reservation as memory.low and the swap limit as memory.swap.max.
+	memoryV2 := util.StringInSlice("memory", v2Controllers)
+	if v2Controllers != nil {
+		if err := checkCgroupV2Memory(&config.Resources); err != nil {
+			return warnings, err
+		}
+	}
 	if config.Resources.Memory != 0 && config.Resources.Memory < linuxMinMemory {
 		return warnings, fmt.Errorf("minimum memory limit allowed is 4MB")
 	}
-	if config.Resources.Memory > 0 && !sysInfo.MemoryLimit {
+	if config.Resources.Memory > 0 && !sysInfo.MemoryLimit && !memoryV2 {
 		warnings = addWarning(warnings, "Your kernel does not support memory limit capabilities or the cgroup is not mounted. Limitation discarded.")
 		config.Resources.Memory = 0
 		config.Resources.MemorySwap = -1
 	}
-	if config.Resources.Memory > 0 && config.Resources.MemorySwap != -1 && !sysInfo.SwapLimit {
+	if config.Resources.Memory > 0 && config.Resources.MemorySwap != -1 && !sysInfo.SwapLimit && !memoryV2 {
 		warnings = addWarning(warnings, "Your kernel does not support swap limit capabilities,or the cgroup is not mounted. Memory limited without swap.")
 		config.Resources.MemorySwap = -1
 	}
@@ -189,7 +310,7 @@ func verifyContainerResources(config *cc.CreateConfig, update bool) ([]string, e
 			}
 		}
 	}
-	if config.Resources.MemoryReservation > 0 && !sysInfo.MemoryReservation {
+	if config.Resources.MemoryReservation > 0 && !sysInfo.MemoryReservation && !memoryV2 {
 		warnings = addWarning(warnings, "Your kernel does not support memory soft limit capabilities or the cgroup is not mounted. Limitation discarded.")
 		config.Resources.MemoryReservation = 0
 	}
@@ -213,78 +334,260 @@ func verifyContainerResources(config *cc.CreateConfig, update bool) ([]string, e
 		config.Resources.DisableOomKiller = false
 	}
 
-	if config.Resources.PidsLimit != 0 && !sysInfo.PidsLimit {
+	// hugetlb subsystem checks and adjustments, the limits themselves are
+	// verified against the hugepage pools when the spec is generated
+	if len(config.Resources.HugetlbLimits) > 0 && !hugetlbController(v2Controllers) {
+		warnings = addWarning(warnings, "Your kernel does not support hugetlb limits or the cgroup is not mounted. Hugetlb limits discarded.")
+		config.Resources.HugetlbLimits = []string{}
+	}
+
+	if config.Resources.PidsLimit != 0 && !sysInfo.PidsLimit && !util.StringInSlice("pids", v2Controllers) {
 		warnings = addWarning(warnings, "Your kernel does not support pids limit capabilities or the cgroup is not mounted. PIDs limit discarded.")
 		config.Resources.PidsLimit = 0
 	}
 
-	if config.Resources.CPUShares > 0 && !sysInfo.CPUShares {
+	// cpu subsystem checks and adjustments. On the unified cgroup v2
+	// hierarchy the cpu controller provides the shares as cpu.weight and
+	// the period and quota as cpu.max.
+	cpuV2 := util.StringInSlice("cpu", v2Controllers)
+	if config.Resources.CPUShares > 0 && !sysInfo.CPUShares && !cpuV2 {
 		warnings = addWarning(warnings, "Your kernel does not support CPU shares or the cgroup is not mounted. Shares discarded.")
 		config.Resources.CPUShares = 0
 	}
-	if config.Resources.CPUPeriod > 0 && !sysInfo.CPUCfsPeriod {
+	if config.Resources.CPUPeriod > 0 && !sysInfo.CPUCfsPeriod && !cpuV2 {
 		warnings = addWarning(warnings, "Your kernel does not support CPU cfs period or the cgroup is not mounted. Period discarded.")
 		config.Resources.CPUPeriod = 0
 	}
 	if config.Resources.CPUPeriod != 0 && (config.Resources.CPUPeriod < 1000 || config.Resources.CPUPeriod > 1000000) {
 		return warnings, fmt.Errorf("CPU cfs period can not be less than 1ms (i.e. 1000) or larger than 1s (i.e. 1000000)")
 	}
-	if config.Resources.CPUQuota > 0 && !sysInfo.CPUCfsQuota {
+	if config.Resources.CPUQuota > 0 && !sysInfo.CPUCfsQuota && !cpuV2 {
 		warnings = addWarning(warnings, "Your kernel does not support CPU cfs quota or the cgroup is not mounted. Quota discarded.")
 		config.Resources.CPUQuota = 0
 	}
 	if config.Resources.CPUQuota > 0 && config.Resources.CPUQuota < 1000 {
 		return warnings, fmt.Errorf("CPU cfs quota can not be less than 1ms (i.e. 1000)")
 	}
-	// cpuset subsystem checks and adjustments
-	if (config.Resources.CPUsetCPUs != "" || config.Resources.CPUsetMems != "") && !sysInfo.Cpuset {
+	if config.Resources.CPUs > 0 && !sysInfo.CPUCfsQuota && !cpuV2 {
+		warnings = addWarning(warnings, "Your kernel does not support CPU cfs quota or the cgroup is not mounted. CPUs discarded.")
+		config.Resources.CPUs = 0
+	}
+	// cpuset subsystem checks and adjustments. On the unified cgroup v2
+	// hierarchy the cpuset controller provides them.
+	cpusetController := sysInfo.Cpuset || util.StringInSlice("cpuset", v2Controllers)
+	if (config.Resources.CPUsetCPUs != "" || config.Resources.CPUsetMems != "") && !cpusetController {
 		warnings = addWarning(warnings, "Your kernel does not support cpuset or the cgroup is not mounted. CPUset discarded.")
 		config.Resources.CPUsetCPUs = ""
 		config.Resources.CPUsetMems = ""
 	}
-	cpusAvailable, err := sysInfo.IsCpusetCpusAvailable(config.Resources.CPUsetCPUs)
-	if err != nil {
-		return warnings, fmt.Errorf("invalid value %s for cpuset cpus", config.Resources.CPUsetCPUs)
-	}
-	if !cpusAvailable {
-		return warnings, fmt.Errorf("requested CPUs are not available - requested %s, available: %s", config.Resources.CPUsetCPUs, sysInfo.Cpus)
+	if config.Resources.CPUsetCPUs != "" {
+		online := readOnlineList(onlineCPUsPath)
+		cpusOnline, err := cpusetAvailable(config.Resources.CPUsetCPUs, online)
+		if err != nil {
+			return warnings, fmt.Errorf("invalid value %s for cpuset cpus", config.Resources.CPUsetCPUs)
+		}
+		if !cpusOnline {
+			return warnings, fmt.Errorf("requested CPUs are not online - requested %s, online: %s", config.Resources.CPUsetCPUs, online)
+		}
 	}
-	memsAvailable, err := sysInfo.IsCpusetMemsAvailable(config.Resources.CPUsetMems)
-	if err != nil {
-		return warnings, fmt.Errorf("invalid value %s for cpuset mems", config.Resources.CPUsetMems)
+	if config.Resources.CPUsetMems != "" {
+		online := readOnlineList(onlineNodesPath)
+		memsOnline, err := cpusetAvailable(config.Resources.CPUsetMems, online)
+		if err != nil {
+			return warnings, fmt.Errorf("invalid value %s for cpuset mems", config.Resources.CPUsetMems)
+		}
+		if !memsOnline {
+			return warnings, fmt.Errorf("requested memory nodes are not online - requested %s, online: %s", config.Resources.CPUsetMems, online)
+		}
 	}
-	if !memsAvailable {
-		return warnings, fmt.Errorf("requested memory nodes are not available - requested %s, available: %s", config.Resources.CPUsetMems, sysInfo.Mems)
+	if sysInfo.Cpuset {
+		cpusAvailable, err := sysInfo.IsCpusetCpusAvailable(config.Resources.CPUsetCPUs)
+		if err != nil {
+			return warnings, fmt.Errorf("invalid value %s for cpuset cpus", config.Resources.CPUsetCPUs)
+		}
+		if !cpusAvailable {
+			return warnings, fmt.Errorf("requested CPUs are not available - requested %s, available: %s", config.Resources.CPUsetCPUs, sysInfo.Cpus)
+		}
+		memsAvailable, err := sysInfo.IsCpusetMemsAvailable(config.Resources.CPUsetMems)
+		if err != nil {
+			return warnings, fmt.Errorf("invalid value %s for cpuset mems", config.Resources.CPUsetMems)
+		}
+		if !memsAvailable {
+			return warnings, fmt.Errorf("requested memory nodes are not available - requested %s, available: %s", config.Resources.CPUsetMems, sysInfo.Mems)
+		}
 	}
 
-	// blkio subsystem checks and adjustments
-	if config.Resources.BlkioWeight > 0 && !sysInfo.BlkioWeight {
+	// blkio subsystem checks and adjustments. On the unified cgroup v2
+	// hierarchy the io controller provides all the block IO limits, which
+	// the OCI runtime maps to io.weight and io.max.
+	ioController := util.StringInSlice("io", v2Controllers)
+	if config.Resources.BlkioWeight > 0 && !sysInfo.BlkioWeight && !ioController {
 		warnings = addWarning(warnings, "Your kernel does not support Block I/O weight or the cgroup is not mounted. Weight discarded.")
 		config.Resources.BlkioWeight = 0
 	}
 	if config.Resources.BlkioWeight > 0 && (config.Resources.BlkioWeight < 10 || config.Resources.BlkioWeight > 1000) {
 		return warnings, fmt.Errorf("range of blkio weight is from 10 to 1000")
 	}
-	if len(config.Resources.BlkioWeightDevice) > 0 && !sysInfo.BlkioWeightDevice {
+	if len(config.Resources.BlkioWeightDevice) > 0 && !sysInfo.BlkioWeightDevice && !ioController {
 		warnings = addWarning(warnings, "Your kernel does not support Block I/O weight_device or the cgroup is not mounted. Weight-device discarded.")
 		config.Resources.BlkioWeightDevice = []string{}
 	}
-	if len(config.Resources.DeviceReadBps) > 0 && !sysInfo.BlkioReadBpsDevice {
+	if len(config.Resources.DeviceReadBps) > 0 && !sysInfo.BlkioReadBpsDevice && !ioController {
 		warnings = addWarning(warnings, "Your kernel does not support BPS Block I/O read limit or the cgroup is not mounted. Block I/O BPS read limit discarded")
 		config.Resources.DeviceReadBps = []string{}
 	}
-	if len(config.Resources.DeviceWriteBps) > 0 && !sysInfo.BlkioWriteBpsDevice {
+	if len(config.Resources.DeviceWriteBps) > 0 && !sysInfo.BlkioWriteBpsDevice && !ioController {
 		warnings = addWarning(warnings, "Your kernel does not support BPS Block I/O write limit or the cgroup is not mounted. Block I/O BPS write limit discarded.")
 		config.Resources.DeviceWriteBps = []string{}
 	}
-	if len(config.Resources.DeviceReadIOps) > 0 && !sysInfo.BlkioReadIOpsDevice {
+	if len(config.Resources.DeviceReadIOps) > 0 && !sysInfo.BlkioReadIOpsDevice && !ioController {
 		warnings = addWarning(warnings, "Your kernel does not support IOPS Block read limit or the cgroup is not mounted. Block I/O IOPS read limit discarded.")
 		config.Resources.DeviceReadIOps = []string{}
 	}
-	if len(config.Resources.DeviceWriteIOps) > 0 && !sysInfo.BlkioWriteIOpsDevice {
+	if len(config.Resources.DeviceWriteIOps) > 0 && !sysInfo.BlkioWriteIOpsDevice && !ioController {
 		warnings = addWarning(warnings, "Your kernel does not support IOPS Block I/O write limit or the cgroup is not mounted. Block I/O IOPS write limit discarded.")
 		config.Resources.DeviceWriteIOps = []string{}
 	}
 
 	return warnings, nil
 }
+
+// parseHealthCheck returns the healthcheck of a container, the healthcheck of
+// its image overridden by the --health flags, or nil if it has none. A
+// --health-cmd in JSON array form runs without a shell.
+func parseHealthCheck(c *cli.Context, image *manifest.Schema2HealthConfig) (*manifest.Schema2HealthConfig, error) {
+	healthFlags := []string{"health-cmd", "health-interval", "health-retries", "health-timeout"}
+	if c.Bool("no-healthcheck") {
+		for _, flag := range healthFlags {
+			if c.IsSet(flag) {
+				return nil, errors.Errorf("--no-healthcheck conflicts with --%s", flag)
+			}
+		}
+		return nil, nil
+	}
+
+	var hc manifest.Schema2HealthConfig
+	if image != nil && !(len(image.Test) > 0 && image.Test[0] == "NONE") {
+		hc = *image
+	}
+	if c.IsSet("health-cmd") {
+		cmd := c.String("health-cmd")
+		switch {
+		case strings.ToLower(cmd) == "none":
+			return nil, nil
+		case strings.HasPrefix(strings.TrimSpace(cmd), "["):
+			var args []string
+			if err := json.Unmarshal([]byte(cmd), &args); err != nil || len(args) == 0 {
+				return nil, errors.Errorf("invalid --health-cmd %q, must be a command or a JSON array", cmd)
+			}
+			hc.Test = append([]string{"CMD"}, args...)
+		case cmd == "":
+			return nil, errors.Errorf("--health-cmd must not be empty")
+		default:
+			hc.Test = []string{"CMD-SHELL", cmd}
+		}
+	}
+	if len(hc.Test) == 0 {
+		for _, flag := range healthFlags[1:] {
+			if c.IsSet(flag) {
+				return nil, errors.Errorf("--%s requires a healthcheck, set with --health-cmd", flag)
+			}
+		}
+		return nil, nil
+	}
+
+	if err := parseHealthDurations(c, "health-interval", &hc.Interval, "health-timeout", &hc.Timeout); err != nil {
+		return nil, err
+	}
+	if c.IsSet("health-retries") {
+		if c.Uint("health-retries") == 0 {
+			return nil, errors.Errorf("--health-retries must be at least 1")
+		}
+		hc.Retries = int(c.Uint("health-retries"))
+	}
+	return &hc, nil
+}
+
+// parseStartupHealthCheck returns the startup healthcheck of a container set
+// by the --health-startup flags, or nil if it has none. Its retries default
+// to 0, retrying it until it passes.
+func parseStartupHealthCheck(c *cli.Context) (*manifest.Schema2HealthConfig, error) {
+	if !c.IsSet("health-startup-cmd") {
+		for _, flag := range []string{"health-startup-interval", "health-startup-retries", "health-startup-timeout"} {
+			if c.IsSet(flag) {
+				return nil, errors.Errorf("--%s requires a startup healthcheck, set with --health-startup-cmd", flag)
+			}
+		}
+		return nil, nil
+	}
+	if c.Bool("no-healthcheck") {
+		return nil, errors.Errorf("--no-healthcheck conflicts with --health-startup-cmd")
+	}
+
+	var hc manifest.Schema2HealthConfig
+	cmd := c.String("health-startup-cmd")
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(cmd), "["):
+		var args []string
+		if err := json.Unmarshal([]byte(cmd), &args); err != nil || len(args) == 0 {
+			return nil, errors.Errorf("invalid --health-startup-cmd %q, must be a command or a JSON array", cmd)
+		}
+		hc.Test = append([]string{"CMD"}, args...)
+	case cmd == "":
+		return nil, errors.Errorf("--health-startup-cmd must not be empty")
+	default:
+		hc.Test = []string{"CMD-SHELL", cmd}
+	}
+	if err := parseHealthDurations(c, "health-startup-interval", &hc.Interval, "health-startup-timeout", &hc.Timeout); err != nil {
+		return nil, err
+	}
+	hc.Retries = int(c.Uint("health-startup-retries"))
+	return &hc, nil
+}
+
+// parseHealthDurations sets interval and timeout to the durations of the
+// intervalFlag and timeoutFlag flags, when they are set. They must be at
+// least a second.
+func parseHealthDurations(c *cli.Context, intervalFlag string, interval *time.Duration, timeoutFlag string, timeout *time.Duration) error {
+	for _, d := range []struct {
+		flag  string
+		value *time.Duration
+	}{{intervalFlag, interval}, {timeoutFlag, timeout}} {
+		if !c.IsSet(d.flag) {
+			continue
+		}
+		duration, err := time.ParseDuration(c.String(d.flag))
+		if err != nil {
+			return errors.Wrapf(err, "invalid --%s", d.flag)
+		}
+		if duration < time.Second {
+			return errors.Errorf("--%s must be at least 1s", d.flag)
+		}
+		*d.value = duration
+	}
+	return nil
+}
+
+// parseRestartPolicy returns the restart policy and the maximum number of
+// retries of a --restart value, such as on-failure:3. Only the on-failure
+// policy takes a maximum number of retries.
+func parseRestartPolicy(value string) (string, uint, error) {
+	split := strings.SplitN(value, ":", 2)
+	policy := split[0]
+	switch policy {
+	case libpod.RestartPolicyNo, libpod.RestartPolicyOnFailure, libpod.RestartPolicyAlways, libpod.RestartPolicyUnlessStopped:
+	default:
+		return "", 0, errors.Errorf("invalid restart policy %q, must be no, on-failure[:max-retries], always or unless-stopped", value)
+	}
+	if len(split) == 1 {
+		return policy, 0, nil
+	}
+	if policy != libpod.RestartPolicyOnFailure {
+		return "", 0, errors.Errorf("invalid restart policy %q, only on-failure takes a maximum number of retries", value)
+	}
+	retries, err := strconv.ParseUint(split[1], 10, 32)
+	if err != nil {
+		return "", 0, errors.Errorf("invalid maximum number of retries %q of restart policy %q", split[1], value)
+	}
+	return policy, uint(retries), nil
+}
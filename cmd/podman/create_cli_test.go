@@ -1,11 +1,17 @@
 package main
 
 import (
+	"flag"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/containers/image/manifest"
+	cc "github.com/containers/libpod/pkg/spec"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
 )
 
 var (
@@ -68,3 +74,176 @@ func TestGetAllLabelsFile(t *testing.T) {
 	result, _ := getAllLabels(fileLabels, Var1)
 	assert.Equal(t, len(result), 3)
 }
+
+func TestCpusetAvailable(t *testing.T) {
+	available, err := cpusetAvailable("0-3,8", "0-15")
+	assert.NoError(t, err)
+	assert.True(t, available)
+
+	available, err = cpusetAvailable("2,16", "0-15")
+	assert.NoError(t, err)
+	assert.False(t, available)
+
+	_, err = cpusetAvailable("3-1", "0-15")
+	assert.Error(t, err)
+}
+
+func TestCheckCgroupV2Memory(t *testing.T) {
+	resources := cc.CreateResourceConfig{
+		Memory:            512 * 1024 * 1024,
+		MemoryReservation: 256 * 1024 * 1024,
+		MemorySwap:        1024 * 1024 * 1024,
+		MemorySwappiness:  -1,
+	}
+	assert.NoError(t, checkCgroupV2Memory(&resources))
+
+	swappiness := resources
+	swappiness.MemorySwappiness = 10
+	assert.Error(t, checkCgroupV2Memory(&swappiness))
+
+	kernel := resources
+	kernel.KernelMemory = 64 * 1024 * 1024
+	assert.Error(t, checkCgroupV2Memory(&kernel))
+
+	swap := resources
+	swap.Memory = 0
+	assert.Error(t, checkCgroupV2Memory(&swap))
+	swap.MemorySwap = -1
+	assert.NoError(t, checkCgroupV2Memory(&swap))
+}
+
+func TestResourceLimitsSet(t *testing.T) {
+	resources := cc.CreateResourceConfig{
+		MemorySwap:       -1,
+		MemorySwappiness: -1,
+	}
+	assert.False(t, resourceLimitsSet(&resources))
+
+	memory := resources
+	memory.Memory = 512 * 1024 * 1024
+	assert.True(t, resourceLimitsSet(&memory))
+
+	cpus := resources
+	cpus.CPUs = 1.5
+	assert.True(t, resourceLimitsSet(&cpus))
+
+	pids := resources
+	pids.PidsLimit = 100
+	assert.True(t, resourceLimitsSet(&pids))
+}
+
+func healthCheckContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.String("health-cmd", "", "")
+	set.String("health-interval", "", "")
+	set.Uint("health-retries", 0, "")
+	set.String("health-timeout", "", "")
+	set.String("health-startup-cmd", "", "")
+	set.String("health-startup-interval", "", "")
+	set.Uint("health-startup-retries", 0, "")
+	set.String("health-startup-timeout", "", "")
+	set.Bool("no-healthcheck", false, "")
+	require.NoError(t, set.Parse(args))
+	return cli.NewContext(nil, set, nil)
+}
+
+func TestParseHealthCheck(t *testing.T) {
+	image := &manifest.Schema2HealthConfig{
+		Test:     []string{"CMD-SHELL", "curl -f http://localhost/"},
+		Interval: time.Minute,
+	}
+
+	hc, err := parseHealthCheck(healthCheckContext(t), nil)
+	require.NoError(t, err)
+	assert.Nil(t, hc)
+
+	hc, err = parseHealthCheck(healthCheckContext(t), image)
+	require.NoError(t, err)
+	assert.Equal(t, image, hc)
+
+	hc, err = parseHealthCheck(healthCheckContext(t, "--health-retries=5", "--health-timeout=10s"), image)
+	require.NoError(t, err)
+	assert.Equal(t, &manifest.Schema2HealthConfig{
+		Test:     []string{"CMD-SHELL", "curl -f http://localhost/"},
+		Interval: time.Minute,
+		Timeout:  10 * time.Second,
+		Retries:  5,
+	}, hc)
+
+	hc, err = parseHealthCheck(healthCheckContext(t, "--health-cmd", "pg_isready", "--health-interval", "5s"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, &manifest.Schema2HealthConfig{Test: []string{"CMD-SHELL", "pg_isready"}, Interval: 5 * time.Second}, hc)
+
+	hc, err = parseHealthCheck(healthCheckContext(t, `--health-cmd=["pg_isready", "-q"]`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CMD", "pg_isready", "-q"}, hc.Test)
+
+	for _, disable := range [][]string{{"--no-healthcheck"}, {"--health-cmd=none"}} {
+		hc, err = parseHealthCheck(healthCheckContext(t, disable...), image)
+		require.NoError(t, err)
+		assert.Nil(t, hc)
+	}
+	hc, err = parseHealthCheck(healthCheckContext(t), &manifest.Schema2HealthConfig{Test: []string{"NONE"}})
+	require.NoError(t, err)
+	assert.Nil(t, hc)
+
+	for _, args := range [][]string{
+		{"--no-healthcheck", "--health-cmd=true"},
+		{"--health-interval=10s"},
+		{"--health-cmd=true", "--health-interval=10ms"},
+		{"--health-cmd=true", "--health-timeout=ten"},
+		{"--health-cmd=true", "--health-retries=0"},
+		{"--health-cmd=[true"},
+	} {
+		_, err := parseHealthCheck(healthCheckContext(t, args...), nil)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestParseStartupHealthCheck(t *testing.T) {
+	hc, err := parseStartupHealthCheck(healthCheckContext(t, "--health-cmd", "pg_isready"))
+	require.NoError(t, err)
+	assert.Nil(t, hc)
+
+	hc, err = parseStartupHealthCheck(healthCheckContext(t, "--health-startup-cmd", "pg_isready", "--health-startup-interval", "2s"))
+	require.NoError(t, err)
+	assert.Equal(t, &manifest.Schema2HealthConfig{Test: []string{"CMD-SHELL", "pg_isready"}, Interval: 2 * time.Second}, hc)
+
+	hc, err = parseStartupHealthCheck(healthCheckContext(t, `--health-startup-cmd=["pg_isready", "-q"]`, "--health-startup-retries=10", "--health-startup-timeout=5s"))
+	require.NoError(t, err)
+	assert.Equal(t, &manifest.Schema2HealthConfig{Test: []string{"CMD", "pg_isready", "-q"}, Timeout: 5 * time.Second, Retries: 10}, hc)
+
+	for _, args := range [][]string{
+		{"--no-healthcheck", "--health-startup-cmd=true"},
+		{"--health-startup-retries=3"},
+		{"--health-startup-cmd="},
+		{"--health-startup-cmd=true", "--health-startup-interval=10ms"},
+		{"--health-startup-cmd=[true"},
+	} {
+		_, err := parseStartupHealthCheck(healthCheckContext(t, args...))
+		assert.Error(t, err, args)
+	}
+}
+
+func TestParseRestartPolicy(t *testing.T) {
+	for value, expected := range map[string]struct {
+		policy  string
+		retries uint
+	}{
+		"no":             {"no", 0},
+		"always":         {"always", 0},
+		"unless-stopped": {"unless-stopped", 0},
+		"on-failure":     {"on-failure", 0},
+		"on-failure:5":   {"on-failure", 5},
+	} {
+		policy, retries, err := parseRestartPolicy(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected.policy, policy, value)
+		assert.Equal(t, expected.retries, retries, value)
+	}
+
+	for _, value := range []string{"", "sometimes", "always:3", "on-failure:", "on-failure:-1", "on-failure:x"} {
+		_, _, err := parseRestartPolicy(value)
+		assert.Error(t, err, value)
+	}
+}
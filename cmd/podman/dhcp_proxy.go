@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/containers/libpod/libpod/shutdown"
+	"github.com/containers/libpod/pkg/dhcpproxy"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	dhcpProxyFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "socket",
+			Usage: "Path of the socket the CNI dhcp IPAM plugin connects to",
+			Value: dhcpproxy.DefaultSocketPath,
+		},
+	}
+	dhcpProxyDescription = `
+	Performs DHCP on behalf of containers attached to networks using the dhcp
+	IPAM plugin of CNI, such as macvlan networks, and renews their leases until
+	they are detached.
+`
+	dhcpProxyCommand = cli.Command{
+		Name:        "dhcp-proxy",
+		Usage:       "Run the DHCP proxy for macvlan networks",
+		Description: dhcpProxyDescription,
+		Flags:       dhcpProxyFlags,
+		Action:      dhcpProxyCmd,
+		ArgsUsage:   "",
+	}
+)
+
+func dhcpProxyCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("dhcp-proxy does not accept any arguments")
+	}
+	if err := validateFlags(c, dhcpProxyFlags); err != nil {
+		return err
+	}
+
+	// Serve until terminated
+	ctx, cancel := context.WithCancel(getContext())
+	defer cancel()
+	shutdown.Start()
+	done := make(chan struct{})
+	if err := shutdown.Register("dhcp-proxy", func(os.Signal) error {
+		cancel()
+		<-done
+		return nil
+	}); err != nil {
+		return err
+	}
+	defer close(done)
+
+	return dhcpproxy.NewProxy().Serve(ctx, c.String("socket"))
+}
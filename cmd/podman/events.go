@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/events"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	eventsFlags = []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "filter",
+			Usage: "filter output",
+		},
+		cli.StringFlag{
+			Name:  "cursor",
+			Usage: "show the events following the one with this cursor, with --stream=false",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "format the output using a Go template or json",
+		},
+		cli.UintFlag{
+			Name:  "limit",
+			Usage: "show at most this number of events, with --stream=false",
+		},
+		cli.UintFlag{
+			Name:  "offset",
+			Usage: "skip this number of matching events, with --stream=false",
+		},
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "show all events created since timestamp",
+		},
+		cli.StringFlag{
+			Name:  "until",
+			Usage: "show all events created until timestamp",
+		},
+		cli.BoolTFlag{
+			Name:  "stream",
+			Usage: "stream new events; for testing only",
+		},
+	}
+	eventsDescription = "Monitor podman events.  Past events are shown first, followed by new events until the command is interrupted or the --until time is reached."
+	eventsCommand     = cli.Command{
+		Name:                   "events",
+		Usage:                  "show podman events",
+		Description:            eventsDescription,
+		Flags:                  eventsFlags,
+		Action:                 eventsCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func eventsCmd(c *cli.Context) error {
+	if err := validateFlags(c, eventsFlags); err != nil {
+		return err
+	}
+	if len(c.Args()) > 0 {
+		return errors.Errorf("'podman events' takes no arguments")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var tmpl *template.Template
+	format := c.String("format")
+	if format != "" && format != formats.JSONString {
+		if tmpl, err = formats.Parse(format); err != nil {
+			return errors.Wrapf(err, "invalid format %q", format)
+		}
+	}
+
+	// Stop reading events if their output fails
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	options := events.ReadOptions{
+		Context:      ctx,
+		EventChannel: make(chan *events.Event),
+		Filters:      c.StringSlice("filter"),
+		Stream:       c.BoolT("stream"),
+		Limit:        int(c.Uint("limit")),
+		Offset:       int(c.Uint("offset")),
+		Cursor:       c.String("cursor"),
+	}
+	for _, flag := range []string{"cursor", "limit", "offset"} {
+		if c.IsSet(flag) && options.Stream {
+			return errors.Errorf("--%s requires --stream=false", flag)
+		}
+	}
+	if c.IsSet("since") {
+		if options.Since, err = parseInputTime(c.String("since")); err != nil {
+			return errors.Wrapf(err, "could not parse time: %q", c.String("since"))
+		}
+	}
+	if c.IsSet("until") {
+		if options.Until, err = parseInputTime(c.String("until")); err != nil {
+			return errors.Wrapf(err, "could not parse time: %q", c.String("until"))
+		}
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- runtime.Events(options)
+	}()
+	for event := range options.EventChannel {
+		switch {
+		case format == formats.JSONString:
+			jsonStr, err := event.ToJSONString()
+			if err != nil {
+				return errors.Wrapf(err, "unable to format event as json")
+			}
+			fmt.Println(jsonStr)
+		case tmpl != nil:
+			if err := tmpl.Execute(os.Stdout, event); err != nil {
+				return err
+			}
+			fmt.Println()
+		default:
+			fmt.Println(event.ToHumanReadable())
+		}
+	}
+	return <-readErr
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var (
+	generateDescription = `Generate configuration files from containers and pods, such as the
+   systemd service units or the Kubernetes YAML running them.`
+	generateSubCommands = []cli.Command{
+		generateKubeCommand,
+		generateSystemdCommand,
+	}
+	generateCommand = cli.Command{
+		Name:                   "generate",
+		Usage:                  "Generate structured data based on containers and pods",
+		Description:            generateDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            generateSubCommands,
+	}
+)
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/adapter/kube"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"k8s.io/api/core/v1"
+)
+
+var (
+	generateKubeFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "service, s",
+			Usage: "Also generate a Service publishing the ports of the pod",
+		},
+	}
+	generateKubeDescription = `Generates the Kubernetes YAML of a Pod running a container, or the
+   containers of a pod, with their ports, bind mounts, environment and
+   resource limits.  With --service, the YAML of a NodePort Service
+   publishing their ports is generated too.  podman play kube creates the
+   pod and its containers from the YAML again.`
+	generateKubeCommand = cli.Command{
+		Name:                   "kube",
+		Usage:                  "Generate Kubernetes YAML based on a container or pod",
+		Description:            generateKubeDescription,
+		Flags:                  generateKubeFlags,
+		Action:                 generateKubeCmd,
+		ArgsUsage:              "CONTAINER | POD",
+		UseShortOptionHandling: true,
+	}
+)
+
+func generateKubeCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.Errorf("you must provide one container or pod")
+	}
+	if err := validateFlags(c, generateKubeFlags); err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var pod *v1.Pod
+	if ctr, err := runtime.LookupContainer(args[0]); err == nil {
+		if pod, err = kube.GenerateContainerPod(ctr); err != nil {
+			return err
+		}
+	} else if errors.Cause(err) != libpod.ErrNoSuchCtr {
+		return err
+	} else {
+		libpodPod, err := runtime.LookupPod(args[0])
+		if err != nil {
+			if errors.Cause(err) == libpod.ErrNoSuchPod {
+				return errors.Errorf("no container or pod %q found", args[0])
+			}
+			return err
+		}
+		var infra *libpod.Container
+		if libpodPod.HasInfraContainer() {
+			infraID, err := libpodPod.InfraContainerID()
+			if err != nil {
+				return err
+			}
+			if infra, err = runtime.LookupContainer(infraID); err != nil {
+				return errors.Wrapf(err, "error looking up the infra container of pod %s", libpodPod.ID())
+			}
+		}
+		if pod, err = kube.GeneratePod(libpodPod, infra); err != nil {
+			return err
+		}
+	}
+
+	objects := []interface{}{pod}
+	if c.Bool("service") {
+		service, err := kube.GenerateService(pod)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, service)
+	}
+	content, err := kube.Marshal(objects...)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(content))
+	return nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/systemdgen"
+	"github.com/containers/libpod/version"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	generateSystemdFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "files, f",
+			Usage: "Write the units to files in the current directory rather than to stdout",
+		},
+		cli.BoolFlag{
+			Name:  "name, n",
+			Usage: "Use the names of the container and pod rather than their IDs",
+		},
+		cli.BoolFlag{
+			Name:  "new",
+			Usage: "Create the container when the unit starts and remove it when it stops",
+		},
+		cli.StringFlag{
+			Name:  "restart-policy",
+			Usage: "Restart policy of the units",
+			Value: "on-failure",
+		},
+		cli.UintFlag{
+			Name:  "time, t",
+			Usage: "Seconds to wait for the containers to stop before killing them (default the stop timeout of the container)",
+		},
+	}
+	generateSystemdDescription = `Generates the systemd service unit of a container, or the units of a pod
+   and of its containers.  The units start the containers with podman and
+   track their conmon process.  With --new, the container is created as it
+   was each time its unit starts, and removed when it stops.`
+	generateSystemdCommand = cli.Command{
+		Name:                   "systemd",
+		Usage:                  "Generate systemd units for a container or pod",
+		Description:            generateSystemdDescription,
+		Flags:                  generateSystemdFlags,
+		Action:                 generateSystemdCmd,
+		ArgsUsage:              "CONTAINER | POD",
+		UseShortOptionHandling: true,
+	}
+)
+
+// generateSystemdOptions are the options of podman generate systemd
+type generateSystemdOptions struct {
+	executable    string
+	useName       bool
+	new           bool
+	restartPolicy string
+	stopTimeout   *uint
+}
+
+// serviceName returns the name of the unit of a container or pod
+func (o *generateSystemdOptions) serviceName(kind, id, name string) string {
+	if o.useName {
+		return kind + "-" + name
+	}
+	return kind + "-" + id
+}
+
+// containerService returns the service of a container, bound to the service
+// of its pod if it is set
+func (o *generateSystemdOptions) containerService(ctr *libpod.Container, podService string) (*systemdgen.ServiceInfo, error) {
+	info := &systemdgen.ServiceInfo{
+		ServiceName:   o.serviceName("container", ctr.ID(), ctr.Name()),
+		Executable:    o.executable,
+		ContainerName: ctr.ID(),
+		RestartPolicy: o.restartPolicy,
+		StopTimeout:   ctr.StopTimeout(),
+		PIDFile:       ctr.ConmonPidFile(),
+		PodmanVersion: version.Version,
+	}
+	if o.useName {
+		info.ContainerName = ctr.Name()
+	}
+	if o.stopTimeout != nil {
+		info.StopTimeout = *o.stopTimeout
+	}
+	if podService != "" {
+		info.BoundToServices = []string{podService}
+	}
+	if o.new {
+		if len(ctr.CreateCommand()) == 0 {
+			return nil, errors.Errorf("container %s was not created by the podman CLI, or before podman recorded how containers are created", ctr.ID())
+		}
+		// The container is created again with another ID
+		info.ContainerName = ctr.Name()
+		cmd, err := systemdgen.NewCreateCommand(ctr.CreateCommand(), o.executable, ctr.Name())
+		if err != nil {
+			return nil, err
+		}
+		info.CreateCommand = cmd
+		info.PIDFile = systemdgen.NewPIDFile
+	}
+	return info, nil
+}
+
+// podServices returns the service of a pod, which starts its infra
+// container, followed by the services of its other containers
+func (o *generateSystemdOptions) podServices(runtime *libpod.Runtime, pod *libpod.Pod) ([]*systemdgen.ServiceInfo, error) {
+	if o.new {
+		return nil, errors.Errorf("--new is not supported for pods, which do not record how they were created")
+	}
+	if !pod.HasInfraContainer() {
+		return nil, errors.Errorf("pod %s has no infra container, generate the units of its containers instead", pod.ID())
+	}
+	infraID, err := pod.InfraContainerID()
+	if err != nil {
+		return nil, err
+	}
+	infra, err := runtime.LookupContainer(infraID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error looking up the infra container of pod %s", pod.ID())
+	}
+	podService, err := o.containerService(infra, "")
+	if err != nil {
+		return nil, err
+	}
+	podService.ServiceName = o.serviceName("pod", pod.ID(), pod.Name())
+
+	ctrs, err := pod.AllContainers()
+	if err != nil {
+		return nil, err
+	}
+	services := []*systemdgen.ServiceInfo{podService}
+	for _, ctr := range ctrs {
+		if ctr.ID() == infraID {
+			continue
+		}
+		service, err := o.containerService(ctr, podService.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+		podService.RequiredServices = append(podService.RequiredServices, service.ServiceName)
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+func generateSystemdCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.Errorf("you must provide one container or pod")
+	}
+	if err := validateFlags(c, generateSystemdFlags); err != nil {
+		return err
+	}
+	opts := &generateSystemdOptions{
+		useName:       c.Bool("name"),
+		new:           c.Bool("new"),
+		restartPolicy: c.String("restart-policy"),
+	}
+	if err := systemdgen.ValidateRestartPolicy(opts.restartPolicy); err != nil {
+		return err
+	}
+	if c.IsSet("time") {
+		stopTimeout := c.Uint("time")
+		opts.stopTimeout = &stopTimeout
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Wrapf(err, "error finding the path of podman")
+	}
+	opts.executable = executable
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var services []*systemdgen.ServiceInfo
+	if ctr, err := runtime.LookupContainer(args[0]); err == nil {
+		service, err := opts.containerService(ctr, "")
+		if err != nil {
+			return err
+		}
+		services = append(services, service)
+	} else if errors.Cause(err) != libpod.ErrNoSuchCtr {
+		return err
+	} else {
+		pod, err := runtime.LookupPod(args[0])
+		if err != nil {
+			if errors.Cause(err) == libpod.ErrNoSuchPod {
+				return errors.Errorf("no container or pod %q found", args[0])
+			}
+			return err
+		}
+		if services, err = opts.podServices(runtime, pod); err != nil {
+			return err
+		}
+	}
+
+	for i, service := range services {
+		unit, err := service.Generate()
+		if err != nil {
+			return err
+		}
+		if !c.Bool("files") {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Print(unit)
+			continue
+		}
+		path, err := filepath.Abs(service.ServiceName + ".service")
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(unit), 0644); err != nil {
+			return errors.Wrapf(err, "error writing unit %s", path)
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var (
+	healthcheckDescription = `Manage the healthchecks of containers, which run at their interval while
+   the containers run.`
+	healthcheckSubCommands = []cli.Command{
+		healthcheckRunCommand,
+	}
+	healthcheckCommand = cli.Command{
+		Name:                   "healthcheck",
+		Usage:                  "Manage the healthchecks of containers",
+		Description:            healthcheckDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            healthcheckSubCommands,
+	}
+)
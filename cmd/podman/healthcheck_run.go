@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	healthcheckRunDescription = `
+   Runs the healthcheck of a container once and records its result, as the
+   timer of the container does at its interval. Prints healthy and exits 0
+   if the healthcheck passes, prints unhealthy and exits 1 if it fails.
+`
+	healthcheckRunCommand = cli.Command{
+		Name:        "run",
+		Usage:       "Run the healthcheck of a container",
+		Description: healthcheckRunDescription,
+		Action:      healthcheckRunCmd,
+		ArgsUsage:   "CONTAINER",
+	}
+)
+
+func healthcheckRunCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.Errorf("podman healthcheck run takes a container")
+	}
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "error creating libpod runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	status, err := runtime.HealthCheck(args[0])
+	if err != nil {
+		return err
+	}
+	if status == libpod.HealthCheckFailure {
+		fmt.Println("unhealthy")
+		exitCode = 1
+		return nil
+	}
+	fmt.Println("healthy")
+	return nil
+}
@@ -11,13 +11,16 @@ var (
 		importCommand,
 		inspectCommand,
 		loadCommand,
+		imageLockCommand,
 		lsImagesCommand,
 		//		pruneCommand,
 		pullCommand,
 		pushCommand,
 		rmImageCommand,
 		saveCommand,
+		imageSignCommand,
 		tagCommand,
+		trustCommand,
 	}
 
 	imageDescription = "Manage images"
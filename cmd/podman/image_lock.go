@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/containers/image/types"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	imageLockFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all, a",
+			Usage: "Pin the images of all containers",
+		},
+		cli.StringFlag{
+			Name:  "authfile",
+			Usage: "Path of the authentication file. Default is ${XDG_RUNTIME_DIR}/containers/auth.json",
+		},
+		cli.StringFlag{
+			Name:  "cert-dir",
+			Usage: "`pathname` of a directory containing TLS certificates and keys",
+		},
+		cli.StringFlag{
+			Name:  "creds",
+			Usage: "`credentials` (USERNAME:PASSWORD) to use for authenticating to a registry",
+		},
+		cli.StringSliceFlag{
+			Name:  "kube",
+			Usage: "Pin the images of the containers of a Kubernetes YAML file (default [])",
+		},
+		cli.StringFlag{
+			Name:  "output, o",
+			Usage: "Write the lockfile to the file instead of stdout",
+		},
+		cli.BoolTFlag{
+			Name:  "tls-verify",
+			Usage: "require HTTPS and verify certificates when contacting registries (default: true)",
+		},
+	}
+
+	imageLockDescription = `
+Resolves the tags of the images used by containers, or by the containers of
+Kubernetes YAML files, to the digests of their manifests, and writes them to a
+lockfile. Containers created with --image-lock and the lockfile only use these
+digests.
+`
+	imageLockCommand = cli.Command{
+		Name:                   "lock",
+		Usage:                  "Pin the images of containers by digest in a lockfile",
+		Description:            imageLockDescription,
+		Flags:                  imageLockFlags,
+		Action:                 imageLockCmd,
+		ArgsUsage:              "[CONTAINER...]",
+		UseShortOptionHandling: true,
+	}
+)
+
+func imageLockCmd(c *cli.Context) error {
+	if err := validateFlags(c, imageLockFlags); err != nil {
+		return err
+	}
+	args := c.Args()
+	if len(args) == 0 && !c.Bool("all") && len(c.StringSlice("kube")) == 0 {
+		return errors.Errorf("containers, --all or --kube must be given")
+	}
+	if len(args) > 0 && c.Bool("all") {
+		return errors.Errorf("--all and containers cannot be used together")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var names []string
+	var containers []*libpod.Container
+	if c.Bool("all") {
+		if containers, err = runtime.GetAllContainers(); err != nil {
+			return err
+		}
+	}
+	for _, arg := range args {
+		ctr, err := runtime.LookupContainer(arg)
+		if err != nil {
+			return errors.Wrapf(err, "unable to find container %s", arg)
+		}
+		containers = append(containers, ctr)
+	}
+	for _, ctr := range containers {
+		// Containers created from a root filesystem have no image
+		if id, name := ctr.Image(); id != "" {
+			if name == "" {
+				name = id
+			}
+			names = append(names, name)
+		}
+	}
+	for _, path := range c.StringSlice("kube") {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "error reading %q", path)
+		}
+		pods, err := readKubePods(content)
+		if err != nil {
+			return errors.Wrapf(err, "invalid Kubernetes YAML %q", path)
+		}
+		for _, pod := range pods {
+			for _, kubeCtr := range pod.Spec.Containers {
+				names = append(names, kubeCtr.Image)
+			}
+		}
+	}
+
+	var registryCreds *types.DockerAuthConfig
+	if c.IsSet("creds") {
+		if registryCreds, err = util.ParseRegistryCreds(c.String("creds")); err != nil {
+			return err
+		}
+	}
+	dockerRegistryOptions := image.DockerRegistryOptions{
+		DockerRegistryCreds:         registryCreds,
+		DockerCertPath:              c.String("cert-dir"),
+		DockerInsecureSkipTLSVerify: !c.BoolT("tls-verify"),
+	}
+	sc := dockerRegistryOptions.GetSystemContext(image.GetSystemContext("", c.String("authfile"), false), nil)
+
+	lockfile, err := runtime.ImageRuntime().LockfileForNames(getContext(), names, sc)
+	if err != nil {
+		return err
+	}
+	if c.IsSet("output") {
+		return lockfile.Write(c.String("output"))
+	}
+	data, err := lockfile.Marshal()
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+// lockedImageName returns the reference of an image to create a container
+// with. In locked mode, with --image-lock, it is the reference pinned by the
+// lockfile, and references not pinned by digest are refused.
+func lockedImageName(c *cli.Context, name string) (string, error) {
+	if !c.IsSet("image-lock") {
+		return name, nil
+	}
+	lockfile, err := image.ReadLockfile(c.String("image-lock"))
+	if err != nil {
+		return "", err
+	}
+	return lockfile.Pin(name)
+}
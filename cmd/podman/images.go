@@ -131,6 +131,7 @@ var (
 	}
 	lsImagesCommand = cli.Command{
 		Name:                   "ls",
+		Aliases:                []string{"list"},
 		Usage:                  "list images in local storage",
 		Description:            imagesDescription,
 		Flags:                  imagesFlags,
@@ -264,12 +265,12 @@ func getImagesTemplateOutput(ctx context.Context, runtime *libpod.Runtime, image
 	for _, img := range images {
 		// If all is false and the image doesn't have a name, check to see if the top layer of the image is a parent
 		// to another image's top layer. If it is, then it is an intermediate image so don't print out if the --all flag
-		// is not set.
+		// is not set. The same goes for the images of the build cache.
 		isParent, err := img.IsParent()
 		if err != nil {
 			logrus.Errorf("error checking if image is a parent %q: %v", img.ID(), err)
 		}
-		if !opts.all && len(img.Names()) == 0 && isParent {
+		if !opts.all && len(img.Names()) == 0 && (isParent || img.IsBuildCache()) {
 			continue
 		}
 		createdTime := img.Created()
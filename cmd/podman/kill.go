@@ -6,6 +6,8 @@ import (
 
 	"fmt"
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/cmd/podman/shared"
+	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/docker/docker/pkg/signal"
 	"github.com/pkg/errors"
@@ -14,6 +16,10 @@ import (
 
 var (
 	killFlags = []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "filter, f",
+			Usage: "kill all running containers matching the given filters",
+		},
 		cli.StringFlag{
 			Name:  "signal, s",
 			Usage: "Signal to send to the container",
@@ -36,11 +42,15 @@ var (
 // killCmd kills one or more containers with a signal
 func killCmd(c *cli.Context) error {
 	args := c.Args()
-	if len(args) == 0 && !c.Bool("latest") {
+	filters := c.StringSlice("filter")
+	if len(args) == 0 && !c.Bool("latest") && len(filters) == 0 {
 		return errors.Errorf("specify one or more containers to kill")
 	}
-	if len(args) > 0 && c.Bool("latest") {
-		return errors.Errorf("you cannot specific any containers to kill with --latest")
+	if len(args) > 0 && (c.Bool("latest") || len(filters) > 0) {
+		return errors.Errorf("you cannot specify any containers to kill with --latest or --filter")
+	}
+	if c.Bool("latest") && len(filters) > 0 {
+		return errors.Errorf("--latest and --filter cannot be used together")
 	}
 	if err := validateFlags(c, killFlags); err != nil {
 		return err
@@ -72,6 +82,24 @@ func killCmd(c *cli.Context) error {
 		args = append(args, latestCtr.ID())
 	}
 
+	if len(filters) > 0 {
+		filterFuncs, err := shared.ParseContainerFilters(filters, runtime)
+		if err != nil {
+			return err
+		}
+		filterFuncs = append(filterFuncs, func(c *libpod.Container) bool {
+			state, _ := c.State()
+			return state == libpod.ContainerStateRunning
+		})
+		containers, err := runtime.GetContainers(filterFuncs...)
+		if err != nil {
+			return errors.Wrapf(err, "unable to get containers matching filters")
+		}
+		for _, ctr := range containers {
+			args = append(args, ctr.ID())
+		}
+	}
+
 	var lastError error
 	for _, container := range args {
 		ctr, err := runtime.LookupContainer(container)
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 
 	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/audit"
+	"github.com/containers/libpod/pkg/nested"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/storage"
 	"github.com/pkg/errors"
@@ -82,6 +84,11 @@ func GetRuntimeWithStorageOpts(c *cli.Context, storageOpts *storage.StoreOptions
 	if c.GlobalIsSet("storage-opt") {
 		storageOpts.GraphDriverOptions = c.GlobalStringSlice("storage-opt")
 	}
+	nestedOpts, err := nested.StoreOptions(*storageOpts)
+	if err != nil {
+		return nil, err
+	}
+	*storageOpts = nestedOpts
 
 	options = append(options, libpod.WithStorageConfig(*storageOpts))
 
@@ -99,6 +106,9 @@ func GetRuntimeWithStorageOpts(c *cli.Context, storageOpts *storage.StoreOptions
 	if c.GlobalIsSet("conmon") {
 		options = append(options, libpod.WithConmonPath(c.GlobalString("conmon")))
 	}
+	if c.GlobalBool("read-only") {
+		options = append(options, libpod.WithReadOnly())
+	}
 	if c.GlobalIsSet("tmpdir") {
 		options = append(options, libpod.WithTmpDir(c.GlobalString("tmpdir")))
 	}
@@ -106,7 +116,14 @@ func GetRuntimeWithStorageOpts(c *cli.Context, storageOpts *storage.StoreOptions
 	if c.GlobalIsSet("cgroup-manager") {
 		options = append(options, libpod.WithCgroupManager(c.GlobalString("cgroup-manager")))
 	} else {
+		// Containers usually have no systemd to manage cgroups, and
+		// rootless users only get cgroups from their systemd instance
+		// on cgroup v2
+		useCgroupfs := nested.Level() > 0 && !nested.SystemdRunning()
 		if rootless.IsRootless() {
+			useCgroupfs = rootless.CgroupV2Controllers() == nil
+		}
+		if useCgroupfs {
 			options = append(options, libpod.WithCgroupManager("cgroupfs"))
 		}
 	}
@@ -133,5 +150,32 @@ func GetRuntimeWithStorageOpts(c *cli.Context, storageOpts *storage.StoreOptions
 		options = append(options, libpod.WithDefaultInfraCommand(c.String("infra-command")))
 	}
 
-	return libpod.NewRuntime(options...)
+	runtime, err := libpod.NewRuntime(options...)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeAuditRecord(c, runtime); err != nil {
+		runtime.Shutdown(false)
+		return nil, err
+	}
+	return runtime, nil
+}
+
+// AuditRecordKey is the key of the app metadata holding the audit record of
+// the command, written to the audit log once the runtime is created
+const AuditRecordKey = "auditRecord"
+
+// writeAuditRecord writes the audit record of the command to the audit log of
+// the runtime, once, if auditing is enabled
+func writeAuditRecord(c *cli.Context, runtime *libpod.Runtime) error {
+	record, ok := c.App.Metadata[AuditRecordKey].(*audit.Record)
+	if !ok {
+		return nil
+	}
+	delete(c.App.Metadata, AuditRecordKey)
+	auditLog := runtime.AuditLog()
+	if auditLog == nil {
+		return nil
+	}
+	return errors.Wrapf(auditLog.Write(*record), "error writing audit record")
 }
@@ -5,11 +5,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"strings"
 
 	"github.com/containers/image/docker"
 	"github.com/containers/image/pkg/docker/config"
 	"github.com/containers/libpod/libpod/common"
+	"github.com/containers/libpod/pkg/auth"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 	"golang.org/x/crypto/ssh/terminal"
@@ -29,6 +31,10 @@ var (
 			Name:  "authfile",
 			Usage: "Path of the authentication file. Default is ${XDG_RUNTIME_DIR}/containers/auth.json",
 		},
+		cli.StringFlag{
+			Name:  "credential-helper",
+			Usage: "Store the credentials of the registry with the docker-credential-`HELPER` program instead of the authentication file",
+		},
 		cli.StringFlag{
 			Name:  "cert-dir",
 			Usage: "Pathname of a directory containing TLS certificates and keys used to connect to the registry",
@@ -64,7 +70,11 @@ func loginCmd(c *cli.Context) error {
 		server = args[0]
 	}
 
-	sc := common.GetSystemContext("", c.String("authfile"), false)
+	authfile, err := auth.GetAuthFile(c.String("authfile"))
+	if err != nil {
+		return err
+	}
+	sc := common.GetSystemContext("", authfile, false)
 
 	// username of user logged in to server (if one exists)
 	userFromAuthFile, err := config.GetUserLoggedIn(sc, server)
@@ -80,12 +90,17 @@ func loginCmd(c *cli.Context) error {
 		sc.DockerCertPath = c.String("cert-dir")
 	}
 
-	if err = docker.CheckAuth(context.TODO(), sc, username, password, server); err == nil {
-		if err := config.SetAuthentication(sc, server, username, password); err != nil {
+	if helper := c.String("credential-helper"); helper != "" {
+		if _, err := exec.LookPath("docker-credential-" + helper); err != nil {
+			return errors.Wrapf(err, "credential helper %q not found", helper)
+		}
+		if err := auth.SetCredentialHelper(authfile, server, helper); err != nil {
 			return err
 		}
 	}
-	switch err {
+
+	err = auth.Login(context.TODO(), sc, server, username, password)
+	switch errors.Cause(err) {
 	case nil:
 		fmt.Println("Login Succeeded!")
 		return nil
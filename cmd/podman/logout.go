@@ -5,6 +5,7 @@ import (
 
 	"github.com/containers/image/pkg/docker/config"
 	"github.com/containers/libpod/libpod/common"
+	"github.com/containers/libpod/pkg/auth"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
@@ -46,18 +47,22 @@ func logoutCmd(c *cli.Context) error {
 		server = args[0]
 	}
 
-	sc := common.GetSystemContext("", c.String("authfile"), false)
+	authfile, err := auth.GetAuthFile(c.String("authfile"))
+	if err != nil {
+		return err
+	}
+	sc := common.GetSystemContext("", authfile, false)
 
 	if c.Bool("all") {
-		if err := config.RemoveAllAuthentication(sc); err != nil {
+		if err := auth.LogoutAll(sc); err != nil {
 			return err
 		}
 		fmt.Println("Remove login credentials for all registries")
 		return nil
 	}
 
-	err := config.RemoveAuthentication(sc, server)
-	switch err {
+	err = auth.Logout(sc, server)
+	switch errors.Cause(err) {
 	case nil:
 		fmt.Printf("Remove login credentials for %s\n", server)
 		return nil
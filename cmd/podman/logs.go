@@ -123,21 +123,26 @@ func logsCmd(c *cli.Context) error {
 		}
 	}
 
-	file, err := os.Open(logPath)
-	if err != nil {
-		return errors.Wrapf(err, "unable to read container log file")
+	// The rotated segments of the log are read before the log file
+	var readers []io.Reader
+	var file *os.File
+	for _, path := range ctr.LogPaths() {
+		if file, err = os.Open(path); err != nil {
+			return errors.Wrapf(err, "unable to read container log file")
+		}
+		defer file.Close()
+		readers = append(readers, file)
 	}
-	defer file.Close()
-	reader := bufio.NewReader(file)
+	reader := bufio.NewReader(io.MultiReader(readers...))
 	if opts.follow {
-		followLog(reader, opts, ctr)
+		followLog(reader, file, opts, ctr)
 	} else {
 		dumpLog(reader, opts)
 	}
 	return err
 }
 
-func followLog(reader *bufio.Reader, opts logOptions, ctr *libpod.Container) error {
+func followLog(reader *bufio.Reader, file *os.File, opts logOptions, ctr *libpod.Container) error {
 	var cacheOutput []string
 	firstPass := false
 	if opts.tail > 0 {
@@ -170,6 +175,12 @@ func followLog(reader *bufio.Reader, opts logOptions, ctr *libpod.Container) err
 			if state != libpod.ContainerStateRunning && state != libpod.ContainerStatePaused {
 				break
 			}
+			// Follow the log file, from its start once it is truncated
+			// by a rotation
+			if err := rewindRotatedLog(file); err != nil {
+				return err
+			}
+			reader.Reset(file)
 			continue
 		}
 		// exits
@@ -185,6 +196,25 @@ func followLog(reader *bufio.Reader, opts logOptions, ctr *libpod.Container) err
 	return nil
 }
 
+// rewindRotatedLog seeks back to the start of a log file truncated since it
+// was read
+func rewindRotatedLog(file *os.File) error {
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read container log file")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "unable to read container log file")
+	}
+	if info.Size() < offset {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return errors.Wrapf(err, "unable to read container log file")
+		}
+	}
+	return nil
+}
+
 func dumpLog(reader *bufio.Reader, opts logOptions) error {
 	output := readLog(reader, opts)
 	for _, line := range output {
@@ -2,11 +2,16 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"runtime/pprof"
+	"strconv"
+	"strings"
 	"syscall"
 
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/pkg/hooks"
 	_ "github.com/containers/libpod/pkg/hooks/0.1.0"
 	"github.com/containers/libpod/pkg/rootless"
@@ -34,6 +39,104 @@ var cmdsNotRequiringRootless = map[string]bool{
 	"kill":    true,
 	"search":  true,
 	"stop":    true,
+	// Checking the subordinate IDs of a user must work without them
+	"system subids": true,
+}
+
+// cmdsAllowedReadOnly are the commands that can be run in read-only mode, by
+// name: subcommands are listed with the names of their groups, aliases
+// resolve to the names they stand for.
+var cmdsAllowedReadOnly = map[string]bool{
+	"help":    true,
+	"version": true,
+	"diff":    true,
+	"events":  true,
+	"history": true,
+	"images":  true,
+	"info":    true,
+	"inspect": true,
+	"logs":    true,
+	"port":    true,
+	"ps":      true,
+	"search":  true,
+	"stats":   true,
+	"top":     true,
+	"wait":    true,
+
+	"artifact inspect":  true,
+	"artifact ls":       true,
+	"container diff":    true,
+	"container inspect": true,
+	"container logs":    true,
+	"container ls":      true,
+	"container port":    true,
+	"container stats":   true,
+	"container top":     true,
+	"container wait":    true,
+	"generate kube":     true,
+	"generate systemd":  true,
+	"image history":     true,
+	"image inspect":     true,
+	"image ls":          true,
+	"manifest inspect":  true,
+	"name ls":           true,
+	"pod inspect":       true,
+	"pod ps":            true,
+	"pod stats":         true,
+	"pod top":           true,
+	"system audit":      true,
+	"system df":         true,
+	"system subids":     true,
+
+	"image trust show": true,
+}
+
+// allowedReadOnly returns whether the command given by the arguments, among
+// commands, can be run in read-only mode. The help of any group of commands
+// can.
+func allowedReadOnly(commands []cli.Command, args []string) bool {
+	cmd, names, i := resolveCommand(commands, args)
+	if cmd == nil {
+		return false
+	}
+	if len(cmd.Subcommands) > 0 && i < len(args) && (args[i] == "help" || args[i] == "h") {
+		return true
+	}
+	return cmdsAllowedReadOnly[strings.Join(names, " ")]
+}
+
+// requiresRootless returns whether the command given by the arguments must
+// run in the rootless user namespace
+func requiresRootless(args cli.Args) bool {
+	if cmdsNotRequiringRootless[args.First()] {
+		return false
+	}
+	return len(args) < 2 || !cmdsNotRequiringRootless[args.First()+" "+args.Get(1)]
+}
+
+// becomeRootInUserNS re-executes podman in the rootless user and mount
+// namespaces. The namespaces of the pause process are joined when it is
+// running, so all the podman processes of a user see the same storage and
+// mounts, otherwise new ones are created with a new pause process.
+func becomeRootInUserNS() (bool, int, error) {
+	pausePidPath, err := libpod.GetRootlessPauseProcessPidPath()
+	if err != nil {
+		return false, -1, errors.Wrapf(err, "could not get pause process pid file path")
+	}
+	data, err := ioutil.ReadFile(pausePidPath)
+	if err == nil {
+		pausePid, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 0)
+		// The pid of a pause process that is gone may have been reused
+		comm, _ := ioutil.ReadFile(fmt.Sprintf("/proc/%d/comm", pausePid))
+		if err == nil && strings.TrimSpace(string(comm)) == "podman pause" {
+			became, ret, err := rootless.JoinUserAndMountNS(uint(pausePid))
+			if err == nil {
+				return became, ret, nil
+			}
+			logrus.Debugf("cannot join the namespaces of the pause process %d, creating new ones: %v", pausePid, err)
+		}
+	}
+	return rootless.BecomeRootInUserNS(pausePidPath)
 }
 
 func main() {
@@ -51,14 +154,21 @@ func main() {
 	app.Version = version.Version
 
 	app.Commands = []cli.Command{
+		artifactCommand,
 		attachCommand,
+		bindHelperCommand,
+		builderCommand,
 		commitCommand,
 		containerCommand,
 		buildCommand,
 		createCommand,
+		dhcpProxyCommand,
 		diffCommand,
 		execCommand,
+		eventsCommand,
 		exportCommand,
+		generateCommand,
+		healthcheckCommand,
 		historyCommand,
 		imageCommand,
 		imagesCommand,
@@ -70,8 +180,12 @@ func main() {
 		loginCommand,
 		logoutCommand,
 		logsCommand,
+		manifestCommand,
 		mountCommand,
+		nameCommand,
+		networkCommand,
 		pauseCommand,
+		playCommand,
 		psCommand,
 		podCommand,
 		portCommand,
@@ -86,10 +200,12 @@ func main() {
 		startCommand,
 		statsCommand,
 		stopCommand,
+		systemCommand,
 		tagCommand,
 		topCommand,
 		umountCommand,
 		unpauseCommand,
+		updateCommand,
 		versionCommand,
 		waitCommand,
 	}
@@ -100,9 +216,12 @@ func main() {
 
 	app.Before = func(c *cli.Context) error {
 		args := c.Args()
+		if c.GlobalBool("read-only") && args.Present() && !allowedReadOnly(c.App.Commands, args) {
+			return errors.Errorf("\"podman %s\" can not be run in read-only mode, only commands inspecting containers, pods and images can", strings.Join(args, " "))
+		}
 		if args.Present() {
-			if _, notRequireRootless := cmdsNotRequiringRootless[args.First()]; !notRequireRootless {
-				became, ret, err := rootless.BecomeRootInUserNS()
+			if requiresRootless(args) {
+				became, ret, err := becomeRootInUserNS()
 				if err != nil {
 					logrus.Errorf(err.Error())
 					os.Exit(1)
@@ -111,6 +230,11 @@ func main() {
 					os.Exit(ret)
 				}
 			}
+			// Commands modifying containers, pods and images are
+			// audited once they create their runtime
+			if !allowedReadOnly(c.App.Commands, args) {
+				c.App.Metadata[libpodruntime.AuditRecordKey] = cliAuditRecord(c.App.Commands, args)
+			}
 		}
 		if c.GlobalBool("syslog") {
 			hook, err := lsyslog.NewSyslogHook("", "", syslog.LOG_INFO, "")
@@ -192,6 +316,10 @@ func main() {
 			Usage: "set the libpod namespace, used to create separate views of the containers and pods on the system",
 			Value: "",
 		},
+		cli.BoolFlag{
+			Name:  "read-only",
+			Usage: "reject all commands modifying containers, pods and images, only allow inspecting them",
+		},
 		cli.StringFlag{
 			Name:  "root",
 			Usage: "path to the root directory in which data, including images, is stored",
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/containers/image/docker"
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/image/manifest"
+	"github.com/containers/image/types"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/libpod/pkg/util"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	manifestDescription = `Build manifest lists, the OCI image indexes or Docker manifest lists of the
+   images of several platforms, and push them with their images to publish
+   multi-architecture images.`
+	manifestSubCommands = []cli.Command{
+		manifestAddCommand,
+		manifestAnnotateCommand,
+		manifestCreateCommand,
+		manifestInspectCommand,
+		manifestPushCommand,
+		manifestRmCommand,
+	}
+	manifestCommand = cli.Command{
+		Name:                   "manifest",
+		Usage:                  "Build and push manifest lists",
+		Description:            manifestDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            manifestSubCommands,
+	}
+
+	// manifestRegistryFlags are the flags of the subcommands reading
+	// images from registries
+	manifestRegistryFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "authfile",
+			Usage: "Path of the authentication file. Default is ${XDG_RUNTIME_DIR}/containers/auth.json",
+		},
+		cli.StringFlag{
+			Name:  "cert-dir",
+			Usage: "`pathname` of a directory containing TLS certificates and keys",
+		},
+		cli.StringFlag{
+			Name:  "creds",
+			Usage: "`credentials` (USERNAME:PASSWORD) to use for authenticating to a registry",
+		},
+		cli.BoolTFlag{
+			Name:  "tls-verify",
+			Usage: "require HTTPS and verify certificates when contacting registries (default: true)",
+		},
+	}
+)
+
+// manifestSystemContext returns the system context of the registry flags of
+// the manifest subcommands
+func manifestSystemContext(c *cli.Context) (*types.SystemContext, error) {
+	dockerRegistryOptions := image.DockerRegistryOptions{
+		DockerCertPath:              c.String("cert-dir"),
+		DockerInsecureSkipTLSVerify: !c.BoolT("tls-verify"),
+	}
+	if c.IsSet("creds") {
+		creds, err := util.ParseRegistryCreds(c.String("creds"))
+		if err != nil {
+			return nil, err
+		}
+		dockerRegistryOptions.DockerRegistryCreds = creds
+	}
+	return dockerRegistryOptions.GetSystemContext(image.GetSystemContext(c.String("signature-policy"), c.String("authfile"), false), nil), nil
+}
+
+// manifestImageReference returns the reference of an image added to a
+// manifest list: references with a transport are used as given, otherwise
+// local images are added, else the images of registries
+func manifestImageReference(runtime *libpod.Runtime, name string) (types.ImageReference, error) {
+	ref, err := image.ParseTransportReference(name)
+	if err != nil || ref != nil {
+		return ref, err
+	}
+	if img, err := runtime.ImageRuntime().NewFromLocal(name); err == nil {
+		return img.StorageReference()
+	}
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image reference %q", name)
+	}
+	return docker.NewReference(reference.TagNameOnly(named))
+}
+
+// manifestListType returns the MIME type of a manifest list format of
+// --format, or "" to choose it from the images of the list
+func manifestListType(format string) (string, error) {
+	switch format {
+	case "":
+		return "", nil
+	case "oci":
+		return imgspecv1.MediaTypeImageIndex, nil
+	case "v2s2", "docker":
+		return manifest.DockerV2ListMediaType, nil
+	default:
+		return "", errors.Errorf("unknown format %q. Choose one of the supported formats: 'oci' or 'v2s2'", format)
+	}
+}
+
+// parseManifestAnnotations parses the KEY=VALUE annotations of --annotation
+func parseManifestAnnotations(annotations []string) (map[string]string, error) {
+	parsed := make(map[string]string)
+	for _, annotation := range annotations {
+		split := strings.SplitN(annotation, "=", 2)
+		if len(split) != 2 || split[0] == "" {
+			return nil, errors.Errorf("invalid annotation %q, must be KEY=VALUE", annotation)
+		}
+		parsed[split[0]] = split[1]
+	}
+	return parsed, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/manifests"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	manifestAddFlags = append([]cli.Flag{
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "Add all the images of a manifest list, not only the image of the host platform",
+		},
+		cli.StringSliceFlag{
+			Name:  "annotation",
+			Usage: "Add an annotation, KEY=VALUE, to the image in the manifest list",
+		},
+		cli.StringFlag{
+			Name:  "arch",
+			Usage: "Architecture of the image, chosen from a manifest list",
+		},
+		cli.StringFlag{
+			Name:  "os",
+			Usage: "Operating system of the image, chosen from a manifest list",
+		},
+		cli.StringFlag{
+			Name:  "variant",
+			Usage: "Variant of the architecture of the image",
+		},
+	}, manifestRegistryFlags...)
+	manifestAddDescription = `
+   Adds an image to a manifest list.  Local images are added by name or ID, other
+   images with a reference such as docker://quay.io/image or oci:/path.  Adding
+   a manifest list of a registry adds its image of the host platform, or of
+   --arch and --os, or all its images with --all.  The digests of the images
+   added are printed.
+`
+	manifestAddCommand = cli.Command{
+		Name:                   "add",
+		Usage:                  "Add an image to a manifest list",
+		Description:            manifestAddDescription,
+		Flags:                  manifestAddFlags,
+		Action:                 manifestAddCmd,
+		ArgsUsage:              "LIST IMAGE",
+		UseShortOptionHandling: true,
+	}
+)
+
+func manifestAddCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.Errorf("a manifest list and an image must be specified")
+	}
+	if err := validateFlags(c, manifestAddFlags); err != nil {
+		return err
+	}
+	if c.Bool("all") && (c.IsSet("arch") || c.IsSet("os") || c.IsSet("variant")) {
+		return errors.Errorf("--all cannot be used with --arch, --os or --variant")
+	}
+	annotations, err := parseManifestAnnotations(c.StringSlice("annotation"))
+	if err != nil {
+		return err
+	}
+	sc, err := manifestSystemContext(c)
+	if err != nil {
+		return err
+	}
+	sc.OSChoice = c.String("os")
+	sc.ArchitectureChoice = c.String("arch")
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ManifestStore()
+	if err != nil {
+		return err
+	}
+	list, err := store.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	ref, err := manifestImageReference(runtime, args[1])
+	if err != nil {
+		return err
+	}
+	added, err := list.Add(getContext(), sc, ref, c.Bool("all"))
+	if err != nil {
+		return errors.Wrapf(err, "error adding %s to manifest list %s", args[1], list.Name)
+	}
+	annotation := manifests.Annotation{
+		Architecture: c.String("arch"),
+		OS:           c.String("os"),
+		Variant:      c.String("variant"),
+		Annotations:  annotations,
+	}
+	for _, d := range added {
+		if err := list.Annotate(d, annotation); err != nil {
+			return err
+		}
+	}
+	if err := store.Save(list); err != nil {
+		return err
+	}
+	for _, d := range added {
+		fmt.Println(d)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/manifests"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	manifestAnnotateFlags = []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "annotation",
+			Usage: "Add an annotation, KEY=VALUE, to the image",
+		},
+		cli.StringFlag{
+			Name:  "arch",
+			Usage: "Set the architecture of the image",
+		},
+		cli.StringFlag{
+			Name:  "os",
+			Usage: "Set the operating system of the image",
+		},
+		cli.StringSliceFlag{
+			Name:  "os-features",
+			Usage: "Set the features of the operating system the image requires",
+		},
+		cli.StringFlag{
+			Name:  "os-version",
+			Usage: "Set the version of the operating system the image requires",
+		},
+		cli.StringFlag{
+			Name:  "variant",
+			Usage: "Set the variant of the architecture of the image",
+		},
+	}
+	manifestAnnotateDescription = `
+   Sets the platform of an image of a manifest list, which clients pull the
+   image for, and adds annotations to it.  Annotations are only pushed in OCI
+   image indexes.
+`
+	manifestAnnotateCommand = cli.Command{
+		Name:                   "annotate",
+		Usage:                  "Set the platform and annotations of an image of a manifest list",
+		Description:            manifestAnnotateDescription,
+		Flags:                  manifestAnnotateFlags,
+		Action:                 manifestAnnotateCmd,
+		ArgsUsage:              "LIST DIGEST",
+		UseShortOptionHandling: true,
+	}
+)
+
+func manifestAnnotateCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.Errorf("a manifest list and the digest of one of its images must be specified")
+	}
+	if err := validateFlags(c, manifestAnnotateFlags); err != nil {
+		return err
+	}
+	d, err := digest.Parse(args[1])
+	if err != nil {
+		return errors.Wrapf(err, "invalid image digest %q", args[1])
+	}
+	annotations, err := parseManifestAnnotations(c.StringSlice("annotation"))
+	if err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ManifestStore()
+	if err != nil {
+		return err
+	}
+	list, err := store.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	err = list.Annotate(d, manifests.Annotation{
+		Architecture: c.String("arch"),
+		OS:           c.String("os"),
+		OSVersion:    c.String("os-version"),
+		OSFeatures:   c.StringSlice("os-features"),
+		Variant:      c.String("variant"),
+		Annotations:  annotations,
+	})
+	if err != nil {
+		return err
+	}
+	return store.Save(list)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var (
+	manifestCreateFlags = append([]cli.Flag{
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "Add all the images of the manifest lists given, not only the image of the host platform",
+		},
+	}, manifestRegistryFlags...)
+	manifestCreateDescription = `
+   Creates a manifest list, adding the images given to it.  The name of the list
+   is its default destination when it is pushed.
+`
+	manifestCreateCommand = cli.Command{
+		Name:                   "create",
+		Usage:                  "Create a manifest list",
+		Description:            manifestCreateDescription,
+		Flags:                  manifestCreateFlags,
+		Action:                 manifestCreateCmd,
+		ArgsUsage:              "LIST [IMAGE...]",
+		UseShortOptionHandling: true,
+	}
+)
+
+func manifestCreateCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("a manifest list name must be specified")
+	}
+	if err := validateFlags(c, manifestCreateFlags); err != nil {
+		return err
+	}
+	sc, err := manifestSystemContext(c)
+	if err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ManifestStore()
+	if err != nil {
+		return err
+	}
+	list, err := store.Create(args[0])
+	if err != nil {
+		return err
+	}
+	for _, name := range args[1:] {
+		ref, err := manifestImageReference(runtime, name)
+		if err == nil {
+			_, err = list.Add(getContext(), sc, ref, c.Bool("all"))
+		}
+		if err != nil {
+			// The list is not left behind half built
+			if rmErr := store.Remove(list.Name); rmErr != nil {
+				logrus.Errorf("unable to remove manifest list %s: %v", list.Name, rmErr)
+			}
+			return errors.Wrapf(err, "error adding %s to manifest list %s", name, list.Name)
+		}
+	}
+	if err := store.Save(list); err != nil {
+		return err
+	}
+	fmt.Println(list.Name)
+	return nil
+}
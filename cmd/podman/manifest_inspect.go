@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	manifestInspectDescription = `Displays a manifest list as it is pushed, or as an OCI image index or
+   Docker manifest list with --format.`
+	manifestInspectFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Usage: "Format of the manifest list, oci or v2s2",
+		},
+	}
+	manifestInspectCommand = cli.Command{
+		Name:        "inspect",
+		Usage:       "Display a manifest list",
+		Description: manifestInspectDescription,
+		Flags:       manifestInspectFlags,
+		Action:      manifestInspectCmd,
+		ArgsUsage:   "LIST",
+	}
+)
+
+func manifestInspectCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.Errorf("you must provide exactly one manifest list name")
+	}
+	if err := validateFlags(c, manifestInspectFlags); err != nil {
+		return err
+	}
+	mimeType, err := manifestListType(c.String("format"))
+	if err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ManifestStore()
+	if err != nil {
+		return err
+	}
+	list, err := store.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	b, _, err := list.Serialize(mimeType)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, b, "", "    "); err != nil {
+		return err
+	}
+	fmt.Println(out.String())
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/image/signature"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/image"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var (
+	manifestPushFlags = append([]cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Usage: "Format of the manifest list, oci or v2s2 (default is v2s2 unless the list has OCI images)",
+		},
+		cli.BoolFlag{
+			Name:  "purge",
+			Usage: "Remove the manifest list once it is pushed",
+		},
+		cli.BoolFlag{
+			Name:  "quiet, q",
+			Usage: "Don't output progress information when pushing",
+		},
+		cli.StringFlag{
+			Name:   "signature-policy",
+			Usage:  "`pathname` of signature policy file (not usually used)",
+			Hidden: true,
+		},
+	}, manifestRegistryFlags...)
+	manifestPushDescription = `
+   Pushes the images of a manifest list to the repository of DESTINATION, by
+   digest, then the manifest list itself, tagged DESTINATION.  DESTINATION
+   defaults to the name of the list.  Local images are compressed as they are
+   pushed, which updates their digests in the list.  The digest of the list is
+   printed.
+`
+	manifestPushCommand = cli.Command{
+		Name:                   "push",
+		Usage:                  "Push a manifest list and its images to a registry",
+		Description:            manifestPushDescription,
+		Flags:                  manifestPushFlags,
+		Action:                 manifestPushCmd,
+		ArgsUsage:              "LIST [DESTINATION]",
+		UseShortOptionHandling: true,
+	}
+)
+
+func manifestPushCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) == 0 || len(args) > 2 {
+		return errors.Errorf("a manifest list, and optionally a destination, must be specified")
+	}
+	if err := validateFlags(c, manifestPushFlags); err != nil {
+		return err
+	}
+	mimeType, err := manifestListType(c.String("format"))
+	if err != nil {
+		return err
+	}
+	sc, err := manifestSystemContext(c)
+	if err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ManifestStore()
+	if err != nil {
+		return err
+	}
+	list, err := store.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	destName := list.Name
+	if len(args) > 1 {
+		destName = args[1]
+	}
+	dest, err := reference.ParseNormalizedNamed(strings.TrimPrefix(destName, image.DockerTransport+"://"))
+	if err != nil {
+		return errors.Wrapf(err, "invalid destination %q, manifest lists are pushed to registries", destName)
+	}
+
+	policy, err := signature.DefaultPolicy(sc)
+	if err != nil {
+		return errors.Wrapf(err, "error obtaining the signature policy")
+	}
+	policyContext, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return err
+	}
+	defer policyContext.Destroy()
+
+	var writer io.Writer
+	if !c.Bool("quiet") {
+		writer = os.Stderr
+	}
+	listDigest, err := list.Push(getContext(), sc, policyContext, dest, mimeType, writer)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing manifest list %s", list.Name)
+	}
+	if c.Bool("purge") {
+		if err := store.Remove(list.Name); err != nil {
+			return err
+		}
+	} else if err := store.Save(list); err != nil {
+		// The digests of the local images pushed are kept
+		logrus.Errorf("unable to save manifest list %s: %v", list.Name, err)
+	}
+	fmt.Println(listDigest)
+	return nil
+}
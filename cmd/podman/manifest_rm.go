@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/manifests"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	manifestRmDescription = `Removes one or more manifest lists.  The images of the lists are not
+   removed.`
+	manifestRmCommand = cli.Command{
+		Name:        "rm",
+		Usage:       "Remove one or more manifest lists",
+		Description: manifestRmDescription,
+		Action:      manifestRmCmd,
+		ArgsUsage:   "LIST [LIST ...]",
+	}
+)
+
+func manifestRmCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("manifest list name must be specified")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	store, err := runtime.ManifestStore()
+	if err != nil {
+		return err
+	}
+
+	var lastError error
+	for _, name := range args {
+		if err := store.Remove(name); err != nil {
+			if lastError != nil {
+				fmt.Fprintln(os.Stderr, lastError)
+			}
+			lastError = errors.Wrapf(err, "failed to remove manifest list %q", name)
+			continue
+		}
+		fmt.Println(manifests.ListName(name))
+	}
+	return lastError
+}
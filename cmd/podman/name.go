@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var (
+	nameDescription = `Manage the reservations of the names of containers and pods.  A reserved name
+   can only be used by the container or pod created with the token of its
+   reservation, with --name-reservation.`
+	nameSubCommands = []cli.Command{
+		nameListCommand,
+		nameReleaseCommand,
+		nameReserveCommand,
+	}
+	nameCommand = cli.Command{
+		Name:                   "name",
+		Usage:                  "Manage name reservations",
+		Description:            nameDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            nameSubCommands,
+	}
+)
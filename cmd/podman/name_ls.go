@@ -0,0 +1,145 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// nameListTemplateParams stores info about each name reservation
+type nameListTemplateParams struct {
+	Name    string
+	Token   string
+	Created string
+}
+
+var (
+	nameListFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "Change the output to JSON or a Go template",
+		},
+		cli.BoolFlag{
+			Name:  "noheading, n",
+			Usage: "Do not print column headings",
+		},
+		cli.BoolFlag{
+			Name:  "no-trunc, notruncate",
+			Usage: "Do not truncate the output",
+		},
+		cli.BoolFlag{
+			Name:  "quiet, q",
+			Usage: "Display only reserved names",
+		},
+	}
+	nameListDescription = "Lists the reserved names, with the tokens of their reservations."
+	nameListCommand     = cli.Command{
+		Name:                   "ls",
+		Aliases:                []string{"list"},
+		Usage:                  "List name reservations",
+		Description:            nameListDescription,
+		Flags:                  nameListFlags,
+		Action:                 nameListCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func nameListCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman name ls does not take any arguments")
+	}
+	if err := validateFlags(c, nameListFlags); err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	reservations, err := runtime.NameReservations()
+	if err != nil {
+		return err
+	}
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	var out formats.Writer
+	format := genNameListFormat(c.String("format"), c.Bool("quiet"), c.Bool("noheading"))
+	if format == formats.JSONString {
+		out = formats.JSONStructArray{Output: nameReservationsToGeneric(reservations)}
+	} else {
+		params := getNameListTemplateOutput(reservations, c.Bool("no-trunc"))
+		out = formats.StdoutTemplateArray{Output: nameParamsToGeneric(params), Template: format, Fields: params[0].headerMap()}
+	}
+	return formats.Writer(out).Out()
+}
+
+func genNameListFormat(format string, quiet, noHeading bool) string {
+	if format != "" {
+		// "\t" from the command line is not being recognized as a tab
+		// replacing the string "\t" to a tab character if the user passes in "\t"
+		return strings.Replace(format, `\t`, "\t", -1)
+	}
+	if quiet {
+		return "{{.Name}}"
+	}
+	format = "{{.Name}}\t{{.Token}}\t{{.Created}}\t"
+	if noHeading {
+		return format
+	}
+	return "table " + format
+}
+
+// getNameListTemplateOutput returns the name reservations in the format of the
+// default table
+func getNameListTemplateOutput(reservations []*libpod.NameReservation, noTrunc bool) []nameListTemplateParams {
+	params := make([]nameListTemplateParams, 0, len(reservations))
+	for _, reservation := range reservations {
+		token := reservation.Token
+		if !noTrunc {
+			token = shortID(token)
+		}
+		params = append(params, nameListTemplateParams{
+			Name:    reservation.Name,
+			Token:   token,
+			Created: units.HumanDuration(time.Since(reservation.Created)) + " ago",
+		})
+	}
+	return params
+}
+
+func nameReservationsToGeneric(reservations []*libpod.NameReservation) (genericParams []interface{}) {
+	for _, reservation := range reservations {
+		genericParams = append(genericParams, interface{}(reservation))
+	}
+	return
+}
+
+func nameParamsToGeneric(params []nameListTemplateParams) (genericParams []interface{}) {
+	for _, p := range params {
+		genericParams = append(genericParams, interface{}(p))
+	}
+	return
+}
+
+// generate the header based on the template provided
+func (n *nameListTemplateParams) headerMap() map[string]string {
+	v := reflect.Indirect(reflect.ValueOf(n))
+	values := make(map[string]string)
+	for i := 0; i < v.NumField(); i++ {
+		key := v.Type().Field(i).Name
+		values[key] = strings.ToUpper(splitCamelCase(key))
+	}
+	return values
+}
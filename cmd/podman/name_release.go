@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	nameReleaseFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "Release the reservations of the names whatever their token",
+		},
+	}
+	nameReleaseDescription = `Releases the reservation of a name, given the token of the reservation, so
+   that any container or pod can use it.  With --force, the reservations of one
+   or more names are released without their token.`
+	nameReleaseCommand = cli.Command{
+		Name:        "release",
+		Usage:       "Release the reservation of a name",
+		Description: nameReleaseDescription,
+		Flags:       nameReleaseFlags,
+		Action:      nameReleaseCmd,
+		ArgsUsage:   "NAME TOKEN | --force NAME [NAME ...]",
+	}
+)
+
+func nameReleaseCmd(c *cli.Context) error {
+	if err := validateFlags(c, nameReleaseFlags); err != nil {
+		return err
+	}
+	args := c.Args()
+	force := c.Bool("force")
+	if force && len(args) == 0 {
+		return errors.Errorf("at least one name must be specified")
+	}
+	if !force && len(args) != 2 {
+		return errors.Errorf("a name and the token of its reservation must be specified")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	if !force {
+		if err := runtime.ReleaseName(args[0], args[1], false); err != nil {
+			return errors.Wrapf(err, "failed to release name %q", args[0])
+		}
+		fmt.Println(args[0])
+		return nil
+	}
+
+	var lastError error
+	for _, name := range args {
+		if err := runtime.ReleaseName(name, "", true); err != nil {
+			if lastError != nil {
+				fmt.Fprintln(os.Stderr, lastError)
+			}
+			lastError = errors.Wrapf(err, "failed to release name %q", name)
+			continue
+		}
+		fmt.Println(name)
+	}
+	return lastError
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	nameReserveDescription = `Reserves one or more names for containers and pods created later, and prints
+   the token of each reservation.  Only the container or pod created with the
+   token, with --name-reservation, can use the name, and claims the reservation
+   as it is created.`
+	nameReserveCommand = cli.Command{
+		Name:        "reserve",
+		Usage:       "Reserve one or more names",
+		Description: nameReserveDescription,
+		Action:      nameReserveCmd,
+		ArgsUsage:   "NAME [NAME ...]",
+	}
+)
+
+func nameReserveCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("at least one name must be specified")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var lastError error
+	for _, name := range args {
+		reservation, err := runtime.ReserveName(name)
+		if err != nil {
+			if lastError != nil {
+				fmt.Fprintln(os.Stderr, lastError)
+			}
+			lastError = errors.Wrapf(err, "failed to reserve name %q", name)
+			continue
+		}
+		fmt.Println(reservation.Token)
+	}
+	return lastError
+}
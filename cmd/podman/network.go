@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var (
+	networkDescription = `Manage the networks of containers.`
+	networkSubCommands = []cli.Command{
+		networkReloadCommand,
+	}
+	networkCommand = cli.Command{
+		Name:                   "network",
+		Usage:                  "Manage networks",
+		Description:            networkDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            networkSubCommands,
+	}
+)
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	networkReloadFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all, a",
+			Usage: "reload the network of all running containers",
+		},
+		LatestFlag,
+	}
+	networkReloadDescription = `
+   Tears down the network configuration of one or more running containers and
+   sets it up again, without restarting them.  The containers may be given new
+   addresses, and the firewall rules of their networks are added again.
+`
+	networkReloadCommand = cli.Command{
+		Name:                   "reload",
+		Usage:                  "Reload the network of one or more containers",
+		Description:            networkReloadDescription,
+		Flags:                  networkReloadFlags,
+		Action:                 networkReloadCmd,
+		ArgsUsage:              "CONTAINER-NAME [CONTAINER-NAME ...]",
+		UseShortOptionHandling: true,
+	}
+)
+
+func networkReloadCmd(c *cli.Context) error {
+	args := c.Args()
+	if (c.Bool("all") || c.Bool("latest")) && len(args) > 0 {
+		return errors.Errorf("no arguments are needed with --all or --latest")
+	}
+	if c.Bool("all") && c.Bool("latest") {
+		return errors.Errorf("--all and --latest cannot be used together")
+	}
+	if len(args) < 1 && !c.Bool("all") && !c.Bool("latest") {
+		return errors.Errorf("you must provide at least one container name or id")
+	}
+	if err := validateFlags(c, networkReloadFlags); err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "error creating libpod runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var containers []*libpod.Container
+	var lastError error
+	switch {
+	case c.Bool("all"):
+		containers, err = runtime.GetRunningContainers()
+		if err != nil {
+			return errors.Wrapf(err, "unable to get running containers")
+		}
+	case c.Bool("latest"):
+		ctr, err := runtime.GetLatestContainer()
+		if err != nil {
+			return errors.Wrapf(err, "unable to get latest container")
+		}
+		containers = append(containers, ctr)
+	default:
+		for _, arg := range args {
+			ctr, err := runtime.LookupContainer(arg)
+			if err != nil {
+				if lastError != nil {
+					fmt.Fprintln(os.Stderr, lastError)
+				}
+				lastError = errors.Wrapf(err, "error looking up container %q", arg)
+				continue
+			}
+			containers = append(containers, ctr)
+		}
+	}
+
+	for _, ctr := range containers {
+		if err := ctr.ReloadNetwork(); err != nil {
+			if lastError != nil {
+				fmt.Fprintln(os.Stderr, lastError)
+			}
+			lastError = errors.Wrapf(err, "failed to reload network of container %v", ctr.ID())
+		} else {
+			fmt.Println(ctr.ID())
+		}
+	}
+	return lastError
+}
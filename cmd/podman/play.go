@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var (
+	playDescription = `Play structured data, such as the Kubernetes YAML of pods, creating
+   the pods and containers it describes.`
+	playSubCommands = []cli.Command{
+		playKubeCommand,
+	}
+	playCommand = cli.Command{
+		Name:                   "play",
+		Usage:                  "Play pods and containers based on a structured input file",
+		Description:            playDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            playSubCommands,
+	}
+)
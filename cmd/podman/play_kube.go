@@ -0,0 +1,899 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/image/manifest"
+	"github.com/containers/image/types"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/cmd/podman/shared"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/libpod/pkg/adapter/kube"
+	ann "github.com/containers/libpod/pkg/annotations"
+	"github.com/containers/libpod/pkg/inspect"
+	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/signal"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var (
+	playKubeFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "authfile",
+			Usage: "Path of the authentication file. Default is ${XDG_RUNTIME_DIR}/containers/auth.json",
+		},
+		cli.StringFlag{
+			Name:  "cert-dir",
+			Usage: "`pathname` of a directory containing TLS certificates and keys",
+		},
+		cli.StringFlag{
+			Name:  "creds",
+			Usage: "`credentials` (USERNAME:PASSWORD) to use for authenticating to a registry",
+		},
+		cli.StringFlag{
+			Name:  "exit-code-propagation",
+			Usage: "Wait for the containers to exit and exit with the exit code of the failed ones: 'none', 'any-failed' or 'all-failed'",
+			Value: kubeExitCodeNone,
+		},
+		cli.StringFlag{
+			Name:  "image-lock",
+			Usage: "Create the containers in locked mode, only from images pinned by digest by the lockfile or by their references",
+		},
+		cli.BoolFlag{
+			Name:  "quiet, q",
+			Usage: "Suppress output information when pulling images",
+		},
+		cli.StringFlag{
+			Name:  "signature-policy",
+			Usage: "`pathname` of signature policy file (not usually used)",
+		},
+		cli.BoolTFlag{
+			Name:  "start",
+			Usage: "Start the pods once they are created (default true)",
+		},
+		cli.BoolTFlag{
+			Name:  "tls-verify",
+			Usage: "require HTTPS and verify certificates when contacting registries (default: true)",
+		},
+	}
+	playKubeDescription = `
+   Creates the pods and containers described by the Kubernetes YAML of a Pod,
+   or of a Deployment, one pod for each of its replicas, and starts them.  The
+   images of the containers are pulled if need be, the host ports of their
+   ports are published by the infra container of the pod, and the hostPath
+   volumes of the pod are bind mounted.
+`
+	playKubeCommand = cli.Command{
+		Name:                   "kube",
+		Usage:                  "Create pods and containers based on Kubernetes YAML",
+		Description:            playKubeDescription,
+		Flags:                  playKubeFlags,
+		Action:                 playKubeCmd,
+		ArgsUsage:              "KUBEFILE",
+		UseShortOptionHandling: true,
+	}
+)
+
+// kubeDocumentSeparator separates the documents of Kubernetes YAML
+var kubeDocumentSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+const (
+	// kubeSeccompProfileRoot is the directory of the localhost seccomp
+	// profiles given by a relative path, the default one of the kubelet
+	kubeSeccompProfileRoot = "/var/lib/kubelet/seccomp"
+	// kubeExitCodeNone does not wait for the containers played to exit
+	kubeExitCodeNone = "none"
+	// kubeExitCodeAnyFailed exits with the exit code of the first container
+	// that failed, if any did
+	kubeExitCodeAnyFailed = "any-failed"
+	// kubeExitCodeAllFailed exits with the exit code of the last container
+	// if all failed
+	kubeExitCodeAllFailed = "all-failed"
+	// kubeAppArmorAnnotationKeyPrefix prefixes the annotation of the
+	// AppArmor profile of a container of a pod
+	kubeAppArmorAnnotationKeyPrefix = "container.apparmor.security.beta.kubernetes.io/"
+)
+
+// kubeDeployment is the part of a Kubernetes Deployment podman plays, the
+// template of its pods and their number
+type kubeDeployment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Replicas *int32             `json:"replicas,omitempty"`
+		Template v1.PodTemplateSpec `json:"template"`
+	} `json:"spec"`
+}
+
+// readKubePods returns the pods of the documents of Kubernetes YAML, Pods,
+// and Deployments whose pods are named after them. Services are skipped, the
+// host ports of the pods are published by their infra containers.
+func readKubePods(content []byte) ([]v1.Pod, error) {
+	var pods []v1.Pod
+	for _, document := range kubeDocumentSeparator.Split(string(content), -1) {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+		documentPods, err := readKubeDocument([]byte(document))
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, documentPods...)
+	}
+	if len(pods) == 0 {
+		return nil, errors.Errorf("Kubernetes YAML has no Pod or Deployment")
+	}
+	return pods, nil
+}
+
+// readKubeDocument returns the pods of a document of Kubernetes YAML
+func readKubeDocument(content []byte) ([]v1.Pod, error) {
+	var meta metav1.TypeMeta
+	if err := yaml.Unmarshal(content, &meta); err != nil {
+		return nil, errors.Wrapf(err, "error parsing Kubernetes YAML")
+	}
+	switch meta.Kind {
+	case "Pod":
+		var pod v1.Pod
+		if err := yaml.Unmarshal(content, &pod); err != nil {
+			return nil, errors.Wrapf(err, "error parsing Kubernetes Pod")
+		}
+		return []v1.Pod{pod}, nil
+	case "Deployment":
+		var deployment kubeDeployment
+		if err := yaml.Unmarshal(content, &deployment); err != nil {
+			return nil, errors.Wrapf(err, "error parsing Kubernetes Deployment")
+		}
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		pods := make([]v1.Pod, 0, replicas)
+		for i := int32(0); i < replicas; i++ {
+			pod := v1.Pod{
+				ObjectMeta: *deployment.Spec.Template.ObjectMeta.DeepCopy(),
+				Spec:       *deployment.Spec.Template.Spec.DeepCopy(),
+			}
+			pod.Name = fmt.Sprintf("%s-pod-%d", deployment.Name, i)
+			pods = append(pods, pod)
+		}
+		return pods, nil
+	case "Service":
+		return nil, nil
+	case "":
+		return nil, errors.Errorf("Kubernetes YAML has no kind")
+	}
+	return nil, errors.Errorf("Kubernetes kind %s is not supported, only Pod, Deployment and Service", meta.Kind)
+}
+
+func playKubeCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.Errorf("podman play kube takes a Kubernetes YAML file")
+	}
+	if err := validateFlags(c, playKubeFlags); err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return errors.Wrapf(err, "error reading %q", args[0])
+	}
+	pods, err := readKubePods(content)
+	if err != nil {
+		return err
+	}
+	exitCodePolicy := c.String("exit-code-propagation")
+	switch exitCodePolicy {
+	case kubeExitCodeNone, kubeExitCodeAnyFailed, kubeExitCodeAllFailed:
+	default:
+		return errors.Errorf("invalid exit code propagation %q, must be none, any-failed or all-failed", exitCodePolicy)
+	}
+	if exitCodePolicy != kubeExitCodeNone && !c.BoolT("start") {
+		return errors.Errorf("--exit-code-propagation requires the pods to be started")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var registryCreds *types.DockerAuthConfig
+	if c.IsSet("creds") {
+		if registryCreds, err = util.ParseRegistryCreds(c.String("creds")); err != nil {
+			return err
+		}
+	}
+	dockerRegistryOptions := &image.DockerRegistryOptions{
+		DockerRegistryCreds:         registryCreds,
+		DockerCertPath:              c.String("cert-dir"),
+		DockerInsecureSkipTLSVerify: !c.BoolT("tls-verify"),
+	}
+
+	ctx := getContext()
+	var played []*libpod.Pod
+	for i := range pods {
+		pod, err := playKubePod(ctx, c, runtime, &pods[i], dockerRegistryOptions)
+		if err != nil {
+			return err
+		}
+		if !c.BoolT("start") {
+			continue
+		}
+		ctrErrs, err := pod.Start(ctx)
+		for ctr, ctrErr := range ctrErrs {
+			fmt.Fprintf(os.Stderr, "unable to start container %q of pod %q: %v\n", ctr, pod.Name(), ctrErr)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to start pod %q", pod.Name())
+		}
+		played = append(played, pod)
+	}
+	if exitCodePolicy == kubeExitCodeNone {
+		return nil
+	}
+
+	// Wait for the containers of the pods, in the order they were created,
+	// other than their infra and init containers which already exited
+	var exitCodes []int32
+	for _, pod := range played {
+		ctrs, err := pod.AllContainers()
+		if err != nil {
+			return err
+		}
+		sort.Slice(ctrs, func(i, j int) bool {
+			return ctrs[i].CreatedTime().Before(ctrs[j].CreatedTime())
+		})
+		for _, ctr := range ctrs {
+			if ctr.IsInfra() || ctr.InitContainerType() != "" {
+				continue
+			}
+			code, err := ctr.Wait()
+			if err != nil {
+				return errors.Wrapf(err, "error waiting for container %s", ctr.ID())
+			}
+			exitCodes = append(exitCodes, code)
+		}
+	}
+	exitCode = kubeExitCode(exitCodePolicy, exitCodes)
+	return nil
+}
+
+// kubeExitCode returns the exit code of podman play kube for the exit codes of
+// the containers played, according to an exit code propagation policy
+func kubeExitCode(policy string, exitCodes []int32) int {
+	switch policy {
+	case kubeExitCodeAnyFailed:
+		for _, code := range exitCodes {
+			if code != 0 {
+				return int(code)
+			}
+		}
+	case kubeExitCodeAllFailed:
+		for _, code := range exitCodes {
+			if code == 0 {
+				return 0
+			}
+		}
+		if len(exitCodes) > 0 {
+			return int(exitCodes[len(exitCodes)-1])
+		}
+	}
+	return 0
+}
+
+// playKubePod creates the pod and containers of a Kubernetes pod, pulling
+// their images, and prints their IDs
+func playKubePod(ctx context.Context, c *cli.Context, runtime *libpod.Runtime, kubePod *v1.Pod, dockerRegistryOptions *image.DockerRegistryOptions) (*libpod.Pod, error) {
+	if kubePod.Name == "" {
+		return nil, errors.Errorf("Kubernetes pod has no name")
+	}
+	volumes, err := kubeVolumes(kubePod.Spec.Volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []libpod.PodCreateOption{
+		libpod.WithPodName(kubePod.Name),
+		libpod.WithPodCgroups(),
+	}
+	if len(kubePod.Labels) > 0 {
+		options = append(options, libpod.WithPodLabels(kubePod.Labels))
+	}
+	if namespaces := kubeSharedNamespaces(&kubePod.Spec); len(namespaces) > 0 {
+		nsOptions, err := shared.GetNamespaceOptions(namespaces)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, libpod.WithInfraContainer())
+		options = append(options, nsOptions...)
+		if ports := kubePortMappings(kubePod.Spec.Containers); len(ports) > 0 && !kubePod.Spec.HostNetwork {
+			options = append(options, libpod.WithInfraContainerPorts(ports))
+		}
+	}
+	pod, err := runtime.NewPod(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println(pod.ID())
+
+	var writer *os.File
+	if !c.Bool("quiet") {
+		writer = os.Stderr
+	}
+	// Init containers are created first, in their order, for the pod to run
+	// them in the order they were created
+	kubeCtrs := append(append([]v1.Container{}, kubePod.Spec.InitContainers...), kubePod.Spec.Containers...)
+	for i := range kubeCtrs {
+		kubeCtr := &kubeCtrs[i]
+		forcePull := kubeCtr.ImagePullPolicy == v1.PullAlways
+		imageRef, err := lockedImageName(c, kubeCtr.Image)
+		if err != nil {
+			return pod, errors.Wrapf(err, "invalid image of container %q", kubeCtr.Name)
+		}
+		var newImage *image.Image
+		if kubeCtr.ImagePullPolicy == v1.PullNever {
+			newImage, err = runtime.ImageRuntime().NewFromLocal(imageRef)
+		} else {
+			newImage, err = runtime.ImageRuntime().New(ctx, imageRef, c.String("signature-policy"), c.String("authfile"), writer, dockerRegistryOptions, image.SigningOptions{}, forcePull, false)
+		}
+		if err != nil {
+			return pod, errors.Wrapf(err, "unable to get image %q of container %q", kubeCtr.Image, kubeCtr.Name)
+		}
+		data, err := newImage.Inspect(ctx)
+		if err != nil {
+			return pod, err
+		}
+		config, securityOpts, err := kubeContainerToCreateConfig(kubeCtr, &kubePod.Spec, kubePod.Annotations, pod.Name(), pod.ID(), newImage.Names()[0], data, volumes)
+		if err != nil {
+			return pod, errors.Wrapf(err, "invalid container %q", kubeCtr.Name)
+		}
+		if i < len(kubePod.Spec.InitContainers) {
+			if config.InitContainerType, err = kubeInitContainerType(kubePod.Annotations, kubeCtr.Name); err != nil {
+				return pod, err
+			}
+		}
+		config.Runtime = runtime
+		if err := parseSecurityOpt(config, securityOpts); err != nil {
+			return pod, err
+		}
+		config.SecurityOpts = securityOpts
+		warnings, err := verifyContainerResources(config, false)
+		if err != nil {
+			return pod, err
+		}
+		for _, warning := range warnings {
+			fmt.Fprintln(os.Stderr, warning)
+		}
+
+		runtimeSpec, err := cc.CreateConfigToOCISpec(config)
+		if err != nil {
+			return pod, err
+		}
+		ctrOptions, err := config.GetContainerCreateOptions(runtime)
+		if err != nil {
+			return pod, err
+		}
+		ctr, err := runtime.NewContainer(ctx, runtimeSpec, ctrOptions...)
+		if err != nil {
+			return pod, err
+		}
+		createConfigJSON, err := json.Marshal(config)
+		if err != nil {
+			return pod, err
+		}
+		if err := ctr.AddArtifact("create-config", createConfigJSON); err != nil {
+			return pod, err
+		}
+		fmt.Println(ctr.ID())
+	}
+	return pod, nil
+}
+
+// kubeVolumes returns the host paths of the hostPath volumes of a pod by
+// name, creating those to be created and checking the types of the others
+func kubeVolumes(volumes []v1.Volume) (map[string]string, error) {
+	paths := make(map[string]string)
+	for _, volume := range volumes {
+		hostPath := volume.HostPath
+		if hostPath == nil {
+			return nil, errors.Errorf("volume %q is not supported, only hostPath volumes are", volume.Name)
+		}
+		hostPathType := v1.HostPathUnset
+		if hostPath.Type != nil {
+			hostPathType = *hostPath.Type
+		}
+		switch hostPathType {
+		case v1.HostPathDirectoryOrCreate:
+			if err := os.MkdirAll(hostPath.Path, 0755); err != nil {
+				return nil, errors.Wrapf(err, "error creating directory of volume %q", volume.Name)
+			}
+		case v1.HostPathFileOrCreate:
+			f, err := os.OpenFile(hostPath.Path, os.O_CREATE|os.O_RDONLY, 0644)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error creating file of volume %q", volume.Name)
+			}
+			f.Close()
+		case v1.HostPathDirectory, v1.HostPathFile:
+			info, err := os.Stat(hostPath.Path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid volume %q", volume.Name)
+			}
+			if info.IsDir() != (hostPathType == v1.HostPathDirectory) {
+				return nil, errors.Errorf("invalid volume %q, %s is not of type %s", volume.Name, hostPath.Path, hostPathType)
+			}
+		case v1.HostPathUnset:
+		default:
+			return nil, errors.Errorf("volume %q has unsupported hostPath type %s", volume.Name, hostPathType)
+		}
+		paths[volume.Name] = hostPath.Path
+	}
+	return paths, nil
+}
+
+// kubeSharedNamespaces returns the namespaces the containers of a pod share,
+// those they do not share with the host
+func kubeSharedNamespaces(spec *v1.PodSpec) []string {
+	var shared []string
+	if !spec.HostNetwork {
+		shared = append(shared, "net", "uts")
+	}
+	if !spec.HostIPC {
+		shared = append(shared, "ipc")
+	}
+	if spec.ShareProcessNamespace != nil && *spec.ShareProcessNamespace && !spec.HostPID {
+		shared = append(shared, "pid")
+	}
+	return shared
+}
+
+// kubePortMappings returns the port mappings of the ports of the containers
+// which have a host port
+func kubePortMappings(containers []v1.Container) []ocicni.PortMapping {
+	var ports []ocicni.PortMapping
+	for _, kubeCtr := range containers {
+		for _, port := range kubeCtr.Ports {
+			if port.HostPort == 0 {
+				continue
+			}
+			protocol := strings.ToLower(string(port.Protocol))
+			if protocol == "" {
+				protocol = "tcp"
+			}
+			ports = append(ports, ocicni.PortMapping{
+				HostPort:      port.HostPort,
+				ContainerPort: port.ContainerPort,
+				Protocol:      protocol,
+				HostIP:        port.HostIP,
+			})
+		}
+	}
+	return ports
+}
+
+// kubeNamespaceMode returns the mode of a namespace of the containers of a
+// pod: the host's, the pod's if it shares it, or their own
+func kubeNamespaceMode(host, shared bool) string {
+	switch {
+	case host:
+		return "host"
+	case shared:
+		return cc.POD
+	}
+	return ""
+}
+
+// kubeContainerToCreateConfig returns the configuration of the container of
+// the pod podID for a container of a Kubernetes pod spec, run from the image
+// of data, and the security options of its security context and of the
+// seccomp and AppArmor annotations of the pod. volumes are the host paths of
+// the volumes of the pod.
+func kubeContainerToCreateConfig(kubeCtr *v1.Container, spec *v1.PodSpec, podAnnotations map[string]string, podName, podID, imageName string, data *inspect.ImageData, volumes map[string]string) (*cc.CreateConfig, []string, error) {
+	idmappings, err := util.ParseIDMapping("", nil, nil, "", "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The command of the container replaces the entrypoint of the image,
+	// and drops its command, its arguments replace the command
+	entrypoint := data.ContainerConfig.Entrypoint
+	cmd := data.ContainerConfig.Cmd
+	if len(kubeCtr.Command) > 0 {
+		entrypoint = kubeCtr.Command
+		cmd = nil
+	}
+	if len(kubeCtr.Args) > 0 {
+		cmd = kubeCtr.Args
+	}
+	command := append(append([]string{}, entrypoint...), cmd...)
+	if len(command) == 0 {
+		return nil, nil, errors.Errorf("no command specified in the container or the image")
+	}
+
+	env := make(map[string]string)
+	for k, v := range defaultEnvVariables {
+		env[k] = v
+	}
+	for _, e := range data.ContainerConfig.Env {
+		split := strings.SplitN(e, "=", 2)
+		if len(split) > 1 {
+			env[split[0]] = split[1]
+		} else {
+			env[split[0]] = ""
+		}
+	}
+	for _, e := range kubeCtr.Env {
+		if e.ValueFrom != nil {
+			return nil, nil, errors.Errorf("environment variable %s is set from a source, which is not supported", e.Name)
+		}
+		env[e.Name] = e.Value
+	}
+
+	workDir := "/"
+	if kubeCtr.WorkingDir != "" {
+		workDir = kubeCtr.WorkingDir
+	} else if data.ContainerConfig.WorkingDir != "" {
+		workDir = data.ContainerConfig.WorkingDir
+	}
+
+	var (
+		volumeMounts []string
+		timezone     string
+	)
+	for _, mount := range kubeCtr.VolumeMounts {
+		hostPath, ok := volumes[mount.Name]
+		if !ok {
+			return nil, nil, errors.Errorf("volume mount %q has no hostPath volume", mount.Name)
+		}
+		// A zoneinfo file mounted as /etc/localtime is the timezone of
+		// the container, which libpod mounts itself
+		if filepath.Clean(mount.MountPath) == "/etc/localtime" {
+			if tz, ok := kube.Timezone(hostPath); ok {
+				timezone = tz
+				continue
+			}
+		}
+		volume := hostPath + ":" + mount.MountPath
+		if mount.ReadOnly {
+			volume += ":ro"
+		}
+		volumeMounts = append(volumeMounts, volume)
+	}
+	if err := parseVolumes(volumeMounts); err != nil {
+		return nil, nil, err
+	}
+
+	stopSignal := syscall.SIGTERM
+	if data.ContainerConfig.StopSignal != "" {
+		if stopSignal, err = signal.ParseSignal(data.ContainerConfig.StopSignal); err != nil {
+			return nil, nil, err
+		}
+	}
+	stopTimeout := uint(libpod.CtrRemoveTimeout)
+	if spec.TerminationGracePeriodSeconds != nil {
+		stopTimeout = uint(*spec.TerminationGracePeriodSeconds)
+	}
+
+	annotations := map[string]string{
+		ann.ContainerType: "sandbox",
+		ann.TTY:           "false",
+	}
+	if kubeCtr.TTY {
+		annotations[ann.TTY] = "true"
+	}
+	for key, value := range data.Annotations {
+		annotations[key] = value
+	}
+
+	namespaces := kubeSharedNamespaces(spec)
+	netMode := kubeNamespaceMode(spec.HostNetwork, util.StringInSlice("net", namespaces))
+	config := &cc.CreateConfig{
+		Annotations:       annotations,
+		BuiltinImgVolumes: data.ContainerConfig.Volumes,
+		ImageVolumeType:   "bind",
+		Command:           command,
+		Detach:            true,
+		Entrypoint:        entrypoint,
+		Env:               env,
+		HealthCheck:       data.HealthCheck,
+		IDMappings:        idmappings,
+		Image:             imageName,
+		ImageID:           data.ID,
+		ImageLabels:       data.ContainerConfig.Labels,
+		Interactive:       kubeCtr.Stdin,
+		Labels:            data.ContainerConfig.Labels,
+		Name:              podName + "-" + kubeCtr.Name,
+		Network:           netMode,
+		IpcMode:           container.IpcMode(kubeNamespaceMode(spec.HostIPC, util.StringInSlice("ipc", namespaces))),
+		NetMode:           container.NetworkMode(netMode),
+		UtsMode:           container.UTSMode(kubeNamespaceMode(spec.HostNetwork, util.StringInSlice("uts", namespaces))),
+		PidMode:           container.PidMode(kubeNamespaceMode(spec.HostPID, util.StringInSlice("pid", namespaces))),
+		Pod:               podID,
+		Resources: cc.CreateResourceConfig{
+			ShmSize:          65536 * 1024,
+			MemorySwappiness: -1,
+		},
+		StopSignal:  stopSignal,
+		StopTimeout: stopTimeout,
+		Timezone:    timezone,
+		Tty:         kubeCtr.TTY,
+		User:        data.ContainerConfig.User,
+		Volumes:     volumeMounts,
+		WorkDir:     workDir,
+	}
+	if err := kubeResources(&config.Resources, kubeCtr.Resources); err != nil {
+		return nil, nil, err
+	}
+	if kubeCtr.LivenessProbe != nil {
+		if config.HealthCheck, err = kubeProbeToHealthCheck(kubeCtr.LivenessProbe, kubeCtr.Ports); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid livenessProbe")
+		}
+	}
+	securityOpts, err := kubeSecurityContext(config, spec.SecurityContext, kubeCtr.SecurityContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	annotationOpts, err := kubeSecurityAnnotations(podAnnotations, kubeCtr.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, append(securityOpts, annotationOpts...), nil
+}
+
+// kubeInitContainerType returns the type of the init container ctrName from
+// the annotations of its pod, always by default
+func kubeInitContainerType(annotations map[string]string, ctrName string) (string, error) {
+	initType, ok := annotations[kube.InitContainerTypeAnnotationPrefix+ctrName]
+	if !ok {
+		return libpod.AlwaysInitContainer, nil
+	}
+	if initType != libpod.AlwaysInitContainer && initType != libpod.OnceInitContainer {
+		return "", errors.Errorf("invalid type %q of init container %q", initType, ctrName)
+	}
+	return initType, nil
+}
+
+// kubeResources sets the resources of a container from its Kubernetes
+// requirements: memory and CPU limits, and the CPU shares and memory
+// reservation of its requests
+func kubeResources(resources *cc.CreateResourceConfig, requirements v1.ResourceRequirements) error {
+	if memory, ok := requirements.Limits[v1.ResourceMemory]; ok {
+		resources.Memory = memory.Value()
+	}
+	if cpu, ok := requirements.Limits[v1.ResourceCPU]; ok {
+		resources.CPUs = float64(cpu.MilliValue()) / 1000
+	}
+	if memory, ok := requirements.Requests[v1.ResourceMemory]; ok {
+		resources.MemoryReservation = memory.Value()
+	}
+	if cpu, ok := requirements.Requests[v1.ResourceCPU]; ok {
+		// As the kubelet converts requests to shares
+		shares := uint64(cpu.MilliValue()) * 1024 / 1000
+		if shares < 2 {
+			shares = 2
+		}
+		resources.CPUShares = shares
+	}
+	if resources.Memory < 0 || resources.MemoryReservation < 0 || resources.CPUs < 0 {
+		return errors.Errorf("resources can not be negative")
+	}
+	return nil
+}
+
+// kubeSecurityContext sets the user, groups, privileges, capabilities and
+// read-only root filesystem of a container from its Kubernetes security
+// context and the one of its pod, and returns its security options
+func kubeSecurityContext(config *cc.CreateConfig, podContext *v1.PodSecurityContext, context *v1.SecurityContext) ([]string, error) {
+	var (
+		runAsUser      *int64
+		runAsNonRoot   *bool
+		seLinuxOptions *v1.SELinuxOptions
+		securityOpts   []string
+	)
+	if podContext != nil {
+		runAsUser = podContext.RunAsUser
+		runAsNonRoot = podContext.RunAsNonRoot
+		seLinuxOptions = podContext.SELinuxOptions
+		for _, group := range podContext.SupplementalGroups {
+			config.GroupAdd = append(config.GroupAdd, strconv.FormatInt(group, 10))
+		}
+		if podContext.FSGroup != nil {
+			config.GroupAdd = append(config.GroupAdd, strconv.FormatInt(*podContext.FSGroup, 10))
+		}
+	}
+	if context != nil {
+		if context.RunAsUser != nil {
+			runAsUser = context.RunAsUser
+		}
+		if context.RunAsNonRoot != nil {
+			runAsNonRoot = context.RunAsNonRoot
+		}
+		if context.SELinuxOptions != nil {
+			seLinuxOptions = context.SELinuxOptions
+		}
+		if context.Privileged != nil {
+			config.Privileged = *context.Privileged
+		}
+		if context.ReadOnlyRootFilesystem != nil {
+			config.ReadOnlyRootfs = *context.ReadOnlyRootFilesystem
+		}
+		if context.AllowPrivilegeEscalation != nil && !*context.AllowPrivilegeEscalation {
+			securityOpts = append(securityOpts, "no-new-privileges")
+		}
+		if context.Capabilities != nil {
+			for _, capability := range context.Capabilities.Add {
+				config.CapAdd = append(config.CapAdd, string(capability))
+			}
+			for _, capability := range context.Capabilities.Drop {
+				config.CapDrop = append(config.CapDrop, string(capability))
+			}
+		}
+	}
+
+	if runAsUser != nil {
+		config.User = strconv.FormatInt(*runAsUser, 10)
+	}
+	if runAsNonRoot != nil && *runAsNonRoot {
+		// Like the kubelet, refuse to run as root, which is also the user
+		// of images that do not set one
+		user := strings.SplitN(config.User, ":", 2)[0]
+		if user == "" || user == "0" || user == "root" {
+			return nil, errors.Errorf("runAsNonRoot is set but the container would run as root")
+		}
+	}
+	if seLinuxOptions != nil {
+		for _, label := range []struct{ key, value string }{
+			{"user", seLinuxOptions.User},
+			{"role", seLinuxOptions.Role},
+			{"type", seLinuxOptions.Type},
+			{"level", seLinuxOptions.Level},
+		} {
+			if label.value != "" {
+				securityOpts = append(securityOpts, fmt.Sprintf("label=%s:%s", label.key, label.value))
+			}
+		}
+	}
+	return securityOpts, nil
+}
+
+// kubeSecurityAnnotations returns the security options of the container
+// ctrName from the seccomp and AppArmor annotations of its pod, the seccomp
+// profile of the container overriding the one of the pod
+func kubeSecurityAnnotations(annotations map[string]string, ctrName string) ([]string, error) {
+	var securityOpts []string
+
+	profile, ok := annotations[v1.SeccompContainerAnnotationKeyPrefix+ctrName]
+	if !ok {
+		profile = annotations[v1.SeccompPodAnnotationKey]
+	}
+	switch {
+	case profile == "", profile == "runtime/default", profile == "docker/default":
+	case profile == "unconfined":
+		securityOpts = append(securityOpts, "seccomp=unconfined")
+	case strings.HasPrefix(profile, "localhost/"):
+		path := strings.TrimPrefix(profile, "localhost/")
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(kubeSeccompProfileRoot, path)
+		}
+		securityOpts = append(securityOpts, "seccomp="+path)
+	default:
+		return nil, errors.Errorf("unsupported seccomp profile %q", profile)
+	}
+
+	profile = annotations[kubeAppArmorAnnotationKeyPrefix+ctrName]
+	switch {
+	case profile == "", profile == "runtime/default":
+	case profile == "unconfined":
+		securityOpts = append(securityOpts, "apparmor=unconfined")
+	case strings.HasPrefix(profile, "localhost/"):
+		securityOpts = append(securityOpts, "apparmor="+strings.TrimPrefix(profile, "localhost/"))
+	default:
+		return nil, errors.Errorf("unsupported AppArmor profile %q", profile)
+	}
+	return securityOpts, nil
+}
+
+// kubeProbeToHealthCheck returns the healthcheck running a Kubernetes probe
+// of a container with ports. HTTP and TCP probes run curl and nc in the
+// container, which must provide them.
+func kubeProbeToHealthCheck(probe *v1.Probe, ports []v1.ContainerPort) (*manifest.Schema2HealthConfig, error) {
+	var test []string
+	switch {
+	case probe.Exec != nil:
+		if len(probe.Exec.Command) == 0 {
+			return nil, errors.Errorf("exec probe has no command")
+		}
+		test = append([]string{"CMD"}, probe.Exec.Command...)
+	case probe.HTTPGet != nil:
+		port, err := kubeProbePort(probe.HTTPGet.Port, ports)
+		if err != nil {
+			return nil, err
+		}
+		scheme := strings.ToLower(string(probe.HTTPGet.Scheme))
+		if scheme == "" {
+			scheme = "http"
+		}
+		host := probe.HTTPGet.Host
+		if host == "" {
+			host = "localhost"
+		}
+		path := probe.HTTPGet.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		cmd := []string{"curl", "-f", "-s", "-o", "/dev/null"}
+		if scheme == "https" {
+			cmd = append(cmd, "-k")
+		}
+		for _, header := range probe.HTTPGet.HTTPHeaders {
+			cmd = append(cmd, "-H", header.Name+": "+header.Value)
+		}
+		test = append([]string{"CMD"}, append(cmd, fmt.Sprintf("%s://%s:%d%s", scheme, host, port, path))...)
+	case probe.TCPSocket != nil:
+		port, err := kubeProbePort(probe.TCPSocket.Port, ports)
+		if err != nil {
+			return nil, err
+		}
+		host := probe.TCPSocket.Host
+		if host == "" {
+			host = "localhost"
+		}
+		test = []string{"CMD", "nc", "-z", host, strconv.Itoa(port)}
+	default:
+		return nil, errors.Errorf("probe has no handler")
+	}
+
+	healthCheck := &manifest.Schema2HealthConfig{
+		Test:     test,
+		Interval: 10 * time.Second,
+		Timeout:  time.Second,
+		Retries:  3,
+	}
+	if probe.PeriodSeconds > 0 {
+		healthCheck.Interval = time.Duration(probe.PeriodSeconds) * time.Second
+	}
+	if probe.TimeoutSeconds > 0 {
+		healthCheck.Timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+	if probe.FailureThreshold > 0 {
+		healthCheck.Retries = int(probe.FailureThreshold)
+	}
+	return healthCheck, nil
+}
+
+// kubeProbePort returns the number of the port of a probe, looking named
+// ports up in the ports of its container
+func kubeProbePort(port intstr.IntOrString, ports []v1.ContainerPort) (int, error) {
+	if port.Type == intstr.Int {
+		if port.IntVal <= 0 || port.IntVal > 65535 {
+			return 0, errors.Errorf("invalid port %d", port.IntVal)
+		}
+		return int(port.IntVal), nil
+	}
+	for _, p := range ports {
+		if p.Name == port.StrVal {
+			return int(p.ContainerPort), nil
+		}
+	}
+	return 0, errors.Errorf("no container port named %q", port.StrVal)
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/adapter/kube"
+	"github.com/containers/libpod/pkg/inspect"
+	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const kubePodYAML = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  terminationGracePeriodSeconds: 5
+  securityContext:
+    runAsUser: 1000
+  containers:
+  - name: nginx
+    image: nginx:alpine
+    args: ["-g", "daemon off;"]
+    env:
+    - name: MODE
+      value: production
+    ports:
+    - containerPort: 80
+      hostPort: 8080
+    - containerPort: 9000
+    securityContext:
+      readOnlyRootFilesystem: true
+      allowPrivilegeEscalation: false
+      capabilities:
+        add: ["NET_ADMIN"]
+        drop: ["MKNOD"]
+      seLinuxOptions:
+        level: "s0:c1,c2"
+    resources:
+      limits:
+        memory: 64Mi
+        cpu: 500m
+      requests:
+        cpu: 250m
+    volumeMounts:
+    - name: data
+      mountPath: /data
+      readOnly: true
+  volumes:
+  - name: data
+    hostPath:
+      path: /srv/data
+`
+
+func TestReadKubePods(t *testing.T) {
+	pods, err := readKubePods([]byte(kubePodYAML))
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "web", pods[0].Name)
+	assert.Equal(t, map[string]string{"app": "web"}, pods[0].Labels)
+	require.Len(t, pods[0].Spec.Containers, 1)
+	assert.Equal(t, "nginx:alpine", pods[0].Spec.Containers[0].Image)
+
+	pods, err = readKubePods([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:alpine
+`))
+	require.NoError(t, err)
+	require.Len(t, pods, 2)
+	assert.Equal(t, "web-pod-0", pods[0].Name)
+	assert.Equal(t, "web-pod-1", pods[1].Name)
+	assert.Equal(t, map[string]string{"app": "web"}, pods[1].Labels)
+	require.Len(t, pods[1].Spec.Containers, 1)
+
+	pods, err = readKubePods([]byte(kubePodYAML + "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"))
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "web", pods[0].Name)
+
+	for _, content := range []string{"kind: Service\n", "kind: ReplicaSet\n", "metadata:\n  name: web\n", "kind: [\n", "---\n"} {
+		_, err := readKubePods([]byte(content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestKubeVolumes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volumes")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	hostPathVolume := func(name, path string, hostPathType v1.HostPathType) v1.Volume {
+		return v1.Volume{Name: name, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: path, Type: &hostPathType}}}
+	}
+
+	created := filepath.Join(dir, "created")
+	file := filepath.Join(dir, "created", "file")
+	volumes, err := kubeVolumes([]v1.Volume{
+		hostPathVolume("dir", created, v1.HostPathDirectoryOrCreate),
+		hostPathVolume("file", file, v1.HostPathFileOrCreate),
+		hostPathVolume("existing", dir, v1.HostPathDirectory),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"dir": created, "file": file, "existing": dir}, volumes)
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.False(t, info.IsDir())
+
+	for _, volume := range []v1.Volume{
+		hostPathVolume("missing", filepath.Join(dir, "missing"), v1.HostPathDirectory),
+		hostPathVolume("notfile", dir, v1.HostPathFile),
+		hostPathVolume("socket", dir, v1.HostPathSocket),
+		{Name: "empty", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+	} {
+		_, err := kubeVolumes([]v1.Volume{volume})
+		assert.Error(t, err, volume.Name)
+	}
+}
+
+func TestKubeSharedNamespaces(t *testing.T) {
+	share := true
+	assert.Equal(t, []string{"net", "uts", "ipc"}, kubeSharedNamespaces(&v1.PodSpec{}))
+	assert.Equal(t, []string{"ipc", "pid"}, kubeSharedNamespaces(&v1.PodSpec{HostNetwork: true, ShareProcessNamespace: &share}))
+	assert.Empty(t, kubeSharedNamespaces(&v1.PodSpec{HostNetwork: true, HostIPC: true, HostPID: true, ShareProcessNamespace: &share}))
+}
+
+func TestKubePortMappings(t *testing.T) {
+	pods, err := readKubePods([]byte(kubePodYAML))
+	require.NoError(t, err)
+	assert.Equal(t, []ocicni.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}, kubePortMappings(pods[0].Spec.Containers))
+	assert.Empty(t, kubePortMappings([]v1.Container{{Ports: []v1.ContainerPort{{ContainerPort: 53, Protocol: v1.ProtocolUDP}}}}))
+	assert.Equal(t, []ocicni.PortMapping{{HostPort: 53, ContainerPort: 53, Protocol: "udp", HostIP: "127.0.0.1"}},
+		kubePortMappings([]v1.Container{{Ports: []v1.ContainerPort{{HostPort: 53, ContainerPort: 53, Protocol: v1.ProtocolUDP, HostIP: "127.0.0.1"}}}}))
+}
+
+func TestKubeContainerToCreateConfig(t *testing.T) {
+	pods, err := readKubePods([]byte(kubePodYAML))
+	require.NoError(t, err)
+	spec := &pods[0].Spec
+	dir, err := ioutil.TempDir("", "data")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	volumes := map[string]string{"data": dir}
+	data := &inspect.ImageData{
+		ID: "0123456789ab",
+		ContainerConfig: &ociv1.ImageConfig{
+			Entrypoint: []string{"nginx"},
+			Cmd:        []string{"-c", "/etc/nginx/nginx.conf"},
+			Env:        []string{"MODE=development", "NGINX_VERSION=1.15"},
+			WorkingDir: "/usr/share/nginx",
+		},
+	}
+
+	config, securityOpts, err := kubeContainerToCreateConfig(&spec.Containers[0], spec, nil, "web", "podid", "nginx:alpine", data, volumes)
+	require.NoError(t, err)
+	assert.Equal(t, "web-nginx", config.Name)
+	assert.Equal(t, "podid", config.Pod)
+	assert.Equal(t, []string{"nginx", "-g", "daemon off;"}, config.Command)
+	assert.Equal(t, "production", config.Env["MODE"])
+	assert.Equal(t, "1.15", config.Env["NGINX_VERSION"])
+	assert.Equal(t, defaultEnvVariables["PATH"], config.Env["PATH"])
+	assert.Equal(t, "/usr/share/nginx", config.WorkDir)
+	assert.Equal(t, []string{dir + ":/data:ro"}, config.Volumes)
+	assert.Equal(t, "1000", config.User)
+	assert.True(t, config.ReadOnlyRootfs)
+	assert.Equal(t, []string{"NET_ADMIN"}, config.CapAdd)
+	assert.Equal(t, []string{"MKNOD"}, config.CapDrop)
+	assert.Equal(t, []string{"no-new-privileges", "label=level:s0:c1,c2"}, securityOpts)
+	assert.Equal(t, cc.POD, string(config.NetMode))
+	assert.Equal(t, cc.POD, string(config.IpcMode))
+	assert.Equal(t, "", string(config.PidMode))
+	assert.Equal(t, uint(5), config.StopTimeout)
+	assert.Equal(t, int64(64*1024*1024), config.Resources.Memory)
+	assert.Equal(t, 0.5, config.Resources.CPUs)
+	assert.Equal(t, uint64(256), config.Resources.CPUShares)
+
+	spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, v1.VolumeMount{Name: "localtime", MountPath: "/etc/localtime", ReadOnly: true})
+	volumes["localtime"] = "/usr/share/zoneinfo/Europe/Paris"
+	config, _, err = kubeContainerToCreateConfig(&spec.Containers[0], spec, nil, "web", "podid", "nginx:alpine", data, volumes)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Paris", config.Timezone)
+	assert.Equal(t, []string{dir + ":/data:ro"}, config.Volumes)
+
+	spec.Containers[0].Command = []string{"/bin/sh"}
+	spec.Containers[0].Args = nil
+	config, _, err = kubeContainerToCreateConfig(&spec.Containers[0], spec, nil, "web", "podid", "nginx:alpine", data, volumes)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/bin/sh"}, config.Command)
+
+	_, _, err = kubeContainerToCreateConfig(&spec.Containers[0], spec, nil, "web", "podid", "nginx:alpine", data, nil)
+	assert.Error(t, err)
+	spec.Containers[0].VolumeMounts = nil
+	spec.Containers[0].Env = append(spec.Containers[0].Env, v1.EnvVar{Name: "HOST", ValueFrom: &v1.EnvVarSource{}})
+	_, _, err = kubeContainerToCreateConfig(&spec.Containers[0], spec, nil, "web", "podid", "nginx:alpine", data, nil)
+	assert.Error(t, err)
+}
+
+func TestKubeResources(t *testing.T) {
+	var resources cc.CreateResourceConfig
+	require.NoError(t, kubeResources(&resources, v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse("32Mi"),
+			v1.ResourceCPU:    resource.MustParse("1m"),
+		},
+	}))
+	assert.Equal(t, int64(32*1024*1024), resources.MemoryReservation)
+	assert.Equal(t, uint64(2), resources.CPUShares)
+	assert.Error(t, kubeResources(&resources, v1.ResourceRequirements{
+		Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("-1")},
+	}))
+}
+
+func TestKubeSecurityContext(t *testing.T) {
+	nonRoot, root := true, false
+	fsGroup := int64(2000)
+	config := &cc.CreateConfig{}
+	securityOpts, err := kubeSecurityContext(config, &v1.PodSecurityContext{
+		RunAsNonRoot:       &nonRoot,
+		SupplementalGroups: []int64{10, 20},
+		FSGroup:            &fsGroup,
+	}, &v1.SecurityContext{RunAsNonRoot: &root})
+	require.NoError(t, err)
+	assert.Empty(t, securityOpts)
+	assert.Equal(t, []string{"10", "20", "2000"}, config.GroupAdd)
+
+	for _, user := range []string{"", "0", "root", "root:wheel"} {
+		_, err = kubeSecurityContext(&cc.CreateConfig{User: user}, &v1.PodSecurityContext{RunAsNonRoot: &nonRoot}, nil)
+		assert.Error(t, err, user)
+	}
+	_, err = kubeSecurityContext(&cc.CreateConfig{User: "nginx"}, &v1.PodSecurityContext{RunAsNonRoot: &nonRoot}, nil)
+	assert.NoError(t, err)
+}
+
+func TestKubeSecurityAnnotations(t *testing.T) {
+	securityOpts, err := kubeSecurityAnnotations(nil, "web")
+	require.NoError(t, err)
+	assert.Empty(t, securityOpts)
+
+	annotations := map[string]string{
+		v1.SeccompPodAnnotationKey:                     "unconfined",
+		v1.SeccompContainerAnnotationKeyPrefix + "web": "localhost/web.json",
+		kubeAppArmorAnnotationKeyPrefix + "web":        "localhost/web-profile",
+		kubeAppArmorAnnotationKeyPrefix + "db":         "unconfined",
+	}
+	securityOpts, err = kubeSecurityAnnotations(annotations, "web")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"seccomp=" + filepath.Join(kubeSeccompProfileRoot, "web.json"), "apparmor=web-profile"}, securityOpts)
+	securityOpts, err = kubeSecurityAnnotations(annotations, "db")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"seccomp=unconfined", "apparmor=unconfined"}, securityOpts)
+
+	_, err = kubeSecurityAnnotations(map[string]string{v1.SeccompPodAnnotationKey: "other"}, "web")
+	assert.Error(t, err)
+}
+
+func TestKubeProbeToHealthCheck(t *testing.T) {
+	ports := []v1.ContainerPort{{Name: "http", ContainerPort: 8080}}
+
+	healthCheck, err := kubeProbeToHealthCheck(&v1.Probe{
+		Handler: v1.Handler{Exec: &v1.ExecAction{Command: []string{"cat", "/tmp/healthy"}}},
+	}, ports)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CMD", "cat", "/tmp/healthy"}, healthCheck.Test)
+	assert.Equal(t, 10*time.Second, healthCheck.Interval)
+	assert.Equal(t, time.Second, healthCheck.Timeout)
+	assert.Equal(t, 3, healthCheck.Retries)
+
+	healthCheck, err = kubeProbeToHealthCheck(&v1.Probe{
+		Handler: v1.Handler{HTTPGet: &v1.HTTPGetAction{
+			Path:        "healthz",
+			Port:        intstr.FromString("http"),
+			HTTPHeaders: []v1.HTTPHeader{{Name: "X-Probe", Value: "1"}},
+		}},
+		PeriodSeconds:    5,
+		TimeoutSeconds:   2,
+		FailureThreshold: 1,
+	}, ports)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CMD", "curl", "-f", "-s", "-o", "/dev/null", "-H", "X-Probe: 1", "http://localhost:8080/healthz"}, healthCheck.Test)
+	assert.Equal(t, 5*time.Second, healthCheck.Interval)
+	assert.Equal(t, 2*time.Second, healthCheck.Timeout)
+	assert.Equal(t, 1, healthCheck.Retries)
+
+	healthCheck, err = kubeProbeToHealthCheck(&v1.Probe{
+		Handler: v1.Handler{TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(5432)}},
+	}, ports)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CMD", "nc", "-z", "localhost", "5432"}, healthCheck.Test)
+
+	_, err = kubeProbeToHealthCheck(&v1.Probe{
+		Handler: v1.Handler{TCPSocket: &v1.TCPSocketAction{Port: intstr.FromString("db")}},
+	}, ports)
+	assert.Error(t, err)
+	_, err = kubeProbeToHealthCheck(&v1.Probe{}, ports)
+	assert.Error(t, err)
+}
+
+func TestKubeInitContainerType(t *testing.T) {
+	annotations := map[string]string{
+		kube.InitContainerTypeAnnotationPrefix + "setup":   libpod.OnceInitContainer,
+		kube.InitContainerTypeAnnotationPrefix + "migrate": "sometimes",
+	}
+	initType, err := kubeInitContainerType(annotations, "setup")
+	require.NoError(t, err)
+	assert.Equal(t, libpod.OnceInitContainer, initType)
+	initType, err = kubeInitContainerType(annotations, "wait")
+	require.NoError(t, err)
+	assert.Equal(t, libpod.AlwaysInitContainer, initType)
+	_, err = kubeInitContainerType(annotations, "migrate")
+	assert.Error(t, err)
+}
+
+func TestKubeExitCode(t *testing.T) {
+	for _, test := range []struct {
+		policy    string
+		exitCodes []int32
+		exitCode  int
+	}{
+		{kubeExitCodeNone, []int32{1, 2}, 0},
+		{kubeExitCodeAnyFailed, []int32{0, 2, 3}, 2},
+		{kubeExitCodeAnyFailed, []int32{0, 0}, 0},
+		{kubeExitCodeAllFailed, []int32{0, 2}, 0},
+		{kubeExitCodeAllFailed, []int32{1, 2}, 2},
+		{kubeExitCodeAllFailed, nil, 0},
+	} {
+		assert.Equal(t, test.exitCode, kubeExitCode(test.policy, test.exitCodes), "%s %v", test.policy, test.exitCodes)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/cmd/podman/shared"
 	"github.com/containers/libpod/libpod"
+	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -18,6 +20,10 @@ var (
 	DefaultKernelNamespaces = "ipc,net,uts"
 )
 
+// podCPUPeriod is the period of the CPU quota of pods with --cpus, in
+// microseconds
+const podCPUPeriod = 100000
+
 var podCreateDescription = "Creates a new empty pod. The pod ID is then" +
 	" printed to stdout. You can then start it at any time with the" +
 	" podman pod start <pod_id> command. The pod will be created with the" +
@@ -37,6 +43,26 @@ var podCreateFlags = []cli.Flag{
 		Usage: "The image of the infra container to associate with the pod",
 		Value: libpod.DefaultInfraImage,
 	},
+	cli.StringSliceFlag{
+		Name:  "infra-cap-add",
+		Usage: "Add capabilities to the infra container of the pod (default [])",
+	},
+	cli.Float64Flag{
+		Name:  "cpus",
+		Usage: "Number of CPUs the containers of the pod can use together",
+	},
+	cli.StringSliceFlag{
+		Name:  "dns",
+		Usage: "Set custom DNS servers of the containers sharing the network namespace of the pod",
+	},
+	cli.StringSliceFlag{
+		Name:  "dns-opt",
+		Usage: "Set custom DNS options of the containers sharing the network namespace of the pod",
+	},
+	cli.StringSliceFlag{
+		Name:  "dns-search",
+		Usage: "Set custom DNS search domains of the containers sharing the network namespace of the pod",
+	},
 	cli.StringFlag{
 		Name:  "infra-command",
 		Usage: "The command to run on the infra container when the pod is started",
@@ -50,19 +76,35 @@ var podCreateFlags = []cli.Flag{
 		Name:  "label, l",
 		Usage: "Set metadata on pod (default [])",
 	},
+	cli.StringFlag{
+		Name:  "memory, m",
+		Usage: "Memory limit of the containers of the pod together (format: <number>[<unit>], where unit = b, k, m or g)",
+	},
 	cli.StringFlag{
 		Name:  "name, n",
 		Usage: "Assign a name to the pod",
 	},
+	cli.StringFlag{
+		Name:  "name-reservation",
+		Usage: "Token of the reservation of the name of the pod, from `podman name reserve`",
+	},
 	cli.StringFlag{
 		Name:  "pod-id-file",
 		Usage: "Write the pod ID to the file",
 	},
+	cli.StringSliceFlag{
+		Name:  "publish, p",
+		Usage: "Publish a port, or a range of ports, of the pod to the host (default [])",
+	},
 	cli.StringFlag{
 		Name:  "share",
 		Usage: "A comma delimited list of kernel namespaces the pod will share",
 		Value: DefaultKernelNamespaces,
 	},
+	cli.StringSliceFlag{
+		Name:  "sysctl",
+		Usage: "Sysctl options of the infra container of the pod (default [])",
+	},
 }
 
 var podCreateCommand = cli.Command{
@@ -113,7 +155,12 @@ func podCreateCmd(c *cli.Context) error {
 		options = append(options, libpod.WithPodLabels(labels))
 	}
 
-	if c.IsSet("name") {
+	if c.IsSet("name-reservation") {
+		if !c.IsSet("name") {
+			return errors.Errorf("--name-reservation requires --name")
+		}
+		options = append(options, libpod.WithPodNameReservation(c.String("name"), c.String("name-reservation")))
+	} else if c.IsSet("name") {
 		options = append(options, libpod.WithPodName(c.String("name")))
 	}
 
@@ -124,12 +171,37 @@ func podCreateCmd(c *cli.Context) error {
 			return err
 		}
 		options = append(options, nsOptions...)
+		infraOptions, err := podInfraOptions(c)
+		if err != nil {
+			return err
+		}
+		options = append(options, infraOptions...)
+	} else {
+		for _, flag := range podInfraFlags {
+			if c.IsSet(flag) {
+				return errors.Errorf("--%s configures the infra container, it cannot be used with --infra=false", flag)
+			}
+		}
 	}
 
 	// always have containers use pod cgroups
 	// User Opt out is not yet supported
 	options = append(options, libpod.WithPodCgroups())
 
+	if c.IsSet("memory") {
+		memoryLimit, err := units.RAMInBytes(c.String("memory"))
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for memory")
+		}
+		options = append(options, libpod.WithPodMemoryLimit(memoryLimit))
+	}
+	if c.IsSet("cpus") {
+		if c.Float64("cpus") <= 0 {
+			return errors.Errorf("invalid value for cpus, it must be positive")
+		}
+		options = append(options, libpod.WithPodCPUQuota(int64(c.Float64("cpus")*podCPUPeriod), podCPUPeriod))
+	}
+
 	ctx := getContext()
 	pod, err := runtime.NewPod(ctx, options...)
 	if err != nil {
@@ -147,3 +219,54 @@ func podCreateCmd(c *cli.Context) error {
 
 	return nil
 }
+
+// podInfraFlags are the flags of pod create configuring the infra container
+var podInfraFlags = []string{"infra-image", "infra-command", "infra-cap-add", "publish", "sysctl", "dns", "dns-opt", "dns-search"}
+
+// podInfraOptions returns the options configuring the infra container of the
+// pod from the flags set
+func podInfraOptions(c *cli.Context) ([]libpod.PodCreateOption, error) {
+	var options []libpod.PodCreateOption
+
+	if c.IsSet("infra-image") {
+		options = append(options, libpod.WithInfraImage(c.String("infra-image")))
+	}
+	if c.IsSet("infra-command") {
+		command := strings.Fields(c.String("infra-command"))
+		if len(command) == 0 {
+			return nil, errors.Errorf("the infra command cannot be empty")
+		}
+		options = append(options, libpod.WithInfraCommand(command))
+	}
+	if c.IsSet("infra-cap-add") {
+		options = append(options, libpod.WithInfraCapAdd(c.StringSlice("infra-cap-add")))
+	}
+	if c.IsSet("publish") {
+		portBindings, err := cc.ExposedPorts(nil, c.StringSlice("publish"), false, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to process ports")
+		}
+		ports, err := (&cc.CreateConfig{PortBindings: portBindings}).CreatePortBindings()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, libpod.WithInfraContainerPorts(ports))
+	}
+	if c.IsSet("sysctl") {
+		sysctls, err := validateSysctl(c.StringSlice("sysctl"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for sysctl")
+		}
+		options = append(options, libpod.WithInfraSysctls(sysctls))
+	}
+	if c.IsSet("dns") {
+		options = append(options, libpod.WithInfraDNS(c.StringSlice("dns")))
+	}
+	if c.IsSet("dns-search") {
+		options = append(options, libpod.WithInfraDNSSearch(c.StringSlice("dns-search")))
+	}
+	if c.IsSet("dns-opt") {
+		options = append(options, libpod.WithInfraDNSOption(c.StringSlice("dns-opt")))
+	}
+	return options, nil
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -12,7 +11,6 @@ import (
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/cmd/podman/shared"
 	"github.com/containers/libpod/libpod"
-	"github.com/containers/libpod/pkg/util"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 	"github.com/docker/go-units"
 	"github.com/pkg/errors"
@@ -73,6 +71,7 @@ type psJSONParams struct {
 	Namespaces       *shared.Namespace     `json:"namespace,omitempty"`
 	Pod              string                `json:"pod,omitempty"`
 	IsInfra          bool                  `json:"infra"`
+	Health           string                `json:"health,omitempty"`
 }
 
 // Type declaration and functions for sorting the PS output
@@ -189,6 +188,7 @@ var (
 	}
 	lsCommand = cli.Command{
 		Name:                   "ls",
+		Aliases:                []string{"list", "ps"},
 		Usage:                  "List containers",
 		Description:            psDescription,
 		Flags:                  psFlags,
@@ -245,20 +245,11 @@ func psCmd(c *cli.Context) error {
 		})
 	}
 
-	filters := c.StringSlice("filter")
-	if len(filters) > 0 {
-		for _, f := range filters {
-			filterSplit := strings.SplitN(f, "=", 2)
-			if len(filterSplit) < 2 {
-				return errors.Errorf("filter input must be in the form of filter=value: %s is invalid", f)
-			}
-			generatedFunc, err := generateContainerFilterFuncs(filterSplit[0], filterSplit[1], runtime)
-			if err != nil {
-				return errors.Wrapf(err, "invalid filter")
-			}
-			filterFuncs = append(filterFuncs, generatedFunc)
-		}
+	generatedFuncs, err := shared.ParseContainerFilters(c.StringSlice("filter"), runtime)
+	if err != nil {
+		return err
 	}
+	filterFuncs = append(filterFuncs, generatedFuncs...)
 
 	var outputContainers []*libpod.Container
 
@@ -309,115 +300,6 @@ func checkFlagsPassed(c *cli.Context) error {
 	return nil
 }
 
-func generateContainerFilterFuncs(filter, filterValue string, runtime *libpod.Runtime) (func(container *libpod.Container) bool, error) {
-	switch filter {
-	case "id":
-		return func(c *libpod.Container) bool {
-			return strings.Contains(c.ID(), filterValue)
-		}, nil
-	case "label":
-		var filterArray []string = strings.SplitN(filterValue, "=", 2)
-		var filterKey string = filterArray[0]
-		if len(filterArray) > 1 {
-			filterValue = filterArray[1]
-		} else {
-			filterValue = ""
-		}
-		return func(c *libpod.Container) bool {
-			for labelKey, labelValue := range c.Labels() {
-				if labelKey == filterKey && ("" == filterValue || labelValue == filterValue) {
-					return true
-				}
-			}
-			return false
-		}, nil
-	case "name":
-		return func(c *libpod.Container) bool {
-			return strings.Contains(c.Name(), filterValue)
-		}, nil
-	case "exited":
-		exitCode, err := strconv.ParseInt(filterValue, 10, 32)
-		if err != nil {
-			return nil, errors.Wrapf(err, "exited code out of range %q", filterValue)
-		}
-		return func(c *libpod.Container) bool {
-			ec, exited, err := c.ExitCode()
-			if ec == int32(exitCode) && err == nil && exited == true {
-				return true
-			}
-			return false
-		}, nil
-	case "status":
-		if !util.StringInSlice(filterValue, []string{"created", "restarting", "running", "paused", "exited", "unknown"}) {
-			return nil, errors.Errorf("%s is not a valid status", filterValue)
-		}
-		return func(c *libpod.Container) bool {
-			status, err := c.State()
-			if err != nil {
-				return false
-			}
-			state := status.String()
-			if status == libpod.ContainerStateConfigured {
-				state = "created"
-			}
-			return state == filterValue
-		}, nil
-	case "ancestor":
-		// This needs to refine to match docker
-		// - ancestor=(<image-name>[:tag]|<image-id>| ⟨image@digest⟩) - containers created from an image or a descendant.
-		return func(c *libpod.Container) bool {
-			containerConfig := c.Config()
-			if strings.Contains(containerConfig.RootfsImageID, filterValue) || strings.Contains(containerConfig.RootfsImageName, filterValue) {
-				return true
-			}
-			return false
-		}, nil
-	case "before":
-		ctr, err := runtime.LookupContainer(filterValue)
-		if err != nil {
-			return nil, errors.Errorf("unable to find container by name or id of %s", filterValue)
-		}
-		containerConfig := ctr.Config()
-		createTime := containerConfig.CreatedTime
-		return func(c *libpod.Container) bool {
-			cc := c.Config()
-			return createTime.After(cc.CreatedTime)
-		}, nil
-	case "since":
-		ctr, err := runtime.LookupContainer(filterValue)
-		if err != nil {
-			return nil, errors.Errorf("unable to find container by name or id of %s", filterValue)
-		}
-		containerConfig := ctr.Config()
-		createTime := containerConfig.CreatedTime
-		return func(c *libpod.Container) bool {
-			cc := c.Config()
-			return createTime.Before(cc.CreatedTime)
-		}, nil
-	case "volume":
-		//- volume=(<volume-name>|<mount-point-destination>)
-		return func(c *libpod.Container) bool {
-			containerConfig := c.Config()
-			var dest string
-			arr := strings.Split(filterValue, ":")
-			source := arr[0]
-			if len(arr) == 2 {
-				dest = arr[1]
-			}
-			for _, mount := range containerConfig.Spec.Mounts {
-				if dest != "" && (mount.Source == source && mount.Destination == dest) {
-					return true
-				}
-				if dest == "" && mount.Source == source {
-					return true
-				}
-			}
-			return false
-		}, nil
-	}
-	return nil, errors.Errorf("%s is an invalid filter", filter)
-}
-
 // generate the template based on conditions given
 func genPsFormat(format string, quiet, size, namespace, pod, infra bool) string {
 	if format != "" {
@@ -551,9 +433,21 @@ func getTemplateOutput(psParams []psJSONParams, opts shared.PsOptions) ([]psTemp
 			status = fmt.Sprintf("Exited (%d) %s ago", psParam.ExitCode, exitedSince)
 		case libpod.ContainerStateRunning.String():
 			status = "Up " + units.HumanDuration(time.Since(psParam.StartedAt)) + " ago"
+			if psParam.Health != "" {
+				status += " (" + psParam.Health + ")"
+			}
 		case libpod.ContainerStatePaused.String():
 			status = "Paused"
-		case libpod.ContainerStateCreated.String(), libpod.ContainerStateConfigured.String():
+		case libpod.ContainerStateConfigured.String():
+			// Containers running when the system rebooted are
+			// configured again, but have exited
+			if psParam.Exited {
+				exitedSince := units.HumanDuration(time.Since(psParam.ExitedAt))
+				status = fmt.Sprintf("Exited (%d) %s ago", psParam.ExitCode, exitedSince)
+			} else {
+				status = "Created"
+			}
+		case libpod.ContainerStateCreated.String():
 			status = "Created"
 		default:
 			status = "Error"
@@ -635,6 +529,7 @@ func getAndSortJSONParams(containers []*libpod.Container, opts shared.PsOptions)
 			Namespaces:       ns,
 			Pod:              ctr.PodID(),
 			IsInfra:          ctr.IsInfra(),
+			Health:           batchInfo.Health,
 		}
 
 		psOutput = append(psOutput, params)
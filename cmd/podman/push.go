@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strings"
 
 	"github.com/containers/image/directory"
 	"github.com/containers/image/manifest"
@@ -99,9 +98,12 @@ func pushCmd(c *cli.Context) error {
 	}
 
 	// --compress and --format can only be used for the "dir" transport
-	splitArg := strings.SplitN(destName, ":", 2)
 	if c.IsSet("compress") || c.IsSet("format") {
-		if splitArg[0] != directory.Transport.Name() {
+		dest, err := image.ParseDestination(destName, image.DefaultTransport)
+		if err != nil {
+			return err
+		}
+		if dest.Transport().Name() != directory.Transport.Name() {
 			return errors.Errorf("--compress and --format can be set only when pushing to a directory using the 'dir' transport")
 		}
 	}
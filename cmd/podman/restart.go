@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/cmd/podman/shared"
 	"github.com/containers/libpod/libpod"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
@@ -18,6 +19,10 @@ var (
 			Usage: "Seconds to wait for stop before killing the container",
 			Value: libpod.CtrRemoveTimeout,
 		},
+		cli.StringSliceFlag{
+			Name:  "filter, f",
+			Usage: "restart all containers matching the given filters",
+		},
 		LatestFlag,
 	}
 	restartDescription = `Restarts one or more running containers. The container ID or name can be used. A timeout before forcibly stopping can be set, but defaults to 10 seconds`
@@ -35,9 +40,16 @@ var (
 
 func restartCmd(c *cli.Context) error {
 	args := c.Args()
-	if len(args) < 1 && !c.Bool("latest") {
+	filters := c.StringSlice("filter")
+	if len(args) < 1 && !c.Bool("latest") && len(filters) == 0 {
 		return errors.Wrapf(libpod.ErrInvalidArg, "you must provide at least one container name or ID")
 	}
+	if len(args) > 0 && len(filters) > 0 {
+		return errors.Wrapf(libpod.ErrInvalidArg, "no arguments are needed with --filter")
+	}
+	if c.Bool("latest") && len(filters) > 0 {
+		return errors.Wrapf(libpod.ErrInvalidArg, "--latest cannot be used together with --filter")
+	}
 
 	if err := validateFlags(c, restartFlags); err != nil {
 		return err
@@ -69,6 +81,20 @@ func restartCmd(c *cli.Context) error {
 		}
 	}
 
+	if len(filters) > 0 {
+		filterFuncs, err := shared.ParseContainerFilters(filters, runtime)
+		if err != nil {
+			return err
+		}
+		containers, err := runtime.GetContainers(filterFuncs...)
+		if err != nil {
+			return errors.Wrapf(err, "unable to get containers matching filters")
+		}
+		for _, ctr := range containers {
+			args = append(args, ctr.ID())
+		}
+	}
+
 	for _, id := range args {
 		ctr, err := runtime.LookupContainer(id)
 		if err != nil {
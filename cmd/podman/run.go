@@ -57,7 +57,7 @@ func runCmd(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	mappings, err := util.ParseIDMapping(c.StringSlice("uidmap"), c.StringSlice("gidmap"), c.String("subuidmap"), c.String("subgidmap"))
+	mappings, err := util.ParseIDMapping(c.String("userns"), c.StringSlice("uidmap"), c.StringSlice("gidmap"), c.String("subuidmap"), c.String("subgidmap"))
 	if err != nil {
 		return err
 	}
@@ -79,13 +79,27 @@ func runCmd(c *cli.Context) error {
 		rootfs = c.Args()[0]
 	}
 
+	dockerRegistryOptions, err := platformRegistryOptions(c)
+	if err != nil {
+		return err
+	}
+
 	ctx := getContext()
 	rtc := runtime.GetConfig()
 
 	var newImage *image.Image = nil
 	var data *inspect.ImageData = nil
 	if rootfs == "" {
-		newImage, err = runtime.ImageRuntime().New(ctx, c.Args()[0], rtc.SignaturePolicyPath, "", os.Stderr, nil, image.SigningOptions{}, false, false)
+		var imageRef string
+		if c.Bool("build-context") {
+			imageRef, err = buildEphemeralImage(c, runtime, c.Args()[0])
+		} else {
+			imageRef, err = lockedImageName(c, c.Args()[0])
+		}
+		if err != nil {
+			return err
+		}
+		newImage, err = runtime.ImageRuntime().New(ctx, imageRef, rtc.SignaturePolicyPath, "", os.Stderr, dockerRegistryOptions, image.SigningOptions{}, false, false)
 		if err != nil {
 			return errors.Wrapf(err, "unable to find image")
 		}
@@ -153,6 +167,9 @@ func runCmd(c *cli.Context) error {
 			}
 			return err
 		}
+		if err := ctr.NotifyStarted(ctx); err != nil {
+			return err
+		}
 
 		fmt.Printf("%s\n", ctr.ID())
 		exitCode = 0
@@ -220,7 +237,7 @@ func runCmd(c *cli.Context) error {
 		return runtime.RemoveContainer(ctx, ctr, true)
 	}
 
-	if err := ctr.Cleanup(); err != nil {
+	if err := ctr.Cleanup(ctx); err != nil {
 		// If the container has been removed already, no need to error on cleanup
 		// Also, if it was restarted, don't error either
 		if errors.Cause(err) == libpod.ErrNoSuchCtr ||
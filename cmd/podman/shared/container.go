@@ -12,6 +12,7 @@ import (
 	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/pkg/inspect"
 	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/containers/libpod/pkg/util"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -43,6 +44,8 @@ type BatchContainerStruct struct {
 	StartedTime time.Time
 	ExitedTime  time.Time
 	Size        *ContainerSize
+	// Health is the health of a container with a healthcheck
+	Health string
 }
 
 // Namespace describes output for ps namespace
@@ -77,6 +80,7 @@ func BatchContainerOp(ctr *libpod.Container, opts PsOptions) (BatchContainerStru
 		size        *ContainerSize
 		startedTime time.Time
 		exitedTime  time.Time
+		health      string
 	)
 
 	batchErr := ctr.Batch(func(c *libpod.Container) error {
@@ -98,6 +102,12 @@ func BatchContainerOp(ctr *libpod.Container, opts PsOptions) (BatchContainerStru
 		if err != nil {
 			logrus.Errorf("error getting exited time for %q: %v", c.ID(), err)
 		}
+		if conConfig.HealthCheckConfig != nil && conState == libpod.ContainerStateRunning {
+			health, err = c.HealthCheckStatus()
+			if err != nil {
+				logrus.Errorf("error getting health of %q: %v", c.ID(), err)
+			}
+		}
 
 		if !opts.Size && !opts.Namespace {
 			return nil
@@ -139,9 +149,179 @@ func BatchContainerOp(ctr *libpod.Container, opts PsOptions) (BatchContainerStru
 		StartedTime: startedTime,
 		ExitedTime:  exitedTime,
 		Size:        size,
+		Health:      health,
 	}, nil
 }
 
+// GenerateContainerFilterFuncs returns a ContainerFilter for the given filter
+// and value. The same filters are accepted by ps and by the commands and API
+// calls that act on groups of containers.
+func GenerateContainerFilterFuncs(filter, filterValue string, runtime *libpod.Runtime) (libpod.ContainerFilter, error) {
+	switch filter {
+	case "id":
+		return func(c *libpod.Container) bool {
+			return strings.Contains(c.ID(), filterValue)
+		}, nil
+	case "label":
+		var filterArray []string = strings.SplitN(filterValue, "=", 2)
+		var filterKey string = filterArray[0]
+		if len(filterArray) > 1 {
+			filterValue = filterArray[1]
+		} else {
+			filterValue = ""
+		}
+		return func(c *libpod.Container) bool {
+			for labelKey, labelValue := range c.Labels() {
+				if labelKey == filterKey && ("" == filterValue || labelValue == filterValue) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case "name":
+		return func(c *libpod.Container) bool {
+			return strings.Contains(c.Name(), filterValue)
+		}, nil
+	case "exited":
+		exitCode, err := strconv.ParseInt(filterValue, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "exited code out of range %q", filterValue)
+		}
+		return func(c *libpod.Container) bool {
+			ec, exited, err := c.ExitCode()
+			if ec == int32(exitCode) && err == nil && exited == true {
+				return true
+			}
+			return false
+		}, nil
+	case "status":
+		if !util.StringInSlice(filterValue, []string{"created", "restarting", "running", "paused", "exited", "unknown"}) {
+			return nil, errors.Errorf("%s is not a valid status", filterValue)
+		}
+		return func(c *libpod.Container) bool {
+			status, err := c.State()
+			if err != nil {
+				return false
+			}
+			state := status.String()
+			if status == libpod.ContainerStateConfigured {
+				state = "created"
+				if _, exited, err := c.ExitCode(); err == nil && exited {
+					state = "exited"
+				}
+			}
+			return state == filterValue
+		}, nil
+	case "health":
+		if !util.StringInSlice(filterValue, []string{libpod.HealthCheckStarting, libpod.HealthCheckHealthy, libpod.HealthCheckUnhealthy, "none"}) {
+			return nil, errors.Errorf("%s is not a valid health, must be one of starting, healthy, unhealthy or none", filterValue)
+		}
+		return func(c *libpod.Container) bool {
+			if c.HealthCheckConfig() == nil {
+				return filterValue == "none"
+			}
+			health, err := c.HealthCheckStatus()
+			if err != nil {
+				return false
+			}
+			return health == filterValue
+		}, nil
+	case "ancestor":
+		// This needs to refine to match docker
+		// - ancestor=(<image-name>[:tag]|<image-id>| ⟨image@digest⟩) - containers created from an image or a descendant.
+		return func(c *libpod.Container) bool {
+			containerConfig := c.Config()
+			if strings.Contains(containerConfig.RootfsImageID, filterValue) || strings.Contains(containerConfig.RootfsImageName, filterValue) {
+				return true
+			}
+			return false
+		}, nil
+	case "before":
+		ctr, err := runtime.LookupContainer(filterValue)
+		if err != nil {
+			return nil, errors.Errorf("unable to find container by name or id of %s", filterValue)
+		}
+		containerConfig := ctr.Config()
+		createTime := containerConfig.CreatedTime
+		return func(c *libpod.Container) bool {
+			cc := c.Config()
+			return createTime.After(cc.CreatedTime)
+		}, nil
+	case "since":
+		ctr, err := runtime.LookupContainer(filterValue)
+		if err != nil {
+			return nil, errors.Errorf("unable to find container by name or id of %s", filterValue)
+		}
+		containerConfig := ctr.Config()
+		createTime := containerConfig.CreatedTime
+		return func(c *libpod.Container) bool {
+			cc := c.Config()
+			return createTime.Before(cc.CreatedTime)
+		}, nil
+	case "volume":
+		//- volume=(<volume-name>|<mount-point-destination>)
+		return func(c *libpod.Container) bool {
+			containerConfig := c.Config()
+			var dest string
+			arr := strings.Split(filterValue, ":")
+			source := arr[0]
+			if len(arr) == 2 {
+				dest = arr[1]
+			}
+			for _, mount := range containerConfig.Spec.Mounts {
+				if dest != "" && (mount.Source == source && mount.Destination == dest) {
+					return true
+				}
+				if dest == "" && mount.Source == source {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case "pod":
+		pod, err := runtime.LookupPod(filterValue)
+		if err != nil {
+			return nil, errors.Errorf("unable to find pod by name or id of %s", filterValue)
+		}
+		podID := pod.ID()
+		return func(c *libpod.Container) bool {
+			return c.PodID() == podID
+		}, nil
+	case "network":
+		return func(c *libpod.Container) bool {
+			networks := c.Config().Networks
+			if len(networks) == 0 && c.NewNetNS() {
+				// Containers without explicit networks are attached
+				// to the default CNI network
+				networks = []string{runtime.GetConfig().CNIDefaultNetwork}
+			}
+			return util.StringInSlice(filterValue, networks)
+		}, nil
+	case "unit":
+		return libpod.SystemdUnitFilter(filterValue), nil
+	}
+	return nil, errors.Errorf("%s is an invalid filter", filter)
+}
+
+// ParseContainerFilters converts filters given in the form filter=value into
+// ContainerFilter functions. Multiple filters are expected to be ANDed together
+// by the caller, as GetContainers does.
+func ParseContainerFilters(filters []string, runtime *libpod.Runtime) ([]libpod.ContainerFilter, error) {
+	filterFuncs := make([]libpod.ContainerFilter, 0, len(filters))
+	for _, f := range filters {
+		filterSplit := strings.SplitN(f, "=", 2)
+		if len(filterSplit) < 2 {
+			return nil, errors.Errorf("filter input must be in the form of filter=value: %s is invalid", f)
+		}
+		generatedFunc, err := GenerateContainerFilterFuncs(filterSplit[0], filterSplit[1], runtime)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid filter")
+		}
+		filterFuncs = append(filterFuncs, generatedFunc)
+	}
+	return filterFuncs, nil
+}
+
 // GetNamespaces returns a populated namespace struct
 func GetNamespaces(pid int) *Namespace {
 	ctrPID := strconv.Itoa(pid)
@@ -193,6 +373,7 @@ func GetCtrInspectInfo(ctr *libpod.Container, ctrInspectData *inspect.ContainerI
 	blkioWeight, blkioWeightDevice, blkioReadBps, blkioWriteBps, blkioReadIOPS, blkioeWriteIOPS := getBLKIOInfo(spec)
 	memKernel, memReservation, memSwap, memSwappiness, memDisableOOMKiller := getMemoryInfo(spec)
 	pidsLimit := getPidsInfo(spec)
+	hugepageLimits := getHugepageInfo(spec)
 	cgroup := getCgroup(spec)
 
 	var createArtifact cc.CreateConfig
@@ -224,6 +405,7 @@ func GetCtrInspectInfo(ctr *libpod.Container, ctrInspectData *inspect.ContainerI
 			CPUSetCPUs:           cpus,
 			CPUSetMems:           mems,
 			Devices:              spec.Linux.Devices,
+			HugepageLimits:       hugepageLimits,
 			KernelMemory:         memKernel,
 			MemoryReservation:    memReservation,
 			MemorySwap:           memSwap,
@@ -231,6 +413,7 @@ func GetCtrInspectInfo(ctr *libpod.Container, ctrInspectData *inspect.ContainerI
 			OomKillDisable:       memDisableOOMKiller,
 			PidsLimit:            pidsLimit,
 			Privileged:           config.Privileged,
+			PrivilegedProfile:    createArtifact.PrivilegedProfile.Relaxed(),
 			ReadonlyRootfs:       spec.Root.Readonly,
 			Runtime:              ctr.RuntimeName(),
 			NetworkMode:          string(createArtifact.NetMode),
@@ -253,20 +436,30 @@ func GetCtrInspectInfo(ctr *libpod.Container, ctrInspectData *inspect.ContainerI
 			Ulimits:              createArtifact.Resources.Ulimit,
 			SecurityOpt:          createArtifact.SecurityOpts,
 			Tmpfs:                createArtifact.Tmpfs,
+			LogConfig: &inspect.LogConfig{
+				Type:   ctr.LogDriver(),
+				Config: map[string]string{},
+			},
+			RestartPolicy: inspect.RestartPolicy{
+				Name:              ctr.RestartPolicy(),
+				MaximumRetryCount: ctr.RestartRetries(),
+			},
 		},
 		&inspect.CtrConfig{
-			Hostname:    spec.Hostname,
-			User:        spec.Process.User,
-			Env:         spec.Process.Env,
-			Image:       config.RootfsImageName,
-			WorkingDir:  spec.Process.Cwd,
-			Labels:      config.Labels,
-			Annotations: spec.Annotations,
-			Tty:         spec.Process.Terminal,
-			OpenStdin:   config.Stdin,
-			StopSignal:  config.StopSignal,
-			Cmd:         config.Spec.Process.Args,
-			Entrypoint:  strings.Join(createArtifact.Entrypoint, " "),
+			Hostname:           spec.Hostname,
+			User:               spec.Process.User,
+			Env:                spec.Process.Env,
+			Image:              config.RootfsImageName,
+			WorkingDir:         spec.Process.Cwd,
+			Labels:             config.Labels,
+			Annotations:        spec.Annotations,
+			Tty:                spec.Process.Terminal,
+			OpenStdin:          config.Stdin,
+			StopSignal:         config.StopSignal,
+			Cmd:                config.Spec.Process.Args,
+			Entrypoint:         strings.Join(createArtifact.Entrypoint, " "),
+			Healthcheck:        config.HealthCheckConfig,
+			StartupHealthcheck: config.StartupHealthCheckConfig,
 		},
 	}
 	return data, nil
@@ -316,6 +509,13 @@ func getPidsInfo(spec *specs.Spec) *int64 {
 	return &pids.Limit
 }
 
+func getHugepageInfo(spec *specs.Spec) []specs.LinuxHugepageLimit {
+	if spec.Linux.Resources == nil {
+		return nil
+	}
+	return spec.Linux.Resources.HugepageLimits
+}
+
 func getCgroup(spec *specs.Spec) string {
 	cgroup := "host"
 	for _, ns := range spec.Linux.Namespaces {
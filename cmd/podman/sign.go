@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	imageSignFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "authfile",
+			Usage: "Path of the authentication file. Default is ${XDG_RUNTIME_DIR}/containers/auth.json",
+		},
+		cli.StringFlag{
+			Name:  "cert-dir",
+			Usage: "`pathname` of a directory containing TLS certificates and keys",
+		},
+		cli.StringFlag{
+			Name:  "creds",
+			Usage: "`credentials` (USERNAME:PASSWORD) to use for authenticating to a registry",
+		},
+		cli.StringFlag{
+			Name:  "directory, d",
+			Usage: "Write the signatures to the directory instead of the signature storage of registries.d",
+		},
+		cli.StringFlag{
+			Name:  "sign-by",
+			Usage: "Identity of the GPG key signing the images",
+		},
+		cli.BoolTFlag{
+			Name:  "tls-verify",
+			Usage: "require HTTPS and verify certificates when contacting registries (default: true)",
+		},
+	}
+	imageSignDescription = `
+   Signs images already pushed to their registry with a GPG key, and writes the
+   signatures to the signature storage of their repository configured in
+   registries.d, or to a directory. Images are signed as they are pushed with
+   podman push --sign-by.
+`
+	imageSignCommand = cli.Command{
+		Name:                   "sign",
+		Usage:                  "Sign images of registries",
+		Description:            imageSignDescription,
+		Flags:                  imageSignFlags,
+		Action:                 imageSignCmd,
+		ArgsUsage:              "IMAGE [IMAGE...]",
+		UseShortOptionHandling: true,
+	}
+)
+
+func imageSignCmd(c *cli.Context) error {
+	if err := validateFlags(c, imageSignFlags); err != nil {
+		return err
+	}
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("at least one image must be given")
+	}
+	if c.String("sign-by") == "" {
+		return errors.Errorf("the signing key must be given with --sign-by")
+	}
+
+	dockerRegistryOptions := image.DockerRegistryOptions{
+		DockerCertPath:              c.String("cert-dir"),
+		DockerInsecureSkipTLSVerify: !c.BoolT("tls-verify"),
+	}
+	if c.IsSet("creds") {
+		creds, err := util.ParseRegistryCreds(c.String("creds"))
+		if err != nil {
+			return err
+		}
+		dockerRegistryOptions.DockerRegistryCreds = creds
+	}
+	sc := dockerRegistryOptions.GetSystemContext(image.GetSystemContext("", c.String("authfile"), false), nil)
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	for _, name := range args {
+		path, err := runtime.ImageRuntime().SignImage(getContext(), name, c.String("sign-by"), c.String("directory"), sc)
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
@@ -5,11 +5,16 @@ import (
 	"syscall"
 
 	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/shutdown"
 	"github.com/docker/docker/pkg/signal"
 	"github.com/sirupsen/logrus"
 )
 
 func ProxySignals(ctr *libpod.Container) {
+	// Stop handling termination signals ourselves, they are forwarded
+	// to the container instead
+	shutdown.Stop()
+
 	sigBuffer := make(chan os.Signal, 128)
 	signal.CatchAll(sigBuffer)
 
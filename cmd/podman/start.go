@@ -120,7 +120,7 @@ func startCmd(c *cli.Context) error {
 				exitCode = int(ecode)
 			}
 
-			return ctr.Cleanup()
+			return ctr.Cleanup(getContext())
 		}
 		if ctrRunning {
 			fmt.Println(ctr.ID())
@@ -134,6 +134,13 @@ func startCmd(c *cli.Context) error {
 			lastError = errors.Wrapf(err, "unable to start container %q", container)
 			continue
 		}
+		if err := ctr.NotifyStarted(getContext()); err != nil {
+			if lastError != nil {
+				fmt.Fprintln(os.Stderr, lastError)
+			}
+			lastError = err
+			continue
+		}
 		fmt.Println(container)
 	}
 
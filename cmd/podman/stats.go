@@ -15,17 +15,30 @@ import (
 	"github.com/urfave/cli"
 )
 
-type statsOutputParams struct {
+// statsHistoryOutputParams is a sample of the stats history of a container
+type statsHistoryOutputParams struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
+	Time     string `json:"time"`
 	CPUPerc  string `json:"cpu_percent"`
 	MemUsage string `json:"mem_usage"`
 	MemPerc  string `json:"mem_percent"`
-	NetIO    string `json:"netio"`
-	BlockIO  string `json:"blocki"`
 	PIDS     string `json:"pids"`
 }
 
+type statsOutputParams struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CPUPerc   string `json:"cpu_percent"`
+	MemUsage  string `json:"mem_usage"`
+	MemPerc   string `json:"mem_percent"`
+	NetIO     string `json:"netio"`
+	BlockIO   string `json:"blocki"`
+	PIDS      string `json:"pids"`
+	PIDSLimit string `json:"pids_limit"`
+	Hugetlb   string `json:"hugetlb"`
+}
+
 var (
 	statsFlags = []cli.Flag{
 		cli.BoolFlag{
@@ -40,6 +53,10 @@ var (
 			Name:  "format",
 			Usage: "pretty-print container statistics to JSON or using a Go template",
 		},
+		cli.BoolFlag{
+			Name:  "history",
+			Usage: "display the stats history recorded by the services, see stats_history_interval in libpod.conf",
+		},
 		cli.BoolFlag{
 			Name:  "no-reset",
 			Usage: "disable resetting the screen between intervals",
@@ -115,6 +132,9 @@ func statsCmd(c *cli.Context) error {
 	if err != nil {
 		return errors.Wrapf(err, "unable to get list of containers")
 	}
+	if c.Bool("history") {
+		return outputStatsHistory(ctrs, c.String("format"))
+	}
 
 	containerStats := map[string]*libpod.ContainerStats{}
 	for _, ctr := range ctrs {
@@ -170,6 +190,52 @@ func statsCmd(c *cli.Context) error {
 	return nil
 }
 
+// outputStatsHistory prints the stats history of the containers, oldest
+// samples first
+func outputStatsHistory(ctrs []*libpod.Container, format string) error {
+	samples := []interface{}{}
+	for _, ctr := range ctrs {
+		history, err := ctr.StatsHistory()
+		if err != nil {
+			return errors.Wrapf(err, "unable to read the stats history of container %s", ctr.ID())
+		}
+		for _, s := range history {
+			memPerc := 0.0
+			if s.MemLimit > 0 {
+				memPerc = float64(s.MemUsage) / float64(s.MemLimit) * 100
+			}
+			samples = append(samples, statsHistoryOutputParams{
+				ID:       shortID(ctr.ID()),
+				Name:     ctr.Name(),
+				Time:     s.Time.Format(time.RFC3339),
+				CPUPerc:  floatToPercentString(s.CPU),
+				MemUsage: combineHumanValues(s.MemUsage, s.MemLimit),
+				MemPerc:  floatToPercentString(memPerc),
+				PIDS:     pidsToString(s.PIDs),
+			})
+		}
+	}
+	var out formats.Writer
+	if strings.ToLower(format) == formats.JSONString {
+		out = formats.JSONStructArray{Output: samples}
+	} else {
+		if format == "" {
+			format = "table {{.ID}}\t{{.Name}}\t{{.Time}}\t{{.CPUPerc}}\t{{.MemUsage}}\t{{.MemPerc}}\t{{.PIDS}}"
+		}
+		headers := map[string]string{
+			"ID":       "ID",
+			"Name":     "NAME",
+			"Time":     "TIME",
+			"CPUPerc":  "CPU %",
+			"MemUsage": "MEM USAGE / LIMIT",
+			"MemPerc":  "MEM %",
+			"PIDS":     "PIDS",
+		}
+		out = formats.StdoutTemplateArray{Output: samples, Template: genStatsFormat(format), Fields: headers}
+	}
+	return out.Out()
+}
+
 func outputStats(stats []*libpod.ContainerStats, format string) error {
 	var out formats.Writer
 	var outputStats []statsOutputParams
@@ -229,6 +295,8 @@ func (i *statsOutputParams) headerMap() map[string]string {
 			value = "MemUsage/Limit"
 		case "MemPerc":
 			value = "Mem%"
+		case "PIDSLimit":
+			value = "PIDS Limit"
 		}
 		values[key] = strings.ToUpper(splitCamelCase(value))
 	}
@@ -259,28 +327,46 @@ func pidsToString(pid uint64) string {
 	return fmt.Sprintf("%d", pid)
 }
 
+func hugetlbToString(hugetlb []libpod.HugetlbStats) string {
+	var pools []string
+	for _, h := range hugetlb {
+		if h.Usage == 0 && h.Failcnt == 0 {
+			continue
+		}
+		pools = append(pools, fmt.Sprintf("%s: %s", h.PageSize, combineHumanValues(h.Usage, h.Limit)))
+	}
+	if len(pools) == 0 {
+		return "--"
+	}
+	return strings.Join(pools, ", ")
+}
+
 func getStatsOutputParams(stats *libpod.ContainerStats) statsOutputParams {
 	return statsOutputParams{
-		Name:     stats.Name,
-		ID:       shortID(stats.ContainerID),
-		CPUPerc:  floatToPercentString(stats.CPU),
-		MemUsage: combineHumanValues(stats.MemUsage, stats.MemLimit),
-		MemPerc:  floatToPercentString(stats.MemPerc),
-		NetIO:    combineHumanValues(stats.NetInput, stats.NetOutput),
-		BlockIO:  combineHumanValues(stats.BlockInput, stats.BlockOutput),
-		PIDS:     pidsToString(stats.PIDs),
+		Name:      stats.Name,
+		ID:        shortID(stats.ContainerID),
+		CPUPerc:   floatToPercentString(stats.CPU),
+		MemUsage:  combineHumanValues(stats.MemUsage, stats.MemLimit),
+		MemPerc:   floatToPercentString(stats.MemPerc),
+		NetIO:     combineHumanValues(stats.NetInput, stats.NetOutput),
+		BlockIO:   combineHumanValues(stats.BlockInput, stats.BlockOutput),
+		PIDS:      pidsToString(stats.PIDs),
+		PIDSLimit: pidsToString(stats.PIDsLimit),
+		Hugetlb:   hugetlbToString(stats.Hugetlb),
 	}
 }
 
 func getStatsOutputParamsEmpty() statsOutputParams {
 	return statsOutputParams{
-		Name:     "",
-		ID:       "",
-		CPUPerc:  "",
-		MemUsage: "",
-		MemPerc:  "",
-		NetIO:    "",
-		BlockIO:  "",
-		PIDS:     "",
+		Name:      "",
+		ID:        "",
+		CPUPerc:   "",
+		MemUsage:  "",
+		MemPerc:   "",
+		NetIO:     "",
+		BlockIO:   "",
+		PIDS:      "",
+		PIDSLimit: "",
+		Hugetlb:   "",
 	}
 }
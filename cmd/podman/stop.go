@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/cmd/podman/shared"
 	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/pkg/errors"
@@ -21,7 +22,12 @@ var (
 		cli.BoolFlag{
 			Name:  "all, a",
 			Usage: "stop all running containers",
-		}, LatestFlag,
+		},
+		cli.StringSliceFlag{
+			Name:  "filter, f",
+			Usage: "stop all running containers matching the given filters",
+		},
+		LatestFlag,
 	}
 	stopDescription = `
    podman stop
@@ -43,13 +49,14 @@ var (
 
 func stopCmd(c *cli.Context) error {
 	args := c.Args()
-	if (c.Bool("all") || c.Bool("latest")) && len(args) > 0 {
-		return errors.Errorf("no arguments are needed with --all or --latest")
+	filters := c.StringSlice("filter")
+	if (c.Bool("all") || c.Bool("latest") || len(filters) > 0) && len(args) > 0 {
+		return errors.Errorf("no arguments are needed with --all, --filter or --latest")
 	}
-	if c.Bool("all") && c.Bool("latest") {
-		return errors.Errorf("--all and --latest cannot be used together")
+	if c.Bool("latest") && (c.Bool("all") || len(filters) > 0) {
+		return errors.Errorf("--latest cannot be used together with --all or --filter")
 	}
-	if len(args) < 1 && !c.Bool("all") && !c.Bool("latest") {
+	if len(args) < 1 && !c.Bool("all") && !c.Bool("latest") && len(filters) == 0 {
 		return errors.Errorf("you must provide at least one container name or id")
 	}
 	if err := validateFlags(c, stopFlags); err != nil {
@@ -67,12 +74,17 @@ func stopCmd(c *cli.Context) error {
 	var containers []*libpod.Container
 	var lastError error
 
-	if c.Bool("all") {
+	if c.Bool("all") || len(filters) > 0 {
 		// only get running containers
 		filterFuncs = append(filterFuncs, func(c *libpod.Container) bool {
 			state, _ := c.State()
 			return state == libpod.ContainerStateRunning
 		})
+		generatedFuncs, err := shared.ParseContainerFilters(filters, runtime)
+		if err != nil {
+			return err
+		}
+		filterFuncs = append(filterFuncs, generatedFuncs...)
 		containers, err = runtime.GetContainers(filterFuncs...)
 		if err != nil {
 			return errors.Wrapf(err, "unable to get running containers")
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var (
+	systemDescription = `Manage the podman installation.`
+	systemSubCommands = []cli.Command{
+		systemAuditCommand,
+		systemDfCommand,
+		systemGCCommand,
+		systemMigrateCommand,
+		systemPruneCommand,
+		systemServiceCommand,
+		systemSubIDsCommand,
+		systemTunnelCommand,
+	}
+	systemCommand = cli.Command{
+		Name:                   "system",
+		Usage:                  "Manage podman",
+		Description:            systemDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            systemSubCommands,
+	}
+)
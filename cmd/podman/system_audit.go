@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/pkg/audit"
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	systemAuditFlags = []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "filter",
+			Usage: "filter the records: operation, source, target or uid",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "format the output using a Go template or json",
+		},
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "show the records since timestamp",
+		},
+		cli.StringFlag{
+			Name:  "until",
+			Usage: "show the records until timestamp",
+		},
+	}
+	systemAuditDescription = `
+   Shows the records of the audit log: the operations of podman commands and
+   of the API modifying containers, pods and images, with the identity of their
+   caller. The audit log is enabled by audit_log_path in libpod.conf.
+`
+	systemAuditCommand = cli.Command{
+		Name:                   "audit",
+		Usage:                  "Show the audit log",
+		Description:            systemAuditDescription,
+		Flags:                  systemAuditFlags,
+		Action:                 systemAuditCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func systemAuditCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman system audit takes no arguments")
+	}
+	if err := validateFlags(c, systemAuditFlags); err != nil {
+		return err
+	}
+	query := audit.Query{Filters: c.StringSlice("filter")}
+	var err error
+	if c.IsSet("since") {
+		if query.Since, err = parseInputTime(c.String("since")); err != nil {
+			return errors.Wrapf(err, "could not parse time: %q", c.String("since"))
+		}
+	}
+	if c.IsSet("until") {
+		if query.Until, err = parseInputTime(c.String("until")); err != nil {
+			return errors.Wrapf(err, "could not parse time: %q", c.String("until"))
+		}
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	auditLog := runtime.AuditLog()
+	if auditLog == nil {
+		return errors.Errorf("auditing is disabled, set audit_log_path in libpod.conf to enable it")
+	}
+	records, err := auditLog.Read(query)
+	if err != nil {
+		return err
+	}
+
+	format := c.String("format")
+	if format == formats.JSONString {
+		output := make([]interface{}, len(records))
+		for i := range records {
+			output[i] = records[i]
+		}
+		return formats.JSONStructArray{Output: output}.Out()
+	}
+	if format != "" {
+		tmpl, err := formats.Parse(format)
+		if err != nil {
+			return errors.Wrapf(err, "invalid format %q", format)
+		}
+		for _, record := range records {
+			if err := tmpl.Execute(os.Stdout, record); err != nil {
+				return err
+			}
+			fmt.Println()
+		}
+		return nil
+	}
+	for _, record := range records {
+		fmt.Println(auditRecordString(record))
+	}
+	return nil
+}
+
+// auditRecordString returns a record of the audit log as a line of text
+func auditRecordString(record audit.Record) string {
+	caller := fmt.Sprintf("uid=%d", record.UID)
+	if record.PID != 0 {
+		caller += fmt.Sprintf(" pid=%d", record.PID)
+	}
+	if record.CN != "" {
+		caller += " cn=" + record.CN
+	}
+	line := fmt.Sprintf("%s %s %s %q", record.Time.Format("2006-01-02 15:04:05.000000000 -0700 MST"), record.Source, caller, record.Operation)
+	if len(record.Target) > 0 {
+		line += " " + strings.Join(record.Target, " ")
+	}
+	if record.Status != 0 {
+		line += fmt.Sprintf(" status=%d", record.Status)
+	}
+	return line + " args=" + record.ArgsHash
+}
+
+// cliAuditRecord returns the audit record of the podman command run with the
+// arguments. The operation is the command and its subcommand, the targets its
+// arguments, without the options and their values.
+func cliAuditRecord(commands []cli.Command, args []string) *audit.Record {
+	record := &audit.Record{
+		Time:     time.Now(),
+		Source:   audit.SourceCLI,
+		UID:      rootless.GetRootlessUID(),
+		PID:      os.Getpid(),
+		ArgsHash: audit.HashArgs(args...),
+	}
+	cmd, operation, i := resolveCommand(commands, args)
+	record.Operation = strings.Join(operation, " ")
+	if cmd != nil {
+		record.Target = auditTargets(cmd, args[i:])
+	}
+	return record
+}
+
+// resolveCommand returns the command given by the leading arguments among
+// commands and their subcommands, the names of the command and of its
+// groups, aliases resolved, and the number of arguments naming them. The
+// command is nil if the first argument names none.
+func resolveCommand(commands []cli.Command, args []string) (*cli.Command, []string, int) {
+	var cmd *cli.Command
+	var names []string
+	i := 0
+	for ; i < len(args); i++ {
+		var found *cli.Command
+		for j := range commands {
+			if commands[j].HasName(args[i]) {
+				found = &commands[j]
+				break
+			}
+		}
+		if found == nil {
+			break
+		}
+		cmd = found
+		names = append(names, found.Name)
+		commands = found.Subcommands
+	}
+	return cmd, names, i
+}
+
+// auditTargets returns the arguments of a command, skipping its options and
+// their values. The arguments of commands running a command in a container,
+// which skip the reordering of their arguments, stop at the first one.
+func auditTargets(cmd *cli.Command, args []string) []string {
+	valueFlags := make(map[string]bool)
+	for _, flag := range cmd.Flags {
+		switch flag.(type) {
+		case cli.BoolFlag, cli.BoolTFlag:
+			continue
+		}
+		for _, name := range strings.Split(flag.GetName(), ",") {
+			valueFlags[strings.TrimSpace(name)] = true
+		}
+	}
+	var targets []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			targets = append(targets, args[i+1:]...)
+			break
+		}
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			name := strings.TrimLeft(arg, "-")
+			if !strings.Contains(name, "=") && valueFlags[name] {
+				i++
+			}
+			continue
+		}
+		targets = append(targets, arg)
+		if cmd.SkipArgReorder {
+			break
+		}
+	}
+	return targets
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/containers/libpod/pkg/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+)
+
+func TestCLIAuditRecord(t *testing.T) {
+	commands := []cli.Command{containerCommand, rmCommand, runCommand}
+	for _, tc := range []struct {
+		args      []string
+		operation string
+		target    []string
+	}{
+		{[]string{"rm", "-f", "foo", "bar"}, "rm", []string{"foo", "bar"}},
+		{[]string{"container", "rm", "--force", "foo"}, "container rm", []string{"foo"}},
+		{[]string{"run", "--name", "web", "-d", "alpine", "sleep", "10"}, "run", []string{"alpine"}},
+		{[]string{"run", "--name=web", "alpine"}, "run", []string{"alpine"}},
+		{[]string{"container", "unknown", "foo"}, "container", []string{"unknown", "foo"}},
+	} {
+		record := cliAuditRecord(commands, tc.args)
+		assert.Equal(t, audit.SourceCLI, record.Source)
+		assert.Equal(t, tc.operation, record.Operation, "%v", tc.args)
+		assert.Equal(t, tc.target, record.Target, "%v", tc.args)
+		assert.Equal(t, audit.HashArgs(tc.args...), record.ArgsHash)
+	}
+}
+
+func TestAllowedReadOnly(t *testing.T) {
+	commands := []cli.Command{containerCommand, imageCommand, systemCommand, generateCommand, podCommand, psCommand, rmCommand}
+	for _, tc := range []struct {
+		args    []string
+		allowed bool
+	}{
+		{[]string{"ps", "-a"}, true},
+		{[]string{"container", "ls"}, true},
+		{[]string{"container", "list"}, true},
+		{[]string{"container", "ps", "-a"}, true},
+		{[]string{"image", "list"}, true},
+		{[]string{"system", "df"}, true},
+		{[]string{"generate", "kube", "web"}, true},
+		{[]string{"pod", "list"}, true},
+		{[]string{"container", "help"}, true},
+		{[]string{"image", "trust", "show"}, true},
+		{[]string{"rm", "web"}, false},
+		{[]string{"container", "rm", "web"}, false},
+		{[]string{"system", "prune"}, false},
+		{[]string{"unknown"}, false},
+	} {
+		assert.Equal(t, tc.allowed, allowedReadOnly(commands, tc.args), "%v", tc.args)
+	}
+}
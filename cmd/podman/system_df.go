@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/image"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// systemDfSummaryParams is a row of the summary of the disk usage
+type systemDfSummaryParams struct {
+	Type        string
+	Total       int
+	Active      int
+	Size        string
+	Reclaimable string
+}
+
+var (
+	systemDfFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "Change the output of the summary to JSON or a Go template",
+		},
+		cli.BoolFlag{
+			Name:  "verbose, v",
+			Usage: "Show the disk space used by each image, container and volume",
+		},
+	}
+	systemDfDescription = `Shows the disk space used by the images, containers, built-in volumes of
+   containers and build cache, and the space removing those not in use would
+   reclaim.  The layers shared between images are counted once.`
+	systemDfCommand = cli.Command{
+		Name:                   "df",
+		Usage:                  "Show podman disk usage",
+		Description:            systemDfDescription,
+		Flags:                  systemDfFlags,
+		Action:                 systemDfCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func systemDfCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman system df takes no arguments")
+	}
+	if err := validateFlags(c, systemDfFlags); err != nil {
+		return err
+	}
+	format := c.String("format")
+	verbose := c.Bool("verbose")
+	if verbose && format != "" && format != formats.JSONString {
+		return errors.Errorf("--verbose only supports the json format")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	usage, err := runtime.DiskUsage(getContext())
+	if err != nil {
+		return err
+	}
+	if verbose {
+		if format == formats.JSONString {
+			return formats.JSONStruct{Output: usage}.Out()
+		}
+		return outputSystemDfVerbose(usage)
+	}
+
+	summaries := []interface{}{}
+	for _, s := range []struct {
+		name    string
+		summary libpod.DiskUsageSummary
+	}{
+		{"Images", usage.ImagesSummary},
+		{"Containers", usage.ContainersSummary},
+		{"Local Volumes", usage.VolumesSummary},
+		{"Build Cache", usage.BuildCacheSummary},
+	} {
+		if format == formats.JSONString {
+			// The sizes are given in bytes
+			summaries = append(summaries, struct {
+				Type string
+				libpod.DiskUsageSummary
+			}{s.name, s.summary})
+			continue
+		}
+		summaries = append(summaries, systemDfSummaryParams{
+			Type:        s.name,
+			Total:       s.summary.Total,
+			Active:      s.summary.Active,
+			Size:        units.HumanSizeWithPrecision(float64(s.summary.Size), 3),
+			Reclaimable: reclaimableString(s.summary),
+		})
+	}
+	if format == formats.JSONString {
+		return formats.JSONStructArray{Output: summaries}.Out()
+	}
+	if format == "" {
+		format = "table {{.Type}}\t{{.Total}}\t{{.Active}}\t{{.Size}}\t{{.Reclaimable}}"
+	}
+	headers := map[string]string{
+		"Type":        "TYPE",
+		"Total":       "TOTAL",
+		"Active":      "ACTIVE",
+		"Size":        "SIZE",
+		"Reclaimable": "RECLAIMABLE",
+	}
+	return formats.StdoutTemplateArray{Output: summaries, Template: strings.Replace(format, `\t`, "\t", -1), Fields: headers}.Out()
+}
+
+// reclaimableString returns the reclaimable space of a summary, with its
+// share of the space used
+func reclaimableString(summary libpod.DiskUsageSummary) string {
+	percent := 0
+	if summary.Size > 0 {
+		percent = int(summary.Reclaimable * 100 / summary.Size)
+	}
+	return fmt.Sprintf("%s (%d%%)", units.HumanSizeWithPrecision(float64(summary.Reclaimable), 3), percent)
+}
+
+// outputSystemDfVerbose prints the disk usage of each image, container and
+// volume
+func outputSystemDfVerbose(usage *libpod.DiskUsage) error {
+	size := func(size uint64) string {
+		return units.HumanSizeWithPrecision(float64(size), 3)
+	}
+	created := func(t time.Time) string {
+		return units.HumanDuration(time.Since(t)) + " ago"
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	section := func(title string, headers []string, rows [][]string) {
+		fmt.Fprintf(w, "%s\n\n%s\n", title, strings.Join(headers, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		fmt.Fprintln(w)
+	}
+
+	var rows [][]string
+	for _, img := range usage.Images {
+		for repo, tags := range image.ReposToMap(img.Names) {
+			for _, tag := range tags {
+				rows = append(rows, []string{repo, tag, shortID(img.ID), created(img.Created), size(img.Size), size(img.SharedSize), size(img.UniqueSize), strconv.Itoa(img.Containers)})
+			}
+		}
+	}
+	section("Images space usage:", []string{"REPOSITORY", "TAG", "IMAGE ID", "CREATED", "SIZE", "SHARED SIZE", "UNIQUE SIZE", "CONTAINERS"}, rows)
+
+	rows = nil
+	for _, ctr := range usage.Containers {
+		rows = append(rows, []string{shortID(ctr.ID), ctr.Image, strings.Join(ctr.Command, " "), strconv.Itoa(ctr.Volumes), size(ctr.Size), created(ctr.Created), ctr.State.String(), ctr.Name})
+	}
+	section("Containers space usage:", []string{"CONTAINER ID", "IMAGE", "COMMAND", "LOCAL VOLUMES", "SIZE", "CREATED", "STATUS", "NAMES"}, rows)
+
+	rows = nil
+	for _, vol := range usage.Volumes {
+		rows = append(rows, []string{shortID(vol.Container), vol.Destination, size(vol.Size)})
+	}
+	section("Local Volumes space usage:", []string{"CONTAINER ID", "DESTINATION", "SIZE"}, rows)
+
+	fmt.Fprintf(w, "Build cache usage: %s\n", size(usage.BuildCacheSummary.Size))
+	return w.Flush()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var (
+	systemGCFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Show what would be removed without removing it",
+		},
+		cli.BoolFlag{
+			Name:  "quiet, q",
+			Usage: "Only print the reclaimed space",
+		},
+	}
+	systemGCDescription = `
+   Removes the layers of the storage no image or container uses, the blobs of
+   interrupted pulls, the directories the storage driver left behind for layers
+   that no longer exist, and the conmon sockets and exit files of removed
+   containers, then prints the space reclaimed.  Layers and files more recent
+   than an hour are kept, as they may belong to a pull or a build in progress.
+`
+	systemGCCommand = cli.Command{
+		Name:                   "gc",
+		Usage:                  "Remove unreferenced layers and leftover files",
+		Description:            systemGCDescription,
+		Flags:                  systemGCFlags,
+		Action:                 systemGCCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func systemGCCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman system gc takes no arguments")
+	}
+	if err := validateFlags(c, systemGCFlags); err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "error creating libpod runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	dryRun := c.Bool("dry-run")
+	report, err := runtime.GarbageCollect(dryRun)
+	if err != nil {
+		return err
+	}
+	verb, reclaimed := "Removed", "Reclaimed"
+	if dryRun {
+		verb, reclaimed = "Would remove", "Would reclaim"
+	}
+	if !c.Bool("quiet") {
+		for _, layer := range report.Layers {
+			fmt.Printf("%s layer %s\n", verb, layer)
+		}
+		for _, path := range report.Paths {
+			fmt.Printf("%s %s\n", verb, path)
+		}
+	}
+	fmt.Printf("%s: %s\n", reclaimed, units.HumanSize(float64(report.Reclaimed)))
+
+	for _, err := range report.Errors {
+		logrus.Error(err)
+	}
+	if len(report.Errors) > 0 {
+		return errors.Errorf("%d layers or files could not be removed", len(report.Errors))
+	}
+	return nil
+}
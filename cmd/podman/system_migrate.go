@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	systemMigrateFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "new-storage-driver",
+			Usage: "Storage driver to move all images and containers to",
+		},
+		cli.StringSliceFlag{
+			Name:  "new-storage-opt",
+			Usage: "Option of the new storage driver (default [])",
+		},
+	}
+	systemMigrateDescription = `
+   Moves all images and containers to a new storage driver.  The layers of the
+   images are copied to it, and the containers are created again on them with
+   the content of their writable layer, keeping their IDs and names.  All
+   containers must be stopped.  The storage driver of storage.conf must then be
+   changed to the new one.
+`
+	systemMigrateCommand = cli.Command{
+		Name:                   "migrate",
+		Usage:                  "Move images and containers to a new storage driver",
+		Description:            systemMigrateDescription,
+		Flags:                  systemMigrateFlags,
+		Action:                 systemMigrateCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func systemMigrateCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman system migrate takes no arguments")
+	}
+	if err := validateFlags(c, systemMigrateFlags); err != nil {
+		return err
+	}
+	driver := c.String("new-storage-driver")
+	if driver == "" {
+		return errors.Errorf("the storage driver to move to must be given with --new-storage-driver")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "error creating libpod runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	if err := runtime.MigrateStorage(driver, c.StringSlice("new-storage-opt")); err != nil {
+		return err
+	}
+	fmt.Printf("Images and containers were moved to storage driver %s.\n", driver)
+	fmt.Printf("Set it as the driver of storage.conf, or pass --storage-driver %s, before running podman again.\n", driver)
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/util"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	systemPruneFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all, a",
+			Usage: "Remove all the images no container uses, not only the dangling ones",
+		},
+		cli.BoolFlag{
+			Name:  "build-cache",
+			Usage: "Remove the build cache",
+		},
+		cli.BoolFlag{
+			Name:  "containers",
+			Usage: "Remove the containers that are not running",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Report what would be removed and the space it would reclaim, without removing it",
+		},
+		cli.StringSliceFlag{
+			Name:  "filter",
+			Usage: "Only remove what matches the filters: until=TIMESTAMP, label=KEY or label=KEY=VALUE",
+		},
+		cli.BoolFlag{
+			Name:  "images",
+			Usage: "Remove the unused images",
+		},
+	}
+	systemPruneDescription = `Removes the containers that are not running, the dangling images no
+   container uses, or all the unused images with --all, and the build cache, in
+   one pass.  --containers, --images and --build-cache select what is removed,
+   all of them by default.  Images only used by the containers removed are
+   removed as well.`
+	systemPruneCommand = cli.Command{
+		Name:                   "prune",
+		Usage:                  "Remove the unused containers, images and build cache",
+		Description:            systemPruneDescription,
+		Flags:                  systemPruneFlags,
+		Action:                 systemPruneCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+// parsePruneFilters sets the until and label filters of the prune options
+func parsePruneFilters(options *libpod.PruneOptions, filters []string) error {
+	for _, filter := range filters {
+		split := strings.SplitN(filter, "=", 2)
+		if len(split) != 2 || split[1] == "" {
+			return errors.Errorf("invalid filter %q, it must be key=value", filter)
+		}
+		switch split[0] {
+		case "until":
+			until, err := util.ParseInputTime(split[1])
+			if err != nil {
+				return err
+			}
+			if options.Until.IsZero() || until.Before(options.Until) {
+				options.Until = until
+			}
+		case "label":
+			options.Labels = append(options.Labels, split[1])
+		default:
+			return errors.Errorf("invalid filter %s", split[0])
+		}
+	}
+	return nil
+}
+
+func systemPruneCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman system prune takes no arguments")
+	}
+	if err := validateFlags(c, systemPruneFlags); err != nil {
+		return err
+	}
+	options := libpod.PruneOptions{
+		Containers: c.Bool("containers"),
+		Images:     c.Bool("images"),
+		AllImages:  c.Bool("all"),
+		BuildCache: c.Bool("build-cache"),
+		DryRun:     c.Bool("dry-run"),
+	}
+	if !options.Containers && !options.Images && !options.BuildCache {
+		options.Containers, options.Images, options.BuildCache = true, true, true
+	}
+	if options.AllImages && !options.Images {
+		return errors.Errorf("--all only applies to images, add --images")
+	}
+	if err := parsePruneFilters(&options, c.StringSlice("filter")); err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	report, err := runtime.Prune(getContext(), options)
+	if err != nil {
+		return err
+	}
+	verb := "Deleted"
+	if options.DryRun {
+		verb = "Would delete"
+	}
+	for _, section := range []struct {
+		name      string
+		selected  bool
+		ids       []string
+		reclaimed uint64
+	}{
+		{"containers", options.Containers, report.Containers, report.ContainersReclaimed},
+		{"images", options.Images, report.Images, report.ImagesReclaimed},
+		{"build cache images", options.BuildCache, report.BuildCache, report.BuildCacheReclaimed},
+	} {
+		if !section.selected {
+			continue
+		}
+		fmt.Printf("%s %s (%s):\n", verb, section.name, units.HumanSize(float64(section.reclaimed)))
+		for _, id := range section.ids {
+			fmt.Println(id)
+		}
+	}
+	if options.DryRun {
+		fmt.Printf("Total reclaimable space: %s\n", units.HumanSize(float64(report.Reclaimed())))
+	} else {
+		fmt.Printf("Total reclaimed space: %s\n", units.HumanSize(float64(report.Reclaimed())))
+	}
+
+	var lastError error
+	for _, err := range report.Errors {
+		if lastError != nil {
+			fmt.Fprintln(os.Stderr, lastError)
+		}
+		lastError = err
+	}
+	return lastError
+}
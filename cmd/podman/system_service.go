@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/shutdown"
+	"github.com/containers/libpod/pkg/dockerapi"
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var (
+	systemServiceFlags = []cli.Flag{
+		cli.UintFlag{
+			Name:  "attach-idle-timeout",
+			Usage: "Seconds after which attach sessions without input or output are detached, 0 to never detach them",
+		},
+		cli.BoolFlag{
+			Name:  "client-trust",
+			Usage: "Enforce the signature policy and registries configuration clients send for their pulls",
+		},
+		cli.UintFlag{
+			Name:  "drain-timeout",
+			Usage: "Seconds to wait for the requests in progress once terminated or drained, 0 to wait for all of them",
+		},
+		cli.StringFlag{
+			Name:  "socket",
+			Usage: "Path of the socket to listen on (default \"" + dockerapi.DefaultSocketPath + "\", or podman/podman.sock in ${XDG_RUNTIME_DIR} when rootless)",
+		},
+	}
+	systemServiceDescription = `
+   Serves the Docker Engine API on a unix socket, so that Docker clients and
+   their libraries can manage the containers, images and networks of podman.
+   Set DOCKER_HOST to unix:// followed by the path of the socket to use it.
+   Events are posted to the event_webhooks of libpod.conf while serving, and the
+   stats of containers recorded at its stats_history_interval.
+   On SIGHUP, or a POST to /libpod/reload, the service reloads libpod.conf,
+   registries.conf and policy.json without restarting. On SIGTERM, or a POST
+   to /libpod/drain, it stops accepting requests and exits once the requests in
+   progress are done. Attach sessions idle for --attach-idle-timeout are
+   detached.
+`
+	systemServiceCommand = cli.Command{
+		Name:                   "service",
+		Usage:                  "Serve the Docker Engine API",
+		Description:            systemServiceDescription,
+		Flags:                  systemServiceFlags,
+		Action:                 systemServiceCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+	}
+)
+
+func systemServiceCmd(c *cli.Context) error {
+	if len(c.Args()) > 0 {
+		return errors.Errorf("podman system service takes no arguments")
+	}
+	if err := validateFlags(c, systemServiceFlags); err != nil {
+		return err
+	}
+	socketPath := c.String("socket")
+	if socketPath == "" {
+		socketPath = dockerapi.DefaultSocketPath
+		if rootless.IsRootless() {
+			runtimeDir, err := libpod.GetRootlessRuntimeDir()
+			if err != nil {
+				return err
+			}
+			socketPath = filepath.Join(runtimeDir, "podman", "podman.sock")
+		}
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "error creating libpod runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	// Serve until terminated
+	ctx, cancel := context.WithCancel(getContext())
+	defer cancel()
+	shutdown.Start()
+	done := make(chan struct{})
+	if err := shutdown.Register("system-service", func(os.Signal) error {
+		cancel()
+		<-done
+		return nil
+	}); err != nil {
+		return err
+	}
+	defer close(done)
+
+	// Post events to the webhooks of libpod.conf while serving
+	go func() {
+		if err := runtime.DispatchEvents(ctx); err != nil {
+			logrus.Errorf("Unable to post events to webhooks: %v", err)
+		}
+	}()
+	go func() {
+		if err := runtime.SampleStats(ctx); err != nil {
+			logrus.Errorf("Unable to record the stats history of containers: %v", err)
+		}
+	}()
+	go func() {
+		if err := runtime.WatchPidsLimits(ctx); err != nil {
+			logrus.Errorf("Unable to watch the PIDs limits of containers: %v", err)
+		}
+	}()
+	go func() {
+		if err := runtime.RotateLogs(ctx); err != nil {
+			logrus.Errorf("Unable to rotate the logs of containers: %v", err)
+		}
+	}()
+
+	server := dockerapi.NewServer(runtime)
+	server.SetClientTrust(c.Bool("client-trust"))
+	server.SetAttachIdleTimeout(time.Duration(c.Uint("attach-idle-timeout")) * time.Second)
+	server.SetDrainTimeout(time.Duration(c.Uint("drain-timeout")) * time.Second)
+	go reloadOnSIGHUP(ctx, server)
+	return server.Serve(ctx, socketPath)
+}
+
+// reloadOnSIGHUP reloads the configuration of the server on SIGHUP until ctx
+// is done
+func reloadOnSIGHUP(ctx context.Context, server *dockerapi.Server) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigChan:
+			if _, err := server.Reload(); err != nil {
+				logrus.Errorf("Received SIGHUP: %v", err)
+			}
+		}
+	}
+}
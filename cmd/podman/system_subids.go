@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	systemSubIDsFlags = []cli.Flag{
+		cli.IntFlag{
+			Name:  "size",
+			Usage: "Number of subordinate UIDs and GIDs to allocate to each user",
+			Value: rootless.DefaultSubIDSize,
+		},
+	}
+	systemSubIDsDescription = `
+   Checks that users have the subordinate UIDs and GIDs rootless podman needs,
+   in /etc/subuid and /etc/subgid.  When run as root, the missing ones are
+   allocated after the ranges already in the files.  Without a user, the
+   current user is checked.
+`
+	systemSubIDsCommand = cli.Command{
+		Name:                   "subids",
+		Usage:                  "Check and allocate the subordinate UIDs and GIDs of users",
+		Description:            systemSubIDsDescription,
+		Flags:                  systemSubIDsFlags,
+		Action:                 systemSubIDsCmd,
+		ArgsUsage:              "[USER...]",
+		UseShortOptionHandling: true,
+	}
+)
+
+func systemSubIDsCmd(c *cli.Context) error {
+	if err := validateFlags(c, systemSubIDsFlags); err != nil {
+		return err
+	}
+	size := c.Int("size")
+	if size <= 0 {
+		return errors.Errorf("invalid size %d, it must be positive", size)
+	}
+	privileged := os.Geteuid() == 0 && !rootless.IsRootless()
+
+	users := c.Args()
+	if len(users) == 0 {
+		if privileged {
+			return errors.Errorf("a user must be given when running as root")
+		}
+		u, err := user.Current()
+		if err != nil {
+			return errors.Wrapf(err, "cannot find the current user")
+		}
+		users = []string{u.Username}
+	}
+
+	var lastError error
+	for _, name := range users {
+		if err := checkSubIDs(name, size, privileged); err != nil {
+			if lastError != nil {
+				fmt.Fprintln(os.Stderr, lastError)
+			}
+			lastError = err
+		}
+	}
+	return lastError
+}
+
+// checkSubIDs prints the subordinate UIDs and GIDs of a user, after
+// allocating the missing ones when privileged
+func checkSubIDs(name string, size int, privileged bool) error {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return errors.Wrapf(err, "cannot find user %s", name)
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return errors.Wrapf(err, "invalid UID of user %s", name)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return errors.Wrapf(err, "invalid GID of user %s", name)
+	}
+
+	for _, f := range []struct {
+		path, kind string
+		id         int
+	}{
+		{rootless.SubUIDFile, "UIDs", uid},
+		{rootless.SubGIDFile, "GIDs", gid},
+	} {
+		ranges, err := rootless.SubIDRanges(f.path, u.Username, f.id)
+		if err != nil {
+			return err
+		}
+		if len(ranges) == 0 {
+			if !privileged {
+				return errors.Errorf("user %s has no subordinate %s in %s, run \"podman system subids %s\" as root to allocate them", u.Username, f.kind, f.path, u.Username)
+			}
+			r, err := rootless.AllocateSubIDs(f.path, u.Username, size)
+			if err != nil {
+				return errors.Wrapf(err, "cannot allocate subordinate %s to user %s", f.kind, u.Username)
+			}
+			fmt.Printf("Allocated subordinate %s %d-%d to user %s in %s\n", f.kind, r.Start, r.End(), u.Username, f.path)
+			continue
+		}
+		for _, r := range ranges {
+			fmt.Printf("User %s has subordinate %s %d-%d in %s\n", u.Username, f.kind, r.Start, r.End(), f.path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/shutdown"
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/containers/libpod/pkg/sshtunnel"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	systemTunnelFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "identity, i",
+			Usage: "Path of the SSH private key to authenticate with",
+		},
+		cli.StringFlag{
+			Name:  "socket",
+			Usage: "Path of the local socket forwarded (default \"/run/podman/remote.sock\", or podman/remote.sock in ${XDG_RUNTIME_DIR} when rootless)",
+		},
+	}
+	systemTunnelDescription = `
+   Forwards a local unix socket to the socket of podman system service, or of
+   podman varlink, on a remote host over SSH, until it is terminated. Set
+   DOCKER_HOST to unix:// followed by the path of the local socket to manage
+   the remote host with Docker clients. The podman commands themselves keep
+   managing the local host.
+
+   The destination is ssh://[USER@]HOST[:PORT][/SOCKET], the socket being
+   /run/podman/podman.sock by default.
+`
+	systemTunnelCommand = cli.Command{
+		Name:                   "tunnel",
+		Usage:                  "Forward a local socket to a remote podman service over SSH",
+		Description:            systemTunnelDescription,
+		Flags:                  systemTunnelFlags,
+		Action:                 systemTunnelCmd,
+		ArgsUsage:              "DESTINATION",
+		UseShortOptionHandling: true,
+	}
+)
+
+// TODO: the tunnel only forwards a socket to the remote service. A remote mode
+// of the podman CLI, running its commands against the service through the
+// tunnel, attach and exec included, is still to be written: it needs a
+// varlink transport over ssh, or a client of the Docker API of the service.
+func systemTunnelCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.Errorf("podman system tunnel takes a destination")
+	}
+	if err := validateFlags(c, systemTunnelFlags); err != nil {
+		return err
+	}
+	dest, err := sshtunnel.ParseDestination(args[0])
+	if err != nil {
+		return err
+	}
+	socketPath := c.String("socket")
+	if socketPath == "" {
+		socketPath = "/run/podman/remote.sock"
+		if rootless.IsRootless() {
+			runtimeDir, err := libpod.GetRootlessRuntimeDir()
+			if err != nil {
+				return err
+			}
+			socketPath = filepath.Join(runtimeDir, "podman", "remote.sock")
+		}
+	}
+	socketPath, err = filepath.Abs(socketPath)
+	if err != nil {
+		return errors.Wrapf(err, "error determining path to socket %q", c.String("socket"))
+	}
+
+	// Forward until terminated
+	ctx, cancel := context.WithCancel(getContext())
+	defer cancel()
+	shutdown.Start()
+	done := make(chan struct{})
+	if err := shutdown.Register("system-tunnel", func(os.Signal) error {
+		cancel()
+		<-done
+		return nil
+	}); err != nil {
+		return err
+	}
+	defer close(done)
+
+	fmt.Fprintf(os.Stderr, "Forwarding %s to %s on %s\n", socketPath, dest.Socket, dest.Host)
+	return sshtunnel.Open(ctx, dest, socketPath, c.String("identity"))
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/pkg/trust"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// trustShowTemplateParams is a scope of the trust policy as shown in the table
+type trustShowTemplateParams struct {
+	Registry string
+	Type     string
+	Keys     string
+	Sigstore string
+}
+
+var (
+	trustPolicyFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "policypath",
+			Usage: "Path of the signature policy, default is the signature_policy_path of libpod.conf or " + trust.DefaultPolicyPath,
+		},
+		cli.StringFlag{
+			Name:  "registriesdir",
+			Usage: "Directory of the signature storage configuration of registries",
+			Value: trust.DefaultRegistriesDir,
+		},
+	}
+
+	trustSetFlags = append([]cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "pubkeysfile, f",
+			Usage: "Path of a GPG public key images must be signed by, with the signedBy type (default [])",
+		},
+		cli.StringFlag{
+			Name:  "sigstore",
+			Usage: "URL signatures of the images of the registry are read from",
+		},
+		cli.StringFlag{
+			Name:  "type, t",
+			Usage: "Trust type of the images of the registry: accept, reject or signedBy",
+		},
+	}, trustPolicyFlags...)
+	trustSetDescription = `
+   Sets the trust of the images of a registry or repository, or of the images of
+   no other scope with "default": the requirements of the signature policy their
+   pulls must satisfy, and where their signatures are read from.
+`
+	trustSetCommand = cli.Command{
+		Name:                   "set",
+		Usage:                  "Set the trust of the images of a registry",
+		Description:            trustSetDescription,
+		Flags:                  trustSetFlags,
+		Action:                 trustSetCmd,
+		ArgsUsage:              "REGISTRY",
+		UseShortOptionHandling: true,
+	}
+
+	trustShowFlags = append([]cli.Flag{
+		cli.BoolFlag{
+			Name:  "json, j",
+			Usage: "Output as json",
+		},
+		cli.BoolFlag{
+			Name:  "raw",
+			Usage: "Output the signature policy as it is written",
+		},
+	}, trustPolicyFlags...)
+	trustShowDescription = `
+   Shows the trust of the images of the registries and repositories of the
+   signature policy, and where their signatures are read from.
+`
+	trustShowCommand = cli.Command{
+		Name:                   "show",
+		Usage:                  "Show the trust of the images of registries",
+		Description:            trustShowDescription,
+		Flags:                  trustShowFlags,
+		Action:                 trustShowCmd,
+		ArgsUsage:              "[REGISTRY]",
+		UseShortOptionHandling: true,
+	}
+
+	trustDescription = `
+   Manages the trust of images: the signature policy their pulls must satisfy,
+   policy.json, and where the signatures of registries are read from,
+   registries.d.
+`
+	trustSubCommands = []cli.Command{
+		trustSetCommand,
+		trustShowCommand,
+	}
+	trustCommand = cli.Command{
+		Name:                   "trust",
+		Usage:                  "Manage the trust of images",
+		Description:            trustDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            trustSubCommands,
+	}
+)
+
+// trustPolicyPath returns the path of the signature policy of the command
+func trustPolicyPath(c *cli.Context) (string, error) {
+	if c.IsSet("policypath") {
+		return c.String("policypath"), nil
+	}
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+	if path := runtime.GetConfig().SignaturePolicyPath; path != "" {
+		return path, nil
+	}
+	return trust.DefaultPolicyPath, nil
+}
+
+func trustSetCmd(c *cli.Context) error {
+	if err := validateFlags(c, trustSetFlags); err != nil {
+		return err
+	}
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.Errorf("podman image trust set takes exactly one registry")
+	}
+	scope := args[0]
+	if !c.IsSet("type") {
+		return errors.Errorf("the trust type must be given with --type")
+	}
+	requirements, err := trust.NewRequirements(c.String("type"), c.StringSlice("pubkeysfile"))
+	if err != nil {
+		return err
+	}
+
+	path, err := trustPolicyPath(c)
+	if err != nil {
+		return err
+	}
+	policy, err := trust.ReadPolicy(path)
+	if err != nil {
+		if !os.IsNotExist(errors.Cause(err)) {
+			return err
+		}
+		policy = &trust.Policy{}
+		// Like the policy of distributions, a new policy accepts the
+		// images of the scopes it does not set
+		if policy.Default, err = trust.NewRequirements(trust.TypeAccept, nil); err != nil {
+			return err
+		}
+	}
+	policy.SetRequirements(scope, requirements)
+	if err := trust.WritePolicy(path, policy); err != nil {
+		return err
+	}
+	if c.IsSet("sigstore") {
+		return trust.SetSigstore(c.String("registriesdir"), scope, c.String("sigstore"))
+	}
+	return nil
+}
+
+func trustShowCmd(c *cli.Context) error {
+	if err := validateFlags(c, trustShowFlags); err != nil {
+		return err
+	}
+	args := c.Args()
+	if len(args) > 1 {
+		return errors.Errorf("podman image trust show takes at most one registry")
+	}
+	if c.Bool("raw") && c.Bool("json") {
+		return errors.Errorf("--raw and --json cannot be used together")
+	}
+
+	path, err := trustPolicyPath(c)
+	if err != nil {
+		return err
+	}
+	if c.Bool("raw") {
+		if len(args) > 0 {
+			return errors.Errorf("--raw cannot be used with a registry")
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "error reading signature policy %s", path)
+		}
+		fmt.Print(string(content))
+		return nil
+	}
+	policy, err := trust.ReadPolicy(path)
+	if err != nil {
+		return err
+	}
+	sigstores, err := trust.Sigstores(c.String("registriesdir"))
+	if err != nil {
+		return err
+	}
+	entries := policy.Entries(sigstores)
+	if len(args) > 0 {
+		var selected []trust.Entry
+		for _, entry := range entries {
+			if entry.Scope == args[0] {
+				selected = append(selected, entry)
+			}
+		}
+		if len(selected) == 0 {
+			return errors.Errorf("no trust is set for %s in %s", args[0], path)
+		}
+		entries = selected
+	}
+
+	if c.Bool("json") {
+		output := make([]interface{}, len(entries))
+		for i := range entries {
+			output[i] = entries[i]
+		}
+		return formats.JSONStructArray{Output: output}.Out()
+	}
+	params := make([]trustShowTemplateParams, 0, len(entries))
+	for _, entry := range entries {
+		params = append(params, trustShowTemplateParams{
+			Registry: entry.Scope,
+			Type:     entry.Type,
+			Keys:     strings.Join(entry.Keys, ","),
+			Sigstore: entry.Sigstore,
+		})
+	}
+	genericParams := make([]interface{}, len(params))
+	for i := range params {
+		genericParams[i] = params[i]
+	}
+	out := formats.StdoutTemplateArray{Output: genericParams, Template: "table {{.Registry}}\t{{.Type}}\t{{.Keys}}\t{{.Sigstore}}\t", Fields: params[0].headerMap()}
+	return formats.Writer(out).Out()
+}
+
+// generate the header based on the template provided
+func (t *trustShowTemplateParams) headerMap() map[string]string {
+	v := reflect.Indirect(reflect.ValueOf(t))
+	values := make(map[string]string)
+	for i := 0; i < v.NumField(); i++ {
+		key := v.Type().Field(i).Name
+		values[key] = strings.ToUpper(splitCamelCase(key))
+	}
+	return values
+}
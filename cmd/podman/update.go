@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	cc "github.com/containers/libpod/pkg/spec"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	updateFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "blkio-weight",
+			Usage: "Block IO weight (relative weight) accepts a weight value between 10 and 1000.",
+		},
+		cli.StringSliceFlag{
+			Name:  "blkio-weight-device",
+			Usage: "Block IO weight (relative device weight, format: `DEVICE_NAME:WEIGHT`)",
+		},
+		cli.StringFlag{
+			Name:  "cpuset-cpus",
+			Usage: "CPUs in which to allow execution (0-3, 0,1)",
+		},
+		cli.StringFlag{
+			Name:  "cpuset-mems",
+			Usage: "Memory nodes (MEMs) in which to allow execution (0-3, 0,1). Only effective on NUMA systems.",
+		},
+		cli.StringSliceFlag{
+			Name:  "device-read-bps",
+			Usage: "Limit read rate (bytes per second) from a device (e.g. --device-read-bps=/dev/sda:1mb)",
+		},
+		cli.StringSliceFlag{
+			Name:  "device-read-iops",
+			Usage: "Limit read rate (IO per second) from a device (e.g. --device-read-iops=/dev/sda:1000)",
+		},
+		cli.StringSliceFlag{
+			Name:  "device-write-bps",
+			Usage: "Limit write rate (bytes per second) to a device (e.g. --device-write-bps=/dev/sda:1mb)",
+		},
+		cli.StringSliceFlag{
+			Name:  "device-write-iops",
+			Usage: "Limit write rate (IO per second) to a device (e.g. --device-write-iops=/dev/sda:1000)",
+		},
+	}
+	updateDescription = `
+   podman update
+
+   Updates the block IO limits and cpusets of one or more containers. They apply
+   immediately to created, running and paused containers, and are kept when
+   the containers are restarted.
+`
+	updateCommand = cli.Command{
+		Name:        "update",
+		Usage:       "Update the resource limits of one or more containers",
+		Description: updateDescription,
+		Flags:       updateFlags,
+		Action:      updateCmd,
+		ArgsUsage:   "CONTAINER-NAME [CONTAINER-NAME ...]",
+	}
+)
+
+func updateCmd(c *cli.Context) error {
+	if err := validateFlags(c, updateFlags); err != nil {
+		return err
+	}
+
+	args := c.Args()
+	if len(args) < 1 {
+		return errors.Errorf("you must provide at least one container name or id")
+	}
+
+	config := &cc.CreateConfig{
+		Resources: cc.CreateResourceConfig{
+			BlkioWeightDevice: c.StringSlice("blkio-weight-device"),
+			CPUsetCPUs:        c.String("cpuset-cpus"),
+			CPUsetMems:        c.String("cpuset-mems"),
+			DeviceReadBps:     c.StringSlice("device-read-bps"),
+			DeviceReadIOps:    c.StringSlice("device-read-iops"),
+			DeviceWriteBps:    c.StringSlice("device-write-bps"),
+			DeviceWriteIOps:   c.StringSlice("device-write-iops"),
+			MemorySwappiness:  -1,
+		},
+	}
+	if c.String("blkio-weight") != "" {
+		u, err := strconv.ParseUint(c.String("blkio-weight"), 10, 16)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for blkio-weight")
+		}
+		config.Resources.BlkioWeight = uint16(u)
+	}
+	warnings, err := verifyContainerResources(config, true)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	blockIO, err := config.CreateBlockIO()
+	if err != nil {
+		return errors.Wrapf(err, "error creating block io")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var lastError error
+	for _, arg := range args {
+		ctr, err := runtime.LookupContainer(arg)
+		if err != nil {
+			if lastError != nil {
+				fmt.Fprintln(os.Stderr, lastError)
+			}
+			lastError = errors.Wrapf(err, "error looking up container %q", arg)
+			continue
+		}
+		if err = updateContainer(c, ctr, config, blockIO); err != nil {
+			if lastError != nil {
+				fmt.Fprintln(os.Stderr, lastError)
+			}
+			lastError = errors.Wrapf(err, "failed to update container %v", ctr.ID())
+		} else {
+			fmt.Println(ctr.ID())
+		}
+	}
+	return lastError
+}
+
+// updateContainer applies the limits given on the command line to a container
+func updateContainer(c *cli.Context, ctr *libpod.Container, config *cc.CreateConfig, blockIO *spec.LinuxBlockIO) error {
+	for _, name := range []string{"blkio-weight", "blkio-weight-device", "device-read-bps", "device-read-iops", "device-write-bps", "device-write-iops"} {
+		if c.IsSet(name) {
+			if err := ctr.UpdateBlockIO(blockIO); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return ctr.UpdateCPUSet(config.Resources.CPUsetCPUs, config.Resources.CPUsetMems)
+}
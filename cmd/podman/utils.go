@@ -85,6 +85,12 @@ func startAttachCtr(ctr *libpod.Container, stdout, stderr, stdin *os.File, detac
 		ProxySignals(ctr)
 	}
 
+	go func() {
+		if err := ctr.NotifyStarted(ctx); err != nil {
+			logrus.Errorf("unable to notify systemd that container %s started: %v", ctr.ID(), err)
+		}
+	}()
+
 	if stdout == nil && stderr == nil {
 		fmt.Printf("%s\n", ctr.ID())
 	}
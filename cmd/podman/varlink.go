@@ -3,10 +3,10 @@
 package main
 
 import (
+	"context"
 	"time"
 
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
-	iopodman "github.com/containers/libpod/cmd/podman/varlink"
 	"github.com/containers/libpod/pkg/varlinkapi"
 	"github.com/containers/libpod/version"
 	"github.com/pkg/errors"
@@ -52,7 +52,33 @@ func varlinkCmd(c *cli.Context) error {
 	}
 	defer runtime.Shutdown(false)
 
-	var varlinkInterfaces = []*iopodman.VarlinkInterface{varlinkapi.New(c, runtime)}
+	// Keep the DNS configuration and the firewall rules of containers in
+	// sync with the host, record the stats history of containers and report
+	// those reaching their PIDs limit, for as long as the service runs
+	ctx, cancel := context.WithCancel(getContext())
+	defer cancel()
+	go func() {
+		if err := runtime.WatchResolvConf(ctx); err != nil {
+			logrus.Errorf("Unable to watch the host resolv.conf: %v", err)
+		}
+	}()
+	go func() {
+		if err := runtime.WatchFirewall(ctx); err != nil {
+			logrus.Errorf("Unable to watch for firewalld reloads: %v", err)
+		}
+	}()
+	go func() {
+		if err := runtime.SampleStats(ctx); err != nil {
+			logrus.Errorf("Unable to record the stats history of containers: %v", err)
+		}
+	}()
+	go func() {
+		if err := runtime.WatchPidsLimits(ctx); err != nil {
+			logrus.Errorf("Unable to watch the PIDs limits of containers: %v", err)
+		}
+	}()
+
+	var varlinkInterfaces = []*varlinkapi.AuditedInterface{varlinkapi.New(c, runtime)}
 	// Register varlink service. The metadata can be retrieved with:
 	// $ varlink info [varlink address URI]
 	service, err := varlink.NewService(
@@ -13,9 +13,17 @@ var (
 	waitDescription = `
 	podman wait
 
-	Block until one or more containers stop and then print their exit codes
+	Block until one or more containers stop, or are removed with --condition=removed,
+	and then print their exit codes
 `
-	waitFlags   = []cli.Flag{LatestFlag}
+	waitFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "condition",
+			Usage: "Condition to wait for: 'stopped' or 'removed'",
+			Value: "stopped",
+		},
+		LatestFlag,
+	}
 	waitCommand = cli.Command{
 		Name:        "wait",
 		Usage:       "Block on one or more containers",
@@ -31,6 +39,13 @@ func waitCmd(c *cli.Context) error {
 	if len(args) < 1 && !c.Bool("latest") {
 		return errors.Errorf("you must provide at least one container name or id")
 	}
+	if err := validateFlags(c, waitFlags); err != nil {
+		return err
+	}
+	condition := c.String("condition")
+	if condition != "stopped" && condition != "removed" {
+		return errors.Errorf("invalid condition %q, must be stopped or removed", condition)
+	}
 
 	runtime, err := libpodruntime.GetRuntime(c)
 	if err != nil {
@@ -56,7 +71,12 @@ func waitCmd(c *cli.Context) error {
 		if err != nil {
 			return errors.Wrapf(err, "unable to find container %s", container)
 		}
-		returnCode, err := ctr.Wait()
+		var returnCode int32
+		if condition == "removed" {
+			returnCode, err = ctr.WaitRemoved()
+		} else {
+			returnCode, err = ctr.Wait()
+		}
 		if err != nil {
 			if lastError != nil {
 				fmt.Fprintln(os.Stderr, lastError)
@@ -0,0 +1,80 @@
+// Package artifact stores OCI artifacts that are not container images, such as
+// models, WASM modules or configuration bundles. Artifacts are kept in an OCI
+// image layout, and their layers are made available to containers as files.
+package artifact
+
+import (
+	"path/filepath"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNoSuchArtifact indicates the requested artifact does not exist
+	ErrNoSuchArtifact = errors.New("no such artifact")
+	// ErrNotArtifact indicates the manifest of a pulled reference is not
+	// an OCI manifest
+	ErrNotArtifact = errors.New("not an OCI artifact")
+	// ErrArtifactInUse indicates containers mount the files of the
+	// artifact
+	ErrArtifactInUse = errors.New("artifact is in use by containers")
+)
+
+// Artifact is an OCI artifact in the store
+type Artifact struct {
+	// Name is the name the artifact was pulled as
+	Name string `json:"name"`
+	// Digest is the digest of the manifest of the artifact
+	Digest digest.Digest `json:"digest"`
+	// Manifest is the manifest of the artifact
+	Manifest imgspecv1.Manifest `json:"manifest"`
+}
+
+// ID returns the ID of the artifact, the hex of the digest of its manifest
+func (a *Artifact) ID() string {
+	return a.Digest.Hex()
+}
+
+// Type returns the type of the artifact, the media type of its config
+func (a *Artifact) Type() string {
+	return a.Manifest.Config.MediaType
+}
+
+// Size returns the size of the config and layers of the artifact
+func (a *Artifact) Size() int64 {
+	size := a.Manifest.Config.Size
+	for _, layer := range a.Manifest.Layers {
+		size += layer.Size
+	}
+	return size
+}
+
+// File is a layer of an artifact, made available to containers as a file
+type File struct {
+	// Name is the name of the file: the title of the layer, or the hex of
+	// its digest if it has none
+	Name string `json:"name"`
+	// Path is the path of the content of the file in the store
+	Path string `json:"path"`
+	// Digest is the digest of the layer
+	Digest digest.Digest `json:"digest"`
+	// MediaType is the media type of the layer
+	MediaType string `json:"mediaType"`
+	// Size is the size of the layer
+	Size int64 `json:"size"`
+}
+
+// fileName returns the name of the file of a layer, making sure it can not
+// refer to other locations
+func fileName(layer imgspecv1.Descriptor) (string, error) {
+	name, ok := layer.Annotations[imgspecv1.AnnotationTitle]
+	if !ok || name == "" {
+		return layer.Digest.Hex(), nil
+	}
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return "", errors.Errorf("invalid title %q of layer %s", name, layer.Digest)
+	}
+	return name, nil
+}
@@ -0,0 +1,430 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/image/image"
+	"github.com/containers/image/signature"
+	"github.com/containers/image/transports"
+	"github.com/containers/image/types"
+	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/ioutils"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// Store keeps artifacts in an OCI image layout, naming them with the
+// org.opencontainers.image.ref.name annotation of the index. Blobs shared by
+// several artifacts are stored once.
+type Store struct {
+	dir   string
+	lock  storage.Locker
+	inUse InUseFunc
+}
+
+// InUseFunc returns the digests of the blobs containers bind mount, which are
+// not removed from the store while they are
+type InUseFunc func() (map[digest.Digest]bool, error)
+
+// NewStore returns the artifact store in the given directory, creating it if
+// needed. inUse may be nil if no container uses the store.
+func NewStore(dir string, inUse InUseFunc) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "blobs", string(digest.Canonical)), 0755); err != nil {
+		return nil, errors.Wrapf(err, "error creating artifact store %s", dir)
+	}
+	lock, err := storage.GetLockfile(filepath.Join(dir, "artifacts.lock"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving lock of artifact store %s", dir)
+	}
+	s := &Store{dir: dir, lock: lock, inUse: inUse}
+
+	layoutPath := filepath.Join(dir, imgspecv1.ImageLayoutFile)
+	if _, err := os.Stat(layoutPath); os.IsNotExist(err) {
+		layout, err := json.Marshal(imgspecv1.ImageLayout{Version: imgspecv1.ImageLayoutVersion})
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutils.AtomicWriteFile(layoutPath, layout, 0644); err != nil {
+			return nil, errors.Wrapf(err, "error writing %s", layoutPath)
+		}
+	}
+	return s, nil
+}
+
+// blobPath returns the path of the blob with the given digest
+func (s *Store) blobPath(d digest.Digest) string {
+	return filepath.Join(s.dir, "blobs", d.Algorithm().String(), d.Hex())
+}
+
+// BlobDigest returns the digest of the blob at a path of the store, and
+// whether the path is the one of a blob
+func (s *Store) BlobDigest(path string) (digest.Digest, bool) {
+	dir, hex := filepath.Split(filepath.Clean(path))
+	if filepath.Clean(dir) != filepath.Join(s.dir, "blobs", string(digest.Canonical)) {
+		return "", false
+	}
+	d := digest.NewDigestFromHex(string(digest.Canonical), hex)
+	if d.Validate() != nil {
+		return "", false
+	}
+	return d, true
+}
+
+// blobsInUse returns the digests of the blobs containers bind mount
+func (s *Store) blobsInUse() (map[digest.Digest]bool, error) {
+	if s.inUse == nil {
+		return nil, nil
+	}
+	inUse, err := s.inUse()
+	return inUse, errors.Wrapf(err, "error listing artifact blobs in use")
+}
+
+// indexPath returns the path of the index of the store
+func (s *Store) indexPath() string {
+	return filepath.Join(s.dir, "index.json")
+}
+
+// readIndex reads the index of the store, which does not exist until the first
+// artifact is pulled
+func (s *Store) readIndex() (*imgspecv1.Index, error) {
+	index := &imgspecv1.Index{Versioned: imgspecs.Versioned{SchemaVersion: 2}}
+	data, err := ioutil.ReadFile(s.indexPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, errors.Wrapf(err, "error reading artifact index")
+	}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, errors.Wrapf(err, "error parsing artifact index")
+	}
+	return index, nil
+}
+
+func (s *Store) writeIndex(index *imgspecv1.Index) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(ioutils.AtomicWriteFile(s.indexPath(), data, 0644), "error writing artifact index")
+}
+
+// readArtifact reads the manifest an entry of the index refers to
+func (s *Store) readArtifact(desc imgspecv1.Descriptor) (*Artifact, error) {
+	data, err := ioutil.ReadFile(s.blobPath(desc.Digest))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading manifest %s", desc.Digest)
+	}
+	a := &Artifact{Name: desc.Annotations[imgspecv1.AnnotationRefName], Digest: desc.Digest}
+	if err := json.Unmarshal(data, &a.Manifest); err != nil {
+		return nil, errors.Wrapf(err, "error parsing manifest %s", desc.Digest)
+	}
+	return a, nil
+}
+
+// hasBlob returns whether the store holds the blob with the given digest
+func (s *Store) hasBlob(d digest.Digest) bool {
+	_, err := os.Stat(s.blobPath(d))
+	return err == nil
+}
+
+// writeBlob stores a blob, verifying its digest
+func (s *Store) writeBlob(d digest.Digest, r io.Reader) error {
+	if err := d.Validate(); err != nil {
+		return errors.Wrapf(err, "invalid blob digest %q", d)
+	}
+	path := s.blobPath(d)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return errors.Wrapf(err, "error creating blob %s", d)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	verifier := d.Verifier()
+	if _, err := io.Copy(tmp, io.TeeReader(r, verifier)); err != nil {
+		return errors.Wrapf(err, "error writing blob %s", d)
+	}
+	if !verifier.Verified() {
+		return errors.Errorf("blob %s does not match its digest", d)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return errors.Wrapf(os.Rename(tmp.Name(), path), "error storing blob %s", d)
+}
+
+// artifactName returns the name of an artifact pulled from a reference: the
+// normalized name of references to registries, else the full reference
+func artifactName(ref types.ImageReference) string {
+	if named := ref.DockerReference(); named != nil {
+		return reference.TagNameOnly(named).String()
+	}
+	return transports.ImageName(ref)
+}
+
+// Pull copies the artifact at the reference into the store, replacing the
+// artifact with the same name if there is one. Progress is written to writer
+// if it is not nil. If policyContext is not nil, the artifact must be allowed
+// by its signature policy.
+func (s *Store) Pull(ctx context.Context, ref types.ImageReference, sys *types.SystemContext, policyContext *signature.PolicyContext, writer io.Writer) (*Artifact, error) {
+	name := artifactName(ref)
+	src, err := ref.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading artifact %s", name)
+	}
+	defer src.Close()
+
+	if policyContext != nil {
+		if allowed, err := policyContext.IsRunningImageAllowed(ctx, image.UnparsedInstance(src, nil)); !allowed || err != nil {
+			return nil, errors.Wrapf(err, "artifact %s is not allowed by the signature policy", name)
+		}
+	}
+
+	manifestBytes, mimeType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading manifest of %s", name)
+	}
+	if mimeType != imgspecv1.MediaTypeImageManifest {
+		return nil, errors.Wrapf(ErrNotArtifact, "%s has a manifest of type %q", name, mimeType)
+	}
+	var manifest imgspecv1.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "error parsing manifest of %s", name)
+	}
+	for _, layer := range manifest.Layers {
+		if _, err := fileName(layer); err != nil {
+			return nil, err
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, desc := range append([]imgspecv1.Descriptor{manifest.Config}, manifest.Layers...) {
+		if s.hasBlob(desc.Digest) {
+			if writer != nil {
+				fmt.Fprintf(writer, "Skipping blob %s (already present)\n", desc.Digest)
+			}
+			continue
+		}
+		if writer != nil {
+			fmt.Fprintf(writer, "Copying blob %s\n", desc.Digest)
+		}
+		blob, _, err := src.GetBlob(ctx, types.BlobInfo{Digest: desc.Digest, Size: desc.Size, MediaType: desc.MediaType})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading blob %s of %s", desc.Digest, name)
+		}
+		err = s.writeBlob(desc.Digest, blob)
+		blob.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manifestDigest := digest.FromBytes(manifestBytes)
+	if writer != nil {
+		fmt.Fprintf(writer, "Writing manifest %s\n", manifestDigest)
+	}
+	if err := s.writeBlob(manifestDigest, strings.NewReader(string(manifestBytes))); err != nil {
+		return nil, err
+	}
+
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	desc := imgspecv1.Descriptor{
+		MediaType:   mimeType,
+		Digest:      manifestDigest,
+		Size:        int64(len(manifestBytes)),
+		Annotations: map[string]string{imgspecv1.AnnotationRefName: name},
+	}
+	manifests := []imgspecv1.Descriptor{desc}
+	for _, m := range index.Manifests {
+		if m.Annotations[imgspecv1.AnnotationRefName] != name {
+			manifests = append(manifests, m)
+		}
+	}
+	index.Manifests = manifests
+	if err := s.writeIndex(index); err != nil {
+		return nil, err
+	}
+	// A replaced artifact may have left unreferenced blobs behind
+	if err := s.collectGarbage(index); err != nil {
+		return nil, err
+	}
+
+	return &Artifact{Name: name, Digest: manifestDigest, Manifest: manifest}, nil
+}
+
+// List returns all artifacts in the store
+func (s *Store) List() ([]*Artifact, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	artifacts := make([]*Artifact, 0, len(index.Manifests))
+	for _, desc := range index.Manifests {
+		a, err := s.readArtifact(desc)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, nil
+}
+
+// Lookup returns the artifact with the given name, which may be given without
+// the registry or tag of registry references as for images, or the artifact
+// whose ID starts with the given name
+func (s *Store) Lookup(name string) (*Artifact, error) {
+	artifacts, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []string{name}
+	if named, err := reference.ParseNormalizedNamed(name); err == nil {
+		candidates = append(candidates, reference.TagNameOnly(named).String())
+	}
+	for _, a := range artifacts {
+		for _, candidate := range candidates {
+			if a.Name == candidate {
+				return a, nil
+			}
+		}
+	}
+
+	var match *Artifact
+	for _, a := range artifacts {
+		if strings.HasPrefix(a.ID(), strings.TrimPrefix(name, string(digest.Canonical)+":")) {
+			if match != nil && match.Digest != a.Digest {
+				return nil, errors.Errorf("artifact ID %q is ambiguous", name)
+			}
+			match = a
+		}
+	}
+	if match == nil {
+		return nil, errors.Wrapf(ErrNoSuchArtifact, "%s", name)
+	}
+	return match, nil
+}
+
+// Remove removes the artifact with the given name or ID from the store, along
+// with the blobs no other artifact refers to. Artifacts whose files containers
+// mount are not removed.
+func (s *Store) Remove(name string) (*Artifact, error) {
+	a, err := s.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	inUse, err := s.blobsInUse()
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range a.Manifest.Layers {
+		if inUse[layer.Digest] {
+			return nil, errors.Wrapf(ErrArtifactInUse, "%s", a.Name)
+		}
+	}
+
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	manifests := make([]imgspecv1.Descriptor, 0, len(index.Manifests))
+	for _, m := range index.Manifests {
+		if m.Digest != a.Digest || m.Annotations[imgspecv1.AnnotationRefName] != a.Name {
+			manifests = append(manifests, m)
+		}
+	}
+	index.Manifests = manifests
+	if err := s.writeIndex(index); err != nil {
+		return nil, err
+	}
+	return a, s.collectGarbage(index)
+}
+
+// collectGarbage removes the blobs none of the artifacts in the index refer
+// to, unless containers mount them. The store must be locked.
+func (s *Store) collectGarbage(index *imgspecv1.Index) error {
+	inUse, err := s.blobsInUse()
+	if err != nil {
+		return err
+	}
+	referenced := make(map[digest.Digest]bool)
+	for d := range inUse {
+		referenced[d] = true
+	}
+	for _, desc := range index.Manifests {
+		a, err := s.readArtifact(desc)
+		if err != nil {
+			return err
+		}
+		referenced[desc.Digest] = true
+		referenced[a.Manifest.Config.Digest] = true
+		for _, layer := range a.Manifest.Layers {
+			referenced[layer.Digest] = true
+		}
+	}
+
+	dir := filepath.Join(s.dir, "blobs", string(digest.Canonical))
+	blobs, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Wrapf(err, "error listing blobs")
+	}
+	for _, blob := range blobs {
+		d := digest.NewDigestFromHex(string(digest.Canonical), blob.Name())
+		if d.Validate() != nil || referenced[d] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, blob.Name())); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error removing blob %s", d)
+		}
+	}
+	return nil
+}
+
+// Files returns the layers of the artifact as files, in the order of the
+// manifest
+func (s *Store) Files(a *Artifact) ([]File, error) {
+	files := make([]File, 0, len(a.Manifest.Layers))
+	for _, layer := range a.Manifest.Layers {
+		name, err := fileName(layer)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{
+			Name:      name,
+			Path:      s.blobPath(layer.Digest),
+			Digest:    layer.Digest,
+			MediaType: layer.MediaType,
+			Size:      layer.Size,
+		})
+	}
+	return files, nil
+}
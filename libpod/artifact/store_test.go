@@ -0,0 +1,171 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/image/oci/layout"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLayoutBlob writes a blob to an OCI layout and returns its descriptor
+func writeLayoutBlob(t *testing.T, dir, mediaType string, data []byte, annotations map[string]string) imgspecv1.Descriptor {
+	d := digest.FromBytes(data)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "blobs", "sha256", d.Hex()), data, 0644))
+	return imgspecv1.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(data)), Annotations: annotations}
+}
+
+// writeLayout writes an OCI layout holding one artifact per tag, whose layers
+// have the given titles and contents
+func writeLayout(t *testing.T, dir string, artifacts map[string]map[string]string) {
+	index := imgspecv1.Index{Versioned: imgspecs.Versioned{SchemaVersion: 2}}
+	for tag, files := range artifacts {
+		manifest := imgspecv1.Manifest{
+			Versioned: imgspecs.Versioned{SchemaVersion: 2},
+			Config:    writeLayoutBlob(t, dir, "application/vnd.example.model.config.v1+json", []byte("{}"), nil),
+		}
+		for title, content := range files {
+			manifest.Layers = append(manifest.Layers, writeLayoutBlob(t, dir, "application/octet-stream", []byte(content), map[string]string{imgspecv1.AnnotationTitle: title}))
+		}
+		data, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		desc := writeLayoutBlob(t, dir, imgspecv1.MediaTypeImageManifest, data, map[string]string{imgspecv1.AnnotationRefName: tag})
+		index.Manifests = append(index.Manifests, desc)
+	}
+	data, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.json"), data, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, imgspecv1.ImageLayoutFile), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644))
+}
+
+func countBlobs(t *testing.T, s *Store) int {
+	blobs, err := ioutil.ReadDir(filepath.Join(s.dir, "blobs", "sha256"))
+	require.NoError(t, err)
+	return len(blobs)
+}
+
+func TestStorePullLookupRemove(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "artifact")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	src := filepath.Join(tmp, "src")
+	writeLayout(t, src, map[string]map[string]string{
+		"v1": {"weights.bin": "weights", "vocab.txt": "vocab"},
+		"v2": {"weights.bin": "weights"},
+	})
+	inUse := make(map[digest.Digest]bool)
+	s, err := NewStore(filepath.Join(tmp, "store"), func() (map[digest.Digest]bool, error) {
+		return inUse, nil
+	})
+	require.NoError(t, err)
+
+	ref, err := layout.NewReference(src, "v1")
+	require.NoError(t, err)
+	a1, err := s.Pull(context.Background(), ref, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "oci:"+src+":v1", a1.Name)
+	assert.Equal(t, "application/vnd.example.model.config.v1+json", a1.Type())
+	assert.Equal(t, int64(len("{}weightsvocab")), a1.Size())
+
+	files, err := s.Files(a1)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f.Path)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"weights.bin": "weights", "vocab.txt": "vocab"}[f.Name], string(content))
+	}
+
+	ref, err = layout.NewReference(src, "v2")
+	require.NoError(t, err)
+	a2, err := s.Pull(context.Background(), ref, nil, nil, nil)
+	require.NoError(t, err)
+	// The config and weights are shared: 3 layers and configs plus 2 manifests
+	assert.Equal(t, 5, countBlobs(t, s))
+
+	artifacts, err := s.List()
+	require.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+
+	found, err := s.Lookup(a2.ID()[:12])
+	require.NoError(t, err)
+	assert.Equal(t, a2.Name, found.Name)
+	found, err = s.Lookup(a1.Name)
+	require.NoError(t, err)
+	assert.Equal(t, a1.Digest, found.Digest)
+	_, err = s.Lookup("missing")
+	assert.Error(t, err)
+
+	// The vocabulary of v1 is mounted by a container
+	var vocab digest.Digest
+	for _, f := range files {
+		if f.Name == "vocab.txt" {
+			d, ok := s.BlobDigest(f.Path)
+			require.True(t, ok)
+			assert.Equal(t, f.Digest, d)
+			vocab = d
+		}
+	}
+	_, ok := s.BlobDigest(filepath.Join(tmp, "store", "index.json"))
+	assert.False(t, ok)
+	inUse[vocab] = true
+	_, err = s.Remove(a1.Name)
+	assert.Equal(t, ErrArtifactInUse, errors.Cause(err))
+	assert.Equal(t, 5, countBlobs(t, s))
+
+	inUse = map[digest.Digest]bool{}
+	_, err = s.Remove(a1.Name)
+	require.NoError(t, err)
+	assert.Equal(t, 3, countBlobs(t, s))
+	_, err = s.Lookup(a1.Name)
+	assert.Error(t, err)
+
+	// Blobs no artifact refers to are kept while containers mount them
+	inUse[a2.Manifest.Layers[0].Digest] = true
+	_, err = s.Remove(a2.ID())
+	assert.Equal(t, ErrArtifactInUse, errors.Cause(err))
+	require.NoError(t, s.collectGarbage(&imgspecv1.Index{}))
+	assert.Equal(t, 1, countBlobs(t, s))
+
+	inUse = map[digest.Digest]bool{}
+	require.NoError(t, s.collectGarbage(&imgspecv1.Index{}))
+	assert.Equal(t, 0, countBlobs(t, s))
+}
+
+func TestFileName(t *testing.T) {
+	d := digest.FromString("content")
+	for _, c := range []struct {
+		title string
+		name  string
+		valid bool
+	}{
+		{"", d.Hex(), true},
+		{"model.onnx", "model.onnx", true},
+		{"../etc/passwd", "", false},
+		{"dir/file", "", false},
+		{"..", "", false},
+	} {
+		layer := imgspecv1.Descriptor{Digest: d}
+		if c.title != "" {
+			layer.Annotations = map[string]string{imgspecv1.AnnotationTitle: c.title}
+		}
+		name, err := fileName(layer)
+		if !c.valid {
+			assert.Error(t, err, c.title)
+			continue
+		}
+		assert.NoError(t, err, c.title)
+		assert.Equal(t, c.name, name)
+	}
+}
@@ -0,0 +1,13 @@
+package libpod
+
+import (
+	"github.com/containers/libpod/pkg/audit"
+)
+
+// AuditLog returns the audit log of the runtime, nil if auditing is disabled
+func (r *Runtime) AuditLog() *audit.Log {
+	if r.config().AuditLogPath == "" {
+		return nil
+	}
+	return audit.NewLog(r.config().AuditLogPath)
+}
@@ -109,6 +109,9 @@ func NewBoltState(path, lockDir string, runtime *Runtime) (State, error) {
 		if _, err := tx.CreateBucketIfNotExists(runtimeConfigBkt); err != nil {
 			return errors.Wrapf(err, "error creating runtime-config bucket")
 		}
+		if _, err := tx.CreateBucketIfNotExists(reservationsBkt); err != nil {
+			return errors.Wrapf(err, "error creating name-reservations bucket")
+		}
 		return nil
 	})
 	if err != nil {
@@ -143,6 +146,7 @@ func (s *BoltState) Refresh() error {
 	}
 	defer s.closeDBCon(db)
 
+	var staleNetNS []string
 	err = db.Update(func(tx *bolt.Tx) error {
 		idBucket, err := getIDBucket(tx)
 		if err != nil {
@@ -204,6 +208,11 @@ func (s *BoltState) Refresh() error {
 			}
 
 			// First, delete the network namespace
+			// Its bind mount did not survive the reboot, but the
+			// file it was mounted on may have
+			if netNSBytes := ctrBkt.Get(netNSKey); netNSBytes != nil {
+				staleNetNS = append(staleNetNS, string(netNSBytes))
+			}
 			if err := ctrBkt.Delete(netNSKey); err != nil {
 				return errors.Wrapf(err, "error removing network namespace for container %s", string(id))
 			}
@@ -237,7 +246,16 @@ func (s *BoltState) Refresh() error {
 		})
 		return err
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	for _, path := range staleNetNS {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logrus.Debugf("Error removing stale network namespace %s: %v", path, err)
+		}
+	}
+	return nil
 }
 
 // SetNamespace sets the namespace that will be used for container and pod
@@ -600,6 +618,60 @@ func (s *BoltState) SaveContainer(ctr *Container) error {
 	return err
 }
 
+// RewriteContainerConfig replaces the configuration of a container in the
+// database, used when moving containers to a new storage driver
+func (s *BoltState) RewriteContainerConfig(ctr *Container, newCfg *ContainerConfig) error {
+	if !s.valid {
+		return ErrDBClosed
+	}
+
+	if !ctr.valid {
+		return ErrCtrRemoved
+	}
+
+	if s.namespace != "" && s.namespace != ctr.config.Namespace {
+		return errors.Wrapf(ErrNSMismatch, "container %s is in namespace %q, does not match our namespace %q", ctr.ID(), ctr.config.Namespace, s.namespace)
+	}
+
+	configJSON, err := json.Marshal(newCfg)
+	if err != nil {
+		return errors.Wrapf(err, "error marshalling container %s config to JSON", ctr.ID())
+	}
+
+	ctrID := []byte(ctr.ID())
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return err
+	}
+	defer s.closeDBCon(db)
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		ctrBucket, err := getCtrBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		ctrToUpdate := ctrBucket.Bucket(ctrID)
+		if ctrToUpdate == nil {
+			ctr.valid = false
+			return errors.Wrapf(ErrNoSuchCtr, "container %s does not exist in DB", ctr.ID())
+		}
+
+		if err := ctrToUpdate.Put(configKey, configJSON); err != nil {
+			return errors.Wrapf(err, "error updating container %s config in DB", ctr.ID())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ctr.config = newCfg
+	return nil
+}
+
 // ContainerInUse checks if other containers depend on the given container
 // It returns a slice of the IDs of the containers depending on the given
 // container. If the slice is empty, no containers depend on the given container
@@ -663,6 +735,18 @@ func (s *BoltState) ContainerInUse(ctr *Container) ([]string, error) {
 
 // AllContainers retrieves all the containers in the database
 func (s *BoltState) AllContainers() ([]*Container, error) {
+	return s.allContainers(s.namespaceBytes)
+}
+
+// AllContainersInAllNamespaces retrieves all the containers in the database,
+// ignoring the set namespace
+func (s *BoltState) AllContainersInAllNamespaces() ([]*Container, error) {
+	return s.allContainers(nil)
+}
+
+// allContainers retrieves all the containers in the database part of the
+// given namespace, or of any namespace if it is nil
+func (s *BoltState) allContainers(namespace []byte) ([]*Container, error) {
 	if !s.valid {
 		return nil, ErrDBClosed
 	}
@@ -699,7 +783,7 @@ func (s *BoltState) AllContainers() ([]*Container, error) {
 			ctr.config = new(ContainerConfig)
 			ctr.state = new(containerState)
 
-			if err := s.getContainerFromDB(id, ctr, ctrBucket); err != nil {
+			if err := s.getContainerFromDBInNamespace(id, ctr, ctrBucket, namespace); err != nil {
 				// If the error is a namespace mismatch, we can
 				// ignore it safely.
 				// We just won't include the container in the
@@ -1174,6 +1258,9 @@ func (s *BoltState) AddPod(pod *Pod) error {
 		if nameExist != nil {
 			return errors.Wrapf(ErrPodExists, "name %s is in use", pod.Name())
 		}
+		if err := claimNameReservation(tx, podName, pod.nameReservation); err != nil {
+			return err
+		}
 
 		// We are good to add the pod
 		// Make a bucket for it
@@ -1622,6 +1709,18 @@ func (s *BoltState) SavePod(pod *Pod) error {
 
 // AllPods returns all pods present in the state
 func (s *BoltState) AllPods() ([]*Pod, error) {
+	return s.allPods(s.namespaceBytes)
+}
+
+// AllPodsInAllNamespaces returns all pods present in the state, ignoring the
+// set namespace
+func (s *BoltState) AllPodsInAllNamespaces() ([]*Pod, error) {
+	return s.allPods(nil)
+}
+
+// allPods returns all pods present in the state part of the given namespace,
+// or of any namespace if it is nil
+func (s *BoltState) allPods(namespace []byte) ([]*Pod, error) {
 	if !s.valid {
 		return nil, ErrDBClosed
 	}
@@ -1657,7 +1756,7 @@ func (s *BoltState) AllPods() ([]*Pod, error) {
 			pod.config = new(PodConfig)
 			pod.state = new(podState)
 
-			if err := s.getPodFromDB(id, pod, podBucket); err != nil {
+			if err := s.getPodFromDBInNamespace(id, pod, podBucket, namespace); err != nil {
 				if errors.Cause(err) != ErrNSMismatch {
 					logrus.Errorf("Error retrieving pod %s from the database: %v", string(id), err)
 				}
@@ -1675,3 +1774,196 @@ func (s *BoltState) AllPods() ([]*Pod, error) {
 
 	return pods, nil
 }
+
+// SetGraphDriverName records the storage driver the containers in the
+// database use, checked against the one of the runtime opening it
+func (s *BoltState) SetGraphDriverName(name string) error {
+	if !s.valid {
+		return ErrDBClosed
+	}
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return err
+	}
+	defer s.closeDBCon(db)
+
+	return db.Update(func(tx *bolt.Tx) error {
+		configBkt, err := getRuntimeConfigBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		if err := configBkt.Put(graphDriverNameKey, []byte(name)); err != nil {
+			return errors.Wrapf(err, "error updating graph driver name in DB runtime config")
+		}
+
+		return nil
+	})
+}
+
+// ReserveName reserves a name for a container or pod added later with the
+// token of the reservation
+func (s *BoltState) ReserveName(reservation *NameReservation) error {
+	if !s.valid {
+		return ErrDBClosed
+	}
+
+	reservationJSON, err := json.Marshal(reservation)
+	if err != nil {
+		return errors.Wrapf(err, "error marshalling reservation of name %s to JSON", reservation.Name)
+	}
+	name := []byte(reservation.Name)
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return err
+	}
+	defer s.closeDBCon(db)
+
+	return db.Update(func(tx *bolt.Tx) error {
+		namesBkt, err := getNamesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		ctrBkt, err := getCtrBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		reservationsBkt, err := getReservationsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		if id := namesBkt.Get(name); id != nil {
+			if ctrBkt.Bucket(id) != nil {
+				return errors.Wrapf(ErrCtrExists, "name %s is in use", reservation.Name)
+			}
+			return errors.Wrapf(ErrPodExists, "name %s is in use", reservation.Name)
+		}
+		if reservationsBkt.Get(name) != nil {
+			return errors.Wrapf(ErrNameReserved, "name %s is already reserved", reservation.Name)
+		}
+
+		if err := reservationsBkt.Put(name, reservationJSON); err != nil {
+			return errors.Wrapf(err, "error adding reservation of name %s to DB", reservation.Name)
+		}
+
+		return nil
+	})
+}
+
+// ReleaseName releases the reservation of a name made with the given token,
+// or with any token if it is empty
+func (s *BoltState) ReleaseName(name, token string) error {
+	if !s.valid {
+		return ErrDBClosed
+	}
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return err
+	}
+	defer s.closeDBCon(db)
+
+	return db.Update(func(tx *bolt.Tx) error {
+		reservationsBkt, err := getReservationsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		reservationJSON := reservationsBkt.Get([]byte(name))
+		if reservationJSON == nil {
+			return errors.Wrapf(ErrNoSuchNameReservation, "name %s is not reserved", name)
+		}
+		if token != "" {
+			reservation := new(NameReservation)
+			if err := json.Unmarshal(reservationJSON, reservation); err != nil {
+				return errors.Wrapf(err, "error unmarshalling reservation of name %s", name)
+			}
+			if token != reservation.Token {
+				return errors.Wrapf(ErrNameReserved, "name %s is reserved with another token", name)
+			}
+		}
+
+		if err := reservationsBkt.Delete([]byte(name)); err != nil {
+			return errors.Wrapf(err, "error removing reservation of name %s from DB", name)
+		}
+
+		return nil
+	})
+}
+
+// NameReservations retrieves the reservations of all names
+func (s *BoltState) NameReservations() ([]*NameReservation, error) {
+	if !s.valid {
+		return nil, ErrDBClosed
+	}
+
+	reservations := []*NameReservation{}
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBCon(db)
+
+	err = db.View(func(tx *bolt.Tx) error {
+		reservationsBkt, err := getReservationsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return reservationsBkt.ForEach(func(name, reservationJSON []byte) error {
+			reservation := new(NameReservation)
+			if err := json.Unmarshal(reservationJSON, reservation); err != nil {
+				return errors.Wrapf(err, "error unmarshalling reservation of name %s", name)
+			}
+			reservations = append(reservations, reservation)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// NameInUse checks if a container or pod of any namespace has the given
+// name, or if it is reserved
+func (s *BoltState) NameInUse(name string) (bool, error) {
+	if !s.valid {
+		return false, ErrDBClosed
+	}
+
+	inUse := false
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return false, err
+	}
+	defer s.closeDBCon(db)
+
+	err = db.View(func(tx *bolt.Tx) error {
+		namesBkt, err := getNamesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		reservationsBkt, err := getReservationsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		inUse = namesBkt.Get([]byte(name)) != nil || reservationsBkt.Get([]byte(name)) != nil
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return inUse, nil
+}
@@ -22,6 +22,7 @@ const (
 	podName           = "pod"
 	allPodsName       = "allPods"
 	runtimeConfigName = "runtime-config"
+	reservationsName  = "name-reservations"
 
 	configName       = "config"
 	stateName        = "state"
@@ -41,6 +42,7 @@ var (
 	podBkt           = []byte(podName)
 	allPodsBkt       = []byte(allPodsName)
 	runtimeConfigBkt = []byte(runtimeConfigName)
+	reservationsBkt  = []byte(reservationsName)
 
 	configKey       = []byte(configName)
 	stateKey        = []byte(stateName)
@@ -49,6 +51,8 @@ var (
 	containersBkt   = []byte(containersName)
 	podIDKey        = []byte(podIDName)
 	namespaceKey    = []byte(namespaceName)
+
+	graphDriverNameKey = []byte("graph-driver-name")
 )
 
 // Check if the configuration of the database is compatible with the
@@ -56,12 +60,11 @@ var (
 // If there is no runtime configuration loaded, load our own
 func checkRuntimeConfig(db *bolt.DB, rt *Runtime) error {
 	var (
-		staticDir       = []byte("static-dir")
-		tmpDir          = []byte("tmp-dir")
-		runRoot         = []byte("run-root")
-		graphRoot       = []byte("graph-root")
-		graphDriverName = []byte("graph-driver-name")
-		osKey           = []byte("os")
+		staticDir = []byte("static-dir")
+		tmpDir    = []byte("tmp-dir")
+		runRoot   = []byte("run-root")
+		graphRoot = []byte("graph-root")
+		osKey     = []byte("os")
 	)
 
 	err := db.Update(func(tx *bolt.Tx) error {
@@ -75,30 +78,30 @@ func checkRuntimeConfig(db *bolt.DB, rt *Runtime) error {
 		}
 
 		if err := validateDBAgainstConfig(configBkt, "static dir",
-			rt.config.StaticDir, staticDir, ""); err != nil {
+			rt.config().StaticDir, staticDir, ""); err != nil {
 			return err
 		}
 
 		if err := validateDBAgainstConfig(configBkt, "tmp dir",
-			rt.config.TmpDir, tmpDir, ""); err != nil {
+			rt.config().TmpDir, tmpDir, ""); err != nil {
 			return err
 		}
 
 		if err := validateDBAgainstConfig(configBkt, "run root",
-			rt.config.StorageConfig.RunRoot, runRoot,
+			rt.config().StorageConfig.RunRoot, runRoot,
 			storage.DefaultStoreOptions.RunRoot); err != nil {
 			return err
 		}
 
 		if err := validateDBAgainstConfig(configBkt, "graph root",
-			rt.config.StorageConfig.GraphRoot, graphRoot,
+			rt.config().StorageConfig.GraphRoot, graphRoot,
 			storage.DefaultStoreOptions.GraphRoot); err != nil {
 			return err
 		}
 
 		return validateDBAgainstConfig(configBkt, "graph driver name",
-			rt.config.StorageConfig.GraphDriverName,
-			graphDriverName,
+			rt.config().StorageConfig.GraphDriverName,
+			graphDriverNameKey,
 			storage.DefaultStoreOptions.GraphDriverName)
 	})
 
@@ -237,17 +240,61 @@ func getRuntimeConfigBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
 	return bkt, nil
 }
 
+func getReservationsBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	bkt := tx.Bucket(reservationsBkt)
+	if bkt == nil {
+		return nil, errors.Wrapf(ErrDBBadConfig, "name reservations bucket not found in DB")
+	}
+	return bkt, nil
+}
+
+// claimNameReservation checks that the name of a container or pod being added
+// is not reserved, unless with the given token, in which case the reservation
+// is removed as the name is now in use
+func claimNameReservation(tx *bolt.Tx, name []byte, token string) error {
+	reservationsBkt, err := getReservationsBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	reservationJSON := reservationsBkt.Get(name)
+	if reservationJSON == nil {
+		if token != "" {
+			return errors.Wrapf(ErrNoSuchNameReservation, "name %s is not reserved", name)
+		}
+		return nil
+	}
+	reservation := new(NameReservation)
+	if err := json.Unmarshal(reservationJSON, reservation); err != nil {
+		return errors.Wrapf(err, "error unmarshalling reservation of name %s", name)
+	}
+	if token != reservation.Token {
+		return errors.Wrapf(ErrNameReserved, "name %s is reserved", name)
+	}
+
+	if err := reservationsBkt.Delete(name); err != nil {
+		return errors.Wrapf(err, "error removing reservation of name %s from DB", name)
+	}
+	return nil
+}
+
 func (s *BoltState) getContainerFromDB(id []byte, ctr *Container, ctrsBkt *bolt.Bucket) error {
+	return s.getContainerFromDBInNamespace(id, ctr, ctrsBkt, s.namespaceBytes)
+}
+
+// Get a container from the DB, which must be part of the given namespace
+// unless it is nil
+func (s *BoltState) getContainerFromDBInNamespace(id []byte, ctr *Container, ctrsBkt *bolt.Bucket, namespace []byte) error {
 	valid := true
 	ctrBkt := ctrsBkt.Bucket(id)
 	if ctrBkt == nil {
 		return errors.Wrapf(ErrNoSuchCtr, "container %s not found in DB", string(id))
 	}
 
-	if s.namespaceBytes != nil {
+	if namespace != nil {
 		ctrNamespaceBytes := ctrBkt.Get(namespaceKey)
-		if !bytes.Equal(s.namespaceBytes, ctrNamespaceBytes) {
-			return errors.Wrapf(ErrNSMismatch, "cannot retrieve container %s as it is part of namespace %q and we are in namespace %q", string(id), string(ctrNamespaceBytes), s.namespace)
+		if !bytes.Equal(namespace, ctrNamespaceBytes) {
+			return errors.Wrapf(ErrNSMismatch, "cannot retrieve container %s as it is part of namespace %q and we are in namespace %q", string(id), string(ctrNamespaceBytes), string(namespace))
 		}
 	}
 
@@ -275,15 +322,21 @@ func (s *BoltState) getContainerFromDB(id []byte, ctr *Container, ctrsBkt *bolt.
 }
 
 func (s *BoltState) getPodFromDB(id []byte, pod *Pod, podBkt *bolt.Bucket) error {
+	return s.getPodFromDBInNamespace(id, pod, podBkt, s.namespaceBytes)
+}
+
+// Get a pod from the DB, which must be part of the given namespace unless it
+// is nil
+func (s *BoltState) getPodFromDBInNamespace(id []byte, pod *Pod, podBkt *bolt.Bucket, namespace []byte) error {
 	podDB := podBkt.Bucket(id)
 	if podDB == nil {
 		return errors.Wrapf(ErrNoSuchPod, "pod with ID %s not found", string(id))
 	}
 
-	if s.namespaceBytes != nil {
+	if namespace != nil {
 		podNamespaceBytes := podDB.Get(namespaceKey)
-		if !bytes.Equal(s.namespaceBytes, podNamespaceBytes) {
-			return errors.Wrapf(ErrNSMismatch, "cannot retrieve pod %s as it is part of namespace %q and we are in namespace %q", string(id), string(podNamespaceBytes), s.namespace)
+		if !bytes.Equal(namespace, podNamespaceBytes) {
+			return errors.Wrapf(ErrNSMismatch, "cannot retrieve pod %s as it is part of namespace %q and we are in namespace %q", string(id), string(podNamespaceBytes), string(namespace))
 		}
 	}
 
@@ -408,6 +461,9 @@ func (s *BoltState) addContainer(ctr *Container, pod *Pod) error {
 		if nameExist != nil {
 			return errors.Wrapf(ErrCtrExists, "name %s is in use", ctr.Name())
 		}
+		if err := claimNameReservation(tx, ctrName, ctr.nameReservation); err != nil {
+			return err
+		}
 
 		// No overlapping containers
 		// Add the new container to the DB
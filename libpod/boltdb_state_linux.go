@@ -3,6 +3,8 @@
 package libpod
 
 import (
+	"os"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -21,6 +23,12 @@ func replaceNetNS(netNSPath string, ctr *Container, newState *containerState) er
 			}
 
 			// Open the new network namespace
+			// It may have been lost with the temporary files
+			// without the container being removed
+			if _, err := os.Stat(netNSPath); os.IsNotExist(err) {
+				logrus.Warnf("Network namespace %s of container %s no longer exists", netNSPath, ctr.ID())
+				return nil
+			}
 			ns, err := joinNetNS(netNSPath)
 			if err == nil {
 				newState.NetNS = ns
@@ -0,0 +1,278 @@
+package libpod
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/pkg/buildfile"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/projectatomic/buildah/imagebuildah"
+	"github.com/sirupsen/logrus"
+)
+
+// buildCacheMountsDir is the directory of the static directory holding the
+// directories of the cache mounts of RUN instructions
+const buildCacheMountsDir = "build-cache"
+
+// build builds the Containerfiles with their heredocs expanded. Those with
+// RUN instructions with mounts are built in segments, each of these
+// instructions being built alone with its mounts.
+func (r *Runtime) build(ctx context.Context, options imagebuildah.BuildOptions, secrets map[string]string, dockerfiles ...string) error {
+	files, err := buildfile.Read(options.ContextDirectory, dockerfiles)
+	if err != nil {
+		return err
+	}
+	if files == nil {
+		return imagebuildah.BuildDockerfiles(ctx, r.store, options, dockerfiles...)
+	}
+	node, err := buildfile.Parse(files)
+	if err != nil {
+		return err
+	}
+	plan, err := buildfile.NewPlan(node)
+	if err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir("", "podman-build")
+	if err != nil {
+		return errors.Wrapf(err, "error creating build directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			logrus.Errorf("Unable to remove build directory %s: %v", dir, err)
+		}
+	}()
+	if !plan.HasMounts() {
+		var paths []string
+		for i, contents := range files {
+			path := filepath.Join(dir, fmt.Sprintf("Containerfile.%d", i))
+			if err := ioutil.WriteFile(path, contents, 0600); err != nil {
+				return errors.Wrapf(err, "error writing Containerfile")
+			}
+			paths = append(paths, path)
+		}
+		return imagebuildah.BuildDockerfiles(ctx, r.store, options, paths...)
+	}
+	return r.buildSegments(ctx, options, secrets, plan, dir)
+}
+
+// buildSegments builds the segments of a Containerfile one after the other,
+// each based on the image of the previous segment of its stage. The images
+// of the segments but the last are unnamed, and removed once the build is
+// over unless they are the cache of the build.
+func (r *Runtime) buildSegments(ctx context.Context, options imagebuildah.BuildOptions, secrets map[string]string, plan *buildfile.Plan, dir string) error {
+	if options.Squash {
+		return errors.Wrapf(ErrInvalidArg, "images built with RUN instructions with mounts can not be squashed")
+	}
+	var (
+		images       []string
+		intermediate []string
+	)
+	defer func() {
+		if options.Layers || !options.RemoveIntermediateCtrs {
+			return
+		}
+		for i := len(intermediate) - 1; i >= 0; i-- {
+			if _, err := r.store.DeleteImage(intermediate[i], true); err != nil {
+				logrus.Errorf("Unable to remove intermediate image %s: %v", intermediate[i], err)
+			}
+		}
+	}()
+
+	for i, segment := range plan.Segments {
+		for len(images) <= segment.Stage {
+			images = append(images, "")
+		}
+		contents, err := plan.Containerfile(segment, images)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, fmt.Sprintf("Containerfile.%d", i))
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			return errors.Wrapf(err, "error writing Containerfile")
+		}
+
+		segmentOptions := options
+		segmentOptions.TransientMounts = append([]imagebuildah.Mount{}, options.TransientMounts...)
+		for _, m := range segment.Mounts {
+			mount, err := r.buildMount(m, options.ContextDirectory, dir, secrets)
+			if err != nil {
+				return err
+			}
+			if mount != nil {
+				segmentOptions.TransientMounts = append(segmentOptions.TransientMounts, *mount)
+			}
+		}
+		// The segments following others are based on their local images
+		if i > 0 && options.PullPolicy == imagebuildah.PullAlways {
+			segmentOptions.PullPolicy = imagebuildah.PullIfMissing
+		}
+		last := i == len(plan.Segments)-1
+		if !last {
+			segmentOptions.Output = ""
+			segmentOptions.AdditionalTags = nil
+			segmentOptions.IIDFile = filepath.Join(dir, fmt.Sprintf("iid.%d", i))
+		}
+		if err := imagebuildah.BuildDockerfiles(ctx, r.store, segmentOptions, path); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		id, err := ioutil.ReadFile(segmentOptions.IIDFile)
+		if err != nil {
+			return errors.Wrapf(err, "error reading ID of intermediate image")
+		}
+		images[segment.Stage] = strings.TrimSpace(string(id))
+		intermediate = append(intermediate, images[segment.Stage])
+	}
+	return nil
+}
+
+// buildMount returns the mount of the build container of a RUN instruction
+// for one of its mounts, nil for a secret mount whose secret was not given
+// and is not required. Bind mounts are of files of the context directory,
+// cache mounts of directories of the build cache, tmpfs mounts of empty
+// directories in dir, and secret mounts of the files of secrets.
+func (r *Runtime) buildMount(m *buildfile.Mount, contextDir, dir string, secrets map[string]string) (*imagebuildah.Mount, error) {
+	mount := &imagebuildah.Mount{
+		Destination: m.Target,
+		Type:        "bind",
+		Options:     []string{"rw"},
+	}
+	if m.ReadOnly {
+		mount.Options = []string{"ro"}
+	}
+	switch m.Type {
+	case buildfile.MountTypeBind:
+		source, err := contextPath(contextDir, m.Source)
+		if err != nil {
+			return nil, err
+		}
+		mount.Source = source
+	case buildfile.MountTypeCache:
+		source, err := r.buildCacheMount(m.ID)
+		if err != nil {
+			return nil, err
+		}
+		mount.Source = source
+		mount.Options = append(mount.Options, "z")
+	case buildfile.MountTypeTmpfs:
+		source, err := ioutil.TempDir(dir, "tmpfs")
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating directory of tmpfs mount %s", m.Target)
+		}
+		mount.Source = source
+		mount.Options = append(mount.Options, "z")
+	case buildfile.MountTypeSecret:
+		source, ok := secrets[m.ID]
+		if !ok {
+			if m.Required {
+				return nil, errors.Wrapf(ErrInvalidArg, "secret %q is required by a RUN instruction but was not given", m.ID)
+			}
+			return nil, nil
+		}
+		mount.Source = source
+	default:
+		return nil, errors.Wrapf(ErrInvalidArg, "unknown mount type %q", m.Type)
+	}
+	return mount, nil
+}
+
+// contextPath returns the path of a file of the context directory, which
+// must not be outside of it once its symbolic links are resolved
+func contextPath(contextDir, path string) (string, error) {
+	root, err := filepath.EvalSymlinks(contextDir)
+	if err != nil {
+		return "", errors.Wrapf(err, "error resolving context directory %s", contextDir)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, path))
+	if err != nil {
+		return "", errors.Wrapf(err, "error resolving %q in the context directory", path)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", errors.Wrapf(ErrInvalidArg, "%q is outside of the context directory", path)
+	}
+	return resolved, nil
+}
+
+// buildCacheMount returns the directory of the cache mounts with the ID,
+// created if need be, and marks it as used now
+func (r *Runtime) buildCacheMount(id string) (string, error) {
+	dir := filepath.Join(r.config().StaticDir, buildCacheMountsDir, digest.FromString(id).Hex())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrapf(err, "error creating directory of cache mount %q", id)
+	}
+	now := time.Now()
+	if err := os.Chtimes(dir, now, now); err != nil {
+		return "", errors.Wrapf(err, "error updating directory of cache mount %q", id)
+	}
+	return dir, nil
+}
+
+// PruneBuildCacheMounts removes the directories of the cache mounts of RUN
+// instructions not used since until, all of them if until is zero, and
+// returns the space reclaimed
+func (r *Runtime) PruneBuildCacheMounts(until time.Time) (uint64, error) {
+	if err := r.checkReadOnly(); err != nil {
+		return 0, err
+	}
+	return r.pruneBuildCacheMounts(until, false)
+}
+
+// pruneBuildCacheMounts removes the cache mounts not used since until, and
+// returns the space reclaimed. With dryRun, nothing is removed.
+func (r *Runtime) pruneBuildCacheMounts(until time.Time, dryRun bool) (uint64, error) {
+	root := filepath.Join(r.config().StaticDir, buildCacheMountsDir)
+	dirs, err := ioutil.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "error reading cache mounts directory %s", root)
+	}
+	var reclaimed uint64
+	for _, dir := range dirs {
+		if !until.IsZero() && !dir.ModTime().Before(until) {
+			continue
+		}
+		path := filepath.Join(root, dir.Name())
+		size, err := dirSize(path)
+		if err != nil {
+			return reclaimed, err
+		}
+		if !dryRun {
+			if err := os.RemoveAll(path); err != nil {
+				return reclaimed, errors.Wrapf(err, "error removing cache mount directory %s", path)
+			}
+		}
+		reclaimed += size
+	}
+	return reclaimed, nil
+}
+
+// dirSize returns the size of the files in a directory
+func dirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "error computing size of %s", dir)
+	}
+	return size, nil
+}
@@ -0,0 +1,119 @@
+// +build linux
+
+package libpod
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// BuildNetNS is a network namespace configured by slirp4netns, which the RUN
+// instructions of rootless builds join, as they can not use CNI networks
+type BuildNetNS struct {
+	// holder keeps the namespace alive until its stdin is closed
+	holder      *exec.Cmd
+	holderStdin io.WriteCloser
+	slirp       *exec.Cmd
+	// slirpExit makes slirp4netns exit once closed
+	slirpExit *os.File
+}
+
+// NewBuildNetNS creates a network namespace for the RUN instructions of a
+// rootless build, configured by slirp4netns with the given options
+func (r *Runtime) NewBuildNetNS(options []string) (_ *BuildNetNS, err error) {
+	opts, err := parseSlirp4netnsOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	path, err := exec.LookPath("slirp4netns")
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find slirp4netns")
+	}
+
+	n := &BuildNetNS{}
+	defer func() {
+		if err != nil {
+			if err2 := n.Close(); err2 != nil {
+				logrus.Debugf("%v", err2)
+			}
+		}
+	}()
+
+	// The namespace is held by a process in it, which exits with podman as
+	// its stdin is closed then
+	n.holder = exec.Command("cat")
+	n.holder.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWNET,
+		Pdeathsig:  syscall.SIGKILL,
+	}
+	if n.holderStdin, err = n.holder.StdinPipe(); err != nil {
+		return nil, errors.Wrapf(err, "failed to open pipe")
+	}
+	if err := n.holder.Start(); err != nil {
+		n.holder = nil
+		return nil, errors.Wrapf(err, "failed to create network namespace")
+	}
+
+	exitR, exitW, err := os.Pipe()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open pipe")
+	}
+	defer exitR.Close()
+	n.slirpExit = exitW
+	syncR, syncW, err := os.Pipe()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open pipe")
+	}
+	defer syncR.Close()
+	defer syncW.Close()
+
+	args := append([]string{"-c", "-e", "3", "-r", "4"}, opts.args()...)
+	args = append(args, fmt.Sprintf("%d", n.holder.Process.Pid), "tap0")
+	n.slirp = exec.Command(path, args...)
+	n.slirp.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+	n.slirp.ExtraFiles = append(n.slirp.ExtraFiles, exitR, syncW)
+	if err := n.slirp.Start(); err != nil {
+		n.slirp = nil
+		return nil, errors.Wrapf(err, "failed to start slirp4netns")
+	}
+	syncW.Close()
+
+	b := make([]byte, 16)
+	if _, err := syncR.Read(b); err != nil {
+		return nil, errors.Wrapf(err, "failed to read from sync pipe")
+	}
+	logrus.Debugf("Configured build network namespace %s with slirp4netns", n.Path())
+	return n, nil
+}
+
+// Path returns the path of the network namespace
+func (n *BuildNetNS) Path() string {
+	return fmt.Sprintf("/proc/%d/ns/net", n.holder.Process.Pid)
+}
+
+// Close stops slirp4netns and releases the network namespace
+func (n *BuildNetNS) Close() error {
+	if n.slirpExit != nil {
+		n.slirpExit.Close()
+	}
+	if n.slirp != nil {
+		n.slirp.Wait()
+	}
+	if n.holderStdin != nil {
+		n.holderStdin.Close()
+	}
+	if n.holder != nil {
+		if err := n.holder.Wait(); err != nil {
+			return errors.Wrapf(err, "error releasing build network namespace")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+// +build !linux
+
+package libpod
+
+// BuildNetNS is a network namespace for the RUN instructions of rootless
+// builds, not supported on this platform
+type BuildNetNS struct{}
+
+// NewBuildNetNS is not implemented on this platform
+func (r *Runtime) NewBuildNetNS(options []string) (*BuildNetNS, error) {
+	return nil, ErrNotImplemented
+}
+
+// Path is not implemented on this platform
+func (n *BuildNetNS) Path() string {
+	return ""
+}
+
+// Close is not implemented on this platform
+func (n *BuildNetNS) Close() error {
+	return ErrNotImplemented
+}
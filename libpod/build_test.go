@@ -0,0 +1,98 @@
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containers/libpod/pkg/buildfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "context")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	contextDir := filepath.Join(dir, "context")
+	require.NoError(t, os.MkdirAll(filepath.Join(contextDir, "src"), 0755))
+	require.NoError(t, os.Symlink("src", filepath.Join(contextDir, "inside")))
+	require.NoError(t, os.Symlink(dir, filepath.Join(contextDir, "outside")))
+
+	path, err := contextPath(contextDir, ".")
+	require.NoError(t, err)
+	assert.Equal(t, contextDir, path)
+	path, err = contextPath(contextDir, "inside")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(contextDir, "src"), path)
+
+	for _, outside := range []string{"outside", "..", "src/../../context/../"} {
+		_, err := contextPath(contextDir, outside)
+		assert.Error(t, err, outside)
+	}
+	_, err = contextPath(contextDir, "missing")
+	assert.Error(t, err)
+}
+
+func TestBuildMount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "build")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	r := &Runtime{}
+	r.setConfig(&RuntimeConfig{StaticDir: dir})
+
+	cache, err := r.buildMount(&buildfile.Mount{Type: buildfile.MountTypeCache, Target: "/root/.cache", ID: "go"}, dir, dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/root/.cache", cache.Destination)
+	assert.Equal(t, []string{"rw", "z"}, cache.Options)
+	again, err := r.buildMount(&buildfile.Mount{Type: buildfile.MountTypeCache, Target: "/go/pkg", ID: "go", ReadOnly: true}, dir, dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, cache.Source, again.Source)
+	assert.Equal(t, []string{"ro", "z"}, again.Options)
+
+	secret := &buildfile.Mount{Type: buildfile.MountTypeSecret, Target: "/run/secrets/token", ID: "token", ReadOnly: true}
+	mount, err := r.buildMount(secret, dir, dir, map[string]string{"token": "/tmp/token"})
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/token", mount.Source)
+	mount, err = r.buildMount(secret, dir, dir, nil)
+	require.NoError(t, err)
+	assert.Nil(t, mount)
+	secret.Required = true
+	_, err = r.buildMount(secret, dir, dir, nil)
+	assert.Error(t, err)
+}
+
+func TestPruneBuildCacheMounts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "build")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	r := &Runtime{}
+	r.setConfig(&RuntimeConfig{StaticDir: dir})
+
+	reclaimed, err := r.PruneBuildCacheMounts(time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), reclaimed)
+
+	old, err := r.buildCacheMount("old")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(old, "file"), []byte("1234"), 0644))
+	past := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(old, past, past))
+	recent, err := r.buildCacheMount("recent")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(recent, "file"), []byte("12"), 0644))
+
+	reclaimed, err = r.PruneBuildCacheMounts(time.Now().Add(-24 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), reclaimed)
+	_, err = os.Stat(old)
+	assert.True(t, os.IsNotExist(err))
+
+	reclaimed, err = r.PruneBuildCacheMounts(time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), reclaimed)
+	_, err = os.Stat(recent)
+	assert.True(t, os.IsNotExist(err))
+}
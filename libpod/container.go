@@ -9,6 +9,9 @@ import (
 
 	"github.com/containernetworking/cni/pkg/types"
 	cnitypes "github.com/containernetworking/cni/pkg/types/current"
+	"github.com/containers/image/manifest"
+	"github.com/containers/libpod/pkg/inspect"
+	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/storage"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
@@ -38,6 +41,10 @@ const (
 	ContainerStatePaused ContainerStatus = iota
 )
 
+// RebootExitCode is the exit code recorded for containers that were running
+// when the system rebooted, as their real exit code is lost
+const RebootExitCode = 255
+
 // CgroupfsDefaultCgroupParent is the cgroup parent for CGroupFS in libpod
 const CgroupfsDefaultCgroupParent = "/libpod_parent"
 
@@ -45,6 +52,19 @@ const CgroupfsDefaultCgroupParent = "/libpod_parent"
 // manager in libpod
 const SystemdDefaultCgroupParent = "machine.slice"
 
+// SystemdDefaultRootlessCgroupParent is the cgroup parent for the systemd
+// cgroup manager in rootless libpod, a slice of the systemd instance of the
+// user
+const SystemdDefaultRootlessCgroupParent = "user.slice"
+
+// KubernetesLogDriver is the log driver writing the output of containers to
+// a file in the Kubernetes CRI log format, the only one conmon supports
+const KubernetesLogDriver = "k8s-file"
+
+// JSONLogDriver is the name of the Docker log driver accepted for the
+// Kubernetes one, which podman logs reads the same way
+const JSONLogDriver = "json-file"
+
 // LinuxNS represents a Linux namespace
 type LinuxNS int
 
@@ -119,6 +139,10 @@ type Container struct {
 
 	rootlessSlirpSyncR *os.File
 	rootlessSlirpSyncW *os.File
+
+	// nameReservation is the token of the reservation of the name of the
+	// container, claimed as it is added to the state
+	nameReservation string
 }
 
 // containerState contains the current state of the container
@@ -179,6 +203,36 @@ type containerState struct {
 	// and not delegated to the OCI runtime.
 	ExtensionStageHooks map[string][]spec.Hook `json:"extensionStageHooks,omitempty"`
 
+	// BlockIO holds the block IO limits set by UpdateBlockIO, which
+	// replace those of the spec of the container
+	BlockIO *spec.LinuxBlockIO `json:"blockIO,omitempty"`
+	// CPUSetCPUs and CPUSetMems hold the cpuset set by UpdateCPUSet,
+	// which replaces that of the spec of the container
+	CPUSetCPUs string `json:"cpusetCpus,omitempty"`
+	CPUSetMems string `json:"cpusetMems,omitempty"`
+
+	// HealthCheck holds the results of the healthchecks run since the
+	// container last started, if it has a healthcheck
+	HealthCheck *inspect.HealthCheckResults `json:"healthCheck,omitempty"`
+	// StartupHealthCheckPassed indicates that the startup healthcheck of
+	// the container passed since it last started, so its regular
+	// healthchecks run and it counts as started for its dependents
+	StartupHealthCheckPassed bool `json:"startupHealthCheckPassed,omitempty"`
+
+	// RestartCount is the number of times the container was restarted by
+	// its restart policy since it was last started by a user
+	RestartCount uint `json:"restartCount,omitempty"`
+	// StoppedByUser indicates that the container was stopped or killed by
+	// a user, and is not restarted by its restart policy
+	StoppedByUser bool `json:"stoppedByUser,omitempty"`
+	// SystemdService is the systemd service unit the container was
+	// started from, if any
+	SystemdService string `json:"systemdService,omitempty"`
+	// PidsLimitHits is the number of times the container failed to create
+	// a process because of its PIDs limit since it last started, as last
+	// reported
+	PidsLimitHits uint64 `json:"pidsLimitHits,omitempty"`
+
 	// containerPlatformState holds platform-specific container state.
 	containerPlatformState
 }
@@ -265,6 +319,11 @@ type ContainerConfig struct {
 	// network namespace for the container
 	// This cannot be set if NetNsCtr is also set
 	CreateNetNS bool `json:"createNetNS"`
+	// NetNsPath is the path to an existing network namespace, created by
+	// something other than libpod, that the container joins
+	// Libpod does not configure the namespace, but records its addresses
+	// This cannot be set if CreateNetNS or NetNsCtr is also set
+	NetNsPath string `json:"netNsPath,omitempty"`
 	// PortMappings are the ports forwarded to the container's network
 	// namespace
 	// These are not used unless CreateNetNS is true
@@ -283,6 +342,14 @@ type ContainerConfig struct {
 	HostAdd []string `json:"hostsAdd,omitempty"`
 	// Network names (CNI) to add container to. Empty to use default network.
 	Networks []string `json:"networks,omitempty"`
+	// NetworkOptions are options of the tools configuring the network
+	// namespace of the container, by tool, such as
+	// Slirp4netnsNetworkOptions for rootless containers
+	NetworkOptions map[string][]string `json:"networkOptions,omitempty"`
+	// ResourceWaitTimeout is how long, in seconds, the container waits when
+	// it starts for the external resources it depends on, its CNI
+	// networks, to be available. If 0 it fails at once when they are not.
+	ResourceWaitTimeout uint `json:"resourceWaitTimeout,omitempty"`
 
 	// Image Config
 
@@ -317,10 +384,29 @@ type ContainerConfig struct {
 	CreatedTime time.Time `json:"createdTime"`
 	// Cgroup parent of the container
 	CgroupParent string `json:"cgroupParent"`
+	// LogDriver is the log driver of the container, KubernetesLogDriver.
+	// Empty for containers created before it was recorded.
+	LogDriver string `json:"logDriver,omitempty"`
 	// LogPath log location
 	LogPath string `json:"logPath"`
+	// LogMaxSize is the size in bytes at which the log file is rotated.
+	// If 0, the log file is not rotated.
+	LogMaxSize int64 `json:"logMaxSize,omitempty"`
+	// LogMaxFiles is the number of log files kept, the log file included,
+	// when it is rotated
+	LogMaxFiles uint `json:"logMaxFiles,omitempty"`
 	// File containing the conmon PID
 	ConmonPidFile string `json:"conmonPidFile,omitempty"`
+	// CreateCommand is the command line the container was created with,
+	// to create it again, as systemd units generated with --new do
+	CreateCommand []string `json:"createCommand,omitempty"`
+	// HealthCheckConfig is the healthcheck of the container, run by podman
+	// healthcheck run at its interval while the container runs
+	HealthCheckConfig *manifest.Schema2HealthConfig `json:"healthcheck,omitempty"`
+	// StartupHealthCheckConfig is the startup healthcheck of the
+	// container, run at its own interval after the container starts until
+	// it passes once. A retries of 0 retries it forever.
+	StartupHealthCheckConfig *manifest.Schema2HealthConfig `json:"startupHealthcheck,omitempty"`
 	// TODO log options for log drivers
 
 	PostConfigureNetNS bool `json:"postConfigureNetNS"`
@@ -328,6 +414,20 @@ type ContainerConfig struct {
 	// ExitCommand is the container's exit command.
 	// This Command will be executed when the container exits
 	ExitCommand []string `json:"exitCommand,omitempty"`
+	// RestartPolicy is the policy restarting the container when it exits,
+	// one of the RestartPolicy constants. If empty, it is not restarted.
+	RestartPolicy string `json:"restartPolicy,omitempty"`
+	// RestartRetries is the number of times the container is restarted by
+	// the on-failure policy. If 0, it is restarted indefinitely.
+	RestartRetries uint `json:"restartRetries,omitempty"`
+	// RuntimeHandler selects the OCI runtime running the container instead
+	// of the default one, WasmRuntimeHandler or KataRuntimeHandler
+	RuntimeHandler string `json:"runtimeHandler,omitempty"`
+	// Timezone is the timezone of the container, mounted from the zoneinfo
+	// database of the host as its /etc/localtime. LocalTimezone gives it
+	// the timezone of the host. If empty, the /etc/localtime of the image
+	// is used.
+	Timezone string `json:"timezone,omitempty"`
 	// LocalVolumes are the built-in volumes we get from the --volumes-from flag
 	// It picks up the built-in volumes of the container used by --volumes-from
 	LocalVolumes []string
@@ -335,6 +435,10 @@ type ContainerConfig struct {
 	// IsInfra is a bool indicating whether this container is an infra container used for
 	// sharing kernel namespaces in a pod
 	IsInfra bool `json:"pause"`
+	// InitContainerType is the type of the container if it is an init
+	// container of its pod, one of the InitContainer constants, and empty
+	// otherwise
+	InitContainerType string `json:"initContainerType,omitempty"`
 }
 
 // ContainerStatus returns a string representation for users
@@ -496,6 +600,28 @@ func (c *Container) NewNetNS() bool {
 	return c.config.CreateNetNS
 }
 
+// NetNSPath returns the path of the existing network namespace the container
+// joins, or "" if it does not join one by path
+func (c *Container) NetNSPath() string {
+	return c.config.NetNsPath
+}
+
+// NetworkOptions returns the options of the tools configuring the network
+// namespace of the container, by tool
+func (c *Container) NetworkOptions() map[string][]string {
+	options := make(map[string][]string, len(c.config.NetworkOptions))
+	for tool, opts := range c.config.NetworkOptions {
+		options[tool] = append([]string(nil), opts...)
+	}
+	return options
+}
+
+// ResourceWaitTimeout returns how long, in seconds, the container waits when
+// it starts for its CNI networks to be available
+func (c *Container) ResourceWaitTimeout() uint {
+	return c.config.ResourceWaitTimeout
+}
+
 // PortMappings returns the ports that will be mapped into a container if
 // a new network namespace is created
 // If NewNetNS() is false, this value is unused
@@ -592,6 +718,17 @@ func (c *Container) StopTimeout() uint {
 	return c.config.StopTimeout
 }
 
+// RestartPolicy returns the policy restarting the container when it exits
+func (c *Container) RestartPolicy() string {
+	return c.config.RestartPolicy
+}
+
+// RestartRetries returns the number of times the container is restarted by
+// the on-failure restart policy, 0 if indefinitely
+func (c *Container) RestartRetries() uint {
+	return c.config.RestartRetries
+}
+
 // CreatedTime gets the time when the container was created
 func (c *Container) CreatedTime() time.Time {
 	return c.config.CreatedTime
@@ -602,6 +739,29 @@ func (c *Container) CgroupParent() string {
 	return c.config.CgroupParent
 }
 
+// ConmonPidFile returns the path of the file conmon writes its PID to
+func (c *Container) ConmonPidFile() string {
+	return c.config.ConmonPidFile
+}
+
+// CreateCommand returns the command line the container was created with, if
+// it was recorded
+func (c *Container) CreateCommand() []string {
+	return c.config.CreateCommand
+}
+
+// HealthCheckConfig returns the healthcheck of the container, nil if it has
+// none
+func (c *Container) HealthCheckConfig() *manifest.Schema2HealthConfig {
+	return c.config.HealthCheckConfig
+}
+
+// StartupHealthCheckConfig returns the startup healthcheck of the container,
+// nil if it has none
+func (c *Container) StartupHealthCheckConfig() *manifest.Schema2HealthConfig {
+	return c.config.StartupHealthCheckConfig
+}
+
 // LogPath returns the path to the container's log file
 // This file will only be present after Init() is called to create the container
 // in the runtime
@@ -609,11 +769,31 @@ func (c *Container) LogPath() string {
 	return c.config.LogPath
 }
 
+// LogDriver returns the log driver of the container
+func (c *Container) LogDriver() string {
+	if c.config.LogDriver == "" {
+		return KubernetesLogDriver
+	}
+	return c.config.LogDriver
+}
+
 // RuntimeName returns the name of the runtime
 func (c *Container) RuntimeName() string {
 	return c.runtime.ociRuntime.name
 }
 
+// RuntimeHandler returns the handler selecting the OCI runtime of the
+// container, or "" if it runs with the default runtime
+func (c *Container) RuntimeHandler() string {
+	return c.config.RuntimeHandler
+}
+
+// Timezone returns the timezone of the container, or "" if it uses the one of
+// its image
+func (c *Container) Timezone() string {
+	return c.config.Timezone
+}
+
 // Runtime spec accessors
 // Unlocked
 
@@ -791,7 +971,8 @@ func (c *Container) ExecSession(id string) (*ExecSession, error) {
 
 // IPs retrieves a container's IP address(es)
 // This will only be populated if the container is configured to created a new
-// network namespace, and that namespace is presently active
+// network namespace or to join one by path, and that namespace is presently
+// active
 func (c *Container) IPs() ([]net.IPNet, error) {
 	if !c.batched {
 		c.lock.Lock()
@@ -802,8 +983,8 @@ func (c *Container) IPs() ([]net.IPNet, error) {
 		}
 	}
 
-	if !c.config.CreateNetNS {
-		return nil, errors.Wrapf(ErrInvalidArg, "container %s network namespace is not managed by libpod")
+	if !c.config.CreateNetNS && c.config.NetNsPath == "" {
+		return nil, errors.Wrapf(ErrInvalidArg, "container %s network namespace is not managed by libpod", c.ID())
 	}
 
 	ips := make([]net.IPNet, 0)
@@ -819,7 +1000,8 @@ func (c *Container) IPs() ([]net.IPNet, error) {
 
 // Routes retrieves a container's routes
 // This will only be populated if the container is configured to created a new
-// network namespace, and that namespace is presently active
+// network namespace or to join one by path, and that namespace is presently
+// active
 func (c *Container) Routes() ([]types.Route, error) {
 	if !c.batched {
 		c.lock.Lock()
@@ -830,8 +1012,8 @@ func (c *Container) Routes() ([]types.Route, error) {
 		}
 	}
 
-	if !c.config.CreateNetNS {
-		return nil, errors.Wrapf(ErrInvalidArg, "container %s network namespace is not managed by libpod")
+	if !c.config.CreateNetNS && c.config.NetNsPath == "" {
+		return nil, errors.Wrapf(ErrInvalidArg, "container %s network namespace is not managed by libpod", c.ID())
 	}
 
 	routes := make([]types.Route, 0)
@@ -905,13 +1087,16 @@ func (c *Container) NamespacePath(ns LinuxNS) (string, error) {
 
 // CGroupPath returns a cgroups "path" for a given container.
 func (c *Container) CGroupPath() (string, error) {
-	switch c.runtime.config.CgroupManager {
+	switch c.runtime.config().CgroupManager {
 	case CgroupfsCgroupsManager:
 		return filepath.Join(c.config.CgroupParent, fmt.Sprintf("libpod-%s", c.ID())), nil
 	case SystemdCgroupsManager:
+		if rootless.IsRootless() {
+			return filepath.Join(rootless.UserCgroup(), c.config.CgroupParent, createUnitName("libpod", c.ID())), nil
+		}
 		return filepath.Join(c.config.CgroupParent, createUnitName("libpod", c.ID())), nil
 	default:
-		return "", errors.Wrapf(ErrInvalidArg, "unsupported CGroup manager %s in use", c.runtime.config.CgroupManager)
+		return "", errors.Wrapf(ErrInvalidArg, "unsupported CGroup manager %s in use", c.runtime.config().CgroupManager)
 	}
 }
 
@@ -968,3 +1153,9 @@ func (c *Container) RootGID() int {
 func (c *Container) IsInfra() bool {
 	return c.config.IsInfra
 }
+
+// InitContainerType returns the type of the container if it is an init
+// container of its pod, and an empty string otherwise
+func (c *Container) InitContainerType() string {
+	return c.config.InitContainerType
+}
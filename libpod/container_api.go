@@ -7,21 +7,28 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/containers/libpod/libpod/driver"
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/pkg/chrootuser"
 	"github.com/containers/libpod/pkg/inspect"
+	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/storage/pkg/stringid"
 	"github.com/docker/docker/daemon/caps"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/remotecommand"
 )
 
 // Init creates a container in the OCI runtime
 func (c *Container) Init(ctx context.Context) (err error) {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -36,7 +43,7 @@ func (c *Container) Init(ctx context.Context) (err error) {
 		return errors.Wrapf(ErrCtrExists, "container %s has already been created in runtime", c.ID())
 	}
 
-	notRunning, err := c.checkDependenciesRunning()
+	notRunning, err := c.checkDependenciesRunning(ctx)
 	if err != nil {
 		return errors.Wrapf(err, "error checking dependencies for container %s")
 	}
@@ -72,6 +79,10 @@ func (c *Container) Init(ctx context.Context) (err error) {
 // Stopped containers will be deleted and re-created in runc, undergoing a fresh
 // Init()
 func (c *Container) Start(ctx context.Context) (err error) {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -88,7 +99,7 @@ func (c *Container) Start(ctx context.Context) (err error) {
 		return errors.Wrapf(ErrCtrStateInvalid, "container %s must be in Created or Stopped state to be started", c.ID())
 	}
 
-	notRunning, err := c.checkDependenciesRunning()
+	notRunning, err := c.checkDependenciesRunning(ctx)
 	if err != nil {
 		return errors.Wrapf(err, "error checking dependencies for container %s")
 	}
@@ -135,6 +146,10 @@ func (c *Container) Start(ctx context.Context) (err error) {
 // The channel will be closed automatically after the result of attach has been
 // sent
 func (c *Container) StartAndAttach(ctx context.Context, streams *AttachStreams, keys string, resize <-chan remotecommand.TerminalSize) (attachResChan <-chan error, err error) {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return nil, err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -151,7 +166,7 @@ func (c *Container) StartAndAttach(ctx context.Context, streams *AttachStreams,
 		return nil, errors.Wrapf(ErrCtrStateInvalid, "container %s must be in Created or Stopped state to be started", c.ID())
 	}
 
-	notRunning, err := c.checkDependenciesRunning()
+	notRunning, err := c.checkDependenciesRunning(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error checking dependencies for container %s")
 	}
@@ -202,6 +217,10 @@ func (c *Container) StartAndAttach(ctx context.Context, streams *AttachStreams,
 // Default stop timeout is 10 seconds, but can be overridden when the container
 // is created
 func (c *Container) Stop() error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -218,7 +237,7 @@ func (c *Container) Stop() error {
 	}
 
 	if c.state.State == ContainerStateStopped {
-		return ErrCtrStopped
+		return c.cancelRestart()
 	}
 
 	return c.stop(c.config.StopTimeout)
@@ -228,6 +247,10 @@ func (c *Container) Stop() error {
 // manually. If timeout is 0, SIGKILL will be used immediately to kill the
 // container.
 func (c *Container) StopWithTimeout(timeout uint) error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -244,7 +267,7 @@ func (c *Container) StopWithTimeout(timeout uint) error {
 	}
 
 	if c.state.State == ContainerStateStopped {
-		return ErrCtrStopped
+		return c.cancelRestart()
 	}
 
 	return c.stop(timeout)
@@ -252,6 +275,10 @@ func (c *Container) StopWithTimeout(timeout uint) error {
 
 // Kill sends a signal to a container
 func (c *Container) Kill(signal uint) error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -265,13 +292,34 @@ func (c *Container) Kill(signal uint) error {
 		return errors.Wrapf(ErrCtrStateInvalid, "can only kill running containers")
 	}
 
-	return c.runtime.ociRuntime.killContainer(c, signal)
+	if err := c.runtime.ociRuntime.killContainer(c, signal); err != nil {
+		return err
+	}
+	// The restart policy does not restart containers killed by a user, but
+	// other signals than the ones stopping it may just be handled by the
+	// container
+	stopSignal := c.config.StopSignal
+	if stopSignal == 0 {
+		stopSignal = uint(syscall.SIGTERM)
+	}
+	if signal == uint(syscall.SIGKILL) || signal == stopSignal {
+		c.state.StoppedByUser = true
+		if err := c.save(); err != nil {
+			return err
+		}
+	}
+	c.newContainerEvent(events.Kill)
+	return nil
 }
 
-// Exec starts a new process inside the container
-// TODO allow specifying streams to attach to
+// Exec starts a new process inside the container, attached to the given
+// streams
 // TODO investigate allowing exec without attaching
-func (c *Container) Exec(tty, privileged bool, env, cmd []string, user string) error {
+func (c *Container) Exec(tty, privileged bool, env, cmd []string, user string, streams *AttachStreams) error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	var capList []string
 
 	locked := false
@@ -333,7 +381,7 @@ func (c *Container) Exec(tty, privileged bool, env, cmd []string, user string) e
 
 	logrus.Debugf("Creating new exec session in container %s with session id %s", c.ID(), sessionID)
 
-	execCmd, err := c.runtime.ociRuntime.execContainer(c, cmd, capList, env, tty, hostUser, sessionID)
+	execCmd, err := c.runtime.ociRuntime.execContainer(c, cmd, capList, env, tty, hostUser, sessionID, streams)
 	if err != nil {
 		return errors.Wrapf(err, "error exec %s", c.ID())
 	}
@@ -388,6 +436,7 @@ func (c *Container) Exec(tty, privileged bool, env, cmd []string, user string) e
 	}
 
 	logrus.Debugf("Successfully started exec session %s in container %s", sessionID, c.ID())
+	c.newContainerEvent(events.Exec)
 
 	// Unlock so other processes can use the container
 	if !c.batched {
@@ -433,12 +482,17 @@ func (c *Container) Attach(streams *AttachStreams, keys string, resize <-chan re
 		return errors.Wrapf(ErrCtrStateInvalid, "can only attach to created or running containers")
 	}
 
+	c.newContainerEvent(events.Attach)
 	return c.attach(streams, keys, resize, false)
 }
 
 // Mount mounts a container's filesystem on the host
 // The path where the container has been mounted is returned
 func (c *Container) Mount() (string, error) {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return "", err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -448,11 +502,20 @@ func (c *Container) Mount() (string, error) {
 		}
 	}
 
-	return c.mount()
+	mountPoint, err := c.mount()
+	if err != nil {
+		return "", err
+	}
+	c.newContainerEvent(events.Mount)
+	return mountPoint, nil
 }
 
 // Unmount unmounts a container's filesystem on the host
 func (c *Container) Unmount(force bool) error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -480,11 +543,19 @@ func (c *Container) Unmount(force bool) error {
 			return errors.Wrapf(err, "can't unmount %s last mount, it is still in use", c.ID())
 		}
 	}
-	return c.unmount(force)
+	if err := c.unmount(force); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Unmount)
+	return nil
 }
 
 // Pause pauses a container
 func (c *Container) Pause() error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -501,11 +572,38 @@ func (c *Container) Pause() error {
 		return errors.Wrapf(ErrCtrStateInvalid, "%q is not running, can't pause", c.state.State)
 	}
 
+	// Freezing the PID namespace of running containers would leave them
+	// unable to reap children or receive signals until it is unpaused
+	dependents, err := c.runtime.state.ContainerInUse(c)
+	if err != nil {
+		return err
+	}
+	for _, id := range dependents {
+		dep, err := c.runtime.state.Container(id)
+		if err != nil {
+			return err
+		}
+		if dep.config.PIDNsCtr != c.ID() {
+			continue
+		}
+		state, err := dep.State()
+		if err != nil {
+			return err
+		}
+		if state == ContainerStateRunning {
+			return errors.Wrapf(ErrCtrStateInvalid, "container %s shares its PID namespace with running container %s, can't pause", c.ID(), id)
+		}
+	}
+
 	return c.pause()
 }
 
 // Unpause unpauses a container
 func (c *Container) Unpause() error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -522,6 +620,93 @@ func (c *Container) Unpause() error {
 	return c.unpause()
 }
 
+// UpdateBlockIO updates the block IO weight and throttles of the container.
+// Fields of the update that are nil or empty keep their current value. The
+// limits apply immediately to a created, running or paused container, and
+// replace those it was created with when it is next started.
+func (c *Container) UpdateBlockIO(update *spec.LinuxBlockIO) error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	if rootless.IsRootless() {
+		return errors.Wrapf(ErrInvalidArg, "block IO limits can not be set on rootless containers")
+	}
+
+	current := c.state.BlockIO
+	if current == nil && c.config.Spec.Linux != nil && c.config.Spec.Linux.Resources != nil {
+		current = c.config.Spec.Linux.Resources.BlockIO
+	}
+	blockIO := mergeBlockIO(current, update)
+
+	switch c.state.State {
+	case ContainerStateCreated, ContainerStateRunning, ContainerStatePaused:
+		if update != nil {
+			if err := c.applyBlockIO(update); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.state.BlockIO = blockIO
+	return c.save()
+}
+
+// UpdateCPUSet updates the CPUs and memory nodes the container is restricted
+// to. Empty lists keep their current value. The cpuset applies immediately to
+// a created, running or paused container, and replaces the one it was created
+// with when it is next started.
+func (c *Container) UpdateCPUSet(cpus, mems string) error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	if rootless.IsRootless() {
+		return errors.Wrapf(ErrInvalidArg, "cpusets can not be set on rootless containers")
+	}
+	if cpus == "" && mems == "" {
+		return nil
+	}
+
+	live := c.state.State == ContainerStateCreated || c.state.State == ContainerStateRunning || c.state.State == ContainerStatePaused
+	if live {
+		if err := c.runtime.ociRuntime.updateContainerResources(c, &spec.LinuxResources{CPU: &spec.LinuxCPU{Cpus: cpus, Mems: mems}}); err != nil {
+			return err
+		}
+	}
+
+	if cpus != "" {
+		c.state.CPUSetCPUs = cpus
+	}
+	if mems != "" {
+		c.state.CPUSetMems = mems
+	}
+	if live {
+		if err := c.runtime.ociRuntime.setSystemdCpuset(c); err != nil {
+			logrus.Warnf("Failed to set the cpuset of the systemd scope of container %s: %v", c.ID(), err)
+		}
+	}
+	return c.save()
+}
+
 // Export exports a container's root filesystem as a tar archive
 // The archive will be saved as a file at the given path
 func (c *Container) Export(path string) error {
@@ -534,11 +719,19 @@ func (c *Container) Export(path string) error {
 		}
 	}
 
-	return c.export(path)
+	if err := c.export(path); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Export)
+	return nil
 }
 
 // AddArtifact creates and writes to an artifact file for the container
 func (c *Container) AddArtifact(name string, data []byte) error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.valid {
 		return ErrCtrRemoved
 	}
@@ -557,6 +750,10 @@ func (c *Container) GetArtifact(name string) ([]byte, error) {
 
 // RemoveArtifact deletes the specified artifacts file
 func (c *Container) RemoveArtifact(name string) error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.valid {
 		return ErrCtrRemoved
 	}
@@ -597,28 +794,67 @@ func (c *Container) Wait() (int32, error) {
 		return -1, ErrCtrRemoved
 	}
 
-	err := wait.PollImmediateInfinite(1,
-		func() (bool, error) {
-			stopped, err := c.isStopped()
-			if err != nil {
-				return false, err
-			}
-			if !stopped {
-				return false, nil
-			}
-			return true, nil
-		},
-	)
+	// Wake up as soon as conmon writes the exit file or the cgroup of
+	// the container empties, polling only as a fallback
+	watcher, err := c.newExitWatcher()
 	if err != nil {
-		return 0, err
+		logrus.Debugf("Error watching container %s for exit, falling back to polling: %v", c.ID(), err)
+	} else {
+		defer watcher.Close()
+	}
+
+	for {
+		stopped, err := c.isStopped()
+		if err != nil {
+			return 0, err
+		}
+		if stopped {
+			break
+		}
+		watcher = c.waitForExitEvent(watcher)
 	}
 	exitCode := c.state.ExitCode
 	return exitCode, nil
 }
 
+// WaitRemoved blocks until the container is removed, once it is cleaned up
+// and its storage unmounted and removed, and returns the exit code it was last
+// seen stopped with, or -1 if it was removed before it was seen stopped
+func (c *Container) WaitRemoved() (int32, error) {
+	// Removing the container holds its lock until it is fully removed, so
+	// it is gone from the state once the lock is taken
+	watcher, err := c.newExitWatcher()
+	if err != nil {
+		logrus.Debugf("Error watching container %s for exit, falling back to polling: %v", c.ID(), err)
+	} else {
+		defer watcher.Close()
+		// The state database is written as the container is removed
+		if err := watcher.Add(c.runtime.config().StaticDir); err != nil {
+			logrus.Debugf("Not watching the state of container %s: %v", c.ID(), err)
+		}
+	}
+
+	exitCode := int32(-1)
+	for {
+		stopped, err := c.isStopped()
+		if err != nil {
+			switch errors.Cause(err) {
+			case ErrNoSuchCtr, ErrCtrRemoved:
+				return exitCode, nil
+			}
+			return -1, err
+		}
+		if stopped {
+			exitCode = c.state.ExitCode
+		}
+		watcher = c.waitForExitEvent(watcher)
+	}
+}
+
 // Cleanup unmounts all mount points in container and cleans up container storage
 // It also cleans up the network stack
-func (c *Container) Cleanup() error {
+// If the restart policy of the container asks for it, it is then restarted
+func (c *Container) Cleanup(ctx context.Context) error {
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -637,7 +873,51 @@ func (c *Container) Cleanup() error {
 		return errors.Wrapf(ErrCtrStateInvalid, "container %s has active exec sessions, refusing to clean up", c.ID())
 	}
 
-	return c.cleanup()
+	if err := c.cleanup(); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Cleanup)
+
+	if !c.shouldRestart() {
+		return nil
+	}
+	// Wait before restarting the container if its restart policy asks for
+	// it, without keeping it locked so it can be stopped meanwhile
+	delay := c.restartDelay()
+	logrus.Debugf("Restarting container %s in %s", c.ID(), delay)
+	if !c.batched {
+		c.lock.Unlock()
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+	}
+	if !c.batched {
+		c.lock.Lock()
+		if err := c.syncContainer(); err != nil {
+			if errors.Cause(err) == ErrCtrRemoved {
+				return nil
+			}
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return c.handleRestartPolicy(ctx)
+}
+
+// cancelRestart prevents the restart policy of a stopped container from
+// restarting it, as it is stopped by a user while waiting to be restarted,
+// and returns ErrCtrStopped
+func (c *Container) cancelRestart() error {
+	if c.config.RestartPolicy != RestartPolicyNone && !c.state.StoppedByUser {
+		c.state.StoppedByUser = true
+		if err := c.save(); err != nil {
+			return err
+		}
+	}
+	return ErrCtrStopped
 }
 
 // Batch starts a batch operation on the given container
@@ -703,6 +983,7 @@ func (c *Container) Sync() error {
 			if err := c.save(); err != nil {
 				return err
 			}
+			c.newExitEvents(oldState)
 		}
 	}
 
@@ -711,6 +992,10 @@ func (c *Container) Sync() error {
 
 // RestartWithTimeout restarts a running container and takes a given timeout in uint
 func (c *Container) RestartWithTimeout(ctx context.Context, timeout uint) (err error) {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -720,7 +1005,7 @@ func (c *Container) RestartWithTimeout(ctx context.Context, timeout uint) (err e
 		}
 	}
 
-	notRunning, err := c.checkDependenciesRunning()
+	notRunning, err := c.checkDependenciesRunning(ctx)
 	if err != nil {
 		return errors.Wrapf(err, "error checking dependencies for container %s")
 	}
@@ -731,9 +1016,55 @@ func (c *Container) RestartWithTimeout(ctx context.Context, timeout uint) (err e
 	return c.restartWithTimeout(ctx, timeout)
 }
 
+// ReloadNetwork tears down the network configuration of a running container
+// and sets it up again, without restarting the container. The container may
+// be given new addresses, and the firewall rules of its networks are added
+// again. Only network namespaces created by libpod can be reloaded.
+func (c *Container) ReloadNetwork() error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	if c.state.State != ContainerStateRunning && c.state.State != ContainerStatePaused {
+		return errors.Wrapf(ErrCtrStateInvalid, "container %s is not running, can't reload its network", c.ID())
+	}
+	if !c.config.CreateNetNS {
+		return errors.Wrapf(ErrInvalidArg, "container %s network namespace is not managed by libpod", c.ID())
+	}
+	if rootless.IsRootless() {
+		return errors.Wrapf(ErrNotImplemented, "reloading the network of rootless containers")
+	}
+	if c.state.NetNS == nil {
+		return errors.Wrapf(ErrInternal, "container %s has no network namespace", c.ID())
+	}
+
+	if err := c.runtime.reloadNetNS(c); err != nil {
+		// Record that the container lost its network
+		if err2 := c.save(); err2 != nil {
+			logrus.Errorf("Error saving container %s state: %v", c.ID(), err2)
+		}
+		return err
+	}
+
+	return c.save()
+}
+
 // Refresh refreshes a container's state in the database, restarting the
 // container if it is running
 func (c *Container) Refresh(ctx context.Context) error {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return err
+	}
+
 	if !c.batched {
 		c.lock.Lock()
 		defer c.lock.Unlock()
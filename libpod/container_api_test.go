@@ -0,0 +1,82 @@
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitRemoved(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wait")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	exitsDir := filepath.Join(dir, "exits")
+	require.NoError(t, os.Mkdir(exitsDir, 0700))
+	state, err := NewInMemoryState()
+	require.NoError(t, err)
+	ctr, err := getTestCtr1(dir)
+	require.NoError(t, err)
+	ctr.state.State = ContainerStateConfigured
+	ctr.runtime = &Runtime{
+		state:      state,
+		ociRuntime: &OCIRuntime{exitsDir: exitsDir},
+	}
+	ctr.runtime.setConfig(&RuntimeConfig{StaticDir: dir})
+	require.NoError(t, state.AddContainer(ctr))
+
+	done := make(chan int32)
+	go func() {
+		exitCode, err := ctr.WaitRemoved()
+		assert.NoError(t, err)
+		done <- exitCode
+	}()
+
+	// The container is removed with its lock held until its storage is
+	// removed, which the wait must not return before
+	ctr.lock.Lock()
+	require.NoError(t, state.RemoveContainer(ctr))
+	select {
+	case <-done:
+		t.Fatal("the wait returned before the removal completed")
+	case <-time.After(2 * exitPollInterval):
+	}
+	ctr.lock.Unlock()
+
+	select {
+	case exitCode := <-done:
+		// The container was never seen stopped
+		assert.Equal(t, int32(-1), exitCode)
+	case <-time.After(5 * exitPollInterval):
+		t.Fatal("the wait did not return once the container was removed")
+	}
+}
+
+func TestWaitForExitEventFallsBackToPolling(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wait")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ctr, err := getTestCtr1(dir)
+	require.NoError(t, err)
+	ctr.runtime = &Runtime{ociRuntime: &OCIRuntime{exitsDir: dir}}
+
+	watcher, err := fsnotify.NewWatcher()
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	// Errors of the watcher do not fail the wait, which polls from then on
+	go func() {
+		watcher.Errors <- errors.New("queue overflow")
+	}()
+	assert.Nil(t, ctr.waitForExitEvent(watcher))
+
+	start := time.Now()
+	assert.Nil(t, ctr.waitForExitEvent(nil))
+	assert.True(t, time.Since(start) >= exitPollInterval)
+}
@@ -6,7 +6,9 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/pkg/kubeutils"
 	"github.com/containers/libpod/utils"
 	"github.com/docker/docker/pkg/term"
@@ -40,6 +42,29 @@ type AttachStreams struct {
 	// AttachInput is whether to attach to STDIN
 	// If false, stdout will not be attached
 	AttachInput bool
+	// IdleTimeout detaches from the container once no input or output
+	// went through the streams for that long, if not zero. Exec ignores
+	// it: its process has no conmon to keep it running once detached.
+	IdleTimeout time.Duration
+}
+
+// activityReader signals the data read through it on activity, without
+// blocking
+type activityReader struct {
+	io.Reader
+	activity chan<- struct{}
+}
+
+// Read reads from the reader and signals the activity
+func (r activityReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		select {
+		case r.activity <- struct{}{}:
+		default:
+		}
+	}
+	return n, err
 }
 
 // Attach to the given container
@@ -95,33 +120,60 @@ func (c *Container) attachContainerSocket(resize <-chan remotecommand.TerminalSi
 		}
 	}
 
-	receiveStdoutError := make(chan error)
+	activity := make(chan struct{}, 1)
+	var timer *time.Timer
+	var idle <-chan time.Time
+	if streams.IdleTimeout > 0 {
+		timer = time.NewTimer(streams.IdleTimeout)
+		defer timer.Stop()
+		idle = timer.C
+	}
+
+	// The goroutines do not block once the session is detached
+	receiveStdoutError := make(chan error, 1)
 	go func() {
-		receiveStdoutError <- redirectResponseToOutputStreams(streams.OutputStream, streams.ErrorStream, streams.AttachOutput, streams.AttachError, conn)
+		receiveStdoutError <- redirectResponseToOutputStreams(streams.OutputStream, streams.ErrorStream, streams.AttachOutput, streams.AttachError, activityReader{conn, activity})
 	}()
 
-	stdinDone := make(chan error)
+	stdinDone := make(chan error, 1)
 	go func() {
 		var err error
 		if streams.AttachInput {
-			_, err = utils.CopyDetachable(conn, streams.InputStream, detachKeys)
+			_, err = utils.CopyDetachable(conn, activityReader{streams.InputStream, activity}, detachKeys)
 			conn.CloseWrite()
 		}
 		stdinDone <- err
 	}()
 
-	select {
-	case err := <-receiveStdoutError:
-		return err
-	case err := <-stdinDone:
-		if _, ok := err.(utils.DetachError); ok {
+	for {
+		select {
+		case err := <-receiveStdoutError:
+			return err
+		case err := <-stdinDone:
+			if _, ok := err.(utils.DetachError); ok {
+				return nil
+			}
+			if !streams.AttachOutput && !streams.AttachError {
+				return nil
+			}
+			// Wait for the output, which may still stay idle
+			stdinDone = nil
+		case <-activity:
+			if timer != nil {
+				if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(streams.IdleTimeout)
+			}
+		case <-idle:
+			// Closing the attach socket detaches from the container,
+			// which conmon keeps running
+			logrus.Debugf("Detaching from container %s, idle for %s", c.ID(), streams.IdleTimeout)
+			conn.Close()
+			c.writeContainerEvent(events.Detach, map[string]string{"reason": "idle"})
 			return nil
 		}
-		if streams.AttachOutput || streams.AttachError {
-			return <-receiveStdoutError
-		}
 	}
-	return nil
 }
 
 func redirectResponseToOutputStreams(outputStream, errorStream io.Writer, writeOutput, writeError bool, conn io.Reader) error {
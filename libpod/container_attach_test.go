@@ -0,0 +1,91 @@
+package libpod
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containers/libpod/libpod/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nopCloser is a buffer closed with the attach session
+type nopCloser struct {
+	bytes.Buffer
+}
+
+func (*nopCloser) Close() error {
+	return nil
+}
+
+func TestAttachIdleTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "attach")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	eventer, err := events.NewEventer(events.EventerOptions{LogFilePath: filepath.Join(dir, "events.log")})
+	require.NoError(t, err)
+	ctr, err := getTestCtr1(dir)
+	require.NoError(t, err)
+	ctr.config.StaticDir = dir
+	ctr.runtime = &Runtime{
+		ociRuntime: &OCIRuntime{socketsDir: filepath.Join(dir, "socket")},
+		eventer:    eventer,
+	}
+
+	// Stand in for conmon, sending some output then nothing
+	require.NoError(t, os.MkdirAll(filepath.Dir(ctr.AttachSocketPath()), 0700))
+	listener, err := net.ListenUnix("unixpacket", &net.UnixAddr{Name: ctr.AttachSocketPath(), Net: "unixpacket"})
+	require.NoError(t, err)
+	defer listener.Close()
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write(append([]byte{AttachPipeStdout}, "hello"...))
+		io.Copy(ioutil.Discard, conn)
+	}()
+
+	stdin, stdinWriter := io.Pipe()
+	defer stdinWriter.Close()
+	stdout := &nopCloser{}
+	streams := &AttachStreams{
+		OutputStream: stdout,
+		ErrorStream:  stdout,
+		InputStream:  stdin,
+		AttachInput:  true,
+		AttachOutput: true,
+		IdleTimeout:  200 * time.Millisecond,
+	}
+	start := time.Now()
+	require.NoError(t, ctr.attachContainerSocket(nil, nil, streams, false))
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+	assert.Equal(t, "hello", stdout.String())
+	// The connection to conmon is closed
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the attach socket was not closed")
+	}
+
+	options := events.ReadOptions{EventChannel: make(chan *events.Event, 10)}
+	require.NoError(t, eventer.Read(options))
+	var detached []*events.Event
+	for e := range options.EventChannel {
+		if e.Status == events.Detach {
+			detached = append(detached, e)
+		}
+	}
+	require.Len(t, detached, 1)
+	assert.Equal(t, ctr.ID(), detached[0].ID)
+	assert.Equal(t, "idle", detached[0].Attributes["reason"])
+}
@@ -0,0 +1,143 @@
+// +build linux
+
+package libpod
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/libpod/pkg/cgroups"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+// applyBlockIO writes the block IO limits set in update to the cgroup of the
+// container. The OCI runtime only updates the weight of a running container,
+// so the limits are written to the controller files directly.
+func (c *Container) applyBlockIO(update *spec.LinuxBlockIO) error {
+	cgroupPath, err := c.CGroupPath()
+	if err != nil {
+		return err
+	}
+	if cgroups.IsCgroup2UnifiedMode() {
+		err = writeCgroup2BlockIO(cgroups.V2Dir(cgroupPath), update)
+	} else {
+		err = writeCgroup1BlockIO(cgroups.V1Dir("blkio", cgroupPath), update)
+	}
+	return errors.Wrapf(err, "error updating the block IO limits of container %s", c.ID())
+}
+
+// writeCgroup1BlockIO writes block IO limits to the files of the blkio
+// controller of the cgroup v1 directory dir
+func writeCgroup1BlockIO(dir string, update *spec.LinuxBlockIO) error {
+	if update.Weight != nil {
+		if err := writeBlockIOFile(dir, "blkio.weight", "weight", fmt.Sprintf("%d", *update.Weight)); err != nil {
+			return err
+		}
+	}
+	if update.LeafWeight != nil {
+		if err := writeBlockIOFile(dir, "blkio.leaf_weight", "leaf weight", fmt.Sprintf("%d", *update.LeafWeight)); err != nil {
+			return err
+		}
+	}
+	for _, d := range update.WeightDevice {
+		if d.Weight != nil {
+			if err := writeBlockIOFile(dir, "blkio.weight_device", "device weight", fmt.Sprintf("%d:%d %d", d.Major, d.Minor, *d.Weight)); err != nil {
+				return err
+			}
+		}
+		if d.LeafWeight != nil {
+			if err := writeBlockIOFile(dir, "blkio.leaf_weight_device", "device leaf weight", fmt.Sprintf("%d:%d %d", d.Major, d.Minor, *d.LeafWeight)); err != nil {
+				return err
+			}
+		}
+	}
+	throttles := []struct {
+		file, name string
+		devices    []spec.LinuxThrottleDevice
+	}{
+		{"blkio.throttle.read_bps_device", "read bps limit", update.ThrottleReadBpsDevice},
+		{"blkio.throttle.write_bps_device", "write bps limit", update.ThrottleWriteBpsDevice},
+		{"blkio.throttle.read_iops_device", "read iops limit", update.ThrottleReadIOPSDevice},
+		{"blkio.throttle.write_iops_device", "write iops limit", update.ThrottleWriteIOPSDevice},
+	}
+	for _, throttle := range throttles {
+		for _, d := range throttle.devices {
+			if err := writeBlockIOFile(dir, throttle.file, throttle.name, fmt.Sprintf("%d:%d %d", d.Major, d.Minor, d.Rate)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeCgroup2BlockIO writes block IO limits to the files of the io
+// controller of the cgroup v2 directory dir. Weights are converted from the
+// blkio range as the OCI runtimes do, leaf weights have no equivalent.
+func writeCgroup2BlockIO(dir string, update *spec.LinuxBlockIO) error {
+	if update.LeafWeight != nil {
+		return errors.Wrapf(ErrInvalidArg, "leaf weights are not supported by the cgroup v2 io controller")
+	}
+	for _, d := range update.WeightDevice {
+		if d.LeafWeight != nil {
+			return errors.Wrapf(ErrInvalidArg, "leaf weights are not supported by the cgroup v2 io controller")
+		}
+	}
+	if update.Weight != nil {
+		if err := writeBlockIOFile(dir, "io.weight", "weight", fmt.Sprintf("default %d", cgroup2IOWeight(*update.Weight))); err != nil {
+			return err
+		}
+	}
+	for _, d := range update.WeightDevice {
+		if d.Weight != nil {
+			if err := writeBlockIOFile(dir, "io.weight", "device weight", fmt.Sprintf("%d:%d %d", d.Major, d.Minor, cgroup2IOWeight(*d.Weight))); err != nil {
+				return err
+			}
+		}
+	}
+	throttles := []struct {
+		key     string
+		devices []spec.LinuxThrottleDevice
+	}{
+		{"rbps", update.ThrottleReadBpsDevice},
+		{"wbps", update.ThrottleWriteBpsDevice},
+		{"riops", update.ThrottleReadIOPSDevice},
+		{"wiops", update.ThrottleWriteIOPSDevice},
+	}
+	for _, throttle := range throttles {
+		for _, d := range throttle.devices {
+			rate := "max"
+			if d.Rate > 0 {
+				rate = fmt.Sprintf("%d", d.Rate)
+			}
+			if err := writeBlockIOFile(dir, "io.max", "device limit", fmt.Sprintf("%d:%d %s=%s", d.Major, d.Minor, throttle.key, rate)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cgroup2IOWeight converts a blkio weight, from 10 to 1000, to an io.weight,
+// from 1 to 10000
+func cgroup2IOWeight(weight uint16) uint64 {
+	if weight < 10 {
+		return 1
+	}
+	return 1 + (uint64(weight)-10)*9999/990
+}
+
+// writeBlockIOFile writes a value to a block IO controller file of a cgroup,
+// reporting the limit named as not supported if the kernel lacks the file
+func writeBlockIOFile(dir, file, name, value string) error {
+	path := filepath.Join(dir, file)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return errors.Wrapf(ErrInvalidArg, "the kernel does not support the block IO %s, %s is missing", name, path)
+	}
+	if err := ioutil.WriteFile(path, []byte(value), 0); err != nil {
+		return errors.Wrapf(err, "error setting the block IO %s in %s", name, path)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// +build linux
+
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBlockIO(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blkio")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	readFile := func(name string) string {
+		content, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(content)
+	}
+	weight := uint16(500)
+	throttle := spec.LinuxThrottleDevice{Rate: 1024}
+	throttle.Major = 8
+	update := &spec.LinuxBlockIO{
+		Weight:                 &weight,
+		ThrottleWriteBpsDevice: []spec.LinuxThrottleDevice{throttle},
+	}
+
+	// Limits whose files are missing are not supported by the kernel
+	err = writeCgroup1BlockIO(dir, update)
+	assert.Equal(t, ErrInvalidArg, errors.Cause(err))
+
+	for _, name := range []string{"blkio.weight", "blkio.throttle.write_bps_device", "io.weight", "io.max"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+	require.NoError(t, writeCgroup1BlockIO(dir, update))
+	assert.Equal(t, "500", readFile("blkio.weight"))
+	assert.Equal(t, "8:0 1024", readFile("blkio.throttle.write_bps_device"))
+
+	require.NoError(t, writeCgroup2BlockIO(dir, update))
+	assert.Equal(t, "default 4950", readFile("io.weight"))
+	assert.Equal(t, "8:0 wbps=1024", readFile("io.max"))
+
+	update.LeafWeight = &weight
+	err = writeCgroup2BlockIO(dir, update)
+	assert.Equal(t, ErrInvalidArg, errors.Cause(err))
+}
+
+func TestCgroup2IOWeight(t *testing.T) {
+	assert.Equal(t, uint64(1), cgroup2IOWeight(10))
+	assert.Equal(t, uint64(10000), cgroup2IOWeight(1000))
+}
@@ -0,0 +1,9 @@
+// +build !linux
+
+package libpod
+
+import spec "github.com/opencontainers/runtime-spec/specs-go"
+
+func (c *Container) applyBlockIO(update *spec.LinuxBlockIO) error {
+	return ErrOSNotSupported
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	is "github.com/containers/image/storage"
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/libpod/image"
 	"github.com/pkg/errors"
 	"github.com/projectatomic/buildah"
@@ -29,7 +30,15 @@ var ChangeCmds = []string{"CMD", "ENTRYPOINT", "ENV", "EXPOSE", "LABEL", "ONBUIL
 
 // Commit commits the changes between a container and its image, creating a new
 // image
+// The destination is an image name in local storage, or an image name with the
+// prefix of another transport, such as dir:, oci:, docker-archive: or
+// containers-storage: with the location of another store. The committed image
+// is only returned if it was written to local storage; otherwise it is nil.
 func (c *Container) Commit(ctx context.Context, destImage string, options ContainerCommitOptions) (*image.Image, error) {
+	if err := c.runtime.checkReadOnly(); err != nil {
+		return nil, err
+	}
+
 	var (
 		isEnvCleared, isLabelCleared, isExposeCleared, isVolumeCleared bool
 	)
@@ -161,6 +170,26 @@ func (c *Container) Commit(ctx context.Context, destImage string, options Contai
 			importBuilder.SetWorkDir(splitChange[1])
 		}
 	}
+	// Images are committed to local storage unless the destination names
+	// another transport, or the storage of another user
+	destRef, err := image.ParseTransportReference(destImage)
+	if err != nil {
+		return nil, err
+	}
+	if destRef != nil && destRef.Transport().Name() == is.Transport.Name() {
+		if name := strings.SplitN(destImage, ":", 2)[1]; !strings.HasPrefix(name, "[") {
+			destImage = name
+			destRef = nil
+		}
+	}
+	if destRef != nil {
+		if _, err := importBuilder.Commit(ctx, destRef, commitOptions); err != nil {
+			return nil, err
+		}
+		c.newContainerEvent(events.Commit)
+		return nil, nil
+	}
+
 	candidates, err := util.ResolveName(destImage, "", sc, c.runtime.store)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error resolving name %q", destImage)
@@ -176,5 +205,6 @@ func (c *Container) Commit(ctx context.Context, destImage string, options Contai
 	if err != nil {
 		return nil, err
 	}
+	c.newContainerEvent(events.Commit)
 	return c.runtime.imageRuntime.NewFromLocal(id)
 }
@@ -6,6 +6,8 @@ import (
 	json "encoding/json"
 	types "github.com/containernetworking/cni/pkg/types"
 	current "github.com/containernetworking/cni/pkg/types/current"
+	manifest "github.com/containers/image/manifest"
+	inspect "github.com/containers/libpod/pkg/inspect"
 	storage "github.com/containers/storage"
 	idtools "github.com/containers/storage/pkg/idtools"
 	ocicni "github.com/cri-o/ocicni/pkg/ocicni"
@@ -15,6 +17,7 @@ import (
 	specs_go "github.com/opencontainers/runtime-spec/specs-go"
 	net "net"
 	os "os"
+	time "time"
 )
 
 // suppress unused package warning
@@ -198,6 +201,40 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodLibpod(in *jlexer.Lexer, out
 				}
 				in.Delim('}')
 			}
+		case "blockIO":
+			if in.IsNull() {
+				in.Skip()
+				out.BlockIO = nil
+			} else {
+				if out.BlockIO == nil {
+					out.BlockIO = new(specs_go.LinuxBlockIO)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(in, &*out.BlockIO)
+			}
+		case "cpusetCpus":
+			out.CPUSetCPUs = string(in.String())
+		case "cpusetMems":
+			out.CPUSetMems = string(in.String())
+		case "healthCheck":
+			if in.IsNull() {
+				in.Skip()
+				out.HealthCheck = nil
+			} else {
+				if out.HealthCheck == nil {
+					out.HealthCheck = new(inspect.HealthCheckResults)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodPkgInspect(in, &*out.HealthCheck)
+			}
+		case "startupHealthCheckPassed":
+			out.StartupHealthCheckPassed = bool(in.Bool())
+		case "restartCount":
+			out.RestartCount = uint(in.Uint())
+		case "stoppedByUser":
+			out.StoppedByUser = bool(in.Bool())
+		case "systemdService":
+			out.SystemdService = string(in.String())
+		case "pidsLimitHits":
+			out.PidsLimitHits = uint64(in.Uint64())
 		default:
 			in.SkipRecursive()
 		}
@@ -462,6 +499,96 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodLibpod(out *jwriter.Writer,
 			out.RawByte('}')
 		}
 	}
+	if in.BlockIO != nil {
+		const prefix string = ",\"blockIO\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(out, *in.BlockIO)
+	}
+	if in.CPUSetCPUs != "" {
+		const prefix string = ",\"cpusetCpus\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.CPUSetCPUs))
+	}
+	if in.CPUSetMems != "" {
+		const prefix string = ",\"cpusetMems\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.CPUSetMems))
+	}
+	if in.HealthCheck != nil {
+		const prefix string = ",\"healthCheck\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodPkgInspect(out, *in.HealthCheck)
+	}
+	if in.StartupHealthCheckPassed {
+		const prefix string = ",\"startupHealthCheckPassed\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.StartupHealthCheckPassed))
+	}
+	if in.RestartCount != 0 {
+		const prefix string = ",\"restartCount\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint(uint(in.RestartCount))
+	}
+	if in.StoppedByUser {
+		const prefix string = ",\"stoppedByUser\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.StoppedByUser))
+	}
+	if in.SystemdService != "" {
+		const prefix string = ",\"systemdService\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.SystemdService))
+	}
+	if in.PidsLimitHits != 0 {
+		const prefix string = ",\"pidsLimitHits\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(in.PidsLimitHits))
+	}
 	out.RawByte('}')
 }
 
@@ -488,7 +615,7 @@ func (v *containerState) UnmarshalJSON(data []byte) error {
 func (v *containerState) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson1dbef17bDecodeGithubComContainersLibpodLibpod(l, v)
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(in *jlexer.Lexer, out *specs_go.Hook) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodPkgInspect(in *jlexer.Lexer, out *inspect.HealthCheckResults) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -507,64 +634,33 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "path":
-			out.Path = string(in.String())
-		case "args":
-			if in.IsNull() {
-				in.Skip()
-				out.Args = nil
-			} else {
-				in.Delim('[')
-				if out.Args == nil {
-					if !in.IsDelim(']') {
-						out.Args = make([]string, 0, 4)
-					} else {
-						out.Args = []string{}
-					}
-				} else {
-					out.Args = (out.Args)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v13 string
-					v13 = string(in.String())
-					out.Args = append(out.Args, v13)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "env":
+		case "Status":
+			out.Status = string(in.String())
+		case "FailingStreak":
+			out.FailingStreak = int(in.Int())
+		case "Log":
 			if in.IsNull() {
 				in.Skip()
-				out.Env = nil
+				out.Log = nil
 			} else {
 				in.Delim('[')
-				if out.Env == nil {
+				if out.Log == nil {
 					if !in.IsDelim(']') {
-						out.Env = make([]string, 0, 4)
+						out.Log = make([]inspect.HealthCheckLog, 0, 1)
 					} else {
-						out.Env = []string{}
+						out.Log = []inspect.HealthCheckLog{}
 					}
 				} else {
-					out.Env = (out.Env)[:0]
+					out.Log = (out.Log)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v14 string
-					v14 = string(in.String())
-					out.Env = append(out.Env, v14)
+					var v13 inspect.HealthCheckLog
+					easyjson1dbef17bDecodeGithubComContainersLibpodPkgInspect1(in, &v13)
+					out.Log = append(out.Log, v13)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "timeout":
-			if in.IsNull() {
-				in.Skip()
-				out.Timeout = nil
-			} else {
-				if out.Timeout == nil {
-					out.Timeout = new(int)
-				}
-				*out.Timeout = int(in.Int())
-			}
 		default:
 			in.SkipRecursive()
 		}
@@ -575,71 +671,54 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(out *jwriter.Writer, in specs_go.Hook) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodPkgInspect(out *jwriter.Writer, in inspect.HealthCheckResults) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"path\":"
+		const prefix string = ",\"Status\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Path))
+		out.String(string(in.Status))
 	}
-	if len(in.Args) != 0 {
-		const prefix string = ",\"args\":"
+	{
+		const prefix string = ",\"FailingStreak\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v15, v16 := range in.Args {
-				if v15 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v16))
-			}
-			out.RawByte(']')
-		}
+		out.Int(int(in.FailingStreak))
 	}
-	if len(in.Env) != 0 {
-		const prefix string = ",\"env\":"
+	{
+		const prefix string = ",\"Log\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
+		if in.Log == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
 			out.RawByte('[')
-			for v17, v18 := range in.Env {
-				if v17 > 0 {
+			for v14, v15 := range in.Log {
+				if v14 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v18))
+				easyjson1dbef17bEncodeGithubComContainersLibpodPkgInspect1(out, v15)
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.Timeout != nil {
-		const prefix string = ",\"timeout\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int(int(*in.Timeout))
-	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent(in *jlexer.Lexer, out *current.Result) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodPkgInspect1(in *jlexer.Lexer, out *inspect.HealthCheckLog) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -658,107 +737,18 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetw
 			continue
 		}
 		switch key {
-		case "cniVersion":
-			out.CNIVersion = string(in.String())
-		case "interfaces":
-			if in.IsNull() {
-				in.Skip()
-				out.Interfaces = nil
-			} else {
-				in.Delim('[')
-				if out.Interfaces == nil {
-					if !in.IsDelim(']') {
-						out.Interfaces = make([]*current.Interface, 0, 8)
-					} else {
-						out.Interfaces = []*current.Interface{}
-					}
-				} else {
-					out.Interfaces = (out.Interfaces)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v19 *current.Interface
-					if in.IsNull() {
-						in.Skip()
-						v19 = nil
-					} else {
-						if v19 == nil {
-							v19 = new(current.Interface)
-						}
-						easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent1(in, &*v19)
-					}
-					out.Interfaces = append(out.Interfaces, v19)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "ips":
-			if in.IsNull() {
-				in.Skip()
-				out.IPs = nil
-			} else {
-				in.Delim('[')
-				if out.IPs == nil {
-					if !in.IsDelim(']') {
-						out.IPs = make([]*current.IPConfig, 0, 8)
-					} else {
-						out.IPs = []*current.IPConfig{}
-					}
-				} else {
-					out.IPs = (out.IPs)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v20 *current.IPConfig
-					if in.IsNull() {
-						in.Skip()
-						v20 = nil
-					} else {
-						if v20 == nil {
-							v20 = new(current.IPConfig)
-						}
-						if data := in.Raw(); in.Ok() {
-							in.AddError((*v20).UnmarshalJSON(data))
-						}
-					}
-					out.IPs = append(out.IPs, v20)
-					in.WantComma()
-				}
-				in.Delim(']')
+		case "Start":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Start).UnmarshalJSON(data))
 			}
-		case "routes":
-			if in.IsNull() {
-				in.Skip()
-				out.Routes = nil
-			} else {
-				in.Delim('[')
-				if out.Routes == nil {
-					if !in.IsDelim(']') {
-						out.Routes = make([]*types.Route, 0, 8)
-					} else {
-						out.Routes = []*types.Route{}
-					}
-				} else {
-					out.Routes = (out.Routes)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v21 *types.Route
-					if in.IsNull() {
-						in.Skip()
-						v21 = nil
-					} else {
-						if v21 == nil {
-							v21 = new(types.Route)
-						}
-						if data := in.Raw(); in.Ok() {
-							in.AddError((*v21).UnmarshalJSON(data))
-						}
-					}
-					out.Routes = append(out.Routes, v21)
-					in.WantComma()
-				}
-				in.Delim(']')
+		case "End":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.End).UnmarshalJSON(data))
 			}
-		case "dns":
-			easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypes(in, &out.DNS)
+		case "ExitCode":
+			out.ExitCode = int(in.Int())
+		case "Output":
+			out.Output = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -769,102 +759,53 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetw
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent(out *jwriter.Writer, in current.Result) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodPkgInspect1(out *jwriter.Writer, in inspect.HealthCheckLog) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.CNIVersion != "" {
-		const prefix string = ",\"cniVersion\":"
+	{
+		const prefix string = ",\"Start\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.CNIVersion))
+		out.Raw((in.Start).MarshalJSON())
 	}
-	if len(in.Interfaces) != 0 {
-		const prefix string = ",\"interfaces\":"
+	{
+		const prefix string = ",\"End\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v22, v23 := range in.Interfaces {
-				if v22 > 0 {
-					out.RawByte(',')
-				}
-				if v23 == nil {
-					out.RawString("null")
-				} else {
-					easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent1(out, *v23)
-				}
-			}
-			out.RawByte(']')
+		out.Raw((in.End).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"ExitCode\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
+		out.Int(int(in.ExitCode))
 	}
-	if len(in.IPs) != 0 {
-		const prefix string = ",\"ips\":"
+	{
+		const prefix string = ",\"Output\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v24, v25 := range in.IPs {
-				if v24 > 0 {
-					out.RawByte(',')
-				}
-				if v25 == nil {
-					out.RawString("null")
-				} else {
-					out.Raw((*v25).MarshalJSON())
-				}
-			}
-			out.RawByte(']')
-		}
-	}
-	if len(in.Routes) != 0 {
-		const prefix string = ",\"routes\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('[')
-			for v26, v27 := range in.Routes {
-				if v26 > 0 {
-					out.RawByte(',')
-				}
-				if v27 == nil {
-					out.RawString("null")
-				} else {
-					out.Raw((*v27).MarshalJSON())
-				}
-			}
-			out.RawByte(']')
-		}
-	}
-	if true {
-		const prefix string = ",\"dns\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypes(out, in.DNS)
+		out.String(string(in.Output))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypes(in *jlexer.Lexer, out *types.DNS) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(in *jlexer.Lexer, out *specs_go.LinuxBlockIO) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -883,73 +824,137 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetw
 			continue
 		}
 		switch key {
-		case "nameservers":
+		case "weight":
 			if in.IsNull() {
 				in.Skip()
-				out.Nameservers = nil
+				out.Weight = nil
+			} else {
+				if out.Weight == nil {
+					out.Weight = new(uint16)
+				}
+				*out.Weight = uint16(in.Uint16())
+			}
+		case "leafWeight":
+			if in.IsNull() {
+				in.Skip()
+				out.LeafWeight = nil
+			} else {
+				if out.LeafWeight == nil {
+					out.LeafWeight = new(uint16)
+				}
+				*out.LeafWeight = uint16(in.Uint16())
+			}
+		case "weightDevice":
+			if in.IsNull() {
+				in.Skip()
+				out.WeightDevice = nil
 			} else {
 				in.Delim('[')
-				if out.Nameservers == nil {
+				if out.WeightDevice == nil {
 					if !in.IsDelim(']') {
-						out.Nameservers = make([]string, 0, 4)
+						out.WeightDevice = make([]specs_go.LinuxWeightDevice, 0, 2)
 					} else {
-						out.Nameservers = []string{}
+						out.WeightDevice = []specs_go.LinuxWeightDevice{}
 					}
 				} else {
-					out.Nameservers = (out.Nameservers)[:0]
+					out.WeightDevice = (out.WeightDevice)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v28 string
-					v28 = string(in.String())
-					out.Nameservers = append(out.Nameservers, v28)
+					var v16 specs_go.LinuxWeightDevice
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo2(in, &v16)
+					out.WeightDevice = append(out.WeightDevice, v16)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "domain":
-			out.Domain = string(in.String())
-		case "search":
+		case "throttleReadBpsDevice":
 			if in.IsNull() {
 				in.Skip()
-				out.Search = nil
+				out.ThrottleReadBpsDevice = nil
 			} else {
 				in.Delim('[')
-				if out.Search == nil {
+				if out.ThrottleReadBpsDevice == nil {
 					if !in.IsDelim(']') {
-						out.Search = make([]string, 0, 4)
+						out.ThrottleReadBpsDevice = make([]specs_go.LinuxThrottleDevice, 0, 2)
 					} else {
-						out.Search = []string{}
+						out.ThrottleReadBpsDevice = []specs_go.LinuxThrottleDevice{}
 					}
 				} else {
-					out.Search = (out.Search)[:0]
+					out.ThrottleReadBpsDevice = (out.ThrottleReadBpsDevice)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v29 string
-					v29 = string(in.String())
-					out.Search = append(out.Search, v29)
+					var v17 specs_go.LinuxThrottleDevice
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(in, &v17)
+					out.ThrottleReadBpsDevice = append(out.ThrottleReadBpsDevice, v17)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "options":
+		case "throttleWriteBpsDevice":
 			if in.IsNull() {
 				in.Skip()
-				out.Options = nil
+				out.ThrottleWriteBpsDevice = nil
 			} else {
 				in.Delim('[')
-				if out.Options == nil {
+				if out.ThrottleWriteBpsDevice == nil {
 					if !in.IsDelim(']') {
-						out.Options = make([]string, 0, 4)
+						out.ThrottleWriteBpsDevice = make([]specs_go.LinuxThrottleDevice, 0, 2)
 					} else {
-						out.Options = []string{}
+						out.ThrottleWriteBpsDevice = []specs_go.LinuxThrottleDevice{}
 					}
 				} else {
-					out.Options = (out.Options)[:0]
+					out.ThrottleWriteBpsDevice = (out.ThrottleWriteBpsDevice)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v18 specs_go.LinuxThrottleDevice
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(in, &v18)
+					out.ThrottleWriteBpsDevice = append(out.ThrottleWriteBpsDevice, v18)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "throttleReadIOPSDevice":
+			if in.IsNull() {
+				in.Skip()
+				out.ThrottleReadIOPSDevice = nil
+			} else {
+				in.Delim('[')
+				if out.ThrottleReadIOPSDevice == nil {
+					if !in.IsDelim(']') {
+						out.ThrottleReadIOPSDevice = make([]specs_go.LinuxThrottleDevice, 0, 2)
+					} else {
+						out.ThrottleReadIOPSDevice = []specs_go.LinuxThrottleDevice{}
+					}
+				} else {
+					out.ThrottleReadIOPSDevice = (out.ThrottleReadIOPSDevice)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 specs_go.LinuxThrottleDevice
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(in, &v19)
+					out.ThrottleReadIOPSDevice = append(out.ThrottleReadIOPSDevice, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "throttleWriteIOPSDevice":
+			if in.IsNull() {
+				in.Skip()
+				out.ThrottleWriteIOPSDevice = nil
+			} else {
+				in.Delim('[')
+				if out.ThrottleWriteIOPSDevice == nil {
+					if !in.IsDelim(']') {
+						out.ThrottleWriteIOPSDevice = make([]specs_go.LinuxThrottleDevice, 0, 2)
+					} else {
+						out.ThrottleWriteIOPSDevice = []specs_go.LinuxThrottleDevice{}
+					}
+				} else {
+					out.ThrottleWriteIOPSDevice = (out.ThrottleWriteIOPSDevice)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v30 string
-					v30 = string(in.String())
-					out.Options = append(out.Options, v30)
+					var v20 specs_go.LinuxThrottleDevice
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(in, &v20)
+					out.ThrottleWriteIOPSDevice = append(out.ThrottleWriteIOPSDevice, v20)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -964,12 +969,32 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetw
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypes(out *jwriter.Writer, in types.DNS) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(out *jwriter.Writer, in specs_go.LinuxBlockIO) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if len(in.Nameservers) != 0 {
-		const prefix string = ",\"nameservers\":"
+	if in.Weight != nil {
+		const prefix string = ",\"weight\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint16(uint16(*in.Weight))
+	}
+	if in.LeafWeight != nil {
+		const prefix string = ",\"leafWeight\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint16(uint16(*in.LeafWeight))
+	}
+	if len(in.WeightDevice) != 0 {
+		const prefix string = ",\"weightDevice\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -978,27 +1003,36 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetw
 		}
 		{
 			out.RawByte('[')
-			for v31, v32 := range in.Nameservers {
-				if v31 > 0 {
+			for v21, v22 := range in.WeightDevice {
+				if v21 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v32))
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo2(out, v22)
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.Domain != "" {
-		const prefix string = ",\"domain\":"
+	if len(in.ThrottleReadBpsDevice) != 0 {
+		const prefix string = ",\"throttleReadBpsDevice\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Domain))
+		{
+			out.RawByte('[')
+			for v23, v24 := range in.ThrottleReadBpsDevice {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(out, v24)
+			}
+			out.RawByte(']')
+		}
 	}
-	if len(in.Search) != 0 {
-		const prefix string = ",\"search\":"
+	if len(in.ThrottleWriteBpsDevice) != 0 {
+		const prefix string = ",\"throttleWriteBpsDevice\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -1007,17 +1041,17 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetw
 		}
 		{
 			out.RawByte('[')
-			for v33, v34 := range in.Search {
-				if v33 > 0 {
+			for v25, v26 := range in.ThrottleWriteBpsDevice {
+				if v25 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v34))
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(out, v26)
 			}
 			out.RawByte(']')
 		}
 	}
-	if len(in.Options) != 0 {
-		const prefix string = ",\"options\":"
+	if len(in.ThrottleReadIOPSDevice) != 0 {
+		const prefix string = ",\"throttleReadIOPSDevice\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -1026,18 +1060,37 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetw
 		}
 		{
 			out.RawByte('[')
-			for v35, v36 := range in.Options {
-				if v35 > 0 {
+			for v27, v28 := range in.ThrottleReadIOPSDevice {
+				if v27 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v36))
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(out, v28)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.ThrottleWriteIOPSDevice) != 0 {
+		const prefix string = ",\"throttleWriteIOPSDevice\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v29, v30 := range in.ThrottleWriteIOPSDevice {
+				if v29 > 0 {
+					out.RawByte(',')
+				}
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(out, v30)
 			}
 			out.RawByte(']')
 		}
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent1(in *jlexer.Lexer, out *current.Interface) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(in *jlexer.Lexer, out *specs_go.LinuxThrottleDevice) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1056,12 +1109,12 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetw
 			continue
 		}
 		switch key {
-		case "name":
-			out.Name = string(in.String())
-		case "mac":
-			out.Mac = string(in.String())
-		case "sandbox":
-			out.Sandbox = string(in.String())
+		case "rate":
+			out.Rate = uint64(in.Uint64())
+		case "major":
+			out.Major = int64(in.Int64())
+		case "minor":
+			out.Minor = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -1072,43 +1125,43 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetw
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent1(out *jwriter.Writer, in current.Interface) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(out *jwriter.Writer, in specs_go.LinuxThrottleDevice) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"name\":"
+		const prefix string = ",\"rate\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Name))
+		out.Uint64(uint64(in.Rate))
 	}
-	if in.Mac != "" {
-		const prefix string = ",\"mac\":"
+	{
+		const prefix string = ",\"major\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Mac))
+		out.Int64(int64(in.Major))
 	}
-	if in.Sandbox != "" {
-		const prefix string = ",\"sandbox\":"
+	{
+		const prefix string = ",\"minor\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Sandbox))
+		out.Int64(int64(in.Minor))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodLibpod1(in *jlexer.Lexer, out *ExecSession) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo2(in *jlexer.Lexer, out *specs_go.LinuxWeightDevice) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1127,33 +1180,30 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodLibpod1(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = string(in.String())
-		case "command":
+		case "weight":
 			if in.IsNull() {
 				in.Skip()
-				out.Command = nil
+				out.Weight = nil
 			} else {
-				in.Delim('[')
-				if out.Command == nil {
-					if !in.IsDelim(']') {
-						out.Command = make([]string, 0, 4)
-					} else {
-						out.Command = []string{}
-					}
-				} else {
-					out.Command = (out.Command)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v37 string
-					v37 = string(in.String())
-					out.Command = append(out.Command, v37)
-					in.WantComma()
+				if out.Weight == nil {
+					out.Weight = new(uint16)
 				}
-				in.Delim(']')
+				*out.Weight = uint16(in.Uint16())
 			}
-		case "pid":
-			out.PID = int(in.Int())
+		case "leafWeight":
+			if in.IsNull() {
+				in.Skip()
+				out.LeafWeight = nil
+			} else {
+				if out.LeafWeight == nil {
+					out.LeafWeight = new(uint16)
+				}
+				*out.LeafWeight = uint16(in.Uint16())
+			}
+		case "major":
+			out.Major = int64(in.Int64())
+		case "minor":
+			out.Minor = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -1164,78 +1214,53 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodLibpod1(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodLibpod1(out *jwriter.Writer, in ExecSession) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo2(out *jwriter.Writer, in specs_go.LinuxWeightDevice) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"id\":"
+	if in.Weight != nil {
+		const prefix string = ",\"weight\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.ID))
+		out.Uint16(uint16(*in.Weight))
 	}
-	{
-		const prefix string = ",\"command\":"
+	if in.LeafWeight != nil {
+		const prefix string = ",\"leafWeight\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if in.Command == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
+		out.Uint16(uint16(*in.LeafWeight))
+	}
+	{
+		const prefix string = ",\"major\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
 		} else {
-			out.RawByte('[')
-			for v38, v39 := range in.Command {
-				if v38 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v39))
-			}
-			out.RawByte(']')
+			out.RawString(prefix)
 		}
+		out.Int64(int64(in.Major))
 	}
 	{
-		const prefix string = ",\"pid\":"
+		const prefix string = ",\"minor\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int(int(in.PID))
+		out.Int64(int64(in.Minor))
 	}
 	out.RawByte('}')
 }
-
-// MarshalJSON supports json.Marshaler interface
-func (v ExecSession) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjson1dbef17bEncodeGithubComContainersLibpodLibpod1(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ExecSession) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson1dbef17bEncodeGithubComContainersLibpodLibpod1(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *ExecSession) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjson1dbef17bDecodeGithubComContainersLibpodLibpod1(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ExecSession) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson1dbef17bDecodeGithubComContainersLibpodLibpod1(l, v)
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodLibpod2(in *jlexer.Lexer, out *ContainerConfig) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(in *jlexer.Lexer, out *specs_go.Hook) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1254,428 +1279,453 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodLibpod2(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "spec":
-			if in.IsNull() {
-				in.Skip()
-				out.Spec = nil
-			} else {
-				if out.Spec == nil {
-					out.Spec = new(specs_go.Spec)
-				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(in, &*out.Spec)
-			}
-		case "id":
-			out.ID = string(in.String())
-		case "name":
-			out.Name = string(in.String())
-		case "pod":
-			out.Pod = string(in.String())
-		case "namespace":
-			out.Namespace = string(in.String())
-		case "idMappingsOptions":
-			easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStorage(in, &out.IDMappings)
-		case "rootfsImageID":
-			out.RootfsImageID = string(in.String())
-		case "rootfsImageName":
-			out.RootfsImageName = string(in.String())
-		case "rootfs":
-			out.Rootfs = string(in.String())
-		case "imageVolumes":
-			out.ImageVolumes = bool(in.Bool())
-		case "ShmDir":
-			out.ShmDir = string(in.String())
-		case "shmSize":
-			out.ShmSize = int64(in.Int64())
-		case "staticDir":
-			out.StaticDir = string(in.String())
-		case "mounts":
+		case "path":
+			out.Path = string(in.String())
+		case "args":
 			if in.IsNull() {
 				in.Skip()
-				out.Mounts = nil
+				out.Args = nil
 			} else {
 				in.Delim('[')
-				if out.Mounts == nil {
+				if out.Args == nil {
 					if !in.IsDelim(']') {
-						out.Mounts = make([]string, 0, 4)
+						out.Args = make([]string, 0, 4)
 					} else {
-						out.Mounts = []string{}
+						out.Args = []string{}
 					}
 				} else {
-					out.Mounts = (out.Mounts)[:0]
+					out.Args = (out.Args)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v40 string
-					v40 = string(in.String())
-					out.Mounts = append(out.Mounts, v40)
+					var v31 string
+					v31 = string(in.String())
+					out.Args = append(out.Args, v31)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "privileged":
-			out.Privileged = bool(in.Bool())
-		case "ProcessLabel":
-			out.ProcessLabel = string(in.String())
-		case "MountLabel":
-			out.MountLabel = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "groups":
+		case "env":
 			if in.IsNull() {
 				in.Skip()
-				out.Groups = nil
+				out.Env = nil
 			} else {
 				in.Delim('[')
-				if out.Groups == nil {
+				if out.Env == nil {
 					if !in.IsDelim(']') {
-						out.Groups = make([]string, 0, 4)
+						out.Env = make([]string, 0, 4)
 					} else {
-						out.Groups = []string{}
+						out.Env = []string{}
 					}
 				} else {
-					out.Groups = (out.Groups)[:0]
+					out.Env = (out.Env)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v41 string
-					v41 = string(in.String())
-					out.Groups = append(out.Groups, v41)
+					var v32 string
+					v32 = string(in.String())
+					out.Env = append(out.Env, v32)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "ipcNsCtr":
-			out.IPCNsCtr = string(in.String())
-		case "mountNsCtr":
-			out.MountNsCtr = string(in.String())
-		case "netNsCtr":
-			out.NetNsCtr = string(in.String())
-		case "pidNsCtr":
-			out.PIDNsCtr = string(in.String())
-		case "userNsCtr":
-			out.UserNsCtr = string(in.String())
-		case "utsNsCtr":
-			out.UTSNsCtr = string(in.String())
-		case "cgroupNsCtr":
-			out.CgroupNsCtr = string(in.String())
-		case "Dependencies":
+		case "timeout":
 			if in.IsNull() {
 				in.Skip()
-				out.Dependencies = nil
+				out.Timeout = nil
 			} else {
-				in.Delim('[')
-				if out.Dependencies == nil {
-					if !in.IsDelim(']') {
-						out.Dependencies = make([]string, 0, 4)
-					} else {
-						out.Dependencies = []string{}
-					}
-				} else {
-					out.Dependencies = (out.Dependencies)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v42 string
-					v42 = string(in.String())
-					out.Dependencies = append(out.Dependencies, v42)
-					in.WantComma()
+				if out.Timeout == nil {
+					out.Timeout = new(int)
 				}
-				in.Delim(']')
+				*out.Timeout = int(in.Int())
 			}
-		case "createNetNS":
-			out.CreateNetNS = bool(in.Bool())
-		case "portMappings":
-			if in.IsNull() {
-				in.Skip()
-				out.PortMappings = nil
-			} else {
-				in.Delim('[')
-				if out.PortMappings == nil {
-					if !in.IsDelim(']') {
-						out.PortMappings = make([]ocicni.PortMapping, 0, 1)
-					} else {
-						out.PortMappings = []ocicni.PortMapping{}
-					}
-				} else {
-					out.PortMappings = (out.PortMappings)[:0]
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(out *jwriter.Writer, in specs_go.Hook) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"path\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Path))
+	}
+	if len(in.Args) != 0 {
+		const prefix string = ",\"args\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v33, v34 := range in.Args {
+				if v33 > 0 {
+					out.RawByte(',')
 				}
-				for !in.IsDelim(']') {
-					var v43 ocicni.PortMapping
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(in, &v43)
-					out.PortMappings = append(out.PortMappings, v43)
-					in.WantComma()
+				out.String(string(v34))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Env) != 0 {
+		const prefix string = ",\"env\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v35, v36 := range in.Env {
+				if v35 > 0 {
+					out.RawByte(',')
 				}
-				in.Delim(']')
+				out.String(string(v36))
 			}
-		case "dnsServer":
-			if in.IsNull() {
-				in.Skip()
-				out.DNSServer = nil
-			} else {
-				in.Delim('[')
-				if out.DNSServer == nil {
-					if !in.IsDelim(']') {
-						out.DNSServer = make([]net.IP, 0, 2)
+			out.RawByte(']')
+		}
+	}
+	if in.Timeout != nil {
+		const prefix string = ",\"timeout\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.Timeout))
+	}
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent(in *jlexer.Lexer, out *current.Result) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "cniVersion":
+			out.CNIVersion = string(in.String())
+		case "interfaces":
+			if in.IsNull() {
+				in.Skip()
+				out.Interfaces = nil
+			} else {
+				in.Delim('[')
+				if out.Interfaces == nil {
+					if !in.IsDelim(']') {
+						out.Interfaces = make([]*current.Interface, 0, 8)
 					} else {
-						out.DNSServer = []net.IP{}
+						out.Interfaces = []*current.Interface{}
 					}
 				} else {
-					out.DNSServer = (out.DNSServer)[:0]
+					out.Interfaces = (out.Interfaces)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v44 net.IP
-					if data := in.UnsafeBytes(); in.Ok() {
-						in.AddError((v44).UnmarshalText(data))
+					var v37 *current.Interface
+					if in.IsNull() {
+						in.Skip()
+						v37 = nil
+					} else {
+						if v37 == nil {
+							v37 = new(current.Interface)
+						}
+						easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent1(in, &*v37)
 					}
-					out.DNSServer = append(out.DNSServer, v44)
+					out.Interfaces = append(out.Interfaces, v37)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "dnsSearch":
+		case "ips":
 			if in.IsNull() {
 				in.Skip()
-				out.DNSSearch = nil
+				out.IPs = nil
 			} else {
 				in.Delim('[')
-				if out.DNSSearch == nil {
+				if out.IPs == nil {
 					if !in.IsDelim(']') {
-						out.DNSSearch = make([]string, 0, 4)
+						out.IPs = make([]*current.IPConfig, 0, 8)
 					} else {
-						out.DNSSearch = []string{}
+						out.IPs = []*current.IPConfig{}
 					}
 				} else {
-					out.DNSSearch = (out.DNSSearch)[:0]
+					out.IPs = (out.IPs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v45 string
-					v45 = string(in.String())
-					out.DNSSearch = append(out.DNSSearch, v45)
+					var v38 *current.IPConfig
+					if in.IsNull() {
+						in.Skip()
+						v38 = nil
+					} else {
+						if v38 == nil {
+							v38 = new(current.IPConfig)
+						}
+						if data := in.Raw(); in.Ok() {
+							in.AddError((*v38).UnmarshalJSON(data))
+						}
+					}
+					out.IPs = append(out.IPs, v38)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "dnsOption":
+		case "routes":
 			if in.IsNull() {
 				in.Skip()
-				out.DNSOption = nil
+				out.Routes = nil
 			} else {
 				in.Delim('[')
-				if out.DNSOption == nil {
+				if out.Routes == nil {
 					if !in.IsDelim(']') {
-						out.DNSOption = make([]string, 0, 4)
+						out.Routes = make([]*types.Route, 0, 8)
 					} else {
-						out.DNSOption = []string{}
+						out.Routes = []*types.Route{}
 					}
 				} else {
-					out.DNSOption = (out.DNSOption)[:0]
+					out.Routes = (out.Routes)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v46 string
-					v46 = string(in.String())
-					out.DNSOption = append(out.DNSOption, v46)
+					var v39 *types.Route
+					if in.IsNull() {
+						in.Skip()
+						v39 = nil
+					} else {
+						if v39 == nil {
+							v39 = new(types.Route)
+						}
+						if data := in.Raw(); in.Ok() {
+							in.AddError((*v39).UnmarshalJSON(data))
+						}
+					}
+					out.Routes = append(out.Routes, v39)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "hostsAdd":
+		case "dns":
+			easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypes(in, &out.DNS)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent(out *jwriter.Writer, in current.Result) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.CNIVersion != "" {
+		const prefix string = ",\"cniVersion\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.CNIVersion))
+	}
+	if len(in.Interfaces) != 0 {
+		const prefix string = ",\"interfaces\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v40, v41 := range in.Interfaces {
+				if v40 > 0 {
+					out.RawByte(',')
+				}
+				if v41 == nil {
+					out.RawString("null")
+				} else {
+					easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent1(out, *v41)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.IPs) != 0 {
+		const prefix string = ",\"ips\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v42, v43 := range in.IPs {
+				if v42 > 0 {
+					out.RawByte(',')
+				}
+				if v43 == nil {
+					out.RawString("null")
+				} else {
+					out.Raw((*v43).MarshalJSON())
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Routes) != 0 {
+		const prefix string = ",\"routes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v44, v45 := range in.Routes {
+				if v44 > 0 {
+					out.RawByte(',')
+				}
+				if v45 == nil {
+					out.RawString("null")
+				} else {
+					out.Raw((*v45).MarshalJSON())
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if true {
+		const prefix string = ",\"dns\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypes(out, in.DNS)
+	}
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypes(in *jlexer.Lexer, out *types.DNS) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nameservers":
 			if in.IsNull() {
 				in.Skip()
-				out.HostAdd = nil
+				out.Nameservers = nil
 			} else {
 				in.Delim('[')
-				if out.HostAdd == nil {
+				if out.Nameservers == nil {
 					if !in.IsDelim(']') {
-						out.HostAdd = make([]string, 0, 4)
+						out.Nameservers = make([]string, 0, 4)
 					} else {
-						out.HostAdd = []string{}
+						out.Nameservers = []string{}
 					}
 				} else {
-					out.HostAdd = (out.HostAdd)[:0]
+					out.Nameservers = (out.Nameservers)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v47 string
-					v47 = string(in.String())
-					out.HostAdd = append(out.HostAdd, v47)
+					var v46 string
+					v46 = string(in.String())
+					out.Nameservers = append(out.Nameservers, v46)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "networks":
+		case "domain":
+			out.Domain = string(in.String())
+		case "search":
 			if in.IsNull() {
 				in.Skip()
-				out.Networks = nil
+				out.Search = nil
 			} else {
 				in.Delim('[')
-				if out.Networks == nil {
+				if out.Search == nil {
 					if !in.IsDelim(']') {
-						out.Networks = make([]string, 0, 4)
+						out.Search = make([]string, 0, 4)
 					} else {
-						out.Networks = []string{}
+						out.Search = []string{}
 					}
 				} else {
-					out.Networks = (out.Networks)[:0]
+					out.Search = (out.Search)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v48 string
-					v48 = string(in.String())
-					out.Networks = append(out.Networks, v48)
+					var v47 string
+					v47 = string(in.String())
+					out.Search = append(out.Search, v47)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "userVolumes":
+		case "options":
 			if in.IsNull() {
 				in.Skip()
-				out.UserVolumes = nil
+				out.Options = nil
 			} else {
 				in.Delim('[')
-				if out.UserVolumes == nil {
+				if out.Options == nil {
 					if !in.IsDelim(']') {
-						out.UserVolumes = make([]string, 0, 4)
+						out.Options = make([]string, 0, 4)
 					} else {
-						out.UserVolumes = []string{}
+						out.Options = []string{}
 					}
 				} else {
-					out.UserVolumes = (out.UserVolumes)[:0]
+					out.Options = (out.Options)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v49 string
-					v49 = string(in.String())
-					out.UserVolumes = append(out.UserVolumes, v49)
+					var v48 string
+					v48 = string(in.String())
+					out.Options = append(out.Options, v48)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "entrypoint":
-			if in.IsNull() {
-				in.Skip()
-				out.Entrypoint = nil
-			} else {
-				in.Delim('[')
-				if out.Entrypoint == nil {
-					if !in.IsDelim(']') {
-						out.Entrypoint = make([]string, 0, 4)
-					} else {
-						out.Entrypoint = []string{}
-					}
-				} else {
-					out.Entrypoint = (out.Entrypoint)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v50 string
-					v50 = string(in.String())
-					out.Entrypoint = append(out.Entrypoint, v50)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "command":
-			if in.IsNull() {
-				in.Skip()
-				out.Command = nil
-			} else {
-				in.Delim('[')
-				if out.Command == nil {
-					if !in.IsDelim(']') {
-						out.Command = make([]string, 0, 4)
-					} else {
-						out.Command = []string{}
-					}
-				} else {
-					out.Command = (out.Command)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v51 string
-					v51 = string(in.String())
-					out.Command = append(out.Command, v51)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "stdin":
-			out.Stdin = bool(in.Bool())
-		case "labels":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				in.Delim('{')
-				if !in.IsDelim('}') {
-					out.Labels = make(map[string]string)
-				} else {
-					out.Labels = nil
-				}
-				for !in.IsDelim('}') {
-					key := string(in.String())
-					in.WantColon()
-					var v52 string
-					v52 = string(in.String())
-					(out.Labels)[key] = v52
-					in.WantComma()
-				}
-				in.Delim('}')
-			}
-		case "stopSignal":
-			out.StopSignal = uint(in.Uint())
-		case "stopTimeout":
-			out.StopTimeout = uint(in.Uint())
-		case "createdTime":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.CreatedTime).UnmarshalJSON(data))
-			}
-		case "cgroupParent":
-			out.CgroupParent = string(in.String())
-		case "logPath":
-			out.LogPath = string(in.String())
-		case "conmonPidFile":
-			out.ConmonPidFile = string(in.String())
-		case "postConfigureNetNS":
-			out.PostConfigureNetNS = bool(in.Bool())
-		case "exitCommand":
-			if in.IsNull() {
-				in.Skip()
-				out.ExitCommand = nil
-			} else {
-				in.Delim('[')
-				if out.ExitCommand == nil {
-					if !in.IsDelim(']') {
-						out.ExitCommand = make([]string, 0, 4)
-					} else {
-						out.ExitCommand = []string{}
-					}
-				} else {
-					out.ExitCommand = (out.ExitCommand)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v53 string
-					v53 = string(in.String())
-					out.ExitCommand = append(out.ExitCommand, v53)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "LocalVolumes":
-			if in.IsNull() {
-				in.Skip()
-				out.LocalVolumes = nil
-			} else {
-				in.Delim('[')
-				if out.LocalVolumes == nil {
-					if !in.IsDelim(']') {
-						out.LocalVolumes = make([]string, 0, 4)
-					} else {
-						out.LocalVolumes = []string{}
-					}
-				} else {
-					out.LocalVolumes = (out.LocalVolumes)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v54 string
-					v54 = string(in.String())
-					out.LocalVolumes = append(out.LocalVolumes, v54)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "pause":
-			out.IsInfra = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -1686,439 +1736,972 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodLibpod2(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodLibpod2(out *jwriter.Writer, in ContainerConfig) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypes(out *jwriter.Writer, in types.DNS) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"spec\":"
+	if len(in.Nameservers) != 0 {
+		const prefix string = ",\"nameservers\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if in.Spec == nil {
-			out.RawString("null")
-		} else {
-			easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(out, *in.Spec)
+		{
+			out.RawByte('[')
+			for v49, v50 := range in.Nameservers {
+				if v49 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v50))
+			}
+			out.RawByte(']')
 		}
 	}
-	{
-		const prefix string = ",\"id\":"
+	if in.Domain != "" {
+		const prefix string = ",\"domain\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.ID))
+		out.String(string(in.Domain))
 	}
-	{
-		const prefix string = ",\"name\":"
+	if len(in.Search) != 0 {
+		const prefix string = ",\"search\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Name))
-	}
-	if in.Pod != "" {
-		const prefix string = ",\"pod\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v51, v52 := range in.Search {
+				if v51 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v52))
+			}
+			out.RawByte(']')
 		}
-		out.String(string(in.Pod))
 	}
-	if in.Namespace != "" {
-		const prefix string = ",\"namespace\":"
+	if len(in.Options) != 0 {
+		const prefix string = ",\"options\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Namespace))
-	}
-	if true {
-		const prefix string = ",\"idMappingsOptions\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v53, v54 := range in.Options {
+				if v53 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v54))
+			}
+			out.RawByte(']')
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStorage(out, in.IDMappings)
 	}
-	if in.RootfsImageID != "" {
-		const prefix string = ",\"rootfsImageID\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent1(in *jlexer.Lexer, out *current.Interface) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
 		}
-		out.String(string(in.RootfsImageID))
+		in.Skip()
+		return
 	}
-	if in.RootfsImageName != "" {
-		const prefix string = ",\"rootfsImageName\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
 		}
-		out.String(string(in.RootfsImageName))
-	}
-	if in.Rootfs != "" {
-		const prefix string = ",\"rootfs\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "mac":
+			out.Mac = string(in.String())
+		case "sandbox":
+			out.Sandbox = string(in.String())
+		default:
+			in.SkipRecursive()
 		}
-		out.String(string(in.Rootfs))
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
 	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainernetworkingCniPkgTypesCurrent1(out *jwriter.Writer, in current.Interface) {
+	out.RawByte('{')
+	first := true
+	_ = first
 	{
-		const prefix string = ",\"imageVolumes\":"
+		const prefix string = ",\"name\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.ImageVolumes))
+		out.String(string(in.Name))
 	}
-	if in.ShmDir != "" {
-		const prefix string = ",\"ShmDir\":"
+	if in.Mac != "" {
+		const prefix string = ",\"mac\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.ShmDir))
+		out.String(string(in.Mac))
 	}
-	{
-		const prefix string = ",\"shmSize\":"
+	if in.Sandbox != "" {
+		const prefix string = ",\"sandbox\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int64(int64(in.ShmSize))
+		out.String(string(in.Sandbox))
 	}
-	{
-		const prefix string = ",\"staticDir\":"
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodLibpod1(in *jlexer.Lexer, out *ExecSession) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "command":
+			if in.IsNull() {
+				in.Skip()
+				out.Command = nil
+			} else {
+				in.Delim('[')
+				if out.Command == nil {
+					if !in.IsDelim(']') {
+						out.Command = make([]string, 0, 4)
+					} else {
+						out.Command = []string{}
+					}
+				} else {
+					out.Command = (out.Command)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v55 string
+					v55 = string(in.String())
+					out.Command = append(out.Command, v55)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "pid":
+			out.PID = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodLibpod1(out *jwriter.Writer, in ExecSession) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.StaticDir))
+		out.String(string(in.ID))
 	}
-	if len(in.Mounts) != 0 {
-		const prefix string = ",\"mounts\":"
+	{
+		const prefix string = ",\"command\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
+		if in.Command == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
 			out.RawByte('[')
-			for v55, v56 := range in.Mounts {
-				if v55 > 0 {
+			for v56, v57 := range in.Command {
+				if v56 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v56))
+				out.String(string(v57))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"pid\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.PID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ExecSession) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson1dbef17bEncodeGithubComContainersLibpodLibpod1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ExecSession) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson1dbef17bEncodeGithubComContainersLibpodLibpod1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ExecSession) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson1dbef17bDecodeGithubComContainersLibpodLibpod1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ExecSession) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson1dbef17bDecodeGithubComContainersLibpodLibpod1(l, v)
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodLibpod2(in *jlexer.Lexer, out *ContainerConfig) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "spec":
+			if in.IsNull() {
+				in.Skip()
+				out.Spec = nil
+			} else {
+				if out.Spec == nil {
+					out.Spec = new(specs_go.Spec)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo4(in, &*out.Spec)
+			}
+		case "id":
+			out.ID = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "pod":
+			out.Pod = string(in.String())
+		case "namespace":
+			out.Namespace = string(in.String())
+		case "idMappingsOptions":
+			easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStorage(in, &out.IDMappings)
+		case "rootfsImageID":
+			out.RootfsImageID = string(in.String())
+		case "rootfsImageName":
+			out.RootfsImageName = string(in.String())
+		case "rootfs":
+			out.Rootfs = string(in.String())
+		case "imageVolumes":
+			out.ImageVolumes = bool(in.Bool())
+		case "ShmDir":
+			out.ShmDir = string(in.String())
+		case "shmSize":
+			out.ShmSize = int64(in.Int64())
+		case "staticDir":
+			out.StaticDir = string(in.String())
+		case "mounts":
+			if in.IsNull() {
+				in.Skip()
+				out.Mounts = nil
+			} else {
+				in.Delim('[')
+				if out.Mounts == nil {
+					if !in.IsDelim(']') {
+						out.Mounts = make([]string, 0, 4)
+					} else {
+						out.Mounts = []string{}
+					}
+				} else {
+					out.Mounts = (out.Mounts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v58 string
+					v58 = string(in.String())
+					out.Mounts = append(out.Mounts, v58)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "privileged":
+			out.Privileged = bool(in.Bool())
+		case "ProcessLabel":
+			out.ProcessLabel = string(in.String())
+		case "MountLabel":
+			out.MountLabel = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "groups":
+			if in.IsNull() {
+				in.Skip()
+				out.Groups = nil
+			} else {
+				in.Delim('[')
+				if out.Groups == nil {
+					if !in.IsDelim(']') {
+						out.Groups = make([]string, 0, 4)
+					} else {
+						out.Groups = []string{}
+					}
+				} else {
+					out.Groups = (out.Groups)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v59 string
+					v59 = string(in.String())
+					out.Groups = append(out.Groups, v59)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "ipcNsCtr":
+			out.IPCNsCtr = string(in.String())
+		case "mountNsCtr":
+			out.MountNsCtr = string(in.String())
+		case "netNsCtr":
+			out.NetNsCtr = string(in.String())
+		case "pidNsCtr":
+			out.PIDNsCtr = string(in.String())
+		case "userNsCtr":
+			out.UserNsCtr = string(in.String())
+		case "utsNsCtr":
+			out.UTSNsCtr = string(in.String())
+		case "cgroupNsCtr":
+			out.CgroupNsCtr = string(in.String())
+		case "Dependencies":
+			if in.IsNull() {
+				in.Skip()
+				out.Dependencies = nil
+			} else {
+				in.Delim('[')
+				if out.Dependencies == nil {
+					if !in.IsDelim(']') {
+						out.Dependencies = make([]string, 0, 4)
+					} else {
+						out.Dependencies = []string{}
+					}
+				} else {
+					out.Dependencies = (out.Dependencies)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v60 string
+					v60 = string(in.String())
+					out.Dependencies = append(out.Dependencies, v60)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "createNetNS":
+			out.CreateNetNS = bool(in.Bool())
+		case "netNsPath":
+			out.NetNsPath = string(in.String())
+		case "portMappings":
+			if in.IsNull() {
+				in.Skip()
+				out.PortMappings = nil
+			} else {
+				in.Delim('[')
+				if out.PortMappings == nil {
+					if !in.IsDelim(']') {
+						out.PortMappings = make([]ocicni.PortMapping, 0, 1)
+					} else {
+						out.PortMappings = []ocicni.PortMapping{}
+					}
+				} else {
+					out.PortMappings = (out.PortMappings)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v61 ocicni.PortMapping
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(in, &v61)
+					out.PortMappings = append(out.PortMappings, v61)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "dnsServer":
+			if in.IsNull() {
+				in.Skip()
+				out.DNSServer = nil
+			} else {
+				in.Delim('[')
+				if out.DNSServer == nil {
+					if !in.IsDelim(']') {
+						out.DNSServer = make([]net.IP, 0, 2)
+					} else {
+						out.DNSServer = []net.IP{}
+					}
+				} else {
+					out.DNSServer = (out.DNSServer)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v62 net.IP
+					if data := in.UnsafeBytes(); in.Ok() {
+						in.AddError((v62).UnmarshalText(data))
+					}
+					out.DNSServer = append(out.DNSServer, v62)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "dnsSearch":
+			if in.IsNull() {
+				in.Skip()
+				out.DNSSearch = nil
+			} else {
+				in.Delim('[')
+				if out.DNSSearch == nil {
+					if !in.IsDelim(']') {
+						out.DNSSearch = make([]string, 0, 4)
+					} else {
+						out.DNSSearch = []string{}
+					}
+				} else {
+					out.DNSSearch = (out.DNSSearch)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v63 string
+					v63 = string(in.String())
+					out.DNSSearch = append(out.DNSSearch, v63)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "dnsOption":
+			if in.IsNull() {
+				in.Skip()
+				out.DNSOption = nil
+			} else {
+				in.Delim('[')
+				if out.DNSOption == nil {
+					if !in.IsDelim(']') {
+						out.DNSOption = make([]string, 0, 4)
+					} else {
+						out.DNSOption = []string{}
+					}
+				} else {
+					out.DNSOption = (out.DNSOption)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v64 string
+					v64 = string(in.String())
+					out.DNSOption = append(out.DNSOption, v64)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "hostsAdd":
+			if in.IsNull() {
+				in.Skip()
+				out.HostAdd = nil
+			} else {
+				in.Delim('[')
+				if out.HostAdd == nil {
+					if !in.IsDelim(']') {
+						out.HostAdd = make([]string, 0, 4)
+					} else {
+						out.HostAdd = []string{}
+					}
+				} else {
+					out.HostAdd = (out.HostAdd)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v65 string
+					v65 = string(in.String())
+					out.HostAdd = append(out.HostAdd, v65)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "networks":
+			if in.IsNull() {
+				in.Skip()
+				out.Networks = nil
+			} else {
+				in.Delim('[')
+				if out.Networks == nil {
+					if !in.IsDelim(']') {
+						out.Networks = make([]string, 0, 4)
+					} else {
+						out.Networks = []string{}
+					}
+				} else {
+					out.Networks = (out.Networks)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v66 string
+					v66 = string(in.String())
+					out.Networks = append(out.Networks, v66)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "networkOptions":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.NetworkOptions = make(map[string][]string)
+				} else {
+					out.NetworkOptions = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v67 []string
+					if in.IsNull() {
+						in.Skip()
+						v67 = nil
+					} else {
+						in.Delim('[')
+						if v67 == nil {
+							if !in.IsDelim(']') {
+								v67 = make([]string, 0, 4)
+							} else {
+								v67 = []string{}
+							}
+						} else {
+							v67 = (v67)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v68 string
+							v68 = string(in.String())
+							v67 = append(v67, v68)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					(out.NetworkOptions)[key] = v67
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "resourceWaitTimeout":
+			out.ResourceWaitTimeout = uint(in.Uint())
+		case "userVolumes":
+			if in.IsNull() {
+				in.Skip()
+				out.UserVolumes = nil
+			} else {
+				in.Delim('[')
+				if out.UserVolumes == nil {
+					if !in.IsDelim(']') {
+						out.UserVolumes = make([]string, 0, 4)
+					} else {
+						out.UserVolumes = []string{}
+					}
+				} else {
+					out.UserVolumes = (out.UserVolumes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v69 string
+					v69 = string(in.String())
+					out.UserVolumes = append(out.UserVolumes, v69)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "entrypoint":
+			if in.IsNull() {
+				in.Skip()
+				out.Entrypoint = nil
+			} else {
+				in.Delim('[')
+				if out.Entrypoint == nil {
+					if !in.IsDelim(']') {
+						out.Entrypoint = make([]string, 0, 4)
+					} else {
+						out.Entrypoint = []string{}
+					}
+				} else {
+					out.Entrypoint = (out.Entrypoint)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v70 string
+					v70 = string(in.String())
+					out.Entrypoint = append(out.Entrypoint, v70)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "command":
+			if in.IsNull() {
+				in.Skip()
+				out.Command = nil
+			} else {
+				in.Delim('[')
+				if out.Command == nil {
+					if !in.IsDelim(']') {
+						out.Command = make([]string, 0, 4)
+					} else {
+						out.Command = []string{}
+					}
+				} else {
+					out.Command = (out.Command)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v71 string
+					v71 = string(in.String())
+					out.Command = append(out.Command, v71)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "stdin":
+			out.Stdin = bool(in.Bool())
+		case "labels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Labels = make(map[string]string)
+				} else {
+					out.Labels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v72 string
+					v72 = string(in.String())
+					(out.Labels)[key] = v72
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "stopSignal":
+			out.StopSignal = uint(in.Uint())
+		case "stopTimeout":
+			out.StopTimeout = uint(in.Uint())
+		case "createdTime":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedTime).UnmarshalJSON(data))
+			}
+		case "cgroupParent":
+			out.CgroupParent = string(in.String())
+		case "logDriver":
+			out.LogDriver = string(in.String())
+		case "logPath":
+			out.LogPath = string(in.String())
+		case "logMaxSize":
+			out.LogMaxSize = int64(in.Int64())
+		case "logMaxFiles":
+			out.LogMaxFiles = uint(in.Uint())
+		case "conmonPidFile":
+			out.ConmonPidFile = string(in.String())
+		case "createCommand":
+			if in.IsNull() {
+				in.Skip()
+				out.CreateCommand = nil
+			} else {
+				in.Delim('[')
+				if out.CreateCommand == nil {
+					if !in.IsDelim(']') {
+						out.CreateCommand = make([]string, 0, 4)
+					} else {
+						out.CreateCommand = []string{}
+					}
+				} else {
+					out.CreateCommand = (out.CreateCommand)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v73 string
+					v73 = string(in.String())
+					out.CreateCommand = append(out.CreateCommand, v73)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "healthcheck":
+			if in.IsNull() {
+				in.Skip()
+				out.HealthCheckConfig = nil
+			} else {
+				if out.HealthCheckConfig == nil {
+					out.HealthCheckConfig = new(manifest.Schema2HealthConfig)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersImageManifest(in, &*out.HealthCheckConfig)
+			}
+		case "startupHealthcheck":
+			if in.IsNull() {
+				in.Skip()
+				out.StartupHealthCheckConfig = nil
+			} else {
+				if out.StartupHealthCheckConfig == nil {
+					out.StartupHealthCheckConfig = new(manifest.Schema2HealthConfig)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersImageManifest(in, &*out.StartupHealthCheckConfig)
+			}
+		case "postConfigureNetNS":
+			out.PostConfigureNetNS = bool(in.Bool())
+		case "exitCommand":
+			if in.IsNull() {
+				in.Skip()
+				out.ExitCommand = nil
+			} else {
+				in.Delim('[')
+				if out.ExitCommand == nil {
+					if !in.IsDelim(']') {
+						out.ExitCommand = make([]string, 0, 4)
+					} else {
+						out.ExitCommand = []string{}
+					}
+				} else {
+					out.ExitCommand = (out.ExitCommand)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v74 string
+					v74 = string(in.String())
+					out.ExitCommand = append(out.ExitCommand, v74)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "restartPolicy":
+			out.RestartPolicy = string(in.String())
+		case "restartRetries":
+			out.RestartRetries = uint(in.Uint())
+		case "runtimeHandler":
+			out.RuntimeHandler = string(in.String())
+		case "timezone":
+			out.Timezone = string(in.String())
+		case "LocalVolumes":
+			if in.IsNull() {
+				in.Skip()
+				out.LocalVolumes = nil
+			} else {
+				in.Delim('[')
+				if out.LocalVolumes == nil {
+					if !in.IsDelim(']') {
+						out.LocalVolumes = make([]string, 0, 4)
+					} else {
+						out.LocalVolumes = []string{}
+					}
+				} else {
+					out.LocalVolumes = (out.LocalVolumes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v75 string
+					v75 = string(in.String())
+					out.LocalVolumes = append(out.LocalVolumes, v75)
+					in.WantComma()
+				}
+				in.Delim(']')
 			}
-			out.RawByte(']')
+		case "pause":
+			out.IsInfra = bool(in.Bool())
+		case "initContainerType":
+			out.InitContainerType = string(in.String())
+		default:
+			in.SkipRecursive()
 		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
 	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodLibpod2(out *jwriter.Writer, in ContainerConfig) {
+	out.RawByte('{')
+	first := true
+	_ = first
 	{
-		const prefix string = ",\"privileged\":"
+		const prefix string = ",\"spec\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.Privileged))
-	}
-	if in.ProcessLabel != "" {
-		const prefix string = ",\"ProcessLabel\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
+		if in.Spec == nil {
+			out.RawString("null")
 		} else {
-			out.RawString(prefix)
+			easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo4(out, *in.Spec)
 		}
-		out.String(string(in.ProcessLabel))
 	}
-	if in.MountLabel != "" {
-		const prefix string = ",\"MountLabel\":"
+	{
+		const prefix string = ",\"id\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.MountLabel))
+		out.String(string(in.ID))
 	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
+	{
+		const prefix string = ",\"name\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.User))
+		out.String(string(in.Name))
 	}
-	if len(in.Groups) != 0 {
-		const prefix string = ",\"groups\":"
+	if in.Pod != "" {
+		const prefix string = ",\"pod\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v57, v58 := range in.Groups {
-				if v57 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v58))
-			}
-			out.RawByte(']')
-		}
+		out.String(string(in.Pod))
 	}
-	if in.IPCNsCtr != "" {
-		const prefix string = ",\"ipcNsCtr\":"
+	if in.Namespace != "" {
+		const prefix string = ",\"namespace\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.IPCNsCtr))
+		out.String(string(in.Namespace))
 	}
-	if in.MountNsCtr != "" {
-		const prefix string = ",\"mountNsCtr\":"
+	if true {
+		const prefix string = ",\"idMappingsOptions\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.MountNsCtr))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStorage(out, in.IDMappings)
 	}
-	if in.NetNsCtr != "" {
-		const prefix string = ",\"netNsCtr\":"
+	if in.RootfsImageID != "" {
+		const prefix string = ",\"rootfsImageID\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.NetNsCtr))
+		out.String(string(in.RootfsImageID))
 	}
-	if in.PIDNsCtr != "" {
-		const prefix string = ",\"pidNsCtr\":"
+	if in.RootfsImageName != "" {
+		const prefix string = ",\"rootfsImageName\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.PIDNsCtr))
+		out.String(string(in.RootfsImageName))
 	}
-	if in.UserNsCtr != "" {
-		const prefix string = ",\"userNsCtr\":"
+	if in.Rootfs != "" {
+		const prefix string = ",\"rootfs\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.UserNsCtr))
+		out.String(string(in.Rootfs))
 	}
-	if in.UTSNsCtr != "" {
-		const prefix string = ",\"utsNsCtr\":"
+	{
+		const prefix string = ",\"imageVolumes\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.UTSNsCtr))
+		out.Bool(bool(in.ImageVolumes))
 	}
-	if in.CgroupNsCtr != "" {
-		const prefix string = ",\"cgroupNsCtr\":"
+	if in.ShmDir != "" {
+		const prefix string = ",\"ShmDir\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.CgroupNsCtr))
+		out.String(string(in.ShmDir))
 	}
 	{
-		const prefix string = ",\"Dependencies\":"
+		const prefix string = ",\"shmSize\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if in.Dependencies == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v59, v60 := range in.Dependencies {
-				if v59 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v60))
-			}
-			out.RawByte(']')
-		}
+		out.Int64(int64(in.ShmSize))
 	}
 	{
-		const prefix string = ",\"createNetNS\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Bool(bool(in.CreateNetNS))
-	}
-	if len(in.PortMappings) != 0 {
-		const prefix string = ",\"portMappings\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('[')
-			for v61, v62 := range in.PortMappings {
-				if v61 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(out, v62)
-			}
-			out.RawByte(']')
-		}
-	}
-	if len(in.DNSServer) != 0 {
-		const prefix string = ",\"dnsServer\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('[')
-			for v63, v64 := range in.DNSServer {
-				if v63 > 0 {
-					out.RawByte(',')
-				}
-				out.RawText((v64).MarshalText())
-			}
-			out.RawByte(']')
-		}
-	}
-	if len(in.DNSSearch) != 0 {
-		const prefix string = ",\"dnsSearch\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('[')
-			for v65, v66 := range in.DNSSearch {
-				if v65 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v66))
-			}
-			out.RawByte(']')
-		}
-	}
-	if len(in.DNSOption) != 0 {
-		const prefix string = ",\"dnsOption\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('[')
-			for v67, v68 := range in.DNSOption {
-				if v67 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v68))
-			}
-			out.RawByte(']')
-		}
-	}
-	if len(in.HostAdd) != 0 {
-		const prefix string = ",\"hostsAdd\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('[')
-			for v69, v70 := range in.HostAdd {
-				if v69 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v70))
-			}
-			out.RawByte(']')
-		}
-	}
-	if len(in.Networks) != 0 {
-		const prefix string = ",\"networks\":"
+		const prefix string = ",\"staticDir\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v71, v72 := range in.Networks {
-				if v71 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v72))
-			}
-			out.RawByte(']')
-		}
+		out.String(string(in.StaticDir))
 	}
-	if len(in.UserVolumes) != 0 {
-		const prefix string = ",\"userVolumes\":"
+	if len(in.Mounts) != 0 {
+		const prefix string = ",\"mounts\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -2127,716 +2710,527 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodLibpod2(out *jwriter.Writer,
 		}
 		{
 			out.RawByte('[')
-			for v73, v74 := range in.UserVolumes {
-				if v73 > 0 {
+			for v76, v77 := range in.Mounts {
+				if v76 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v74))
+				out.String(string(v77))
 			}
 			out.RawByte(']')
 		}
 	}
-	if len(in.Entrypoint) != 0 {
-		const prefix string = ",\"entrypoint\":"
+	{
+		const prefix string = ",\"privileged\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v75, v76 := range in.Entrypoint {
-				if v75 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v76))
-			}
-			out.RawByte(']')
-		}
+		out.Bool(bool(in.Privileged))
 	}
-	if len(in.Command) != 0 {
-		const prefix string = ",\"command\":"
+	if in.ProcessLabel != "" {
+		const prefix string = ",\"ProcessLabel\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v77, v78 := range in.Command {
-				if v77 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v78))
-			}
-			out.RawByte(']')
-		}
+		out.String(string(in.ProcessLabel))
 	}
-	if in.Stdin {
-		const prefix string = ",\"stdin\":"
+	if in.MountLabel != "" {
+		const prefix string = ",\"MountLabel\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.Stdin))
+		out.String(string(in.MountLabel))
 	}
-	if len(in.Labels) != 0 {
-		const prefix string = ",\"labels\":"
+	if in.User != "" {
+		const prefix string = ",\"user\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('{')
-			v79First := true
-			for v79Name, v79Value := range in.Labels {
-				if v79First {
-					v79First = false
-				} else {
-					out.RawByte(',')
-				}
-				out.String(string(v79Name))
-				out.RawByte(':')
-				out.String(string(v79Value))
-			}
-			out.RawByte('}')
+		} else {
+			out.RawString(prefix)
 		}
+		out.String(string(in.User))
 	}
-	if in.StopSignal != 0 {
-		const prefix string = ",\"stopSignal\":"
+	if len(in.Groups) != 0 {
+		const prefix string = ",\"groups\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint(uint(in.StopSignal))
+		{
+			out.RawByte('[')
+			for v78, v79 := range in.Groups {
+				if v78 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v79))
+			}
+			out.RawByte(']')
+		}
 	}
-	if in.StopTimeout != 0 {
-		const prefix string = ",\"stopTimeout\":"
+	if in.IPCNsCtr != "" {
+		const prefix string = ",\"ipcNsCtr\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint(uint(in.StopTimeout))
+		out.String(string(in.IPCNsCtr))
 	}
-	{
-		const prefix string = ",\"createdTime\":"
+	if in.MountNsCtr != "" {
+		const prefix string = ",\"mountNsCtr\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Raw((in.CreatedTime).MarshalJSON())
+		out.String(string(in.MountNsCtr))
 	}
-	{
-		const prefix string = ",\"cgroupParent\":"
+	if in.NetNsCtr != "" {
+		const prefix string = ",\"netNsCtr\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.CgroupParent))
+		out.String(string(in.NetNsCtr))
 	}
-	{
-		const prefix string = ",\"logPath\":"
+	if in.PIDNsCtr != "" {
+		const prefix string = ",\"pidNsCtr\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.LogPath))
+		out.String(string(in.PIDNsCtr))
 	}
-	if in.ConmonPidFile != "" {
-		const prefix string = ",\"conmonPidFile\":"
+	if in.UserNsCtr != "" {
+		const prefix string = ",\"userNsCtr\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.ConmonPidFile))
+		out.String(string(in.UserNsCtr))
 	}
-	{
-		const prefix string = ",\"postConfigureNetNS\":"
+	if in.UTSNsCtr != "" {
+		const prefix string = ",\"utsNsCtr\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.PostConfigureNetNS))
+		out.String(string(in.UTSNsCtr))
 	}
-	if len(in.ExitCommand) != 0 {
-		const prefix string = ",\"exitCommand\":"
+	if in.CgroupNsCtr != "" {
+		const prefix string = ",\"cgroupNsCtr\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v80, v81 := range in.ExitCommand {
-				if v80 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v81))
-			}
-			out.RawByte(']')
-		}
+		out.String(string(in.CgroupNsCtr))
 	}
 	{
-		const prefix string = ",\"LocalVolumes\":"
+		const prefix string = ",\"Dependencies\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if in.LocalVolumes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		if in.Dependencies == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v82, v83 := range in.LocalVolumes {
-				if v82 > 0 {
+			for v80, v81 := range in.Dependencies {
+				if v80 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v83))
+				out.String(string(v81))
 			}
 			out.RawByte(']')
 		}
 	}
 	{
-		const prefix string = ",\"pause\":"
+		const prefix string = ",\"createNetNS\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.IsInfra))
-	}
-	out.RawByte('}')
-}
-
-// MarshalJSON supports json.Marshaler interface
-func (v ContainerConfig) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjson1dbef17bEncodeGithubComContainersLibpodLibpod2(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ContainerConfig) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson1dbef17bEncodeGithubComContainersLibpodLibpod2(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *ContainerConfig) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjson1dbef17bDecodeGithubComContainersLibpodLibpod2(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ContainerConfig) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson1dbef17bDecodeGithubComContainersLibpodLibpod2(l, v)
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(in *jlexer.Lexer, out *ocicni.PortMapping) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "hostPort":
-			out.HostPort = int32(in.Int32())
-		case "containerPort":
-			out.ContainerPort = int32(in.Int32())
-		case "protocol":
-			out.Protocol = string(in.String())
-		case "hostIP":
-			out.HostIP = string(in.String())
-		default:
-			in.SkipRecursive()
-		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
+		out.Bool(bool(in.CreateNetNS))
 	}
-}
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(out *jwriter.Writer, in ocicni.PortMapping) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"hostPort\":"
+	if in.NetNsPath != "" {
+		const prefix string = ",\"netNsPath\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int32(int32(in.HostPort))
+		out.String(string(in.NetNsPath))
 	}
-	{
-		const prefix string = ",\"containerPort\":"
+	if len(in.PortMappings) != 0 {
+		const prefix string = ",\"portMappings\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int32(int32(in.ContainerPort))
-	}
-	{
-		const prefix string = ",\"protocol\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v82, v83 := range in.PortMappings {
+				if v82 > 0 {
+					out.RawByte(',')
+				}
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(out, v83)
+			}
+			out.RawByte(']')
 		}
-		out.String(string(in.Protocol))
 	}
-	{
-		const prefix string = ",\"hostIP\":"
+	if len(in.DNSServer) != 0 {
+		const prefix string = ",\"dnsServer\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.HostIP))
-	}
-	out.RawByte('}')
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStorage(in *jlexer.Lexer, out *storage.IDMappingOptions) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "HostUIDMapping":
-			out.HostUIDMapping = bool(in.Bool())
-		case "HostGIDMapping":
-			out.HostGIDMapping = bool(in.Bool())
-		case "UIDMap":
-			if in.IsNull() {
-				in.Skip()
-				out.UIDMap = nil
-			} else {
-				in.Delim('[')
-				if out.UIDMap == nil {
-					if !in.IsDelim(']') {
-						out.UIDMap = make([]idtools.IDMap, 0, 2)
-					} else {
-						out.UIDMap = []idtools.IDMap{}
-					}
-				} else {
-					out.UIDMap = (out.UIDMap)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v84 idtools.IDMap
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(in, &v84)
-					out.UIDMap = append(out.UIDMap, v84)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "GIDMap":
-			if in.IsNull() {
-				in.Skip()
-				out.GIDMap = nil
-			} else {
-				in.Delim('[')
-				if out.GIDMap == nil {
-					if !in.IsDelim(']') {
-						out.GIDMap = make([]idtools.IDMap, 0, 2)
-					} else {
-						out.GIDMap = []idtools.IDMap{}
-					}
-				} else {
-					out.GIDMap = (out.GIDMap)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v85 idtools.IDMap
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(in, &v85)
-					out.GIDMap = append(out.GIDMap, v85)
-					in.WantComma()
+		{
+			out.RawByte('[')
+			for v84, v85 := range in.DNSServer {
+				if v84 > 0 {
+					out.RawByte(',')
 				}
-				in.Delim(']')
-			}
-		default:
-			in.SkipRecursive()
-		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
-	}
-}
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStorage(out *jwriter.Writer, in storage.IDMappingOptions) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"HostUIDMapping\":"
+				out.RawText((v85).MarshalText())
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.DNSSearch) != 0 {
+		const prefix string = ",\"dnsSearch\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.HostUIDMapping))
+		{
+			out.RawByte('[')
+			for v86, v87 := range in.DNSSearch {
+				if v86 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v87))
+			}
+			out.RawByte(']')
+		}
 	}
-	{
-		const prefix string = ",\"HostGIDMapping\":"
+	if len(in.DNSOption) != 0 {
+		const prefix string = ",\"dnsOption\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.HostGIDMapping))
+		{
+			out.RawByte('[')
+			for v88, v89 := range in.DNSOption {
+				if v88 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v89))
+			}
+			out.RawByte(']')
+		}
 	}
-	{
-		const prefix string = ",\"UIDMap\":"
+	if len(in.HostAdd) != 0 {
+		const prefix string = ",\"hostsAdd\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if in.UIDMap == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
+		{
 			out.RawByte('[')
-			for v86, v87 := range in.UIDMap {
-				if v86 > 0 {
+			for v90, v91 := range in.HostAdd {
+				if v90 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(out, v87)
+				out.String(string(v91))
 			}
 			out.RawByte(']')
 		}
 	}
-	{
-		const prefix string = ",\"GIDMap\":"
+	if len(in.Networks) != 0 {
+		const prefix string = ",\"networks\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if in.GIDMap == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
+		{
 			out.RawByte('[')
-			for v88, v89 := range in.GIDMap {
-				if v88 > 0 {
+			for v92, v93 := range in.Networks {
+				if v92 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(out, v89)
+				out.String(string(v93))
 			}
 			out.RawByte(']')
 		}
 	}
-	out.RawByte('}')
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(in *jlexer.Lexer, out *idtools.IDMap) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
+	if len(in.NetworkOptions) != 0 {
+		const prefix string = ",\"networkOptions\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
-		switch key {
-		case "container_id":
-			out.ContainerID = int(in.Int())
-		case "host_id":
-			out.HostID = int(in.Int())
-		case "size":
-			out.Size = int(in.Int())
-		default:
-			in.SkipRecursive()
+		{
+			out.RawByte('{')
+			v94First := true
+			for v94Name, v94Value := range in.NetworkOptions {
+				if v94First {
+					v94First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v94Name))
+				out.RawByte(':')
+				if v94Value == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v95, v96 := range v94Value {
+						if v95 > 0 {
+							out.RawByte(',')
+						}
+						out.String(string(v96))
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte('}')
 		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
 	}
-}
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(out *jwriter.Writer, in idtools.IDMap) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"container_id\":"
+	if in.ResourceWaitTimeout != 0 {
+		const prefix string = ",\"resourceWaitTimeout\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int(int(in.ContainerID))
+		out.Uint(uint(in.ResourceWaitTimeout))
 	}
-	{
-		const prefix string = ",\"host_id\":"
+	if len(in.UserVolumes) != 0 {
+		const prefix string = ",\"userVolumes\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int(int(in.HostID))
+		{
+			out.RawByte('[')
+			for v97, v98 := range in.UserVolumes {
+				if v97 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v98))
+			}
+			out.RawByte(']')
+		}
 	}
-	{
-		const prefix string = ",\"size\":"
+	if len(in.Entrypoint) != 0 {
+		const prefix string = ",\"entrypoint\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int(int(in.Size))
-	}
-	out.RawByte('}')
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(in *jlexer.Lexer, out *specs_go.Spec) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
+		{
+			out.RawByte('[')
+			for v99, v100 := range in.Entrypoint {
+				if v99 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v100))
+			}
+			out.RawByte(']')
 		}
-		in.Skip()
-		return
 	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
+	if len(in.Command) != 0 {
+		const prefix string = ",\"command\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
-		switch key {
-		case "ociVersion":
-			out.Version = string(in.String())
-		case "process":
-			if in.IsNull() {
-				in.Skip()
-				out.Process = nil
-			} else {
-				if out.Process == nil {
-					out.Process = new(specs_go.Process)
-				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo2(in, &*out.Process)
-			}
-		case "root":
-			if in.IsNull() {
-				in.Skip()
-				out.Root = nil
-			} else {
-				if out.Root == nil {
-					out.Root = new(specs_go.Root)
-				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(in, &*out.Root)
-			}
-		case "hostname":
-			out.Hostname = string(in.String())
-		case "mounts":
-			if in.IsNull() {
-				in.Skip()
-				out.Mounts = nil
-			} else {
-				in.Delim('[')
-				if out.Mounts == nil {
-					if !in.IsDelim(']') {
-						out.Mounts = make([]specs_go.Mount, 0, 1)
-					} else {
-						out.Mounts = []specs_go.Mount{}
-					}
-				} else {
-					out.Mounts = (out.Mounts)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v90 specs_go.Mount
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo4(in, &v90)
-					out.Mounts = append(out.Mounts, v90)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "hooks":
-			if in.IsNull() {
-				in.Skip()
-				out.Hooks = nil
-			} else {
-				if out.Hooks == nil {
-					out.Hooks = new(specs_go.Hooks)
-				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo5(in, &*out.Hooks)
-			}
-		case "annotations":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				in.Delim('{')
-				if !in.IsDelim('}') {
-					out.Annotations = make(map[string]string)
-				} else {
-					out.Annotations = nil
-				}
-				for !in.IsDelim('}') {
-					key := string(in.String())
-					in.WantColon()
-					var v91 string
-					v91 = string(in.String())
-					(out.Annotations)[key] = v91
-					in.WantComma()
-				}
-				in.Delim('}')
-			}
-		case "linux":
-			if in.IsNull() {
-				in.Skip()
-				out.Linux = nil
-			} else {
-				if out.Linux == nil {
-					out.Linux = new(specs_go.Linux)
-				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo6(in, &*out.Linux)
-			}
-		case "solaris":
-			if in.IsNull() {
-				in.Skip()
-				out.Solaris = nil
-			} else {
-				if out.Solaris == nil {
-					out.Solaris = new(specs_go.Solaris)
+		{
+			out.RawByte('[')
+			for v101, v102 := range in.Command {
+				if v101 > 0 {
+					out.RawByte(',')
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo7(in, &*out.Solaris)
-			}
-		case "windows":
-			if in.IsNull() {
-				in.Skip()
-				out.Windows = nil
-			} else {
-				if out.Windows == nil {
-					out.Windows = new(specs_go.Windows)
+				out.String(string(v102))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Stdin {
+		const prefix string = ",\"stdin\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Stdin))
+	}
+	if len(in.Labels) != 0 {
+		const prefix string = ",\"labels\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v103First := true
+			for v103Name, v103Value := range in.Labels {
+				if v103First {
+					v103First = false
+				} else {
+					out.RawByte(',')
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo8(in, &*out.Windows)
+				out.String(string(v103Name))
+				out.RawByte(':')
+				out.String(string(v103Value))
 			}
-		default:
-			in.SkipRecursive()
+			out.RawByte('}')
 		}
-		in.WantComma()
 	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
+	if in.StopSignal != 0 {
+		const prefix string = ",\"stopSignal\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint(uint(in.StopSignal))
+	}
+	if in.StopTimeout != 0 {
+		const prefix string = ",\"stopTimeout\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint(uint(in.StopTimeout))
 	}
-}
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(out *jwriter.Writer, in specs_go.Spec) {
-	out.RawByte('{')
-	first := true
-	_ = first
 	{
-		const prefix string = ",\"ociVersion\":"
+		const prefix string = ",\"createdTime\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Version))
+		out.Raw((in.CreatedTime).MarshalJSON())
 	}
-	if in.Process != nil {
-		const prefix string = ",\"process\":"
+	{
+		const prefix string = ",\"cgroupParent\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo2(out, *in.Process)
+		out.String(string(in.CgroupParent))
 	}
-	if in.Root != nil {
-		const prefix string = ",\"root\":"
+	if in.LogDriver != "" {
+		const prefix string = ",\"logDriver\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(out, *in.Root)
+		out.String(string(in.LogDriver))
 	}
-	if in.Hostname != "" {
-		const prefix string = ",\"hostname\":"
+	{
+		const prefix string = ",\"logPath\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Hostname))
+		out.String(string(in.LogPath))
 	}
-	if len(in.Mounts) != 0 {
-		const prefix string = ",\"mounts\":"
+	if in.LogMaxSize != 0 {
+		const prefix string = ",\"logMaxSize\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.LogMaxSize))
+	}
+	if in.LogMaxFiles != 0 {
+		const prefix string = ",\"logMaxFiles\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint(uint(in.LogMaxFiles))
+	}
+	if in.ConmonPidFile != "" {
+		const prefix string = ",\"conmonPidFile\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ConmonPidFile))
+	}
+	if len(in.CreateCommand) != 0 {
+		const prefix string = ",\"createCommand\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -2845,27 +3239,47 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v92, v93 := range in.Mounts {
-				if v92 > 0 {
+			for v104, v105 := range in.CreateCommand {
+				if v104 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo4(out, v93)
+				out.String(string(v105))
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.Hooks != nil {
-		const prefix string = ",\"hooks\":"
+	if in.HealthCheckConfig != nil {
+		const prefix string = ",\"healthcheck\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo5(out, *in.Hooks)
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersImageManifest(out, *in.HealthCheckConfig)
 	}
-	if len(in.Annotations) != 0 {
-		const prefix string = ",\"annotations\":"
+	if in.StartupHealthCheckConfig != nil {
+		const prefix string = ",\"startupHealthcheck\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersImageManifest(out, *in.StartupHealthCheckConfig)
+	}
+	{
+		const prefix string = ",\"postConfigureNetNS\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.PostConfigureNetNS))
+	}
+	if len(in.ExitCommand) != 0 {
+		const prefix string = ",\"exitCommand\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -2873,54 +3287,124 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 			out.RawString(prefix)
 		}
 		{
-			out.RawByte('{')
-			v94First := true
-			for v94Name, v94Value := range in.Annotations {
-				if v94First {
-					v94First = false
-				} else {
+			out.RawByte('[')
+			for v106, v107 := range in.ExitCommand {
+				if v106 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v94Name))
-				out.RawByte(':')
-				out.String(string(v94Value))
+				out.String(string(v107))
 			}
-			out.RawByte('}')
+			out.RawByte(']')
 		}
 	}
-	if in.Linux != nil {
-		const prefix string = ",\"linux\":"
+	if in.RestartPolicy != "" {
+		const prefix string = ",\"restartPolicy\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo6(out, *in.Linux)
+		out.String(string(in.RestartPolicy))
 	}
-	if in.Solaris != nil {
-		const prefix string = ",\"solaris\":"
+	if in.RestartRetries != 0 {
+		const prefix string = ",\"restartRetries\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo7(out, *in.Solaris)
+		out.Uint(uint(in.RestartRetries))
 	}
-	if in.Windows != nil {
-		const prefix string = ",\"windows\":"
+	if in.RuntimeHandler != "" {
+		const prefix string = ",\"runtimeHandler\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.RuntimeHandler))
+	}
+	if in.Timezone != "" {
+		const prefix string = ",\"timezone\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Timezone))
+	}
+	{
+		const prefix string = ",\"LocalVolumes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.LocalVolumes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v108, v109 := range in.LocalVolumes {
+				if v108 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v109))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"pause\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.IsInfra))
+	}
+	if in.InitContainerType != "" {
+		const prefix string = ",\"initContainerType\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo8(out, *in.Windows)
+		out.String(string(in.InitContainerType))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo8(in *jlexer.Lexer, out *specs_go.Windows) {
+
+// MarshalJSON supports json.Marshaler interface
+func (v ContainerConfig) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson1dbef17bEncodeGithubComContainersLibpodLibpod2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ContainerConfig) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson1dbef17bEncodeGithubComContainersLibpodLibpod2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ContainerConfig) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson1dbef17bDecodeGithubComContainersLibpodLibpod2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ContainerConfig) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson1dbef17bDecodeGithubComContainersLibpodLibpod2(l, v)
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersImageManifest(in *jlexer.Lexer, out *manifest.Schema2HealthConfig) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2939,71 +3423,35 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "layerFolders":
+		case "Test":
 			if in.IsNull() {
 				in.Skip()
-				out.LayerFolders = nil
+				out.Test = nil
 			} else {
 				in.Delim('[')
-				if out.LayerFolders == nil {
+				if out.Test == nil {
 					if !in.IsDelim(']') {
-						out.LayerFolders = make([]string, 0, 4)
+						out.Test = make([]string, 0, 4)
 					} else {
-						out.LayerFolders = []string{}
+						out.Test = []string{}
 					}
 				} else {
-					out.LayerFolders = (out.LayerFolders)[:0]
+					out.Test = (out.Test)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v95 string
-					v95 = string(in.String())
-					out.LayerFolders = append(out.LayerFolders, v95)
+					var v110 string
+					v110 = string(in.String())
+					out.Test = append(out.Test, v110)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "resources":
-			if in.IsNull() {
-				in.Skip()
-				out.Resources = nil
-			} else {
-				if out.Resources == nil {
-					out.Resources = new(specs_go.WindowsResources)
-				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo9(in, &*out.Resources)
-			}
-		case "credentialSpec":
-			if m, ok := out.CredentialSpec.(easyjson.Unmarshaler); ok {
-				m.UnmarshalEasyJSON(in)
-			} else if m, ok := out.CredentialSpec.(json.Unmarshaler); ok {
-				_ = m.UnmarshalJSON(in.Raw())
-			} else {
-				out.CredentialSpec = in.Interface()
-			}
-		case "servicing":
-			out.Servicing = bool(in.Bool())
-		case "ignoreFlushesDuringBoot":
-			out.IgnoreFlushesDuringBoot = bool(in.Bool())
-		case "hyperv":
-			if in.IsNull() {
-				in.Skip()
-				out.HyperV = nil
-			} else {
-				if out.HyperV == nil {
-					out.HyperV = new(specs_go.WindowsHyperV)
-				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo10(in, &*out.HyperV)
-			}
-		case "network":
-			if in.IsNull() {
-				in.Skip()
-				out.Network = nil
-			} else {
-				if out.Network == nil {
-					out.Network = new(specs_go.WindowsNetwork)
-				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo11(in, &*out.Network)
-			}
+		case "Interval":
+			out.Interval = time.Duration(in.Int64())
+		case "Timeout":
+			out.Timeout = time.Duration(in.Int64())
+		case "Retries":
+			out.Retries = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -3014,100 +3462,145 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo8(out *jwriter.Writer, in specs_go.Windows) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersImageManifest(out *jwriter.Writer, in manifest.Schema2HealthConfig) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"layerFolders\":"
+	if len(in.Test) != 0 {
+		const prefix string = ",\"Test\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if in.LayerFolders == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
+		{
 			out.RawByte('[')
-			for v96, v97 := range in.LayerFolders {
-				if v96 > 0 {
+			for v111, v112 := range in.Test {
+				if v111 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v97))
+				out.String(string(v112))
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.Resources != nil {
-		const prefix string = ",\"resources\":"
+	if in.Interval != 0 {
+		const prefix string = ",\"Interval\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo9(out, *in.Resources)
+		out.Int64(int64(in.Interval))
 	}
-	if in.CredentialSpec != nil {
-		const prefix string = ",\"credentialSpec\":"
+	if in.Timeout != 0 {
+		const prefix string = ",\"Timeout\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if m, ok := in.CredentialSpec.(easyjson.Marshaler); ok {
-			m.MarshalEasyJSON(out)
-		} else if m, ok := in.CredentialSpec.(json.Marshaler); ok {
-			out.Raw(m.MarshalJSON())
+		out.Int64(int64(in.Timeout))
+	}
+	if in.Retries != 0 {
+		const prefix string = ",\"Retries\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
 		} else {
-			out.Raw(json.Marshal(in.CredentialSpec))
+			out.RawString(prefix)
 		}
+		out.Int(int(in.Retries))
 	}
-	if in.Servicing {
-		const prefix string = ",\"servicing\":"
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(in *jlexer.Lexer, out *ocicni.PortMapping) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "hostPort":
+			out.HostPort = int32(in.Int32())
+		case "containerPort":
+			out.ContainerPort = int32(in.Int32())
+		case "protocol":
+			out.Protocol = string(in.String())
+		case "hostIP":
+			out.HostIP = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(out *jwriter.Writer, in ocicni.PortMapping) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"hostPort\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.Servicing))
+		out.Int32(int32(in.HostPort))
 	}
-	if in.IgnoreFlushesDuringBoot {
-		const prefix string = ",\"ignoreFlushesDuringBoot\":"
+	{
+		const prefix string = ",\"containerPort\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.IgnoreFlushesDuringBoot))
+		out.Int32(int32(in.ContainerPort))
 	}
-	if in.HyperV != nil {
-		const prefix string = ",\"hyperv\":"
+	{
+		const prefix string = ",\"protocol\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo10(out, *in.HyperV)
+		out.String(string(in.Protocol))
 	}
-	if in.Network != nil {
-		const prefix string = ",\"network\":"
+	{
+		const prefix string = ",\"hostIP\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo11(out, *in.Network)
+		out.String(string(in.HostIP))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo11(in *jlexer.Lexer, out *specs_go.WindowsNetwork) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStorage(in *jlexer.Lexer, out *storage.IDMappingOptions) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3126,56 +3619,56 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "endpointList":
+		case "HostUIDMapping":
+			out.HostUIDMapping = bool(in.Bool())
+		case "HostGIDMapping":
+			out.HostGIDMapping = bool(in.Bool())
+		case "UIDMap":
 			if in.IsNull() {
 				in.Skip()
-				out.EndpointList = nil
+				out.UIDMap = nil
 			} else {
 				in.Delim('[')
-				if out.EndpointList == nil {
+				if out.UIDMap == nil {
 					if !in.IsDelim(']') {
-						out.EndpointList = make([]string, 0, 4)
+						out.UIDMap = make([]idtools.IDMap, 0, 2)
 					} else {
-						out.EndpointList = []string{}
+						out.UIDMap = []idtools.IDMap{}
 					}
 				} else {
-					out.EndpointList = (out.EndpointList)[:0]
+					out.UIDMap = (out.UIDMap)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v98 string
-					v98 = string(in.String())
-					out.EndpointList = append(out.EndpointList, v98)
+					var v113 idtools.IDMap
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(in, &v113)
+					out.UIDMap = append(out.UIDMap, v113)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "allowUnqualifiedDNSQuery":
-			out.AllowUnqualifiedDNSQuery = bool(in.Bool())
-		case "DNSSearchList":
+		case "GIDMap":
 			if in.IsNull() {
 				in.Skip()
-				out.DNSSearchList = nil
+				out.GIDMap = nil
 			} else {
 				in.Delim('[')
-				if out.DNSSearchList == nil {
+				if out.GIDMap == nil {
 					if !in.IsDelim(']') {
-						out.DNSSearchList = make([]string, 0, 4)
+						out.GIDMap = make([]idtools.IDMap, 0, 2)
 					} else {
-						out.DNSSearchList = []string{}
+						out.GIDMap = []idtools.IDMap{}
 					}
 				} else {
-					out.DNSSearchList = (out.DNSSearchList)[:0]
+					out.GIDMap = (out.GIDMap)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v99 string
-					v99 = string(in.String())
-					out.DNSSearchList = append(out.DNSSearchList, v99)
+					var v114 idtools.IDMap
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(in, &v114)
+					out.GIDMap = append(out.GIDMap, v114)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "networkSharedContainerName":
-			out.NetworkSharedContainerName = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3186,71 +3679,75 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo11(out *jwriter.Writer, in specs_go.WindowsNetwork) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStorage(out *jwriter.Writer, in storage.IDMappingOptions) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if len(in.EndpointList) != 0 {
-		const prefix string = ",\"endpointList\":"
+	{
+		const prefix string = ",\"HostUIDMapping\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v100, v101 := range in.EndpointList {
-				if v100 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v101))
-			}
-			out.RawByte(']')
-		}
+		out.Bool(bool(in.HostUIDMapping))
 	}
-	if in.AllowUnqualifiedDNSQuery {
-		const prefix string = ",\"allowUnqualifiedDNSQuery\":"
+	{
+		const prefix string = ",\"HostGIDMapping\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.AllowUnqualifiedDNSQuery))
+		out.Bool(bool(in.HostGIDMapping))
 	}
-	if len(in.DNSSearchList) != 0 {
-		const prefix string = ",\"DNSSearchList\":"
+	{
+		const prefix string = ",\"UIDMap\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
+		if in.UIDMap == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
 			out.RawByte('[')
-			for v102, v103 := range in.DNSSearchList {
-				if v102 > 0 {
+			for v115, v116 := range in.UIDMap {
+				if v115 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v103))
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(out, v116)
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.NetworkSharedContainerName != "" {
-		const prefix string = ",\"networkSharedContainerName\":"
+	{
+		const prefix string = ",\"GIDMap\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.NetworkSharedContainerName))
+		if in.GIDMap == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v117, v118 := range in.GIDMap {
+				if v117 > 0 {
+					out.RawByte(',')
+				}
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(out, v118)
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo10(in *jlexer.Lexer, out *specs_go.WindowsHyperV) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(in *jlexer.Lexer, out *idtools.IDMap) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3269,8 +3766,12 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "utilityVMPath":
-			out.UtilityVMPath = string(in.String())
+		case "container_id":
+			out.ContainerID = int(in.Int())
+		case "host_id":
+			out.HostID = int(in.Int())
+		case "size":
+			out.Size = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -3281,23 +3782,43 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo10(out *jwriter.Writer, in specs_go.WindowsHyperV) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComContainersStoragePkgIdtools(out *jwriter.Writer, in idtools.IDMap) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.UtilityVMPath != "" {
-		const prefix string = ",\"utilityVMPath\":"
+	{
+		const prefix string = ",\"container_id\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.UtilityVMPath))
+		out.Int(int(in.ContainerID))
+	}
+	{
+		const prefix string = ",\"host_id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.HostID))
+	}
+	{
+		const prefix string = ",\"size\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Size))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo9(in *jlexer.Lexer, out *specs_go.WindowsResources) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo4(in *jlexer.Lexer, out *specs_go.Spec) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3316,35 +3837,112 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "memory":
+		case "ociVersion":
+			out.Version = string(in.String())
+		case "process":
+			if in.IsNull() {
+				in.Skip()
+				out.Process = nil
+			} else {
+				if out.Process == nil {
+					out.Process = new(specs_go.Process)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo5(in, &*out.Process)
+			}
+		case "root":
+			if in.IsNull() {
+				in.Skip()
+				out.Root = nil
+			} else {
+				if out.Root == nil {
+					out.Root = new(specs_go.Root)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo6(in, &*out.Root)
+			}
+		case "hostname":
+			out.Hostname = string(in.String())
+		case "mounts":
+			if in.IsNull() {
+				in.Skip()
+				out.Mounts = nil
+			} else {
+				in.Delim('[')
+				if out.Mounts == nil {
+					if !in.IsDelim(']') {
+						out.Mounts = make([]specs_go.Mount, 0, 1)
+					} else {
+						out.Mounts = []specs_go.Mount{}
+					}
+				} else {
+					out.Mounts = (out.Mounts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v119 specs_go.Mount
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo7(in, &v119)
+					out.Mounts = append(out.Mounts, v119)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "hooks":
+			if in.IsNull() {
+				in.Skip()
+				out.Hooks = nil
+			} else {
+				if out.Hooks == nil {
+					out.Hooks = new(specs_go.Hooks)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo8(in, &*out.Hooks)
+			}
+		case "annotations":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Annotations = make(map[string]string)
+				} else {
+					out.Annotations = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v120 string
+					v120 = string(in.String())
+					(out.Annotations)[key] = v120
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "linux":
 			if in.IsNull() {
 				in.Skip()
-				out.Memory = nil
+				out.Linux = nil
 			} else {
-				if out.Memory == nil {
-					out.Memory = new(specs_go.WindowsMemoryResources)
+				if out.Linux == nil {
+					out.Linux = new(specs_go.Linux)
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo12(in, &*out.Memory)
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo9(in, &*out.Linux)
 			}
-		case "cpu":
+		case "solaris":
 			if in.IsNull() {
 				in.Skip()
-				out.CPU = nil
+				out.Solaris = nil
 			} else {
-				if out.CPU == nil {
-					out.CPU = new(specs_go.WindowsCPUResources)
+				if out.Solaris == nil {
+					out.Solaris = new(specs_go.Solaris)
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo13(in, &*out.CPU)
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo10(in, &*out.Solaris)
 			}
-		case "storage":
+		case "windows":
 			if in.IsNull() {
 				in.Skip()
-				out.Storage = nil
+				out.Windows = nil
 			} else {
-				if out.Storage == nil {
-					out.Storage = new(specs_go.WindowsStorageResources)
+				if out.Windows == nil {
+					out.Windows = new(specs_go.Windows)
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo14(in, &*out.Storage)
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo11(in, &*out.Windows)
 			}
 		default:
 			in.SkipRecursive()
@@ -3356,138 +3954,136 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo9(out *jwriter.Writer, in specs_go.WindowsResources) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo4(out *jwriter.Writer, in specs_go.Spec) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Memory != nil {
-		const prefix string = ",\"memory\":"
+	{
+		const prefix string = ",\"ociVersion\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo12(out, *in.Memory)
+		out.String(string(in.Version))
 	}
-	if in.CPU != nil {
-		const prefix string = ",\"cpu\":"
+	if in.Process != nil {
+		const prefix string = ",\"process\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo13(out, *in.CPU)
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo5(out, *in.Process)
 	}
-	if in.Storage != nil {
-		const prefix string = ",\"storage\":"
+	if in.Root != nil {
+		const prefix string = ",\"root\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo14(out, *in.Storage)
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo6(out, *in.Root)
 	}
-	out.RawByte('}')
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo14(in *jlexer.Lexer, out *specs_go.WindowsStorageResources) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
+	if in.Hostname != "" {
+		const prefix string = ",\"hostname\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
-		in.Skip()
-		return
+		out.String(string(in.Hostname))
 	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
+	if len(in.Mounts) != 0 {
+		const prefix string = ",\"mounts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
-		switch key {
-		case "iops":
-			if in.IsNull() {
-				in.Skip()
-				out.Iops = nil
-			} else {
-				if out.Iops == nil {
-					out.Iops = new(uint64)
-				}
-				*out.Iops = uint64(in.Uint64())
-			}
-		case "bps":
-			if in.IsNull() {
-				in.Skip()
-				out.Bps = nil
-			} else {
-				if out.Bps == nil {
-					out.Bps = new(uint64)
+		{
+			out.RawByte('[')
+			for v121, v122 := range in.Mounts {
+				if v121 > 0 {
+					out.RawByte(',')
 				}
-				*out.Bps = uint64(in.Uint64())
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo7(out, v122)
 			}
-		case "sandboxSize":
-			if in.IsNull() {
-				in.Skip()
-				out.SandboxSize = nil
-			} else {
-				if out.SandboxSize == nil {
-					out.SandboxSize = new(uint64)
+			out.RawByte(']')
+		}
+	}
+	if in.Hooks != nil {
+		const prefix string = ",\"hooks\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo8(out, *in.Hooks)
+	}
+	if len(in.Annotations) != 0 {
+		const prefix string = ",\"annotations\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v123First := true
+			for v123Name, v123Value := range in.Annotations {
+				if v123First {
+					v123First = false
+				} else {
+					out.RawByte(',')
 				}
-				*out.SandboxSize = uint64(in.Uint64())
+				out.String(string(v123Name))
+				out.RawByte(':')
+				out.String(string(v123Value))
 			}
-		default:
-			in.SkipRecursive()
+			out.RawByte('}')
 		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
 	}
-}
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo14(out *jwriter.Writer, in specs_go.WindowsStorageResources) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	if in.Iops != nil {
-		const prefix string = ",\"iops\":"
+	if in.Linux != nil {
+		const prefix string = ",\"linux\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(*in.Iops))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo9(out, *in.Linux)
 	}
-	if in.Bps != nil {
-		const prefix string = ",\"bps\":"
+	if in.Solaris != nil {
+		const prefix string = ",\"solaris\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(*in.Bps))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo10(out, *in.Solaris)
 	}
-	if in.SandboxSize != nil {
-		const prefix string = ",\"sandboxSize\":"
+	if in.Windows != nil {
+		const prefix string = ",\"windows\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(*in.SandboxSize))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo11(out, *in.Windows)
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo13(in *jlexer.Lexer, out *specs_go.WindowsCPUResources) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo11(in *jlexer.Lexer, out *specs_go.Windows) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3506,35 +4102,70 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "count":
+		case "layerFolders":
 			if in.IsNull() {
 				in.Skip()
-				out.Count = nil
+				out.LayerFolders = nil
 			} else {
-				if out.Count == nil {
-					out.Count = new(uint64)
+				in.Delim('[')
+				if out.LayerFolders == nil {
+					if !in.IsDelim(']') {
+						out.LayerFolders = make([]string, 0, 4)
+					} else {
+						out.LayerFolders = []string{}
+					}
+				} else {
+					out.LayerFolders = (out.LayerFolders)[:0]
 				}
-				*out.Count = uint64(in.Uint64())
+				for !in.IsDelim(']') {
+					var v124 string
+					v124 = string(in.String())
+					out.LayerFolders = append(out.LayerFolders, v124)
+					in.WantComma()
+				}
+				in.Delim(']')
 			}
-		case "shares":
+		case "resources":
 			if in.IsNull() {
 				in.Skip()
-				out.Shares = nil
+				out.Resources = nil
 			} else {
-				if out.Shares == nil {
-					out.Shares = new(uint16)
+				if out.Resources == nil {
+					out.Resources = new(specs_go.WindowsResources)
 				}
-				*out.Shares = uint16(in.Uint16())
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo12(in, &*out.Resources)
 			}
-		case "maximum":
+		case "credentialSpec":
+			if m, ok := out.CredentialSpec.(easyjson.Unmarshaler); ok {
+				m.UnmarshalEasyJSON(in)
+			} else if m, ok := out.CredentialSpec.(json.Unmarshaler); ok {
+				_ = m.UnmarshalJSON(in.Raw())
+			} else {
+				out.CredentialSpec = in.Interface()
+			}
+		case "servicing":
+			out.Servicing = bool(in.Bool())
+		case "ignoreFlushesDuringBoot":
+			out.IgnoreFlushesDuringBoot = bool(in.Bool())
+		case "hyperv":
 			if in.IsNull() {
 				in.Skip()
-				out.Maximum = nil
+				out.HyperV = nil
 			} else {
-				if out.Maximum == nil {
-					out.Maximum = new(uint16)
+				if out.HyperV == nil {
+					out.HyperV = new(specs_go.WindowsHyperV)
 				}
-				*out.Maximum = uint16(in.Uint16())
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo13(in, &*out.HyperV)
+			}
+		case "network":
+			if in.IsNull() {
+				in.Skip()
+				out.Network = nil
+			} else {
+				if out.Network == nil {
+					out.Network = new(specs_go.WindowsNetwork)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo14(in, &*out.Network)
 			}
 		default:
 			in.SkipRecursive()
@@ -3546,98 +4177,100 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo13(out *jwriter.Writer, in specs_go.WindowsCPUResources) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo11(out *jwriter.Writer, in specs_go.Windows) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Count != nil {
-		const prefix string = ",\"count\":"
+	{
+		const prefix string = ",\"layerFolders\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.LayerFolders == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v125, v126 := range in.LayerFolders {
+				if v125 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v126))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Resources != nil {
+		const prefix string = ",\"resources\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo12(out, *in.Resources)
+	}
+	if in.CredentialSpec != nil {
+		const prefix string = ",\"credentialSpec\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if m, ok := in.CredentialSpec.(easyjson.Marshaler); ok {
+			m.MarshalEasyJSON(out)
+		} else if m, ok := in.CredentialSpec.(json.Marshaler); ok {
+			out.Raw(m.MarshalJSON())
+		} else {
+			out.Raw(json.Marshal(in.CredentialSpec))
+		}
+	}
+	if in.Servicing {
+		const prefix string = ",\"servicing\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(*in.Count))
+		out.Bool(bool(in.Servicing))
 	}
-	if in.Shares != nil {
-		const prefix string = ",\"shares\":"
+	if in.IgnoreFlushesDuringBoot {
+		const prefix string = ",\"ignoreFlushesDuringBoot\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint16(uint16(*in.Shares))
+		out.Bool(bool(in.IgnoreFlushesDuringBoot))
 	}
-	if in.Maximum != nil {
-		const prefix string = ",\"maximum\":"
+	if in.HyperV != nil {
+		const prefix string = ",\"hyperv\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint16(uint16(*in.Maximum))
-	}
-	out.RawByte('}')
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo12(in *jlexer.Lexer, out *specs_go.WindowsMemoryResources) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "limit":
-			if in.IsNull() {
-				in.Skip()
-				out.Limit = nil
-			} else {
-				if out.Limit == nil {
-					out.Limit = new(uint64)
-				}
-				*out.Limit = uint64(in.Uint64())
-			}
-		default:
-			in.SkipRecursive()
-		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo13(out, *in.HyperV)
 	}
-}
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo12(out *jwriter.Writer, in specs_go.WindowsMemoryResources) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	if in.Limit != nil {
-		const prefix string = ",\"limit\":"
+	if in.Network != nil {
+		const prefix string = ",\"network\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(*in.Limit))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo14(out, *in.Network)
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo7(in *jlexer.Lexer, out *specs_go.Solaris) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo14(in *jlexer.Lexer, out *specs_go.WindowsNetwork) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3656,55 +4289,56 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "milestone":
-			out.Milestone = string(in.String())
-		case "limitpriv":
-			out.LimitPriv = string(in.String())
-		case "maxShmMemory":
-			out.MaxShmMemory = string(in.String())
-		case "anet":
+		case "endpointList":
 			if in.IsNull() {
 				in.Skip()
-				out.Anet = nil
+				out.EndpointList = nil
 			} else {
 				in.Delim('[')
-				if out.Anet == nil {
+				if out.EndpointList == nil {
 					if !in.IsDelim(']') {
-						out.Anet = make([]specs_go.SolarisAnet, 0, 1)
+						out.EndpointList = make([]string, 0, 4)
 					} else {
-						out.Anet = []specs_go.SolarisAnet{}
+						out.EndpointList = []string{}
 					}
 				} else {
-					out.Anet = (out.Anet)[:0]
+					out.EndpointList = (out.EndpointList)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v104 specs_go.SolarisAnet
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo15(in, &v104)
-					out.Anet = append(out.Anet, v104)
+					var v127 string
+					v127 = string(in.String())
+					out.EndpointList = append(out.EndpointList, v127)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "cappedCPU":
+		case "allowUnqualifiedDNSQuery":
+			out.AllowUnqualifiedDNSQuery = bool(in.Bool())
+		case "DNSSearchList":
 			if in.IsNull() {
 				in.Skip()
-				out.CappedCPU = nil
+				out.DNSSearchList = nil
 			} else {
-				if out.CappedCPU == nil {
-					out.CappedCPU = new(specs_go.SolarisCappedCPU)
+				in.Delim('[')
+				if out.DNSSearchList == nil {
+					if !in.IsDelim(']') {
+						out.DNSSearchList = make([]string, 0, 4)
+					} else {
+						out.DNSSearchList = []string{}
+					}
+				} else {
+					out.DNSSearchList = (out.DNSSearchList)[:0]
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo16(in, &*out.CappedCPU)
-			}
-		case "cappedMemory":
-			if in.IsNull() {
-				in.Skip()
-				out.CappedMemory = nil
-			} else {
-				if out.CappedMemory == nil {
-					out.CappedMemory = new(specs_go.SolarisCappedMemory)
+				for !in.IsDelim(']') {
+					var v128 string
+					v128 = string(in.String())
+					out.DNSSearchList = append(out.DNSSearchList, v128)
+					in.WantComma()
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo17(in, &*out.CappedMemory)
+				in.Delim(']')
 			}
+		case "networkSharedContainerName":
+			out.NetworkSharedContainerName = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3715,42 +4349,41 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo7(out *jwriter.Writer, in specs_go.Solaris) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo14(out *jwriter.Writer, in specs_go.WindowsNetwork) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Milestone != "" {
-		const prefix string = ",\"milestone\":"
+	if len(in.EndpointList) != 0 {
+		const prefix string = ",\"endpointList\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Milestone))
-	}
-	if in.LimitPriv != "" {
-		const prefix string = ",\"limitpriv\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v129, v130 := range in.EndpointList {
+				if v129 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v130))
+			}
+			out.RawByte(']')
 		}
-		out.String(string(in.LimitPriv))
 	}
-	if in.MaxShmMemory != "" {
-		const prefix string = ",\"maxShmMemory\":"
+	if in.AllowUnqualifiedDNSQuery {
+		const prefix string = ",\"allowUnqualifiedDNSQuery\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.MaxShmMemory))
+		out.Bool(bool(in.AllowUnqualifiedDNSQuery))
 	}
-	if len(in.Anet) != 0 {
-		const prefix string = ",\"anet\":"
+	if len(in.DNSSearchList) != 0 {
+		const prefix string = ",\"DNSSearchList\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -3759,38 +4392,75 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v105, v106 := range in.Anet {
-				if v105 > 0 {
+			for v131, v132 := range in.DNSSearchList {
+				if v131 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo15(out, v106)
+				out.String(string(v132))
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.CappedCPU != nil {
-		const prefix string = ",\"cappedCPU\":"
+	if in.NetworkSharedContainerName != "" {
+		const prefix string = ",\"networkSharedContainerName\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo16(out, *in.CappedCPU)
+		out.String(string(in.NetworkSharedContainerName))
 	}
-	if in.CappedMemory != nil {
-		const prefix string = ",\"cappedMemory\":"
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo13(in *jlexer.Lexer, out *specs_go.WindowsHyperV) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "utilityVMPath":
+			out.UtilityVMPath = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo13(out *jwriter.Writer, in specs_go.WindowsHyperV) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.UtilityVMPath != "" {
+		const prefix string = ",\"utilityVMPath\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo17(out, *in.CappedMemory)
+		out.String(string(in.UtilityVMPath))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo17(in *jlexer.Lexer, out *specs_go.SolarisCappedMemory) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo12(in *jlexer.Lexer, out *specs_go.WindowsResources) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3809,10 +4479,36 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "physical":
-			out.Physical = string(in.String())
-		case "swap":
-			out.Swap = string(in.String())
+		case "memory":
+			if in.IsNull() {
+				in.Skip()
+				out.Memory = nil
+			} else {
+				if out.Memory == nil {
+					out.Memory = new(specs_go.WindowsMemoryResources)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo15(in, &*out.Memory)
+			}
+		case "cpu":
+			if in.IsNull() {
+				in.Skip()
+				out.CPU = nil
+			} else {
+				if out.CPU == nil {
+					out.CPU = new(specs_go.WindowsCPUResources)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo16(in, &*out.CPU)
+			}
+		case "storage":
+			if in.IsNull() {
+				in.Skip()
+				out.Storage = nil
+			} else {
+				if out.Storage == nil {
+					out.Storage = new(specs_go.WindowsStorageResources)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo17(in, &*out.Storage)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -3823,33 +4519,43 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo17(out *jwriter.Writer, in specs_go.SolarisCappedMemory) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo12(out *jwriter.Writer, in specs_go.WindowsResources) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Physical != "" {
-		const prefix string = ",\"physical\":"
+	if in.Memory != nil {
+		const prefix string = ",\"memory\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Physical))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo15(out, *in.Memory)
 	}
-	if in.Swap != "" {
-		const prefix string = ",\"swap\":"
+	if in.CPU != nil {
+		const prefix string = ",\"cpu\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Swap))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo16(out, *in.CPU)
+	}
+	if in.Storage != nil {
+		const prefix string = ",\"storage\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo17(out, *in.Storage)
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo16(in *jlexer.Lexer, out *specs_go.SolarisCappedCPU) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo17(in *jlexer.Lexer, out *specs_go.WindowsStorageResources) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3868,8 +4574,36 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "ncpus":
-			out.Ncpus = string(in.String())
+		case "iops":
+			if in.IsNull() {
+				in.Skip()
+				out.Iops = nil
+			} else {
+				if out.Iops == nil {
+					out.Iops = new(uint64)
+				}
+				*out.Iops = uint64(in.Uint64())
+			}
+		case "bps":
+			if in.IsNull() {
+				in.Skip()
+				out.Bps = nil
+			} else {
+				if out.Bps == nil {
+					out.Bps = new(uint64)
+				}
+				*out.Bps = uint64(in.Uint64())
+			}
+		case "sandboxSize":
+			if in.IsNull() {
+				in.Skip()
+				out.SandboxSize = nil
+			} else {
+				if out.SandboxSize == nil {
+					out.SandboxSize = new(uint64)
+				}
+				*out.SandboxSize = uint64(in.Uint64())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -3880,23 +4614,43 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo16(out *jwriter.Writer, in specs_go.SolarisCappedCPU) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo17(out *jwriter.Writer, in specs_go.WindowsStorageResources) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Ncpus != "" {
-		const prefix string = ",\"ncpus\":"
+	if in.Iops != nil {
+		const prefix string = ",\"iops\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Ncpus))
+		out.Uint64(uint64(*in.Iops))
+	}
+	if in.Bps != nil {
+		const prefix string = ",\"bps\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(*in.Bps))
+	}
+	if in.SandboxSize != nil {
+		const prefix string = ",\"sandboxSize\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(*in.SandboxSize))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo15(in *jlexer.Lexer, out *specs_go.SolarisAnet) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo16(in *jlexer.Lexer, out *specs_go.WindowsCPUResources) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3915,20 +4669,36 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "linkname":
-			out.Linkname = string(in.String())
-		case "lowerLink":
-			out.Lowerlink = string(in.String())
-		case "allowedAddress":
-			out.Allowedaddr = string(in.String())
-		case "configureAllowedAddress":
-			out.Configallowedaddr = string(in.String())
-		case "defrouter":
-			out.Defrouter = string(in.String())
-		case "linkProtection":
-			out.Linkprotection = string(in.String())
-		case "macAddress":
-			out.Macaddress = string(in.String())
+		case "count":
+			if in.IsNull() {
+				in.Skip()
+				out.Count = nil
+			} else {
+				if out.Count == nil {
+					out.Count = new(uint64)
+				}
+				*out.Count = uint64(in.Uint64())
+			}
+		case "shares":
+			if in.IsNull() {
+				in.Skip()
+				out.Shares = nil
+			} else {
+				if out.Shares == nil {
+					out.Shares = new(uint16)
+				}
+				*out.Shares = uint16(in.Uint16())
+			}
+		case "maximum":
+			if in.IsNull() {
+				in.Skip()
+				out.Maximum = nil
+			} else {
+				if out.Maximum == nil {
+					out.Maximum = new(uint16)
+				}
+				*out.Maximum = uint16(in.Uint16())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -3939,83 +4709,43 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo15(out *jwriter.Writer, in specs_go.SolarisAnet) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo16(out *jwriter.Writer, in specs_go.WindowsCPUResources) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Linkname != "" {
-		const prefix string = ",\"linkname\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Linkname))
-	}
-	if in.Lowerlink != "" {
-		const prefix string = ",\"lowerLink\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Lowerlink))
-	}
-	if in.Allowedaddr != "" {
-		const prefix string = ",\"allowedAddress\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Allowedaddr))
-	}
-	if in.Configallowedaddr != "" {
-		const prefix string = ",\"configureAllowedAddress\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Configallowedaddr))
-	}
-	if in.Defrouter != "" {
-		const prefix string = ",\"defrouter\":"
+	if in.Count != nil {
+		const prefix string = ",\"count\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Defrouter))
+		out.Uint64(uint64(*in.Count))
 	}
-	if in.Linkprotection != "" {
-		const prefix string = ",\"linkProtection\":"
+	if in.Shares != nil {
+		const prefix string = ",\"shares\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Linkprotection))
+		out.Uint16(uint16(*in.Shares))
 	}
-	if in.Macaddress != "" {
-		const prefix string = ",\"macAddress\":"
+	if in.Maximum != nil {
+		const prefix string = ",\"maximum\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Macaddress))
+		out.Uint16(uint16(*in.Maximum))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo6(in *jlexer.Lexer, out *specs_go.Linux) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo15(in *jlexer.Lexer, out *specs_go.WindowsMemoryResources) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -4033,200 +4763,110 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			in.WantComma()
 			continue
 		}
-		switch key {
-		case "uidMappings":
-			if in.IsNull() {
-				in.Skip()
-				out.UIDMappings = nil
-			} else {
-				in.Delim('[')
-				if out.UIDMappings == nil {
-					if !in.IsDelim(']') {
-						out.UIDMappings = make([]specs_go.LinuxIDMapping, 0, 5)
-					} else {
-						out.UIDMappings = []specs_go.LinuxIDMapping{}
-					}
-				} else {
-					out.UIDMappings = (out.UIDMappings)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v107 specs_go.LinuxIDMapping
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(in, &v107)
-					out.UIDMappings = append(out.UIDMappings, v107)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "gidMappings":
-			if in.IsNull() {
-				in.Skip()
-				out.GIDMappings = nil
-			} else {
-				in.Delim('[')
-				if out.GIDMappings == nil {
-					if !in.IsDelim(']') {
-						out.GIDMappings = make([]specs_go.LinuxIDMapping, 0, 5)
-					} else {
-						out.GIDMappings = []specs_go.LinuxIDMapping{}
-					}
-				} else {
-					out.GIDMappings = (out.GIDMappings)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v108 specs_go.LinuxIDMapping
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(in, &v108)
-					out.GIDMappings = append(out.GIDMappings, v108)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "sysctl":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				in.Delim('{')
-				if !in.IsDelim('}') {
-					out.Sysctl = make(map[string]string)
-				} else {
-					out.Sysctl = nil
-				}
-				for !in.IsDelim('}') {
-					key := string(in.String())
-					in.WantColon()
-					var v109 string
-					v109 = string(in.String())
-					(out.Sysctl)[key] = v109
-					in.WantComma()
-				}
-				in.Delim('}')
-			}
-		case "resources":
-			if in.IsNull() {
-				in.Skip()
-				out.Resources = nil
-			} else {
-				if out.Resources == nil {
-					out.Resources = new(specs_go.LinuxResources)
-				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo19(in, &*out.Resources)
-			}
-		case "cgroupsPath":
-			out.CgroupsPath = string(in.String())
-		case "namespaces":
-			if in.IsNull() {
-				in.Skip()
-				out.Namespaces = nil
-			} else {
-				in.Delim('[')
-				if out.Namespaces == nil {
-					if !in.IsDelim(']') {
-						out.Namespaces = make([]specs_go.LinuxNamespace, 0, 2)
-					} else {
-						out.Namespaces = []specs_go.LinuxNamespace{}
-					}
-				} else {
-					out.Namespaces = (out.Namespaces)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v110 specs_go.LinuxNamespace
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo20(in, &v110)
-					out.Namespaces = append(out.Namespaces, v110)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "devices":
-			if in.IsNull() {
-				in.Skip()
-				out.Devices = nil
-			} else {
-				in.Delim('[')
-				if out.Devices == nil {
-					if !in.IsDelim(']') {
-						out.Devices = make([]specs_go.LinuxDevice, 0, 1)
-					} else {
-						out.Devices = []specs_go.LinuxDevice{}
-					}
-				} else {
-					out.Devices = (out.Devices)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v111 specs_go.LinuxDevice
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(in, &v111)
-					out.Devices = append(out.Devices, v111)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "seccomp":
+		switch key {
+		case "limit":
 			if in.IsNull() {
 				in.Skip()
-				out.Seccomp = nil
+				out.Limit = nil
 			} else {
-				if out.Seccomp == nil {
-					out.Seccomp = new(specs_go.LinuxSeccomp)
+				if out.Limit == nil {
+					out.Limit = new(uint64)
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo22(in, &*out.Seccomp)
+				*out.Limit = uint64(in.Uint64())
 			}
-		case "rootfsPropagation":
-			out.RootfsPropagation = string(in.String())
-		case "maskedPaths":
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo15(out *jwriter.Writer, in specs_go.WindowsMemoryResources) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Limit != nil {
+		const prefix string = ",\"limit\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(*in.Limit))
+	}
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo10(in *jlexer.Lexer, out *specs_go.Solaris) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "milestone":
+			out.Milestone = string(in.String())
+		case "limitpriv":
+			out.LimitPriv = string(in.String())
+		case "maxShmMemory":
+			out.MaxShmMemory = string(in.String())
+		case "anet":
 			if in.IsNull() {
 				in.Skip()
-				out.MaskedPaths = nil
+				out.Anet = nil
 			} else {
 				in.Delim('[')
-				if out.MaskedPaths == nil {
+				if out.Anet == nil {
 					if !in.IsDelim(']') {
-						out.MaskedPaths = make([]string, 0, 4)
+						out.Anet = make([]specs_go.SolarisAnet, 0, 1)
 					} else {
-						out.MaskedPaths = []string{}
+						out.Anet = []specs_go.SolarisAnet{}
 					}
 				} else {
-					out.MaskedPaths = (out.MaskedPaths)[:0]
+					out.Anet = (out.Anet)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v112 string
-					v112 = string(in.String())
-					out.MaskedPaths = append(out.MaskedPaths, v112)
+					var v133 specs_go.SolarisAnet
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(in, &v133)
+					out.Anet = append(out.Anet, v133)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "readonlyPaths":
+		case "cappedCPU":
 			if in.IsNull() {
 				in.Skip()
-				out.ReadonlyPaths = nil
+				out.CappedCPU = nil
 			} else {
-				in.Delim('[')
-				if out.ReadonlyPaths == nil {
-					if !in.IsDelim(']') {
-						out.ReadonlyPaths = make([]string, 0, 4)
-					} else {
-						out.ReadonlyPaths = []string{}
-					}
-				} else {
-					out.ReadonlyPaths = (out.ReadonlyPaths)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v113 string
-					v113 = string(in.String())
-					out.ReadonlyPaths = append(out.ReadonlyPaths, v113)
-					in.WantComma()
+				if out.CappedCPU == nil {
+					out.CappedCPU = new(specs_go.SolarisCappedCPU)
 				}
-				in.Delim(']')
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo19(in, &*out.CappedCPU)
 			}
-		case "mountLabel":
-			out.MountLabel = string(in.String())
-		case "intelRdt":
+		case "cappedMemory":
 			if in.IsNull() {
 				in.Skip()
-				out.IntelRdt = nil
+				out.CappedMemory = nil
 			} else {
-				if out.IntelRdt == nil {
-					out.IntelRdt = new(specs_go.LinuxIntelRdt)
+				if out.CappedMemory == nil {
+					out.CappedMemory = new(specs_go.SolarisCappedMemory)
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo23(in, &*out.IntelRdt)
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo20(in, &*out.CappedMemory)
 			}
 		default:
 			in.SkipRecursive()
@@ -4238,94 +4878,42 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo6(out *jwriter.Writer, in specs_go.Linux) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo10(out *jwriter.Writer, in specs_go.Solaris) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if len(in.UIDMappings) != 0 {
-		const prefix string = ",\"uidMappings\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('[')
-			for v114, v115 := range in.UIDMappings {
-				if v114 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(out, v115)
-			}
-			out.RawByte(']')
-		}
-	}
-	if len(in.GIDMappings) != 0 {
-		const prefix string = ",\"gidMappings\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('[')
-			for v116, v117 := range in.GIDMappings {
-				if v116 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(out, v117)
-			}
-			out.RawByte(']')
-		}
-	}
-	if len(in.Sysctl) != 0 {
-		const prefix string = ",\"sysctl\":"
+	if in.Milestone != "" {
+		const prefix string = ",\"milestone\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('{')
-			v118First := true
-			for v118Name, v118Value := range in.Sysctl {
-				if v118First {
-					v118First = false
-				} else {
-					out.RawByte(',')
-				}
-				out.String(string(v118Name))
-				out.RawByte(':')
-				out.String(string(v118Value))
-			}
-			out.RawByte('}')
-		}
+		out.String(string(in.Milestone))
 	}
-	if in.Resources != nil {
-		const prefix string = ",\"resources\":"
+	if in.LimitPriv != "" {
+		const prefix string = ",\"limitpriv\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo19(out, *in.Resources)
+		out.String(string(in.LimitPriv))
 	}
-	if in.CgroupsPath != "" {
-		const prefix string = ",\"cgroupsPath\":"
+	if in.MaxShmMemory != "" {
+		const prefix string = ",\"maxShmMemory\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.CgroupsPath))
+		out.String(string(in.MaxShmMemory))
 	}
-	if len(in.Namespaces) != 0 {
-		const prefix string = ",\"namespaces\":"
+	if len(in.Anet) != 0 {
+		const prefix string = ",\"anet\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -4334,115 +4922,97 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v119, v120 := range in.Namespaces {
-				if v119 > 0 {
+			for v134, v135 := range in.Anet {
+				if v134 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo20(out, v120)
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(out, v135)
 			}
 			out.RawByte(']')
 		}
 	}
-	if len(in.Devices) != 0 {
-		const prefix string = ",\"devices\":"
+	if in.CappedCPU != nil {
+		const prefix string = ",\"cappedCPU\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v121, v122 := range in.Devices {
-				if v121 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(out, v122)
-			}
-			out.RawByte(']')
-		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo19(out, *in.CappedCPU)
 	}
-	if in.Seccomp != nil {
-		const prefix string = ",\"seccomp\":"
+	if in.CappedMemory != nil {
+		const prefix string = ",\"cappedMemory\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo22(out, *in.Seccomp)
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo20(out, *in.CappedMemory)
 	}
-	if in.RootfsPropagation != "" {
-		const prefix string = ",\"rootfsPropagation\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo20(in *jlexer.Lexer, out *specs_go.SolarisCappedMemory) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
 		}
-		out.String(string(in.RootfsPropagation))
+		in.Skip()
+		return
 	}
-	if len(in.MaskedPaths) != 0 {
-		const prefix string = ",\"maskedPaths\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
 		}
-		{
-			out.RawByte('[')
-			for v123, v124 := range in.MaskedPaths {
-				if v123 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v124))
-			}
-			out.RawByte(']')
+		switch key {
+		case "physical":
+			out.Physical = string(in.String())
+		case "swap":
+			out.Swap = string(in.String())
+		default:
+			in.SkipRecursive()
 		}
+		in.WantComma()
 	}
-	if len(in.ReadonlyPaths) != 0 {
-		const prefix string = ",\"readonlyPaths\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		{
-			out.RawByte('[')
-			for v125, v126 := range in.ReadonlyPaths {
-				if v125 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v126))
-			}
-			out.RawByte(']')
-		}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
 	}
-	if in.MountLabel != "" {
-		const prefix string = ",\"mountLabel\":"
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo20(out *jwriter.Writer, in specs_go.SolarisCappedMemory) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Physical != "" {
+		const prefix string = ",\"physical\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.MountLabel))
+		out.String(string(in.Physical))
 	}
-	if in.IntelRdt != nil {
-		const prefix string = ",\"intelRdt\":"
+	if in.Swap != "" {
+		const prefix string = ",\"swap\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo23(out, *in.IntelRdt)
+		out.String(string(in.Swap))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo23(in *jlexer.Lexer, out *specs_go.LinuxIntelRdt) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo19(in *jlexer.Lexer, out *specs_go.SolarisCappedCPU) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -4461,8 +5031,8 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "l3CacheSchema":
-			out.L3CacheSchema = string(in.String())
+		case "ncpus":
+			out.Ncpus = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -4473,23 +5043,23 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo23(out *jwriter.Writer, in specs_go.LinuxIntelRdt) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo19(out *jwriter.Writer, in specs_go.SolarisCappedCPU) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.L3CacheSchema != "" {
-		const prefix string = ",\"l3CacheSchema\":"
+	if in.Ncpus != "" {
+		const prefix string = ",\"ncpus\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.L3CacheSchema))
+		out.String(string(in.Ncpus))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo22(in *jlexer.Lexer, out *specs_go.LinuxSeccomp) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(in *jlexer.Lexer, out *specs_go.SolarisAnet) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -4508,54 +5078,20 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "defaultAction":
-			out.DefaultAction = specs_go.LinuxSeccompAction(in.String())
-		case "architectures":
-			if in.IsNull() {
-				in.Skip()
-				out.Architectures = nil
-			} else {
-				in.Delim('[')
-				if out.Architectures == nil {
-					if !in.IsDelim(']') {
-						out.Architectures = make([]specs_go.Arch, 0, 4)
-					} else {
-						out.Architectures = []specs_go.Arch{}
-					}
-				} else {
-					out.Architectures = (out.Architectures)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v127 specs_go.Arch
-					v127 = specs_go.Arch(in.String())
-					out.Architectures = append(out.Architectures, v127)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "syscalls":
-			if in.IsNull() {
-				in.Skip()
-				out.Syscalls = nil
-			} else {
-				in.Delim('[')
-				if out.Syscalls == nil {
-					if !in.IsDelim(']') {
-						out.Syscalls = make([]specs_go.LinuxSyscall, 0, 1)
-					} else {
-						out.Syscalls = []specs_go.LinuxSyscall{}
-					}
-				} else {
-					out.Syscalls = (out.Syscalls)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v128 specs_go.LinuxSyscall
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo24(in, &v128)
-					out.Syscalls = append(out.Syscalls, v128)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
+		case "linkname":
+			out.Linkname = string(in.String())
+		case "lowerLink":
+			out.Lowerlink = string(in.String())
+		case "allowedAddress":
+			out.Allowedaddr = string(in.String())
+		case "configureAllowedAddress":
+			out.Configallowedaddr = string(in.String())
+		case "defrouter":
+			out.Defrouter = string(in.String())
+		case "linkProtection":
+			out.Linkprotection = string(in.String())
+		case "macAddress":
+			out.Macaddress = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -4566,61 +5102,83 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo22(out *jwriter.Writer, in specs_go.LinuxSeccomp) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(out *jwriter.Writer, in specs_go.SolarisAnet) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"defaultAction\":"
+	if in.Linkname != "" {
+		const prefix string = ",\"linkname\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.DefaultAction))
+		out.String(string(in.Linkname))
 	}
-	if len(in.Architectures) != 0 {
-		const prefix string = ",\"architectures\":"
+	if in.Lowerlink != "" {
+		const prefix string = ",\"lowerLink\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v129, v130 := range in.Architectures {
-				if v129 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v130))
-			}
-			out.RawByte(']')
+		out.String(string(in.Lowerlink))
+	}
+	if in.Allowedaddr != "" {
+		const prefix string = ",\"allowedAddress\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Allowedaddr))
+	}
+	if in.Configallowedaddr != "" {
+		const prefix string = ",\"configureAllowedAddress\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
+		out.String(string(in.Configallowedaddr))
 	}
-	if len(in.Syscalls) != 0 {
-		const prefix string = ",\"syscalls\":"
+	if in.Defrouter != "" {
+		const prefix string = ",\"defrouter\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v131, v132 := range in.Syscalls {
-				if v131 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo24(out, v132)
-			}
-			out.RawByte(']')
+		out.String(string(in.Defrouter))
+	}
+	if in.Linkprotection != "" {
+		const prefix string = ",\"linkProtection\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Linkprotection))
+	}
+	if in.Macaddress != "" {
+		const prefix string = ",\"macAddress\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
+		out.String(string(in.Macaddress))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo24(in *jlexer.Lexer, out *specs_go.LinuxSyscall) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo9(in *jlexer.Lexer, out *specs_go.Linux) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -4639,54 +5197,200 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "names":
+		case "uidMappings":
+			if in.IsNull() {
+				in.Skip()
+				out.UIDMappings = nil
+			} else {
+				in.Delim('[')
+				if out.UIDMappings == nil {
+					if !in.IsDelim(']') {
+						out.UIDMappings = make([]specs_go.LinuxIDMapping, 0, 5)
+					} else {
+						out.UIDMappings = []specs_go.LinuxIDMapping{}
+					}
+				} else {
+					out.UIDMappings = (out.UIDMappings)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v136 specs_go.LinuxIDMapping
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(in, &v136)
+					out.UIDMappings = append(out.UIDMappings, v136)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "gidMappings":
+			if in.IsNull() {
+				in.Skip()
+				out.GIDMappings = nil
+			} else {
+				in.Delim('[')
+				if out.GIDMappings == nil {
+					if !in.IsDelim(']') {
+						out.GIDMappings = make([]specs_go.LinuxIDMapping, 0, 5)
+					} else {
+						out.GIDMappings = []specs_go.LinuxIDMapping{}
+					}
+				} else {
+					out.GIDMappings = (out.GIDMappings)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v137 specs_go.LinuxIDMapping
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(in, &v137)
+					out.GIDMappings = append(out.GIDMappings, v137)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "sysctl":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Sysctl = make(map[string]string)
+				} else {
+					out.Sysctl = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v138 string
+					v138 = string(in.String())
+					(out.Sysctl)[key] = v138
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "resources":
+			if in.IsNull() {
+				in.Skip()
+				out.Resources = nil
+			} else {
+				if out.Resources == nil {
+					out.Resources = new(specs_go.LinuxResources)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo22(in, &*out.Resources)
+			}
+		case "cgroupsPath":
+			out.CgroupsPath = string(in.String())
+		case "namespaces":
+			if in.IsNull() {
+				in.Skip()
+				out.Namespaces = nil
+			} else {
+				in.Delim('[')
+				if out.Namespaces == nil {
+					if !in.IsDelim(']') {
+						out.Namespaces = make([]specs_go.LinuxNamespace, 0, 2)
+					} else {
+						out.Namespaces = []specs_go.LinuxNamespace{}
+					}
+				} else {
+					out.Namespaces = (out.Namespaces)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v139 specs_go.LinuxNamespace
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo23(in, &v139)
+					out.Namespaces = append(out.Namespaces, v139)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "devices":
+			if in.IsNull() {
+				in.Skip()
+				out.Devices = nil
+			} else {
+				in.Delim('[')
+				if out.Devices == nil {
+					if !in.IsDelim(']') {
+						out.Devices = make([]specs_go.LinuxDevice, 0, 1)
+					} else {
+						out.Devices = []specs_go.LinuxDevice{}
+					}
+				} else {
+					out.Devices = (out.Devices)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v140 specs_go.LinuxDevice
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo24(in, &v140)
+					out.Devices = append(out.Devices, v140)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "seccomp":
+			if in.IsNull() {
+				in.Skip()
+				out.Seccomp = nil
+			} else {
+				if out.Seccomp == nil {
+					out.Seccomp = new(specs_go.LinuxSeccomp)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo25(in, &*out.Seccomp)
+			}
+		case "rootfsPropagation":
+			out.RootfsPropagation = string(in.String())
+		case "maskedPaths":
 			if in.IsNull() {
 				in.Skip()
-				out.Names = nil
+				out.MaskedPaths = nil
 			} else {
 				in.Delim('[')
-				if out.Names == nil {
+				if out.MaskedPaths == nil {
 					if !in.IsDelim(']') {
-						out.Names = make([]string, 0, 4)
+						out.MaskedPaths = make([]string, 0, 4)
 					} else {
-						out.Names = []string{}
+						out.MaskedPaths = []string{}
 					}
 				} else {
-					out.Names = (out.Names)[:0]
+					out.MaskedPaths = (out.MaskedPaths)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v133 string
-					v133 = string(in.String())
-					out.Names = append(out.Names, v133)
+					var v141 string
+					v141 = string(in.String())
+					out.MaskedPaths = append(out.MaskedPaths, v141)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "action":
-			out.Action = specs_go.LinuxSeccompAction(in.String())
-		case "args":
+		case "readonlyPaths":
 			if in.IsNull() {
 				in.Skip()
-				out.Args = nil
+				out.ReadonlyPaths = nil
 			} else {
 				in.Delim('[')
-				if out.Args == nil {
+				if out.ReadonlyPaths == nil {
 					if !in.IsDelim(']') {
-						out.Args = make([]specs_go.LinuxSeccompArg, 0, 1)
+						out.ReadonlyPaths = make([]string, 0, 4)
 					} else {
-						out.Args = []specs_go.LinuxSeccompArg{}
+						out.ReadonlyPaths = []string{}
 					}
 				} else {
-					out.Args = (out.Args)[:0]
+					out.ReadonlyPaths = (out.ReadonlyPaths)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v134 specs_go.LinuxSeccompArg
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo25(in, &v134)
-					out.Args = append(out.Args, v134)
+					var v142 string
+					v142 = string(in.String())
+					out.ReadonlyPaths = append(out.ReadonlyPaths, v142)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "mountLabel":
+			out.MountLabel = string(in.String())
+		case "intelRdt":
+			if in.IsNull() {
+				in.Skip()
+				out.IntelRdt = nil
+			} else {
+				if out.IntelRdt == nil {
+					out.IntelRdt = new(specs_go.LinuxIntelRdt)
+				}
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo26(in, &*out.IntelRdt)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -4697,43 +5401,31 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo24(out *jwriter.Writer, in specs_go.LinuxSyscall) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo9(out *jwriter.Writer, in specs_go.Linux) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"names\":"
+	if len(in.UIDMappings) != 0 {
+		const prefix string = ",\"uidMappings\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		if in.Names == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
+		{
 			out.RawByte('[')
-			for v135, v136 := range in.Names {
-				if v135 > 0 {
+			for v143, v144 := range in.UIDMappings {
+				if v143 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v136))
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(out, v144)
 			}
 			out.RawByte(']')
 		}
 	}
-	{
-		const prefix string = ",\"action\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Action))
-	}
-	if len(in.Args) != 0 {
-		const prefix string = ",\"args\":"
+	if len(in.GIDMappings) != 0 {
+		const prefix string = ",\"gidMappings\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -4742,244 +5434,178 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v137, v138 := range in.Args {
-				if v137 > 0 {
+			for v145, v146 := range in.GIDMappings {
+				if v145 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo25(out, v138)
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(out, v146)
 			}
 			out.RawByte(']')
 		}
 	}
-	out.RawByte('}')
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo25(in *jlexer.Lexer, out *specs_go.LinuxSeccompArg) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "index":
-			out.Index = uint(in.Uint())
-		case "value":
-			out.Value = uint64(in.Uint64())
-		case "valueTwo":
-			out.ValueTwo = uint64(in.Uint64())
-		case "op":
-			out.Op = specs_go.LinuxSeccompOperator(in.String())
-		default:
-			in.SkipRecursive()
-		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
-	}
-}
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo25(out *jwriter.Writer, in specs_go.LinuxSeccompArg) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"index\":"
+	if len(in.Sysctl) != 0 {
+		const prefix string = ",\"sysctl\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint(uint(in.Index))
+		{
+			out.RawByte('{')
+			v147First := true
+			for v147Name, v147Value := range in.Sysctl {
+				if v147First {
+					v147First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v147Name))
+				out.RawByte(':')
+				out.String(string(v147Value))
+			}
+			out.RawByte('}')
+		}
 	}
-	{
-		const prefix string = ",\"value\":"
+	if in.Resources != nil {
+		const prefix string = ",\"resources\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(in.Value))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo22(out, *in.Resources)
 	}
-	if in.ValueTwo != 0 {
-		const prefix string = ",\"valueTwo\":"
+	if in.CgroupsPath != "" {
+		const prefix string = ",\"cgroupsPath\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(in.ValueTwo))
+		out.String(string(in.CgroupsPath))
 	}
-	{
-		const prefix string = ",\"op\":"
+	if len(in.Namespaces) != 0 {
+		const prefix string = ",\"namespaces\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Op))
-	}
-	out.RawByte('}')
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(in *jlexer.Lexer, out *specs_go.LinuxDevice) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "path":
-			out.Path = string(in.String())
-		case "type":
-			out.Type = string(in.String())
-		case "major":
-			out.Major = int64(in.Int64())
-		case "minor":
-			out.Minor = int64(in.Int64())
-		case "fileMode":
-			if in.IsNull() {
-				in.Skip()
-				out.FileMode = nil
-			} else {
-				if out.FileMode == nil {
-					out.FileMode = new(os.FileMode)
-				}
-				*out.FileMode = os.FileMode(in.Uint32())
-			}
-		case "uid":
-			if in.IsNull() {
-				in.Skip()
-				out.UID = nil
-			} else {
-				if out.UID == nil {
-					out.UID = new(uint32)
-				}
-				*out.UID = uint32(in.Uint32())
-			}
-		case "gid":
-			if in.IsNull() {
-				in.Skip()
-				out.GID = nil
-			} else {
-				if out.GID == nil {
-					out.GID = new(uint32)
+		{
+			out.RawByte('[')
+			for v148, v149 := range in.Namespaces {
+				if v148 > 0 {
+					out.RawByte(',')
 				}
-				*out.GID = uint32(in.Uint32())
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo23(out, v149)
 			}
-		default:
-			in.SkipRecursive()
+			out.RawByte(']')
 		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
 	}
-}
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(out *jwriter.Writer, in specs_go.LinuxDevice) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"path\":"
+	if len(in.Devices) != 0 {
+		const prefix string = ",\"devices\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Path))
+		{
+			out.RawByte('[')
+			for v150, v151 := range in.Devices {
+				if v150 > 0 {
+					out.RawByte(',')
+				}
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo24(out, v151)
+			}
+			out.RawByte(']')
+		}
 	}
-	{
-		const prefix string = ",\"type\":"
+	if in.Seccomp != nil {
+		const prefix string = ",\"seccomp\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Type))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo25(out, *in.Seccomp)
 	}
-	{
-		const prefix string = ",\"major\":"
+	if in.RootfsPropagation != "" {
+		const prefix string = ",\"rootfsPropagation\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int64(int64(in.Major))
+		out.String(string(in.RootfsPropagation))
 	}
-	{
-		const prefix string = ",\"minor\":"
+	if len(in.MaskedPaths) != 0 {
+		const prefix string = ",\"maskedPaths\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int64(int64(in.Minor))
+		{
+			out.RawByte('[')
+			for v152, v153 := range in.MaskedPaths {
+				if v152 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v153))
+			}
+			out.RawByte(']')
+		}
 	}
-	if in.FileMode != nil {
-		const prefix string = ",\"fileMode\":"
+	if len(in.ReadonlyPaths) != 0 {
+		const prefix string = ",\"readonlyPaths\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint32(uint32(*in.FileMode))
+		{
+			out.RawByte('[')
+			for v154, v155 := range in.ReadonlyPaths {
+				if v154 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v155))
+			}
+			out.RawByte(']')
+		}
 	}
-	if in.UID != nil {
-		const prefix string = ",\"uid\":"
+	if in.MountLabel != "" {
+		const prefix string = ",\"mountLabel\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint32(uint32(*in.UID))
+		out.String(string(in.MountLabel))
 	}
-	if in.GID != nil {
-		const prefix string = ",\"gid\":"
+	if in.IntelRdt != nil {
+		const prefix string = ",\"intelRdt\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint32(uint32(*in.GID))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo26(out, *in.IntelRdt)
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo20(in *jlexer.Lexer, out *specs_go.LinuxNamespace) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo26(in *jlexer.Lexer, out *specs_go.LinuxIntelRdt) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -4998,10 +5624,8 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "type":
-			out.Type = specs_go.LinuxNamespaceType(in.String())
-		case "path":
-			out.Path = string(in.String())
+		case "l3CacheSchema":
+			out.L3CacheSchema = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -5012,33 +5636,23 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo20(out *jwriter.Writer, in specs_go.LinuxNamespace) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo26(out *jwriter.Writer, in specs_go.LinuxIntelRdt) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"type\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Type))
-	}
-	if in.Path != "" {
-		const prefix string = ",\"path\":"
+	if in.L3CacheSchema != "" {
+		const prefix string = ",\"l3CacheSchema\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Path))
+		out.String(string(in.L3CacheSchema))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo19(in *jlexer.Lexer, out *specs_go.LinuxResources) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo25(in *jlexer.Lexer, out *specs_go.LinuxSeccomp) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -5057,101 +5671,184 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "devices":
+		case "defaultAction":
+			out.DefaultAction = specs_go.LinuxSeccompAction(in.String())
+		case "architectures":
 			if in.IsNull() {
 				in.Skip()
-				out.Devices = nil
+				out.Architectures = nil
 			} else {
 				in.Delim('[')
-				if out.Devices == nil {
+				if out.Architectures == nil {
 					if !in.IsDelim(']') {
-						out.Devices = make([]specs_go.LinuxDeviceCgroup, 0, 1)
+						out.Architectures = make([]specs_go.Arch, 0, 4)
 					} else {
-						out.Devices = []specs_go.LinuxDeviceCgroup{}
+						out.Architectures = []specs_go.Arch{}
 					}
 				} else {
-					out.Devices = (out.Devices)[:0]
+					out.Architectures = (out.Architectures)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v139 specs_go.LinuxDeviceCgroup
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo26(in, &v139)
-					out.Devices = append(out.Devices, v139)
+					var v156 specs_go.Arch
+					v156 = specs_go.Arch(in.String())
+					out.Architectures = append(out.Architectures, v156)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "memory":
+		case "syscalls":
 			if in.IsNull() {
 				in.Skip()
-				out.Memory = nil
+				out.Syscalls = nil
 			} else {
-				if out.Memory == nil {
-					out.Memory = new(specs_go.LinuxMemory)
+				in.Delim('[')
+				if out.Syscalls == nil {
+					if !in.IsDelim(']') {
+						out.Syscalls = make([]specs_go.LinuxSyscall, 0, 1)
+					} else {
+						out.Syscalls = []specs_go.LinuxSyscall{}
+					}
+				} else {
+					out.Syscalls = (out.Syscalls)[:0]
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo27(in, &*out.Memory)
-			}
-		case "cpu":
-			if in.IsNull() {
-				in.Skip()
-				out.CPU = nil
-			} else {
-				if out.CPU == nil {
-					out.CPU = new(specs_go.LinuxCPU)
+				for !in.IsDelim(']') {
+					var v157 specs_go.LinuxSyscall
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo27(in, &v157)
+					out.Syscalls = append(out.Syscalls, v157)
+					in.WantComma()
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo28(in, &*out.CPU)
+				in.Delim(']')
 			}
-		case "pids":
-			if in.IsNull() {
-				in.Skip()
-				out.Pids = nil
-			} else {
-				if out.Pids == nil {
-					out.Pids = new(specs_go.LinuxPids)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo25(out *jwriter.Writer, in specs_go.LinuxSeccomp) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"defaultAction\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.DefaultAction))
+	}
+	if len(in.Architectures) != 0 {
+		const prefix string = ",\"architectures\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v158, v159 := range in.Architectures {
+				if v158 > 0 {
+					out.RawByte(',')
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo29(in, &*out.Pids)
+				out.String(string(v159))
 			}
-		case "blockIO":
-			if in.IsNull() {
-				in.Skip()
-				out.BlockIO = nil
-			} else {
-				if out.BlockIO == nil {
-					out.BlockIO = new(specs_go.LinuxBlockIO)
+			out.RawByte(']')
+		}
+	}
+	if len(in.Syscalls) != 0 {
+		const prefix string = ",\"syscalls\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v160, v161 := range in.Syscalls {
+				if v160 > 0 {
+					out.RawByte(',')
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo30(in, &*out.BlockIO)
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo27(out, v161)
 			}
-		case "hugepageLimits":
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo27(in *jlexer.Lexer, out *specs_go.LinuxSyscall) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "names":
 			if in.IsNull() {
 				in.Skip()
-				out.HugepageLimits = nil
+				out.Names = nil
 			} else {
 				in.Delim('[')
-				if out.HugepageLimits == nil {
+				if out.Names == nil {
 					if !in.IsDelim(']') {
-						out.HugepageLimits = make([]specs_go.LinuxHugepageLimit, 0, 2)
+						out.Names = make([]string, 0, 4)
 					} else {
-						out.HugepageLimits = []specs_go.LinuxHugepageLimit{}
+						out.Names = []string{}
 					}
 				} else {
-					out.HugepageLimits = (out.HugepageLimits)[:0]
+					out.Names = (out.Names)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v140 specs_go.LinuxHugepageLimit
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo31(in, &v140)
-					out.HugepageLimits = append(out.HugepageLimits, v140)
+					var v162 string
+					v162 = string(in.String())
+					out.Names = append(out.Names, v162)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "network":
+		case "action":
+			out.Action = specs_go.LinuxSeccompAction(in.String())
+		case "args":
 			if in.IsNull() {
 				in.Skip()
-				out.Network = nil
+				out.Args = nil
 			} else {
-				if out.Network == nil {
-					out.Network = new(specs_go.LinuxNetwork)
+				in.Delim('[')
+				if out.Args == nil {
+					if !in.IsDelim(']') {
+						out.Args = make([]specs_go.LinuxSeccompArg, 0, 1)
+					} else {
+						out.Args = []specs_go.LinuxSeccompArg{}
+					}
+				} else {
+					out.Args = (out.Args)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v163 specs_go.LinuxSeccompArg
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo28(in, &v163)
+					out.Args = append(out.Args, v163)
+					in.WantComma()
 				}
-				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo32(in, &*out.Network)
+				in.Delim(']')
 			}
 		default:
 			in.SkipRecursive()
@@ -5163,101 +5860,146 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo19(out *jwriter.Writer, in specs_go.LinuxResources) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo27(out *jwriter.Writer, in specs_go.LinuxSyscall) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if len(in.Devices) != 0 {
-		const prefix string = ",\"devices\":"
+	{
+		const prefix string = ",\"names\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
+		if in.Names == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
 			out.RawByte('[')
-			for v141, v142 := range in.Devices {
-				if v141 > 0 {
+			for v164, v165 := range in.Names {
+				if v164 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo26(out, v142)
+				out.String(string(v165))
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.Memory != nil {
-		const prefix string = ",\"memory\":"
+	{
+		const prefix string = ",\"action\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo27(out, *in.Memory)
+		out.String(string(in.Action))
 	}
-	if in.CPU != nil {
-		const prefix string = ",\"cpu\":"
+	if len(in.Args) != 0 {
+		const prefix string = ",\"args\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo28(out, *in.CPU)
+		{
+			out.RawByte('[')
+			for v166, v167 := range in.Args {
+				if v166 > 0 {
+					out.RawByte(',')
+				}
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo28(out, v167)
+			}
+			out.RawByte(']')
+		}
 	}
-	if in.Pids != nil {
-		const prefix string = ",\"pids\":"
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo28(in *jlexer.Lexer, out *specs_go.LinuxSeccompArg) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "index":
+			out.Index = uint(in.Uint())
+		case "value":
+			out.Value = uint64(in.Uint64())
+		case "valueTwo":
+			out.ValueTwo = uint64(in.Uint64())
+		case "op":
+			out.Op = specs_go.LinuxSeccompOperator(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo28(out *jwriter.Writer, in specs_go.LinuxSeccompArg) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"index\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo29(out, *in.Pids)
+		out.Uint(uint(in.Index))
 	}
-	if in.BlockIO != nil {
-		const prefix string = ",\"blockIO\":"
+	{
+		const prefix string = ",\"value\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo30(out, *in.BlockIO)
+		out.Uint64(uint64(in.Value))
 	}
-	if len(in.HugepageLimits) != 0 {
-		const prefix string = ",\"hugepageLimits\":"
+	if in.ValueTwo != 0 {
+		const prefix string = ",\"valueTwo\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v143, v144 := range in.HugepageLimits {
-				if v143 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo31(out, v144)
-			}
-			out.RawByte(']')
-		}
+		out.Uint64(uint64(in.ValueTwo))
 	}
-	if in.Network != nil {
-		const prefix string = ",\"network\":"
+	{
+		const prefix string = ",\"op\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo32(out, *in.Network)
+		out.String(string(in.Op))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo32(in *jlexer.Lexer, out *specs_go.LinuxNetwork) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo24(in *jlexer.Lexer, out *specs_go.LinuxDevice) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -5276,38 +6018,43 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "classID":
+		case "path":
+			out.Path = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "major":
+			out.Major = int64(in.Int64())
+		case "minor":
+			out.Minor = int64(in.Int64())
+		case "fileMode":
 			if in.IsNull() {
 				in.Skip()
-				out.ClassID = nil
+				out.FileMode = nil
 			} else {
-				if out.ClassID == nil {
-					out.ClassID = new(uint32)
+				if out.FileMode == nil {
+					out.FileMode = new(os.FileMode)
 				}
-				*out.ClassID = uint32(in.Uint32())
+				*out.FileMode = os.FileMode(in.Uint32())
 			}
-		case "priorities":
+		case "uid":
 			if in.IsNull() {
 				in.Skip()
-				out.Priorities = nil
+				out.UID = nil
 			} else {
-				in.Delim('[')
-				if out.Priorities == nil {
-					if !in.IsDelim(']') {
-						out.Priorities = make([]specs_go.LinuxInterfacePriority, 0, 2)
-					} else {
-						out.Priorities = []specs_go.LinuxInterfacePriority{}
-					}
-				} else {
-					out.Priorities = (out.Priorities)[:0]
+				if out.UID == nil {
+					out.UID = new(uint32)
 				}
-				for !in.IsDelim(']') {
-					var v145 specs_go.LinuxInterfacePriority
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo33(in, &v145)
-					out.Priorities = append(out.Priorities, v145)
-					in.WantComma()
+				*out.UID = uint32(in.Uint32())
+			}
+		case "gid":
+			if in.IsNull() {
+				in.Skip()
+				out.GID = nil
+			} else {
+				if out.GID == nil {
+					out.GID = new(uint32)
 				}
-				in.Delim(']')
+				*out.GID = uint32(in.Uint32())
 			}
 		default:
 			in.SkipRecursive()
@@ -5319,101 +6066,83 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo32(out *jwriter.Writer, in specs_go.LinuxNetwork) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo24(out *jwriter.Writer, in specs_go.LinuxDevice) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.ClassID != nil {
-		const prefix string = ",\"classID\":"
+	{
+		const prefix string = ",\"path\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint32(uint32(*in.ClassID))
+		out.String(string(in.Path))
 	}
-	if len(in.Priorities) != 0 {
-		const prefix string = ",\"priorities\":"
+	{
+		const prefix string = ",\"type\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v146, v147 := range in.Priorities {
-				if v146 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo33(out, v147)
-			}
-			out.RawByte(']')
-		}
+		out.String(string(in.Type))
 	}
-	out.RawByte('}')
-}
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo33(in *jlexer.Lexer, out *specs_go.LinuxInterfacePriority) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
+	{
+		const prefix string = ",\"major\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
-		in.Skip()
-		return
+		out.Int64(int64(in.Major))
 	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeString()
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "name":
-			out.Name = string(in.String())
-		case "priority":
-			out.Priority = uint32(in.Uint32())
-		default:
-			in.SkipRecursive()
+	{
+		const prefix string = ",\"minor\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
-		in.WantComma()
+		out.Int64(int64(in.Minor))
 	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
+	if in.FileMode != nil {
+		const prefix string = ",\"fileMode\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint32(uint32(*in.FileMode))
 	}
-}
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo33(out *jwriter.Writer, in specs_go.LinuxInterfacePriority) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"name\":"
+	if in.UID != nil {
+		const prefix string = ",\"uid\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Name))
+		out.Uint32(uint32(*in.UID))
 	}
-	{
-		const prefix string = ",\"priority\":"
+	if in.GID != nil {
+		const prefix string = ",\"gid\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint32(uint32(in.Priority))
+		out.Uint32(uint32(*in.GID))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo31(in *jlexer.Lexer, out *specs_go.LinuxHugepageLimit) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo23(in *jlexer.Lexer, out *specs_go.LinuxNamespace) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -5432,10 +6161,10 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "pageSize":
-			out.Pagesize = string(in.String())
-		case "limit":
-			out.Limit = uint64(in.Uint64())
+		case "type":
+			out.Type = specs_go.LinuxNamespaceType(in.String())
+		case "path":
+			out.Path = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -5446,33 +6175,33 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo31(out *jwriter.Writer, in specs_go.LinuxHugepageLimit) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo23(out *jwriter.Writer, in specs_go.LinuxNamespace) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"pageSize\":"
+		const prefix string = ",\"type\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Pagesize))
+		out.String(string(in.Type))
 	}
-	{
-		const prefix string = ",\"limit\":"
+	if in.Path != "" {
+		const prefix string = ",\"path\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(in.Limit))
+		out.String(string(in.Path))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo30(in *jlexer.Lexer, out *specs_go.LinuxBlockIO) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo22(in *jlexer.Lexer, out *specs_go.LinuxResources) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -5491,140 +6220,101 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "weight":
-			if in.IsNull() {
-				in.Skip()
-				out.Weight = nil
-			} else {
-				if out.Weight == nil {
-					out.Weight = new(uint16)
-				}
-				*out.Weight = uint16(in.Uint16())
-			}
-		case "leafWeight":
-			if in.IsNull() {
-				in.Skip()
-				out.LeafWeight = nil
-			} else {
-				if out.LeafWeight == nil {
-					out.LeafWeight = new(uint16)
-				}
-				*out.LeafWeight = uint16(in.Uint16())
-			}
-		case "weightDevice":
+		case "devices":
 			if in.IsNull() {
 				in.Skip()
-				out.WeightDevice = nil
+				out.Devices = nil
 			} else {
 				in.Delim('[')
-				if out.WeightDevice == nil {
+				if out.Devices == nil {
 					if !in.IsDelim(']') {
-						out.WeightDevice = make([]specs_go.LinuxWeightDevice, 0, 2)
+						out.Devices = make([]specs_go.LinuxDeviceCgroup, 0, 1)
 					} else {
-						out.WeightDevice = []specs_go.LinuxWeightDevice{}
+						out.Devices = []specs_go.LinuxDeviceCgroup{}
 					}
 				} else {
-					out.WeightDevice = (out.WeightDevice)[:0]
+					out.Devices = (out.Devices)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v148 specs_go.LinuxWeightDevice
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo34(in, &v148)
-					out.WeightDevice = append(out.WeightDevice, v148)
+					var v168 specs_go.LinuxDeviceCgroup
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo29(in, &v168)
+					out.Devices = append(out.Devices, v168)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "throttleReadBpsDevice":
+		case "memory":
 			if in.IsNull() {
 				in.Skip()
-				out.ThrottleReadBpsDevice = nil
+				out.Memory = nil
 			} else {
-				in.Delim('[')
-				if out.ThrottleReadBpsDevice == nil {
-					if !in.IsDelim(']') {
-						out.ThrottleReadBpsDevice = make([]specs_go.LinuxThrottleDevice, 0, 2)
-					} else {
-						out.ThrottleReadBpsDevice = []specs_go.LinuxThrottleDevice{}
-					}
-				} else {
-					out.ThrottleReadBpsDevice = (out.ThrottleReadBpsDevice)[:0]
+				if out.Memory == nil {
+					out.Memory = new(specs_go.LinuxMemory)
 				}
-				for !in.IsDelim(']') {
-					var v149 specs_go.LinuxThrottleDevice
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(in, &v149)
-					out.ThrottleReadBpsDevice = append(out.ThrottleReadBpsDevice, v149)
-					in.WantComma()
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo30(in, &*out.Memory)
+			}
+		case "cpu":
+			if in.IsNull() {
+				in.Skip()
+				out.CPU = nil
+			} else {
+				if out.CPU == nil {
+					out.CPU = new(specs_go.LinuxCPU)
 				}
-				in.Delim(']')
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo31(in, &*out.CPU)
 			}
-		case "throttleWriteBpsDevice":
+		case "pids":
 			if in.IsNull() {
 				in.Skip()
-				out.ThrottleWriteBpsDevice = nil
+				out.Pids = nil
 			} else {
-				in.Delim('[')
-				if out.ThrottleWriteBpsDevice == nil {
-					if !in.IsDelim(']') {
-						out.ThrottleWriteBpsDevice = make([]specs_go.LinuxThrottleDevice, 0, 2)
-					} else {
-						out.ThrottleWriteBpsDevice = []specs_go.LinuxThrottleDevice{}
-					}
-				} else {
-					out.ThrottleWriteBpsDevice = (out.ThrottleWriteBpsDevice)[:0]
+				if out.Pids == nil {
+					out.Pids = new(specs_go.LinuxPids)
 				}
-				for !in.IsDelim(']') {
-					var v150 specs_go.LinuxThrottleDevice
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(in, &v150)
-					out.ThrottleWriteBpsDevice = append(out.ThrottleWriteBpsDevice, v150)
-					in.WantComma()
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo32(in, &*out.Pids)
+			}
+		case "blockIO":
+			if in.IsNull() {
+				in.Skip()
+				out.BlockIO = nil
+			} else {
+				if out.BlockIO == nil {
+					out.BlockIO = new(specs_go.LinuxBlockIO)
 				}
-				in.Delim(']')
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(in, &*out.BlockIO)
 			}
-		case "throttleReadIOPSDevice":
+		case "hugepageLimits":
 			if in.IsNull() {
 				in.Skip()
-				out.ThrottleReadIOPSDevice = nil
+				out.HugepageLimits = nil
 			} else {
 				in.Delim('[')
-				if out.ThrottleReadIOPSDevice == nil {
+				if out.HugepageLimits == nil {
 					if !in.IsDelim(']') {
-						out.ThrottleReadIOPSDevice = make([]specs_go.LinuxThrottleDevice, 0, 2)
+						out.HugepageLimits = make([]specs_go.LinuxHugepageLimit, 0, 2)
 					} else {
-						out.ThrottleReadIOPSDevice = []specs_go.LinuxThrottleDevice{}
+						out.HugepageLimits = []specs_go.LinuxHugepageLimit{}
 					}
 				} else {
-					out.ThrottleReadIOPSDevice = (out.ThrottleReadIOPSDevice)[:0]
+					out.HugepageLimits = (out.HugepageLimits)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v151 specs_go.LinuxThrottleDevice
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(in, &v151)
-					out.ThrottleReadIOPSDevice = append(out.ThrottleReadIOPSDevice, v151)
+					var v169 specs_go.LinuxHugepageLimit
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo33(in, &v169)
+					out.HugepageLimits = append(out.HugepageLimits, v169)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "throttleWriteIOPSDevice":
+		case "network":
 			if in.IsNull() {
 				in.Skip()
-				out.ThrottleWriteIOPSDevice = nil
+				out.Network = nil
 			} else {
-				in.Delim('[')
-				if out.ThrottleWriteIOPSDevice == nil {
-					if !in.IsDelim(']') {
-						out.ThrottleWriteIOPSDevice = make([]specs_go.LinuxThrottleDevice, 0, 2)
-					} else {
-						out.ThrottleWriteIOPSDevice = []specs_go.LinuxThrottleDevice{}
-					}
-				} else {
-					out.ThrottleWriteIOPSDevice = (out.ThrottleWriteIOPSDevice)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v152 specs_go.LinuxThrottleDevice
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(in, &v152)
-					out.ThrottleWriteIOPSDevice = append(out.ThrottleWriteIOPSDevice, v152)
-					in.WantComma()
+				if out.Network == nil {
+					out.Network = new(specs_go.LinuxNetwork)
 				}
-				in.Delim(']')
+				easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo34(in, &*out.Network)
 			}
 		default:
 			in.SkipRecursive()
@@ -5636,89 +6326,71 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo30(out *jwriter.Writer, in specs_go.LinuxBlockIO) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo22(out *jwriter.Writer, in specs_go.LinuxResources) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Weight != nil {
-		const prefix string = ",\"weight\":"
+	if len(in.Devices) != 0 {
+		const prefix string = ",\"devices\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint16(uint16(*in.Weight))
+		{
+			out.RawByte('[')
+			for v170, v171 := range in.Devices {
+				if v170 > 0 {
+					out.RawByte(',')
+				}
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo29(out, v171)
+			}
+			out.RawByte(']')
+		}
 	}
-	if in.LeafWeight != nil {
-		const prefix string = ",\"leafWeight\":"
+	if in.Memory != nil {
+		const prefix string = ",\"memory\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint16(uint16(*in.LeafWeight))
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo30(out, *in.Memory)
 	}
-	if len(in.WeightDevice) != 0 {
-		const prefix string = ",\"weightDevice\":"
+	if in.CPU != nil {
+		const prefix string = ",\"cpu\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v153, v154 := range in.WeightDevice {
-				if v153 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo34(out, v154)
-			}
-			out.RawByte(']')
-		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo31(out, *in.CPU)
 	}
-	if len(in.ThrottleReadBpsDevice) != 0 {
-		const prefix string = ",\"throttleReadBpsDevice\":"
+	if in.Pids != nil {
+		const prefix string = ",\"pids\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v155, v156 := range in.ThrottleReadBpsDevice {
-				if v155 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(out, v156)
-			}
-			out.RawByte(']')
-		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo32(out, *in.Pids)
 	}
-	if len(in.ThrottleWriteBpsDevice) != 0 {
-		const prefix string = ",\"throttleWriteBpsDevice\":"
+	if in.BlockIO != nil {
+		const prefix string = ",\"blockIO\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v157, v158 := range in.ThrottleWriteBpsDevice {
-				if v157 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(out, v158)
-			}
-			out.RawByte(']')
-		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo1(out, *in.BlockIO)
 	}
-	if len(in.ThrottleReadIOPSDevice) != 0 {
-		const prefix string = ",\"throttleReadIOPSDevice\":"
+	if len(in.HugepageLimits) != 0 {
+		const prefix string = ",\"hugepageLimits\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -5727,37 +6399,28 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v159, v160 := range in.ThrottleReadIOPSDevice {
-				if v159 > 0 {
+			for v172, v173 := range in.HugepageLimits {
+				if v172 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(out, v160)
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo33(out, v173)
 			}
 			out.RawByte(']')
 		}
 	}
-	if len(in.ThrottleWriteIOPSDevice) != 0 {
-		const prefix string = ",\"throttleWriteIOPSDevice\":"
+	if in.Network != nil {
+		const prefix string = ",\"network\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v161, v162 := range in.ThrottleWriteIOPSDevice {
-				if v161 > 0 {
-					out.RawByte(',')
-				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(out, v162)
-			}
-			out.RawByte(']')
-		}
+		easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo34(out, *in.Network)
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(in *jlexer.Lexer, out *specs_go.LinuxThrottleDevice) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo34(in *jlexer.Lexer, out *specs_go.LinuxNetwork) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -5776,12 +6439,39 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "rate":
-			out.Rate = uint64(in.Uint64())
-		case "major":
-			out.Major = int64(in.Int64())
-		case "minor":
-			out.Minor = int64(in.Int64())
+		case "classID":
+			if in.IsNull() {
+				in.Skip()
+				out.ClassID = nil
+			} else {
+				if out.ClassID == nil {
+					out.ClassID = new(uint32)
+				}
+				*out.ClassID = uint32(in.Uint32())
+			}
+		case "priorities":
+			if in.IsNull() {
+				in.Skip()
+				out.Priorities = nil
+			} else {
+				in.Delim('[')
+				if out.Priorities == nil {
+					if !in.IsDelim(']') {
+						out.Priorities = make([]specs_go.LinuxInterfacePriority, 0, 2)
+					} else {
+						out.Priorities = []specs_go.LinuxInterfacePriority{}
+					}
+				} else {
+					out.Priorities = (out.Priorities)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v174 specs_go.LinuxInterfacePriority
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(in, &v174)
+					out.Priorities = append(out.Priorities, v174)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -5792,43 +6482,42 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(out *jwriter.Writer, in specs_go.LinuxThrottleDevice) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo34(out *jwriter.Writer, in specs_go.LinuxNetwork) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"rate\":"
+	if in.ClassID != nil {
+		const prefix string = ",\"classID\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(in.Rate))
+		out.Uint32(uint32(*in.ClassID))
 	}
-	{
-		const prefix string = ",\"major\":"
+	if len(in.Priorities) != 0 {
+		const prefix string = ",\"priorities\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int64(int64(in.Major))
-	}
-	{
-		const prefix string = ",\"minor\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v175, v176 := range in.Priorities {
+				if v175 > 0 {
+					out.RawByte(',')
+				}
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(out, v176)
+			}
+			out.RawByte(']')
 		}
-		out.Int64(int64(in.Minor))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo34(in *jlexer.Lexer, out *specs_go.LinuxWeightDevice) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(in *jlexer.Lexer, out *specs_go.LinuxInterfacePriority) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -5847,30 +6536,10 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 			continue
 		}
 		switch key {
-		case "weight":
-			if in.IsNull() {
-				in.Skip()
-				out.Weight = nil
-			} else {
-				if out.Weight == nil {
-					out.Weight = new(uint16)
-				}
-				*out.Weight = uint16(in.Uint16())
-			}
-		case "leafWeight":
-			if in.IsNull() {
-				in.Skip()
-				out.LeafWeight = nil
-			} else {
-				if out.LeafWeight == nil {
-					out.LeafWeight = new(uint16)
-				}
-				*out.LeafWeight = uint16(in.Uint16())
-			}
-		case "major":
-			out.Major = int64(in.Int64())
-		case "minor":
-			out.Minor = int64(in.Int64())
+		case "name":
+			out.Name = string(in.String())
+		case "priority":
+			out.Priority = uint32(in.Uint32())
 		default:
 			in.SkipRecursive()
 		}
@@ -5881,53 +6550,92 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo34(out *jwriter.Writer, in specs_go.LinuxWeightDevice) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo35(out *jwriter.Writer, in specs_go.LinuxInterfacePriority) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Weight != nil {
-		const prefix string = ",\"weight\":"
+	{
+		const prefix string = ",\"name\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint16(uint16(*in.Weight))
+		out.String(string(in.Name))
 	}
-	if in.LeafWeight != nil {
-		const prefix string = ",\"leafWeight\":"
+	{
+		const prefix string = ",\"priority\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint16(uint16(*in.LeafWeight))
+		out.Uint32(uint32(in.Priority))
+	}
+	out.RawByte('}')
+}
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo33(in *jlexer.Lexer, out *specs_go.LinuxHugepageLimit) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "pageSize":
+			out.Pagesize = string(in.String())
+		case "limit":
+			out.Limit = uint64(in.Uint64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
 	}
+}
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo33(out *jwriter.Writer, in specs_go.LinuxHugepageLimit) {
+	out.RawByte('{')
+	first := true
+	_ = first
 	{
-		const prefix string = ",\"major\":"
+		const prefix string = ",\"pageSize\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int64(int64(in.Major))
+		out.String(string(in.Pagesize))
 	}
 	{
-		const prefix string = ",\"minor\":"
+		const prefix string = ",\"limit\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int64(int64(in.Minor))
+		out.Uint64(uint64(in.Limit))
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo29(in *jlexer.Lexer, out *specs_go.LinuxPids) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo32(in *jlexer.Lexer, out *specs_go.LinuxPids) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -5958,7 +6666,7 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo29(out *jwriter.Writer, in specs_go.LinuxPids) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo32(out *jwriter.Writer, in specs_go.LinuxPids) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -5974,7 +6682,7 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo28(in *jlexer.Lexer, out *specs_go.LinuxCPU) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo31(in *jlexer.Lexer, out *specs_go.LinuxCPU) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -6057,7 +6765,7 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo28(out *jwriter.Writer, in specs_go.LinuxCPU) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo31(out *jwriter.Writer, in specs_go.LinuxCPU) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -6133,7 +6841,7 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo27(in *jlexer.Lexer, out *specs_go.LinuxMemory) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo30(in *jlexer.Lexer, out *specs_go.LinuxMemory) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -6232,7 +6940,7 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo27(out *jwriter.Writer, in specs_go.LinuxMemory) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo30(out *jwriter.Writer, in specs_go.LinuxMemory) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -6308,7 +7016,7 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo26(in *jlexer.Lexer, out *specs_go.LinuxDeviceCgroup) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo29(in *jlexer.Lexer, out *specs_go.LinuxDeviceCgroup) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -6363,7 +7071,7 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo26(out *jwriter.Writer, in specs_go.LinuxDeviceCgroup) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo29(out *jwriter.Writer, in specs_go.LinuxDeviceCgroup) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -6419,7 +7127,7 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(in *jlexer.Lexer, out *specs_go.LinuxIDMapping) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(in *jlexer.Lexer, out *specs_go.LinuxIDMapping) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -6454,7 +7162,7 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo18(out *jwriter.Writer, in specs_go.LinuxIDMapping) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo21(out *jwriter.Writer, in specs_go.LinuxIDMapping) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -6490,7 +7198,7 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo5(in *jlexer.Lexer, out *specs_go.Hooks) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo8(in *jlexer.Lexer, out *specs_go.Hooks) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -6525,9 +7233,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Prestart = (out.Prestart)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v163 specs_go.Hook
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(in, &v163)
-					out.Prestart = append(out.Prestart, v163)
+					var v177 specs_go.Hook
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(in, &v177)
+					out.Prestart = append(out.Prestart, v177)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -6548,9 +7256,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Poststart = (out.Poststart)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v164 specs_go.Hook
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(in, &v164)
-					out.Poststart = append(out.Poststart, v164)
+					var v178 specs_go.Hook
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(in, &v178)
+					out.Poststart = append(out.Poststart, v178)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -6571,9 +7279,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Poststop = (out.Poststop)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v165 specs_go.Hook
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(in, &v165)
-					out.Poststop = append(out.Poststop, v165)
+					var v179 specs_go.Hook
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(in, &v179)
+					out.Poststop = append(out.Poststop, v179)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -6588,7 +7296,7 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo5(out *jwriter.Writer, in specs_go.Hooks) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo8(out *jwriter.Writer, in specs_go.Hooks) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -6602,11 +7310,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v166, v167 := range in.Prestart {
-				if v166 > 0 {
+			for v180, v181 := range in.Prestart {
+				if v180 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(out, v167)
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(out, v181)
 			}
 			out.RawByte(']')
 		}
@@ -6621,11 +7329,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v168, v169 := range in.Poststart {
-				if v168 > 0 {
+			for v182, v183 := range in.Poststart {
+				if v182 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(out, v169)
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(out, v183)
 			}
 			out.RawByte(']')
 		}
@@ -6640,18 +7348,18 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v170, v171 := range in.Poststop {
-				if v170 > 0 {
+			for v184, v185 := range in.Poststop {
+				if v184 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(out, v171)
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo(out, v185)
 			}
 			out.RawByte(']')
 		}
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo4(in *jlexer.Lexer, out *specs_go.Mount) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo7(in *jlexer.Lexer, out *specs_go.Mount) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -6692,9 +7400,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Options = (out.Options)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v172 string
-					v172 = string(in.String())
-					out.Options = append(out.Options, v172)
+					var v186 string
+					v186 = string(in.String())
+					out.Options = append(out.Options, v186)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -6709,7 +7417,7 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo4(out *jwriter.Writer, in specs_go.Mount) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo7(out *jwriter.Writer, in specs_go.Mount) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -6753,18 +7461,18 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v173, v174 := range in.Options {
-				if v173 > 0 {
+			for v187, v188 := range in.Options {
+				if v187 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v174))
+				out.String(string(v188))
 			}
 			out.RawByte(']')
 		}
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(in *jlexer.Lexer, out *specs_go.Root) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo6(in *jlexer.Lexer, out *specs_go.Root) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -6797,7 +7505,7 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo3(out *jwriter.Writer, in specs_go.Root) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo6(out *jwriter.Writer, in specs_go.Root) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -6823,7 +7531,7 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 	}
 	out.RawByte('}')
 }
-func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo2(in *jlexer.Lexer, out *specs_go.Process) {
+func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo5(in *jlexer.Lexer, out *specs_go.Process) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -6872,9 +7580,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Args = (out.Args)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v175 string
-					v175 = string(in.String())
-					out.Args = append(out.Args, v175)
+					var v189 string
+					v189 = string(in.String())
+					out.Args = append(out.Args, v189)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -6895,9 +7603,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Env = (out.Env)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v176 string
-					v176 = string(in.String())
-					out.Env = append(out.Env, v176)
+					var v190 string
+					v190 = string(in.String())
+					out.Env = append(out.Env, v190)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -6930,9 +7638,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Rlimits = (out.Rlimits)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v177 specs_go.POSIXRlimit
-					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo39(in, &v177)
-					out.Rlimits = append(out.Rlimits, v177)
+					var v191 specs_go.POSIXRlimit
+					easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo39(in, &v191)
+					out.Rlimits = append(out.Rlimits, v191)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -6963,7 +7671,7 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 		in.Consumed()
 	}
 }
-func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo2(out *jwriter.Writer, in specs_go.Process) {
+func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo5(out *jwriter.Writer, in specs_go.Process) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -7009,11 +7717,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v178, v179 := range in.Args {
-				if v178 > 0 {
+			for v192, v193 := range in.Args {
+				if v192 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v179))
+				out.String(string(v193))
 			}
 			out.RawByte(']')
 		}
@@ -7028,11 +7736,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v180, v181 := range in.Env {
-				if v180 > 0 {
+			for v194, v195 := range in.Env {
+				if v194 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v181))
+				out.String(string(v195))
 			}
 			out.RawByte(']')
 		}
@@ -7067,11 +7775,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v182, v183 := range in.Rlimits {
-				if v182 > 0 {
+			for v196, v197 := range in.Rlimits {
+				if v196 > 0 {
 					out.RawByte(',')
 				}
-				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo39(out, v183)
+				easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainersRuntimeSpecSpecsGo39(out, v197)
 			}
 			out.RawByte(']')
 		}
@@ -7224,9 +7932,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Bounding = (out.Bounding)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v184 string
-					v184 = string(in.String())
-					out.Bounding = append(out.Bounding, v184)
+					var v198 string
+					v198 = string(in.String())
+					out.Bounding = append(out.Bounding, v198)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -7247,9 +7955,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Effective = (out.Effective)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v185 string
-					v185 = string(in.String())
-					out.Effective = append(out.Effective, v185)
+					var v199 string
+					v199 = string(in.String())
+					out.Effective = append(out.Effective, v199)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -7270,9 +7978,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Inheritable = (out.Inheritable)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v186 string
-					v186 = string(in.String())
-					out.Inheritable = append(out.Inheritable, v186)
+					var v200 string
+					v200 = string(in.String())
+					out.Inheritable = append(out.Inheritable, v200)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -7293,9 +8001,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Permitted = (out.Permitted)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v187 string
-					v187 = string(in.String())
-					out.Permitted = append(out.Permitted, v187)
+					var v201 string
+					v201 = string(in.String())
+					out.Permitted = append(out.Permitted, v201)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -7316,9 +8024,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.Ambient = (out.Ambient)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v188 string
-					v188 = string(in.String())
-					out.Ambient = append(out.Ambient, v188)
+					var v202 string
+					v202 = string(in.String())
+					out.Ambient = append(out.Ambient, v202)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -7347,11 +8055,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v189, v190 := range in.Bounding {
-				if v189 > 0 {
+			for v203, v204 := range in.Bounding {
+				if v203 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v190))
+				out.String(string(v204))
 			}
 			out.RawByte(']')
 		}
@@ -7366,11 +8074,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v191, v192 := range in.Effective {
-				if v191 > 0 {
+			for v205, v206 := range in.Effective {
+				if v205 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v192))
+				out.String(string(v206))
 			}
 			out.RawByte(']')
 		}
@@ -7385,11 +8093,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v193, v194 := range in.Inheritable {
-				if v193 > 0 {
+			for v207, v208 := range in.Inheritable {
+				if v207 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v194))
+				out.String(string(v208))
 			}
 			out.RawByte(']')
 		}
@@ -7404,11 +8112,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v195, v196 := range in.Permitted {
-				if v195 > 0 {
+			for v209, v210 := range in.Permitted {
+				if v209 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v196))
+				out.String(string(v210))
 			}
 			out.RawByte(']')
 		}
@@ -7423,11 +8131,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v197, v198 := range in.Ambient {
-				if v197 > 0 {
+			for v211, v212 := range in.Ambient {
+				if v211 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v198))
+				out.String(string(v212))
 			}
 			out.RawByte(']')
 		}
@@ -7473,9 +8181,9 @@ func easyjson1dbef17bDecodeGithubComContainersLibpodVendorGithubComOpencontainer
 					out.AdditionalGids = (out.AdditionalGids)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v199 uint32
-					v199 = uint32(in.Uint32())
-					out.AdditionalGids = append(out.AdditionalGids, v199)
+					var v213 uint32
+					v213 = uint32(in.Uint32())
+					out.AdditionalGids = append(out.AdditionalGids, v213)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -7526,11 +8234,11 @@ func easyjson1dbef17bEncodeGithubComContainersLibpodVendorGithubComOpencontainer
 		}
 		{
 			out.RawByte('[')
-			for v200, v201 := range in.AdditionalGids {
-				if v200 > 0 {
+			for v214, v215 := range in.AdditionalGids {
+				if v214 > 0 {
 					out.RawByte(',')
 				}
-				out.Uint32(uint32(v201))
+				out.Uint32(uint32(v215))
 			}
 			out.RawByte(']')
 		}
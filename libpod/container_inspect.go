@@ -1,8 +1,13 @@
 package libpod
 
 import (
+	"encoding/json"
+
 	"github.com/containers/libpod/pkg/inspect"
 	"github.com/cri-o/ocicni/pkg/ocicni"
+	"github.com/opencontainers/go-digest"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -54,17 +59,18 @@ func (c *Container) getContainerInspectData(size bool, driverData *inspect.Data)
 		Path:    path,
 		Args:    args,
 		State: &inspect.ContainerInspectState{
-			OciVersion: spec.Version,
-			Status:     runtimeInfo.State.String(),
-			Running:    runtimeInfo.State == ContainerStateRunning,
-			Paused:     runtimeInfo.State == ContainerStatePaused,
-			OOMKilled:  runtimeInfo.OOMKilled,
-			Dead:       runtimeInfo.State.String() == "bad state",
-			Pid:        runtimeInfo.PID,
-			ExitCode:   runtimeInfo.ExitCode,
-			Error:      "", // can't get yet
-			StartedAt:  runtimeInfo.StartedTime,
-			FinishedAt: runtimeInfo.FinishedTime,
+			OciVersion:  spec.Version,
+			Status:      runtimeInfo.State.String(),
+			Running:     runtimeInfo.State == ContainerStateRunning,
+			Paused:      runtimeInfo.State == ContainerStatePaused,
+			OOMKilled:   runtimeInfo.OOMKilled,
+			Dead:        runtimeInfo.State.String() == "bad state",
+			Pid:         runtimeInfo.PID,
+			ExitCode:    runtimeInfo.ExitCode,
+			Error:       "", // can't get yet
+			StartedAt:   runtimeInfo.StartedTime,
+			FinishedAt:  runtimeInfo.FinishedTime,
+			Healthcheck: runtimeInfo.HealthCheck,
 		},
 		ImageID:         config.RootfsImageID,
 		ImageName:       config.RootfsImageName,
@@ -77,6 +83,7 @@ func (c *Container) getContainerInspectData(size bool, driverData *inspect.Data)
 		StaticDir:       config.StaticDir,
 		LogPath:         config.LogPath,
 		Name:            config.Name,
+		RestartCount:    int32(runtimeInfo.RestartCount),
 		Driver:          driverData.Name,
 		MountLabel:      config.MountLabel,
 		EffectiveCaps:   spec.Process.Capabilities.Effective,
@@ -88,11 +95,11 @@ func (c *Container) getContainerInspectData(size bool, driverData *inspect.Data)
 		Mounts:          spec.Mounts,
 		Dependencies:    c.Dependencies(),
 		NetworkSettings: &inspect.NetworkSettings{
-			Bridge:                 "",    // TODO
-			SandboxID:              "",    // TODO - is this even relevant?
-			HairpinMode:            false, // TODO
-			LinkLocalIPv6Address:   "",    // TODO - do we even support IPv6?
-			LinkLocalIPv6PrefixLen: 0,     // TODO - do we even support IPv6?
+			Bridge:                 "",                     // TODO
+			SandboxID:              "",                     // TODO - is this even relevant?
+			HairpinMode:            false,                  // TODO
+			LinkLocalIPv6Address:   "",                     // TODO - do we even support IPv6?
+			LinkLocalIPv6PrefixLen: 0,                      // TODO - do we even support IPv6?
 			Ports:                  []ocicni.PortMapping{}, // TODO - maybe worth it to put this in Docker format?
 			SandboxKey:             "",                     // Network namespace path
 			SecondaryIPAddresses:   nil,                    // TODO - do we support this?
@@ -106,7 +113,8 @@ func (c *Container) getContainerInspectData(size bool, driverData *inspect.Data)
 			IPv6Gateway:            "",
 			MacAddress:             "", // TODO
 		},
-		IsInfra: c.IsInfra(),
+		IsInfra:      c.IsInfra(),
+		SystemdUnits: c.systemdUnits(),
 	}
 
 	// Copy port mappings into network settings
@@ -117,6 +125,24 @@ func (c *Container) getContainerInspectData(size bool, driverData *inspect.Data)
 	// Get information on the container's network namespace (if present)
 	data = c.getContainerNetworkInfo(data)
 
+	// Get the processes of a running container from its pids cgroup
+	if runtimeInfo.State == ContainerStateRunning {
+		if cgroupPath, err := c.CGroupPath(); err == nil {
+			if pids, err := cgroupPids(cgroupPath); err == nil {
+				data.State.PidsCurrent = pids.Current
+				data.State.PidsLimit = pids.Limit
+			} else {
+				logrus.Debugf("error getting processes of container %s: %v", config.ID, err)
+			}
+		}
+	}
+
+	security, err := c.getSecurityConfig()
+	if err != nil {
+		return nil, err
+	}
+	data.SecurityConfig = security
+
 	if size {
 		rootFsSize, err := c.rootFsSize()
 		if err != nil {
@@ -131,3 +157,48 @@ func (c *Container) getContainerInspectData(size bool, driverData *inspect.Data)
 	}
 	return data, nil
 }
+
+// getSecurityConfig assembles the effective security settings of the
+// container from its spec
+func (c *Container) getSecurityConfig() (*inspect.SecurityConfig, error) {
+	spec := c.config.Spec
+	security := &inspect.SecurityConfig{
+		Privileged: c.config.Privileged,
+		MountLabel: c.config.MountLabel,
+		UIDMap:     []rspec.LinuxIDMapping{},
+		GIDMap:     []rspec.LinuxIDMapping{},
+	}
+
+	if spec.Process != nil {
+		security.AppArmorProfile = spec.Process.ApparmorProfile
+		security.ProcessLabel = spec.Process.SelinuxLabel
+		security.NoNewPrivileges = spec.Process.NoNewPrivileges
+		if caps := spec.Process.Capabilities; caps != nil {
+			security.Capabilities = &inspect.SecurityCapabilities{
+				Bounding:    caps.Bounding,
+				Effective:   caps.Effective,
+				Inheritable: caps.Inheritable,
+				Permitted:   caps.Permitted,
+				Ambient:     caps.Ambient,
+			}
+		}
+	}
+
+	if spec.Linux != nil {
+		if spec.Linux.Seccomp != nil {
+			seccomp, err := json.Marshal(spec.Linux.Seccomp)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error marshalling seccomp profile of container %s", c.ID())
+			}
+			security.SeccompProfileHash = digest.FromBytes(seccomp).String()
+		}
+		if spec.Linux.UIDMappings != nil {
+			security.UIDMap = spec.Linux.UIDMappings
+		}
+		if spec.Linux.GIDMappings != nil {
+			security.GIDMap = spec.Linux.GIDMappings
+		}
+	}
+
+	return security, nil
+}
@@ -0,0 +1,54 @@
+package libpod
+
+import (
+	"testing"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSecurityConfig(t *testing.T) {
+	c := &Container{config: &ContainerConfig{
+		ID:         "test",
+		MountLabel: "system_u:object_r:container_file_t:s0:c1,c2",
+		Spec: &rspec.Spec{
+			Process: &rspec.Process{
+				NoNewPrivileges: true,
+				SelinuxLabel:    "system_u:system_r:container_t:s0:c1,c2",
+				Capabilities: &rspec.LinuxCapabilities{
+					Bounding:  []string{"CAP_CHOWN", "CAP_KILL"},
+					Effective: []string{"CAP_CHOWN"},
+				},
+			},
+			Linux: &rspec.Linux{
+				Seccomp:     &rspec.LinuxSeccomp{DefaultAction: rspec.ActErrno},
+				UIDMappings: []rspec.LinuxIDMapping{{ContainerID: 0, HostID: 100000, Size: 65536}},
+			},
+		},
+	}}
+
+	security, err := c.getSecurityConfig()
+	assert.NoError(t, err)
+	assert.True(t, security.NoNewPrivileges)
+	assert.Equal(t, "system_u:system_r:container_t:s0:c1,c2", security.ProcessLabel)
+	assert.Equal(t, c.config.MountLabel, security.MountLabel)
+	assert.Equal(t, []string{"CAP_CHOWN"}, security.Capabilities.Effective)
+	assert.Equal(t, uint32(100000), security.UIDMap[0].HostID)
+	assert.Empty(t, security.GIDMap)
+	assert.Contains(t, security.SeccompProfileHash, "sha256:")
+
+	// The hash only changes with the filter
+	hash := security.SeccompProfileHash
+	security, err = c.getSecurityConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, hash, security.SeccompProfileHash)
+	c.config.Spec.Linux.Seccomp.DefaultAction = rspec.ActKill
+	security, err = c.getSecurityConfig()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, security.SeccompProfileHash)
+
+	c.config.Spec.Linux.Seccomp = nil
+	security, err = c.getSecurityConfig()
+	assert.NoError(t, err)
+	assert.Empty(t, security.SeccompProfileHash)
+}
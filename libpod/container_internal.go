@@ -9,12 +9,17 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/containers/libpod/libpod/events"
+	"github.com/containers/libpod/pkg/cgroups"
 	"github.com/containers/libpod/pkg/chrootuser"
 	"github.com/containers/libpod/pkg/hooks"
 	"github.com/containers/libpod/pkg/hooks/exec"
+	"github.com/containers/libpod/pkg/nested"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/libpod/pkg/secrets"
 	"github.com/containers/libpod/pkg/util"
@@ -22,6 +27,7 @@ import (
 	"github.com/containers/storage/pkg/archive"
 	"github.com/containers/storage/pkg/chrootarchive"
 	"github.com/containers/storage/pkg/mount"
+	"github.com/fsnotify/fsnotify"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/opencontainers/selinux/go-selinux/label"
@@ -33,6 +39,11 @@ import (
 const (
 	// name of the directory holding the artifacts
 	artifactsDir = "artifacts"
+	// name of the directory holding the built-in volumes
+	volumesDir = "volumes"
+	// exitPollInterval is how often Wait checks the state of a container
+	// if no exit notification arrives
+	exitPollInterval = time.Second
 )
 
 var (
@@ -156,11 +167,19 @@ func (c *Container) syncContainer() error {
 		if err := c.runtime.ociRuntime.updateContainerStatus(c); err != nil {
 			return err
 		}
-		// Only save back to DB if state changed
-		if c.state.State != oldState {
+		// Only save back to DB if state changed, the change is only
+		// seen by this process in read-only mode
+		if c.state.State != oldState && !c.runtime.config().ReadOnly {
 			if err := c.save(); err != nil {
 				return err
 			}
+			c.newExitEvents(oldState)
+		}
+		// The log file is rotated as the container is synced
+		if !c.runtime.config().ReadOnly {
+			if err := c.rotateLog(); err != nil {
+				logrus.Errorf("Error rotating the log of container %s: %v", c.ID(), err)
+			}
 		}
 	}
 
@@ -191,20 +210,20 @@ func (c *Container) setupStorage(ctx context.Context) error {
 		options = &storage.ContainerOptions{c.config.IDMappings}
 
 	}
-	containerInfo, err := c.runtime.storageService.CreateContainerStorage(ctx, c.runtime.imageContext, c.config.RootfsImageName, c.config.RootfsImageID, c.config.Name, c.config.ID, c.config.MountLabel, options)
+	containerInfo, err := c.runtime.storageService.CreateContainerStorage(ctx, c.runtime.imageContext(), c.config.RootfsImageName, c.config.RootfsImageID, c.config.Name, c.config.ID, c.config.MountLabel, options)
 	if err != nil {
 		return errors.Wrapf(err, "error creating container storage")
 	}
 
 	if !rootless.IsRootless() && (len(c.config.IDMappings.UIDMap) != 0 || len(c.config.IDMappings.GIDMap) != 0) {
-		info, err := os.Stat(c.runtime.config.TmpDir)
+		info, err := os.Stat(c.runtime.config().TmpDir)
 		if err != nil {
-			return errors.Wrapf(err, "cannot stat `%s`", c.runtime.config.TmpDir)
+			return errors.Wrapf(err, "cannot stat `%s`", c.runtime.config().TmpDir)
 		}
-		if err := os.Chmod(c.runtime.config.TmpDir, info.Mode()|0111); err != nil {
-			return errors.Wrapf(err, "cannot chmod `%s`", c.runtime.config.TmpDir)
+		if err := os.Chmod(c.runtime.config().TmpDir, info.Mode()|0111); err != nil {
+			return errors.Wrapf(err, "cannot chmod `%s`", c.runtime.config().TmpDir)
 		}
-		root := filepath.Join(c.runtime.config.TmpDir, "containers-root", c.ID())
+		root := filepath.Join(c.runtime.config().TmpDir, "containers-root", c.ID())
 		if err := os.MkdirAll(root, 0755); err != nil {
 			return errors.Wrapf(err, "error creating userNS tmpdir for container %s", c.ID())
 		}
@@ -281,6 +300,12 @@ func (c *Container) teardownStorage() error {
 // It is performed before a refresh and clears the state after a reboot
 // It does not save the results - assumes the database will do that for us
 func resetState(state *containerState) error {
+	// Containers alive before the reboot were killed by it
+	if state.State == ContainerStateRunning || state.State == ContainerStatePaused {
+		state.Exited = true
+		state.ExitCode = RebootExitCode
+		state.FinishedTime = time.Now()
+	}
 	state.PID = 0
 	state.Mountpoint = ""
 	state.Mounted = false
@@ -312,14 +337,14 @@ func (c *Container) refresh() error {
 	}
 
 	if len(c.config.IDMappings.UIDMap) != 0 || len(c.config.IDMappings.GIDMap) != 0 {
-		info, err := os.Stat(c.runtime.config.TmpDir)
+		info, err := os.Stat(c.runtime.config().TmpDir)
 		if err != nil {
-			return errors.Wrapf(err, "cannot stat `%s`", c.runtime.config.TmpDir)
+			return errors.Wrapf(err, "cannot stat `%s`", c.runtime.config().TmpDir)
 		}
-		if err := os.Chmod(c.runtime.config.TmpDir, info.Mode()|0111); err != nil {
-			return errors.Wrapf(err, "cannot chmod `%s`", c.runtime.config.TmpDir)
+		if err := os.Chmod(c.runtime.config().TmpDir, info.Mode()|0111); err != nil {
+			return errors.Wrapf(err, "cannot chmod `%s`", c.runtime.config().TmpDir)
 		}
-		root := filepath.Join(c.runtime.config.TmpDir, "containers-root", c.ID())
+		root := filepath.Join(c.runtime.config().TmpDir, "containers-root", c.ID())
 		if err := os.MkdirAll(root, 0755); err != nil {
 			return errors.Wrapf(err, "error creating userNS tmpdir for container %s", c.ID())
 		}
@@ -350,6 +375,35 @@ func (c *Container) refresh() error {
 	return nil
 }
 
+// recoverState reconstructs the temporary files of the container after they
+// were lost without a reboot. The container may still be alive, so its state
+// is reconciled with the OCI runtime instead of reset.
+func (c *Container) recoverState() error {
+	if err := c.refresh(); err != nil {
+		return err
+	}
+
+	if c.state.State != ContainerStateConfigured && c.state.State != ContainerStateUnknown {
+		if err := c.runtime.ociRuntime.updateContainerStatus(c); err != nil {
+			return err
+		}
+	}
+
+	// The mount may have been lost with the temporary files
+	if c.state.Mounted {
+		mounted, err := mount.Mounted(c.state.Mountpoint)
+		if err != nil {
+			return errors.Wrapf(err, "error checking mount of container %s", c.ID())
+		}
+		if !mounted {
+			c.state.Mounted = false
+			c.state.Mountpoint = ""
+		}
+	}
+
+	return c.save()
+}
+
 // Remove conmon attach socket and terminal resize FIFO
 // This is necessary for restarting containers
 func (c *Container) removeConmonFiles() error {
@@ -425,6 +479,62 @@ func (c *Container) isStopped() (bool, error) {
 	return c.state.State == ContainerStateStopped, nil
 }
 
+// newExitWatcher returns a watcher notified when the container may have
+// exited: when conmon writes its exit file, and, on cgroup v2, when the
+// populated state of its cgroup changes
+func (c *Container) newExitWatcher() (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(c.runtime.ociRuntime.exitsDir); err != nil {
+		watcher.Close()
+		return nil, errors.Wrapf(err, "error watching exits directory %s", c.runtime.ociRuntime.exitsDir)
+	}
+	if cgroupPath, err := c.CGroupPath(); err == nil && cgroups.IsCgroup2UnifiedMode() {
+		// Not an error if this fails, the exit file is enough
+		if err := watcher.Add(filepath.Join(cgroups.V2Dir(cgroupPath), "cgroup.events")); err != nil {
+			logrus.Debugf("Not watching cgroup events of container %s: %v", c.ID(), err)
+		}
+	}
+	return watcher, nil
+}
+
+// waitForExitEvent blocks until the watcher reports an event that may mean
+// the container exited, or until exitPollInterval passed. The watcher may be
+// nil, in which case this only sleeps. If the watcher fails, the error is
+// logged and the watcher closed, and nil is returned for the caller to fall
+// back to polling; otherwise the watcher is returned. Callers must check the
+// state of the container afterwards.
+func (c *Container) waitForExitEvent(watcher *fsnotify.Watcher) *fsnotify.Watcher {
+	var (
+		events <-chan fsnotify.Event
+		errs   <-chan error
+	)
+	if watcher != nil {
+		events = watcher.Events
+		errs = watcher.Errors
+	}
+	timer := time.NewTimer(exitPollInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case event := <-events:
+			// Events for other containers' exit files are expected
+			if filepath.Dir(event.Name) == c.runtime.ociRuntime.exitsDir && filepath.Base(event.Name) != c.ID() {
+				continue
+			}
+			return watcher
+		case err := <-errs:
+			logrus.Warnf("Error watching container %s for exit, falling back to polling: %v", c.ID(), err)
+			watcher.Close()
+			return nil
+		case <-timer.C:
+			return watcher
+		}
+	}
+}
+
 // save container state to the database
 func (c *Container) save() error {
 	if err := c.runtime.state.SaveContainer(c); err != nil {
@@ -435,7 +545,9 @@ func (c *Container) save() error {
 
 // Check if a container's dependencies are running
 // Returns a []string containing the IDs of dependencies that are not running
-func (c *Container) checkDependenciesRunning() ([]string, error) {
+// Dependencies with a startup healthcheck are waited for until it passes, and
+// count as not running if they stop first
+func (c *Container) checkDependenciesRunning(ctx context.Context) ([]string, error) {
 	deps := c.Dependencies()
 	notRunning := []string{}
 
@@ -450,11 +562,11 @@ func (c *Container) checkDependenciesRunning() ([]string, error) {
 		}
 
 		// Check the status
-		state, err := depCtr.State()
+		started, err := depCtr.waitStarted(ctx)
 		if err != nil {
 			return nil, errors.Wrapf(err, "error retrieving state of dependency %s of container %s", dep, c.ID())
 		}
-		if state != ContainerStateRunning {
+		if !started {
 			notRunning = append(notRunning, dep)
 		}
 		depCtrs[dep] = depCtr
@@ -483,7 +595,7 @@ func (c *Container) checkDependenciesRunningLocked(depCtrs map[string]*Container
 			return nil, err
 		}
 
-		if depCtr.state.State != ContainerStateRunning {
+		if !depCtr.started() {
 			notRunning = append(notRunning, dep)
 		}
 	}
@@ -491,6 +603,48 @@ func (c *Container) checkDependenciesRunningLocked(depCtrs map[string]*Container
 	return notRunning, nil
 }
 
+// started returns whether the container is running and passed its startup
+// healthcheck, if it has one
+// Assumes the container is locked
+func (c *Container) started() bool {
+	if c.state.State != ContainerStateRunning {
+		return false
+	}
+	return c.config.StartupHealthCheckConfig == nil || c.state.StartupHealthCheckPassed
+}
+
+// waitStarted waits for a running container to pass its startup healthcheck
+// and returns whether it started, false if it is not running or stops first
+func (c *Container) waitStarted(ctx context.Context) (bool, error) {
+	for {
+		state, started, err := c.startedState()
+		if err != nil {
+			return false, err
+		}
+		if started || state != ContainerStateRunning {
+			return started, nil
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(exitPollInterval):
+		}
+	}
+}
+
+// startedState returns the state of the container and whether it started
+func (c *Container) startedState() (ContainerStatus, bool, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return ContainerStateUnknown, false, err
+		}
+	}
+	return c.state.State, c.started(), nil
+}
+
 func (c *Container) completeNetworkSetup() error {
 	if !c.config.PostConfigureNetNS {
 		return nil
@@ -516,6 +670,10 @@ func (c *Container) init(ctx context.Context) error {
 		return err
 	}
 
+	if err := c.runtime.ociRuntime.checkFeatures(c, spec); err != nil {
+		return err
+	}
+
 	// Save the OCI spec to disk
 	if err := c.saveSpec(spec); err != nil {
 		return err
@@ -528,13 +686,91 @@ func (c *Container) init(ctx context.Context) error {
 
 	logrus.Debugf("Created container %s in OCI runtime", c.ID())
 
+	if err := c.runtime.ociRuntime.setSystemdCpuset(c); err != nil {
+		logrus.Warnf("Failed to set the cpuset of the systemd scope of container %s: %v", c.ID(), err)
+	}
+
 	c.state.State = ContainerStateCreated
 
 	if err := c.save(); err != nil {
 		return err
 	}
 
-	return c.completeNetworkSetup()
+	if err := c.completeNetworkSetup(); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Init)
+	return nil
+}
+
+// cpuset returns the CPUs and memory nodes the container is restricted to,
+// empty if it is not
+func (c *Container) cpuset() (string, string) {
+	var cpus, mems string
+	if c.config.Spec.Linux != nil && c.config.Spec.Linux.Resources != nil && c.config.Spec.Linux.Resources.CPU != nil {
+		cpus, mems = c.config.Spec.Linux.Resources.CPU.Cpus, c.config.Spec.Linux.Resources.CPU.Mems
+	}
+	if c.state.CPUSetCPUs != "" {
+		cpus = c.state.CPUSetCPUs
+	}
+	if c.state.CPUSetMems != "" {
+		mems = c.state.CPUSetMems
+	}
+	return cpus, mems
+}
+
+// mergeBlockIO returns the block IO limits of current with the fields set in
+// update replaced. Device weights and throttles replace those of the same
+// device.
+func mergeBlockIO(current, update *spec.LinuxBlockIO) *spec.LinuxBlockIO {
+	merged := &spec.LinuxBlockIO{}
+	if current != nil {
+		*merged = *current
+	}
+	if update == nil {
+		return merged
+	}
+	if update.Weight != nil {
+		merged.Weight = update.Weight
+	}
+	if update.LeafWeight != nil {
+		merged.LeafWeight = update.LeafWeight
+	}
+
+	weightDevices := make([]spec.LinuxWeightDevice, 0, len(merged.WeightDevice)+len(update.WeightDevice))
+	for _, d := range merged.WeightDevice {
+		replaced := false
+		for _, u := range update.WeightDevice {
+			if u.Major == d.Major && u.Minor == d.Minor {
+				replaced = true
+			}
+		}
+		if !replaced {
+			weightDevices = append(weightDevices, d)
+		}
+	}
+	merged.WeightDevice = append(weightDevices, update.WeightDevice...)
+
+	mergeThrottles := func(current, update []spec.LinuxThrottleDevice) []spec.LinuxThrottleDevice {
+		throttles := make([]spec.LinuxThrottleDevice, 0, len(current)+len(update))
+		for _, d := range current {
+			replaced := false
+			for _, u := range update {
+				if u.Major == d.Major && u.Minor == d.Minor {
+					replaced = true
+				}
+			}
+			if !replaced {
+				throttles = append(throttles, d)
+			}
+		}
+		return append(throttles, update...)
+	}
+	merged.ThrottleReadBpsDevice = mergeThrottles(merged.ThrottleReadBpsDevice, update.ThrottleReadBpsDevice)
+	merged.ThrottleWriteBpsDevice = mergeThrottles(merged.ThrottleWriteBpsDevice, update.ThrottleWriteBpsDevice)
+	merged.ThrottleReadIOPSDevice = mergeThrottles(merged.ThrottleReadIOPSDevice, update.ThrottleReadIOPSDevice)
+	merged.ThrottleWriteIOPSDevice = mergeThrottles(merged.ThrottleWriteIOPSDevice, update.ThrottleWriteIOPSDevice)
+	return merged
 }
 
 // Reinitialize a container
@@ -559,6 +795,7 @@ func (c *Container) reinit(ctx context.Context) error {
 	c.state.State = ContainerStateConfigured
 	c.state.ExitCode = 0
 	c.state.Exited = false
+	c.state.OOMKilled = false
 	if err := c.save(); err != nil {
 		return err
 	}
@@ -642,22 +879,39 @@ func (c *Container) start() error {
 	logrus.Debugf("Started container %s", c.ID())
 
 	c.state.State = ContainerStateRunning
+	c.state.RestartCount = 0
+	c.state.StoppedByUser = false
+	c.state.SystemdService = currentSystemdService()
+	c.startHealthCheck()
 
-	return c.save()
+	if err := c.save(); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Start)
+	return nil
 }
 
 // Internal, non-locking function to stop container
 func (c *Container) stop(timeout uint) error {
 	logrus.Debugf("Stopping ctr %s with timeout %d", c.ID(), timeout)
 
+	oldState := c.state.State
 	if err := c.runtime.ociRuntime.stopContainer(c, timeout); err != nil {
 		return err
 	}
+	// The restart policy does not restart containers stopped by a user
+	c.state.StoppedByUser = true
 
 	// Sync the container's state to pick up return code
 	if err := c.runtime.ociRuntime.updateContainerStatus(c); err != nil {
 		return err
 	}
+	// Save the exit of the container, so that it is only reported once
+	if err := c.save(); err != nil {
+		return err
+	}
+	c.newExitEvents(oldState)
+	c.newContainerEvent(events.Stop)
 
 	// Container should clean itself up
 	return nil
@@ -665,7 +919,18 @@ func (c *Container) stop(timeout uint) error {
 
 // Internal, non-locking function to pause a container
 func (c *Container) pause() error {
-	if err := c.runtime.ociRuntime.pauseContainer(c); err != nil {
+	// Use the cgroup v2 freezer directly where it is available, as not
+	// all OCI runtimes support it, and it does not need privileges when
+	// the cgroup is delegated to a rootless user
+	freezeFile, err := c.cgroupFreezeFile()
+	if err != nil {
+		return err
+	}
+	if freezeFile != "" {
+		if err := freezeCgroup(freezeFile, true); err != nil {
+			return errors.Wrapf(err, "error freezing container %s", c.ID())
+		}
+	} else if err := c.runtime.ociRuntime.pauseContainer(c); err != nil {
 		return err
 	}
 
@@ -673,12 +938,24 @@ func (c *Container) pause() error {
 
 	c.state.State = ContainerStatePaused
 
-	return c.save()
+	if err := c.save(); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Pause)
+	return nil
 }
 
 // Internal, non-locking function to unpause a container
 func (c *Container) unpause() error {
-	if err := c.runtime.ociRuntime.unpauseContainer(c); err != nil {
+	freezeFile, err := c.cgroupFreezeFile()
+	if err != nil {
+		return err
+	}
+	if freezeFile != "" {
+		if err := freezeCgroup(freezeFile, false); err != nil {
+			return errors.Wrapf(err, "error thawing container %s", c.ID())
+		}
+	} else if err := c.runtime.ociRuntime.unpauseContainer(c); err != nil {
 		return err
 	}
 
@@ -686,7 +963,11 @@ func (c *Container) unpause() error {
 
 	c.state.State = ContainerStateRunning
 
-	return c.save()
+	if err := c.save(); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Unpause)
+	return nil
 }
 
 // Internal, non-locking function to restart a container
@@ -723,7 +1004,11 @@ func (c *Container) restartWithTimeout(ctx context.Context, timeout uint) (err e
 		}
 	}
 
-	return c.start()
+	if err := c.start(); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Restart)
+	return nil
 }
 
 // mountStorage sets up the container's root filesystem
@@ -831,6 +1116,8 @@ func (c *Container) cleanup() error {
 
 	logrus.Debugf("Cleaning up container %s", c.ID())
 
+	c.stopHealthCheck()
+
 	// Clean up network namespace, if present
 	if err := c.cleanupNetwork(); err != nil {
 		lastError = err
@@ -950,18 +1237,27 @@ func (c *Container) makeBindMounts() error {
 	}
 
 	// Make .containerenv
-	// Empty file, so no need to recreate if it exists
+	// It only records the nesting level, so no need to recreate if it
+	// exists
 	if _, ok := c.state.BindMounts["/run/.containerenv"]; !ok {
-		// Empty string for now, but we may consider populating this later
-		containerenvPath, err := c.writeStringToRundir(".containerenv", "")
+		containerenvPath, err := c.writeStringToRundir(".containerenv", nested.Containerenv())
 		if err != nil {
 			return errors.Wrapf(err, "error creating containerenv file for container %s", c.ID())
 		}
 		c.state.BindMounts["/run/.containerenv"] = containerenvPath
 	}
 
+	// Make /etc/localtime
+	if c.config.Timezone != "" {
+		localtime, err := c.generateLocaltime()
+		if err != nil {
+			return errors.Wrapf(err, "error creating localtime file for container %s", c.ID())
+		}
+		c.state.BindMounts["/etc/localtime"] = localtime
+	}
+
 	// Add Secret Mounts
-	secretMounts := secrets.SecretMountsWithUIDGID(c.config.MountLabel, c.state.RunDir, c.runtime.config.DefaultMountsFile, c.state.DestinationRunDir, c.RootUID(), c.RootGID())
+	secretMounts := secrets.SecretMountsWithUIDGID(c.config.MountLabel, c.state.RunDir, c.runtime.config().DefaultMountsFile, c.state.DestinationRunDir, c.RootUID(), c.RootGID())
 	for _, mount := range secretMounts {
 		if _, ok := c.state.BindMounts[mount.Destination]; !ok {
 			c.state.BindMounts[mount.Destination] = mount.Source
@@ -1007,8 +1303,48 @@ type resolvConf struct {
 
 // generateResolvConf generates a containers resolv.conf
 func (c *Container) generateResolvConf() (string, error) {
-	// Determine the endpoint for resolv.conf in case it is a symlink
-	resolvPath, err := filepath.EvalSymlinks("/etc/resolv.conf")
+	contents, err := c.resolvConfContents()
+	if err != nil {
+		return "", err
+	}
+	return c.writeStringToRundir("resolv.conf", contents)
+}
+
+// updateResolvConf regenerates the resolv.conf of a running container from
+// the current resolv.conf of the host. The file is rewritten in place, as
+// replacing it would leave the container with the old file bind mounted.
+func (c *Container) updateResolvConf() error {
+	contents, err := c.resolvConfContents()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(c.state.RunDir, "resolv.conf")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return errors.Wrapf(err, "unable to open %s", path)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(contents); err != nil {
+		return errors.Wrapf(err, "unable to write %s", path)
+	}
+	return nil
+}
+
+// tracksHostResolvConf returns whether the resolv.conf of the container
+// follows the one of the host, that is, whether the container uses the name
+// servers of the host and did not replace its resolv.conf with a mount
+func (c *Container) tracksHostResolvConf() bool {
+	if len(c.config.DNSServer) > 0 {
+		return false
+	}
+	return !MountExists(c.config.Spec.Mounts, "/etc/resolv.conf")
+}
+
+// resolvConfContents returns the contents of the resolv.conf of the
+// container: the resolv.conf of the host, with the DNS settings of the
+// container applied
+func (c *Container) resolvConfContents() (string, error) {
+	resolvPath, err := hostResolvConfPath(c.config.CreateNetNS || c.config.NetNsPath != "")
 	if err != nil {
 		return "", err
 	}
@@ -1017,7 +1353,7 @@ func (c *Container) generateResolvConf() (string, error) {
 		return "", errors.Wrapf(err, "unable to read %s", resolvPath)
 	}
 	if len(c.config.DNSServer) == 0 && len(c.config.DNSSearch) == 0 && len(c.config.DNSOption) == 0 {
-		return c.writeStringToRundir("resolv.conf", fmt.Sprintf("%s", orig))
+		return string(orig), nil
 	}
 
 	// Read and organize the hosts /etc/resolv.conf
@@ -1045,7 +1381,7 @@ func (c *Container) generateResolvConf() (string, error) {
 		resolv.options = nil
 		resolv.options = append(resolv.options, c.Config().DNSOption...)
 	}
-	return c.writeStringToRundir("resolv.conf", resolv.ToString())
+	return resolv.ToString(), nil
 }
 
 // createResolv creates a resolv struct from an input string
@@ -1111,31 +1447,44 @@ func (c *Container) generateHosts() (string, error) {
 	return c.writeStringToRundir("hosts", hosts)
 }
 
-func (c *Container) addLocalVolumes(ctx context.Context, g *generate.Generator) error {
-	mountPoint := c.state.Mountpoint
-	if !c.state.Mounted {
-		return errors.Wrapf(ErrInternal, "container is not mounted")
-	}
+// builtinVolumes returns the destinations of the built-in volumes of the
+// container: the volumes of its image and the built-in volumes of the
+// container passed in to --volumes-from
+func (c *Container) builtinVolumes(ctx context.Context) ([]string, error) {
 	newImage, err := c.runtime.imageRuntime.NewFromLocal(c.config.RootfsImageID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	imageData, err := newImage.Inspect(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	volumes := make(map[string]bool)
+	for vol := range imageData.ContainerConfig.Volumes {
+		volumes[vol] = true
 	}
-	// Add the built-in volumes of the container passed in to --volumes-from
 	for _, vol := range c.config.LocalVolumes {
-		if imageData.ContainerConfig.Volumes == nil {
-			imageData.ContainerConfig.Volumes = map[string]struct{}{
-				vol: {},
-			}
-		} else {
-			imageData.ContainerConfig.Volumes[vol] = struct{}{}
-		}
+		volumes[vol] = true
+	}
+	destinations := make([]string, 0, len(volumes))
+	for vol := range volumes {
+		destinations = append(destinations, vol)
 	}
+	sort.Strings(destinations)
+	return destinations, nil
+}
 
-	for k := range imageData.ContainerConfig.Volumes {
+func (c *Container) addLocalVolumes(ctx context.Context, g *generate.Generator) error {
+	mountPoint := c.state.Mountpoint
+	if !c.state.Mounted {
+		return errors.Wrapf(ErrInternal, "container is not mounted")
+	}
+	volumes, err := c.builtinVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range volumes {
 		mount := spec.Mount{
 			Destination: k,
 			Type:        "bind",
@@ -1144,7 +1493,7 @@ func (c *Container) addLocalVolumes(ctx context.Context, g *generate.Generator)
 		if MountExists(g.Mounts(), k) {
 			continue
 		}
-		volumePath := filepath.Join(c.config.StaticDir, "volumes", k)
+		volumePath := filepath.Join(c.config.StaticDir, volumesDir, k)
 		srcPath := filepath.Join(mountPoint, k)
 
 		var (
@@ -1246,7 +1595,7 @@ func (c *Container) saveSpec(spec *spec.Spec) error {
 }
 
 func (c *Container) setupOCIHooks(ctx context.Context, config *spec.Spec) (extensionStageHooks map[string][]spec.Hook, err error) {
-	if c.runtime.config.HooksDir == "" {
+	if c.runtime.config().HooksDir == "" {
 		return nil, nil
 	}
 
@@ -1277,9 +1626,9 @@ func (c *Container) setupOCIHooks(ctx context.Context, config *spec.Spec) (exten
 		}
 	}
 
-	manager, err := hooks.New(ctx, []string{c.runtime.config.HooksDir}, []string{"poststop"}, lang)
+	manager, err := hooks.New(ctx, []string{c.runtime.config().HooksDir}, []string{"poststop"}, lang)
 	if err != nil {
-		if c.runtime.config.HooksDirNotExistFatal || !os.IsNotExist(err) {
+		if c.runtime.config().HooksDirNotExistFatal || !os.IsNotExist(err) {
 			return nil, err
 		}
 		logrus.Warnf("failed to load hooks: {}", err)
@@ -1307,3 +1656,27 @@ func (c *Container) unmount(force bool) error {
 
 	return nil
 }
+
+// sandboxID returns the ID of the infra container of the pod of the
+// container, which is the sandbox of VM-isolated runtimes
+func (c *Container) sandboxID() (string, error) {
+	if c.config.IsInfra {
+		return c.ID(), nil
+	}
+	if c.config.Pod == "" {
+		return "", errors.Wrapf(ErrInvalidArg, "container %s of runtime handler %q is not in a pod", c.ID(), c.config.RuntimeHandler)
+	}
+	// Do not lock the pod, it may be locked while starting its
+	// containers. Its infra container does not change.
+	pod, err := c.runtime.state.Pod(c.config.Pod)
+	if err != nil {
+		return "", errors.Wrapf(err, "error retrieving pod %s of container %s", c.config.Pod, c.ID())
+	}
+	if err := c.runtime.state.UpdatePod(pod); err != nil {
+		return "", errors.Wrapf(err, "error retrieving state of pod %s", pod.ID())
+	}
+	if pod.state.InfraContainerID == "" {
+		return "", errors.Wrapf(ErrInvalidArg, "pod %s of container %s has no infra container", pod.ID(), c.ID())
+	}
+	return pod.state.InfraContainerID, nil
+}
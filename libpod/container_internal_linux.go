@@ -5,12 +5,16 @@ package libpod
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 
 	crioAnnotations "github.com/containers/libpod/pkg/annotations"
+	"github.com/containers/libpod/pkg/cgroups"
 	"github.com/containers/libpod/pkg/chrootuser"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/storage/pkg/idtools"
@@ -22,6 +26,13 @@ import (
 	"golang.org/x/sys/unix"
 )
 
+const (
+	// freezeRetries and freezeRetryInterval bound how long to wait for
+	// the kernel to freeze or thaw a cgroup
+	freezeRetries       = 100
+	freezeRetryInterval = 10 * time.Millisecond
+)
+
 func (c *Container) mountSHM(shmOptions string) error {
 	if err := unix.Mount("shm", c.config.ShmDir, "tmpfs", unix.MS_NOEXEC|unix.MS_NOSUID|unix.MS_NODEV,
 		label.FormatMountLabel(shmOptions, c.config.MountLabel)); err != nil {
@@ -48,15 +59,19 @@ func (c *Container) prepare() (err error) {
 	}
 
 	// Set up network namespace if not already set up
+	var netErr error
 	if c.config.CreateNetNS && c.state.NetNS == nil && !c.config.PostConfigureNetNS {
-		if err := c.runtime.createNetNS(c); err != nil {
-			// Tear down storage before exiting to make sure we
-			// don't leak mounts
-			if err2 := c.cleanupStorage(); err2 != nil {
-				logrus.Errorf("Error cleaning up storage for container %s: %v", c.ID(), err2)
-			}
-			return err
+		netErr = c.runtime.createNetNS(c)
+	} else if c.config.NetNsPath != "" && c.state.NetNS == nil {
+		netErr = c.runtime.joinExternalNetNS(c)
+	}
+	if netErr != nil {
+		// Tear down storage before exiting to make sure we
+		// don't leak mounts
+		if err2 := c.cleanupStorage(); err2 != nil {
+			logrus.Errorf("Error cleaning up storage for container %s: %v", c.ID(), err2)
 		}
+		return netErr
 	}
 
 	return nil
@@ -70,7 +85,12 @@ func (c *Container) cleanupNetwork() error {
 	}
 
 	// Stop the container's network namespace (if it has one)
-	if err := c.runtime.teardownNetNS(c); err != nil {
+	// Namespaces libpod did not create are only closed
+	if c.config.NetNsPath != "" {
+		if err := c.runtime.closeNetNS(c); err != nil {
+			logrus.Errorf("unable to close network namespace for container %s: %q", c.ID(), err)
+		}
+	} else if err := c.runtime.teardownNetNS(c); err != nil {
 		logrus.Errorf("unable to cleanup network for container %s: %q", c.ID(), err)
 	}
 
@@ -96,6 +116,8 @@ func (c *Container) generateSpec(ctx context.Context) (*spec.Spec, error) {
 		} else {
 			g.AddOrReplaceLinuxNamespace(spec.NetworkNamespace, c.state.NetNS.Path())
 		}
+	} else if c.config.NetNsPath != "" {
+		g.AddOrReplaceLinuxNamespace(spec.NetworkNamespace, c.config.NetNsPath)
 	}
 
 	// Remove the default /dev/shm mount to ensure we overwrite it
@@ -109,6 +131,10 @@ func (c *Container) generateSpec(ctx context.Context) (*spec.Spec, error) {
 			Destination: dstPath,
 			Options:     []string{"rw", "bind", "private"},
 		}
+		// /etc/localtime may be the zoneinfo file of the host
+		if dstPath == "/etc/localtime" {
+			newMount.Options[0] = "ro"
+		}
 		if !MountExists(g.Mounts(), dstPath) {
 			g.AddMount(newMount)
 		} else {
@@ -214,6 +240,25 @@ func (c *Container) generateSpec(ctx context.Context) (*spec.Spec, error) {
 	g.SetRootPath(c.state.RealMountpoint)
 	g.AddAnnotation(crioAnnotations.Created, c.config.CreatedTime.Format(time.RFC3339Nano))
 	g.AddAnnotation("org.opencontainers.image.stopSignal", fmt.Sprintf("%d", c.config.StopSignal))
+	switch c.config.RuntimeHandler {
+	case WasmRuntimeHandler:
+		if _, ok := g.Config.Annotations[wasmHandlerAnnotation]; !ok {
+			g.AddAnnotation(wasmHandlerAnnotation, WasmRuntimeHandler)
+		}
+	case KataRuntimeHandler:
+		// Kata starts a VM for the sandbox, the infra container of the
+		// pod, and runs the other containers of the pod in it
+		sandboxID, err := c.sandboxID()
+		if err != nil {
+			return nil, err
+		}
+		containerType := crioAnnotations.ContainerTypeContainer
+		if sandboxID == c.ID() {
+			containerType = crioAnnotations.ContainerTypeSandbox
+		}
+		g.AddAnnotation(crioAnnotations.ContainerType, containerType)
+		g.AddAnnotation(crioAnnotations.SandboxID, sandboxID)
+	}
 
 	for _, i := range c.config.Spec.Linux.Namespaces {
 		if string(i.Type) == spec.UTSNamespace {
@@ -236,9 +281,9 @@ func (c *Container) generateSpec(ctx context.Context) (*spec.Spec, error) {
 		g.AddProcessEnv("container", "libpod")
 	}
 
-	if rootless.IsRootless() {
+	if rootless.IsRootless() && c.runtime.config().CgroupManager != SystemdCgroupsManager {
 		g.SetLinuxCgroupsPath("")
-	} else if c.runtime.config.CgroupManager == SystemdCgroupsManager {
+	} else if c.runtime.config().CgroupManager == SystemdCgroupsManager {
 		// When runc is set to use Systemd as a cgroup manager, it
 		// expects cgroups to be passed as follows:
 		// slice:prefix:name
@@ -254,6 +299,21 @@ func (c *Container) generateSpec(ctx context.Context) (*spec.Spec, error) {
 		g.SetLinuxCgroupsPath(cgroupPath)
 	}
 
+	// Block IO limits and cpusets updated since the container was created
+	// replace those it was created with
+	if c.state.BlockIO != nil && !rootless.IsRootless() {
+		if g.Config.Linux.Resources == nil {
+			g.Config.Linux.Resources = &spec.LinuxResources{}
+		}
+		g.Config.Linux.Resources.BlockIO = c.state.BlockIO
+	}
+	if c.state.CPUSetCPUs != "" && !rootless.IsRootless() {
+		g.SetLinuxResourcesCPUCpus(c.state.CPUSetCPUs)
+	}
+	if c.state.CPUSetMems != "" && !rootless.IsRootless() {
+		g.SetLinuxResourcesCPUMems(c.state.CPUSetMems)
+	}
+
 	// Mounts need to be sorted so paths will not cover other paths
 	mounts := sortMounts(g.Mounts())
 	g.ClearMounts()
@@ -282,3 +342,83 @@ func (c *Container) addNamespaceContainer(g *generate.Generator, ns LinuxNS, ctr
 
 	return nil
 }
+
+// cgroupFreezeFile returns the cgroup.freeze file of the cgroup v2 cgroup of
+// the container, or "" if the container is not in a cgroup v2 hierarchy.
+// The cgroup is looked up from the init process of the container, so this
+// also finds cgroups in systemd-managed user scopes of rootless containers.
+func (c *Container) cgroupFreezeFile() (string, error) {
+	if !cgroups.IsCgroup2UnifiedMode() {
+		return "", nil
+	}
+	cgroupFile := fmt.Sprintf("/proc/%d/cgroup", c.state.PID)
+	contents, err := ioutil.ReadFile(cgroupFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading cgroups of container %s", c.ID())
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(contents)), "\n") {
+		// The cgroup v2 hierarchy is listed as 0::/path
+		if !strings.HasPrefix(line, "0::") {
+			continue
+		}
+		freezeFile := filepath.Join(cgroups.V2Dir(strings.TrimPrefix(line, "0::")), "cgroup.freeze")
+		if _, err := os.Stat(freezeFile); err != nil {
+			if os.IsNotExist(err) {
+				return "", nil
+			}
+			return "", errors.Wrapf(err, "error checking freezer of container %s", c.ID())
+		}
+		return freezeFile, nil
+	}
+	return "", nil
+}
+
+// cgroupFrozen returns whether the cgroup v2 cgroup of the container is
+// frozen. It is false if the container is not in a cgroup v2 hierarchy.
+func (c *Container) cgroupFrozen() (bool, error) {
+	freezeFile, err := c.cgroupFreezeFile()
+	if err != nil || freezeFile == "" {
+		return false, err
+	}
+	return cgroupEventSet(filepath.Join(filepath.Dir(freezeFile), "cgroup.events"), "frozen")
+}
+
+// freezeCgroup freezes or thaws the cgroup of the given cgroup.freeze file,
+// and waits until the kernel reports it done
+func freezeCgroup(freezeFile string, freeze bool) error {
+	value := "0"
+	if freeze {
+		value = "1"
+	}
+	if err := ioutil.WriteFile(freezeFile, []byte(value), 0); err != nil {
+		return errors.Wrapf(err, "error writing %s", freezeFile)
+	}
+
+	eventsFile := filepath.Join(filepath.Dir(freezeFile), "cgroup.events")
+	for i := 0; i < freezeRetries; i++ {
+		frozen, err := cgroupEventSet(eventsFile, "frozen")
+		if err != nil {
+			return err
+		}
+		if frozen == freeze {
+			return nil
+		}
+		time.Sleep(freezeRetryInterval)
+	}
+	return errors.Errorf("timed out waiting for %s to be %s", eventsFile, value)
+}
+
+// cgroupEventSet returns whether the given key of a cgroup.events file is 1
+func cgroupEventSet(eventsFile, key string) (bool, error) {
+	contents, err := ioutil.ReadFile(eventsFile)
+	if err != nil {
+		return false, errors.Wrapf(err, "error reading %s", eventsFile)
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			return fields[1] == "1", nil
+		}
+	}
+	return false, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -17,6 +18,93 @@ import (
 // hookPath is the path to an example hook executable.
 var hookPath string
 
+func TestResetStateMarksRunningContainersExited(t *testing.T) {
+	running := &containerState{State: ContainerStateRunning, PID: 1234}
+	assert.NoError(t, resetState(running))
+	assert.Equal(t, ContainerStateConfigured, running.State)
+	assert.True(t, running.Exited)
+	assert.Equal(t, int32(RebootExitCode), running.ExitCode)
+	assert.Equal(t, 0, running.PID)
+
+	created := &containerState{State: ContainerStateCreated}
+	assert.NoError(t, resetState(created))
+	assert.Equal(t, ContainerStateConfigured, created.State)
+	assert.False(t, created.Exited)
+}
+
+func TestRuntimePathOfHandler(t *testing.T) {
+	r := &OCIRuntime{path: "/usr/bin/runc", wasmPath: "/usr/bin/crun-wasm"}
+	c := &Container{config: &ContainerConfig{}}
+	assert.Equal(t, "/usr/bin/runc", r.runtimePath(c))
+
+	c.config.RuntimeHandler = WasmRuntimeHandler
+	assert.Equal(t, "/usr/bin/crun-wasm", r.runtimePath(c))
+
+	// A handler whose runtime was not found has no path
+	c.config.RuntimeHandler = KataRuntimeHandler
+	assert.Equal(t, "", r.runtimePath(c))
+
+	assert.NoError(t, validRuntimeHandler(KataRuntimeHandler))
+	assert.Error(t, validRuntimeHandler("gvisor"))
+}
+
+func TestSandboxIDOfInfraContainer(t *testing.T) {
+	c := &Container{config: &ContainerConfig{ID: "infra", IsInfra: true, RuntimeHandler: KataRuntimeHandler}}
+	id, err := c.sandboxID()
+	assert.NoError(t, err)
+	assert.Equal(t, "infra", id)
+
+	c.config.IsInfra = false
+	_, err = c.sandboxID()
+	assert.Error(t, err)
+}
+
+func TestTracksHostResolvConf(t *testing.T) {
+	c := &Container{config: &ContainerConfig{Spec: &rspec.Spec{}}}
+	assert.True(t, c.tracksHostResolvConf())
+
+	c.config.DNSSearch = []string{"example.com"}
+	assert.True(t, c.tracksHostResolvConf())
+
+	c.config.DNSServer = []net.IP{net.ParseIP("192.0.2.1")}
+	assert.False(t, c.tracksHostResolvConf())
+
+	c.config.DNSServer = nil
+	c.config.Spec.Mounts = []rspec.Mount{{Destination: "/etc/resolv.conf", Source: "/tmp/resolv.conf"}}
+	assert.False(t, c.tracksHostResolvConf())
+}
+
+func TestUpdateResolvConfRewritesInPlace(t *testing.T) {
+	if _, err := os.Stat(hostResolvConf); err != nil {
+		t.Skipf("%s is required: %v", hostResolvConf, err)
+	}
+	dir, err := ioutil.TempDir("", "libpod_test_")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "resolv.conf")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("nameserver 192.0.2.1\nnameserver 192.0.2.2\n"), 0644))
+	// The container sees the file through a bind mount, i.e. by inode
+	mounted, err := os.Open(path)
+	assert.NoError(t, err)
+	defer mounted.Close()
+
+	c := &Container{
+		config: &ContainerConfig{
+			Spec:      &rspec.Spec{},
+			DNSServer: []net.IP{net.ParseIP("198.51.100.1")},
+			DNSSearch: []string{"example.com"},
+			DNSOption: []string{"ndots:2"},
+		},
+		state: &containerState{RunDir: dir},
+	}
+	assert.NoError(t, c.updateResolvConf())
+
+	contents, err := ioutil.ReadAll(mounted)
+	assert.NoError(t, err)
+	assert.Equal(t, "search example.com\nnameserver 198.51.100.1\noptions ndots:2\n", string(contents))
+}
+
 func TestPostDeleteHooks(t *testing.T) {
 	ctx := context.Background()
 	dir, err := ioutil.TempDir("", "libpod_test_")
@@ -79,3 +167,43 @@ func init() {
 		panic("we need a reliable executable path on Windows")
 	}
 }
+
+func TestMergeBlockIO(t *testing.T) {
+	throttle := func(major, minor int64, rate uint64) rspec.LinuxThrottleDevice {
+		d := rspec.LinuxThrottleDevice{Rate: rate}
+		d.Major, d.Minor = major, minor
+		return d
+	}
+	weight, newWeight := uint16(500), uint16(100)
+	current := &rspec.LinuxBlockIO{
+		Weight: &weight,
+		ThrottleReadBpsDevice: []rspec.LinuxThrottleDevice{
+			throttle(8, 0, 1024),
+			throttle(8, 16, 2048),
+		},
+	}
+	update := &rspec.LinuxBlockIO{
+		ThrottleReadBpsDevice: []rspec.LinuxThrottleDevice{
+			throttle(8, 16, 4096),
+		},
+		ThrottleWriteIOPSDevice: []rspec.LinuxThrottleDevice{
+			throttle(8, 0, 100),
+		},
+	}
+
+	merged := mergeBlockIO(current, update)
+	assert.Equal(t, uint16(500), *merged.Weight)
+	assert.Equal(t, []rspec.LinuxThrottleDevice{
+		throttle(8, 0, 1024),
+		throttle(8, 16, 4096),
+	}, merged.ThrottleReadBpsDevice)
+	assert.Len(t, merged.ThrottleWriteIOPSDevice, 1)
+	// The current limits are left alone
+	assert.Equal(t, uint64(2048), current.ThrottleReadBpsDevice[1].Rate)
+
+	merged = mergeBlockIO(merged, &rspec.LinuxBlockIO{Weight: &newWeight})
+	assert.Equal(t, uint16(100), *merged.Weight)
+	assert.Len(t, merged.ThrottleReadBpsDevice, 2)
+
+	assert.Equal(t, uint16(100), *mergeBlockIO(nil, &rspec.LinuxBlockIO{Weight: &newWeight}).Weight)
+}
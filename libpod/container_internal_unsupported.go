@@ -27,3 +27,15 @@ func (c *Container) cleanupNetwork() error {
 func (c *Container) generateSpec(ctx context.Context) (*spec.Spec, error) {
 	return nil, ErrNotImplemented
 }
+
+func (c *Container) cgroupFreezeFile() (string, error) {
+	return "", nil
+}
+
+func (c *Container) cgroupFrozen() (bool, error) {
+	return false, nil
+}
+
+func freezeCgroup(freezeFile string, freeze bool) error {
+	return ErrNotImplemented
+}
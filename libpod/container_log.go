@@ -0,0 +1,172 @@
+package libpod
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// LogPaths returns the paths of the rotated segments of the log file of the
+// container, oldest first, followed by the path of the log file itself
+func (c *Container) LogPaths() []string {
+	return logPaths(c.config.LogPath, c.config.LogMaxFiles)
+}
+
+// logPaths returns the paths of the existing rotated segments of a log file,
+// oldest first, followed by the path of the log file
+func logPaths(path string, maxFiles uint) []string {
+	var paths []string
+	for i := maxFiles; i > 0; i-- {
+		segment := logSegment(path, i)
+		if _, err := os.Stat(segment); err == nil {
+			paths = append(paths, segment)
+		}
+	}
+	return append(paths, path)
+}
+
+// logSegment returns the path of the nth rotated segment of a log file
+func logSegment(path string, n uint) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// logRotateInterval is the interval at which RotateLogs checks the size of
+// the log files of the running containers
+const logRotateInterval = 10 * time.Second
+
+// errLogCollapseUnsupported is returned when the filesystem of a log file
+// cannot remove a range from its start
+var errLogCollapseUnsupported = errors.New("collapsing log files is not supported")
+
+// RotateLogs rotates the log files of the running containers reaching their
+// maximum size every logRotateInterval, until the context is done. Containers
+// are otherwise only rotated as podman commands sync them.
+func (r *Runtime) RotateLogs(ctx context.Context) error {
+	ticker := time.NewTicker(logRotateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.rotateLogs()
+		}
+	}
+}
+
+// rotateLogs rotates the log files of the running containers reaching their
+// maximum size
+func (r *Runtime) rotateLogs() {
+	ctrs, err := r.GetRunningContainers()
+	if err != nil {
+		logrus.Errorf("Unable to rotate the logs of containers: %v", err)
+		return
+	}
+	for _, ctr := range ctrs {
+		if ctr.config.LogMaxSize <= 0 {
+			continue
+		}
+		ctr.lock.Lock()
+		if err := ctr.rotateLog(); err != nil {
+			logrus.Errorf("Error rotating the log of container %s: %v", ctr.ID(), err)
+		}
+		ctr.lock.Unlock()
+	}
+}
+
+// rotateLog rotates the log file of the container once it reaches the maximum
+// size set for it
+func (c *Container) rotateLog() error {
+	return rotateLog(c.config.LogPath, c.config.LogMaxSize, c.config.LogMaxFiles)
+}
+
+// rotateLog rotates a log file larger than maxSize, keeping maxFiles files
+// with it. As conmon keeps the log file open in append mode, its content is
+// moved to the first segment by moveLog rather than the file renamed.
+func rotateLog(path string, maxSize int64, maxFiles uint) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "error checking the size of log file %s", path)
+	}
+	if info.Size() < maxSize {
+		return nil
+	}
+
+	// The log file counts in the files kept, without segments the log is
+	// truncated
+	if maxFiles <= 1 {
+		if err := os.Truncate(path, 0); err != nil {
+			return errors.Wrapf(err, "error truncating log file %s", path)
+		}
+		return nil
+	}
+	if err := os.Remove(logSegment(path, maxFiles-1)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error removing the oldest segment of log file %s", path)
+	}
+	for i := maxFiles - 2; i > 0; i-- {
+		if err := os.Rename(logSegment(path, i), logSegment(path, i+1)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error rotating log file %s", path)
+		}
+	}
+	return moveLog(path, logSegment(path, 1), info.Mode())
+}
+
+// moveLog moves the content of a log file to its first segment. The whole
+// blocks at the start of the file are collapsed out of it, keeping the lines
+// conmon appends meanwhile, and the rest is left in the log file. Where the
+// filesystem cannot collapse them, the file is copied until no more lines are
+// appended to it and truncated.
+func moveLog(path, segment string, mode os.FileMode) error {
+	src, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return errors.Wrapf(err, "error opening log file %s", path)
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(segment, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return errors.Wrapf(err, "error creating log segment %s", segment)
+	}
+	defer dst.Close()
+
+	length, err := collapsibleLogLength(src)
+	if err != nil {
+		return err
+	}
+	if length > 0 {
+		if _, err := io.CopyN(dst, src, length); err != nil {
+			return errors.Wrapf(err, "error copying log file %s to %s", path, segment)
+		}
+		err := collapseLog(src, length)
+		if err == nil {
+			return dst.Close()
+		}
+		if err != errLogCollapseUnsupported {
+			return err
+		}
+	}
+
+	for {
+		n, err := io.Copy(dst, src)
+		if err != nil {
+			return errors.Wrapf(err, "error copying log file %s to %s", path, segment)
+		}
+		if n == 0 {
+			break
+		}
+	}
+	if err := src.Truncate(0); err != nil {
+		return errors.Wrapf(err, "error truncating log file %s", path)
+	}
+	return dst.Close()
+}
@@ -0,0 +1,40 @@
+// +build linux
+
+package libpod
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// collapsibleLogLength returns the length of the longest prefix of a log file
+// made of whole filesystem blocks which can be collapsed out of it. The
+// collapsed range may not reach the end of the file.
+func collapsibleLogLength(file *os.File) (int64, error) {
+	var st unix.Stat_t
+	if err := unix.Fstat(int(file.Fd()), &st); err != nil {
+		return 0, errors.Wrapf(err, "error checking the size of log file %s", file.Name())
+	}
+	if st.Blksize <= 0 || st.Size <= 0 {
+		return 0, nil
+	}
+	blockSize := int64(st.Blksize)
+	return (st.Size - 1) / blockSize * blockSize, nil
+}
+
+// collapseLog removes the first length bytes of a log file in place, so that
+// writes appended to it meanwhile by conmon are kept. It returns
+// errLogCollapseUnsupported if the filesystem cannot collapse the range.
+func collapseLog(file *os.File, length int64) error {
+	err := unix.Fallocate(int(file.Fd()), unix.FALLOC_FL_COLLAPSE_RANGE, 0, length)
+	switch err {
+	case nil:
+		return nil
+	case unix.EOPNOTSUPP, unix.EINVAL:
+		return errLogCollapseUnsupported
+	default:
+		return errors.Wrapf(err, "error collapsing log file %s", file.Name())
+	}
+}
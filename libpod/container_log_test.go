@@ -0,0 +1,85 @@
+package libpod
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ctr.log")
+
+	readLog := func(path string) string {
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	// Missing log files and logs without a maximum size are not rotated
+	require.NoError(t, rotateLog(path, 4, 3))
+	require.NoError(t, ioutil.WriteFile(path, []byte("first\n"), 0600))
+	require.NoError(t, rotateLog(path, 0, 3))
+	require.NoError(t, rotateLog(path, 10, 3))
+	assert.Equal(t, []string{path}, logPaths(path, 3))
+
+	require.NoError(t, rotateLog(path, 4, 3))
+	assert.Equal(t, "", readLog(path))
+	assert.Equal(t, "first\n", readLog(path+".1"))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("second\n"), 0600))
+	require.NoError(t, rotateLog(path, 4, 3))
+	require.NoError(t, ioutil.WriteFile(path, []byte("third\n"), 0600))
+	require.NoError(t, rotateLog(path, 4, 3))
+	require.NoError(t, ioutil.WriteFile(path, []byte("fourth\n"), 0600))
+	// The oldest segment is dropped
+	assert.Equal(t, []string{path + ".2", path + ".1", path}, logPaths(path, 3))
+	assert.Equal(t, "second\n", readLog(path+".2"))
+	assert.Equal(t, "third\n", readLog(path+".1"))
+	assert.Equal(t, "fourth\n", readLog(path))
+
+	// With a single file, the log is truncated
+	require.NoError(t, rotateLog(path, 4, 1))
+	assert.Equal(t, "", readLog(path))
+	assert.Equal(t, "third\n", readLog(path+".1"))
+}
+
+func TestRotateLogKeepsLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ctr.log")
+
+	// Logs spanning several blocks are collapsed where the filesystem
+	// supports it, in any case no line is lost
+	var content []byte
+	for i := 0; len(content) < 3*4096+100; i++ {
+		content = append(content, []byte(fmt.Sprintf("line %d\n", i))...)
+	}
+	require.NoError(t, ioutil.WriteFile(path, content, 0600))
+	require.NoError(t, rotateLog(path, 4096, 3))
+
+	segment, err := ioutil.ReadFile(path + ".1")
+	require.NoError(t, err)
+	rest, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(segment)+string(rest))
+}
+
+func TestWithLogDriver(t *testing.T) {
+	ctr := &Container{config: &ContainerConfig{}}
+	require.NoError(t, WithLogDriver(JSONLogDriver)(ctr))
+	assert.Equal(t, KubernetesLogDriver, ctr.config.LogDriver)
+	assert.Equal(t, KubernetesLogDriver, ctr.LogDriver())
+
+	err := WithLogDriver("journald")(ctr)
+	assert.Equal(t, ErrInvalidArg, errors.Cause(err))
+}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package libpod
+
+import "os"
+
+func collapsibleLogLength(file *os.File) (int64, error) {
+	return 0, nil
+}
+
+func collapseLog(file *os.File, length int64) error {
+	return errLogCollapseUnsupported
+}
@@ -2,6 +2,8 @@ package libpod
 
 import (
 	"errors"
+
+	"github.com/containers/libpod/libpod/image"
 )
 
 var (
@@ -45,6 +47,9 @@ var (
 	// ErrRuntimeStopped indicates that the runtime has already been shut
 	// down and no further operations can be performed on it
 	ErrRuntimeStopped = errors.New("runtime has already been stopped")
+	// ErrRuntimeReadOnly indicates that the runtime is in read-only mode and
+	// the requested operation would modify a container, pod or image
+	ErrRuntimeReadOnly = errors.New("runtime is in read-only mode")
 	// ErrCtrStopped indicates that the requested container is not running
 	// and the requested operation cannot be performed until it is started
 	ErrCtrStopped = errors.New("container is stopped")
@@ -74,4 +79,28 @@ var (
 	// ErrOSNotSupported indicates the function is not available on the particular
 	// OS.
 	ErrOSNotSupported = errors.New("No support for this OS yet")
+
+	// ErrRuntimeUnsupported indicates the OCI runtime does not support a
+	// feature the container requires
+	ErrRuntimeUnsupported = errors.New("OCI runtime does not support the requested feature")
+
+	// ErrNameReserved indicates that a name is reserved for a container or
+	// pod created with the token of its reservation
+	ErrNameReserved = errors.New("name is reserved")
+	// ErrNoSuchNameReservation indicates that a name is not reserved
+	ErrNoSuchNameReservation = errors.New("no such name reservation")
+
+	// ErrNoSuchNetwork indicates the requested CNI network is not
+	// configured
+	ErrNoSuchNetwork = errors.New("no such network")
+	// ErrNetworkExists indicates a CNI network with the same name is
+	// already configured
+	ErrNetworkExists = errors.New("network already exists")
+	// ErrNetworkInUse indicates a CNI network cannot be removed while
+	// containers use it
+	ErrNetworkInUse = errors.New("network is in use")
+
+	// ErrStorageQuota indicates that the storage reached its quota, so no
+	// image can be pulled nor container created
+	ErrStorageQuota = image.ErrStorageQuota
 )
@@ -0,0 +1,95 @@
+package libpod
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/containers/libpod/libpod/events"
+	"github.com/sirupsen/logrus"
+)
+
+// newContainerEvent writes an event of the container, logging errors
+func (c *Container) newContainerEvent(status events.Status) {
+	c.writeContainerEvent(status, nil)
+}
+
+// newContainerExitedEvent writes the died event of the container, with its
+// exit code
+func (c *Container) newContainerExitedEvent(exitCode int32) {
+	c.writeContainerEvent(events.Died, map[string]string{
+		"exitCode": strconv.Itoa(int(exitCode)),
+	})
+}
+
+// newExitEvents writes the oom and died events of a container seen exiting
+// since it was in oldState
+func (c *Container) newExitEvents(oldState ContainerStatus) {
+	if c.state.State != ContainerStateStopped ||
+		(oldState != ContainerStateRunning && oldState != ContainerStatePaused) {
+		return
+	}
+	if c.state.OOMKilled {
+		c.newContainerEvent(events.OOM)
+	}
+	c.newContainerExitedEvent(c.state.ExitCode)
+}
+
+// writeContainerEvent writes an event of the container, with its labels and
+// pod as attributes along with the given ones
+func (c *Container) writeContainerEvent(status events.Status, attributes map[string]string) {
+	e := events.NewEvent(status)
+	e.ID = c.ID()
+	e.Name = c.Name()
+	e.Image = c.config.RootfsImageName
+	e.Type = events.Container
+	e.Attributes = make(map[string]string)
+	for key, value := range c.config.Labels {
+		e.Attributes[key] = value
+	}
+	if c.config.Pod != "" {
+		podName := ""
+		if pod, err := c.runtime.state.Pod(c.config.Pod); err == nil {
+			podName = pod.Name()
+		}
+		for key, value := range events.PodAttributes(c.config.Pod, podName) {
+			e.Attributes[key] = value
+		}
+	}
+	for key, value := range attributes {
+		e.Attributes[key] = value
+	}
+	if err := c.runtime.eventer.Write(e); err != nil {
+		logrus.Errorf("Unable to write %s event of container %s: %v", status, c.ID(), err)
+	}
+}
+
+// newPodEvent writes an event of the pod, logging errors
+func (p *Pod) newPodEvent(status events.Status) {
+	e := events.NewEvent(status)
+	e.ID = p.ID()
+	e.Name = p.Name()
+	e.Type = events.Pod
+	if err := p.runtime.eventer.Write(e); err != nil {
+		logrus.Errorf("Unable to write %s event of pod %s: %v", status, p.ID(), err)
+	}
+}
+
+// Events reads the events of the runtime matching the options, sending them
+// to their event channel, which is closed once done
+func (r *Runtime) Events(options events.ReadOptions) error {
+	return r.eventer.Read(options)
+}
+
+// DispatchEvents posts the events of the runtime to the webhooks of its
+// configuration until the context is done. It returns at once if there are no
+// webhooks.
+func (r *Runtime) DispatchEvents(ctx context.Context) error {
+	if len(r.config().EventWebhooks) == 0 {
+		return nil
+	}
+	dispatcher, err := events.NewDispatcher(r.eventer, r.config().EventWebhooks)
+	if err != nil {
+		return err
+	}
+	return dispatcher.Run(ctx)
+}
@@ -0,0 +1,159 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// EventerType describes the backend events are written to and read from
+type EventerType int
+
+const (
+	// LogFile keeps the events in a file, one JSON event per line
+	LogFile EventerType = iota
+	// Journald keeps the events in the systemd journal
+	Journald
+	// Null discards the events
+	Null
+)
+
+// Eventer writes events to a backend and reads them back
+type Eventer interface {
+	// Write writes an event to the backend
+	Write(event Event) error
+	// Read sends the events of the backend matching the options to their
+	// event channel, which it closes once done
+	Read(options ReadOptions) error
+}
+
+// EventerOptions describe the backend of an eventer
+type EventerOptions struct {
+	// EventerType is the backend, "file", "journald" or "none"
+	EventerType string
+	// LogFilePath is the path of the events file of the file backend
+	LogFilePath string
+}
+
+// ReadOptions describe the events to read
+type ReadOptions struct {
+	// Context stops the reading of events once done, including streaming
+	Context context.Context
+	// EventChannel receives the events read, it is closed once done
+	EventChannel chan *Event
+	// Filters are the filters events must match, as KEY=VALUE
+	Filters []string
+	// Since is the time from which events are read, if not zero
+	Since time.Time
+	// Until is the time until which events are read, if not zero. Once
+	// it is reached, streaming stops.
+	Until time.Time
+	// Stream reads the events written after those already kept, until
+	// the context is done
+	Stream bool
+	// Limit is the maximum number of events read, if not zero. It only
+	// applies to reads without Stream, as do Offset and Cursor.
+	Limit int
+	// Offset is the number of matching events skipped before those read
+	Offset int
+	// Cursor is the cursor of an event read before, events are read from
+	// the one following it, if not empty
+	Cursor string
+}
+
+// Event describes an event of a container, image or pod
+type Event struct {
+	// ID is the ID of the container, image or pod
+	ID string `json:",omitempty"`
+	// Image is the name of the image of the container, or of the image
+	Image string `json:",omitempty"`
+	// Name is the name of the container or pod
+	Name string `json:",omitempty"`
+	// Status is what happened, such as start or pull
+	Status Status
+	// Time is when the event happened
+	Time time.Time
+	// Type is the kind of object of the event
+	Type Type
+	// Attributes are further details of the event, such as the labels of
+	// containers and their exit code
+	Attributes map[string]string `json:",omitempty"`
+	// Cursor is the position of the event in the backend it was read
+	// from, which reads resume after with ReadOptions.Cursor
+	Cursor string `json:",omitempty"`
+}
+
+// Type is the kind of object of an event
+type Type string
+
+// Status is what happened to the object of an event
+type Status string
+
+const (
+	// Container is the type of container events
+	Container Type = "container"
+	// Image is the type of image events
+	Image Type = "image"
+	// Pod is the type of pod events
+	Pod Type = "pod"
+
+	// Attach is the status of attaching to a container
+	Attach Status = "attach"
+	// Cleanup is the status of cleaning up a stopped container
+	Cleanup Status = "cleanup"
+	// Commit is the status of committing a container to an image
+	Commit Status = "commit"
+	// Create is the status of creating a container or pod
+	Create Status = "create"
+	// Detach is the status of detaching from a container, such as when an
+	// attach session stays idle for too long
+	Detach Status = "detach"
+	// Died is the status of a container whose processes exited
+	Died Status = "died"
+	// Exec is the status of running a process in a container
+	Exec Status = "exec"
+	// Export is the status of exporting a container
+	Export Status = "export"
+	// HealthStatus is the status of a container whose health changed, such
+	// as becoming unhealthy
+	HealthStatus Status = "health_status"
+	// Import is the status of importing an image
+	Import Status = "import"
+	// Init is the status of creating a container in the OCI runtime
+	Init Status = "init"
+	// Kill is the status of sending a signal to a container or pod
+	Kill Status = "kill"
+	// LoadFromArchive is the status of loading an image from an archive
+	LoadFromArchive Status = "load"
+	// Mount is the status of mounting the root filesystem of a container
+	Mount Status = "mount"
+	// OOM is the status of a container killed by the OOM killer
+	OOM Status = "oom"
+	// Pause is the status of pausing a container or pod
+	Pause Status = "pause"
+	// PidsLimit is the status of a container failing to create processes
+	// as it reached its limit of processes
+	PidsLimit Status = "pids-limit"
+	// Pull is the status of pulling an image
+	Pull Status = "pull"
+	// Push is the status of pushing an image
+	Push Status = "push"
+	// Remove is the status of removing a container, image or pod
+	Remove Status = "remove"
+	// Restart is the status of restarting a container or pod
+	Restart Status = "restart"
+	// Save is the status of saving an image to an archive
+	Save Status = "save"
+	// Start is the status of starting a container or pod
+	Start Status = "start"
+	// Stop is the status of stopping a container or pod
+	Stop Status = "stop"
+	// Tag is the status of tagging an image
+	Tag Status = "tag"
+	// Unmount is the status of unmounting the root filesystem of a
+	// container
+	Unmount Status = "unmount"
+	// Unpause is the status of unpausing a container or pod
+	Unpause Status = "unpause"
+	// Untag is the status of removing a tag of an image
+	Untag Status = "untag"
+)
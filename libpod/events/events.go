@@ -0,0 +1,103 @@
+// Package events describes the events of the containers, images and pods of
+// libpod, and keeps them in a backend they can be read from, with filters and
+// as a stream.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnknownEventer is returned for unknown event backends
+	ErrUnknownEventer = errors.New("unknown events backend")
+	// ErrInvalidCursor is returned for reads resuming after a cursor the
+	// backend did not return
+	ErrInvalidCursor = errors.New("invalid events cursor")
+)
+
+// NewEvent returns an event of the given status happening now
+func NewEvent(status Status) Event {
+	return Event{
+		Status: status,
+		Time:   time.Now(),
+	}
+}
+
+// NewEventer returns an eventer for the backend of the options
+func NewEventer(options EventerOptions) (Eventer, error) {
+	switch strings.ToLower(options.EventerType) {
+	case "", "file":
+		if options.LogFilePath == "" {
+			return nil, errors.Errorf("the file events backend requires the path of the events file")
+		}
+		return EventLogFile{options}, nil
+	case "journald":
+		return newEventJournalD(options)
+	case "none":
+		return EventToNull{}, nil
+	default:
+		return nil, errors.Wrapf(ErrUnknownEventer, "%q", options.EventerType)
+	}
+}
+
+// ToJSONString returns the event as JSON
+func (e *Event) ToJSONString() (string, error) {
+	b, err := json.Marshal(e)
+	return string(b), err
+}
+
+// ToHumanReadable returns the event as a line of text
+func (e *Event) ToHumanReadable() string {
+	line := fmt.Sprintf("%s %s %s %s", e.Time.Format("2006-01-02 15:04:05.000000000 -0700 MST"), e.Type, e.Status, e.ID)
+	var details []string
+	if e.Image != "" && e.Type != Image {
+		details = append(details, "image="+e.Image)
+	}
+	if e.Name != "" {
+		details = append(details, "name="+e.Name)
+	}
+	keys := make([]string, 0, len(e.Attributes))
+	for key := range e.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		details = append(details, key+"="+e.Attributes[key])
+	}
+	if e.Type == Image && e.Image != "" {
+		line += " " + e.Image
+	}
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+	return line
+}
+
+// newEventFromJSONString returns the event of a line of JSON
+func newEventFromJSONString(event string) (*Event, error) {
+	e := new(Event)
+	if err := json.Unmarshal([]byte(event), e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// EventToNull is the backend of disabled events, which discards them
+type EventToNull struct{}
+
+// Write discards the event
+func (e EventToNull) Write(ee Event) error {
+	return nil
+}
+
+// Read reads no events
+func (e EventToNull) Read(options ReadOptions) error {
+	close(options.EventChannel)
+	return nil
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEventer(t *testing.T) {
+	eventer, err := NewEventer(EventerOptions{LogFilePath: "/tmp/events.log"})
+	require.NoError(t, err)
+	assert.IsType(t, EventLogFile{}, eventer)
+
+	_, err = NewEventer(EventerOptions{EventerType: "file"})
+	assert.Error(t, err)
+
+	eventer, err = NewEventer(EventerOptions{EventerType: "none"})
+	require.NoError(t, err)
+	assert.IsType(t, EventToNull{}, eventer)
+
+	_, err = NewEventer(EventerOptions{EventerType: "syslog"})
+	assert.Equal(t, ErrUnknownEventer, errors.Cause(err))
+}
+
+func TestToHumanReadable(t *testing.T) {
+	when := time.Date(2019, 3, 1, 10, 0, 0, 0, time.UTC)
+	e := Event{
+		ID:         "abc",
+		Image:      "docker.io/library/alpine:latest",
+		Name:       "web",
+		Status:     Start,
+		Time:       when,
+		Type:       Container,
+		Attributes: map[string]string{"b": "2", "a": "1"},
+	}
+	assert.Equal(t, "2019-03-01 10:00:00.000000000 +0000 UTC container start abc (image=docker.io/library/alpine:latest, name=web, a=1, b=2)", e.ToHumanReadable())
+
+	e = Event{ID: "def", Image: "alpine", Status: Pull, Time: when, Type: Image}
+	assert.Equal(t, "2019-03-01 10:00:00.000000000 +0000 UTC image pull def alpine", e.ToHumanReadable())
+}
+
+func TestJSONString(t *testing.T) {
+	e := NewEvent(Died)
+	e.ID = "abc"
+	e.Type = Container
+	e.Attributes = map[string]string{"exitCode": "1"}
+	s, err := e.ToJSONString()
+	require.NoError(t, err)
+	decoded, err := newEventFromJSONString(s)
+	require.NoError(t, err)
+	assert.Equal(t, e.ID, decoded.ID)
+	assert.Equal(t, e.Status, decoded.Status)
+	assert.True(t, e.Time.Equal(decoded.Time))
+	assert.Equal(t, e.Attributes, decoded.Attributes)
+}
+
+func TestFilters(t *testing.T) {
+	ctr := &Event{
+		ID:         "0123456789",
+		Image:      "docker.io/library/alpine:latest",
+		Name:       "web",
+		Status:     Start,
+		Type:       Container,
+		Attributes: map[string]string{"app": "front", podIDAttribute: "9876", podNameAttribute: "mypod"},
+	}
+	img := &Event{ID: "fedcba", Image: "alpine:latest", Status: Pull, Type: Image}
+	pod := &Event{ID: "9876", Name: "mypod", Status: Create, Type: Pod}
+
+	for _, tc := range []struct {
+		filters []string
+		matches []*Event
+	}{
+		{nil, []*Event{ctr, img, pod}},
+		{[]string{"container=web"}, []*Event{ctr}},
+		{[]string{"container=0123"}, []*Event{ctr}},
+		{[]string{"event=start"}, []*Event{ctr}},
+		{[]string{"event=start", "event=pull"}, []*Event{ctr, img}},
+		{[]string{"image=alpine:latest"}, []*Event{ctr, img}},
+		{[]string{"image=fed"}, []*Event{img}},
+		{[]string{"label=app"}, []*Event{ctr}},
+		{[]string{"label=app=back"}, nil},
+		{[]string{"pod=mypod"}, []*Event{ctr, pod}},
+		{[]string{"pod=9876", "type=pod"}, []*Event{pod}},
+		{[]string{"type=container", "event=pull"}, nil},
+	} {
+		set, err := parseFilters(tc.filters)
+		require.NoError(t, err, tc.filters)
+		var matches []*Event
+		for _, e := range []*Event{ctr, img, pod} {
+			if set.matches(e) {
+				matches = append(matches, e)
+			}
+		}
+		assert.Equal(t, tc.matches, matches, "%v", tc.filters)
+	}
+
+	for _, filters := range [][]string{{"event"}, {"event="}, {"volume=foo"}} {
+		_, err := parseFilters(filters)
+		assert.Error(t, err, filters)
+	}
+}
+
+func TestMatcherTimes(t *testing.T) {
+	now := time.Now()
+	match, err := ReadOptions{Since: now.Add(-time.Minute), Until: now.Add(time.Minute)}.matcher()
+	require.NoError(t, err)
+	assert.True(t, match(&Event{Time: now}))
+	assert.False(t, match(&Event{Time: now.Add(-time.Hour)}))
+	assert.False(t, match(&Event{Time: now.Add(time.Hour)}))
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// eventFilter tells whether an event matches a value of a filter
+type eventFilter func(e *Event, value string) bool
+
+// eventFilters are the filters events can be read with, by key
+var eventFilters = map[string]eventFilter{
+	"container": func(e *Event, value string) bool {
+		return e.Type == Container && matchIDOrName(e, value)
+	},
+	"event": func(e *Event, value string) bool {
+		return string(e.Status) == value
+	},
+	"image": func(e *Event, value string) bool {
+		if e.Image == value || strings.TrimPrefix(e.Image, "docker.io/library/") == value {
+			return true
+		}
+		return e.Type == Image && strings.HasPrefix(e.ID, value)
+	},
+	"label": func(e *Event, value string) bool {
+		split := strings.SplitN(value, "=", 2)
+		labelValue, ok := e.Attributes[split[0]]
+		if len(split) == 1 {
+			return ok
+		}
+		return ok && labelValue == split[1]
+	},
+	"pod": func(e *Event, value string) bool {
+		if e.Type == Pod {
+			return matchIDOrName(e, value)
+		}
+		return e.Attributes[podIDAttribute] != "" && (strings.HasPrefix(e.Attributes[podIDAttribute], value) || e.Attributes[podNameAttribute] == value)
+	},
+	"type": func(e *Event, value string) bool {
+		return string(e.Type) == value
+	},
+}
+
+const (
+	// podIDAttribute is the attribute of the events of containers in
+	// pods with the ID of their pod
+	podIDAttribute = "podId"
+	// podNameAttribute is the attribute of the events of containers in
+	// pods with the name of their pod
+	podNameAttribute = "podName"
+)
+
+// PodAttributes returns the attributes of the events of a container of a pod
+func PodAttributes(id, name string) map[string]string {
+	return map[string]string{podIDAttribute: id, podNameAttribute: name}
+}
+
+// matchIDOrName tells whether an event is of the object whose ID starts with
+// or whose name is value
+func matchIDOrName(e *Event, value string) bool {
+	return strings.HasPrefix(e.ID, value) || e.Name == value
+}
+
+// filterSet are parsed filters, events must match one of the values of each
+// key
+type filterSet map[string][]string
+
+// parseFilters parses filters given as KEY=VALUE
+func parseFilters(filters []string) (filterSet, error) {
+	set := make(filterSet)
+	for _, filter := range filters {
+		split := strings.SplitN(filter, "=", 2)
+		if len(split) != 2 || split[1] == "" {
+			return nil, errors.Errorf("invalid filter %q, must be KEY=VALUE", filter)
+		}
+		key := strings.ToLower(strings.TrimSpace(split[0]))
+		if _, ok := eventFilters[key]; !ok {
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		set[key] = append(set[key], strings.TrimSpace(split[1]))
+	}
+	return set, nil
+}
+
+// matches tells whether the event matches the filters
+func (set filterSet) matches(e *Event) bool {
+	for key, values := range set {
+		filter := eventFilters[key]
+		matched := false
+		for _, value := range values {
+			if filter(e, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matcher returns a function telling whether events match the filters and
+// times of the options
+func (options ReadOptions) matcher() (func(e *Event) bool, error) {
+	set, err := parseFilters(options.Filters)
+	if err != nil {
+		return nil, err
+	}
+	return func(e *Event) bool {
+		if !options.Since.IsZero() && e.Time.Before(options.Since) {
+			return false
+		}
+		if !options.Until.IsZero() && e.Time.After(options.Until) {
+			return false
+		}
+		return set.matches(e)
+	}, nil
+}
+
+// pager counts the events matching the options against their offset and limit
+type pager struct {
+	offset int
+	limit  int
+}
+
+// pager returns the pager of the events read with the options. Only reads
+// without Stream are paginated.
+func (options ReadOptions) pager() (*pager, error) {
+	if options.Limit < 0 || options.Offset < 0 {
+		return nil, errors.Errorf("the limit and offset of events must not be negative")
+	}
+	if options.Stream && (options.Limit > 0 || options.Offset > 0 || options.Cursor != "") {
+		return nil, errors.Errorf("the limit, offset and cursor of events only apply to reads without streaming")
+	}
+	return &pager{offset: options.Offset, limit: options.Limit}, nil
+}
+
+// skip tells whether a matching event is skipped, as one of the offset first
+func (p *pager) skip() bool {
+	if p.offset > 0 {
+		p.offset--
+		return true
+	}
+	return false
+}
+
+// full counts a matching event read and tells whether the limit is reached
+func (p *pager) full() bool {
+	if p.limit == 0 {
+		return false
+	}
+	p.limit--
+	return p.limit == 0
+}
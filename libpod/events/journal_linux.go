@@ -0,0 +1,229 @@
+// +build linux
+
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// journalSocket is the socket of the native protocol of journald
+	journalSocket = "/run/systemd/journal/socket"
+	// journalIdentifier is the syslog identifier of the events in the
+	// journal
+	journalIdentifier = "podman"
+)
+
+// EventJournalD is the backend keeping events in the systemd journal. Events
+// are sent with the native protocol of journald, and read back through
+// journalctl.
+type EventJournalD struct {
+	options EventerOptions
+}
+
+// newEventJournalD returns the journald backend, if journald is running
+func newEventJournalD(options EventerOptions) (Eventer, error) {
+	if _, err := os.Stat(journalSocket); err != nil {
+		return nil, errors.Wrapf(err, "the journald events backend requires journald")
+	}
+	return EventJournalD{options}, nil
+}
+
+// Write sends an event to the journal
+func (e EventJournalD) Write(ee Event) error {
+	fields := map[string]string{
+		"MESSAGE":           ee.ToHumanReadable(),
+		"PRIORITY":          "6",
+		"SYSLOG_IDENTIFIER": journalIdentifier,
+		"PODMAN_EVENT":      string(ee.Status),
+		"PODMAN_TYPE":       string(ee.Type),
+		"PODMAN_TIME":       ee.Time.Format(time.RFC3339Nano),
+	}
+	if ee.ID != "" {
+		fields["PODMAN_ID"] = ee.ID
+	}
+	if ee.Name != "" {
+		fields["PODMAN_NAME"] = ee.Name
+	}
+	if ee.Image != "" {
+		fields["PODMAN_IMAGE"] = ee.Image
+	}
+	if len(ee.Attributes) > 0 {
+		attributes, err := json.Marshal(ee.Attributes)
+		if err != nil {
+			return err
+		}
+		fields["PODMAN_ATTRIBUTES"] = string(attributes)
+	}
+
+	conn, err := net.Dial("unixgram", journalSocket)
+	if err != nil {
+		return errors.Wrapf(err, "error connecting to journald")
+	}
+	defer conn.Close()
+	if _, err := conn.Write(journalMessage(fields)); err != nil {
+		return errors.Wrapf(err, "error sending event to journald")
+	}
+	return nil
+}
+
+// journalMessage encodes fields with the native protocol of journald, as
+// FIELD=value lines, or with the binary length of values spanning lines
+func journalMessage(fields map[string]string) []byte {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	for _, key := range keys {
+		value := fields[key]
+		if !strings.Contains(value, "\n") {
+			buf.WriteString(key + "=" + value + "\n")
+			continue
+		}
+		buf.WriteString(key + "\n")
+		binary.Write(&buf, binary.LittleEndian, uint64(len(value)))
+		buf.WriteString(value + "\n")
+	}
+	return buf.Bytes()
+}
+
+// journalMatches returns the journalctl matches of the events sent by podman
+// running as uid. Other processes of other users can send entries with the
+// syslog identifier of podman, but journald records the UID of the sender.
+func journalMatches(uid int) []string {
+	return []string{"SYSLOG_IDENTIFIER=" + journalIdentifier, "_UID=" + strconv.Itoa(uid)}
+}
+
+// Read reads the events of the journal through journalctl, and with Stream
+// those sent to it until the context is done or the until time is reached.
+// The cursor of an event is that of its journal entry.
+func (e EventJournalD) Read(options ReadOptions) error {
+	defer close(options.EventChannel)
+	match, err := options.matcher()
+	if err != nil {
+		return err
+	}
+	page, err := options.pager()
+	if err != nil {
+		return err
+	}
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var cancel context.CancelFunc
+	if options.Stream && !options.Until.IsZero() {
+		ctx, cancel = context.WithDeadline(ctx, options.Until)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	args := append([]string{"--output=json", "--no-pager"}, journalMatches(rootless.GetRootlessUID())...)
+	if !options.Since.IsZero() {
+		args = append(args, "--since=@"+strconv.FormatInt(options.Since.Unix(), 10))
+	}
+	if options.Stream {
+		args = append(args, "--follow", "--lines=all")
+	}
+	if options.Cursor != "" {
+		args = append(args, "--after-cursor="+options.Cursor)
+	}
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return errors.Wrapf(err, "error reading the journal")
+	}
+	defer func() {
+		// Stop journalctl, which may have more entries to write once
+		// the limit is reached
+		cancel()
+		cmd.Wait()
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		event, err := newEventFromJournalEntry(scanner.Bytes())
+		if err != nil {
+			logrus.Debugf("Skipping invalid journal entry: %v", err)
+			continue
+		}
+		if event == nil || !match(event) || page.skip() {
+			continue
+		}
+		select {
+		case options.EventChannel <- event:
+		case <-ctx.Done():
+			return nil
+		}
+		if page.full() {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return errors.Wrapf(err, "error reading the journal")
+	}
+	// journalctl fails on cursors of no entry
+	if err := cmd.Wait(); err != nil && ctx.Err() == nil && options.Cursor != "" {
+		return errors.Wrapf(ErrInvalidCursor, "%q: %s", options.Cursor, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// newEventFromJournalEntry returns the event of an entry of the journal in
+// the JSON output of journalctl, or nil if the entry is not an event
+func newEventFromJournalEntry(entry []byte) (*Event, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(entry, &fields); err != nil {
+		return nil, err
+	}
+	field := func(key string) string {
+		// Binary values are arrays, events have none
+		value, _ := fields[key].(string)
+		return value
+	}
+	if field("PODMAN_EVENT") == "" {
+		return nil, nil
+	}
+	event := &Event{
+		ID:     field("PODMAN_ID"),
+		Image:  field("PODMAN_IMAGE"),
+		Name:   field("PODMAN_NAME"),
+		Status: Status(field("PODMAN_EVENT")),
+		Type:   Type(field("PODMAN_TYPE")),
+		Cursor: field("__CURSOR"),
+	}
+	var err error
+	if event.Time, err = time.Parse(time.RFC3339Nano, field("PODMAN_TIME")); err != nil {
+		return nil, errors.Wrapf(err, "invalid time of event")
+	}
+	if attributes := field("PODMAN_ATTRIBUTES"); attributes != "" {
+		if err := json.Unmarshal([]byte(attributes), &event.Attributes); err != nil {
+			return nil, errors.Wrapf(err, "invalid attributes of event")
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,43 @@
+// +build linux
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalMessage(t *testing.T) {
+	msg := journalMessage(map[string]string{
+		"PODMAN_EVENT": "start",
+		"MESSAGE":      "a\nb",
+	})
+	assert.Equal(t, "MESSAGE\n\x03\x00\x00\x00\x00\x00\x00\x00a\nb\nPODMAN_EVENT=start\n", string(msg))
+}
+
+func TestJournalMatches(t *testing.T) {
+	assert.Equal(t, []string{"SYSLOG_IDENTIFIER=podman", "_UID=1000"}, journalMatches(1000))
+}
+
+func TestNewEventFromJournalEntry(t *testing.T) {
+	e, err := newEventFromJournalEntry([]byte(`{"MESSAGE":"foo","SYSLOG_IDENTIFIER":"podman"}`))
+	require.NoError(t, err)
+	assert.Nil(t, e)
+
+	e, err = newEventFromJournalEntry([]byte(`{"PODMAN_EVENT":"died","PODMAN_TYPE":"container","PODMAN_ID":"abc","PODMAN_NAME":"web","PODMAN_TIME":"2019-03-01T10:00:00.5Z","PODMAN_ATTRIBUTES":"{\"exitCode\":\"1\"}","__CURSOR":"s=1;i=2"}`))
+	require.NoError(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, Died, e.Status)
+	assert.Equal(t, Container, e.Type)
+	assert.Equal(t, "abc", e.ID)
+	assert.Equal(t, "web", e.Name)
+	assert.True(t, e.Time.Equal(time.Date(2019, 3, 1, 10, 0, 0, 500000000, time.UTC)))
+	assert.Equal(t, map[string]string{"exitCode": "1"}, e.Attributes)
+	assert.Equal(t, "s=1;i=2", e.Cursor)
+
+	_, err = newEventFromJournalEntry([]byte(`{"PODMAN_EVENT":"start","PODMAN_TIME":"now"}`))
+	assert.Error(t, err)
+}
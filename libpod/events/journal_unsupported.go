@@ -0,0 +1,12 @@
+// +build !linux
+
+package events
+
+import (
+	"github.com/pkg/errors"
+)
+
+// newEventJournalD returns an error, journald is only available on Linux
+func newEventJournalD(options EventerOptions) (Eventer, error) {
+	return nil, errors.Errorf("the journald events backend is only supported on Linux")
+}
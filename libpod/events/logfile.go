@@ -0,0 +1,155 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// pollInterval is the interval at which streamed events files are checked
+// for new events
+const pollInterval = 250 * time.Millisecond
+
+// EventLogFile is the backend keeping events in a file, one JSON event per
+// line
+type EventLogFile struct {
+	options EventerOptions
+}
+
+// Write appends an event to the events file
+func (e EventLogFile) Write(ee Event) error {
+	if err := os.MkdirAll(filepath.Dir(e.options.LogFilePath), 0700); err != nil {
+		return errors.Wrapf(err, "error creating the directory of events file %s", e.options.LogFilePath)
+	}
+	lock, err := storage.GetLockfile(e.options.LogFilePath + ".lock")
+	if err != nil {
+		return errors.Wrapf(err, "error locking events file %s", e.options.LogFilePath)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+
+	f, err := os.OpenFile(e.options.LogFilePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "error opening events file %s", e.options.LogFilePath)
+	}
+	defer f.Close()
+	eventJSONString, err := ee.ToJSONString()
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(eventJSONString + "\n"); err != nil {
+		return errors.Wrapf(err, "error writing to events file %s", e.options.LogFilePath)
+	}
+	return nil
+}
+
+// Read reads the events of the events file, and with Stream those appended
+// to it until the context is done or the until time is reached. The cursor of
+// an event is the offset in the file of the line following it.
+func (e EventLogFile) Read(options ReadOptions) error {
+	defer close(options.EventChannel)
+	match, err := options.matcher()
+	if err != nil {
+		return err
+	}
+	page, err := options.pager()
+	if err != nil {
+		return err
+	}
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	f, err := os.Open(e.options.LogFilePath)
+	for err != nil {
+		if !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error opening events file %s", e.options.LogFilePath)
+		}
+		// No event was written yet
+		if !options.Stream || !wait(ctx, options.Until) {
+			return nil
+		}
+		f, err = os.Open(e.options.LogFilePath)
+	}
+	defer f.Close()
+
+	var position int64
+	if options.Cursor != "" {
+		if position, err = seekCursor(f, options.Cursor); err != nil {
+			return err
+		}
+	}
+	reader := bufio.NewReader(f)
+	var partial string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return errors.Wrapf(err, "error reading events file %s", e.options.LogFilePath)
+		}
+		if err == io.EOF {
+			// Keep the start of an event being written
+			partial += line
+			if !options.Stream || !wait(ctx, options.Until) {
+				return nil
+			}
+			continue
+		}
+		line, partial = partial+line, ""
+		position += int64(len(line))
+		event, err := newEventFromJSONString(strings.TrimSpace(line))
+		if err != nil {
+			logrus.Debugf("Skipping invalid event %q: %v", line, err)
+			continue
+		}
+		if !match(event) || page.skip() {
+			continue
+		}
+		event.Cursor = strconv.FormatInt(position, 10)
+		select {
+		case options.EventChannel <- event:
+		case <-ctx.Done():
+			return nil
+		}
+		if page.full() {
+			return nil
+		}
+	}
+}
+
+// seekCursor seeks the events file to the line at cursor, which must follow
+// an event, and returns its offset
+func seekCursor(f *os.File, cursor string) (int64, error) {
+	offset, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || offset <= 0 {
+		return 0, errors.Wrapf(ErrInvalidCursor, "%q", cursor)
+	}
+	previous := make([]byte, 1)
+	if _, err := f.ReadAt(previous, offset-1); err != nil || previous[0] != '\n' {
+		return 0, errors.Wrapf(ErrInvalidCursor, "%q", cursor)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, errors.Wrapf(err, "error seeking events file %s", f.Name())
+	}
+	return offset, nil
+}
+
+// wait waits for new events to be written, and returns false once the context
+// is done or the until time, if not zero, is reached
+func wait(ctx context.Context, until time.Time) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(pollInterval):
+		return until.IsZero() || time.Now().Before(until)
+	}
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAll reads the events of the eventer with the options
+func readAll(t *testing.T, eventer Eventer, options ReadOptions) []*Event {
+	options.EventChannel = make(chan *Event)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- eventer.Read(options)
+	}()
+	var read []*Event
+	for e := range options.EventChannel {
+		read = append(read, e)
+	}
+	require.NoError(t, <-errChan)
+	return read
+}
+
+func TestEventLogFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	eventer, err := NewEventer(EventerOptions{LogFilePath: filepath.Join(dir, "events", "events.log")})
+	require.NoError(t, err)
+
+	// No events were written yet
+	assert.Empty(t, readAll(t, eventer, ReadOptions{}))
+
+	for _, status := range []Status{Create, Start, Died} {
+		e := NewEvent(status)
+		e.ID = "abc"
+		e.Type = Container
+		require.NoError(t, eventer.Write(e))
+	}
+	read := readAll(t, eventer, ReadOptions{})
+	require.Len(t, read, 3)
+	assert.Equal(t, Create, read[0].Status)
+	assert.Equal(t, Died, read[2].Status)
+
+	read = readAll(t, eventer, ReadOptions{Filters: []string{"event=start"}})
+	require.Len(t, read, 1)
+	assert.Equal(t, Start, read[0].Status)
+
+	// A partial line is kept until the event is fully written
+	f, err := os.OpenFile(filepath.Join(dir, "events", "events.log"), os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"ID":"abc",`)
+	require.NoError(t, err)
+	f.Close()
+	assert.Len(t, readAll(t, eventer, ReadOptions{}), 3)
+}
+
+func TestEventLogFileStream(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	eventer, err := NewEventer(EventerOptions{LogFilePath: filepath.Join(dir, "events.log")})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	options := ReadOptions{
+		Context:      ctx,
+		EventChannel: make(chan *Event),
+		Stream:       true,
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- eventer.Read(options)
+	}()
+
+	// Events written while streaming are read
+	for _, status := range []Status{Create, Start} {
+		require.NoError(t, eventer.Write(NewEvent(status)))
+		select {
+		case e := <-options.EventChannel:
+			assert.Equal(t, status, e.Status)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s event was not streamed", status)
+		}
+	}
+
+	cancel()
+	for range options.EventChannel {
+	}
+	assert.NoError(t, <-errChan)
+
+	// Streaming stops once the until time is reached
+	read := readAll(t, eventer, ReadOptions{Stream: true, Until: time.Now().Add(500 * time.Millisecond)})
+	assert.Len(t, read, 2)
+}
+
+func TestEventLogFilePagination(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	eventer, err := NewEventer(EventerOptions{LogFilePath: filepath.Join(dir, "events.log")})
+	require.NoError(t, err)
+
+	statuses := []Status{Create, Init, Start, Stop, Died}
+	for _, status := range statuses {
+		require.NoError(t, eventer.Write(NewEvent(status)))
+	}
+
+	read := readAll(t, eventer, ReadOptions{Limit: 2})
+	require.Len(t, read, 2)
+	assert.Equal(t, Init, read[1].Status)
+
+	read = readAll(t, eventer, ReadOptions{Limit: 2, Offset: 2})
+	require.Len(t, read, 2)
+	assert.Equal(t, Start, read[0].Status)
+	assert.Equal(t, Stop, read[1].Status)
+
+	// Events written after a page do not shift the next one read from its
+	// cursor
+	cursor := read[1].Cursor
+	require.NoError(t, eventer.Write(NewEvent(Remove)))
+	read = readAll(t, eventer, ReadOptions{Cursor: cursor})
+	require.Len(t, read, 2)
+	assert.Equal(t, Died, read[0].Status)
+	assert.Equal(t, Remove, read[1].Status)
+
+	read = readAll(t, eventer, ReadOptions{Cursor: cursor, Filters: []string{"event=remove"}, Limit: 1})
+	require.Len(t, read, 1)
+	assert.Equal(t, Remove, read[0].Status)
+
+	for _, options := range []ReadOptions{
+		{Cursor: "abc"},
+		{Cursor: "1"},
+		{Cursor: "100000"},
+	} {
+		options.EventChannel = make(chan *Event, 10)
+		err := eventer.Read(options)
+		assert.Equal(t, ErrInvalidCursor, errors.Cause(err), options.Cursor)
+	}
+	for _, options := range []ReadOptions{
+		{Limit: -1},
+		{Stream: true, Limit: 1},
+		{Stream: true, Cursor: cursor},
+	} {
+		options.EventChannel = make(chan *Event, 10)
+		assert.Error(t, eventer.Read(options))
+	}
+}
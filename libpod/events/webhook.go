@@ -0,0 +1,191 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultWebhookRetries is the number of times deliveries to webhooks
+	// with no retries set are retried
+	DefaultWebhookRetries = 3
+	// SignatureHeader is the header of the HMAC-SHA256 signature of the
+	// events posted to webhooks with a secret, as sha256=HEX
+	SignatureHeader = "X-Podman-Signature"
+	// EventHeader is the header of the status of the events posted to
+	// webhooks
+	EventHeader = "X-Podman-Event"
+
+	// webhookTimeout is the time a delivery to a webhook may take
+	webhookTimeout = 10 * time.Second
+	// webhookBackoff is the time before the first retry of a delivery,
+	// doubled for each further retry
+	webhookBackoff = time.Second
+	// webhookQueueLength is the number of events waiting for their
+	// delivery to a webhook, further events are dropped
+	webhookQueueLength = 100
+)
+
+// Webhook is an HTTP endpoint events are posted to as JSON
+type Webhook struct {
+	// URL is the http or https URL events are posted to
+	URL string `toml:"url"`
+	// Filters are the filters events posted must match, as KEY=VALUE,
+	// like those of reading events. All events are posted if empty.
+	Filters []string `toml:"filters,omitempty"`
+	// Secret signs the events posted with HMAC-SHA256 in their
+	// X-Podman-Signature header, if not empty
+	Secret string `toml:"secret,omitempty"`
+	// Retries is the number of times failed deliveries are retried, with
+	// an exponential backoff. If 0, DefaultWebhookRetries is used, and if
+	// negative deliveries are not retried.
+	Retries int `toml:"retries,omitempty"`
+}
+
+// Dispatcher posts the events written to an eventer to webhooks
+type Dispatcher struct {
+	eventer  Eventer
+	webhooks []Webhook
+	filters  []filterSet
+	client   *http.Client
+}
+
+// NewDispatcher returns a dispatcher of the events of the eventer to the
+// webhooks
+func NewDispatcher(eventer Eventer, webhooks []Webhook) (*Dispatcher, error) {
+	d := &Dispatcher{
+		eventer:  eventer,
+		webhooks: webhooks,
+		client:   &http.Client{Timeout: webhookTimeout},
+	}
+	for _, webhook := range webhooks {
+		u, err := url.Parse(webhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, errors.Errorf("invalid webhook URL %q, must be an http or https URL", webhook.URL)
+		}
+		set, err := parseFilters(webhook.Filters)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid filters of webhook %s", webhook.URL)
+		}
+		d.filters = append(d.filters, set)
+	}
+	return d, nil
+}
+
+// Run posts the events written from now on to the webhooks they match, until
+// the context is done. Each webhook receives its events in order, deliveries
+// to slow webhooks do not delay the others.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	queues := make([]chan *Event, len(d.webhooks))
+	done := make(chan struct{}, len(d.webhooks))
+	for i := range d.webhooks {
+		queues[i] = make(chan *Event, webhookQueueLength)
+		go func(webhook Webhook, queue chan *Event) {
+			defer func() { done <- struct{}{} }()
+			for e := range queue {
+				if err := d.deliver(ctx, webhook, e); err != nil {
+					logrus.Errorf("Unable to post %s event of %s to webhook %s: %v", e.Status, e.ID, webhook.URL, err)
+				}
+			}
+		}(d.webhooks[i], queues[i])
+	}
+	defer func() {
+		// Deliveries in progress are abandoned
+		cancel()
+		for i := range queues {
+			close(queues[i])
+			<-done
+		}
+	}()
+
+	options := ReadOptions{
+		Context:      ctx,
+		EventChannel: make(chan *Event),
+		Since:        time.Now(),
+		Stream:       true,
+	}
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- d.eventer.Read(options)
+	}()
+	for e := range options.EventChannel {
+		for i, set := range d.filters {
+			if !set.matches(e) {
+				continue
+			}
+			select {
+			case queues[i] <- e:
+			default:
+				logrus.Warnf("Dropping %s event of %s, too many events are waiting for webhook %s", e.Status, e.ID, d.webhooks[i].URL)
+			}
+		}
+	}
+	return <-readErr
+}
+
+// deliver posts an event to a webhook, retrying failed deliveries
+func (d *Dispatcher) deliver(ctx context.Context, webhook Webhook, e *Event) error {
+	body, err := e.ToJSONString()
+	if err != nil {
+		return err
+	}
+	retries := webhook.Retries
+	if retries == 0 {
+		retries = DefaultWebhookRetries
+	}
+	backoff := webhookBackoff
+	for attempt := 0; ; attempt++ {
+		err = d.post(ctx, webhook, e, []byte(body))
+		if err == nil || attempt >= retries {
+			return err
+		}
+		logrus.Debugf("Retrying delivery to webhook %s in %s: %v", webhook.URL, backoff, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post posts the JSON body of an event to a webhook
+func (d *Dispatcher) post(ctx context.Context, webhook Webhook, e *Event, body []byte) error {
+	req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(e.Status))
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Signature(webhook.Secret, body))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// Signature returns the signature of the body of an event posted to a webhook
+// with the secret, as sha256=HEX of its HMAC-SHA256
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
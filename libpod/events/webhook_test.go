@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	// echo -n '{"Status":"died"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=d9c02450d6967e62b5d23559ba5cd0e39af32c14fca081541632b6d432684bad", Signature("secret", []byte(`{"Status":"died"}`)))
+}
+
+func TestNewDispatcher(t *testing.T) {
+	_, err := NewDispatcher(EventToNull{}, []Webhook{{URL: "https://example.com/hook", Filters: []string{"event=died"}}})
+	assert.NoError(t, err)
+	for _, webhook := range []Webhook{
+		{URL: "example.com/hook"},
+		{URL: "ftp://example.com/hook"},
+		{URL: "https://example.com/hook", Filters: []string{"volume=foo"}},
+	} {
+		_, err := NewDispatcher(EventToNull{}, []Webhook{webhook})
+		assert.Error(t, err, webhook.URL)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	eventer, err := NewEventer(EventerOptions{LogFilePath: filepath.Join(dir, "events.log")})
+	require.NoError(t, err)
+	// Events written before the dispatcher runs are not posted
+	require.NoError(t, eventer.Write(NewEvent(Died)))
+
+	var lock sync.Mutex
+	failures := 1
+	received := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		// The first delivery fails and is retried
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		// Answer before the test goes on
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	dispatcher, err := NewDispatcher(eventer, []Webhook{{URL: server.URL, Filters: []string{"event=died"}, Secret: "secret"}})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- dispatcher.Run(ctx)
+	}()
+	// Let the dispatcher start reading before writing events
+	time.Sleep(2 * pollInterval)
+
+	e := NewEvent(Start)
+	e.ID = "abc"
+	require.NoError(t, eventer.Write(e))
+	e = NewEvent(Died)
+	e.ID = "abc"
+	require.NoError(t, eventer.Write(e))
+
+	select {
+	case r := <-received:
+		body := <-bodies
+		assert.Equal(t, "died", r.Header.Get(EventHeader))
+		assert.Equal(t, Signature("secret", body), r.Header.Get(SignatureHeader))
+		posted, err := newEventFromJSONString(string(body))
+		require.NoError(t, err)
+		assert.Equal(t, "abc", posted.ID)
+		assert.Equal(t, Died, posted.Status)
+	case <-time.After(10 * time.Second):
+		t.Fatal("died event was not posted")
+	}
+
+	cancel()
+	assert.NoError(t, <-errChan)
+	assert.Empty(t, received)
+}
@@ -0,0 +1,502 @@
+package libpod
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/image/manifest"
+	"github.com/containers/libpod/libpod/events"
+	"github.com/containers/libpod/pkg/inspect"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// HealthCheckStatus is the outcome of running the healthcheck of a container
+type HealthCheckStatus int
+
+const (
+	// HealthCheckSuccess means the healthcheck passed
+	HealthCheckSuccess HealthCheckStatus = iota
+	// HealthCheckFailure means the healthcheck failed
+	HealthCheckFailure HealthCheckStatus = iota
+	// HealthCheckContainerStopped means the container is not running, so
+	// the healthcheck did not run
+	HealthCheckContainerStopped HealthCheckStatus = iota
+	// HealthCheckContainerNotFound means the container does not exist
+	HealthCheckContainerNotFound HealthCheckStatus = iota
+	// HealthCheckNotDefined means the container has no healthcheck
+	HealthCheckNotDefined HealthCheckStatus = iota
+	// HealthCheckInternalError means the healthcheck could not be run
+	HealthCheckInternalError HealthCheckStatus = iota
+)
+
+const (
+	// HealthCheckStarting is the health of a container whose healthcheck
+	// has not passed, nor failed its retries, since it started
+	HealthCheckStarting = "starting"
+	// HealthCheckHealthy is the health of a container whose last
+	// healthcheck passed
+	HealthCheckHealthy = "healthy"
+	// HealthCheckUnhealthy is the health of a container whose last
+	// healthchecks failed as many times as it retries them
+	HealthCheckUnhealthy = "unhealthy"
+
+	// DefaultHealthCheckInterval is the time between healthchecks of
+	// containers with no interval set
+	DefaultHealthCheckInterval = 30 * time.Second
+	// DefaultHealthCheckTimeout is the time healthchecks of containers with
+	// no timeout set may take
+	DefaultHealthCheckTimeout = 30 * time.Second
+	// DefaultHealthCheckRetries is the number of consecutive failures
+	// making containers with no retries set unhealthy
+	DefaultHealthCheckRetries = 3
+
+	// maxHealthCheckLogLength is the number of healthchecks kept in the
+	// results of a container
+	maxHealthCheckLogLength = 5
+	// healthStatusAttribute is the attribute of health_status events with
+	// the new health of the container
+	healthStatusAttribute = "healthStatus"
+	// maxHealthCheckOutputLength is the number of bytes of output of a
+	// healthcheck kept in its log
+	maxHealthCheckOutputLength = 4096
+)
+
+// limitedBuffer holds the first bytes written to it, up to its limit
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write writes what fits of p to the buffer and drops the rest
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := b.limit - b.Len(); room < len(p) {
+		if room < 0 {
+			room = 0
+		}
+		p = p[:room]
+	}
+	b.Buffer.Write(p)
+	return n, nil
+}
+
+// Close does nothing
+func (b *limitedBuffer) Close() error {
+	return nil
+}
+
+// healthCheckCommand returns the command running the test of a healthcheck,
+// which must be a CMD or CMD-SHELL one
+func healthCheckCommand(test []string) ([]string, error) {
+	if len(test) == 0 {
+		return nil, errors.Wrapf(ErrInvalidArg, "healthcheck has no test")
+	}
+	switch test[0] {
+	case "CMD":
+		if len(test) < 2 {
+			return nil, errors.Wrapf(ErrInvalidArg, "healthcheck test CMD has no command")
+		}
+		return test[1:], nil
+	case "CMD-SHELL":
+		if len(test) != 2 || test[1] == "" {
+			return nil, errors.Wrapf(ErrInvalidArg, "healthcheck test CMD-SHELL takes a single command")
+		}
+		return []string{"/bin/sh", "-c", test[1]}, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidArg, "invalid healthcheck test %q, must start with CMD or CMD-SHELL", strings.Join(test, " "))
+}
+
+// healthCheckInterval returns the time between healthchecks
+func healthCheckInterval(hc *manifest.Schema2HealthConfig) time.Duration {
+	if hc.Interval > 0 {
+		return hc.Interval
+	}
+	return DefaultHealthCheckInterval
+}
+
+// healthCheckTimeout returns the time a healthcheck may take
+func healthCheckTimeout(hc *manifest.Schema2HealthConfig) time.Duration {
+	if hc.Timeout > 0 {
+		return hc.Timeout
+	}
+	return DefaultHealthCheckTimeout
+}
+
+// healthCheckRetries returns the number of consecutive failed healthchecks
+// making a container unhealthy
+func healthCheckRetries(hc *manifest.Schema2HealthConfig) int {
+	if hc.Retries > 0 {
+		return hc.Retries
+	}
+	return DefaultHealthCheckRetries
+}
+
+// updateHealthCheckResults adds the log of a healthcheck to the results of a
+// container and updates its health. A passing healthcheck makes it healthy,
+// retries consecutive failing ones unhealthy.
+func updateHealthCheckResults(results *inspect.HealthCheckResults, log inspect.HealthCheckLog, retries int) {
+	appendHealthCheckLog(results, log)
+	if log.ExitCode == 0 {
+		results.FailingStreak = 0
+		results.Status = HealthCheckHealthy
+		return
+	}
+	results.FailingStreak++
+	if results.FailingStreak >= retries {
+		results.Status = HealthCheckUnhealthy
+	}
+}
+
+// updateStartupHealthCheckResults adds the log of a startup healthcheck to
+// the results of a container, leaving its health alone, and returns whether
+// the startup healthcheck passed. Failing ones count in the failing streak.
+func updateStartupHealthCheckResults(results *inspect.HealthCheckResults, log inspect.HealthCheckLog) bool {
+	appendHealthCheckLog(results, log)
+	if log.ExitCode == 0 {
+		results.FailingStreak = 0
+		return true
+	}
+	results.FailingStreak++
+	return false
+}
+
+// appendHealthCheckLog adds the log of a healthcheck to the results of a
+// container, dropping the oldest beyond maxHealthCheckLogLength
+func appendHealthCheckLog(results *inspect.HealthCheckResults, log inspect.HealthCheckLog) {
+	results.Log = append(results.Log, log)
+	if len(results.Log) > maxHealthCheckLogLength {
+		results.Log = results.Log[len(results.Log)-maxHealthCheckLogLength:]
+	}
+}
+
+// HealthCheck runs the healthcheck of a container and records its result
+func (r *Runtime) HealthCheck(name string) (HealthCheckStatus, error) {
+	ctr, err := r.LookupContainer(name)
+	if err != nil {
+		return HealthCheckContainerNotFound, errors.Wrapf(err, "unable to look up %s to run its healthcheck", name)
+	}
+	return ctr.runHealthCheck()
+}
+
+// runHealthCheck runs the healthcheck command of the container in an exec
+// session and records its result. Healthchecks taking longer than their
+// timeout fail. Until the startup healthcheck of the container passes, it
+// runs in place of the regular one.
+func (c *Container) runHealthCheck() (HealthCheckStatus, error) {
+	hc, startup, err := c.currentHealthCheck()
+	if err != nil {
+		return HealthCheckInternalError, err
+	}
+	if hc == nil {
+		return HealthCheckNotDefined, errors.Wrapf(ErrInvalidArg, "container %s has no healthcheck", c.ID())
+	}
+	cmd, err := healthCheckCommand(hc.Test)
+	if err != nil {
+		return HealthCheckInternalError, err
+	}
+	state, err := c.State()
+	if err != nil {
+		return HealthCheckInternalError, err
+	}
+	if state != ContainerStateRunning {
+		return HealthCheckContainerStopped, errors.Wrapf(ErrCtrStateInvalid, "container %s is not running", c.ID())
+	}
+
+	output := &limitedBuffer{limit: maxHealthCheckOutputLength}
+	streams := &AttachStreams{
+		OutputStream: output,
+		ErrorStream:  output,
+		AttachOutput: true,
+		AttachError:  true,
+	}
+	log := inspect.HealthCheckLog{Start: time.Now()}
+	if err := c.Exec(false, false, nil, cmd, "", streams); err != nil {
+		exitErr, ok := errors.Cause(err).(*exec.ExitError)
+		if !ok {
+			return HealthCheckInternalError, errors.Wrapf(err, "error running the healthcheck of container %s", c.ID())
+		}
+		log.ExitCode = 1
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.ExitStatus() > 0 {
+			log.ExitCode = status.ExitStatus()
+		}
+	}
+	log.End = time.Now()
+	log.Output = output.String()
+	if timeout := healthCheckTimeout(hc); log.End.Sub(log.Start) > timeout {
+		log.ExitCode = -1
+		log.Output = fmt.Sprintf("Healthcheck exceeded timeout of %s", timeout)
+	}
+
+	if startup {
+		restart, err := c.recordStartupHealthCheck(log)
+		if err != nil {
+			return HealthCheckInternalError, err
+		}
+		if restart {
+			logrus.Infof("Restarting container %s, its startup healthcheck failed %d times", c.ID(), hc.Retries)
+			if err := c.RestartWithTimeout(context.Background(), c.StopTimeout()); err != nil {
+				return HealthCheckInternalError, errors.Wrapf(err, "error restarting container %s after its startup healthcheck failed", c.ID())
+			}
+		}
+	} else if err := c.recordHealthCheck(log); err != nil {
+		return HealthCheckInternalError, err
+	}
+	if log.ExitCode != 0 {
+		return HealthCheckFailure, nil
+	}
+	return HealthCheckSuccess, nil
+}
+
+// currentHealthCheck returns the healthcheck to run in the container, its
+// startup healthcheck if it has one that has not passed, which startup
+// reports, and its regular healthcheck otherwise
+func (c *Container) currentHealthCheck() (*manifest.Schema2HealthConfig, bool, error) {
+	if c.config.StartupHealthCheckConfig == nil {
+		return c.config.HealthCheckConfig, false, nil
+	}
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return nil, false, err
+		}
+	}
+	if c.state.StartupHealthCheckPassed {
+		return c.config.HealthCheckConfig, false, nil
+	}
+	return c.config.StartupHealthCheckConfig, true, nil
+}
+
+// recordStartupHealthCheck adds the log of a startup healthcheck to the
+// results of the container. Once it passes, the timer running it is replaced
+// by that of the regular healthcheck. It returns whether the container must
+// be restarted, its startup healthcheck having failed as many times as it
+// retries it.
+func (c *Container) recordStartupHealthCheck(log inspect.HealthCheckLog) (bool, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return false, err
+		}
+	}
+	// The container may have restarted, or the startup healthcheck passed,
+	// while this one ran
+	if c.state.State != ContainerStateRunning || c.state.StartupHealthCheckPassed {
+		return false, nil
+	}
+	if c.state.HealthCheck == nil {
+		c.state.HealthCheck = &inspect.HealthCheckResults{Status: HealthCheckStarting}
+	}
+	if !updateStartupHealthCheckResults(c.state.HealthCheck, log) {
+		retries := c.config.StartupHealthCheckConfig.Retries
+		if err := c.save(); err != nil {
+			return false, err
+		}
+		return retries > 0 && c.state.HealthCheck.FailingStreak >= retries, nil
+	}
+
+	c.state.StartupHealthCheckPassed = true
+	if c.config.HealthCheckConfig == nil {
+		c.state.HealthCheck.Status = HealthCheckHealthy
+	}
+	if err := c.save(); err != nil {
+		return false, err
+	}
+	logrus.Debugf("Startup healthcheck of container %s passed", c.ID())
+	if c.config.HealthCheckConfig == nil {
+		c.writeContainerEvent(events.HealthStatus, map[string]string{
+			healthStatusAttribute: c.state.HealthCheck.Status,
+		})
+	}
+	if err := c.removeHealthCheckTimer(c.startupHealthCheckUnit()); err != nil {
+		logrus.Debugf("Error removing the startup healthcheck timer of container %s: %v", c.ID(), err)
+	}
+	if hc := c.config.HealthCheckConfig; hc != nil {
+		if err := c.createHealthCheckTimer(c.ID(), healthCheckInterval(hc)); err != nil {
+			logrus.Warnf("Healthchecks of container %s will not run: %v", c.ID(), err)
+		}
+	}
+	return false, nil
+}
+
+// recordHealthCheck adds the log of a healthcheck to the results of the
+// container
+func (c *Container) recordHealthCheck(log inspect.HealthCheckLog) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+	if c.state.HealthCheck == nil {
+		c.state.HealthCheck = &inspect.HealthCheckResults{Status: HealthCheckStarting}
+	}
+	oldStatus := c.state.HealthCheck.Status
+	updateHealthCheckResults(c.state.HealthCheck, log, healthCheckRetries(c.config.HealthCheckConfig))
+	if err := c.save(); err != nil {
+		return err
+	}
+	if c.state.HealthCheck.Status != oldStatus {
+		c.writeContainerEvent(events.HealthStatus, map[string]string{
+			healthStatusAttribute: c.state.HealthCheck.Status,
+		})
+	}
+	return nil
+}
+
+// HealthCheckResults returns the results of the healthchecks run since the
+// container last started, nil if it has no healthcheck or has not started
+func (c *Container) HealthCheckResults() (*inspect.HealthCheckResults, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return nil, err
+		}
+	}
+	if c.state.HealthCheck == nil {
+		return nil, nil
+	}
+	results := *c.state.HealthCheck
+	results.Log = append([]inspect.HealthCheckLog{}, c.state.HealthCheck.Log...)
+	return &results, nil
+}
+
+// HealthCheckStatus returns the health of the container, "" if it has no
+// healthcheck or has not started
+func (c *Container) HealthCheckStatus() (string, error) {
+	results, err := c.HealthCheckResults()
+	if err != nil || results == nil {
+		return "", err
+	}
+	return results.Status, nil
+}
+
+// healthCheckTimerCommand returns the command of the timer running the
+// healthcheck of the container, podman healthcheck run with the global
+// options of the exit command of the container
+func (c *Container) healthCheckTimerCommand() []string {
+	exitCommand := c.config.ExitCommand
+	n := len(exitCommand)
+	if n >= 3 && exitCommand[n-3] == "container" && exitCommand[n-2] == "cleanup" {
+		cmd := append([]string{}, exitCommand[:n-3]...)
+		return append(cmd, "healthcheck", "run", c.ID())
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "podman"
+	}
+	return []string{executable, "healthcheck", "run", c.ID()}
+}
+
+// startupHealthCheckUnit returns the name of the timer running the startup
+// healthcheck of the container since it last started. The name differs on
+// every start, as a container restarted by its startup healthcheck creates
+// the new timer while the unit of the old one still runs.
+func (c *Container) startupHealthCheckUnit() string {
+	return fmt.Sprintf("%s-startup-%d", c.ID(), c.state.StartedTime.UnixNano())
+}
+
+// startHealthCheck resets the health of a starting container with a
+// healthcheck and schedules its healthchecks, starting with its startup
+// healthcheck if it has one. Containers start even if their healthchecks
+// cannot be scheduled.
+func (c *Container) startHealthCheck() {
+	c.state.StartupHealthCheckPassed = false
+	hc, unit := c.config.HealthCheckConfig, c.ID()
+	if c.config.StartupHealthCheckConfig != nil {
+		hc, unit = c.config.StartupHealthCheckConfig, c.startupHealthCheckUnit()
+	}
+	if hc == nil {
+		return
+	}
+	c.state.HealthCheck = &inspect.HealthCheckResults{Status: HealthCheckStarting}
+	if err := c.createHealthCheckTimer(unit, healthCheckInterval(hc)); err != nil {
+		logrus.Warnf("Healthchecks of container %s will not run: %v", c.ID(), err)
+	}
+}
+
+// stopHealthCheck unschedules the healthchecks of a container that stopped
+func (c *Container) stopHealthCheck() {
+	if c.config.StartupHealthCheckConfig != nil {
+		if err := c.removeHealthCheckTimer(c.startupHealthCheckUnit()); err != nil {
+			logrus.Debugf("Error removing the startup healthcheck timer of container %s: %v", c.ID(), err)
+		}
+	}
+	if c.config.HealthCheckConfig == nil {
+		return
+	}
+	if err := c.removeHealthCheckTimer(c.ID()); err != nil {
+		logrus.Debugf("Error removing the healthcheck timer of container %s: %v", c.ID(), err)
+	}
+}
+
+// NotifyStarted waits for the startup healthcheck of the container to pass
+// and then reports the container ready to systemd, through the socket in
+// NOTIFY_SOCKET, with conmon as the main process of the service. It does
+// nothing when NOTIFY_SOCKET is not set or the container has no startup
+// healthcheck, as the container reports itself ready then.
+func (c *Container) NotifyStarted(ctx context.Context) error {
+	socket, ok := os.LookupEnv("NOTIFY_SOCKET")
+	if !ok || c.config.StartupHealthCheckConfig == nil {
+		return nil
+	}
+	started, err := c.waitStarted(ctx)
+	if err != nil {
+		return err
+	}
+	if !started {
+		return errors.Wrapf(ErrCtrStopped, "container %s stopped before its startup healthcheck passed", c.ID())
+	}
+	message := "READY=1"
+	if pid, err := readConmonPidFile(c.config.ConmonPidFile); err == nil {
+		message = fmt.Sprintf("MAINPID=%d\n%s", pid, message)
+	}
+	return sdNotify(socket, message)
+}
+
+// readConmonPidFile returns the PID conmon wrote to path
+func readConmonPidFile(path string) (int, error) {
+	if path == "" {
+		return 0, errors.Errorf("no conmon PID file")
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(content)))
+}
+
+// sdNotify sends message to the systemd notification socket at path. A path
+// starting with @ is an abstract socket.
+func sdNotify(path, message string) error {
+	addr := &net.UnixAddr{Name: path, Net: "unixgram"}
+	if strings.HasPrefix(path, "@") {
+		addr.Name = "\x00" + path[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		return errors.Wrapf(err, "error connecting to notification socket %s", path)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(message)); err != nil {
+		return errors.Wrapf(err, "error notifying %s", path)
+	}
+	return nil
+}
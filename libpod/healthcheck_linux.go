@@ -0,0 +1,65 @@
+// +build linux
+
+package libpod
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// healthCheckTimerArgs returns the arguments of systemd-run creating the
+// transient timer unit that runs command at interval, from the time it is
+// created. user creates it in the user instance of systemd.
+func healthCheckTimerArgs(unit string, interval time.Duration, command []string, user bool) []string {
+	var args []string
+	if user {
+		args = append(args, "--user")
+	}
+	every := fmt.Sprintf("%dms", interval/time.Millisecond)
+	args = append(args,
+		"--unit", unit,
+		"--description", "podman healthcheck run "+unit,
+		"--on-active="+every,
+		"--on-unit-inactive="+every,
+		"--timer-property=AccuracySec=1s",
+		"--")
+	return append(args, command...)
+}
+
+// createHealthCheckTimer creates the systemd timer unit running the
+// healthcheck of the container at interval, replacing any left by a previous
+// run
+func (c *Container) createHealthCheckTimer(unit string, interval time.Duration) error {
+	path, err := exec.LookPath("systemd-run")
+	if err != nil {
+		return errors.Wrapf(err, "systemd-run is needed to schedule healthchecks")
+	}
+	if err := c.removeHealthCheckTimer(unit); err != nil {
+		logrus.Debugf("No previous healthcheck timer of container %s removed: %v", c.ID(), err)
+	}
+	args := healthCheckTimerArgs(unit, interval, c.healthCheckTimerCommand(), rootless.IsRootless())
+	logrus.Debugf("Creating healthcheck timer with %s %s", path, strings.Join(args, " "))
+	if output, err := exec.Command(path, args...).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "error creating healthcheck timer: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// removeHealthCheckTimer stops the systemd timer unit running the
+// healthcheck of the container, which removes the transient unit
+func (c *Container) removeHealthCheckTimer(unit string) error {
+	args := []string{"stop", unit + ".timer"}
+	if rootless.IsRootless() {
+		args = append([]string{"--user"}, args...)
+	}
+	if output, err := exec.Command("systemctl", args...).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "error stopping healthcheck timer: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+// +build linux
+
+package libpod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheckTimerArgs(t *testing.T) {
+	command := []string{"/usr/bin/podman", "healthcheck", "run", "abc"}
+	assert.Equal(t, []string{
+		"--unit", "abc",
+		"--description", "podman healthcheck run abc",
+		"--on-active=30000ms",
+		"--on-unit-inactive=30000ms",
+		"--timer-property=AccuracySec=1s",
+		"--",
+		"/usr/bin/podman", "healthcheck", "run", "abc",
+	}, healthCheckTimerArgs("abc", 30*time.Second, command, false))
+
+	args := healthCheckTimerArgs("abc", 90*time.Second, command, true)
+	assert.Equal(t, "--user", args[0])
+	assert.Contains(t, args, "--on-unit-inactive=90000ms")
+}
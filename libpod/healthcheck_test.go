@@ -0,0 +1,138 @@
+package libpod
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containers/image/manifest"
+	"github.com/containers/libpod/pkg/inspect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheckCommand(t *testing.T) {
+	cmd, err := healthCheckCommand([]string{"CMD", "curl", "-f", "http://localhost/"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"curl", "-f", "http://localhost/"}, cmd)
+
+	cmd, err = healthCheckCommand([]string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/bin/sh", "-c", "curl -f http://localhost/ || exit 1"}, cmd)
+
+	for _, test := range [][]string{
+		nil,
+		{"CMD"},
+		{"CMD-SHELL"},
+		{"CMD-SHELL", "true", "false"},
+		{"NONE"},
+		{"curl", "-f", "http://localhost/"},
+	} {
+		_, err := healthCheckCommand(test)
+		assert.Error(t, err, strings.Join(test, " "))
+	}
+}
+
+func TestUpdateHealthCheckResults(t *testing.T) {
+	results := &inspect.HealthCheckResults{Status: HealthCheckStarting}
+	pass := inspect.HealthCheckLog{ExitCode: 0}
+	fail := inspect.HealthCheckLog{ExitCode: 1}
+
+	// Failures short of the retries leave a starting container starting
+	updateHealthCheckResults(results, fail, 2)
+	assert.Equal(t, HealthCheckStarting, results.Status)
+	assert.Equal(t, 1, results.FailingStreak)
+
+	updateHealthCheckResults(results, pass, 2)
+	assert.Equal(t, HealthCheckHealthy, results.Status)
+	assert.Equal(t, 0, results.FailingStreak)
+
+	updateHealthCheckResults(results, fail, 2)
+	assert.Equal(t, HealthCheckHealthy, results.Status)
+	updateHealthCheckResults(results, fail, 2)
+	assert.Equal(t, HealthCheckUnhealthy, results.Status)
+	assert.Equal(t, 2, results.FailingStreak)
+
+	for i := 0; i < maxHealthCheckLogLength; i++ {
+		updateHealthCheckResults(results, fail, 2)
+	}
+	assert.Len(t, results.Log, maxHealthCheckLogLength)
+	assert.Equal(t, HealthCheckUnhealthy, results.Status)
+	assert.Equal(t, 2+maxHealthCheckLogLength, results.FailingStreak)
+}
+
+func TestUpdateStartupHealthCheckResults(t *testing.T) {
+	results := &inspect.HealthCheckResults{Status: HealthCheckStarting}
+	pass := inspect.HealthCheckLog{ExitCode: 0}
+	fail := inspect.HealthCheckLog{ExitCode: 1}
+
+	assert.False(t, updateStartupHealthCheckResults(results, fail))
+	assert.False(t, updateStartupHealthCheckResults(results, fail))
+	assert.Equal(t, 2, results.FailingStreak)
+	assert.Equal(t, HealthCheckStarting, results.Status)
+
+	assert.True(t, updateStartupHealthCheckResults(results, pass))
+	assert.Equal(t, 0, results.FailingStreak)
+	assert.Equal(t, HealthCheckStarting, results.Status)
+	assert.Len(t, results.Log, 3)
+}
+
+func TestStartedWaitsForStartupHealthCheck(t *testing.T) {
+	c := &Container{
+		config: &ContainerConfig{ID: "abc"},
+		state:  &containerState{State: ContainerStateRunning},
+	}
+	assert.True(t, c.started())
+
+	c.config.StartupHealthCheckConfig = &manifest.Schema2HealthConfig{Test: []string{"CMD", "true"}}
+	assert.False(t, c.started())
+	c.state.StartupHealthCheckPassed = true
+	assert.True(t, c.started())
+
+	c.state.State = ContainerStateStopped
+	assert.False(t, c.started())
+}
+
+func TestStartupHealthCheckUnit(t *testing.T) {
+	c := &Container{
+		config: &ContainerConfig{ID: "abc"},
+		state:  &containerState{StartedTime: time.Unix(0, 42)},
+	}
+	unit := c.startupHealthCheckUnit()
+	assert.Equal(t, "abc-startup-42", unit)
+	c.state.StartedTime = time.Unix(1, 0)
+	assert.NotEqual(t, unit, c.startupHealthCheckUnit())
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{limit: 8}
+	n, err := b.Write([]byte("healthy\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 8, n)
+	n, err = b.Write([]byte("dropped"))
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+	assert.Equal(t, "healthy\n", b.String())
+}
+
+func TestHealthCheckTimerCommand(t *testing.T) {
+	c := &Container{config: &ContainerConfig{ID: "abc"}}
+	c.config.ExitCommand = []string{"/usr/bin/podman", "--root", "/var/lib/containers/storage", "container", "cleanup", "abc"}
+	assert.Equal(t, []string{"/usr/bin/podman", "--root", "/var/lib/containers/storage", "healthcheck", "run", "abc"}, c.healthCheckTimerCommand())
+
+	c.config.ExitCommand = nil
+	cmd := c.healthCheckTimerCommand()
+	assert.Equal(t, []string{"healthcheck", "run", "abc"}, cmd[1:])
+}
+
+func TestHealthCheckDefaults(t *testing.T) {
+	hc := &manifest.Schema2HealthConfig{Test: []string{"CMD", "true"}}
+	assert.Equal(t, DefaultHealthCheckInterval, healthCheckInterval(hc))
+	assert.Equal(t, DefaultHealthCheckTimeout, healthCheckTimeout(hc))
+	assert.Equal(t, DefaultHealthCheckRetries, healthCheckRetries(hc))
+
+	hc = &manifest.Schema2HealthConfig{Test: []string{"CMD", "true"}, Interval: time.Minute, Timeout: 5 * time.Second, Retries: 1}
+	assert.Equal(t, time.Minute, healthCheckInterval(hc))
+	assert.Equal(t, 5*time.Second, healthCheckTimeout(hc))
+	assert.Equal(t, 1, healthCheckRetries(hc))
+}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package libpod
+
+import "time"
+
+func (c *Container) createHealthCheckTimer(unit string, interval time.Duration) error {
+	return ErrOSNotSupported
+}
+
+func (c *Container) removeHealthCheckTimer(unit string) error {
+	return ErrOSNotSupported
+}
@@ -0,0 +1,169 @@
+package image
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// blobCacheMode is the mode of the directory of the shared blob cache. All
+// users can add blobs, but not list or remove those of others: a blob can only
+// be read by users knowing its digest, from the manifest of an image they
+// were able to pull.
+const blobCacheMode = os.ModeSticky | 0733
+
+// copySuffix is part of the name of the private copies of shared blobs
+const copySuffix = ".copy"
+
+// sharedCacheReference wraps the reference of an image being pulled so that
+// its blobs are read from a blob cache shared by the stores of the host, and
+// the blobs missing from it are added to it while they are downloaded. Blobs
+// are stored by digest, so that the blobs of images pulled by several users,
+// or into several stores, are only downloaded once.
+// The blobs of the cache can be owned by other users, who can change them at
+// any time, so they are copied into the private directory copyDir while their
+// digest is verified, and the copies are read instead.
+type sharedCacheReference struct {
+	types.ImageReference
+	cacheDir string
+	copyDir  string
+}
+
+func newSharedCacheReference(ref types.ImageReference, cacheDir, copyDir string) (*sharedCacheReference, error) {
+	if err := os.MkdirAll(copyDir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "error creating pull cache directory %s", copyDir)
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheDir), 0755); err != nil {
+		return nil, errors.Wrapf(err, "error creating blob cache directory %s", cacheDir)
+	}
+	if err := os.Mkdir(cacheDir, blobCacheMode); err != nil {
+		if !os.IsExist(err) {
+			return nil, errors.Wrapf(err, "error creating blob cache directory %s", cacheDir)
+		}
+	} else if err := os.Chmod(cacheDir, blobCacheMode); err != nil {
+		// The umask was applied to the mode of the new directory
+		return nil, errors.Wrapf(err, "error setting the permissions of blob cache directory %s", cacheDir)
+	}
+	return &sharedCacheReference{ImageReference: ref, cacheDir: cacheDir, copyDir: copyDir}, nil
+}
+
+// NewImageSource returns a source reading blobs through the cache
+func (r *sharedCacheReference) NewImageSource(ctx context.Context, sys *types.SystemContext) (types.ImageSource, error) {
+	src, err := r.ImageReference.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+	return &sharedCacheSource{ImageSource: src, cacheDir: r.cacheDir, copyDir: r.copyDir}, nil
+}
+
+type sharedCacheSource struct {
+	types.ImageSource
+	cacheDir string
+	copyDir  string
+}
+
+// GetBlob returns a private copy of the blob from the cache if it is there and
+// valid, and otherwise downloads it into the cache while returning it
+func (s *sharedCacheSource) GetBlob(ctx context.Context, info types.BlobInfo) (io.ReadCloser, int64, error) {
+	if err := info.Digest.Validate(); err != nil {
+		return s.ImageSource.GetBlob(ctx, info)
+	}
+	path := filepath.Join(s.cacheDir, info.Digest.Algorithm().String()+"-"+info.Digest.Hex())
+
+	cached, size, err := copySharedBlob(path, info.Digest, s.copyDir)
+	if err != nil {
+		// The blob may have been added by another user, who alone
+		// can remove it if it is corrupted: download it instead
+		logrus.Debugf("Error reading blob %s from the blob cache: %v", info.Digest, err)
+	}
+	if cached != nil {
+		logrus.Debugf("Using blob %s from the blob cache", info.Digest)
+		// The time of the last use tells which blobs can be pruned
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			logrus.Debugf("Error updating the time of cached blob %s: %v", path, err)
+		}
+		return cached, size, nil
+	}
+
+	rc, size, err := s.ImageSource.GetBlob(ctx, info)
+	if err != nil {
+		return nil, 0, err
+	}
+	partial, err := ioutil.TempFile(s.cacheDir, filepath.Base(path)+partialSuffix)
+	if err != nil {
+		// The cache is an optimization, pull without it
+		logrus.Debugf("Error caching blob %s: %v", info.Digest, err)
+		return rc, size, nil
+	}
+	return &cachingReader{
+		source:   rc,
+		partial:  partial,
+		path:     path,
+		mode:     0644,
+		digester: info.Digest.Algorithm().Digester(),
+		expected: info.Digest,
+	}, size, nil
+}
+
+// copySharedBlob copies the blob at path of the shared cache into a private
+// file of dir, verifying its digest while copying, and returns the copy, which
+// is removed once closed. The blob is neither read through a symlink nor
+// opened if it is not a regular file, which could block. It returns nil if
+// there is no valid cached blob.
+func copySharedBlob(path string, expected digest.Digest, dir string) (io.ReadCloser, int64, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NOFOLLOW|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, errors.Wrapf(err, "error opening cached blob %s", path)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "error reading cached blob %s", path)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, 0, errors.Errorf("cached blob %s is not a regular file", path)
+	}
+
+	copied, err := ioutil.TempFile(dir, filepath.Base(path)+copySuffix)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "error copying cached blob %s", path)
+	}
+	// The copy is only reachable through the file, and goes away with it
+	if err := os.Remove(copied.Name()); err != nil {
+		copied.Close()
+		return nil, 0, errors.Wrapf(err, "error copying cached blob %s", path)
+	}
+	verifier := expected.Verifier()
+	size, err := io.Copy(io.MultiWriter(copied, verifier), f)
+	if err != nil {
+		copied.Close()
+		return nil, 0, errors.Wrapf(err, "error copying cached blob %s", path)
+	}
+	if !verifier.Verified() {
+		copied.Close()
+		logrus.Debugf("Cached blob %s does not match its digest, discarding it", path)
+		// Only the user who added the blob can remove it
+		if err := os.Remove(path); err != nil {
+			logrus.Debugf("Error removing corrupted cached blob %s: %v", path, err)
+		}
+		return nil, 0, nil
+	}
+	if _, err := copied.Seek(0, io.SeekStart); err != nil {
+		copied.Close()
+		return nil, 0, errors.Wrapf(err, "error copying cached blob %s", path)
+	}
+	return copied, size, nil
+}
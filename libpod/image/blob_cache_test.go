@@ -0,0 +1,120 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSource serves a single blob, counting the times it was downloaded
+type countingSource struct {
+	types.ImageSource
+	blob      []byte
+	downloads int
+}
+
+func (s *countingSource) GetBlob(ctx context.Context, info types.BlobInfo) (io.ReadCloser, int64, error) {
+	s.downloads++
+	return ioutil.NopCloser(bytes.NewReader(s.blob)), int64(len(s.blob)), nil
+}
+
+func TestSharedCacheSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobcache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cacheDir := filepath.Join(dir, "cache", "blobs")
+	copyDir := filepath.Join(dir, "pull-cache")
+
+	ref, err := newSharedCacheReference(nil, cacheDir, copyDir)
+	require.NoError(t, err)
+	info, err := os.Stat(cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, blobCacheMode, info.Mode()&(os.ModeSticky|os.ModePerm))
+	// The cache directory may already exist
+	_, err = newSharedCacheReference(nil, cacheDir, copyDir)
+	require.NoError(t, err)
+
+	blob := []byte("layer contents")
+	blobInfo := types.BlobInfo{Digest: digest.FromBytes(blob), Size: int64(len(blob))}
+	upstream := &countingSource{blob: blob}
+	src := &sharedCacheSource{ImageSource: upstream, cacheDir: ref.cacheDir, copyDir: ref.copyDir}
+
+	// Sources of other pulls read the blob downloaded by the first one
+	for i := 0; i < 2; i++ {
+		rc, size, err := src.GetBlob(context.Background(), blobInfo)
+		require.NoError(t, err)
+		read, err := ioutil.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		assert.Equal(t, blob, read)
+		assert.Equal(t, int64(len(blob)), size)
+		src = &sharedCacheSource{ImageSource: upstream, cacheDir: ref.cacheDir, copyDir: ref.copyDir}
+	}
+	assert.Equal(t, 1, upstream.downloads)
+
+	entries, err := ioutil.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "sha256-"+blobInfo.Digest.Hex(), entries[0].Name())
+	assert.Equal(t, os.FileMode(0644), entries[0].Mode().Perm())
+
+	// A blob not matching its digest is downloaded again
+	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, entries[0].Name()), []byte("corrupted"), 0644))
+	rc, _, err := src.GetBlob(context.Background(), blobInfo)
+	require.NoError(t, err)
+	read, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, blob, read)
+	assert.Equal(t, 2, upstream.downloads)
+
+	// The private copies of the blobs read are removed
+	copies, err := ioutil.ReadDir(copyDir)
+	require.NoError(t, err)
+	assert.Len(t, copies, 0)
+}
+
+func TestCopySharedBlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobcache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	blob := []byte("layer contents")
+	blobDigest := digest.FromBytes(blob)
+	path := filepath.Join(dir, "sha256-"+blobDigest.Hex())
+	require.NoError(t, ioutil.WriteFile(path, blob, 0644))
+
+	// Changing the cached blob does not change the copy being read
+	rc, size, err := copySharedBlob(path, blobDigest, dir)
+	require.NoError(t, err)
+	require.NotNil(t, rc)
+	assert.Equal(t, int64(len(blob)), size)
+	require.NoError(t, ioutil.WriteFile(path, []byte("rewritten cont"), 0644))
+	read, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, blob, read)
+
+	// Symlinks are not followed
+	target := filepath.Join(dir, "target")
+	require.NoError(t, ioutil.WriteFile(target, blob, 0644))
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, os.Symlink(target, path))
+	_, _, err = copySharedBlob(path, blobDigest, dir)
+	assert.Error(t, err)
+
+	// FIFOs are refused rather than blocking
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, syscall.Mkfifo(path, 0644))
+	_, _, err = copySharedBlob(path, blobDigest, dir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package image
+
+import (
+	"time"
+
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// buildCacheKey is the key of the data marking the intermediate images
+// committed by builds, which later builds reuse as their cache
+const buildCacheKey = "libpod-build-cache"
+
+// BuildCacheEntry describes an image of the build cache
+type BuildCacheEntry struct {
+	ID      string    `json:"id"`
+	Parent  string    `json:"parent,omitempty"`
+	Created time.Time `json:"created"`
+	// Size is the size of the layer the step of the build added
+	Size int64 `json:"size"`
+	// Containers is the number of containers using the image
+	Containers int `json:"containers"`
+}
+
+// ImageIDs returns the IDs of the images in the store
+func (ir *Runtime) ImageIDs() ([]string, error) {
+	images, err := ir.store.Images()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(images))
+	for _, img := range images {
+		ids = append(ids, img.ID)
+	}
+	return ids, nil
+}
+
+// MarkBuildCache marks the intermediate images of a build, the images not in
+// existing it committed without a name for each of its steps. The image of
+// the last step of the build, which has no child, is its result.
+func (ir *Runtime) MarkBuildCache(existing []string) error {
+	images, err := ir.GetImages()
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		if len(img.Names()) > 0 || util.StringInSlice(img.ID(), existing) {
+			continue
+		}
+		isParent, err := img.IsParent()
+		if err != nil {
+			return err
+		}
+		if !isParent {
+			continue
+		}
+		if err := ir.store.SetImageBigData(img.ID(), buildCacheKey, []byte("{}")); err != nil {
+			return errors.Wrapf(err, "error marking image %s as build cache", img.ID())
+		}
+	}
+	return nil
+}
+
+// IsBuildCache returns true if the image is an intermediate image of a build
+// which was not named since
+func (i *Image) IsBuildCache() bool {
+	if i.image == nil {
+		img, err := i.getLocalImage()
+		if err != nil {
+			return false
+		}
+		i.image = img
+	}
+	if len(i.image.Names) > 0 {
+		return false
+	}
+	return util.StringInSlice(buildCacheKey, i.image.BigDataNames)
+}
+
+// BuildCacheFilter allows you to filter images for the build cache
+func BuildCacheFilter() ResultFilter {
+	return func(i *Image) bool {
+		return i.IsBuildCache()
+	}
+}
+
+// BuildCacheEntry returns the description of an image of the build cache
+func (i *Image) BuildCacheEntry() (*BuildCacheEntry, error) {
+	entry := &BuildCacheEntry{
+		ID:      i.ID(),
+		Created: i.Created(),
+	}
+	parent, err := i.GetParent()
+	if err != nil {
+		return nil, err
+	}
+	if parent != nil {
+		entry.Parent = parent.ID()
+	}
+	layer, err := i.Layer()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting top layer of image %s", i.ID())
+	}
+	entry.Size = layer.UncompressedSize
+	containers, err := i.Containers()
+	if err != nil {
+		return nil, err
+	}
+	entry.Containers = len(containers)
+	return entry, nil
+}
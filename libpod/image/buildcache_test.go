@@ -0,0 +1,23 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/containers/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBuildCache(t *testing.T) {
+	cached := &Image{image: &storage.Image{ID: "cached", BigDataNames: []string{"manifest", buildCacheKey}}}
+	assert.True(t, cached.IsBuildCache())
+
+	// Images named since they were built are not cache anymore
+	named := &Image{image: &storage.Image{ID: "named", Names: []string{"localhost/app:latest"}, BigDataNames: []string{"manifest", buildCacheKey}}}
+	assert.False(t, named.IsBuildCache())
+
+	dangling := &Image{image: &storage.Image{ID: "dangling", BigDataNames: []string{"manifest"}}}
+	assert.False(t, dangling.IsBuildCache())
+
+	images := FilterImages([]*Image{cached, named, dangling}, []ResultFilter{BuildCacheFilter()})
+	assert.Equal(t, []*Image{cached}, images)
+}
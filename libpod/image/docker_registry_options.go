@@ -21,6 +21,13 @@ type DockerRegistryOptions struct {
 	// DockerInsecureSkipTLSVerify turns off verification of TLS
 	// certificates and allows connecting to registries without encryption.
 	DockerInsecureSkipTLSVerify bool
+	// OSChoice and ArchitectureChoice select the image of another platform
+	// than the host in manifest lists, such as wasi/wasm32.
+	OSChoice           string
+	ArchitectureChoice string
+	// RegistriesConfPath is the registries file searched for unqualified
+	// names and insecure registries, instead of the global one.
+	RegistriesConfPath string
 }
 
 // GetSystemContext constructs a new system context from a parent context. the values in the DockerRegistryOptions, and other parameters.
@@ -30,6 +37,9 @@ func (o DockerRegistryOptions) GetSystemContext(parent *types.SystemContext, add
 		DockerCertPath:              o.DockerCertPath,
 		DockerInsecureSkipTLSVerify: o.DockerInsecureSkipTLSVerify,
 		DockerArchiveAdditionalTags: additionalDockerArchiveTags,
+		OSChoice:                    o.OSChoice,
+		ArchitectureChoice:          o.ArchitectureChoice,
+		SystemRegistriesConfPath:    o.RegistriesConfPath,
 	}
 	if parent != nil {
 		sc.SignaturePolicyPath = parent.SignaturePolicyPath
@@ -39,6 +49,15 @@ func (o DockerRegistryOptions) GetSystemContext(parent *types.SystemContext, add
 	return sc
 }
 
+// registriesConfPath returns the registries file of the options, which may be
+// nil, or an empty path for the global one
+func (o *DockerRegistryOptions) registriesConfPath() string {
+	if o == nil {
+		return ""
+	}
+	return o.RegistriesConfPath
+}
+
 // GetSystemContext Constructs a new containers/image/types.SystemContext{} struct from the given signaturePolicy path
 func GetSystemContext(signaturePolicyPath, authFilePath string, forceCompress bool) *types.SystemContext {
 	sc := &types.SystemContext{}
@@ -0,0 +1,35 @@
+package image
+
+import (
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// ephemeralKey is the key of the data marking the images built for a single
+// container, which are removed with it
+const ephemeralKey = "libpod-ephemeral"
+
+// MarkEphemeral marks an image as built for a single container, so that it is
+// removed with the last container using it
+func (ir *Runtime) MarkEphemeral(id string) error {
+	if err := ir.store.SetImageBigData(id, ephemeralKey, []byte("{}")); err != nil {
+		return errors.Wrapf(err, "error marking image %s as ephemeral", id)
+	}
+	return nil
+}
+
+// IsEphemeral returns true if the image was built for a single container and
+// was not named since
+func (i *Image) IsEphemeral() bool {
+	if i.image == nil {
+		img, err := i.getLocalImage()
+		if err != nil {
+			return false
+		}
+		i.image = img
+	}
+	if len(i.image.Names) > 0 {
+		return false
+	}
+	return util.StringInSlice(ephemeralKey, i.image.BigDataNames)
+}
@@ -0,0 +1,21 @@
+package image
+
+import (
+	"github.com/containers/libpod/libpod/events"
+	"github.com/sirupsen/logrus"
+)
+
+// newImageEvent writes an event of an image, named name, if the runtime has
+// an eventer
+func (ir *Runtime) newImageEvent(status events.Status, id, name string) {
+	if ir.Eventer == nil {
+		return
+	}
+	e := events.NewEvent(status)
+	e.ID = id
+	e.Image = name
+	e.Type = events.Image
+	if err := ir.Eventer.Write(e); err != nil {
+		logrus.Errorf("Unable to write %s event of image %s: %v", status, id, err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/containers/image/types"
 	"github.com/containers/libpod/libpod/common"
 	"github.com/containers/libpod/libpod/driver"
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/pkg/inspect"
 	"github.com/containers/libpod/pkg/registries"
 	"github.com/containers/libpod/pkg/util"
@@ -52,12 +54,43 @@ type Image struct {
 	image        *storage.Image
 	imageruntime *Runtime
 	repotagsMap  map[string][]string
+	// index is shared with the other images listed with this one, if any
+	index *imageIndex
+	// size is the size of the image, once computed
+	size *uint64
 }
 
 // Runtime contains the store
 type Runtime struct {
-	store               storage.Store
-	SignaturePolicyPath string
+	store storage.Store
+	// signaturePolicyPath is the string path of the signature policy used
+	// by default, which may be replaced while images are pulled
+	signaturePolicyPath atomic.Value
+	// BlobCacheDir is the directory of the blob cache shared by the
+	// stores of the host, which pulls read blobs from. It is not used if
+	// empty.
+	BlobCacheDir string
+	// Eventer receives the events of images, such as pulls. Events are
+	// not written if it is nil.
+	Eventer events.Eventer
+	// StorageQuota is the maximum total size, in bytes, of the layers of
+	// the store. Pulls and imports are refused once it is reached. There
+	// is no quota if it is 0.
+	StorageQuota int64
+	// layerSizes keeps the sizes of layers computed for the StorageQuota
+	layerSizes *layerSizeCache
+}
+
+// SignaturePolicyPath returns the path of the signature policy used by
+// default, "" for the system default
+func (ir *Runtime) SignaturePolicyPath() string {
+	path, _ := ir.signaturePolicyPath.Load().(string)
+	return path
+}
+
+// SetSignaturePolicyPath sets the path of the signature policy used by default
+func (ir *Runtime) SetSignaturePolicyPath(path string) {
+	ir.signaturePolicyPath.Store(path)
 }
 
 // ErrRepoTagNotFound is the error returned when the image id given doesn't match a rep tag in store
@@ -66,7 +99,8 @@ var ErrRepoTagNotFound = errors.New("unable to match user input to any specific
 // NewImageRuntimeFromStore creates an ImageRuntime based on a provided store
 func NewImageRuntimeFromStore(store storage.Store) *Runtime {
 	return &Runtime{
-		store: store,
+		store:      store,
+		layerSizes: newLayerSizeCache(),
 	}
 }
 
@@ -82,7 +116,8 @@ func NewImageRuntimeFromOptions(options storage.StoreOptions) (*Runtime, error)
 	}
 
 	return &Runtime{
-		store: store,
+		store:      store,
+		layerSizes: newLayerSizeCache(),
 	}, nil
 }
 
@@ -143,7 +178,7 @@ func (ir *Runtime) New(ctx context.Context, name, signaturePolicyPath, authfile
 
 	// The image is not local
 	if signaturePolicyPath == "" {
-		signaturePolicyPath = ir.SignaturePolicyPath
+		signaturePolicyPath = ir.SignaturePolicyPath()
 	}
 	imageName, err := ir.pullImageFromHeuristicSource(ctx, name, writer, authfile, signaturePolicyPath, signingoptions, dockeroptions, forceSecure)
 	if err != nil {
@@ -156,6 +191,7 @@ func (ir *Runtime) New(ctx context.Context, name, signaturePolicyPath, authfile
 		return nil, errors.Wrapf(err, "error retrieving local image after pulling %s", name)
 	}
 	newImage.image = img
+	ir.newImageEvent(events.Pull, img.ID, imageName[0])
 	return &newImage, nil
 }
 
@@ -165,7 +201,7 @@ func (ir *Runtime) LoadFromArchiveReference(ctx context.Context, srcRef types.Im
 	var newImages []*Image
 
 	if signaturePolicyPath == "" {
-		signaturePolicyPath = ir.SignaturePolicyPath
+		signaturePolicyPath = ir.SignaturePolicyPath()
 	}
 	imageNames, err := ir.pullImageFromReference(ctx, srcRef, writer, "", signaturePolicyPath, SigningOptions{}, &DockerRegistryOptions{}, false)
 	if err != nil {
@@ -184,6 +220,7 @@ func (ir *Runtime) LoadFromArchiveReference(ctx context.Context, srcRef types.Im
 		}
 		newImage.image = img
 		newImages = append(newImages, &newImage)
+		ir.newImageEvent(events.LoadFromArchive, img.ID, name)
 	}
 
 	return newImages, nil
@@ -348,6 +385,11 @@ func (i *Image) Remove(force bool) error {
 	if _, err := i.imageruntime.store.DeleteImage(i.ID(), true); err != nil {
 		return err
 	}
+	name := i.InputName
+	if name == "" && len(i.Names()) > 0 {
+		name = i.Names()[0]
+	}
+	i.imageruntime.newImageEvent(events.Remove, i.ID(), name)
 	for parent != nil {
 		nextParent, err := parent.GetParent()
 		if err != nil {
@@ -435,6 +477,7 @@ func (ir *Runtime) GetImages() ([]*Image, error) {
 		img := ir.newFromStorage(&image)
 		newImages = append(newImages, img)
 	}
+	newImageIndex(newImages)
 	return newImages, nil
 }
 
@@ -486,6 +529,7 @@ func (i *Image) TagImage(tag string) error {
 		return err
 	}
 	i.reloadImage()
+	i.imageruntime.newImageEvent(events.Tag, i.ID(), tag)
 	return nil
 }
 
@@ -506,6 +550,7 @@ func (i *Image) UntagImage(tag string) error {
 		return err
 	}
 	i.reloadImage()
+	i.imageruntime.newImageEvent(events.Untag, i.ID(), tag)
 	return nil
 }
 
@@ -517,17 +562,9 @@ func (i *Image) PushImageToHeuristicDestination(ctx context.Context, destination
 	}
 
 	// Get the destination Image Reference
-	dest, err := alltransports.ParseImageName(destination)
+	dest, err := ParseDestination(destination, DefaultTransport)
 	if err != nil {
-		if hasTransport(destination) {
-			return errors.Wrapf(err, "error getting destination imageReference for %q", destination)
-		}
-		// Try adding the images default transport
-		destination2 := DefaultTransport + destination
-		dest, err = alltransports.ParseImageName(destination2)
-		if err != nil {
-			return err
-		}
+		return errors.Wrapf(err, "error getting destination imageReference for %q", destination)
 	}
 	return i.PushImageToReference(ctx, dest, manifestMIMEType, authFile, signaturePolicyPath, writer, forceCompress, signingOptions, dockerRegistryOptions, forceSecure, additionalDockerArchiveTags)
 }
@@ -542,6 +579,12 @@ func (i *Image) PushImageToReference(ctx context.Context, dest types.ImageRefere
 	}
 	defer policyContext.Destroy()
 
+	if signingOptions.SignBy != "" {
+		if err := checkSigning(sc, dest); err != nil {
+			return err
+		}
+	}
+
 	// Look up the source image, expecting it to be in local storage
 	src, err := is.Transport.ParseStoreReference(i.imageruntime.store, i.ID())
 	if err != nil {
@@ -569,6 +612,12 @@ func (i *Image) PushImageToReference(ctx context.Context, dest types.ImageRefere
 	if err != nil {
 		return errors.Wrapf(err, "Error copying image to the remote destination")
 	}
+	// Images copied to other transports than registries are saved
+	status := events.Save
+	if dest.Transport().Name() == DockerTransport {
+		status = events.Push
+	}
+	i.imageruntime.newImageEvent(status, i.ID(), transports.ImageName(dest))
 	return nil
 }
 
@@ -596,6 +645,11 @@ func (i *Image) toStorageReference() (types.ImageReference, error) {
 	return i.storeRef, nil
 }
 
+// StorageReference returns the reference of the image in local storage
+func (i *Image) StorageReference() (types.ImageReference, error) {
+	return is.Transport.ParseStoreReference(i.imageruntime.store, "@"+i.ID())
+}
+
 // ToImageRef returns an image reference type from an image
 // TODO: Hopefully we can remove this exported function for mheon
 func (i *Image) ToImageRef(ctx context.Context) (types.Image, error) {
@@ -628,6 +682,24 @@ type sizer interface {
 
 //Size returns the size of the image
 func (i *Image) Size(ctx context.Context) (*uint64, error) {
+	if i.size != nil {
+		return i.size, nil
+	}
+	// Images listed together share the layers of the listing, as long as
+	// the store recorded the sizes of their data blobs
+	if i.index != nil && len(i.image.BigDataSizes) == len(i.image.BigDataNames) {
+		if err := i.index.load(i.imageruntime.store); err != nil {
+			return nil, err
+		}
+		sum, err := i.index.size(i)
+		if err != nil {
+			return nil, err
+		}
+		usum := uint64(sum)
+		i.size = &usum
+		return i.size, nil
+	}
+
 	storeRef, err := is.Transport.ParseStoreReference(i.imageruntime.store, i.ID())
 	if err != nil {
 		return nil, err
@@ -640,7 +712,8 @@ func (i *Image) Size(ctx context.Context) (*uint64, error) {
 	if s, ok := img.(sizer); ok {
 		if sum, err := s.Size(); err == nil {
 			usum := uint64(sum)
-			return &usum, nil
+			i.size = &usum
+			return i.size, nil
 		}
 	}
 	return nil, errors.Errorf("unable to determine size")
@@ -844,6 +917,10 @@ func (i *Image) Inspect(ctx context.Context) (*inspect.ImageData, error) {
 	if err != nil {
 		return nil, err
 	}
+	healthCheck, err := i.HealthCheck(ctx, manifestType)
+	if err != nil {
+		return nil, err
+	}
 
 	data := &inspect.ImageData{
 		ID:              i.ID(),
@@ -868,12 +945,16 @@ func (i *Image) Inspect(ctx context.Context) (*inspect.ImageData, error) {
 		GraphDriver:  driver,
 		ManifestType: manifestType,
 		User:         ociv1Img.Config.User,
+		HealthCheck:  healthCheck,
 	}
 	return data, nil
 }
 
 // Import imports and image into the store and returns an image
 func (ir *Runtime) Import(ctx context.Context, path, reference string, writer io.Writer, signingOptions SigningOptions, imageConfig ociv1.Image) (*Image, error) {
+	if err := ir.CheckStorageQuota(); err != nil {
+		return nil, err
+	}
 	src, err := tarball.Transport.ParseReference(path)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error parsing image name %q", path)
@@ -914,7 +995,12 @@ func (ir *Runtime) Import(ctx context.Context, path, reference string, writer io
 	if err = cp.Image(ctx, policyContext, dest, src, copyOptions); err != nil {
 		return nil, err
 	}
-	return ir.NewFromLocal(reference)
+	newImage, err := ir.NewFromLocal(reference)
+	if err != nil {
+		return nil, err
+	}
+	ir.newImageEvent(events.Import, newImage.ID(), reference)
+	return newImage, nil
 }
 
 // MatchRepoTag takes a string and tries to match it against an
@@ -981,6 +1067,12 @@ func (i *Image) IsParent() (bool, error) {
 
 // GetParent returns the image ID of the parent. Return nil if a parent is not found.
 func (i *Image) GetParent() (*Image, error) {
+	if i.index != nil {
+		if err := i.index.load(i.imageruntime.store); err != nil {
+			return nil, err
+		}
+		return i.index.parent(i)
+	}
 	images, err := i.imageruntime.GetImages()
 	if err != nil {
 		return nil, err
@@ -999,6 +1091,12 @@ func (i *Image) GetParent() (*Image, error) {
 
 // GetChildren returns a list of the imageIDs that depend on the image
 func (i *Image) GetChildren() ([]string, error) {
+	if i.index != nil {
+		if err := i.index.load(i.imageruntime.store); err != nil {
+			return nil, err
+		}
+		return i.index.children(i), nil
+	}
 	var children []string
 	images, err := i.imageruntime.GetImages()
 	if err != nil {
@@ -1074,3 +1172,27 @@ func (i *Image) Comment(ctx context.Context, manifestType string) (string, error
 	}
 	return ociv1Img.History[0].Comment, nil
 }
+
+// HealthCheck returns the healthcheck of containers of the image, which only
+// images in the docker format have
+func (i *Image) HealthCheck(ctx context.Context, manifestType string) (*manifest.Schema2HealthConfig, error) {
+	if manifestType != manifest.DockerV2Schema2MediaType {
+		return nil, nil
+	}
+	imgRef, err := i.toImageRef(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create image reference from image")
+	}
+	blob, err := imgRef.ConfigBlob(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get config blob from image")
+	}
+	b := manifest.Schema2Image{}
+	if err := json.Unmarshal(blob, &b); err != nil {
+		return nil, err
+	}
+	if b.Config == nil {
+		return nil, nil
+	}
+	return b.Config.Healthcheck, nil
+}
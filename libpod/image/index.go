@@ -0,0 +1,121 @@
+package image
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+)
+
+// imageIndex is shared by the images of a single listing of the store. It
+// lists the layers once, when first needed, so that the relations between the
+// images and their sizes are computed without walking the store for every
+// image of the listing.
+type imageIndex struct {
+	images []*Image
+
+	once sync.Once
+	err  error
+	// layers maps layer IDs to layers
+	layers map[string]*storage.Layer
+	// childLayers maps layer IDs to the IDs of their child layers
+	childLayers map[string][]string
+	// byTopLayer maps layer IDs to the images using them as top layer
+	byTopLayer map[string][]*Image
+}
+
+func newImageIndex(images []*Image) *imageIndex {
+	idx := &imageIndex{images: images}
+	for _, img := range images {
+		img.index = idx
+	}
+	return idx
+}
+
+// load lists the layers of the store, once
+func (idx *imageIndex) load(store storage.Store) error {
+	idx.once.Do(func() {
+		layers, err := store.Layers()
+		if err != nil {
+			idx.err = err
+			return
+		}
+		idx.build(layers)
+	})
+	return idx.err
+}
+
+// build indexes the given layers and the images using them
+func (idx *imageIndex) build(layers []storage.Layer) {
+	idx.layers = make(map[string]*storage.Layer, len(layers))
+	idx.childLayers = make(map[string][]string)
+	for i := range layers {
+		layer := &layers[i]
+		idx.layers[layer.ID] = layer
+		if layer.Parent != "" {
+			idx.childLayers[layer.Parent] = append(idx.childLayers[layer.Parent], layer.ID)
+		}
+	}
+	idx.byTopLayer = make(map[string][]*Image, len(idx.images))
+	for _, img := range idx.images {
+		idx.byTopLayer[img.TopLayer()] = append(idx.byTopLayer[img.TopLayer()], img)
+	}
+}
+
+// children returns the IDs of the images whose top layer is a child of the
+// top layer of the given image
+func (idx *imageIndex) children(img *Image) []string {
+	var children []string
+	for _, layerID := range idx.childLayers[img.TopLayer()] {
+		for _, child := range idx.byTopLayer[layerID] {
+			children = append(children, child.ID())
+		}
+	}
+	return children
+}
+
+// parent returns the image whose top layer is the parent of the top layer of
+// the given image, or nil
+func (idx *imageIndex) parent(img *Image) (*Image, error) {
+	layer, ok := idx.layers[img.TopLayer()]
+	if !ok {
+		return nil, errors.Wrapf(storage.ErrLayerUnknown, "layer %s", img.TopLayer())
+	}
+	if parents := idx.byTopLayer[layer.Parent]; len(parents) > 0 && layer.Parent != "" {
+		return parents[0], nil
+	}
+	return nil, nil
+}
+
+// size adds up the sizes of the data blobs, the signatures and the
+// uncompressed layers of the image, as the storage transport does
+func (idx *imageIndex) size(img *Image) (int64, error) {
+	var sum int64
+	for _, size := range img.image.BigDataSizes {
+		sum += size
+	}
+	if img.image.Metadata != "" {
+		var metadata struct {
+			SignatureSizes []int `json:"signature-sizes,omitempty"`
+		}
+		if err := json.Unmarshal([]byte(img.image.Metadata), &metadata); err != nil {
+			return -1, errors.Wrapf(err, "error decoding metadata of image %s", img.ID())
+		}
+		for _, size := range metadata.SignatureSizes {
+			sum += int64(size)
+		}
+	}
+	for layerID := img.TopLayer(); layerID != ""; {
+		layer, ok := idx.layers[layerID]
+		if !ok {
+			return -1, errors.Wrapf(storage.ErrLayerUnknown, "layer %s", layerID)
+		}
+		if layer.UncompressedDigest == "" || layer.UncompressedSize < 0 {
+			return -1, errors.Errorf("size for layer %q is unknown", layerID)
+		}
+		sum += layer.UncompressedSize
+		layerID = layer.Parent
+	}
+	return sum, nil
+}
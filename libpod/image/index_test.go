@@ -0,0 +1,59 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/containers/storage"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestIndex builds an index of the given images over the given layers,
+// without a store
+func newTestIndex(images []*Image, layers []storage.Layer) *imageIndex {
+	idx := newImageIndex(images)
+	idx.once.Do(func() { idx.build(layers) })
+	return idx
+}
+
+func TestImageIndex(t *testing.T) {
+	layers := []storage.Layer{
+		{ID: "base", UncompressedDigest: digest.FromString("base"), UncompressedSize: 100},
+		{ID: "middle", Parent: "base", UncompressedDigest: digest.FromString("middle"), UncompressedSize: 10},
+		{ID: "top", Parent: "middle", UncompressedDigest: digest.FromString("top"), UncompressedSize: 1},
+	}
+	base := &Image{image: &storage.Image{ID: "base-image", TopLayer: "base"}}
+	child := &Image{image: &storage.Image{
+		ID:           "child-image",
+		TopLayer:     "middle",
+		BigDataSizes: map[string]int64{"manifest": 5},
+		Metadata:     `{"signature-sizes":[2,3]}`,
+	}}
+	other := &Image{image: &storage.Image{ID: "other-image", TopLayer: "top"}}
+	idx := newTestIndex([]*Image{base, child, other}, layers)
+
+	assert.Equal(t, []string{"child-image"}, idx.children(base))
+	assert.Equal(t, []string{"other-image"}, idx.children(child))
+	assert.Empty(t, idx.children(other))
+
+	parent, err := idx.parent(child)
+	assert.NoError(t, err)
+	assert.Equal(t, base, parent)
+	parent, err = idx.parent(base)
+	assert.NoError(t, err)
+	assert.Nil(t, parent)
+
+	size, err := idx.size(child)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5+2+3+10+100), size)
+}
+
+func TestImageIndexUnknownLayer(t *testing.T) {
+	img := &Image{image: &storage.Image{ID: "image", TopLayer: "missing"}}
+	idx := newTestIndex([]*Image{img}, nil)
+
+	_, err := idx.parent(img)
+	assert.Error(t, err)
+	_, err = idx.size(img)
+	assert.Error(t, err)
+}
@@ -0,0 +1,239 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/containers/image/docker"
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/image/manifest"
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// ErrNotPinned indicates that an image reference is not pinned by digest, in
+// locked mode
+var ErrNotPinned = errors.New("image reference is not pinned by digest")
+
+// lockfileVersion is the version of the format of lockfiles
+const lockfileVersion = 1
+
+// Lockfile pins the references of images used by containers to the digests
+// of their manifests. Creating containers in locked mode only accepts the
+// references of images pinned by a lockfile, or by digest.
+type Lockfile struct {
+	Version int `json:"version"`
+	// Images maps references of images, as the configuration of
+	// containers names them, to the same references pinned by digest
+	Images map[string]string `json:"images"`
+}
+
+// NewLockfile returns an empty lockfile
+func NewLockfile() *Lockfile {
+	return &Lockfile{Version: lockfileVersion, Images: make(map[string]string)}
+}
+
+// ReadLockfile reads the lockfile at path
+func ReadLockfile(path string) (*Lockfile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading lockfile %s", path)
+	}
+	lockfile := NewLockfile()
+	if err := json.Unmarshal(data, lockfile); err != nil {
+		return nil, errors.Wrapf(err, "error parsing lockfile %s", path)
+	}
+	if lockfile.Version != lockfileVersion {
+		return nil, errors.Errorf("unsupported version %d of lockfile %s", lockfile.Version, path)
+	}
+	for name, pinned := range lockfile.Images {
+		if !isPinned(pinned) {
+			return nil, errors.Wrapf(ErrNotPinned, "invalid lockfile %s, %q is pinned to %q", path, name, pinned)
+		}
+	}
+	return lockfile, nil
+}
+
+// Write writes the lockfile to path
+func (l *Lockfile) Write(path string) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrapf(err, "error writing lockfile %s", path)
+	}
+	return nil
+}
+
+// Marshal returns the content of the lockfile
+func (l *Lockfile) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error encoding lockfile")
+	}
+	return append(data, '\n'), nil
+}
+
+// Names returns the references pinned by the lockfile, sorted
+func (l *Lockfile) Names() []string {
+	names := make([]string, 0, len(l.Images))
+	for name := range l.Images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pin returns the reference of an image to use in locked mode: the reference
+// itself if it is pinned by digest, or else the reference the lockfile pins
+// it to. ErrNotPinned is returned for other references.
+func (l *Lockfile) Pin(name string) (string, error) {
+	if isPinned(name) {
+		return name, nil
+	}
+	if pinned, ok := l.Images[name]; ok {
+		return pinned, nil
+	}
+	// The lockfile may name the image differently, e.g. qualified
+	if normalized := normalizedName(name); normalized != "" {
+		for _, lockedName := range l.Names() {
+			if normalizedName(lockedName) == normalized {
+				return l.Images[lockedName], nil
+			}
+		}
+	}
+	return "", errors.Wrapf(ErrNotPinned, "%q is not pinned by the lockfile", name)
+}
+
+// normalizedName returns the qualified and tagged form of a reference, or ""
+// if it is not a valid reference
+func normalizedName(name string) string {
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return ""
+	}
+	return reference.TagNameOnly(named).String()
+}
+
+// isPinned returns whether a reference names an image by digest
+func isPinned(name string) bool {
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return false
+	}
+	_, ok := named.(reference.Canonical)
+	return ok
+}
+
+// PinnedReference returns name, a reference of the image, pinned to the
+// digest of its manifest
+func (i *Image) PinnedReference(name string) (string, error) {
+	if i.Digest() == "" {
+		return "", errors.Errorf("image %s has no manifest digest, it was not pulled from a registry", i.ID())
+	}
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "error parsing image reference %q", name)
+	}
+	return pinnedName(named, i.Digest())
+}
+
+// ResolveDigest returns name, a reference of an image, pinned to the digest of
+// the manifest of the image: the local image if there is one, or else the
+// image in its registry. Unqualified names are resolved in docker.io.
+func (ir *Runtime) ResolveDigest(ctx context.Context, name string, sc *types.SystemContext) (string, error) {
+	if isPinned(name) {
+		return name, nil
+	}
+	if img, err := ir.NewFromLocal(name); err == nil {
+		localName := localImageName(img, name)
+		if localName == "" {
+			return "", errors.Errorf("image %s has no name to pin", img.ID())
+		}
+		return img.PinnedReference(localName)
+	}
+
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "error parsing image reference %q", name)
+	}
+	ref, err := docker.NewReference(reference.TagNameOnly(named))
+	if err != nil {
+		return "", errors.Wrapf(err, "error parsing image reference %q", name)
+	}
+	src, err := ref.NewImageSource(ctx, sc)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading image %q", name)
+	}
+	defer src.Close()
+	manifestBlob, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading the manifest of image %q", name)
+	}
+	manifestDigest, err := manifest.Digest(manifestBlob)
+	if err != nil {
+		return "", errors.Wrapf(err, "error computing the digest of the manifest of image %q", name)
+	}
+	return pinnedName(named, manifestDigest)
+}
+
+// localImageName returns the name of a local image that name refers to,
+// which qualifies short names found in a search registry. The first name of
+// the image is returned for an image ID, and "" if it has none.
+func localImageName(img *Image, name string) string {
+	if strings.HasPrefix(img.ID(), stripSha256(name)) {
+		if len(img.Names()) == 0 {
+			return ""
+		}
+		return img.Names()[0]
+	}
+	parts, err := decompose(name)
+	if err != nil {
+		return name
+	}
+	for _, imageName := range img.Names() {
+		imageParts, err := decompose(imageName)
+		if err != nil {
+			continue
+		}
+		if parts.hasRegistry && imageParts.registry != parts.registry {
+			continue
+		}
+		if imageParts.name == parts.name || imageParts.name == "library/"+parts.name {
+			return imageName
+		}
+	}
+	return name
+}
+
+// pinnedName returns the reference of named pinned to a manifest digest
+func pinnedName(named reference.Named, manifestDigest digest.Digest) (string, error) {
+	pinned, err := reference.WithDigest(reference.TrimNamed(named), manifestDigest)
+	if err != nil {
+		return "", errors.Wrapf(err, "error pinning %s", named.String())
+	}
+	return pinned.String(), nil
+}
+
+// LockfileForNames returns a lockfile pinning the references of images in
+// names, resolved with ResolveDigest. References already pinned by digest are
+// left out.
+func (ir *Runtime) LockfileForNames(ctx context.Context, names []string, sc *types.SystemContext) (*Lockfile, error) {
+	lockfile := NewLockfile()
+	for _, name := range names {
+		if _, ok := lockfile.Images[name]; ok || isPinned(name) {
+			continue
+		}
+		pinned, err := ir.ResolveDigest(ctx, name, sc)
+		if err != nil {
+			return nil, err
+		}
+		lockfile.Images[name] = pinned
+	}
+	return lockfile, nil
+}
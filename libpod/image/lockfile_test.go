@@ -0,0 +1,80 @@
+package image
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/storage"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pinnedNginx = "docker.io/library/nginx@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestLockfilePin(t *testing.T) {
+	lockfile := NewLockfile()
+	lockfile.Images["docker.io/library/nginx:alpine"] = pinnedNginx
+
+	for _, name := range []string{"docker.io/library/nginx:alpine", "nginx:alpine", "library/nginx:alpine", pinnedNginx} {
+		pinned, err := lockfile.Pin(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, pinnedNginx, pinned, name)
+	}
+	for _, name := range []string{"nginx", "nginx:latest", "quay.io/nginx:alpine", "fedora"} {
+		_, err := lockfile.Pin(name)
+		assert.Equal(t, ErrNotPinned, errors.Cause(err), name)
+	}
+}
+
+func TestLockfileReadWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lockfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "images.lock")
+
+	lockfile := NewLockfile()
+	lockfile.Images["nginx:alpine"] = pinnedNginx
+	require.NoError(t, lockfile.Write(path))
+	read, err := ReadLockfile(path)
+	require.NoError(t, err)
+	assert.Equal(t, lockfile, read)
+	assert.Equal(t, []string{"nginx:alpine"}, read.Names())
+
+	for _, content := range []string{
+		`{"version": 2, "images": {}}`,
+		`{"version": 1, "images": {"nginx:alpine": "nginx:alpine"}}`,
+		`{"version": 1, "images": [}`,
+	} {
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		_, err := ReadLockfile(path)
+		assert.Error(t, err, content)
+	}
+}
+
+func TestPinnedReference(t *testing.T) {
+	manifestDigest := digest.Digest("sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	img := &Image{image: &storage.Image{
+		ID:     "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		Names:  []string{"quay.io/app/nginx:alpine", "docker.io/library/nginx:alpine"},
+		Digest: manifestDigest,
+	}}
+
+	pinned, err := img.PinnedReference(localImageName(img, "nginx:alpine"))
+	require.NoError(t, err)
+	assert.Equal(t, pinnedNginx, pinned)
+	pinned, err = img.PinnedReference(localImageName(img, "quay.io/app/nginx"))
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/app/nginx@"+manifestDigest.String(), pinned)
+	// Images named by ID are pinned by their first name
+	pinned, err = img.PinnedReference(localImageName(img, "fedcba987654"))
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/app/nginx@"+manifestDigest.String(), pinned)
+
+	img.image.Digest = ""
+	_, err = img.PinnedReference("nginx:alpine")
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	cp "github.com/containers/image/copy"
@@ -14,12 +15,15 @@ import (
 	"github.com/containers/image/docker/tarfile"
 	ociarchive "github.com/containers/image/oci/archive"
 	"github.com/containers/image/pkg/sysregistries"
+	"github.com/containers/image/signature"
 	is "github.com/containers/image/storage"
 	"github.com/containers/image/transports"
 	"github.com/containers/image/transports/alltransports"
 	"github.com/containers/image/types"
+	"github.com/containers/libpod/libpod/shutdown"
 	"github.com/containers/libpod/pkg/registries"
 	"github.com/containers/libpod/pkg/util"
+	"github.com/containers/storage"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -198,7 +202,7 @@ func (ir *Runtime) pullImageFromHeuristicSource(ctx context.Context, inputName s
 	srcRef, err := alltransports.ParseImageName(inputName)
 	if err != nil {
 		// could be trying to pull from registry with short name
-		goal, err = ir.pullGoalFromPossiblyUnqualifiedName(inputName)
+		goal, err = ir.pullGoalFromPossiblyUnqualifiedName(inputName, dockerOptions.registriesConfPath())
 		if err != nil {
 			return nil, errors.Wrap(err, "error getting default registries to try")
 		}
@@ -229,11 +233,33 @@ func (ir *Runtime) doPullImage(ctx context.Context, sc *types.SystemContext, goa
 	}
 	defer policyContext.Destroy()
 
-	insecureRegistries, err := registries.GetInsecureRegistries()
+	// Abort the pull if the process is terminated, and wait for the copy
+	// to clean up its temporary files before exiting
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	handlerName := fmt.Sprintf("image-pull-%p", done)
+	if err := shutdown.Register(handlerName, func(os.Signal) error {
+		cancel()
+		<-done
+		return nil
+	}); err != nil {
+		cancel()
+		return nil, err
+	}
+	defer func() {
+		cancel()
+		close(done)
+		if err := shutdown.Unregister(handlerName); err != nil {
+			logrus.Debugf("Error unregistering shutdown handler: %v", err)
+		}
+	}()
+
+	insecureRegistries, err := registries.GetInsecureRegistriesFromFile(dockerOptions.registriesConfPath())
 	if err != nil {
 		return nil, err
 	}
 	var images []string
+	var policyErr error
 	for _, imageInfo := range goal.refPairs {
 		copyOptions := getCopyOptions(sc, writer, dockerOptions, nil, signingOptions, "", nil)
 		if imageInfo.srcRef.Transport().Name() == DockerTransport {
@@ -253,11 +279,48 @@ func (ir *Runtime) doPullImage(ctx context.Context, sc *types.SystemContext, goa
 		if writer != nil && (imageInfo.srcRef.Transport().Name() == DockerTransport || imageInfo.srcRef.Transport().Name() == AtomicTransport) {
 			io.WriteString(writer, fmt.Sprintf("Trying to pull %s...", imageInfo.image))
 		}
-		if err = cp.Image(ctx, policyContext, imageInfo.dstRef, imageInfo.srcRef, copyOptions); err != nil {
+		// Refuse pulls which would exceed the storage quota before
+		// downloading their layers
+		if err := ir.checkPullQuota(ctx, copyOptions.SourceCtx, imageInfo.srcRef, imageInfo.image); err != nil {
+			return nil, err
+		}
+		srcRef := imageInfo.srcRef
+		var resumable *resumableReference
+		var pullLock storage.Locker
+		if srcRef.Transport().Name() == DockerTransport {
+			if ir.BlobCacheDir != "" {
+				// The shared cache keeps the downloaded blobs,
+				// which also resumes interrupted pulls
+				if srcRef, err = newSharedCacheReference(srcRef, ir.BlobCacheDir, ir.PullCacheDir()); err != nil {
+					return nil, err
+				}
+			} else {
+				// Keep downloaded blobs for resuming an interrupted pull
+				resumable, err = newResumableReference(srcRef, ir.PullCacheDir())
+				if err != nil {
+					return nil, err
+				}
+				srcRef = resumable
+			}
+			if pullLock, err = lockPull(ctx, pullLockPath(ir.store.RunRoot(), imageInfo.srcRef)); err != nil {
+				return nil, err
+			}
+		}
+		err = cp.Image(ctx, policyContext, imageInfo.dstRef, srcRef, copyOptions)
+		if pullLock != nil {
+			pullLock.Unlock()
+		}
+		if err != nil {
 			if writer != nil {
 				io.WriteString(writer, "Failed\n")
 			}
+			if _, ok := errors.Cause(err).(signature.PolicyRequirementError); ok {
+				policyErr = err
+			}
 		} else {
+			if resumable != nil {
+				resumable.removeBlobs()
+			}
 			if !goal.pullAllPairs {
 				return []string{imageInfo.image}, nil
 			}
@@ -266,7 +329,12 @@ func (ir *Runtime) doPullImage(ctx context.Context, sc *types.SystemContext, goa
 	}
 	// If no image was found, we should handle.  Lets be nicer to the user and see if we can figure out why.
 	if len(images) == 0 {
-		registryPath := sysregistries.RegistriesConfPath(&types.SystemContext{})
+		// The trust policy rejecting an image is more useful to report
+		// than failing to find it
+		if policyErr != nil {
+			return nil, policyErr
+		}
+		registryPath := sysregistries.RegistriesConfPath(&types.SystemContext{SystemRegistriesConfPath: dockerOptions.registriesConfPath()})
 		if goal.usedSearchRegistries && len(goal.searchedRegistries) == 0 {
 			return nil, errors.Errorf("image name provided is a short name and no search registries are defined in %s.", registryPath)
 		}
@@ -287,8 +355,9 @@ func hasShaInInputName(inputName string) bool {
 }
 
 // pullGoalFromPossiblyUnqualifiedName looks at inputName and determines the possible
-// image references to try pulling in combination with the registries.conf file as well
-func (ir *Runtime) pullGoalFromPossiblyUnqualifiedName(inputName string) (*pullGoal, error) {
+// image references to try pulling in combination with the registries.conf file as well,
+// or with the registries file at registriesConfPath if it is not empty
+func (ir *Runtime) pullGoalFromPossiblyUnqualifiedName(inputName, registriesConfPath string) (*pullGoal, error) {
 	decomposedImage, err := decompose(inputName)
 	if err != nil {
 		return nil, err
@@ -321,7 +390,7 @@ func (ir *Runtime) pullGoalFromPossiblyUnqualifiedName(inputName string) (*pullG
 		return singlePullRefPairGoal(ps), nil
 	}
 
-	searchRegistries, err := registries.GetRegistries()
+	searchRegistries, err := registries.GetRegistriesFromFile(registriesConfPath)
 	if err != nil {
 		return nil, err
 	}
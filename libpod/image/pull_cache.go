@@ -0,0 +1,215 @@
+package image
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// pullCacheDir is the directory, relative to the graph root of the store, in
+// which blobs of pulls in progress are kept
+const pullCacheDir = "libpod/pull-cache"
+
+// PullCacheDir returns the directory in which the blobs of pulls in progress
+// are kept, until they complete
+func (ir *Runtime) PullCacheDir() string {
+	return filepath.Join(ir.store.GraphRoot(), pullCacheDir)
+}
+
+// partialSuffix is part of the name of blobs still being downloaded
+const partialSuffix = ".partial"
+
+// resumableReference wraps the reference of an image being pulled so that the
+// blobs read from it are kept in a cache directory until the pull completes.
+// If the pull is interrupted, the next pull of an image sharing those blobs
+// reads the completely downloaded ones from the cache instead of the network.
+type resumableReference struct {
+	types.ImageReference
+	cacheDir string
+
+	lock  sync.Mutex
+	blobs []string
+}
+
+func newResumableReference(ref types.ImageReference, cacheDir string) (*resumableReference, error) {
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "error creating pull cache directory %s", cacheDir)
+	}
+	return &resumableReference{ImageReference: ref, cacheDir: cacheDir}, nil
+}
+
+// NewImageSource returns a source reading blobs through the cache
+func (r *resumableReference) NewImageSource(ctx context.Context, sys *types.SystemContext) (types.ImageSource, error) {
+	src, err := r.ImageReference.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+	return &resumableSource{ImageSource: src, ref: r}, nil
+}
+
+// removeBlobs removes the cached blobs used by the pull, once they were
+// committed to storage and are no longer needed
+func (r *resumableReference) removeBlobs() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, path := range r.blobs {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logrus.Debugf("Error removing cached blob %s: %v", path, err)
+		}
+	}
+	r.blobs = nil
+}
+
+func (r *resumableReference) addBlob(path string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.blobs = append(r.blobs, path)
+}
+
+type resumableSource struct {
+	types.ImageSource
+	ref *resumableReference
+}
+
+// GetBlob returns the blob from the cache if a previous pull downloaded it
+// completely, and otherwise downloads it into the cache while returning it
+func (s *resumableSource) GetBlob(ctx context.Context, info types.BlobInfo) (io.ReadCloser, int64, error) {
+	if err := info.Digest.Validate(); err != nil {
+		return s.ImageSource.GetBlob(ctx, info)
+	}
+	path := filepath.Join(s.ref.cacheDir, info.Digest.Algorithm().String()+"-"+info.Digest.Hex())
+	s.ref.addBlob(path)
+
+	cached, size, err := openCachedBlob(path, info.Digest)
+	if err != nil {
+		return nil, 0, err
+	}
+	if cached != nil {
+		logrus.Debugf("Reusing blob %s from interrupted pull", info.Digest)
+		return cached, size, nil
+	}
+
+	rc, size, err := s.ImageSource.GetBlob(ctx, info)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Concurrent pulls of the same blob each download their own copy
+	partial, err := ioutil.TempFile(s.ref.cacheDir, filepath.Base(path)+partialSuffix)
+	if err != nil {
+		// The cache is an optimization, pull without it
+		logrus.Debugf("Error caching blob %s: %v", info.Digest, err)
+		return rc, size, nil
+	}
+	return &cachingReader{
+		source:   rc,
+		partial:  partial,
+		path:     path,
+		digester: info.Digest.Algorithm().Digester(),
+		expected: info.Digest,
+	}, size, nil
+}
+
+// openCachedBlob opens the cached blob at path after verifying its digest.
+// It returns nil if there is no valid cached blob.
+func openCachedBlob(path string, expected digest.Digest) (io.ReadCloser, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, errors.Wrapf(err, "error opening cached blob %s", path)
+	}
+	verifier := expected.Verifier()
+	size, err := io.Copy(verifier, f)
+	if err != nil {
+		f.Close()
+		return nil, 0, errors.Wrapf(err, "error reading cached blob %s", path)
+	}
+	if !verifier.Verified() {
+		f.Close()
+		logrus.Debugf("Cached blob %s does not match its digest, discarding it", path)
+		if err := os.Remove(path); err != nil {
+			return nil, 0, errors.Wrapf(err, "error removing corrupted cached blob %s", path)
+		}
+		return nil, 0, nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, 0, errors.Wrapf(err, "error reading cached blob %s", path)
+	}
+	return f, size, nil
+}
+
+// cachingReader copies a blob into the cache while it is read. Once the blob
+// was read completely and matches its digest, it is moved into place for
+// later pulls to reuse, with mode as its permissions if set.
+type cachingReader struct {
+	source   io.ReadCloser
+	partial  *os.File
+	path     string
+	mode     os.FileMode
+	digester digest.Digester
+	expected digest.Digest
+	writeErr error
+	done     bool
+}
+
+func (r *cachingReader) Read(p []byte) (int, error) {
+	n, err := r.source.Read(p)
+	if n > 0 && !r.done {
+		if r.writeErr == nil {
+			_, r.writeErr = r.partial.Write(p[:n])
+		}
+		r.digester.Hash().Write(p[:n])
+	}
+	if err == io.EOF && !r.done {
+		r.commit()
+	}
+	return n, err
+}
+
+// commit moves the downloaded blob into place if it is complete and valid
+func (r *cachingReader) commit() {
+	r.done = true
+	if err := r.partial.Close(); err != nil && r.writeErr == nil {
+		r.writeErr = err
+	}
+	if r.writeErr != nil {
+		logrus.Debugf("Error caching blob %s: %v", r.expected, r.writeErr)
+		return
+	}
+	if r.digester.Digest() != r.expected {
+		logrus.Debugf("Downloaded blob does not match digest %s, not caching it", r.expected)
+		return
+	}
+	if r.mode != 0 {
+		if err := os.Chmod(r.partial.Name(), r.mode); err != nil {
+			logrus.Debugf("Error caching blob %s: %v", r.expected, err)
+			return
+		}
+	}
+	if err := os.Rename(r.partial.Name(), r.path); err != nil {
+		logrus.Debugf("Error caching blob %s: %v", r.expected, err)
+	}
+}
+
+// Close closes the source, discarding incompletely downloaded blobs
+func (r *cachingReader) Close() error {
+	if !r.done {
+		r.done = true
+		r.partial.Close()
+	}
+	if err := os.Remove(r.partial.Name()); err != nil && !os.IsNotExist(err) {
+		logrus.Debugf("Error removing partial blob %s: %v", r.partial.Name(), err)
+	}
+	return r.source.Close()
+}
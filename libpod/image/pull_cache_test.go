@@ -0,0 +1,89 @@
+package image
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachingReaderCommitsCompleteBlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pullcache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	blob := []byte("layer contents")
+	blobDigest := digest.FromBytes(blob)
+	path := filepath.Join(dir, "blob")
+	partial, err := ioutil.TempFile(dir, "blob"+partialSuffix)
+	assert.NoError(t, err)
+
+	r := &cachingReader{
+		source:   ioutil.NopCloser(bytes.NewReader(blob)),
+		partial:  partial,
+		path:     path,
+		digester: blobDigest.Algorithm().Digester(),
+		expected: blobDigest,
+	}
+	read, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, blob, read)
+	assert.NoError(t, r.Close())
+
+	cached, size, err := openCachedBlob(path, blobDigest)
+	assert.NoError(t, err)
+	assert.NotNil(t, cached)
+	assert.Equal(t, int64(len(blob)), size)
+	cached.Close()
+
+	// Nothing but the committed blob is left behind
+	entries, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestCachingReaderDiscardsInterruptedBlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pullcache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	blob := []byte("layer contents")
+	blobDigest := digest.FromBytes(blob)
+	path := filepath.Join(dir, "blob")
+	partial, err := ioutil.TempFile(dir, "blob"+partialSuffix)
+	assert.NoError(t, err)
+
+	r := &cachingReader{
+		source:   ioutil.NopCloser(bytes.NewReader(blob)),
+		partial:  partial,
+		path:     path,
+		digester: blobDigest.Algorithm().Digester(),
+		expected: blobDigest,
+	}
+	_, err = r.Read(make([]byte, 4))
+	assert.NoError(t, err)
+	assert.NoError(t, r.Close())
+
+	entries, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 0)
+}
+
+func TestOpenCachedBlobDiscardsCorruptBlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pullcache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "blob")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("corrupted"), 0600))
+
+	cached, _, err := openCachedBlob(path, digest.FromBytes([]byte("layer contents")))
+	assert.NoError(t, err)
+	assert.Nil(t, cached)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,63 @@
+package image
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/image/transports"
+	"github.com/containers/image/types"
+	"github.com/containers/storage"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// pullLockDir is the directory, relative to the run root of the store, holding
+// the locks serializing pulls of the same image
+const pullLockDir = "libpod/pull-locks"
+
+// pullLockPath returns the path of the lock for pulls of the given reference.
+// References by digest are keyed on the digest, so that pulls of the same
+// image from different repositories are serialized as well; other references
+// are keyed on the digest of their name.
+func pullLockPath(runRoot string, srcRef types.ImageReference) string {
+	key := digest.FromString(transports.ImageName(srcRef))
+	if canonical, ok := srcRef.DockerReference().(reference.Canonical); ok {
+		key = canonical.Digest()
+	}
+	return filepath.Join(runRoot, pullLockDir, key.Hex()+".lock")
+}
+
+// lockPull takes the pull lock at path, waiting until no other process is
+// pulling the same image, so that concurrent pulls of an image do not download
+// its layers twice: once the first pull completes, the layers are in storage
+// and the following ones reuse them. The returned lock must be unlocked once
+// the pull is done.
+func lockPull(ctx context.Context, path string) (storage.Locker, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, errors.Wrapf(err, "error creating pull lock directory %s", filepath.Dir(path))
+	}
+	lock, err := storage.GetLockfile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving pull lock %s", path)
+	}
+
+	// Waiting for the lock can not be interrupted, so wait in the background
+	// to be able to give up when the pull is cancelled
+	locked := make(chan struct{})
+	go func() {
+		lock.Lock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		return lock, nil
+	case <-ctx.Done():
+		go func() {
+			<-locked
+			lock.Unlock()
+		}()
+		return nil, errors.Wrapf(ctx.Err(), "error waiting for pull lock %s", path)
+	}
+}
@@ -0,0 +1,50 @@
+package image
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containers/image/transports/alltransports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullLockPath(t *testing.T) {
+	tagged, err := alltransports.ParseImageName("docker://docker.io/library/busybox:latest")
+	assert.NoError(t, err)
+	other, err := alltransports.ParseImageName("docker://docker.io/library/alpine:latest")
+	assert.NoError(t, err)
+	assert.NotEqual(t, pullLockPath("/run", tagged), pullLockPath("/run", other))
+
+	const hex = "7cf14f8f2ae4a12a8ca5e2d1b2e8e1d6f2a5d1d2e5c3b0b2a8f1d9e8c7b6a5f4"
+	byDigest, err := alltransports.ParseImageName("docker://docker.io/library/busybox@sha256:" + hex)
+	assert.NoError(t, err)
+	mirrored, err := alltransports.ParseImageName("docker://quay.io/mirror/busybox@sha256:" + hex)
+	assert.NoError(t, err)
+	assert.Equal(t, "/run/"+pullLockDir+"/"+hex+".lock", pullLockPath("/run", byDigest))
+	assert.Equal(t, pullLockPath("/run", byDigest), pullLockPath("/run", mirrored))
+}
+
+func TestLockPullWaitsForOtherPull(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulllock")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, pullLockDir, "image.lock")
+
+	first, err := lockPull(context.Background(), path)
+	assert.NoError(t, err)
+
+	// A pull waiting for the lock gives up when cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = lockPull(ctx, path)
+	assert.Error(t, err)
+
+	first.Unlock()
+	second, err := lockPull(context.Background(), path)
+	assert.NoError(t, err)
+	second.Unlock()
+}
@@ -372,7 +372,7 @@ func TestPullGoalFromPossiblyUnqualifiedName(t *testing.T) {
 		// Unqualified, name:tag@digest. This code is happy to try, but .srcRef parsing currently rejects such input.
 		{"busybox:notlatest" + digestSuffix, nil, false},
 	} {
-		res, err := ir.pullGoalFromPossiblyUnqualifiedName(c.input)
+		res, err := ir.pullGoalFromPossiblyUnqualifiedName(c.input, "")
 		if len(c.expected) == 0 {
 			assert.Error(t, err, c.input)
 		} else {
@@ -392,4 +392,16 @@ func TestPullGoalFromPossiblyUnqualifiedName(t *testing.T) {
 			}
 		}
 	}
+	// A registries file given explicitly takes precedence over the global one
+	otherConf, err := ioutil.TempFile("", "TestPullGoalFromPossiblyUnqualifiedName")
+	require.NoError(t, err)
+	defer otherConf.Close()
+	defer os.Remove(otherConf.Name())
+	err = ioutil.WriteFile(otherConf.Name(), []byte("[registries.search]\nregistries = ['quay.io']\n"), 0600)
+	require.NoError(t, err)
+	res, err := ir.pullGoalFromPossiblyUnqualifiedName("busybox", otherConf.Name())
+	require.NoError(t, err)
+	require.Len(t, res.refPairs, 1)
+	assert.Equal(t, "docker://quay.io/busybox:latest", transports.ImageName(res.refPairs[0].srcRef))
+	assert.Equal(t, []string{"quay.io"}, res.searchedRegistries)
 }
@@ -0,0 +1,158 @@
+package image
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/containers/image/types"
+	"github.com/containers/storage"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrStorageQuota indicates that the usage of the storage reached its quota
+var ErrStorageQuota = errors.New("storage quota exceeded")
+
+// containerLayerSizeTTL is how long the size computed for the read-write
+// layer of a container is reused by StorageUsage, as it changes while the
+// container runs
+const containerLayerSizeTTL = 30 * time.Second
+
+// layerSizeCache keeps the sizes StorageUsage computes for the layers without
+// a recorded size, as computing them walks the content of the layers
+type layerSizeCache struct {
+	lock  sync.Mutex
+	sizes map[string]cachedLayerSize
+}
+
+// cachedLayerSize is the size of a layer computed at a time
+type cachedLayerSize struct {
+	size     int64
+	computed time.Time
+}
+
+func newLayerSizeCache() *layerSizeCache {
+	return &layerSizeCache{sizes: make(map[string]cachedLayerSize)}
+}
+
+// StorageUsage returns the total size of the layers of the store: the layers
+// of images and the read-write layers of containers. Layers shared by several
+// images are counted once. The sizes of the layers of containers are computed
+// again once they are older than containerLayerSizeTTL.
+func (ir *Runtime) StorageUsage() (int64, error) {
+	layers, err := ir.store.Layers()
+	if err != nil {
+		return 0, errors.Wrapf(err, "error listing layers")
+	}
+	containers, err := ir.store.Containers()
+	if err != nil {
+		return 0, errors.Wrapf(err, "error listing containers")
+	}
+	containerLayers := make(map[string]bool, len(containers))
+	for _, ctr := range containers {
+		containerLayers[ctr.LayerID] = true
+	}
+
+	cache := ir.layerSizes
+	if cache == nil {
+		cache = newLayerSizeCache()
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	sizes := make(map[string]cachedLayerSize, len(cache.sizes))
+	now := time.Now()
+
+	var usage int64
+	for _, layer := range layers {
+		size := layer.UncompressedSize
+		if size <= 0 {
+			// Container layers, and layers of images committed
+			// locally, have no recorded size
+			cached, ok := cache.sizes[layer.ID]
+			if !ok || (containerLayers[layer.ID] && now.Sub(cached.computed) >= containerLayerSizeTTL) {
+				if cached.size, err = ir.store.DiffSize("", layer.ID); err != nil {
+					if errors.Cause(err) == storage.ErrLayerUnknown {
+						// The layer was removed meanwhile
+						continue
+					}
+					return 0, errors.Wrapf(err, "error computing the size of layer %s", layer.ID)
+				}
+				cached.computed = now
+			}
+			sizes[layer.ID] = cached
+			size = cached.size
+		}
+		usage += size
+	}
+	// Layers removed since are dropped from the cache
+	cache.sizes = sizes
+	return usage, nil
+}
+
+// CheckStorageQuota returns an ErrStorageQuota error, with the usage of the
+// storage, if it reached the StorageQuota of the runtime
+func (ir *Runtime) CheckStorageQuota() error {
+	return ir.checkStorageQuota(0, "")
+}
+
+// checkStorageQuota returns an ErrStorageQuota error if the usage of the
+// storage, with needed more bytes for the image name, exceeds the
+// StorageQuota of the runtime
+func (ir *Runtime) checkStorageQuota(needed int64, name string) error {
+	if ir.StorageQuota <= 0 {
+		return nil
+	}
+	usage, err := ir.StorageUsage()
+	if err != nil {
+		return err
+	}
+	if usage >= ir.StorageQuota {
+		return errors.Wrapf(ErrStorageQuota, "storage usage of %s reached the quota of %s, remove unused images and containers to free space", units.BytesSize(float64(usage)), units.BytesSize(float64(ir.StorageQuota)))
+	}
+	if usage+needed > ir.StorageQuota {
+		return errors.Wrapf(ErrStorageQuota, "pulling %s needs at least %s more than the storage usage of %s, over the quota of %s, remove unused images and containers to free space", name, units.BytesSize(float64(needed)), units.BytesSize(float64(usage)), units.BytesSize(float64(ir.StorageQuota)))
+	}
+	return nil
+}
+
+// checkPullQuota returns an ErrStorageQuota error if pulling the image of
+// srcRef would exceed the StorageQuota of the runtime. The layers of the
+// image missing from the store are counted with their compressed size, the
+// least they take once pulled.
+func (ir *Runtime) checkPullQuota(ctx context.Context, sc *types.SystemContext, srcRef types.ImageReference, name string) error {
+	if ir.StorageQuota <= 0 {
+		return nil
+	}
+	needed, err := ir.missingLayersSize(ctx, sc, srcRef)
+	if err != nil {
+		// The pull reports why the image cannot be read
+		logrus.Debugf("Unable to compute the size of the layers of %s: %v", name, err)
+		needed = 0
+	}
+	return ir.checkStorageQuota(needed, name)
+}
+
+// missingLayersSize returns the total compressed size of the layers of the
+// image of ref which are not in the store
+func (ir *Runtime) missingLayersSize(ctx context.Context, sc *types.SystemContext, ref types.ImageReference) (int64, error) {
+	img, err := ref.NewImage(ctx, sc)
+	if err != nil {
+		return 0, err
+	}
+	defer img.Close()
+	var needed int64
+	for _, info := range img.LayerInfos() {
+		if info.Size <= 0 || info.Digest == "" {
+			continue
+		}
+		if _, err := ir.store.LayersByCompressedDigest(info.Digest); err == nil {
+			continue
+		} else if errors.Cause(err) != storage.ErrLayerUnknown {
+			return 0, err
+		}
+		needed += info.Size
+	}
+	return needed, nil
+}
@@ -0,0 +1,70 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStorageQuota(t *testing.T) {
+	ir, cleanup := newTestRuntime(t)
+	defer cleanup()
+
+	usage, err := ir.StorageUsage()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), usage)
+
+	var diff bytes.Buffer
+	tw := tar.NewWriter(&diff)
+	content := bytes.Repeat([]byte("a"), 4096)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "file", Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err = tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	size := int64(diff.Len())
+	_, _, err = ir.store.PutLayer("", "", nil, "", false, nil, &diff)
+	require.NoError(t, err)
+
+	usage, err = ir.StorageUsage()
+	require.NoError(t, err)
+	assert.Equal(t, size, usage)
+
+	// No quota
+	assert.NoError(t, ir.CheckStorageQuota())
+
+	ir.StorageQuota = size + 1
+	assert.NoError(t, ir.CheckStorageQuota())
+
+	ir.StorageQuota = size
+	err = ir.CheckStorageQuota()
+	assert.Equal(t, ErrStorageQuota, errors.Cause(err))
+
+	// Pulls needing more than what is left of the quota are refused
+	ir.StorageQuota = size + 10
+	assert.NoError(t, ir.checkStorageQuota(10, "image"))
+	err = ir.checkStorageQuota(11, "image")
+	assert.Equal(t, ErrStorageQuota, errors.Cause(err))
+}
+
+func TestStorageUsageCachesLayerSizes(t *testing.T) {
+	ir, cleanup := newTestRuntime(t)
+	defer cleanup()
+
+	// Layers created without a diff have no recorded size
+	layer, err := ir.store.CreateLayer("", "", nil, "", false, nil)
+	require.NoError(t, err)
+
+	_, err = ir.StorageUsage()
+	require.NoError(t, err)
+	assert.Contains(t, ir.layerSizes.sizes, layer.ID)
+
+	// Removed layers are dropped from the cache
+	require.NoError(t, ir.store.DeleteLayer(layer.ID))
+	_, err = ir.StorageUsage()
+	require.NoError(t, err)
+	assert.NotContains(t, ir.layerSizes.sizes, layer.ID)
+}
@@ -0,0 +1,145 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/docker"
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/image/manifest"
+	"github.com/containers/image/signature"
+	"github.com/containers/image/types"
+	"github.com/containers/libpod/pkg/trust"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// registriesDir returns the registries.d directory of the system context
+func registriesDir(sc *types.SystemContext) string {
+	if sc != nil && sc.RegistriesDirPath != "" {
+		return sc.RegistriesDirPath
+	}
+	return trust.DefaultRegistriesDir
+}
+
+// checkSigning returns an error if images pushed to dest cannot be signed,
+// before anything is pushed, as signatures are only created once the image
+// is pushed
+func checkSigning(sc *types.SystemContext, dest types.ImageReference) error {
+	mech, err := signature.NewGPGSigningMechanism()
+	if err != nil {
+		return errors.Wrapf(err, "error initializing GPG")
+	}
+	defer mech.Close()
+	if err := mech.SupportsSigning(); err != nil {
+		return errors.Wrapf(err, "images cannot be signed")
+	}
+	// Registries do not store signatures, they are written to the
+	// signature storage of their repository
+	if dest.Transport().Name() != DockerTransport || dest.DockerReference() == nil {
+		return nil
+	}
+	_, err = localSigstore(sc, dest.DockerReference())
+	return err
+}
+
+// localSigstore returns the directory the signatures of the images of a
+// repository are written to, configured in registries.d
+func localSigstore(sc *types.SystemContext, named reference.Named) (string, error) {
+	sigstore, err := trust.WriteSigstore(registriesDir(sc), named.Name())
+	if err != nil {
+		return "", err
+	}
+	if sigstore == "" {
+		return "", errors.Errorf("no signature storage is configured for %s in %s", named.Name(), registriesDir(sc))
+	}
+	u, err := url.Parse(sigstore)
+	if err != nil || u.Scheme != "file" {
+		return "", errors.Errorf("signature storage %s of %s is not a local directory", sigstore, named.Name())
+	}
+	return u.Path, nil
+}
+
+// SignImage signs the image of a docker reference, as stored in its registry,
+// with the GPG key signBy. The signature is written to the directory
+// sigstoreDir, or else to the signature storage configured for the repository
+// in registries.d, which must be local. It returns the path of the signature.
+func (ir *Runtime) SignImage(ctx context.Context, name, signBy, sigstoreDir string, sc *types.SystemContext) (string, error) {
+	named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(name, DockerTransport+"://"))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image reference %q", name)
+	}
+	named = reference.TagNameOnly(named)
+	ref, err := docker.NewReference(named)
+	if err != nil {
+		return "", err
+	}
+
+	if sigstoreDir == "" {
+		if sigstoreDir, err = localSigstore(sc, named); err != nil {
+			return "", err
+		}
+	}
+
+	mech, err := signature.NewGPGSigningMechanism()
+	if err != nil {
+		return "", errors.Wrapf(err, "error initializing GPG")
+	}
+	defer mech.Close()
+	if err := mech.SupportsSigning(); err != nil {
+		return "", errors.Wrapf(err, "images cannot be signed")
+	}
+
+	src, err := ref.NewImageSource(ctx, sc)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading %s", named.String())
+	}
+	defer src.Close()
+	manifestBytes, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading the manifest of %s", named.String())
+	}
+	manifestDigest, err := manifest.Digest(manifestBytes)
+	if err != nil {
+		return "", err
+	}
+	sig, err := signature.SignDockerManifest(manifestBytes, named.String(), mech, signBy)
+	if err != nil {
+		return "", errors.Wrapf(err, "error signing %s", named.String())
+	}
+	return putSignature(sigstoreDir, named, manifestDigest, sig)
+}
+
+// putSignature writes a signature of the manifest of a repository to a
+// local signature storage, in the layout containers/image reads: the
+// signatures of a manifest are signature-1, signature-2... of the
+// REPOSITORY@ALGORITHM=HEX directory. It returns the path of the signature.
+func putSignature(sigstoreDir string, named reference.Named, manifestDigest digest.Digest, sig []byte) (string, error) {
+	dir := filepath.Join(sigstoreDir, fmt.Sprintf("%s@%s=%s", reference.Path(named), manifestDigest.Algorithm(), manifestDigest.Hex()))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrapf(err, "error creating %s", dir)
+	}
+	for i := 1; ; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("signature-%d", i))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			if os.IsExist(err) {
+				continue
+			}
+			return "", errors.Wrapf(err, "error writing %s", path)
+		}
+		_, err = f.Write(sig)
+		if err2 := f.Close(); err == nil {
+			err = err2
+		}
+		if err != nil {
+			os.Remove(path)
+			return "", errors.Wrapf(err, "error writing %s", path)
+		}
+		return path, nil
+	}
+}
@@ -0,0 +1,61 @@
+package image
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutSignature(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sigstore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	named, err := reference.ParseNormalizedNamed("registry.example.com/team/app:1.0")
+	require.NoError(t, err)
+	manifestDigest := digest.FromString("manifest")
+
+	// Signatures are numbered from 1, after the existing ones
+	path, err := putSignature(dir, named, manifestDigest, []byte("first"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "team/app@sha256="+manifestDigest.Hex(), "signature-1"), path)
+	path, err = putSignature(dir, named, manifestDigest, []byte("second"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "team/app@sha256="+manifestDigest.Hex(), "signature-2"), path)
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+}
+
+func TestLocalSigstore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registries.d")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	sc := &types.SystemContext{RegistriesDirPath: dir}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "default.yaml"), []byte(`docker:
+  registry.example.com:
+    sigstore-staging: file:///var/lib/containers/sigstore
+  quay.io:
+    sigstore: https://sigstore.quay.io
+`), 0644))
+
+	named, err := reference.ParseNormalizedNamed("registry.example.com/app")
+	require.NoError(t, err)
+	path, err := localSigstore(sc, named)
+	require.NoError(t, err)
+	assert.Equal(t, "/var/lib/containers/sigstore", path)
+
+	// Signatures cannot be written to remote or missing storages
+	for _, name := range []string{"quay.io/app", "docker.io/library/alpine"} {
+		named, err := reference.ParseNormalizedNamed(name)
+		require.NoError(t, err)
+		_, err = localSigstore(sc, named)
+		assert.Error(t, err, name)
+	}
+}
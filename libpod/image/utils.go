@@ -7,6 +7,8 @@ import (
 	cp "github.com/containers/image/copy"
 	"github.com/containers/image/docker/reference"
 	"github.com/containers/image/signature"
+	"github.com/containers/image/transports"
+	"github.com/containers/image/transports/alltransports"
 	"github.com/containers/image/types"
 	"github.com/containers/storage"
 	"github.com/pkg/errors"
@@ -81,6 +83,42 @@ func hasTransport(image string) bool {
 	return strings.Contains(image, "://")
 }
 
+// ParseTransportReference parses an image name that starts with the name of a
+// known transport, such as dir:/path, oci:/path:tag, docker-archive:/path or
+// containers-storage:[driver@graphroot+runroot]name, as a reference of that
+// transport. It returns a nil reference if the name has no transport prefix.
+// Image names that merely look like one, such as docker:latest, are not
+// treated as references unless they contain "://".
+func ParseTransportReference(name string) (types.ImageReference, error) {
+	parts := strings.SplitN(name, ":", 2)
+	if len(parts) != 2 || transports.Get(parts[0]) == nil {
+		return nil, nil
+	}
+	ref, err := alltransports.ParseImageName(name)
+	if err != nil {
+		if hasTransport(name) {
+			return nil, errors.Wrapf(err, "error parsing image reference %q", name)
+		}
+		return nil, nil
+	}
+	return ref, nil
+}
+
+// ParseDestination parses the destination of a push or commit: an image name
+// with a transport prefix as accepted by ParseTransportReference, or else an
+// image name of defaultTransport, such as DefaultTransport
+func ParseDestination(name, defaultTransport string) (types.ImageReference, error) {
+	ref, err := ParseTransportReference(name)
+	if err != nil || ref != nil {
+		return ref, err
+	}
+	ref, err = alltransports.ParseImageName(defaultTransport + name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing image name %q", name)
+	}
+	return ref, nil
+}
+
 // ReposToMap parses the specified repotags and returns a map with repositories
 // as keys and the corresponding arrays of tags as values.
 func ReposToMap(repotags []string) map[string][]string {
@@ -0,0 +1,45 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/containers/image/transports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDestination(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		expected string // "" if the input is expected to fail
+	}{
+		{"busybox", "docker://busybox:latest"},
+		{"localhost:5000/busybox:tag", "docker://localhost:5000/busybox:tag"},
+		{"docker://quay.io/busybox", "docker://quay.io/busybox:latest"},
+		{"docker:latest", "docker://docker:latest"}, // An image name, not a docker: reference
+		{"dir:/tmp/image", "dir:/tmp/image"},
+		{"oci:/tmp/layout:tag", "oci:/tmp/layout:tag"},
+		{"docker-archive:/tmp/image.tar", "docker-archive:/tmp/image.tar"},
+		{"docker://Invalid", ""},
+	} {
+		ref, err := ParseDestination(c.input, DefaultTransport)
+		if c.expected == "" {
+			assert.Error(t, err, c.input)
+			continue
+		}
+		require.NoError(t, err, c.input)
+		assert.Equal(t, c.expected, transports.ImageName(ref), c.input)
+	}
+}
+
+func TestParseTransportReference(t *testing.T) {
+	for _, name := range []string{"busybox", "busybox:latest", "example.com/busybox:latest", "docker:latest"} {
+		ref, err := ParseTransportReference(name)
+		assert.NoError(t, err, name)
+		assert.Nil(t, ref, name)
+	}
+
+	ref, err := ParseTransportReference("oci-archive:/tmp/image.tar:tag")
+	require.NoError(t, err)
+	assert.Equal(t, "oci-archive", ref.Transport().Name())
+}
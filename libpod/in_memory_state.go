@@ -31,6 +31,8 @@ type InMemoryState struct {
 	// Maps namespace name to local ID and name registries for looking up
 	// pods and containers in a specific namespace.
 	namespaceIndexes map[string]*namespaceIndex
+	// Maps reserved name to its reservation.
+	reservations map[string]*NameReservation
 }
 
 // namespaceIndex contains name and ID registries for a specific namespace.
@@ -58,6 +60,8 @@ func NewInMemoryState() (State, error) {
 
 	state.namespaceIndexes = make(map[string]*namespaceIndex)
 
+	state.reservations = make(map[string]*NameReservation)
+
 	return state, nil
 }
 
@@ -197,6 +201,10 @@ func (s *InMemoryState) AddContainer(ctr *Container) error {
 		}
 	}
 
+	if err := s.checkNameReservation(ctr.Name(), ctr.nameReservation); err != nil {
+		return err
+	}
+
 	if err := s.nameIndex.Reserve(ctr.Name(), ctr.ID()); err != nil {
 		return errors.Wrapf(err, "error registering container name %s", ctr.Name())
 	}
@@ -207,6 +215,7 @@ func (s *InMemoryState) AddContainer(ctr *Container) error {
 	}
 
 	s.containers[ctr.ID()] = ctr
+	delete(s.reservations, ctr.Name())
 
 	// If we're in a namespace, add us to that namespace's indexes
 	if ctr.config.Namespace != "" {
@@ -322,6 +331,29 @@ func (s *InMemoryState) SaveContainer(ctr *Container) error {
 	return s.checkNSMatch(ctr.ID(), ctr.Namespace())
 }
 
+// RewriteContainerConfig replaces the configuration of a container
+func (s *InMemoryState) RewriteContainerConfig(ctr *Container, newCfg *ContainerConfig) error {
+	// If the container is invalid, return error
+	if !ctr.valid {
+		return errors.Wrapf(ErrCtrRemoved, "container with ID %s is not valid", ctr.ID())
+	}
+
+	// If the container does not exist, return error
+	stateCtr, ok := s.containers[ctr.ID()]
+	if !ok {
+		ctr.valid = false
+		return errors.Wrapf(ErrNoSuchCtr, "container with ID %s not found in state", ctr.ID())
+	}
+
+	if err := s.checkNSMatch(ctr.ID(), ctr.Namespace()); err != nil {
+		return err
+	}
+
+	stateCtr.config = newCfg
+	ctr.config = newCfg
+	return nil
+}
+
 // ContainerInUse checks if the given container is being used by other containers
 func (s *InMemoryState) ContainerInUse(ctr *Container) ([]string, error) {
 	if !ctr.valid {
@@ -358,6 +390,17 @@ func (s *InMemoryState) AllContainers() ([]*Container, error) {
 	return ctrs, nil
 }
 
+// AllContainersInAllNamespaces retrieves all containers from the state,
+// ignoring the set namespace
+func (s *InMemoryState) AllContainersInAllNamespaces() ([]*Container, error) {
+	ctrs := make([]*Container, 0, len(s.containers))
+	for _, ctr := range s.containers {
+		ctrs = append(ctrs, ctr)
+	}
+
+	return ctrs, nil
+}
+
 // Pod retrieves a pod from the state from its full ID
 func (s *InMemoryState) Pod(id string) (*Pod, error) {
 	if id == "" {
@@ -541,6 +584,10 @@ func (s *InMemoryState) AddPod(pod *Pod) error {
 		return errors.Wrapf(ErrPodExists, "pod with ID %s already exists in state", pod.ID())
 	}
 
+	if err := s.checkNameReservation(pod.Name(), pod.nameReservation); err != nil {
+		return err
+	}
+
 	if err := s.nameIndex.Reserve(pod.Name(), pod.ID()); err != nil {
 		return errors.Wrapf(err, "error registering pod name %s", pod.Name())
 	}
@@ -551,6 +598,7 @@ func (s *InMemoryState) AddPod(pod *Pod) error {
 	}
 
 	s.pods[pod.ID()] = pod
+	delete(s.reservations, pod.Name())
 
 	s.podContainers[pod.ID()] = make(map[string]*Container)
 
@@ -725,6 +773,10 @@ func (s *InMemoryState) AddContainerToPod(pod *Pod, ctr *Container) error {
 		return errors.Wrapf(ErrCtrExists, "container with ID %s already exists in state", ctr.ID())
 	}
 
+	if err := s.checkNameReservation(ctr.Name(), ctr.nameReservation); err != nil {
+		return err
+	}
+
 	if err := s.nameIndex.Reserve(ctr.Name(), ctr.ID()); err != nil {
 		return errors.Wrapf(err, "error reserving container name %s", ctr.Name())
 	}
@@ -735,6 +787,7 @@ func (s *InMemoryState) AddContainerToPod(pod *Pod, ctr *Container) error {
 	}
 
 	s.containers[ctr.ID()] = ctr
+	delete(s.reservations, ctr.Name())
 
 	// Add container to pod containers
 	podCtrs[ctr.ID()] = ctr
@@ -893,8 +946,95 @@ func (s *InMemoryState) AllPods() ([]*Pod, error) {
 	return pods, nil
 }
 
+// AllPodsInAllNamespaces retrieves all pods from the state, ignoring the set
+// namespace
+func (s *InMemoryState) AllPodsInAllNamespaces() ([]*Pod, error) {
+	pods := make([]*Pod, 0, len(s.pods))
+	for _, pod := range s.pods {
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
+
+// SetGraphDriverName does nothing, the in-memory state is not kept across
+// runtimes so it records no storage driver
+func (s *InMemoryState) SetGraphDriverName(name string) error {
+	return nil
+}
+
+// ReserveName reserves a name for a container or pod added later with the
+// token of the reservation
+func (s *InMemoryState) ReserveName(reservation *NameReservation) error {
+	if id, err := s.nameIndex.Get(reservation.Name); err == nil {
+		if _, ok := s.containers[id]; ok {
+			return errors.Wrapf(ErrCtrExists, "name %s is in use", reservation.Name)
+		}
+		return errors.Wrapf(ErrPodExists, "name %s is in use", reservation.Name)
+	}
+	if _, ok := s.reservations[reservation.Name]; ok {
+		return errors.Wrapf(ErrNameReserved, "name %s is already reserved", reservation.Name)
+	}
+
+	s.reservations[reservation.Name] = reservation
+
+	return nil
+}
+
+// ReleaseName releases the reservation of a name made with the given token,
+// or with any token if it is empty
+func (s *InMemoryState) ReleaseName(name, token string) error {
+	reservation, ok := s.reservations[name]
+	if !ok {
+		return errors.Wrapf(ErrNoSuchNameReservation, "name %s is not reserved", name)
+	}
+	if token != "" && token != reservation.Token {
+		return errors.Wrapf(ErrNameReserved, "name %s is reserved with another token", name)
+	}
+
+	delete(s.reservations, name)
+
+	return nil
+}
+
+// NameReservations retrieves the reservations of all names
+func (s *InMemoryState) NameReservations() ([]*NameReservation, error) {
+	reservations := make([]*NameReservation, 0, len(s.reservations))
+	for _, reservation := range s.reservations {
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, nil
+}
+
+// NameInUse checks if a container or pod of any namespace has the given
+// name, or if it is reserved
+func (s *InMemoryState) NameInUse(name string) (bool, error) {
+	if _, err := s.nameIndex.Get(name); err == nil {
+		return true, nil
+	}
+	_, ok := s.reservations[name]
+	return ok, nil
+}
+
 // Internal Functions
 
+// Check that a name being added to the state is not reserved, unless with the
+// given token
+func (s *InMemoryState) checkNameReservation(name, token string) error {
+	reservation, ok := s.reservations[name]
+	if !ok {
+		if token != "" {
+			return errors.Wrapf(ErrNoSuchNameReservation, "name %s is not reserved", name)
+		}
+		return nil
+	}
+	if token != reservation.Token {
+		return errors.Wrapf(ErrNameReserved, "name %s is reserved", name)
+	}
+	return nil
+}
+
 // Add a container to the dependency mappings
 func (s *InMemoryState) addCtrToDependsMap(ctrID, dependsID string) {
 	if dependsID != "" {
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/containers/libpod/pkg/nested"
 	"github.com/containers/libpod/utils"
 	"github.com/containers/storage/pkg/system"
 	"github.com/pkg/errors"
@@ -44,10 +45,22 @@ func (r *Runtime) hostInfo() (map[string]interface{}, error) {
 		"package": r.ociRuntime.conmonPackage(),
 		"version": conmonVersion,
 	}
+	ociruntimeFeatures, _ := r.ociRuntime.features(r.ociRuntime.path)
 	info["OCIRuntime"] = map[string]interface{}{
-		"path":    r.ociRuntime.path,
-		"package": r.ociRuntime.pathPackage(),
-		"version": ociruntimeVersion,
+		"path":     r.ociRuntime.path,
+		"package":  r.ociRuntime.pathPackage(),
+		"version":  ociruntimeVersion,
+		"features": ociruntimeFeatures,
+	}
+	if r.ociRuntime.wasmPath != "" {
+		info["WasmRuntime"] = map[string]interface{}{
+			"path": r.ociRuntime.wasmPath,
+		}
+	}
+	if r.ociRuntime.kataPath != "" {
+		info["KataRuntime"] = map[string]interface{}{
+			"path": r.ociRuntime.kataPath,
+		}
 	}
 
 	kv, err := readKernelVersion()
@@ -97,6 +110,7 @@ func (r *Runtime) hostInfo() (map[string]interface{}, error) {
 		return nil, errors.Wrapf(err, "error getting hostname")
 	}
 	info["hostname"] = host
+	info["nestingLevel"] = nested.Level()
 
 	return info, nil
 }
@@ -0,0 +1,101 @@
+package libpod
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// AlwaysInitContainer is an init container run each time its pod is
+	// started
+	AlwaysInitContainer = "always"
+	// OnceInitContainer is an init container run the first time its pod is
+	// started, and removed once it succeeds
+	OnceInitContainer = "once"
+)
+
+// splitInitContainers splits containers of a pod into its init containers,
+// in the order they were created, and the others
+func splitInitContainers(ctrs []*Container) ([]*Container, []*Container) {
+	var initCtrs, otherCtrs []*Container
+	for _, ctr := range ctrs {
+		if ctr.config.InitContainerType != "" {
+			initCtrs = append(initCtrs, ctr)
+		} else {
+			otherCtrs = append(otherCtrs, ctr)
+		}
+	}
+	sort.SliceStable(initCtrs, func(i, j int) bool {
+		return initCtrs[i].config.CreatedTime.Before(initCtrs[j].config.CreatedTime)
+	})
+	return initCtrs, otherCtrs
+}
+
+// startInitContainers runs the init containers among the containers of the
+// pod to completion, one after the other, after starting the infra container
+// they share namespaces with. Once init containers are removed when they
+// succeed. They are not run if other containers of the pod are running
+// already. It returns the other containers of the pod.
+// The pod must be locked.
+func (p *Pod) startInitContainers(ctx context.Context, ctrs []*Container) ([]*Container, error) {
+	initCtrs, otherCtrs := splitInitContainers(ctrs)
+	if len(initCtrs) == 0 {
+		return otherCtrs, nil
+	}
+
+	var infra *Container
+	for _, ctr := range otherCtrs {
+		if ctr.ID() == p.state.InfraContainerID {
+			infra = ctr
+			continue
+		}
+		state, err := ctr.State()
+		if err != nil {
+			return nil, err
+		}
+		if state == ContainerStateRunning {
+			return otherCtrs, nil
+		}
+	}
+
+	if infra != nil {
+		if err := infra.startIfNotRunning(ctx); err != nil {
+			return nil, errors.Wrapf(err, "error starting infra container %s of pod %s", infra.ID(), p.ID())
+		}
+	}
+
+	for _, ctr := range initCtrs {
+		if err := ctr.startIfNotRunning(ctx); err != nil {
+			return nil, errors.Wrapf(err, "error starting init container %s", ctr.ID())
+		}
+		exitCode, err := ctr.Wait()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error waiting for init container %s", ctr.ID())
+		}
+		if exitCode != 0 {
+			return nil, errors.Wrapf(ErrCtrStateInvalid, "init container %s exited with code %d", ctr.ID(), exitCode)
+		}
+		if ctr.config.InitContainerType == OnceInitContainer {
+			if err := p.runtime.removeContainer(ctx, ctr, false, true); err != nil {
+				return nil, errors.Wrapf(err, "error removing init container %s", ctr.ID())
+			}
+		}
+	}
+	return otherCtrs, nil
+}
+
+// startIfNotRunning starts the container unless it is already running
+func (c *Container) startIfNotRunning(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.syncContainer(); err != nil {
+		return err
+	}
+	if c.state.State == ContainerStateRunning {
+		return nil
+	}
+	return c.initAndStart(ctx)
+}
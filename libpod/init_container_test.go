@@ -0,0 +1,41 @@
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitInitContainers(t *testing.T) {
+	lockPath, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(lockPath)
+
+	var ctrs []*Container
+	for i := 1; i <= 4; i++ {
+		ctr, err := getTestCtrN(strconv.Itoa(i), lockPath)
+		require.NoError(t, err)
+		ctrs = append(ctrs, ctr)
+	}
+	now := time.Now()
+	ctrs[1].config.InitContainerType = AlwaysInitContainer
+	ctrs[1].config.CreatedTime = now.Add(time.Second)
+	ctrs[3].config.InitContainerType = OnceInitContainer
+	ctrs[3].config.CreatedTime = now
+
+	initCtrs, otherCtrs := splitInitContainers(ctrs)
+	assert.Equal(t, []*Container{ctrs[3], ctrs[1]}, initCtrs)
+	assert.Equal(t, []*Container{ctrs[0], ctrs[2]}, otherCtrs)
+
+	ctr, err := getTestCtrN("5", lockPath)
+	require.NoError(t, err)
+	ctr.valid = false
+	assert.Error(t, WithInitContainerType("sometimes")(ctr))
+	require.NoError(t, WithInitContainerType(OnceInitContainer)(ctr))
+	assert.Equal(t, OnceInitContainer, ctr.InitContainerType())
+}
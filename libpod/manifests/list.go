@@ -0,0 +1,280 @@
+// Package manifests builds manifest lists, the OCI image indexes or Docker
+// manifest lists of the images of several platforms, and pushes them with
+// their images to registries to publish multi-architecture images.
+package manifests
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"strings"
+
+	"github.com/containers/image/docker"
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/image/image"
+	"github.com/containers/image/manifest"
+	"github.com/containers/image/transports"
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNoSuchList indicates the requested manifest list does not exist
+	ErrNoSuchList = errors.New("no such manifest list")
+	// ErrListExists indicates a manifest list with the name exists
+	ErrListExists = errors.New("manifest list already exists")
+	// ErrNoSuchInstance indicates the manifest list has no image with the
+	// requested digest
+	ErrNoSuchInstance = errors.New("no such image in manifest list")
+)
+
+// List is a manifest list being built
+type List struct {
+	// Name is the name of the list, and its default destination
+	Name string `json:"name"`
+	// Index is the list, as an OCI image index. It is pushed as a Docker
+	// manifest list if it only has Docker images.
+	Index imgspecv1.Index `json:"index"`
+	// References are the references the images of the list are pushed
+	// from, by the digests of their manifests
+	References map[digest.Digest]string `json:"references"`
+}
+
+// NewList returns an empty manifest list
+func NewList(name string) *List {
+	return &List{
+		Name:       name,
+		Index:      imgspecv1.Index{Versioned: imgspecs.Versioned{SchemaVersion: 2}},
+		References: make(map[digest.Digest]string),
+	}
+}
+
+// instance returns the descriptor of the image of the list with the digest
+func (l *List) instance(d digest.Digest) (*imgspecv1.Descriptor, error) {
+	for i := range l.Index.Manifests {
+		if l.Index.Manifests[i].Digest == d {
+			return &l.Index.Manifests[i], nil
+		}
+	}
+	return nil, errors.Wrapf(ErrNoSuchInstance, "%s in %s", d, l.Name)
+}
+
+// addInstance adds the image of a descriptor, pushed from the reference, to
+// the list, replacing the image with the same digest
+func (l *List) addInstance(desc imgspecv1.Descriptor, ref string) {
+	l.References[desc.Digest] = ref
+	if existing, err := l.instance(desc.Digest); err == nil {
+		*existing = desc
+		return
+	}
+	l.Index.Manifests = append(l.Index.Manifests, desc)
+}
+
+// Remove removes the image with the digest from the list
+func (l *List) Remove(d digest.Digest) error {
+	for i := range l.Index.Manifests {
+		if l.Index.Manifests[i].Digest == d {
+			l.Index.Manifests = append(l.Index.Manifests[:i], l.Index.Manifests[i+1:]...)
+			delete(l.References, d)
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrNoSuchInstance, "%s in %s", d, l.Name)
+}
+
+// Add adds the image of a reference to the list, and returns the digests of
+// the images added. The image of the platform of sys, or else of the host,
+// is added from a reference to a manifest list, or all its images if all
+// is set, which must be in a registry.
+func (l *List) Add(ctx context.Context, sys *types.SystemContext, ref types.ImageReference, all bool) ([]digest.Digest, error) {
+	src, err := ref.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading %s", transports.ImageName(ref))
+	}
+	defer src.Close()
+	manifestBytes, mimeType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading the manifest of %s", transports.ImageName(ref))
+	}
+
+	if mimeType != manifest.DockerV2ListMediaType && mimeType != imgspecv1.MediaTypeImageIndex {
+		manifestDigest, err := manifest.Digest(manifestBytes)
+		if err != nil {
+			return nil, err
+		}
+		img, err := image.FromUnparsedImage(ctx, sys, image.UnparsedInstance(src, nil))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading %s", transports.ImageName(ref))
+		}
+		info, err := img.Inspect(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading the configuration of %s", transports.ImageName(ref))
+		}
+		l.addInstance(imgspecv1.Descriptor{
+			MediaType: mimeType,
+			Digest:    manifestDigest,
+			Size:      int64(len(manifestBytes)),
+			Platform: &imgspecv1.Platform{
+				Architecture: info.Architecture,
+				OS:           info.Os,
+			},
+		}, instanceReference(ref, manifestDigest))
+		return []digest.Digest{manifestDigest}, nil
+	}
+
+	// Docker manifest lists and OCI image indexes have the same layout
+	var list imgspecv1.Index
+	if err := json.Unmarshal(manifestBytes, &list); err != nil {
+		return nil, errors.Wrapf(err, "error parsing the manifest list of %s", transports.ImageName(ref))
+	}
+	if ref.DockerReference() == nil {
+		return nil, errors.Errorf("the images of manifest lists can only be added from registries, not from %s", transports.ImageName(ref))
+	}
+	var added []digest.Digest
+	for _, desc := range list.Manifests {
+		if !all && !matchesPlatform(desc.Platform, sys) {
+			continue
+		}
+		l.addInstance(desc, instanceReference(ref, desc.Digest))
+		added = append(added, desc.Digest)
+		if !all {
+			break
+		}
+	}
+	if len(added) == 0 {
+		return nil, errors.Errorf("the manifest list of %s has no image for the platform %s/%s", transports.ImageName(ref), platformOS(sys), platformArch(sys))
+	}
+	return added, nil
+}
+
+// instanceReference returns the reference the image with the digest is pushed
+// from, pinned by digest for references to registries
+func instanceReference(ref types.ImageReference, d digest.Digest) string {
+	if named := ref.DockerReference(); named != nil {
+		if canonical, err := reference.WithDigest(reference.TrimNamed(named), d); err == nil {
+			return docker.Transport.Name() + "://" + canonical.String()
+		}
+	}
+	return transports.ImageName(ref)
+}
+
+// platformOS returns the operating system of the images chosen for sys
+func platformOS(sys *types.SystemContext) string {
+	if sys != nil && sys.OSChoice != "" {
+		return sys.OSChoice
+	}
+	return runtime.GOOS
+}
+
+// platformArch returns the architecture of the images chosen for sys
+func platformArch(sys *types.SystemContext) string {
+	if sys != nil && sys.ArchitectureChoice != "" {
+		return sys.ArchitectureChoice
+	}
+	return runtime.GOARCH
+}
+
+// matchesPlatform returns whether the platform of an image of a manifest list
+// is the one chosen for sys
+func matchesPlatform(platform *imgspecv1.Platform, sys *types.SystemContext) bool {
+	return platform != nil && platform.OS == platformOS(sys) && platform.Architecture == platformArch(sys)
+}
+
+// Annotation updates the platform and annotations of an image of a list.
+// Its empty fields are left unchanged.
+type Annotation struct {
+	Architecture string
+	OS           string
+	OSVersion    string
+	OSFeatures   []string
+	Variant      string
+	// Annotations are added to those of the image
+	Annotations map[string]string
+}
+
+// Annotate updates the platform and annotations of the image of the list
+// with the digest
+func (l *List) Annotate(d digest.Digest, annotation Annotation) error {
+	desc, err := l.instance(d)
+	if err != nil {
+		return err
+	}
+	if desc.Platform == nil {
+		desc.Platform = &imgspecv1.Platform{}
+	}
+	if annotation.Architecture != "" {
+		desc.Platform.Architecture = annotation.Architecture
+	}
+	if annotation.OS != "" {
+		desc.Platform.OS = annotation.OS
+	}
+	if annotation.OSVersion != "" {
+		desc.Platform.OSVersion = annotation.OSVersion
+	}
+	if len(annotation.OSFeatures) > 0 {
+		desc.Platform.OSFeatures = annotation.OSFeatures
+	}
+	if annotation.Variant != "" {
+		desc.Platform.Variant = annotation.Variant
+	}
+	for key, value := range annotation.Annotations {
+		if desc.Annotations == nil {
+			desc.Annotations = make(map[string]string)
+		}
+		desc.Annotations[key] = value
+	}
+	return nil
+}
+
+// dockerList is a Docker manifest list
+type dockerList struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	MediaType     string               `json:"mediaType"`
+	Manifests     []dockerListInstance `json:"manifests"`
+}
+
+// dockerListInstance is an image of a Docker manifest list
+type dockerListInstance struct {
+	MediaType string             `json:"mediaType"`
+	Size      int64              `json:"size"`
+	Digest    digest.Digest      `json:"digest"`
+	Platform  imgspecv1.Platform `json:"platform"`
+}
+
+// Serialize returns the list in the format of the MIME type, an OCI image
+// index or a Docker manifest list. If mimeType is empty, OCI image indexes
+// are used for the lists with OCI images, else Docker manifest lists.
+func (l *List) Serialize(mimeType string) ([]byte, string, error) {
+	if mimeType == "" {
+		mimeType = manifest.DockerV2ListMediaType
+		for _, desc := range l.Index.Manifests {
+			if strings.HasPrefix(desc.MediaType, "application/vnd.oci.") {
+				mimeType = imgspecv1.MediaTypeImageIndex
+			}
+		}
+	}
+	switch mimeType {
+	case imgspecv1.MediaTypeImageIndex:
+		b, err := json.Marshal(l.Index)
+		return b, mimeType, err
+	case manifest.DockerV2ListMediaType:
+		list := dockerList{SchemaVersion: 2, MediaType: mimeType, Manifests: []dockerListInstance{}}
+		for _, desc := range l.Index.Manifests {
+			if desc.MediaType != manifest.DockerV2Schema2MediaType {
+				return nil, "", errors.Errorf("image %s of type %s cannot be in a Docker manifest list", desc.Digest, desc.MediaType)
+			}
+			instance := dockerListInstance{MediaType: desc.MediaType, Size: desc.Size, Digest: desc.Digest}
+			if desc.Platform != nil {
+				instance.Platform = *desc.Platform
+			}
+			list.Manifests = append(list.Manifests, instance)
+		}
+		b, err := json.Marshal(list)
+		return b, mimeType, err
+	default:
+		return nil, "", errors.Errorf("unsupported manifest list type %q", mimeType)
+	}
+}
@@ -0,0 +1,128 @@
+package manifests
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/image/manifest"
+	"github.com/containers/image/oci/layout"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLayoutBlob writes a blob to an OCI layout and returns its descriptor
+func writeLayoutBlob(t *testing.T, dir, mediaType string, data []byte) imgspecv1.Descriptor {
+	d := digest.FromBytes(data)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "blobs", "sha256", d.Hex()), data, 0644))
+	return imgspecv1.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(data))}
+}
+
+// writeLayout writes an OCI layout holding one image of the architecture per
+// tag, and returns the digests of their manifests
+func writeLayout(t *testing.T, dir string, arches map[string]string) map[string]digest.Digest {
+	index := imgspecv1.Index{Versioned: imgspecs.Versioned{SchemaVersion: 2}}
+	digests := make(map[string]digest.Digest)
+	for tag, arch := range arches {
+		config, err := json.Marshal(imgspecv1.Image{Architecture: arch, OS: "linux"})
+		require.NoError(t, err)
+		m := imgspecv1.Manifest{
+			Versioned: imgspecs.Versioned{SchemaVersion: 2},
+			Config:    writeLayoutBlob(t, dir, imgspecv1.MediaTypeImageConfig, config),
+			Layers:    []imgspecv1.Descriptor{writeLayoutBlob(t, dir, imgspecv1.MediaTypeImageLayerGzip, []byte(arch))},
+		}
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		desc := writeLayoutBlob(t, dir, imgspecv1.MediaTypeImageManifest, data)
+		desc.Annotations = map[string]string{imgspecv1.AnnotationRefName: tag}
+		index.Manifests = append(index.Manifests, desc)
+		digests[tag] = desc.Digest
+	}
+	data, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.json"), data, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, imgspecv1.ImageLayoutFile), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644))
+	return digests
+}
+
+func TestListAddAnnotate(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "manifests")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	digests := writeLayout(t, tmp, map[string]string{"amd64": "amd64", "arm64": "arm64"})
+
+	l := NewList("localhost/list")
+	for _, tag := range []string{"amd64", "arm64", "amd64"} {
+		ref, err := layout.NewReference(tmp, tag)
+		require.NoError(t, err)
+		added, err := l.Add(context.Background(), nil, ref, false)
+		require.NoError(t, err)
+		assert.Equal(t, []digest.Digest{digests[tag]}, added)
+	}
+	// Adding an image again replaces it
+	require.Len(t, l.Index.Manifests, 2)
+	desc := l.Index.Manifests[1]
+	assert.Equal(t, imgspecv1.MediaTypeImageManifest, desc.MediaType)
+	assert.Equal(t, &imgspecv1.Platform{Architecture: "arm64", OS: "linux"}, desc.Platform)
+	assert.Equal(t, "oci:"+tmp+":arm64", l.References[digests["arm64"]])
+
+	err = l.Annotate(digests["arm64"], Annotation{Variant: "v8", Annotations: map[string]string{"key": "value"}})
+	require.NoError(t, err)
+	desc = l.Index.Manifests[1]
+	assert.Equal(t, &imgspecv1.Platform{Architecture: "arm64", OS: "linux", Variant: "v8"}, desc.Platform)
+	assert.Equal(t, map[string]string{"key": "value"}, desc.Annotations)
+	assert.Error(t, l.Annotate(digest.FromString("missing"), Annotation{OS: "windows"}))
+
+	require.NoError(t, l.Remove(digests["amd64"]))
+	assert.Len(t, l.Index.Manifests, 1)
+	assert.NotContains(t, l.References, digests["amd64"])
+	assert.Error(t, l.Remove(digests["amd64"]))
+}
+
+func TestListSerialize(t *testing.T) {
+	l := NewList("localhost/list")
+	l.addInstance(imgspecv1.Descriptor{
+		MediaType: manifest.DockerV2Schema2MediaType,
+		Digest:    digest.FromString("amd64"),
+		Size:      10,
+		Platform:  &imgspecv1.Platform{Architecture: "amd64", OS: "linux"},
+	}, "docker://localhost/amd64")
+
+	b, mimeType, err := l.Serialize("")
+	require.NoError(t, err)
+	assert.Equal(t, manifest.DockerV2ListMediaType, mimeType)
+	assert.Equal(t, mimeType, manifest.GuessMIMEType(b))
+	var list dockerList
+	require.NoError(t, json.Unmarshal(b, &list))
+	require.Len(t, list.Manifests, 1)
+	assert.Equal(t, digest.FromString("amd64"), list.Manifests[0].Digest)
+	assert.Equal(t, "amd64", list.Manifests[0].Platform.Architecture)
+
+	b, mimeType, err = l.Serialize(imgspecv1.MediaTypeImageIndex)
+	require.NoError(t, err)
+	assert.Equal(t, imgspecv1.MediaTypeImageIndex, mimeType)
+	var index imgspecv1.Index
+	require.NoError(t, json.Unmarshal(b, &index))
+	assert.Equal(t, l.Index, index)
+
+	// OCI images cannot be in Docker manifest lists
+	l.addInstance(imgspecv1.Descriptor{
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Digest:    digest.FromString("arm64"),
+		Size:      10,
+	}, "docker://localhost/arm64")
+	_, mimeType, err = l.Serialize("")
+	require.NoError(t, err)
+	assert.Equal(t, imgspecv1.MediaTypeImageIndex, mimeType)
+	_, _, err = l.Serialize(manifest.DockerV2ListMediaType)
+	assert.Error(t, err)
+	_, _, err = l.Serialize("text/plain")
+	assert.Error(t, err)
+}
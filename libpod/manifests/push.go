@@ -0,0 +1,135 @@
+package manifests
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	cp "github.com/containers/image/copy"
+	"github.com/containers/image/directory"
+	"github.com/containers/image/docker"
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/image/manifest"
+	"github.com/containers/image/signature"
+	"github.com/containers/image/transports"
+	"github.com/containers/image/transports/alltransports"
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// Push pushes the images of the list to the repository of dest, by digest,
+// then the list itself, tagged dest, in the format of mimeType as for
+// Serialize. Images not pushed from registries are compressed first, which
+// updates their digests in the list. Progress is written to writer if it is
+// not nil. It returns the digest of the list pushed.
+func (l *List) Push(ctx context.Context, sys *types.SystemContext, policyContext *signature.PolicyContext, dest reference.Named, mimeType string, writer io.Writer) (digest.Digest, error) {
+	if len(l.Index.Manifests) == 0 {
+		return "", errors.Errorf("manifest list %s has no images", l.Name)
+	}
+	dest = reference.TagNameOnly(dest)
+	repo := reference.TrimNamed(dest)
+
+	tmpDir, err := ioutil.TempDir("", "manifest-push")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for i := range l.Index.Manifests {
+		desc := &l.Index.Manifests[i]
+		ref := l.References[desc.Digest]
+		src, err := alltransports.ParseImageName(ref)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid reference of image %s", desc.Digest)
+		}
+		options := &cp.Options{
+			ReportWriter:   writer,
+			SourceCtx:      sys,
+			DestinationCtx: sys,
+		}
+		if src.Transport().Name() != docker.Transport.Name() {
+			// Pushing compresses the layers of local images, changing
+			// their manifests, whose digests must be known to push
+			// them by digest
+			if src, err = stage(ctx, sys, policyContext, src, filepath.Join(tmpDir, desc.Digest.Hex()), writer); err != nil {
+				return "", err
+			}
+			manifestBytes, err := ioutil.ReadFile(filepath.Join(src.StringWithinTransport(), "manifest.json"))
+			if err != nil {
+				return "", errors.Wrapf(err, "error reading the manifest of image %s", desc.Digest)
+			}
+			delete(l.References, desc.Digest)
+			if desc.Digest, err = manifest.Digest(manifestBytes); err != nil {
+				return "", err
+			}
+			l.References[desc.Digest] = ref
+			desc.MediaType = manifest.GuessMIMEType(manifestBytes)
+			desc.Size = int64(len(manifestBytes))
+		}
+		options.ForceManifestMIMEType = desc.MediaType
+
+		canonical, err := reference.WithDigest(repo, desc.Digest)
+		if err != nil {
+			return "", err
+		}
+		destRef, err := docker.NewReference(canonical)
+		if err != nil {
+			return "", err
+		}
+		if err := cp.Image(ctx, policyContext, destRef, src, options); err != nil {
+			return "", errors.Wrapf(err, "error pushing image %s to %s", desc.Digest, canonical.String())
+		}
+	}
+
+	listBytes, _, err := l.Serialize(mimeType)
+	if err != nil {
+		return "", err
+	}
+	listDigest, err := manifest.Digest(listBytes)
+	if err != nil {
+		return "", err
+	}
+	destRef, err := docker.NewReference(dest)
+	if err != nil {
+		return "", err
+	}
+	imgDest, err := destRef.NewImageDestination(ctx, sys)
+	if err != nil {
+		return "", errors.Wrapf(err, "error pushing to %s", dest.String())
+	}
+	defer imgDest.Close()
+	if err := imgDest.PutManifest(ctx, listBytes); err != nil {
+		return "", errors.Wrapf(err, "error pushing manifest list %s to %s", l.Name, dest.String())
+	}
+	if err := imgDest.Commit(ctx); err != nil {
+		return "", errors.Wrapf(err, "error pushing manifest list %s to %s", l.Name, dest.String())
+	}
+	return listDigest, nil
+}
+
+// stage copies a local image to a directory, compressing its layers as they
+// are pushed, and returns the reference of the directory
+func stage(ctx context.Context, sys *types.SystemContext, policyContext *signature.PolicyContext, src types.ImageReference, dir string, writer io.Writer) (types.ImageReference, error) {
+	dirRef, err := directory.NewReference(dir)
+	if err != nil {
+		return nil, err
+	}
+	dirCtx := &types.SystemContext{DirForceCompress: true}
+	if sys != nil {
+		copied := *sys
+		copied.DirForceCompress = true
+		dirCtx = &copied
+	}
+	options := &cp.Options{
+		ReportWriter:   writer,
+		SourceCtx:      sys,
+		DestinationCtx: dirCtx,
+	}
+	if err := cp.Image(ctx, policyContext, dirRef, src, options); err != nil {
+		return nil, errors.Wrapf(err, "error copying %s", transports.ImageName(src))
+	}
+	return dirRef, nil
+}
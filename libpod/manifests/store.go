@@ -0,0 +1,158 @@
+package manifests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containers/image/docker/reference"
+	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/ioutils"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// Store keeps the manifest lists being built, one JSON file per list, named
+// after the digest of the name of the list
+type Store struct {
+	dir  string
+	lock storage.Locker
+}
+
+// NewStore returns the manifest list store in the given directory, creating
+// it if needed
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "error creating manifest list store %s", dir)
+	}
+	lock, err := storage.GetLockfile(filepath.Join(dir, "manifests.lock"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving lock of manifest list store %s", dir)
+	}
+	return &Store{dir: dir, lock: lock}, nil
+}
+
+// ListName returns the name of a manifest list: names are normalized as
+// registry references, so lists are found without the registry or tag
+func ListName(name string) string {
+	if named, err := reference.ParseNormalizedNamed(name); err == nil {
+		return reference.TagNameOnly(named).String()
+	}
+	return name
+}
+
+// listPath returns the path of the file of the list with the name
+func (s *Store) listPath(name string) string {
+	return filepath.Join(s.dir, digest.FromString(name).Hex()+".json")
+}
+
+// readList reads a list file
+func readList(path string) (*List, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	l := NewList("")
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, errors.Wrapf(err, "error parsing manifest list %s", path)
+	}
+	return l, nil
+}
+
+// Create creates an empty manifest list with the name
+func (s *Store) Create(name string) (*List, error) {
+	name = ListName(name)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err := os.Stat(s.listPath(name)); err == nil {
+		return nil, errors.Wrapf(ErrListExists, "%s", name)
+	}
+	l := NewList(name)
+	if err := s.save(l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Lookup returns the manifest list with the name
+func (s *Store) Lookup(name string) (*List, error) {
+	name = ListName(name)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	l, err := readList(s.listPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Wrapf(ErrNoSuchList, "%s", name)
+		}
+		return nil, errors.Wrapf(err, "error reading manifest list %s", name)
+	}
+	return l, nil
+}
+
+// List returns all manifest lists, sorted by name
+func (s *Store) List() ([]*List, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing manifest lists")
+	}
+	lists := []*List{}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		l, err := readList(filepath.Join(s.dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, l)
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].Name < lists[j].Name })
+	return lists, nil
+}
+
+// Save writes the changes of a list returned by Create or Lookup
+func (s *Store) Save(l *List) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err := os.Stat(s.listPath(l.Name)); os.IsNotExist(err) {
+		return errors.Wrapf(ErrNoSuchList, "%s", l.Name)
+	}
+	return s.save(l)
+}
+
+// save writes a list. The store must be locked.
+func (s *Store) save(l *List) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(ioutils.AtomicWriteFile(s.listPath(l.Name), data, 0644), "error writing manifest list %s", l.Name)
+}
+
+// Remove removes the manifest list with the name. The images of the list
+// are not removed.
+func (s *Store) Remove(name string) error {
+	name = ListName(name)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := os.Remove(s.listPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return errors.Wrapf(ErrNoSuchList, "%s", name)
+		}
+		return errors.Wrapf(err, "error removing manifest list %s", name)
+	}
+	return nil
+}
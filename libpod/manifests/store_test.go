@@ -0,0 +1,47 @@
+package manifests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "manifests")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	s, err := NewStore(tmp)
+	require.NoError(t, err)
+	l, err := s.Create("list")
+	require.NoError(t, err)
+	assert.Equal(t, "docker.io/library/list:latest", l.Name)
+	_, err = s.Create("docker.io/library/list")
+	assert.Equal(t, ErrListExists, errors.Cause(err))
+
+	l.addInstance(imgspecv1.Descriptor{MediaType: imgspecv1.MediaTypeImageManifest, Digest: digest.FromString("image")}, "oci:/image")
+	require.NoError(t, s.Save(l))
+	found, err := s.Lookup("list:latest")
+	require.NoError(t, err)
+	assert.Equal(t, l, found)
+
+	_, err = s.Create("quay.io/example/list:v1")
+	require.NoError(t, err)
+	lists, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, lists, 2)
+	assert.Equal(t, "docker.io/library/list:latest", lists[0].Name)
+	assert.Equal(t, "quay.io/example/list:v1", lists[1].Name)
+
+	require.NoError(t, s.Remove("list"))
+	_, err = s.Lookup("list")
+	assert.Equal(t, ErrNoSuchList, errors.Cause(err))
+	assert.Equal(t, ErrNoSuchList, errors.Cause(s.Remove("list")))
+	assert.Equal(t, ErrNoSuchList, errors.Cause(s.Save(l)))
+}
@@ -0,0 +1,301 @@
+// +build linux
+
+package libpod
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// networkBridgePrefix is the prefix of the bridges of the networks
+	// created by podman, followed by a number
+	networkBridgePrefix = "cni-podman"
+	// maxNetworkBridges is the number of bridges, and of subnets picked
+	// in networkSubnetBase, networks can be created with
+	maxNetworkBridges = 256
+)
+
+// networkNameRegex matches the names of the networks created by podman, which
+// are the names of their configuration files
+var networkNameRegex = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9_.-]*$")
+
+// networkSubnetBase is the range of the /24 subnets of the networks created
+// without one
+var networkSubnetBase = net.IPv4(10, 89, 0, 0)
+
+// NetworkConfig describes a CNI network created by CreateNetwork
+type NetworkConfig struct {
+	// Name is the name of the network
+	Name string
+	// Subnet is the subnet of the network. A free /24 subnet of
+	// 10.89.0.0/16 is used if it is nil.
+	Subnet *net.IPNet
+	// Gateway is the address of the bridge in the subnet, its first
+	// address if it is nil
+	Gateway net.IP
+	// Internal networks are not masqueraded, so their containers can only
+	// reach each other and the host
+	Internal bool
+}
+
+// cniBridgeConfig is the part of the configuration of the bridge plugin
+// which tells which bridge and subnets a network uses
+type cniBridgeConfig struct {
+	Bridge string `json:"bridge"`
+	IPAM   struct {
+		Subnet string `json:"subnet"`
+		Ranges [][]struct {
+			Subnet string `json:"subnet"`
+		} `json:"ranges"`
+	} `json:"ipam"`
+}
+
+// networkFile returns the path of the configuration file of a network created
+// by podman
+func (r *Runtime) networkFile(name string) string {
+	return filepath.Join(r.config().CNIConfigDir, name+".conflist")
+}
+
+// usedBridgesAndSubnets returns the bridges and the subnets of the networks
+func usedBridgesAndSubnets(networks map[string]*libcni.NetworkConfigList) (map[string]bool, []*net.IPNet) {
+	bridges := make(map[string]bool)
+	var subnets []*net.IPNet
+	addSubnet := func(subnet string) {
+		if _, ipNet, err := net.ParseCIDR(subnet); err == nil {
+			subnets = append(subnets, ipNet)
+		}
+	}
+	for _, list := range networks {
+		for _, plugin := range list.Plugins {
+			var config cniBridgeConfig
+			if err := json.Unmarshal(plugin.Bytes, &config); err != nil {
+				continue
+			}
+			if config.Bridge != "" {
+				bridges[config.Bridge] = true
+			}
+			addSubnet(config.IPAM.Subnet)
+			for _, set := range config.IPAM.Ranges {
+				for _, r := range set {
+					addSubnet(r.Subnet)
+				}
+			}
+		}
+	}
+	return bridges, subnets
+}
+
+// subnetsOverlap returns whether two subnets share addresses
+func subnetsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// networkConfList returns the configuration of a bridge network, with the
+// plugins of the default network of podman
+func networkConfList(config *NetworkConfig, bridge string) ([]byte, error) {
+	type ipam struct {
+		Type    string              `json:"type"`
+		Subnet  string              `json:"subnet"`
+		Gateway string              `json:"gateway"`
+		Routes  []map[string]string `json:"routes"`
+	}
+	type plugin struct {
+		Type         string          `json:"type"`
+		Bridge       string          `json:"bridge,omitempty"`
+		IsGateway    bool            `json:"isGateway,omitempty"`
+		IPMasq       bool            `json:"ipMasq,omitempty"`
+		IPAM         *ipam           `json:"ipam,omitempty"`
+		Capabilities map[string]bool `json:"capabilities,omitempty"`
+	}
+	list := struct {
+		CNIVersion string   `json:"cniVersion"`
+		Name       string   `json:"name"`
+		Plugins    []plugin `json:"plugins"`
+	}{
+		CNIVersion: "0.3.0",
+		Name:       config.Name,
+		Plugins: []plugin{
+			{
+				Type:      "bridge",
+				Bridge:    bridge,
+				IsGateway: true,
+				IPMasq:    !config.Internal,
+				IPAM: &ipam{
+					Type:    "host-local",
+					Subnet:  config.Subnet.String(),
+					Gateway: config.Gateway.String(),
+					Routes:  []map[string]string{{"dst": "0.0.0.0/0"}},
+				},
+			},
+			{
+				Type:         "portmap",
+				Capabilities: map[string]bool{"portMappings": true},
+			},
+		},
+	}
+	return json.MarshalIndent(list, "", "    ")
+}
+
+// CreateNetwork creates a CNI bridge network, writing its configuration to
+// the CNI configuration directory, and returns its configuration with its
+// subnet and gateway set
+func (r *Runtime) CreateNetwork(config NetworkConfig) (*NetworkConfig, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+	if err := r.checkReadOnly(); err != nil {
+		return nil, err
+	}
+	if !networkNameRegex.MatchString(config.Name) {
+		return nil, errors.Wrapf(ErrInvalidArg, "network name %q must match regex %s", config.Name, networkNameRegex)
+	}
+
+	networks, _, err := r.CNINetworks()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := networks[config.Name]; ok {
+		return nil, errors.Wrapf(ErrNetworkExists, "network %s", config.Name)
+	}
+	if _, err := os.Stat(r.networkFile(config.Name)); err == nil {
+		return nil, errors.Wrapf(ErrNetworkExists, "configuration file %s of network %s", r.networkFile(config.Name), config.Name)
+	}
+	bridges, subnets := usedBridgesAndSubnets(networks)
+
+	bridge := ""
+	for i := 0; i < maxNetworkBridges && bridge == ""; i++ {
+		name := fmt.Sprintf("%s%d", networkBridgePrefix, i)
+		if bridges[name] {
+			continue
+		}
+		if config.Subnet == nil {
+			ip := make(net.IP, net.IPv4len)
+			copy(ip, networkSubnetBase.To4())
+			ip[2] = byte(i)
+			subnet := &net.IPNet{IP: ip, Mask: net.CIDRMask(24, 32)}
+			overlaps := false
+			for _, used := range subnets {
+				overlaps = overlaps || subnetsOverlap(subnet, used)
+			}
+			if overlaps {
+				continue
+			}
+			config.Subnet = subnet
+		}
+		bridge = name
+	}
+	if bridge == "" || config.Subnet == nil {
+		return nil, errors.Errorf("no bridge or subnet of %s/16 left for network %s", networkSubnetBase, config.Name)
+	}
+	for _, used := range subnets {
+		if subnetsOverlap(config.Subnet, used) {
+			return nil, errors.Wrapf(ErrInvalidArg, "subnet %s of network %s overlaps subnet %s of another network", config.Subnet, config.Name, used)
+		}
+	}
+	if config.Gateway == nil {
+		gateway := make(net.IP, len(config.Subnet.IP))
+		copy(gateway, config.Subnet.IP)
+		gateway[len(gateway)-1]++
+		config.Gateway = gateway
+	} else if !config.Subnet.Contains(config.Gateway) {
+		return nil, errors.Wrapf(ErrInvalidArg, "gateway %s of network %s is not in subnet %s", config.Gateway, config.Name, config.Subnet)
+	}
+
+	data, err := networkConfList(&config, bridge)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error encoding configuration of network %s", config.Name)
+	}
+	if err := os.MkdirAll(r.config().CNIConfigDir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "error creating CNI configuration directory %s", r.config().CNIConfigDir)
+	}
+	// Write the configuration under a name CNI ignores, so that it is
+	// never loaded incomplete
+	tmp, err := ioutil.TempFile(r.config().CNIConfigDir, "."+config.Name+".tmp")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error writing configuration of network %s", config.Name)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, errors.Wrapf(err, "error writing configuration of network %s", config.Name)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return nil, errors.Wrapf(err, "error writing configuration of network %s", config.Name)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, errors.Wrapf(err, "error writing configuration of network %s", config.Name)
+	}
+	if err := os.Rename(tmp.Name(), r.networkFile(config.Name)); err != nil {
+		return nil, errors.Wrapf(err, "error writing configuration of network %s", config.Name)
+	}
+	logrus.Debugf("Created network %s with bridge %s and subnet %s", config.Name, bridge, config.Subnet)
+	return &config, nil
+}
+
+// RemoveNetwork removes a CNI network created by CreateNetwork, which no
+// container may use. The default network and the networks configured
+// otherwise are not removed.
+func (r *Runtime) RemoveNetwork(name string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return ErrRuntimeStopped
+	}
+	if err := r.checkReadOnly(); err != nil {
+		return err
+	}
+
+	networks, defaultNetwork, err := r.CNINetworks()
+	if err != nil {
+		return err
+	}
+	if _, ok := networks[name]; !ok {
+		return errors.Wrapf(ErrNoSuchNetwork, "%s", name)
+	}
+	if name == defaultNetwork {
+		return errors.Wrapf(ErrInvalidArg, "network %s is the default network and cannot be removed", name)
+	}
+	if !networkNameRegex.MatchString(name) {
+		return errors.Wrapf(ErrInvalidArg, "network %s was not created by podman, remove its configuration from %s", name, r.config().CNIConfigDir)
+	}
+	list, err := libcni.ConfListFromFile(r.networkFile(name))
+	if err != nil || list.Name != name {
+		return errors.Wrapf(ErrInvalidArg, "network %s was not created by podman, remove its configuration from %s", name, r.config().CNIConfigDir)
+	}
+
+	ctrs, err := r.state.AllContainersInAllNamespaces()
+	if err != nil {
+		return err
+	}
+	var users []string
+	for _, ctr := range ctrs {
+		for _, network := range ctr.config.Networks {
+			if network == name {
+				users = append(users, ctr.ID())
+			}
+		}
+	}
+	if len(users) > 0 {
+		return errors.Wrapf(ErrNetworkInUse, "network %s is used by containers %v", name, users)
+	}
+
+	if err := os.Remove(r.networkFile(name)); err != nil {
+		return errors.Wrapf(err, "error removing configuration of network %s", name)
+	}
+	return nil
+}
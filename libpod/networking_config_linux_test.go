@@ -0,0 +1,72 @@
+// +build linux
+
+package libpod
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndRemoveNetwork(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cni")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "87-podman-bridge.conflist"), []byte(testBridgeConfList), 0644))
+	state, err := NewInMemoryState()
+	require.NoError(t, err)
+	r := &Runtime{
+		valid: true,
+		state: state,
+	}
+	r.setConfig(&RuntimeConfig{CNIConfigDir: dir})
+
+	created, err := r.CreateNetwork(NetworkConfig{Name: "web"})
+	require.NoError(t, err)
+	assert.Equal(t, "10.89.0.0/24", created.Subnet.String())
+	assert.Equal(t, "10.89.0.1", created.Gateway.String())
+
+	// The next network gets the next bridge and subnet
+	_, subnet, err := net.ParseCIDR("10.89.1.0/24")
+	require.NoError(t, err)
+	_, err = r.CreateNetwork(NetworkConfig{Name: "overlapping", Subnet: subnet})
+	assert.NoError(t, err)
+	created, err = r.CreateNetwork(NetworkConfig{Name: "db", Internal: true})
+	require.NoError(t, err)
+	assert.Equal(t, "10.89.2.0/24", created.Subnet.String())
+
+	networks, _, err := r.CNINetworks()
+	require.NoError(t, err)
+	require.Contains(t, networks, "db")
+	bridges, _ := usedBridgesAndSubnets(networks)
+	assert.Equal(t, map[string]bool{"cni0": true, "cni-podman0": true, "cni-podman1": true, "cni-podman2": true}, bridges)
+
+	_, err = r.CreateNetwork(NetworkConfig{Name: "web"})
+	assert.Equal(t, ErrNetworkExists, errors.Cause(err))
+	_, err = r.CreateNetwork(NetworkConfig{Name: "other", Subnet: subnet})
+	assert.Equal(t, ErrInvalidArg, errors.Cause(err))
+	_, err = r.CreateNetwork(NetworkConfig{Name: "gateway", Subnet: &net.IPNet{IP: net.IPv4(10, 90, 0, 0).To4(), Mask: net.CIDRMask(24, 32)}, Gateway: net.IPv4(10, 91, 0, 1)})
+	assert.Equal(t, ErrInvalidArg, errors.Cause(err))
+	_, err = r.CreateNetwork(NetworkConfig{Name: "not/valid"})
+	assert.Equal(t, ErrInvalidArg, errors.Cause(err))
+
+	// Networks used by containers, and those podman did not create, are
+	// not removed
+	ctr, err := getTestCtr1(dir)
+	require.NoError(t, err)
+	ctr.config.Networks = []string{"web"}
+	require.NoError(t, state.AddContainer(ctr))
+	assert.Equal(t, ErrNetworkInUse, errors.Cause(r.RemoveNetwork("web")))
+	assert.Equal(t, ErrInvalidArg, errors.Cause(r.RemoveNetwork("podman")))
+	assert.Equal(t, ErrNoSuchNetwork, errors.Cause(r.RemoveNetwork("missing")))
+
+	assert.NoError(t, r.RemoveNetwork("db"))
+	_, err = os.Stat(filepath.Join(dir, "db.conflist"))
+	assert.True(t, os.IsNotExist(err))
+}
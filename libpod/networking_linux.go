@@ -3,20 +3,26 @@
 package libpod
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/containernetworking/cni/libcni"
+	"github.com/containernetworking/cni/pkg/types"
 	cnitypes "github.com/containernetworking/cni/pkg/types/current"
 	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/containers/libpod/pkg/bindhelper"
+	"github.com/containers/libpod/pkg/firewall"
 	"github.com/containers/libpod/pkg/inspect"
 	"github.com/containers/libpod/pkg/netns"
-	"github.com/containers/libpod/utils"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -36,8 +42,34 @@ func getPodNetwork(id, name, nsPath string, networks []string, ports []ocicni.Po
 	}
 }
 
+// networksAvailable returns an error if one of the CNI networks, or the
+// default network if none is given, is not configured
+func (r *Runtime) networksAvailable(networks []string) error {
+	if len(networks) == 0 {
+		if err := r.netPlugin.Status(); err != nil {
+			return errors.Wrapf(err, "default CNI network is not available")
+		}
+		return nil
+	}
+	for _, name := range networks {
+		if _, err := libcni.LoadConfList(r.config().CNIConfigDir, name); err != nil {
+			return errors.Wrapf(err, "CNI network %s is not available", name)
+		}
+	}
+	return nil
+}
+
 // Create and configure a new network namespace for a container
 func (r *Runtime) configureNetNS(ctr *Container, ctrNS ns.NetNS) (err error) {
+	if ctr.config.ResourceWaitTimeout > 0 {
+		timeout := time.Duration(ctr.config.ResourceWaitTimeout) * time.Second
+		if err := retryWithBackoff(timeout, 100*time.Millisecond, 5*time.Second, func() error {
+			return r.networksAvailable(ctr.config.Networks)
+		}); err != nil {
+			return errors.Wrapf(err, "networks of container %s are not available after %s", ctr.ID(), timeout)
+		}
+	}
+
 	podNetwork := getPodNetwork(ctr.ID(), ctr.Name(), ctrNS.Path(), ctr.config.Networks, ctr.config.PortMappings)
 
 	results, err := r.netPlugin.SetUpPod(podNetwork)
@@ -63,16 +95,10 @@ func (r *Runtime) configureNetNS(ctr *Container, ctrNS ns.NetNS) (err error) {
 		ctr.state.NetworkStatus = append(ctr.state.NetworkStatus, resultCurrent)
 	}
 
-	for _, r := range ctr.state.NetworkStatus {
-		// We need to temporarily use iptables to allow the container
-		// to resolve DNS until this issue is fixed upstream.
-		// https://github.com/containernetworking/plugins/pull/75
-		for _, ip := range r.IPs {
-			if ip.Address.IP.To4() != nil {
-				iptablesDNS("-I", ip.Address.IP.String())
-			}
-		}
-	}
+	// We need to temporarily use the firewall to allow the container
+	// to resolve DNS until this issue is fixed upstream.
+	// https://github.com/containernetworking/plugins/pull/75
+	r.allowForward(ctr)
 
 	return nil
 }
@@ -95,6 +121,24 @@ func (r *Runtime) createNetNS(ctr *Container) (err error) {
 	return r.configureNetNS(ctr, ctrNS)
 }
 
+// Tear down the CNI configuration of a container's network namespace and set
+// it up again, keeping the namespace and the processes in it
+func (r *Runtime) reloadNetNS(ctr *Container) error {
+	logrus.Debugf("Reloading network namespace at %s for container %s", ctr.state.NetNS.Path(), ctr.ID())
+
+	r.removeForward(ctr)
+
+	podNetwork := getPodNetwork(ctr.ID(), ctr.Name(), ctr.state.NetNS.Path(), ctr.config.Networks, ctr.config.PortMappings)
+
+	// Some of the configuration may already be gone, so don't fail here
+	if err := r.netPlugin.TearDownPod(podNetwork); err != nil {
+		logrus.Warnf("Error tearing down CNI namespace configuration for container %s: %v", ctr.ID(), err)
+	}
+	ctr.state.NetworkStatus = nil
+
+	return r.configureNetNS(ctr, ctr.state.NetNS)
+}
+
 // Configure the network namespace for a rootless container
 func (r *Runtime) setupRootlessNetNS(ctr *Container) (err error) {
 	defer ctr.rootlessSlirpSyncR.Close()
@@ -106,6 +150,11 @@ func (r *Runtime) setupRootlessNetNS(ctr *Container) (err error) {
 		return nil
 	}
 
+	opts, err := parseSlirp4netnsOptions(ctr.config.NetworkOptions[Slirp4netnsNetworkOptions])
+	if err != nil {
+		return err
+	}
+
 	syncR, syncW, err := os.Pipe()
 	if err != nil {
 		return errors.Wrapf(err, "failed to open pipe")
@@ -113,24 +162,118 @@ func (r *Runtime) setupRootlessNetNS(ctr *Container) (err error) {
 	defer syncR.Close()
 	defer syncW.Close()
 
-	cmd := exec.Command(path, "-c", "-e", "3", "-r", "4", fmt.Sprintf("%d", ctr.state.PID), "tap0")
+	// The privileged ports are forwarded by the bind helper, as long as
+	// slirp4netns keeps the connections to it open
+	hostFwds, helperConns, err := r.rootlessPortForwards(ctr)
+	for _, conn := range helperConns {
+		defer conn.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"-c", "-e", "3", "-r", "4"}, opts.args()...)
+	apiSocket := filepath.Join(r.config().TmpDir, fmt.Sprintf("slirp4netns-%s.sock", ctr.ID()[:12]))
+	if len(hostFwds) > 0 {
+		if err := os.Remove(apiSocket); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "cannot remove stale slirp4netns API socket %s", apiSocket)
+		}
+		args = append(args, "--api-socket", apiSocket)
+	}
+	args = append(args, fmt.Sprintf("%d", ctr.state.PID), "tap0")
+	cmd := exec.Command(path, args...)
 
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
 	}
 	cmd.ExtraFiles = append(cmd.ExtraFiles, ctr.rootlessSlirpSyncR, syncW)
+	cmd.ExtraFiles = append(cmd.ExtraFiles, helperConns...)
 
 	if err := cmd.Start(); err != nil {
 		return errors.Wrapf(err, "failed to start process")
 	}
+	defer func() {
+		if err != nil {
+			if err := cmd.Process.Kill(); err != nil {
+				logrus.Errorf("Error killing slirp4netns of container %s: %v", ctr.ID(), err)
+			}
+		}
+	}()
 
 	b := make([]byte, 16)
 	if _, err := syncR.Read(b); err != nil {
 		return errors.Wrapf(err, "failed to read from sync pipe")
 	}
+
+	for _, fwd := range hostFwds {
+		if err := slirp4netnsAddHostFwd(apiSocket, fwd); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// rootlessPortForwards returns the ports slirp4netns forwards to a rootless
+// container. slirp4netns binds the ports of the host, except the privileged
+// ones: the bind helper binds them and forwards them to unprivileged ports of
+// the loopback address slirp4netns binds instead, until the returned
+// connections to the helper are closed.
+func (r *Runtime) rootlessPortForwards(ctr *Container) ([]*slirp4netnsHostFwd, []*os.File, error) {
+	var fwds []*slirp4netnsHostFwd
+	var helperConns []*os.File
+	for _, port := range ctr.config.PortMappings {
+		fwd := &slirp4netnsHostFwd{
+			Proto:     port.Protocol,
+			HostAddr:  port.HostIP,
+			HostPort:  port.HostPort,
+			GuestPort: port.ContainerPort,
+		}
+		if fwd.HostAddr == "" {
+			fwd.HostAddr = "0.0.0.0"
+		}
+		if bindhelper.Privileged(port.HostPort) {
+			conn, targetPort, err := r.forwardPrivilegedPort(port)
+			if err != nil {
+				return nil, helperConns, err
+			}
+			helperConns = append(helperConns, conn)
+			fwd.HostAddr = "127.0.0.1"
+			fwd.HostPort = targetPort
+		}
+		fwds = append(fwds, fwd)
+	}
+	return fwds, helperConns, nil
+}
+
+// forwardPrivilegedPort asks the bind helper to forward a privileged port of
+// the host to a free port of the loopback address, and returns the connection
+// to the helper with the port
+func (r *Runtime) forwardPrivilegedPort(port ocicni.PortMapping) (*os.File, int32, error) {
+	if r.config().BindHelperSocket == "" {
+		return nil, 0, errors.Errorf("rootless containers can not publish port %d below %d without the bind helper, see bind_helper_socket in libpod.conf", port.HostPort, bindhelper.UnprivilegedPortStart())
+	}
+	if port.Protocol != "tcp" {
+		return nil, 0, errors.Errorf("rootless containers can only publish privileged TCP ports, not %s port %d", port.Protocol, port.HostPort)
+	}
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "cannot find a free port to forward port %d to", port.HostPort)
+	}
+	targetPort := int32(l.Addr().(*net.TCPAddr).Port)
+	l.Close()
+
+	conn, err := bindhelper.Forward(r.config().BindHelperSocket, &bindhelper.Request{
+		Protocol:   port.Protocol,
+		HostIP:     port.HostIP,
+		HostPort:   port.HostPort,
+		TargetPort: targetPort,
+	})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "cannot forward privileged port %d", port.HostPort)
+	}
+	return conn, targetPort, nil
+}
+
 // Configure the network namespace using the container process
 func (r *Runtime) setupNetNS(ctr *Container) (err error) {
 	nsProcess := fmt.Sprintf("/proc/%d/ns/net", ctr.state.PID)
@@ -164,16 +307,96 @@ func (r *Runtime) setupNetNS(ctr *Container) (err error) {
 	return r.configureNetNS(ctr, netNS)
 }
 
-// iptablesDNS accepts an arg (-I|-D) and IP address of the container and then
-// generates an iptables command to either add or subtract the needed rule
-func iptablesDNS(arg, ip string) error {
-	iptablesCmd := []string{"-t", "filter", arg, "FORWARD", "-s", ip, "!", "-o", ip, "-j", "ACCEPT"}
-	logrus.Debug("Running iptables command: ", strings.Join(iptablesCmd, " "))
-	_, err := utils.ExecCmd("iptables", iptablesCmd...)
+// getFirewall returns the firewall driver, setting it up on first use
+func (r *Runtime) getFirewall() (firewall.Firewall, error) {
+	r.firewallOnce.Do(func() {
+		r.firewall, r.firewallErr = firewall.New(r.config().FirewallDriver)
+		if r.firewallErr == nil {
+			logrus.Debugf("Using firewall driver %s", r.firewall.Name())
+		}
+	})
+	return r.firewall, r.firewallErr
+}
+
+// forwardIPs returns the IPv4 addresses of the container that forwarding is
+// allowed from
+func forwardIPs(ctr *Container) []net.IP {
+	var ips []net.IP
+	for _, r := range ctr.state.NetworkStatus {
+		for _, ip := range r.IPs {
+			if ip.Address.IP.To4() != nil {
+				ips = append(ips, ip.Address.IP)
+			}
+		}
+	}
+	return ips
+}
+
+// allowForward adds the firewall rules allowing forwarding from the addresses
+// of the container. Errors are only logged, as they only prevent the container
+// from reaching the name servers of the host.
+func (r *Runtime) allowForward(ctr *Container) {
+	fw, err := r.getFirewall()
+	if err != nil {
+		logrus.Errorf("Error setting up firewall for container %s: %v", ctr.ID(), err)
+		return
+	}
+	for _, ip := range forwardIPs(ctr) {
+		if err := fw.AllowForward(ip); err != nil {
+			logrus.Errorf("Error allowing forwarding from container %s: %v", ctr.ID(), err)
+		}
+	}
+}
+
+// removeForward removes the firewall rules added by allowForward
+func (r *Runtime) removeForward(ctr *Container) {
+	fw, err := r.getFirewall()
 	if err != nil {
-		logrus.Error(err)
+		logrus.Errorf("Error setting up firewall for container %s: %v", ctr.ID(), err)
+		return
+	}
+	for _, ip := range forwardIPs(ctr) {
+		if err := fw.RemoveForward(ip); err != nil {
+			logrus.Errorf("Error removing firewall rules of container %s: %v", ctr.ID(), err)
+		}
 	}
-	return err
+}
+
+// ReconcileFirewall adds the firewall rules of running containers again, after
+// something else, such as a reload of firewalld, removed them. This includes
+// the rules of the chained CNI plugins publishing the ports of containers.
+func (r *Runtime) ReconcileFirewall() error {
+	ctrs, err := r.GetRunningContainers()
+	if err != nil {
+		return err
+	}
+	for _, ctr := range ctrs {
+		ctr.lock.Lock()
+		if err := ctr.syncContainer(); err != nil {
+			ctr.lock.Unlock()
+			logrus.Errorf("Error syncing container %s: %v", ctr.ID(), err)
+			continue
+		}
+		if ctr.state.State == ContainerStateRunning && ctr.config.CreateNetNS && ctr.state.NetNS != nil {
+			r.allowForward(ctr)
+			if err := r.restoreNetworkRules(ctr); err != nil {
+				logrus.Errorf("Error restoring network rules of container %s: %v", ctr.ID(), err)
+			}
+		}
+		ctr.lock.Unlock()
+	}
+	return nil
+}
+
+// WatchFirewall reconciles the firewall rules of running containers whenever
+// firewalld is reloaded, until the context is cancelled
+func (r *Runtime) WatchFirewall(ctx context.Context) error {
+	return firewall.WatchReloads(ctx, func() {
+		logrus.Debugf("Reconciling firewall rules of containers")
+		if err := r.ReconcileFirewall(); err != nil {
+			logrus.Errorf("Error reconciling firewall rules: %v", err)
+		}
+	})
 }
 
 // Join an existing network namespace
@@ -186,6 +409,113 @@ func joinNetNS(path string) (ns.NetNS, error) {
 	return ns, nil
 }
 
+// Join the existing network namespace of a container created by something
+// other than libpod, recording the addresses configured in it
+func (r *Runtime) joinExternalNetNS(ctr *Container) (err error) {
+	ctrNS, err := joinNetNS(ctr.config.NetNsPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if err2 := ctrNS.Close(); err2 != nil {
+				logrus.Errorf("Error closing network namespace for container %s: %v", ctr.ID(), err2)
+			}
+		}
+	}()
+
+	var links []netNSLink
+	err = ctrNS.Do(func(_ ns.NetNS) error {
+		linkList, err := netlink.LinkList()
+		if err != nil {
+			return err
+		}
+		for _, link := range linkList {
+			if link.Attrs().Flags&net.FlagLoopback != 0 {
+				continue
+			}
+			addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+			if err != nil {
+				return err
+			}
+			routes, err := netlink.RouteList(link, netlink.FAMILY_ALL)
+			if err != nil {
+				return err
+			}
+			links = append(links, netNSLink{attrs: link.Attrs(), addrs: addrs, routes: routes})
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error reading addresses of network namespace %s for container %s", ctrNS.Path(), ctr.ID())
+	}
+
+	logrus.Debugf("Joined network namespace at %s for container %s", ctrNS.Path(), ctr.ID())
+	ctr.state.NetNS = ctrNS
+	ctr.state.NetworkStatus = []*cnitypes.Result{netNSResult(ctrNS.Path(), links)}
+	return nil
+}
+
+// netNSLink is a network interface in a network namespace, with its
+// addresses and routes
+type netNSLink struct {
+	attrs  *netlink.LinkAttrs
+	addrs  []netlink.Addr
+	routes []netlink.Route
+}
+
+// netNSResult describes the interfaces of a network namespace libpod did not
+// configure as a CNI result, so that they are reported like those of networks
+// set up by CNI. Link-local addresses are left out.
+func netNSResult(nsPath string, links []netNSLink) *cnitypes.Result {
+	result := &cnitypes.Result{CNIVersion: cnitypes.ImplementedSpecVersion}
+	for _, link := range links {
+		iface := len(result.Interfaces)
+		result.Interfaces = append(result.Interfaces, &cnitypes.Interface{
+			Name:    link.attrs.Name,
+			Mac:     link.attrs.HardwareAddr.String(),
+			Sandbox: nsPath,
+		})
+
+		var gw4, gw6 net.IP
+		for _, route := range link.routes {
+			if route.Gw == nil {
+				continue
+			}
+			dst := route.Dst
+			if dst == nil {
+				// A default route
+				if route.Gw.To4() != nil {
+					gw4 = route.Gw
+					dst = &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}
+				} else {
+					gw6 = route.Gw
+					dst = &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
+				}
+			}
+			result.Routes = append(result.Routes, &types.Route{Dst: *dst, GW: route.Gw})
+		}
+
+		for _, addr := range link.addrs {
+			if addr.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			ip := &cnitypes.IPConfig{
+				Version:   "4",
+				Interface: cnitypes.Int(iface),
+				Address:   *addr.IPNet,
+				Gateway:   gw4,
+			}
+			if addr.IP.To4() == nil {
+				ip.Version = "6"
+				ip.Gateway = gw6
+			}
+			result.IPs = append(result.IPs, ip)
+		}
+	}
+	return result
+}
+
 // Close a network namespace.
 // Differs from teardownNetNS() in that it will not attempt to undo the setup of
 // the namespace, but will instead only close the open file descriptor
@@ -213,17 +543,11 @@ func (r *Runtime) teardownNetNS(ctr *Container) error {
 		return nil
 	}
 
-	// Because we are using iptables to allow the container to resolve DNS
-	// on per IP address, we also need to try to remove the iptables rule
+	// Because we are using the firewall to allow the container to resolve DNS
+	// on per IP address, we also need to try to remove the firewall rule
 	// on cleanup. Remove when https://github.com/containernetworking/plugins/pull/75
 	// is merged.
-	for _, r := range ctr.state.NetworkStatus {
-		for _, ip := range r.IPs {
-			if ip.Address.IP.To4() != nil {
-				iptablesDNS("-D", ip.Address.IP.String())
-			}
-		}
-	}
+	r.removeForward(ctr)
 
 	logrus.Debugf("Tearing down network namespace at %s for container %s", ctr.state.NetNS.Path(), ctr.ID())
 
@@ -300,11 +624,15 @@ func (c *Container) getContainerNetworkInfo(data *inspect.ContainerInspectData)
 			if ctrIP.Version == "4" {
 				data.NetworkSettings.IPAddress = splitIP[0]
 				data.NetworkSettings.IPPrefixLen = mask
-				data.NetworkSettings.Gateway = ctrIP.Gateway.String()
+				if ctrIP.Gateway != nil {
+					data.NetworkSettings.Gateway = ctrIP.Gateway.String()
+				}
 			} else {
 				data.NetworkSettings.GlobalIPv6Address = splitIP[0]
 				data.NetworkSettings.GlobalIPv6PrefixLen = mask
-				data.NetworkSettings.IPv6Gateway = ctrIP.Gateway.String()
+				if ctrIP.Gateway != nil {
+					data.NetworkSettings.IPv6Gateway = ctrIP.Gateway.String()
+				}
 			}
 		}
 
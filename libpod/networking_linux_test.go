@@ -0,0 +1,66 @@
+// +build linux
+
+package libpod
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+)
+
+func mustParseCIDR(t *testing.T, s string) *net.IPNet {
+	ip, ipNet, err := net.ParseCIDR(s)
+	assert.NoError(t, err)
+	ipNet.IP = ip
+	return ipNet
+}
+
+func TestNetNSResult(t *testing.T) {
+	mac, err := net.ParseMAC("52:54:00:12:34:56")
+	assert.NoError(t, err)
+
+	links := []netNSLink{
+		{
+			attrs: &netlink.LinkAttrs{Name: "eth0", HardwareAddr: mac},
+			addrs: []netlink.Addr{
+				{IPNet: mustParseCIDR(t, "192.168.1.10/24")},
+				{IPNet: mustParseCIDR(t, "fe80::5054:ff:fe12:3456/64")},
+				{IPNet: mustParseCIDR(t, "fd00::10/64")},
+			},
+			routes: []netlink.Route{
+				{Dst: mustParseCIDR(t, "192.168.1.0/24")},
+				{Gw: net.ParseIP("192.168.1.1")},
+				{Dst: mustParseCIDR(t, "10.0.0.0/8"), Gw: net.ParseIP("192.168.1.254")},
+			},
+		},
+		{
+			attrs: &netlink.LinkAttrs{Name: "wg0"},
+			addrs: []netlink.Addr{{IPNet: mustParseCIDR(t, "10.8.0.2/32")}},
+		},
+	}
+
+	result := netNSResult("/run/netns/external", links)
+	assert.Len(t, result.Interfaces, 2)
+	assert.Equal(t, "eth0", result.Interfaces[0].Name)
+	assert.Equal(t, "52:54:00:12:34:56", result.Interfaces[0].Mac)
+	assert.Equal(t, "/run/netns/external", result.Interfaces[0].Sandbox)
+	assert.Equal(t, "", result.Interfaces[1].Mac)
+
+	assert.Len(t, result.IPs, 3)
+	assert.Equal(t, "4", result.IPs[0].Version)
+	assert.Equal(t, "192.168.1.10/24", result.IPs[0].Address.String())
+	assert.Equal(t, "192.168.1.1", result.IPs[0].Gateway.String())
+	assert.Equal(t, 0, *result.IPs[0].Interface)
+	assert.Equal(t, "6", result.IPs[1].Version)
+	assert.Equal(t, "fd00::10/64", result.IPs[1].Address.String())
+	assert.Nil(t, result.IPs[1].Gateway)
+	assert.Equal(t, 1, *result.IPs[2].Interface)
+	assert.Nil(t, result.IPs[2].Gateway)
+
+	assert.Len(t, result.Routes, 2)
+	assert.Equal(t, "0.0.0.0/0", result.Routes[0].Dst.String())
+	assert.Equal(t, "10.0.0.0/8", result.Routes[1].Dst.String())
+	assert.Equal(t, "192.168.1.254", result.Routes[1].GW.String())
+}
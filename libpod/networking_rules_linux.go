@@ -0,0 +1,152 @@
+// +build linux
+
+package libpod
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ruleManagingPlugins are the types of chained CNI plugins which only add
+// firewall rules for a container, such as those publishing its ports. Adding
+// a container to them again is harmless, so they are run again to restore
+// their rules.
+var ruleManagingPlugins = map[string]bool{
+	"portmap":  true,
+	"firewall": true,
+}
+
+// loadCNINetworks loads the CNI networks in the configuration directory,
+// returning them by name along with the name of the default network. Like
+// OCICNI, the default network is the first one in the directory unless
+// defaultNetwork is set.
+func loadCNINetworks(confDir, defaultNetwork string) (map[string]*libcni.NetworkConfigList, string, error) {
+	files, err := libcni.ConfFiles(confDir, []string{".conf", ".conflist", ".json"})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "error listing CNI configurations in %s", confDir)
+	}
+	sort.Strings(files)
+
+	networks := make(map[string]*libcni.NetworkConfigList)
+	for _, file := range files {
+		var list *libcni.NetworkConfigList
+		if strings.HasSuffix(file, ".conflist") {
+			list, err = libcni.ConfListFromFile(file)
+		} else {
+			var conf *libcni.NetworkConfig
+			if conf, err = libcni.ConfFromFile(file); err == nil {
+				list, err = libcni.ConfListFromConf(conf)
+			}
+		}
+		if err != nil {
+			logrus.Debugf("Error loading CNI configuration %s: %v", file, err)
+			continue
+		}
+		if len(list.Plugins) == 0 {
+			continue
+		}
+		if list.Name == "" {
+			list.Name = filepath.Base(file)
+		}
+		networks[list.Name] = list
+		if defaultNetwork == "" {
+			defaultNetwork = list.Name
+		}
+	}
+	return networks, defaultNetwork, nil
+}
+
+// CNINetworks returns the CNI networks configured for the runtime by name,
+// along with the name of the default network
+func (r *Runtime) CNINetworks() (map[string]*libcni.NetworkConfigList, string, error) {
+	return loadCNINetworks(r.config().CNIConfigDir, r.config().CNIDefaultNetwork)
+}
+
+// ruleManagingConfigs returns the configurations of the chained plugins of
+// the network that only manage firewall rules, as standalone configurations
+// taking prevResult as the result of the network
+func ruleManagingConfigs(list *libcni.NetworkConfigList, prevResult interface{}) ([]*libcni.NetworkConfig, error) {
+	var confs []*libcni.NetworkConfig
+	// The first plugin sets up the interface of the container, and must
+	// not run again
+	for _, plugin := range list.Plugins[1:] {
+		if !ruleManagingPlugins[plugin.Network.Type] {
+			continue
+		}
+		conf, err := libcni.InjectConf(plugin, map[string]interface{}{
+			"name":       list.Name,
+			"cniVersion": list.CNIVersion,
+			"prevResult": prevResult,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error building configuration of %s plugin of network %s", plugin.Network.Type, list.Name)
+		}
+		confs = append(confs, conf)
+	}
+	return confs, nil
+}
+
+// restoreNetworkRules runs the chained CNI plugins managing firewall rules,
+// such as portmap, for the networks of the container again, restoring the
+// rules publishing its ports
+func (r *Runtime) restoreNetworkRules(ctr *Container) error {
+	lists, defaultNetwork, err := loadCNINetworks(r.config().CNIConfigDir, r.config().CNIDefaultNetwork)
+	if err != nil {
+		return err
+	}
+	networks := ctr.config.Networks
+	if len(networks) == 0 {
+		networks = []string{defaultNetwork}
+	}
+
+	cniConfig := libcni.NewCNIConfig(r.config().CNIPluginDir, nil)
+	podNetwork := getPodNetwork(ctr.ID(), ctr.Name(), ctr.state.NetNS.Path(), networks, ctr.config.PortMappings)
+	for i, name := range networks {
+		if i >= len(ctr.state.NetworkStatus) {
+			break
+		}
+		list, ok := lists[name]
+		if !ok {
+			return errors.Errorf("CNI network %s of container %s not found", name, ctr.ID())
+		}
+		prevResult, err := ctr.state.NetworkStatus[i].GetAsVersion(list.CNIVersion)
+		if err != nil {
+			return errors.Wrapf(err, "error converting result of network %s of container %s", name, ctr.ID())
+		}
+		confs, err := ruleManagingConfigs(list, prevResult)
+		if err != nil {
+			return err
+		}
+
+		// Mirror the runtime configuration OCICNI set the network up with
+		rt := &libcni.RuntimeConf{
+			ContainerID: podNetwork.ID,
+			NetNS:       podNetwork.NetNS,
+			IfName:      fmt.Sprintf("eth%d", i),
+			Args: [][2]string{
+				{"IgnoreUnknown", "1"},
+				{"K8S_POD_NAMESPACE", podNetwork.Namespace},
+				{"K8S_POD_NAME", podNetwork.Name},
+				{"K8S_POD_INFRA_CONTAINER_ID", podNetwork.ID},
+			},
+		}
+		if len(podNetwork.PortMappings) > 0 {
+			rt.CapabilityArgs = map[string]interface{}{
+				"portMappings": podNetwork.PortMappings,
+			}
+		}
+		for _, conf := range confs {
+			logrus.Debugf("Restoring rules of %s plugin of network %s for container %s", conf.Network.Type, name, ctr.ID())
+			if _, err := cniConfig.AddNetwork(conf, rt); err != nil {
+				return errors.Wrapf(err, "error restoring rules of %s plugin of network %s for container %s", conf.Network.Type, name, ctr.ID())
+			}
+		}
+	}
+	return nil
+}
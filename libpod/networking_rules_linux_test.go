@@ -0,0 +1,77 @@
+// +build linux
+
+package libpod
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBridgeConfList = `{
+	"cniVersion": "0.3.1",
+	"name": "podman",
+	"plugins": [
+		{"type": "bridge", "bridge": "cni0", "ipMasq": true},
+		{"type": "portmap", "capabilities": {"portMappings": true}},
+		{"type": "tuning"}
+	]
+}`
+
+const testMacvlanConf = `{
+	"cniVersion": "0.3.1",
+	"name": "lan",
+	"type": "macvlan",
+	"master": "eth0"
+}`
+
+func TestLoadCNINetworks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cni")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "87-podman-bridge.conflist"), []byte(testBridgeConfList), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "90-lan.conf"), []byte(testMacvlanConf), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "10-broken.conf"), []byte("{"), 0644))
+
+	networks, defaultNetwork, err := loadCNINetworks(dir, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "podman", defaultNetwork)
+	assert.Len(t, networks, 2)
+	assert.Len(t, networks["lan"].Plugins, 1)
+
+	_, defaultNetwork, err = loadCNINetworks(dir, "lan")
+	assert.NoError(t, err)
+	assert.Equal(t, "lan", defaultNetwork)
+}
+
+func TestRuleManagingConfigs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cni")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "87-podman-bridge.conflist"), []byte(testBridgeConfList), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "90-lan.conf"), []byte(testMacvlanConf), 0644))
+	networks, _, err := loadCNINetworks(dir, "")
+	assert.NoError(t, err)
+
+	prevResult := map[string]interface{}{"cniVersion": "0.3.1"}
+	confs, err := ruleManagingConfigs(networks["podman"], prevResult)
+	assert.NoError(t, err)
+	assert.Len(t, confs, 1)
+	assert.Equal(t, "portmap", confs[0].Network.Type)
+	assert.Equal(t, "podman", confs[0].Network.Name)
+	assert.Equal(t, "0.3.1", confs[0].Network.CNIVersion)
+
+	var conf map[string]interface{}
+	assert.NoError(t, json.Unmarshal(confs[0].Bytes, &conf))
+	assert.Equal(t, prevResult, conf["prevResult"])
+	assert.Equal(t, map[string]interface{}{"portMappings": true}, conf["capabilities"])
+
+	confs, err = ruleManagingConfigs(networks["lan"], prevResult)
+	assert.NoError(t, err)
+	assert.Empty(t, confs)
+}
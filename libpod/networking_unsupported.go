@@ -3,6 +3,10 @@
 package libpod
 
 import (
+	"context"
+	"net"
+
+	"github.com/containernetworking/cni/libcni"
 	"github.com/containers/libpod/pkg/inspect"
 )
 
@@ -22,6 +26,43 @@ func (r *Runtime) createNetNS(ctr *Container) (err error) {
 	return ErrNotImplemented
 }
 
+func (r *Runtime) reloadNetNS(ctr *Container) error {
+	return ErrNotImplemented
+}
+
 func (c *Container) getContainerNetworkInfo(data *inspect.ContainerInspectData) *inspect.ContainerInspectData {
 	return nil
 }
+
+// ReconcileFirewall is not implemented on this platform
+func (r *Runtime) ReconcileFirewall() error {
+	return ErrNotImplemented
+}
+
+// WatchFirewall is not implemented on this platform
+func (r *Runtime) WatchFirewall(ctx context.Context) error {
+	return ErrNotImplemented
+}
+
+// CNINetworks is not implemented on this platform
+func (r *Runtime) CNINetworks() (map[string]*libcni.NetworkConfigList, string, error) {
+	return nil, "", ErrNotImplemented
+}
+
+// NetworkConfig describes a CNI network created by CreateNetwork
+type NetworkConfig struct {
+	Name     string
+	Subnet   *net.IPNet
+	Gateway  net.IP
+	Internal bool
+}
+
+// CreateNetwork is not implemented on this platform
+func (r *Runtime) CreateNetwork(config NetworkConfig) (*NetworkConfig, error) {
+	return nil, ErrNotImplemented
+}
+
+// RemoveNetwork is not implemented on this platform
+func (r *Runtime) RemoveNetwork(name string) error {
+	return ErrNotImplemented
+}
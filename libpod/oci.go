@@ -12,6 +12,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -51,6 +52,20 @@ const (
 	// NsRunDir is the default directory in which running network namespaces
 	// are stored
 	NsRunDir = "/var/run/netns"
+
+	// WasmRuntimeHandler is the runtime handler of containers running WASM
+	// modules with the runtime of wasm_runtime_path
+	WasmRuntimeHandler = "wasm"
+	// KataRuntimeHandler is the runtime handler of containers running in a
+	// VM with the runtime of kata_runtime_path. All containers of a pod
+	// of this runtime class share the VM of its infra container.
+	KataRuntimeHandler = "kata"
+	// RuntimeClassLabel is the label of containers and pods selecting
+	// their runtime handler
+	RuntimeClassLabel = "io.podman.runtime-class"
+	// wasmHandlerAnnotation is the annotation selecting the WASM handler
+	// of crun
+	wasmHandlerAnnotation = "run.oci.handler"
 )
 
 // OCIRuntime represents an OCI-compatible runtime that libpod can call into
@@ -58,6 +73,8 @@ const (
 type OCIRuntime struct {
 	name          string
 	path          string
+	wasmPath      string
+	kataPath      string
 	conmonPath    string
 	conmonEnv     []string
 	cgroupManager string
@@ -66,6 +83,8 @@ type OCIRuntime struct {
 	socketsDir    string
 	logSizeMax    int64
 	noPivot       bool
+	featuresLock  sync.Mutex
+	featuresCache map[string]*RuntimeFeatures
 }
 
 // syncInfo is used to return data from monitor process to daemon
@@ -74,6 +93,24 @@ type syncInfo struct {
 	Message string `json:"message,omitempty"`
 }
 
+// handlerPath returns the path of the OCI runtime binary of a runtime handler,
+// or "" if it was not found. The default runtime has the empty handler.
+func (r *OCIRuntime) handlerPath(handler string) string {
+	switch handler {
+	case WasmRuntimeHandler:
+		return r.wasmPath
+	case KataRuntimeHandler:
+		return r.kataPath
+	}
+	return r.path
+}
+
+// runtimePath returns the path of the OCI runtime binary that runs the given
+// container
+func (r *OCIRuntime) runtimePath(ctr *Container) string {
+	return r.handlerPath(ctr.config.RuntimeHandler)
+}
+
 // Make a new OCI runtime with provided options
 func newOCIRuntime(name string, path string, conmonPath string, conmonEnv []string, cgroupManager string, tmpDir string, logSizeMax int64, noPivotRoot bool) (*OCIRuntime, error) {
 	runtime := new(OCIRuntime)
@@ -181,6 +218,10 @@ func waitPidsStop(pids []int, timeout time.Duration) error {
 }
 
 func bindPorts(ports []ocicni.PortMapping) ([]*os.File, error) {
+	// slirp4netns binds the ports of rootless containers
+	if rootless.IsRootless() {
+		return nil, nil
+	}
 	var files []*os.File
 	for _, i := range ports {
 		switch i.Protocol {
@@ -252,7 +293,7 @@ func (r *OCIRuntime) createOCIContainer(ctr *Container, cgroupParent string) (er
 	}
 	args = append(args, "-c", ctr.ID())
 	args = append(args, "-u", ctr.ID())
-	args = append(args, "-r", r.path)
+	args = append(args, "-r", r.runtimePath(ctr))
 	args = append(args, "-b", ctr.bundlePath())
 	args = append(args, "-p", filepath.Join(ctr.state.RunDir, "pidfile"))
 	args = append(args, "-l", ctr.LogPath())
@@ -330,7 +371,9 @@ func (r *OCIRuntime) createOCIContainer(ctr *Container, cgroupParent string) (er
 		cmd.ExtraFiles = append(cmd.ExtraFiles, ctr.rootlessSlirpSyncW)
 	}
 
-	if notify, ok := os.LookupEnv("NOTIFY_SOCKET"); ok {
+	// Containers with a startup healthcheck are ready once it passes, which
+	// NotifyStarted reports instead of the container
+	if notify, ok := os.LookupEnv("NOTIFY_SOCKET"); ok && ctr.config.StartupHealthCheckConfig == nil {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("NOTIFY_SOCKET=%s", notify))
 	}
 	if listenfds, ok := os.LookupEnv("LISTEN_FDS"); ok {
@@ -446,7 +489,7 @@ func (r *OCIRuntime) updateContainerStatus(ctr *Container) error {
 	// Store old state so we know if we were already stopped
 	oldState := ctr.state.State
 
-	cmd := exec.Command(r.path, "state", ctr.ID())
+	cmd := exec.Command(r.runtimePath(ctr), "state", ctr.ID())
 	cmd.Env = append(cmd.Env, fmt.Sprintf("XDG_RUNTIME_DIR=%s", runtimeDir))
 
 	out, err := cmd.CombinedOutput()
@@ -470,6 +513,17 @@ func (r *OCIRuntime) updateContainerStatus(ctr *Container) error {
 		ctr.state.State = ContainerStatePaused
 	case "running":
 		ctr.state.State = ContainerStateRunning
+		// OCI runtimes unaware of the cgroup v2 freezer report
+		// containers paused through it as running
+		if oldState == ContainerStatePaused {
+			frozen, err := ctr.cgroupFrozen()
+			if err != nil {
+				return err
+			}
+			if frozen {
+				ctr.state.State = ContainerStatePaused
+			}
+		}
 	case "stopped":
 		ctr.state.State = ContainerStateStopped
 	default:
@@ -516,9 +570,13 @@ func (r *OCIRuntime) updateContainerStatus(ctr *Container) error {
 		}
 		ctr.state.ExitCode = int32(statusCode)
 
+		// Conmon records OOM kills it is notified of in the bundle.
+		// Fall back to the kill counter of the memory cgroup, which
+		// also catches OOM kills conmon missed.
 		oomFilePath := filepath.Join(ctr.bundlePath(), "oom")
-		if _, err = os.Stat(oomFilePath); err == nil {
+		if _, err = os.Stat(oomFilePath); err == nil || cgroupOOMKilled(ctr) {
 			ctr.state.OOMKilled = true
+			logrus.Infof("Container %s was killed by the OOM killer", ctr.ID())
 		}
 
 		ctr.state.Exited = true
@@ -531,11 +589,13 @@ func (r *OCIRuntime) updateContainerStatus(ctr *Container) error {
 // Sets time the container was started, but does not save it.
 func (r *OCIRuntime) startContainer(ctr *Container) error {
 	// TODO: streams should probably *not* be our STDIN/OUT/ERR - redirect to buffers?
-	if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.path, "start", ctr.ID()); err != nil {
+	if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.runtimePath(ctr), "start", ctr.ID()); err != nil {
 		return err
 	}
 
 	ctr.state.StartedTime = time.Now()
+	// The cgroup of the container counts the hits of its PIDs limit anew
+	ctr.state.PidsLimitHits = 0
 
 	return nil
 }
@@ -543,7 +603,7 @@ func (r *OCIRuntime) startContainer(ctr *Container) error {
 // killContainer sends the given signal to the given container
 func (r *OCIRuntime) killContainer(ctr *Container, signal uint) error {
 	logrus.Debugf("Sending signal %d to container %s", signal, ctr.ID())
-	if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.path, "kill", ctr.ID(), fmt.Sprintf("%d", signal)); err != nil {
+	if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.runtimePath(ctr), "kill", ctr.ID(), fmt.Sprintf("%d", signal)); err != nil {
 		return errors.Wrapf(err, "error sending signal to container %s", ctr.ID())
 	}
 
@@ -601,7 +661,7 @@ func (r *OCIRuntime) stopContainer(ctr *Container, timeout uint) error {
 		args = []string{"kill", "--all", ctr.ID(), "KILL"}
 	}
 
-	if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.path, args...); err != nil {
+	if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.runtimePath(ctr), args...); err != nil {
 		// Again, check if the container is gone. If it is, exit cleanly.
 		err := unix.Kill(ctr.state.PID, 0)
 		if err == unix.ESRCH {
@@ -621,25 +681,44 @@ func (r *OCIRuntime) stopContainer(ctr *Container, timeout uint) error {
 
 // deleteContainer deletes a container from the OCI runtime
 func (r *OCIRuntime) deleteContainer(ctr *Container) error {
-	_, err := utils.ExecCmd(r.path, "delete", "--force", ctr.ID())
+	_, err := utils.ExecCmd(r.runtimePath(ctr), "delete", "--force", ctr.ID())
 	return err
 }
 
 // pauseContainer pauses the given container
 func (r *OCIRuntime) pauseContainer(ctr *Container) error {
-	return utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.path, "pause", ctr.ID())
+	return utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.runtimePath(ctr), "pause", ctr.ID())
 }
 
 // unpauseContainer unpauses the given container
 func (r *OCIRuntime) unpauseContainer(ctr *Container) error {
-	return utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.path, "resume", ctr.ID())
+	return utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.runtimePath(ctr), "resume", ctr.ID())
+}
+
+// updateContainerResources updates the cgroup resources of a created,
+// running or paused container
+func (r *OCIRuntime) updateContainerResources(ctr *Container, resources *spec.LinuxResources) error {
+	f, err := ioutil.TempFile(r.tmpDir, "resources")
+	if err != nil {
+		return errors.Wrapf(err, "error creating resources file for container %s", ctr.ID())
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(resources); err != nil {
+		return errors.Wrapf(err, "error writing resources file for container %s", ctr.ID())
+	}
+	if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.runtimePath(ctr), "update", "--resources", f.Name(), ctr.ID()); err != nil {
+		return errors.Wrapf(err, "error updating resources of container %s", ctr.ID())
+	}
+	return nil
 }
 
 // execContainer executes a command in a running container
 // TODO: Add --detach support
 // TODO: Convert to use conmon
 // TODO: add --pid-file and use that to generate exec session tracking
-func (r *OCIRuntime) execContainer(c *Container, cmd, capAdd, env []string, tty bool, user, sessionID string) (*exec.Cmd, error) {
+func (r *OCIRuntime) execContainer(c *Container, cmd, capAdd, env []string, tty bool, user, sessionID string, streams *AttachStreams) (*exec.Cmd, error) {
 	if len(cmd) == 0 {
 		return nil, errors.Wrapf(ErrInvalidArg, "must provide a command to execute")
 	}
@@ -688,11 +767,12 @@ func (r *OCIRuntime) execContainer(c *Container, cmd, capAdd, env []string, tty
 	args = append(args, c.ID())
 	args = append(args, cmd...)
 
-	logrus.Debugf("Starting runtime %s with following arguments: %v", r.path, args)
+	runtimePath := r.runtimePath(c)
+	logrus.Debugf("Starting runtime %s with following arguments: %v", runtimePath, args)
 
-	execCmd := exec.Command(r.path, args...)
+	execCmd := exec.Command(runtimePath, args...)
 	if rootless.IsRootless() {
-		args = append([]string{"--preserve-credentials", "--user=/proc/self/fd/3", r.path}, args...)
+		args = append([]string{"--preserve-credentials", "--user=/proc/self/fd/3", runtimePath}, args...)
 		f, err := rootless.GetUserNSForPid(uint(c.state.PID))
 		if err != nil {
 			return nil, err
@@ -700,9 +780,15 @@ func (r *OCIRuntime) execContainer(c *Container, cmd, capAdd, env []string, tty
 		execCmd = exec.Command("nsenter", args...)
 		execCmd.ExtraFiles = append(execCmd.ExtraFiles, f)
 	}
-	execCmd.Stdout = os.Stdout
-	execCmd.Stderr = os.Stderr
-	execCmd.Stdin = os.Stdin
+	if streams.AttachOutput {
+		execCmd.Stdout = streams.OutputStream
+	}
+	if streams.AttachError {
+		execCmd.Stderr = streams.ErrorStream
+	}
+	if streams.AttachInput {
+		execCmd.Stdin = streams.InputStream
+	}
 	execCmd.Env = append(execCmd.Env, fmt.Sprintf("XDG_RUNTIME_DIR=%s", runtimeDir))
 
 	if err := execCmd.Start(); err != nil {
@@ -745,7 +831,7 @@ func (r *OCIRuntime) execStopContainer(ctr *Container, timeout uint) error {
 		// Stop using SIGTERM by default
 		// Use SIGSTOP after a timeout
 		logrus.Debugf("Killing all processes in container %s with SIGTERM", ctr.ID())
-		if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.path, "kill", "--all", ctr.ID(), "TERM"); err != nil {
+		if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.runtimePath(ctr), "kill", "--all", ctr.ID(), "TERM"); err != nil {
 			return errors.Wrapf(err, "error sending SIGTERM to container %s processes", ctr.ID())
 		}
 
@@ -760,7 +846,7 @@ func (r *OCIRuntime) execStopContainer(ctr *Container, timeout uint) error {
 
 	// Send SIGKILL
 	logrus.Debugf("Killing all processes in container %s with SIGKILL", ctr.ID())
-	if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.path, "kill", "--all", ctr.ID(), "KILL"); err != nil {
+	if err := utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, r.runtimePath(ctr), "kill", "--all", ctr.ID(), "KILL"); err != nil {
 		return errors.Wrapf(err, "error sending SIGKILL to container %s processes", ctr.ID())
 	}
 
@@ -0,0 +1,242 @@
+package libpod
+
+import (
+	"encoding/json"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/containers/libpod/pkg/cgroups"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// FeatureSupport is whether an OCI runtime supports a feature
+type FeatureSupport string
+
+const (
+	// FeatureSupported is for features the runtime supports
+	FeatureSupported FeatureSupport = "supported"
+	// FeatureUnsupported is for features the runtime does not support,
+	// containers requiring them are refused
+	FeatureUnsupported FeatureSupport = "unsupported"
+	// FeatureUnknown is for features the runtime does not report and that
+	// can not be guessed from its version, left to the runtime to refuse
+	FeatureUnknown FeatureSupport = "unknown"
+)
+
+// featureSupport returns the support of a feature the runtime reports or whose
+// support is known from its version
+func featureSupport(supported bool) FeatureSupport {
+	if supported {
+		return FeatureSupported
+	}
+	return FeatureUnsupported
+}
+
+// RuntimeFeatures describes the features an OCI runtime supports
+type RuntimeFeatures struct {
+	// Probed is how the features were found: "features" if the runtime
+	// reported them, "version" if they were guessed from its version
+	Probed string `json:"probed"`
+	// IDMappedMounts is whether the runtime supports the idmap mount
+	// option
+	IDMappedMounts FeatureSupport `json:"idMappedMounts"`
+	// CgroupV2 is whether the runtime supports the unified cgroup
+	// hierarchy
+	CgroupV2 FeatureSupport `json:"cgroupV2"`
+	// CRIU is whether the runtime can checkpoint and restore containers
+	CRIU FeatureSupport `json:"criu"`
+	// IntelRDT is whether the runtime supports resctrl classes
+	IntelRDT FeatureSupport `json:"intelRdt"`
+}
+
+// newRuntimeFeatures returns the features of a runtime, all unknown until
+// they are found
+func newRuntimeFeatures(probed string) *RuntimeFeatures {
+	return &RuntimeFeatures{
+		Probed:         probed,
+		IDMappedMounts: FeatureUnknown,
+		CgroupV2:       FeatureUnknown,
+		CRIU:           FeatureUnknown,
+		IntelRDT:       FeatureUnknown,
+	}
+}
+
+// ociFeatures is the part of the output of the features subcommand of OCI
+// runtimes libpod uses
+type ociFeatures struct {
+	MountOptions []string `json:"mountOptions"`
+	Linux        *struct {
+		Cgroup *struct {
+			V2 *bool `json:"v2"`
+		} `json:"cgroup"`
+		IntelRdt *struct {
+			Enabled *bool `json:"enabled"`
+		} `json:"intelRdt"`
+		MountExtensions *struct {
+			IDMap *struct {
+				Enabled *bool `json:"enabled"`
+			} `json:"idmap"`
+		} `json:"mountExtensions"`
+	} `json:"linux"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// checkpointAnnotations are the annotations runtimes report CRIU support in
+var checkpointAnnotations = []string{
+	"org.opencontainers.runc.checkpoint.enabled",
+	"run.oci.crun.checkpoint.enabled",
+}
+
+var runtimeVersionRegexp = regexp.MustCompile(`(?m)^(\S+) version (\S+)`)
+
+// parseRuntimeFeatures parses the output of the features subcommand of an
+// OCI runtime
+func parseRuntimeFeatures(output []byte) (*RuntimeFeatures, error) {
+	var features ociFeatures
+	if err := json.Unmarshal(output, &features); err != nil {
+		return nil, errors.Wrapf(err, "error parsing OCI runtime features")
+	}
+
+	f := newRuntimeFeatures("features")
+	if features.MountOptions != nil {
+		f.IDMappedMounts = FeatureUnsupported
+		for _, opt := range features.MountOptions {
+			if opt == "idmap" {
+				f.IDMappedMounts = FeatureSupported
+			}
+		}
+	}
+	if linux := features.Linux; linux != nil {
+		if linux.Cgroup != nil && linux.Cgroup.V2 != nil {
+			f.CgroupV2 = featureSupport(*linux.Cgroup.V2)
+		}
+		if linux.IntelRdt != nil && linux.IntelRdt.Enabled != nil {
+			f.IntelRDT = featureSupport(*linux.IntelRdt.Enabled)
+		}
+		if linux.MountExtensions != nil && linux.MountExtensions.IDMap != nil && linux.MountExtensions.IDMap.Enabled != nil && f.IDMappedMounts != FeatureSupported {
+			f.IDMappedMounts = featureSupport(*linux.MountExtensions.IDMap.Enabled)
+		}
+	}
+	for _, annotation := range checkpointAnnotations {
+		if value, ok := features.Annotations[annotation]; ok && f.CRIU != FeatureSupported {
+			f.CRIU = featureSupport(value == "true")
+		}
+	}
+	return f, nil
+}
+
+// runtimeFeaturesFromVersion guesses the features of an OCI runtime that has
+// no features subcommand from the output of its --version. The features of
+// other runtimes than runc and crun, or of versions that can not be parsed, are
+// unknown.
+func runtimeFeaturesFromVersion(output string, criuFound bool) *RuntimeFeatures {
+	f := newRuntimeFeatures("version")
+	match := runtimeVersionRegexp.FindStringSubmatch(output)
+	if match == nil {
+		return f
+	}
+	version, err := semver.ParseTolerant(match[2])
+	if err != nil {
+		return f
+	}
+	atLeast := func(min string) FeatureSupport {
+		return featureSupport(version.GTE(semver.MustParse(min)))
+	}
+	switch match[1] {
+	case "runc":
+		f.CgroupV2 = atLeast("1.0.0")
+		f.IDMappedMounts = atLeast("1.2.0")
+		f.IntelRDT = atLeast("1.0.0-rc5")
+		f.CRIU = featureSupport(criuFound)
+	case "crun":
+		f.CgroupV2 = FeatureSupported
+		f.IDMappedMounts = atLeast("1.8.0")
+		f.CRIU = featureSupport(strings.Contains(output, "+CRIU"))
+	}
+	return f
+}
+
+// probeFeatures probes the OCI runtime binary at the given path for the
+// features it supports
+func probeFeatures(path string) (*RuntimeFeatures, error) {
+	output, err := exec.Command(path, "features").Output()
+	if err == nil {
+		return parseRuntimeFeatures(output)
+	}
+	logrus.Debugf("OCI runtime %s has no features subcommand, guessing features from its version: %v", path, err)
+
+	output, err = exec.Command(path, "--version").Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error probing features of OCI runtime %s", path)
+	}
+	_, criuErr := exec.LookPath("criu")
+	return runtimeFeaturesFromVersion(string(output), criuErr == nil), nil
+}
+
+// features returns the features of the OCI runtime binary at the given path,
+// probing it on first use
+func (r *OCIRuntime) features(path string) (*RuntimeFeatures, error) {
+	r.featuresLock.Lock()
+	defer r.featuresLock.Unlock()
+
+	if f, ok := r.featuresCache[path]; ok {
+		return f, nil
+	}
+	f, err := probeFeatures(path)
+	if err != nil {
+		return nil, err
+	}
+	if r.featuresCache == nil {
+		r.featuresCache = make(map[string]*RuntimeFeatures)
+	}
+	r.featuresCache[path] = f
+	return f, nil
+}
+
+// checkSpecFeatures verifies the OCI runtime supports what the spec of a
+// container requires, so it fails with a clear message instead of a runtime
+// error. Only the features known to be unsupported are refused.
+func checkSpecFeatures(spec *spec.Spec, f *RuntimeFeatures, cgroupV2 bool) error {
+	if cgroupV2 && f.CgroupV2 == FeatureUnsupported {
+		return errors.Wrapf(ErrRuntimeUnsupported, "the host uses cgroup v2, which the OCI runtime does not support")
+	}
+	if spec.Linux != nil && spec.Linux.IntelRdt != nil && f.IntelRDT == FeatureUnsupported {
+		return errors.Wrapf(ErrRuntimeUnsupported, "the OCI runtime does not support Intel RDT")
+	}
+	for _, mount := range spec.Mounts {
+		for _, opt := range mount.Options {
+			if opt == "idmap" && f.IDMappedMounts == FeatureUnsupported {
+				return errors.Wrapf(ErrRuntimeUnsupported, "the OCI runtime does not support idmapped mount %s", mount.Destination)
+			}
+		}
+	}
+	return nil
+}
+
+// checkFeatures verifies the OCI runtime of a container supports what its
+// spec requires
+func (r *OCIRuntime) checkFeatures(ctr *Container, spec *spec.Spec) error {
+	f, err := r.features(r.runtimePath(ctr))
+	if err != nil {
+		// Leave it to the runtime to fail if it can not be probed
+		logrus.Warnf("%v", err)
+		return nil
+	}
+	return checkSpecFeatures(spec, f, cgroups.IsCgroup2UnifiedMode())
+}
+
+// RuntimeFeatures returns the features the configured OCI runtime supports
+func (r *Runtime) RuntimeFeatures() (*RuntimeFeatures, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+
+	return r.ociRuntime.features(r.ociRuntime.path)
+}
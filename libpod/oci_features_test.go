@@ -0,0 +1,62 @@
+package libpod
+
+import (
+	"testing"
+
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRuntimeFeatures(t *testing.T) {
+	f, err := parseRuntimeFeatures([]byte(`{
+		"ociVersionMin": "1.0.0",
+		"mountOptions": ["ro", "rw", "idmap"],
+		"linux": {
+			"cgroup": {"v1": true, "v2": true},
+			"intelRdt": {"enabled": false}
+		},
+		"annotations": {"org.opencontainers.runc.checkpoint.enabled": "true"}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &RuntimeFeatures{Probed: "features", IDMappedMounts: FeatureSupported, CgroupV2: FeatureSupported, CRIU: FeatureSupported, IntelRDT: FeatureUnsupported}, f)
+
+	// Features the runtime does not report are unknown
+	f, err = parseRuntimeFeatures([]byte(`{"ociVersionMin": "1.0.0", "mountOptions": ["ro", "rw"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &RuntimeFeatures{Probed: "features", IDMappedMounts: FeatureUnsupported, CgroupV2: FeatureUnknown, CRIU: FeatureUnknown, IntelRDT: FeatureUnknown}, f)
+
+	_, err = parseRuntimeFeatures([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestRuntimeFeaturesFromVersion(t *testing.T) {
+	f := runtimeFeaturesFromVersion("runc version 1.0.0-rc5\nspec: 1.0.0\n", true)
+	assert.Equal(t, &RuntimeFeatures{Probed: "version", IDMappedMounts: FeatureUnsupported, CgroupV2: FeatureUnsupported, IntelRDT: FeatureSupported, CRIU: FeatureSupported}, f)
+
+	f = runtimeFeaturesFromVersion("crun version 1.8.1\ncommit: abc\n+SYSTEMD +SELINUX +CRIU +YAJL\n", true)
+	assert.Equal(t, &RuntimeFeatures{Probed: "version", IDMappedMounts: FeatureSupported, CgroupV2: FeatureSupported, CRIU: FeatureSupported, IntelRDT: FeatureUnknown}, f)
+
+	unknown := newRuntimeFeatures("version")
+	assert.Equal(t, unknown, runtimeFeaturesFromVersion("unknown output", true))
+	assert.Equal(t, unknown, runtimeFeaturesFromVersion("runsc version release-20190806.1\nspec: 1.0.1-dev\n", true))
+	assert.Equal(t, unknown, runtimeFeaturesFromVersion("kata-runtime  : 1.8.0\n   commit   : 3a4b5c\n", true))
+}
+
+func TestCheckSpecFeatures(t *testing.T) {
+	s := &spec.Spec{
+		Linux:  &spec.Linux{IntelRdt: &spec.LinuxIntelRdt{L3CacheSchema: "L3:0=ff"}},
+		Mounts: []spec.Mount{{Destination: "/data", Options: []string{"rbind", "idmap"}}},
+	}
+	all := &RuntimeFeatures{IDMappedMounts: FeatureSupported, CgroupV2: FeatureSupported, IntelRDT: FeatureSupported}
+	assert.NoError(t, checkSpecFeatures(s, all, true))
+	// Unknown features are left to the runtime
+	assert.NoError(t, checkSpecFeatures(s, newRuntimeFeatures("version"), true))
+
+	err := checkSpecFeatures(s, &RuntimeFeatures{IDMappedMounts: FeatureSupported, CgroupV2: FeatureSupported, IntelRDT: FeatureUnsupported}, true)
+	assert.Equal(t, ErrRuntimeUnsupported, errors.Cause(err))
+	err = checkSpecFeatures(s, &RuntimeFeatures{IntelRDT: FeatureSupported, IDMappedMounts: FeatureUnsupported}, false)
+	assert.Equal(t, ErrRuntimeUnsupported, errors.Cause(err))
+	err = checkSpecFeatures(&spec.Spec{}, &RuntimeFeatures{CgroupV2: FeatureUnsupported}, true)
+	assert.Equal(t, ErrRuntimeUnsupported, errors.Cause(err))
+}
@@ -4,17 +4,22 @@ package libpod
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/containerd/cgroups"
+	cg "github.com/containers/libpod/pkg/cgroups"
 	"github.com/containers/libpod/utils"
 	"github.com/containers/storage/pkg/idtools"
+	"github.com/docker/docker/pkg/parsers"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
 )
@@ -51,6 +56,75 @@ func (r *OCIRuntime) moveConmonToCgroup(ctr *Container, cgroupParent string, cmd
 	return nil
 }
 
+// cpusetBitmask converts a cpuset list, such as 0-3,8, to the bitmask systemd
+// takes for AllowedCPUs and AllowedMemoryNodes
+func cpusetBitmask(list string) ([]byte, error) {
+	if list == "" {
+		return nil, nil
+	}
+	ids, err := parsers.ParseUintList(list)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid cpuset %q", list)
+	}
+	var mask []byte
+	for id := range ids {
+		for len(mask) <= id/8 {
+			mask = append(mask, 0)
+		}
+		mask[id/8] |= 1 << uint(id%8)
+	}
+	return mask, nil
+}
+
+// setSystemdCpuset sets the AllowedCPUs and AllowedMemoryNodes of the systemd
+// scope of a container to its cpuset, so systemd does not reset it
+func (r *OCIRuntime) setSystemdCpuset(ctr *Container) error {
+	if r.cgroupManager != SystemdCgroupsManager || os.Getuid() != 0 {
+		return nil
+	}
+	cpus, mems := ctr.cpuset()
+	cpusMask, err := cpusetBitmask(cpus)
+	if err != nil {
+		return err
+	}
+	memsMask, err := cpusetBitmask(mems)
+	if err != nil {
+		return err
+	}
+	return utils.SetSystemdUnitCpuset(createUnitName("libpod", ctr.ID()), cpusMask, memsMask)
+}
+
+// cgroupOOMKilled returns whether the kernel OOM killer killed a process in
+// the memory cgroup of the container, which the cgroup v2 memory.events file
+// and the cgroup v1 memory.oom_control file both report as oom_kill.
+func cgroupOOMKilled(ctr *Container) bool {
+	cgroupPath, err := ctr.CGroupPath()
+	if err != nil {
+		return false
+	}
+	file := filepath.Join(cg.V1Dir("memory", cgroupPath), "memory.oom_control")
+	if cg.IsCgroup2UnifiedMode() {
+		file = filepath.Join(cg.V2Dir(cgroupPath), "memory.events")
+	}
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "oom_kill" {
+			continue
+		}
+		kills, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			logrus.Debugf("Error parsing %s of container %s: %v", file, ctr.ID(), err)
+			return false
+		}
+		return kills > 0
+	}
+	return false
+}
+
 // newPipe creates a unix socket pair for communication
 func newPipe() (parent *os.File, child *os.File, err error) {
 	fds, err := unix.Socketpair(unix.AF_LOCAL, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
@@ -0,0 +1,22 @@
+// +build linux
+
+package libpod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCpusetBitmask(t *testing.T) {
+	mask, err := cpusetBitmask("0-3,8,15")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0f, 0x81}, mask)
+
+	mask, err = cpusetBitmask("")
+	assert.NoError(t, err)
+	assert.Nil(t, mask)
+
+	_, err = cpusetBitmask("a-b")
+	assert.Error(t, err)
+}
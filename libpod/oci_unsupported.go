@@ -11,6 +11,14 @@ func (r *OCIRuntime) moveConmonToCgroup(ctr *Container, cgroupParent string, cmd
 	return ErrOSNotSupported
 }
 
+func cgroupOOMKilled(ctr *Container) bool {
+	return false
+}
+
+func (r *OCIRuntime) setSystemdCpuset(ctr *Container) error {
+	return ErrOSNotSupported
+}
+
 func newPipe() (parent *os.File, child *os.File, err error) {
 	return nil, nil, ErrNotImplemented
 }
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"syscall"
 
+	"github.com/containers/image/manifest"
 	"github.com/containers/storage"
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/cri-o/ocicni/pkg/ocicni"
@@ -28,19 +29,19 @@ func WithStorageConfig(config storage.StoreOptions) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.StorageConfig.RunRoot = config.RunRoot
-		rt.config.StorageConfig.GraphRoot = config.GraphRoot
-		rt.config.StorageConfig.GraphDriverName = config.GraphDriverName
-		rt.config.StaticDir = filepath.Join(config.GraphRoot, "libpod")
+		rt.config().StorageConfig.RunRoot = config.RunRoot
+		rt.config().StorageConfig.GraphRoot = config.GraphRoot
+		rt.config().StorageConfig.GraphDriverName = config.GraphDriverName
+		rt.config().StaticDir = filepath.Join(config.GraphRoot, "libpod")
 
-		rt.config.StorageConfig.GraphDriverOptions = make([]string, len(config.GraphDriverOptions))
-		copy(rt.config.StorageConfig.GraphDriverOptions, config.GraphDriverOptions)
+		rt.config().StorageConfig.GraphDriverOptions = make([]string, len(config.GraphDriverOptions))
+		copy(rt.config().StorageConfig.GraphDriverOptions, config.GraphDriverOptions)
 
-		rt.config.StorageConfig.UIDMap = make([]idtools.IDMap, len(config.UIDMap))
-		copy(rt.config.StorageConfig.UIDMap, config.UIDMap)
+		rt.config().StorageConfig.UIDMap = make([]idtools.IDMap, len(config.UIDMap))
+		copy(rt.config().StorageConfig.UIDMap, config.UIDMap)
 
-		rt.config.StorageConfig.GIDMap = make([]idtools.IDMap, len(config.GIDMap))
-		copy(rt.config.StorageConfig.GIDMap, config.GIDMap)
+		rt.config().StorageConfig.GIDMap = make([]idtools.IDMap, len(config.GIDMap))
+		copy(rt.config().StorageConfig.GIDMap, config.GIDMap)
 
 		return nil
 	}
@@ -53,7 +54,7 @@ func WithDefaultTransport(defaultTransport string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.ImageDefaultTransport = defaultTransport
+		rt.config().ImageDefaultTransport = defaultTransport
 
 		return nil
 	}
@@ -69,7 +70,7 @@ func WithSignaturePolicy(path string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.SignaturePolicyPath = path
+		rt.config().SignaturePolicyPath = path
 
 		return nil
 	}
@@ -89,7 +90,7 @@ func WithStateType(storeType RuntimeStateStore) RuntimeOption {
 			return errors.Wrapf(ErrInvalidArg, "must provide a valid state store type")
 		}
 
-		rt.config.StateType = storeType
+		rt.config().StateType = storeType
 
 		return nil
 	}
@@ -106,7 +107,7 @@ func WithOCIRuntime(runtimePath string) RuntimeOption {
 			return errors.Wrapf(ErrInvalidArg, "must provide a valid path")
 		}
 
-		rt.config.RuntimePath = []string{runtimePath}
+		rt.config().RuntimePath = []string{runtimePath}
 
 		return nil
 	}
@@ -124,7 +125,7 @@ func WithConmonPath(path string) RuntimeOption {
 			return errors.Wrapf(ErrInvalidArg, "must provide a valid path")
 		}
 
-		rt.config.ConmonPath = []string{path}
+		rt.config().ConmonPath = []string{path}
 
 		return nil
 	}
@@ -137,8 +138,8 @@ func WithConmonEnv(environment []string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.ConmonEnvVars = make([]string, len(environment))
-		copy(rt.config.ConmonEnvVars, environment)
+		rt.config().ConmonEnvVars = make([]string, len(environment))
+		copy(rt.config().ConmonEnvVars, environment)
 
 		return nil
 	}
@@ -158,7 +159,7 @@ func WithCgroupManager(manager string) RuntimeOption {
 				CgroupfsCgroupsManager, SystemdCgroupsManager)
 		}
 
-		rt.config.CgroupManager = manager
+		rt.config().CgroupManager = manager
 
 		return nil
 	}
@@ -172,7 +173,7 @@ func WithStaticDir(dir string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.StaticDir = dir
+		rt.config().StaticDir = dir
 
 		return nil
 	}
@@ -191,8 +192,8 @@ func WithHooksDir(hooksDir string, dirNotExistFatal bool) RuntimeOption {
 			return errors.Wrap(ErrInvalidArg, "empty-string hook directories are not supported")
 		}
 
-		rt.config.HooksDir = hooksDir
-		rt.config.HooksDirNotExistFatal = dirNotExistFatal
+		rt.config().HooksDir = hooksDir
+		rt.config().HooksDirNotExistFatal = dirNotExistFatal
 		return nil
 	}
 }
@@ -210,7 +211,7 @@ func WithDefaultMountsFile(mountsFile string) RuntimeOption {
 		if mountsFile == "" {
 			return ErrInvalidArg
 		}
-		rt.config.DefaultMountsFile = mountsFile
+		rt.config().DefaultMountsFile = mountsFile
 		return nil
 	}
 }
@@ -224,7 +225,7 @@ func WithTmpDir(dir string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.TmpDir = dir
+		rt.config().TmpDir = dir
 
 		return nil
 	}
@@ -238,7 +239,7 @@ func WithMaxLogSize(limit int64) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.MaxLogSize = limit
+		rt.config().MaxLogSize = limit
 
 		return nil
 	}
@@ -252,7 +253,7 @@ func WithNoPivotRoot(noPivot bool) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.NoPivotRoot = true
+		rt.config().NoPivotRoot = true
 
 		return nil
 	}
@@ -265,7 +266,7 @@ func WithCNIConfigDir(dir string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.CNIConfigDir = dir
+		rt.config().CNIConfigDir = dir
 
 		return nil
 	}
@@ -278,7 +279,7 @@ func WithCNIPluginDir(dir string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.CNIPluginDir = []string{dir}
+		rt.config().CNIPluginDir = []string{dir}
 
 		return nil
 	}
@@ -298,7 +299,35 @@ func WithNamespace(ns string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.Namespace = ns
+		rt.config().Namespace = ns
+
+		return nil
+	}
+}
+
+// WithReadOnly puts the runtime in read-only mode, where all operations
+// modifying containers, pods and images are rejected.
+func WithReadOnly() RuntimeOption {
+	return func(rt *Runtime) error {
+		if rt.valid {
+			return ErrRuntimeFinalized
+		}
+
+		rt.config().ReadOnly = true
+
+		return nil
+	}
+}
+
+// WithNameGenerator sets the generator of the names of the containers and pods
+// created without one, in place of the name_generator of the configuration.
+func WithNameGenerator(generator NameGenerator) RuntimeOption {
+	return func(rt *Runtime) error {
+		if rt.valid {
+			return ErrRuntimeFinalized
+		}
+
+		rt.nameGenerator = generator
 
 		return nil
 	}
@@ -315,7 +344,7 @@ func WithDefaultInfraImage(img string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.InfraImage = img
+		rt.config().InfraImage = img
 
 		return nil
 	}
@@ -329,7 +358,7 @@ func WithDefaultInfraCommand(cmd string) RuntimeOption {
 			return ErrRuntimeFinalized
 		}
 
-		rt.config.InfraCommand = cmd
+		rt.config().InfraCommand = cmd
 
 		return nil
 	}
@@ -492,6 +521,25 @@ func WithName(name string) CtrCreateOption {
 	}
 }
 
+// WithNameReservation sets the container's name to a name reserved with
+// Runtime.ReserveName, claiming the reservation with its token as the container
+// is created.
+func WithNameReservation(name, token string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if err := WithName(name)(ctr); err != nil {
+			return err
+		}
+
+		if token == "" {
+			return errors.Wrapf(ErrInvalidArg, "the token of the reservation of name %s is required", name)
+		}
+
+		ctr.nameReservation = token
+
+		return nil
+	}
+}
+
 // WithStopSignal sets the signal that will be sent to stop the container.
 func WithStopSignal(signal syscall.Signal) CtrCreateOption {
 	return func(ctr *Container) error {
@@ -525,6 +573,74 @@ func WithStopTimeout(timeout uint) CtrCreateOption {
 	}
 }
 
+// WithRestartPolicy sets the policy restarting the container when it exits,
+// one of the RestartPolicy constants.
+func WithRestartPolicy(policy string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		switch policy {
+		case RestartPolicyNone, RestartPolicyNo, RestartPolicyOnFailure, RestartPolicyAlways, RestartPolicyUnlessStopped:
+			ctr.config.RestartPolicy = policy
+		default:
+			return errors.Wrapf(ErrInvalidArg, "%q is not a valid restart policy", policy)
+		}
+
+		return nil
+	}
+}
+
+// WithInitContainerType makes the container an init container of its pod,
+// run to completion before the other containers of the pod are started, of
+// one of the InitContainer types.
+func WithInitContainerType(initType string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		switch initType {
+		case AlwaysInitContainer, OnceInitContainer:
+			ctr.config.InitContainerType = initType
+		default:
+			return errors.Wrapf(ErrInvalidArg, "%q is not a valid init container type", initType)
+		}
+
+		return nil
+	}
+}
+
+// WithRestartRetries sets the number of times the container is restarted by
+// the on-failure restart policy. If 0, it is restarted indefinitely.
+func WithRestartRetries(tries uint) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		ctr.config.RestartRetries = tries
+
+		return nil
+	}
+}
+
+// WithResourceWaitTimeout sets how long, in seconds, the container waits
+// when it starts for the external resources it depends on, its CNI networks,
+// to be available, instead of failing at once.
+func WithResourceWaitTimeout(timeout uint) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		ctr.config.ResourceWaitTimeout = timeout
+
+		return nil
+	}
+}
+
 // WithIDMappings sets the idmappsings for the container
 func WithIDMappings(idmappings storage.IDMappingOptions) CtrCreateOption {
 	return func(ctr *Container) error {
@@ -549,6 +665,68 @@ func WithExitCommand(exitCommand []string) CtrCreateOption {
 	}
 }
 
+// WithRuntimeHandler selects the OCI runtime running the container instead of
+// the default one: WasmRuntimeHandler runs it with the first runtime of
+// wasm_runtime_path found, and KataRuntimeHandler with the first runtime of
+// kata_runtime_path found.
+// Containers joining a pod of a runtime class must have its handler.
+func WithRuntimeHandler(handler string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		if err := validRuntimeHandler(handler); err != nil {
+			return err
+		}
+
+		ctr.config.RuntimeHandler = handler
+		return nil
+	}
+}
+
+// WithTimezone sets the timezone of the container, a zone of the zoneinfo
+// database of the host or LocalTimezone for the timezone of the host. It is
+// mounted as the /etc/localtime of the container.
+func WithTimezone(tz string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		if _, err := timezoneFile(tz); err != nil {
+			return err
+		}
+
+		ctr.config.Timezone = tz
+		return nil
+	}
+}
+
+// validRuntimeHandler returns an error if the handler is not a known runtime
+// handler
+func validRuntimeHandler(handler string) error {
+	switch handler {
+	case WasmRuntimeHandler, KataRuntimeHandler:
+		return nil
+	}
+	return errors.Wrapf(ErrInvalidArg, "unknown runtime handler %q", handler)
+}
+
+// validLogDriver returns the log driver recorded for driver, or an error if
+// it is not supported. conmon reads the output of containers and takes only
+// the path of a file to write it to with -l: the conmon podman runs has no
+// journald output, so journald is not supported either.
+func validLogDriver(driver string) (string, error) {
+	switch driver {
+	case KubernetesLogDriver, JSONLogDriver:
+		return KubernetesLogDriver, nil
+	case "journald":
+		return "", errors.Wrapf(ErrInvalidArg, "the journald log driver is not supported, conmon only writes the output of containers to a file")
+	}
+	return "", errors.Wrapf(ErrInvalidArg, "unsupported log driver %q, only %s and %s are supported", driver, KubernetesLogDriver, JSONLogDriver)
+}
+
 // WithIPCNSFromPod indicates the the container should join the IPC namespace of
 // its pod
 func WithIPCNSFromPod(p *Pod) CtrCreateOption {
@@ -781,6 +959,10 @@ func WithNetNSFrom(nsCtr *Container) CtrCreateOption {
 			return errors.Wrapf(ErrInvalidArg, "cannot join another container's net ns as we are making a new net ns")
 		}
 
+		if ctr.config.NetNsPath != "" {
+			return errors.Wrapf(ErrInvalidArg, "cannot join another container's net ns as we are joining the net ns at %s", ctr.config.NetNsPath)
+		}
+
 		if ctr.config.Pod != "" && nsCtr.config.Pod != ctr.config.Pod {
 			return errors.Wrapf(ErrInvalidArg, "container has joined pod %s and dependency container %s is not a member of the pod", ctr.config.Pod, nsCtr.ID())
 		}
@@ -949,6 +1131,10 @@ func WithNetNS(portMappings []ocicni.PortMapping, postConfigureNetNS bool, netwo
 			return errors.Wrapf(ErrInvalidArg, "container is already set to join another container's net ns, cannot create a new net ns")
 		}
 
+		if ctr.config.NetNsPath != "" {
+			return errors.Wrapf(ErrInvalidArg, "container is already set to join the net ns at %s, cannot create a new net ns", ctr.config.NetNsPath)
+		}
+
 		ctr.config.PostConfigureNetNS = postConfigureNetNS
 		ctr.config.CreateNetNS = true
 		ctr.config.PortMappings = portMappings
@@ -958,6 +1144,78 @@ func WithNetNS(portMappings []ocicni.PortMapping, postConfigureNetNS bool, netwo
 	}
 }
 
+// WithNetNSPath indicates that the container should join the existing network
+// namespace at the given path, created by an external network manager.
+// Libpod does not configure networking in the namespace, but records the
+// addresses found in it when the container starts.
+// Conflicts with WithNetNS() and WithNetNSFrom().
+func WithNetNSPath(path string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		if !filepath.IsAbs(path) {
+			return errors.Wrapf(ErrInvalidArg, "network namespace path %q must be absolute", path)
+		}
+
+		if ctr.config.CreateNetNS {
+			return errors.Wrapf(ErrInvalidArg, "container is already set to create a new net ns, cannot join the net ns at %s", path)
+		}
+
+		if ctr.config.NetNsCtr != "" {
+			return errors.Wrapf(ErrInvalidArg, "container is already set to join another container's net ns, cannot join the net ns at %s", path)
+		}
+
+		ctr.config.NetNsPath = path
+
+		return nil
+	}
+}
+
+// WithNetworkOptions sets the options of the tools configuring the network
+// namespace of the container, by tool. Only the options of slirp4netns, with
+// the key Slirp4netnsNetworkOptions, are supported, and only by rootless
+// containers that create a network namespace.
+func WithNetworkOptions(options map[string][]string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		for tool, opts := range options {
+			if tool != Slirp4netnsNetworkOptions {
+				return errors.Wrapf(ErrInvalidArg, "unknown network tool %q", tool)
+			}
+			if _, err := parseSlirp4netnsOptions(opts); err != nil {
+				return err
+			}
+		}
+
+		ctr.config.NetworkOptions = options
+
+		return nil
+	}
+}
+
+// WithLogDriver sets the log driver of the container. Only the file log
+// driver, KubernetesLogDriver or JSONLogDriver, is supported.
+func WithLogDriver(driver string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+		driver, err := validLogDriver(driver)
+		if err != nil {
+			return err
+		}
+
+		ctr.config.LogDriver = driver
+
+		return nil
+	}
+}
+
 // WithLogPath sets the path to the log file.
 func WithLogPath(path string) CtrCreateOption {
 	return func(ctr *Container) error {
@@ -974,6 +1232,24 @@ func WithLogPath(path string) CtrCreateOption {
 	}
 }
 
+// WithLogRotation sets the size at which the log file of the container is
+// rotated, and the number of log files kept, the log file included.
+func WithLogRotation(maxSize int64, maxFiles uint) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+		if maxSize < 0 {
+			return errors.Wrapf(ErrInvalidArg, "log max size must not be negative")
+		}
+
+		ctr.config.LogMaxSize = maxSize
+		ctr.config.LogMaxFiles = maxFiles
+
+		return nil
+	}
+}
+
 // WithCgroupParent sets the Cgroup Parent of the new container.
 func WithCgroupParent(parent string) CtrCreateOption {
 	return func(ctr *Container) error {
@@ -1055,6 +1331,50 @@ func WithConmonPidFile(path string) CtrCreateOption {
 	}
 }
 
+// WithCreateCommand records the command line the container is created with.
+func WithCreateCommand(cmd []string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+		ctr.config.CreateCommand = cmd
+		return nil
+	}
+}
+
+// WithHealthCheck adds a healthcheck to the container, run while it runs.
+// The test of the healthcheck must be a CMD or CMD-SHELL one.
+func WithHealthCheck(healthCheck *manifest.Schema2HealthConfig) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+		if _, err := healthCheckCommand(healthCheck.Test); err != nil {
+			return err
+		}
+		ctr.config.HealthCheckConfig = healthCheck
+		return nil
+	}
+}
+
+// WithStartupHealthCheck adds a startup healthcheck to the container, run
+// after it starts until it passes once. The regular healthchecks of the
+// container begin, and it counts as started for the containers depending on
+// it, only once it passed. A retries of 0 retries it forever, otherwise the
+// container is restarted after retries consecutive failures.
+func WithStartupHealthCheck(healthCheck *manifest.Schema2HealthConfig) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+		if _, err := healthCheckCommand(healthCheck.Test); err != nil {
+			return err
+		}
+		ctr.config.StartupHealthCheckConfig = healthCheck
+		return nil
+	}
+}
+
 // WithGroups sets additional groups for the container, which are defined by
 // the user.
 func WithGroups(groups []string) CtrCreateOption {
@@ -1218,6 +1538,25 @@ func WithPodName(name string) PodCreateOption {
 	}
 }
 
+// WithPodNameReservation sets the name of the pod to a name reserved with
+// Runtime.ReserveName, claiming the reservation with its token as the pod is
+// created.
+func WithPodNameReservation(name, token string) PodCreateOption {
+	return func(pod *Pod) error {
+		if err := WithPodName(name)(pod); err != nil {
+			return err
+		}
+
+		if token == "" {
+			return errors.Wrapf(ErrInvalidArg, "the token of the reservation of name %s is required", name)
+		}
+
+		pod.nameReservation = token
+
+		return nil
+	}
+}
+
 // WithPodLabels sets the labels of a pod.
 func WithPodLabels(labels map[string]string) PodCreateOption {
 	return func(pod *Pod) error {
@@ -1263,6 +1602,46 @@ func WithPodCgroups() PodCreateOption {
 	}
 }
 
+// WithPodMemoryLimit sets the memory limit in bytes of the pod cgroup, shared
+// by all the containers of the pod, which requires pod cgroups.
+func WithPodMemoryLimit(limit int64) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		if limit < 0 {
+			return errors.Wrapf(ErrInvalidArg, "memory limit must not be negative")
+		}
+		pod.config.MemoryLimit = limit
+
+		return nil
+	}
+}
+
+// WithPodCPUQuota limits the CPU time of the pod cgroup, shared by all the
+// containers of the pod, to quota microseconds every period microseconds,
+// which requires pod cgroups.
+func WithPodCPUQuota(quota int64, period uint64) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		// The bounds of the kernel
+		if quota < 0 || (quota != 0 && quota < 1000) {
+			return errors.Wrapf(ErrInvalidArg, "CPU quota must be at least 1000 microseconds")
+		}
+		if quota != 0 && (period < 1000 || period > 1000000) {
+			return errors.Wrapf(ErrInvalidArg, "CPU period must be between 1000 and 1000000 microseconds")
+		}
+		pod.config.CPUQuota = quota
+		pod.config.CPUPeriod = period
+
+		return nil
+	}
+}
+
 // WithPodNamespace sets the namespace for the created pod.
 // Namespaces are used to create separate views of Podman's state - runtimes can
 // join a specific namespace and see only containers and pods in that namespace.
@@ -1392,3 +1771,119 @@ func WithInfraContainer() PodCreateOption {
 		return nil
 	}
 }
+
+// WithInfraImage sets the image of the infra container of the pod, instead of
+// the infra_image of libpod.conf
+func WithInfraImage(image string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		pod.config.InfraContainer.Image = image
+
+		return nil
+	}
+}
+
+// WithInfraCommand sets the command of the infra container of the pod, instead
+// of the infra_command of libpod.conf
+func WithInfraCommand(command []string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		pod.config.InfraContainer.Command = command
+
+		return nil
+	}
+}
+
+// WithInfraCapAdd adds capabilities to the infra container of the pod
+func WithInfraCapAdd(capabilities []string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		pod.config.InfraContainer.CapAdd = capabilities
+
+		return nil
+	}
+}
+
+// WithInfraSysctls sets sysctls in the infra container of the pod, such as the
+// network sysctls of the network namespace the pod shares
+func WithInfraSysctls(sysctls map[string]string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		pod.config.InfraContainer.Sysctls = sysctls
+
+		return nil
+	}
+}
+
+// WithInfraDNS sets the name servers of the infra container of the pod, used
+// by the containers sharing its network namespace without their own
+func WithInfraDNS(dnsServers []string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		for _, server := range dnsServers {
+			if net.ParseIP(server) == nil {
+				return errors.Wrapf(ErrInvalidArg, "invalid IP address %s", server)
+			}
+		}
+		pod.config.InfraContainer.DNSServer = dnsServers
+
+		return nil
+	}
+}
+
+// WithInfraDNSSearch sets the search domains of the infra container of the
+// pod, used by the containers sharing its network namespace without their own
+func WithInfraDNSSearch(searchDomains []string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		pod.config.InfraContainer.DNSSearch = searchDomains
+
+		return nil
+	}
+}
+
+// WithInfraDNSOption sets the resolver options of the infra container of the
+// pod, used by the containers sharing its network namespace without their own
+func WithInfraDNSOption(dnsOptions []string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		pod.config.InfraContainer.DNSOption = dnsOptions
+
+		return nil
+	}
+}
+
+// WithInfraContainerPorts sets the ports the infra container of the pod
+// forwards from the host, to the containers sharing its network namespace
+func WithInfraContainerPorts(bindings []ocicni.PortMapping) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return ErrPodFinalized
+		}
+
+		pod.config.InfraContainer.PortBindings = bindings
+
+		return nil
+	}
+}
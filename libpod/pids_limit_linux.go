@@ -0,0 +1,132 @@
+// +build linux
+
+package libpod
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/libpod/libpod/events"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// pidsLimitRescanInterval is the interval at which WatchPidsLimits looks for
+// the containers started and stopped since
+const pidsLimitRescanInterval = 10 * time.Second
+
+// checkPidsLimit reports, with a warning and a pids-limit event, that the
+// container failed to create processes because of its PIDs limit since it
+// was last checked. The count of hits of the limit of pids.events last
+// reported is kept in the state of the container, so that hits are reported
+// once, whichever process reads them. The container must be locked.
+func (c *Container) checkPidsLimit(pids *PidsStats) error {
+	if pids.LimitHits <= c.state.PidsLimitHits || c.runtime.config().ReadOnly {
+		return nil
+	}
+	logrus.Warnf("Container %s reached its limit of %d processes, new processes could not be created", c.ID(), pids.Limit)
+	c.newContainerEvent(events.PidsLimit)
+	c.state.PidsLimitHits = pids.LimitHits
+	return c.save()
+}
+
+// checkRunningPidsLimit checks the PIDs limit of the container if it is
+// running
+func (c *Container) checkRunningPidsLimit() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.syncContainer(); err != nil {
+		return err
+	}
+	if c.state.State != ContainerStateRunning {
+		return nil
+	}
+	cgroupPath, err := c.CGroupPath()
+	if err != nil {
+		return err
+	}
+	pids, err := cgroupPids(cgroupPath)
+	if err != nil {
+		return err
+	}
+	return c.checkPidsLimit(pids)
+}
+
+// WatchPidsLimits watches the pids.events files of the cgroups of the running
+// containers, and reports the containers failing to create processes because
+// of their PIDs limit as soon as they do, until the context is done
+func (r *Runtime) WatchPidsLimits(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrapf(err, "error creating pids.events watcher")
+	}
+	defer watcher.Close()
+
+	// The containers watched, by path of their pids.events file
+	watched := make(map[string]string)
+	rescan := func() {
+		ctrs, err := r.GetRunningContainers()
+		if err != nil {
+			logrus.Errorf("Unable to watch the PIDs limits of containers: %v", err)
+			return
+		}
+		running := make(map[string]bool, len(ctrs))
+		for _, ctr := range ctrs {
+			cgroupPath, err := ctr.CGroupPath()
+			if err != nil {
+				continue
+			}
+			path := filepath.Join(cgroupPidsDir(cgroupPath), "pids.events")
+			running[path] = true
+			if _, ok := watched[path]; ok {
+				continue
+			}
+			// Kernels before 4.3 have no pids.events
+			if err := watcher.Add(path); err != nil {
+				logrus.Debugf("Unable to watch %s: %v", path, err)
+				continue
+			}
+			watched[path] = ctr.ID()
+			// Hits before the watch are reported at once
+			if err := ctr.checkRunningPidsLimit(); err != nil {
+				logrus.Debugf("Error checking the PIDs limit of container %s: %v", ctr.ID(), err)
+			}
+		}
+		for path := range watched {
+			if !running[path] {
+				// The cgroup may be removed already
+				_ = watcher.Remove(path)
+				delete(watched, path)
+			}
+		}
+	}
+
+	rescan()
+	ticker := time.NewTicker(pidsLimitRescanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event := <-watcher.Events:
+			id, ok := watched[event.Name]
+			if !ok || event.Op&fsnotify.Write == 0 {
+				continue
+			}
+			ctr, err := r.LookupContainer(id)
+			if err != nil {
+				continue
+			}
+			if err := ctr.checkRunningPidsLimit(); err != nil {
+				logrus.Debugf("Error checking the PIDs limit of container %s: %v", id, err)
+			}
+		case err := <-watcher.Errors:
+			logrus.Errorf("Error watching the PIDs limits of containers: %v", err)
+		case <-ticker.C:
+			rescan()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/containers/storage"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 )
 
@@ -26,6 +28,10 @@ type Pod struct {
 	valid   bool
 	runtime *Runtime
 	lock    storage.Locker
+
+	// nameReservation is the token of the reservation of the name of the
+	// pod, claimed as it is added to the state
+	nameReservation string
 }
 
 // PodConfig represents a pod's static configuration
@@ -45,6 +51,14 @@ type PodConfig struct {
 	// If true, all containers joined to the pod will use the pod cgroup as
 	// their cgroup parent, and cannot set a different cgroup parent
 	UsePodCgroup bool `json:"sharesCgroup,omitempty"`
+	// MemoryLimit is the memory limit of the pod cgroup in bytes, shared by
+	// all the containers of the pod. 0 is no limit
+	MemoryLimit int64 `json:"memoryLimit,omitempty"`
+	// CPUQuota and CPUPeriod limit the CPU time of the pod cgroup, shared
+	// by all the containers of the pod, to CPUQuota microseconds every
+	// CPUPeriod microseconds. A CPUQuota of 0 is no limit
+	CPUQuota  int64  `json:"cpuQuota,omitempty"`
+	CPUPeriod uint64 `json:"cpuPeriod,omitempty"`
 
 	// The following UsePod{kernelNamespace} indicate whether the containers
 	// in the pod will inherit the namespace from the first container in the pod.
@@ -85,6 +99,20 @@ type PodInspect struct {
 type PodInspectState struct {
 	CgroupPath       string `json:"cgroupPath"`
 	InfraContainerID string `json:"infraContainerID"`
+	// RuntimeClass is the runtime handler of the containers of the pod
+	RuntimeClass string `json:"runtimeClass,omitempty"`
+	// VMOverhead is the resources used by the VM of a pod of a
+	// VM-isolated runtime class in addition to its containers
+	VMOverhead *PodVMOverhead `json:"vmOverhead,omitempty"`
+}
+
+// PodVMOverhead is the resources used by the VM of a pod
+// easyjson:json
+type PodVMOverhead struct {
+	// Memory is the memory used by the VM in bytes
+	Memory int64 `json:"memory"`
+	// CPU is the number of CPUs used by the VM
+	CPU float64 `json:"cpu"`
 }
 
 // PodContainerInfo keeps information on a container in a pod
@@ -96,7 +124,23 @@ type PodContainerInfo struct {
 
 // InfraContainerConfig is the configuration for the pod's infra container
 type InfraContainerConfig struct {
-	HasInfraContainer bool `json:"makeInfraContainer"`
+	HasInfraContainer bool                 `json:"makeInfraContainer"`
+	PortBindings      []ocicni.PortMapping `json:"infraPortBindings"`
+	// Image and Command override the infra_image and infra_command of
+	// libpod.conf for the infra container of the pod
+	Image   string   `json:"infraImage,omitempty"`
+	Command []string `json:"infraCommand,omitempty"`
+	// CapAdd are the capabilities added to the infra container
+	CapAdd []string `json:"infraCapAdd,omitempty"`
+	// Sysctls are set in the infra container, which creates the kernel
+	// namespaces the pod shares
+	Sysctls map[string]string `json:"infraSysctls,omitempty"`
+	// DNSServer, DNSSearch and DNSOption are the DNS settings of the infra
+	// container, which the containers sharing its network namespace use
+	// unless they set their own
+	DNSServer []string `json:"infraDNSServer,omitempty"`
+	DNSSearch []string `json:"infraDNSSearch,omitempty"`
+	DNSOption []string `json:"infraDNSOption,omitempty"`
 }
 
 // ID retrieves the pod's ID
@@ -135,6 +179,56 @@ func (p *Pod) CgroupParent() string {
 	return p.config.CgroupParent
 }
 
+// MemoryLimit returns the memory limit of the pod cgroup in bytes, or 0 if it
+// has none
+func (p *Pod) MemoryLimit() int64 {
+	return p.config.MemoryLimit
+}
+
+// CPUQuota returns the CPU time the containers of the pod can use every
+// period, in microseconds, or a quota of 0 if it has no limit
+func (p *Pod) CPUQuota() (int64, uint64) {
+	return p.config.CPUQuota, p.config.CPUPeriod
+}
+
+// resources returns the resource limits of the pod cgroup, or nil if it has
+// none. The VM of a pod of a VM-isolated runtime class runs in the pod cgroup
+// with its containers, so its overhead is added to the limits.
+func (p *Pod) resources() *spec.LinuxResources {
+	if p.config.MemoryLimit == 0 && p.config.CPUQuota == 0 {
+		return nil
+	}
+	overhead := p.vmOverhead()
+	resources := &spec.LinuxResources{}
+	if p.config.MemoryLimit != 0 {
+		limit := p.config.MemoryLimit
+		if overhead != nil {
+			limit += overhead.Memory
+		}
+		resources.Memory = &spec.LinuxMemory{Limit: &limit}
+	}
+	if p.config.CPUQuota != 0 {
+		quota, period := p.config.CPUQuota, p.config.CPUPeriod
+		if overhead != nil {
+			quota += int64(overhead.CPU * float64(period))
+		}
+		resources.CPU = &spec.LinuxCPU{Quota: &quota, Period: &period}
+	}
+	return resources
+}
+
+// vmOverhead returns the resources used by the VM of the pod in addition to
+// its containers, or nil if its runtime class does not run it in a VM
+func (p *Pod) vmOverhead() *PodVMOverhead {
+	if p.config.Labels[RuntimeClassLabel] != KataRuntimeHandler {
+		return nil
+	}
+	return &PodVMOverhead{
+		Memory: p.runtime.config().VMOverheadMemory,
+		CPU:    p.runtime.config().VMOverheadCPU,
+	}
+}
+
 // SharesPID returns whether containers in pod
 // default to use PID namespace of first container in pod
 func (p *Pod) SharesPID() bool {
@@ -3,6 +3,7 @@ package libpod
 import (
 	"context"
 
+	"github.com/containers/libpod/libpod/events"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/ulule/deepcopier"
@@ -13,6 +14,9 @@ import (
 // If a container has already been initialized it will be started,
 // otherwise it will be initialized then started.
 // Containers that are already running or have been paused are ignored
+// The init containers of the pod are run to completion first, one after the
+// other, unless other containers are running already. If one fails, no other
+// container is started.
 // All containers are started independently, in order dictated by their
 // dependencies.
 // An error and a map[string]error are returned
@@ -23,6 +27,10 @@ import (
 // set to ErrCtrExists
 // If both error and the map are nil, all containers were started successfully
 func (p *Pod) Start(ctx context.Context) (map[string]error, error) {
+	if err := p.runtime.checkReadOnly(); err != nil {
+		return nil, err
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -35,6 +43,12 @@ func (p *Pod) Start(ctx context.Context) (map[string]error, error) {
 		return nil, err
 	}
 
+	// Run the init containers to completion before starting the others
+	allCtrs, err = p.startInitContainers(ctx, allCtrs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build a dependency graph of containers in the pod
 	graph, err := buildContainerGraph(allCtrs)
 	if err != nil {
@@ -59,6 +73,7 @@ func (p *Pod) Start(ctx context.Context) (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error starting some containers")
 	}
 
+	p.newPodEvent(events.Start)
 	return nil, nil
 }
 
@@ -78,6 +93,10 @@ func (p *Pod) Start(ctx context.Context) (map[string]error, error) {
 // set to ErrCtrExists
 // If both error and the map are nil, all containers were stopped without error
 func (p *Pod) Stop(cleanup bool) (map[string]error, error) {
+	if err := p.runtime.checkReadOnly(); err != nil {
+		return nil, err
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -130,6 +149,7 @@ func (p *Pod) Stop(cleanup bool) (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error stopping some containers")
 	}
 
+	p.newPodEvent(events.Stop)
 	return nil, nil
 }
 
@@ -146,6 +166,10 @@ func (p *Pod) Stop(cleanup bool) (map[string]error, error) {
 // set to ErrCtrExists
 // If both error and the map are nil, all containers were paused without error
 func (p *Pod) Pause() (map[string]error, error) {
+	if err := p.runtime.checkReadOnly(); err != nil {
+		return nil, err
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -189,6 +213,7 @@ func (p *Pod) Pause() (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error pausing some containers")
 	}
 
+	p.newPodEvent(events.Pause)
 	return nil, nil
 }
 
@@ -205,6 +230,10 @@ func (p *Pod) Pause() (map[string]error, error) {
 // set to ErrCtrExists
 // If both error and the map are nil, all containers were unpaused without error
 func (p *Pod) Unpause() (map[string]error, error) {
+	if err := p.runtime.checkReadOnly(); err != nil {
+		return nil, err
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -248,10 +277,12 @@ func (p *Pod) Unpause() (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error unpausing some containers")
 	}
 
+	p.newPodEvent(events.Unpause)
 	return nil, nil
 }
 
 // Restart restarts all containers within a pod that are not paused or in an error state.
+// Init containers are not run again.
 // It combines the effects of Stop() and Start() on a container
 // Each container will use its own stop timeout.
 // All containers are started independently, in order dictated by their
@@ -265,6 +296,10 @@ func (p *Pod) Unpause() (map[string]error, error) {
 // set to ErrCtrExists
 // If both error and the map are nil, all containers were restarted without error
 func (p *Pod) Restart(ctx context.Context) (map[string]error, error) {
+	if err := p.runtime.checkReadOnly(); err != nil {
+		return nil, err
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -277,6 +312,9 @@ func (p *Pod) Restart(ctx context.Context) (map[string]error, error) {
 		return nil, err
 	}
 
+	// Init containers only run when the pod is started
+	_, allCtrs = splitInitContainers(allCtrs)
+
 	// Build a dependency graph of containers in the pod
 	graph, err := buildContainerGraph(allCtrs)
 	if err != nil {
@@ -301,6 +339,7 @@ func (p *Pod) Restart(ctx context.Context) (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error stopping some containers")
 	}
 
+	p.newPodEvent(events.Restart)
 	return nil, nil
 }
 
@@ -316,6 +355,10 @@ func (p *Pod) Restart(ctx context.Context) (map[string]error, error) {
 // set to ErrCtrExists
 // If both error and the map are nil, all containers were signalled successfully
 func (p *Pod) Kill(signal uint) (map[string]error, error) {
+	if err := p.runtime.checkReadOnly(); err != nil {
+		return nil, err
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -359,6 +402,7 @@ func (p *Pod) Kill(signal uint) (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error killing some containers")
 	}
 
+	p.newPodEvent(events.Kill)
 	return nil, nil
 }
 
@@ -438,5 +482,9 @@ func (p *Pod) Inspect() (*PodInspect, error) {
 		},
 		Containers: podContainers,
 	}
+	if class := p.config.Labels[RuntimeClassLabel]; class != "" {
+		inspectData.State.RuntimeClass = class
+		inspectData.State.VMOverhead = p.vmOverhead()
+	}
 	return &inspectData, nil
 }
@@ -4,6 +4,7 @@ package libpod
 
 import (
 	json "encoding/json"
+	ocicni "github.com/cri-o/ocicni/pkg/ocicni"
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
@@ -100,7 +101,90 @@ func (v *podState) UnmarshalJSON(data []byte) error {
 func (v *podState) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonBe091417DecodeGithubComContainersLibpodLibpod(l, v)
 }
-func easyjsonBe091417DecodeGithubComContainersLibpodLibpod1(in *jlexer.Lexer, out *PodInspectState) {
+func easyjsonBe091417DecodeGithubComContainersLibpodLibpod1(in *jlexer.Lexer, out *PodVMOverhead) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "memory":
+			out.Memory = int64(in.Int64())
+		case "cpu":
+			out.CPU = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBe091417EncodeGithubComContainersLibpodLibpod1(out *jwriter.Writer, in PodVMOverhead) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"memory\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Memory))
+	}
+	{
+		const prefix string = ",\"cpu\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(in.CPU))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PodVMOverhead) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PodVMOverhead) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PodVMOverhead) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PodVMOverhead) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod1(l, v)
+}
+func easyjsonBe091417DecodeGithubComContainersLibpodLibpod2(in *jlexer.Lexer, out *PodInspectState) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -123,6 +207,18 @@ func easyjsonBe091417DecodeGithubComContainersLibpodLibpod1(in *jlexer.Lexer, ou
 			out.CgroupPath = string(in.String())
 		case "infraContainerID":
 			out.InfraContainerID = string(in.String())
+		case "runtimeClass":
+			out.RuntimeClass = string(in.String())
+		case "vmOverhead":
+			if in.IsNull() {
+				in.Skip()
+				out.VMOverhead = nil
+			} else {
+				if out.VMOverhead == nil {
+					out.VMOverhead = new(PodVMOverhead)
+				}
+				(*out.VMOverhead).UnmarshalEasyJSON(in)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -133,7 +229,7 @@ func easyjsonBe091417DecodeGithubComContainersLibpodLibpod1(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonBe091417EncodeGithubComContainersLibpodLibpod1(out *jwriter.Writer, in PodInspectState) {
+func easyjsonBe091417EncodeGithubComContainersLibpodLibpod2(out *jwriter.Writer, in PodInspectState) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -157,33 +253,53 @@ func easyjsonBe091417EncodeGithubComContainersLibpodLibpod1(out *jwriter.Writer,
 		}
 		out.String(string(in.InfraContainerID))
 	}
+	if in.RuntimeClass != "" {
+		const prefix string = ",\"runtimeClass\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.RuntimeClass))
+	}
+	if in.VMOverhead != nil {
+		const prefix string = ",\"vmOverhead\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.VMOverhead).MarshalEasyJSON(out)
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v PodInspectState) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonBe091417EncodeGithubComContainersLibpodLibpod1(&w, v)
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v PodInspectState) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonBe091417EncodeGithubComContainersLibpodLibpod1(w, v)
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *PodInspectState) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonBe091417DecodeGithubComContainersLibpodLibpod1(&r, v)
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *PodInspectState) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonBe091417DecodeGithubComContainersLibpodLibpod1(l, v)
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod2(l, v)
 }
-func easyjsonBe091417DecodeGithubComContainersLibpodLibpod2(in *jlexer.Lexer, out *PodInspect) {
+func easyjsonBe091417DecodeGithubComContainersLibpodLibpod3(in *jlexer.Lexer, out *PodInspect) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -261,7 +377,7 @@ func easyjsonBe091417DecodeGithubComContainersLibpodLibpod2(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonBe091417EncodeGithubComContainersLibpodLibpod2(out *jwriter.Writer, in PodInspect) {
+func easyjsonBe091417EncodeGithubComContainersLibpodLibpod3(out *jwriter.Writer, in PodInspect) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -320,27 +436,27 @@ func easyjsonBe091417EncodeGithubComContainersLibpodLibpod2(out *jwriter.Writer,
 // MarshalJSON supports json.Marshaler interface
 func (v PodInspect) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonBe091417EncodeGithubComContainersLibpodLibpod2(&w, v)
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v PodInspect) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonBe091417EncodeGithubComContainersLibpodLibpod2(w, v)
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *PodInspect) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonBe091417DecodeGithubComContainersLibpodLibpod2(&r, v)
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *PodInspect) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonBe091417DecodeGithubComContainersLibpodLibpod2(l, v)
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod3(l, v)
 }
-func easyjsonBe091417DecodeGithubComContainersLibpodLibpod3(in *jlexer.Lexer, out *PodContainerInfo) {
+func easyjsonBe091417DecodeGithubComContainersLibpodLibpod4(in *jlexer.Lexer, out *PodContainerInfo) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -373,7 +489,7 @@ func easyjsonBe091417DecodeGithubComContainersLibpodLibpod3(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonBe091417EncodeGithubComContainersLibpodLibpod3(out *jwriter.Writer, in PodContainerInfo) {
+func easyjsonBe091417EncodeGithubComContainersLibpodLibpod4(out *jwriter.Writer, in PodContainerInfo) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -403,27 +519,27 @@ func easyjsonBe091417EncodeGithubComContainersLibpodLibpod3(out *jwriter.Writer,
 // MarshalJSON supports json.Marshaler interface
 func (v PodContainerInfo) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonBe091417EncodeGithubComContainersLibpodLibpod3(&w, v)
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v PodContainerInfo) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonBe091417EncodeGithubComContainersLibpodLibpod3(w, v)
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *PodContainerInfo) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonBe091417DecodeGithubComContainersLibpodLibpod3(&r, v)
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *PodContainerInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonBe091417DecodeGithubComContainersLibpodLibpod3(l, v)
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod4(l, v)
 }
-func easyjsonBe091417DecodeGithubComContainersLibpodLibpod4(in *jlexer.Lexer, out *PodConfig) {
+func easyjsonBe091417DecodeGithubComContainersLibpodLibpod5(in *jlexer.Lexer, out *PodConfig) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -472,6 +588,12 @@ func easyjsonBe091417DecodeGithubComContainersLibpodLibpod4(in *jlexer.Lexer, ou
 			out.CgroupParent = string(in.String())
 		case "sharesCgroup":
 			out.UsePodCgroup = bool(in.Bool())
+		case "memoryLimit":
+			out.MemoryLimit = int64(in.Int64())
+		case "cpuQuota":
+			out.CPUQuota = int64(in.Int64())
+		case "cpuPeriod":
+			out.CPUPeriod = uint64(in.Uint64())
 		case "sharesPid":
 			out.UsePodPID = bool(in.Bool())
 		case "sharesIpc":
@@ -492,7 +614,7 @@ func easyjsonBe091417DecodeGithubComContainersLibpodLibpod4(in *jlexer.Lexer, ou
 				if out.InfraContainer == nil {
 					out.InfraContainer = new(InfraContainerConfig)
 				}
-				easyjsonBe091417DecodeGithubComContainersLibpodLibpod5(in, &*out.InfraContainer)
+				easyjsonBe091417DecodeGithubComContainersLibpodLibpod6(in, &*out.InfraContainer)
 			}
 		case "created":
 			if data := in.Raw(); in.Ok() {
@@ -508,7 +630,7 @@ func easyjsonBe091417DecodeGithubComContainersLibpodLibpod4(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonBe091417EncodeGithubComContainersLibpodLibpod4(out *jwriter.Writer, in PodConfig) {
+func easyjsonBe091417EncodeGithubComContainersLibpodLibpod5(out *jwriter.Writer, in PodConfig) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -588,6 +710,36 @@ func easyjsonBe091417EncodeGithubComContainersLibpodLibpod4(out *jwriter.Writer,
 		}
 		out.Bool(bool(in.UsePodCgroup))
 	}
+	if in.MemoryLimit != 0 {
+		const prefix string = ",\"memoryLimit\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.MemoryLimit))
+	}
+	if in.CPUQuota != 0 {
+		const prefix string = ",\"cpuQuota\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.CPUQuota))
+	}
+	if in.CPUPeriod != 0 {
+		const prefix string = ",\"cpuPeriod\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(in.CPUPeriod))
+	}
 	if in.UsePodPID {
 		const prefix string = ",\"sharesPid\":"
 		if first {
@@ -659,7 +811,7 @@ func easyjsonBe091417EncodeGithubComContainersLibpodLibpod4(out *jwriter.Writer,
 		if in.InfraContainer == nil {
 			out.RawString("null")
 		} else {
-			easyjsonBe091417EncodeGithubComContainersLibpodLibpod5(out, *in.InfraContainer)
+			easyjsonBe091417EncodeGithubComContainersLibpodLibpod6(out, *in.InfraContainer)
 		}
 	}
 	{
@@ -678,27 +830,27 @@ func easyjsonBe091417EncodeGithubComContainersLibpodLibpod4(out *jwriter.Writer,
 // MarshalJSON supports json.Marshaler interface
 func (v PodConfig) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonBe091417EncodeGithubComContainersLibpodLibpod4(&w, v)
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v PodConfig) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonBe091417EncodeGithubComContainersLibpodLibpod4(w, v)
+	easyjsonBe091417EncodeGithubComContainersLibpodLibpod5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *PodConfig) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonBe091417DecodeGithubComContainersLibpodLibpod4(&r, v)
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *PodConfig) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonBe091417DecodeGithubComContainersLibpodLibpod4(l, v)
+	easyjsonBe091417DecodeGithubComContainersLibpodLibpod5(l, v)
 }
-func easyjsonBe091417DecodeGithubComContainersLibpodLibpod5(in *jlexer.Lexer, out *InfraContainerConfig) {
+func easyjsonBe091417DecodeGithubComContainersLibpodLibpod6(in *jlexer.Lexer, out *InfraContainerConfig) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -719,6 +871,166 @@ func easyjsonBe091417DecodeGithubComContainersLibpodLibpod5(in *jlexer.Lexer, ou
 		switch key {
 		case "makeInfraContainer":
 			out.HasInfraContainer = bool(in.Bool())
+		case "infraPortBindings":
+			if in.IsNull() {
+				in.Skip()
+				out.PortBindings = nil
+			} else {
+				in.Delim('[')
+				if out.PortBindings == nil {
+					if !in.IsDelim(']') {
+						out.PortBindings = make([]ocicni.PortMapping, 0, 1)
+					} else {
+						out.PortBindings = []ocicni.PortMapping{}
+					}
+				} else {
+					out.PortBindings = (out.PortBindings)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v6 ocicni.PortMapping
+					easyjsonBe091417DecodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(in, &v6)
+					out.PortBindings = append(out.PortBindings, v6)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "infraImage":
+			out.Image = string(in.String())
+		case "infraCommand":
+			if in.IsNull() {
+				in.Skip()
+				out.Command = nil
+			} else {
+				in.Delim('[')
+				if out.Command == nil {
+					if !in.IsDelim(']') {
+						out.Command = make([]string, 0, 4)
+					} else {
+						out.Command = []string{}
+					}
+				} else {
+					out.Command = (out.Command)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 string
+					v7 = string(in.String())
+					out.Command = append(out.Command, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "infraCapAdd":
+			if in.IsNull() {
+				in.Skip()
+				out.CapAdd = nil
+			} else {
+				in.Delim('[')
+				if out.CapAdd == nil {
+					if !in.IsDelim(']') {
+						out.CapAdd = make([]string, 0, 4)
+					} else {
+						out.CapAdd = []string{}
+					}
+				} else {
+					out.CapAdd = (out.CapAdd)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v8 string
+					v8 = string(in.String())
+					out.CapAdd = append(out.CapAdd, v8)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "infraSysctls":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Sysctls = make(map[string]string)
+				} else {
+					out.Sysctls = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v9 string
+					v9 = string(in.String())
+					(out.Sysctls)[key] = v9
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "infraDNSServer":
+			if in.IsNull() {
+				in.Skip()
+				out.DNSServer = nil
+			} else {
+				in.Delim('[')
+				if out.DNSServer == nil {
+					if !in.IsDelim(']') {
+						out.DNSServer = make([]string, 0, 4)
+					} else {
+						out.DNSServer = []string{}
+					}
+				} else {
+					out.DNSServer = (out.DNSServer)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 string
+					v10 = string(in.String())
+					out.DNSServer = append(out.DNSServer, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "infraDNSSearch":
+			if in.IsNull() {
+				in.Skip()
+				out.DNSSearch = nil
+			} else {
+				in.Delim('[')
+				if out.DNSSearch == nil {
+					if !in.IsDelim(']') {
+						out.DNSSearch = make([]string, 0, 4)
+					} else {
+						out.DNSSearch = []string{}
+					}
+				} else {
+					out.DNSSearch = (out.DNSSearch)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v11 string
+					v11 = string(in.String())
+					out.DNSSearch = append(out.DNSSearch, v11)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "infraDNSOption":
+			if in.IsNull() {
+				in.Skip()
+				out.DNSOption = nil
+			} else {
+				in.Delim('[')
+				if out.DNSOption == nil {
+					if !in.IsDelim(']') {
+						out.DNSOption = make([]string, 0, 4)
+					} else {
+						out.DNSOption = []string{}
+					}
+				} else {
+					out.DNSOption = (out.DNSOption)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v12 string
+					v12 = string(in.String())
+					out.DNSOption = append(out.DNSOption, v12)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -729,7 +1041,7 @@ func easyjsonBe091417DecodeGithubComContainersLibpodLibpod5(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonBe091417EncodeGithubComContainersLibpodLibpod5(out *jwriter.Writer, in InfraContainerConfig) {
+func easyjsonBe091417EncodeGithubComContainersLibpodLibpod6(out *jwriter.Writer, in InfraContainerConfig) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -743,5 +1055,238 @@ func easyjsonBe091417EncodeGithubComContainersLibpodLibpod5(out *jwriter.Writer,
 		}
 		out.Bool(bool(in.HasInfraContainer))
 	}
+	{
+		const prefix string = ",\"infraPortBindings\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.PortBindings == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v13, v14 := range in.PortBindings {
+				if v13 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonBe091417EncodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(out, v14)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Image != "" {
+		const prefix string = ",\"infraImage\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Image))
+	}
+	if len(in.Command) != 0 {
+		const prefix string = ",\"infraCommand\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v15, v16 := range in.Command {
+				if v15 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v16))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.CapAdd) != 0 {
+		const prefix string = ",\"infraCapAdd\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v17, v18 := range in.CapAdd {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v18))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Sysctls) != 0 {
+		const prefix string = ",\"infraSysctls\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v19First := true
+			for v19Name, v19Value := range in.Sysctls {
+				if v19First {
+					v19First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v19Name))
+				out.RawByte(':')
+				out.String(string(v19Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.DNSServer) != 0 {
+		const prefix string = ",\"infraDNSServer\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v20, v21 := range in.DNSServer {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v21))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.DNSSearch) != 0 {
+		const prefix string = ",\"infraDNSSearch\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v22, v23 := range in.DNSSearch {
+				if v22 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v23))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.DNSOption) != 0 {
+		const prefix string = ",\"infraDNSOption\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v24, v25 := range in.DNSOption {
+				if v24 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v25))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjsonBe091417DecodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(in *jlexer.Lexer, out *ocicni.PortMapping) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "hostPort":
+			out.HostPort = int32(in.Int32())
+		case "containerPort":
+			out.ContainerPort = int32(in.Int32())
+		case "protocol":
+			out.Protocol = string(in.String())
+		case "hostIP":
+			out.HostIP = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBe091417EncodeGithubComContainersLibpodVendorGithubComCriOOcicniPkgOcicni(out *jwriter.Writer, in ocicni.PortMapping) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"hostPort\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int32(int32(in.HostPort))
+	}
+	{
+		const prefix string = ",\"containerPort\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int32(int32(in.ContainerPort))
+	}
+	{
+		const prefix string = ",\"protocol\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Protocol))
+	}
+	{
+		const prefix string = ",\"hostIP\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.HostIP))
+	}
 	out.RawByte('}')
 }
@@ -67,9 +67,9 @@ func (p *Pod) refresh() error {
 
 	// We need to recreate the pod's cgroup
 	if p.config.UsePodCgroup {
-		switch p.runtime.config.CgroupManager {
+		switch p.runtime.config().CgroupManager {
 		case SystemdCgroupsManager:
-			cgroupPath, err := systemdSliceFromPath(p.config.CgroupParent, fmt.Sprintf("libpod_pod_%s", p.ID()))
+			cgroupPath, err := systemdSliceFromPath(p.config.CgroupParent, fmt.Sprintf("libpod_pod_%s", p.ID()), p.resources())
 			if err != nil {
 				logrus.Errorf("Error creating CGroup for pod %s: %v", p.ID(), err)
 			}
@@ -78,8 +78,15 @@ func (p *Pod) refresh() error {
 			p.state.CgroupPath = filepath.Join(p.config.CgroupParent, p.ID())
 
 			logrus.Debugf("setting pod cgroup to %s", p.state.CgroupPath)
+			// The limits are set when the cgroup is created, before
+			// the containers of the pod
+			if resources := p.resources(); resources != nil {
+				if err := makeCgroupfsCgroup(p.state.CgroupPath, resources); err != nil {
+					logrus.Errorf("Error creating CGroup for pod %s: %v", p.ID(), err)
+				}
+			}
 		default:
-			return errors.Wrapf(ErrInvalidArg, "unknown cgroups manager %s specified", p.runtime.config.CgroupManager)
+			return errors.Wrapf(ErrInvalidArg, "unknown cgroups manager %s specified", p.runtime.config().CgroupManager)
 		}
 	}
 
@@ -133,7 +140,7 @@ func startNode(ctx context.Context, node *containerNode, setError bool, ctrError
 	// But they could have died before we got here
 	// Does not require that the container be locked, we only need to lock
 	// the dependencies
-	depsStopped, err := node.container.checkDependenciesRunning()
+	depsStopped, err := node.container.checkDependenciesRunning(ctx)
 	if err != nil {
 		ctrErrors[node.id] = err
 		ctrErrored = true
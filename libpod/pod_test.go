@@ -0,0 +1,96 @@
+package libpod
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodResources(t *testing.T) {
+	pod := &Pod{config: &PodConfig{}}
+	assert.Nil(t, pod.resources())
+
+	require.NoError(t, WithPodMemoryLimit(512*1024*1024)(pod))
+	resources := pod.resources()
+	require.NotNil(t, resources)
+	assert.Equal(t, int64(512*1024*1024), *resources.Memory.Limit)
+	assert.Nil(t, resources.CPU)
+
+	require.NoError(t, WithPodCPUQuota(150000, 100000)(pod))
+	resources = pod.resources()
+	assert.Equal(t, int64(150000), *resources.CPU.Quota)
+	assert.Equal(t, uint64(100000), *resources.CPU.Period)
+	quota, period := pod.CPUQuota()
+	assert.Equal(t, int64(150000), quota)
+	assert.Equal(t, uint64(100000), period)
+
+	// The limits are saved with the pod
+	data, err := pod.config.MarshalJSON()
+	require.NoError(t, err)
+	var config PodConfig
+	require.NoError(t, config.UnmarshalJSON(data))
+	assert.Equal(t, int64(512*1024*1024), config.MemoryLimit)
+	assert.Equal(t, int64(150000), config.CPUQuota)
+	assert.Equal(t, uint64(100000), config.CPUPeriod)
+
+	for _, option := range []PodCreateOption{
+		WithPodMemoryLimit(-1),
+		WithPodCPUQuota(-1, 100000),
+		WithPodCPUQuota(999, 100000),
+		WithPodCPUQuota(100000, 0),
+		WithPodCPUQuota(100000, 2000000),
+	} {
+		assert.Equal(t, ErrInvalidArg, errors.Cause(option(pod)))
+	}
+	pod.valid = true
+	assert.Equal(t, ErrPodFinalized, WithPodMemoryLimit(0)(pod))
+}
+
+func TestPodResourcesVMOverhead(t *testing.T) {
+	runtime := &Runtime{}
+	runtime.setConfig(&RuntimeConfig{VMOverheadMemory: 160 * 1024 * 1024, VMOverheadCPU: 0.25})
+	pod := &Pod{config: &PodConfig{Labels: map[string]string{RuntimeClassLabel: KataRuntimeHandler}}, runtime: runtime}
+	assert.Nil(t, pod.resources())
+
+	require.NoError(t, WithPodMemoryLimit(512*1024*1024)(pod))
+	require.NoError(t, WithPodCPUQuota(150000, 100000)(pod))
+	resources := pod.resources()
+	assert.Equal(t, int64(672*1024*1024), *resources.Memory.Limit)
+	assert.Equal(t, int64(175000), *resources.CPU.Quota)
+	assert.Equal(t, uint64(100000), *resources.CPU.Period)
+	assert.Equal(t, &PodVMOverhead{Memory: 160 * 1024 * 1024, CPU: 0.25}, pod.vmOverhead())
+
+	// The limits of the pod are those its containers use
+	assert.Equal(t, int64(512*1024*1024), pod.MemoryLimit())
+	quota, _ := pod.CPUQuota()
+	assert.Equal(t, int64(150000), quota)
+}
+
+func TestPodInfraContainerOptions(t *testing.T) {
+	pod := &Pod{config: &PodConfig{InfraContainer: &InfraContainerConfig{}}}
+	for _, option := range []PodCreateOption{
+		WithInfraContainer(),
+		WithInfraImage("registry.example.com/pause:latest"),
+		WithInfraCommand([]string{"/pause", "-v"}),
+		WithInfraCapAdd([]string{"NET_ADMIN"}),
+		WithInfraSysctls(map[string]string{"net.ipv4.ip_forward": "1"}),
+		WithInfraDNS([]string{"10.0.0.53", "fd00::53"}),
+		WithInfraDNSSearch([]string{"example.com"}),
+		WithInfraDNSOption([]string{"ndots:2"}),
+	} {
+		require.NoError(t, option(pod))
+	}
+	assert.Equal(t, ErrInvalidArg, errors.Cause(WithInfraDNS([]string{"ns.example.com"})(pod)))
+
+	// The configuration of the infra container is saved with the pod
+	data, err := pod.config.MarshalJSON()
+	require.NoError(t, err)
+	var config PodConfig
+	require.NoError(t, config.UnmarshalJSON(data))
+	assert.Equal(t, pod.config.InfraContainer, config.InfraContainer)
+
+	pod.valid = true
+	assert.Equal(t, ErrPodFinalized, WithInfraImage("pause")(pod))
+}
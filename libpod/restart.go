@@ -0,0 +1,113 @@
+package libpod
+
+import (
+	"context"
+	"time"
+
+	"github.com/containers/libpod/libpod/events"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// RestartPolicyNone is the restart policy of containers created without
+	// one, which are not restarted
+	RestartPolicyNone = ""
+	// RestartPolicyNo does not restart containers when they exit
+	RestartPolicyNo = "no"
+	// RestartPolicyOnFailure restarts containers when they exit with a
+	// non-zero exit code, up to their restart retries
+	RestartPolicyOnFailure = "on-failure"
+	// RestartPolicyAlways restarts containers whenever they exit, unless
+	// they were stopped by a user
+	RestartPolicyAlways = "always"
+	// RestartPolicyUnlessStopped restarts containers whenever they exit,
+	// unless they were stopped by a user. As podman has no daemon starting
+	// containers at boot, it is the same as RestartPolicyAlways.
+	RestartPolicyUnlessStopped = "unless-stopped"
+)
+
+const (
+	// restartDelayBase is the delay before the first restart of a
+	// container, doubled at each restart
+	restartDelayBase = 100 * time.Millisecond
+	// restartDelayMax is the maximum delay before restarting a container
+	restartDelayMax = time.Minute
+	// restartDelayReset is how long a container must run for the delay
+	// before its next restart to be reset
+	restartDelayReset = 10 * time.Second
+)
+
+// shouldRestart returns whether the restart policy of the container restarts
+// it after its exit
+func (c *Container) shouldRestart() bool {
+	if c.state.StoppedByUser || c.state.State != ContainerStateStopped {
+		return false
+	}
+	switch c.config.RestartPolicy {
+	case RestartPolicyAlways, RestartPolicyUnlessStopped:
+		return true
+	case RestartPolicyOnFailure:
+		if c.state.ExitCode == 0 {
+			return false
+		}
+		return c.config.RestartRetries == 0 || c.state.RestartCount < c.config.RestartRetries
+	}
+	return false
+}
+
+// restartDelay returns how long to wait before restarting the container after
+// its exit. The delay doubles at each restart, so containers exiting at once
+// do not use the host in a loop, and is reset once a container ran for a
+// while.
+func (c *Container) restartDelay() time.Duration {
+	if c.state.FinishedTime.Sub(c.state.StartedTime) >= restartDelayReset {
+		return restartDelayBase
+	}
+	delay := restartDelayBase
+	for i := uint(0); i < c.state.RestartCount && delay < restartDelayMax; i++ {
+		delay *= 2
+	}
+	if delay > restartDelayMax {
+		delay = restartDelayMax
+	}
+	return delay
+}
+
+// Internal, non-locking function restarting a container that exited if its
+// restart policy asks for it. The container must be cleaned up.
+func (c *Container) handleRestartPolicy(ctx context.Context) (err error) {
+	if !c.shouldRestart() {
+		return nil
+	}
+	logrus.Debugf("Restarting container %s due to its restart policy %s", c.ID(), c.config.RestartPolicy)
+
+	// Starting the container resets the count of its restarts, and the
+	// service it was started from, as podman restarts it from conmon
+	restartCount := c.state.RestartCount + 1
+	systemdService := c.state.SystemdService
+
+	if err := c.prepare(); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if err2 := c.cleanup(); err2 != nil {
+				logrus.Errorf("error cleaning up container %s: %v", c.ID(), err2)
+			}
+		}
+	}()
+	if err := c.reinit(ctx); err != nil {
+		return err
+	}
+	if err := c.start(); err != nil {
+		return err
+	}
+
+	c.state.RestartCount = restartCount
+	c.state.SystemdService = systemdService
+	if err := c.save(); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Restart)
+	return nil
+}
@@ -0,0 +1,70 @@
+package libpod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldRestart(t *testing.T) {
+	for _, test := range []struct {
+		policy        string
+		retries       uint
+		restartCount  uint
+		exitCode      int32
+		state         ContainerStatus
+		stoppedByUser bool
+		restart       bool
+	}{
+		{RestartPolicyNone, 0, 0, 1, ContainerStateStopped, false, false},
+		{RestartPolicyNo, 0, 0, 1, ContainerStateStopped, false, false},
+		{RestartPolicyAlways, 0, 0, 0, ContainerStateStopped, false, true},
+		{RestartPolicyAlways, 0, 0, 0, ContainerStateStopped, true, false},
+		{RestartPolicyAlways, 0, 0, 0, ContainerStateRunning, false, false},
+		{RestartPolicyUnlessStopped, 0, 0, 1, ContainerStateStopped, false, true},
+		{RestartPolicyUnlessStopped, 0, 0, 1, ContainerStateStopped, true, false},
+		{RestartPolicyOnFailure, 0, 0, 0, ContainerStateStopped, false, false},
+		{RestartPolicyOnFailure, 0, 10, 1, ContainerStateStopped, false, true},
+		{RestartPolicyOnFailure, 3, 2, 1, ContainerStateStopped, false, true},
+		{RestartPolicyOnFailure, 3, 3, 1, ContainerStateStopped, false, false},
+		{RestartPolicyOnFailure, 3, 0, 137, ContainerStateStopped, true, false},
+	} {
+		ctr := &Container{
+			config: &ContainerConfig{RestartPolicy: test.policy, RestartRetries: test.retries},
+			state: &containerState{
+				State:         test.state,
+				ExitCode:      test.exitCode,
+				RestartCount:  test.restartCount,
+				StoppedByUser: test.stoppedByUser,
+			},
+		}
+		assert.Equal(t, test.restart, ctr.shouldRestart(), "%+v", test)
+	}
+}
+
+func TestRestartDelay(t *testing.T) {
+	started := time.Now()
+	for _, test := range []struct {
+		restartCount uint
+		ran          time.Duration
+		delay        time.Duration
+	}{
+		{0, time.Second, 100 * time.Millisecond},
+		{1, time.Second, 200 * time.Millisecond},
+		{3, time.Second, 800 * time.Millisecond},
+		{20, time.Second, time.Minute},
+		// The delay is reset once the container ran for a while
+		{20, time.Minute, 100 * time.Millisecond},
+	} {
+		ctr := &Container{
+			config: &ContainerConfig{RestartPolicy: RestartPolicyAlways},
+			state: &containerState{
+				RestartCount: test.restartCount,
+				StartedTime:  started,
+				FinishedTime: started.Add(test.ran),
+			},
+		}
+		assert.Equal(t, test.delay, ctr.restartDelay(), "%+v", test)
+	}
+}
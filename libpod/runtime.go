@@ -6,18 +6,27 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	is "github.com/containers/image/storage"
 	"github.com/containers/image/types"
+	"github.com/containers/libpod/libpod/artifact"
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/libpod/libpod/manifests"
+	"github.com/containers/libpod/libpod/shutdown"
+	"github.com/containers/libpod/pkg/firewall"
 	"github.com/containers/libpod/pkg/hooks"
 	sysreg "github.com/containers/libpod/pkg/registries"
 	"github.com/containers/libpod/pkg/rootless"
+	"github.com/containers/libpod/pkg/util"
 	"github.com/containers/storage"
 	"github.com/cri-o/ocicni/pkg/ocicni"
-	"github.com/docker/docker/pkg/namesgenerator"
+	"github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/ulule/deepcopier"
@@ -53,9 +62,14 @@ const (
 	// NewRuntimeFromConfig() instead of NewRuntime()
 	ConfigPath = "/usr/share/containers/libpod.conf"
 	// OverrideConfigPath is the path to an override for the default libpod
-	// configuration file. If OverrideConfigPath exists, it will be used in
-	// place of the configuration file pointed to by ConfigPath.
+	// configuration file. If OverrideConfigPath exists, it is loaded after
+	// the configuration file pointed to by ConfigPath, and options set in
+	// it take precedence.
 	OverrideConfigPath = "/etc/containers/libpod.conf"
+	// UserOverrideConfigPath is the path, relative to the user's home
+	// directory, of the libpod configuration file for rootless users. It
+	// is loaded last and takes precedence over the system-wide files.
+	UserOverrideConfigPath = ".config/containers/libpod.conf"
 
 	// DefaultInfraImage to use for infra container
 	DefaultInfraImage = "k8s.gcr.io/pause:3.1"
@@ -69,11 +83,14 @@ type RuntimeOption func(*Runtime) error
 
 // Runtime is the core libpod runtime
 type Runtime struct {
-	config         *RuntimeConfig
+	// configValue holds the *RuntimeConfig of the runtime, read with
+	// config(). ReloadConfig replaces it as a whole while other goroutines
+	// read it, so a configuration is not modified once the runtime is
+	// initialized.
+	configValue    atomic.Value
 	state          State
 	store          storage.Store
 	storageService *storageService
-	imageContext   *types.SystemContext
 	ociRuntime     *OCIRuntime
 	lockDir        string
 	netPlugin      ocicni.CNIPlugin
@@ -82,6 +99,25 @@ type Runtime struct {
 	valid          bool
 	lock           sync.RWMutex
 	imageRuntime   *image.Runtime
+	eventer        events.Eventer
+	firewallOnce   sync.Once
+	firewall       firewall.Firewall
+	firewallErr    error
+	artifactOnce   sync.Once
+	artifactStore  *artifact.Store
+	artifactErr    error
+	manifestOnce   sync.Once
+	manifestStore  *manifests.Store
+	manifestErr    error
+	// nameGenerator generates names in place of the generator of the
+	// configuration, if set
+	nameGenerator NameGenerator
+
+	// readConfig reads the configuration files of the runtime, and
+	// fileConfig is the configuration they had when it was created or
+	// last reloaded
+	readConfig func() (*RuntimeConfig, error)
+	fileConfig *RuntimeConfig
 }
 
 // RuntimeConfig contains configuration options used to set up the runtime
@@ -109,6 +145,23 @@ type RuntimeConfig struct {
 	// containers
 	// The first path pointing to a valid file will be used
 	RuntimePath []string `toml:"runtime_path"`
+	// WasmRuntimePath is the path to an OCI runtime binary able to run
+	// WASM modules, used for containers of WASM images
+	// The first path pointing to a valid file will be used
+	WasmRuntimePath []string `toml:"wasm_runtime_path"`
+	// KataRuntimePath is the path to the Kata Containers OCI runtime
+	// binary, used for containers of the kata runtime class, which run in
+	// a VM shared by the containers of their pod
+	// The first path pointing to a valid file will be used
+	KataRuntimePath []string `toml:"kata_runtime_path"`
+	// VMOverheadMemory is the memory in bytes used by the VM of a pod of
+	// a VM-isolated runtime class in addition to its containers, added to
+	// the memory limit of the pod cgroup
+	VMOverheadMemory int64 `toml:"vm_overhead_memory"`
+	// VMOverheadCPU is the number of CPUs used by the VM of a pod of a
+	// VM-isolated runtime class in addition to its containers, added to the
+	// CPU limit of the pod cgroup
+	VMOverheadCPU float64 `toml:"vm_overhead_cpu"`
 	// ConmonPath is the path to the Conmon binary used for managing
 	// containers
 	// The first path pointing to a valid file will be used
@@ -161,6 +214,105 @@ type RuntimeConfig struct {
 	InfraImage string `toml:"infra_image"`
 	// InfraCommand is the command run to start up a pod infra container
 	InfraCommand string `toml:"infra_command"`
+	// FirewallDriver is the driver used to manage the firewall rules of
+	// containers: "iptables", "nftables" or "firewalld". If empty, the
+	// driver matching the firewall in use on the host is selected.
+	FirewallDriver string `toml:"firewall_driver,omitempty"`
+	// BindHelperSocket is the socket of the bind helper, which forwards
+	// the privileged ports published by rootless containers. If empty,
+	// rootless containers can not publish privileged ports.
+	BindHelperSocket string `toml:"bind_helper_socket,omitempty"`
+	// ReadOnly rejects all operations that modify containers, pods and
+	// images, so the runtime can only be used to inspect them. The state of
+	// the runtime must be current, it can not be refreshed after a reboot
+	// in read-only mode.
+	ReadOnly bool `toml:"read_only"`
+	// BlobCacheDir is the directory of a blob cache shared by the users
+	// and stores of the host. Pulls read the blobs of images from it, and
+	// add those they download, so that each blob is downloaded once. If
+	// empty, there is no shared cache.
+	BlobCacheDir string `toml:"blob_cache_dir,omitempty"`
+	// EventsLogger is the backend the events of containers, images and
+	// pods are written to: "file", "journald" or "none"
+	EventsLogger string `toml:"events_logger,omitempty"`
+	// EventsLogFilePath is the file events are written to by the file
+	// backend. If empty, it is events/events.log in StaticDir.
+	EventsLogFilePath string `toml:"events_logfile_path,omitempty"`
+	// EventWebhooks are the HTTP endpoints selected events are posted to
+	// by DispatchEvents
+	EventWebhooks []events.Webhook `toml:"event_webhooks,omitempty"`
+	// AuditLogPath is the file of the audit log, recording the operations
+	// of the CLI and the API modifying containers, pods and images with
+	// the identity of their caller. Auditing is disabled if empty.
+	AuditLogPath string `toml:"audit_log_path,omitempty"`
+	// StorageQuota is the maximum total size of the storage of root, such
+	// as 50G. Pulls and container creations are refused once the layers
+	// of its images and containers reach it. There is no quota if empty.
+	StorageQuota string `toml:"storage_quota,omitempty"`
+	// RootlessStorageQuota is the maximum total size of the storage of
+	// each rootless user, like StorageQuota. It is only honored from the
+	// system-wide configuration files.
+	RootlessStorageQuota string `toml:"rootless_storage_quota,omitempty"`
+	// AutoUserNSUser is the user whose subordinate IDs, in /etc/subuid and
+	// /etc/subgid, are allocated to the containers and builds of root with
+	// automatic user namespaces
+	AutoUserNSUser string `toml:"auto_userns_user,omitempty"`
+	// AutoUserNSSize is the number of UIDs and GIDs allocated to each
+	// container or build with an automatic user namespace
+	AutoUserNSSize int `toml:"auto_userns_size,omitempty"`
+	// StatsHistoryInterval is the interval, such as 10s, at which
+	// SampleStats records the resource usage of running containers in
+	// their stats history. Containers have no history if empty.
+	StatsHistoryInterval string `toml:"stats_history_interval,omitempty"`
+	// StatsHistorySize is the number of samples the stats history of each
+	// container holds, older ones are overwritten
+	StatsHistorySize int `toml:"stats_history_size,omitempty"`
+	// NameGenerator generates the names of the containers and pods created
+	// without one: "random", Docker-style names made of an adjective and
+	// the name of a scientist, "sequential", NamePrefix followed by the
+	// lowest number free, or "template", names made from NameTemplate. If
+	// empty, names are random.
+	NameGenerator string `toml:"name_generator,omitempty"`
+	// NamePrefix is the prefix of the names of the sequential generator. If
+	// empty, it is the kind of what is named, "container-" or "pod-".
+	NamePrefix string `toml:"name_prefix,omitempty"`
+	// NameTemplate is the Go template of the names of the template
+	// generator, given the fields of NameRequest
+	NameTemplate string `toml:"name_template,omitempty"`
+
+	// The following options are defaults for containers created by
+	// libpod. They apply to containers created through any libpod client,
+	// and are overridden by the image and by the user's own options.
+
+	// Env is a list of environment variables, in the form KEY=VALUE, set
+	// in all containers
+	Env []string `toml:"env,omitempty"`
+	// DefaultUlimits is a list of ulimits, in the form TYPE=SOFT:HARD, set
+	// in all containers
+	DefaultUlimits []string `toml:"default_ulimits,omitempty"`
+	// TZ is the timezone of containers, a zone of the zoneinfo database of
+	// the host or "local" for the timezone of the host. It is mounted as
+	// their /etc/localtime and set in the TZ environment variable. If
+	// empty, the timezone of the image is used.
+	TZ string `toml:"tz,omitempty"`
+	// Locale is the locale set in containers through the LANG environment
+	// variable. If empty, LANG is not set.
+	Locale string `toml:"locale,omitempty"`
+	// LogDriver is the log driver of containers created without one, only
+	// "k8s-file" or "json-file". If empty, "k8s-file" is used.
+	LogDriver string `toml:"log_driver,omitempty"`
+	// NetworkMode is the network mode used by containers, e.g. "bridge",
+	// "host" or "none"
+	NetworkMode string `toml:"network_mode,omitempty"`
+	// SeccompProfile is the path to the seccomp profile used by containers.
+	// If empty, SeccompOverridePath and SeccompDefaultPath are tried in
+	// turn, falling back to the builtin profile.
+	SeccompProfile string `toml:"seccomp_profile,omitempty"`
+	// ImageRunOptions applies the labels of images setting default run
+	// options of their containers, such as the capabilities and devices
+	// they require, for hosts only running images they trust. They are
+	// ignored by default, as any image could grant itself privileges.
+	ImageRunOptions bool `toml:"image_run_options"`
 }
 
 var (
@@ -176,6 +328,18 @@ var (
 			"/bin/runc",
 			"/usr/lib/cri-o-runc/sbin/runc",
 		},
+		WasmRuntimePath: []string{
+			"/usr/bin/crun-wasm",
+			"/usr/bin/crun",
+			"/usr/local/bin/crun",
+		},
+		KataRuntimePath: []string{
+			"/usr/bin/kata-runtime",
+			"/usr/local/bin/kata-runtime",
+			"/opt/kata/bin/kata-runtime",
+		},
+		VMOverheadMemory: 160 * 1024 * 1024,
+		VMOverheadCPU:    0.25,
 		ConmonPath: []string{
 			"/usr/libexec/podman/conmon",
 			"/usr/libexec/crio/conmon",
@@ -197,6 +361,12 @@ var (
 		CNIPluginDir:  []string{"/usr/libexec/cni", "/usr/lib/cni", "/opt/cni/bin"},
 		InfraCommand:  DefaultInfraCommand,
 		InfraImage:    DefaultInfraImage,
+		NetworkMode:   "bridge",
+		EventsLogger:  "file",
+
+		AutoUserNSUser:   "containers",
+		AutoUserNSSize:   65536,
+		StatsHistorySize: 360,
 	}
 )
 
@@ -234,6 +404,16 @@ func GetRootlessRuntimeDir() (string, error) {
 	return runtimeDir, nil
 }
 
+// GetRootlessPauseProcessPidPath returns the path to the file with the pid of
+// the pause process keeping the rootless user and mount namespaces alive
+func GetRootlessPauseProcessPidPath() (string, error) {
+	runtimeDir, err := GetRootlessRuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(runtimeDir, "libpod", "pause.pid"), nil
+}
+
 func getDefaultTmpDir() (string, error) {
 	if !rootless.IsRootless() {
 		return "/var/run/libpod", nil
@@ -250,30 +430,54 @@ func getDefaultTmpDir() (string, error) {
 // Options can be passed to override the default configuration for the runtime
 func NewRuntime(options ...RuntimeOption) (runtime *Runtime, err error) {
 	runtime = new(Runtime)
-	runtime.config = new(RuntimeConfig)
+	config, err := readConfigFiles()
+	if err != nil {
+		return nil, err
+	}
+	runtime.setConfig(config)
+	runtime.readConfig = readConfigFiles
+	runtime.fileConfig = new(RuntimeConfig)
+	deepcopier.Copy(runtime.config()).To(runtime.fileConfig)
+
+	// Overwrite config with user-given configuration options
+	for _, opt := range options {
+		if err := opt(runtime); err != nil {
+			return nil, errors.Wrapf(err, "error configuring runtime")
+		}
+	}
+	if err := makeRuntime(runtime); err != nil {
+		return nil, err
+	}
+	return runtime, nil
+}
+
+// readConfigFiles returns the default configuration, overridden by the
+// configuration files
+func readConfigFiles() (*RuntimeConfig, error) {
+	config := new(RuntimeConfig)
 
 	// Copy the default configuration
 	tmpDir, err := getDefaultTmpDir()
 	if err != nil {
 		return nil, err
 	}
-	deepcopier.Copy(defaultRuntimeConfig).To(runtime.config)
-	runtime.config.TmpDir = tmpDir
+	deepcopier.Copy(defaultRuntimeConfig).To(config)
+	config.TmpDir = tmpDir
 
-	configPath := ConfigPath
-	foundConfig := true
+	// Configuration files are loaded in order of increasing precedence.
+	// Each file only overrides the options it sets.
+	configPaths := []string{ConfigPath, OverrideConfigPath}
+	userConfigPath := ""
 	if rootless.IsRootless() {
 		home := os.Getenv("HOME")
-		if runtime.config.SignaturePolicyPath == "" {
+		if config.SignaturePolicyPath == "" {
 			newPath := filepath.Join(home, ".config/containers/policy.json")
 			if _, err := os.Stat(newPath); err == nil {
-				runtime.config.SignaturePolicyPath = newPath
+				config.SignaturePolicyPath = newPath
 			}
 		}
-		configPath = filepath.Join(home, ".config/containers/libpod.conf")
-		if _, err := os.Stat(configPath); err != nil {
-			foundConfig = false
-		}
+		userConfigPath = filepath.Join(home, UserOverrideConfigPath)
+		configPaths = append(configPaths, userConfigPath)
 
 		runtimeDir, err := GetRootlessRuntimeDir()
 		if err != nil {
@@ -287,49 +491,84 @@ func NewRuntime(options ...RuntimeOption) (runtime *Runtime, err error) {
 			return nil, errors.Wrapf(err, "cannot set XDG_RUNTIME_DIR")
 		}
 
-	} else if _, err := os.Stat(OverrideConfigPath); err == nil {
-		// Use the override configuration path
-		configPath = OverrideConfigPath
-	} else if _, err := os.Stat(ConfigPath); err != nil {
-		// Both stat checks failed, no config found
-		foundConfig = false
 	}
 
-	// If we have a valid configuration file, load it in
-	if foundConfig {
+	for _, configPath := range configPaths {
+		if _, err := os.Stat(configPath); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "error checking configuration file %s", configPath)
+		}
 		contents, err := ioutil.ReadFile(configPath)
 		if err != nil {
 			return nil, errors.Wrapf(err, "error reading configuration file %s", configPath)
 		}
-		if _, err := toml.Decode(string(contents), runtime.config); err != nil {
+		rootlessStorageQuota := config.RootlessStorageQuota
+		md, err := toml.Decode(string(contents), config)
+		if err != nil {
 			return nil, errors.Wrapf(err, "error decoding configuration file %s", configPath)
 		}
+		// The system-wide temporary directory is not writable by
+		// rootless users, only honor it from their own configuration
+		if userConfigPath != "" && configPath != userConfigPath && md.IsDefined("tmp_dir") {
+			config.TmpDir = tmpDir
+		}
+		// Nor is the system-wide audit log, rootless users keep their
+		// own if they configure it
+		if userConfigPath != "" && configPath != userConfigPath && md.IsDefined("audit_log_path") {
+			config.AuditLogPath = ""
+		}
+		// Rootless users can not raise their own storage quota
+		if configPath == userConfigPath && md.IsDefined("rootless_storage_quota") {
+			config.RootlessStorageQuota = rootlessStorageQuota
+		}
+		logrus.Debugf("Loaded libpod configuration file %s", configPath)
 	}
 
-	// Overwrite config with user-given configuration options
+	return config, nil
+}
+
+// NewRuntimeFromConfig creates a new container runtime using the given
+// configuration file for its default configuration. Passed RuntimeOption
+// functions can be used to mutate this configuration further.
+// An error will be returned if the configuration file at the given path does
+// not exist or cannot be loaded
+func NewRuntimeFromConfig(configPath string, options ...RuntimeOption) (runtime *Runtime, err error) {
+	runtime = new(Runtime)
+	runtime.readConfig = func() (*RuntimeConfig, error) {
+		return readConfigFile(configPath)
+	}
+	config, err := runtime.readConfig()
+	if err != nil {
+		return nil, err
+	}
+	runtime.setConfig(config)
+	runtime.fileConfig = new(RuntimeConfig)
+	deepcopier.Copy(runtime.config()).To(runtime.fileConfig)
+
+	// Overwrite the config with user-given configuration options
 	for _, opt := range options {
 		if err := opt(runtime); err != nil {
 			return nil, errors.Wrapf(err, "error configuring runtime")
 		}
 	}
+
 	if err := makeRuntime(runtime); err != nil {
 		return nil, err
 	}
+
 	return runtime, nil
 }
 
-// NewRuntimeFromConfig creates a new container runtime using the given
-// configuration file for its default configuration. Passed RuntimeOption
-// functions can be used to mutate this configuration further.
-// An error will be returned if the configuration file at the given path does
-// not exist or cannot be loaded
-func NewRuntimeFromConfig(configPath string, options ...RuntimeOption) (runtime *Runtime, err error) {
-	runtime = new(Runtime)
-	runtime.config = new(RuntimeConfig)
+// readConfigFile returns the configuration of the given configuration file,
+// without defaults
+func readConfigFile(configPath string) (*RuntimeConfig, error) {
+	config := new(RuntimeConfig)
 
 	// Set two fields not in the TOML config
-	runtime.config.StateType = defaultRuntimeConfig.StateType
-	runtime.config.StorageConfig = storage.StoreOptions{}
+	config.StateType = defaultRuntimeConfig.StateType
+	config.StorageConfig = storage.StoreOptions{}
 
 	// Check to see if the given configuration file exists
 	if _, err := os.Stat(configPath); err != nil {
@@ -343,22 +582,23 @@ func NewRuntimeFromConfig(configPath string, options ...RuntimeOption) (runtime
 	}
 
 	// Decode configuration file
-	if _, err := toml.Decode(string(contents), runtime.config); err != nil {
+	if _, err := toml.Decode(string(contents), config); err != nil {
 		return nil, errors.Wrapf(err, "error decoding configuration from file %s", configPath)
 	}
+	return config, nil
+}
 
-	// Overwrite the config with user-given configuration options
-	for _, opt := range options {
-		if err := opt(runtime); err != nil {
-			return nil, errors.Wrapf(err, "error configuring runtime")
+// findRuntimeBinary returns the first of the given paths pointing to a file, or
+// "" if there is none
+func findRuntimeBinary(paths []string) string {
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil || stat.IsDir() {
+			continue
 		}
+		return path
 	}
-
-	if err := makeRuntime(runtime); err != nil {
-		return nil, err
-	}
-
-	return runtime, nil
+	return ""
 }
 
 // Make a new runtime based on the given configuration
@@ -366,7 +606,7 @@ func NewRuntimeFromConfig(configPath string, options ...RuntimeOption) (runtime
 func makeRuntime(runtime *Runtime) (err error) {
 	// Find a working OCI runtime binary
 	foundRuntime := false
-	for _, path := range runtime.config.RuntimePath {
+	for _, path := range runtime.config().RuntimePath {
 		stat, err := os.Stat(path)
 		if err != nil {
 			continue
@@ -381,12 +621,12 @@ func makeRuntime(runtime *Runtime) (err error) {
 	if !foundRuntime {
 		return errors.Wrapf(ErrInvalidArg,
 			"could not find a working runc binary (configured options: %v)",
-			runtime.config.RuntimePath)
+			runtime.config().RuntimePath)
 	}
 
 	// Find a working conmon binary
 	foundConmon := false
-	for _, path := range runtime.config.ConmonPath {
+	for _, path := range runtime.config().ConmonPath {
 		stat, err := os.Stat(path)
 		if err != nil {
 			continue
@@ -401,7 +641,7 @@ func makeRuntime(runtime *Runtime) (err error) {
 	if !foundConmon {
 		return errors.Wrapf(ErrInvalidArg,
 			"could not find a working conmon binary (configured options: %v)",
-			runtime.config.ConmonPath)
+			runtime.config().ConmonPath)
 	}
 
 	// Set up containers/storage
@@ -409,7 +649,7 @@ func makeRuntime(runtime *Runtime) (err error) {
 	if rootless.SkipStorageSetup() {
 		logrus.Debug("Not configuring container store")
 	} else {
-		store, err = storage.GetStore(runtime.config.StorageConfig)
+		store, err = storage.GetStore(runtime.config().StorageConfig)
 		if err != nil {
 			return err
 		}
@@ -427,7 +667,25 @@ func makeRuntime(runtime *Runtime) (err error) {
 	runtime.imageRuntime = ir
 
 	// Setting signaturepolicypath
-	ir.SignaturePolicyPath = runtime.config.SignaturePolicyPath
+	ir.SetSignaturePolicyPath(runtime.config().SignaturePolicyPath)
+	ir.BlobCacheDir = runtime.config().BlobCacheDir
+	storageQuota := runtime.config().StorageQuota
+	if rootless.IsRootless() {
+		storageQuota = runtime.config().RootlessStorageQuota
+	}
+	if storageQuota != "" {
+		if ir.StorageQuota, err = units.RAMInBytes(storageQuota); err != nil || ir.StorageQuota <= 0 {
+			return errors.Wrapf(ErrInvalidArg, "invalid storage quota %q", storageQuota)
+		}
+	}
+	if runtime.config().StatsHistoryInterval != "" {
+		if interval, err := time.ParseDuration(runtime.config().StatsHistoryInterval); err != nil || interval <= 0 {
+			return errors.Wrapf(ErrInvalidArg, "invalid stats_history_interval %q", runtime.config().StatsHistoryInterval)
+		}
+		if runtime.config().StatsHistorySize <= 0 {
+			return errors.Wrapf(ErrInvalidArg, "invalid stats_history_size %d", runtime.config().StatsHistorySize)
+		}
+	}
 	defer func() {
 		if err != nil && store != nil {
 			// Don't forcibly shut down
@@ -447,40 +705,52 @@ func makeRuntime(runtime *Runtime) (err error) {
 	}
 	runtime.storageService = storageService
 
-	// Set up containers/image
-	runtime.imageContext = &types.SystemContext{
-		SignaturePolicyPath: runtime.config.SignaturePolicyPath,
-	}
-
 	// Create the tmpDir
-	if err := os.MkdirAll(runtime.config.TmpDir, 0751); err != nil {
+	if err := os.MkdirAll(runtime.config().TmpDir, 0751); err != nil {
 		// The directory is allowed to exist
 		if !os.IsExist(err) {
-			return errors.Wrapf(err, "error creating tmpdir %s", runtime.config.TmpDir)
+			return errors.Wrapf(err, "error creating tmpdir %s", runtime.config().TmpDir)
 		}
 	}
 
 	// Make an OCI runtime to perform container operations
 	ociRuntime, err := newOCIRuntime("runc", runtime.ociRuntimePath,
-		runtime.conmonPath, runtime.config.ConmonEnvVars,
-		runtime.config.CgroupManager, runtime.config.TmpDir,
-		runtime.config.MaxLogSize, runtime.config.NoPivotRoot)
+		runtime.conmonPath, runtime.config().ConmonEnvVars,
+		runtime.config().CgroupManager, runtime.config().TmpDir,
+		runtime.config().MaxLogSize, runtime.config().NoPivotRoot)
 	if err != nil {
 		return err
 	}
+	// The OCI runtimes of runtime handlers are optional
+	ociRuntime.wasmPath = findRuntimeBinary(runtime.config().WasmRuntimePath)
+	ociRuntime.kataPath = findRuntimeBinary(runtime.config().KataRuntimePath)
 	runtime.ociRuntime = ociRuntime
 
 	// Make the static files directory if it does not exist
-	if err := os.MkdirAll(runtime.config.StaticDir, 0755); err != nil {
+	if err := os.MkdirAll(runtime.config().StaticDir, 0755); err != nil {
 		// The directory is allowed to exist
 		if !os.IsExist(err) {
 			return errors.Wrapf(err, "error creating runtime static files directory %s",
-				runtime.config.StaticDir)
+				runtime.config().StaticDir)
 		}
 	}
 
+	// Set up the backend of events
+	if runtime.config().EventsLogFilePath == "" {
+		runtime.config().EventsLogFilePath = filepath.Join(runtime.config().StaticDir, "events", "events.log")
+	}
+	eventer, err := events.NewEventer(events.EventerOptions{
+		EventerType: runtime.config().EventsLogger,
+		LogFilePath: runtime.config().EventsLogFilePath,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error setting up the events backend")
+	}
+	runtime.eventer = eventer
+	ir.Eventer = eventer
+
 	// Make a directory to hold container lockfiles
-	lockDir := filepath.Join(runtime.config.TmpDir, "lock")
+	lockDir := filepath.Join(runtime.config().TmpDir, "lock")
 	if err := os.MkdirAll(lockDir, 0755); err != nil {
 		// The directory is allowed to exist
 		if !os.IsExist(err) {
@@ -491,23 +761,34 @@ func makeRuntime(runtime *Runtime) (err error) {
 	runtime.lockDir = lockDir
 
 	// Make the per-boot files directory if it does not exist
-	if err := os.MkdirAll(runtime.config.TmpDir, 0755); err != nil {
+	if err := os.MkdirAll(runtime.config().TmpDir, 0755); err != nil {
 		// The directory is allowed to exist
 		if !os.IsExist(err) {
 			return errors.Wrapf(err, "error creating runtime temporary files directory %s",
-				runtime.config.TmpDir)
+				runtime.config().TmpDir)
 		}
 	}
 
 	// Set up the CNI net plugin
-	netPlugin, err := ocicni.InitCNI(runtime.config.CNIDefaultNetwork, runtime.config.CNIConfigDir, runtime.config.CNIPluginDir...)
+	netPlugin, err := ocicni.InitCNI(runtime.config().CNIDefaultNetwork, runtime.config().CNIConfigDir, runtime.config().CNIPluginDir...)
 	if err != nil {
 		return errors.Wrapf(err, "error configuring CNI network plugin")
 	}
 	runtime.netPlugin = netPlugin
 
+	// The firewall driver is only set up once a container needs it, but
+	// check it exists now
+	if !util.StringInSlice(runtime.config().FirewallDriver, firewall.Drivers) {
+		return errors.Wrapf(ErrInvalidArg, "unknown firewall driver %q", runtime.config().FirewallDriver)
+	}
+
+	// Check the name generator before names are generated with it
+	if _, err := newNameGenerator(runtime.config()); err != nil {
+		return err
+	}
+
 	// Set up the state
-	switch runtime.config.StateType {
+	switch runtime.config().StateType {
 	case InMemoryStateStore:
 		state, err := NewInMemoryState()
 		if err != nil {
@@ -517,7 +798,7 @@ func makeRuntime(runtime *Runtime) (err error) {
 	case SQLiteStateStore:
 		return errors.Wrapf(ErrInvalidArg, "SQLite state is currently disabled")
 	case BoltDBStateStore:
-		dbPath := filepath.Join(runtime.config.StaticDir, "bolt_state.db")
+		dbPath := filepath.Join(runtime.config().StaticDir, "bolt_state.db")
 
 		state, err := NewBoltState(dbPath, runtime.lockDir, runtime)
 		if err != nil {
@@ -527,17 +808,15 @@ func makeRuntime(runtime *Runtime) (err error) {
 	default:
 		return errors.Wrapf(ErrInvalidArg, "unrecognized state type passed")
 	}
-
-	if err := runtime.state.SetNamespace(runtime.config.Namespace); err != nil {
-		return errors.Wrapf(err, "error setting libpod namespace in state")
+	if runtime.config().ReadOnly {
+		runtime.state = &readOnlyState{runtime.state}
 	}
-	logrus.Debugf("Set libpod namespace to %q", runtime.config.Namespace)
 
 	// We now need to see if the system has restarted
 	// We check for the presence of a file in our tmp directory to verify this
 	// This check must be locked to prevent races
-	runtimeAliveLock := filepath.Join(runtime.config.TmpDir, "alive.lck")
-	runtimeAliveFile := filepath.Join(runtime.config.TmpDir, "alive")
+	runtimeAliveLock := filepath.Join(runtime.config().TmpDir, "alive.lck")
+	runtimeAliveFile := filepath.Join(runtime.config().TmpDir, "alive")
 	aliveLock, err := storage.GetLockfile(runtimeAliveLock)
 	if err != nil {
 		return errors.Wrapf(err, "error acquiring runtime init lock")
@@ -548,28 +827,96 @@ func makeRuntime(runtime *Runtime) (err error) {
 	// and use it to lock important operations
 	aliveLock.Lock()
 	defer aliveLock.Unlock()
-	_, err = os.Stat(runtimeAliveFile)
+	bootID, err := getBootID()
+	if err != nil {
+		return err
+	}
+	aliveBootID, err := ioutil.ReadFile(runtimeAliveFile)
 	if err != nil {
+		if !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error reading runtime status file %s", runtimeAliveFile)
+		}
 		// If the file doesn't exist, we need to refresh the state
 		// This will trigger on first use as well, but refreshing an
 		// empty state only creates a single file
 		// As such, it's not really a performance concern
-		if os.IsNotExist(err) {
-			if err2 := runtime.refresh(runtimeAliveFile); err2 != nil {
-				return err2
+		// If the system did not reboot, only our temporary files
+		// were lost, and containers may still be alive
+		lastBootID, err := ioutil.ReadFile(runtime.lastBootIDPath())
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error reading boot ID file %s", runtime.lastBootIDPath())
+		}
+		if bootID != "" && string(lastBootID) == bootID {
+			logrus.Infof("Temporary files of libpod were lost, recovering state")
+			if err := runtime.recoverState(runtimeAliveFile, bootID); err != nil {
+				return err
 			}
-		} else {
-			return errors.Wrapf(err, "error reading runtime status file %s", runtimeAliveFile)
+		} else if err := runtime.refresh(runtimeAliveFile, bootID); err != nil {
+			return err
+		}
+	} else if len(aliveBootID) == 0 {
+		// Written before boot IDs were recorded, assume it is current
+		if err := runtime.markAlive(runtimeAliveFile, bootID); err != nil {
+			return err
+		}
+	} else if string(aliveBootID) != bootID {
+		// The temporary directory is not a tmpfs and survived a
+		// reboot, the state is stale all the same
+		logrus.Infof("System rebooted since libpod last ran, refreshing state")
+		if err := runtime.refresh(runtimeAliveFile, bootID); err != nil {
+			return err
 		}
 	}
 
+	// Only join the libpod namespace now, the state of the containers and
+	// pods of all namespaces is refreshed after a reboot
+	if err := runtime.state.SetNamespace(runtime.config().Namespace); err != nil {
+		return errors.Wrapf(err, "error setting libpod namespace in state")
+	}
+	logrus.Debugf("Set libpod namespace to %q", runtime.config().Namespace)
+
 	// Mark the runtime as valid - ready to be used, cannot be modified
 	// further
 	runtime.valid = true
 
+	// Close the state and release storage if the process is terminated
+	shutdown.Start()
+	if err := shutdown.Register(runtime.shutdownHandlerName(), func(sig os.Signal) error {
+		return runtime.Shutdown(false)
+	}); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// shutdownHandlerName is the name the runtime registers its shutdown handler
+// under
+func (r *Runtime) shutdownHandlerName() string {
+	return fmt.Sprintf("libpod-runtime-%p", r)
+}
+
+// config returns the configuration of the runtime. As ReloadConfig may
+// replace it meanwhile, callers reading several options that must agree keep
+// the configuration returned rather than calling config() again.
+func (r *Runtime) config() *RuntimeConfig {
+	config, _ := r.configValue.Load().(*RuntimeConfig)
+	return config
+}
+
+// imageContext returns the containers/image context of the storage of the
+// containers
+func (r *Runtime) imageContext() *types.SystemContext {
+	return &types.SystemContext{
+		SignaturePolicyPath: r.config().SignaturePolicyPath,
+	}
+}
+
+// setConfig replaces the configuration of the runtime
+func (r *Runtime) setConfig(config *RuntimeConfig) {
+	r.configValue.Store(config)
+}
+
 // GetConfig returns a copy of the configuration used by the runtime
 func (r *Runtime) GetConfig() *RuntimeConfig {
 	r.lock.RLock()
@@ -582,7 +929,7 @@ func (r *Runtime) GetConfig() *RuntimeConfig {
 	config := new(RuntimeConfig)
 
 	// Copy so the caller won't be able to modify the actual config
-	deepcopier.Copy(r.config).To(config)
+	deepcopier.Copy(r.config()).To(config)
 
 	return config
 }
@@ -601,6 +948,10 @@ func (r *Runtime) Shutdown(force bool) error {
 
 	r.valid = false
 
+	if err := shutdown.Unregister(r.shutdownHandlerName()); err != nil {
+		logrus.Debugf("Error unregistering shutdown handler: %v", err)
+	}
+
 	// Shutdown all containers if --force is given
 	if force {
 		ctrs, err := r.state.AllContainers()
@@ -634,7 +985,11 @@ func (r *Runtime) Shutdown(force bool) error {
 // Reconfigures the runtime after a reboot
 // Refreshes the state, recreating temporary files
 // Does not check validity as the runtime is not valid until after this has run
-func (r *Runtime) refresh(alivePath string) error {
+func (r *Runtime) refresh(alivePath, bootID string) error {
+	if r.config().ReadOnly {
+		return errors.Wrapf(ErrRuntimeReadOnly, "the state of libpod must be refreshed after a reboot, run podman without read-only mode once")
+	}
+
 	// First clear the state in the database
 	if err := r.state.Refresh(); err != nil {
 		return err
@@ -665,16 +1020,53 @@ func (r *Runtime) refresh(alivePath string) error {
 		pod.lock.Unlock()
 	}
 
-	// Create a file indicating the runtime is alive and ready
-	file, err := os.OpenFile(alivePath, os.O_RDONLY|os.O_CREATE, 0644)
+	return r.markAlive(alivePath, bootID)
+}
+
+// recoverState reconstructs temporary files after they were lost without a
+// reboot, e.g. because the temporary directory was wiped. Unlike refresh, it
+// keeps the state of containers the OCI runtime still knows about.
+// Does not check validity as the runtime is not valid until after this has run
+func (r *Runtime) recoverState(alivePath, bootID string) error {
+	if r.config().ReadOnly {
+		return errors.Wrapf(ErrRuntimeReadOnly, "the temporary files of libpod must be recovered, run podman without read-only mode once")
+	}
+
+	ctrs, err := r.state.AllContainers()
 	if err != nil {
-		return errors.Wrapf(err, "error creating runtime status file %s", alivePath)
+		return errors.Wrapf(err, "error retrieving all containers from state")
+	}
+	for _, ctr := range ctrs {
+		ctr.lock.Lock()
+		if err := ctr.recoverState(); err != nil {
+			logrus.Errorf("Error recovering container %s: %v", ctr.ID(), err)
+		}
+		ctr.lock.Unlock()
 	}
-	defer file.Close()
 
+	return r.markAlive(alivePath, bootID)
+}
+
+// markAlive creates the file indicating the runtime is alive and ready,
+// recording the boot it is valid for, and remembers the boot persistently
+// to tell reboots from lost temporary files
+func (r *Runtime) markAlive(alivePath, bootID string) error {
+	if err := ioutil.WriteFile(alivePath, []byte(bootID), 0644); err != nil {
+		return errors.Wrapf(err, "error creating runtime status file %s", alivePath)
+	}
+	if err := ioutil.WriteFile(r.lastBootIDPath(), []byte(bootID), 0644); err != nil {
+		return errors.Wrapf(err, "error writing boot ID file %s", r.lastBootIDPath())
+	}
 	return nil
 }
 
+// lastBootIDPath is the path of the file recording the boot libpod last ran
+// in, which unlike the runtime status file survives the loss of the
+// temporary directory
+func (r *Runtime) lastBootIDPath() string {
+	return filepath.Join(r.config().StaticDir, "bootid")
+}
+
 // Info returns the store and host information
 func (r *Runtime) Info() ([]InfoData, error) {
 	info := []InfoData{}
@@ -710,32 +1102,116 @@ func (r *Runtime) Info() ([]InfoData, error) {
 	return info, nil
 }
 
-// generateName generates a unique name for a container or pod.
-func (r *Runtime) generateName() (string, error) {
-	for {
-		name := namesgenerator.GetRandomName(0)
-		// Make sure container with this name does not exist
-		if _, err := r.state.LookupContainer(name); err == nil {
-			continue
-		} else {
-			if errors.Cause(err) != ErrNoSuchCtr {
-				return "", err
-			}
+// generateName generates a unique name for a container or pod, of the given
+// kind and image, with the name generator of the runtime.
+// Names are unique across all libpod namespaces, and reserved names are
+// skipped.
+func (r *Runtime) generateName(kind, image string) (string, error) {
+	generator := r.nameGenerator
+	if generator == nil {
+		var err error
+		if generator, err = newNameGenerator(r.config()); err != nil {
+			return "", err
 		}
-		// Make sure pod with this name does not exist
-		if _, err := r.state.LookupPod(name); err == nil {
-			continue
-		} else {
-			if errors.Cause(err) != ErrNoSuchPod {
+	}
+	// The sequential generator goes through every name in use before a
+	// free one, so all names are read at once for it. The names of the
+	// other generators are usually free, and looked up one by one.
+	var taken map[string]bool
+	if _, ok := generator.(*sequentialNameGenerator); ok {
+		var err error
+		if taken, err = r.takenNames(); err != nil {
+			return "", err
+		}
+	}
+	for index := 1; ; index++ {
+		name, err := generator.GenerateName(NameRequest{Kind: kind, Image: image, Index: index})
+		if err != nil {
+			return "", err
+		}
+		if !nameRegex.MatchString(name) {
+			return "", errors.Wrapf(ErrInvalidArg, "generated name %q must match regex [a-zA-Z0-9_-]+", name)
+		}
+		inUse := taken[name]
+		if taken == nil {
+			if inUse, err = r.state.NameInUse(name); err != nil {
 				return "", err
 			}
 		}
-		return name, nil
+		if !inUse {
+			return name, nil
+		}
+	}
+}
+
+// takenNames returns the names of the containers and pods of all libpod
+// namespaces, and the reserved names
+func (r *Runtime) takenNames() (map[string]bool, error) {
+	taken := make(map[string]bool)
+	ctrs, err := r.state.AllContainersInAllNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, ctr := range ctrs {
+		taken[ctr.Name()] = true
 	}
-	// The code should never reach here.
+	pods, err := r.state.AllPodsInAllNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		taken[pod.Name()] = true
+	}
+	reservations, err := r.state.NameReservations()
+	if err != nil {
+		return nil, err
+	}
+	for _, reservation := range reservations {
+		taken[reservation.Name] = true
+	}
+	return taken, nil
 }
 
 // ImageRuntime returns the imageruntime for image resolution
 func (r *Runtime) ImageRuntime() *image.Runtime {
 	return r.imageRuntime
 }
+
+// ArtifactStore returns the store of OCI artifacts, creating it on first use
+func (r *Runtime) ArtifactStore() (*artifact.Store, error) {
+	r.artifactOnce.Do(func() {
+		r.artifactStore, r.artifactErr = artifact.NewStore(filepath.Join(r.config().StaticDir, "artifacts"), r.artifactBlobsInUse)
+	})
+	return r.artifactStore, r.artifactErr
+}
+
+// artifactBlobsInUse returns the digests of the artifact blobs the containers
+// of all libpod namespaces bind mount, which stay in the store until they are
+// removed
+func (r *Runtime) artifactBlobsInUse() (map[digest.Digest]bool, error) {
+	ctrs, err := r.state.AllContainersInAllNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	inUse := make(map[digest.Digest]bool)
+	for _, ctr := range ctrs {
+		if ctr.config.Spec == nil {
+			continue
+		}
+		for _, mount := range ctr.config.Spec.Mounts {
+			if d, ok := r.artifactStore.BlobDigest(mount.Source); ok {
+				inUse[d] = true
+			}
+		}
+	}
+	return inUse, nil
+}
+
+// ManifestStore returns the store of the manifest lists being built,
+// creating it on first use
+func (r *Runtime) ManifestStore() (*manifests.Store, error) {
+	r.manifestOnce.Do(func() {
+		r.manifestStore, r.manifestErr = manifests.NewStore(filepath.Join(r.config().StaticDir, "manifests"))
+	})
+	return r.manifestStore, r.manifestErr
+}
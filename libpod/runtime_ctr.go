@@ -2,12 +2,16 @@ package libpod
 
 import (
 	"context"
+	"net"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/containers/libpod/libpod/events"
+	"github.com/containers/libpod/libpod/shutdown"
+	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/storage"
 	"github.com/containers/storage/pkg/stringid"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
@@ -33,11 +37,18 @@ type ContainerFilter func(*Container) bool
 
 // NewContainer creates a new container from a given OCI config
 func (r *Runtime) NewContainer(ctx context.Context, rSpec *spec.Spec, options ...CtrCreateOption) (c *Container, err error) {
+	// Don't leave a partially created container behind on termination
+	shutdown.Inhibit()
+	defer shutdown.Uninhibit()
+
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	if !r.valid {
 		return nil, ErrRuntimeStopped
 	}
+	if err := r.checkReadOnly(); err != nil {
+		return nil, err
+	}
 	return r.newContainer(ctx, rSpec, options...)
 }
 
@@ -45,6 +56,9 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 	if rSpec == nil {
 		return nil, errors.Wrapf(ErrInvalidArg, "must provide a valid runtime spec to create container")
 	}
+	if err := r.imageRuntime.CheckStorageQuota(); err != nil {
+		return nil, err
+	}
 
 	ctr := new(Container)
 	ctr.config = new(ContainerConfig)
@@ -73,8 +87,8 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 
 	// Set namespace based on current runtime namespace
 	// Do so before options run so they can override it
-	if r.config.Namespace != "" {
-		ctr.config.Namespace = r.config.Namespace
+	if r.config().Namespace != "" {
+		ctr.config.Namespace = r.config().Namespace
 	}
 
 	for _, option := range options {
@@ -94,10 +108,48 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 		if err != nil {
 			return nil, errors.Wrapf(err, "cannot add container %s to pod %s", ctr.ID(), ctr.config.Pod)
 		}
+
+		// Containers of a pod of a runtime class share its VM, or
+		// whatever else the runtime handler isolates the pod with
+		if class := pod.config.Labels[RuntimeClassLabel]; class != "" {
+			if ctr.config.RuntimeHandler == "" {
+				ctr.config.RuntimeHandler = class
+			} else if ctr.config.RuntimeHandler != class {
+				return nil, errors.Wrapf(ErrInvalidArg, "container of runtime handler %q cannot join pod %s of runtime class %q", ctr.config.RuntimeHandler, pod.ID(), class)
+			}
+		} else if ctr.config.RuntimeHandler == KataRuntimeHandler {
+			return nil, errors.Wrapf(ErrInvalidArg, "containers of runtime handler %q can only join pods with the %s=%s label", KataRuntimeHandler, RuntimeClassLabel, KataRuntimeHandler)
+		}
+
+		// Containers sharing the network namespace of the infra
+		// container use its DNS settings, unless they set their own
+		infra := pod.config.InfraContainer
+		if ctr.config.NetNsCtr != "" && (len(infra.DNSServer) > 0 || len(infra.DNSSearch) > 0 || len(infra.DNSOption) > 0) &&
+			len(ctr.config.DNSServer) == 0 && len(ctr.config.DNSSearch) == 0 && len(ctr.config.DNSOption) == 0 {
+			infraID, err := pod.InfraContainerID()
+			if err != nil {
+				return nil, errors.Wrapf(err, "error retrieving pod %s infra container", pod.ID())
+			}
+			if ctr.config.NetNsCtr == infraID {
+				for _, server := range infra.DNSServer {
+					ctr.config.DNSServer = append(ctr.config.DNSServer, net.ParseIP(server))
+				}
+				ctr.config.DNSSearch = infra.DNSSearch
+				ctr.config.DNSOption = infra.DNSOption
+			}
+		}
+	}
+
+	if ctr.config.InitContainerType != "" && (pod == nil || ctr.config.IsInfra) {
+		return nil, errors.Wrapf(ErrInvalidArg, "init containers must be created in a pod, and cannot be its infra container")
+	}
+
+	if ctr.config.RuntimeHandler != "" && r.ociRuntime.handlerPath(ctr.config.RuntimeHandler) == "" {
+		return nil, errors.Wrapf(ErrInvalidArg, "could not find an OCI runtime for runtime handler %q, check %s_runtime_path in libpod.conf", ctr.config.RuntimeHandler, ctr.config.RuntimeHandler)
 	}
 
 	if ctr.config.Name == "" {
-		name, err := r.generateName()
+		name, err := r.generateName(ContainerNameKind, shortImageName(ctr.config.RootfsImageName))
 		if err != nil {
 			return nil, err
 		}
@@ -105,8 +157,20 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 		ctr.config.Name = name
 	}
 
+	// Containers of pods with resource limits share them, they cannot
+	// leave the pod cgroup
+	if pod != nil && pod.resources() != nil && ctr.config.CgroupParent != "" {
+		podCgroup, err := pod.CgroupPath()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error retrieving pod %s cgroup", pod.ID())
+		}
+		if ctr.config.CgroupParent != podCgroup {
+			return nil, errors.Wrapf(ErrInvalidArg, "containers of pod %s cannot set a cgroup parent, the pod has resource limits", pod.ID())
+		}
+	}
+
 	// Check CGroup parent sanity, and set it if it was not set
-	switch r.config.CgroupManager {
+	switch r.config().CgroupManager {
 	case CgroupfsCgroupsManager:
 		if ctr.config.CgroupParent == "" {
 			if pod != nil && pod.config.UsePodCgroup {
@@ -132,6 +196,8 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 					return nil, errors.Wrapf(err, "error retrieving pod %s cgroup", pod.ID())
 				}
 				ctr.config.CgroupParent = podCgroup
+			} else if rootless.IsRootless() {
+				ctr.config.CgroupParent = SystemdDefaultRootlessCgroupParent
 			} else {
 				ctr.config.CgroupParent = SystemdDefaultCgroupParent
 			}
@@ -139,7 +205,16 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 			return nil, errors.Wrapf(ErrInvalidArg, "did not receive systemd slice as cgroup parent when using systemd to manage cgroups")
 		}
 	default:
-		return nil, errors.Wrapf(ErrInvalidArg, "unsupported CGroup manager: %s - cannot validate cgroup parent", r.config.CgroupManager)
+		return nil, errors.Wrapf(ErrInvalidArg, "unsupported CGroup manager: %s - cannot validate cgroup parent", r.config().CgroupManager)
+	}
+
+	if ctr.config.LogDriver == "" {
+		ctr.config.LogDriver = KubernetesLogDriver
+		if r.config().LogDriver != "" {
+			if ctr.config.LogDriver, err = validLogDriver(r.config().LogDriver); err != nil {
+				return nil, errors.Wrapf(err, "invalid log_driver in libpod configuration")
+			}
+		}
 	}
 
 	// Set up storage for the container
@@ -157,6 +232,10 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 	if ctr.config.LogPath == "" {
 		ctr.config.LogPath = filepath.Join(ctr.config.StaticDir, "ctr.log")
 	}
+	// conmon always writes its PID, for systemd units to find it
+	if ctr.config.ConmonPidFile == "" {
+		ctr.config.ConmonPidFile = filepath.Join(ctr.state.RunDir, "conmon.pid")
+	}
 	if ctr.config.ShmDir == "" {
 		if ctr.state.UserNSRoot == "" {
 			ctr.config.ShmDir = filepath.Join(ctr.bundlePath(), "shm")
@@ -186,6 +265,7 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 			return nil, err
 		}
 	}
+	ctr.newContainerEvent(events.Create)
 	return ctr, nil
 }
 
@@ -193,15 +273,25 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 // If force is specified, the container will be stopped first
 // Otherwise, RemoveContainer will return an error if the container is running
 func (r *Runtime) RemoveContainer(ctx context.Context, c *Container, force bool) error {
+	// Don't leave a partially removed container behind on termination
+	shutdown.Inhibit()
+	defer shutdown.Uninhibit()
+
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	return r.removeContainer(ctx, c, force)
+	if err := r.checkReadOnly(); err != nil {
+		return err
+	}
+
+	return r.removeContainer(ctx, c, force, false)
 }
 
 // Internal function to remove a container
 // Locks the container, but does not lock the runtime
-func (r *Runtime) removeContainer(ctx context.Context, c *Container, force bool) error {
+// Locks the pod of the container unless podLocked is true, the caller
+// holding its lock
+func (r *Runtime) removeContainer(ctx context.Context, c *Container, force, podLocked bool) error {
 	if !c.valid {
 		if ok, _ := r.state.HasContainer(c.ID()); !ok {
 			// Container probably already removed
@@ -221,8 +311,10 @@ func (r *Runtime) removeContainer(ctx context.Context, c *Container, force bool)
 		}
 
 		// Lock the pod while we're removing container
-		pod.lock.Lock()
-		defer pod.lock.Unlock()
+		if !podLocked {
+			pod.lock.Lock()
+			defer pod.lock.Unlock()
+		}
 		if err := pod.updatePod(); err != nil {
 			return err
 		}
@@ -306,6 +398,8 @@ func (r *Runtime) removeContainer(ctx context.Context, c *Container, force bool)
 		}
 	}
 
+	removed := cleanupErr == nil
+
 	// Set container as invalid so it can no longer be used
 	c.valid = false
 
@@ -340,6 +434,19 @@ func (r *Runtime) removeContainer(ctx context.Context, c *Container, force bool)
 		}
 	}
 
+	// Remove the image built for the container with it
+	if c.config.RootfsImageID != "" {
+		if err := r.removeEphemeralImage(c.config.RootfsImageID); err != nil {
+			logrus.Errorf("remove ephemeral image: %v", err)
+		}
+	}
+
+	// Only notify once the container is cleaned up and its storage removed,
+	// so that its name and directories can be reused
+	if removed {
+		c.newContainerEvent(events.Remove)
+	}
+
 	return cleanupErr
 }
 
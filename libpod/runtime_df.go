@@ -0,0 +1,353 @@
+package libpod
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ImageDiskUsage is the disk space used by an image
+type ImageDiskUsage struct {
+	ID      string
+	Names   []string
+	Created time.Time
+	// Size is the size of the layers of the image
+	Size uint64
+	// SharedSize is the size of the layers of the image other images
+	// share, and UniqueSize the size of those only the image uses
+	SharedSize uint64
+	UniqueSize uint64
+	// Containers is the number of containers using the image, including
+	// those of other tools sharing the storage
+	Containers int
+}
+
+// ContainerDiskUsage is the disk space used by a container
+type ContainerDiskUsage struct {
+	ID      string
+	Name    string
+	Image   string
+	Command []string
+	Created time.Time
+	State   ContainerStatus
+	// Size is the size of the writable layer of the container
+	Size uint64
+	// Volumes is the number of built-in volumes of the container
+	Volumes int
+}
+
+// VolumeDiskUsage is the disk space used by a built-in volume of a
+// container, which holds a volume of its image and is removed with it
+type VolumeDiskUsage struct {
+	// Container is the ID of the container of the volume
+	Container   string
+	Destination string
+	Path        string
+	Size        uint64
+	// Active is true if the container is running or paused
+	Active bool
+}
+
+// DiskUsageSummary is the disk space used by images, containers, volumes or
+// the build cache
+type DiskUsageSummary struct {
+	// Total is their number, and Active the number of those in use: images
+	// used by containers, running or paused containers and their volumes
+	Total  int
+	Active int
+	// Size is the space they use, in bytes, the layers shared between
+	// images counted once
+	Size uint64
+	// Reclaimable is the space removing those not in use would reclaim
+	Reclaimable uint64
+}
+
+// DiskUsage is the disk space used by the runtime
+type DiskUsage struct {
+	// Images are the images, except those of the build cache
+	Images     []ImageDiskUsage
+	Containers []ContainerDiskUsage
+	Volumes    []VolumeDiskUsage
+	// ImagesSummary, ContainersSummary, VolumesSummary and
+	// BuildCacheSummary sum the space used by each. The build cache only
+	// counts the layers other images do not use, and the cache mounts of
+	// builds.
+	ImagesSummary     DiskUsageSummary
+	ContainersSummary DiskUsageSummary
+	VolumesSummary    DiskUsageSummary
+	BuildCacheSummary DiskUsageSummary
+}
+
+// layerSize returns the size of a layer, computing it if the storage does not
+// record it
+func layerSize(store storage.Store, layer *storage.Layer) uint64 {
+	size := layer.UncompressedSize
+	if size <= 0 {
+		var err error
+		if size, err = store.DiffSize(layer.Parent, layer.ID); err != nil {
+			logrus.Debugf("Error computing the size of layer %s: %v", layer.ID, err)
+			return 0
+		}
+	}
+	return uint64(size)
+}
+
+// layerUsage accounts the layers of images, shared between images with the
+// same parents
+type layerUsage struct {
+	sizes map[string]uint64
+	// chains are the layers of the images, by image ID, and users the
+	// number of images using each layer
+	chains map[string][]string
+	users  map[string]int
+}
+
+// newLayerUsage returns the accounting of the layers of the images
+func newLayerUsage(store storage.Store, layers []storage.Layer, images []*image.Image) *layerUsage {
+	u := &layerUsage{
+		sizes:  make(map[string]uint64, len(layers)),
+		chains: make(map[string][]string, len(images)),
+		users:  make(map[string]int, len(layers)),
+	}
+	parents := make(map[string]string, len(layers))
+	for i := range layers {
+		parents[layers[i].ID] = layers[i].Parent
+		u.sizes[layers[i].ID] = layerSize(store, &layers[i])
+	}
+	for _, img := range images {
+		var chain []string
+		for id := img.TopLayer(); id != ""; id = parents[id] {
+			chain = append(chain, id)
+			u.users[id]++
+		}
+		u.chains[img.ID()] = chain
+	}
+	return u
+}
+
+// image returns the size of the layers of an image, and of those other
+// images share
+func (u *layerUsage) image(id string) (uint64, uint64) {
+	var size, shared uint64
+	for _, layer := range u.chains[id] {
+		size += u.sizes[layer]
+		if u.users[layer] > 1 {
+			shared += u.sizes[layer]
+		}
+	}
+	return size, shared
+}
+
+// layers returns the layers of the images, each once
+func (u *layerUsage) layers(images []*image.Image) map[string]bool {
+	layers := make(map[string]bool)
+	for _, img := range images {
+		for _, layer := range u.chains[img.ID()] {
+			layers[layer] = true
+		}
+	}
+	return layers
+}
+
+// size returns the size of the layers, except those of the excluded ones
+func (u *layerUsage) size(layers, excluded map[string]bool) uint64 {
+	var size uint64
+	for layer := range layers {
+		if !excluded[layer] {
+			size += u.sizes[layer]
+		}
+	}
+	return size
+}
+
+// DiskUsage returns the disk space used by the images, containers, built-in
+// volumes of containers and build cache of the runtime, and the space
+// removing those not in use would reclaim
+func (r *Runtime) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+	layers, err := r.store.Layers()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing layers")
+	}
+	images, err := r.imageRuntime.GetImages()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing images")
+	}
+	// Containers of all namespaces and other tools use images too
+	storageContainers, err := r.store.Containers()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing storage containers")
+	}
+	users := make(map[string]int)
+	for _, ctr := range storageContainers {
+		users[ctr.ImageID]++
+	}
+
+	usage := &DiskUsage{
+		Images:     []ImageDiskUsage{},
+		Containers: []ContainerDiskUsage{},
+		Volumes:    []VolumeDiskUsage{},
+	}
+	u := newLayerUsage(r.store, layers, images)
+	var regular, buildCache, used, unusedRegular, unusedBuildCache []*image.Image
+	for _, img := range images {
+		isBuildCache := img.IsBuildCache()
+		if isBuildCache {
+			buildCache = append(buildCache, img)
+		} else {
+			regular = append(regular, img)
+		}
+		switch {
+		case users[img.ID()] > 0:
+			used = append(used, img)
+		case isBuildCache:
+			unusedBuildCache = append(unusedBuildCache, img)
+		default:
+			unusedRegular = append(unusedRegular, img)
+		}
+		if isBuildCache {
+			continue
+		}
+		size, shared := u.image(img.ID())
+		usage.Images = append(usage.Images, ImageDiskUsage{
+			ID:         img.ID(),
+			Names:      img.Names(),
+			Created:    img.Created(),
+			Size:       size,
+			SharedSize: shared,
+			UniqueSize: size - shared,
+			Containers: users[img.ID()],
+		})
+	}
+
+	// Removing the unused images of a kind only reclaims the layers the
+	// images kept do not use
+	regularLayers := u.layers(regular)
+	buildCacheLayers := u.layers(buildCache)
+	usedLayers := u.layers(used)
+	usage.ImagesSummary = DiskUsageSummary{
+		Total:       len(regular),
+		Active:      len(regular) - len(unusedRegular),
+		Size:        u.size(regularLayers, nil),
+		Reclaimable: u.size(u.layers(unusedRegular), mergeLayers(usedLayers, buildCacheLayers)),
+	}
+	mounts, err := r.pruneBuildCacheMounts(time.Time{}, true)
+	if err != nil {
+		return nil, err
+	}
+	usage.BuildCacheSummary = DiskUsageSummary{
+		Total:       len(buildCache),
+		Active:      len(buildCache) - len(unusedBuildCache),
+		Size:        u.size(buildCacheLayers, regularLayers) + mounts,
+		Reclaimable: u.size(u.layers(unusedBuildCache), mergeLayers(usedLayers, regularLayers)) + mounts,
+	}
+
+	ctrs, err := r.GetAllContainers()
+	if err != nil {
+		return nil, err
+	}
+	for _, ctr := range ctrs {
+		state, err := ctr.State()
+		if err != nil {
+			return nil, err
+		}
+		active := state == ContainerStateRunning || state == ContainerStatePaused
+		var size uint64
+		if rwSize, err := ctr.RWSize(); err != nil {
+			logrus.Debugf("Error computing the size of container %s: %v", ctr.ID(), err)
+		} else if rwSize > 0 {
+			size = uint64(rwSize)
+		}
+		volumes, err := ctr.volumesDiskUsage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range volumes {
+			volumes[i].Active = active
+			usage.VolumesSummary.Total++
+			usage.VolumesSummary.Size += volumes[i].Size
+			if active {
+				usage.VolumesSummary.Active++
+			} else {
+				usage.VolumesSummary.Reclaimable += volumes[i].Size
+			}
+		}
+		usage.Volumes = append(usage.Volumes, volumes...)
+
+		usage.Containers = append(usage.Containers, ContainerDiskUsage{
+			ID:      ctr.ID(),
+			Name:    ctr.Name(),
+			Image:   ctr.config.RootfsImageName,
+			Command: ctr.config.Command,
+			Created: ctr.CreatedTime(),
+			State:   state,
+			Size:    size,
+			Volumes: len(volumes),
+		})
+		usage.ContainersSummary.Total++
+		usage.ContainersSummary.Size += size
+		if active {
+			usage.ContainersSummary.Active++
+		} else {
+			usage.ContainersSummary.Reclaimable += size
+		}
+	}
+	return usage, nil
+}
+
+// mergeLayers returns the layers of both sets
+func mergeLayers(a, b map[string]bool) map[string]bool {
+	merged := make(map[string]bool, len(a)+len(b))
+	for layer := range a {
+		merged[layer] = true
+	}
+	for layer := range b {
+		merged[layer] = true
+	}
+	return merged
+}
+
+// volumesDiskUsage returns the disk space used by the built-in volumes of the
+// container, which only exist once it was started
+func (c *Container) volumesDiskUsage(ctx context.Context) ([]VolumeDiskUsage, error) {
+	if c.config.Rootfs != "" || !c.config.ImageVolumes {
+		return nil, nil
+	}
+	root := filepath.Join(c.config.StaticDir, volumesDir)
+	if _, err := os.Stat(root); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "error reading volumes of container %s", c.ID())
+	}
+	destinations, err := c.builtinVolumes(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading volumes of container %s", c.ID())
+	}
+	var volumes []VolumeDiskUsage
+	for _, dest := range destinations {
+		path := filepath.Join(root, dest)
+		size, err := dirSize(path)
+		if err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "error computing the size of volume %s of container %s", dest, c.ID())
+		}
+		volumes = append(volumes, VolumeDiskUsage{
+			Container:   c.ID(),
+			Destination: dest,
+			Path:        path,
+			Size:        size,
+		})
+	}
+	return volumes, nil
+}
@@ -0,0 +1,31 @@
+package libpod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayerUsage(t *testing.T) {
+	u := &layerUsage{
+		sizes: map[string]uint64{"base": 100, "a": 10, "b": 20},
+		chains: map[string][]string{
+			"img-a": {"a", "base"},
+			"img-b": {"b", "base"},
+			"img-c": {"b", "base"},
+		},
+		users: map[string]int{"base": 3, "a": 1, "b": 2},
+	}
+	size, shared := u.image("img-a")
+	assert.Equal(t, uint64(110), size)
+	assert.Equal(t, uint64(100), shared)
+	size, shared = u.image("img-b")
+	assert.Equal(t, uint64(120), size)
+	assert.Equal(t, uint64(120), shared)
+
+	all := map[string]bool{"base": true, "a": true, "b": true}
+	// Shared layers are counted once
+	assert.Equal(t, uint64(130), u.size(all, nil))
+	assert.Equal(t, uint64(10), u.size(all, map[string]bool{"base": true, "b": true}))
+	assert.Equal(t, all, mergeLayers(map[string]bool{"a": true}, map[string]bool{"base": true, "b": true}))
+}
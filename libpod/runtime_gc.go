@@ -0,0 +1,270 @@
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// gcGracePeriod is the age under which layers and files are not collected,
+// as they may belong to a pull or a build in progress
+const gcGracePeriod = time.Hour
+
+// GCReport describes what a garbage collection removed, or would remove
+type GCReport struct {
+	// Layers are the IDs of the layers no image or container uses
+	Layers []string
+	// Paths are the files and directories left behind by interrupted
+	// pulls, by layers that no longer exist and by removed containers
+	Paths []string
+	// Reclaimed is the disk space reclaimed, in bytes
+	Reclaimed int64
+	// Errors are the errors removing layers or paths, which do not stop
+	// the collection
+	Errors []error
+}
+
+// GarbageCollect removes the layers of the storage no image or container
+// uses, the blobs of interrupted pulls, the directories of the storage driver
+// of layers that no longer exist, and the conmon sockets and exit files of
+// removed containers. Layers and files more recent than an hour are kept, as
+// they may belong to a pull or a build in progress. With dryRun, nothing is
+// removed, the report describes what would be.
+func (r *Runtime) GarbageCollect(dryRun bool) (*GCReport, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+	if !dryRun {
+		if err := r.checkReadOnly(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &GCReport{}
+	before := time.Now().Add(-gcGracePeriod)
+	if err := r.collectLayers(report, before, dryRun); err != nil {
+		return nil, err
+	}
+	if err := r.collectDriverDirs(report, before, dryRun); err != nil {
+		return nil, err
+	}
+	collectOldFiles(report, r.imageRuntime.PullCacheDir(), before, dryRun)
+	if err := r.collectContainerFiles(report, dryRun); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// collectLayers removes the layers no image or container uses
+func (r *Runtime) collectLayers(report *GCReport, before time.Time, dryRun bool) error {
+	layers, err := r.store.Layers()
+	if err != nil {
+		return errors.Wrapf(err, "error listing layers")
+	}
+	images, err := r.store.Images()
+	if err != nil {
+		return errors.Wrapf(err, "error listing images")
+	}
+	containers, err := r.store.Containers()
+	if err != nil {
+		return errors.Wrapf(err, "error listing storage containers")
+	}
+	for _, layer := range orphanLayers(layers, images, containers, before) {
+		size := layer.UncompressedSize
+		if size <= 0 {
+			if size, err = r.store.DiffSize(layer.Parent, layer.ID); err != nil {
+				logrus.Debugf("Error computing the size of layer %s: %v", layer.ID, err)
+				size = 0
+			}
+		}
+		if !dryRun {
+			if err := r.store.DeleteLayer(layer.ID); err != nil {
+				report.Errors = append(report.Errors, errors.Wrapf(err, "error removing layer %s", layer.ID))
+				continue
+			}
+		}
+		report.Layers = append(report.Layers, layer.ID)
+		report.Reclaimed += size
+	}
+	return nil
+}
+
+// orphanLayers returns the layers created before a time that no image or
+// container uses, directly or as a parent, children before their parents so
+// that they can be removed in turn
+func orphanLayers(layers []storage.Layer, images []storage.Image, containers []storage.Container, before time.Time) []storage.Layer {
+	byID := make(map[string]*storage.Layer, len(layers))
+	for i := range layers {
+		byID[layers[i].ID] = &layers[i]
+	}
+	used := make(map[string]bool)
+	use := func(id string) {
+		for id != "" && !used[id] {
+			used[id] = true
+			layer, ok := byID[id]
+			if !ok {
+				return
+			}
+			id = layer.Parent
+		}
+	}
+	for _, image := range images {
+		use(image.TopLayer)
+		for _, layer := range image.MappedTopLayers {
+			use(layer)
+		}
+	}
+	for _, container := range containers {
+		use(container.LayerID)
+	}
+
+	// Layers more recent than before, or mounted, are kept with their
+	// parents
+	for _, layer := range layers {
+		if !layer.Created.Before(before) || layer.MountCount > 0 {
+			use(layer.ID)
+		}
+	}
+
+	// The children of unused layers are unused as well
+	children := make(map[string]int)
+	var leaves []string
+	for _, layer := range layers {
+		if layer.Parent != "" {
+			children[layer.Parent]++
+		}
+	}
+	for _, layer := range layers {
+		if !used[layer.ID] && children[layer.ID] == 0 {
+			leaves = append(leaves, layer.ID)
+		}
+	}
+	var orphans []storage.Layer
+	for len(leaves) > 0 {
+		layer := byID[leaves[0]]
+		leaves = leaves[1:]
+		orphans = append(orphans, *layer)
+		if _, ok := byID[layer.Parent]; ok && !used[layer.Parent] {
+			children[layer.Parent]--
+			if children[layer.Parent] == 0 {
+				leaves = append(leaves, layer.Parent)
+			}
+		}
+	}
+	return orphans
+}
+
+// collectDriverDirs removes the directories of the overlay driver of layers
+// that no longer exist, such as those of layers whose creation was
+// interrupted, and their work directories
+func (r *Runtime) collectDriverDirs(report *GCReport, before time.Time, dryRun bool) error {
+	driver := r.store.GraphDriverName()
+	if driver != "overlay" && driver != "overlay2" {
+		return nil
+	}
+	layers, err := r.store.Layers()
+	if err != nil {
+		return errors.Wrapf(err, "error listing layers")
+	}
+	known := map[string]bool{
+		// The directory of the short links to the layers
+		"l": true,
+	}
+	for _, layer := range layers {
+		known[layer.ID] = true
+	}
+	driverDir := filepath.Join(r.store.GraphRoot(), driver)
+	entries, err := ioutil.ReadDir(driverDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "error reading %s", driverDir)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || known[entry.Name()] || !entry.ModTime().Before(before) {
+			continue
+		}
+		collectPath(report, filepath.Join(driverDir, entry.Name()), dryRun)
+	}
+	return nil
+}
+
+// collectContainerFiles removes the conmon sockets and exit files of
+// containers that no longer exist
+// Must be called with the runtime locked, in all namespaces
+func (r *Runtime) collectContainerFiles(report *GCReport, dryRun bool) error {
+	// Containers of all namespaces use the same sockets directory
+	ctrs, err := r.state.AllContainersInAllNamespaces()
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(ctrs))
+	for _, ctr := range ctrs {
+		exists[ctr.ID()] = true
+	}
+	for _, dir := range []string{r.ociRuntime.socketsDir, r.ociRuntime.exitsDir} {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return errors.Wrapf(err, "error reading %s", dir)
+		}
+		for _, entry := range entries {
+			if !exists[entry.Name()] {
+				collectPath(report, filepath.Join(dir, entry.Name()), dryRun)
+			}
+		}
+	}
+	return nil
+}
+
+// collectOldFiles removes the entries of a directory modified before a time
+func collectOldFiles(report *GCReport, dir string, before time.Time, dryRun bool) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			report.Errors = append(report.Errors, errors.Wrapf(err, "error reading %s", dir))
+		}
+		return
+	}
+	for _, entry := range entries {
+		if entry.ModTime().Before(before) {
+			collectPath(report, filepath.Join(dir, entry.Name()), dryRun)
+		}
+	}
+}
+
+// collectPath removes a file or directory, adding it to the report
+func collectPath(report *GCReport, path string, dryRun bool) {
+	size := diskUsage(path)
+	if !dryRun {
+		if err := os.RemoveAll(path); err != nil {
+			report.Errors = append(report.Errors, errors.Wrapf(err, "error removing %s", path))
+			return
+		}
+	}
+	report.Paths = append(report.Paths, path)
+	report.Reclaimed += size
+}
+
+// diskUsage returns the size of the regular files under path
+func diskUsage(path string) int64 {
+	var size int64
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
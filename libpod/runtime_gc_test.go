@@ -0,0 +1,80 @@
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containers/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrphanLayers(t *testing.T) {
+	old := time.Now().Add(-2 * gcGracePeriod)
+	before := time.Now().Add(-gcGracePeriod)
+	layers := []storage.Layer{
+		{ID: "base", Created: old},
+		{ID: "image", Parent: "base", Created: old},
+		{ID: "container", Parent: "image", Created: old},
+		{ID: "orphan", Parent: "base", Created: old},
+		{ID: "orphan-child", Parent: "orphan", Created: old},
+		{ID: "orphan-grandchild", Parent: "orphan-child", Created: old},
+		{ID: "pulling", Created: old},
+		{ID: "pulling-child", Parent: "pulling", Created: time.Now()},
+		{ID: "mounted", Created: old, MountCount: 1},
+		{ID: "mapped", Created: old},
+	}
+	images := []storage.Image{{ID: "img", TopLayer: "image", MappedTopLayers: []string{"mapped"}}}
+	containers := []storage.Container{{ID: "ctr", LayerID: "container"}}
+
+	var ids []string
+	for _, layer := range orphanLayers(layers, images, containers, before) {
+		ids = append(ids, layer.ID)
+	}
+	// Children are removed before their parents
+	assert.Equal(t, []string{"orphan-grandchild", "orphan-child", "orphan"}, ids)
+
+	assert.Empty(t, orphanLayers(layers[:3], images, containers, before))
+	assert.Len(t, orphanLayers(layers[:3], nil, nil, before), 3)
+}
+
+func TestCollectOldFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	old := time.Now().Add(-2 * gcGracePeriod)
+	oldBlob := filepath.Join(dir, "sha256-old")
+	require.NoError(t, ioutil.WriteFile(oldBlob, make([]byte, 1000), 0600))
+	require.NoError(t, os.Chtimes(oldBlob, old, old))
+	oldPartial := filepath.Join(dir, "sha256-partial.partial123")
+	require.NoError(t, ioutil.WriteFile(oldPartial, make([]byte, 24), 0600))
+	require.NoError(t, os.Chtimes(oldPartial, old, old))
+	recentBlob := filepath.Join(dir, "sha256-recent")
+	require.NoError(t, ioutil.WriteFile(recentBlob, make([]byte, 10), 0600))
+
+	before := time.Now().Add(-gcGracePeriod)
+	report := &GCReport{}
+	collectOldFiles(report, dir, before, true)
+	assert.Equal(t, []string{oldBlob, oldPartial}, report.Paths)
+	assert.Equal(t, int64(1024), report.Reclaimed)
+	_, err = os.Stat(oldBlob)
+	assert.NoError(t, err, "dry run removed a file")
+
+	report = &GCReport{}
+	collectOldFiles(report, dir, before, false)
+	assert.Equal(t, []string{oldBlob, oldPartial}, report.Paths)
+	assert.Empty(t, report.Errors)
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "sha256-recent", entries[0].Name())
+
+	report = &GCReport{}
+	collectOldFiles(report, filepath.Join(dir, "missing"), before, false)
+	assert.Empty(t, report.Paths)
+	assert.Empty(t, report.Errors)
+}
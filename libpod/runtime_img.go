@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/containers/libpod/libpod/common"
 	"github.com/containers/libpod/libpod/image"
@@ -12,6 +16,7 @@ import (
 	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/projectatomic/buildah/imagebuildah"
+	"github.com/sirupsen/logrus"
 )
 
 // Runtime API
@@ -56,6 +61,9 @@ func (r *Runtime) RemoveImage(ctx context.Context, img *image.Image, force bool)
 	if !r.valid {
 		return "", ErrRuntimeStopped
 	}
+	if err := r.checkReadOnly(); err != nil {
+		return "", err
+	}
 
 	// Get all containers, filter to only those using the image, and remove those containers
 	ctrs, err := r.state.AllContainers()
@@ -68,10 +76,26 @@ func (r *Runtime) RemoveImage(ctx context.Context, img *image.Image, force bool)
 			imageCtrs = append(imageCtrs, ctr)
 		}
 	}
+	// Containers of other libpod namespaces are not ours to remove
+	otherCtrs := 0
+	if r.config().Namespace != "" {
+		allCtrs, err := r.state.AllContainersInAllNamespaces()
+		if err != nil {
+			return "", err
+		}
+		for _, ctr := range allCtrs {
+			if ctr.config.RootfsImageID == img.ID() && ctr.config.Namespace != r.config().Namespace {
+				otherCtrs++
+			}
+		}
+	}
+	if otherCtrs > 0 && len(img.Names()) <= 1 {
+		return "", errors.Wrapf(ErrNSMismatch, "could not remove image %s as it is being used by %d containers in other libpod namespaces", img.ID(), otherCtrs)
+	}
 	if len(imageCtrs) > 0 && len(img.Names()) <= 1 {
 		if force {
 			for _, ctr := range imageCtrs {
-				if err := r.removeContainer(ctx, ctr, true); err != nil {
+				if err := r.removeContainer(ctx, ctr, true, false); err != nil {
 					return "", errors.Wrapf(err, "error removing image %s: container %s using image could not be removed", img.ID(), ctr.ID())
 				}
 			}
@@ -162,7 +186,127 @@ func removeStorageContainers(ctrIDs []string, store storage.Store) error {
 	return nil
 }
 
-// Build adds the runtime to the imagebuildah call
-func (r *Runtime) Build(ctx context.Context, options imagebuildah.BuildOptions, dockerfiles ...string) error {
-	return imagebuildah.BuildDockerfiles(ctx, r.store, options, dockerfiles...)
+// Build adds the runtime to the imagebuildah call. The secrets, files by
+// ID, are mounted by RUN instructions with secret mounts.
+func (r *Runtime) Build(ctx context.Context, options imagebuildah.BuildOptions, secrets map[string]string, dockerfiles ...string) error {
+	if err := r.checkReadOnly(); err != nil {
+		return err
+	}
+	if !options.Layers {
+		return r.build(ctx, options, secrets, dockerfiles...)
+	}
+
+	// The images committed for the steps of the build are its cache, they
+	// are marked so they can be told apart from the images of users
+	existing, err := r.imageRuntime.ImageIDs()
+	if err != nil {
+		return errors.Wrapf(err, "error listing images")
+	}
+	buildErr := r.build(ctx, options, secrets, dockerfiles...)
+	if err := r.imageRuntime.MarkBuildCache(existing); err != nil {
+		if buildErr != nil {
+			logrus.Errorf("Unable to mark the build cache: %v", err)
+			return buildErr
+		}
+		return err
+	}
+	return buildErr
+}
+
+// BuildEphemeral builds an unnamed image for a single container, which is
+// removed with the last container using it, and returns its ID
+func (r *Runtime) BuildEphemeral(ctx context.Context, options imagebuildah.BuildOptions, dockerfiles ...string) (string, error) {
+	dir, err := ioutil.TempDir("", "podman-ephemeral")
+	if err != nil {
+		return "", errors.Wrapf(err, "error creating build directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			logrus.Errorf("Unable to remove build directory %s: %v", dir, err)
+		}
+	}()
+	options.Output = ""
+	options.AdditionalTags = nil
+	options.IIDFile = filepath.Join(dir, "iid")
+	if err := r.Build(ctx, options, nil, dockerfiles...); err != nil {
+		return "", err
+	}
+	iid, err := ioutil.ReadFile(options.IIDFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading the ID of the built image")
+	}
+	id := strings.TrimPrefix(strings.TrimSpace(string(iid)), "sha256:")
+	if err := r.imageRuntime.MarkEphemeral(id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// removeEphemeralImage removes an image built for a single container once no
+// container uses it
+func (r *Runtime) removeEphemeralImage(id string) error {
+	img, err := r.imageRuntime.NewFromLocal(id)
+	if err != nil {
+		// The image was removed already
+		return nil
+	}
+	if !img.IsEphemeral() {
+		return nil
+	}
+	ctrIDs, err := storageContainers(id, r.store)
+	if err != nil {
+		return errors.Wrapf(err, "error getting containers for image %q", id)
+	}
+	if len(ctrIDs) > 0 {
+		return nil
+	}
+	if err := img.Remove(false); err != nil {
+		return errors.Wrapf(err, "error removing ephemeral image %s", id)
+	}
+	return nil
+}
+
+// PruneBuildCache removes the images of the build cache matching all the
+// filters, which are not used by containers, and returns their IDs and the
+// space reclaimed
+func (r *Runtime) PruneBuildCache(ctx context.Context, filters []image.ResultFilter) ([]string, uint64, error) {
+	if err := r.checkReadOnly(); err != nil {
+		return nil, 0, err
+	}
+	var (
+		removed   []string
+		reclaimed uint64
+	)
+	// Images are removed before their parents, which can only be removed
+	// once they have no children left
+	for {
+		images, err := r.imageRuntime.GetImages()
+		if err != nil {
+			return removed, reclaimed, errors.Wrapf(err, "error getting images")
+		}
+		filters := append([]image.ResultFilter{image.BuildCacheFilter()}, filters...)
+		progress := false
+		for _, img := range image.FilterImages(images, filters) {
+			entry, err := img.BuildCacheEntry()
+			if err != nil {
+				return removed, reclaimed, err
+			}
+			isParent, err := img.IsParent()
+			if err != nil {
+				return removed, reclaimed, err
+			}
+			if isParent || entry.Containers > 0 {
+				continue
+			}
+			if _, err := r.RemoveImage(ctx, img, false); err != nil {
+				return removed, reclaimed, errors.Wrapf(err, "error removing build cache image %s", img.ID())
+			}
+			removed = append(removed, img.ID())
+			reclaimed += uint64(entry.Size)
+			progress = true
+		}
+		if !progress {
+			return removed, reclaimed, nil
+		}
+	}
 }
@@ -0,0 +1,285 @@
+package libpod
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// MigrateStorage moves all images and containers to a new storage driver.
+// The layers of the images are copied to the new driver, then containers are
+// created again on it with the content of their writable layer and of their
+// data directory, keeping their IDs. All containers must be stopped. If the
+// runtime has a libpod namespace, no container may be in another one.
+// The content of the old driver is left in place, it can be removed once the
+// storage driver of the configuration is changed to the new one, which must be
+// done before libpod is used again.
+func (r *Runtime) MigrateStorage(driver string, driverOptions []string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return ErrRuntimeStopped
+	}
+	if err := r.checkReadOnly(); err != nil {
+		return err
+	}
+
+	if driver == r.store.GraphDriverName() {
+		return errors.Wrapf(ErrInvalidArg, "containers already use storage driver %s", driver)
+	}
+
+	return r.migrateStorage(driver, driverOptions)
+}
+
+// migrateStorage moves all images and containers to a new storage driver
+// Must be called with the runtime locked
+func (r *Runtime) migrateStorage(driver string, driverOptions []string) error {
+	// The storage is shared by all libpod namespaces, but the containers
+	// of other namespaces can only be updated without one
+	ctrs, err := r.state.AllContainersInAllNamespaces()
+	if err != nil {
+		return err
+	}
+	for _, ctr := range ctrs {
+		if r.config().Namespace != "" && ctr.config.Namespace != r.config().Namespace {
+			return errors.Wrapf(ErrNSMismatch, "container %s is in libpod namespace %q, storage must be moved outside of libpod namespaces", ctr.ID(), ctr.config.Namespace)
+		}
+	}
+	// The containers stay locked until all of them are moved
+	for _, ctr := range ctrs {
+		ctr.lock.Lock()
+		defer ctr.lock.Unlock()
+
+		if err := ctr.syncContainer(); err != nil {
+			return err
+		}
+		if ctr.state.State != ContainerStateConfigured && ctr.state.State != ContainerStateStopped {
+			return errors.Wrapf(ErrCtrStateInvalid, "container %s is %s, all containers must be stopped to change storage driver", ctr.ID(), ctr.state.State.String())
+		}
+		if ctr.state.Mounted {
+			return errors.Wrapf(ErrCtrStateInvalid, "container %s is mounted, all containers must be unmounted to change storage driver", ctr.ID())
+		}
+	}
+
+	options := r.config().StorageConfig
+	options.GraphDriverName = driver
+	options.GraphDriverOptions = driverOptions
+	newStore, err := storage.GetStore(options)
+	if err != nil {
+		return errors.Wrapf(err, "error opening storage driver %s", driver)
+	}
+	defer func() {
+		if _, err := newStore.Shutdown(false); err != nil {
+			logrus.Errorf("Error shutting down storage driver %s: %v", driver, err)
+		}
+	}()
+
+	images, err := r.store.Images()
+	if err != nil {
+		return err
+	}
+	for _, image := range images {
+		logrus.Debugf("moving image %s to storage driver %s", image.ID, driver)
+		if err := migrateImage(r.store, newStore, image); err != nil {
+			return errors.Wrapf(err, "error moving image %s to storage driver %s", image.ID, driver)
+		}
+	}
+
+	// Only point the containers at the new storage once all of them have
+	// been copied, so they are left untouched if one fails
+	newDirs := make(map[string][2]string)
+	for _, ctr := range ctrs {
+		if ctr.config.Rootfs != "" {
+			continue
+		}
+		logrus.Debugf("moving container %s to storage driver %s", ctr.ID(), driver)
+		if err := migrateContainerStorage(r.store, newStore, ctr.ID()); err != nil {
+			return errors.Wrapf(err, "error moving container %s to storage driver %s", ctr.ID(), driver)
+		}
+		dir, err := newStore.ContainerDirectory(ctr.ID())
+		if err != nil {
+			return err
+		}
+		runDir, err := newStore.ContainerRunDirectory(ctr.ID())
+		if err != nil {
+			return err
+		}
+		newDirs[ctr.ID()] = [2]string{dir, runDir}
+	}
+
+	for _, ctr := range ctrs {
+		dirs, ok := newDirs[ctr.ID()]
+		if !ok {
+			continue
+		}
+		if err := ctr.moveStorage(dirs[0], dirs[1]); err != nil {
+			return err
+		}
+	}
+
+	return r.state.SetGraphDriverName(newStore.GraphDriverName())
+}
+
+// moveStorage points the configuration and state of a container at its data
+// and run directories in a new storage driver
+// Must be called with the container locked
+func (c *Container) moveStorage(dir, runDir string) error {
+	newConfig := *c.config
+	newConfig.StaticDir = dir
+	newConfig.LogPath = rebasePath(c.config.LogPath, c.config.StaticDir, dir)
+	newConfig.ShmDir = rebasePath(c.config.ShmDir, c.config.StaticDir, dir)
+	if err := c.runtime.state.RewriteContainerConfig(c, &newConfig); err != nil {
+		return errors.Wrapf(err, "error updating configuration of container %s", c.ID())
+	}
+
+	c.state.RunDir = runDir
+	if c.state.UserNSRoot == "" {
+		c.state.DestinationRunDir = runDir
+	}
+	// The bind mounts are made again in the new run directory when the
+	// container is started
+	c.state.BindMounts = nil
+	return c.save()
+}
+
+// rebasePath returns the path under newDir of a path under oldDir. Paths
+// outside of oldDir are returned as they are.
+func rebasePath(path, oldDir, newDir string) string {
+	rel, err := filepath.Rel(oldDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return path
+	}
+	return filepath.Join(newDir, rel)
+}
+
+// migrateLayer copies a layer and its parents from a store to another one
+// that does not have them
+func migrateLayer(from, to storage.Store, id string) error {
+	if _, err := to.Layer(id); err == nil {
+		return nil
+	}
+	layer, err := from.Layer(id)
+	if err != nil {
+		return err
+	}
+	if layer.Parent != "" {
+		if err := migrateLayer(from, to, layer.Parent); err != nil {
+			return err
+		}
+	}
+
+	uncompressed := archive.Uncompressed
+	diff, err := from.Diff("", id, &storage.DiffOptions{Compression: &uncompressed})
+	if err != nil {
+		return errors.Wrapf(err, "error reading layer %s", id)
+	}
+	defer diff.Close()
+
+	options := &storage.LayerOptions{
+		IDMappingOptions: storage.IDMappingOptions{
+			UIDMap: layer.UIDMap,
+			GIDMap: layer.GIDMap,
+		},
+	}
+	if _, _, err := to.PutLayer(id, layer.Parent, layer.Names, layer.MountLabel, false, options, diff); err != nil {
+		return errors.Wrapf(err, "error writing layer %s", id)
+	}
+	return nil
+}
+
+// migrateImage copies an image, its layers and its data from a store to
+// another one
+func migrateImage(from, to storage.Store, image storage.Image) error {
+	if _, err := to.Image(image.ID); err == nil {
+		return nil
+	}
+	if image.TopLayer != "" {
+		if err := migrateLayer(from, to, image.TopLayer); err != nil {
+			return err
+		}
+	}
+
+	options := &storage.ImageOptions{
+		CreationDate: image.Created,
+		Digest:       image.Digest,
+	}
+	if _, err := to.CreateImage(image.ID, image.Names, image.TopLayer, image.Metadata, options); err != nil {
+		return err
+	}
+	for _, key := range image.BigDataNames {
+		data, err := from.ImageBigData(image.ID, key)
+		if err != nil {
+			return err
+		}
+		if err := to.SetImageBigData(image.ID, key, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateContainerStorage creates a container again in another store, with
+// the content of its writable layer and of its data directory
+func migrateContainerStorage(from, to storage.Store, id string) error {
+	ctr, err := from.Container(id)
+	if err != nil {
+		return err
+	}
+	// Left over from a move that failed
+	if _, err := to.Container(id); err == nil {
+		if err := to.DeleteContainer(id); err != nil {
+			return err
+		}
+	}
+
+	options := &storage.ContainerOptions{
+		IDMappingOptions: storage.IDMappingOptions{
+			UIDMap: ctr.UIDMap,
+			GIDMap: ctr.GIDMap,
+		},
+	}
+	newCtr, err := to.CreateContainer(ctr.ID, ctr.Names, ctr.ImageID, "", ctr.Metadata, options)
+	if err != nil {
+		return err
+	}
+	if layer, err := from.Layer(ctr.LayerID); err == nil {
+		if err := to.SetNames(newCtr.LayerID, layer.Names); err != nil {
+			return err
+		}
+	}
+
+	uncompressed := archive.Uncompressed
+	diff, err := from.Diff("", ctr.LayerID, &storage.DiffOptions{Compression: &uncompressed})
+	if err != nil {
+		return errors.Wrapf(err, "error reading writable layer")
+	}
+	defer diff.Close()
+	if _, err := to.ApplyDiff(newCtr.LayerID, diff); err != nil {
+		return errors.Wrapf(err, "error writing writable layer")
+	}
+
+	for _, key := range ctr.BigDataNames {
+		data, err := from.ContainerBigData(id, key)
+		if err != nil {
+			return err
+		}
+		if err := to.SetContainerBigData(id, key, data); err != nil {
+			return err
+		}
+	}
+
+	dir, err := from.ContainerDirectory(id)
+	if err != nil {
+		return err
+	}
+	newDir, err := to.ContainerDirectory(id)
+	if err != nil {
+		return err
+	}
+	return archive.NewDefaultArchiver().CopyWithTar(dir, newDir)
+}
@@ -0,0 +1,17 @@
+package libpod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebasePath(t *testing.T) {
+	oldDir := "/var/lib/containers/storage/vfs-containers/abc/userdata"
+	newDir := "/var/lib/containers/storage/overlay-containers/abc/userdata"
+
+	assert.Equal(t, newDir+"/ctr.log", rebasePath(oldDir+"/ctr.log", oldDir, newDir))
+	assert.Equal(t, newDir, rebasePath(oldDir, oldDir, newDir))
+	assert.Equal(t, "/tmp/ctr.log", rebasePath("/tmp/ctr.log", oldDir, newDir))
+	assert.Equal(t, "", rebasePath("", oldDir, newDir))
+}
@@ -0,0 +1,229 @@
+package libpod
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/containers/storage/pkg/stringid"
+	"github.com/docker/docker/pkg/namesgenerator"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RandomNameGenerator generates Docker-style names, made of an
+	// adjective and the name of a scientist
+	RandomNameGenerator = "random"
+	// SequentialNameGenerator generates names made of a prefix and the
+	// lowest number free
+	SequentialNameGenerator = "sequential"
+	// TemplateNameGenerator generates names from a Go template
+	TemplateNameGenerator = "template"
+
+	// ContainerNameKind is the kind of the names of containers
+	ContainerNameKind = "container"
+	// PodNameKind is the kind of the names of pods
+	PodNameKind = "pod"
+)
+
+// NameGenerators are the name generators of the name_generator option of the
+// configuration
+var NameGenerators = []string{RandomNameGenerator, SequentialNameGenerator, TemplateNameGenerator}
+
+// NameRequest describes the container or pod a name is generated for
+type NameRequest struct {
+	// Kind is what is named, ContainerNameKind or PodNameKind
+	Kind string
+	// Image is the name of the image of the container, without its
+	// registry, repository path and tag, such as nginx. It is empty for
+	// pods and containers created from a root filesystem.
+	Image string
+	// Index counts the names generated for the container or pod, from 1. A
+	// name is generated again with the next index as long as the name is
+	// in use or reserved.
+	Index int
+}
+
+// NameGenerator generates the names of the containers and pods created
+// without one
+type NameGenerator interface {
+	// GenerateName returns a name for the request. Names are generated
+	// until one is free, so the names returned for successive indexes must
+	// differ, or an error be returned.
+	GenerateName(request NameRequest) (string, error)
+}
+
+// randomNameGenerator generates Docker-style names
+type randomNameGenerator struct{}
+
+// GenerateName returns a random name, with a random digit appended once names
+// were in use, as Docker does
+func (randomNameGenerator) GenerateName(request NameRequest) (string, error) {
+	return namesgenerator.GetRandomName(request.Index - 1), nil
+}
+
+// sequentialNameGenerator generates names made of a prefix and the lowest
+// number free
+type sequentialNameGenerator struct {
+	// prefix is the kind of what is named followed by a dash if empty
+	prefix string
+}
+
+// GenerateName returns the prefix followed by the index of the request
+func (g *sequentialNameGenerator) GenerateName(request NameRequest) (string, error) {
+	prefix := g.prefix
+	if prefix == "" {
+		prefix = request.Kind + "-"
+	}
+	return prefix + strconv.Itoa(request.Index), nil
+}
+
+// templateNameGenerator generates names from a Go template given the request
+type templateNameGenerator struct {
+	tmpl *template.Template
+}
+
+// execute returns the name of the template for the request
+func (g *templateNameGenerator) execute(request NameRequest) (string, error) {
+	var name bytes.Buffer
+	if err := g.tmpl.Execute(&name, request); err != nil {
+		return "", errors.Wrapf(ErrInvalidArg, "error generating name from template %q: %v", g.tmpl.Root.String(), err)
+	}
+	if name.Len() == 0 {
+		return "", errors.Wrapf(ErrInvalidArg, "template %q generated an empty name", g.tmpl.Root.String())
+	}
+	return name.String(), nil
+}
+
+// GenerateName returns the name of the template for the request. Templates
+// not using the index generate a single name, which is an error once it is
+// taken.
+func (g *templateNameGenerator) GenerateName(request NameRequest) (string, error) {
+	name, err := g.execute(request)
+	if err != nil {
+		return "", err
+	}
+	if request.Index > 1 {
+		first := request
+		first.Index = 1
+		if firstName, err := g.execute(first); err == nil && firstName == name {
+			if request.Kind == PodNameKind {
+				return "", errors.Wrapf(ErrPodExists, "name %s generated from template is in use", name)
+			}
+			return "", errors.Wrapf(ErrCtrExists, "name %s generated from template is in use", name)
+		}
+	}
+	return name, nil
+}
+
+// newNameGenerator returns the name generator of the configuration, checking
+// its options
+func newNameGenerator(config *RuntimeConfig) (NameGenerator, error) {
+	switch config.NameGenerator {
+	case "", RandomNameGenerator:
+		return randomNameGenerator{}, nil
+	case SequentialNameGenerator:
+		return &sequentialNameGenerator{prefix: config.NamePrefix}, nil
+	case TemplateNameGenerator:
+		if config.NameTemplate == "" {
+			return nil, errors.Wrapf(ErrInvalidArg, "name_template must be set with the %s name generator", TemplateNameGenerator)
+		}
+		tmpl, err := template.New("name").Parse(config.NameTemplate)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidArg, "invalid name_template: %v", err)
+		}
+		generator := &templateNameGenerator{tmpl: tmpl}
+		// Catch the fields that do not exist before names are needed
+		if _, err := generator.execute(NameRequest{Kind: ContainerNameKind, Image: "image", Index: 1}); err != nil {
+			return nil, err
+		}
+		return generator, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidArg, "unknown name generator %q, must be one of %s", config.NameGenerator, strings.Join(NameGenerators, ", "))
+}
+
+// shortImageName returns the name of an image without its registry,
+// repository path, tag and digest
+func shortImageName(name string) string {
+	name = name[strings.LastIndex(name, "/")+1:]
+	if i := strings.IndexAny(name, ":@"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// NameReservation is a name reserved for a container or pod created later
+// with its token
+type NameReservation struct {
+	Name    string    `json:"name"`
+	Token   string    `json:"token"`
+	Created time.Time `json:"created"`
+}
+
+// ReserveName reserves a name, so that only the container or pod created with
+// the token of the reservation can use it, see WithNameReservation and
+// WithPodNameReservation. The reservation is claimed as the container or pod
+// is created, atomically, or released with ReleaseName.
+// Names are unique across all libpod namespaces, so are their reservations.
+func (r *Runtime) ReserveName(name string) (*NameReservation, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+
+	if !nameRegex.MatchString(name) {
+		return nil, errors.Wrapf(ErrInvalidArg, "name must match regex [a-zA-Z0-9_-]+")
+	}
+
+	reservation := &NameReservation{
+		Name:    name,
+		Token:   stringid.GenerateRandomID(),
+		Created: time.Now(),
+	}
+	if err := r.state.ReserveName(reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// ReleaseName releases the reservation of a name made with the given token,
+// or whatever its token with force
+func (r *Runtime) ReleaseName(name, token string, force bool) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return ErrRuntimeStopped
+	}
+
+	if force {
+		token = ""
+	} else if token == "" {
+		return errors.Wrapf(ErrInvalidArg, "the token of the reservation of name %s is required", name)
+	}
+	return r.state.ReleaseName(name, token)
+}
+
+// NameReservations returns the reservations of names, sorted by name
+func (r *Runtime) NameReservations() ([]*NameReservation, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+
+	reservations, err := r.state.NameReservations()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].Name < reservations[j].Name
+	})
+	return reservations, nil
+}
@@ -0,0 +1,116 @@
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequentialNameGenerator(t *testing.T) {
+	generator, err := newNameGenerator(&RuntimeConfig{NameGenerator: SequentialNameGenerator})
+	require.NoError(t, err)
+	name, err := generator.GenerateName(NameRequest{Kind: ContainerNameKind, Index: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, "container-3", name)
+	name, err = generator.GenerateName(NameRequest{Kind: PodNameKind, Index: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "pod-1", name)
+
+	generator, err = newNameGenerator(&RuntimeConfig{NameGenerator: SequentialNameGenerator, NamePrefix: "web"})
+	require.NoError(t, err)
+	name, err = generator.GenerateName(NameRequest{Kind: ContainerNameKind, Index: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "web2", name)
+}
+
+func TestTemplateNameGenerator(t *testing.T) {
+	generator, err := newNameGenerator(&RuntimeConfig{
+		NameGenerator: TemplateNameGenerator,
+		NameTemplate:  "{{.Image}}-{{.Index}}",
+	})
+	require.NoError(t, err)
+	name, err := generator.GenerateName(NameRequest{Kind: ContainerNameKind, Image: "nginx", Index: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx-2", name)
+
+	// Templates without the index generate a single name
+	generator, err = newNameGenerator(&RuntimeConfig{
+		NameGenerator: TemplateNameGenerator,
+		NameTemplate:  "{{.Kind}}-{{.Image}}",
+	})
+	require.NoError(t, err)
+	name, err = generator.GenerateName(NameRequest{Kind: ContainerNameKind, Image: "nginx", Index: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "container-nginx", name)
+	_, err = generator.GenerateName(NameRequest{Kind: ContainerNameKind, Image: "nginx", Index: 2})
+	assert.Equal(t, ErrCtrExists, errors.Cause(err))
+	_, err = generator.GenerateName(NameRequest{Kind: PodNameKind, Index: 2})
+	assert.Equal(t, ErrPodExists, errors.Cause(err))
+}
+
+func TestInvalidNameGeneratorConfig(t *testing.T) {
+	for _, config := range []*RuntimeConfig{
+		{NameGenerator: "unknown"},
+		{NameGenerator: TemplateNameGenerator},
+		{NameGenerator: TemplateNameGenerator, NameTemplate: "{{.Image"},
+		{NameGenerator: TemplateNameGenerator, NameTemplate: "{{.Tag}}"},
+	} {
+		_, err := newNameGenerator(config)
+		assert.Equal(t, ErrInvalidArg, errors.Cause(err), "name_generator %q, name_template %q", config.NameGenerator, config.NameTemplate)
+	}
+}
+
+func TestShortImageName(t *testing.T) {
+	for image, name := range map[string]string{
+		"":                                 "",
+		"nginx":                            "nginx",
+		"docker.io/library/nginx:latest":   "nginx",
+		"localhost:5000/team/app":          "app",
+		"quay.io/team/app@sha256:0123abcd": "app",
+	} {
+		assert.Equal(t, name, shortImageName(image), image)
+	}
+}
+
+func TestGenerateNameSkipsTakenNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	state, err := NewInMemoryState()
+	require.NoError(t, err)
+	r := &Runtime{state: state}
+	r.setConfig(&RuntimeConfig{NameGenerator: SequentialNameGenerator, NamePrefix: "test"})
+	// test1 is in use and test2 is reserved
+	ctr, err := getTestCtr1(dir)
+	require.NoError(t, err)
+	require.NoError(t, state.AddContainer(ctr))
+	require.NoError(t, state.ReserveName(&NameReservation{Name: "test2", Token: "token", Created: time.Now()}))
+
+	name, err := r.generateName(ContainerNameKind, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "test3", name)
+}
+
+func TestGenerateNameLooksUpNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	state, err := NewInMemoryState()
+	require.NoError(t, err)
+	r := &Runtime{state: state}
+	r.setConfig(&RuntimeConfig{NameGenerator: TemplateNameGenerator, NameTemplate: "test{{.Index}}"})
+	// test1 is in use and test2 is reserved
+	ctr, err := getTestCtr1(dir)
+	require.NoError(t, err)
+	require.NoError(t, state.AddContainer(ctr))
+	require.NoError(t, state.ReserveName(&NameReservation{Name: "test2", Token: "token", Created: time.Now()}))
+
+	name, err := r.generateName(ContainerNameKind, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "test3", name)
+}
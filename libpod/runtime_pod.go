@@ -32,6 +32,9 @@ func (r *Runtime) RemovePod(ctx context.Context, p *Pod, removeCtrs, force bool)
 	if !r.valid {
 		return ErrRuntimeStopped
 	}
+	if err := r.checkReadOnly(); err != nil {
+		return err
+	}
 
 	if !p.valid {
 		if ok, _ := r.state.HasPod(p.ID()); !ok {
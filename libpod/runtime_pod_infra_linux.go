@@ -8,7 +8,9 @@ import (
 	"github.com/containers/libpod/libpod/image"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/cri-o/ocicni/pkg/ocicni"
+	"github.com/docker/docker/daemon/caps"
 	"github.com/opencontainers/runtime-tools/generate"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -25,8 +27,26 @@ func (r *Runtime) makeInfraContainer(ctx context.Context, p *Pod, imgName, imgID
 		return nil, err
 	}
 
+	infra := p.config.InfraContainer
 	g.SetRootReadonly(true)
-	g.SetProcessArgs([]string{r.config.InfraCommand})
+	if len(infra.Command) > 0 {
+		g.SetProcessArgs(infra.Command)
+	} else {
+		g.SetProcessArgs([]string{r.config().InfraCommand})
+	}
+	if len(infra.CapAdd) > 0 {
+		caplist, err := caps.TweakCapabilities(g.Config.Process.Capabilities.Bounding, infra.CapAdd, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid capabilities of the infra container")
+		}
+		g.Config.Process.Capabilities.Bounding = caplist
+		g.Config.Process.Capabilities.Permitted = caplist
+		g.Config.Process.Capabilities.Effective = caplist
+		g.Config.Process.Capabilities.Inheritable = caplist
+	}
+	for key, value := range infra.Sysctls {
+		g.AddLinuxSysctl(key, value)
+	}
 
 	containerName := p.ID()[:IDTruncLength] + "-infra"
 	var options []CtrCreateOption
@@ -37,8 +57,18 @@ func (r *Runtime) makeInfraContainer(ctx context.Context, p *Pod, imgName, imgID
 
 	// Since user namespace sharing is not implemented, we only need to check if it's rootless
 	portMappings := make([]ocicni.PortMapping, 0)
+	portMappings = append(portMappings, p.config.InfraContainer.PortBindings...)
 	networks := make([]string, 0)
 	options = append(options, WithNetNS(portMappings, rootless.IsRootless(), networks))
+	if len(infra.DNSServer) > 0 {
+		options = append(options, WithDNS(infra.DNSServer))
+	}
+	if len(infra.DNSSearch) > 0 {
+		options = append(options, WithDNSSearch(infra.DNSSearch))
+	}
+	if len(infra.DNSOption) > 0 {
+		options = append(options, WithDNSOption(infra.DNSOption))
+	}
 
 	return r.newContainer(ctx, g.Config, options...)
 }
@@ -51,7 +81,11 @@ func (r *Runtime) createInfraContainer(ctx context.Context, p *Pod) (*Container,
 		return nil, ErrRuntimeStopped
 	}
 
-	newImage, err := r.ImageRuntime().New(ctx, r.config.InfraImage, "", "", nil, nil, image.SigningOptions{}, false, false)
+	infraImage := r.config().InfraImage
+	if p.config.InfraContainer.Image != "" {
+		infraImage = p.config.InfraContainer.Image
+	}
+	newImage, err := r.ImageRuntime().New(ctx, infraImage, "", "", nil, nil, image.SigningOptions{}, false, false)
 	if err != nil {
 		return nil, err
 	}
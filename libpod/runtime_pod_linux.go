@@ -8,8 +8,11 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/containerd/cgroups"
+	"github.com/containers/libpod/libpod/events"
+	"github.com/containers/libpod/pkg/rootless"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -22,6 +25,9 @@ func (r *Runtime) NewPod(ctx context.Context, options ...PodCreateOption) (*Pod,
 	if !r.valid {
 		return nil, ErrRuntimeStopped
 	}
+	if err := r.checkReadOnly(); err != nil {
+		return nil, err
+	}
 
 	pod, err := newPod(r.lockDir, r)
 	if err != nil {
@@ -30,8 +36,8 @@ func (r *Runtime) NewPod(ctx context.Context, options ...PodCreateOption) (*Pod,
 
 	// Set default namespace to runtime's namespace
 	// Do so before options run so they can override it
-	if r.config.Namespace != "" {
-		pod.config.Namespace = r.config.Namespace
+	if r.config().Namespace != "" {
+		pod.config.Namespace = r.config().Namespace
 	}
 
 	for _, option := range options {
@@ -40,8 +46,21 @@ func (r *Runtime) NewPod(ctx context.Context, options ...PodCreateOption) (*Pod,
 		}
 	}
 
+	if class := pod.config.Labels[RuntimeClassLabel]; class != "" {
+		if err := validRuntimeHandler(class); err != nil {
+			return nil, errors.Wrapf(err, "invalid runtime class of pod")
+		}
+		if r.ociRuntime.handlerPath(class) == "" {
+			return nil, errors.Wrapf(ErrInvalidArg, "could not find an OCI runtime for runtime class %q, check %s_runtime_path in libpod.conf", class, class)
+		}
+		// The VM of the pod is started with its infra container
+		if class == KataRuntimeHandler && !pod.config.InfraContainer.HasInfraContainer {
+			return nil, errors.Wrapf(ErrInvalidArg, "pods of runtime class %q must have an infra container", class)
+		}
+	}
+
 	if pod.config.Name == "" {
-		name, err := r.generateName()
+		name, err := r.generateName(PodNameKind, "")
 		if err != nil {
 			return nil, err
 		}
@@ -50,8 +69,19 @@ func (r *Runtime) NewPod(ctx context.Context, options ...PodCreateOption) (*Pod,
 
 	pod.valid = true
 
+	// The limits of the pod are those of its cgroup, which rootless
+	// pods cannot set
+	if pod.resources() != nil {
+		if !pod.config.UsePodCgroup {
+			return nil, errors.Wrapf(ErrInvalidArg, "pods must use a pod cgroup to have resource limits")
+		}
+		if rootless.IsRootless() {
+			return nil, errors.Wrapf(ErrInvalidArg, "rootless pods cannot have resource limits")
+		}
+	}
+
 	// Check CGroup parent sanity, and set it if it was not set
-	switch r.config.CgroupManager {
+	switch r.config().CgroupManager {
 	case CgroupfsCgroupsManager:
 		if pod.config.CgroupParent == "" {
 			pod.config.CgroupParent = CgroupfsDefaultCgroupParent
@@ -61,27 +91,41 @@ func (r *Runtime) NewPod(ctx context.Context, options ...PodCreateOption) (*Pod,
 		// If we are set to use pod cgroups, set the cgroup parent that
 		// all containers in the pod will share
 		// No need to create it with cgroupfs - the first container to
-		// launch should do it for us
+		// launch should do it for us, unless the pod has limits
 		if pod.config.UsePodCgroup {
 			pod.state.CgroupPath = filepath.Join(pod.config.CgroupParent, pod.ID())
+			if resources := pod.resources(); resources != nil {
+				if err := makeCgroupfsCgroup(pod.state.CgroupPath, resources); err != nil {
+					return nil, errors.Wrapf(err, "unable to create pod cgroup for pod %s", pod.ID())
+				}
+			}
 		}
 	case SystemdCgroupsManager:
 		if pod.config.CgroupParent == "" {
 			pod.config.CgroupParent = SystemdDefaultCgroupParent
+			if rootless.IsRootless() {
+				pod.config.CgroupParent = SystemdDefaultRootlessCgroupParent
+			}
 		} else if len(pod.config.CgroupParent) < 6 || !strings.HasSuffix(path.Base(pod.config.CgroupParent), ".slice") {
 			return nil, errors.Wrapf(ErrInvalidArg, "did not receive systemd slice as cgroup parent when using systemd to manage cgroups")
 		}
+		// Slices can only be created in the systemd instance of the
+		// system, rootless pods have none
+		if pod.config.UsePodCgroup && rootless.IsRootless() {
+			logrus.Debugf("Not creating a cgroup for rootless pod %s", pod.ID())
+			pod.config.UsePodCgroup = false
+		}
 		// If we are set to use pod cgroups, set the cgroup parent that
 		// all containers in the pod will share
 		if pod.config.UsePodCgroup {
-			cgroupPath, err := systemdSliceFromPath(pod.config.CgroupParent, fmt.Sprintf("libpod_pod_%s", pod.ID()))
+			cgroupPath, err := systemdSliceFromPath(pod.config.CgroupParent, fmt.Sprintf("libpod_pod_%s", pod.ID()), pod.resources())
 			if err != nil {
 				return nil, errors.Wrapf(err, "unable to create pod cgroup for pod %s", pod.ID())
 			}
 			pod.state.CgroupPath = cgroupPath
 		}
 	default:
-		return nil, errors.Wrapf(ErrInvalidArg, "unsupported CGroup manager: %s - cannot validate cgroup parent", r.config.CgroupManager)
+		return nil, errors.Wrapf(ErrInvalidArg, "unsupported CGroup manager: %s - cannot validate cgroup parent", r.config().CgroupManager)
 	}
 
 	if pod.config.UsePodCgroup {
@@ -102,6 +146,13 @@ func (r *Runtime) NewPod(ctx context.Context, options ...PodCreateOption) (*Pod,
 			// a pause container, and it does not.
 			if err2 := r.removePod(ctx, pod, true, true); err2 != nil {
 				logrus.Errorf("Error removing pod after pause container creation failure: %v", err2)
+			} else if pod.nameReservation != "" {
+				// Give the name back to the reservation the pod claimed,
+				// so that creating the pod can be retried
+				reservation := &NameReservation{Name: pod.Name(), Token: pod.nameReservation, Created: time.Now()}
+				if err2 := r.state.ReserveName(reservation); err2 != nil {
+					logrus.Errorf("Error restoring the reservation of name %s: %v", pod.Name(), err2)
+				}
 			}
 			return nil, errors.Wrapf(err, "error adding Infra Container")
 		}
@@ -110,6 +161,7 @@ func (r *Runtime) NewPod(ctx context.Context, options ...PodCreateOption) (*Pod,
 			return nil, err
 		}
 	}
+	pod.newPodEvent(events.Create)
 
 	return pod, nil
 }
@@ -245,7 +297,7 @@ func (r *Runtime) removePod(ctx context.Context, p *Pod, removeCtrs, force bool)
 	if p.state.CgroupPath != "" {
 		logrus.Debugf("Removing pod cgroup %s", p.state.CgroupPath)
 
-		switch p.runtime.config.CgroupManager {
+		switch p.runtime.config().CgroupManager {
 		case SystemdCgroupsManager:
 			if err := deleteSystemdCgroup(p.state.CgroupPath); err != nil {
 				// The pod is already almost gone.
@@ -267,7 +319,7 @@ func (r *Runtime) removePod(ctx context.Context, p *Pod, removeCtrs, force bool)
 				}
 			}
 		default:
-			return errors.Wrapf(ErrInvalidArg, "unknown cgroups manager %s specified", p.runtime.config.CgroupManager)
+			return errors.Wrapf(ErrInvalidArg, "unknown cgroups manager %s specified", p.runtime.config().CgroupManager)
 		}
 	}
 
@@ -275,6 +327,7 @@ func (r *Runtime) removePod(ctx context.Context, p *Pod, removeCtrs, force bool)
 	if err := r.state.RemovePod(p); err != nil {
 		return err
 	}
+	p.newPodEvent(events.Remove)
 
 	// Mark pod invalid
 	p.valid = false
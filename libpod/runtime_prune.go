@@ -0,0 +1,288 @@
+package libpod
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// PruneOptions selects what Prune removes
+type PruneOptions struct {
+	// Containers removes the containers that are not running or paused
+	Containers bool
+	// Images removes the dangling images, those without a name, that no
+	// container uses and that are not the parents of other images
+	Images bool
+	// AllImages removes the named images no container uses as well
+	AllImages bool
+	// BuildCache removes the images of the build cache no container uses,
+	// and the cache mounts of RUN instructions
+	BuildCache bool
+	// Until only removes what was created before it, if not zero
+	Until time.Time
+	// Labels only removes the containers and images with all the labels,
+	// as KEY or KEY=VALUE
+	Labels []string
+	// DryRun removes nothing, the report describes what would be removed
+	DryRun bool
+}
+
+// PruneReport describes what Prune removed, or would remove
+type PruneReport struct {
+	// Containers, Images and BuildCache are the IDs of the containers,
+	// images and images of the build cache removed
+	Containers []string
+	Images     []string
+	BuildCache []string
+	// ContainersReclaimed, ImagesReclaimed and BuildCacheReclaimed are the
+	// disk space reclaimed by each of them, in bytes: the size of the
+	// layers nothing else uses, and of the cache mounts of builds
+	ContainersReclaimed uint64
+	ImagesReclaimed     uint64
+	BuildCacheReclaimed uint64
+	// Errors are the errors removing containers and images, which do not
+	// stop the pruning
+	Errors []error
+}
+
+// Reclaimed returns the total disk space reclaimed, in bytes
+func (p *PruneReport) Reclaimed() uint64 {
+	return p.ContainersReclaimed + p.ImagesReclaimed + p.BuildCacheReclaimed
+}
+
+// matchLabels returns true if the labels match all the filters, as KEY or
+// KEY=VALUE
+func matchLabels(labels map[string]string, filters []string) bool {
+	for _, filter := range filters {
+		split := strings.SplitN(filter, "=", 2)
+		value, ok := labels[split[0]]
+		if !ok || (len(split) == 2 && value != split[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// prunePlan is the state of the storage once the containers and images
+// planned are removed, to tell which images become unused and the layers
+// their removal frees
+type prunePlan struct {
+	store         storage.Store
+	layers        []storage.Layer
+	images        []*image.Image
+	storageImages []storage.Image
+	containers    []storage.Container
+	// removedImages and removedContainers are the IDs of the images and
+	// storage containers planned for removal
+	removedImages     map[string]bool
+	removedContainers map[string]bool
+	// orphaned are the layers unused once the plan is applied
+	orphaned map[string]bool
+}
+
+// newPrunePlan returns the plan of the current state of the storage
+func (r *Runtime) newPrunePlan() (*prunePlan, error) {
+	p := &prunePlan{
+		store:             r.store,
+		removedImages:     make(map[string]bool),
+		removedContainers: make(map[string]bool),
+	}
+	var err error
+	if p.layers, err = r.store.Layers(); err != nil {
+		return nil, errors.Wrapf(err, "error listing layers")
+	}
+	if p.images, err = r.imageRuntime.GetImages(); err != nil {
+		return nil, errors.Wrapf(err, "error listing images")
+	}
+	if p.storageImages, err = r.store.Images(); err != nil {
+		return nil, errors.Wrapf(err, "error listing images")
+	}
+	// Containers of all namespaces and other tools use images too
+	if p.containers, err = r.store.Containers(); err != nil {
+		return nil, errors.Wrapf(err, "error listing storage containers")
+	}
+	p.orphaned = p.orphanLayers()
+	return p, nil
+}
+
+// orphanLayers returns the layers no image or container left uses
+func (p *prunePlan) orphanLayers() map[string]bool {
+	var images []storage.Image
+	for _, img := range p.storageImages {
+		if !p.removedImages[img.ID] {
+			images = append(images, img)
+		}
+	}
+	var containers []storage.Container
+	for _, ctr := range p.containers {
+		if !p.removedContainers[ctr.ID] {
+			containers = append(containers, ctr)
+		}
+	}
+	orphaned := make(map[string]bool)
+	for _, layer := range orphanLayers(p.layers, images, containers, time.Now().Add(time.Hour)) {
+		orphaned[layer.ID] = true
+	}
+	return orphaned
+}
+
+// reclaim updates the layers the plan frees, and returns the size of those
+// freed since the last call
+func (p *prunePlan) reclaim() uint64 {
+	orphaned := p.orphanLayers()
+	var size uint64
+	for i := range p.layers {
+		if orphaned[p.layers[i].ID] && !p.orphaned[p.layers[i].ID] {
+			size += layerSize(p.store, &p.layers[i])
+		}
+	}
+	p.orphaned = orphaned
+	return size
+}
+
+// unusedImages plans the removal of the images matching match that no
+// container left uses and that are not the parents of images left, and
+// returns them children first, so that they can be removed in turn
+func (p *prunePlan) unusedImages(match func(*image.Image) bool) []*image.Image {
+	parents := make(map[string]string, len(p.layers))
+	for _, layer := range p.layers {
+		parents[layer.ID] = layer.Parent
+	}
+	var removed []*image.Image
+	for {
+		used := make(map[string]bool)
+		for _, ctr := range p.containers {
+			if !p.removedContainers[ctr.ID] {
+				used[ctr.ImageID] = true
+			}
+		}
+		hasChildren := make(map[string]bool)
+		for _, img := range p.images {
+			if parent := parents[img.TopLayer()]; parent != "" && !p.removedImages[img.ID()] {
+				hasChildren[parent] = true
+			}
+		}
+		progress := false
+		for _, img := range p.images {
+			if p.removedImages[img.ID()] || used[img.ID()] || hasChildren[img.TopLayer()] || !match(img) {
+				continue
+			}
+			p.removedImages[img.ID()] = true
+			removed = append(removed, img)
+			progress = true
+		}
+		if !progress {
+			return removed
+		}
+	}
+}
+
+// Prune removes the containers that are not running, the unused images and
+// the build cache selected by the options, in one pass, and returns what it
+// removed and the disk space reclaimed. Images only used by the containers
+// removed are removed as well. Failures to remove containers or images are
+// reported, and do not stop the pruning. With DryRun, nothing is removed, the
+// report describes what would be.
+func (r *Runtime) Prune(ctx context.Context, options PruneOptions) (*PruneReport, error) {
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+	if !options.DryRun {
+		if err := r.checkReadOnly(); err != nil {
+			return nil, err
+		}
+	}
+	created := func(t time.Time) bool {
+		return options.Until.IsZero() || t.Before(options.Until)
+	}
+	plan, err := r.newPrunePlan()
+	if err != nil {
+		return nil, err
+	}
+	report := &PruneReport{}
+
+	var ctrs []*Container
+	if options.Containers {
+		ctrs, err = r.GetContainers(func(c *Container) bool {
+			if c.IsInfra() || !created(c.CreatedTime()) || !matchLabels(c.Labels(), options.Labels) {
+				return false
+			}
+			state, err := c.State()
+			if err != nil {
+				logrus.Debugf("Error getting the state of container %s: %v", c.ID(), err)
+				return false
+			}
+			return state != ContainerStateRunning && state != ContainerStatePaused
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, ctr := range ctrs {
+			plan.removedContainers[ctr.ID()] = true
+		}
+		report.ContainersReclaimed = plan.reclaim()
+	}
+
+	matchImage := func(img *image.Image) bool {
+		if !created(img.Created()) {
+			return false
+		}
+		if len(options.Labels) > 0 {
+			labels, err := img.Labels(ctx)
+			if err != nil || !matchLabels(labels, options.Labels) {
+				return false
+			}
+		}
+		return true
+	}
+	var images []*image.Image
+	if options.Images {
+		images = plan.unusedImages(func(img *image.Image) bool {
+			return (options.AllImages || img.Dangling()) && !img.IsBuildCache() && matchImage(img)
+		})
+		report.ImagesReclaimed = plan.reclaim()
+	}
+	var buildCache []*image.Image
+	if options.BuildCache {
+		buildCache = plan.unusedImages(func(img *image.Image) bool {
+			return img.IsBuildCache() && matchImage(img)
+		})
+		report.BuildCacheReclaimed = plan.reclaim()
+		mountsReclaimed, err := r.pruneBuildCacheMounts(options.Until, options.DryRun)
+		if err != nil {
+			report.Errors = append(report.Errors, err)
+		}
+		report.BuildCacheReclaimed += mountsReclaimed
+	}
+
+	for _, ctr := range ctrs {
+		if !options.DryRun {
+			if err := r.RemoveContainer(ctx, ctr, false); err != nil {
+				report.Errors = append(report.Errors, errors.Wrapf(err, "error removing container %s", ctr.ID()))
+				continue
+			}
+		}
+		report.Containers = append(report.Containers, ctr.ID())
+	}
+	removeImages := func(images []*image.Image) []string {
+		var removed []string
+		for _, img := range images {
+			if !options.DryRun {
+				if _, err := r.RemoveImage(ctx, img, false); err != nil {
+					report.Errors = append(report.Errors, errors.Wrapf(err, "error removing image %s", img.ID()))
+					continue
+				}
+			}
+			removed = append(removed, img.ID())
+		}
+		return removed
+	}
+	report.Images = removeImages(images)
+	report.BuildCache = removeImages(buildCache)
+	return report, nil
+}
@@ -0,0 +1,45 @@
+package libpod
+
+import (
+	"testing"
+
+	"github.com/containers/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"env": "test", "app": ""}
+	assert.True(t, matchLabels(labels, nil))
+	assert.True(t, matchLabels(labels, []string{"env"}))
+	assert.True(t, matchLabels(labels, []string{"env=test", "app"}))
+	assert.True(t, matchLabels(labels, []string{"app="}))
+	assert.False(t, matchLabels(labels, []string{"env=prod"}))
+	assert.False(t, matchLabels(labels, []string{"env", "other"}))
+	assert.False(t, matchLabels(nil, []string{"env"}))
+}
+
+func TestPrunePlanReclaim(t *testing.T) {
+	p := &prunePlan{
+		layers: []storage.Layer{
+			{ID: "base", UncompressedSize: 100},
+			{ID: "image", Parent: "base", UncompressedSize: 10},
+			{ID: "ctr1", Parent: "image", UncompressedSize: 1},
+			{ID: "ctr2", Parent: "image", UncompressedSize: 2},
+		},
+		storageImages:     []storage.Image{{ID: "img", TopLayer: "image"}},
+		containers:        []storage.Container{{ID: "c1", LayerID: "ctr1"}, {ID: "c2", LayerID: "ctr2"}},
+		removedImages:     make(map[string]bool),
+		removedContainers: make(map[string]bool),
+	}
+	p.orphaned = p.orphanLayers()
+	assert.Empty(t, p.orphaned)
+
+	p.removedContainers["c1"] = true
+	assert.Equal(t, uint64(1), p.reclaim())
+	// Layers freed before are not counted again
+	p.removedContainers["c2"] = true
+	assert.Equal(t, uint64(2), p.reclaim())
+	p.removedImages["img"] = true
+	assert.Equal(t, uint64(110), p.reclaim())
+	assert.Equal(t, uint64(0), p.reclaim())
+}
@@ -0,0 +1,87 @@
+package libpod
+
+// readOnlyState is the state of a runtime in read-only mode. It gives access
+// to containers and pods, but rejects any change to them.
+type readOnlyState struct {
+	State
+}
+
+// Refresh is rejected in read-only mode
+func (s *readOnlyState) Refresh() error {
+	return ErrRuntimeReadOnly
+}
+
+// AddContainer is rejected in read-only mode
+func (s *readOnlyState) AddContainer(ctr *Container) error {
+	return ErrRuntimeReadOnly
+}
+
+// RemoveContainer is rejected in read-only mode
+func (s *readOnlyState) RemoveContainer(ctr *Container) error {
+	return ErrRuntimeReadOnly
+}
+
+// SaveContainer is rejected in read-only mode
+func (s *readOnlyState) SaveContainer(ctr *Container) error {
+	return ErrRuntimeReadOnly
+}
+
+// RewriteContainerConfig is rejected in read-only mode
+func (s *readOnlyState) RewriteContainerConfig(ctr *Container, newCfg *ContainerConfig) error {
+	return ErrRuntimeReadOnly
+}
+
+// AddPod is rejected in read-only mode
+func (s *readOnlyState) AddPod(pod *Pod) error {
+	return ErrRuntimeReadOnly
+}
+
+// RemovePod is rejected in read-only mode
+func (s *readOnlyState) RemovePod(pod *Pod) error {
+	return ErrRuntimeReadOnly
+}
+
+// RemovePodContainers is rejected in read-only mode
+func (s *readOnlyState) RemovePodContainers(pod *Pod) error {
+	return ErrRuntimeReadOnly
+}
+
+// AddContainerToPod is rejected in read-only mode
+func (s *readOnlyState) AddContainerToPod(pod *Pod, ctr *Container) error {
+	return ErrRuntimeReadOnly
+}
+
+// RemoveContainerFromPod is rejected in read-only mode
+func (s *readOnlyState) RemoveContainerFromPod(pod *Pod, ctr *Container) error {
+	return ErrRuntimeReadOnly
+}
+
+// SavePod is rejected in read-only mode
+func (s *readOnlyState) SavePod(pod *Pod) error {
+	return ErrRuntimeReadOnly
+}
+
+// SetGraphDriverName is rejected in read-only mode
+func (s *readOnlyState) SetGraphDriverName(name string) error {
+	return ErrRuntimeReadOnly
+}
+
+// ReserveName is rejected in read-only mode
+func (s *readOnlyState) ReserveName(reservation *NameReservation) error {
+	return ErrRuntimeReadOnly
+}
+
+// ReleaseName is rejected in read-only mode
+func (s *readOnlyState) ReleaseName(name, token string) error {
+	return ErrRuntimeReadOnly
+}
+
+// checkReadOnly returns an error if the runtime is in read-only mode. It is
+// called before operations that change containers, pods or images outside of
+// the state, so they are rejected before doing anything.
+func (r *Runtime) checkReadOnly() error {
+	if r.config().ReadOnly {
+		return ErrRuntimeReadOnly
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package libpod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyStateRejectsChanges(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		testCtr1, err := getTestCtr1(lockPath)
+		require.NoError(t, err)
+		testCtr2, err := getTestCtr2(lockPath)
+		require.NoError(t, err)
+		testPod, err := getTestPod1(lockPath)
+		require.NoError(t, err)
+
+		err = state.AddContainer(testCtr1)
+		require.NoError(t, err)
+
+		roState := &readOnlyState{state}
+
+		assert.Equal(t, ErrRuntimeReadOnly, roState.AddContainer(testCtr2))
+		assert.Equal(t, ErrRuntimeReadOnly, roState.SaveContainer(testCtr1))
+		assert.Equal(t, ErrRuntimeReadOnly, roState.RemoveContainer(testCtr1))
+		assert.Equal(t, ErrRuntimeReadOnly, roState.AddPod(testPod))
+		assert.Equal(t, ErrRuntimeReadOnly, roState.Refresh())
+
+		ctrs, err := roState.AllContainers()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(ctrs))
+
+		ctr, err := roState.LookupContainer(testCtr1.Name())
+		assert.NoError(t, err)
+		testContainersEqual(t, ctr, testCtr1, true)
+	})
+}
@@ -0,0 +1,163 @@
+package libpod
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/containers/image/signature"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/ulule/deepcopier"
+)
+
+// reloadableConfig are the options of the configuration files, by TOML key,
+// that ReloadConfig applies: they are read as they are used, such as the
+// defaults of the containers created. The others configure the storage,
+// state, OCI runtime and networks set up when the runtime is created.
+var reloadableConfig = map[string]bool{
+	"signature_policy_path": true,
+	"env":                   true,
+	"default_ulimits":       true,
+	"tz":                    true,
+	"locale":                true,
+	"log_driver":            true,
+	"network_mode":          true,
+	"seccomp_profile":       true,
+	"image_run_options":     true,
+	"name_generator":        true,
+	"name_prefix":           true,
+	"name_template":         true,
+}
+
+// ConfigReload is the outcome of the reload of the configuration files of a
+// runtime
+type ConfigReload struct {
+	// Reloaded are the options applied, by TOML key
+	Reloaded []string
+	// RestartRequired are the options changed that only apply once the
+	// runtime is created again
+	RestartRequired []string
+}
+
+// tomlKey returns the TOML key of a field of RuntimeConfig, or "" if it is
+// not read from the configuration files
+func tomlKey(field reflect.StructField) string {
+	key := strings.Split(field.Tag.Get("toml"), ",")[0]
+	if key == "-" {
+		return ""
+	}
+	return key
+}
+
+// changedConfig returns the indexes of the fields of RuntimeConfig read from
+// the configuration files that differ between the configurations
+func changedConfig(old, new *RuntimeConfig) []int {
+	var changed []int
+	oldValue, newValue := reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem()
+	for i := 0; i < oldValue.NumField(); i++ {
+		if tomlKey(oldValue.Type().Field(i)) == "" {
+			continue
+		}
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
+
+// validateReloadableConfig returns an error if the options ReloadConfig
+// applies are invalid
+func validateReloadableConfig(config *RuntimeConfig) error {
+	for _, env := range config.Env {
+		if !strings.Contains(env, "=") || strings.HasPrefix(env, "=") {
+			return errors.Wrapf(ErrInvalidArg, "invalid environment variable %q in env, must be KEY=VALUE", env)
+		}
+	}
+	for _, ulimit := range config.DefaultUlimits {
+		if _, err := units.ParseUlimit(ulimit); err != nil {
+			return errors.Wrapf(ErrInvalidArg, "invalid ulimit %q in default_ulimits: %v", ulimit, err)
+		}
+	}
+	if config.LogDriver != "" {
+		if _, err := validLogDriver(config.LogDriver); err != nil {
+			return errors.Wrapf(err, "invalid log_driver")
+		}
+	}
+	if config.SeccompProfile != "" {
+		if _, err := os.Stat(config.SeccompProfile); err != nil {
+			return errors.Wrapf(ErrInvalidArg, "invalid seccomp_profile: %v", err)
+		}
+	}
+	if config.SignaturePolicyPath != "" {
+		if _, err := signature.NewPolicyFromFile(config.SignaturePolicyPath); err != nil {
+			return errors.Wrapf(ErrInvalidArg, "invalid signature policy %s: %v", config.SignaturePolicyPath, err)
+		}
+	}
+	if _, err := newNameGenerator(config); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReloadConfig reads the configuration files of the runtime again and applies
+// the changes of the options read as they are used, such as the defaults of
+// the containers created, so long-running services pick them up without a
+// restart. The changes of the other options are reported, they require the
+// runtime to be created again. The configuration is validated first, and is
+// rejected as a whole if it is invalid, or if validate, when not nil, returns
+// an error for it. Options set when the runtime was created are kept unless
+// the files change them.
+func (r *Runtime) ReloadConfig(validate func(*RuntimeConfig) error) (*ConfigReload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+
+	fileConfig, err := r.readConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateReloadableConfig(fileConfig); err != nil {
+		return nil, err
+	}
+	if validate != nil {
+		if err := validate(fileConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	// The configuration is replaced rather than modified, so its readers
+	// always see a consistent one
+	config := new(RuntimeConfig)
+	deepcopier.Copy(r.config()).To(config)
+	reload := &ConfigReload{}
+	configValue, fileValue := reflect.ValueOf(config).Elem(), reflect.ValueOf(fileConfig).Elem()
+	oldFileValue := reflect.ValueOf(r.fileConfig).Elem()
+	for _, i := range changedConfig(r.fileConfig, fileConfig) {
+		key := tomlKey(configValue.Type().Field(i))
+		if !reloadableConfig[key] {
+			// Reported until the runtime is created again
+			fileValue.Field(i).Set(oldFileValue.Field(i))
+			reload.RestartRequired = append(reload.RestartRequired, key)
+			continue
+		}
+		configValue.Field(i).Set(fileValue.Field(i))
+		reload.Reloaded = append(reload.Reloaded, key)
+	}
+
+	r.setConfig(config)
+	r.fileConfig = fileConfig
+	r.imageRuntime.SetSignaturePolicyPath(config.SignaturePolicyPath)
+
+	if len(reload.Reloaded) > 0 {
+		logrus.Infof("Reloaded configuration options %s", strings.Join(reload.Reloaded, ", "))
+	}
+	if len(reload.RestartRequired) > 0 {
+		logrus.Warnf("Configuration options %s changed, they only apply once restarted", strings.Join(reload.RestartRequired, ", "))
+	}
+	return reload, nil
+}
@@ -0,0 +1,70 @@
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/libpod/libpod/image"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadConfig(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "reload")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	configPath := filepath.Join(tmp, "libpod.conf")
+	writeConfig := func(content string) {
+		require.NoError(t, ioutil.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	writeConfig("tz = \"UTC\"\ninfra_image = \"k8s.gcr.io/pause:3.1\"\n")
+	fileConfig, err := readConfigFile(configPath)
+	require.NoError(t, err)
+	config := new(RuntimeConfig)
+	*config = *fileConfig
+	// Options set when the runtime was created are kept
+	config.CgroupManager = CgroupfsCgroupsManager
+	r := &Runtime{
+		valid:        true,
+		fileConfig:   fileConfig,
+		imageRuntime: &image.Runtime{},
+		readConfig: func() (*RuntimeConfig, error) {
+			return readConfigFile(configPath)
+		},
+	}
+	r.setConfig(config)
+
+	writeConfig("tz = \"local\"\ninfra_image = \"localhost/pause\"\ndefault_ulimits = [\"nofile=1024:2048\"]\n")
+	reload, err := r.ReloadConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default_ulimits", "tz"}, reload.Reloaded)
+	assert.Equal(t, []string{"infra_image"}, reload.RestartRequired)
+	assert.Equal(t, "local", r.config().TZ)
+	assert.Equal(t, []string{"nofile=1024:2048"}, r.config().DefaultUlimits)
+	assert.Equal(t, "k8s.gcr.io/pause:3.1", r.config().InfraImage)
+	assert.Equal(t, CgroupfsCgroupsManager, r.config().CgroupManager)
+
+	// Options requiring a restart are reported until then
+	reload, err = r.ReloadConfig(nil)
+	require.NoError(t, err)
+	assert.Empty(t, reload.Reloaded)
+	assert.Equal(t, []string{"infra_image"}, reload.RestartRequired)
+
+	// Invalid configurations are rejected as a whole
+	writeConfig("tz = \"UTC\"\nenv = [\"NOVALUE\"]\n")
+	_, err = r.ReloadConfig(nil)
+	assert.Equal(t, ErrInvalidArg, errors.Cause(err))
+	writeConfig("tz = \"UTC\"\nlog_driver = \"journald\"\n")
+	_, err = r.ReloadConfig(nil)
+	assert.Equal(t, ErrInvalidArg, errors.Cause(err))
+	writeConfig("tz = \"UTC\"\n")
+	_, err = r.ReloadConfig(func(*RuntimeConfig) error {
+		return ErrInvalidArg
+	})
+	assert.Equal(t, ErrInvalidArg, errors.Cause(err))
+	assert.Equal(t, "local", r.config().TZ)
+}
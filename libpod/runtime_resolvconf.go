@@ -0,0 +1,152 @@
+package libpod
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// hostResolvConf is the resolv.conf of the host
+	hostResolvConf = "/etc/resolv.conf"
+	// systemdResolvedConf is the resolv.conf written by systemd-resolved,
+	// listing the upstream name servers instead of its local stub resolver
+	systemdResolvedConf = "/run/systemd/resolve/resolv.conf"
+	// resolvConfSettleTime is how long to wait for changes to the host
+	// resolv.conf to stop before updating containers, as network managers
+	// usually rewrite it several times in a row
+	resolvConfSettleTime = 200 * time.Millisecond
+)
+
+// hostResolvConfPath returns the path of the resolv.conf of the host to base
+// the resolv.conf of a container on. Containers with their own network
+// namespace can not reach a resolver listening on the loopback interface of
+// the host, such as the stub resolver of systemd-resolved, so they use the
+// upstream name servers of systemd-resolved instead.
+func hostResolvConfPath(ownNetNS bool) (string, error) {
+	// Determine the endpoint for resolv.conf in case it is a symlink
+	resolvPath, err := filepath.EvalSymlinks(hostResolvConf)
+	if err != nil {
+		return "", err
+	}
+	if !ownNetNS {
+		return resolvPath, nil
+	}
+	contents, err := ioutil.ReadFile(resolvPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read %s", resolvPath)
+	}
+	nameServers := createResolv(string(contents)).nameServers
+	if len(nameServers) == 0 {
+		return resolvPath, nil
+	}
+	for _, server := range nameServers {
+		if ip := net.ParseIP(server); ip == nil || !ip.IsLoopback() {
+			return resolvPath, nil
+		}
+	}
+	if _, err := os.Stat(systemdResolvedConf); err != nil {
+		return resolvPath, nil
+	}
+	return systemdResolvedConf, nil
+}
+
+// UpdateResolvConf regenerates the resolv.conf of running containers
+// following the resolv.conf of the host, so that they see changes to the name
+// servers of the host, e.g. after connecting to a VPN
+func (r *Runtime) UpdateResolvConf() error {
+	ctrs, err := r.GetRunningContainers()
+	if err != nil {
+		return err
+	}
+	var lastError error
+	for _, ctr := range ctrs {
+		if !ctr.tracksHostResolvConf() {
+			continue
+		}
+		if err := ctr.updateRunningResolvConf(); err != nil {
+			if lastError != nil {
+				logrus.Errorf("%v", lastError)
+			}
+			lastError = errors.Wrapf(err, "error updating resolv.conf of container %s", ctr.ID())
+		}
+	}
+	return lastError
+}
+
+// updateRunningResolvConf updates the resolv.conf of the container if it is
+// still running
+func (c *Container) updateRunningResolvConf() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.syncContainer(); err != nil {
+		return err
+	}
+	if c.state.State != ContainerStateRunning {
+		return nil
+	}
+	return c.updateResolvConf()
+}
+
+// WatchResolvConf updates the resolv.conf of running containers whenever the
+// resolv.conf of the host or the one of systemd-resolved changes, until the
+// context is cancelled
+func (r *Runtime) WatchResolvConf(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrapf(err, "error creating resolv.conf watcher")
+	}
+	defer watcher.Close()
+
+	// Files are usually replaced rather than written to, so watch the
+	// directories holding them
+	watched := map[string]bool{
+		hostResolvConf:      true,
+		systemdResolvedConf: true,
+	}
+	if target, err := filepath.EvalSymlinks(hostResolvConf); err == nil {
+		watched[target] = true
+	}
+	dirs := make(map[string]bool)
+	for path := range watched {
+		dir := filepath.Dir(path)
+		if dirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return errors.Wrapf(err, "error watching %s", dir)
+		}
+		dirs[dir] = true
+	}
+
+	settle := time.NewTimer(resolvConfSettleTime)
+	settle.Stop()
+	for {
+		select {
+		case event := <-watcher.Events:
+			if watched[event.Name] {
+				settle.Reset(resolvConfSettleTime)
+			}
+		case err := <-watcher.Errors:
+			logrus.Errorf("Error watching resolv.conf: %v", err)
+		case <-settle.C:
+			logrus.Debugf("Host resolv.conf changed, updating containers")
+			if err := r.UpdateResolvConf(); err != nil {
+				logrus.Errorf("%v", err)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package libpod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakenNamesSeesOtherNamespaces(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		testCtr1, err := getTestCtr1(lockPath)
+		require.NoError(t, err)
+		testCtr1.config.Namespace = "test1"
+		testCtr2, err := getTestCtr2(lockPath)
+		require.NoError(t, err)
+		testCtr2.config.Namespace = "test2"
+
+		require.NoError(t, state.AddContainer(testCtr1))
+		require.NoError(t, state.AddContainer(testCtr2))
+
+		r := &Runtime{state: state}
+		r.setConfig(&RuntimeConfig{Namespace: "test1"})
+		require.NoError(t, state.SetNamespace("test1"))
+
+		taken, err := r.takenNames()
+		require.NoError(t, err)
+		assert.True(t, taken[testCtr1.Name()])
+		assert.True(t, taken[testCtr2.Name()])
+
+		// The namespace of the state is left as it is
+		ctrs, err := state.AllContainers()
+		assert.NoError(t, err)
+		require.Equal(t, 1, len(ctrs))
+		assert.Equal(t, testCtr1.ID(), ctrs[0].ID())
+	})
+}
@@ -0,0 +1,147 @@
+package shutdown
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrHandlerExists is returned when registering a handler under a
+	// name that is already in use
+	ErrHandlerExists = errors.New("a shutdown handler with that name already exists")
+	// ErrNoSuchHandler is returned when unregistering a handler that is
+	// not registered
+	ErrNoSuchHandler = errors.New("no shutdown handler with that name exists")
+
+	lock          sync.Mutex
+	handlers      = make(map[string]func(os.Signal) error)
+	handlerOrder  []string
+	sigChan       chan os.Signal
+	stopChan      chan struct{}
+	inhibitLock   sync.RWMutex
+	exitFunc      = os.Exit
+	shutdownSigns = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+)
+
+// Start starts catching SIGINT and SIGTERM. When one of them is received,
+// critical sections marked with Inhibit are allowed to finish, the registered
+// handlers are run, and the process exits.
+// Calling Start while already started does nothing.
+func Start() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if sigChan != nil {
+		return
+	}
+
+	sigChan = make(chan os.Signal, 1)
+	stopChan = make(chan struct{})
+	signal.Notify(sigChan, shutdownSigns...)
+
+	go func(sigChan chan os.Signal, stopChan chan struct{}) {
+		select {
+		case sig := <-sigChan:
+			shutdown(sig)
+		case <-stopChan:
+		}
+	}(sigChan, stopChan)
+}
+
+// Stop stops catching signals, restoring their default behavior, e.g. so
+// they can be forwarded to a container instead. Calling Stop while not
+// started does nothing.
+func Stop() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if sigChan == nil {
+		return
+	}
+
+	signal.Stop(sigChan)
+	close(stopChan)
+	sigChan = nil
+	stopChan = nil
+}
+
+// Register registers a handler run on shutdown under the given name. Handlers
+// run in reverse order of registration, so that handlers of operations using
+// e.g. the runtime run before the handler shutting down the runtime.
+func Register(name string, handler func(os.Signal) error) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, ok := handlers[name]; ok {
+		return errors.Wrapf(ErrHandlerExists, "handler %s", name)
+	}
+	handlers[name] = handler
+	handlerOrder = append(handlerOrder, name)
+	return nil
+}
+
+// Unregister removes the handler registered under the given name
+func Unregister(name string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, ok := handlers[name]; !ok {
+		return errors.Wrapf(ErrNoSuchHandler, "handler %s", name)
+	}
+	delete(handlers, name)
+	for i, n := range handlerOrder {
+		if n == name {
+			handlerOrder = append(handlerOrder[:i], handlerOrder[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Inhibit marks the start of a critical section that a shutdown must not
+// interrupt, such as creating or removing a container. It must be paired with
+// a call to Uninhibit. Critical sections may run concurrently.
+func Inhibit() {
+	inhibitLock.RLock()
+}
+
+// Uninhibit marks the end of a critical section started with Inhibit
+func Uninhibit() {
+	inhibitLock.RUnlock()
+}
+
+// shutdown waits for critical sections to finish, runs all handlers and
+// exits with the conventional status for termination by the given signal
+func shutdown(sig os.Signal) {
+	logrus.Debugf("Received %s, shutting down", sig)
+
+	// Wait for critical sections in progress, and keep new ones from
+	// starting, as the process is about to exit
+	inhibitLock.Lock()
+
+	lock.Lock()
+	order := append([]string{}, handlerOrder...)
+	toRun := make(map[string]func(os.Signal) error, len(handlers))
+	for name, handler := range handlers {
+		toRun[name] = handler
+	}
+	lock.Unlock()
+
+	for i := len(order) - 1; i >= 0; i-- {
+		name := order[i]
+		if err := toRun[name](sig); err != nil {
+			logrus.Errorf("Error running shutdown handler %s: %v", name, err)
+		}
+	}
+
+	status := 1
+	if s, ok := sig.(syscall.Signal); ok {
+		status = 128 + int(s)
+	}
+	exitFunc(status)
+}
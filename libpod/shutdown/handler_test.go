@@ -0,0 +1,51 @@
+package shutdown
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterDuplicate(t *testing.T) {
+	assert.NoError(t, Register("dup", func(os.Signal) error { return nil }))
+	defer Unregister("dup")
+
+	err := Register("dup", func(os.Signal) error { return nil })
+	assert.Equal(t, ErrHandlerExists, errors.Cause(err))
+}
+
+func TestUnregisterMissing(t *testing.T) {
+	err := Unregister("missing")
+	assert.Equal(t, ErrNoSuchHandler, errors.Cause(err))
+}
+
+func TestShutdownRunsHandlersInReverseOrder(t *testing.T) {
+	var ran []string
+	for _, name := range []string{"first", "second", "third"} {
+		name := name
+		assert.NoError(t, Register(name, func(os.Signal) error {
+			ran = append(ran, name)
+			return nil
+		}))
+		defer Unregister(name)
+	}
+	assert.NoError(t, Unregister("second"))
+	assert.NoError(t, Register("second", func(os.Signal) error {
+		ran = append(ran, "second")
+		return nil
+	}))
+
+	status := 0
+	exitFunc = func(code int) { status = code }
+	defer func() {
+		exitFunc = os.Exit
+		inhibitLock.Unlock()
+	}()
+
+	shutdown(syscall.SIGTERM)
+	assert.Equal(t, []string{"second", "third", "first"}, ran)
+	assert.Equal(t, 128+int(syscall.SIGTERM), status)
+}
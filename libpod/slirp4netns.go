@@ -0,0 +1,124 @@
+package libpod
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Slirp4netnsNetworkOptions is the key of the options of slirp4netns, which
+// configures the network of rootless containers, in the network options of a
+// container
+const Slirp4netnsNetworkOptions = "slirp4netns"
+
+// slirp4netnsOptions are the options given to slirp4netns when it configures
+// the network namespace of a rootless container
+type slirp4netnsOptions struct {
+	// mtu is the MTU of the tap device, the default of slirp4netns if 0
+	mtu int
+	// cidr is the network of the container, 10.0.2.0/24 if nil
+	cidr *net.IPNet
+	// disableHostLoopback prevents the container from connecting to the
+	// loopback addresses of the host through the gateway
+	disableHostLoopback bool
+}
+
+// parseSlirp4netnsOptions parses options given as key=value
+func parseSlirp4netnsOptions(options []string) (*slirp4netnsOptions, error) {
+	opts := &slirp4netnsOptions{}
+	for _, o := range options {
+		parts := strings.SplitN(o, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Wrapf(ErrInvalidArg, "slirp4netns option %q must be key=value", o)
+		}
+		key, value := parts[0], parts[1]
+		switch key {
+		case "mtu":
+			mtu, err := strconv.Atoi(value)
+			if err != nil || mtu < 68 || mtu > 65521 {
+				return nil, errors.Wrapf(ErrInvalidArg, "invalid slirp4netns MTU %q, it must be between 68 and 65521", value)
+			}
+			opts.mtu = mtu
+		case "cidr":
+			ip, cidr, err := net.ParseCIDR(value)
+			if err != nil || ip.To4() == nil {
+				return nil, errors.Wrapf(ErrInvalidArg, "invalid slirp4netns CIDR %q, it must be an IPv4 network", value)
+			}
+			if ones, _ := cidr.Mask.Size(); ones > 25 {
+				return nil, errors.Wrapf(ErrInvalidArg, "slirp4netns CIDR %q is too small, its prefix must be at most 25 bits", value)
+			}
+			opts.cidr = cidr
+		case "allow_host_loopback":
+			allow, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, errors.Wrapf(ErrInvalidArg, "invalid slirp4netns allow_host_loopback %q, it must be true or false", value)
+			}
+			opts.disableHostLoopback = !allow
+		default:
+			return nil, errors.Wrapf(ErrInvalidArg, "unknown slirp4netns option %q", key)
+		}
+	}
+	return opts, nil
+}
+
+// args returns the command line arguments of slirp4netns for the options
+func (o *slirp4netnsOptions) args() []string {
+	var args []string
+	if o.mtu != 0 {
+		args = append(args, fmt.Sprintf("--mtu=%d", o.mtu))
+	}
+	if o.cidr != nil {
+		args = append(args, fmt.Sprintf("--cidr=%s", o.cidr.String()))
+	}
+	if o.disableHostLoopback {
+		args = append(args, "--disable-host-loopback")
+	}
+	return args
+}
+
+// slirp4netnsHostFwd is a port of the host slirp4netns forwards to the
+// container
+type slirp4netnsHostFwd struct {
+	Proto     string `json:"proto"`
+	HostAddr  string `json:"host_addr"`
+	HostPort  int32  `json:"host_port"`
+	GuestPort int32  `json:"guest_port"`
+}
+
+// slirp4netnsAddHostFwd asks slirp4netns, through its API socket, to forward a
+// port of the host to the container
+func slirp4netnsAddHostFwd(apiSocket string, fwd *slirp4netnsHostFwd) error {
+	conn, err := net.Dial("unix", apiSocket)
+	if err != nil {
+		return errors.Wrapf(err, "cannot connect to the slirp4netns API socket %s", apiSocket)
+	}
+	defer conn.Close()
+
+	request := struct {
+		Execute   string              `json:"execute"`
+		Arguments *slirp4netnsHostFwd `json:"arguments"`
+	}{"add_hostfwd", fwd}
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return errors.Wrapf(err, "cannot send request to slirp4netns")
+	}
+	// slirp4netns reads the request until the end of the stream
+	if err := conn.(*net.UnixConn).CloseWrite(); err != nil {
+		return errors.Wrapf(err, "cannot send request to slirp4netns")
+	}
+	var response struct {
+		Error *struct {
+			Desc string `json:"desc"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return errors.Wrapf(err, "cannot read response of slirp4netns")
+	}
+	if response.Error != nil {
+		return errors.Errorf("slirp4netns cannot forward %s port %d: %s", fwd.Proto, fwd.HostPort, response.Error.Desc)
+	}
+	return nil
+}
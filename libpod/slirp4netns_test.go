@@ -0,0 +1,74 @@
+package libpod
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSlirp4netnsOptions(t *testing.T) {
+	opts, err := parseSlirp4netnsOptions(nil)
+	require.NoError(t, err)
+	assert.Empty(t, opts.args())
+
+	opts, err = parseSlirp4netnsOptions([]string{"mtu=65520", "cidr=10.0.10.0/24", "allow_host_loopback=false"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--mtu=65520", "--cidr=10.0.10.0/24", "--disable-host-loopback"}, opts.args())
+
+	opts, err = parseSlirp4netnsOptions([]string{"allow_host_loopback=true"})
+	require.NoError(t, err)
+	assert.Empty(t, opts.args())
+
+	for _, o := range []string{"mtu", "mtu=0", "mtu=65522", "cidr=10.0.10.0/26", "cidr=fd00::/64", "cidr=10.0.10.1", "allow_host_loopback=maybe", "port_handler=rootlesskit"} {
+		_, err := parseSlirp4netnsOptions([]string{o})
+		assert.Error(t, err, o)
+	}
+}
+
+func TestSlirp4netnsAddHostFwd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "slirp4netns")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	apiSocket := filepath.Join(dir, "api.sock")
+
+	// The API socket refuses ports below 1024, as slirp4netns does
+	// without privileges
+	l, err := net.Listen("unix", apiSocket)
+	require.NoError(t, err)
+	defer l.Close()
+	requests := make(chan map[string]interface{}, 2)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			data, _ := ioutil.ReadAll(conn)
+			var request struct {
+				Execute   string                 `json:"execute"`
+				Arguments map[string]interface{} `json:"arguments"`
+			}
+			json.Unmarshal(data, &request)
+			requests <- request.Arguments
+			if request.Execute == "add_hostfwd" && request.Arguments["host_port"].(float64) >= 1024 {
+				conn.Write([]byte(`{"return": {"id": 1}}`))
+			} else {
+				conn.Write([]byte(`{"error": {"desc": "bad request: add_hostfwd: slirp_add_hostfwd failed"}}`))
+			}
+			conn.Close()
+		}
+	}()
+
+	require.NoError(t, slirp4netnsAddHostFwd(apiSocket, &slirp4netnsHostFwd{Proto: "tcp", HostAddr: "0.0.0.0", HostPort: 8080, GuestPort: 80}))
+	assert.Equal(t, map[string]interface{}{"proto": "tcp", "host_addr": "0.0.0.0", "host_port": 8080.0, "guest_port": 80.0}, <-requests)
+
+	err = slirp4netnsAddHostFwd(apiSocket, &slirp4netnsHostFwd{Proto: "tcp", HostAddr: "0.0.0.0", HostPort: 80, GuestPort: 80})
+	assert.Error(t, err)
+	<-requests
+}
@@ -58,6 +58,11 @@ type State interface {
 	// SaveContainer saves a container's current state to the backing store.
 	// The container must be part of the set namespace.
 	SaveContainer(ctr *Container) error
+	// RewriteContainerConfig replaces the configuration of a container.
+	// The configuration is otherwise immutable, this is only meant to move
+	// containers to a new storage driver.
+	// The container must be part of the set namespace.
+	RewriteContainerConfig(ctr *Container, newCfg *ContainerConfig) error
 	// ContainerInUse checks if other containers depend upon a given
 	// container.
 	// It returns a slice of the IDs of containers which depend on the given
@@ -70,6 +75,9 @@ type State interface {
 	// If a namespace is set, only containers within the namespace will be
 	// returned.
 	AllContainers() ([]*Container, error)
+	// Retrieves all containers presently in state, whatever their
+	// namespace and the set namespace.
+	AllContainersInAllNamespaces() ([]*Container, error)
 
 	// Accepts full ID of pod.
 	// If the pod given is not in the set namespace, an error will be
@@ -127,4 +135,26 @@ type State interface {
 	// If a namespace has been set, only pods in that namespace will be
 	// returned.
 	AllPods() ([]*Pod, error)
+	// Retrieves all pods presently in state, whatever their namespace and
+	// the set namespace.
+	AllPodsInAllNamespaces() ([]*Pod, error)
+
+	// SetGraphDriverName records the storage driver the containers in the
+	// state now use, once they have been moved to it.
+	SetGraphDriverName(name string) error
+
+	// ReserveName reserves a name for a container or pod added later with
+	// the token of the reservation, which claims it. Other containers and
+	// pods cannot be added with the name until it is released.
+	// The name must not be in use or reserved. Names are global, their
+	// reservations are not part of any namespace.
+	ReserveName(reservation *NameReservation) error
+	// ReleaseName releases the reservation of a name, which must have been
+	// made with the given token, or with any token if it is empty.
+	ReleaseName(name, token string) error
+	// NameReservations retrieves the reservations of all names.
+	NameReservations() ([]*NameReservation, error)
+	// NameInUse checks if a container or pod of any namespace has the
+	// given name, or if it is reserved.
+	NameInUse(name string) (bool, error)
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/containers/storage"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -46,8 +47,8 @@ func getEmptyBoltState() (s State, p string, p2 string, err error) {
 	lockDir := filepath.Join(tmpDir, "locks")
 
 	runtime := new(Runtime)
-	runtime.config = new(RuntimeConfig)
-	runtime.config.StorageConfig = storage.StoreOptions{}
+	runtime.setConfig(new(RuntimeConfig))
+	runtime.config().StorageConfig = storage.StoreOptions{}
 
 	state, err := NewBoltState(dbPath, lockDir, runtime)
 	if err != nil {
@@ -924,6 +925,30 @@ func TestGetAllContainersNoContainerInNamespace(t *testing.T) {
 	})
 }
 
+func TestGetAllContainersInAllNamespaces(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		testCtr1, err := getTestCtr1(lockPath)
+		assert.NoError(t, err)
+
+		testCtr1.config.Namespace = "test1"
+
+		testCtr2, err := getTestCtr2(lockPath)
+		assert.NoError(t, err)
+
+		err = state.AddContainer(testCtr1)
+		assert.NoError(t, err)
+
+		err = state.AddContainer(testCtr2)
+		assert.NoError(t, err)
+
+		state.SetNamespace("test2")
+
+		ctrs, err := state.AllContainersInAllNamespaces()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(ctrs))
+	})
+}
+
 func TestGetContainerOneContainerInNamespace(t *testing.T) {
 	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
 		testCtr1, err := getTestCtr1(lockPath)
@@ -2044,6 +2069,30 @@ func TestAllPodsPodInDifferentNamespaces(t *testing.T) {
 	})
 }
 
+func TestAllPodsInAllNamespaces(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		testPod1, err := getTestPod1(lockPath)
+		assert.NoError(t, err)
+
+		testPod1.config.Namespace = "test1"
+
+		testPod2, err := getTestPod2(lockPath)
+		assert.NoError(t, err)
+
+		err = state.AddPod(testPod1)
+		assert.NoError(t, err)
+
+		err = state.AddPod(testPod2)
+		assert.NoError(t, err)
+
+		state.SetNamespace("test2")
+
+		allPods, err := state.AllPodsInAllNamespaces()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(allPods))
+	})
+}
+
 func TestAllPodsOnePodInDifferentNamespace(t *testing.T) {
 	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
 		testPod1, err := getTestPod1(lockPath)
@@ -3517,3 +3566,168 @@ func TestSaveAndUpdatePodSameNamespace(t *testing.T) {
 		testPodsEqual(t, testPod, statePod, false)
 	})
 }
+
+func TestRewriteContainerConfig(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		testCtr, err := getTestCtr1(lockPath)
+		assert.NoError(t, err)
+
+		err = state.AddContainer(testCtr)
+		assert.NoError(t, err)
+
+		newConfig := *testCtr.config
+		newConfig.StaticDir = "/var/lib/containers/storage/overlay-containers/" + testCtr.ID() + "/userdata"
+		err = state.RewriteContainerConfig(testCtr, &newConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, newConfig.StaticDir, testCtr.config.StaticDir)
+
+		retrievedCtr, err := state.Container(testCtr.ID())
+		assert.NoError(t, err)
+		assert.Equal(t, newConfig.StaticDir, retrievedCtr.config.StaticDir)
+	})
+}
+
+func TestRewriteContainerConfigNoSuchCtrFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		testCtr, err := getTestCtr1(lockPath)
+		assert.NoError(t, err)
+
+		err = state.RewriteContainerConfig(testCtr, testCtr.config)
+		assert.Error(t, err)
+	})
+}
+
+func TestReserveNameAndCreateContainerWithToken(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		reservation := &NameReservation{Name: "test1", Token: "token", Created: time.Now()}
+		err := state.ReserveName(reservation)
+		assert.NoError(t, err)
+
+		reservations, err := state.NameReservations()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(reservations))
+		assert.Equal(t, "test1", reservations[0].Name)
+		assert.Equal(t, "token", reservations[0].Token)
+
+		testCtr, err := getTestCtr1(lockPath)
+		assert.NoError(t, err)
+		err = state.AddContainer(testCtr)
+		assert.Error(t, err)
+		assert.Equal(t, ErrNameReserved, errors.Cause(err))
+
+		testCtr.nameReservation = "wrong"
+		err = state.AddContainer(testCtr)
+		assert.Equal(t, ErrNameReserved, errors.Cause(err))
+
+		testCtr.nameReservation = "token"
+		err = state.AddContainer(testCtr)
+		assert.NoError(t, err)
+
+		// The reservation is claimed by the container
+		reservations, err = state.NameReservations()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(reservations))
+	})
+}
+
+func TestCreateContainerWithoutReservationFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		testCtr, err := getTestCtr1(lockPath)
+		assert.NoError(t, err)
+		testCtr.nameReservation = "token"
+
+		err = state.AddContainer(testCtr)
+		assert.Equal(t, ErrNoSuchNameReservation, errors.Cause(err))
+
+		ctrs, err := state.AllContainers()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(ctrs))
+	})
+}
+
+func TestReserveNameAndCreatePodWithToken(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		err := state.ReserveName(&NameReservation{Name: "test1", Token: "token", Created: time.Now()})
+		assert.NoError(t, err)
+
+		testPod, err := getTestPod1(lockPath)
+		assert.NoError(t, err)
+		err = state.AddPod(testPod)
+		assert.Equal(t, ErrNameReserved, errors.Cause(err))
+
+		testPod.nameReservation = "token"
+		err = state.AddPod(testPod)
+		assert.NoError(t, err)
+
+		reservations, err := state.NameReservations()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(reservations))
+	})
+}
+
+func TestReserveNameInUseFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		testCtr, err := getTestCtr1(lockPath)
+		assert.NoError(t, err)
+		err = state.AddContainer(testCtr)
+		assert.NoError(t, err)
+
+		err = state.ReserveName(&NameReservation{Name: testCtr.Name(), Token: "token", Created: time.Now()})
+		assert.Equal(t, ErrCtrExists, errors.Cause(err))
+	})
+}
+
+func TestReserveNameTwiceFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		err := state.ReserveName(&NameReservation{Name: "test1", Token: "token1", Created: time.Now()})
+		assert.NoError(t, err)
+
+		err = state.ReserveName(&NameReservation{Name: "test1", Token: "token2", Created: time.Now()})
+		assert.Equal(t, ErrNameReserved, errors.Cause(err))
+	})
+}
+
+func TestReleaseName(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		err := state.ReserveName(&NameReservation{Name: "test1", Token: "token", Created: time.Now()})
+		assert.NoError(t, err)
+
+		err = state.ReleaseName("test1", "wrong")
+		assert.Equal(t, ErrNameReserved, errors.Cause(err))
+
+		err = state.ReleaseName("test1", "token")
+		assert.NoError(t, err)
+
+		err = state.ReleaseName("test1", "")
+		assert.Equal(t, ErrNoSuchNameReservation, errors.Cause(err))
+
+		// The name is free again
+		testCtr, err := getTestCtr1(lockPath)
+		assert.NoError(t, err)
+		err = state.AddContainer(testCtr)
+		assert.NoError(t, err)
+	})
+}
+
+func TestNameInUse(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, lockPath string) {
+		testCtr, err := getTestCtr1(lockPath)
+		assert.NoError(t, err)
+		testCtr.config.Namespace = "test1"
+		err = state.AddContainer(testCtr)
+		assert.NoError(t, err)
+
+		err = state.ReserveName(&NameReservation{Name: "test2", Token: "token", Created: time.Now()})
+		assert.NoError(t, err)
+
+		// Names are global, whatever the namespace of the state
+		err = state.SetNamespace("test2")
+		assert.NoError(t, err)
+
+		for name, inUse := range map[string]bool{"test1": true, "test2": true, "test3": false} {
+			used, err := state.NameInUse(name)
+			assert.NoError(t, err)
+			assert.Equal(t, inUse, used, name)
+		}
+	})
+}
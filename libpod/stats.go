@@ -3,12 +3,18 @@
 package libpod
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/containerd/cgroups"
+	cg "github.com/containers/libpod/pkg/cgroups"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // GetContainerStats gets the running stats for a given container
@@ -33,19 +39,14 @@ func (c *Container) GetContainerStats(previousStats *ContainerStats) (*Container
 	if err != nil {
 		return nil, err
 	}
-
-	cgroup, err := cgroups.Load(cgroups.V1, cgroups.StaticPath(cgroupPath))
-	if err != nil {
-		return stats, errors.Wrapf(err, "unable to load cgroup at %s", cgroupPath)
-	}
-
-	cgroupStats, err := cgroup.Stat()
-	if err != nil {
-		return stats, errors.Wrapf(err, "unable to obtain cgroup stats")
+	var cgStats *cgroupStats
+	if cg.IsCgroup2UnifiedMode() {
+		cgStats, err = readCgroup2Stats(cg.V2Dir(cgroupPath))
+	} else {
+		cgStats, err = readCgroup1Stats(cgroupPath)
 	}
-	conState := c.state.State
 	if err != nil {
-		return stats, errors.Wrapf(err, "unable to determine container state")
+		return stats, err
 	}
 
 	netStats, err := getContainerNetIO(c)
@@ -53,19 +54,27 @@ func (c *Container) GetContainerStats(previousStats *ContainerStats) (*Container
 		return nil, err
 	}
 
-	previousCPU := previousStats.CPUNano
-	previousSystem := previousStats.SystemNano
-	stats.CPU = calculateCPUPercent(cgroupStats, previousCPU, previousSystem)
-	stats.MemUsage = cgroupStats.Memory.Usage.Usage
-	stats.MemLimit = getMemLimit(cgroupStats.Memory.Usage.Limit)
+	// The CPU usage is averaged since the previous sample, or since the
+	// container started for the first one
+	now := time.Now()
+	previousCPU, previousTime := previousStats.CPUNano, previousStats.SystemNano
+	if previousTime == 0 || previousCPU > cgStats.cpuNano {
+		previousCPU, previousTime = 0, uint64(c.state.StartedTime.UnixNano())
+	}
+	stats.CPU = calculateCPUPercent(cgStats.cpuNano-previousCPU, uint64(now.UnixNano())-previousTime)
+	stats.CPUNano = cgStats.cpuNano
+	stats.SystemNano = uint64(now.UnixNano())
+	stats.MemUsage = cgStats.memUsage
+	stats.MemLimit = getMemLimit(cgStats.memLimit)
 	stats.MemPerc = (float64(stats.MemUsage) / float64(stats.MemLimit)) * 100
-	stats.PIDs = 0
-	if conState == ContainerStateRunning {
-		stats.PIDs = cgroupStats.Pids.Current
+	stats.PIDs = cgStats.pids.Current
+	stats.PIDsLimit = cgStats.pids.Limit
+	stats.PIDsLimitHits = cgStats.pids.LimitHits
+	if err := c.checkPidsLimit(&cgStats.pids); err != nil {
+		logrus.Errorf("Error checking the PIDs limit of container %s: %v", c.ID(), err)
 	}
-	stats.BlockInput, stats.BlockOutput = calculateBlockIO(cgroupStats)
-	stats.CPUNano = cgroupStats.CPU.Usage.Total
-	stats.SystemNano = cgroupStats.CPU.Usage.Kernel
+	stats.BlockInput, stats.BlockOutput = cgStats.blockInput, cgStats.blockOutput
+	stats.Hugetlb = cgStats.hugetlb
 	// Handle case where the container is not in a network namespace
 	if netStats != nil {
 		stats.NetInput = netStats.TxBytes
@@ -78,6 +87,46 @@ func (c *Container) GetContainerStats(previousStats *ContainerStats) (*Container
 	return stats, nil
 }
 
+// cgroupStats is the resource usage of the cgroup of a container, read from
+// the cgroup v1 hierarchies or from the unified cgroup v2 one
+type cgroupStats struct {
+	cpuNano     uint64
+	memUsage    uint64
+	memLimit    uint64
+	pids        PidsStats
+	blockInput  uint64
+	blockOutput uint64
+	hugetlb     []HugetlbStats
+}
+
+// readCgroup1Stats reads the resource usage of the cgroup at cgroupPath in
+// the cgroup v1 hierarchies
+func readCgroup1Stats(cgroupPath string) (*cgroupStats, error) {
+	cgroup, err := cgroups.Load(cgroups.V1, cgroups.StaticPath(cgroupPath))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to load cgroup at %s", cgroupPath)
+	}
+	metrics, err := cgroup.Stat()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to obtain cgroup stats")
+	}
+	stats := &cgroupStats{
+		cpuNano:  metrics.CPU.Usage.Total,
+		memUsage: metrics.Memory.Usage.Usage,
+		memLimit: metrics.Memory.Usage.Limit,
+		pids: PidsStats{
+			Current: metrics.Pids.Current,
+			Limit:   metrics.Pids.Limit,
+		},
+		hugetlb: calculateHugetlb(metrics),
+	}
+	if pids, err := cgroupPids(cgroupPath); err == nil {
+		stats.pids.LimitHits = pids.LimitHits
+	}
+	stats.blockInput, stats.blockOutput = calculateBlockIO(metrics)
+	return stats, nil
+}
+
 // getMemory limit returns the memory limit for a given cgroup
 // If the configured memory limit is larger than the total memory on the sys, the
 // physical system memory size is returned
@@ -95,18 +144,13 @@ func getMemLimit(cgroupLimit uint64) uint64 {
 	return cgroupLimit
 }
 
-func calculateCPUPercent(stats *cgroups.Metrics, previousCPU, previousSystem uint64) float64 {
-	var (
-		cpuPercent  = 0.0
-		cpuDelta    = float64(stats.CPU.Usage.Total - previousCPU)
-		systemDelta = float64(uint64(time.Now().UnixNano()) - previousSystem)
-	)
-	if systemDelta > 0.0 && cpuDelta > 0.0 {
-		// gets a ratio of container cpu usage total, multiplies it by the number of cores (4 cores running
-		// at 100% utilization should be 400% utilization), and multiplies that by 100 to get a percentage
-		cpuPercent = (cpuDelta / systemDelta) * float64(len(stats.CPU.Usage.PerCPU)) * 100
-	}
-	return cpuPercent
+// calculateCPUPercent returns the CPU usage over a period of time, as a
+// percentage of one CPU: a container using 4 CPUs fully uses 400%
+func calculateCPUPercent(cpuDelta, timeDelta uint64) float64 {
+	if timeDelta == 0 {
+		return 0
+	}
+	return float64(cpuDelta) / float64(timeDelta) * 100
 }
 
 func calculateBlockIO(stats *cgroups.Metrics) (read uint64, write uint64) {
@@ -120,3 +164,149 @@ func calculateBlockIO(stats *cgroups.Metrics) (read uint64, write uint64) {
 	}
 	return
 }
+
+func calculateHugetlb(stats *cgroups.Metrics) []HugetlbStats {
+	var hugetlb []HugetlbStats
+	for _, entry := range stats.Hugetlb {
+		hugetlb = append(hugetlb, HugetlbStats{
+			PageSize: entry.Pagesize,
+			Usage:    entry.Usage,
+			Limit:    entry.Max,
+			Failcnt:  entry.Failcnt,
+		})
+	}
+	return hugetlb
+}
+
+// cgroupPids reads the number of processes of the pids cgroup at cgroupPath,
+// on the cgroup v1 pids hierarchy or on the unified cgroup v2 one
+func cgroupPids(cgroupPath string) (*PidsStats, error) {
+	return readCgroupPids(cgroupPidsDir(cgroupPath))
+}
+
+// cgroupPidsDir returns the directory of the pids controller of the cgroup at
+// cgroupPath, in the cgroup v1 hierarchy or the unified cgroup v2 one
+func cgroupPidsDir(cgroupPath string) string {
+	return cg.Dir("pids", cgroupPath)
+}
+
+// readCgroupPids reads the pids.* files of the cgroup directory dir
+func readCgroupPids(dir string) (*PidsStats, error) {
+	pids := new(PidsStats)
+	current, err := ioutil.ReadFile(filepath.Join(dir, "pids.current"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the processes of cgroup %s", dir)
+	}
+	if pids.Current, err = strconv.ParseUint(strings.TrimSpace(string(current)), 10, 64); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the processes of cgroup %s", dir)
+	}
+	if max, err := ioutil.ReadFile(filepath.Join(dir, "pids.max")); err == nil {
+		// The limit is "max" when the processes are not limited
+		pids.Limit, _ = strconv.ParseUint(strings.TrimSpace(string(max)), 10, 64)
+	}
+	// Kernels before 4.3 have no pids.events
+	if events, err := ioutil.ReadFile(filepath.Join(dir, "pids.events")); err == nil {
+		for _, line := range strings.Split(string(events), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 2 && fields[0] == "max" {
+				pids.LimitHits, _ = strconv.ParseUint(fields[1], 10, 64)
+			}
+		}
+	}
+	return pids, nil
+}
+
+// readCgroup2Stats reads the resource usage of the cgroup directory dir of the
+// unified cgroup v2 hierarchy. The files of the controllers not enabled for
+// the cgroup are missing, and their usage left at 0.
+func readCgroup2Stats(dir string) (*cgroupStats, error) {
+	cpuStat, err := ioutil.ReadFile(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the CPU usage of cgroup %s", dir)
+	}
+	stats := new(cgroupStats)
+	for _, line := range strings.Split(string(cpuStat), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "usage_usec" {
+			usec, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to parse the CPU usage of cgroup %s", dir)
+			}
+			stats.cpuNano = usec * 1000
+		}
+	}
+	if stats.memUsage, err = readCgroup2Value(dir, "memory.current"); err != nil {
+		return nil, err
+	}
+	if stats.memLimit, err = readCgroup2Value(dir, "memory.max"); err != nil {
+		return nil, err
+	}
+	if stats.memLimit == 0 {
+		// Not limited, getMemLimit returns the memory of the host
+		stats.memLimit = ^uint64(0)
+	}
+	if pids, err := readCgroupPids(dir); err == nil {
+		stats.pids = *pids
+	}
+	if ioStat, err := ioutil.ReadFile(filepath.Join(dir, "io.stat")); err == nil {
+		// Lines are the device followed by key=value pairs
+		for _, line := range strings.Split(string(ioStat), "\n") {
+			for _, field := range strings.Fields(line) {
+				split := strings.SplitN(field, "=", 2)
+				if len(split) != 2 {
+					continue
+				}
+				value, _ := strconv.ParseUint(split[1], 10, 64)
+				switch split[0] {
+				case "rbytes":
+					stats.blockInput += value
+				case "wbytes":
+					stats.blockOutput += value
+				}
+			}
+		}
+	}
+	currents, err := filepath.Glob(filepath.Join(dir, "hugetlb.*.current"))
+	if err != nil {
+		return nil, err
+	}
+	for _, current := range currents {
+		pageSize := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(current), "hugetlb."), ".current")
+		hugetlb := HugetlbStats{PageSize: pageSize}
+		if hugetlb.Usage, err = readCgroup2Value(dir, filepath.Base(current)); err != nil {
+			return nil, err
+		}
+		hugetlb.Limit, _ = readCgroup2Value(dir, "hugetlb."+pageSize+".max")
+		if events, err := ioutil.ReadFile(filepath.Join(dir, "hugetlb."+pageSize+".events")); err == nil {
+			for _, line := range strings.Split(string(events), "\n") {
+				fields := strings.Fields(line)
+				if len(fields) == 2 && fields[0] == "max" {
+					hugetlb.Failcnt, _ = strconv.ParseUint(fields[1], 10, 64)
+				}
+			}
+		}
+		stats.hugetlb = append(stats.hugetlb, hugetlb)
+	}
+	return stats, nil
+}
+
+// readCgroup2Value reads the value of a file of the cgroup directory dir, 0 if
+// the file is missing or the value is "max"
+func readCgroup2Value(dir, file string) (uint64, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "unable to read %s of cgroup %s", file, dir)
+	}
+	value := strings.TrimSpace(string(content))
+	if value == "max" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to parse %s of cgroup %s", file, dir)
+	}
+	return v, nil
+}
@@ -4,7 +4,11 @@ package libpod
 type ContainerStats struct {
 	ContainerID string
 	Name        string
-	CPU         float64
+	// CPU is the CPU usage of the container since the previous stats, or
+	// since it started, as a percentage of one CPU
+	CPU float64
+	// CPUNano is the total CPU time used by the container, and SystemNano
+	// when the stats were read, in nanoseconds since the epoch
 	CPUNano     uint64
 	SystemNano  uint64
 	MemUsage    uint64
@@ -15,4 +19,26 @@ type ContainerStats struct {
 	BlockInput  uint64
 	BlockOutput uint64
 	PIDs        uint64
+	// PIDsLimit is the maximum number of processes of the container, 0 if
+	// it is not limited
+	PIDsLimit uint64
+	// PIDsLimitHits is how many times the container failed to create a
+	// process because it reached PIDsLimit
+	PIDsLimitHits uint64
+	Hugetlb       []HugetlbStats
+}
+
+// PidsStats contains the number of processes of a container and their limit
+type PidsStats struct {
+	Current   uint64
+	Limit     uint64
+	LimitHits uint64
+}
+
+// HugetlbStats contains the hugepage usage of a container for a page size
+type HugetlbStats struct {
+	PageSize string
+	Usage    uint64
+	Limit    uint64
+	Failcnt  uint64
 }
@@ -0,0 +1,99 @@
+package libpod
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/libpod/pkg/statshistory"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// statsHistoryFile is the file of the stats history of a container, in its
+// static directory so that it is kept until the container is removed
+const statsHistoryFile = "stats-history"
+
+// statsHistory returns the stats history of the container
+func (c *Container) statsHistory() *statshistory.Ring {
+	return statshistory.NewRing(filepath.Join(c.config.StaticDir, statsHistoryFile), c.runtime.config().StatsHistorySize)
+}
+
+// StatsHistory returns the samples of the resource usage of the container
+// recorded by SampleStats, oldest first, including those of its previous
+// runs
+func (c *Container) StatsHistory() ([]statshistory.Sample, error) {
+	if !c.valid {
+		return nil, ErrCtrRemoved
+	}
+	return c.statsHistory().Read()
+}
+
+// statsSample is the last sample of a container taken by SampleStats
+type statsSample struct {
+	stats *ContainerStats
+}
+
+// SampleStats records the resource usage of the running containers in their
+// stats history at the stats_history_interval of the configuration, until
+// the context is done. It returns at once if no interval is configured.
+func (r *Runtime) SampleStats(ctx context.Context) error {
+	if r.config().StatsHistoryInterval == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(r.config().StatsHistoryInterval)
+	if err != nil || interval <= 0 {
+		return errors.Wrapf(ErrInvalidArg, "invalid stats_history_interval %q", r.config().StatsHistoryInterval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	previous := make(map[string]statsSample)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			previous = r.sampleStats(previous)
+		}
+	}
+}
+
+// sampleStats records the resource usage of the running containers, given
+// their previous samples, and returns their samples
+func (r *Runtime) sampleStats(previous map[string]statsSample) map[string]statsSample {
+	ctrs, err := r.GetRunningContainers()
+	if err != nil {
+		logrus.Errorf("Unable to sample the stats of containers: %v", err)
+		return previous
+	}
+	samples := make(map[string]statsSample, len(ctrs))
+	for _, ctr := range ctrs {
+		prev, ok := previous[ctr.ID()]
+		if !ok {
+			prev.stats = &ContainerStats{}
+		}
+		stats, err := ctr.GetContainerStats(prev.stats)
+		if err != nil {
+			// The container may have stopped since it was listed
+			logrus.Debugf("Unable to sample the stats of container %s: %v", ctr.ID(), err)
+			continue
+		}
+		samples[ctr.ID()] = statsSample{stats: stats}
+		sample := statshistory.Sample{
+			Time:     time.Now(),
+			CPUNano:  stats.CPUNano,
+			MemUsage: stats.MemUsage,
+			MemLimit: stats.MemLimit,
+			PIDs:     stats.PIDs,
+		}
+		// GetContainerStats averages the CPU usage since the previous
+		// sample, the first one has none to average since
+		if ok {
+			sample.CPU = stats.CPU
+		}
+		if err := ctr.statsHistory().Write(sample); err != nil {
+			logrus.Errorf("Unable to record the stats of container %s: %v", ctr.ID(), err)
+		}
+	}
+	return samples
+}
@@ -0,0 +1,45 @@
+package libpod
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// StreamStats sends the stats of the container to statsChan, at once and then
+// at every interval, until the context is done or the container stops. The
+// CPU usage of each sample is averaged since the previous one, and since the
+// container started for the first one. The channel is not closed.
+func (c *Container) StreamStats(ctx context.Context, interval time.Duration, statsChan chan<- *ContainerStats) error {
+	if interval <= 0 {
+		return errors.Wrapf(ErrInvalidArg, "invalid stats interval %s", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	previous := &ContainerStats{}
+	for {
+		stats, err := c.GetContainerStats(previous)
+		if err != nil {
+			switch errors.Cause(err) {
+			case ErrCtrStateInvalid, ErrCtrRemoved, ErrNoSuchCtr:
+				// The container stopped since the previous sample
+				if previous.SystemNano != 0 {
+					return nil
+				}
+			}
+			return err
+		}
+		select {
+		case statsChan <- stats:
+		case <-ctx.Done():
+			return nil
+		}
+		previous = stats
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,129 @@
+//go:build linux
+// +build linux
+
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/containers/libpod/libpod/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCgroupPids(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pids")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = readCgroupPids(dir)
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "pids.current"), []byte("3\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "pids.max"), []byte("max\n"), 0644))
+	pids, err := readCgroupPids(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, &PidsStats{Current: 3}, pids)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "pids.max"), []byte("100\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "pids.events"), []byte("max 7\n"), 0644))
+	pids, err = readCgroupPids(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, &PidsStats{Current: 3, Limit: 100, LimitHits: 7}, pids)
+}
+
+func TestReadCgroup2Stats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup2")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = readCgroup2Stats(dir)
+	assert.Error(t, err)
+
+	for file, content := range map[string]string{
+		"cpu.stat":            "usage_usec 1500\nuser_usec 1000\nsystem_usec 500\n",
+		"memory.current":      "4096\n",
+		"memory.max":          "max\n",
+		"pids.current":        "2\n",
+		"pids.max":            "10\n",
+		"io.stat":             "8:0 rbytes=100 wbytes=200 rios=1 wios=2\n8:16 rbytes=1 wbytes=2\n",
+		"hugetlb.2MB.current": "2097152\n",
+		"hugetlb.2MB.max":     "4194304\n",
+		"hugetlb.2MB.events":  "max 3\n",
+		"hugetlb.1GB.current": "0\n",
+		"hugetlb.1GB.max":     "max\n",
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+	}
+	stats, err := readCgroup2Stats(dir)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1500000), stats.cpuNano)
+	assert.Equal(t, uint64(4096), stats.memUsage)
+	assert.Equal(t, ^uint64(0), stats.memLimit)
+	assert.Equal(t, PidsStats{Current: 2, Limit: 10}, stats.pids)
+	assert.Equal(t, uint64(101), stats.blockInput)
+	assert.Equal(t, uint64(202), stats.blockOutput)
+	assert.Equal(t, []HugetlbStats{
+		{PageSize: "1GB"},
+		{PageSize: "2MB", Usage: 2097152, Limit: 4194304, Failcnt: 3},
+	}, stats.hugetlb)
+
+	// The files of the controllers not enabled are missing
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "memory.max"), []byte("8192\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "io.stat")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "pids.current")))
+	stats, err = readCgroup2Stats(dir)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8192), stats.memLimit)
+	assert.Equal(t, uint64(0), stats.blockInput)
+	assert.Equal(t, PidsStats{}, stats.pids)
+}
+
+func TestCalculateCPUPercent(t *testing.T) {
+	assert.Equal(t, 0.0, calculateCPUPercent(100, 0))
+	assert.Equal(t, 50.0, calculateCPUPercent(500, 1000))
+	// Several CPUs used fully
+	assert.Equal(t, 400.0, calculateCPUPercent(4000, 1000))
+}
+
+func TestCheckPidsLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pids")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	eventer, err := events.NewEventer(events.EventerOptions{LogFilePath: filepath.Join(dir, "events.log")})
+	require.NoError(t, err)
+	state, err := NewInMemoryState()
+	require.NoError(t, err)
+	ctr, err := getTestCtr1(dir)
+	require.NoError(t, err)
+	ctr.runtime = &Runtime{state: state, eventer: eventer}
+	ctr.runtime.setConfig(&RuntimeConfig{})
+	require.NoError(t, state.AddContainer(ctr))
+
+	readEvents := func() int {
+		content, err := ioutil.ReadFile(filepath.Join(dir, "events.log"))
+		if os.IsNotExist(err) {
+			return 0
+		}
+		require.NoError(t, err)
+		return strings.Count(string(content), string(events.PidsLimit))
+	}
+
+	require.NoError(t, ctr.checkPidsLimit(&PidsStats{Limit: 10}))
+	assert.Equal(t, 0, readEvents())
+
+	// Hits are reported once, the count last reported is saved
+	require.NoError(t, ctr.checkPidsLimit(&PidsStats{Limit: 10, LimitHits: 2}))
+	require.NoError(t, ctr.checkPidsLimit(&PidsStats{Limit: 10, LimitHits: 2}))
+	assert.Equal(t, 1, readEvents())
+	saved, err := state.Container(ctr.ID())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), saved.state.PidsLimitHits)
+
+	require.NoError(t, ctr.checkPidsLimit(&PidsStats{Limit: 10, LimitHits: 3}))
+	assert.Equal(t, 2, readEvents())
+}
@@ -2,7 +2,19 @@
 
 package libpod
 
+import "context"
+
 // GetContainerStats gets the running stats for a given container
 func (c *Container) GetContainerStats(previousStats *ContainerStats) (*ContainerStats, error) {
 	return nil, ErrOSNotSupported
 }
+
+func cgroupPids(cgroupPath string) (*PidsStats, error) {
+	return nil, ErrOSNotSupported
+}
+
+// WatchPidsLimits reports the containers failing to create processes
+// because of their PIDs limit
+func (r *Runtime) WatchPidsLimits(ctx context.Context) error {
+	return ErrOSNotSupported
+}
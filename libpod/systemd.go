@@ -0,0 +1,88 @@
+package libpod
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/containers/libpod/pkg/inspect"
+	"github.com/containers/libpod/pkg/rootless"
+)
+
+// SystemdUnitLabel is the label of containers naming the systemd service unit
+// managing them, rather than the service they are started from
+const SystemdUnitLabel = "PODMAN_SYSTEMD_UNIT"
+
+// systemdServiceFromCgroup returns the systemd service unit of a process from
+// the content of its /proc/PID/cgroup, if its cgroup is that of a service.
+// Processes in the slices or scopes of a service, such as the sessions of the
+// user@.service of a user, are not in a service unit of their own.
+func systemdServiceFromCgroup(content []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		// HIERARCHY:CONTROLLERS:PATH, the systemd hierarchy is the
+		// name=systemd one on cgroup v1, the unified one on v2
+		split := strings.SplitN(scanner.Text(), ":", 3)
+		if len(split) != 3 || (split[1] != "name=systemd" && split[0] != "0") {
+			continue
+		}
+		unit := path.Base(split[2])
+		if strings.HasSuffix(unit, ".service") {
+			return unit
+		}
+		return ""
+	}
+	return ""
+}
+
+// SystemdUnits returns the systemd units of the container. Its scopes are
+// only known while it has processes.
+func (c *Container) SystemdUnits() (*inspect.SystemdUnits, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return nil, err
+		}
+	}
+	return c.systemdUnits(), nil
+}
+
+// Internal, non-locking function returning the systemd units of the container
+func (c *Container) systemdUnits() *inspect.SystemdUnits {
+	units := &inspect.SystemdUnits{Service: c.state.SystemdService}
+	if service, ok := c.config.Labels[SystemdUnitLabel]; ok {
+		units.Service = service
+	}
+	if c.runtime.config().CgroupManager == SystemdCgroupsManager {
+		switch c.state.State {
+		case ContainerStateCreated, ContainerStateRunning, ContainerStatePaused:
+			units.Scope = createUnitName("libpod", c.ID())
+			// Only root moves conmon to a scope
+			if !rootless.IsRootless() {
+				units.ConmonScope = createUnitName("libpod-conmon", c.ID())
+			}
+		}
+	}
+	return units
+}
+
+// SystemdUnitFilter returns a filter of the containers with the systemd
+// unit: their service or one of their scopes
+func SystemdUnitFilter(unit string) ContainerFilter {
+	return func(c *Container) bool {
+		units, err := c.SystemdUnits()
+		if err != nil {
+			return false
+		}
+		return unit != "" && (units.Service == unit || units.Scope == unit || units.ConmonScope == unit)
+	}
+}
+
+// GetContainersBySystemdUnit returns the containers with the systemd unit:
+// the containers of a service, or the container of a scope
+func (r *Runtime) GetContainersBySystemdUnit(unit string) ([]*Container, error) {
+	return r.GetContainers(SystemdUnitFilter(unit))
+}
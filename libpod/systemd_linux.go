@@ -0,0 +1,20 @@
+// +build linux
+
+package libpod
+
+import (
+	"io/ioutil"
+
+	"github.com/sirupsen/logrus"
+)
+
+// currentSystemdService returns the systemd service unit podman runs in, if
+// any
+func currentSystemdService() string {
+	content, err := ioutil.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		logrus.Debugf("Unable to read the cgroup of podman: %v", err)
+		return ""
+	}
+	return systemdServiceFromCgroup(content)
+}
@@ -0,0 +1,27 @@
+package libpod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemdServiceFromCgroup(t *testing.T) {
+	for _, tc := range []struct {
+		cgroup  string
+		service string
+	}{
+		// cgroup v1
+		{"12:pids:/system.slice/container-web.service\n1:name=systemd:/system.slice/container-web.service\n", "container-web.service"},
+		{"1:name=systemd:/user.slice/user-1000.slice/session-2.scope\n", ""},
+		// cgroup v2
+		{"0::/system.slice/container-web.service\n", "container-web.service"},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/container-web.service\n", "container-web.service"},
+		// Sessions of the service of a user are not services
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/gnome-terminal.scope\n", ""},
+		{"0::/machine.slice/libpod-conmon-a7d5d3f2e9c1.scope\n", ""},
+		{"", ""},
+	} {
+		assert.Equal(t, tc.service, systemdServiceFromCgroup([]byte(tc.cgroup)), tc.cgroup)
+	}
+}
@@ -0,0 +1,9 @@
+// +build !linux
+
+package libpod
+
+// currentSystemdService returns the systemd service unit podman runs in, if
+// any
+func currentSystemdService() string {
+	return ""
+}
@@ -0,0 +1,59 @@
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// LocalTimezone is the timezone giving containers the timezone of the
+	// host
+	LocalTimezone = "local"
+
+	// zoneinfoDir is the zoneinfo database of the host
+	zoneinfoDir = "/usr/share/zoneinfo"
+	// hostLocaltime is the file holding the timezone of the host
+	hostLocaltime = "/etc/localtime"
+)
+
+// timezoneFile returns the zoneinfo file of a timezone on the host
+func timezoneFile(tz string) (string, error) {
+	if tz == LocalTimezone {
+		path, err := filepath.EvalSymlinks(hostLocaltime)
+		if err != nil {
+			return "", errors.Wrapf(err, "error finding the timezone of the host")
+		}
+		return path, nil
+	}
+	if tz == "" || filepath.IsAbs(tz) || strings.Contains(tz, "..") {
+		return "", errors.Wrapf(ErrInvalidArg, "invalid timezone %q", tz)
+	}
+	path := filepath.Join(zoneinfoDir, tz)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", errors.Wrapf(ErrInvalidArg, "timezone %q is not in the zoneinfo database of the host", tz)
+	}
+	return path, nil
+}
+
+// generateLocaltime returns the file mounted as the /etc/localtime of the
+// container. The zoneinfo file of the host is mounted as is, unless the
+// container has a user namespace: it then gets a copy owned by its root.
+func (c *Container) generateLocaltime() (string, error) {
+	zone, err := timezoneFile(c.config.Timezone)
+	if err != nil {
+		return "", err
+	}
+	if len(c.config.IDMappings.UIDMap) == 0 && len(c.config.IDMappings.GIDMap) == 0 {
+		return zone, nil
+	}
+	content, err := ioutil.ReadFile(zone)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading zoneinfo file %s", zone)
+	}
+	return c.writeStringToRundir("localtime", string(content))
+}
@@ -0,0 +1,26 @@
+package libpod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimezoneFile(t *testing.T) {
+	for _, tz := range []string{"", "/etc/passwd", "../../../etc/passwd", "Not/A_Zone"} {
+		_, err := timezoneFile(tz)
+		assert.Error(t, err, tz)
+	}
+
+	if _, err := os.Stat(filepath.Join(zoneinfoDir, "UTC")); err != nil {
+		t.Skip("the host has no zoneinfo database")
+	}
+	path, err := timezoneFile("UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(zoneinfoDir, "UTC"), path)
+	// Directories of the database are not zones
+	_, err = timezoneFile("Etc")
+	assert.Error(t, err)
+}
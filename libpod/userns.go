@@ -0,0 +1,29 @@
+package libpod
+
+import (
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/pkg/errors"
+)
+
+// allocateIDs returns the first host ID of a range of size IDs within the
+// available ranges, which overlaps none of the used ranges
+func allocateIDs(available, used []idtools.IDMap, size int) (int, error) {
+	for _, avail := range available {
+		start := avail.HostID
+		for start+size <= avail.HostID+avail.Size {
+			overlap := false
+			for _, u := range used {
+				if u.HostID < start+size && start < u.HostID+u.Size {
+					// Retry after the used range
+					start = u.HostID + u.Size
+					overlap = true
+					break
+				}
+			}
+			if !overlap {
+				return start, nil
+			}
+		}
+	}
+	return 0, errors.Errorf("no range of %d unused IDs is left", size)
+}
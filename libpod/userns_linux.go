@@ -0,0 +1,143 @@
+// +build linux
+
+package libpod
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/pkg/errors"
+)
+
+// userNSReservationsFile is the file, in the static directory, of the ranges
+// of IDs reserved by processes creating containers and builds with automatic
+// user namespaces
+const userNSReservationsFile = "userns-reservations.json"
+
+// userNSReservation is a range of IDs allocated to a process for the user
+// namespace of the containers it creates. The range is held by the
+// reservation until the process exits, and by the storage containers using
+// it afterwards.
+type userNSReservation struct {
+	PID    int             `json:"pid"`
+	UIDMap []idtools.IDMap `json:"uidmap"`
+	GIDMap []idtools.IDMap `json:"gidmap"`
+}
+
+// AllocateUserNS allocates ID mappings for the user namespace of a container
+// or build of root, from the subordinate IDs of the auto_userns_user user.
+// The range of IDs is not used by other containers of the storage, nor
+// allocated to other processes, so that the processes of the container run as
+// users of their own.
+func (r *Runtime) AllocateUserNS() (*storage.IDMappingOptions, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+	if rootless.IsRootless() {
+		return nil, errors.Wrapf(ErrInvalidArg, "automatic user namespaces require root")
+	}
+	user := r.config().AutoUserNSUser
+	size := r.config().AutoUserNSSize
+	if size <= 0 {
+		return nil, errors.Wrapf(ErrInvalidArg, "invalid number of IDs %d of automatic user namespaces", size)
+	}
+	subIDs, err := idtools.NewIDMappings(user, user)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading the subordinate IDs of user %s", user)
+	}
+
+	path := filepath.Join(r.config().StaticDir, userNSReservationsFile)
+	lock, err := storage.GetLockfile(path + ".lock")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error locking %s", path)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+
+	reservations, err := readUserNSReservations(path)
+	if err != nil {
+		return nil, err
+	}
+	ctrs, err := r.store.Containers()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing storage containers")
+	}
+	var usedUIDs, usedGIDs []idtools.IDMap
+	for _, ctr := range ctrs {
+		usedUIDs = append(usedUIDs, ctr.UIDMap...)
+		usedGIDs = append(usedGIDs, ctr.GIDMap...)
+	}
+	for _, reservation := range reservations {
+		usedUIDs = append(usedUIDs, reservation.UIDMap...)
+		usedGIDs = append(usedGIDs, reservation.GIDMap...)
+	}
+
+	uid, err := allocateIDs(subIDs.UIDs(), usedUIDs, size)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error allocating UIDs from the subordinate UIDs of user %s", user)
+	}
+	gid, err := allocateIDs(subIDs.GIDs(), usedGIDs, size)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error allocating GIDs from the subordinate GIDs of user %s", user)
+	}
+	reservation := userNSReservation{
+		PID:    os.Getpid(),
+		UIDMap: []idtools.IDMap{{ContainerID: 0, HostID: uid, Size: size}},
+		GIDMap: []idtools.IDMap{{ContainerID: 0, HostID: gid, Size: size}},
+	}
+	if err := writeUserNSReservations(path, append(reservations, reservation)); err != nil {
+		return nil, err
+	}
+	return &storage.IDMappingOptions{
+		UIDMap: reservation.UIDMap,
+		GIDMap: reservation.GIDMap,
+	}, nil
+}
+
+// readUserNSReservations returns the reservations of the file at path held by
+// running processes
+func readUserNSReservations(path string) ([]userNSReservation, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "error reading %s", path)
+	}
+	var all []userNSReservation
+	if err := json.Unmarshal(content, &all); err != nil {
+		return nil, errors.Wrapf(err, "error decoding %s", path)
+	}
+	var reservations []userNSReservation
+	for _, reservation := range all {
+		if err := syscall.Kill(reservation.PID, 0); err == nil || err == syscall.EPERM {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations, nil
+}
+
+// writeUserNSReservations replaces the reservations of the file at path
+func writeUserNSReservations(path string, reservations []userNSReservation) error {
+	content, err := json.Marshal(reservations)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0600); err != nil {
+		return errors.Wrapf(err, "error writing %s", tmpPath)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrapf(err, "error writing %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// +build linux
+
+package libpod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserNSReservations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "userns")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, userNSReservationsFile)
+
+	reservations, err := readUserNSReservations(path)
+	require.NoError(t, err)
+	assert.Empty(t, reservations)
+
+	running := userNSReservation{
+		PID:    os.Getpid(),
+		UIDMap: []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+		GIDMap: []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+	}
+	// PIDs are at most 2^22, so this process does not exist
+	exited := userNSReservation{
+		PID:    1 << 30,
+		UIDMap: []idtools.IDMap{{ContainerID: 0, HostID: 165536, Size: 65536}},
+		GIDMap: []idtools.IDMap{{ContainerID: 0, HostID: 165536, Size: 65536}},
+	}
+	require.NoError(t, writeUserNSReservations(path, []userNSReservation{running, exited}))
+
+	// The reservations of processes which exited are dropped
+	reservations, err = readUserNSReservations(path)
+	require.NoError(t, err)
+	assert.Equal(t, []userNSReservation{running}, reservations)
+}
@@ -0,0 +1,45 @@
+package libpod
+
+import (
+	"testing"
+
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocateIDs(t *testing.T) {
+	available := []idtools.IDMap{
+		{ContainerID: 0, HostID: 100000, Size: 65536 * 3},
+		{ContainerID: 65536 * 3, HostID: 500000, Size: 65536},
+	}
+
+	start, err := allocateIDs(available, nil, 65536)
+	require.NoError(t, err)
+	assert.Equal(t, 100000, start)
+
+	// Ranges of the host, and those of other containers, are skipped
+	used := []idtools.IDMap{
+		{ContainerID: 0, HostID: 0, Size: 1000},
+		{ContainerID: 0, HostID: 100000, Size: 65536},
+		{ContainerID: 0, HostID: 100000 + 65536 + 10, Size: 5},
+	}
+	start, err = allocateIDs(available, used, 65536)
+	require.NoError(t, err)
+	assert.Equal(t, 100000+65536+15, start)
+
+	// The next available range is used once one is full
+	used = append(used, idtools.IDMap{ContainerID: 0, HostID: 100000 + 65536 + 15, Size: 65536})
+	start, err = allocateIDs(available, used, 65536)
+	require.NoError(t, err)
+	assert.Equal(t, 500000, start)
+
+	used = append(used, idtools.IDMap{ContainerID: 0, HostID: 500000, Size: 1})
+	_, err = allocateIDs(available, used, 65536)
+	assert.Error(t, err)
+
+	// Smaller ranges still fit between used ones
+	start, err = allocateIDs(available, used, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 100000+65536, start)
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package libpod
+
+import (
+	"github.com/containers/storage"
+)
+
+// AllocateUserNS is not implemented on this platform
+func (r *Runtime) AllocateUserNS() (*storage.IDMappingOptions, error) {
+	return nil, ErrNotImplemented
+}
@@ -2,6 +2,7 @@ package libpod
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
@@ -22,6 +23,22 @@ const (
 	DefaultTransport = "docker://"
 )
 
+// bootIDPath is the file the kernel exposes the ID of the current boot in
+const bootIDPath = "/proc/sys/kernel/random/boot_id"
+
+// getBootID returns the ID of the current boot, or "" if the kernel does not
+// provide one
+func getBootID() (string, error) {
+	bootID, err := ioutil.ReadFile(bootIDPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "error reading boot ID from %s", bootIDPath)
+	}
+	return strings.TrimSpace(string(bootID)), nil
+}
+
 // WriteFile writes a provided string to a provided path
 func WriteFile(content string, path string) error {
 	baseDir := filepath.Dir(path)
@@ -123,6 +140,32 @@ func WaitForFile(path string, timeout time.Duration) error {
 	}
 }
 
+// retryWithBackoff calls check until it succeeds or the timeout has
+// occurred, waiting between the calls for an interval starting at
+// minInterval and doubling up to maxInterval. The last error of check is
+// returned on timeout.
+func retryWithBackoff(timeout, minInterval, maxInterval time.Duration, check func() error) error {
+	deadline := time.Now().Add(timeout)
+	interval := minInterval
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return err
+		}
+		if interval > remaining {
+			interval = remaining
+		}
+		time.Sleep(interval)
+		if interval *= 2; interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
 type byDestination []spec.Mount
 
 func (m byDestination) Len() int {
@@ -4,18 +4,21 @@ package libpod
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/containerd/cgroups"
+	systemdDbus "github.com/coreos/go-systemd/dbus"
+	"github.com/godbus/dbus"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 // systemdSliceFromPath makes a new systemd slice under the given parent with
-// the given name.
+// the given name, limited to the given resources if they are not nil.
 // The parent must be a slice. The name must NOT include ".slice"
-func systemdSliceFromPath(parent, name string) (string, error) {
+func systemdSliceFromPath(parent, name string, resources *spec.LinuxResources) (string, error) {
 	cgroupPath, err := assembleSystemdCgroupName(parent, name)
 	if err != nil {
 		return "", err
@@ -23,7 +26,7 @@ func systemdSliceFromPath(parent, name string) (string, error) {
 
 	logrus.Debugf("Created cgroup path %s for parent %s and name %s", cgroupPath, parent, name)
 
-	if err := makeSystemdCgroup(cgroupPath); err != nil {
+	if err := makeSystemdCgroup(cgroupPath, resources); err != nil {
 		return "", errors.Wrapf(err, "error creating cgroup %s", cgroupPath)
 	}
 
@@ -32,14 +35,59 @@ func systemdSliceFromPath(parent, name string) (string, error) {
 	return cgroupPath, nil
 }
 
-// makeSystemdCgroup creates a systemd CGroup at the given location.
-func makeSystemdCgroup(path string) error {
+// makeSystemdCgroup creates a systemd CGroup at the given location, limited
+// to the given resources if they are not nil.
+func makeSystemdCgroup(path string, resources *spec.LinuxResources) error {
 	controller, err := cgroups.NewSystemd(SystemdDefaultCgroupParent)
 	if err != nil {
 		return err
 	}
 
-	return controller.Create(path, &spec.LinuxResources{})
+	// The controller ignores the resources, they are set as properties
+	// of the slice so systemd keeps them
+	if err := controller.Create(path, &spec.LinuxResources{}); err != nil {
+		return err
+	}
+	if resources == nil {
+		return nil
+	}
+	return setSystemdCgroupResources(path, resources)
+}
+
+// setSystemdCgroupResources sets the memory limit and CPU quota of the systemd
+// CGroup at the given location
+func setSystemdCgroupResources(path string, resources *spec.LinuxResources) error {
+	var properties []systemdDbus.Property
+	if resources.Memory != nil && resources.Memory.Limit != nil && *resources.Memory.Limit > 0 {
+		properties = append(properties, systemdDbus.Property{
+			Name:  "MemoryLimit",
+			Value: dbus.MakeVariant(uint64(*resources.Memory.Limit)),
+		})
+	}
+	if resources.CPU != nil && resources.CPU.Quota != nil && *resources.CPU.Quota > 0 && resources.CPU.Period != nil && *resources.CPU.Period > 0 {
+		// systemd takes the quota per second
+		properties = append(properties, systemdDbus.Property{
+			Name:  "CPUQuotaPerSecUSec",
+			Value: dbus.MakeVariant(uint64(*resources.CPU.Quota) * 1000000 / *resources.CPU.Period),
+		})
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+
+	conn, err := systemdDbus.New()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.SetUnitProperties(filepath.Base(path), true, properties...)
+}
+
+// makeCgroupfsCgroup creates a cgroupfs CGroup at the given location, limited
+// to the given resources.
+func makeCgroupfsCgroup(path string, resources *spec.LinuxResources) error {
+	_, err := cgroups.New(cgroups.V1, cgroups.StaticPath(path), resources)
+	return err
 }
 
 // deleteSystemdCgroup deletes the systemd cgroup at the given location
@@ -1,8 +1,11 @@
 package libpod
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRemoveScientificNotationFromFloat(t *testing.T) {
@@ -14,3 +17,23 @@ func TestRemoveScientificNotationFromFloat(t *testing.T) {
 		assert.Equal(t, result, results[i])
 	}
 }
+
+func TestRetryWithBackoff(t *testing.T) {
+	calls := 0
+	err := retryWithBackoff(time.Second, time.Millisecond, 10*time.Millisecond, func() error {
+		if calls++; calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	// The last error is returned on timeout
+	start := time.Now()
+	err = retryWithBackoff(50*time.Millisecond, time.Millisecond, 10*time.Millisecond, func() error {
+		return errors.New("never")
+	})
+	assert.EqualError(t, err, "never")
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
@@ -3,14 +3,19 @@
 package libpod
 
 import (
+	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 )
 
-func systemdSliceFromPath(parent, name string) (string, error) {
+func systemdSliceFromPath(parent, name string, resources *spec.LinuxResources) (string, error) {
 	return "", errors.Wrapf(ErrOSNotSupported, "cgroups are not supported on non-linux OSes")
 }
 
-func makeSystemdCgroup(path string) error {
+func makeSystemdCgroup(path string, resources *spec.LinuxResources) error {
+	return errors.Wrapf(ErrOSNotSupported, "cgroups are not supported on non-linux OSes")
+}
+
+func makeCgroupfsCgroup(path string, resources *spec.LinuxResources) error {
 	return errors.Wrapf(ErrOSNotSupported, "cgroups are not supported on non-linux OSes")
 }
 
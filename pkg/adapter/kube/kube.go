@@ -0,0 +1,523 @@
+// Package kube generates the Kubernetes YAML of libpod containers and pods,
+// a Pod running them and a Service publishing their ports, which podman play
+// kube creates them from again.
+package kube
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+	"github.com/ghodss/yaml"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// defaultEnv are the environment variables libpod and podman set in all
+// containers, left out of the Kubernetes containers when they have their
+// default value
+var defaultEnv = map[string]string{
+	"PATH":      "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+	"TERM":      "xterm",
+	"container": "podman",
+}
+
+// InitContainerTypeAnnotationPrefix prefixes the annotation of a pod giving
+// the type of one of its init containers, once or always. Init containers
+// without one are always run when the pod starts.
+const InitContainerTypeAnnotationPrefix = "init-container-type.podman.io/"
+
+const (
+	// zoneinfoDir is the zoneinfo database of the host
+	zoneinfoDir = "/usr/share/zoneinfo"
+	// localtimePath is the file holding the timezone of the host and of
+	// containers
+	localtimePath = "/etc/localtime"
+)
+
+// GenerateContainerPod returns the Kubernetes pod of a container which is not
+// in a pod, named after it
+func GenerateContainerPod(ctr *libpod.Container) (*v1.Pod, error) {
+	if ctr.PodID() != "" {
+		return nil, errors.Errorf("container %s is in a pod, generate the YAML of its pod instead", ctr.ID())
+	}
+	container, volumes, err := containerToKube(ctr, "")
+	if err != nil {
+		return nil, err
+	}
+	container.Ports = portsToKube(ctr.PortMappings())
+	pod := newPod(ctr.Name()+"-pod", nil, []v1.Container{*container}, volumes)
+	setHostNamespaces(&pod.Spec, ctr.Spec())
+	setTerminationGracePeriod(&pod.Spec, ctr)
+	return pod, nil
+}
+
+// GeneratePod returns the Kubernetes pod of a pod and of its containers other
+// than its infra container, whose ports are the ports of the first container.
+// Init containers are listed as init containers of the pod, annotated with
+// their type when they run once.
+func GeneratePod(pod *libpod.Pod, infra *libpod.Container) (*v1.Pod, error) {
+	ctrs, err := pod.AllContainers()
+	if err != nil {
+		return nil, err
+	}
+	// Containers are listed in the order they were created in
+	sort.Slice(ctrs, func(i, j int) bool {
+		return ctrs[i].CreatedTime().Before(ctrs[j].CreatedTime())
+	})
+
+	var (
+		containers     []v1.Container
+		initContainers []v1.Container
+		annotations    map[string]string
+		volumes        []v1.Volume
+		first          *libpod.Container
+	)
+	for _, ctr := range ctrs {
+		if ctr.IsInfra() {
+			continue
+		}
+		container, ctrVolumes, err := containerToKube(ctr, pod.Name())
+		if err != nil {
+			return nil, err
+		}
+		volumes = mergeVolumes(volumes, ctrVolumes)
+		if initType := ctr.InitContainerType(); initType != "" {
+			initContainers = append(initContainers, *container)
+			if initType != libpod.AlwaysInitContainer {
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+				annotations[InitContainerTypeAnnotationPrefix+container.Name] = initType
+			}
+			continue
+		}
+		containers = append(containers, *container)
+		if first == nil {
+			first = ctr
+		}
+	}
+	if len(containers) == 0 {
+		return nil, errors.Errorf("pod %s has no containers other than its infra container", pod.ID())
+	}
+	if infra != nil {
+		containers[0].Ports = portsToKube(infra.PortMappings())
+	}
+
+	kubePod := newPod(pod.Name(), pod.Labels(), containers, volumes)
+	kubePod.Annotations = annotations
+	kubePod.Spec.InitContainers = initContainers
+	setHostNamespaces(&kubePod.Spec, first.Spec())
+	setTerminationGracePeriod(&kubePod.Spec, first)
+	if pod.SharesPID() {
+		share := true
+		kubePod.Spec.ShareProcessNamespace = &share
+	}
+	return kubePod, nil
+}
+
+// GenerateService returns the NodePort Service publishing the ports of the
+// containers of a pod, with the nodes ports assigned by the cluster
+func GenerateService(pod *v1.Pod) (*v1.Service, error) {
+	var ports []v1.ServicePort
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			ports = append(ports, v1.ServicePort{
+				Name:       strings.ToLower(string(port.Protocol)) + "-" + strconv.Itoa(int(port.ContainerPort)),
+				Protocol:   port.Protocol,
+				Port:       port.ContainerPort,
+				TargetPort: intstr.FromInt(int(port.ContainerPort)),
+			})
+		}
+	}
+	if len(ports) == 0 {
+		return nil, errors.Errorf("pod %s has no ports to publish in a service", pod.Name)
+	}
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   pod.Name,
+			Labels: map[string]string{"app": pod.Name},
+		},
+		Spec: v1.ServiceSpec{
+			Type:     v1.ServiceTypeNodePort,
+			Selector: map[string]string{"app": pod.Name},
+			Ports:    ports,
+		},
+	}, nil
+}
+
+// Marshal returns the YAML of Kubernetes objects, as a document each
+func Marshal(objects ...interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, object := range objects {
+		content, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error marshaling Kubernetes YAML")
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(content)
+	}
+	return buf.Bytes(), nil
+}
+
+// newPod returns a Kubernetes pod, labeled with its name as its app for
+// services to select it
+func newPod(name string, labels map[string]string, containers []v1.Container, volumes []v1.Volume) *v1.Pod {
+	podLabels := map[string]string{"app": name}
+	for key, value := range labels {
+		podLabels[key] = value
+	}
+	return &v1.Pod{
+		TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: podLabels,
+		},
+		Spec: v1.PodSpec{
+			Containers: containers,
+			Volumes:    volumes,
+		},
+	}
+}
+
+// containerToKube returns the Kubernetes container of a container, named
+// without the name of its pod, which podman play kube prepends, and the
+// volumes of its bind mounts
+func containerToKube(ctr *libpod.Container, podName string) (*v1.Container, []v1.Volume, error) {
+	ctrSpec := ctr.Spec()
+	_, imageName := ctr.Image()
+	if imageName == "" {
+		return nil, nil, errors.Errorf("container %s has no image", ctr.ID())
+	}
+	name := ctr.Name()
+	if podName != "" && strings.HasPrefix(name, podName+"-") && len(name) > len(podName)+1 {
+		name = strings.TrimPrefix(name, podName+"-")
+	}
+
+	container := &v1.Container{
+		Name:  name,
+		Image: imageName,
+		Stdin: ctr.Stdin(),
+	}
+	// The command of the container is its entrypoint followed by its
+	// arguments
+	command, entrypoint := ctr.Command(), ctr.Entrypoint()
+	if len(entrypoint) > 0 && len(command) >= len(entrypoint) && reflect.DeepEqual(command[:len(entrypoint)], entrypoint) {
+		container.Command = entrypoint
+		container.Args = command[len(entrypoint):]
+	} else {
+		container.Command = command
+	}
+	if ctrSpec.Process != nil {
+		container.Env = envToKube(ctrSpec.Process.Env)
+		container.TTY = ctrSpec.Process.Terminal
+		if ctrSpec.Process.Cwd != "/" {
+			container.WorkingDir = ctrSpec.Process.Cwd
+		}
+	}
+	if ctrSpec.Linux != nil {
+		container.Resources = resourcesToKube(ctrSpec.Linux.Resources)
+	}
+	container.SecurityContext = securityContextToKube(ctr, ctrSpec)
+
+	mounts, volumes := mountsToKube(ctrSpec.Mounts, ctr.UserVolumes())
+	container.VolumeMounts = mounts
+	// The timezone of the container is mounted as its /etc/localtime, its
+	// TZ and LANG environment variables being in its environment
+	if tz := ctr.Timezone(); tz != "" {
+		mount, volume := timezoneToKube(tz)
+		container.VolumeMounts = append(container.VolumeMounts, mount)
+		volumes = mergeVolumes(volumes, []v1.Volume{volume})
+	}
+	return container, volumes, nil
+}
+
+// timezoneToKube returns the volume mount and hostPath volume of the
+// zoneinfo file of the host mounted as the /etc/localtime of a container with
+// a timezone
+func timezoneToKube(tz string) (v1.VolumeMount, v1.Volume) {
+	path := filepath.Join(zoneinfoDir, tz)
+	if tz == libpod.LocalTimezone {
+		path = localtimePath
+	}
+	name := volumeName(path)
+	hostPathType := v1.HostPathFile
+	mount := v1.VolumeMount{
+		Name:      name,
+		MountPath: localtimePath,
+		ReadOnly:  true,
+	}
+	volume := v1.Volume{
+		Name: name,
+		VolumeSource: v1.VolumeSource{
+			HostPath: &v1.HostPathVolumeSource{Path: path, Type: &hostPathType},
+		},
+	}
+	return mount, volume
+}
+
+// Timezone returns the timezone of a container whose /etc/localtime is
+// mounted from a host path, if it is a zoneinfo file of the host or its
+// /etc/localtime
+func Timezone(hostPath string) (string, bool) {
+	hostPath = filepath.Clean(hostPath)
+	if hostPath == localtimePath {
+		return libpod.LocalTimezone, true
+	}
+	if tz := strings.TrimPrefix(hostPath, zoneinfoDir+"/"); tz != hostPath {
+		return tz, true
+	}
+	return "", false
+}
+
+// envToKube returns the Kubernetes environment variables of the environment
+// of a container, without those set to their default value
+func envToKube(env []string) []v1.EnvVar {
+	var envVars []v1.EnvVar
+	for _, e := range env {
+		split := strings.SplitN(e, "=", 2)
+		value := ""
+		if len(split) > 1 {
+			value = split[1]
+		}
+		// The hostname of the pod is set by Kubernetes
+		if defaultValue, ok := defaultEnv[split[0]]; (ok && defaultValue == value) || split[0] == "HOSTNAME" {
+			continue
+		}
+		envVars = append(envVars, v1.EnvVar{Name: split[0], Value: value})
+	}
+	return envVars
+}
+
+// portsToKube returns the Kubernetes container ports of port mappings
+func portsToKube(mappings []ocicni.PortMapping) []v1.ContainerPort {
+	var ports []v1.ContainerPort
+	for _, mapping := range mappings {
+		protocol := v1.ProtocolTCP
+		if strings.EqualFold(mapping.Protocol, "udp") {
+			protocol = v1.ProtocolUDP
+		}
+		ports = append(ports, v1.ContainerPort{
+			HostPort:      mapping.HostPort,
+			ContainerPort: mapping.ContainerPort,
+			Protocol:      protocol,
+			HostIP:        mapping.HostIP,
+		})
+	}
+	return ports
+}
+
+// resourcesToKube returns the Kubernetes resources of the memory and CPU
+// resources of a container: its limits, and its CPU shares and memory
+// reservation as requests
+func resourcesToKube(resources *spec.LinuxResources) v1.ResourceRequirements {
+	requirements := v1.ResourceRequirements{}
+	if resources == nil {
+		return requirements
+	}
+	limits, requests := v1.ResourceList{}, v1.ResourceList{}
+	if memory := resources.Memory; memory != nil {
+		if memory.Limit != nil && *memory.Limit > 0 {
+			limits[v1.ResourceMemory] = *resource.NewQuantity(*memory.Limit, resource.BinarySI)
+		}
+		if memory.Reservation != nil && *memory.Reservation > 0 {
+			requests[v1.ResourceMemory] = *resource.NewQuantity(*memory.Reservation, resource.BinarySI)
+		}
+	}
+	if cpu := resources.CPU; cpu != nil {
+		if cpu.Quota != nil && *cpu.Quota > 0 && cpu.Period != nil && *cpu.Period > 0 {
+			limits[v1.ResourceCPU] = *resource.NewMilliQuantity(*cpu.Quota*1000/int64(*cpu.Period), resource.DecimalSI)
+		}
+		if cpu.Shares != nil && *cpu.Shares > 0 {
+			requests[v1.ResourceCPU] = *resource.NewMilliQuantity(int64(*cpu.Shares)*1000/1024, resource.DecimalSI)
+		}
+	}
+	if len(limits) > 0 {
+		requirements.Limits = limits
+	}
+	if len(requests) > 0 {
+		requirements.Requests = requests
+	}
+	return requirements
+}
+
+// securityContextToKube returns the Kubernetes security context of a
+// container, or nil if it has the default one
+func securityContextToKube(ctr *libpod.Container, ctrSpec *spec.Spec) *v1.SecurityContext {
+	var (
+		context = &v1.SecurityContext{}
+		set     bool
+	)
+	if ctr.Privileged() {
+		privileged := true
+		context.Privileged = &privileged
+		set = true
+	}
+	if ctrSpec.Root != nil && ctrSpec.Root.Readonly {
+		readOnly := true
+		context.ReadOnlyRootFilesystem = &readOnly
+		set = true
+	}
+	if ctrSpec.Process != nil && ctrSpec.Process.NoNewPrivileges {
+		allow := false
+		context.AllowPrivilegeEscalation = &allow
+		set = true
+	}
+	// Only numeric users have a Kubernetes equivalent
+	if uid, err := strconv.ParseInt(strings.SplitN(ctr.User(), ":", 2)[0], 10, 64); err == nil {
+		context.RunAsUser = &uid
+		set = true
+	}
+	if !ctr.Privileged() && ctrSpec.Process != nil && ctrSpec.Process.Capabilities != nil {
+		if capabilities := capabilitiesToKube(ctrSpec.Process.Capabilities.Bounding); capabilities != nil {
+			context.Capabilities = capabilities
+			set = true
+		}
+	}
+	if !set {
+		return nil
+	}
+	return context
+}
+
+// capabilitiesToKube returns the Kubernetes capabilities added and dropped
+// to get the bounding capabilities of a container from the default ones, or
+// nil if they are the default ones
+func capabilitiesToKube(bounding []string) *v1.Capabilities {
+	g, err := generate.New("linux")
+	if err != nil || g.Config.Process == nil || g.Config.Process.Capabilities == nil {
+		return nil
+	}
+	defaults := g.Config.Process.Capabilities.Bounding
+	capabilities := &v1.Capabilities{}
+	for _, capability := range bounding {
+		if !util.StringInSlice(capability, defaults) {
+			capabilities.Add = append(capabilities.Add, v1.Capability(strings.TrimPrefix(capability, "CAP_")))
+		}
+	}
+	for _, capability := range defaults {
+		if !util.StringInSlice(capability, bounding) {
+			capabilities.Drop = append(capabilities.Drop, v1.Capability(strings.TrimPrefix(capability, "CAP_")))
+		}
+	}
+	if len(capabilities.Add) == 0 && len(capabilities.Drop) == 0 {
+		return nil
+	}
+	return capabilities
+}
+
+// mountsToKube returns the Kubernetes volume mounts and hostPath volumes of
+// the bind mounts of a container from the host paths of the user
+func mountsToKube(mounts []spec.Mount, userVolumes []string) ([]v1.VolumeMount, []v1.Volume) {
+	var (
+		volumeMounts []v1.VolumeMount
+		volumes      []v1.Volume
+	)
+	for _, mount := range mounts {
+		if mount.Type != "bind" || !util.StringInSlice(mount.Source, userVolumes) {
+			continue
+		}
+		name := volumeName(mount.Source)
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      name,
+			MountPath: mount.Destination,
+			ReadOnly:  util.StringInSlice("ro", mount.Options),
+		})
+		hostPathType := v1.HostPathUnset
+		if info, err := os.Stat(mount.Source); err == nil {
+			if info.IsDir() {
+				hostPathType = v1.HostPathDirectory
+			} else {
+				hostPathType = v1.HostPathFile
+			}
+		}
+		volumes = mergeVolumes(volumes, []v1.Volume{{
+			Name: name,
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{Path: mount.Source, Type: &hostPathType},
+			},
+		}})
+	}
+	return volumeMounts, volumes
+}
+
+// volumeName returns the name of the volume of a host path, valid as the
+// name of a Kubernetes volume
+func volumeName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, strings.Trim(path, "/"))
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "root-host"
+	}
+	return name + "-host"
+}
+
+// mergeVolumes appends the volumes not already in a list of volumes to it
+func mergeVolumes(volumes, others []v1.Volume) []v1.Volume {
+	for _, other := range others {
+		found := false
+		for _, volume := range volumes {
+			if volume.Name == other.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			volumes = append(volumes, other)
+		}
+	}
+	return volumes
+}
+
+// setHostNamespaces sets the host namespaces of a Kubernetes pod from the
+// namespaces of the spec of one of its containers: those it does not have
+// its own of are the host's
+func setHostNamespaces(podSpec *v1.PodSpec, ctrSpec *spec.Spec) {
+	if ctrSpec == nil || ctrSpec.Linux == nil {
+		return
+	}
+	has := func(nsType spec.LinuxNamespaceType) bool {
+		for _, ns := range ctrSpec.Linux.Namespaces {
+			if ns.Type == nsType {
+				return true
+			}
+		}
+		return false
+	}
+	podSpec.HostNetwork = !has(spec.NetworkNamespace)
+	podSpec.HostIPC = !has(spec.IPCNamespace)
+	podSpec.HostPID = !has(spec.PIDNamespace)
+}
+
+// setTerminationGracePeriod sets the termination grace period of a Kubernetes
+// pod to the stop timeout of one of its containers, unless it is the default
+// one
+func setTerminationGracePeriod(podSpec *v1.PodSpec, ctr *libpod.Container) {
+	if ctr.StopTimeout() != libpod.CtrRemoveTimeout {
+		seconds := int64(ctr.StopTimeout())
+		podSpec.TerminationGracePeriodSeconds = &seconds
+	}
+}
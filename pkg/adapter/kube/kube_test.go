@@ -0,0 +1,182 @@
+package kube
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+	"github.com/ghodss/yaml"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+)
+
+func TestEnvToKube(t *testing.T) {
+	env := envToKube([]string{
+		"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+		"TERM=xterm",
+		"container=podman",
+		"HOSTNAME=abc",
+		"MODE=production",
+		"EMPTY",
+		"TERM2=vt100",
+	})
+	assert.Equal(t, []v1.EnvVar{{Name: "MODE", Value: "production"}, {Name: "EMPTY"}, {Name: "TERM2", Value: "vt100"}}, env)
+	assert.Equal(t, []v1.EnvVar{{Name: "PATH", Value: "/bin"}}, envToKube([]string{"PATH=/bin"}))
+}
+
+func TestPortsToKube(t *testing.T) {
+	ports := portsToKube([]ocicni.PortMapping{
+		{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostPort: 53, ContainerPort: 53, Protocol: "udp", HostIP: "127.0.0.1"},
+	})
+	assert.Equal(t, []v1.ContainerPort{
+		{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+		{HostPort: 53, ContainerPort: 53, Protocol: v1.ProtocolUDP, HostIP: "127.0.0.1"},
+	}, ports)
+}
+
+func TestResourcesToKube(t *testing.T) {
+	assert.Equal(t, v1.ResourceRequirements{}, resourcesToKube(nil))
+	assert.Equal(t, v1.ResourceRequirements{}, resourcesToKube(&spec.LinuxResources{}))
+
+	limit, reservation := int64(64*1024*1024), int64(32*1024*1024)
+	quota, period, shares := int64(50000), uint64(100000), uint64(512)
+	requirements := resourcesToKube(&spec.LinuxResources{
+		Memory: &spec.LinuxMemory{Limit: &limit, Reservation: &reservation},
+		CPU:    &spec.LinuxCPU{Quota: &quota, Period: &period, Shares: &shares},
+	})
+	memory := requirements.Limits[v1.ResourceMemory]
+	assert.Equal(t, "64Mi", memory.String())
+	cpu := requirements.Limits[v1.ResourceCPU]
+	assert.Equal(t, "500m", cpu.String())
+	memory = requirements.Requests[v1.ResourceMemory]
+	assert.Equal(t, "32Mi", memory.String())
+	cpu = requirements.Requests[v1.ResourceCPU]
+	assert.Equal(t, "500m", cpu.String())
+}
+
+func TestCapabilitiesToKube(t *testing.T) {
+	g, err := generate.New("linux")
+	require.NoError(t, err)
+	defaults := g.Config.Process.Capabilities.Bounding
+	assert.Nil(t, capabilitiesToKube(defaults))
+
+	var bounding []string
+	for _, capability := range defaults {
+		if capability != "CAP_MKNOD" {
+			bounding = append(bounding, capability)
+		}
+	}
+	bounding = append(bounding, "CAP_NET_ADMIN")
+	assert.Equal(t, &v1.Capabilities{Add: []v1.Capability{"NET_ADMIN"}, Drop: []v1.Capability{"MKNOD"}}, capabilitiesToKube(bounding))
+}
+
+func TestMountsToKube(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mounts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "app.conf")
+	require.NoError(t, ioutil.WriteFile(file, nil, 0644))
+
+	mounts, volumes := mountsToKube([]spec.Mount{
+		{Type: "bind", Source: dir, Destination: "/data", Options: []string{"rbind", "rw"}},
+		{Type: "bind", Source: dir, Destination: "/backup", Options: []string{"rbind", "ro"}},
+		{Type: "bind", Source: file, Destination: "/etc/app.conf", Options: []string{"rbind", "ro"}},
+		{Type: "bind", Source: "/etc/hosts", Destination: "/etc/hosts"},
+		{Type: "tmpfs", Source: "tmpfs", Destination: "/tmp"},
+	}, []string{dir, file})
+	dirName, fileName := volumeName(dir), volumeName(file)
+	assert.Equal(t, []v1.VolumeMount{
+		{Name: dirName, MountPath: "/data"},
+		{Name: dirName, MountPath: "/backup", ReadOnly: true},
+		{Name: fileName, MountPath: "/etc/app.conf", ReadOnly: true},
+	}, mounts)
+	require.Len(t, volumes, 2)
+	assert.Equal(t, dirName, volumes[0].Name)
+	assert.Equal(t, dir, volumes[0].HostPath.Path)
+	assert.Equal(t, v1.HostPathDirectory, *volumes[0].HostPath.Type)
+	assert.Equal(t, v1.HostPathFile, *volumes[1].HostPath.Type)
+}
+
+func TestVolumeName(t *testing.T) {
+	assert.Equal(t, "srv-data-host", volumeName("/srv/data/"))
+	assert.Equal(t, "home-user-app-conf-host", volumeName("/home/User/app.conf"))
+	assert.Equal(t, "root-host", volumeName("/"))
+}
+
+func TestTimezoneToKube(t *testing.T) {
+	mount, volume := timezoneToKube("Europe/Paris")
+	assert.Equal(t, v1.VolumeMount{Name: "usr-share-zoneinfo-europe-paris-host", MountPath: "/etc/localtime", ReadOnly: true}, mount)
+	assert.Equal(t, mount.Name, volume.Name)
+	assert.Equal(t, "/usr/share/zoneinfo/Europe/Paris", volume.HostPath.Path)
+	assert.Equal(t, v1.HostPathFile, *volume.HostPath.Type)
+	tz, ok := Timezone(volume.HostPath.Path)
+	assert.True(t, ok)
+	assert.Equal(t, "Europe/Paris", tz)
+
+	_, volume = timezoneToKube(libpod.LocalTimezone)
+	assert.Equal(t, "/etc/localtime", volume.HostPath.Path)
+	tz, ok = Timezone(volume.HostPath.Path)
+	assert.True(t, ok)
+	assert.Equal(t, libpod.LocalTimezone, tz)
+
+	_, ok = Timezone("/srv/localtime")
+	assert.False(t, ok)
+}
+
+func TestSetHostNamespaces(t *testing.T) {
+	var podSpec v1.PodSpec
+	setHostNamespaces(&podSpec, &spec.Spec{Linux: &spec.Linux{Namespaces: []spec.LinuxNamespace{
+		{Type: spec.PIDNamespace},
+		{Type: spec.IPCNamespace},
+		{Type: spec.MountNamespace},
+	}}})
+	assert.True(t, podSpec.HostNetwork)
+	assert.False(t, podSpec.HostIPC)
+	assert.False(t, podSpec.HostPID)
+}
+
+func TestGenerateService(t *testing.T) {
+	pod := newPod("web", map[string]string{"tier": "front"}, []v1.Container{{
+		Name:  "nginx",
+		Image: "nginx:alpine",
+		Ports: []v1.ContainerPort{{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP}},
+	}}, nil)
+	assert.Equal(t, map[string]string{"app": "web", "tier": "front"}, pod.Labels)
+
+	service, err := GenerateService(pod)
+	require.NoError(t, err)
+	assert.Equal(t, "web", service.Name)
+	assert.Equal(t, v1.ServiceTypeNodePort, service.Spec.Type)
+	assert.Equal(t, map[string]string{"app": "web"}, service.Spec.Selector)
+	require.Len(t, service.Spec.Ports, 1)
+	assert.Equal(t, "tcp-80", service.Spec.Ports[0].Name)
+	assert.Equal(t, int32(80), service.Spec.Ports[0].Port)
+	assert.Equal(t, 80, service.Spec.Ports[0].TargetPort.IntValue())
+
+	pod.Spec.Containers[0].Ports = nil
+	_, err = GenerateService(pod)
+	assert.Error(t, err)
+}
+
+func TestMarshal(t *testing.T) {
+	pod := newPod("web", nil, []v1.Container{{Name: "nginx", Image: "nginx:alpine"}}, nil)
+	content, err := Marshal(pod, pod)
+	require.NoError(t, err)
+	documents := strings.Split(string(content), "---\n")
+	require.Len(t, documents, 2)
+	assert.Equal(t, documents[0], documents[1])
+
+	var again v1.Pod
+	require.NoError(t, yaml.Unmarshal([]byte(documents[0]), &again))
+	assert.Equal(t, "Pod", again.Kind)
+	assert.Equal(t, "web", again.Name)
+	assert.Equal(t, pod.Spec.Containers, again.Spec.Containers)
+}
@@ -0,0 +1,213 @@
+// Package audit keeps an append-only log of the operations modifying
+// containers, pods and images, with the identity of their caller, for the
+// CLI, the API and varlink.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SourceCLI is the source of the records of podman commands
+	SourceCLI = "cli"
+	// SourceAPI is the source of the records of API requests
+	SourceAPI = "api"
+	// SourceVarlink is the source of the records of varlink calls
+	SourceVarlink = "varlink"
+	// UnknownUID is the UID of the records of callers whose UID is unknown
+	UnknownUID = -1
+)
+
+// Record is an operation of the audit log
+type Record struct {
+	// Time is when the operation was requested
+	Time time.Time `json:"time"`
+	// Source is where the operation was requested, SourceCLI, SourceAPI
+	// or SourceVarlink
+	Source string `json:"source"`
+	// UID is the user ID of the caller, UnknownUID if not known
+	UID int `json:"uid"`
+	// PID is the process ID of the caller, if known
+	PID int `json:"pid,omitempty"`
+	// CN is the common name of the TLS client certificate of the caller,
+	// if any
+	CN string `json:"cn,omitempty"`
+	// Operation is the podman command, such as "container rm", or the
+	// method and route of the API request, such as
+	// "POST /containers/{name}/start", or the varlink method, such as
+	// "io.podman.StartContainer"
+	Operation string `json:"operation"`
+	// Target is what the operation is applied to, such as the names of
+	// containers
+	Target []string `json:"target,omitempty"`
+	// ArgsHash is the SHA-256 of the arguments of the operation, as HEX
+	ArgsHash string `json:"argsHash"`
+	// Status is the HTTP status the API answered, 0 for the CLI and
+	// varlink
+	Status int `json:"status,omitempty"`
+}
+
+// HashArgs returns the hash of arguments for the ArgsHash of records
+func HashArgs(args ...string) string {
+	hash := sha256.New()
+	for _, arg := range args {
+		// Separate arguments, so that ["ab"] and ["a", "b"] differ
+		hash.Write([]byte(strconv.Itoa(len(arg)) + ":" + arg))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Log is an audit log, kept in a file with one JSON record per line. Records
+// are only appended to it.
+type Log struct {
+	path string
+}
+
+// NewLog returns the audit log kept in the file at path
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Write appends a record to the log
+func (l *Log) Write(record Record) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return errors.Wrapf(err, "error creating the directory of audit log %s", l.path)
+	}
+	lock, err := storage.GetLockfile(l.path + ".lock")
+	if err != nil {
+		return errors.Wrapf(err, "error locking audit log %s", l.path)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "error opening audit log %s", l.path)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return errors.Wrapf(err, "error writing to audit log %s", l.path)
+	}
+	return nil
+}
+
+// Query selects the records read from the log
+type Query struct {
+	// Since is the time from which records are read, if not zero
+	Since time.Time
+	// Until is the time until which records are read, if not zero
+	Until time.Time
+	// Filters are the filters records must match, as KEY=VALUE: operation,
+	// source, target and uid. Records must match one of the values of each
+	// key.
+	Filters []string
+}
+
+// recordFilters are the filters of records, by key
+var recordFilters = map[string]func(r *Record, value string) bool{
+	"operation": func(r *Record, value string) bool {
+		return r.Operation == value
+	},
+	"source": func(r *Record, value string) bool {
+		return r.Source == value
+	},
+	"target": func(r *Record, value string) bool {
+		for _, target := range r.Target {
+			if target == value {
+				return true
+			}
+		}
+		return false
+	},
+	"uid": func(r *Record, value string) bool {
+		return strconv.Itoa(r.UID) == value
+	},
+}
+
+// matcher returns a function telling whether records match the query
+func (q Query) matcher() (func(r *Record) bool, error) {
+	filters := make(map[string][]string)
+	for _, filter := range q.Filters {
+		split := strings.SplitN(filter, "=", 2)
+		if len(split) != 2 || split[1] == "" {
+			return nil, errors.Errorf("invalid filter %q, must be KEY=VALUE", filter)
+		}
+		key := strings.ToLower(strings.TrimSpace(split[0]))
+		if _, ok := recordFilters[key]; !ok {
+			return nil, errors.Errorf("unknown filter %q", key)
+		}
+		filters[key] = append(filters[key], strings.TrimSpace(split[1]))
+	}
+	return func(r *Record) bool {
+		if !q.Since.IsZero() && r.Time.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && r.Time.After(q.Until) {
+			return false
+		}
+		for key, values := range filters {
+			matched := false
+			for _, value := range values {
+				if recordFilters[key](r, value) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// Read returns the records of the log matching the query, oldest first
+func (l *Log) Read(query Query) ([]Record, error) {
+	match, err := query.matcher()
+	if err != nil {
+		return nil, err
+	}
+	records := []Record{}
+	f, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, errors.Wrapf(err, "error opening audit log %s", l.path)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Only the last line can be partial, as it is written
+			logrus.Debugf("Skipping invalid audit record %q: %v", scanner.Text(), err)
+			continue
+		}
+		if match(&record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "error reading audit log %s", l.path)
+	}
+	return records, nil
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashArgs(t *testing.T) {
+	assert.Equal(t, HashArgs("rm", "foo"), HashArgs("rm", "foo"))
+	assert.NotEqual(t, HashArgs("rm", "foo"), HashArgs("rm", "bar"))
+	assert.NotEqual(t, HashArgs("ab"), HashArgs("a", "b"))
+	assert.Len(t, HashArgs(), 64)
+}
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit", "audit.log")
+	auditLog := NewLog(path)
+
+	// Nothing was recorded yet
+	records, err := auditLog.Read(Query{})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	now := time.Now()
+	for _, record := range []Record{
+		{Time: now.Add(-time.Hour), Source: SourceCLI, UID: 1000, Operation: "rm", Target: []string{"foo"}},
+		{Time: now, Source: SourceAPI, UID: 0, Operation: "POST /containers/{name}/start", Target: []string{"bar"}, Status: 204},
+		{Time: now, Source: SourceCLI, UID: 0, Operation: "run", Target: []string{"alpine"}},
+	} {
+		require.NoError(t, auditLog.Write(record))
+	}
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	for _, tc := range []struct {
+		query     Query
+		operation []string
+	}{
+		{Query{}, []string{"rm", "POST /containers/{name}/start", "run"}},
+		{Query{Since: now.Add(-time.Minute)}, []string{"POST /containers/{name}/start", "run"}},
+		{Query{Until: now.Add(-time.Minute)}, []string{"rm"}},
+		{Query{Filters: []string{"source=cli"}}, []string{"rm", "run"}},
+		{Query{Filters: []string{"uid=0", "source=cli"}}, []string{"run"}},
+		{Query{Filters: []string{"target=foo", "target=bar"}}, []string{"rm", "POST /containers/{name}/start"}},
+		{Query{Filters: []string{"operation=run"}}, []string{"run"}},
+	} {
+		records, err := auditLog.Read(tc.query)
+		require.NoError(t, err)
+		var operations []string
+		for _, record := range records {
+			operations = append(operations, record.Operation)
+		}
+		assert.Equal(t, tc.operation, operations, "%v", tc.query.Filters)
+	}
+
+	for _, filters := range [][]string{{"uid"}, {"uid="}, {"image=alpine"}} {
+		_, err := auditLog.Read(Query{Filters: filters})
+		assert.Error(t, err, filters)
+	}
+}
@@ -0,0 +1,195 @@
+// Package auth manages the credentials podman authenticates to registries
+// with: those stored in its authentication file, auth.json, or by the
+// credential helpers it configures, and those clients send for a single
+// request.
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/docker"
+	"github.com/containers/image/pkg/docker/config"
+	"github.com/containers/image/types"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/rootless"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+)
+
+// GetAuthFile returns the path of the authentication file: authfile if it is
+// not empty, else containers/auth.json in ${XDG_RUNTIME_DIR}, the runtime
+// directory of podman when rootless, or /run/containers/UID/auth.json.
+func GetAuthFile(authfile string) (string, error) {
+	if authfile != "" {
+		return authfile, nil
+	}
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" && rootless.IsRootless() {
+		// The runtime sets XDG_RUNTIME_DIR to this directory, where
+		// pulls find the file
+		var err error
+		if runtimeDir, err = libpod.GetRootlessRuntimeDir(); err != nil {
+			return "", err
+		}
+	}
+	if runtimeDir != "" {
+		return filepath.Join(runtimeDir, "containers", "auth.json"), nil
+	}
+	return fmt.Sprintf("/run/containers/%d/auth.json", os.Getuid()), nil
+}
+
+// Login checks the credentials of a user with a registry, and stores them in
+// the authentication file of sys or with the credential helper configured
+// for the registry. docker.ErrUnauthorizedForCredentials is returned for
+// credentials the registry refuses.
+func Login(ctx context.Context, sys *types.SystemContext, registry, username, password string) error {
+	if err := docker.CheckAuth(ctx, sys, username, password, registry); err != nil {
+		return err
+	}
+	if err := config.SetAuthentication(sys, registry, username, password); err != nil {
+		return err
+	}
+	// containers/image writes the file readable by all users
+	if sys != nil && sys.AuthFilePath != "" {
+		if err := os.Chmod(sys.AuthFilePath, 0600); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error setting the permissions of %s", sys.AuthFilePath)
+		}
+	}
+	return nil
+}
+
+// Logout removes the credentials of a registry from the authentication file
+// of sys, or from the credential helper configured for the registry.
+// config.ErrNotLoggedIn is returned when there are none.
+func Logout(sys *types.SystemContext, registry string) error {
+	return config.RemoveAuthentication(sys, registry)
+}
+
+// LogoutAll removes the credentials of all the registries, and the
+// configuration of their credential helpers, from the authentication file of
+// sys
+func LogoutAll(sys *types.SystemContext) error {
+	return config.RemoveAllAuthentication(sys)
+}
+
+// CredentialHelpers returns the credential helpers configured in an
+// authentication file, by registry
+func CredentialHelpers(authfile string) (map[string]string, error) {
+	content, err := readAuthFile(authfile)
+	if err != nil {
+		return nil, err
+	}
+	helpers := make(map[string]string)
+	if raw, ok := content["credHelpers"]; ok {
+		if err := json.Unmarshal(raw, &helpers); err != nil {
+			return nil, errors.Wrapf(err, "error parsing the credential helpers of %s", authfile)
+		}
+	}
+	return helpers, nil
+}
+
+// SetCredentialHelper configures the credential helper storing the
+// credentials of a registry in an authentication file, or removes it if
+// helper is empty. The helper is the docker-credential-HELPER program, found
+// in $PATH, as for Docker.
+func SetCredentialHelper(authfile, registry, helper string) error {
+	if registry == "" {
+		return errors.Errorf("registry must be given")
+	}
+	if strings.ContainsRune(helper, filepath.Separator) {
+		return errors.Errorf("invalid credential helper %q, it is the suffix of the name of a docker-credential- program", helper)
+	}
+	content, err := readAuthFile(authfile)
+	if err != nil {
+		return err
+	}
+	helpers, err := CredentialHelpers(authfile)
+	if err != nil {
+		return err
+	}
+	if helper == "" {
+		delete(helpers, registry)
+	} else {
+		helpers[registry] = helper
+	}
+	if content["credHelpers"], err = json.Marshal(helpers); err != nil {
+		return err
+	}
+	if _, ok := content["auths"]; !ok {
+		content["auths"] = json.RawMessage("{}")
+	}
+	data, err := json.MarshalIndent(content, "", "\t")
+	if err != nil {
+		return errors.Wrapf(err, "error encoding %s", authfile)
+	}
+	if err := os.MkdirAll(filepath.Dir(authfile), 0700); err != nil {
+		return errors.Wrapf(err, "error creating the directory of %s", authfile)
+	}
+	if err := ioutil.WriteFile(authfile, data, 0600); err != nil {
+		return errors.Wrapf(err, "error writing %s", authfile)
+	}
+	return nil
+}
+
+// readAuthFile returns the top-level entries of an authentication file,
+// which is empty if it does not exist, keeping those podman does not know
+func readAuthFile(authfile string) (map[string]json.RawMessage, error) {
+	content := make(map[string]json.RawMessage)
+	data, err := ioutil.ReadFile(authfile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return content, nil
+		}
+		return nil, errors.Wrapf(err, "error reading %s", authfile)
+	}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, errors.Wrapf(err, "error parsing %s", authfile)
+	}
+	return content, nil
+}
+
+// ParseRegistryAuth returns the credentials of a X-Registry-Auth header, the
+// JSON of the credentials encoded in base64 Docker clients send for a single
+// pull or push. They are used for the request only, not stored. nil is
+// returned for a header without credentials, to use the stored ones.
+func ParseRegistryAuth(header string) (*types.DockerAuthConfig, error) {
+	if header == "" {
+		return nil, nil
+	}
+	data, err := base64.URLEncoding.DecodeString(header)
+	if err != nil {
+		// Some clients encode the header with the standard alphabet
+		if data, err = base64.StdEncoding.DecodeString(header); err != nil {
+			return nil, errors.Wrapf(err, "error decoding the registry credentials")
+		}
+	}
+	var authConfig dockertypes.AuthConfig
+	if err := json.Unmarshal(data, &authConfig); err != nil {
+		return nil, errors.Wrapf(err, "error parsing the registry credentials")
+	}
+	if authConfig.Username == "" && authConfig.Password == "" && authConfig.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(authConfig.Auth)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error decoding the auth of the registry credentials")
+		}
+		userPassword := strings.SplitN(string(decoded), ":", 2)
+		if len(userPassword) != 2 {
+			return nil, errors.Errorf("invalid auth of the registry credentials, it must be username:password")
+		}
+		authConfig.Username, authConfig.Password = userPassword[0], userPassword[1]
+	}
+	if authConfig.Username == "" && authConfig.Password == "" {
+		if authConfig.IdentityToken != "" || authConfig.RegistryToken != "" {
+			return nil, errors.Errorf("registry tokens are not supported, credentials must have a username and password")
+		}
+		return nil, nil
+	}
+	return &types.DockerAuthConfig{Username: authConfig.Username, Password: authConfig.Password}, nil
+}
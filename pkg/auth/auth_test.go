@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/image/pkg/docker/config"
+	"github.com/containers/image/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAuthFile(t *testing.T) {
+	path, err := GetAuthFile("/tmp/auth.json")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/auth.json", path)
+
+	oldRuntimeDir, hasRuntimeDir := os.LookupEnv("XDG_RUNTIME_DIR")
+	defer func() {
+		if hasRuntimeDir {
+			os.Setenv("XDG_RUNTIME_DIR", oldRuntimeDir)
+		} else {
+			os.Unsetenv("XDG_RUNTIME_DIR")
+		}
+	}()
+	os.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	path, err = GetAuthFile("")
+	require.NoError(t, err)
+	assert.Equal(t, "/run/user/1000/containers/auth.json", path)
+}
+
+func TestSetCredentialHelper(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	authfile := filepath.Join(dir, "containers", "auth.json")
+
+	helpers, err := CredentialHelpers(authfile)
+	require.NoError(t, err)
+	assert.Empty(t, helpers)
+
+	require.NoError(t, SetCredentialHelper(authfile, "quay.io", "secretservice"))
+	require.NoError(t, SetCredentialHelper(authfile, "registry.example.com", "pass"))
+	helpers, err = CredentialHelpers(authfile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"quay.io": "secretservice", "registry.example.com": "pass"}, helpers)
+	info, err := os.Stat(authfile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// The credentials stored in the file and its other entries are kept
+	sys := &types.SystemContext{AuthFilePath: authfile}
+	require.NoError(t, config.SetAuthentication(sys, "docker.io", "user", "secret"))
+	data, err := ioutil.ReadFile(authfile)
+	require.NoError(t, err)
+	var content map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &content))
+	content["detachKeys"] = "ctrl-a"
+	data, err = json.Marshal(content)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(authfile, data, 0600))
+
+	require.NoError(t, SetCredentialHelper(authfile, "registry.example.com", ""))
+	helpers, err = CredentialHelpers(authfile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"quay.io": "secretservice"}, helpers)
+	username, password, err := config.GetAuthentication(sys, "docker.io")
+	require.NoError(t, err)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "secret", password)
+	data, err = ioutil.ReadFile(authfile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"detachKeys": "ctrl-a"`)
+
+	assert.Error(t, SetCredentialHelper(authfile, "", "pass"))
+	assert.Error(t, SetCredentialHelper(authfile, "quay.io", "../pass"))
+}
+
+func TestParseRegistryAuth(t *testing.T) {
+	encode := func(s string) string {
+		return base64.URLEncoding.EncodeToString([]byte(s))
+	}
+	for _, tc := range []struct {
+		header   string
+		expected *types.DockerAuthConfig
+	}{
+		{"", nil},
+		{encode("{}"), nil},
+		{encode(`{"username":"user","password":"secret","serveraddress":"quay.io"}`), &types.DockerAuthConfig{Username: "user", Password: "secret"}},
+		{base64.StdEncoding.EncodeToString([]byte(`{"username":"user","password":"se?cr>et"}`)), &types.DockerAuthConfig{Username: "user", Password: "se?cr>et"}},
+		{encode(`{"auth":"` + base64.StdEncoding.EncodeToString([]byte("user:pass:word")) + `"}`), &types.DockerAuthConfig{Username: "user", Password: "pass:word"}},
+	} {
+		authConfig, err := ParseRegistryAuth(tc.header)
+		require.NoError(t, err, tc.header)
+		assert.Equal(t, tc.expected, authConfig, tc.header)
+	}
+
+	for _, header := range []string{
+		"not base64!",
+		encode("not json"),
+		encode(`{"auth":"not base64!"}`),
+		encode(`{"auth":"` + base64.StdEncoding.EncodeToString([]byte("user")) + `"}`),
+		encode(`{"identitytoken":"token"}`),
+	} {
+		_, err := ParseRegistryAuth(header)
+		assert.Error(t, err, header)
+	}
+}
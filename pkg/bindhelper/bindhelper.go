@@ -0,0 +1,373 @@
+// Package bindhelper forwards privileged ports on behalf of rootless users,
+// who can not bind the ports below the unprivileged port start of the host.
+// The helper runs as root, binds the privileged TCP ports its clients ask for
+// over a unix socket, only accessible to a group, and forwards their
+// connections to an unprivileged port of the loopback address the user
+// listens on. It only binds the ports each user is allowed.
+package bindhelper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultSocketPath is where the helper listens by default
+	DefaultSocketPath = "/run/podman/bind-helper.sock"
+	// DefaultGroup is the group allowed to connect to the helper by
+	// default
+	DefaultGroup = "podman-bind"
+
+	// unprivilegedPortStartPath is the sysctl of the first port
+	// unprivileged users can bind
+	unprivilegedPortStartPath = "/proc/sys/net/ipv4/ip_unprivileged_port_start"
+	// defaultUnprivilegedPortStart is the first port unprivileged users
+	// can bind on kernels without the sysctl
+	defaultUnprivilegedPortStart = 1024
+)
+
+// Request asks the helper to forward a privileged port
+type Request struct {
+	// Protocol is the protocol of the port, only tcp can be forwarded
+	Protocol string `json:"protocol"`
+	// HostIP is the address to bind, all the addresses when empty
+	HostIP string `json:"hostIP,omitempty"`
+	// HostPort is the privileged port to bind
+	HostPort int32 `json:"hostPort"`
+	// TargetPort is the unprivileged port of the loopback address the
+	// connections are forwarded to, which a socket of the user must
+	// listen on
+	TargetPort int32 `json:"targetPort"`
+}
+
+// response is sent back to the client once the port is bound, or with the
+// error preventing it
+type response struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PortRange is a range of ports a user is allowed to bind
+type PortRange struct {
+	// Protocol is the protocol of the ports, tcp or udp, or both when
+	// empty
+	Protocol string
+	// Start is the first port of the range
+	Start int32
+	// End is the last port of the range
+	End int32
+}
+
+// contains returns whether the range includes the port of a request
+func (r PortRange) contains(req *Request) bool {
+	if r.Protocol != "" && r.Protocol != req.Protocol {
+		return false
+	}
+	return req.HostPort >= r.Start && req.HostPort <= r.End
+}
+
+// parsePort parses a port of a range
+func parsePort(port string) (int32, error) {
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || n == 0 {
+		return 0, errors.Errorf("invalid port %q", port)
+	}
+	return int32(n), nil
+}
+
+// ParsePortRanges parses a comma separated list of ports and ranges of ports,
+// such as 80,443/tcp,8000-8080. A protocol, tcp or udp, can follow each of
+// them after a slash, both protocols are allowed otherwise.
+func ParsePortRanges(spec string) ([]PortRange, error) {
+	var ranges []PortRange
+	for _, field := range strings.Split(spec, ",") {
+		var r PortRange
+		if i := strings.Index(field, "/"); i >= 0 {
+			r.Protocol = field[i+1:]
+			field = field[:i]
+			if r.Protocol != "tcp" && r.Protocol != "udp" {
+				return nil, errors.Errorf("unknown protocol %q", r.Protocol)
+			}
+		}
+		start, end := field, field
+		if i := strings.Index(field, "-"); i >= 0 {
+			start, end = field[:i], field[i+1:]
+		}
+		var err error
+		if r.Start, err = parsePort(start); err != nil {
+			return nil, err
+		}
+		if r.End, err = parsePort(end); err != nil {
+			return nil, err
+		}
+		if r.End < r.Start {
+			return nil, errors.Errorf("invalid range of ports %q", field)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// UnprivilegedPortStart returns the first port unprivileged users can bind
+func UnprivilegedPortStart() int32 {
+	data, err := ioutil.ReadFile(unprivilegedPortStartPath)
+	if err != nil {
+		return defaultUnprivilegedPortStart
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return defaultUnprivilegedPortStart
+	}
+	return int32(start)
+}
+
+// Privileged returns whether binding the port requires privileges
+func Privileged(port int32) bool {
+	return port > 0 && port < UnprivilegedPortStart()
+}
+
+// Forward asks the helper listening on socketPath to forward a privileged port
+// to a port of the loopback address. The helper forwards the port until the
+// returned connection to it is closed, by the process the port is forwarded
+// for inheriting it.
+func Forward(socketPath string, req *Request) (*os.File, error) {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot connect to the bind helper at %s", socketPath)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, errors.Wrapf(err, "cannot send request to the bind helper")
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, errors.Wrapf(err, "cannot read response of the bind helper")
+	}
+	if resp.Error != "" {
+		return nil, errors.Errorf("bind helper: %s", resp.Error)
+	}
+	return conn.(*net.UnixConn).File()
+}
+
+// Helper forwards privileged ports for the users connecting to its socket
+type Helper struct {
+	portStart int32
+	// allowed are the ports each user, by UID, is allowed to bind
+	allowed map[int][]PortRange
+}
+
+// NewHelper returns a helper forwarding the ports below the unprivileged port
+// start of the host that are allowed to the users connecting to it. The ports
+// of the users not in allowed are refused.
+func NewHelper(allowed map[int][]PortRange) *Helper {
+	return &Helper{portStart: UnprivilegedPortStart(), allowed: allowed}
+}
+
+// validate verifies the helper can forward the port of a request for the user
+// uid. Unprivileged ports are refused, clients bind them themselves.
+func (h *Helper) validate(uid int, req *Request) error {
+	if req.Protocol != "tcp" {
+		return errors.Errorf("only TCP ports can be forwarded, not %q", req.Protocol)
+	}
+	if req.HostPort <= 0 || req.HostPort >= h.portStart {
+		return errors.Errorf("port %d is not a privileged port", req.HostPort)
+	}
+	if req.TargetPort < h.portStart || req.TargetPort > 65535 {
+		return errors.Errorf("target port %d is not an unprivileged port", req.TargetPort)
+	}
+	if req.HostIP != "" && net.ParseIP(req.HostIP) == nil {
+		return errors.Errorf("invalid address %q", req.HostIP)
+	}
+	for _, r := range h.allowed[uid] {
+		if r.contains(req) {
+			return nil
+		}
+	}
+	return errors.Errorf("user %d is not allowed to bind %s port %d", uid, req.Protocol, req.HostPort)
+}
+
+// listen binds the port of a request for the user uid
+func (h *Helper) listen(uid int, req *Request) (net.Listener, error) {
+	if err := h.validate(uid, req); err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("tcp4", net.JoinHostPort(req.HostIP, strconv.Itoa(int(req.HostPort))))
+	return l, errors.Wrapf(err, "cannot listen on the TCP port")
+}
+
+// handle serves the request of a client, forwarding its port until it closes
+// the connection
+func (h *Helper) handle(conn *net.UnixConn) {
+	defer conn.Close()
+
+	uid, err := peerUID(conn)
+	if err != nil {
+		logrus.Errorf("Cannot identify bind helper client: %v", err)
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		h.reply(conn, errors.Wrapf(err, "cannot parse request"))
+		return
+	}
+	l, err := h.listen(uid, &req)
+	if err != nil {
+		logrus.Warnf("Cannot forward %s port %d for user %d: %v", req.Protocol, req.HostPort, uid, err)
+		h.reply(conn, err)
+		return
+	}
+	defer l.Close()
+	if err := h.reply(conn, nil); err != nil {
+		return
+	}
+
+	logrus.Infof("Forwarding %s port %d to port %d for user %d", req.Protocol, req.HostPort, req.TargetPort, uid)
+	go forward(l, uid, req.TargetPort)
+	// The client sends nothing more, it closes the connection once the
+	// port is no longer used
+	io.Copy(ioutil.Discard, conn)
+	logrus.Infof("Stopped forwarding %s port %d for user %d", req.Protocol, req.HostPort, uid)
+}
+
+// reply sends the response to a request
+func (h *Helper) reply(conn *net.UnixConn, err error) error {
+	var resp response
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		logrus.Errorf("Cannot send bind helper response: %v", err)
+		return err
+	}
+	return nil
+}
+
+// forward forwards the connections accepted by l to the target port of the
+// loopback address, as long as the user uid listens on it, until l is closed
+func forward(l net.Listener, uid int, targetPort int32) {
+	target := net.JoinHostPort("127.0.0.1", strconv.Itoa(int(targetPort)))
+	for {
+		client, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer client.Close()
+			// The port may have been released and bound by another
+			// user since the request
+			if owner, err := loopbackListenerUID(targetPort); err != nil || owner != uid {
+				logrus.Warnf("Not forwarding connection to port %d, which user %d does not listen on", targetPort, uid)
+				return
+			}
+			server, err := net.Dial("tcp4", target)
+			if err != nil {
+				logrus.Debugf("Cannot forward connection to port %d: %v", targetPort, err)
+				return
+			}
+			defer server.Close()
+			proxy(client, server)
+		}()
+	}
+}
+
+// proxy copies the data of two connections to each other until both are
+// done
+func proxy(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	copyConn := func(dst, src net.Conn) {
+		io.Copy(dst, src)
+		if tcp, ok := dst.(*net.TCPConn); ok {
+			tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}
+	go copyConn(a, b)
+	go copyConn(b, a)
+	<-done
+	<-done
+}
+
+// parseListenerUID returns the user of the socket listening on a port of the
+// loopback address in a table of TCP sockets in the format of /proc/net/tcp
+func parseListenerUID(r io.Reader, port int32) (int, error) {
+	scanner := bufio.NewScanner(r)
+	// The first line is the header of the table
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// Listening sockets are in the state 0A
+		if len(fields) < 8 || fields[3] != "0A" {
+			continue
+		}
+		split := strings.SplitN(fields[1], ":", 2)
+		// The address is in the byte order of the host
+		if len(split) != 2 || (split[0] != "0100007F" && split[0] != "7F000001") {
+			continue
+		}
+		if p, err := strconv.ParseUint(split[1], 16, 16); err != nil || int32(p) != port {
+			continue
+		}
+		return strconv.Atoi(fields[7])
+	}
+	if err := scanner.Err(); err != nil {
+		return -1, err
+	}
+	return -1, errors.Errorf("no socket listens on port %d of the loopback address", port)
+}
+
+// Serve serves the requests of the clients connecting to the socket at
+// socketPath until the context is cancelled. The socket can only be used by
+// the members of the group gid.
+func (h *Helper) Serve(ctx context.Context, socketPath string, gid int) error {
+	if gid < 0 {
+		return errors.Errorf("the group allowed to use the bind helper is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0755); err != nil {
+		return errors.Wrapf(err, "cannot create directory for %s", socketPath)
+	}
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "cannot remove stale socket %s", socketPath)
+	}
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return errors.Wrapf(err, "cannot listen on %s", socketPath)
+	}
+	defer l.Close()
+
+	if err := os.Chown(socketPath, 0, gid); err != nil {
+		return errors.Wrapf(err, "cannot set the group of %s", socketPath)
+	}
+	if err := os.Chmod(socketPath, 0660); err != nil {
+		return errors.Wrapf(err, "cannot set the permissions of %s", socketPath)
+	}
+
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+				return errors.Wrapf(err, "cannot accept connection on %s", socketPath)
+			}
+		}
+		go h.handle(conn.(*net.UnixConn))
+	}
+}
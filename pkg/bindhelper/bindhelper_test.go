@@ -0,0 +1,149 @@
+package bindhelper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	h := &Helper{portStart: 1024, allowed: map[int][]PortRange{
+		1000: {{Start: 1, End: 1023}},
+		1001: {{Protocol: "tcp", Start: 53, End: 53}},
+	}}
+	assert.NoError(t, h.validate(1000, &Request{Protocol: "tcp", HostPort: 80, TargetPort: 8080}))
+	assert.NoError(t, h.validate(1000, &Request{Protocol: "tcp", HostIP: "127.0.0.1", HostPort: 53, TargetPort: 1024}))
+	assert.Error(t, h.validate(1000, &Request{Protocol: "udp", HostPort: 53, TargetPort: 8053}))
+	assert.Error(t, h.validate(1000, &Request{Protocol: "sctp", HostPort: 80, TargetPort: 8080}))
+	assert.Error(t, h.validate(1000, &Request{Protocol: "tcp", HostPort: 8080, TargetPort: 8080}))
+	assert.Error(t, h.validate(1000, &Request{Protocol: "tcp", HostPort: 0, TargetPort: 8080}))
+	assert.Error(t, h.validate(1000, &Request{Protocol: "tcp", HostIP: "localhost", HostPort: 80, TargetPort: 8080}))
+	// Connections are only forwarded to unprivileged ports
+	assert.Error(t, h.validate(1000, &Request{Protocol: "tcp", HostPort: 80, TargetPort: 22}))
+	assert.Error(t, h.validate(1000, &Request{Protocol: "tcp", HostPort: 80, TargetPort: 70000}))
+
+	// Only the ports allowed to the user are forwarded
+	assert.NoError(t, h.validate(1001, &Request{Protocol: "tcp", HostPort: 53, TargetPort: 8053}))
+	assert.Error(t, h.validate(1001, &Request{Protocol: "tcp", HostPort: 22, TargetPort: 8022}))
+	assert.Error(t, h.validate(1002, &Request{Protocol: "tcp", HostPort: 80, TargetPort: 8080}))
+}
+
+func TestParseListenerUID(t *testing.T) {
+	table := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 21411 1 0000000000000000 100 0 0 10 0
+   1: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 18213 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:1F91 0100007F:9C40 01 00000000:00000000 00:00000000 00000000  1001        0 21412 1 0000000000000000 20 4 30 10 -1
+`
+	uid, err := parseListenerUID(strings.NewReader(table), 8080)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, uid)
+	// Only listening sockets of the loopback address are considered
+	_, err = parseListenerUID(strings.NewReader(table), 22)
+	assert.Error(t, err)
+	_, err = parseListenerUID(strings.NewReader(table), 8081)
+	assert.Error(t, err)
+}
+
+func TestParsePortRanges(t *testing.T) {
+	ranges, err := ParsePortRanges("80,443/tcp,53/udp,8000-8080")
+	require.NoError(t, err)
+	assert.Equal(t, []PortRange{
+		{Start: 80, End: 80},
+		{Protocol: "tcp", Start: 443, End: 443},
+		{Protocol: "udp", Start: 53, End: 53},
+		{Start: 8000, End: 8080},
+	}, ranges)
+
+	for _, spec := range []string{"", "0", "http", "80/sctp", "90-80", "1-70000", "80,"} {
+		_, err := ParsePortRanges(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// freePort returns a free TCP port of the loopback address
+func freePort(t *testing.T) int32 {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return int32(l.Addr().(*net.TCPAddr).Port)
+}
+
+func TestForward(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindhelper")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "bind-helper.sock")
+
+	// Let the helper bind a free port as if it was privileged, and forward
+	// it to a higher one echoing what it receives
+	port, targetPort := freePort(t), freePort(t)
+	if port > targetPort {
+		port, targetPort = targetPort, port
+	}
+	target, err := net.Listen("tcp4", fmt.Sprintf("127.0.0.1:%d", targetPort))
+	require.NoError(t, err)
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := &Helper{portStart: targetPort, allowed: map[int][]PortRange{
+		os.Getuid(): {{Protocol: "tcp", Start: port, End: port}},
+	}}
+	go h.Serve(ctx, socketPath, os.Getgid())
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(socketPath); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	f, err := Forward(socketPath, &Request{Protocol: "tcp", HostIP: "127.0.0.1", HostPort: port, TargetPort: targetPort})
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp4", fmt.Sprintf("127.0.0.1:%d", port))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+	conn.Close()
+
+	// The port is released once the connection to the helper is closed
+	f.Close()
+	for i := 0; i < 100; i++ {
+		if conn, err = net.Dial("tcp4", fmt.Sprintf("127.0.0.1:%d", port)); err != nil {
+			break
+		}
+		conn.Close()
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Error(t, err)
+
+	// Ports not allowed to the user are refused
+	_, err = Forward(socketPath, &Request{Protocol: "tcp", HostIP: "127.0.0.1", HostPort: port - 1, TargetPort: targetPort})
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+// +build linux
+
+package bindhelper
+
+import (
+	"net"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerUID returns the user of the process at the other end of the connection
+func peerUID(conn *net.UnixConn) (int, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return -1, err
+	}
+	var cred *unix.Ucred
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return -1, err
+	}
+	if credErr != nil {
+		return -1, credErr
+	}
+	return int(cred.Uid), nil
+}
+
+// loopbackListenerUID returns the user of the socket listening on a TCP port
+// of the loopback address
+func loopbackListenerUID(port int32) (int, error) {
+	f, err := os.Open("/proc/net/tcp")
+	if err != nil {
+		return -1, err
+	}
+	defer f.Close()
+	return parseListenerUID(f, port)
+}
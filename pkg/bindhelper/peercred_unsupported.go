@@ -0,0 +1,19 @@
+// +build !linux
+
+package bindhelper
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// peerUID is not supported on this OS
+func peerUID(conn *net.UnixConn) (int, error) {
+	return -1, errors.New("the bind helper is not supported on this OS")
+}
+
+// loopbackListenerUID is not supported on this OS
+func loopbackListenerUID(port int32) (int, error) {
+	return -1, errors.New("the bind helper is not supported on this OS")
+}
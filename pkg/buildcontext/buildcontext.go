@@ -0,0 +1,229 @@
+// Package buildcontext assembles the context directories of builds, from
+// local directories, git repositories, remote tarballs or the archives API
+// clients send, without the files their ignore file excludes.
+package buildcontext
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/storage/pkg/archive"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// IgnoreFiles are the files listing the patterns of the files excluded from
+// a build context, in order of preference
+var IgnoreFiles = []string{".containerignore", ".dockerignore"}
+
+// Context is the context directory of a build
+type Context struct {
+	// Dir is the context directory given to the builder
+	Dir string
+	// tempDir holds the context when it was fetched or assembled, and is
+	// removed by Cleanup
+	tempDir string
+}
+
+// Cleanup removes the directory of the context if it was fetched or
+// assembled
+func (c *Context) Cleanup() error {
+	if c.tempDir == "" {
+		return nil
+	}
+	if err := os.RemoveAll(c.tempDir); err != nil {
+		return errors.Wrapf(err, "error removing build context %s", c.tempDir)
+	}
+	return nil
+}
+
+// New returns the build context of source, a local directory, a git
+// repository, optionally followed by #REF:SUBDIRECTORY, or the URL of a
+// tarball or of a Dockerfile. The files excluded by the ignore file of the
+// context are left out, except the dockerfiles, given relative to the
+// context.
+func New(source string, dockerfiles []string) (*Context, error) {
+	if !IsGitURL(source) && !IsURL(source) {
+		dir, err := filepath.Abs(source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error determining path to directory %q", source)
+		}
+		return assemble(dir, dockerfiles)
+	}
+
+	tempDir, err := ioutil.TempDir("", "podman-build-context")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating temporary directory for %q", source)
+	}
+	c := &Context{Dir: tempDir, tempDir: tempDir}
+	if IsGitURL(source) {
+		c.Dir, err = fetchGit(source, tempDir)
+	} else {
+		err = fetchURL(source, tempDir)
+	}
+	if err == nil {
+		err = removeIgnored(c.Dir, dockerfiles)
+	}
+	if err != nil {
+		if err2 := c.Cleanup(); err2 != nil {
+			logrus.Debugf("%v", err2)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// FromArchive returns the build context in the tar archive r, possibly
+// compressed, as the clients of the API send it
+func FromArchive(r io.Reader, dockerfiles []string) (*Context, error) {
+	tempDir, err := ioutil.TempDir("", "podman-build-context")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating temporary directory for build context")
+	}
+	c := &Context{Dir: tempDir, tempDir: tempDir}
+	err = archive.Untar(r, tempDir, &archive.TarOptions{NoLchown: true})
+	if err != nil {
+		err = errors.Wrapf(err, "error extracting build context")
+	} else {
+		err = removeIgnored(c.Dir, dockerfiles)
+	}
+	if err != nil {
+		if err2 := c.Cleanup(); err2 != nil {
+			logrus.Debugf("%v", err2)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// ReadIgnoreFile returns the patterns of the ignore file of the context
+// directory, and the name of the file, if it has one
+func ReadIgnoreFile(dir string) ([]string, string, error) {
+	for _, name := range IgnoreFiles {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, "", errors.Wrapf(err, "error opening %s", name)
+		}
+		defer f.Close()
+		patterns, err := parseIgnore(f)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "error reading %s", name)
+		}
+		return patterns, name, nil
+	}
+	return nil, "", nil
+}
+
+// parseIgnore parses the patterns of an ignore file, one per line, skipping
+// comments and blank lines
+func parseIgnore(r io.Reader) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		exclusion := strings.HasPrefix(pattern, "!")
+		if exclusion {
+			pattern = strings.TrimSpace(pattern[1:])
+		}
+		pattern = filepath.Clean(pattern)
+		pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "/")
+		if pattern == "" || pattern == "." {
+			continue
+		}
+		if exclusion {
+			pattern = "!" + pattern
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, scanner.Err()
+}
+
+// excludePatterns returns the patterns excluding files from the context
+// directory, keeping its ignore file and the dockerfiles in it
+func excludePatterns(dir string, dockerfiles []string) ([]string, error) {
+	patterns, name, err := ReadIgnoreFile(dir)
+	if err != nil || len(patterns) == 0 {
+		return nil, err
+	}
+	patterns = append(patterns, "!"+name)
+	for _, dockerfile := range dockerfiles {
+		if filepath.IsAbs(dockerfile) || IsURL(dockerfile) {
+			continue
+		}
+		patterns = append(patterns, "!"+filepath.ToSlash(filepath.Clean(dockerfile)))
+	}
+	return patterns, nil
+}
+
+// assemble returns the context of a local directory. When its ignore file
+// excludes files, the other files are copied to a temporary directory, so
+// the directory is left untouched.
+func assemble(dir string, dockerfiles []string) (*Context, error) {
+	patterns, err := excludePatterns(dir, dockerfiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		return &Context{Dir: dir}, nil
+	}
+
+	tempDir, err := ioutil.TempDir("", "podman-build-context")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating temporary directory for %q", dir)
+	}
+	c := &Context{Dir: tempDir, tempDir: tempDir}
+	if err := copyContext(dir, tempDir, patterns); err != nil {
+		if err2 := c.Cleanup(); err2 != nil {
+			logrus.Debugf("%v", err2)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// copyContext copies the files of the directory src not excluded by the
+// patterns to dest
+func copyContext(src, dest string, patterns []string) error {
+	tarball, err := archive.TarWithOptions(src, &archive.TarOptions{ExcludePatterns: patterns})
+	if err != nil {
+		return errors.Wrapf(err, "error reading build context %s", src)
+	}
+	defer tarball.Close()
+	if err := archive.Untar(tarball, dest, &archive.TarOptions{NoLchown: true}); err != nil {
+		return errors.Wrapf(err, "error copying build context %s", src)
+	}
+	return nil
+}
+
+// removeIgnored removes the files excluded by the ignore file from a context
+// which was fetched or extracted to a temporary directory, by copying the
+// files kept aside and swapping the directories
+func removeIgnored(dir string, dockerfiles []string) error {
+	patterns, err := excludePatterns(dir, dockerfiles)
+	if err != nil || len(patterns) == 0 {
+		return err
+	}
+	kept := dir + ".kept"
+	if err := os.Mkdir(kept, 0700); err != nil {
+		return errors.Wrapf(err, "error creating directory %s", kept)
+	}
+	if err := copyContext(dir, kept, patterns); err != nil {
+		os.RemoveAll(kept)
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		os.RemoveAll(kept)
+		return errors.Wrapf(err, "error removing ignored files of %s", dir)
+	}
+	return os.Rename(kept, dir)
+}
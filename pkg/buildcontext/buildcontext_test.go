@@ -0,0 +1,177 @@
+package buildcontext
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/containers/storage/pkg/archive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles creates the files of the map, by path relative to dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// listFiles returns the regular files of dir, relative to it
+func listFiles(t *testing.T, dir string) []string {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		files = append(files, rel)
+		return err
+	})
+	require.NoError(t, err)
+	sort.Strings(files)
+	return files
+}
+
+func TestIsGitURL(t *testing.T) {
+	assert.True(t, IsGitURL("git://example.com/repo"))
+	assert.True(t, IsGitURL("git@github.com:user/repo.git"))
+	assert.True(t, IsGitURL("github.com/user/repo"))
+	assert.True(t, IsGitURL("https://example.com/repo.git"))
+	assert.True(t, IsGitURL("https://example.com/repo.git#v1.0:docker"))
+	assert.False(t, IsGitURL("https://example.com/context.tar.gz"))
+	assert.False(t, IsGitURL("./repo.git"))
+}
+
+func TestParseGitURL(t *testing.T) {
+	for _, tc := range []struct {
+		source, repo, ref, subdir string
+	}{
+		{"git://example.com/repo", "git://example.com/repo", "", ""},
+		{"https://example.com/repo.git#v1.0", "https://example.com/repo.git", "v1.0", ""},
+		{"https://example.com/repo.git#v1.0:docker/app", "https://example.com/repo.git", "v1.0", "docker/app"},
+		{"https://example.com/repo.git#:docker", "https://example.com/repo.git", "", "docker"},
+		{"git@github.com:user/repo.git#main", "git@github.com:user/repo.git", "main", ""},
+		{"github.com/user/repo#main:app", "https://github.com/user/repo", "main", "app"},
+	} {
+		repo, ref, subdir := ParseGitURL(tc.source)
+		assert.Equal(t, tc.repo, repo, tc.source)
+		assert.Equal(t, tc.ref, ref, tc.source)
+		assert.Equal(t, tc.subdir, subdir, tc.source)
+	}
+}
+
+func TestParseIgnore(t *testing.T) {
+	patterns, err := parseIgnore(strings.NewReader(`
+# comment
+*.log
+  /build/
+!build/keep
+./docs/../secret
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.log", "build", "!build/keep", "secret"}, patterns)
+}
+
+func TestNewLocal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildcontext-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Without an ignore file, the directory is used as is
+	writeFiles(t, dir, map[string]string{
+		"Dockerfile":            "FROM scratch",
+		"app/main.go":           "package main",
+		"app/debug.log":         "log",
+		"build/out":             "out",
+		"build/keep":            "keep",
+		"docker/Dockerfile.dev": "FROM scratch",
+	})
+	c, err := New(dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, dir, c.Dir)
+	require.NoError(t, c.Cleanup())
+
+	// .containerignore takes precedence over .dockerignore
+	writeFiles(t, dir, map[string]string{
+		".dockerignore":    "app",
+		".containerignore": "**/*.log\nbuild\n!build/keep\ndocker\n",
+	})
+	c, err = New(dir, []string{"docker/Dockerfile.dev"})
+	require.NoError(t, err)
+	assert.NotEqual(t, dir, c.Dir)
+	assert.Equal(t, []string{".containerignore", ".dockerignore", "Dockerfile", "app/main.go", "build/keep", "docker/Dockerfile.dev"}, listFiles(t, c.Dir))
+	require.NoError(t, c.Cleanup())
+	_, err = os.Stat(c.Dir)
+	assert.True(t, os.IsNotExist(err))
+
+	// The directory itself is left untouched
+	assert.Len(t, listFiles(t, dir), 8)
+}
+
+func TestFromArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildcontext-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"Dockerfile":    "FROM scratch",
+		".dockerignore": "*.secret\nDockerfile\n",
+		"key.secret":    "secret",
+		"main.go":       "package main",
+	})
+	tarball, err := archive.Tar(dir, archive.Gzip)
+	require.NoError(t, err)
+	defer tarball.Close()
+
+	c, err := FromArchive(tarball, []string{"Dockerfile"})
+	require.NoError(t, err)
+	defer c.Cleanup()
+	assert.Equal(t, []string{".dockerignore", "Dockerfile", "main.go"}, listFiles(t, c.Dir))
+}
+
+func TestNewURL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildcontext-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"Dockerfile": "FROM scratch",
+		"main.go":    "package main",
+	})
+	tarball, err := archive.Tar(dir, archive.Gzip)
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(tarball)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/context.tar.gz":
+			w.Write(content)
+		case "/Dockerfile":
+			w.Write([]byte("FROM scratch\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL+"/context.tar.gz", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Dockerfile", "main.go"}, listFiles(t, c.Dir))
+	require.NoError(t, c.Cleanup())
+
+	// A URL which is not a tarball is the Dockerfile
+	c, err = New(server.URL+"/Dockerfile", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Dockerfile"}, listFiles(t, c.Dir))
+	require.NoError(t, c.Cleanup())
+
+	_, err = New(server.URL+"/missing", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package buildcontext
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/storage/pkg/archive"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// IsURL returns true if source is an HTTP or HTTPS URL
+func IsURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// IsGitURL returns true if source names a git repository: a git:// or git@
+// URL, a github.com/ path, or an HTTP URL of a path ending with .git
+func IsGitURL(source string) bool {
+	if strings.HasPrefix(source, "git://") || strings.HasPrefix(source, "git@") || strings.HasPrefix(source, "github.com/") {
+		return true
+	}
+	if !IsURL(source) {
+		return false
+	}
+	repo := strings.SplitN(source, "#", 2)[0]
+	return strings.HasSuffix(repo, ".git")
+}
+
+// ParseGitURL splits a git build context, REPOSITORY#REF:SUBDIRECTORY, in
+// the URL of the repository, the reference to check out and the
+// subdirectory of the repository used as context
+func ParseGitURL(source string) (repo, ref, subdir string) {
+	parts := strings.SplitN(source, "#", 2)
+	repo = parts[0]
+	if strings.HasPrefix(repo, "github.com/") {
+		repo = "https://" + repo
+	}
+	if len(parts) == 2 {
+		fragment := strings.SplitN(parts[1], ":", 2)
+		ref = fragment[0]
+		if len(fragment) == 2 {
+			subdir = fragment[1]
+		}
+	}
+	return repo, ref, subdir
+}
+
+// git runs git with the arguments
+func git(args ...string) error {
+	logrus.Debugf("running git %s", strings.Join(args, " "))
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "error running git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// fetchGit clones the repository of a git build context to dir, and returns
+// the directory of the context in it
+func fetchGit(source, dir string) (string, error) {
+	repo, ref, subdir := ParseGitURL(source)
+	if ref == "" {
+		if err := git("clone", "--recurse-submodules", "--depth", "1", repo, dir); err != nil {
+			return "", err
+		}
+	} else {
+		// The reference can be a commit, which can not be cloned directly
+		if err := git("clone", "--no-checkout", repo, dir); err != nil {
+			return "", err
+		}
+		if err := git("-C", dir, "checkout", "--quiet", ref); err != nil {
+			return "", err
+		}
+		if err := git("-C", dir, "submodule", "update", "--init", "--recursive"); err != nil {
+			return "", err
+		}
+	}
+	if subdir == "" {
+		return dir, nil
+	}
+
+	// The subdirectory must not lead out of the repository
+	contextDir, err := filepath.EvalSymlinks(filepath.Join(dir, filepath.Clean("/"+subdir)))
+	if err != nil {
+		return "", errors.Wrapf(err, "error finding directory %q in %s", subdir, repo)
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	if contextDir != root && !strings.HasPrefix(contextDir, root+string(filepath.Separator)) {
+		return "", errors.Errorf("directory %q is not in repository %s", subdir, repo)
+	}
+	if info, err := os.Stat(contextDir); err != nil || !info.IsDir() {
+		return "", errors.Errorf("%q is not a directory of repository %s", subdir, repo)
+	}
+	return contextDir, nil
+}
+
+// fetchURL downloads a build context to dir. A tarball, possibly
+// compressed, is extracted, anything else is taken as the Dockerfile.
+func fetchURL(url, dir string) error {
+	logrus.Debugf("downloading build context %q", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return errors.Wrapf(err, "error getting %q", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("error getting %q: %s", url, resp.Status)
+	}
+
+	download, err := ioutil.TempFile("", "podman-build-context")
+	if err != nil {
+		return errors.Wrapf(err, "error creating temporary file for %q", url)
+	}
+	defer os.Remove(download.Name())
+	defer download.Close()
+	if _, err := io.Copy(download, resp.Body); err != nil {
+		return errors.Wrapf(err, "error downloading %q", url)
+	}
+
+	if !archive.IsArchivePath(download.Name()) {
+		download.Close()
+		if err := os.Rename(download.Name(), filepath.Join(dir, "Dockerfile")); err != nil {
+			return errors.Wrapf(err, "error saving Dockerfile %q", url)
+		}
+		return os.Chmod(filepath.Join(dir, "Dockerfile"), 0644)
+	}
+	if _, err := download.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := archive.Untar(download, dir, &archive.TarOptions{NoLchown: true}); err != nil {
+		return errors.Wrapf(err, "error extracting %q", url)
+	}
+	return nil
+}
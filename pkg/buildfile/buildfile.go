@@ -0,0 +1,64 @@
+package buildfile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/builder/dockerfile/parser"
+	"github.com/openshift/imagebuilder"
+	"github.com/pkg/errors"
+)
+
+// Read reads the Containerfiles of a build and expands their heredocs. Their
+// paths are resolved as builds do, relative to the context directory when
+// they are not found. Containerfiles given by URL, or preprocessed because of
+// their .in suffix, are not read, and Read returns nil if any is.
+func Read(contextDir string, paths []string) ([][]byte, error) {
+	for _, path := range paths {
+		if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") || strings.HasSuffix(path, ".in") {
+			return nil, nil
+		}
+	}
+	var files [][]byte
+	for _, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			path = filepath.Join(contextDir, path)
+		}
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading %q", path)
+		}
+		if len(contents) == 0 {
+			return nil, errors.Errorf("no contents in %q", path)
+		}
+		expanded, err := ExpandHeredocs(contents)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error in %q", path)
+		}
+		files = append(files, expanded)
+	}
+	return files, nil
+}
+
+// Parse parses Containerfiles, the instructions of the additional ones
+// following those of the first, as builds do
+func Parse(files [][]byte) (*parser.Node, error) {
+	if len(files) == 0 {
+		return nil, errors.Errorf("no Containerfiles")
+	}
+	node, err := imagebuilder.ParseDockerfile(bytes.NewReader(files[0]))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing main Dockerfile")
+	}
+	for _, file := range files[1:] {
+		additional, err := imagebuilder.ParseDockerfile(bytes.NewReader(file))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing additional Dockerfile")
+		}
+		node.Children = append(node.Children, additional.Children...)
+	}
+	return node, nil
+}
@@ -0,0 +1,186 @@
+// Package buildfile supports the instructions of Containerfiles which the
+// parser of builds does not: heredocs, expanded into JSON-form RUN
+// instructions, and the mounts of RUN instructions, built alone with their
+// mounts by splitting the Containerfile into segments.
+package buildfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// heredocRegexp matches the heredoc markers of an instruction, <<WORD or
+	// <<-WORD, the word being quoted or not
+	heredocRegexp = regexp.MustCompile(`<<(-?)("[^"\s]+"|'[^'\s]+'|[A-Za-z0-9_]+)`)
+	// escapeRegexp matches the parser directive setting the escape character
+	escapeRegexp = regexp.MustCompile(`^#\s*escape\s*=\s*(\S)\s*$`)
+)
+
+// heredoc is a heredoc of an instruction
+type heredoc struct {
+	// marker is the marker of the heredoc in the instruction
+	marker string
+	// delimiter is the word ending the heredoc, unquoted
+	delimiter string
+	// stripTabs is set for <<- heredocs, whose leading tabs are stripped
+	stripTabs bool
+	// lines are the lines of the heredoc, terminator included
+	lines []string
+}
+
+// body returns the contents of the heredoc
+func (h *heredoc) body() string {
+	var body string
+	for _, line := range h.lines[:len(h.lines)-1] {
+		if h.stripTabs {
+			line = strings.TrimLeft(line, "\t")
+		}
+		body += line + "\n"
+	}
+	return body
+}
+
+// ExpandHeredocs returns the contents of a Containerfile with the heredocs of
+// its RUN instructions expanded into JSON-form RUN instructions running
+// /bin/sh. A RUN instruction made of a single heredoc runs it as a script,
+// with the interpreter of its #! line if it has one, others run their command
+// line with its heredocs. Each instruction is followed by as many blank lines
+// as it took so that the line numbers of errors stay right.
+func ExpandHeredocs(contents []byte) ([]byte, error) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "error reading Containerfile")
+	}
+
+	escape := `\`
+	var out []string
+	expanded := false
+	directives := true
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			if directives {
+				if m := escapeRegexp.FindStringSubmatch(trimmed); m != nil {
+					escape = m[1]
+				} else if trimmed == "" || !strings.Contains(trimmed, "=") {
+					directives = false
+				}
+			}
+			out = append(out, line)
+			continue
+		}
+		directives = false
+
+		// The logical line of the instruction, joined from the lines
+		// its escape character continues
+		start := i
+		instruction := strings.TrimRight(line, " \t")
+		for strings.HasSuffix(instruction, escape) && i+1 < len(lines) {
+			i++
+			next := strings.TrimSpace(lines[i])
+			if strings.HasPrefix(next, "#") {
+				continue
+			}
+			instruction = strings.TrimSuffix(instruction, escape) + strings.TrimRight(lines[i], " \t")
+		}
+		fields := strings.Fields(instruction)
+		keyword := strings.ToUpper(fields[0])
+		command := strings.TrimSpace(instruction[len(fields[0]):])
+		var flags []string
+		for strings.HasPrefix(command, "--") {
+			flag := strings.Fields(command)[0]
+			flags = append(flags, flag)
+			command = strings.TrimSpace(command[len(flag):])
+		}
+
+		heredocs := findHeredocs(command)
+		if len(heredocs) == 0 || strings.HasPrefix(command, "[") {
+			out = append(out, lines[start:i+1]...)
+			continue
+		}
+		if keyword != "RUN" {
+			return nil, errors.Errorf("line %d: heredocs are only supported in RUN instructions", start+1)
+		}
+		for _, h := range heredocs {
+			for {
+				i++
+				if i >= len(lines) {
+					return nil, errors.Errorf("line %d: heredoc %s is not terminated", start+1, h.marker)
+				}
+				h.lines = append(h.lines, lines[i])
+				terminator := lines[i]
+				if h.stripTabs {
+					terminator = strings.TrimLeft(terminator, "\t")
+				}
+				if terminator == h.delimiter {
+					break
+				}
+			}
+		}
+
+		args, err := json.Marshal(heredocCommand(command, heredocs))
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: error encoding RUN instruction", start+1)
+		}
+		run := append([]string{fields[0]}, flags...)
+		out = append(out, strings.Join(append(run, string(args)), " "))
+		for j := start; j < i; j++ {
+			out = append(out, "")
+		}
+		expanded = true
+	}
+	if !expanded {
+		return contents, nil
+	}
+	return []byte(strings.Join(out, "\n") + "\n"), nil
+}
+
+// findHeredocs returns the heredocs of the command of an instruction, in
+// order. <<< here-strings are not heredocs.
+func findHeredocs(command string) []*heredoc {
+	var heredocs []*heredoc
+	for _, m := range heredocRegexp.FindAllStringSubmatchIndex(command, -1) {
+		if m[0] > 0 && command[m[0]-1] == '<' {
+			continue
+		}
+		word := command[m[4]:m[5]]
+		heredocs = append(heredocs, &heredoc{
+			marker:    command[m[0]:m[1]],
+			delimiter: strings.Trim(word, `"'`),
+			stripTabs: m[3] > m[2],
+		})
+	}
+	return heredocs
+}
+
+// heredocCommand returns the arguments of the JSON-form RUN instruction
+// running the command with its heredocs
+func heredocCommand(command string, heredocs []*heredoc) []string {
+	if len(heredocs) == 1 && command == heredocs[0].marker {
+		body := heredocs[0].body()
+		if !strings.HasPrefix(body, "#!") {
+			return []string{"/bin/sh", "-c", body}
+		}
+		// The script is given to its interpreter on its standard input
+		interpreter := strings.TrimSpace(strings.SplitN(body, "\n", 2)[0][2:])
+		delimiter := heredocs[0].delimiter
+		return []string{"/bin/sh", "-c", interpreter + " <<'" + delimiter + "'\n" + body + delimiter + "\n"}
+	}
+	script := command + "\n"
+	for _, h := range heredocs {
+		script += strings.Join(h.lines, "\n") + "\n"
+	}
+	return []string{"/bin/sh", "-c", script}
+}
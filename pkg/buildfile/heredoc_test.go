@@ -0,0 +1,77 @@
+package buildfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandHeredocs(t *testing.T) {
+	for _, tc := range []struct {
+		name, containerfile, expected string
+	}{
+		{
+			"no heredocs",
+			"FROM alpine\nRUN echo hi \\\n  there\n",
+			"FROM alpine\nRUN echo hi \\\n  there\n",
+		},
+		{
+			"script",
+			"FROM alpine\nRUN <<EOF\necho hi\necho there\nEOF\nCMD sh\n",
+			"FROM alpine\nRUN [\"/bin/sh\",\"-c\",\"echo hi\\necho there\\n\"]\n\n\n\nCMD sh\n",
+		},
+		{
+			"script with interpreter",
+			"FROM alpine\nRUN <<EOF\n#!/usr/bin/env python3\nprint('hi')\nEOF\n",
+			"FROM alpine\nRUN [\"/bin/sh\",\"-c\",\"/usr/bin/env python3 \\u003c\\u003c'EOF'\\n#!/usr/bin/env python3\\nprint('hi')\\nEOF\\n\"]\n\n\n\n",
+		},
+		{
+			"command with heredocs",
+			"FROM alpine\nRUN --mount=type=cache,target=/c cat <<A >/a && cat <<-\"B\" >/b\na\nA\n\tb\n\tB\n",
+			"FROM alpine\nRUN --mount=type=cache,target=/c [\"/bin/sh\",\"-c\",\"cat \\u003c\\u003cA \\u003e/a \\u0026\\u0026 cat \\u003c\\u003c-\\\"B\\\" \\u003e/b\\na\\nA\\n\\tb\\n\\tB\\n\"]\n\n\n\n\n",
+		},
+		{
+			"here-string",
+			"FROM alpine\nRUN cat <<<hi\n",
+			"FROM alpine\nRUN cat <<<hi\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expanded, err := ExpandHeredocs([]byte(tc.containerfile))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(expanded))
+			assert.Equal(t, strings.Count(tc.containerfile, "\n"), strings.Count(string(expanded), "\n"))
+		})
+	}
+}
+
+func TestExpandHeredocsTabsStripped(t *testing.T) {
+	expanded, err := ExpandHeredocs([]byte("FROM alpine\nRUN <<-EOF\n\techo hi\n\tEOF\n"))
+	require.NoError(t, err)
+	node, err := Parse([][]byte{expanded})
+	require.NoError(t, err)
+	require.Len(t, node.Children, 2)
+	run := node.Children[1]
+	assert.Equal(t, 2, run.StartLine)
+	var args []string
+	for n := run.Next; n != nil; n = n.Next {
+		args = append(args, n.Value)
+	}
+	assert.Equal(t, []string{"/bin/sh", "-c", "echo hi\n"}, args)
+}
+
+func TestExpandHeredocsErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, containerfile, err string
+	}{
+		{"unterminated", "FROM alpine\nRUN <<EOF\necho hi\n", "line 2: heredoc <<EOF is not terminated"},
+		{"copy", "FROM alpine\nCOPY <<EOF /a\nhi\nEOF\n", "line 2: heredocs are only supported in RUN instructions"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ExpandHeredocs([]byte(tc.containerfile))
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package buildfile
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MountTypeBind mounts a file or directory of the build context
+	MountTypeBind = "bind"
+	// MountTypeCache mounts a directory kept from build to build
+	MountTypeCache = "cache"
+	// MountTypeTmpfs mounts an empty directory
+	MountTypeTmpfs = "tmpfs"
+	// MountTypeSecret mounts a secret given to the build
+	MountTypeSecret = "secret"
+)
+
+// Mount is a mount of a RUN instruction, given with --mount
+type Mount struct {
+	// Type is the type of the mount, bind by default
+	Type string
+	// Target is the path of the mount in the build container
+	Target string
+	// Source is the path in the build context of bind mounts
+	Source string
+	// ID identifies the directory of cache mounts, their target by
+	// default, and the secret of secret mounts
+	ID string
+	// ReadOnly is set for read-only mounts. Bind and secret mounts always
+	// are.
+	ReadOnly bool
+	// Required makes builds fail when the secret of a secret mount is not
+	// given
+	Required bool
+	// Size is the size of tmpfs mounts, which is not enforced
+	Size int64
+}
+
+// ParseMount parses the value of a --mount flag, comma-separated key=value
+// options
+func ParseMount(value string) (*Mount, error) {
+	mount := &Mount{Type: MountTypeBind}
+	var readWrite bool
+	for _, option := range strings.Split(value, ",") {
+		kv := strings.SplitN(option, "=", 2)
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		val := ""
+		if len(kv) == 2 {
+			val = kv[1]
+		}
+		var err error
+		switch key {
+		case "type":
+			mount.Type = val
+		case "target", "dst", "destination":
+			mount.Target = val
+		case "source", "src":
+			mount.Source = val
+		case "id":
+			mount.ID = val
+		case "ro", "readonly":
+			mount.ReadOnly, err = parseMountBool(key, val, len(kv) == 2)
+		case "rw", "readwrite":
+			readWrite, err = parseMountBool(key, val, len(kv) == 2)
+		case "required":
+			mount.Required, err = parseMountBool(key, val, len(kv) == 2)
+		case "sharing":
+			if val != "shared" && val != "private" && val != "locked" {
+				err = errors.Errorf("invalid sharing %q, must be shared, private or locked", val)
+			}
+		case "size":
+			mount.Size, err = units.RAMInBytes(val)
+		case "from", "mode", "uid", "gid":
+			err = errors.Errorf("option %s is not supported", key)
+		default:
+			err = errors.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid mount %q", value)
+		}
+	}
+
+	switch mount.Type {
+	case MountTypeBind:
+		if readWrite {
+			return nil, errors.Errorf("invalid mount %q: bind mounts are read-only", value)
+		}
+		mount.ReadOnly = true
+		if mount.Source == "" {
+			mount.Source = "."
+		}
+	case MountTypeCache:
+		if readWrite {
+			mount.ReadOnly = false
+		}
+		if mount.ID == "" {
+			mount.ID = mount.Target
+		}
+	case MountTypeTmpfs:
+	case MountTypeSecret:
+		mount.ReadOnly = true
+		if mount.ID == "" && mount.Target != "" {
+			mount.ID = path.Base(mount.Target)
+		}
+		if mount.ID == "" {
+			return nil, errors.Errorf("invalid mount %q: secret mounts need an id or a target", value)
+		}
+		if mount.Target == "" {
+			mount.Target = path.Join("/run/secrets", mount.ID)
+		}
+	default:
+		return nil, errors.Errorf("invalid mount %q: unknown type %q, must be bind, cache, tmpfs or secret", value, mount.Type)
+	}
+	if mount.Target == "" {
+		return nil, errors.Errorf("invalid mount %q: no target", value)
+	}
+	if !path.IsAbs(mount.Target) {
+		return nil, errors.Errorf("invalid mount %q: target %q is not an absolute path", value, mount.Target)
+	}
+	if mount.Source != "" && mount.Type != MountTypeBind {
+		return nil, errors.Errorf("invalid mount %q: only bind mounts have a source", value)
+	}
+	if mount.Size != 0 && mount.Type != MountTypeTmpfs {
+		return nil, errors.Errorf("invalid mount %q: only tmpfs mounts have a size", value)
+	}
+	return mount, nil
+}
+
+// parseMountBool parses a boolean option of a mount, true when it has no
+// value
+func parseMountBool(key, value string, hasValue bool) (bool, error) {
+	if !hasValue {
+		return true, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Errorf("invalid value %q of %s", value, key)
+	}
+	return b, nil
+}
@@ -0,0 +1,51 @@
+package buildfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMount(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected Mount
+	}{
+		{"type=cache,target=/root/.cache", Mount{Type: MountTypeCache, Target: "/root/.cache", ID: "/root/.cache"}},
+		{"type=cache,id=go,dst=/go/pkg,sharing=locked,ro", Mount{Type: MountTypeCache, Target: "/go/pkg", ID: "go", ReadOnly: true}},
+		{"target=/src", Mount{Type: MountTypeBind, Target: "/src", Source: ".", ReadOnly: true}},
+		{"type=bind,source=go.mod,target=/src/go.mod,readonly=false", Mount{Type: MountTypeBind, Target: "/src/go.mod", Source: "go.mod", ReadOnly: true}},
+		{"type=tmpfs,target=/tmp,size=64m", Mount{Type: MountTypeTmpfs, Target: "/tmp", Size: 64 * 1024 * 1024}},
+		{"type=secret,id=token", Mount{Type: MountTypeSecret, Target: "/run/secrets/token", ID: "token", ReadOnly: true}},
+		{"type=secret,target=/root/.netrc,required", Mount{Type: MountTypeSecret, Target: "/root/.netrc", ID: ".netrc", ReadOnly: true, Required: true}},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			mount, err := ParseMount(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, *mount)
+		})
+	}
+}
+
+func TestParseMountErrors(t *testing.T) {
+	for _, value := range []string{
+		"type=cache",
+		"type=cache,target=relative",
+		"type=volume,target=/v",
+		"type=bind,target=/src,rw",
+		"type=bind,target=/src,from=builder",
+		"type=cache,target=/c,uid=1000",
+		"type=cache,target=/c,source=dir",
+		"type=cache,target=/c,sharing=some",
+		"type=cache,target=/c,size=1m",
+		"type=secret",
+		"type=secret,id=a,required=maybe",
+		"type=tmpfs,target=/t,color=blue",
+	} {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParseMount(value)
+			assert.Error(t, err)
+		})
+	}
+}
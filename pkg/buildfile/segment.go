@@ -0,0 +1,214 @@
+package buildfile
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/builder/dockerfile/parser"
+	"github.com/pkg/errors"
+)
+
+// Segment is a part of a stage of a Containerfile built on its own. RUN
+// instructions with mounts are segments of their own, built with their
+// mounts, the segments following them are based on their image.
+type Segment struct {
+	// Stage is the index of the stage of the segment
+	Stage int
+	// Mounts are the mounts of the RUN instruction of the segment
+	Mounts []*Mount
+	// first is set for the first segment of a stage, based on the image of
+	// its FROM instruction
+	first bool
+	// from is the base image of the first segment of a stage, as given
+	from string
+	// fromStage is the index of the stage the first segment of a stage is
+	// based on, or -1 if it is based on an image
+	fromStage int
+	// args are the ARG instructions of the stage before the segment
+	args []string
+	// instructions are the instructions of the segment, but FROM
+	instructions []*parser.Node
+}
+
+// Plan is a Containerfile split into segments
+type Plan struct {
+	// Segments are the segments of the stages, in order
+	Segments []*Segment
+	// args are the ARG instructions before the first stage
+	args []string
+	// stages are the indexes of the named stages
+	stages map[string]int
+	// mounts is set if RUN instructions have mounts
+	mounts bool
+}
+
+// NewPlan splits a parsed Containerfile into segments
+func NewPlan(node *parser.Node) (*Plan, error) {
+	p := &Plan{stages: make(map[string]int)}
+	var (
+		current   *Segment
+		stageArgs []string
+		onBuild   bool
+	)
+	for _, child := range node.Children {
+		if child.Value == "from" {
+			if child.Next == nil {
+				return nil, errors.Errorf("line %d: FROM has no image", child.StartLine)
+			}
+			stage := 0
+			if current != nil {
+				stage = current.Stage + 1
+			}
+			current = &Segment{
+				Stage:     stage,
+				first:     true,
+				from:      child.Next.Value,
+				fromStage: -1,
+			}
+			if i, ok := p.stages[strings.ToLower(current.from)]; ok {
+				current.fromStage = i
+			}
+			if as := child.Next.Next; as != nil && strings.EqualFold(as.Value, "as") && as.Next != nil {
+				p.stages[strings.ToLower(as.Next.Value)] = stage
+			}
+			p.Segments = append(p.Segments, current)
+			stageArgs = nil
+			onBuild = false
+			continue
+		}
+		if current == nil {
+			if child.Value != "arg" {
+				return nil, errors.Errorf("line %d: %s instruction before the first FROM", child.StartLine, strings.ToUpper(child.Value))
+			}
+			p.args = append(p.args, child.Original)
+			continue
+		}
+
+		var mounts []*Mount
+		if child.Value == "run" {
+			for _, flag := range child.Flags {
+				if !strings.HasPrefix(flag, "--mount=") {
+					continue
+				}
+				mount, err := ParseMount(strings.TrimPrefix(flag, "--mount="))
+				if err != nil {
+					return nil, errors.Wrapf(err, "line %d", child.StartLine)
+				}
+				mounts = append(mounts, mount)
+			}
+		}
+		if (len(mounts) > 0 || len(current.Mounts) > 0) && len(current.instructions) > 0 {
+			if onBuild {
+				return nil, errors.Errorf("line %d: RUN instructions with mounts can not come after ONBUILD instructions in a stage", child.StartLine)
+			}
+			current = &Segment{
+				Stage: current.Stage,
+				args:  append([]string{}, stageArgs...),
+			}
+			p.Segments = append(p.Segments, current)
+		}
+		current.instructions = append(current.instructions, child)
+		if len(mounts) > 0 {
+			current.Mounts = mounts
+			p.mounts = true
+		}
+		switch child.Value {
+		case "arg":
+			stageArgs = append(stageArgs, child.Original)
+		case "onbuild":
+			onBuild = true
+		}
+	}
+	if len(p.Segments) == 0 {
+		return nil, errors.Errorf("no FROM instruction")
+	}
+	return p, nil
+}
+
+// HasMounts returns true if RUN instructions of the Containerfile have mounts
+func (p *Plan) HasMounts() bool {
+	return p.mounts
+}
+
+// Last returns true if the segment is the last of its stage
+func (p *Plan) Last(s *Segment) bool {
+	for i, segment := range p.Segments {
+		if segment == s {
+			return i == len(p.Segments)-1 || p.Segments[i+1].Stage != s.Stage
+		}
+	}
+	return false
+}
+
+// Containerfile returns the Containerfile building the segment, images being
+// the IDs of the images the stages built so far ended with. Segments are
+// based on the image of the previous segment of their stage, and the stages
+// they copy from are replaced by their images.
+func (p *Plan) Containerfile(s *Segment, images []string) (string, error) {
+	image := func(stage int) (string, error) {
+		if stage >= len(images) || images[stage] == "" {
+			return "", errors.Errorf("stage %d was not built", stage)
+		}
+		return images[stage], nil
+	}
+
+	base := s.from
+	switch {
+	case !s.first:
+		id, err := image(s.Stage)
+		if err != nil {
+			return "", err
+		}
+		base = id
+	case s.fromStage >= 0:
+		id, err := image(s.fromStage)
+		if err != nil {
+			return "", err
+		}
+		base = id
+	}
+
+	var b strings.Builder
+	for _, arg := range p.args {
+		b.WriteString(arg + "\n")
+	}
+	b.WriteString("FROM " + base + "\n")
+	for _, arg := range s.args {
+		b.WriteString(arg + "\n")
+	}
+	for _, node := range s.instructions {
+		line := node.Original
+		if node.Value == "copy" {
+			for _, flag := range node.Flags {
+				if !strings.HasPrefix(flag, "--from=") {
+					continue
+				}
+				stage, ok := p.stageIndex(strings.TrimPrefix(flag, "--from="), s.Stage)
+				if !ok {
+					continue
+				}
+				id, err := image(stage)
+				if err != nil {
+					return "", err
+				}
+				line = strings.Replace(line, flag, "--from="+id, 1)
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String(), nil
+}
+
+// stageIndex returns the index of the stage before the stage at index
+// before, named or numbered ref
+func (p *Plan) stageIndex(ref string, before int) (int, bool) {
+	i, ok := p.stages[strings.ToLower(ref)]
+	if !ok {
+		n, err := strconv.Atoi(ref)
+		if err != nil {
+			return 0, false
+		}
+		i = n
+	}
+	return i, i >= 0 && i < before
+}
@@ -0,0 +1,94 @@
+package buildfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPlan returns the plan of the Containerfile
+func newPlan(t *testing.T, containerfile string) *Plan {
+	node, err := Parse([][]byte{[]byte(containerfile)})
+	require.NoError(t, err)
+	plan, err := NewPlan(node)
+	require.NoError(t, err)
+	return plan
+}
+
+func TestPlanWithoutMounts(t *testing.T) {
+	plan := newPlan(t, "FROM alpine AS builder\nRUN make\nFROM alpine\nCOPY --from=builder /app /app\n")
+	assert.False(t, plan.HasMounts())
+	require.Len(t, plan.Segments, 2)
+	assert.Equal(t, 0, plan.Segments[0].Stage)
+	assert.Equal(t, 1, plan.Segments[1].Stage)
+}
+
+func TestPlanSegments(t *testing.T) {
+	plan := newPlan(t, `ARG BASE=alpine
+FROM ${BASE} AS builder
+ARG VERSION
+WORKDIR /src
+RUN --mount=type=cache,target=/root/.cache make
+RUN --mount=type=secret,id=token fetch
+RUN make install
+FROM builder
+RUN --mount=type=tmpfs,target=/tmp check
+FROM alpine
+COPY --from=builder /app /app
+COPY --from=1 /report /report
+`)
+	assert.True(t, plan.HasMounts())
+	require.Len(t, plan.Segments, 6)
+	for i, stage := range []int{0, 0, 0, 0, 1, 2} {
+		assert.Equal(t, stage, plan.Segments[i].Stage)
+	}
+	assert.Empty(t, plan.Segments[0].Mounts)
+	require.Len(t, plan.Segments[1].Mounts, 1)
+	assert.Equal(t, MountTypeCache, plan.Segments[1].Mounts[0].Type)
+	require.Len(t, plan.Segments[2].Mounts, 1)
+	assert.Equal(t, MountTypeSecret, plan.Segments[2].Mounts[0].Type)
+	assert.Empty(t, plan.Segments[3].Mounts)
+	require.Len(t, plan.Segments[4].Mounts, 1)
+	assert.Equal(t, MountTypeTmpfs, plan.Segments[4].Mounts[0].Type)
+	assert.False(t, plan.Last(plan.Segments[2]))
+	assert.True(t, plan.Last(plan.Segments[3]))
+
+	images := []string{"s0"}
+	contents, err := plan.Containerfile(plan.Segments[0], images)
+	require.NoError(t, err)
+	assert.Equal(t, "ARG BASE=alpine\nFROM ${BASE}\nARG VERSION\nWORKDIR /src\n", contents)
+
+	contents, err = plan.Containerfile(plan.Segments[2], images)
+	require.NoError(t, err)
+	assert.Equal(t, "ARG BASE=alpine\nFROM s0\nARG VERSION\nRUN --mount=type=secret,id=token fetch\n", contents)
+
+	contents, err = plan.Containerfile(plan.Segments[4], images)
+	require.NoError(t, err)
+	assert.Equal(t, "ARG BASE=alpine\nFROM s0\nRUN --mount=type=tmpfs,target=/tmp check\n", contents)
+
+	_, err = plan.Containerfile(plan.Segments[5], []string{"s0", "", ""})
+	assert.EqualError(t, err, "stage 1 was not built")
+
+	contents, err = plan.Containerfile(plan.Segments[5], []string{"s0", "s1", ""})
+	require.NoError(t, err)
+	assert.Equal(t, "ARG BASE=alpine\nFROM alpine\nCOPY --from=s0 /app /app\nCOPY --from=s1 /report /report\n", contents)
+}
+
+func TestPlanErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, containerfile string
+	}{
+		{"no FROM", "ARG A=1\n"},
+		{"RUN before FROM", "RUN true\nFROM alpine\n"},
+		{"invalid mount", "FROM alpine\nRUN --mount=type=cache true\n"},
+		{"mount after ONBUILD", "FROM alpine\nONBUILD RUN true\nRUN --mount=type=tmpfs,target=/t true\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			node, err := Parse([][]byte{[]byte(tc.containerfile)})
+			require.NoError(t, err)
+			_, err = NewPlan(node)
+			assert.Error(t, err)
+		})
+	}
+}
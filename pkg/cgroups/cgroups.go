@@ -0,0 +1,55 @@
+// Package cgroups locates the cgroups of the host, on the cgroup v1
+// hierarchies or on the unified cgroup v2 hierarchy.
+package cgroups
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Root is where the cgroup hierarchies are mounted: the unified cgroup v2
+// hierarchy itself, or a directory of cgroup v1 hierarchies, one for each
+// controller
+const Root = "/sys/fs/cgroup"
+
+// V1Dir returns the directory of the cgroup at cgroupPath in the cgroup v1
+// hierarchy of controller
+func V1Dir(controller, cgroupPath string) string {
+	return filepath.Join(Root, controller, cgroupPath)
+}
+
+// V2Dir returns the directory of the cgroup at cgroupPath in the unified
+// cgroup v2 hierarchy
+func V2Dir(cgroupPath string) string {
+	return filepath.Join(Root, cgroupPath)
+}
+
+// Dir returns the directory holding the files of controller for the cgroup
+// at cgroupPath, in the hierarchy the host uses
+func Dir(controller, cgroupPath string) string {
+	if IsCgroup2UnifiedMode() {
+		return V2Dir(cgroupPath)
+	}
+	return V1Dir(controller, cgroupPath)
+}
+
+// Controllers returns the controllers available to the cgroup at cgroupPath
+// in the unified cgroup v2 hierarchy, or nil if the host does not use it or
+// there is no such cgroup
+func Controllers(cgroupPath string) []string {
+	if !IsCgroup2UnifiedMode() {
+		return nil
+	}
+	return readControllers(filepath.Join(V2Dir(cgroupPath), "cgroup.controllers"))
+}
+
+// readControllers returns the controllers listed in a cgroup.controllers
+// file, or nil if there is no such file
+func readControllers(path string) []string {
+	controllers, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(controllers))
+}
@@ -0,0 +1,27 @@
+// +build linux
+
+package cgroups
+
+import (
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+var (
+	unifiedOnce sync.Once
+	unified     bool
+)
+
+// IsCgroup2UnifiedMode returns whether the host mounts the unified cgroup v2
+// hierarchy at Root
+func IsCgroup2UnifiedMode() bool {
+	unifiedOnce.Do(func() {
+		var st unix.Statfs_t
+		if err := unix.Statfs(Root, &st); err != nil {
+			return
+		}
+		unified = st.Type == unix.CGROUP2_SUPER_MAGIC
+	})
+	return unified
+}
@@ -0,0 +1,32 @@
+package cgroups
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirs(t *testing.T) {
+	assert.Equal(t, "/sys/fs/cgroup/blkio/machine.slice/foo", V1Dir("blkio", "/machine.slice/foo"))
+	assert.Equal(t, "/sys/fs/cgroup/machine.slice/foo", V2Dir("/machine.slice/foo"))
+	if IsCgroup2UnifiedMode() {
+		assert.Equal(t, V2Dir("/foo"), Dir("pids", "/foo"))
+	} else {
+		assert.Equal(t, V1Dir("pids", "/foo"), Dir("pids", "/foo"))
+	}
+}
+
+func TestReadControllers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroups")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cgroup.controllers")
+	assert.Nil(t, readControllers(path))
+	require.NoError(t, ioutil.WriteFile(path, []byte("cpuset cpu io memory pids\n"), 0644))
+	assert.Equal(t, []string{"cpuset", "cpu", "io", "memory", "pids"}, readControllers(path))
+}
@@ -0,0 +1,8 @@
+// +build !linux
+
+package cgroups
+
+// IsCgroup2UnifiedMode returns false on unsupported OS's
+func IsCgroup2UnifiedMode() bool {
+	return false
+}
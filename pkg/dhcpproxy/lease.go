@@ -0,0 +1,282 @@
+package dhcpproxy
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// minRetryInterval is the minimal interval between attempts to renew a
+// lease, as recommended by RFC 2131
+const minRetryInterval = 60 * time.Second
+
+// errNak is returned when the server refuses to grant or extend a lease
+var errNak = errors.New("DHCP server refused the lease")
+
+// transport sends DHCP messages through the interface a lease is for
+type transport interface {
+	// exchange sends the message to the server at dst, or broadcasts it
+	// if dst is nil, and returns the first reply to it that accept
+	// accepts, retrying if there is none
+	exchange(m *message, dst net.IP, accept func(*message) bool) (*message, error)
+	// send sends the message to the server at dst without waiting for a
+	// reply
+	send(m *message, dst net.IP) error
+}
+
+// lease is an address leased by a DHCP server for an interface of a
+// container. Once acquired, it is renewed until released.
+type lease struct {
+	clientID  string
+	hwAddr    net.HardwareAddr
+	transport transport
+
+	lock       sync.Mutex
+	address    net.IPNet
+	router     net.IP
+	dnsServers []net.IP
+	domain     string
+	serverID   net.IP
+	renewAt    time.Time
+	rebindAt   time.Time
+	expiresAt  time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// acquireLease acquires a lease for the interface with the given hardware
+// address, and starts renewing it
+func acquireLease(clientID string, hwAddr net.HardwareAddr, t transport) (*lease, error) {
+	l := &lease{
+		clientID:  clientID,
+		hwAddr:    hwAddr,
+		transport: t,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if err := l.acquire(); err != nil {
+		return nil, err
+	}
+	go l.maintain()
+	return l, nil
+}
+
+// newMessage returns a new request of the given type from the client
+func (l *lease) newMessage(msgType byte) (*message, error) {
+	var xid [4]byte
+	if _, err := rand.Read(xid[:]); err != nil {
+		return nil, errors.Wrapf(err, "error generating DHCP transaction ID")
+	}
+	clientID := l.clientID
+	if len(clientID) > 254 {
+		clientID = clientID[:254]
+	}
+	return &message{
+		op:     opRequest,
+		xid:    binary.BigEndian.Uint32(xid[:]),
+		chaddr: l.hwAddr,
+		options: map[byte][]byte{
+			optMessageType: {msgType},
+			// Type 0 identifies clients by a string rather than
+			// a hardware address
+			optClientID: append([]byte{0}, clientID...),
+			optParamRequest: {
+				optSubnetMask,
+				optRouter,
+				optDNSServers,
+				optDomainName,
+				optLeaseTime,
+				optRenewalTime,
+				optRebindingTime,
+			},
+		},
+	}, nil
+}
+
+func isReply(msgTypes ...byte) func(*message) bool {
+	return func(m *message) bool {
+		for _, msgType := range msgTypes {
+			if m.messageType() == msgType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// acquire acquires a new lease by discovering the servers and requesting the
+// address offered by the first one to answer
+func (l *lease) acquire() error {
+	discover, err := l.newMessage(msgDiscover)
+	if err != nil {
+		return err
+	}
+	discover.flags = flagBroadcast
+	offer, err := l.transport.exchange(discover, nil, isReply(msgOffer))
+	if err != nil {
+		return errors.Wrapf(err, "error discovering DHCP servers")
+	}
+	serverID := offer.ipOption(optServerID)
+	if serverID == nil {
+		return errors.Errorf("DHCP offer of %s does not identify its server", offer.yiaddr)
+	}
+
+	request, err := l.newMessage(msgRequest)
+	if err != nil {
+		return err
+	}
+	request.flags = flagBroadcast
+	request.options[optRequestedIP] = offer.yiaddr.To4()
+	request.options[optServerID] = serverID.To4()
+	ack, err := l.transport.exchange(request, nil, isReply(msgAck, msgNak))
+	if err != nil {
+		return errors.Wrapf(err, "error requesting %s from DHCP server %s", offer.yiaddr, serverID)
+	}
+	if ack.messageType() == msgNak {
+		return errors.Wrapf(errNak, "error requesting %s from DHCP server %s", offer.yiaddr, serverID)
+	}
+	return l.update(ack, time.Now())
+}
+
+// renew extends the lease, by asking the server that granted it or, once
+// the server failed to answer for too long, by asking any server
+func (l *lease) renew(broadcast bool) error {
+	l.lock.Lock()
+	address := l.address.IP
+	var dst net.IP
+	if !broadcast {
+		dst = l.serverID
+	}
+	l.lock.Unlock()
+
+	request, err := l.newMessage(msgRequest)
+	if err != nil {
+		return err
+	}
+	request.ciaddr = address
+	ack, err := l.transport.exchange(request, dst, isReply(msgAck, msgNak))
+	if err != nil {
+		return err
+	}
+	if ack.messageType() == msgNak {
+		return errNak
+	}
+	return l.update(ack, time.Now())
+}
+
+// update records the parameters of the lease granted by the server
+func (l *lease) update(ack *message, now time.Time) error {
+	leaseTime := ack.durationOption(optLeaseTime)
+	if leaseTime == 0 {
+		return errors.Errorf("DHCP server granted %s without a lease time", ack.yiaddr)
+	}
+	mask := net.IPMask(ack.options[optSubnetMask])
+	if len(mask) != net.IPv4len {
+		mask = ack.yiaddr.DefaultMask()
+	}
+	renewalTime := ack.durationOption(optRenewalTime)
+	if renewalTime == 0 || renewalTime >= leaseTime {
+		renewalTime = leaseTime / 2
+	}
+	rebindingTime := ack.durationOption(optRebindingTime)
+	if rebindingTime <= renewalTime || rebindingTime >= leaseTime {
+		rebindingTime = leaseTime * 7 / 8
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.address = net.IPNet{IP: ack.yiaddr.To4(), Mask: mask}
+	l.router = ack.ipOption(optRouter)
+	l.dnsServers = ack.ipsOption(optDNSServers)
+	l.domain = string(ack.options[optDomainName])
+	if serverID := ack.ipOption(optServerID); serverID != nil {
+		l.serverID = serverID
+	}
+	l.renewAt = now.Add(renewalTime)
+	l.rebindAt = now.Add(rebindingTime)
+	l.expiresAt = now.Add(leaseTime)
+	return nil
+}
+
+// maintain renews the lease until it is released, or lost
+func (l *lease) maintain() {
+	defer close(l.done)
+
+	l.lock.Lock()
+	next := l.renewAt
+	l.lock.Unlock()
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-l.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		l.lock.Lock()
+		address, rebindAt, expiresAt := l.address.IP, l.rebindAt, l.expiresAt
+		l.lock.Unlock()
+
+		now := time.Now()
+		if !now.Before(expiresAt) {
+			logrus.Errorf("DHCP lease of %s for %s expired", address, l.clientID)
+			return
+		}
+		err := l.renew(!now.Before(rebindAt))
+		if err == nil {
+			l.lock.Lock()
+			next = l.renewAt
+			l.lock.Unlock()
+			continue
+		}
+		if err == errNak {
+			logrus.Errorf("DHCP lease of %s for %s was revoked", address, l.clientID)
+			return
+		}
+		logrus.Warnf("Error renewing DHCP lease of %s for %s: %v", address, l.clientID, err)
+		next = now.Add(retryInterval(now, rebindAt, expiresAt))
+	}
+}
+
+// retryInterval returns when to retry renewing a lease after a failure:
+// halfway to the time to rebind the lease or, past that, halfway to its
+// expiration, as recommended by RFC 2131
+func retryInterval(now, rebindAt, expiresAt time.Time) time.Duration {
+	deadline := rebindAt
+	if !now.Before(rebindAt) {
+		deadline = expiresAt
+	}
+	interval := deadline.Sub(now) / 2
+	if interval < minRetryInterval {
+		interval = minRetryInterval
+	}
+	if remaining := expiresAt.Sub(now); interval > remaining {
+		interval = remaining
+	}
+	return interval
+}
+
+// release stops renewing the lease and returns the address to the server
+func (l *lease) release() error {
+	close(l.stop)
+	<-l.done
+
+	request, err := l.newMessage(msgRelease)
+	if err != nil {
+		return err
+	}
+	l.lock.Lock()
+	request.ciaddr = l.address.IP
+	serverID := l.serverID
+	l.lock.Unlock()
+	request.options[optServerID] = serverID.To4()
+	delete(request.options, optParamRequest)
+	return l.transport.send(request, serverID)
+}
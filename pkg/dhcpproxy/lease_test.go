@@ -0,0 +1,126 @@
+package dhcpproxy
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeServer is a transport answering messages as a DHCP server would
+type fakeServer struct {
+	serverID net.IP
+	address  net.IP
+	nak      bool
+	sent     []*message
+	dsts     []net.IP
+}
+
+func (s *fakeServer) reply(m *message, msgType byte) *message {
+	leaseTime := make([]byte, 4)
+	binary.BigEndian.PutUint32(leaseTime, 3600)
+	return &message{
+		op:     opReply,
+		xid:    m.xid,
+		yiaddr: s.address,
+		chaddr: m.chaddr,
+		options: map[byte][]byte{
+			optMessageType: {msgType},
+			optServerID:    s.serverID.To4(),
+			optSubnetMask:  net.CIDRMask(24, 32),
+			optRouter:      net.ParseIP("192.0.2.1").To4(),
+			optDNSServers:  net.ParseIP("192.0.2.53").To4(),
+			optLeaseTime:   leaseTime,
+		},
+	}
+}
+
+func (s *fakeServer) exchange(m *message, dst net.IP, accept func(*message) bool) (*message, error) {
+	s.sent = append(s.sent, m)
+	s.dsts = append(s.dsts, dst)
+	var reply *message
+	switch m.messageType() {
+	case msgDiscover:
+		reply = s.reply(m, msgOffer)
+	case msgRequest:
+		if s.nak {
+			reply = s.reply(m, msgNak)
+		} else {
+			reply = s.reply(m, msgAck)
+		}
+	}
+	if reply == nil || !accept(reply) {
+		return nil, errors.Errorf("no reply from DHCP server")
+	}
+	return reply, nil
+}
+
+func (s *fakeServer) send(m *message, dst net.IP) error {
+	s.sent = append(s.sent, m)
+	s.dsts = append(s.dsts, dst)
+	return nil
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{
+		serverID: net.ParseIP("192.0.2.2"),
+		address:  net.ParseIP("192.0.2.10"),
+	}
+}
+
+func TestAcquireRenewReleaseLease(t *testing.T) {
+	server := newFakeServer()
+	hwAddr, _ := net.ParseMAC("02:42:ac:11:00:02")
+	l, err := acquireLease("ctr/net/eth0", hwAddr, server)
+	assert.NoError(t, err)
+
+	assert.Len(t, server.sent, 2)
+	assert.Equal(t, byte(msgDiscover), server.sent[0].messageType())
+	request := server.sent[1]
+	assert.Equal(t, byte(msgRequest), request.messageType())
+	assert.True(t, server.address.Equal(net.IP(request.options[optRequestedIP])))
+	assert.True(t, server.serverID.Equal(net.IP(request.options[optServerID])))
+	assert.Equal(t, append([]byte{0}, "ctr/net/eth0"...), request.options[optClientID])
+
+	assert.Equal(t, "192.0.2.10/24", l.address.String())
+	assert.True(t, net.ParseIP("192.0.2.1").Equal(l.router))
+	assert.Len(t, l.dnsServers, 1)
+	assert.Equal(t, 30*time.Minute, l.renewAt.Sub(l.expiresAt.Add(-time.Hour)))
+
+	// Renewing asks the server that granted the lease, rebinding asks any
+	assert.NoError(t, l.renew(false))
+	assert.True(t, server.serverID.Equal(server.dsts[2]))
+	assert.True(t, server.address.Equal(server.sent[2].ciaddr))
+	assert.NoError(t, l.renew(true))
+	assert.Nil(t, server.dsts[3])
+
+	server.nak = true
+	assert.Equal(t, errNak, l.renew(false))
+
+	assert.NoError(t, l.release())
+	release := server.sent[len(server.sent)-1]
+	assert.Equal(t, byte(msgRelease), release.messageType())
+	assert.True(t, server.address.Equal(release.ciaddr))
+}
+
+func TestAcquireLeaseRefused(t *testing.T) {
+	server := newFakeServer()
+	server.nak = true
+	hwAddr, _ := net.ParseMAC("02:42:ac:11:00:02")
+	_, err := acquireLease("ctr/net/eth0", hwAddr, server)
+	assert.Equal(t, errNak, errors.Cause(err))
+}
+
+func TestRetryInterval(t *testing.T) {
+	now := time.Now()
+	// Halfway to rebinding
+	assert.Equal(t, 10*time.Minute, retryInterval(now, now.Add(20*time.Minute), now.Add(time.Hour)))
+	// Halfway to expiration once rebinding
+	assert.Equal(t, 20*time.Minute, retryInterval(now, now.Add(-time.Minute), now.Add(40*time.Minute)))
+	// Not more often than every minute, but not past expiration
+	assert.Equal(t, minRetryInterval, retryInterval(now, now.Add(time.Minute), now.Add(time.Hour)))
+	assert.Equal(t, 30*time.Second, retryInterval(now, now.Add(-time.Minute), now.Add(30*time.Second)))
+}
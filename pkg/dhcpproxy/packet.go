@@ -0,0 +1,182 @@
+package dhcpproxy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Operations of DHCP messages
+const (
+	opRequest = 1
+	opReply   = 2
+)
+
+// Types of DHCP messages, from RFC 2132
+const (
+	msgDiscover = 1
+	msgOffer    = 2
+	msgRequest  = 3
+	msgAck      = 5
+	msgNak      = 6
+	msgRelease  = 7
+)
+
+// Codes of the DHCP options used by the proxy, from RFC 2132
+const (
+	optPad           = 0
+	optSubnetMask    = 1
+	optRouter        = 3
+	optDNSServers    = 6
+	optDomainName    = 15
+	optRequestedIP   = 50
+	optLeaseTime     = 51
+	optMessageType   = 53
+	optServerID      = 54
+	optParamRequest  = 55
+	optRenewalTime   = 58
+	optRebindingTime = 59
+	optClientID      = 61
+	optEnd           = 255
+)
+
+const (
+	// headerLen is the length of the fixed part of DHCP messages, up to
+	// and including the magic cookie preceding the options
+	headerLen = 240
+	// minMessageLen is the minimal length of BOOTP messages, which some
+	// servers still require
+	minMessageLen = 300
+	// flagBroadcast asks the server to broadcast its replies, as the
+	// interface of the client has no address to receive them yet
+	flagBroadcast = 0x8000
+)
+
+var magicCookie = []byte{99, 130, 83, 99}
+
+// message is a DHCP message
+type message struct {
+	op      byte
+	xid     uint32
+	flags   uint16
+	ciaddr  net.IP
+	yiaddr  net.IP
+	chaddr  net.HardwareAddr
+	options map[byte][]byte
+}
+
+// marshal encodes the message in wire format
+func (m *message) marshal() []byte {
+	buf := make([]byte, headerLen, minMessageLen)
+	buf[0] = m.op
+	buf[1] = 1 // Ethernet
+	buf[2] = byte(len(m.chaddr))
+	binary.BigEndian.PutUint32(buf[4:8], m.xid)
+	binary.BigEndian.PutUint16(buf[10:12], m.flags)
+	if ip := m.ciaddr.To4(); ip != nil {
+		copy(buf[12:16], ip)
+	}
+	if ip := m.yiaddr.To4(); ip != nil {
+		copy(buf[16:20], ip)
+	}
+	copy(buf[28:44], m.chaddr)
+	copy(buf[236:240], magicCookie)
+
+	codes := make([]int, 0, len(m.options))
+	for code := range m.options {
+		codes = append(codes, int(code))
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		value := m.options[byte(code)]
+		buf = append(buf, byte(code), byte(len(value)))
+		buf = append(buf, value...)
+	}
+	buf = append(buf, optEnd)
+	for len(buf) < minMessageLen {
+		buf = append(buf, optPad)
+	}
+	return buf
+}
+
+// parseMessage decodes a message in wire format
+func parseMessage(buf []byte) (*message, error) {
+	if len(buf) < headerLen {
+		return nil, errors.Errorf("DHCP message too short: %d bytes", len(buf))
+	}
+	if !bytes.Equal(buf[236:240], magicCookie) {
+		return nil, errors.Errorf("DHCP message has an invalid magic cookie")
+	}
+	hlen := int(buf[2])
+	if hlen > 16 {
+		return nil, errors.Errorf("DHCP message has an invalid hardware address length %d", hlen)
+	}
+	m := &message{
+		op:      buf[0],
+		xid:     binary.BigEndian.Uint32(buf[4:8]),
+		flags:   binary.BigEndian.Uint16(buf[10:12]),
+		ciaddr:  net.IP(append([]byte{}, buf[12:16]...)),
+		yiaddr:  net.IP(append([]byte{}, buf[16:20]...)),
+		chaddr:  net.HardwareAddr(append([]byte{}, buf[28:28+hlen]...)),
+		options: make(map[byte][]byte),
+	}
+	for opts := buf[headerLen:]; len(opts) > 0; {
+		code := opts[0]
+		if code == optEnd {
+			break
+		}
+		if code == optPad {
+			opts = opts[1:]
+			continue
+		}
+		if len(opts) < 2 || len(opts) < 2+int(opts[1]) {
+			return nil, errors.Errorf("DHCP message has a truncated option %d", code)
+		}
+		length := int(opts[1])
+		// Options may be split across several instances
+		m.options[code] = append(m.options[code], opts[2:2+length]...)
+		opts = opts[2+length:]
+	}
+	return m, nil
+}
+
+// messageType returns the type of the message, or 0 if it has none
+func (m *message) messageType() byte {
+	if value := m.options[optMessageType]; len(value) == 1 {
+		return value[0]
+	}
+	return 0
+}
+
+// ipOption returns the first address of an option holding addresses
+func (m *message) ipOption(code byte) net.IP {
+	if ips := m.ipsOption(code); len(ips) > 0 {
+		return ips[0]
+	}
+	return nil
+}
+
+// ipsOption returns the addresses of an option holding addresses
+func (m *message) ipsOption(code byte) []net.IP {
+	var ips []net.IP
+	value := m.options[code]
+	for len(value) >= net.IPv4len {
+		ips = append(ips, net.IP(append([]byte{}, value[:net.IPv4len]...)))
+		value = value[net.IPv4len:]
+	}
+	return ips
+}
+
+// durationOption returns the duration of an option holding a number of
+// seconds, or 0 if the message does not have it
+func (m *message) durationOption(code byte) time.Duration {
+	value := m.options[code]
+	if len(value) != 4 {
+		return 0
+	}
+	return time.Duration(binary.BigEndian.Uint32(value)) * time.Second
+}
@@ -0,0 +1,59 @@
+package dhcpproxy
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	hwAddr, err := net.ParseMAC("02:42:ac:11:00:02")
+	assert.NoError(t, err)
+	m := &message{
+		op:     opReply,
+		xid:    0xdeadbeef,
+		flags:  flagBroadcast,
+		ciaddr: net.IPv4zero,
+		yiaddr: net.ParseIP("192.0.2.10"),
+		chaddr: hwAddr,
+		options: map[byte][]byte{
+			optMessageType: {msgAck},
+			optRouter:      net.ParseIP("192.0.2.1").To4(),
+			optDNSServers:  append(net.ParseIP("192.0.2.53").To4(), net.ParseIP("192.0.2.54").To4()...),
+			optLeaseTime:   {0, 0, 0x0e, 0x10},
+		},
+	}
+	buf := m.marshal()
+	assert.True(t, len(buf) >= minMessageLen)
+
+	parsed, err := parseMessage(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, m.op, parsed.op)
+	assert.Equal(t, m.xid, parsed.xid)
+	assert.Equal(t, m.flags, parsed.flags)
+	assert.Equal(t, hwAddr, parsed.chaddr)
+	assert.True(t, m.yiaddr.Equal(parsed.yiaddr))
+	assert.Equal(t, byte(msgAck), parsed.messageType())
+	assert.True(t, net.ParseIP("192.0.2.1").Equal(parsed.ipOption(optRouter)))
+	assert.Len(t, parsed.ipsOption(optDNSServers), 2)
+	assert.Equal(t, time.Hour, parsed.durationOption(optLeaseTime))
+	assert.Equal(t, time.Duration(0), parsed.durationOption(optRenewalTime))
+}
+
+func TestParseMessageInvalid(t *testing.T) {
+	_, err := parseMessage(make([]byte, 10))
+	assert.Error(t, err)
+
+	// No magic cookie
+	_, err = parseMessage(make([]byte, minMessageLen))
+	assert.Error(t, err)
+
+	m := &message{op: opReply, options: map[byte][]byte{}}
+	buf := m.marshal()
+	// Option claiming to be longer than the message
+	buf = append(buf[:headerLen], optRouter, 200, 1, 2)
+	_, err = parseMessage(buf)
+	assert.Error(t, err)
+}
@@ -0,0 +1,166 @@
+package dhcpproxy
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containernetworking/cni/pkg/types"
+	"github.com/containernetworking/cni/pkg/types/current"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultSocketPath is where the dhcp IPAM plugin of CNI expects the DHCP
+// daemon to listen
+const DefaultSocketPath = "/run/cni/dhcp.sock"
+
+// CmdArgs are the arguments the dhcp IPAM plugin of CNI was called with, which
+// it passes on to the DHCP daemon. Its fields match those of skel.CmdArgs.
+type CmdArgs struct {
+	ContainerID string
+	Netns       string
+	IfName      string
+	Args        string
+	Path        string
+	StdinData   []byte
+}
+
+// Proxy performs DHCP on behalf of containers attached to networks using the
+// dhcp IPAM plugin of CNI, such as macvlan networks, and renews their leases
+// for as long as they are attached. It serves the protocol of the DHCP
+// daemon of CNI, so no other daemon is required.
+type Proxy struct {
+	lock   sync.Mutex
+	leases map[string]*lease
+}
+
+// NewProxy returns a new DHCP proxy, without any lease
+func NewProxy() *Proxy {
+	return &Proxy{leases: make(map[string]*lease)}
+}
+
+// clientID returns the client ID identifying the interface of the container
+// on the network to the DHCP server
+func clientID(args *CmdArgs) (string, error) {
+	var conf types.NetConf
+	if err := json.Unmarshal(args.StdinData, &conf); err != nil {
+		return "", errors.Wrapf(err, "error parsing network configuration")
+	}
+	return args.ContainerID + "/" + conf.Name + "/" + args.IfName, nil
+}
+
+// Allocate acquires a lease for the interface of the container, and returns
+// the address, route and DNS servers granted by the DHCP server. It is called
+// by the dhcp IPAM plugin when the container is attached to the network.
+func (p *Proxy) Allocate(args *CmdArgs, result *current.Result) error {
+	id, err := clientID(args)
+	if err != nil {
+		return err
+	}
+	t, hwAddr, err := newTransport(args.Netns, args.IfName)
+	if err != nil {
+		return err
+	}
+	l, err := acquireLease(id, hwAddr, t)
+	if err != nil {
+		return errors.Wrapf(err, "error acquiring DHCP lease for %s", id)
+	}
+
+	p.lock.Lock()
+	old := p.leases[id]
+	p.leases[id] = l
+	p.lock.Unlock()
+	if old != nil {
+		// The container was attached again without being detached
+		if err := old.release(); err != nil {
+			logrus.Debugf("Error releasing previous DHCP lease for %s: %v", id, err)
+		}
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	logrus.Infof("Acquired DHCP lease of %s for %s", l.address.String(), id)
+	result.IPs = []*current.IPConfig{{
+		Version: "4",
+		Address: l.address,
+		Gateway: l.router,
+	}}
+	if l.router != nil {
+		result.Routes = []*types.Route{{
+			Dst: net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)},
+			GW:  l.router,
+		}}
+	}
+	for _, server := range l.dnsServers {
+		result.DNS.Nameservers = append(result.DNS.Nameservers, server.String())
+	}
+	result.DNS.Domain = l.domain
+	return nil
+}
+
+// Release releases the lease of the interface of the container. It is called
+// by the dhcp IPAM plugin when the container is detached from the network.
+func (p *Proxy) Release(args *CmdArgs, reply *struct{}) error {
+	id, err := clientID(args)
+	if err != nil {
+		return err
+	}
+	p.lock.Lock()
+	l, ok := p.leases[id]
+	delete(p.leases, id)
+	p.lock.Unlock()
+	if !ok {
+		// Releasing must succeed if there is nothing to release, e.g.
+		// after a restart of the proxy
+		logrus.Debugf("No DHCP lease to release for %s", id)
+		return nil
+	}
+	if err := l.release(); err != nil {
+		return errors.Wrapf(err, "error releasing DHCP lease for %s", id)
+	}
+	logrus.Infof("Released DHCP lease for %s", id)
+	return nil
+}
+
+// Serve serves the DHCP daemon protocol of CNI on the unix socket at the given
+// path until the context is cancelled. Leases are renewed even while not
+// serving, until released.
+func (p *Proxy) Serve(ctx context.Context, socketPath string) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("DHCP", p); err != nil {
+		return errors.Wrapf(err, "error registering DHCP proxy")
+	}
+	mux := http.NewServeMux()
+	mux.Handle(rpc.DefaultRPCPath, server)
+
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0700); err != nil {
+		return errors.Wrapf(err, "error creating directory for socket %s", socketPath)
+	}
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error removing stale socket %s", socketPath)
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return errors.Wrapf(err, "error listening on %s", socketPath)
+	}
+	defer os.Remove(socketPath)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- http.Serve(listener, mux)
+	}()
+	select {
+	case <-ctx.Done():
+		listener.Close()
+		<-errChan
+		return nil
+	case err := <-errChan:
+		return errors.Wrapf(err, "error serving on %s", socketPath)
+	}
+}
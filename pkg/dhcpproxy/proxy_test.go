@@ -0,0 +1,58 @@
+package dhcpproxy
+
+import (
+	"context"
+	"io/ioutil"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/types/current"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestServeCNIProtocol checks the proxy answers calls made the way the dhcp
+// IPAM plugin of CNI makes them
+func TestServeCNIProtocol(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dhcpproxy")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "dhcp.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error)
+	go func() {
+		served <- NewProxy().Serve(ctx, socketPath)
+	}()
+
+	var client *rpc.Client
+	for i := 0; i < 50; i++ {
+		if client, err = rpc.DialHTTP("unix", socketPath); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NoError(t, err)
+	defer client.Close()
+
+	args := &CmdArgs{
+		ContainerID: "ctr",
+		Netns:       "/var/run/netns/missing",
+		IfName:      "eth0",
+		StdinData:   []byte(`{"name": "macvlan", "type": "macvlan", "ipam": {"type": "dhcp"}}`),
+	}
+	// Releasing a lease the proxy does not know about succeeds
+	assert.NoError(t, client.Call("DHCP.Release", args, &struct{}{}))
+	// Allocating fails when the namespace does not exist
+	assert.Error(t, client.Call("DHCP.Allocate", args, &current.Result{}))
+
+	args.StdinData = []byte("not json")
+	assert.Error(t, client.Call("DHCP.Release", args, &struct{}{}))
+
+	cancel()
+	assert.NoError(t, <-served)
+	_, err = os.Stat(socketPath)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,134 @@
+// +build linux
+
+package dhcpproxy
+
+import (
+	"net"
+	"os"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	clientPort = 68
+	serverPort = 67
+)
+
+// exchangeTimeouts are how long to wait for a reply to each attempt to send a
+// message
+var exchangeTimeouts = []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second}
+
+// netnsTransport sends DHCP messages through an interface in the network
+// namespace of a container
+type netnsTransport struct {
+	netns  string
+	ifName string
+}
+
+// newTransport returns a transport through the given interface of the given
+// network namespace, along with the hardware address of the interface
+func newTransport(netns, ifName string) (transport, net.HardwareAddr, error) {
+	var hwAddr net.HardwareAddr
+	err := ns.WithNetNSPath(netns, func(ns.NetNS) error {
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return errors.Wrapf(err, "error looking up interface %s", ifName)
+		}
+		hwAddr = link.Attrs().HardwareAddr
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(hwAddr) == 0 {
+		return nil, nil, errors.Errorf("interface %s has no hardware address", ifName)
+	}
+	return &netnsTransport{netns: netns, ifName: ifName}, hwAddr, nil
+}
+
+// listen opens a socket on the DHCP client port, bound to the interface. The
+// socket stays in the network namespace of the container once opened.
+func (t *netnsTransport) listen() (net.PacketConn, error) {
+	var conn net.PacketConn
+	err := ns.WithNetNSPath(t.netns, func(ns.NetNS) error {
+		fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.IPPROTO_UDP)
+		if err != nil {
+			return errors.Wrapf(err, "error opening DHCP socket")
+		}
+		f := os.NewFile(uintptr(fd), "dhcp")
+		defer f.Close()
+		if err := unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_REUSEADDR, 1); err != nil {
+			return errors.Wrapf(err, "error configuring DHCP socket")
+		}
+		if err := unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_BROADCAST, 1); err != nil {
+			return errors.Wrapf(err, "error configuring DHCP socket")
+		}
+		if err := unix.BindToDevice(fd, t.ifName); err != nil {
+			return errors.Wrapf(err, "error binding DHCP socket to %s", t.ifName)
+		}
+		if err := unix.Bind(fd, &unix.SockaddrInet4{Port: clientPort}); err != nil {
+			return errors.Wrapf(err, "error binding DHCP socket to port %d", clientPort)
+		}
+		conn, err = net.FilePacketConn(f)
+		return err
+	})
+	return conn, err
+}
+
+func serverAddr(dst net.IP) *net.UDPAddr {
+	if dst == nil {
+		dst = net.IPv4bcast
+	}
+	return &net.UDPAddr{IP: dst, Port: serverPort}
+}
+
+func (t *netnsTransport) send(m *message, dst net.IP) error {
+	conn, err := t.listen()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.WriteTo(m.marshal(), serverAddr(dst)); err != nil {
+		return errors.Wrapf(err, "error sending DHCP message")
+	}
+	return nil
+}
+
+func (t *netnsTransport) exchange(m *message, dst net.IP, accept func(*message) bool) (*message, error) {
+	conn, err := t.listen()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 1500)
+	for _, timeout := range exchangeTimeouts {
+		if _, err := conn.WriteTo(m.marshal(), serverAddr(dst)); err != nil {
+			return nil, errors.Wrapf(err, "error sending DHCP message")
+		}
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, err
+		}
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					break
+				}
+				return nil, errors.Wrapf(err, "error receiving DHCP message")
+			}
+			reply, err := parseMessage(buf[:n])
+			if err != nil || reply.op != opReply || reply.xid != m.xid || reply.chaddr.String() != m.chaddr.String() {
+				continue
+			}
+			if accept(reply) {
+				return reply, nil
+			}
+		}
+	}
+	return nil, errors.Errorf("no reply from DHCP server")
+}
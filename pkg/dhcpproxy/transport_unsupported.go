@@ -0,0 +1,13 @@
+// +build !linux
+
+package dhcpproxy
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+func newTransport(netns, ifName string) (transport, net.HardwareAddr, error) {
+	return nil, nil, errors.Errorf("the DHCP proxy is not supported on this platform")
+}
@@ -0,0 +1,172 @@
+package dockerapi
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// nopWriteCloser writes to the connection of an attach session, which is
+// closed once the session ends rather than by the streams
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close does nothing
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// SetAttachIdleTimeout sets how long attach sessions can go without input or
+// output before the service detaches them, leaving the containers running, 0
+// to never detach them
+func (s *Server) SetAttachIdleTimeout(timeout time.Duration) {
+	s.attachIdleTimeout = timeout
+}
+
+// addResizer registers the channel resizing the terminal of the container
+// for the attach session in progress
+func (s *Server) addResizer(id string) chan remotecommand.TerminalSize {
+	resize := make(chan remotecommand.TerminalSize, 1)
+	s.resizeLock.Lock()
+	defer s.resizeLock.Unlock()
+	s.resizers[id] = resize
+	return resize
+}
+
+// removeResizer unregisters and closes the resize channel of an attach
+// session, unless a later session replaced it
+func (s *Server) removeResizer(id string, resize chan remotecommand.TerminalSize) {
+	s.resizeLock.Lock()
+	defer s.resizeLock.Unlock()
+	if s.resizers[id] == resize {
+		delete(s.resizers, id)
+	}
+	close(resize)
+}
+
+// hijack takes over the connection of the request to stream the attach
+// session, upgrading it when the client asks to
+func hijack(w http.ResponseWriter, r *http.Request) (net.Conn, *bufio.Reader, error) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.Errorf("the connection does not support streaming")
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error taking over the connection")
+	}
+	if r.Header.Get("Upgrade") == "tcp" {
+		fmt.Fprint(conn, "HTTP/1.1 101 UPGRADED\r\nContent-Type: application/vnd.docker.raw-stream\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+	} else {
+		fmt.Fprint(conn, "HTTP/1.1 200 OK\r\nContent-Type: application/vnd.docker.raw-stream\r\n\r\n")
+	}
+	return conn, buf.Reader, nil
+}
+
+// attachContainer attaches the connection of the request to the streams of a
+// container until it exits or the client detaches. The output of
+// containers without a terminal is multiplexed, as Docker does.
+func (s *Server) attachContainer(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var attach [3]bool
+	for i, key := range []string{"stdin", "stdout", "stderr"} {
+		if attach[i], err = boolQuery(r, key); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	if !attach[0] && !attach[1] && !attach[2] {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "at least one of stdin, stdout and stderr must be attached"))
+		return
+	}
+	state, err := ctr.State()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if state != libpod.ContainerStateCreated && state != libpod.ContainerStateRunning {
+		writeError(w, errors.Wrapf(libpod.ErrCtrStateInvalid, "can only attach to created or running containers"))
+		return
+	}
+	spec := ctr.Spec()
+	tty := spec != nil && spec.Process != nil && spec.Process.Terminal
+
+	conn, input, err := hijack(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer conn.Close()
+
+	streams := &libpod.AttachStreams{
+		OutputStream: nopWriteCloser{conn},
+		ErrorStream:  nopWriteCloser{conn},
+		InputStream:  input,
+		AttachInput:  attach[0],
+		AttachOutput: attach[1],
+		AttachError:  attach[2],
+		// Leaked connections of clients would keep the session open
+		IdleTimeout: s.attachIdleTimeout,
+	}
+	if !tty {
+		streams.OutputStream = nopWriteCloser{stdcopy.NewStdWriter(conn, stdcopy.Stdout)}
+		streams.ErrorStream = nopWriteCloser{stdcopy.NewStdWriter(conn, stdcopy.Stderr)}
+	}
+	resize := s.addResizer(ctr.ID())
+	defer s.removeResizer(ctr.ID(), resize)
+	if err := ctr.Attach(streams, r.URL.Query().Get("detachKeys"), resize); err != nil {
+		// The status is sent already, the error can only be logged
+		logrus.Errorf("Error attaching to container %s: %v", ctr.ID(), err)
+	}
+}
+
+// resizeContainer resizes the terminal of a container attached to
+func (s *Server) resizeContainer(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var size remotecommand.TerminalSize
+	for _, dim := range []struct {
+		key   string
+		value *uint16
+	}{{"h", &size.Height}, {"w", &size.Width}} {
+		v, err := strconv.ParseUint(r.URL.Query().Get(dim.key), 10, 16)
+		if err != nil {
+			writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid value %q of %s", r.URL.Query().Get(dim.key), dim.key))
+			return
+		}
+		*dim.value = uint16(v)
+	}
+
+	s.resizeLock.Lock()
+	defer s.resizeLock.Unlock()
+	resize, ok := s.resizers[ctr.ID()]
+	if !ok {
+		writeError(w, errors.Wrapf(libpod.ErrCtrStateInvalid, "container %s is not attached to", ctr.ID()))
+		return
+	}
+	// Only the latest size matters, a pending one is replaced
+	select {
+	case <-resize:
+	default:
+	}
+	resize <- size
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,77 @@
+package dockerapi
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHijack(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, input, err := hijack(w, r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		// Echo the input of the client
+		io.Copy(conn, input)
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		upgrade string
+		status  string
+	}{
+		{"", "HTTP/1.1 200 OK"},
+		{"tcp", "HTTP/1.1 101 UPGRADED"},
+	} {
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		require.NoError(t, err)
+		request := "POST /containers/foo/attach?stream=1&stdin=1 HTTP/1.1\r\nHost: podman\r\n"
+		if tc.upgrade != "" {
+			request += "Connection: Upgrade\r\nUpgrade: " + tc.upgrade + "\r\n"
+		}
+		_, err = io.WriteString(conn, request+"\r\nhello\n")
+		require.NoError(t, err)
+
+		reader := bufio.NewReader(conn)
+		status, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, tc.status, strings.TrimSpace(status))
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\r\n" {
+				break
+			}
+		}
+		echo, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "hello\n", echo)
+		conn.Close()
+	}
+}
+
+func TestResizers(t *testing.T) {
+	s := NewServer(nil)
+	first := s.addResizer("foo")
+	second := s.addResizer("foo")
+	assert.Equal(t, second, s.resizers["foo"])
+
+	// A session ending does not unregister the session replacing it
+	s.removeResizer("foo", first)
+	assert.Equal(t, second, s.resizers["foo"])
+	_, ok := <-first
+	assert.False(t, ok)
+
+	s.removeResizer("foo", second)
+	assert.Empty(t, s.resizers)
+}
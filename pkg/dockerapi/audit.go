@@ -0,0 +1,204 @@
+package dockerapi
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/audit"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// auditFilters are the filters of the records of the audit log
+var auditFilters = map[string]bool{
+	"operation": true,
+	"source":    true,
+	"target":    true,
+	"uid":       true,
+}
+
+// peerKey is the key of the credentials of the peer of the connection of
+// requests in their context
+type peerKey struct{}
+
+// peer is the process at the other end of the connection of a request
+type peer struct {
+	uid int
+	pid int
+}
+
+// peerContext adds the credentials of the peer of a unix connection to its
+// context, for the audit log
+func peerContext(ctx context.Context, c net.Conn) context.Context {
+	uc, ok := c.(*net.UnixConn)
+	if !ok {
+		return ctx
+	}
+	p, err := peerCredentials(uc)
+	if err != nil {
+		logrus.Debugf("Unable to get the credentials of the API client: %v", err)
+		return ctx
+	}
+	return context.WithValue(ctx, peerKey{}, p)
+}
+
+// auditWriter records the status of a response, keeping the streaming and
+// hijacking of the connection available to the handlers
+type auditWriter struct {
+	http.ResponseWriter
+	status   int
+	hijacked bool
+}
+
+// WriteHeader records the status of the response
+func (w *auditWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the default status of responses without one
+func (w *auditWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the response if the writer supports it
+func (w *auditWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection if the writer supports it
+func (w *auditWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.Errorf("the connection can not be hijacked")
+	}
+	w.hijacked = true
+	return hijacker.Hijack()
+}
+
+// hashingBody hashes the body of a request as the handler reads it
+type hashingBody struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+// Read reads and hashes the body
+func (b *hashingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	return n, err
+}
+
+// serveAudited serves a request modifying containers, pods or images and
+// records it in the audit log, with the identity of the client, once served.
+// The arguments of the request are its query and its body, as far as the
+// handler reads it.
+func (s *Server) serveAudited(auditLog *audit.Log, w http.ResponseWriter, r *http.Request) {
+	var match mux.RouteMatch
+	if !s.router.Match(r, &match) {
+		s.router.ServeHTTP(w, r)
+		return
+	}
+	record := audit.Record{
+		Time:   time.Now(),
+		Source: audit.SourceAPI,
+		UID:    audit.UnknownUID,
+	}
+	if p, ok := r.Context().Value(peerKey{}).(peer); ok {
+		record.UID = p.uid
+		record.PID = p.pid
+	}
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		record.CN = r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	template, _ := match.Route.GetPathTemplate()
+	// Routes are also served with the version of the API as prefix
+	if strings.HasPrefix(template, "/v{version") {
+		template = template[strings.Index(template, "}")+1:]
+	}
+	record.Operation = r.Method + " " + template
+	if name := match.Vars["name"]; name != "" {
+		record.Target = []string{name}
+	}
+
+	body := &hashingBody{ReadCloser: r.Body, hash: sha256.New()}
+	r.Body = body
+	aw := &auditWriter{ResponseWriter: w}
+	s.router.ServeHTTP(aw, r)
+
+	record.ArgsHash = audit.HashArgs(r.URL.RawQuery, hex.EncodeToString(body.hash.Sum(nil)))
+	record.Status = aw.status
+	if record.Status == 0 && !aw.hijacked {
+		record.Status = http.StatusOK
+	}
+	if err := auditLog.Write(record); err != nil {
+		logrus.Errorf("Unable to write audit record of %s: %v", record.Operation, err)
+	}
+}
+
+// auditLog returns the audit log requests are recorded in, nil if the request
+// does not modify containers, pods or images or if auditing is disabled
+func (s *Server) auditLog(r *http.Request) *audit.Log {
+	if s.runtime == nil || r.Method == "GET" || r.Method == "HEAD" {
+		return nil
+	}
+	return s.runtime.AuditLog()
+}
+
+// getAuditRecords returns the records of the audit log matching the since,
+// until and filters parameters
+func (s *Server) getAuditRecords(w http.ResponseWriter, r *http.Request) {
+	auditLog := s.runtime.AuditLog()
+	if auditLog == nil {
+		writeError(w, errors.Wrapf(libpod.ErrNotImplemented, "auditing is disabled"))
+		return
+	}
+	var query audit.Query
+	var err error
+	if query.Since, err = eventsTime(r.URL.Query().Get("since")); err != nil {
+		writeError(w, err)
+		return
+	}
+	if query.Until, err = eventsTime(r.URL.Query().Get("until")); err != nil {
+		writeError(w, err)
+		return
+	}
+	filterArgs, err := filters.FromJSON(r.URL.Query().Get("filters"))
+	if err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid filters: %v", err))
+		return
+	}
+	if err := filterArgs.Validate(auditFilters); err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "%v", err))
+		return
+	}
+	for key := range auditFilters {
+		for _, value := range filterArgs.Get(key) {
+			query.Filters = append(query.Filters, key+"="+value)
+		}
+	}
+	records, err := auditLog.Read(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, records)
+}
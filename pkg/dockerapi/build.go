@@ -0,0 +1,211 @@
+package dockerapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/types"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/buildcontext"
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
+	"github.com/projectatomic/buildah"
+	"github.com/projectatomic/buildah/imagebuildah"
+	"github.com/sirupsen/logrus"
+)
+
+// streamWriter streams the output of a build as JSON messages
+type streamWriter struct {
+	progress *progressWriter
+}
+
+// Write sends the output written as a stream message
+func (s streamWriter) Write(b []byte) (int, error) {
+	s.progress.send(jsonmessage.JSONMessage{Stream: string(b)})
+	return len(b), nil
+}
+
+// jsonQuery decodes the JSON query parameter key of the request into v
+func jsonQuery(r *http.Request, key string, v interface{}) error {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return errors.Wrapf(libpod.ErrInvalidArg, "invalid value of %s: %v", key, err)
+	}
+	return nil
+}
+
+// buildImage builds an image from the context of the request, either the
+// tar archive of its body or the git repository or URL of remote
+func (s *Server) buildImage(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	dockerfile := query.Get("dockerfile")
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	var (
+		buildArgs map[string]*string
+		labels    map[string]string
+	)
+	if err := jsonQuery(r, "buildargs", &buildArgs); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := jsonQuery(r, "labels", &labels); err != nil {
+		writeError(w, err)
+		return
+	}
+	quiet, err := boolQuery(r, "q")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	noCache, err := boolQuery(r, "nocache")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	pull, err := boolQuery(r, "pull")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	forceRm, err := boolQuery(r, "forcerm")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// Intermediate containers are removed unless rm is false
+	rm := true
+	if query.Get("rm") != "" {
+		if rm, err = boolQuery(r, "rm"); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	trust, err := s.requestTrust(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer trust.cleanup()
+
+	var buildContext *buildcontext.Context
+	if remote := query.Get("remote"); remote != "" {
+		// Only remote contexts can be named, not the files of the host
+		if !buildcontext.IsGitURL(remote) && !buildcontext.IsURL(remote) {
+			writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "remote %q must be a git repository or a URL", remote))
+			return
+		}
+		buildContext, err = buildcontext.New(remote, []string{dockerfile})
+	} else {
+		buildContext, err = buildcontext.FromArchive(r.Body, []string{dockerfile})
+	}
+	if err != nil {
+		writeError(w, errors.Wrapf(err, "error preparing build context"))
+		return
+	}
+	defer func() {
+		if err := buildContext.Cleanup(); err != nil {
+			logrus.Errorf("%v", err)
+		}
+	}()
+
+	iidFile, err := ioutil.TempFile("", "podman-build-iid")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	iidFile.Close()
+	defer os.Remove(iidFile.Name())
+
+	args := make(map[string]string)
+	for k, v := range buildArgs {
+		if v != nil {
+			args[k] = *v
+		}
+	}
+	var labelOpts []string
+	for k, v := range labels {
+		labelOpts = append(labelOpts, k+"="+v)
+	}
+	tags := query["t"]
+	output := ""
+	if len(tags) > 0 {
+		output = tags[0]
+		tags = tags[1:]
+	}
+	pullPolicy := imagebuildah.PullIfMissing
+	if pull {
+		pullPolicy = imagebuildah.PullAlways
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	progress := newProgressWriter(w)
+	stream := streamWriter{progress: progress}
+	var report, out = ioutil.Discard, ioutil.Discard
+	if !quiet {
+		report, out = stream, stream
+	}
+
+	options := imagebuildah.BuildOptions{
+		ContextDirectory:        buildContext.Dir,
+		PullPolicy:              pullPolicy,
+		Compression:             imagebuildah.Gzip,
+		SignaturePolicyPath:     trust.signaturePolicy(s.runtime),
+		SystemContext:           &types.SystemContext{SystemRegistriesConfPath: trust.registriesConfPath},
+		Args:                    args,
+		Output:                  output,
+		AdditionalTags:          tags,
+		Out:                     out,
+		Err:                     out,
+		ReportWriter:            report,
+		OutputFormat:            imagebuildah.Dockerv2ImageFormat,
+		IDMappingOptions:        &buildah.IDMappingOptions{HostUIDMapping: true, HostGIDMapping: true},
+		CommonBuildOpts:         &buildah.CommonBuildOptions{},
+		IIDFile:                 iidFile.Name(),
+		Labels:                  labelOpts,
+		Layers:                  true,
+		NoCache:                 noCache,
+		RemoveIntermediateCtrs:  rm,
+		ForceRmIntermediateCtrs: forceRm,
+	}
+	if rootless.IsRootless() {
+		options.Isolation = buildah.IsolationOCIRootless
+	}
+	// The dockerfile is in the context, not in the working directory of
+	// the service
+	dockerfilePath := filepath.Join(buildContext.Dir, filepath.Clean("/"+dockerfile))
+	if err := s.runtime.Build(r.Context(), options, nil, dockerfilePath); err != nil {
+		// The status is sent already, errors are reported in the stream
+		progress.send(jsonmessage.JSONMessage{
+			Error:        &jsonmessage.JSONError{Message: err.Error()},
+			ErrorMessage: err.Error(),
+		})
+		return
+	}
+
+	id, err := ioutil.ReadFile(iidFile.Name())
+	if err != nil {
+		progress.send(jsonmessage.JSONMessage{
+			Error:        &jsonmessage.JSONError{Message: err.Error()},
+			ErrorMessage: err.Error(),
+		})
+		return
+	}
+	imageID := strings.TrimPrefix(strings.TrimSpace(string(id)), "sha256:")
+	aux := json.RawMessage(`{"ID":"sha256:` + imageID + `"}`)
+	progress.send(jsonmessage.JSONMessage{Aux: &aux})
+	if len(imageID) > 12 {
+		imageID = imageID[:12]
+	}
+	progress.send(jsonmessage.JSONMessage{Stream: "Successfully built " + imageID + "\n"})
+}
@@ -0,0 +1,528 @@
+package dockerapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/inspect"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// containerFilters are the filters of the containers list
+var containerFilters = map[string]bool{
+	"id":     true,
+	"name":   true,
+	"label":  true,
+	"status": true,
+}
+
+// dockerState returns the Docker state of a container state
+func dockerState(state libpod.ContainerStatus) string {
+	switch state {
+	case libpod.ContainerStateConfigured, libpod.ContainerStateCreated:
+		return "created"
+	case libpod.ContainerStateRunning:
+		return "running"
+	case libpod.ContainerStatePaused:
+		return "paused"
+	case libpod.ContainerStateStopped:
+		return "exited"
+	default:
+		return "dead"
+	}
+}
+
+// dockerHealth returns the Docker health of the healthcheck results of a
+// container
+func dockerHealth(results *inspect.HealthCheckResults) *types.Health {
+	if results == nil {
+		return nil
+	}
+	health := &types.Health{
+		Status:        results.Status,
+		FailingStreak: results.FailingStreak,
+	}
+	for _, log := range results.Log {
+		health.Log = append(health.Log, &types.HealthcheckResult{
+			Start:    log.Start,
+			End:      log.End,
+			ExitCode: log.ExitCode,
+			Output:   log.Output,
+		})
+	}
+	return health
+}
+
+// dockerStatus returns the Docker status of a container, describing its
+// state for humans
+func dockerStatus(ctr *libpod.Container, state libpod.ContainerStatus) string {
+	switch state {
+	case libpod.ContainerStateRunning, libpod.ContainerStatePaused:
+		started, err := ctr.StartedTime()
+		if err != nil {
+			return "Up"
+		}
+		status := "Up " + units.HumanDuration(time.Since(started))
+		if state == libpod.ContainerStatePaused {
+			status += " (Paused)"
+		} else if health, err := ctr.HealthCheckStatus(); err == nil && health != "" {
+			status += " (" + health + ")"
+		}
+		return status
+	case libpod.ContainerStateStopped:
+		exitCode, _, _ := ctr.ExitCode()
+		finished, err := ctr.FinishedTime()
+		if err != nil {
+			return fmt.Sprintf("Exited (%d)", exitCode)
+		}
+		return fmt.Sprintf("Exited (%d) %s ago", exitCode, units.HumanDuration(time.Since(finished)))
+	case libpod.ContainerStateConfigured, libpod.ContainerStateCreated:
+		return "Created"
+	default:
+		return "Unknown"
+	}
+}
+
+// dockerPorts returns the published ports of a container
+func dockerPorts(ctr *libpod.Container) []types.Port {
+	ports := []types.Port{}
+	for _, p := range ctr.PortMappings() {
+		ports = append(ports, types.Port{
+			IP:          p.HostIP,
+			PrivatePort: uint16(p.ContainerPort),
+			PublicPort:  uint16(p.HostPort),
+			Type:        p.Protocol,
+		})
+	}
+	return ports
+}
+
+// lookupContainer returns the container named in the path of the request
+func (s *Server) lookupContainer(r *http.Request) (*libpod.Container, error) {
+	return s.runtime.LookupContainer(mux.Vars(r)["name"])
+}
+
+// listContainers lists the running containers, or all of them, matching
+// the filters
+func (s *Server) listContainers(w http.ResponseWriter, r *http.Request) {
+	all, err := boolQuery(r, "all")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	limit := -1
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if limit, err = strconv.Atoi(l); err != nil {
+			writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid limit %q", l))
+			return
+		}
+	}
+	filterArgs, err := filters.FromJSON(r.URL.Query().Get("filters"))
+	if err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid filters: %v", err))
+		return
+	}
+	if err := filterArgs.Validate(containerFilters); err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "%v", err))
+		return
+	}
+
+	ctrs, err := s.runtime.GetAllContainers()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// The newest containers are listed first
+	sort.Slice(ctrs, func(i, j int) bool {
+		return ctrs[i].CreatedTime().After(ctrs[j].CreatedTime())
+	})
+
+	result := []types.Container{}
+	for _, ctr := range ctrs {
+		if limit >= 0 && len(result) >= limit {
+			break
+		}
+		state, err := ctr.State()
+		if err != nil {
+			// The container may have been removed in the meantime
+			continue
+		}
+		if !all && state != libpod.ContainerStateRunning && !filterArgs.Contains("status") {
+			continue
+		}
+		if filterArgs.Contains("id") && !filterArgs.Match("id", ctr.ID()) {
+			continue
+		}
+		if filterArgs.Contains("name") && !filterArgs.Match("name", ctr.Name()) {
+			continue
+		}
+		if !filterArgs.MatchKVList("label", ctr.Labels()) {
+			continue
+		}
+		if filterArgs.Contains("status") && !filterArgs.ExactMatch("status", dockerState(state)) {
+			continue
+		}
+
+		imageID, imageName := ctr.Image()
+		summary := types.Container{
+			ID:      ctr.ID(),
+			Names:   []string{"/" + ctr.Name()},
+			Image:   imageName,
+			ImageID: imageID,
+			Command: strings.Join(ctr.Command(), " "),
+			Created: ctr.CreatedTime().Unix(),
+			Ports:   dockerPorts(ctr),
+			Labels:  ctr.Labels(),
+			State:   dockerState(state),
+			Status:  dockerStatus(ctr, state),
+		}
+		result = append(result, summary)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// inspectContainer returns the configuration and state of a container
+func (s *Server) inspectContainer(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	data, err := ctr.Inspect(false)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	state, err := ctr.State()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	spec := ctr.Spec()
+
+	_, imageName := ctr.Image()
+	result := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:      data.ID,
+			Created: data.Created.Format(time.RFC3339Nano),
+			Path:    data.Path,
+			Args:    data.Args,
+			State: &types.ContainerState{
+				Status:     dockerState(state),
+				Running:    data.State.Running,
+				Paused:     data.State.Paused,
+				OOMKilled:  data.State.OOMKilled,
+				Dead:       data.State.Dead,
+				Pid:        data.State.Pid,
+				ExitCode:   int(data.State.ExitCode),
+				Error:      data.State.Error,
+				StartedAt:  data.State.StartedAt.Format(time.RFC3339Nano),
+				FinishedAt: data.State.FinishedAt.Format(time.RFC3339Nano),
+				Health:     dockerHealth(data.State.Healthcheck),
+			},
+			Image:           data.ImageID,
+			ResolvConfPath:  data.ResolvConfPath,
+			HostnamePath:    data.HostnamePath,
+			HostsPath:       data.HostsPath,
+			LogPath:         data.LogPath,
+			Name:            "/" + data.Name,
+			RestartCount:    int(data.RestartCount),
+			Driver:          data.Driver,
+			Platform:        "linux",
+			MountLabel:      data.MountLabel,
+			ProcessLabel:    data.ProcessLabel,
+			AppArmorProfile: data.AppArmorProfile,
+			ExecIDs:         data.ExecIDs,
+			HostConfig: &container.HostConfig{
+				PortBindings: portBindings(ctr),
+				LogConfig:    container.LogConfig{Type: ctr.LogDriver(), Config: map[string]string{}},
+				RestartPolicy: container.RestartPolicy{
+					Name:              ctr.RestartPolicy(),
+					MaximumRetryCount: int(ctr.RestartRetries()),
+				},
+			},
+		},
+		Mounts: []types.MountPoint{},
+		Config: &container.Config{
+			Image:      imageName,
+			Cmd:        ctr.Command(),
+			Entrypoint: ctr.Entrypoint(),
+			Labels:     ctr.Labels(),
+			OpenStdin:  ctr.Stdin(),
+		},
+		NetworkSettings: &types.NetworkSettings{},
+	}
+	if data.SecurityConfig != nil {
+		result.HostConfig.Privileged = data.SecurityConfig.Privileged
+	}
+	if hc := ctr.HealthCheckConfig(); hc != nil {
+		result.Config.Healthcheck = &container.HealthConfig{
+			Test:     hc.Test,
+			Interval: hc.Interval,
+			Timeout:  hc.Timeout,
+			Retries:  hc.Retries,
+		}
+	}
+	if spec != nil {
+		result.Config.Hostname = spec.Hostname
+		if spec.Process != nil {
+			result.Config.Env = spec.Process.Env
+			result.Config.WorkingDir = spec.Process.Cwd
+			result.Config.Tty = spec.Process.Terminal
+		}
+	}
+	for _, m := range data.Mounts {
+		result.Mounts = append(result.Mounts, types.MountPoint{
+			Type:        "bind",
+			Source:      m.Source,
+			Destination: m.Destination,
+			RW:          !hasOption(m.Options, "ro"),
+		})
+	}
+	if n := data.NetworkSettings; n != nil {
+		result.NetworkSettings.Gateway = n.Gateway
+		result.NetworkSettings.IPAddress = n.IPAddress
+		result.NetworkSettings.IPPrefixLen = n.IPPrefixLen
+		result.NetworkSettings.MacAddress = n.MacAddress
+		result.NetworkSettings.SandboxKey = n.SandboxKey
+		result.NetworkSettings.Ports = portBindings(ctr)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// portBindings returns the published ports of a container by container port
+func portBindings(ctr *libpod.Container) nat.PortMap {
+	ports := nat.PortMap{}
+	for _, p := range ctr.PortMappings() {
+		port := nat.Port(fmt.Sprintf("%d/%s", p.ContainerPort, p.Protocol))
+		ports[port] = append(ports[port], nat.PortBinding{
+			HostIP:   p.HostIP,
+			HostPort: strconv.Itoa(int(p.HostPort)),
+		})
+	}
+	return ports
+}
+
+// hasOption returns whether a mount has an option
+func hasOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// startContainer starts a container, which is not modified if it is
+// already running
+func (s *Server) startContainer(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	state, err := ctr.State()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if state == libpod.ContainerStateRunning {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if err := ctr.Start(r.Context()); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// stopTimeout returns the timeout of the t query parameter of the request,
+// or the stop timeout of the container
+func stopTimeout(r *http.Request, ctr *libpod.Container) (uint, error) {
+	t := r.URL.Query().Get("t")
+	if t == "" {
+		return ctr.StopTimeout(), nil
+	}
+	timeout, err := strconv.ParseUint(t, 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(libpod.ErrInvalidArg, "invalid timeout %q", t)
+	}
+	return uint(timeout), nil
+}
+
+// stopContainer stops a container, which is not modified if it is not
+// running
+func (s *Server) stopContainer(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	timeout, err := stopTimeout(r, ctr)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	state, err := ctr.State()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if state != libpod.ContainerStateRunning && state != libpod.ContainerStatePaused {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if err := ctr.StopWithTimeout(timeout); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restartContainer stops a container if it is running, and starts it
+func (s *Server) restartContainer(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	timeout, err := stopTimeout(r, ctr)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := ctr.RestartWithTimeout(r.Context(), timeout); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// killContainer sends a signal, SIGKILL by default, to a running container
+func (s *Server) killContainer(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	sig := "KILL"
+	if v := r.URL.Query().Get("signal"); v != "" {
+		sig = v
+	}
+	parsed, err := signal.ParseSignal(sig)
+	if err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "%v", err))
+		return
+	}
+	if err := ctr.Kill(uint(parsed)); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// waitContainer waits for a container to exit, or to be removed with
+// condition=removed, and returns its exit code
+func (s *Server) waitContainer(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var exitCode int32
+	switch condition := r.URL.Query().Get("condition"); condition {
+	case "", "not-running":
+		exitCode, err = ctr.Wait()
+	case "removed":
+		exitCode, err = ctr.WaitRemoved()
+	default:
+		err = errors.Wrapf(libpod.ErrInvalidArg, "invalid value %q of condition, must be not-running or removed", condition)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, container.ContainerWaitOKBody{StatusCode: int64(exitCode)})
+}
+
+// removeContainer removes a container, which is stopped first with force
+func (s *Server) removeContainer(w http.ResponseWriter, r *http.Request) {
+	force, err := boolQuery(r, "force")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.runtime.RemoveContainer(r.Context(), ctr, force); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// healthCheckRunResponse is the response to running the healthcheck of a
+// container
+type healthCheckRunResponse struct {
+	// Status is healthy if the healthcheck passed, unhealthy if it failed
+	Status string `json:"Status"`
+	// Health holds the results of the healthchecks of the container,
+	// including this one
+	Health *inspect.HealthCheckResults `json:"Health"`
+}
+
+// runHealthCheck runs the healthcheck of a running container once and
+// records its result, as podman healthcheck run does
+func (s *Server) runHealthCheck(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	status, err := s.runtime.HealthCheck(ctr.ID())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp := healthCheckRunResponse{Status: libpod.HealthCheckHealthy}
+	if status == libpod.HealthCheckFailure {
+		resp.Status = libpod.HealthCheckUnhealthy
+	}
+	if resp.Health, err = ctr.HealthCheckResults(); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// containerStatsHistory returns the stats history of a container, oldest
+// samples first
+func (s *Server) containerStatsHistory(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	history, err := ctr.StatsHistory()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
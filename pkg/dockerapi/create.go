@@ -0,0 +1,380 @@
+package dockerapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/containers/image/manifest"
+	"github.com/containers/libpod/libpod"
+	ann "github.com/containers/libpod/pkg/annotations"
+	"github.com/containers/libpod/pkg/inspect"
+	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/signal"
+	"github.com/opencontainers/selinux/go-selinux/label"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultShmSize is the size of /dev/shm when the request does not set one,
+// as for podman create
+const defaultShmSize = 65536 * 1024
+
+// defaultEnv is the environment containers get in addition to the one of
+// their image
+var defaultEnv = map[string]string{
+	"PATH": "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+	"TERM": "xterm",
+}
+
+// createRequest is the body of a container create request, the container
+// configuration with the host and networking configurations
+type createRequest struct {
+	container.Config
+	HostConfig       *container.HostConfig
+	NetworkingConfig *network.NetworkingConfig
+}
+
+// createContainer creates a container from a local image
+func (s *Server) createContainer(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid container configuration: %v", err))
+		return
+	}
+	if req.HostConfig == nil {
+		req.HostConfig = &container.HostConfig{}
+	}
+	if req.Image == "" {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "an image must be given"))
+		return
+	}
+
+	// Docker does not pull the images of the containers it creates
+	img, err := s.runtime.ImageRuntime().NewFromLocal(req.Image)
+	if err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrNoSuchImage, "%s: %v", req.Image, err))
+		return
+	}
+	data, err := img.Inspect(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	createConfig, err := s.toCreateConfig(r.URL.Query().Get("name"), &req, img.Names(), data)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	runtimeSpec, err := cc.CreateConfigToOCISpec(createConfig)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	options, err := createConfig.GetContainerCreateOptions(s.runtime)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ctr, err := s.runtime.NewContainer(r.Context(), runtimeSpec, options...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	createConfigJSON, err := json.Marshal(createConfig)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := ctr.AddArtifact("create-config", createConfigJSON); err != nil {
+		writeError(w, err)
+		return
+	}
+	logrus.Debugf("new container created %s", ctr.ID())
+
+	writeJSON(w, http.StatusCreated, container.ContainerCreateCreatedBody{ID: ctr.ID(), Warnings: []string{}})
+}
+
+// toCreateConfig converts a create request to the configuration podman
+// create builds from its options
+func (s *Server) toCreateConfig(name string, req *createRequest, imageNames []string, data *inspect.ImageData) (*cc.CreateConfig, error) {
+	hc := req.HostConfig
+
+	// The entrypoint of the request replaces the one of the image, and its
+	// command then, as Docker does
+	entrypoint := []string(req.Entrypoint)
+	cmd := []string(req.Cmd)
+	if req.Entrypoint == nil {
+		entrypoint = data.ContainerConfig.Entrypoint
+		if len(cmd) == 0 {
+			cmd = data.ContainerConfig.Cmd
+		}
+	}
+	command := append(append([]string{}, entrypoint...), cmd...)
+	if len(command) == 0 {
+		return nil, errors.Wrapf(libpod.ErrInvalidArg, "no command specified in the request nor as CMD or ENTRYPOINT in image %s", req.Image)
+	}
+
+	env := make(map[string]string)
+	for k, v := range defaultEnv {
+		env[k] = v
+	}
+	for _, e := range append(append([]string{}, data.ContainerConfig.Env...), req.Env...) {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) > 1 {
+			env[kv[0]] = kv[1]
+		} else {
+			env[kv[0]] = ""
+		}
+	}
+
+	user := req.User
+	if user == "" {
+		user = data.ContainerConfig.User
+	}
+	workDir := req.WorkingDir
+	if workDir == "" {
+		workDir = data.ContainerConfig.WorkingDir
+	}
+	if workDir == "" {
+		workDir = "/"
+	}
+
+	stopSignal := syscall.SIGTERM
+	signalString := data.ContainerConfig.StopSignal
+	if req.StopSignal != "" {
+		signalString = req.StopSignal
+	}
+	if signalString != "" {
+		sig, err := signal.ParseSignal(signalString)
+		if err != nil {
+			return nil, errors.Wrapf(libpod.ErrInvalidArg, "%v", err)
+		}
+		stopSignal = sig
+	}
+	stopTimeout := uint(libpod.CtrRemoveTimeout)
+	if req.StopTimeout != nil {
+		stopTimeout = uint(*req.StopTimeout)
+	}
+
+	var expose []string
+	for p := range req.ExposedPorts {
+		expose = append(expose, string(p))
+	}
+	var publish []string
+	for p, bindings := range hc.PortBindings {
+		for _, b := range bindings {
+			publish = append(publish, fmt.Sprintf("%s:%s:%s", b.HostIP, b.HostPort, p))
+		}
+	}
+	portBindings, err := cc.ExposedPorts(expose, publish, hc.PublishAllPorts, data.ContainerConfig.ExposedPorts)
+	if err != nil {
+		return nil, errors.Wrapf(libpod.ErrInvalidArg, "%v", err)
+	}
+
+	netMode := string(hc.NetworkMode)
+	if netMode == "" || netMode == "default" {
+		netMode = "bridge"
+		if rtc := s.runtime.GetConfig(); rtc.NetworkMode != "" {
+			netMode = rtc.NetworkMode
+		}
+	}
+	netMode, networkOptions := cc.ParseNetworkMode(netMode)
+
+	idmappings, err := util.ParseIDMapping(string(hc.UsernsMode), nil, nil, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := map[string]string{
+		ann.ContainerType: "sandbox",
+		ann.TTY:           "false",
+	}
+	if req.Tty {
+		annotations[ann.TTY] = "true"
+	}
+	for k, v := range data.Annotations {
+		annotations[k] = v
+	}
+
+	var tmpfs []string
+	for dest, opts := range hc.Tmpfs {
+		if opts != "" {
+			dest += ":" + opts
+		}
+		tmpfs = append(tmpfs, dest)
+	}
+	var ulimits []string
+	for _, u := range hc.Ulimits {
+		ulimits = append(ulimits, u.String())
+	}
+	shmSize := hc.ShmSize
+	if shmSize == 0 {
+		shmSize = defaultShmSize
+	}
+	swappiness := -1
+	if hc.MemorySwappiness != nil {
+		swappiness = int(*hc.MemorySwappiness)
+	}
+	disableOomKiller := false
+	if hc.OomKillDisable != nil {
+		disableOomKiller = *hc.OomKillDisable
+	}
+
+	healthCheck := dockerHealthCheck(req.Healthcheck, data.HealthCheck)
+
+	imageName := req.Image
+	if len(imageNames) > 0 {
+		imageName = imageNames[0]
+	}
+	config := &cc.CreateConfig{
+		Runtime:           s.runtime,
+		Annotations:       annotations,
+		BuiltinImgVolumes: data.ContainerConfig.Volumes,
+		ImageLabels:       data.ContainerConfig.Labels,
+		CapAdd:            hc.CapAdd,
+		CapDrop:           hc.CapDrop,
+		CgroupParent:      hc.CgroupParent,
+		Command:           command,
+		Detach:            true,
+		DNSOpt:            hc.DNSOptions,
+		DNSSearch:         hc.DNSSearch,
+		DNSServers:        hc.DNS,
+		Entrypoint:        entrypoint,
+		Env:               env,
+		GroupAdd:          hc.GroupAdd,
+		HealthCheck:       healthCheck,
+		Hostname:          req.Hostname,
+		HostAdd:           hc.ExtraHosts,
+		IDMappings:        idmappings,
+		Image:             imageName,
+		ImageID:           data.ID,
+		Interactive:       req.OpenStdin,
+		Labels:            cc.MergeLabels(data.ContainerConfig.Labels, req.Labels),
+		LogDriver:         hc.LogConfig.Type,
+		Name:              name,
+		Network:           netMode,
+		IpcMode:           container.IpcMode(hc.IpcMode),
+		NetMode:           container.NetworkMode(netMode),
+		NetworkOptions:    networkOptions,
+		UtsMode:           container.UTSMode(hc.UTSMode),
+		PidMode:           container.PidMode(hc.PidMode),
+		Privileged:        hc.Privileged,
+		PortBindings:      portBindings,
+		ReadOnlyRootfs:    hc.ReadonlyRootfs,
+		Resources: cc.CreateResourceConfig{
+			BlkioWeight:       hc.BlkioWeight,
+			CPUShares:         uint64(hc.CPUShares),
+			CPUPeriod:         uint64(hc.CPUPeriod),
+			CPUQuota:          hc.CPUQuota,
+			CPUs:              float64(hc.NanoCPUs) / 1e9,
+			CPUsetCPUs:        hc.CpusetCpus,
+			CPUsetMems:        hc.CpusetMems,
+			DisableOomKiller:  disableOomKiller,
+			KernelMemory:      hc.KernelMemory,
+			Memory:            hc.Memory,
+			MemoryReservation: hc.MemoryReservation,
+			MemorySwap:        hc.MemorySwap,
+			MemorySwappiness:  swappiness,
+			OomScoreAdj:       hc.OomScoreAdj,
+			PidsLimit:         hc.PidsLimit,
+			ShmSize:           shmSize,
+			Ulimit:            ulimits,
+		},
+		Rm:          hc.AutoRemove,
+		StopSignal:  stopSignal,
+		StopTimeout: stopTimeout,
+		Sysctl:      hc.Sysctls,
+		Tmpfs:       tmpfs,
+		Tty:         req.Tty,
+		User:        user,
+		UsernsMode:  container.UsernsMode(hc.UsernsMode),
+		Volumes:     hc.Binds,
+		VolumesFrom: hc.VolumesFrom,
+		WorkDir:     workDir,
+	}
+	if err := parseSecurityOpt(config, hc.SecurityOpt); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseSecurityOpt applies the security options of a request, given as
+// Docker does, and sets the SELinux labels of the container
+// dockerHealthCheck returns the healthcheck of a container, the one of its
+// image with the fields set in the request replaced, as Docker does. A NONE
+// test disables it.
+func dockerHealthCheck(req *container.HealthConfig, image *manifest.Schema2HealthConfig) *manifest.Schema2HealthConfig {
+	var hc manifest.Schema2HealthConfig
+	if image != nil {
+		hc = *image
+	}
+	if req != nil {
+		if len(req.Test) > 0 {
+			hc.Test = req.Test
+		}
+		if req.Interval > 0 {
+			hc.Interval = req.Interval
+		}
+		if req.Timeout > 0 {
+			hc.Timeout = req.Timeout
+		}
+		if req.Retries > 0 {
+			hc.Retries = req.Retries
+		}
+	}
+	if len(hc.Test) == 0 || hc.Test[0] == "NONE" {
+		return nil
+	}
+	return &hc
+}
+
+func parseSecurityOpt(config *cc.CreateConfig, securityOpts []string) error {
+	var (
+		labelOpts []string
+		err       error
+	)
+	if config.PidMode.IsHost() || config.IpcMode.IsHost() {
+		labelOpts = append(labelOpts, label.DisableSecOpt()...)
+	}
+	for _, opt := range securityOpts {
+		if opt == "no-new-privileges" {
+			config.NoNewPrivs = true
+			continue
+		}
+		// Older clients separate the options from their values by colons
+		con := strings.SplitN(opt, "=", 2)
+		if len(con) != 2 {
+			con = strings.SplitN(opt, ":", 2)
+		}
+		if len(con) != 2 {
+			return errors.Wrapf(libpod.ErrInvalidArg, "invalid security option %q", opt)
+		}
+		switch con[0] {
+		case "label":
+			labelOpts = append(labelOpts, con[1])
+		case "apparmor":
+			config.ApparmorProfile = con[1]
+		case "seccomp":
+			config.SeccompProfilePath = con[1]
+		default:
+			return errors.Wrapf(libpod.ErrInvalidArg, "invalid security option %q", opt)
+		}
+	}
+
+	if config.Privileged {
+		if config.PrivilegedProfile, err = cc.NewPrivilegedProfile(nil); err != nil {
+			return err
+		}
+		config.ApparmorProfile = ""
+		labelOpts = label.DisableSecOpt()
+	}
+	config.ProcessLabel, config.MountLabel, err = label.InitLabels(labelOpts)
+	return err
+}
@@ -0,0 +1,150 @@
+package dockerapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DrainResult is the answer to a request to drain the service
+type DrainResult struct {
+	// InFlight is the number of requests in progress the service waits for
+	InFlight int
+	// Timeout is the number of seconds the service waits for them, 0 if
+	// it waits for all of them
+	Timeout uint
+}
+
+// SetDrainTimeout sets how long the service waits for the requests in
+// progress once terminated, 0 to wait for all of them
+func (s *Server) SetDrainTimeout(timeout time.Duration) {
+	s.drainLock.Lock()
+	defer s.drainLock.Unlock()
+	s.drainTimeout = timeout
+}
+
+// Drain stops the service from accepting requests, and makes Serve return
+// once the requests in progress, such as pulls, builds and attach sessions,
+// are done, or after timeout if it is not 0. It returns the number of
+// requests in progress.
+func (s *Server) Drain(timeout time.Duration) (int, error) {
+	s.drainLock.Lock()
+	defer s.drainLock.Unlock()
+	if s.draining {
+		return 0, errors.Wrapf(libpod.ErrInvalidArg, "the service is already draining")
+	}
+	s.draining = true
+	s.drainTimeout = timeout
+	close(s.drainChan)
+	if s.requests == 0 {
+		close(s.idle)
+	}
+	logrus.Infof("Draining the API service, waiting for %d requests in progress", s.requests)
+	return s.requests, nil
+}
+
+// startRequest counts a request in progress, and returns false if the service
+// is draining and rejects it
+func (s *Server) startRequest() bool {
+	s.drainLock.Lock()
+	defer s.drainLock.Unlock()
+	if s.draining {
+		return false
+	}
+	s.requests++
+	return true
+}
+
+// endRequest counts the end of a request in progress
+func (s *Server) endRequest() {
+	s.drainLock.Lock()
+	defer s.drainLock.Unlock()
+	s.requests--
+	if s.draining && s.requests == 0 {
+		close(s.idle)
+	}
+}
+
+// drainContext returns a context done with ctx or once the service drains,
+// for the requests streaming until the client goes away, such as events
+func (s *Server) drainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-s.drainChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// waitDrained closes the listener and the idle connections of server, and
+// waits for the requests in progress up to the drain timeout
+func (s *Server) waitDrained(server *http.Server) error {
+	s.drainLock.Lock()
+	timeout := s.drainTimeout
+	s.drainLock.Unlock()
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	// Shutdown does not wait for the connections of attach sessions, which
+	// are taken over, the requests in progress count them
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- server.Shutdown(ctx)
+	}()
+	select {
+	case <-s.idle:
+		if err := <-shutdownErr; err != nil {
+			return errors.Wrapf(err, "error shutting down the API server")
+		}
+		logrus.Infof("Drained the API service")
+	case <-expired:
+		s.drainLock.Lock()
+		logrus.Warnf("%d API requests still in progress after draining for %s, exiting", s.requests, timeout)
+		s.drainLock.Unlock()
+		cancel()
+		if err := server.Close(); err != nil {
+			return errors.Wrapf(err, "error closing the API server")
+		}
+	}
+	return nil
+}
+
+// drain drains the service, waiting for the requests in progress up to the
+// timeout query parameter, in seconds, or the timeout of the service
+func (s *Server) drain(w http.ResponseWriter, r *http.Request) {
+	s.drainLock.Lock()
+	timeout := s.drainTimeout
+	s.drainLock.Unlock()
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		seconds, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid value %q of timeout", value))
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	requests, err := s.Drain(timeout)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// The request to drain is still in progress
+	writeJSON(w, http.StatusAccepted, DrainResult{
+		InFlight: requests - 1,
+		Timeout:  uint(timeout / time.Second),
+	})
+}
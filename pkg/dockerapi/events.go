@@ -0,0 +1,216 @@
+package dockerapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/events"
+	dockerevents "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// eventFilters are the filters of the events
+var eventFilters = map[string]bool{
+	"container": true,
+	"event":     true,
+	"image":     true,
+	"label":     true,
+	"pod":       true,
+	"type":      true,
+}
+
+// dockerActions are the Docker actions of the events whose status differs
+var dockerActions = map[events.Status]string{
+	events.Died: "die",
+}
+
+// eventsCursorHeader is the header of the responses to non-streaming events
+// requests with the cursor of the last event, which the next page of events
+// is read after
+const eventsCursorHeader = "Libpod-Events-Cursor"
+
+// getEvents streams the events matching the filters, from the since time
+// until the until time or until the client goes away. With stream=false, it
+// returns the past events, paginated with limit, offset and cursor.
+func (s *Server) getEvents(w http.ResponseWriter, r *http.Request) {
+	// The stream ends when the service drains, rather than delaying it
+	ctx, cancel := s.drainContext(r.Context())
+	defer cancel()
+	options := events.ReadOptions{
+		Context:      ctx,
+		EventChannel: make(chan *events.Event),
+	}
+	var err error
+	if options.Stream, err = streamQuery(r); err != nil {
+		writeError(w, err)
+		return
+	}
+	query := r.URL.Query()
+	for _, q := range []struct {
+		key   string
+		value *int
+	}{{"limit", &options.Limit}, {"offset", &options.Offset}} {
+		v := query.Get(q.key)
+		if v == "" {
+			continue
+		}
+		if *q.value, err = strconv.Atoi(v); err != nil || *q.value < 0 {
+			writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid %s %q", q.key, v))
+			return
+		}
+		if options.Stream {
+			writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "%s requires stream=false", q.key))
+			return
+		}
+	}
+	if options.Cursor = query.Get("cursor"); options.Cursor != "" && options.Stream {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "cursor requires stream=false"))
+		return
+	}
+	if options.Since, err = eventsTime(query.Get("since")); err != nil {
+		writeError(w, err)
+		return
+	}
+	if options.Until, err = eventsTime(query.Get("until")); err != nil {
+		writeError(w, err)
+		return
+	}
+	filterArgs, err := filters.FromJSON(query.Get("filters"))
+	if err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid filters: %v", err))
+		return
+	}
+	if err := filterArgs.Validate(eventFilters); err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "%v", err))
+		return
+	}
+	for key := range eventFilters {
+		for _, value := range filterArgs.Get(key) {
+			if key == "event" && value == dockerActions[events.Died] {
+				value = string(events.Died)
+			}
+			options.Filters = append(options.Filters, key+"="+value)
+		}
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- s.runtime.Events(options)
+	}()
+	if !options.Stream {
+		s.writeEvents(w, options.EventChannel, readErr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	encoder := json.NewEncoder(w)
+	for event := range options.EventChannel {
+		if err := encoder.Encode(dockerEvent(event)); err != nil {
+			logrus.Debugf("Unable to write event to the API client: %v", err)
+			continue
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if err := <-readErr; err != nil {
+		logrus.Errorf("Unable to read events: %v", err)
+	}
+}
+
+// writeEvents writes the events read without streaming once all are read, so
+// that errors reading them, such as invalid cursors, are returned as such
+func (s *Server) writeEvents(w http.ResponseWriter, eventChannel <-chan *events.Event, readErr <-chan error) {
+	var read []*events.Event
+	for event := range eventChannel {
+		read = append(read, event)
+	}
+	if err := <-readErr; err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(read) > 0 {
+		w.Header().Set(eventsCursorHeader, read[len(read)-1].Cursor)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	for _, event := range read {
+		if err := encoder.Encode(dockerEvent(event)); err != nil {
+			logrus.Debugf("Unable to write event to the API client: %v", err)
+			return
+		}
+	}
+}
+
+// eventsTime parses the since and until times of events, which Docker
+// clients give as unix timestamps with optional nanoseconds, such as
+// 1560000000.000000000
+func eventsTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	split := strings.SplitN(value, ".", 2)
+	sec, err := strconv.ParseInt(split[0], 10, 64)
+	if err != nil {
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t, nil
+		}
+		return time.Time{}, errors.Wrapf(libpod.ErrInvalidArg, "invalid time %q", value)
+	}
+	var nsec int64
+	if len(split) == 2 {
+		// Pad the fraction to nanoseconds
+		fraction := (split[1] + "000000000")[:9]
+		if nsec, err = strconv.ParseInt(fraction, 10, 64); err != nil {
+			return time.Time{}, errors.Wrapf(libpod.ErrInvalidArg, "invalid time %q", value)
+		}
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// dockerEvent returns the Docker message of an event. The name and image of
+// the object are attributes of its actor, along with those of the event.
+func dockerEvent(event *events.Event) dockerevents.Message {
+	action := string(event.Status)
+	if dockerAction, ok := dockerActions[event.Status]; ok {
+		action = dockerAction
+	}
+	attributes := make(map[string]string)
+	for key, value := range event.Attributes {
+		attributes[key] = value
+	}
+	if event.Name != "" {
+		attributes["name"] = event.Name
+	}
+	if event.Image != "" {
+		attributes["image"] = event.Image
+	}
+	msg := dockerevents.Message{
+		Type:   string(event.Type),
+		Action: action,
+		Actor: dockerevents.Actor{
+			ID:         event.ID,
+			Attributes: attributes,
+		},
+		Scope:    "local",
+		Time:     event.Time.Unix(),
+		TimeNano: event.Time.UnixNano(),
+	}
+	if event.Type == events.Container {
+		msg.Status = action
+		msg.ID = event.ID
+		msg.From = event.Image
+	}
+	return msg
+}
@@ -0,0 +1,268 @@
+package dockerapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/storage/pkg/stringid"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// execFailedExitCode is the exit code of the exec instances whose process
+// could not be run, as Docker reports it
+const execFailedExitCode = 126
+
+// execInstance is an exec instance created by a client, run once started
+type execInstance struct {
+	id     string
+	ctr    *libpod.Container
+	config types.ExecConfig
+
+	started  bool
+	running  bool
+	exitCode *int
+}
+
+// execInspect is the inspect data of an exec instance
+type execInspect struct {
+	ID            string
+	Running       bool
+	ExitCode      *int
+	ProcessConfig execProcessConfig
+	OpenStdin     bool
+	OpenStderr    bool
+	OpenStdout    bool
+	CanRemove     bool
+	ContainerID   string
+	DetachKeys    string
+	Pid           int
+}
+
+// execProcessConfig is the process of an exec instance
+type execProcessConfig struct {
+	Tty        bool     `json:"tty"`
+	Entrypoint string   `json:"entrypoint"`
+	Arguments  []string `json:"arguments"`
+	Privileged bool     `json:"privileged"`
+	User       string   `json:"user"`
+}
+
+// execExitCode returns the exit code of an exec process from the error
+// libpod returns once it ends
+func execExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := errors.Cause(err).(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	return execFailedExitCode
+}
+
+// addExecInstance registers an exec instance. The instances of containers
+// no longer running are dropped, as Docker does when containers stop.
+func (s *Server) addExecInstance(instance *execInstance) {
+	s.execLock.Lock()
+	defer s.execLock.Unlock()
+	for id, other := range s.execs {
+		if other.running {
+			continue
+		}
+		if state, err := other.ctr.State(); err != nil || state != libpod.ContainerStateRunning {
+			delete(s.execs, id)
+		}
+	}
+	s.execs[instance.id] = instance
+}
+
+// lookupExecInstance returns the exec instance of the request
+func (s *Server) lookupExecInstance(r *http.Request) (*execInstance, error) {
+	id := mux.Vars(r)["id"]
+	s.execLock.Lock()
+	defer s.execLock.Unlock()
+	instance, ok := s.execs[id]
+	if !ok {
+		return nil, errors.Wrapf(errNoSuchExec, "%s", id)
+	}
+	return instance, nil
+}
+
+// createExec creates an exec instance running a command in a container,
+// once started
+func (s *Server) createExec(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var config types.ExecConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "error decoding exec configuration: %v", err))
+		return
+	}
+	if len(config.Cmd) == 0 {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "no command given"))
+		return
+	}
+	state, err := ctr.State()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if state != libpod.ContainerStateRunning {
+		writeError(w, errors.Wrapf(libpod.ErrCtrStateInvalid, "container %s is not running", ctr.ID()))
+		return
+	}
+
+	instance := &execInstance{
+		id:     stringid.GenerateNonCryptoID(),
+		ctr:    ctr,
+		config: config,
+	}
+	s.addExecInstance(instance)
+	writeJSON(w, http.StatusCreated, types.IDResponse{ID: instance.id})
+}
+
+// startExec runs the command of an exec instance, attaching the connection
+// of the request to its streams unless detached. The output of processes
+// without a terminal is multiplexed, as Docker does.
+func (s *Server) startExec(w http.ResponseWriter, r *http.Request) {
+	instance, err := s.lookupExecInstance(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var check types.ExecStartCheck
+	if err := json.NewDecoder(r.Body).Decode(&check); err != nil && err != io.EOF {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "error decoding exec start configuration: %v", err))
+		return
+	}
+
+	s.execLock.Lock()
+	started := instance.started
+	instance.started = true
+	instance.running = !started
+	s.execLock.Unlock()
+	if started {
+		writeError(w, errors.Wrapf(libpod.ErrCtrStateInvalid, "exec instance %s was started already", instance.id))
+		return
+	}
+
+	config := instance.config
+	if check.Detach {
+		go s.runExec(instance, &libpod.AttachStreams{})
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	conn, input, err := hijack(w, r)
+	if err != nil {
+		s.endExec(instance, execFailedExitCode)
+		writeError(w, err)
+		return
+	}
+	defer conn.Close()
+
+	streams := &libpod.AttachStreams{
+		OutputStream: nopWriteCloser{conn},
+		ErrorStream:  nopWriteCloser{conn},
+		AttachOutput: config.AttachStdout,
+		AttachError:  config.AttachStderr,
+	}
+	if !config.Tty {
+		streams.OutputStream = nopWriteCloser{stdcopy.NewStdWriter(conn, stdcopy.Stdout)}
+		streams.ErrorStream = nopWriteCloser{stdcopy.NewStdWriter(conn, stdcopy.Stderr)}
+	}
+	if config.AttachStdin {
+		// The runtime gets a pipe rather than the connection: the
+		// process would otherwise not end before the client closes its
+		// input
+		stdin, stdinWriter, err := os.Pipe()
+		if err != nil {
+			s.endExec(instance, execFailedExitCode)
+			logrus.Errorf("Error creating the input pipe of exec instance %s: %v", instance.id, err)
+			return
+		}
+		defer stdin.Close()
+		defer stdinWriter.Close()
+		go io.Copy(stdinWriter, input)
+		streams.InputStream = stdin
+		streams.AttachInput = true
+	}
+	s.runExec(instance, streams)
+}
+
+// runExec runs the command of an exec instance until it exits
+func (s *Server) runExec(instance *execInstance, streams *libpod.AttachStreams) {
+	config := instance.config
+	err := instance.ctr.Exec(config.Tty, config.Privileged, config.Env, config.Cmd, config.User, streams)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*exec.ExitError); !ok {
+			logrus.Errorf("Error running exec instance %s in container %s: %v", instance.id, instance.ctr.ID(), err)
+		}
+	}
+	s.endExec(instance, execExitCode(err))
+}
+
+// endExec records the exit code of an exec instance
+func (s *Server) endExec(instance *execInstance, exitCode int) {
+	s.execLock.Lock()
+	defer s.execLock.Unlock()
+	instance.running = false
+	instance.exitCode = &exitCode
+}
+
+// inspectExec returns the details of an exec instance, clients read its exit
+// code once its streams end
+func (s *Server) inspectExec(w http.ResponseWriter, r *http.Request) {
+	instance, err := s.lookupExecInstance(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	config := instance.config
+	s.execLock.Lock()
+	inspect := execInspect{
+		ID:       instance.id,
+		Running:  instance.running,
+		ExitCode: instance.exitCode,
+		ProcessConfig: execProcessConfig{
+			Tty:        config.Tty,
+			Entrypoint: config.Cmd[0],
+			Arguments:  config.Cmd[1:],
+			Privileged: config.Privileged,
+			User:       config.User,
+		},
+		OpenStdin:   config.AttachStdin,
+		OpenStderr:  config.AttachStderr,
+		OpenStdout:  config.AttachStdout,
+		CanRemove:   !instance.running && instance.exitCode != nil,
+		ContainerID: instance.ctr.ID(),
+		DetachKeys:  config.DetachKeys,
+	}
+	s.execLock.Unlock()
+	writeJSON(w, http.StatusOK, inspect)
+}
+
+// resizeExec accepts the resizes of the terminal of an exec instance, which
+// libpod cannot apply: the terminal keeps the size it was created with
+func (s *Server) resizeExec(w http.ResponseWriter, r *http.Request) {
+	instance, err := s.lookupExecInstance(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	logrus.Debugf("Ignoring resize of the terminal of exec instance %s", instance.id)
+	w.WriteHeader(http.StatusOK)
+}
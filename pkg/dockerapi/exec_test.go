@@ -0,0 +1,45 @@
+package dockerapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecExitCode(t *testing.T) {
+	assert.Equal(t, 0, execExitCode(nil))
+
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	assert.Equal(t, 3, execExitCode(errors.Wrapf(err, "error exec")))
+
+	assert.Equal(t, execFailedExitCode, execExitCode(errors.Wrapf(libpod.ErrCtrStateInvalid, "not running")))
+}
+
+func TestExecInstances(t *testing.T) {
+	s := NewServer(nil)
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/exec/foo/json", nil),
+		httptest.NewRequest("POST", "/v1.40/exec/foo/start", strings.NewReader(`{"Detach":true}`)),
+		httptest.NewRequest("POST", "/exec/foo/resize?h=24&w=80", nil),
+	} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, req.URL.Path)
+	}
+
+	// Exec instances run once
+	s.execs["foo"] = &execInstance{id: "foo", started: true}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("POST", "/exec/foo/start", strings.NewReader(`{"Detach":true}`)))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("POST", "/exec/foo/resize?h=24&w=80", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,361 @@
+package dockerapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/libpod/pkg/auth"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/go-connections/nat"
+	"github.com/gorilla/mux"
+	"github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// lookupImage returns the local image of the request path
+func (s *Server) lookupImage(r *http.Request) (*image.Image, error) {
+	name := mux.Vars(r)["name"]
+	img, err := s.runtime.ImageRuntime().NewFromLocal(name)
+	if err != nil {
+		return nil, errors.Wrapf(libpod.ErrNoSuchImage, "%s: %v", name, err)
+	}
+	return img, nil
+}
+
+// listImages lists the local images
+func (s *Server) listImages(w http.ResponseWriter, r *http.Request) {
+	all, err := boolQuery(r, "all")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	images, err := s.runtime.ImageRuntime().GetImages()
+	if err != nil {
+		writeError(w, errors.Wrapf(err, "error getting images"))
+		return
+	}
+	result := []types.ImageSummary{}
+	for _, img := range images {
+		// Intermediate images of builds are only listed with all
+		if !all && img.Dangling() {
+			if isParent, err := img.IsParent(); img.IsBuildCache() || (err == nil && isParent) {
+				continue
+			}
+		}
+		summary := types.ImageSummary{
+			ID:          "sha256:" + img.ID(),
+			Created:     img.Created().Unix(),
+			RepoTags:    img.Names(),
+			RepoDigests: img.RepoDigests(),
+			Labels:      map[string]string{},
+			Containers:  -1,
+			SharedSize:  -1,
+		}
+		if summary.RepoTags == nil {
+			summary.RepoTags = []string{"<none>:<none>"}
+		}
+		if labels, err := img.Labels(r.Context()); err == nil && labels != nil {
+			summary.Labels = labels
+		}
+		if size, err := img.Size(r.Context()); err == nil {
+			summary.Size = int64(*size)
+			summary.VirtualSize = int64(*size)
+		}
+		if parent, err := img.GetParent(); err == nil && parent != nil {
+			summary.ParentID = "sha256:" + parent.ID()
+		}
+		result = append(result, summary)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// imageConfig returns the Docker configuration of an OCI image
+// configuration
+func imageConfig(c *v1.ImageConfig) *container.Config {
+	config := &container.Config{
+		User:       c.User,
+		Env:        c.Env,
+		Cmd:        c.Cmd,
+		Entrypoint: c.Entrypoint,
+		Volumes:    c.Volumes,
+		WorkingDir: c.WorkingDir,
+		Labels:     c.Labels,
+		StopSignal: c.StopSignal,
+	}
+	if len(c.ExposedPorts) > 0 {
+		config.ExposedPorts = make(nat.PortSet)
+		for p := range c.ExposedPorts {
+			config.ExposedPorts[nat.Port(p)] = struct{}{}
+		}
+	}
+	return config
+}
+
+// inspectImage returns the details of an image
+func (s *Server) inspectImage(w http.ResponseWriter, r *http.Request) {
+	img, err := s.lookupImage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	data, err := img.Inspect(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	config := imageConfig(data.ContainerConfig)
+	result := types.ImageInspect{
+		ID:              "sha256:" + data.ID,
+		RepoTags:        data.RepoTags,
+		RepoDigests:     data.RepoDigests,
+		Comment:         data.Comment,
+		ContainerConfig: config,
+		DockerVersion:   data.Version,
+		Author:          data.Author,
+		Config:          config,
+		Architecture:    data.Architecture,
+		Os:              data.Os,
+		Size:            data.Size,
+		VirtualSize:     data.VirtualSize,
+		RootFS:          types.RootFS{Type: "layers"},
+	}
+	if result.RepoTags == nil {
+		result.RepoTags = []string{}
+	}
+	if data.Created != nil {
+		result.Created = data.Created.Format(time.RFC3339Nano)
+	}
+	if parent, err := img.GetParent(); err == nil && parent != nil {
+		result.Parent = "sha256:" + parent.ID()
+	}
+	if data.GraphDriver != nil {
+		result.GraphDriver = types.GraphDriverData{Name: data.GraphDriver.Name, Data: data.GraphDriver.Data}
+	}
+	if data.RootFS != nil {
+		result.RootFS.Type = data.RootFS.Type
+		for _, layer := range data.RootFS.Layers {
+			result.RootFS.Layers = append(result.RootFS.Layers, layer.String())
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// progressWriter streams the progress of a pull as JSON messages, the way
+// Docker clients display them
+type progressWriter struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+	flusher http.Flusher
+}
+
+// newProgressWriter returns a progress writer for the response
+func newProgressWriter(w http.ResponseWriter) *progressWriter {
+	p := &progressWriter{encoder: json.NewEncoder(w)}
+	if flusher, ok := w.(http.Flusher); ok {
+		p.flusher = flusher
+	}
+	return p
+}
+
+// send writes a message to the client
+func (p *progressWriter) send(msg jsonmessage.JSONMessage) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if err := p.encoder.Encode(msg); err != nil {
+		logrus.Debugf("Unable to write progress to the API client: %v", err)
+		return
+	}
+	if p.flusher != nil {
+		p.flusher.Flush()
+	}
+}
+
+// Write sends each line written as a status message
+func (p *progressWriter) Write(b []byte) (int, error) {
+	for _, line := range strings.Split(string(b), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			p.send(jsonmessage.JSONMessage{Status: line})
+		}
+	}
+	return len(b), nil
+}
+
+// pullImage pulls an image from a registry, streaming its progress
+func (s *Server) pullImage(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("fromImage")
+	if name == "" {
+		// Importing images from a source is not supported
+		writeError(w, errors.Wrapf(libpod.ErrNotImplemented, "only images from registries can be pulled, fromImage must be given"))
+		return
+	}
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		if strings.HasPrefix(tag, "sha256:") {
+			name += "@" + tag
+		} else {
+			name += ":" + tag
+		}
+	}
+
+	creds, err := auth.ParseRegistryAuth(r.Header.Get("X-Registry-Auth"))
+	if err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid X-Registry-Auth: %v", err))
+		return
+	}
+	trust, err := s.requestTrust(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer trust.cleanup()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	progress := newProgressWriter(w)
+	dockerOptions := &image.DockerRegistryOptions{
+		DockerRegistryCreds: creds,
+		RegistriesConfPath:  trust.registriesConfPath,
+	}
+	img, err := s.runtime.ImageRuntime().New(r.Context(), name, trust.signaturePolicy(s.runtime), "", progress, dockerOptions, image.SigningOptions{}, true, false)
+	if err != nil {
+		// The status is sent already, errors are reported in the stream
+		progress.send(jsonmessage.JSONMessage{
+			Error:        &jsonmessage.JSONError{Message: err.Error()},
+			ErrorMessage: err.Error(),
+		})
+		return
+	}
+	progress.send(jsonmessage.JSONMessage{Status: "Pulled " + name, ID: img.ID()})
+}
+
+// pushImage pushes a local image to its registry, streaming its progress
+func (s *Server) pushImage(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		name += ":" + tag
+	}
+	img, err := s.runtime.ImageRuntime().NewFromLocal(name)
+	if err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrNoSuchImage, "%s: %v", name, err))
+		return
+	}
+	creds, err := auth.ParseRegistryAuth(r.Header.Get("X-Registry-Auth"))
+	if err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid X-Registry-Auth: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	progress := newProgressWriter(w)
+	dockerOptions := &image.DockerRegistryOptions{DockerRegistryCreds: creds}
+	if err := img.PushImageToHeuristicDestination(r.Context(), name, "", "", s.runtime.GetConfig().SignaturePolicyPath, progress, false, image.SigningOptions{}, dockerOptions, false, nil); err != nil {
+		// The status is sent already, errors are reported in the stream
+		progress.send(jsonmessage.JSONMessage{
+			Error:        &jsonmessage.JSONError{Message: err.Error()},
+			ErrorMessage: err.Error(),
+		})
+		return
+	}
+	progress.send(jsonmessage.JSONMessage{Status: "Pushed " + name, ID: img.ID()})
+}
+
+// tagImage adds a name to an image
+func (s *Server) tagImage(w http.ResponseWriter, r *http.Request) {
+	img, err := s.lookupImage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	repo := r.URL.Query().Get("repo")
+	if repo == "" {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "repo must be given"))
+		return
+	}
+	tag := r.URL.Query().Get("tag")
+	if tag == "" {
+		tag = "latest"
+	}
+	if err := img.TagImage(repo + ":" + tag); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// removeImage removes an image, or the name of the request from an image
+// with several names
+func (s *Server) removeImage(w http.ResponseWriter, r *http.Request) {
+	force, err := boolQuery(r, "force")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	img, err := s.lookupImage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	msg, err := s.runtime.RemoveImage(r.Context(), img, force)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var item types.ImageDeleteResponseItem
+	if strings.HasPrefix(msg, "Untagged: ") {
+		item.Untagged = strings.TrimPrefix(msg, "Untagged: ")
+	} else {
+		item.Deleted = "sha256:" + msg
+	}
+	writeJSON(w, http.StatusOK, []types.ImageDeleteResponseItem{item})
+}
+
+// pruneBuildCache removes the images and cache mounts of the build cache
+func (s *Server) pruneBuildCache(w http.ResponseWriter, r *http.Request) {
+	filterArgs, err := filters.FromJSON(r.URL.Query().Get("filters"))
+	if err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid filters: %v", err))
+		return
+	}
+	if err := filterArgs.Validate(map[string]bool{"until": true}); err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "%v", err))
+		return
+	}
+	var (
+		filterFuncs []image.ResultFilter
+		earliest    time.Time
+	)
+	for _, value := range filterArgs.Get("until") {
+		until, err := util.ParseInputTime(value)
+		if err != nil {
+			writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "%v", err))
+			return
+		}
+		filterFuncs = append(filterFuncs, image.CreatedBeforeFilter(until))
+		if earliest.IsZero() || until.Before(earliest) {
+			earliest = until
+		}
+	}
+
+	_, reclaimed, err := s.runtime.PruneBuildCache(r.Context(), filterFuncs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	mountsReclaimed, err := s.runtime.PruneBuildCacheMounts(earliest)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	reclaimed += mountsReclaimed
+	writeJSON(w, http.StatusOK, types.BuildCachePruneReport{SpaceReclaimed: reclaimed})
+}
@@ -0,0 +1,297 @@
+package dockerapi
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// logFollowInterval is how often the log of a followed container is read
+// again once its end was reached
+const logFollowInterval = 250 * time.Millisecond
+
+// logLine is a line of the log of a container, written by conmon as
+// TIMESTAMP STREAM TAG MESSAGE, the tag being P for the parts of a line and F
+// for its end
+type logLine struct {
+	time    time.Time
+	stream  string
+	partial bool
+	message string
+}
+
+// parseLogLine parses a line of the log of a container, without its newline
+func parseLogLine(line string) (*logLine, error) {
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) < 3 {
+		return nil, errors.Errorf("invalid log line %q", line)
+	}
+	t, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid time of log line %q", line)
+	}
+	parsed := &logLine{time: t, stream: fields[1], partial: fields[2] == "P"}
+	if len(fields) == 4 {
+		parsed.message = fields[3]
+	}
+	return parsed, nil
+}
+
+// logsOptions are the query parameters of a logs request
+type logsOptions struct {
+	follow     bool
+	stdout     bool
+	stderr     bool
+	timestamps bool
+	since      time.Time
+	until      time.Time
+	// tail is the number of lines written from the end of the log, all
+	// of them if negative
+	tail int
+}
+
+// unixTimeQuery returns the query parameter key of the request, a Unix time in
+// seconds with an optional fraction, or the zero time if it is not set
+func unixTimeQuery(r *http.Request, key string) (time.Time, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" || value == "0" {
+		return time.Time{}, nil
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(libpod.ErrInvalidArg, "invalid value %q of %s", value, key)
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), nil
+}
+
+// parseLogsOptions returns the options of a logs request
+func parseLogsOptions(r *http.Request) (*logsOptions, error) {
+	opts := &logsOptions{tail: -1}
+	for key, value := range map[string]*bool{
+		"follow":     &opts.follow,
+		"stdout":     &opts.stdout,
+		"stderr":     &opts.stderr,
+		"timestamps": &opts.timestamps,
+	} {
+		b, err := boolQuery(r, key)
+		if err != nil {
+			return nil, err
+		}
+		*value = b
+	}
+	if !opts.stdout && !opts.stderr {
+		return nil, errors.Wrapf(libpod.ErrInvalidArg, "you must choose at least one stream")
+	}
+	var err error
+	if opts.since, err = unixTimeQuery(r, "since"); err != nil {
+		return nil, err
+	}
+	if opts.until, err = unixTimeQuery(r, "until"); err != nil {
+		return nil, err
+	}
+	if tail := r.URL.Query().Get("tail"); tail != "" && tail != "all" {
+		n, err := strconv.Atoi(tail)
+		if err != nil || n < 0 {
+			return nil, errors.Wrapf(libpod.ErrInvalidArg, "invalid value %q of tail", tail)
+		}
+		opts.tail = n
+	}
+	return opts, nil
+}
+
+// logWriter writes the lines of the log of a container to a client, joining
+// the parts of lines, multiplexing stdout and stderr unless the container has
+// a terminal
+type logWriter struct {
+	opts    *logsOptions
+	stdout  io.Writer
+	stderr  io.Writer
+	pending map[string]string
+}
+
+func newLogWriter(w io.Writer, opts *logsOptions, tty bool) *logWriter {
+	lw := &logWriter{opts: opts, stdout: w, stderr: w, pending: make(map[string]string)}
+	if !tty {
+		lw.stdout = stdcopy.NewStdWriter(w, stdcopy.Stdout)
+		lw.stderr = stdcopy.NewStdWriter(w, stdcopy.Stderr)
+	}
+	return lw
+}
+
+// write writes a line of the log if it matches the options, once it is
+// complete
+func (lw *logWriter) write(line *logLine) error {
+	if line.partial {
+		lw.pending[line.stream] += line.message
+		return nil
+	}
+	message := lw.pending[line.stream] + line.message + "\n"
+	delete(lw.pending, line.stream)
+	if !lw.opts.since.IsZero() && line.time.Before(lw.opts.since) {
+		return nil
+	}
+	if !lw.opts.until.IsZero() && line.time.After(lw.opts.until) {
+		return nil
+	}
+	if lw.opts.timestamps {
+		message = line.time.Format(time.RFC3339Nano) + " " + message
+	}
+	switch line.stream {
+	case "stdout":
+		if lw.opts.stdout {
+			_, err := io.WriteString(lw.stdout, message)
+			return err
+		}
+	case "stderr":
+		if lw.opts.stderr {
+			_, err := io.WriteString(lw.stderr, message)
+			return err
+		}
+	}
+	return nil
+}
+
+// readLogLines reads the complete lines of reader, calling fn for each of
+// them, and returns the part of the last line not ending with a newline yet
+func readLogLines(reader *bufio.Reader, partial string, fn func(*logLine) error) (string, error) {
+	for {
+		data, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return partial + data, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		line, err := parseLogLine(strings.TrimSuffix(partial+data, "\n"))
+		partial = ""
+		if err != nil {
+			logrus.Debugf("Skipping log line: %v", err)
+			continue
+		}
+		if err := fn(line); err != nil {
+			return "", err
+		}
+	}
+}
+
+// containerLogs writes the log of a container to the client, and follows it
+// while the container runs with follow
+func (s *Server) containerLogs(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	opts, err := parseLogsOptions(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var files []*os.File
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+	var readers []io.Reader
+	for _, path := range ctr.LogPaths() {
+		file, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The container was never started
+				continue
+			}
+			writeError(w, errors.Wrapf(err, "unable to read container log file"))
+			return
+		}
+		files = append(files, file)
+		readers = append(readers, file)
+	}
+
+	spec := ctr.Spec()
+	tty := spec != nil && spec.Process != nil && spec.Process.Terminal
+	w.Header().Set("Content-Type", "application/vnd.docker.raw-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	lw := newLogWriter(w, opts, tty)
+
+	// Only the last lines are written with tail
+	var lines []*logLine
+	reader := bufio.NewReader(io.MultiReader(readers...))
+	partial, err := readLogLines(reader, "", func(line *logLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("Unable to read the log of container %s: %v", ctr.ID(), err)
+		return
+	}
+	if opts.tail >= 0 && len(lines) > opts.tail {
+		lines = lines[len(lines)-opts.tail:]
+	}
+	for _, line := range lines {
+		if err := lw.write(line); err != nil {
+			return
+		}
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+	// The current log is the last file, the others are rotated segments
+	if !opts.follow || len(files) == 0 || files[len(files)-1].Name() != ctr.LogPath() {
+		return
+	}
+	file := files[len(files)-1]
+	reader.Reset(file)
+	ctx, cancel := s.drainContext(r.Context())
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(logFollowInterval):
+		}
+		if partial, err = readLogLines(reader, partial, lw.write); err != nil {
+			logrus.Debugf("Unable to write the log of container %s to the API client: %v", ctr.ID(), err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		state, err := ctr.State()
+		if err != nil || (state != libpod.ContainerStateRunning && state != libpod.ContainerStatePaused) {
+			return
+		}
+		if err := rewindRotatedLog(file); err != nil {
+			logrus.Errorf("Unable to read the log of container %s: %v", ctr.ID(), err)
+			return
+		}
+		reader.Reset(file)
+	}
+}
+
+// rewindRotatedLog seeks back to the start of a log file truncated since it
+// was read
+func rewindRotatedLog(file *os.File) error {
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < offset {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package dockerapi
+
+import (
+	"bufio"
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLog = `2019-06-01T10:00:00.000000000+00:00 stdout F first
+2019-06-01T10:00:01.000000000+00:00 stderr P sec
+2019-06-01T10:00:01.500000000+00:00 stderr F ond
+2019-06-01T10:00:02.000000000+00:00 stdout F
+2019-06-01T10:00:03.000000000+00:00 stdout F third
+2019-06-01T10:00:04.000000000+00:00 stdout F incomp`
+
+func TestParseLogLine(t *testing.T) {
+	line, err := parseLogLine("2019-06-01T10:00:00.000000000+00:00 stdout F hello world")
+	require.NoError(t, err)
+	assert.Equal(t, "stdout", line.stream)
+	assert.False(t, line.partial)
+	assert.Equal(t, "hello world", line.message)
+	assert.Equal(t, int64(1559383200), line.time.Unix())
+
+	_, err = parseLogLine("not a log line")
+	assert.Error(t, err)
+}
+
+func TestParseLogsOptions(t *testing.T) {
+	opts, err := parseLogsOptions(httptest.NewRequest("GET", "/containers/foo/logs?stdout=1&tail=2&since=1559383201.5", nil))
+	require.NoError(t, err)
+	assert.True(t, opts.stdout)
+	assert.False(t, opts.stderr)
+	assert.Equal(t, 2, opts.tail)
+	assert.Equal(t, time.Unix(1559383201, 500000000), opts.since)
+
+	opts, err = parseLogsOptions(httptest.NewRequest("GET", "/containers/foo/logs?stderr=true&tail=all", nil))
+	require.NoError(t, err)
+	assert.Equal(t, -1, opts.tail)
+
+	for _, query := range []string{"", "stdout=1&tail=-1", "stdout=1&since=yesterday"} {
+		_, err := parseLogsOptions(httptest.NewRequest("GET", "/containers/foo/logs?"+query, nil))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestLogWriter(t *testing.T) {
+	var lines []*logLine
+	partial, err := readLogLines(bufio.NewReader(strings.NewReader(testLog)), "", func(line *logLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, lines, 5)
+	assert.True(t, strings.HasSuffix(partial, "incomp"))
+
+	// Lines are multiplexed, and their parts joined
+	var out bytes.Buffer
+	lw := newLogWriter(&out, &logsOptions{stdout: true, stderr: true}, false)
+	for _, line := range lines {
+		require.NoError(t, lw.write(line))
+	}
+	var stdout, stderr bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, &stderr, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "first\n\nthird\n", stdout.String())
+	assert.Equal(t, "second\n", stderr.String())
+
+	// The lines of the streams not requested, and those before since, are
+	// skipped
+	out.Reset()
+	lw = newLogWriter(&out, &logsOptions{stdout: true, since: lines[1].time, timestamps: true}, true)
+	for _, line := range lines {
+		require.NoError(t, lw.write(line))
+	}
+	assert.Equal(t, "2019-06-01T10:00:02Z \n2019-06-01T10:00:03Z third\n", out.String())
+}
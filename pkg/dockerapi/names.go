@@ -0,0 +1,43 @@
+package dockerapi
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// listNameReservations lists the reservations of names, sorted by name
+func (s *Server) listNameReservations(w http.ResponseWriter, r *http.Request) {
+	reservations, err := s.runtime.NameReservations()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reservations)
+}
+
+// reserveName reserves a name for a container or pod created later, and
+// returns the reservation with its token
+func (s *Server) reserveName(w http.ResponseWriter, r *http.Request) {
+	reservation, err := s.runtime.ReserveName(mux.Vars(r)["name"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, reservation)
+}
+
+// releaseName releases the reservation of a name made with the token
+// parameter, or whatever its token with force
+func (s *Server) releaseName(w http.ResponseWriter, r *http.Request) {
+	force, err := boolQuery(r, "force")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.runtime.ReleaseName(mux.Vars(r)["name"], r.URL.Query().Get("token"), force); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,134 @@
+package dockerapi
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/containers/libpod/libpod"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// cniIPAM is the part of the IPAM configuration of the host-local plugin
+// which describes the subnets of a network
+type cniIPAM struct {
+	IPAM struct {
+		Type    string `json:"type"`
+		Subnet  string `json:"subnet"`
+		Gateway string `json:"gateway"`
+		Ranges  [][]struct {
+			Subnet  string `json:"subnet"`
+			Gateway string `json:"gateway"`
+		} `json:"ranges"`
+	} `json:"ipam"`
+}
+
+// networkResource returns the Docker description of a CNI network, its
+// driver being the type of its first plugin
+func networkResource(list *libcni.NetworkConfigList) types.NetworkResource {
+	result := types.NetworkResource{
+		Name:       list.Name,
+		ID:         list.Name,
+		Scope:      "local",
+		Driver:     list.Plugins[0].Network.Type,
+		Containers: map[string]types.EndpointResource{},
+		Options:    map[string]string{},
+		Labels:     map[string]string{},
+	}
+	var ipam cniIPAM
+	if err := json.Unmarshal(list.Plugins[0].Bytes, &ipam); err == nil {
+		result.IPAM.Driver = ipam.IPAM.Type
+		if ipam.IPAM.Subnet != "" {
+			result.IPAM.Config = append(result.IPAM.Config, network.IPAMConfig{Subnet: ipam.IPAM.Subnet, Gateway: ipam.IPAM.Gateway})
+		}
+		for _, set := range ipam.IPAM.Ranges {
+			for _, r := range set {
+				result.IPAM.Config = append(result.IPAM.Config, network.IPAMConfig{Subnet: r.Subnet, Gateway: r.Gateway})
+			}
+		}
+	}
+	return result
+}
+
+// listNetworks lists the CNI networks
+func (s *Server) listNetworks(w http.ResponseWriter, r *http.Request) {
+	networks, _, err := s.runtime.CNINetworks()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	result := []types.NetworkResource{}
+	for _, list := range networks {
+		result = append(result, networkResource(list))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	writeJSON(w, http.StatusOK, result)
+}
+
+// inspectNetwork returns the details of a CNI network
+func (s *Server) inspectNetwork(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	networks, _, err := s.runtime.CNINetworks()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	list, ok := networks[name]
+	if !ok {
+		writeError(w, errors.Wrapf(libpod.ErrNoSuchNetwork, "%s", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, networkResource(list))
+}
+
+// createNetwork creates a CNI bridge network, with the first subnet and
+// gateway of the IPAM configuration of the request if any
+func (s *Server) createNetwork(w http.ResponseWriter, r *http.Request) {
+	var req types.NetworkCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid network: %v", err))
+		return
+	}
+	if req.Driver != "" && req.Driver != "bridge" {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "network driver %q is not supported, only bridge is", req.Driver))
+		return
+	}
+	config := libpod.NetworkConfig{Name: req.Name, Internal: req.Internal}
+	if req.IPAM != nil && len(req.IPAM.Config) > 0 {
+		ipamConfig := req.IPAM.Config[0]
+		if ipamConfig.Subnet != "" {
+			_, subnet, err := net.ParseCIDR(ipamConfig.Subnet)
+			if err != nil {
+				writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid subnet %q", ipamConfig.Subnet))
+				return
+			}
+			config.Subnet = subnet
+		}
+		if ipamConfig.Gateway != "" {
+			if config.Gateway = net.ParseIP(ipamConfig.Gateway); config.Gateway == nil {
+				writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid gateway %q", ipamConfig.Gateway))
+				return
+			}
+		}
+	}
+	created, err := s.runtime.CreateNetwork(config)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, types.NetworkCreateResponse{ID: created.Name})
+}
+
+// removeNetwork removes a CNI network created by podman
+func (s *Server) removeNetwork(w http.ResponseWriter, r *http.Request) {
+	if err := s.runtime.RemoveNetwork(mux.Vars(r)["name"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,15 @@
+// +build !linux
+
+package dockerapi
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// peerCredentials returns an error, the credentials of peers are only
+// available on Linux
+func peerCredentials(c *net.UnixConn) (peer, error) {
+	return peer{}, errors.Errorf("the credentials of API clients are only available on Linux")
+}
@@ -0,0 +1,176 @@
+package dockerapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/signature"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/registries"
+	"github.com/containers/libpod/pkg/trust"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ReloadResult is the outcome of the reload of the configuration of the
+// service
+type ReloadResult struct {
+	libpod.ConfigReload
+	// SignaturePolicy and RegistriesConf are the files enforced for the
+	// pulls of the service, empty if they do not exist
+	SignaturePolicy string
+	RegistriesConf  string
+}
+
+// serviceTrust is a copy of the signature policy and registries
+// configuration enforced for the pulls of the service, validated when it is
+// taken: edits of the files only apply once the service reloads them, and
+// invalid ones never apply. The paths are empty for the files that do not
+// exist.
+type serviceTrust struct {
+	signaturePolicySource string
+	registriesConfSource  string
+	signaturePolicyPath   string
+	registriesConfPath    string
+}
+
+// copyTrustFile copies a configuration file to dir and validates the copy,
+// which is the file then used. It returns "" if the file does not exist.
+func copyTrustFile(src, dir string, validate func(path string) error) (string, error) {
+	content, err := ioutil.ReadFile(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "error reading %s", src)
+	}
+	path := filepath.Join(dir, filepath.Base(src))
+	if err := ioutil.WriteFile(path, content, 0600); err != nil {
+		return "", errors.Wrapf(err, "error copying %s", src)
+	}
+	if err := validate(path); err != nil {
+		return "", errors.Wrapf(libpod.ErrInvalidArg, "invalid %s: %v", src, err)
+	}
+	return path, nil
+}
+
+// loadTrust copies and validates the signature policy at policyPath, or the
+// default one, and the registries configuration of the service
+func (s *Server) loadTrust(policyPath string) (*serviceTrust, error) {
+	if s.trustDir == "" {
+		dir, err := ioutil.TempDir("", "podman-service-trust")
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating directory for the trust configuration of the service")
+		}
+		s.trustDir = dir
+	}
+	// The copies replaced by a reload are kept until the service exits,
+	// requests in progress may still read them
+	dir, err := ioutil.TempDir(s.trustDir, "trust")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating directory for the trust configuration of the service")
+	}
+
+	if policyPath == "" {
+		policyPath = trust.DefaultPolicyPath
+	}
+	t := &serviceTrust{
+		signaturePolicySource: policyPath,
+		registriesConfSource:  registries.SystemRegistriesConfPath(),
+	}
+	t.signaturePolicyPath, err = copyTrustFile(t.signaturePolicySource, dir, func(path string) error {
+		_, err := signature.NewPolicyFromFile(path)
+		return err
+	})
+	if err == nil {
+		t.registriesConfPath, err = copyTrustFile(t.registriesConfSource, dir, func(path string) error {
+			_, err := registries.GetRegistriesFromFile(path)
+			return err
+		})
+	}
+	if err != nil {
+		if rmErr := os.RemoveAll(dir); rmErr != nil {
+			logrus.Errorf("Error removing %s: %v", dir, rmErr)
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// LoadTrust loads the signature policy and registries configuration the
+// pulls of the service enforce. Until it is called, the files are read as
+// pulls use them.
+func (s *Server) LoadTrust() error {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	t, err := s.loadTrust(s.runtime.GetConfig().SignaturePolicyPath)
+	if err != nil {
+		return err
+	}
+	s.setTrust(t)
+	return nil
+}
+
+// setTrust replaces the trust configuration of the service
+func (s *Server) setTrust(t *serviceTrust) {
+	s.trustLock.Lock()
+	defer s.trustLock.Unlock()
+	s.trust = t
+}
+
+// serviceTrust returns the trust configuration of the service, nil if it
+// is not loaded
+func (s *Server) serviceTrust() *serviceTrust {
+	s.trustLock.RLock()
+	defer s.trustLock.RUnlock()
+	return s.trust
+}
+
+// Reload reloads the configuration of the service: the options of
+// libpod.conf that apply without a restart, and the signature policy and
+// registries configuration enforced for pulls. The configuration is
+// validated first, and rejected as a whole if it is invalid, in which case
+// the service keeps its current one.
+func (s *Server) Reload() (*ReloadResult, error) {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	var t *serviceTrust
+	reload, err := s.runtime.ReloadConfig(func(config *libpod.RuntimeConfig) error {
+		var err error
+		t, err = s.loadTrust(config.SignaturePolicyPath)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reloading the configuration, the current one is kept")
+	}
+	s.setTrust(t)
+	return &ReloadResult{
+		ConfigReload:    *reload,
+		SignaturePolicy: t.signaturePolicySource,
+		RegistriesConf:  t.registriesConfSource,
+	}, nil
+}
+
+// cleanupTrust removes the copies of the trust configuration of the service
+func (s *Server) cleanupTrust() {
+	if s.trustDir == "" {
+		return
+	}
+	if err := os.RemoveAll(s.trustDir); err != nil {
+		logrus.Errorf("Error removing the trust configuration of the service in %s: %v", s.trustDir, err)
+	}
+}
+
+// reload reloads the configuration of the service
+func (s *Server) reload(w http.ResponseWriter, r *http.Request) {
+	result, err := s.Reload()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
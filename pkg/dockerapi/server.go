@@ -0,0 +1,285 @@
+// Package dockerapi serves the Docker Engine API on top of libpod, so that
+// Docker clients and their libraries can manage its containers, images and
+// networks over a unix socket, and run commands in containers. Named volumes,
+// which libpod does not have, are not served.
+package dockerapi
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/events"
+	"github.com/docker/docker/api/types"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	// APIVersion is the version of the Docker Engine API served
+	APIVersion = "1.40"
+	// MinAPIVersion is the oldest version of the API clients can use
+	MinAPIVersion = "1.24"
+	// DefaultSocketPath is the socket the service listens on by default
+	// when podman runs as root
+	DefaultSocketPath = "/run/podman/podman.sock"
+)
+
+var (
+	// errNoSuchVolume is returned for volumes, libpod has no named volumes
+	errNoSuchVolume = errors.New("no such volume")
+	// errNoSuchExec is returned for the exec instances the service does not
+	// know
+	errNoSuchExec = errors.New("no such exec instance")
+	// errDraining is returned for the requests received while the service
+	// drains
+	errDraining = errors.New("the service is draining, it no longer accepts requests")
+)
+
+// Server serves the Docker Engine API for a libpod runtime
+type Server struct {
+	runtime *libpod.Runtime
+	router  *mux.Router
+
+	// resizers resize the terminals of the containers attached to, by
+	// container ID
+	resizers   map[string]chan remotecommand.TerminalSize
+	resizeLock sync.Mutex
+	// attachIdleTimeout detaches the attach sessions idle for that long,
+	// if not 0
+	attachIdleTimeout time.Duration
+
+	// execs are the exec instances created by clients, by ID
+	execs    map[string]*execInstance
+	execLock sync.Mutex
+
+	// clientTrust accepts the trust configuration clients send for the
+	// images pulled on their behalf
+	clientTrust bool
+
+	// trust is the signature policy and registries configuration enforced
+	// for pulls, replaced by Reload
+	trust      *serviceTrust
+	trustLock  sync.RWMutex
+	trustDir   string
+	reloadLock sync.Mutex
+
+	// requests counts the requests in progress, which a drain waits for
+	// until idle is closed, or until drainTimeout if it is not 0.
+	// drainChan is closed when the service starts to drain.
+	requests     int
+	draining     bool
+	drainTimeout time.Duration
+	drainChan    chan struct{}
+	idle         chan struct{}
+	drainLock    sync.Mutex
+}
+
+// NewServer returns a server of the Docker Engine API for the runtime
+func NewServer(runtime *libpod.Runtime) *Server {
+	s := &Server{
+		runtime:   runtime,
+		router:    mux.NewRouter(),
+		resizers:  make(map[string]chan remotecommand.TerminalSize),
+		execs:     make(map[string]*execInstance),
+		drainChan: make(chan struct{}),
+		idle:      make(chan struct{}),
+	}
+	// Clients prefix the paths with the version of the API they use
+	s.registerRoutes(s.router.PathPrefix("/v{version:[0-9.]+}").Subrouter())
+	s.registerRoutes(s.router)
+	return s
+}
+
+// SetClientTrust sets whether the server accepts the signature policy and
+// registries configuration clients send for the images pulled on their
+// behalf, instead of enforcing its own
+func (s *Server) SetClientTrust(clientTrust bool) {
+	s.clientTrust = clientTrust
+}
+
+// registerRoutes registers the endpoints of the API on a router
+func (s *Server) registerRoutes(r *mux.Router) {
+	r.HandleFunc("/_ping", s.ping).Methods("GET", "HEAD")
+	r.HandleFunc("/version", s.version).Methods("GET")
+	r.HandleFunc("/info", s.info).Methods("GET")
+	r.HandleFunc("/auth", s.authenticate).Methods("POST")
+	r.HandleFunc("/events", s.getEvents).Methods("GET")
+	r.HandleFunc("/libpod/audit", s.getAuditRecords).Methods("GET")
+	r.HandleFunc("/libpod/reload", s.reload).Methods("POST")
+	r.HandleFunc("/libpod/drain", s.drain).Methods("POST")
+	r.HandleFunc("/libpod/names", s.listNameReservations).Methods("GET")
+	r.HandleFunc("/libpod/names/{name}", s.reserveName).Methods("POST")
+	r.HandleFunc("/libpod/names/{name}", s.releaseName).Methods("DELETE")
+
+	r.HandleFunc("/containers/json", s.listContainers).Methods("GET")
+	r.HandleFunc("/containers/create", s.createContainer).Methods("POST")
+	r.HandleFunc("/containers/{name}/json", s.inspectContainer).Methods("GET")
+	r.HandleFunc("/containers/{name}/start", s.startContainer).Methods("POST")
+	r.HandleFunc("/containers/{name}/stop", s.stopContainer).Methods("POST")
+	r.HandleFunc("/containers/{name}/restart", s.restartContainer).Methods("POST")
+	r.HandleFunc("/containers/{name}/kill", s.killContainer).Methods("POST")
+	r.HandleFunc("/containers/{name}/wait", s.waitContainer).Methods("POST")
+	r.HandleFunc("/containers/{name}/attach", s.attachContainer).Methods("POST")
+	r.HandleFunc("/containers/{name}/resize", s.resizeContainer).Methods("POST")
+	r.HandleFunc("/containers/{name}/stats", s.containerStats).Methods("GET")
+	r.HandleFunc("/containers/{name}/logs", s.containerLogs).Methods("GET")
+	r.HandleFunc("/containers/{name}/exec", s.createExec).Methods("POST")
+	r.HandleFunc("/containers/{name}", s.removeContainer).Methods("DELETE")
+	r.HandleFunc("/libpod/containers/{name}/stats", s.libpodContainerStats).Methods("GET")
+	r.HandleFunc("/libpod/containers/{name}/stats/history", s.containerStatsHistory).Methods("GET")
+	r.HandleFunc("/libpod/containers/{name}/healthcheck", s.runHealthCheck).Methods("POST")
+
+	r.HandleFunc("/exec/{id}/start", s.startExec).Methods("POST")
+	r.HandleFunc("/exec/{id}/resize", s.resizeExec).Methods("POST")
+	r.HandleFunc("/exec/{id}/json", s.inspectExec).Methods("GET")
+
+	r.HandleFunc("/images/json", s.listImages).Methods("GET")
+	r.HandleFunc("/images/create", s.pullImage).Methods("POST")
+	r.HandleFunc("/images/{name:.+}/json", s.inspectImage).Methods("GET")
+	r.HandleFunc("/images/{name:.+}/tag", s.tagImage).Methods("POST")
+	r.HandleFunc("/images/{name:.+}/push", s.pushImage).Methods("POST")
+	r.HandleFunc("/images/{name:.+}", s.removeImage).Methods("DELETE")
+	r.HandleFunc("/build", s.buildImage).Methods("POST")
+	r.HandleFunc("/build/prune", s.pruneBuildCache).Methods("POST")
+
+	r.HandleFunc("/networks", s.listNetworks).Methods("GET")
+	r.HandleFunc("/networks/create", s.createNetwork).Methods("POST")
+	r.HandleFunc("/networks/{name}", s.inspectNetwork).Methods("GET")
+	r.HandleFunc("/networks/{name}", s.removeNetwork).Methods("DELETE")
+
+	r.HandleFunc("/volumes", s.listVolumes).Methods("GET")
+	r.HandleFunc("/volumes/create", s.createVolume).Methods("POST")
+	r.HandleFunc("/volumes/{name}", s.inspectVolume).Methods("GET")
+	r.HandleFunc("/volumes/{name}", s.inspectVolume).Methods("DELETE")
+}
+
+// ServeHTTP serves a request of the API
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logrus.Debugf("API request %s %s", r.Method, r.URL)
+	if !s.startRequest() {
+		w.Header().Set("Connection", "close")
+		writeError(w, errDraining)
+		return
+	}
+	defer s.endRequest()
+	if auditLog := s.auditLog(r); auditLog != nil {
+		s.serveAudited(auditLog, w, r)
+		return
+	}
+	s.router.ServeHTTP(w, r)
+}
+
+// Serve serves the API on the unix socket at socketPath until ctx is done
+func (s *Server) Serve(ctx context.Context, socketPath string) error {
+	if err := s.LoadTrust(); err != nil {
+		return err
+	}
+	defer s.cleanupTrust()
+
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0700); err != nil {
+		return errors.Wrapf(err, "error creating directory for socket %s", socketPath)
+	}
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error removing stale socket %s", socketPath)
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return errors.Wrapf(err, "error listening on %s", socketPath)
+	}
+	defer os.Remove(socketPath)
+
+	server := &http.Server{Handler: s, ConnContext: peerContext}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(listener)
+	}()
+	select {
+	case <-ctx.Done():
+		// Let the requests in progress finish, such as image pulls
+		s.drainLock.Lock()
+		timeout := s.drainTimeout
+		s.drainLock.Unlock()
+		if _, err := s.Drain(timeout); err != nil {
+			logrus.Debugf("%v", err)
+		}
+	case <-s.drainChan:
+	case err := <-errChan:
+		return errors.Wrapf(err, "error serving on %s", socketPath)
+	}
+	if err := s.waitDrained(server); err != nil {
+		return err
+	}
+	<-errChan
+	return nil
+}
+
+// writeJSON writes a response with the given status and v as body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Errorf("Unable to write API response: %v", err)
+	}
+}
+
+// writeError writes an error response, with the status matching the cause
+// of err
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch errors.Cause(err) {
+	case libpod.ErrNoSuchCtr, libpod.ErrNoSuchImage, libpod.ErrNoSuchNetwork, errNoSuchVolume, errNoSuchExec,
+		libpod.ErrNoSuchNameReservation:
+		status = http.StatusNotFound
+	case libpod.ErrCtrExists, libpod.ErrPodExists, libpod.ErrCtrStateInvalid, libpod.ErrNetworkExists,
+		libpod.ErrNameReserved:
+		status = http.StatusConflict
+	case libpod.ErrNetworkInUse:
+		status = http.StatusForbidden
+	case libpod.ErrInvalidArg, events.ErrInvalidCursor:
+		status = http.StatusBadRequest
+	case libpod.ErrNotImplemented:
+		status = http.StatusNotImplemented
+	case libpod.ErrStorageQuota:
+		status = http.StatusInsufficientStorage
+	case errDraining:
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, types.ErrorResponse{Message: err.Error()})
+}
+
+// boolQuery returns the boolean query parameter key of the request, which
+// Docker clients give as 1 or true
+func boolQuery(r *http.Request, key string) (bool, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrapf(libpod.ErrInvalidArg, "invalid value %q of %s", value, key)
+	}
+	return b, nil
+}
+
+// ping answers the ping of clients, which negotiate the API version with
+// its headers
+func (s *Server) ping(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("API-Version", APIVersion)
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == "GET" {
+		w.Write([]byte("OK"))
+	}
+}
@@ -0,0 +1,351 @@
+package dockerapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/events"
+	"github.com/containers/libpod/pkg/audit"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPingRoutes(t *testing.T) {
+	s := NewServer(nil)
+	for _, path := range []string{"/_ping", "/v1.40/_ping", "/v1.24/_ping"} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, "OK", rec.Body.String(), path)
+		assert.Equal(t, APIVersion, rec.Header().Get("API-Version"), path)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("HEAD", "/_ping", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/vx/_ping", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWriteError(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+	}{
+		{errors.Wrapf(libpod.ErrNoSuchCtr, "foo"), http.StatusNotFound},
+		{errors.Wrapf(libpod.ErrNoSuchImage, "foo"), http.StatusNotFound},
+		{errors.Wrapf(errNoSuchVolume, "foo"), http.StatusNotFound},
+		{errors.Wrapf(errNoSuchExec, "foo"), http.StatusNotFound},
+		{errors.Wrapf(libpod.ErrNoSuchNameReservation, "foo"), http.StatusNotFound},
+		{errors.Wrapf(libpod.ErrNameReserved, "foo"), http.StatusConflict},
+		{errors.Wrapf(libpod.ErrPodExists, "foo"), http.StatusConflict},
+		{errors.Wrapf(libpod.ErrCtrStateInvalid, "foo"), http.StatusConflict},
+		{errors.Wrapf(libpod.ErrNoSuchNetwork, "foo"), http.StatusNotFound},
+		{errors.Wrapf(libpod.ErrNetworkExists, "foo"), http.StatusConflict},
+		{errors.Wrapf(libpod.ErrNetworkInUse, "foo"), http.StatusForbidden},
+		{errors.Wrapf(libpod.ErrInvalidArg, "foo"), http.StatusBadRequest},
+		{errors.Wrapf(events.ErrInvalidCursor, "foo"), http.StatusBadRequest},
+		{errors.Wrapf(libpod.ErrNotImplemented, "foo"), http.StatusNotImplemented},
+		{errors.Wrapf(libpod.ErrStorageQuota, "foo"), http.StatusInsufficientStorage},
+		{errors.New("foo"), http.StatusInternalServerError},
+	} {
+		rec := httptest.NewRecorder()
+		writeError(rec, tc.err)
+		assert.Equal(t, tc.status, rec.Code, tc.err.Error())
+
+		var resp types.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, tc.err.Error(), resp.Message)
+	}
+}
+
+func TestBoolQuery(t *testing.T) {
+	r := httptest.NewRequest("GET", "/containers/json?all=1&size=false&force=maybe", nil)
+	all, err := boolQuery(r, "all")
+	require.NoError(t, err)
+	assert.True(t, all)
+
+	size, err := boolQuery(r, "size")
+	require.NoError(t, err)
+	assert.False(t, size)
+
+	missing, err := boolQuery(r, "missing")
+	require.NoError(t, err)
+	assert.False(t, missing)
+
+	_, err = boolQuery(r, "force")
+	assert.Equal(t, libpod.ErrInvalidArg, errors.Cause(err))
+}
+
+func TestStreamQuery(t *testing.T) {
+	for query, expected := range map[string]bool{"": true, "?stream=1": true, "?stream=false": false, "?stream=0": false} {
+		stream, err := streamQuery(httptest.NewRequest("GET", "/containers/foo/stats"+query, nil))
+		require.NoError(t, err)
+		assert.Equal(t, expected, stream, query)
+	}
+	_, err := streamQuery(httptest.NewRequest("GET", "/containers/foo/stats?stream=maybe", nil))
+	assert.Equal(t, libpod.ErrInvalidArg, errors.Cause(err))
+}
+
+func TestDockerCPUStats(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	pre := dockerCPUStats(0, start)
+	cur := dockerCPUStats(uint64(time.Second/2), start.Add(time.Second))
+	// Docker clients compute the percentage of one CPU used as podman does
+	cpuDelta := float64(cur.CPUUsage.TotalUsage - pre.CPUUsage.TotalUsage)
+	systemDelta := float64(cur.SystemUsage - pre.SystemUsage)
+	assert.InDelta(t, 50.0, cpuDelta/systemDelta*float64(cur.OnlineCPUs)*100, 0.001)
+}
+
+func TestNetworkResource(t *testing.T) {
+	list, err := libcni.ConfListFromBytes([]byte(`{
+		"cniVersion": "0.3.0",
+		"name": "podman",
+		"plugins": [
+			{
+				"type": "bridge",
+				"ipam": {
+					"type": "host-local",
+					"ranges": [[{"subnet": "10.88.0.0/16", "gateway": "10.88.0.1"}]]
+				}
+			},
+			{"type": "portmap"}
+		]
+	}`))
+	require.NoError(t, err)
+
+	result := networkResource(list)
+	assert.Equal(t, "podman", result.Name)
+	assert.Equal(t, "bridge", result.Driver)
+	assert.Equal(t, "local", result.Scope)
+	assert.Equal(t, "host-local", result.IPAM.Driver)
+	assert.Equal(t, []network.IPAMConfig{{Subnet: "10.88.0.0/16", Gateway: "10.88.0.1"}}, result.IPAM.Config)
+}
+
+func TestRequestTrust(t *testing.T) {
+	s := NewServer(nil)
+	r := httptest.NewRequest("POST", "/images/create?fromImage=alpine", nil)
+	trust, err := s.requestTrust(r)
+	require.NoError(t, err)
+	assert.Equal(t, &clientTrust{}, trust)
+	trust.cleanup()
+
+	policy := `{"default":[{"type":"reject"}]}`
+	registriesConf := "[registries.search]\nregistries = ['registry.example.com']\n"
+	r.Header.Set(SignaturePolicyHeader, base64.StdEncoding.EncodeToString([]byte(policy)))
+	r.Header.Set(RegistriesConfHeader, base64.URLEncoding.EncodeToString([]byte(registriesConf)))
+	_, err = s.requestTrust(r)
+	assert.Equal(t, libpod.ErrInvalidArg, errors.Cause(err))
+
+	s.SetClientTrust(true)
+	trust, err = s.requestTrust(r)
+	require.NoError(t, err)
+	content, err := ioutil.ReadFile(trust.signaturePolicyPath)
+	require.NoError(t, err)
+	assert.Equal(t, policy, string(content))
+	content, err = ioutil.ReadFile(trust.registriesConfPath)
+	require.NoError(t, err)
+	assert.Equal(t, registriesConf, string(content))
+	assert.Equal(t, trust.signaturePolicyPath, trust.signaturePolicy(nil))
+	trust.cleanup()
+	_, err = os.Stat(trust.dir)
+	assert.True(t, os.IsNotExist(err))
+
+	for header, value := range map[string]string{
+		SignaturePolicyHeader: "not base64!",
+		RegistriesConfHeader:  base64.StdEncoding.EncodeToString([]byte("[registries.search")),
+	} {
+		r := httptest.NewRequest("POST", "/images/create?fromImage=alpine", nil)
+		r.Header.Set(header, value)
+		_, err := s.requestTrust(r)
+		assert.Equal(t, libpod.ErrInvalidArg, errors.Cause(err), header)
+	}
+	r = httptest.NewRequest("POST", "/images/create?fromImage=alpine", nil)
+	r.Header.Set(SignaturePolicyHeader, base64.StdEncoding.EncodeToString([]byte(`{"default":[]}`)))
+	_, err = s.requestTrust(r)
+	assert.Equal(t, libpod.ErrInvalidArg, errors.Cause(err))
+}
+
+func TestLoadTrust(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "trust")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	policyPath := filepath.Join(tmp, "policy.json")
+	registriesConfPath := filepath.Join(tmp, "registries.conf")
+	require.NoError(t, ioutil.WriteFile(policyPath, []byte(`{"default":[{"type":"reject"}]}`), 0644))
+	require.NoError(t, ioutil.WriteFile(registriesConfPath, []byte("[registries.search]\nregistries = ['registry.example.com']\n"), 0644))
+	defer os.Setenv("REGISTRIES_CONFIG_PATH", os.Getenv("REGISTRIES_CONFIG_PATH"))
+	os.Setenv("REGISTRIES_CONFIG_PATH", registriesConfPath)
+
+	s := NewServer(nil)
+	defer s.cleanupTrust()
+	trust, err := s.loadTrust(policyPath)
+	require.NoError(t, err)
+	assert.Equal(t, policyPath, trust.signaturePolicySource)
+	assert.Equal(t, registriesConfPath, trust.registriesConfSource)
+	s.setTrust(trust)
+
+	// Requests use the copies taken, edits only apply once reloaded
+	require.NoError(t, ioutil.WriteFile(policyPath, []byte(`{"default":[]}`), 0644))
+	r := httptest.NewRequest("POST", "/images/create?fromImage=alpine", nil)
+	requestTrust, err := s.requestTrust(r)
+	require.NoError(t, err)
+	content, err := ioutil.ReadFile(requestTrust.signaturePolicy(nil))
+	require.NoError(t, err)
+	assert.Equal(t, `{"default":[{"type":"reject"}]}`, string(content))
+	assert.Equal(t, trust.registriesConfPath, requestTrust.registriesConfPath)
+	requestTrust.cleanup()
+	_, err = os.Stat(trust.signaturePolicyPath)
+	assert.NoError(t, err)
+
+	_, err = s.loadTrust(policyPath)
+	assert.Equal(t, libpod.ErrInvalidArg, errors.Cause(err))
+	require.NoError(t, os.Remove(policyPath))
+	trust, err = s.loadTrust(policyPath)
+	require.NoError(t, err)
+	assert.Equal(t, "", trust.signaturePolicyPath)
+
+	s.cleanupTrust()
+	_, err = os.Stat(s.trustDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDrain(t *testing.T) {
+	s := NewServer(nil)
+	// A pull in progress
+	require.True(t, s.startRequest())
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("POST", "/v1.40/libpod/drain?timeout=5", nil))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	var result DrainResult
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+	assert.Equal(t, DrainResult{InFlight: 1, Timeout: 5}, result)
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/_ping", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	_, err := s.Drain(0)
+	assert.Error(t, err)
+
+	ctx, cancel := s.drainContext(context.Background())
+	defer cancel()
+	<-ctx.Done()
+	select {
+	case <-s.idle:
+		t.Fatal("drained with a request in progress")
+	default:
+	}
+	s.endRequest()
+	<-s.idle
+	assert.NoError(t, s.waitDrained(&http.Server{}))
+
+	// Requests still in progress after the timeout are abandoned
+	s = NewServer(nil)
+	require.True(t, s.startRequest())
+	_, err = s.Drain(10 * time.Millisecond)
+	require.NoError(t, err)
+	assert.NoError(t, s.waitDrained(&http.Server{}))
+}
+
+func TestAuthenticate(t *testing.T) {
+	assert.Equal(t, "docker.io", registryHostname("https://index.docker.io/v1/"))
+	assert.Equal(t, "localhost:5000", registryHostname("http://localhost:5000"))
+	assert.Equal(t, "quay.io", registryHostname("quay.io"))
+
+	s := NewServer(nil)
+	for _, body := range []string{"not json", `{"username":"user","password":"secret"}`} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest("POST", "/v1.40/auth", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestEventsTime(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		time  time.Time
+	}{
+		{"", time.Time{}},
+		{"1560000000", time.Unix(1560000000, 0)},
+		{"1560000000.5", time.Unix(1560000000, 500000000)},
+		{"1560000000.000000001", time.Unix(1560000000, 1)},
+		{"2019-06-08T13:20:00Z", time.Date(2019, 6, 8, 13, 20, 0, 0, time.UTC)},
+	} {
+		parsed, err := eventsTime(tc.value)
+		require.NoError(t, err, tc.value)
+		assert.True(t, tc.time.Equal(parsed), tc.value)
+	}
+	for _, value := range []string{"yesterday", "1560000000.x"} {
+		_, err := eventsTime(value)
+		assert.Equal(t, libpod.ErrInvalidArg, errors.Cause(err), value)
+	}
+}
+
+func TestDockerEvent(t *testing.T) {
+	e := events.NewEvent(events.Died)
+	e.ID = "abc"
+	e.Name = "web"
+	e.Image = "alpine"
+	e.Type = events.Container
+	e.Attributes = map[string]string{"exitCode": "1"}
+	msg := dockerEvent(&e)
+	assert.Equal(t, "container", msg.Type)
+	assert.Equal(t, "die", msg.Action)
+	assert.Equal(t, "die", msg.Status)
+	assert.Equal(t, "abc", msg.ID)
+	assert.Equal(t, "alpine", msg.From)
+	assert.Equal(t, map[string]string{"exitCode": "1", "name": "web", "image": "alpine"}, msg.Actor.Attributes)
+	assert.Equal(t, e.Time.UnixNano(), msg.TimeNano)
+
+	e = events.NewEvent(events.Pull)
+	e.ID = "def"
+	e.Image = "alpine"
+	e.Type = events.Image
+	msg = dockerEvent(&e)
+	assert.Equal(t, "pull", msg.Action)
+	assert.Empty(t, msg.Status)
+	assert.Equal(t, "def", msg.Actor.ID)
+}
+
+func TestServeAudited(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	auditLog := audit.NewLog(filepath.Join(dir, "audit.log"))
+
+	s := NewServer(nil)
+	body := "not json"
+	rec := httptest.NewRecorder()
+	s.serveAudited(auditLog, rec, httptest.NewRequest("POST", "/v1.40/auth?x=1", strings.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	records, err := auditLog.Read(audit.Query{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, audit.SourceAPI, records[0].Source)
+	assert.Equal(t, audit.UnknownUID, records[0].UID)
+	assert.Equal(t, "POST /auth", records[0].Operation)
+	assert.Equal(t, http.StatusBadRequest, records[0].Status)
+	bodyHash := sha256.Sum256([]byte(body))
+	assert.Equal(t, audit.HashArgs("x=1", hex.EncodeToString(bodyHash[:])), records[0].ArgsHash)
+}
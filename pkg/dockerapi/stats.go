@@ -0,0 +1,178 @@
+package dockerapi
+
+import (
+	"encoding/json"
+	"net/http"
+	goruntime "runtime"
+	"strconv"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultStatsInterval is how often the stats of containers are streamed,
+// every second as Docker does
+const defaultStatsInterval = time.Second
+
+// dockerCPUStats returns the Docker CPU stats of a sample. Docker clients
+// divide the CPU time used by the system CPU time, the time elapsed for all
+// the CPUs, and multiply it by the number of CPUs.
+func dockerCPUStats(cpuNano uint64, sampled time.Time) types.CPUStats {
+	ncpu := uint64(goruntime.NumCPU())
+	return types.CPUStats{
+		CPUUsage:    types.CPUUsage{TotalUsage: cpuNano},
+		SystemUsage: uint64(sampled.UnixNano()) * ncpu,
+		OnlineCPUs:  uint32(ncpu),
+	}
+}
+
+// dockerStats returns the Docker stats of a sample of a container, given the
+// CPU stats of the previous one and when it was taken
+func dockerStats(ctr *libpod.Container, stats *libpod.ContainerStats, preCPU types.CPUStats, preRead time.Time) *types.StatsJSON {
+	read := time.Unix(0, int64(stats.SystemNano))
+	return &types.StatsJSON{
+		Stats: types.Stats{
+			Read:    read,
+			PreRead: preRead,
+			PidsStats: types.PidsStats{
+				Current: stats.PIDs,
+				Limit:   stats.PIDsLimit,
+			},
+			BlkioStats: types.BlkioStats{
+				IoServiceBytesRecursive: []types.BlkioStatEntry{
+					{Op: "Read", Value: stats.BlockInput},
+					{Op: "Write", Value: stats.BlockOutput},
+				},
+			},
+			CPUStats:    dockerCPUStats(stats.CPUNano, read),
+			PreCPUStats: preCPU,
+			MemoryStats: types.MemoryStats{
+				Usage: stats.MemUsage,
+				Limit: stats.MemLimit,
+			},
+		},
+		Name: "/" + ctr.Name(),
+		ID:   ctr.ID(),
+		Networks: map[string]types.NetworkStats{
+			"eth0": {
+				RxBytes: stats.NetInput,
+				TxBytes: stats.NetOutput,
+			},
+		},
+	}
+}
+
+// streamStats sends the stats of a container to the client, until the
+// container stops or the client goes away if stream, else only once. write
+// writes each sample.
+func (s *Server) streamStats(w http.ResponseWriter, r *http.Request, ctr *libpod.Container, stream bool, interval time.Duration, write func(*json.Encoder, *libpod.ContainerStats) error) {
+	state, err := ctr.State()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if state != libpod.ContainerStateRunning {
+		writeError(w, errors.Wrapf(libpod.ErrCtrStateInvalid, "container %s is not running", ctr.ID()))
+		return
+	}
+	// The stream ends when the service drains, rather than delaying it
+	ctx, cancel := s.drainContext(r.Context())
+	defer cancel()
+	statsChan := make(chan *libpod.ContainerStats)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- ctr.StreamStats(ctx, interval, statsChan)
+		close(statsChan)
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for stats := range statsChan {
+		if err := write(encoder, stats); err != nil {
+			logrus.Debugf("Unable to write the stats of container %s to the API client: %v", ctr.ID(), err)
+			break
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if !stream {
+			break
+		}
+	}
+	cancel()
+	// Let StreamStats return once the client stopped reading
+	for range statsChan {
+	}
+	if err := <-streamErr; err != nil {
+		logrus.Errorf("Unable to read the stats of container %s: %v", ctr.ID(), err)
+	}
+}
+
+// streamQuery returns the stream query parameter of the request, true by
+// default
+func streamQuery(r *http.Request) (bool, error) {
+	if r.URL.Query().Get("stream") == "" {
+		return true, nil
+	}
+	return boolQuery(r, "stream")
+}
+
+// containerStats streams the Docker stats of a container every second, or
+// returns them once with stream=false. The CPU usage of the first sample is
+// averaged since the container started.
+func (s *Server) containerStats(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	stream, err := streamQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var preRead time.Time
+	var preCPU types.CPUStats
+	if started, err := ctr.StartedTime(); err == nil {
+		preRead = started
+		preCPU = dockerCPUStats(0, started)
+	}
+	s.streamStats(w, r, ctr, stream, defaultStatsInterval, func(encoder *json.Encoder, stats *libpod.ContainerStats) error {
+		docker := dockerStats(ctr, stats, preCPU, preRead)
+		preCPU, preRead = docker.CPUStats, docker.Read
+		return encoder.Encode(docker)
+	})
+}
+
+// libpodContainerStats streams the stats of a container as podman stats
+// reports them, every interval seconds, or returns them once with
+// stream=false
+func (s *Server) libpodContainerStats(w http.ResponseWriter, r *http.Request) {
+	ctr, err := s.lookupContainer(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	stream, err := streamQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	interval := defaultStatsInterval
+	if value := r.URL.Query().Get("interval"); value != "" {
+		seconds, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || seconds == 0 {
+			writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "invalid value %q of interval", value))
+			return
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+	s.streamStats(w, r, ctr, stream, interval, func(encoder *json.Encoder, stats *libpod.ContainerStats) error {
+		return encoder.Encode(stats)
+	})
+}
@@ -0,0 +1,152 @@
+package dockerapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	goruntime "runtime"
+	"strings"
+	"time"
+
+	"github.com/containers/image/docker"
+	imagetypes "github.com/containers/image/types"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/registries"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/containers/libpod/version"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/pkg/errors"
+)
+
+// version returns the versions of podman and of the API
+func (s *Server) version(w http.ResponseWriter, r *http.Request) {
+	v := types.Version{
+		Version:       version.Version,
+		APIVersion:    APIVersion,
+		MinAPIVersion: MinAPIVersion,
+		GoVersion:     goruntime.Version(),
+		Os:            goruntime.GOOS,
+		Arch:          goruntime.GOARCH,
+	}
+	if info, err := s.runtime.Info(); err == nil {
+		v.KernelVersion = infoString(info, "host", "kernel")
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// info returns the counts of containers and images and the host
+// information of podman info
+func (s *Server) info(w http.ResponseWriter, r *http.Request) {
+	info, err := s.runtime.Info()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ctrs, err := s.runtime.GetAllContainers()
+	if err != nil {
+		writeError(w, errors.Wrapf(err, "error getting containers"))
+		return
+	}
+	images, err := s.runtime.ImageRuntime().GetImages()
+	if err != nil {
+		writeError(w, errors.Wrapf(err, "error getting images"))
+		return
+	}
+
+	result := types.Info{
+		ID:              infoString(info, "host", "hostname"),
+		Name:            infoString(info, "host", "hostname"),
+		Containers:      len(ctrs),
+		Images:          len(images),
+		Driver:          infoString(info, "store", "GraphDriverName"),
+		DockerRootDir:   infoString(info, "store", "GraphRoot"),
+		KernelVersion:   infoString(info, "host", "kernel"),
+		OperatingSystem: goruntime.GOOS,
+		OSType:          goruntime.GOOS,
+		Architecture:    goruntime.GOARCH,
+		NCPU:            goruntime.NumCPU(),
+		ServerVersion:   version.Version,
+		SystemTime:      time.Now().Format(time.RFC3339Nano),
+		CgroupDriver:    s.runtime.GetConfig().CgroupManager,
+		DefaultRuntime:  "runc",
+		LoggingDriver:   libpod.KubernetesLogDriver,
+	}
+	if mem, ok := infoValue(info, "host", "MemTotal").(int64); ok {
+		result.MemTotal = mem
+	}
+	for _, ctr := range ctrs {
+		state, err := ctr.State()
+		if err != nil {
+			continue
+		}
+		switch state {
+		case libpod.ContainerStateRunning:
+			result.ContainersRunning++
+		case libpod.ContainerStatePaused:
+			result.ContainersPaused++
+		default:
+			result.ContainersStopped++
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// infoValue returns a value of the podman info of the given type
+func infoValue(info []libpod.InfoData, infoType, key string) interface{} {
+	for _, i := range info {
+		if i.Type == infoType {
+			return i.Data[key]
+		}
+	}
+	return nil
+}
+
+// infoString returns a value of the podman info as a string
+func infoString(info []libpod.InfoData, infoType, key string) string {
+	v := infoValue(info, infoType, key)
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// authenticate checks the credentials of a registry, without storing them.
+// Docker clients store them and send them with each pull and push.
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) {
+	var authConfig types.AuthConfig
+	if err := json.NewDecoder(r.Body).Decode(&authConfig); err != nil {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "error decoding the credentials: %v", err))
+		return
+	}
+	server := registryHostname(authConfig.ServerAddress)
+	if server == "" {
+		writeError(w, errors.Wrapf(libpod.ErrInvalidArg, "serveraddress must be given"))
+		return
+	}
+	sc := &imagetypes.SystemContext{}
+	if insecureRegistries, err := registries.GetInsecureRegistries(); err == nil && util.StringInSlice(server, insecureRegistries) {
+		sc.DockerInsecureSkipTLSVerify = true
+	}
+	err := docker.CheckAuth(r.Context(), sc, authConfig.Username, authConfig.Password, server)
+	switch err {
+	case nil:
+		writeJSON(w, http.StatusOK, registry.AuthenticateOKBody{Status: "Login Succeeded"})
+	case docker.ErrUnauthorizedForCredentials:
+		writeJSON(w, http.StatusUnauthorized, types.ErrorResponse{Message: fmt.Sprintf("error logging into %q: invalid username/password", server)})
+	default:
+		writeError(w, errors.Wrapf(err, "error authenticating creds for %q", server))
+	}
+}
+
+// registryHostname returns the registry of the server address of Docker
+// credentials, which can be the URL of its API such as
+// https://index.docker.io/v1/
+func registryHostname(address string) string {
+	address = strings.TrimPrefix(strings.TrimPrefix(address, "http://"), "https://")
+	address = strings.SplitN(address, "/", 2)[0]
+	if address == "index.docker.io" {
+		return "docker.io"
+	}
+	return address
+}
@@ -0,0 +1,127 @@
+package dockerapi
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/signature"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/registries"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Headers with which clients send the trust configuration enforced for the
+// images pulled on their behalf, the content of a policy.json and of a
+// registries.conf file encoded in base64. Docker clients set them with the
+// HttpHeaders of their config.json.
+const (
+	// SignaturePolicyHeader carries the signature policy of the client
+	SignaturePolicyHeader = "X-Podman-Signature-Policy"
+	// RegistriesConfHeader carries the registries configuration of the
+	// client, searched for unqualified names and insecure registries
+	RegistriesConfHeader = "X-Podman-Registries-Conf"
+)
+
+// clientTrust is the trust configuration a request is served with, in
+// temporary files for the configuration the client sent
+type clientTrust struct {
+	dir string
+	// signaturePolicyPath and registriesConfPath are the files of the
+	// service loaded with LoadTrust when the client sent none, and are
+	// empty for the files read as pulls use them
+	signaturePolicyPath string
+	registriesConfPath  string
+}
+
+// requestTrust returns the trust configuration of a request. The service only
+// accepts the configuration of clients when started with --client-trust, so
+// that clients cannot lift the policy of the service otherwise.
+func (s *Server) requestTrust(r *http.Request) (*clientTrust, error) {
+	trust := &clientTrust{}
+	if st := s.serviceTrust(); st != nil {
+		trust.signaturePolicyPath = st.signaturePolicyPath
+		trust.registriesConfPath = st.registriesConfPath
+	}
+	policy, err := trustHeader(r, SignaturePolicyHeader)
+	if err != nil {
+		return nil, err
+	}
+	registriesConf, err := trustHeader(r, RegistriesConfHeader)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil && registriesConf == nil {
+		return trust, nil
+	}
+	if !s.clientTrust {
+		return nil, errors.Wrapf(libpod.ErrInvalidArg, "the service does not accept the trust configuration of clients, it was not started with --client-trust")
+	}
+
+	if policy != nil {
+		if _, err := signature.NewPolicyFromBytes(policy); err != nil {
+			return nil, errors.Wrapf(libpod.ErrInvalidArg, "invalid signature policy in %s: %v", SignaturePolicyHeader, err)
+		}
+	}
+	if trust.dir, err = ioutil.TempDir("", "podman-client-trust"); err != nil {
+		return nil, errors.Wrapf(err, "error creating directory for the trust configuration of the client")
+	}
+	if policy != nil {
+		trust.signaturePolicyPath = filepath.Join(trust.dir, "policy.json")
+		if err := ioutil.WriteFile(trust.signaturePolicyPath, policy, 0600); err != nil {
+			trust.cleanup()
+			return nil, errors.Wrapf(err, "error writing the signature policy of the client")
+		}
+	}
+	if registriesConf != nil {
+		trust.registriesConfPath = filepath.Join(trust.dir, "registries.conf")
+		if err := ioutil.WriteFile(trust.registriesConfPath, registriesConf, 0600); err != nil {
+			trust.cleanup()
+			return nil, errors.Wrapf(err, "error writing the registries configuration of the client")
+		}
+		if _, err := registries.GetRegistriesFromFile(trust.registriesConfPath); err != nil {
+			trust.cleanup()
+			return nil, errors.Wrapf(libpod.ErrInvalidArg, "invalid registries configuration in %s: %v", RegistriesConfHeader, err)
+		}
+	}
+	return trust, nil
+}
+
+// trustHeader returns the decoded content of a trust header of a request, or
+// nil if the client did not send it
+func trustHeader(r *http.Request, header string) ([]byte, error) {
+	value := r.Header.Get(header)
+	if value == "" {
+		return nil, nil
+	}
+	content, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		// Docker clients encode X-Registry-Auth with the URL alphabet
+		if content, err = base64.URLEncoding.DecodeString(value); err != nil {
+			return nil, errors.Wrapf(libpod.ErrInvalidArg, "%s is not encoded in base64", header)
+		}
+	}
+	return content, nil
+}
+
+// signaturePolicy returns the signature policy of the request, or the one
+// of the service
+func (t *clientTrust) signaturePolicy(runtime *libpod.Runtime) string {
+	if t.signaturePolicyPath != "" {
+		return t.signaturePolicyPath
+	}
+	return runtime.GetConfig().SignaturePolicyPath
+}
+
+// cleanup removes the files of the configuration the client sent
+func (t *clientTrust) cleanup() {
+	if t.dir == "" {
+		return
+	}
+	if err := os.RemoveAll(t.dir); err != nil {
+		logrus.Errorf("Error removing the trust configuration of the client in %s: %v", t.dir, err)
+	}
+}
@@ -0,0 +1,31 @@
+package dockerapi
+
+import (
+	"net/http"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// listVolumes lists the named volumes, which libpod does not have: the
+// volumes of containers are bind mounts
+func (s *Server) listVolumes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, volume.VolumesListOKBody{
+		Volumes:  []*types.Volume{},
+		Warnings: []string{"named volumes are not supported, use bind mounts"},
+	})
+}
+
+// createVolume refuses to create named volumes, which libpod does not have
+func (s *Server) createVolume(w http.ResponseWriter, r *http.Request) {
+	writeError(w, errors.Wrapf(libpod.ErrNotImplemented, "named volumes are not supported, use bind mounts"))
+}
+
+// inspectVolume returns the details of a named volume, or removes it, of
+// which there are none
+func (s *Server) inspectVolume(w http.ResponseWriter, r *http.Request) {
+	writeError(w, errors.Wrapf(errNoSuchVolume, "%s", mux.Vars(r)["name"]))
+}
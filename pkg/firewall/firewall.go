@@ -0,0 +1,58 @@
+// Package firewall manages the firewall rules libpod adds for containers,
+// through the firewall in use on the host.
+package firewall
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the firewall drivers
+const (
+	// Auto selects the driver matching the firewall in use on the host
+	Auto = ""
+	// IPTables manages rules with the iptables command
+	IPTables = "iptables"
+	// NFTables manages rules in a table of its own with the nft command
+	NFTables = "nftables"
+	// Firewalld manages rules through the D-Bus interface of firewalld
+	Firewalld = "firewalld"
+)
+
+// Drivers are the names of all firewall drivers, including Auto
+var Drivers = []string{Auto, IPTables, NFTables, Firewalld}
+
+// ErrUnknownDriver is returned when requesting a firewall driver that does not
+// exist
+var ErrUnknownDriver = errors.New("unknown firewall driver")
+
+// Firewall adds and removes firewall rules for containers. Adding a rule that
+// already exists and removing one that does not are not errors, so that rules
+// can be reconciled after another program removed them.
+type Firewall interface {
+	// Name returns the name of the driver
+	Name() string
+	// AllowForward allows forwarding traffic from the given address of a
+	// container, e.g. to let it reach name servers of the host
+	AllowForward(ip net.IP) error
+	// RemoveForward removes the rule added by AllowForward
+	RemoveForward(ip net.IP) error
+}
+
+// New returns the firewall driver with the given name, or the one matching the
+// firewall in use on the host if the name is Auto
+func New(name string) (Firewall, error) {
+	switch name {
+	case Auto:
+		return detect()
+	case IPTables:
+		return newIPTables()
+	case NFTables:
+		return newNFTables()
+	case Firewalld:
+		return newFirewalld()
+	default:
+		return nil, errors.Wrapf(ErrUnknownDriver, "firewall driver %q", name)
+	}
+}
@@ -0,0 +1,86 @@
+// +build linux
+
+package firewall
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUnknownDriver(t *testing.T) {
+	_, err := New("ipchains")
+	assert.Equal(t, ErrUnknownDriver, errors.Cause(err))
+}
+
+var origExecCmd = execCmd
+
+// fakeIPTables replaces the commands run by the iptables driver, keeping the
+// rules in memory
+func fakeIPTables(rules map[string]bool) func() {
+	execCmd = func(name string, args ...string) (string, error) {
+		rule := name + " " + strings.Join(append(append([]string{}, args[:2]...), args[3:]...), " ")
+		switch args[2] {
+		case "-C":
+			if !rules[rule] {
+				return "", errors.New("rule does not exist")
+			}
+		case "-I":
+			rules[rule] = true
+		case "-D":
+			delete(rules, rule)
+		}
+		return "", nil
+	}
+	return func() {
+		execCmd = origExecCmd
+	}
+}
+
+func TestIPTablesIdempotent(t *testing.T) {
+	rules := make(map[string]bool)
+	defer fakeIPTables(rules)()
+
+	fw, err := New(IPTables)
+	assert.NoError(t, err)
+	ip := net.ParseIP("10.88.0.2")
+	assert.NoError(t, fw.AllowForward(ip))
+	assert.NoError(t, fw.AllowForward(ip))
+	assert.Equal(t, map[string]bool{
+		"iptables -t filter FORWARD -s 10.88.0.2 ! -o 10.88.0.2 -j ACCEPT": true,
+	}, rules)
+
+	assert.NoError(t, fw.AllowForward(net.ParseIP("fd00::2")))
+	assert.True(t, rules["ip6tables -t filter FORWARD -s fd00::2 ! -o fd00::2 -j ACCEPT"])
+
+	assert.NoError(t, fw.RemoveForward(ip))
+	assert.NoError(t, fw.RemoveForward(ip))
+	assert.Len(t, rules, 1)
+}
+
+func TestNFTablesScripts(t *testing.T) {
+	var scripts []string
+	origRunNFT := runNFT
+	runNFT = func(script string) error {
+		scripts = append(scripts, script)
+		return nil
+	}
+	defer func() {
+		runNFT = origRunNFT
+	}()
+
+	fw, err := New(NFTables)
+	assert.NoError(t, err)
+	assert.NoError(t, fw.AllowForward(net.ParseIP("10.88.0.2")))
+	assert.NoError(t, fw.RemoveForward(net.ParseIP("fd00::2")))
+	assert.Len(t, scripts, 2)
+
+	setup := strings.Join(nftSetup, "\n") + "\n"
+	assert.Equal(t, setup+"add element inet podman forward_allowed { 10.88.0.2 }\n", scripts[0])
+	assert.Equal(t, setup+
+		"add element inet podman forward_allowed6 { fd00::2 }\n"+
+		"delete element inet podman forward_allowed6 { fd00::2 }\n", scripts[1])
+}
@@ -0,0 +1,32 @@
+// +build !linux
+
+package firewall
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+var errUnsupported = errors.New("firewall drivers are not supported on this platform")
+
+func detect() (Firewall, error) {
+	return nil, errUnsupported
+}
+
+func newIPTables() (Firewall, error) {
+	return nil, errUnsupported
+}
+
+func newNFTables() (Firewall, error) {
+	return nil, errUnsupported
+}
+
+func newFirewalld() (Firewall, error) {
+	return nil, errUnsupported
+}
+
+// WatchReloads is not supported on this platform
+func WatchReloads(ctx context.Context, reloaded func()) error {
+	return errUnsupported
+}
@@ -0,0 +1,161 @@
+// +build linux
+
+package firewall
+
+import (
+	"context"
+	"net"
+	"os/exec"
+
+	"github.com/godbus/dbus"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	firewalldName      = "org.fedoraproject.FirewallD1"
+	firewalldPath      = "/org/fedoraproject/FirewallD1"
+	firewalldDirect    = firewalldName + ".direct"
+	firewalldReloaded  = firewalldName + ".Reloaded"
+	nameOwnerChanged   = "org.freedesktop.DBus.NameOwnerChanged"
+	firewalldReloadSig = "type='signal',interface='" + firewalldName + "',member='Reloaded'"
+	firewalldStartSig  = "type='signal',sender='org.freedesktop.DBus',member='NameOwnerChanged',arg0='" + firewalldName + "'"
+)
+
+// firewalldRunning returns whether firewalld is running on the system bus
+func firewalldRunning(conn *dbus.Conn) bool {
+	var running bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, firewalldName).Store(&running); err != nil {
+		logrus.Debugf("Error looking up firewalld on the system bus: %v", err)
+		return false
+	}
+	return running
+}
+
+// detect returns the driver for firewalld if it is running, and otherwise the
+// driver for the first of iptables and nft that is installed
+func detect() (Firewall, error) {
+	if conn, err := dbus.SystemBus(); err == nil && firewalldRunning(conn) {
+		return &firewalld{conn: conn}, nil
+	}
+	if _, err := exec.LookPath("iptables"); err == nil {
+		return newIPTables()
+	}
+	if _, err := exec.LookPath("nft"); err == nil {
+		return newNFTables()
+	}
+	return nil, errors.Errorf("no supported firewall found: firewalld is not running and neither iptables nor nft are installed")
+}
+
+// firewalld adds rules through the direct interface of firewalld, so that
+// firewalld knows about them
+type firewalld struct {
+	conn *dbus.Conn
+}
+
+func newFirewalld() (Firewall, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error connecting to the system bus")
+	}
+	if !firewalldRunning(conn) {
+		return nil, errors.Errorf("firewalld is not running")
+	}
+	return &firewalld{conn: conn}, nil
+}
+
+func (f *firewalld) Name() string {
+	return Firewalld
+}
+
+// call calls a method of the direct interface with the arguments of the rule
+// allowing forwarding from the address
+func (f *firewalld) call(method string, ip net.IP) *dbus.Call {
+	ipv := "ipv4"
+	if ip.To4() == nil {
+		ipv = "ipv6"
+	}
+	args := []string{"-s", ip.String(), "-j", "ACCEPT"}
+	obj := f.conn.Object(firewalldName, firewalldPath)
+	return obj.Call(firewalldDirect+"."+method, 0, ipv, "filter", "FORWARD", int32(0), args)
+}
+
+func (f *firewalld) exists(ip net.IP) (bool, error) {
+	var exists bool
+	if err := f.call("queryRule", ip).Store(&exists); err != nil {
+		return false, errors.Wrapf(err, "error querying firewalld for the rule allowing forwarding from %s", ip)
+	}
+	return exists, nil
+}
+
+func (f *firewalld) AllowForward(ip net.IP) error {
+	exists, err := f.exists(ip)
+	if err != nil || exists {
+		return err
+	}
+	if err := f.call("addRule", ip).Err; err != nil {
+		return errors.Wrapf(err, "error adding firewalld rule allowing forwarding from %s", ip)
+	}
+	return nil
+}
+
+func (f *firewalld) RemoveForward(ip net.IP) error {
+	exists, err := f.exists(ip)
+	if err != nil || !exists {
+		return err
+	}
+	if err := f.call("removeRule", ip).Err; err != nil {
+		return errors.Wrapf(err, "error removing firewalld rule allowing forwarding from %s", ip)
+	}
+	return nil
+}
+
+// WatchReloads calls reloaded whenever firewalld is reloaded or started, as it
+// then removes the rules it did not add itself, as well as its own rules that
+// were not made permanent, until the context is cancelled. Rules of all
+// drivers must be added again then.
+func WatchReloads(ctx context.Context, reloaded func()) error {
+	conn, err := dbus.SystemBusPrivate()
+	if err != nil {
+		return errors.Wrapf(err, "error connecting to the system bus")
+	}
+	defer conn.Close()
+	if err := conn.Auth(nil); err != nil {
+		return errors.Wrapf(err, "error authenticating to the system bus")
+	}
+	if err := conn.Hello(); err != nil {
+		return errors.Wrapf(err, "error connecting to the system bus")
+	}
+	for _, match := range []string{firewalldReloadSig, firewalldStartSig} {
+		if err := conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, match).Err; err != nil {
+			return errors.Wrapf(err, "error watching firewalld")
+		}
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+	defer conn.RemoveSignal(signals)
+	for {
+		select {
+		case signal, ok := <-signals:
+			if !ok {
+				return errors.Errorf("connection to the system bus closed")
+			}
+			switch signal.Name {
+			case firewalldReloaded:
+				logrus.Debugf("firewalld was reloaded")
+			case nameOwnerChanged:
+				// Only a new owner means firewalld started
+				if len(signal.Body) < 3 || signal.Body[2] == "" {
+					continue
+				}
+				logrus.Debugf("firewalld was started")
+			default:
+				continue
+			}
+			reloaded()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
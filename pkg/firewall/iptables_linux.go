@@ -0,0 +1,65 @@
+// +build linux
+
+package firewall
+
+import (
+	"net"
+	"strings"
+
+	"github.com/containers/libpod/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// execCmd runs a command, returning its output
+var execCmd = utils.ExecCmd
+
+type iptables struct{}
+
+func newIPTables() (Firewall, error) {
+	return &iptables{}, nil
+}
+
+func (f *iptables) Name() string {
+	return IPTables
+}
+
+// command returns the iptables command for the family of the address
+func (f *iptables) command(ip net.IP) string {
+	if ip.To4() == nil {
+		return "ip6tables"
+	}
+	return "iptables"
+}
+
+// forwardRule returns the arguments of the rule allowing forwarding from the
+// address. The rule is the one earlier versions added, so that their rules
+// are removed as well.
+func forwardRule(op string, ip net.IP) []string {
+	return []string{"-t", "filter", op, "FORWARD", "-s", ip.String(), "!", "-o", ip.String(), "-j", "ACCEPT"}
+}
+
+// exists returns whether the rule allowing forwarding from the address exists
+func (f *iptables) exists(ip net.IP) bool {
+	_, err := execCmd(f.command(ip), forwardRule("-C", ip)...)
+	return err == nil
+}
+
+func (f *iptables) run(ip net.IP, args []string) error {
+	logrus.Debugf("Running %s command: %s", f.command(ip), strings.Join(args, " "))
+	_, err := execCmd(f.command(ip), args...)
+	return err
+}
+
+func (f *iptables) AllowForward(ip net.IP) error {
+	if f.exists(ip) {
+		return nil
+	}
+	return f.run(ip, forwardRule("-I", ip))
+}
+
+func (f *iptables) RemoveForward(ip net.IP) error {
+	if !f.exists(ip) {
+		return nil
+	}
+	return f.run(ip, forwardRule("-D", ip))
+}
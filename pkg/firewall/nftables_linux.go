@@ -0,0 +1,79 @@
+// +build linux
+
+package firewall
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/containers/libpod/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// nftTable is the table holding the rules of libpod
+	nftTable = "inet podman"
+	// nftSetIPv4 and nftSetIPv6 hold the addresses forwarding is allowed
+	// from
+	nftSetIPv4 = "forward_allowed"
+	nftSetIPv6 = "forward_allowed6"
+)
+
+// nftSetup creates the table of libpod, its sets and the rules accepting
+// traffic from the addresses in the sets. It can be applied repeatedly, as the
+// chain is flushed before adding the rules again.
+var nftSetup = []string{
+	"add table " + nftTable,
+	"add chain " + nftTable + " forward { type filter hook forward priority 0 ; }",
+	"add set " + nftTable + " " + nftSetIPv4 + " { type ipv4_addr ; }",
+	"add set " + nftTable + " " + nftSetIPv6 + " { type ipv6_addr ; }",
+	"flush chain " + nftTable + " forward",
+	"add rule " + nftTable + " forward ip saddr @" + nftSetIPv4 + " accept",
+	"add rule " + nftTable + " forward ip6 saddr @" + nftSetIPv6 + " accept",
+}
+
+// runNFT applies a script of nft commands as a single transaction
+var runNFT = func(script string) error {
+	var stdout, stderr bytes.Buffer
+	if err := utils.ExecCmdWithStdStreams(strings.NewReader(script), &stdout, &stderr, "nft", "-f", "-"); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+type nftables struct{}
+
+func newNFTables() (Firewall, error) {
+	return &nftables{}, nil
+}
+
+func (f *nftables) Name() string {
+	return NFTables
+}
+
+// element returns the set element for the address
+func nftElement(ip net.IP) string {
+	set := nftSetIPv4
+	if ip.To4() == nil {
+		set = nftSetIPv6
+	}
+	return fmt.Sprintf("element %s %s { %s }", nftTable, set, ip.String())
+}
+
+func (f *nftables) apply(commands ...string) error {
+	script := strings.Join(append(append([]string{}, nftSetup...), commands...), "\n") + "\n"
+	logrus.Debugf("Running nft script:\n%s", script)
+	return runNFT(script)
+}
+
+func (f *nftables) AllowForward(ip net.IP) error {
+	return f.apply("add " + nftElement(ip))
+}
+
+func (f *nftables) RemoveForward(ip net.IP) error {
+	// Deleting a missing element fails, so add it first, in the same
+	// transaction
+	return f.apply("add "+nftElement(ip), "delete "+nftElement(ip))
+}
@@ -3,6 +3,7 @@ package inspect
 import (
 	"time"
 
+	"github.com/containers/image/manifest"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 	"github.com/docker/go-connections/nat"
 	"github.com/opencontainers/go-digest"
@@ -21,6 +22,7 @@ type ContainerData struct {
 type HostConfig struct {
 	ContainerIDFile      string                      `json:"ContainerIDFile"`
 	LogConfig            *LogConfig                  `json:"LogConfig"` //TODO
+	RestartPolicy        RestartPolicy               `json:"RestartPolicy"`
 	NetworkMode          string                      `json:"NetworkMode"`
 	PortBindings         nat.PortMap                 `json:"PortBindings"` //TODO
 	AutoRemove           bool                        `json:"AutoRemove"`
@@ -36,6 +38,7 @@ type HostConfig struct {
 	OomScoreAdj          *int                        `json:"OomScoreAdj"`
 	PidMode              string                      `json:"PidMode"`
 	Privileged           bool                        `json:"Privileged"`
+	PrivilegedProfile    []string                    `json:"PrivilegedProfile"`
 	PublishAllPorts      bool                        `json:"PublishAllPorts"` //TODO
 	ReadonlyRootfs       bool                        `json:"ReadonlyRootfs"`
 	SecurityOpt          []string                    `json:"SecurityOpt"`
@@ -62,6 +65,7 @@ type HostConfig struct {
 	CPUSetMems           string                      `json:"CpuSetMems"`
 	Devices              []specs.LinuxDevice         `json:"Devices"`
 	DiskQuota            int                         `json:"DiskQuota"` //check type, TODO
+	HugepageLimits       []specs.LinuxHugepageLimit  `json:"HugepageLimits"`
 	KernelMemory         *int64                      `json:"KernelMemory"`
 	MemoryReservation    *int64                      `json:"MemoryReservation"`
 	MemorySwap           *int64                      `json:"MemorySwap"`
@@ -96,6 +100,11 @@ type CtrConfig struct {
 	Labels       map[string]string   `json:"Labels"`
 	Annotations  map[string]string   `json:"Annotations"`
 	StopSignal   uint                `json:"StopSignal"`
+	// Healthcheck is the healthcheck of the container, if it has one
+	Healthcheck *manifest.Schema2HealthConfig `json:"Healthcheck,omitempty"`
+	// StartupHealthcheck is the startup healthcheck of the container, if it
+	// has one
+	StartupHealthcheck *manifest.Schema2HealthConfig `json:"StartupHealthcheck,omitempty"`
 }
 
 // LogConfig holds the log information for a container
@@ -104,6 +113,12 @@ type LogConfig struct {
 	Config map[string]string `json:"Config"` //idk type, TODO
 }
 
+// RestartPolicy holds the restart policy of a container
+type RestartPolicy struct {
+	Name              string `json:"Name"`
+	MaximumRetryCount uint   `json:"MaximumRetryCount"`
+}
+
 // ImageData holds the inspect information of an image
 type ImageData struct {
 	ID              string            `json:"Id"`
@@ -126,6 +141,9 @@ type ImageData struct {
 	Annotations     map[string]string `json:"Annotations"`
 	ManifestType    string            `json:"ManifestType"`
 	User            string            `json:"User"`
+	// HealthCheck is the healthcheck of containers of the image, if it
+	// has one
+	HealthCheck *manifest.Schema2HealthConfig `json:"Healthcheck,omitempty"`
 }
 
 // RootFS holds the root fs information of an image
@@ -156,7 +174,7 @@ type ContainerInspectData struct {
 	StaticDir       string                 `json:"StaticDir"`
 	LogPath         string                 `json:"LogPath"`
 	Name            string                 `json:"Name"`
-	RestartCount    int32                  `json:"RestartCount"` //TODO
+	RestartCount    int32                  `json:"RestartCount"`
 	Driver          string                 `json:"Driver"`
 	MountLabel      string                 `json:"MountLabel"`
 	ProcessLabel    string                 `json:"ProcessLabel"`
@@ -173,6 +191,47 @@ type ContainerInspectData struct {
 	ExitCommand     []string               `json:"ExitCommand"`
 	Namespace       string                 `json:"Namespace"`
 	IsInfra         bool                   `json:"IsInfra"`
+	SecurityConfig  *SecurityConfig        `json:"SecurityConfig"`
+	SystemdUnits    *SystemdUnits          `json:"SystemdUnits"`
+}
+
+// SystemdUnits are the systemd units of a container
+type SystemdUnits struct {
+	// Service is the service unit managing the container: the value of
+	// its PODMAN_SYSTEMD_UNIT label, or else the service it was started
+	// from
+	Service string `json:"Service,omitempty"`
+	// Scope is the scope of the processes of the container, with the
+	// systemd cgroup manager
+	Scope string `json:"Scope,omitempty"`
+	// ConmonScope is the scope of the conmon process of the container,
+	// with the systemd cgroup manager
+	ConmonScope string `json:"ConmonScope,omitempty"`
+}
+
+// SecurityConfig holds the effective security settings of a container, as
+// applied by its OCI spec
+type SecurityConfig struct {
+	Privileged bool `json:"Privileged"`
+	// SeccompProfileHash is the digest of the seccomp filter of the
+	// container, empty if it runs unconfined
+	SeccompProfileHash string                 `json:"SeccompProfileHash"`
+	AppArmorProfile    string                 `json:"AppArmorProfile"`
+	ProcessLabel       string                 `json:"ProcessLabel"`
+	MountLabel         string                 `json:"MountLabel"`
+	NoNewPrivileges    bool                   `json:"NoNewPrivileges"`
+	Capabilities       *SecurityCapabilities  `json:"Capabilities"`
+	UIDMap             []specs.LinuxIDMapping `json:"UIDMap"`
+	GIDMap             []specs.LinuxIDMapping `json:"GIDMap"`
+}
+
+// SecurityCapabilities holds the capability sets of a container
+type SecurityCapabilities struct {
+	Bounding    []string `json:"Bounding"`
+	Effective   []string `json:"Effective"`
+	Inheritable []string `json:"Inheritable"`
+	Permitted   []string `json:"Permitted"`
+	Ambient     []string `json:"Ambient"`
 }
 
 // ContainerInspectState represents the state of a container.
@@ -189,6 +248,38 @@ type ContainerInspectState struct {
 	Error      string    `json:"Error"` // TODO
 	StartedAt  time.Time `json:"StartedAt"`
 	FinishedAt time.Time `json:"FinishedAt"`
+
+	// PidsCurrent and PidsLimit are the number of processes of a running
+	// container and their limit, 0 if they are not limited
+	PidsCurrent uint64 `json:"PidsCurrent,omitempty"`
+	PidsLimit   uint64 `json:"PidsLimit,omitempty"`
+
+	// Healthcheck holds the results of the healthcheck of the container,
+	// if it has one
+	Healthcheck *HealthCheckResults `json:"Healthcheck,omitempty"`
+}
+
+// HealthCheckResults describes the health of a container and the log of its
+// last healthchecks
+type HealthCheckResults struct {
+	// Status is starting, healthy or unhealthy
+	Status string `json:"Status"`
+	// FailingStreak is the number of consecutive failed healthchecks
+	FailingStreak int `json:"FailingStreak"`
+	// Log holds the last healthchecks, the latest last
+	Log []HealthCheckLog `json:"Log"`
+}
+
+// HealthCheckLog describes the result of a single healthcheck
+type HealthCheckLog struct {
+	// Start is the time the healthcheck started
+	Start time.Time `json:"Start"`
+	// End is the time the healthcheck ended
+	End time.Time `json:"End"`
+	// ExitCode is the exit code of the healthcheck command, 0 when healthy
+	ExitCode int `json:"ExitCode"`
+	// Output is the output of the healthcheck command, truncated
+	Output string `json:"Output"`
 }
 
 // NetworkSettings holds information about the newtwork settings of the container
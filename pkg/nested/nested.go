@@ -0,0 +1,130 @@
+// Package nested detects when podman runs inside a container and picks
+// defaults that work there.
+package nested
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ContainerenvPath is the file libpod creates in its containers to tell
+	// them they run in a container
+	ContainerenvPath = "/run/.containerenv"
+	// nestingKey is the key of the nesting level in the containerenv file
+	nestingKey = "nesting"
+
+	// fuseDevice is the device fuse-overlayfs needs
+	fuseDevice = "/dev/fuse"
+	// fuseOverlayfs is the name of the fuse-overlayfs binary
+	fuseOverlayfs = "fuse-overlayfs"
+)
+
+// Level returns how deep in nested containers the current process runs: 0 on
+// the host, 1 in a container, 2 in a container created by podman running in
+// a container, and so on
+func Level() int {
+	return level(ContainerenvPath, os.Getenv("container"))
+}
+
+func level(containerenv, containerVar string) int {
+	f, err := os.Open(containerenv)
+	if err != nil {
+		// Other container engines set the container environment
+		// variable, as systemd expects
+		if containerVar != "" {
+			return 1
+		}
+		return 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		kv := strings.SplitN(scanner.Text(), "=", 2)
+		if len(kv) != 2 || kv[0] != nestingKey {
+			continue
+		}
+		if n, err := strconv.Atoi(kv[1]); err == nil && n > 0 {
+			return n
+		}
+	}
+	// Created by a podman that did not record the nesting level
+	return 1
+}
+
+// Containerenv returns the content of the containerenv file of a container
+// created at the current nesting level
+func Containerenv() string {
+	return fmt.Sprintf("engine=\"podman\"\n%s=%d\n", nestingKey, Level()+1)
+}
+
+// SystemdRunning returns whether systemd is the init system, so it can manage
+// the cgroups of containers. It usually is not in a container.
+func SystemdRunning() bool {
+	_, err := os.Stat("/run/systemd/system")
+	return err == nil
+}
+
+// StoreOptions adapts the storage driver to a nested container, where
+// overlay usually can not be mounted on top of the overlay of the container.
+// Overlay is kept when it can be mounted natively under the graph root, such
+// as on a volume of the container. Otherwise, without a configured driver,
+// overlay is picked if fuse-overlayfs can mount it, vfs otherwise, and a
+// configured overlay driver is mounted with fuse-overlayfs, and is an error
+// without it. Options on the host are returned as they are.
+func StoreOptions(opts storage.StoreOptions) (storage.StoreOptions, error) {
+	if Level() == 0 {
+		return opts, nil
+	}
+	return nestedStoreOptions(opts, nativeOverlayAvailable, fuseOverlayfsAvailable)
+}
+
+// nestedStoreOptions adapts the storage driver to a nested container, given
+// whether overlay can be mounted natively under a graph root and the path of
+// fuse-overlayfs if it can be used. They are only checked when overlay could
+// be the driver.
+func nestedStoreOptions(opts storage.StoreOptions, nativeOverlay func(graphRoot string) bool, fuse func() string) (storage.StoreOptions, error) {
+	if opts.GraphDriverName != "" && opts.GraphDriverName != "overlay" {
+		return opts, nil
+	}
+	for _, opt := range opts.GraphDriverOptions {
+		if strings.Contains(opt, "mount_program") {
+			return opts, nil
+		}
+	}
+	if nativeOverlay(opts.GraphRoot) {
+		opts.GraphDriverName = "overlay"
+		return opts, nil
+	}
+	if path := fuse(); path != "" {
+		opts.GraphDriverName = "overlay"
+		opts.GraphDriverOptions = append(append([]string{}, opts.GraphDriverOptions...), "overlay.mount_program="+path)
+		return opts, nil
+	}
+	if opts.GraphDriverName == "overlay" {
+		return opts, errors.Errorf("the overlay storage driver can not be mounted under %s in a nested container, nor with %s and %s: install them or use --storage-driver vfs", opts.GraphRoot, fuseOverlayfs, fuseDevice)
+	}
+	opts.GraphDriverName = "vfs"
+	opts.GraphDriverOptions = nil
+	return opts, nil
+}
+
+// fuseOverlayfsAvailable returns the path of fuse-overlayfs if it can be used
+func fuseOverlayfsAvailable() string {
+	if _, err := os.Stat(fuseDevice); err != nil {
+		return ""
+	}
+	path, err := exec.LookPath(fuseOverlayfs)
+	if err != nil {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,46 @@
+// +build linux
+
+package nested
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// nativeOverlayAvailable returns whether the kernel can mount an overlay
+// under graphRoot, by mounting one in a scratch directory. It can not on top
+// of the overlay of the container, but can on a volume of the container.
+func nativeOverlayAvailable(graphRoot string) bool {
+	if graphRoot == "" {
+		return false
+	}
+	if err := os.MkdirAll(graphRoot, 0700); err != nil {
+		return false
+	}
+	dir, err := ioutil.TempDir(graphRoot, "overlay-probe")
+	if err != nil {
+		return false
+	}
+	defer os.RemoveAll(dir)
+
+	for _, sub := range []string{"lower", "upper", "work", "merged"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0700); err != nil {
+			return false
+		}
+	}
+	merged := filepath.Join(dir, "merged")
+	data := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", filepath.Join(dir, "lower"), filepath.Join(dir, "upper"), filepath.Join(dir, "work"))
+	if err := unix.Mount("overlay", merged, "overlay", 0, data); err != nil {
+		logrus.Debugf("Overlay can not be mounted natively under %s: %v", graphRoot, err)
+		return false
+	}
+	if err := unix.Unmount(merged, unix.MNT_DETACH); err != nil {
+		logrus.Debugf("Error unmounting overlay probe %s: %v", merged, err)
+	}
+	return true
+}
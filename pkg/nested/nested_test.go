@@ -0,0 +1,73 @@
+package nested
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nested")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	containerenv := filepath.Join(dir, ".containerenv")
+	assert.Equal(t, 0, level(containerenv, ""))
+	assert.Equal(t, 1, level(containerenv, "docker"))
+
+	require.NoError(t, ioutil.WriteFile(containerenv, []byte(""), 0644))
+	assert.Equal(t, 1, level(containerenv, ""))
+
+	require.NoError(t, ioutil.WriteFile(containerenv, []byte("engine=\"podman\"\nnesting=2\n"), 0644))
+	assert.Equal(t, 2, level(containerenv, ""))
+}
+
+func TestNestedStoreOptions(t *testing.T) {
+	native := func(string) bool { return true }
+	noNative := func(string) bool { return false }
+	fuse := func() string { return "/usr/bin/fuse-overlayfs" }
+	noFuse := func() string { return "" }
+
+	opts, err := nestedStoreOptions(storage.StoreOptions{}, noNative, noFuse)
+	require.NoError(t, err)
+	assert.Equal(t, "vfs", opts.GraphDriverName)
+
+	opts, err = nestedStoreOptions(storage.StoreOptions{}, noNative, fuse)
+	require.NoError(t, err)
+	assert.Equal(t, "overlay", opts.GraphDriverName)
+	assert.Equal(t, []string{"overlay.mount_program=/usr/bin/fuse-overlayfs"}, opts.GraphDriverOptions)
+
+	opts, err = nestedStoreOptions(storage.StoreOptions{GraphDriverName: "overlay"}, noNative, fuse)
+	require.NoError(t, err)
+	assert.Equal(t, "overlay", opts.GraphDriverName)
+	assert.Equal(t, []string{"overlay.mount_program=/usr/bin/fuse-overlayfs"}, opts.GraphDriverOptions)
+
+	// Native overlay needs no mount program
+	opts, err = nestedStoreOptions(storage.StoreOptions{}, native, fuse)
+	require.NoError(t, err)
+	assert.Equal(t, "overlay", opts.GraphDriverName)
+	assert.Empty(t, opts.GraphDriverOptions)
+
+	opts, err = nestedStoreOptions(storage.StoreOptions{GraphDriverName: "overlay"}, native, noFuse)
+	require.NoError(t, err)
+	assert.Equal(t, "overlay", opts.GraphDriverName)
+	assert.Empty(t, opts.GraphDriverOptions)
+
+	// A configured overlay driver is not replaced
+	_, err = nestedStoreOptions(storage.StoreOptions{GraphDriverName: "overlay"}, noNative, noFuse)
+	assert.Error(t, err)
+
+	// A mount program set by the user is kept
+	opts, err = nestedStoreOptions(storage.StoreOptions{GraphDriverName: "overlay", GraphDriverOptions: []string{"overlay.mount_program=/bin/true"}}, noNative, noFuse)
+	require.NoError(t, err)
+	assert.Equal(t, "overlay", opts.GraphDriverName)
+
+	opts, err = nestedStoreOptions(storage.StoreOptions{GraphDriverName: "btrfs"}, noNative, noFuse)
+	require.NoError(t, err)
+	assert.Equal(t, "btrfs", opts.GraphDriverName)
+}
@@ -0,0 +1,8 @@
+// +build !linux
+
+package nested
+
+// nativeOverlayAvailable returns false on unsupported OS's
+func nativeOverlayAvailable(graphRoot string) bool {
+	return false
+}
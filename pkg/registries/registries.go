@@ -13,8 +13,10 @@ import (
 // userRegistriesFile is the path to the per user registry configuration file.
 var userRegistriesFile = filepath.Join(os.Getenv("HOME"), ".config/containers/registries.conf")
 
-// GetRegistries obtains the list of registries defined in the global registries file.
-func GetRegistries() ([]string, error) {
+// SystemRegistriesConfPath returns the path of the global registries file:
+// REGISTRIES_CONFIG_PATH, or the file of rootless users, or else the
+// system-wide file.
+func SystemRegistriesConfPath() string {
 	registryConfigPath := ""
 
 	if rootless.IsRootless() {
@@ -27,7 +29,12 @@ func GetRegistries() ([]string, error) {
 	if len(envOverride) > 0 {
 		registryConfigPath = envOverride
 	}
-	searchRegistries, err := sysregistries.GetRegistries(&types.SystemContext{SystemRegistriesConfPath: registryConfigPath})
+	return sysregistries.RegistriesConfPath(&types.SystemContext{SystemRegistriesConfPath: registryConfigPath})
+}
+
+// GetRegistries obtains the list of registries defined in the global registries file.
+func GetRegistries() ([]string, error) {
+	searchRegistries, err := sysregistries.GetRegistries(&types.SystemContext{SystemRegistriesConfPath: SystemRegistriesConfPath()})
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to parse the registries.conf file")
 	}
@@ -52,3 +59,30 @@ func GetInsecureRegistries() ([]string, error) {
 	}
 	return registries, nil
 }
+
+// GetRegistriesFromFile obtains the list of registries defined in a
+// registries file, or in the global registries file if path is empty.
+func GetRegistriesFromFile(path string) ([]string, error) {
+	if path == "" {
+		return GetRegistries()
+	}
+	searchRegistries, err := sysregistries.GetRegistries(&types.SystemContext{SystemRegistriesConfPath: path})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the registries file %s", path)
+	}
+	return searchRegistries, nil
+}
+
+// GetInsecureRegistriesFromFile obtains the list of insecure registries
+// defined in a registries file, or in the global registries file if path is
+// empty.
+func GetInsecureRegistriesFromFile(path string) ([]string, error) {
+	if path == "" {
+		return GetInsecureRegistries()
+	}
+	registries, err := sysregistries.GetInsecureRegistries(&types.SystemContext{SystemRegistriesConfPath: path})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the registries file %s", path)
+	}
+	return registries, nil
+}
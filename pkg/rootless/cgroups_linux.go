@@ -0,0 +1,44 @@
+// +build linux
+
+package rootless
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/libpod/pkg/cgroups"
+)
+
+// UserCgroup returns the cgroup of the systemd instance of the rootless user,
+// under which it creates the cgroups of rootless containers
+func UserCgroup() string {
+	uid := GetRootlessUID()
+	return fmt.Sprintf("/user.slice/user-%d.slice/user@%d.service", uid, uid)
+}
+
+// CgroupV2Controllers returns the cgroup v2 controllers systemd delegates to
+// the rootless user, which can then limit the resources of its containers.
+// It returns nil if the host does not use the unified cgroup v2 hierarchy,
+// or if the user has no systemd session to create cgroups through.
+func CgroupV2Controllers() []string {
+	if !userSystemdRunning() {
+		return nil
+	}
+	return cgroups.Controllers(UserCgroup())
+}
+
+// userSystemdRunning returns whether the systemd instance of the user can be
+// reached on its D-Bus session bus, which the OCI runtime uses to create
+// cgroups
+func userSystemdRunning() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+		return true
+	}
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(runtimeDir, "bus"))
+	return err == nil
+}
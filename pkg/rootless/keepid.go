@@ -0,0 +1,45 @@
+package rootless
+
+import (
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/pkg/errors"
+)
+
+// GetKeepIDMappings returns the UID and GID mappings of a user namespace
+// where the user keeps its UID and GID, for the keep-id user namespace mode.
+// The mappings are relative to the rootless user namespace podman runs in.
+func GetKeepIDMappings() ([]idtools.IDMap, []idtools.IDMap, error) {
+	if !IsRootless() {
+		return nil, nil, errors.New("keep-id is only supported in rootless mode")
+	}
+	uids, gids, err := GetConfiguredMappings()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot read the additional IDs of the user")
+	}
+	return keepIDMappings(GetRootlessUID(), uids), keepIDMappings(GetRootlessGID(), gids), nil
+}
+
+// keepIDMappings returns the mappings keeping id, the ID of the user on the
+// host. In the rootless user namespace the user is 0 and its additional IDs,
+// configured, follow from 1: id is mapped to 0 and the IDs around it to the
+// additional IDs.
+func keepIDMappings(id int, configured []idtools.IDMap) []idtools.IDMap {
+	size := 0
+	for _, m := range configured {
+		size += m.Size
+	}
+
+	var mappings []idtools.IDMap
+	below := id
+	if size < below {
+		below = size
+	}
+	if below > 0 {
+		mappings = append(mappings, idtools.IDMap{ContainerID: 0, HostID: 1, Size: below})
+	}
+	mappings = append(mappings, idtools.IDMap{ContainerID: id, HostID: 0, Size: 1})
+	if size > id {
+		mappings = append(mappings, idtools.IDMap{ContainerID: id + 1, HostID: id + 1, Size: size - id})
+	}
+	return mappings
+}
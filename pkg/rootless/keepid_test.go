@@ -0,0 +1,28 @@
+package rootless
+
+import (
+	"testing"
+
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepIDMappings(t *testing.T) {
+	configured := []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}}
+	assert.Equal(t, []idtools.IDMap{
+		{ContainerID: 0, HostID: 1, Size: 1000},
+		{ContainerID: 1000, HostID: 0, Size: 1},
+		{ContainerID: 1001, HostID: 1001, Size: 64536},
+	}, keepIDMappings(1000, configured))
+
+	// Without additional IDs only the user is mapped
+	assert.Equal(t, []idtools.IDMap{
+		{ContainerID: 1000, HostID: 0, Size: 1},
+	}, keepIDMappings(1000, nil))
+
+	// IDs above the additional IDs are mapped below the user only
+	assert.Equal(t, []idtools.IDMap{
+		{ContainerID: 0, HostID: 1, Size: 65536},
+		{ContainerID: 200000, HostID: 0, Size: 1},
+	}, keepIDMappings(200000, configured))
+}
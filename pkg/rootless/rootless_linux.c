@@ -11,6 +11,9 @@
 #include <signal.h>
 #include <fcntl.h>
 #include <sys/wait.h>
+#include <sys/prctl.h>
+#include <dirent.h>
+#include <string.h>
 
 static int
 syscall_clone (unsigned long flags, void *child_stack)
@@ -82,8 +85,131 @@ get_cmd_line_args (pid_t pid)
   return argv;
 }
 
+static void
+close_fds_from (int first)
+{
+  DIR *d;
+  struct dirent *de;
+  int fd;
+
+  d = opendir ("/proc/self/fd");
+  if (d == NULL)
+    return;
+
+  while ((de = readdir (d)) != NULL)
+    {
+      fd = atoi (de->d_name);
+      if (fd >= first && fd != dirfd (d))
+        close (fd);
+    }
+  closedir (d);
+}
+
+/* Fork a process that does nothing but keep the user and mount namespaces
+   of its parent alive, so later podman processes can join them.  Its pid is
+   written to pause_pid_file_path.  */
+static int
+create_pause_process (const char *pause_pid_file_path)
+{
+  pid_t pid;
+  int fd;
+  char pid_str[16];
+  char tmp_file_path[PATH_MAX];
+
+  if (strlen (pause_pid_file_path) + 8 > PATH_MAX)
+    return -1;
+
+  pid = fork ();
+  if (pid < 0)
+    return -1;
+  if (pid)
+    return 0;
+
+  setsid ();
+  close_fds_from (3);
+
+  fd = open ("/dev/null", O_RDWR);
+  if (fd >= 0)
+    {
+      dup2 (fd, 0);
+      dup2 (fd, 1);
+      dup2 (fd, 2);
+      if (fd > 2)
+        close (fd);
+    }
+
+  sprintf (tmp_file_path, "%s.XXXXXX", pause_pid_file_path);
+  fd = mkstemp (tmp_file_path);
+  if (fd < 0)
+    _exit (EXIT_FAILURE);
+
+  sprintf (pid_str, "%d", getpid ());
+  if (write (fd, pid_str, strlen (pid_str)) < 0 || close (fd) < 0)
+    {
+      unlink (tmp_file_path);
+      _exit (EXIT_FAILURE);
+    }
+
+  /* Replace any pid file left by a pause process that is gone.  */
+  if (rename (tmp_file_path, pause_pid_file_path) < 0)
+    {
+      unlink (tmp_file_path);
+      _exit (EXIT_FAILURE);
+    }
+
+  prctl (PR_SET_NAME, "podman pause", NULL, NULL, NULL);
+  for (;;)
+    pause ();
+}
+
 int
-reexec_in_user_namespace(int ready)
+reexec_userns_join (int userns, int mountns)
+{
+  pid_t ppid = getpid ();
+  char uid[16];
+  char gid[16];
+  char **argv;
+  int pid;
+
+  sprintf (uid, "%d", geteuid ());
+  sprintf (gid, "%d", getegid ());
+
+  argv = get_cmd_line_args (ppid);
+  if (argv == NULL)
+    return -1;
+
+  pid = fork ();
+  if (pid)
+    {
+      free (argv[0]);
+      free (argv);
+      return pid;
+    }
+
+  /* The child is single threaded, so it can join another user namespace.  */
+  if (setns (userns, 0) < 0)
+    _exit (EXIT_FAILURE);
+  close (userns);
+
+  if (setns (mountns, 0) < 0)
+    _exit (EXIT_FAILURE);
+  close (mountns);
+
+  setenv ("_LIBPOD_USERNS_CONFIGURED", "init", 1);
+  setenv ("_LIBPOD_ROOTLESS_UID", uid, 1);
+  setenv ("_LIBPOD_ROOTLESS_GID", gid, 1);
+
+  if (setresgid (0, 0, 0) < 0 ||
+      setresuid (0, 0, 0) < 0)
+    _exit (EXIT_FAILURE);
+
+  execvp (argv[0], argv);
+
+  _exit (EXIT_FAILURE);
+}
+
+int
+reexec_in_user_namespace(int ready, char *pause_pid_file_path)
 {
   int ret;
   pid_t pid;
@@ -91,8 +217,10 @@ reexec_in_user_namespace(int ready)
   pid_t ppid = getpid ();
   char **argv;
   char uid[16];
+  char gid[16];
 
   sprintf (uid, "%d", geteuid ());
+  sprintf (gid, "%d", getegid ());
 
   pid = syscall_clone (CLONE_NEWUSER|CLONE_NEWNS|SIGCHLD, NULL);
   if (pid)
@@ -104,6 +232,7 @@ reexec_in_user_namespace(int ready)
 
   setenv ("_LIBPOD_USERNS_CONFIGURED", "init", 1);
   setenv ("_LIBPOD_ROOTLESS_UID", uid, 1);
+  setenv ("_LIBPOD_ROOTLESS_GID", gid, 1);
 
   do
     ret = read (ready, &b, 1) < 0;
@@ -116,6 +245,12 @@ reexec_in_user_namespace(int ready)
       setresuid (0, 0, 0) < 0)
     _exit (EXIT_FAILURE);
 
+  if (pause_pid_file_path && pause_pid_file_path[0] != '\0')
+    {
+      if (create_pause_process (pause_pid_file_path) < 0)
+        _exit (EXIT_FAILURE);
+    }
+
   execvp (argv[0], argv);
 
   _exit (EXIT_FAILURE);
@@ -21,8 +21,10 @@ import (
 )
 
 /*
-extern int reexec_in_user_namespace(int ready);
+#include <stdlib.h>
+extern int reexec_in_user_namespace(int ready, char *pause_pid_file_path);
 extern int reexec_in_user_namespace_wait(int pid);
+extern int reexec_userns_join(int userns, int mountns);
 */
 import "C"
 
@@ -60,6 +62,37 @@ func GetRootlessUID() int {
 	return os.Getuid()
 }
 
+// GetRootlessGID returns the GID of the user in the parent userNS
+func GetRootlessGID() int {
+	gidEnv := os.Getenv("_LIBPOD_ROOTLESS_GID")
+	if gidEnv != "" {
+		g, _ := strconv.Atoi(gidEnv)
+		return g
+	}
+	return os.Getgid()
+}
+
+// GetConfiguredMappings returns the additional UIDs and GIDs of the user,
+// configured in /etc/subuid and /etc/subgid
+func GetConfiguredMappings() ([]idtools.IDMap, []idtools.IDMap, error) {
+	username := os.Getenv("USER")
+	if username == "" {
+		user, err := user.LookupId(fmt.Sprintf("%d", GetRootlessUID()))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not find user by UID nor USER env was set")
+		}
+		username = user.Username
+	}
+	if err := CheckSubIDs(username, GetRootlessUID(), GetRootlessGID()); err != nil {
+		return nil, nil, err
+	}
+	mappings, err := idtools.NewIDMappings(username, username)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mappings.UIDs(), mappings.GIDs(), nil
+}
+
 func tryMappingTool(tool string, pid int, hostID int, mappings []idtools.IDMap) error {
 	path, err := exec.LookPath(tool)
 	if err != nil {
@@ -84,16 +117,72 @@ func tryMappingTool(tool string, pid int, hostID int, mappings []idtools.IDMap)
 	return cmd.Run()
 }
 
+// inUserNS returns whether podman already runs as root, or in the user
+// namespace it was re-executed in
+func inUserNS() (bool, error) {
+	if os.Getuid() == 0 || os.Getenv("_LIBPOD_USERNS_CONFIGURED") != "" {
+		if os.Getenv("_LIBPOD_USERNS_CONFIGURED") == "init" {
+			return true, runInUser()
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// JoinUserAndMountNS re-exec podman in the user and mount namespaces of the process pid,
+// usually the pause process created by BecomeRootInUserNS, so podman sees the same storage
+// and mounts as the podman processes before it.  It returns whether podman was re-executed
+// and the return code from the re-executed podman process.
+// If podman was re-executed the caller needs to propagate the error code returned by the child
+// process.
+func JoinUserAndMountNS(pid uint) (bool, int, error) {
+	if in, err := inUserNS(); in || err != nil {
+		return false, 0, err
+	}
+
+	userNSPath := fmt.Sprintf("/proc/%d/ns/user", pid)
+	currentNS, err := readUserNs("/proc/self/ns/user")
+	if err != nil {
+		return false, -1, err
+	}
+	ns, err := readUserNs(userNSPath)
+	if err != nil {
+		return false, -1, errors.Wrapf(err, "cannot read user namespace of process %d", pid)
+	}
+	if ns == currentNS {
+		return false, -1, errors.Errorf("process %d is not in a child user namespace", pid)
+	}
+
+	userNS, err := os.Open(userNSPath)
+	if err != nil {
+		return false, -1, errors.Wrapf(err, "cannot open %s", userNSPath)
+	}
+	defer userNS.Close()
+
+	mountNSPath := fmt.Sprintf("/proc/%d/ns/mnt", pid)
+	mountNS, err := os.Open(mountNSPath)
+	if err != nil {
+		return false, -1, errors.Wrapf(err, "cannot open %s", mountNSPath)
+	}
+	defer mountNS.Close()
+
+	pidC := C.reexec_userns_join(C.int(userNS.Fd()), C.int(mountNS.Fd()))
+	if int(pidC) < 0 {
+		return false, -1, errors.Errorf("cannot re-exec process")
+	}
+
+	return waitAndProxySignalsToChild(pidC)
+}
+
 // BecomeRootInUserNS re-exec podman in a new userNS.  It returns whether podman was re-executed
 // into a new user namespace and the return code from the re-executed podman process.
 // If podman was re-executed the caller needs to propagate the error code returned by the child
 // process.
-func BecomeRootInUserNS() (bool, int, error) {
-	if os.Getuid() == 0 || os.Getenv("_LIBPOD_USERNS_CONFIGURED") != "" {
-		if os.Getenv("_LIBPOD_USERNS_CONFIGURED") == "init" {
-			return false, 0, runInUser()
-		}
-		return false, 0, nil
+// If pausePid is not empty, a pause process keeping the new user and mount namespaces alive is
+// created and its pid is written to the file pausePid, for JoinUserAndMountNS.
+func BecomeRootInUserNS(pausePid string) (bool, int, error) {
+	if in, err := inUserNS(); in || err != nil {
+		return false, 0, err
 	}
 
 	runtime.LockOSThread()
@@ -106,34 +195,27 @@ func BecomeRootInUserNS() (bool, int, error) {
 	defer r.Close()
 	defer w.Close()
 
-	pidC := C.reexec_in_user_namespace(C.int(r.Fd()))
+	if pausePid != "" {
+		if err := os.MkdirAll(filepath.Dir(pausePid), 0700); err != nil {
+			return false, -1, errors.Wrapf(err, "cannot create directory for %s", pausePid)
+		}
+	}
+	pausePidC := C.CString(pausePid)
+	defer C.free(unsafe.Pointer(pausePidC))
+
+	pidC := C.reexec_in_user_namespace(C.int(r.Fd()), pausePidC)
 	pid := int(pidC)
 	if pid < 0 {
 		return false, -1, errors.Errorf("cannot re-exec process")
 	}
 
-	var uids, gids []idtools.IDMap
-	username := os.Getenv("USER")
-	if username == "" {
-		user, err := user.LookupId(fmt.Sprintf("%d", os.Geteuid()))
-		if err != nil && os.Getenv("PODMAN_ALLOW_SINGLE_ID_MAPPING_IN_USERNS") == "" {
-			return false, 0, errors.Wrapf(err, "could not find user by UID nor USER env was set")
-		}
-		if err == nil {
-			username = user.Username
-		}
-	}
-	mappings, err := idtools.NewIDMappings(username, username)
+	uids, gids, err := GetConfiguredMappings()
 	if err != nil && os.Getenv("PODMAN_ALLOW_SINGLE_ID_MAPPING_IN_USERNS") == "" {
 		return false, -1, err
 	}
-	if err == nil {
-		uids = mappings.UIDs()
-		gids = mappings.GIDs()
-	}
 
 	uidsMapped := false
-	if mappings != nil && uids != nil {
+	if uids != nil {
 		uidsMapped = tryMappingTool("newuidmap", pid, os.Getuid(), uids) == nil
 	}
 	if !uidsMapped {
@@ -151,7 +233,7 @@ func BecomeRootInUserNS() (bool, int, error) {
 	}
 
 	gidsMapped := false
-	if mappings != nil && gids != nil {
+	if gids != nil {
 		gidsMapped = tryMappingTool("newgidmap", pid, os.Getgid(), gids) == nil
 	}
 	if !gidsMapped {
@@ -167,6 +249,12 @@ func BecomeRootInUserNS() (bool, int, error) {
 		return false, -1, errors.Wrapf(err, "write to sync pipe")
 	}
 
+	return waitAndProxySignalsToChild(pidC)
+}
+
+// waitAndProxySignalsToChild forwards the signals podman receives to the
+// re-executed podman process and returns its exit code once it exits
+func waitAndProxySignalsToChild(pidC C.int) (bool, int, error) {
 	c := make(chan os.Signal, 1)
 
 	gosignal.Notify(c)
@@ -183,7 +271,7 @@ func BecomeRootInUserNS() (bool, int, error) {
 
 	ret := C.reexec_in_user_namespace_wait(pidC)
 	if ret < 0 {
-		return false, -1, errors.Wrapf(err, "error waiting for the re-exec process")
+		return false, -1, errors.New("error waiting for the re-exec process")
 	}
 
 	return true, int(ret), nil
@@ -5,6 +5,7 @@ package rootless
 import (
 	"os"
 
+	"github.com/containers/storage/pkg/idtools"
 	"github.com/pkg/errors"
 )
 
@@ -15,7 +16,13 @@ func IsRootless() bool {
 
 // BecomeRootInUserNS is a stub function that always returns false and an
 // error on unsupported OS's
-func BecomeRootInUserNS() (bool, int, error) {
+func BecomeRootInUserNS(pausePid string) (bool, int, error) {
+	return false, -1, errors.New("this function is not supported on this os")
+}
+
+// JoinUserAndMountNS is a stub function that always returns false and an
+// error on unsupported OS's
+func JoinUserAndMountNS(pid uint) (bool, int, error) {
 	return false, -1, errors.New("this function is not supported on this os")
 }
 
@@ -24,6 +31,16 @@ func GetRootlessUID() int {
 	return -1
 }
 
+// GetRootlessGID returns the GID of the user in the parent userNS
+func GetRootlessGID() int {
+	return -1
+}
+
+// GetConfiguredMappings returns an error on unsupported OS's
+func GetConfiguredMappings() ([]idtools.IDMap, []idtools.IDMap, error) {
+	return nil, nil, errors.New("this function is not supported on this os")
+}
+
 // SetSkipStorageSetup tells the runtime to not setup containers/storage
 func SetSkipStorageSetup(bool) {
 }
@@ -37,3 +54,13 @@ func SkipStorageSetup() bool {
 func GetUserNSForPid(pid uint) (*os.File, error) {
 	return nil, errors.New("this function is not supported on this os")
 }
+
+// UserCgroup returns an empty string on unsupported OS's
+func UserCgroup() string {
+	return ""
+}
+
+// CgroupV2Controllers returns nil on unsupported OS's
+func CgroupV2Controllers() []string {
+	return nil
+}
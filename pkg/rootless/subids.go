@@ -0,0 +1,171 @@
+package rootless
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SubUIDFile is the file configuring the subordinate UIDs of the users
+	SubUIDFile = "/etc/subuid"
+	// SubGIDFile is the file configuring the subordinate GIDs of the users
+	SubGIDFile = "/etc/subgid"
+	// DefaultSubIDSize is the number of subordinate IDs allocated to a user,
+	// enough to map all the IDs used by images
+	DefaultSubIDSize = 65536
+	// firstSubID is the first subordinate ID allocated, above the IDs used
+	// by the users of the host
+	firstSubID = 100000
+)
+
+// SubIDRange is a range of subordinate IDs of a user
+type SubIDRange struct {
+	Start int
+	Size  int
+}
+
+// End returns the last ID of the range
+func (r SubIDRange) End() int {
+	return r.Start + r.Size - 1
+}
+
+// subIDEntry is an entry of a subordinate ID file, for a user given by name
+// or ID
+type subIDEntry struct {
+	user string
+	SubIDRange
+}
+
+// readSubIDFile returns the entries of a subordinate ID file, none if the file
+// does not exist
+func readSubIDFile(path string) ([]subIDEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "cannot open %s", path)
+	}
+	defer f.Close()
+
+	var entries []subIDEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) != 3 {
+			return nil, errors.Errorf("invalid line %q in %s", line, path)
+		}
+		start, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid start of range %q in %s", line, path)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid size of range %q in %s", line, path)
+		}
+		entries = append(entries, subIDEntry{user: fields[0], SubIDRange: SubIDRange{Start: start, Size: size}})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "cannot read %s", path)
+	}
+	return entries, nil
+}
+
+// SubIDRanges returns the ranges of a user, given by its name and ID, in a
+// subordinate ID file
+func SubIDRanges(path, name string, id int) ([]SubIDRange, error) {
+	entries, err := readSubIDFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ranges []SubIDRange
+	for _, e := range entries {
+		if e.user == name || e.user == strconv.Itoa(id) {
+			ranges = append(ranges, e.SubIDRange)
+		}
+	}
+	return ranges, nil
+}
+
+// CheckSubIDs verifies that a user has subordinate UIDs and GIDs, which
+// rootless podman needs to map more than the ID of the user in its user
+// namespace.  The error tells how to allocate the missing ones.
+func CheckSubIDs(name string, uid, gid int) error {
+	for _, f := range []struct {
+		path, kind string
+		id         int
+	}{
+		{SubUIDFile, "UIDs", uid},
+		{SubGIDFile, "GIDs", gid},
+	} {
+		ranges, err := SubIDRanges(f.path, name, f.id)
+		if err != nil {
+			return err
+		}
+		if len(ranges) == 0 {
+			return errors.Errorf("user %s has no subordinate %s in %s, they can be allocated by running \"podman system subids %s\" as root", name, f.kind, f.path, name)
+		}
+	}
+	return nil
+}
+
+// AllocateSubIDs allocates size subordinate IDs to a user in a subordinate ID
+// file, after all the ranges already allocated, and returns the new range.
+// The file is created if it does not exist.
+func AllocateSubIDs(path, name string, size int) (SubIDRange, error) {
+	entries, err := readSubIDFile(path)
+	if err != nil {
+		return SubIDRange{}, err
+	}
+	r := SubIDRange{Start: firstSubID, Size: size}
+	for _, e := range entries {
+		if e.End() >= r.Start {
+			r.Start = e.End() + 1
+		}
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return SubIDRange{}, errors.Wrapf(err, "cannot read %s", path)
+	}
+	mode := os.FileMode(0644)
+	if st, err := os.Stat(path); err == nil {
+		mode = st.Mode().Perm()
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		content = append(content, '\n')
+	}
+	content = append(content, fmt.Sprintf("%s:%d:%d\n", name, r.Start, r.Size)...)
+
+	// Replace the file at once, so readers never see a partial file
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return SubIDRange{}, errors.Wrapf(err, "cannot create temporary file for %s", path)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return SubIDRange{}, errors.Wrapf(err, "cannot write %s", tmp.Name())
+	}
+	if err := tmp.Close(); err != nil {
+		return SubIDRange{}, errors.Wrapf(err, "cannot write %s", tmp.Name())
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return SubIDRange{}, errors.Wrapf(err, "cannot set the mode of %s", tmp.Name())
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return SubIDRange{}, errors.Wrapf(err, "cannot replace %s", path)
+	}
+	return r, nil
+}
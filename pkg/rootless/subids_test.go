@@ -0,0 +1,67 @@
+package rootless
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubIDRanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subids")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "subuid")
+	require.NoError(t, ioutil.WriteFile(path, []byte("alice:100000:65536\n# comment\n1001:165536:65536\nbob:231072:1000\n"), 0644))
+
+	ranges, err := SubIDRanges(path, "alice", 1000)
+	require.NoError(t, err)
+	assert.Equal(t, []SubIDRange{{Start: 100000, Size: 65536}}, ranges)
+
+	// Users can be given by ID
+	ranges, err = SubIDRanges(path, "carol", 1001)
+	require.NoError(t, err)
+	assert.Equal(t, []SubIDRange{{Start: 165536, Size: 65536}}, ranges)
+
+	ranges, err = SubIDRanges(path, "dave", 1003)
+	require.NoError(t, err)
+	assert.Empty(t, ranges)
+
+	// A missing file has no ranges
+	ranges, err = SubIDRanges(filepath.Join(dir, "missing"), "alice", 1000)
+	require.NoError(t, err)
+	assert.Empty(t, ranges)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("alice:100000\n"), 0644))
+	_, err = SubIDRanges(path, "alice", 1000)
+	assert.Error(t, err)
+}
+
+func TestAllocateSubIDs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subids")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "subuid")
+
+	// The first range starts above the IDs of the users
+	r, err := AllocateSubIDs(path, "alice", DefaultSubIDSize)
+	require.NoError(t, err)
+	assert.Equal(t, SubIDRange{Start: 100000, Size: 65536}, r)
+
+	// The next ranges follow the last one, and the file keeps its mode
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, ioutil.WriteFile(path, []byte("alice:100000:65536\nbob:300000:1000"), 0600))
+	r, err = AllocateSubIDs(path, "carol", DefaultSubIDSize)
+	require.NoError(t, err)
+	assert.Equal(t, SubIDRange{Start: 301000, Size: 65536}, r)
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "alice:100000:65536\nbob:300000:1000\ncarol:301000:65536\n", string(content))
+	st, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), st.Mode().Perm())
+}
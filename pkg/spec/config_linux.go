@@ -3,14 +3,21 @@
 package createconfig
 
 import (
+	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/docker/docker/profiles/seccomp"
+	"github.com/docker/go-units"
 	"github.com/opencontainers/runc/libcontainer/configs"
 	"github.com/opencontainers/runc/libcontainer/devices"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
 )
 
@@ -89,7 +96,9 @@ func getSeccompConfig(config *CreateConfig, configSpec *spec.Spec) (*spec.LinuxS
 
 func (c *CreateConfig) createBlockIO() (*spec.LinuxBlockIO, error) {
 	bio := &spec.LinuxBlockIO{}
-	bio.Weight = &c.Resources.BlkioWeight
+	if c.Resources.BlkioWeight != 0 {
+		bio.Weight = &c.Resources.BlkioWeight
+	}
 	if len(c.Resources.BlkioWeightDevice) > 0 {
 		var lwds []spec.LinuxWeightDevice
 		for _, i := range c.Resources.BlkioWeightDevice {
@@ -169,3 +178,167 @@ func makeThrottleArray(throttleInput []string, rateType int) ([]spec.LinuxThrott
 	}
 	return ltds, nil
 }
+
+// rlimits are the resources copied into containers with --ulimit host, by
+// name as accepted by --ulimit
+var rlimits = []struct {
+	name     string
+	resource int
+}{
+	{"core", unix.RLIMIT_CORE},
+	{"cpu", unix.RLIMIT_CPU},
+	{"data", unix.RLIMIT_DATA},
+	{"fsize", unix.RLIMIT_FSIZE},
+	{"locks", unix.RLIMIT_LOCKS},
+	{"memlock", unix.RLIMIT_MEMLOCK},
+	{"msgqueue", unix.RLIMIT_MSGQUEUE},
+	{"nice", unix.RLIMIT_NICE},
+	{"nofile", unix.RLIMIT_NOFILE},
+	{"nproc", unix.RLIMIT_NPROC},
+	{"rss", unix.RLIMIT_RSS},
+	{"rtprio", unix.RLIMIT_RTPRIO},
+	{"rttime", unix.RLIMIT_RTTIME},
+	{"sigpending", unix.RLIMIT_SIGPENDING},
+	{"stack", unix.RLIMIT_STACK},
+}
+
+// getHostRlimits returns the current limits of this process
+func getHostRlimits() ([]spec.POSIXRlimit, error) {
+	hostRlimits := make([]spec.POSIXRlimit, 0, len(rlimits))
+	for _, r := range rlimits {
+		var limit unix.Rlimit
+		if err := unix.Getrlimit(r.resource, &limit); err != nil {
+			return nil, errors.Wrapf(err, "error getting %s limit", r.name)
+		}
+		hostRlimits = append(hostRlimits, spec.POSIXRlimit{
+			Type: "RLIMIT_" + strings.ToUpper(r.name),
+			Hard: limit.Max,
+			Soft: limit.Cur,
+		})
+	}
+	return hostRlimits, nil
+}
+
+// clampRlimit lowers a limit so it does not exceed the hard limit of this
+// process for the same resource
+func clampRlimit(resource int, hard, soft uint64) (uint64, uint64, error) {
+	var limit unix.Rlimit
+	if err := unix.Getrlimit(resource, &limit); err != nil {
+		return 0, 0, errors.Wrapf(err, "error getting limit for resource %d", resource)
+	}
+	if hard > limit.Max {
+		hard = limit.Max
+	}
+	if soft > hard {
+		soft = hard
+	}
+	return hard, soft, nil
+}
+
+// getRDTClassSchema returns the L3 cache schema of a resctrl class, the
+// schemata of a group of the resctrl filesystem mounted at root
+func getRDTClassSchema(root, class string) (string, error) {
+	if _, err := os.Stat(filepath.Join(root, "schemata")); err != nil {
+		return "", errors.Errorf("resctrl filesystem is not mounted at %s", root)
+	}
+	if class != filepath.Base(class) || class == "." || class == ".." {
+		return "", errors.Errorf("invalid resctrl class %q", class)
+	}
+	schemata, err := ioutil.ReadFile(filepath.Join(root, class, "schemata"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", errors.Errorf("resctrl class %q does not exist in %s", class, root)
+		}
+		return "", errors.Wrapf(err, "error reading schemata of resctrl class %q", class)
+	}
+
+	var l3 string
+	for _, line := range strings.Split(string(schemata), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "L3:"):
+			l3 = line
+		case strings.HasPrefix(line, "MB:"):
+			logrus.Warnf("memory bandwidth allocation of resctrl class %q is not supported by the OCI spec, only its cache allocation applies", class)
+		}
+	}
+	if l3 == "" {
+		return "", errors.Errorf("resctrl class %q has no L3 cache allocation", class)
+	}
+	return l3, nil
+}
+
+// hugepageSizeName returns the name the kernel gives to hugepages of a size in
+// kB, as used by the hugetlb cgroup controller
+func hugepageSizeName(sizeKB uint64) string {
+	switch {
+	case sizeKB >= 1024*1024 && sizeKB%(1024*1024) == 0:
+		return fmt.Sprintf("%dGB", sizeKB/(1024*1024))
+	case sizeKB >= 1024 && sizeKB%1024 == 0:
+		return fmt.Sprintf("%dMB", sizeKB/1024)
+	}
+	return fmt.Sprintf("%dKB", sizeKB)
+}
+
+// hugepagePools returns the number of hugepages in the pools of the host
+// by hugepage size name, read from the hugepages directory at root
+func hugepagePools(root string) (map[string]uint64, error) {
+	dirs, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading hugepage pools from %s", root)
+	}
+	pools := make(map[string]uint64)
+	for _, dir := range dirs {
+		var sizeKB uint64
+		if _, err := fmt.Sscanf(dir.Name(), "hugepages-%dkB", &sizeKB); err != nil {
+			continue
+		}
+		nr, err := ioutil.ReadFile(filepath.Join(root, dir.Name(), "nr_hugepages"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading size of hugepage pool %s", dir.Name())
+		}
+		pages, err := strconv.ParseUint(strings.TrimSpace(string(nr)), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing size of hugepage pool %s", dir.Name())
+		}
+		pools[hugepageSizeName(sizeKB)] = pages
+	}
+	return pools, nil
+}
+
+// getHugepageLimits parses hugetlb limits in the PAGESIZE:LIMIT format and
+// verifies them against the hugepage pools in the hugepages directory at root
+func getHugepageLimits(root string, hugetlb []string) ([]spec.LinuxHugepageLimit, error) {
+	pools, err := hugepagePools(root)
+	if err != nil {
+		return nil, err
+	}
+	var limits []spec.LinuxHugepageLimit
+	for _, val := range hugetlb {
+		split := strings.SplitN(val, ":", 2)
+		if len(split) != 2 {
+			return nil, errors.Errorf("invalid hugetlb limit %q, must be PAGESIZE:LIMIT", val)
+		}
+		size, err := units.RAMInBytes(split[0])
+		if err != nil || size < 1024 {
+			return nil, errors.Errorf("invalid hugepage size %q", split[0])
+		}
+		pageSize := hugepageSizeName(uint64(size) / 1024)
+		pages, ok := pools[pageSize]
+		if !ok {
+			return nil, errors.Errorf("hugepage size %s is not supported by the host", pageSize)
+		}
+		limit, err := units.RAMInBytes(split[1])
+		if err != nil || limit <= 0 {
+			return nil, errors.Errorf("invalid hugetlb limit %q", split[1])
+		}
+		if limit%size != 0 {
+			return nil, errors.Errorf("hugetlb limit %s must be a multiple of the hugepage size %s", split[1], pageSize)
+		}
+		if uint64(limit/size) > pages {
+			logrus.Warnf("hugetlb limit %s is larger than the pool of %d hugepages of size %s", split[1], pages, pageSize)
+		}
+		limits = append(limits, spec.LinuxHugepageLimit{Pagesize: pageSize, Limit: uint64(limit)})
+	}
+	return limits, nil
+}
@@ -26,3 +26,19 @@ func (c *CreateConfig) createBlockIO() (*spec.LinuxBlockIO, error) {
 func makeThrottleArray(throttleInput []string, rateType int) ([]spec.LinuxThrottleDevice, error) {
 	return nil, errors.New("function not implemented")
 }
+
+func getHostRlimits() ([]spec.POSIXRlimit, error) {
+	return nil, errors.New("function not implemented")
+}
+
+func clampRlimit(resource int, hard, soft uint64) (uint64, uint64, error) {
+	return 0, 0, errors.New("function not implemented")
+}
+
+func getRDTClassSchema(root, class string) (string, error) {
+	return "", errors.New("function not implemented")
+}
+
+func getHugepageLimits(root string, hugetlb []string) ([]spec.LinuxHugepageLimit, error) {
+	return nil, errors.New("function not implemented")
+}
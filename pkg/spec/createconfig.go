@@ -3,10 +3,12 @@ package createconfig
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
 
+	"github.com/containers/image/manifest"
 	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/storage"
@@ -53,6 +55,7 @@ type CreateResourceConfig struct {
 	DeviceWriteBps    []string // device-write-bps
 	DeviceWriteIOps   []string // device-write-iops
 	DisableOomKiller  bool     // oom-kill-disable
+	HugetlbLimits     []string // hugetlb
 	KernelMemory      int64    // kernel-memory
 	Memory            int64    //memory
 	MemoryReservation int64    // memory-reservation
@@ -60,6 +63,7 @@ type CreateResourceConfig struct {
 	MemorySwappiness  int      // memory-swappiness
 	OomScoreAdj       int      //oom-score-adj
 	PidsLimit         int64    // pids-limit
+	RDTClass          string   // rdt-class
 	ShmSize           int64
 	Ulimit            []string //ulimit
 }
@@ -73,6 +77,7 @@ type CreateConfig struct {
 	CapDrop            []string // cap-drop
 	CidFile            string
 	ConmonPidFile      string
+	CreateCommand      []string
 	CgroupParent       string // cgroup-parent
 	Command            []string
 	Detach             bool              // detach
@@ -83,11 +88,14 @@ type CreateConfig struct {
 	Entrypoint         []string          //entrypoint
 	Env                map[string]string //env
 	ExposedPorts       map[nat.Port]struct{}
-	GroupAdd           []string // group-add
-	HostAdd            []string //add-host
-	Hostname           string   //hostname
+	GroupAdd           []string                      // group-add
+	HealthCheck        *manifest.Schema2HealthConfig // health-cmd, health-interval, health-retries, health-timeout
+	StartupHealthCheck *manifest.Schema2HealthConfig // health-startup-cmd, health-startup-interval, health-startup-retries, health-startup-timeout
+	HostAdd            []string                      //add-host
+	Hostname           string                        //hostname
 	Image              string
 	ImageID            string
+	ImageLabels        map[string]string   // labels of the image, which can set default run options
 	BuiltinImgVolumes  map[string]struct{} // volumes defined in the image config
 	IDMappings         *storage.IDMappingOptions
 	ImageVolumeType    string                // how to handle the image volume, either bind, tmpfs, or ignore
@@ -96,12 +104,16 @@ type CreateConfig struct {
 	IP6Address         string                //ipv6
 	IPAddress          string                //ip
 	Labels             map[string]string     //label
+	Locale             string                //locale
+	Mounts             []string              //mount
 	LinkLocalIP        []string              // link-local-ip
 	LogDriver          string                // log-driver
 	LogDriverOpt       []string              // log-opt
 	MacAddress         string                //mac-address
 	Name               string                //name
+	NameReservation    string                //name-reservation
 	NetMode            container.NetworkMode //net
+	NetworkOptions     map[string][]string   //net slirp4netns options
 	Network            string                //network
 	NetworkAlias       []string              //network-alias
 	PidMode            container.PidMode     //pid
@@ -113,11 +125,17 @@ type CreateConfig struct {
 	Quiet              bool     //quiet
 	ReadOnlyRootfs     bool     //read-only
 	Resources          CreateResourceConfig
-	Rm                 bool //rm
+	ResourceTimeout    uint   // resource-wait-timeout
+	InitContainerType  string // init-ctr
+	RestartPolicy      string // restart
+	RestartRetries     uint   // restart
+	Rm                 bool   //rm
+	RuntimeHandler     string
 	ShmDir             string
 	StopSignal         syscall.Signal       // stop-signal
 	StopTimeout        uint                 // stop-timeout
 	Sysctl             map[string]string    //sysctl
+	Timezone           string               //tz
 	Tmpfs              []string             // tmpfs
 	Tty                bool                 //tty
 	UsernsMode         container.UsernsMode //userns
@@ -132,6 +150,7 @@ type CreateConfig struct {
 	ApparmorProfile    string //SecurityOpts
 	SeccompProfilePath string //SecurityOpts
 	SecurityOpts       []string
+	PrivilegedProfile  PrivilegedProfile
 	Rootfs             string
 	LocalVolumes       []string //Keeps track of the built-in volumes of container used in the --volumes-from flag
 }
@@ -267,6 +286,104 @@ func (c *CreateConfig) GetVolumesFrom() error {
 	return nil
 }
 
+// ArtifactMount is a mount of type artifact, which mounts the files of an
+// artifact in a directory of the container
+type ArtifactMount struct {
+	// Source is the name or ID of the artifact
+	Source string
+	// Destination is the directory the files are mounted in
+	Destination string
+}
+
+// ParseMount parses the value of a --mount flag, a comma separated list of
+// key=value options.  Only mounts of type artifact are supported.
+func ParseMount(m string) (*ArtifactMount, error) {
+	var mountType string
+	mnt := &ArtifactMount{}
+	for _, opt := range strings.Split(m, ",") {
+		kv := strings.SplitN(opt, "=", 2)
+		switch kv[0] {
+		case "ro", "readonly":
+			// Artifacts are always mounted read-only
+			if len(kv) > 1 && kv[1] != "true" && kv[1] != "1" {
+				return nil, errors.Errorf("artifact mounts are read-only: %q", m)
+			}
+			continue
+		}
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, errors.Errorf("invalid mount option %q in %q", opt, m)
+		}
+		switch kv[0] {
+		case "type":
+			mountType = kv[1]
+		case "src", "source":
+			mnt.Source = kv[1]
+		case "dst", "destination", "target":
+			mnt.Destination = kv[1]
+		default:
+			return nil, errors.Errorf("unknown mount option %q in %q", kv[0], m)
+		}
+	}
+	if mountType != "artifact" {
+		return nil, errors.Errorf("unsupported mount type %q in %q, only artifact is supported", mountType, m)
+	}
+	if mnt.Source == "" {
+		return nil, errors.Errorf("the source artifact must be set in %q", m)
+	}
+	if mnt.Destination == "" || !filepath.IsAbs(mnt.Destination) {
+		return nil, errors.Errorf("the destination must be an absolute path in %q", m)
+	}
+	return mnt, nil
+}
+
+// GetArtifactMounts returns read-only bind mounts of the files of the artifacts
+// given with --mount type=artifact
+func (c *CreateConfig) GetArtifactMounts(specMounts []spec.Mount) ([]spec.Mount, error) {
+	if len(c.Mounts) == 0 {
+		return nil, nil
+	}
+	if c.Runtime == nil {
+		return nil, errors.Errorf("artifact mounts require a runtime")
+	}
+	store, err := c.Runtime.ArtifactStore()
+	if err != nil {
+		return nil, err
+	}
+
+	var m []spec.Mount
+	for _, i := range c.Mounts {
+		mnt, err := ParseMount(i)
+		if err != nil {
+			return nil, err
+		}
+		artifact, err := store.Lookup(mnt.Source)
+		if err != nil {
+			return nil, err
+		}
+		files, err := store.Files(artifact)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			dest := filepath.Join(mnt.Destination, f.Name)
+			if libpod.MountExists(specMounts, dest) {
+				continue
+			}
+			// Blobs are shared by the artifacts and containers that use them
+			if err := label.Relabel(f.Path, c.MountLabel, true); err != nil {
+				return nil, errors.Wrapf(err, "relabel failed %q", f.Path)
+			}
+			m = append(m, spec.Mount{
+				Destination: dest,
+				Type:        string(TypeBind),
+				Source:      f.Path,
+				Options:     []string{"rbind", "ro", "private"},
+			})
+		}
+	}
+	return m, nil
+}
+
 //GetTmpfsMounts takes user provided input for Tmpfs mounts and creates Mount structs
 func (c *CreateConfig) GetTmpfsMounts() []spec.Mount {
 	var m []spec.Mount
@@ -288,6 +405,56 @@ func (c *CreateConfig) GetTmpfsMounts() []spec.Mount {
 	return m
 }
 
+// runtimeConfig returns the configuration of the runtime the container is
+// created in, or nil if there is none
+func (c *CreateConfig) runtimeConfig() *libpod.RuntimeConfig {
+	if c.Runtime == nil {
+		return nil
+	}
+	return c.Runtime.GetConfig()
+}
+
+// timezone returns the timezone of the container: the one the user set, then
+// the one of the runtime configuration
+func (c *CreateConfig) timezone() string {
+	if c.Timezone != "" {
+		return c.Timezone
+	}
+	if rtc := c.runtimeConfig(); rtc != nil {
+		return rtc.TZ
+	}
+	return ""
+}
+
+// timezoneEnv returns the TZ environment variable of a timezone
+func timezoneEnv(tz string) string {
+	if tz == libpod.LocalTimezone {
+		// Read the /etc/localtime mounted from the host
+		return ":/etc/localtime"
+	}
+	return tz
+}
+
+// defaultSeccompProfilePath returns the seccomp profile used when the user
+// did not set one: the profile from the runtime configuration, then the
+// override and default profiles if they exist. An empty path selects the
+// builtin profile.
+func defaultSeccompProfilePath(rtc *libpod.RuntimeConfig) (string, error) {
+	if rtc != nil && rtc.SeccompProfile != "" {
+		return rtc.SeccompProfile, nil
+	}
+	for _, path := range []string{libpod.SeccompOverridePath, libpod.SeccompDefaultPath} {
+		if _, err := os.Stat(path); err != nil {
+			if !os.IsNotExist(err) {
+				return "", errors.Wrapf(err, "can't check if %q exists", path)
+			}
+			continue
+		}
+		return path, nil
+	}
+	return "", nil
+}
+
 func createExitCommand(runtime *libpod.Runtime) []string {
 	config := runtime.GetConfig()
 
@@ -318,10 +485,20 @@ func (c *CreateConfig) GetContainerCreateOptions(runtime *libpod.Runtime) ([]lib
 	if c.Interactive {
 		options = append(options, libpod.WithStdin())
 	}
-	if c.Name != "" {
+	if c.NameReservation != "" {
+		logrus.Debugf("appending reserved name %s", c.Name)
+		options = append(options, libpod.WithNameReservation(c.Name, c.NameReservation))
+	} else if c.Name != "" {
 		logrus.Debugf("appending name %s", c.Name)
 		options = append(options, libpod.WithName(c.Name))
 	}
+	if c.RuntimeHandler != "" {
+		logrus.Debugf("running container with runtime handler %s", c.RuntimeHandler)
+		options = append(options, libpod.WithRuntimeHandler(c.RuntimeHandler))
+	}
+	if tz := c.timezone(); tz != "" {
+		options = append(options, libpod.WithTimezone(tz))
+	}
 	if c.Pod != "" {
 		logrus.Debugf("adding container to pod %s", c.Pod)
 		pod, err = runtime.LookupPod(c.Pod)
@@ -378,7 +555,10 @@ func (c *CreateConfig) GetContainerCreateOptions(runtime *libpod.Runtime) ([]lib
 	}
 
 	if IsNS(string(c.NetMode)) {
-		// pass
+		if len(portBindings) > 0 {
+			return nil, errors.New("port bindings are not supported when joining an existing network namespace")
+		}
+		options = append(options, libpod.WithNetNSPath(NS(string(c.NetMode))))
 	} else if c.NetMode.IsContainer() {
 		connectedCtr, err := c.Runtime.LookupContainer(c.NetMode.ConnectedContainer())
 		if err != nil {
@@ -390,11 +570,14 @@ func (c *CreateConfig) GetContainerCreateOptions(runtime *libpod.Runtime) ([]lib
 	} else if !c.NetMode.IsHost() && !c.NetMode.IsNone() {
 		isRootless := rootless.IsRootless()
 		postConfigureNetNS := isRootless || (len(c.IDMappings.UIDMap) > 0 || len(c.IDMappings.GIDMap) > 0) && !c.UsernsMode.IsHost()
-		if isRootless && len(portBindings) > 0 {
-			return nil, errors.New("port bindings are not yet supported by rootless containers")
-		}
 		options = append(options, libpod.WithNetNS(portBindings, postConfigureNetNS, networks))
 	}
+	if len(c.NetworkOptions) > 0 {
+		if !rootless.IsRootless() || c.NetMode.IsHost() || c.NetMode.IsNone() || c.NetMode.IsContainer() || IsPod(string(c.NetMode)) || IsNS(string(c.NetMode)) {
+			return nil, errors.Errorf("the slirp4netns network mode is only supported by rootless containers creating a network namespace")
+		}
+		options = append(options, libpod.WithNetworkOptions(c.NetworkOptions))
+	}
 
 	if c.PidMode.IsContainer() {
 		connectedCtr, err := c.Runtime.LookupContainer(c.PidMode.Container())
@@ -427,6 +610,16 @@ func (c *CreateConfig) GetContainerCreateOptions(runtime *libpod.Runtime) ([]lib
 	// TODO: MNT, USER, CGROUP
 	options = append(options, libpod.WithStopSignal(c.StopSignal))
 	options = append(options, libpod.WithStopTimeout(c.StopTimeout))
+	if c.ResourceTimeout > 0 {
+		options = append(options, libpod.WithResourceWaitTimeout(c.ResourceTimeout))
+	}
+	if c.InitContainerType != "" {
+		options = append(options, libpod.WithInitContainerType(c.InitContainerType))
+	}
+	if c.RestartPolicy != "" {
+		options = append(options, libpod.WithRestartPolicy(c.RestartPolicy))
+		options = append(options, libpod.WithRestartRetries(c.RestartRetries))
+	}
 	if len(c.DNSSearch) > 0 {
 		options = append(options, libpod.WithDNSSearch(c.DNSSearch))
 	}
@@ -439,10 +632,20 @@ func (c *CreateConfig) GetContainerCreateOptions(runtime *libpod.Runtime) ([]lib
 	if len(c.HostAdd) > 0 {
 		options = append(options, libpod.WithHosts(c.HostAdd))
 	}
+	if c.LogDriver != "" {
+		options = append(options, libpod.WithLogDriver(c.LogDriver))
+	}
 	logPath := getLoggingPath(c.LogDriverOpt)
 	if logPath != "" {
 		options = append(options, libpod.WithLogPath(logPath))
 	}
+	logMaxSize, logMaxFiles, err := getLogRotation(c.LogDriverOpt)
+	if err != nil {
+		return nil, err
+	}
+	if logMaxSize > 0 {
+		options = append(options, libpod.WithLogRotation(logMaxSize, logMaxFiles))
+	}
 
 	options = append(options, libpod.WithPrivileged(c.Privileged))
 
@@ -451,6 +654,15 @@ func (c *CreateConfig) GetContainerCreateOptions(runtime *libpod.Runtime) ([]lib
 	options = append(options, libpod.WithRootFSFromImage(c.ImageID, c.Image, useImageVolumes))
 	options = append(options, libpod.WithSELinuxLabels(c.ProcessLabel, c.MountLabel))
 	options = append(options, libpod.WithConmonPidFile(c.ConmonPidFile))
+	if len(c.CreateCommand) > 0 {
+		options = append(options, libpod.WithCreateCommand(c.CreateCommand))
+	}
+	if c.HealthCheck != nil {
+		options = append(options, libpod.WithHealthCheck(c.HealthCheck))
+	}
+	if c.StartupHealthCheck != nil {
+		options = append(options, libpod.WithStartupHealthCheck(c.StartupHealthCheck))
+	}
 	options = append(options, libpod.WithLabels(c.Labels))
 	options = append(options, libpod.WithUser(c.User))
 	options = append(options, libpod.WithShmDir(c.ShmDir))
@@ -465,7 +677,8 @@ func (c *CreateConfig) GetContainerCreateOptions(runtime *libpod.Runtime) ([]lib
 	if c.CgroupParent != "" {
 		options = append(options, libpod.WithCgroupParent(c.CgroupParent))
 	}
-	if c.Detach {
+	// The exit command also restarts containers with a restart policy
+	if c.Detach || (c.RestartPolicy != "" && c.RestartPolicy != libpod.RestartPolicyNo) {
 		options = append(options, libpod.WithExitCommand(createExitCommand(runtime)))
 	}
 
@@ -0,0 +1,179 @@
+package createconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Labels of images setting default run options of their containers. The
+// options of the user take precedence over them. They are only applied with
+// image_run_options set in libpod.conf, as any image could otherwise grant
+// itself privileges.
+const (
+	// ImageCapabilitiesLabel lists the capabilities, separated by commas,
+	// added to the containers of the image
+	ImageCapabilitiesLabel = "io.containers.capabilities"
+	// ImageDevicesLabel lists the host devices, separated by commas and in
+	// the format of --device, added to the containers of the image
+	ImageDevicesLabel = "io.containers.devices"
+)
+
+// imageDeniedCapabilities are the capabilities images can not add to their
+// containers through their labels, as they give access to the host. Only the
+// user can add them, with --cap-add.
+var imageDeniedCapabilities = []string{
+	"ALL",
+	"CAP_AUDIT_CONTROL",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_MAC_ADMIN",
+	"CAP_MAC_OVERRIDE",
+	"CAP_SYSLOG",
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_BOOT",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_TIME",
+}
+
+// imageDeniedDevices are the character devices images can not add to their
+// containers through their labels, as they give access to the memory of the
+// host. Block devices are denied too.
+var imageDeniedDevices = []string{"/dev/mem", "/dev/kmem", "/dev/port"}
+
+// imageRunOptionsEnabled returns whether the labels of the image of the
+// container can set its run options, which the runtime configuration must
+// enable for the images it trusts
+func (c *CreateConfig) imageRunOptionsEnabled() bool {
+	rtc := c.runtimeConfig()
+	return rtc != nil && rtc.ImageRunOptions
+}
+
+// imageLabelList returns the values of a label of the image of the container
+// listing values separated by commas
+func (c *CreateConfig) imageLabelList(label string) []string {
+	var values []string
+	for _, value := range strings.Split(c.ImageLabels[label], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// normalizeCapability returns the name of a capability in upper case with
+// its CAP_ prefix
+func normalizeCapability(capability string) string {
+	capability = strings.ToUpper(capability)
+	if capability != "ALL" && !strings.HasPrefix(capability, "CAP_") {
+		capability = "CAP_" + capability
+	}
+	return capability
+}
+
+// capAdd returns the capabilities added to the container: those of the user,
+// followed by those of the image the user did not drop if enabled
+func (c *CreateConfig) capAdd() ([]string, error) {
+	if !c.imageRunOptionsEnabled() {
+		return c.CapAdd, nil
+	}
+	return c.capAddWithImage()
+}
+
+func (c *CreateConfig) capAddWithImage() ([]string, error) {
+	var dropped []string
+	for _, capability := range c.CapDrop {
+		dropped = append(dropped, normalizeCapability(capability))
+	}
+	if util.StringInSlice("ALL", dropped) {
+		return c.CapAdd, nil
+	}
+	capAdd := append([]string{}, c.CapAdd...)
+	var added []string
+	for _, capability := range c.imageLabelList(ImageCapabilitiesLabel) {
+		normalized := normalizeCapability(capability)
+		if util.StringInSlice(normalized, dropped) {
+			continue
+		}
+		if util.StringInSlice(normalized, imageDeniedCapabilities) {
+			return nil, errors.Errorf("capability %s in label %s of image %s can only be added with --cap-add", capability, ImageCapabilitiesLabel, c.Image)
+		}
+		capAdd = append(capAdd, capability)
+		added = append(added, capability)
+	}
+	if len(added) > 0 {
+		logrus.Infof("Adding capabilities %s from label %s of image %s", strings.Join(added, ","), ImageCapabilitiesLabel, c.Image)
+	}
+	return capAdd, nil
+}
+
+// devices returns the host devices added to the container: those of the
+// user, followed by those of the image whose host path the user did not add
+// if enabled
+func (c *CreateConfig) devices() ([]string, error) {
+	if !c.imageRunOptionsEnabled() {
+		return c.Devices, nil
+	}
+	return c.devicesWithImage()
+}
+
+func (c *CreateConfig) devicesWithImage() ([]string, error) {
+	var added []string
+	for _, device := range c.Devices {
+		src, _, _, err := parseDevice(device)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, src)
+	}
+	devices := append([]string{}, c.Devices...)
+	var fromImage []string
+	for _, device := range c.imageLabelList(ImageDevicesLabel) {
+		src, _, _, err := parseDevice(device)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid device in label %s of the image", ImageDevicesLabel)
+		}
+		if util.StringInSlice(src, added) {
+			continue
+		}
+		if err := checkImageDevice(src); err != nil {
+			return nil, errors.Wrapf(err, "device %s in label %s of image %s can only be added with --device", src, ImageDevicesLabel, c.Image)
+		}
+		devices = append(devices, device)
+		added = append(added, src)
+		fromImage = append(fromImage, device)
+	}
+	if len(fromImage) > 0 {
+		logrus.Infof("Adding devices %s from label %s of image %s", strings.Join(fromImage, ","), ImageDevicesLabel, c.Image)
+	}
+	return devices, nil
+}
+
+// checkImageDevice returns an error if the labels of an image can not add
+// the host device at path: block devices and the memory of the host
+func checkImageDevice(path string) error {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeDevice == 0 {
+		return errors.Errorf("%s is not a device", resolved)
+	}
+	if info.Mode()&os.ModeCharDevice == 0 {
+		return errors.Errorf("%s is a block device", resolved)
+	}
+	if util.StringInSlice(resolved, imageDeniedDevices) {
+		return errors.Errorf("%s gives access to the memory of the host", resolved)
+	}
+	return nil
+}
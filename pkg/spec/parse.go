@@ -2,10 +2,13 @@ package createconfig
 
 import (
 	"fmt"
+	"runtime"
 	"strconv"
 	"strings"
 
+	"github.com/containers/libpod/libpod"
 	"github.com/docker/go-units"
+	"github.com/pkg/errors"
 )
 
 // POD signifies a kernel namespace is being shared
@@ -54,6 +57,64 @@ func NS(s string) string {
 	return ""
 }
 
+// Slirp4netns is the network mode configuring the network of rootless
+// containers with slirp4netns, given with its options as
+// slirp4netns:key=value,...
+const Slirp4netns = "slirp4netns"
+
+// ParseNetworkMode splits the options of slirp4netns from a network mode.
+// The slirp4netns mode is returned as bridge, the mode rootless containers
+// get a network namespace configured by slirp4netns with.
+func ParseNetworkMode(mode string) (string, map[string][]string) {
+	parts := strings.SplitN(mode, ":", 2)
+	if parts[0] != Slirp4netns {
+		return mode, nil
+	}
+	var options []string
+	if len(parts) > 1 && parts[1] != "" {
+		options = strings.Split(parts[1], ",")
+	}
+	return "bridge", map[string][]string{libpod.Slirp4netnsNetworkOptions: options}
+}
+
+// wasmVariantAnnotation is the annotation of WASM images that can run with
+// crun, whose values are compat and compat-smart
+const wasmVariantAnnotation = "module.wasm.image/variant"
+
+// IsWasmPlatform returns whether the given OS and architecture of an image or
+// --platform are those of WASM modules
+func IsWasmPlatform(os, arch string) bool {
+	return os == "wasi" || arch == "wasm" || arch == "wasm32"
+}
+
+// IsWasmImage returns whether an image with the given platform and annotations
+// holds a WASM module instead of a Linux root filesystem
+func IsWasmImage(os, arch string, annotations map[string]string) bool {
+	if IsWasmPlatform(os, arch) {
+		return true
+	}
+	switch annotations[wasmVariantAnnotation] {
+	case "compat", "compat-smart":
+		return true
+	}
+	return annotations["run.oci.handler"] == "wasm"
+}
+
+// ParsePlatform parses the value of the --platform flag, os/arch[/variant].
+// Only the platform of the host and WASM platforms such as wasi/wasm32 are
+// supported, since images of other platforms can not run.
+func ParsePlatform(platform string) (string, string, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid platform %q, should be os/arch[/variant]", platform)
+	}
+	os, arch := parts[0], parts[1]
+	if !IsWasmPlatform(os, arch) && (os != runtime.GOOS || arch != runtime.GOARCH) {
+		return "", "", errors.Errorf("unsupported platform %q, only %s/%s and wasi/wasm32 are supported", platform, runtime.GOOS, runtime.GOARCH)
+	}
+	return os, arch, nil
+}
+
 // validateweightDevice validates that the specified string has a valid device-weight format
 // for blkio-weight-device flag
 func validateweightDevice(val string) (*weightDevice, error) {
@@ -136,6 +197,20 @@ func validateIOpsDevice(val string) (*throttleDevice, error) { //nolint
 	}, nil
 }
 
+// MergeLabels merges the labels of the image a container is created from with
+// the labels given by the user. Labels given by the user take precedence over
+// labels inherited from the image. A nil map is never returned.
+func MergeLabels(imageLabels, userLabels map[string]string) map[string]string {
+	labels := make(map[string]string, len(imageLabels)+len(userLabels))
+	for key, val := range imageLabels {
+		labels[key] = val
+	}
+	for key, val := range userLabels {
+		labels[key] = val
+	}
+	return labels
+}
+
 func getLoggingPath(opts []string) string {
 	for _, opt := range opts {
 		arr := strings.SplitN(opt, "=", 2)
@@ -148,6 +223,38 @@ func getLoggingPath(opts []string) string {
 	return ""
 }
 
+// getLogRotation returns the size at which the log file is rotated and the
+// number of log files kept from the max-size and max-file log options
+func getLogRotation(opts []string) (int64, uint, error) {
+	var maxSize int64
+	var maxFiles uint64 = 1
+	for _, opt := range opts {
+		arr := strings.SplitN(opt, "=", 2)
+		if len(arr) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(arr[1])
+		var err error
+		switch strings.TrimSpace(arr[0]) {
+		case "max-size":
+			if maxSize, err = units.RAMInBytes(value); err != nil {
+				return 0, 0, errors.Wrapf(err, "invalid log max-size %q", value)
+			}
+			if maxSize <= 0 {
+				return 0, 0, errors.Errorf("invalid log max-size %q, must be positive", value)
+			}
+		case "max-file":
+			if maxFiles, err = strconv.ParseUint(value, 10, 32); err != nil || maxFiles == 0 {
+				return 0, 0, errors.Errorf("invalid log max-file %q, must be a positive integer", value)
+			}
+		}
+	}
+	if maxSize == 0 && maxFiles > 1 {
+		return 0, 0, errors.Errorf("log max-file requires max-size")
+	}
+	return maxSize, uint(maxFiles), nil
+}
+
 // parseDevice parses device mapping string to a src, dest & permissions string
 func parseDevice(device string) (string, string, string, error) { //nolint
 	src := ""
@@ -0,0 +1,82 @@
+package createconfig
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the pieces of the privileged profile, used to keep the
+// confinement they would otherwise relax
+const (
+	// PrivilegedCapabilities grants all capabilities
+	PrivilegedCapabilities = "capabilities"
+	// PrivilegedDevices adds all host devices
+	PrivilegedDevices = "devices"
+	// PrivilegedMasks unmasks kernel filesystems and mounts /sys read-write
+	PrivilegedMasks = "masks"
+	// PrivilegedSeccomp disables the seccomp filter
+	PrivilegedSeccomp = "seccomp"
+	// PrivilegedLabel disables SELinux and AppArmor confinement
+	PrivilegedLabel = "label"
+)
+
+// PrivilegedProfile is the confinement a privileged container gives up.
+// A privileged container relaxes every piece unless asked to keep some of
+// them, an unprivileged one relaxes none.
+type PrivilegedProfile struct {
+	Capabilities bool `json:"capabilities"`
+	Devices      bool `json:"devices"`
+	Unmask       bool `json:"unmask"`
+	Seccomp      bool `json:"seccomp"`
+	Label        bool `json:"label"`
+}
+
+// NewPrivilegedProfile returns the profile of a privileged container that
+// keeps the confinement of the given pieces
+func NewPrivilegedProfile(keep []string) (PrivilegedProfile, error) {
+	p := PrivilegedProfile{
+		Capabilities: true,
+		Devices:      true,
+		Unmask:       true,
+		Seccomp:      true,
+		Label:        true,
+	}
+	for _, piece := range keep {
+		switch strings.TrimSpace(piece) {
+		case PrivilegedCapabilities:
+			p.Capabilities = false
+		case PrivilegedDevices:
+			p.Devices = false
+		case PrivilegedMasks:
+			p.Unmask = false
+		case PrivilegedSeccomp:
+			p.Seccomp = false
+		case PrivilegedLabel, "selinux", "apparmor":
+			p.Label = false
+		default:
+			return PrivilegedProfile{}, errors.Errorf("invalid privileged profile piece %q", piece)
+		}
+	}
+	return p, nil
+}
+
+// Relaxed returns the names of the pieces the profile relaxes
+func (p PrivilegedProfile) Relaxed() []string {
+	relaxed := []string{}
+	for _, piece := range []struct {
+		name    string
+		relaxed bool
+	}{
+		{PrivilegedCapabilities, p.Capabilities},
+		{PrivilegedDevices, p.Devices},
+		{PrivilegedMasks, p.Unmask},
+		{PrivilegedSeccomp, p.Seccomp},
+		{PrivilegedLabel, p.Label},
+	} {
+		if piece.relaxed {
+			relaxed = append(relaxed, piece.name)
+		}
+	}
+	return relaxed
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/docker/docker/daemon/caps"
 	"github.com/docker/docker/pkg/mount"
@@ -14,7 +15,19 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const cpuPeriod = 100000
+const (
+	cpuPeriod = 100000
+
+	// UlimitHost is the ulimit option copying the limits of the current
+	// process into the container
+	UlimitHost = "host"
+
+	// resctrlRoot is where the resctrl filesystem holding the classes of
+	// --rdt-class is mounted
+	resctrlRoot = "/sys/fs/resctrl"
+	// hugepagesRoot is where the kernel reports the hugepage pools
+	hugepagesRoot = "/sys/kernel/mm/hugepages"
+)
 
 // CreateConfigToOCISpec parses information needed to create a container into an OCI runtime spec
 func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
@@ -34,7 +47,7 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 		canMountSys = false
 	}
 
-	if config.Privileged && canMountSys {
+	if config.PrivilegedProfile.Unmask && canMountSys {
 		cgroupPerm = "rw"
 		g.RemoveMount("/sys")
 		sysMnt := spec.Mount{
@@ -48,7 +61,7 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 		addCgroup = false
 		g.RemoveMount("/sys")
 		r := "ro"
-		if config.Privileged {
+		if config.PrivilegedProfile.Unmask {
 			r = "rw"
 		}
 		sysMnt := spec.Mount{
@@ -129,7 +142,9 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 	}
 	g.AddProcessEnv("container", "podman")
 
-	canAddResources := !rootless.IsRootless()
+	// Rootless containers get resource limits through the cgroups the
+	// systemd instance of the user delegates on cgroup v2
+	canAddResources := !isRootless || rootlessCgroups(config)
 
 	if canAddResources {
 		// RESOURCES - MEMORY
@@ -182,9 +197,11 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 		if config.Resources.CPUsetMems != "" {
 			g.SetLinuxResourcesCPUMems(config.Resources.CPUsetMems)
 		}
+	}
 
+	if !isRootless {
 		// Devices
-		if config.Privileged {
+		if config.PrivilegedProfile.Devices {
 			// If privileged, we need to add all the host devices to the
 			// spec.  We do not add the user provided ones because we are
 			// already adding them all.
@@ -192,7 +209,11 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 				return nil, err
 			}
 		} else {
-			for _, device := range config.Devices {
+			devices, err := config.devices()
+			if err != nil {
+				return nil, err
+			}
+			for _, device := range devices {
 				if err := addDevice(&g, device); err != nil {
 					return nil, err
 				}
@@ -200,6 +221,32 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 		}
 	}
 
+	// RESOURCES - INTEL RDT
+	if config.Resources.RDTClass != "" {
+		if isRootless {
+			return nil, errors.Errorf("resctrl classes can not be assigned to rootless containers")
+		}
+		schema, err := getRDTClassSchema(resctrlRoot, config.Resources.RDTClass)
+		if err != nil {
+			return nil, err
+		}
+		g.SetLinuxIntelRdtL3CacheSchema(schema)
+	}
+
+	// RESOURCES - HUGETLB
+	if len(config.Resources.HugetlbLimits) > 0 {
+		if !canAddResources {
+			return nil, errors.Errorf("hugetlb limits can not be set on rootless containers")
+		}
+		limits, err := getHugepageLimits(hugepagesRoot, config.Resources.HugetlbLimits)
+		if err != nil {
+			return nil, err
+		}
+		for _, limit := range limits {
+			g.AddLinuxResourcesHugepageLimit(limit.Pagesize, limit.Limit)
+		}
+	}
+
 	for _, uidmap := range config.IDMappings.UIDMap {
 		g.AddLinuxUIDMapping(uint32(uidmap.HostID), uint32(uidmap.ContainerID), uint32(uidmap.Size))
 	}
@@ -212,9 +259,10 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 	g.SetProcessSelinuxLabel(config.ProcessLabel)
 	g.SetLinuxMountLabel(config.MountLabel)
 
-	if canAddResources {
+	if !isRootless {
 		blockAccessToKernelFilesystems(config, &g)
-
+	}
+	if canAddResources {
 		// RESOURCES - PIDS
 		if config.Resources.PidsLimit != 0 {
 			g.SetLinuxResourcesPidsLimit(config.Resources.PidsLimit)
@@ -240,9 +288,33 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 		g.AddMount(tmpfsMnt)
 	}
 
+	// Environment variables from the runtime configuration are only
+	// defaults, the image and the user override them
+	if rtc := config.runtimeConfig(); rtc != nil {
+		for _, env := range rtc.Env {
+			split := strings.SplitN(env, "=", 2)
+			if len(split) != 2 {
+				return nil, errors.Errorf("invalid environment variable %q in libpod configuration, must be KEY=VALUE", env)
+			}
+			g.AddProcessEnv(split[0], split[1])
+		}
+		if rtc.TZ != "" {
+			g.AddProcessEnv("TZ", timezoneEnv(rtc.TZ))
+		}
+		if rtc.Locale != "" {
+			g.AddProcessEnv("LANG", rtc.Locale)
+		}
+	}
 	for name, val := range config.Env {
 		g.AddProcessEnv(name, val)
 	}
+	// The timezone and locale set for the container override the image
+	if config.Timezone != "" {
+		g.AddProcessEnv("TZ", timezoneEnv(config.Timezone))
+	}
+	if config.Locale != "" {
+		g.AddProcessEnv("LANG", config.Locale)
+	}
 
 	if err := addRlimits(config, &g); err != nil {
 		return nil, err
@@ -271,15 +343,25 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 
 	// HANDLE CAPABILITIES
 	// NOTE: Must happen before SECCOMP
-	if !config.Privileged {
+	if !config.PrivilegedProfile.Capabilities {
 		if err := setupCapabilities(config, configSpec); err != nil {
 			return nil, err
 		}
 	} else {
+		// SetupPrivileged also drops the labels, which are part of the
+		// profile of their own
+		processLabel, apparmorProfile := configSpec.Process.SelinuxLabel, configSpec.Process.ApparmorProfile
 		g.SetupPrivileged(true)
+		configSpec.Process.SelinuxLabel, configSpec.Process.ApparmorProfile = processLabel, apparmorProfile
 	}
 
 	// HANDLE SECCOMP
+	if config.SeccompProfilePath == "" {
+		config.SeccompProfilePath, err = defaultSeccompProfilePath(config.runtimeConfig())
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if config.SeccompProfilePath != "unconfined" {
 		seccompConfig, err := getSeccompConfig(config, configSpec)
@@ -290,7 +372,7 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 	}
 
 	// Clear default Seccomp profile from Generator for privileged containers
-	if config.SeccompProfilePath == "unconfined" || config.Privileged {
+	if config.SeccompProfilePath == "unconfined" || config.PrivilegedProfile.Seccomp {
 		configSpec.Linux.Seccomp = nil
 	}
 
@@ -303,6 +385,11 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 		return nil, errors.Wrapf(err, "error getting volume mounts")
 	}
 	configSpec.Mounts = append(configSpec.Mounts, mounts...)
+	artifactMounts, err := config.GetArtifactMounts(configSpec.Mounts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting artifact mounts")
+	}
+	configSpec.Mounts = append(configSpec.Mounts, artifactMounts...)
 	if err := g.SetLinuxRootPropagation("shared"); err != nil {
 		return nil, errors.Wrapf(err, "failed to set propagation to rslave")
 	}
@@ -324,8 +411,18 @@ func CreateConfigToOCISpec(config *CreateConfig) (*spec.Spec, error) { //nolint
 	return configSpec, nil
 }
 
+// rootlessCgroups returns whether a rootless container can have resource
+// limits: libpod must create its cgroup through systemd, with controllers
+// delegated to the user
+func rootlessCgroups(config *CreateConfig) bool {
+	if config.Runtime == nil || config.Runtime.GetConfig().CgroupManager != libpod.SystemdCgroupsManager {
+		return false
+	}
+	return rootless.CgroupV2Controllers() != nil
+}
+
 func blockAccessToKernelFilesystems(config *CreateConfig, g *generate.Generator) {
-	if !config.Privileged {
+	if !config.PrivilegedProfile.Unmask {
 		for _, mp := range []string{
 			"/proc/acpi",
 			"/proc/kcore",
@@ -446,10 +543,32 @@ func addRlimits(config *CreateConfig, g *generate.Generator) error {
 		nprocSet          = false
 	)
 
-	for _, u := range config.Resources.Ulimit {
+	// Ulimits from the runtime configuration come first so the user's
+	// ulimits of the same type replace them
+	ulimits := config.Resources.Ulimit
+	if rtc := config.runtimeConfig(); rtc != nil && len(rtc.DefaultUlimits) > 0 {
+		ulimits = append(append([]string{}, rtc.DefaultUlimits...), ulimits...)
+	}
+
+	for _, u := range ulimits {
+		// "host" copies all limits of the current process, later
+		// ulimits of the same type still replace them
+		if u == UlimitHost {
+			hostRlimits, err := getHostRlimits()
+			if err != nil {
+				return err
+			}
+			for _, rl := range hostRlimits {
+				g.AddProcessRlimits(rl.Type, rl.Hard, rl.Soft)
+			}
+			nofileSet = true
+			nprocSet = true
+			continue
+		}
+
 		ul, err := units.ParseUlimit(u)
 		if err != nil {
-			return errors.Wrapf(err, "ulimit option %q requires name=SOFT:HARD, failed to be parsed", u)
+			return errors.Wrapf(err, "ulimit option %q requires name=SOFT:HARD or host, failed to be parsed", u)
 		}
 
 		if ul.Name == "nofile" {
@@ -458,7 +577,21 @@ func addRlimits(config *CreateConfig, g *generate.Generator) error {
 			nprocSet = true
 		}
 
-		g.AddProcessRlimits("RLIMIT_"+strings.ToUpper(ul.Name), uint64(ul.Hard), uint64(ul.Soft))
+		hard, soft := uint64(ul.Hard), uint64(ul.Soft)
+		if isRootless {
+			// Rootless containers cannot raise limits above the
+			// hard limits of the current process
+			rl, err := ul.GetRlimit()
+			if err != nil {
+				return errors.Wrapf(err, "ulimit option %q is not valid", u)
+			}
+			hard, soft, err = clampRlimit(rl.Type, hard, soft)
+			if err != nil {
+				return err
+			}
+		}
+
+		g.AddProcessRlimits("RLIMIT_"+strings.ToUpper(ul.Name), hard, soft)
 	}
 
 	// If not explicitly overridden by the user, default number of open
@@ -484,11 +617,15 @@ func setupCapabilities(config *CreateConfig, configSpec *spec.Spec) error {
 
 	var err error
 	var caplist []string
+	capAdd, err := config.capAdd()
+	if err != nil {
+		return err
+	}
 	bounding := configSpec.Process.Capabilities.Bounding
 	if useNotRoot(config.User) {
 		configSpec.Process.Capabilities.Bounding = caplist
 	}
-	caplist, err = caps.TweakCapabilities(configSpec.Process.Capabilities.Bounding, config.CapAdd, config.CapDrop)
+	caplist, err = caps.TweakCapabilities(configSpec.Process.Capabilities.Bounding, capAdd, config.CapDrop)
 	if err != nil {
 		return err
 	}
@@ -499,7 +636,7 @@ func setupCapabilities(config *CreateConfig, configSpec *spec.Spec) error {
 	configSpec.Process.Capabilities.Effective = caplist
 	configSpec.Process.Capabilities.Ambient = caplist
 	if useNotRoot(config.User) {
-		caplist, err = caps.TweakCapabilities(bounding, config.CapAdd, config.CapDrop)
+		caplist, err = caps.TweakCapabilities(bounding, capAdd, config.CapDrop)
 		if err != nil {
 			return err
 		}
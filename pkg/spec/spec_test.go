@@ -1,10 +1,16 @@
 package createconfig
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
 	"testing"
 
+	"github.com/containers/libpod/libpod"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,6 +29,41 @@ func TestCreateConfig_GetVolumeMounts(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(data, specMount[0]))
 }
 
+func TestMergeLabels(t *testing.T) {
+	imageLabels := map[string]string{"maintainer": "image", "version": "1"}
+	userLabels := map[string]string{"version": "2", "app": "web"}
+	labels := MergeLabels(imageLabels, userLabels)
+	assert.Equal(t, map[string]string{"maintainer": "image", "version": "2", "app": "web"}, labels)
+	assert.NotNil(t, MergeLabels(nil, nil))
+}
+
+func TestDefaultSeccompProfilePath(t *testing.T) {
+	path, err := defaultSeccompProfilePath(&libpod.RuntimeConfig{SeccompProfile: "/etc/seccomp.json"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/etc/seccomp.json", path)
+}
+
+func TestAddRlimitsHost(t *testing.T) {
+	g, err := generate.New("linux")
+	assert.NoError(t, err)
+	config := CreateConfig{
+		Resources: CreateResourceConfig{
+			Ulimit: []string{"host", "nofile=10:20"},
+		},
+	}
+	assert.NoError(t, addRlimits(&config, &g))
+
+	hostRlimits, err := getHostRlimits()
+	assert.NoError(t, err)
+	assert.Len(t, g.Config.Process.Rlimits, len(hostRlimits))
+	for _, rl := range g.Config.Process.Rlimits {
+		if rl.Type == "RLIMIT_NOFILE" {
+			assert.Equal(t, uint64(20), rl.Hard)
+			assert.Equal(t, uint64(10), rl.Soft)
+		}
+	}
+}
+
 func TestCreateConfig_GetTmpfsMounts(t *testing.T) {
 	data := spec.Mount{
 		Destination: "/homer",
@@ -37,3 +78,214 @@ func TestCreateConfig_GetTmpfsMounts(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(data, tmpfsMount[0]))
 
 }
+
+func TestParseMount(t *testing.T) {
+	mnt, err := ParseMount("type=artifact,src=quay.io/example/model:v1,dst=/models,ro")
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io/example/model:v1", mnt.Source)
+	assert.Equal(t, "/models", mnt.Destination)
+
+	mnt, err = ParseMount("type=artifact,source=model,target=/models")
+	assert.NoError(t, err)
+	assert.Equal(t, "model", mnt.Source)
+
+	for _, m := range []string{
+		"type=bind,src=/tmp,dst=/tmp",
+		"src=model,dst=/models",
+		"type=artifact,dst=/models",
+		"type=artifact,src=model,dst=models",
+		"type=artifact,src=model,dst=/models,ro=false",
+		"type=artifact,src=model,dst=/models,bind-propagation=shared",
+	} {
+		_, err := ParseMount(m)
+		assert.Error(t, err, m)
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	os, arch, err := ParsePlatform("wasi/wasm32")
+	assert.NoError(t, err)
+	assert.Equal(t, "wasi", os)
+	assert.Equal(t, "wasm32", arch)
+
+	_, _, err = ParsePlatform(runtime.GOOS + "/" + runtime.GOARCH)
+	assert.NoError(t, err)
+
+	for _, platform := range []string{"wasi", "/wasm32", "wasi/wasm32/v1/extra", "windows/mips64"} {
+		_, _, err := ParsePlatform(platform)
+		assert.Error(t, err, platform)
+	}
+}
+
+func TestIsWasmImage(t *testing.T) {
+	assert.True(t, IsWasmImage("wasi", "wasm", nil))
+	assert.True(t, IsWasmImage("linux", "amd64", map[string]string{"module.wasm.image/variant": "compat-smart"}))
+	assert.True(t, IsWasmImage("linux", "amd64", map[string]string{"run.oci.handler": "wasm"}))
+	assert.False(t, IsWasmImage("linux", "amd64", map[string]string{"module.wasm.image/variant": "other"}))
+	assert.False(t, IsWasmImage("linux", "arm64", nil))
+}
+
+func TestNewPrivilegedProfile(t *testing.T) {
+	p, err := NewPrivilegedProfile(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"capabilities", "devices", "masks", "seccomp", "label"}, p.Relaxed())
+
+	p, err = NewPrivilegedProfile([]string{"selinux", "masks"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"capabilities", "devices", "seccomp"}, p.Relaxed())
+
+	_, err = NewPrivilegedProfile([]string{"network"})
+	assert.Error(t, err)
+
+	assert.Empty(t, PrivilegedProfile{}.Relaxed())
+}
+
+func TestBlockAccessToKernelFilesystems(t *testing.T) {
+	g, err := generate.New("linux")
+	assert.NoError(t, err)
+	config := CreateConfig{Privileged: true}
+	config.PrivilegedProfile, err = NewPrivilegedProfile([]string{"masks"})
+	assert.NoError(t, err)
+	blockAccessToKernelFilesystems(&config, &g)
+	assert.Contains(t, g.Config.Linux.MaskedPaths, "/proc/kcore")
+
+	g, err = generate.New("linux")
+	assert.NoError(t, err)
+	config.PrivilegedProfile, err = NewPrivilegedProfile(nil)
+	assert.NoError(t, err)
+	blockAccessToKernelFilesystems(&config, &g)
+	assert.NotContains(t, g.Config.Linux.MaskedPaths, "/proc/kcore")
+}
+
+func TestGetRDTClassSchema(t *testing.T) {
+	root, err := ioutil.TempDir("", "resctrl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	_, err = getRDTClassSchema(root, "gold")
+	assert.Error(t, err, "resctrl is not mounted")
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "schemata"), []byte("    L3:0=fffff;1=fffff\n    MB:0=100;1=100\n"), 0644))
+	assert.NoError(t, os.Mkdir(filepath.Join(root, "gold"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "gold", "schemata"), []byte("    L3:0=ff000;1=ff000\n    MB:0=50;1=50\n"), 0644))
+	assert.NoError(t, os.Mkdir(filepath.Join(root, "mba"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "mba", "schemata"), []byte("MB:0=20\n"), 0644))
+
+	schema, err := getRDTClassSchema(root, "gold")
+	assert.NoError(t, err)
+	assert.Equal(t, "L3:0=ff000;1=ff000", schema)
+
+	_, err = getRDTClassSchema(root, "silver")
+	assert.Error(t, err)
+	_, err = getRDTClassSchema(root, "../gold")
+	assert.Error(t, err)
+	_, err = getRDTClassSchema(root, "mba")
+	assert.Error(t, err)
+}
+
+func TestGetHugepageLimits(t *testing.T) {
+	root, err := ioutil.TempDir("", "hugepages")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	for dir, pages := range map[string]string{"hugepages-2048kB": "512\n", "hugepages-1048576kB": "0\n"} {
+		assert.NoError(t, os.Mkdir(filepath.Join(root, dir), 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(root, dir, "nr_hugepages"), []byte(pages), 0644))
+	}
+
+	limits, err := getHugepageLimits(root, []string{"2MB:1g", "1GB:2g"})
+	assert.NoError(t, err)
+	assert.Equal(t, []spec.LinuxHugepageLimit{
+		{Pagesize: "2MB", Limit: 1 << 30},
+		{Pagesize: "1GB", Limit: 2 << 30},
+	}, limits)
+
+	for _, invalid := range []string{"2MB", "64kB:1m", "2MB:3m", "2MB:-1", "foo:1g"} {
+		_, err = getHugepageLimits(root, []string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseNetworkMode(t *testing.T) {
+	mode, options := ParseNetworkMode("host")
+	assert.Equal(t, "host", mode)
+	assert.Nil(t, options)
+
+	mode, options = ParseNetworkMode("slirp4netns")
+	assert.Equal(t, "bridge", mode)
+	assert.Equal(t, map[string][]string{libpod.Slirp4netnsNetworkOptions: nil}, options)
+
+	mode, options = ParseNetworkMode("slirp4netns:mtu=1500,allow_host_loopback=false")
+	assert.Equal(t, "bridge", mode)
+	assert.Equal(t, map[string][]string{libpod.Slirp4netnsNetworkOptions: {"mtu=1500", "allow_host_loopback=false"}}, options)
+}
+
+func TestImageRunOptions(t *testing.T) {
+	config := CreateConfig{
+		CapAdd:  []string{"SYS_TIME"},
+		CapDrop: []string{"cap_net_raw"},
+		Devices: []string{"/dev/fuse:/dev/fuse:r"},
+		ImageLabels: map[string]string{
+			ImageCapabilitiesLabel: "CAP_NET_ADMIN, NET_RAW",
+			ImageDevicesLabel:      "/dev/fuse,/dev/null",
+		},
+	}
+	// The labels are ignored unless the runtime configuration enables them
+	capAdd, err := config.capAdd()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SYS_TIME"}, capAdd)
+	devices, err := config.devices()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/dev/fuse:/dev/fuse:r"}, devices)
+
+	capAdd, err = config.capAddWithImage()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SYS_TIME", "CAP_NET_ADMIN"}, capAdd)
+	devices, err = config.devicesWithImage()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/dev/fuse:/dev/fuse:r", "/dev/null"}, devices)
+
+	config.CapDrop = []string{"all"}
+	capAdd, err = config.capAddWithImage()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SYS_TIME"}, capAdd)
+
+	config.CapDrop = nil
+	for _, capability := range []string{"ALL", "sys_admin", "CAP_SYS_MODULE"} {
+		config.ImageLabels[ImageCapabilitiesLabel] = capability
+		_, err = config.capAddWithImage()
+		assert.Error(t, err, capability)
+	}
+
+	config.ImageLabels[ImageDevicesLabel] = "/dev/fuse:/dev/fuse:rwm:extra"
+	_, err = config.devicesWithImage()
+	assert.Error(t, err)
+	for _, device := range []string{"/dev/mem", "/dev/loop0", "/dev/does-not-exist", "/tmp"} {
+		config.ImageLabels[ImageDevicesLabel] = device
+		_, err = config.devicesWithImage()
+		assert.Error(t, err, device)
+	}
+}
+
+func TestGetLogRotation(t *testing.T) {
+	maxSize, maxFiles, err := getLogRotation([]string{"path=/tmp/ctr.log"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), maxSize)
+	assert.Equal(t, uint(1), maxFiles)
+
+	maxSize, maxFiles, err = getLogRotation([]string{"max-size=10m", "max-file=3"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10*1024*1024), maxSize)
+	assert.Equal(t, uint(3), maxFiles)
+
+	for _, opts := range [][]string{
+		{"max-size=ten"},
+		{"max-size=0"},
+		{"max-size=10m", "max-file=0"},
+		{"max-size=10m", "max-file=-1"},
+		{"max-file=3"},
+	} {
+		_, _, err := getLogRotation(opts)
+		assert.Error(t, err, opts)
+	}
+}
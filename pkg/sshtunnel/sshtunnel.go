@@ -0,0 +1,117 @@
+// Package sshtunnel forwards a local unix socket to the socket of a podman
+// service on a remote host through ssh(1). Every connection to the local
+// socket is carried over the same SSH connection, streams included, so
+// clients of the Docker Engine API or of varlink manage the remote host as
+// if it were local.
+package sshtunnel
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRemoteSocket is the socket forwarded to when the destination
+// names none, the socket of podman system service running as root
+const DefaultRemoteSocket = "/run/podman/podman.sock"
+
+// Destination is the remote end of a tunnel
+type Destination struct {
+	// User to log in as, the ssh default when empty
+	User string
+	// Host to connect to
+	Host string
+	// Port of the SSH server, the ssh default when 0
+	Port int
+	// Socket is the path of the socket forwarded to on the host
+	Socket string
+}
+
+// ParseDestination parses the destination of a tunnel, given as
+// ssh://[USER@]HOST[:PORT][/SOCKET] or [USER@]HOST
+func ParseDestination(dest string) (*Destination, error) {
+	if !strings.Contains(dest, "://") {
+		dest = "ssh://" + dest
+	}
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid destination %q", dest)
+	}
+	if u.Scheme != "ssh" {
+		return nil, errors.Errorf("invalid destination %q: only ssh:// is supported", dest)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.Errorf("invalid destination %q: no host", dest)
+	}
+	if _, ok := u.User.Password(); ok {
+		return nil, errors.Errorf("invalid destination %q: passwords are asked for by ssh", dest)
+	}
+	d := &Destination{
+		User:   u.User.Username(),
+		Host:   u.Hostname(),
+		Socket: u.Path,
+	}
+	if u.Port() != "" {
+		if d.Port, err = strconv.Atoi(u.Port()); err != nil || d.Port <= 0 || d.Port > 65535 {
+			return nil, errors.Errorf("invalid destination %q: invalid port %q", dest, u.Port())
+		}
+	}
+	if d.Socket == "" || d.Socket == "/" {
+		d.Socket = DefaultRemoteSocket
+	}
+	return d, nil
+}
+
+// Args returns the arguments of ssh forwarding localSocket to the socket of
+// the destination, authenticating with the identity file when set
+func (d *Destination) Args(localSocket, identity string) []string {
+	args := []string{
+		"-N", "-T",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "StreamLocalBindUnlink=yes",
+		"-o", "ServerAliveInterval=30",
+		"-L", localSocket + ":" + d.Socket,
+	}
+	if identity != "" {
+		args = append(args, "-i", identity)
+	}
+	if d.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(d.Port))
+	}
+	if d.User != "" {
+		args = append(args, "-l", d.User)
+	}
+	return append(args, "--", d.Host)
+}
+
+// Open forwards localSocket to the destination until ctx is done or the SSH
+// connection ends. ssh asks for passwords and host keys on the terminal.
+func Open(ctx context.Context, d *Destination, localSocket, identity string) error {
+	if err := os.MkdirAll(filepath.Dir(localSocket), 0700); err != nil {
+		return errors.Wrapf(err, "error creating directory for socket %s", localSocket)
+	}
+	defer os.Remove(localSocket)
+
+	args := d.Args(localSocket, identity)
+	logrus.Debugf("running ssh %s", strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		// The tunnel was closed
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "error forwarding %s to %s on %s", localSocket, d.Socket, d.Host)
+	}
+	return nil
+}
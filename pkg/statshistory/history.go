@@ -0,0 +1,217 @@
+// Package statshistory keeps the history of the resource usage of
+// containers, sampled periodically, in a file holding a ring buffer of
+// fixed-size samples, so that the latest samples remain after a container
+// dies, such as when it is killed for running out of memory.
+package statshistory
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+)
+
+const (
+	// magic starts the files of the history, with the version of their
+	// format
+	magic = "PSH1"
+	// headerSize is the size of the header of the files: the magic, the
+	// number of samples the ring holds and the number of samples written
+	headerSize = 4 + 4 + 8
+	// sampleSize is the size of a sample in the files
+	sampleSize = 6 * 8
+)
+
+// Sample is the resource usage of a container at a point in time
+type Sample struct {
+	// Time is when the sample was taken
+	Time time.Time `json:"time"`
+	// CPU is the CPU usage of the container since the previous sample, as
+	// a percentage of one CPU
+	CPU float64 `json:"cpu"`
+	// CPUNano is the total CPU time used by the container, in nanoseconds
+	CPUNano uint64 `json:"cpuNano"`
+	// MemUsage and MemLimit are the memory used by the container and its
+	// limit, in bytes
+	MemUsage uint64 `json:"memUsage"`
+	MemLimit uint64 `json:"memLimit"`
+	// PIDs is the number of processes of the container
+	PIDs uint64 `json:"pids"`
+}
+
+// Ring is the history of a container, kept in a file holding its latest
+// samples. Older samples are overwritten.
+type Ring struct {
+	path string
+	size int
+}
+
+// NewRing returns the history kept in the file at path, holding up to size
+// samples
+func NewRing(path string, size int) *Ring {
+	return &Ring{path: path, size: size}
+}
+
+// lock locks the file of the history, which other processes read
+func (r *Ring) lock() (storage.Locker, error) {
+	lock, err := storage.GetLockfile(r.path + ".lock")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error locking stats history %s", r.path)
+	}
+	lock.Lock()
+	return lock, nil
+}
+
+// Write adds a sample to the history, replacing the oldest one if it is full
+func (r *Ring) Write(sample Sample) error {
+	if r.size <= 0 {
+		return errors.Errorf("invalid size %d of stats history %s", r.size, r.path)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return errors.Wrapf(err, "error creating the directory of stats history %s", r.path)
+	}
+	lock, err := r.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	f, err := os.OpenFile(r.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "error opening stats history %s", r.path)
+	}
+	defer f.Close()
+	size, written, err := readHeader(f)
+	if err != nil || size != r.size {
+		// Keep the latest samples of a history of another size
+		var samples []Sample
+		if err == nil {
+			samples, _ = readSamples(f, size, written)
+		}
+		if len(samples) > r.size-1 {
+			samples = samples[len(samples)-(r.size-1):]
+		}
+		if err := f.Truncate(0); err != nil {
+			return errors.Wrapf(err, "error resetting stats history %s", r.path)
+		}
+		written = 0
+		for _, s := range samples {
+			if err := writeSample(f, r.size, written, s); err != nil {
+				return errors.Wrapf(err, "error writing stats history %s", r.path)
+			}
+			written++
+		}
+	}
+	if err := writeSample(f, r.size, written, sample); err != nil {
+		return errors.Wrapf(err, "error writing stats history %s", r.path)
+	}
+	// The sample only counts once written, in case of a crash
+	if err := writeHeader(f, r.size, written+1); err != nil {
+		return errors.Wrapf(err, "error writing stats history %s", r.path)
+	}
+	return nil
+}
+
+// Read returns the samples of the history, oldest first
+func (r *Ring) Read() ([]Sample, error) {
+	samples := []Sample{}
+	if _, err := os.Stat(r.path); err != nil {
+		if os.IsNotExist(err) {
+			return samples, nil
+		}
+		return nil, errors.Wrapf(err, "error reading stats history %s", r.path)
+	}
+	lock, err := r.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	f, err := os.Open(r.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening stats history %s", r.path)
+	}
+	defer f.Close()
+	size, written, err := readHeader(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading stats history %s", r.path)
+	}
+	read, err := readSamples(f, size, written)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading stats history %s", r.path)
+	}
+	return append(samples, read...), nil
+}
+
+// readHeader returns the number of samples the ring of the file holds, and
+// the number of samples written to it
+func readHeader(f *os.File) (int, uint64, error) {
+	header := make([]byte, headerSize)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return 0, 0, err
+	}
+	if string(header[:4]) != magic {
+		return 0, 0, errors.Errorf("not a stats history")
+	}
+	size := int(binary.LittleEndian.Uint32(header[4:8]))
+	if size <= 0 {
+		return 0, 0, errors.Errorf("invalid size %d", size)
+	}
+	return size, binary.LittleEndian.Uint64(header[8:]), nil
+}
+
+// writeHeader writes the header of a file
+func writeHeader(f *os.File, size int, written uint64) error {
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(size))
+	binary.LittleEndian.PutUint64(header[8:], written)
+	_, err := f.WriteAt(header, 0)
+	return err
+}
+
+// readSamples reads the samples of the ring of a file, oldest first
+func readSamples(f *os.File, size int, written uint64) ([]Sample, error) {
+	n := written
+	if n > uint64(size) {
+		n = uint64(size)
+	}
+	samples := make([]Sample, 0, n)
+	b := make([]byte, sampleSize)
+	for i := written - n; i < written; i++ {
+		if _, err := f.ReadAt(b, headerSize+int64(i%uint64(size))*sampleSize); err != nil {
+			if err == io.EOF {
+				return nil, errors.Errorf("truncated history")
+			}
+			return nil, err
+		}
+		samples = append(samples, Sample{
+			Time:     time.Unix(0, int64(binary.LittleEndian.Uint64(b[0:]))),
+			CPU:      math.Float64frombits(binary.LittleEndian.Uint64(b[8:])),
+			CPUNano:  binary.LittleEndian.Uint64(b[16:]),
+			MemUsage: binary.LittleEndian.Uint64(b[24:]),
+			MemLimit: binary.LittleEndian.Uint64(b[32:]),
+			PIDs:     binary.LittleEndian.Uint64(b[40:]),
+		})
+	}
+	return samples, nil
+}
+
+// writeSample writes the sample at the index of the samples written to the
+// ring of a file
+func writeSample(f *os.File, size int, index uint64, s Sample) error {
+	b := make([]byte, sampleSize)
+	binary.LittleEndian.PutUint64(b[0:], uint64(s.Time.UnixNano()))
+	binary.LittleEndian.PutUint64(b[8:], math.Float64bits(s.CPU))
+	binary.LittleEndian.PutUint64(b[16:], s.CPUNano)
+	binary.LittleEndian.PutUint64(b[24:], s.MemUsage)
+	binary.LittleEndian.PutUint64(b[32:], s.MemLimit)
+	binary.LittleEndian.PutUint64(b[40:], s.PIDs)
+	_, err := f.WriteAt(b, headerSize+int64(index%uint64(size))*sampleSize)
+	return err
+}
@@ -0,0 +1,66 @@
+package statshistory
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "statshistory")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "stats-history")
+
+	r := NewRing(path, 3)
+	samples, err := r.Read()
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+
+	start := time.Unix(1500000000, 0)
+	var all []Sample
+	for i := 0; i < 5; i++ {
+		s := Sample{
+			Time:     start.Add(time.Duration(i) * time.Second),
+			CPU:      float64(i) + 0.5,
+			CPUNano:  uint64(i) * 1000,
+			MemUsage: uint64(i) << 20,
+			MemLimit: 1 << 30,
+			PIDs:     uint64(i),
+		}
+		all = append(all, s)
+		require.NoError(t, r.Write(s))
+	}
+	samples, err = r.Read()
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	for i, s := range samples {
+		assert.True(t, all[i+2].Time.Equal(s.Time))
+		s.Time = all[i+2].Time
+		assert.Equal(t, all[i+2], s)
+	}
+
+	// Resizing the history keeps the latest samples
+	r = NewRing(path, 2)
+	require.NoError(t, r.Write(all[0]))
+	samples, err = r.Read()
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, all[4].PIDs, samples[0].PIDs)
+	assert.Equal(t, all[0].PIDs, samples[1].PIDs)
+
+	// Other files are replaced
+	require.NoError(t, ioutil.WriteFile(path, []byte("not a history"), 0600))
+	_, err = r.Read()
+	assert.Error(t, err)
+	require.NoError(t, r.Write(all[1]))
+	samples, err = r.Read()
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, all[1].PIDs, samples[0].PIDs)
+}
@@ -0,0 +1,211 @@
+// Package systemdgen generates the systemd service units of containers and
+// pods, which start them with podman and track their conmon process.
+package systemdgen
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// RestartPolicies are the restart policies of systemd services
+var RestartPolicies = []string{"no", "on-success", "on-failure", "on-abnormal", "on-watchdog", "on-abort", "always"}
+
+// NewPIDFile is the conmon PID file of the containers units generated with
+// --new create, in the runtime directory of systemd
+const NewPIDFile = "%t/%n-pid"
+
+// ServiceInfo describes the service of a container, or of a pod through its
+// infra container
+type ServiceInfo struct {
+	// ServiceName is the name of the unit, without .service
+	ServiceName string
+	// Executable is the path of podman
+	Executable string
+	// ContainerName is the name or ID of the container the service starts
+	ContainerName string
+	// RestartPolicy is the systemd restart policy of the service
+	RestartPolicy string
+	// StopTimeout is the time to wait for the container to stop before
+	// killing it
+	StopTimeout uint
+	// PIDFile is the conmon PID file of the container
+	PIDFile string
+	// CreateCommand, if set, creates the container each time the service
+	// starts, which removes it when it stops
+	CreateCommand []string
+	// BoundToServices are the services the service is bound to, the service
+	// of its pod
+	BoundToServices []string
+	// RequiredServices are the services started after the service and along
+	// with it, the services of the containers of a pod
+	RequiredServices []string
+	// PodmanVersion is the version of podman generating the unit
+	PodmanVersion string
+}
+
+// unitTemplate is the template of the service units. The services fork, as
+// podman returns once conmon is running, and are not killed by systemd,
+// podman stops the containers.
+const unitTemplate = `# {{.ServiceName}}.service
+# autogenerated by Podman {{.PodmanVersion}}
+
+[Unit]
+Description=Podman {{.ServiceName}}.service
+Documentation=man:podman-generate-systemd(1)
+Wants=network.target
+After=network-online.target
+{{- if .BoundToServices}}
+RefuseManualStart=yes
+RefuseManualStop=yes
+BindsTo={{services .BoundToServices}}
+After={{services .BoundToServices}}
+{{- end}}
+{{- if .RequiredServices}}
+Requires={{services .RequiredServices}}
+Before={{services .RequiredServices}}
+{{- end}}
+
+[Service]
+Restart={{.RestartPolicy}}
+{{- if .CreateCommand}}
+ExecStartPre=/usr/bin/rm -f {{.PIDFile}}
+ExecStart={{command .CreateCommand}}
+ExecStop={{escape .Executable}} stop -t {{.StopTimeout}} {{escape .ContainerName}}
+ExecStopPost=-{{escape .Executable}} rm -f {{escape .ContainerName}}
+ExecStopPost=/usr/bin/rm -f {{.PIDFile}}
+{{- else}}
+ExecStart={{escape .Executable}} start {{escape .ContainerName}}
+ExecStop={{escape .Executable}} stop -t {{.StopTimeout}} {{escape .ContainerName}}
+{{- end}}
+KillMode=none
+Type=forking
+PIDFile={{.PIDFile}}
+
+[Install]
+WantedBy=multi-user.target default.target
+`
+
+var unit = template.Must(template.New("systemd_unit").Funcs(template.FuncMap{
+	"services": services,
+	"command":  command,
+	"escape":   escape,
+}).Parse(unitTemplate))
+
+// ValidateRestartPolicy returns an error if policy is not a restart policy
+// of systemd
+func ValidateRestartPolicy(policy string) error {
+	for _, p := range RestartPolicies {
+		if p == policy {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid restart policy %q, must be one of %s", policy, strings.Join(RestartPolicies, ", "))
+}
+
+// Generate returns the systemd service unit of the service
+func (info *ServiceInfo) Generate() (string, error) {
+	if err := ValidateRestartPolicy(info.RestartPolicy); err != nil {
+		return "", err
+	}
+	if info.PIDFile == "" {
+		return "", errors.Errorf("container %s has no conmon PID file", info.ContainerName)
+	}
+	var buf bytes.Buffer
+	if err := unit.Execute(&buf, info); err != nil {
+		return "", errors.Wrapf(err, "error generating unit %s", info.ServiceName)
+	}
+	return buf.String(), nil
+}
+
+// services returns the space-separated units of the services
+func services(names []string) string {
+	units := make([]string, 0, len(names))
+	for _, name := range names {
+		units = append(units, name+".service")
+	}
+	return strings.Join(units, " ")
+}
+
+// escape escapes the specifiers and variables of systemd in an argument of
+// a command, and quotes it if it has spaces or quotes
+func escape(arg string) string {
+	arg = strings.Replace(arg, "%", "%%", -1)
+	arg = strings.Replace(arg, "$", "$$", -1)
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\;") {
+		return arg
+	}
+	arg = strings.Replace(arg, `\`, `\\`, -1)
+	arg = strings.Replace(arg, `"`, `\"`, -1)
+	arg = strings.Replace(arg, "\n", `\n`, -1)
+	return `"` + arg + `"`
+}
+
+// command returns the escaped command line of a unit, but for the specifiers
+// of NewPIDFile
+func command(args []string) string {
+	escaped := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == NewPIDFile {
+			escaped = append(escaped, arg)
+		} else {
+			escaped = append(escaped, escape(arg))
+		}
+	}
+	return strings.Join(escaped, " ")
+}
+
+// NewCreateCommand returns the command of a unit creating the container
+// named name as it was by createCommand, run by podman at executable,
+// detached and with the conmon PID file NewPIDFile. The container ID and
+// conmon PID files it was created with are left out, as the command runs
+// again each time the unit starts.
+func NewCreateCommand(createCommand []string, executable, name string) ([]string, error) {
+	sub := -1
+	for i, arg := range createCommand {
+		if i > 0 && (arg == "run" || arg == "create") {
+			sub = i
+			break
+		}
+	}
+	if sub < 0 {
+		return nil, errors.Errorf("container %s was not created by podman run or create", name)
+	}
+
+	args := createCommand[sub+1:]
+	cmd := []string{executable}
+	cmd = append(cmd, createCommand[1:sub]...)
+	cmd = append(cmd, "run", "-d", "--conmon-pidfile", NewPIDFile)
+	if !hasName(args) {
+		// The unit stops and removes the container by name
+		cmd = append(cmd, "--name", name)
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(cmd, args[i:]...), nil
+		case arg == "--conmon-pidfile" || arg == "--cidfile":
+			i++
+		case strings.HasPrefix(arg, "--conmon-pidfile=") || strings.HasPrefix(arg, "--cidfile="):
+		default:
+			cmd = append(cmd, arg)
+		}
+	}
+	return cmd, nil
+}
+
+// hasName returns true if the arguments of podman run name the container
+func hasName(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--name" || strings.HasPrefix(arg, "--name=") {
+			return true
+		}
+	}
+	return false
+}
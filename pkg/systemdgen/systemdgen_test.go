@@ -0,0 +1,134 @@
+package systemdgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateContainer(t *testing.T) {
+	info := &ServiceInfo{
+		ServiceName:   "container-web",
+		Executable:    "/usr/bin/podman",
+		ContainerName: "web",
+		RestartPolicy: "on-failure",
+		StopTimeout:   10,
+		PIDFile:       "/var/run/containers/storage/overlay-containers/abc/userdata/conmon.pid",
+		PodmanVersion: "1.0",
+	}
+	unit, err := info.Generate()
+	require.NoError(t, err)
+	assert.Equal(t, `# container-web.service
+# autogenerated by Podman 1.0
+
+[Unit]
+Description=Podman container-web.service
+Documentation=man:podman-generate-systemd(1)
+Wants=network.target
+After=network-online.target
+
+[Service]
+Restart=on-failure
+ExecStart=/usr/bin/podman start web
+ExecStop=/usr/bin/podman stop -t 10 web
+KillMode=none
+Type=forking
+PIDFile=/var/run/containers/storage/overlay-containers/abc/userdata/conmon.pid
+
+[Install]
+WantedBy=multi-user.target default.target
+`, unit)
+
+	info.RestartPolicy = "sometimes"
+	_, err = info.Generate()
+	assert.Error(t, err)
+	info.RestartPolicy = "always"
+	info.PIDFile = ""
+	_, err = info.Generate()
+	assert.Error(t, err)
+}
+
+func TestGeneratePod(t *testing.T) {
+	pod := &ServiceInfo{
+		ServiceName:      "pod-app",
+		Executable:       "/usr/bin/podman",
+		ContainerName:    "abc-infra",
+		RestartPolicy:    "on-failure",
+		StopTimeout:      10,
+		PIDFile:          "/run/abc/conmon.pid",
+		RequiredServices: []string{"container-db", "container-web"},
+		PodmanVersion:    "1.0",
+	}
+	unit, err := pod.Generate()
+	require.NoError(t, err)
+	assert.Contains(t, unit, "\nRequires=container-db.service container-web.service\nBefore=container-db.service container-web.service\n")
+
+	ctr := &ServiceInfo{
+		ServiceName:     "container-db",
+		Executable:      "/usr/bin/podman",
+		ContainerName:   "db",
+		RestartPolicy:   "on-failure",
+		PIDFile:         "/run/db/conmon.pid",
+		BoundToServices: []string{"pod-app"},
+		PodmanVersion:   "1.0",
+	}
+	unit, err = ctr.Generate()
+	require.NoError(t, err)
+	assert.Contains(t, unit, "\nRefuseManualStart=yes\nRefuseManualStop=yes\nBindsTo=pod-app.service\nAfter=pod-app.service\n")
+}
+
+func TestGenerateNew(t *testing.T) {
+	cmd, err := NewCreateCommand([]string{"podman", "--log-level", "debug", "run", "-d", "--cidfile", "/tmp/cid", "-e", "GREETING=100%", "alpine", "sh", "-c", "echo $GREETING"}, "/usr/bin/podman", "web")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/usr/bin/podman", "--log-level", "debug", "run", "-d", "--conmon-pidfile", NewPIDFile, "--name", "web", "-d", "-e", "GREETING=100%", "alpine", "sh", "-c", "echo $GREETING"}, cmd)
+
+	info := &ServiceInfo{
+		ServiceName:   "container-web",
+		Executable:    "/usr/bin/podman",
+		ContainerName: "web",
+		RestartPolicy: "on-failure",
+		StopTimeout:   5,
+		PIDFile:       NewPIDFile,
+		CreateCommand: cmd,
+		PodmanVersion: "1.0",
+	}
+	unit, err := info.Generate()
+	require.NoError(t, err)
+	assert.Contains(t, unit, `
+ExecStartPre=/usr/bin/rm -f %t/%n-pid
+ExecStart=/usr/bin/podman --log-level debug run -d --conmon-pidfile %t/%n-pid --name web -d -e GREETING=100%% alpine sh -c "echo $$GREETING"
+ExecStop=/usr/bin/podman stop -t 5 web
+ExecStopPost=-/usr/bin/podman rm -f web
+ExecStopPost=/usr/bin/rm -f %t/%n-pid
+KillMode=none
+Type=forking
+PIDFile=%t/%n-pid
+`)
+}
+
+func TestNewCreateCommand(t *testing.T) {
+	// create is replaced by run, named containers keep their name
+	cmd, err := NewCreateCommand([]string{"podman", "create", "--name=web", "--conmon-pidfile=/tmp/pid", "alpine"}, "/usr/bin/podman", "web")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/usr/bin/podman", "run", "-d", "--conmon-pidfile", NewPIDFile, "--name=web", "alpine"}, cmd)
+
+	// Arguments after -- are left as they are
+	cmd, err = NewCreateCommand([]string{"podman", "container", "run", "--", "alpine", "--cidfile", "x"}, "/usr/bin/podman", "web")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/usr/bin/podman", "container", "run", "-d", "--conmon-pidfile", NewPIDFile, "--name", "web", "--", "alpine", "--cidfile", "x"}, cmd)
+
+	_, err = NewCreateCommand([]string{"podman", "pod", "start"}, "/usr/bin/podman", "web")
+	assert.Error(t, err)
+	_, err = NewCreateCommand(nil, "/usr/bin/podman", "web")
+	assert.Error(t, err)
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, "web", escape("web"))
+	assert.Equal(t, `""`, escape(""))
+	assert.Equal(t, `"a b"`, escape("a b"))
+	assert.Equal(t, `"say \"hi\""`, escape(`say "hi"`))
+	assert.Equal(t, "50%%", escape("50%"))
+	assert.Equal(t, "$$HOME", escape("$HOME"))
+}
@@ -0,0 +1,215 @@
+// Package trust manages the trust of images: the signature policy checked when
+// they are pulled, policy.json, and where the signatures of registries are
+// stored, registries.d.
+package trust
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containers/image/signature"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultPolicyPath is the default path of the signature policy
+	DefaultPolicyPath = "/etc/containers/policy.json"
+	// DefaultScope is the scope of the requirements of the images of no
+	// other scope
+	DefaultScope = "default"
+	// DockerTransport is the transport of the scopes of registries
+	DockerTransport = "docker"
+
+	// TypeAccept accepts any image
+	TypeAccept = "accept"
+	// TypeReject rejects any image
+	TypeReject = "reject"
+	// TypeSignedBy accepts the images signed by keys
+	TypeSignedBy = "signedBy"
+
+	// policyTypeAccept is the requirement type of TypeAccept in the policy
+	policyTypeAccept = "insecureAcceptAnything"
+	// keyTypeGPG is the type of the keys of signedBy requirements
+	keyTypeGPG = "GPGKeys"
+)
+
+// Requirement is a requirement of the signature policy an image must satisfy
+type Requirement struct {
+	Type              string          `json:"type"`
+	KeyType           string          `json:"keyType,omitempty"`
+	KeyPath           string          `json:"keyPath,omitempty"`
+	KeyData           string          `json:"keyData,omitempty"`
+	SignedIdentity    json.RawMessage `json:"signedIdentity,omitempty"`
+	BaseLayerIdentity json.RawMessage `json:"baseLayerIdentity,omitempty"`
+}
+
+// Policy is the signature policy, as written in policy.json. The requirements
+// of its transports are by scope, such as registries or repositories.
+type Policy struct {
+	Default    []Requirement                       `json:"default"`
+	Transports map[string]map[string][]Requirement `json:"transports,omitempty"`
+}
+
+// ReadPolicy reads the signature policy at path
+func ReadPolicy(path string) (*Policy, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading signature policy %s", path)
+	}
+	policy := &Policy{}
+	if err := json.Unmarshal(content, policy); err != nil {
+		return nil, errors.Wrapf(err, "error parsing signature policy %s", path)
+	}
+	return policy, nil
+}
+
+// WritePolicy writes the signature policy to path, replacing it atomically.
+// The policy is refused if containers/image does not accept it.
+func WritePolicy(path string, policy *Policy) error {
+	content, err := json.MarshalIndent(policy, "", "    ")
+	if err != nil {
+		return err
+	}
+	if _, err := signature.NewPolicyFromBytes(content); err != nil {
+		return errors.Wrapf(err, "invalid signature policy")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "error creating the directory of %s", path)
+	}
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, append(content, '\n'), 0644); err != nil {
+		return errors.Wrapf(err, "error writing %s", tmpPath)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrapf(err, "error writing %s", path)
+	}
+	return nil
+}
+
+// NewRequirements returns the requirements of a trust type: TypeAccept,
+// TypeReject, or TypeSignedBy with the paths of the GPG public keys images
+// must be signed by, each of them.
+func NewRequirements(trustType string, keyPaths []string) ([]Requirement, error) {
+	switch trustType {
+	case TypeAccept, TypeReject:
+		if len(keyPaths) > 0 {
+			return nil, errors.Errorf("public keys can only be given with trust type %s", TypeSignedBy)
+		}
+		policyType := trustType
+		if trustType == TypeAccept {
+			policyType = policyTypeAccept
+		}
+		return []Requirement{{Type: policyType}}, nil
+	case TypeSignedBy:
+		if len(keyPaths) == 0 {
+			return nil, errors.Errorf("trust type %s requires public keys", TypeSignedBy)
+		}
+		var requirements []Requirement
+		for _, keyPath := range keyPaths {
+			absPath, err := filepath.Abs(keyPath)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := os.Stat(absPath); err != nil {
+				return nil, errors.Wrapf(err, "invalid public key %s", keyPath)
+			}
+			requirements = append(requirements, Requirement{
+				Type:    TypeSignedBy,
+				KeyType: keyTypeGPG,
+				KeyPath: absPath,
+			})
+		}
+		return requirements, nil
+	default:
+		return nil, errors.Errorf("invalid trust type %q, must be %s, %s or %s", trustType, TypeAccept, TypeReject, TypeSignedBy)
+	}
+}
+
+// SetRequirements sets the requirements of the images of a registry or
+// repository scope of the docker transport, or of DefaultScope
+func (p *Policy) SetRequirements(scope string, requirements []Requirement) {
+	if scope == DefaultScope {
+		p.Default = requirements
+		return
+	}
+	if p.Transports == nil {
+		p.Transports = make(map[string]map[string][]Requirement)
+	}
+	if p.Transports[DockerTransport] == nil {
+		p.Transports[DockerTransport] = make(map[string][]Requirement)
+	}
+	p.Transports[DockerTransport][scope] = requirements
+}
+
+// Entry describes the trust of the images of a scope
+type Entry struct {
+	// Scope is the scope of the images, DefaultScope, a registry or a
+	// repository of the docker transport, or TRANSPORT:SCOPE for other
+	// transports
+	Scope string `json:"scope"`
+	// Type is the trust type of the images, or the requirement type for
+	// the requirements of no trust type
+	Type string `json:"type"`
+	// Keys are the paths of the public keys of signedBy requirements,
+	// "(inline)" for the keys written in the policy
+	Keys []string `json:"keys,omitempty"`
+	// Sigstore is where the signatures of the scope are read from
+	Sigstore string `json:"sigstore,omitempty"`
+}
+
+// Entries returns the trust of the scopes of the policy, the default first,
+// with the signature storage of the scopes of sigstores
+func (p *Policy) Entries(sigstores map[string]string) []Entry {
+	entries := []Entry{newEntry(DefaultScope, p.Default, sigstores[DefaultScope])}
+	var transports []string
+	for transport := range p.Transports {
+		transports = append(transports, transport)
+	}
+	sort.Strings(transports)
+	for _, transport := range transports {
+		var scopes []string
+		for scope := range p.Transports[transport] {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+		for _, scope := range scopes {
+			name, sigstore := scope, ""
+			if transport == DockerTransport {
+				sigstore = sigstores[scope]
+			} else {
+				name = transport + ":" + scope
+			}
+			entries = append(entries, newEntry(name, p.Transports[transport][scope], sigstore))
+		}
+	}
+	return entries
+}
+
+// newEntry returns the entry of the requirements of a scope
+func newEntry(scope string, requirements []Requirement, sigstore string) Entry {
+	entry := Entry{Scope: scope, Sigstore: sigstore}
+	var types []string
+	for _, requirement := range requirements {
+		trustType := requirement.Type
+		if trustType == policyTypeAccept {
+			trustType = TypeAccept
+		}
+		if !util.StringInSlice(trustType, types) {
+			types = append(types, trustType)
+		}
+		switch {
+		case requirement.KeyPath != "":
+			entry.Keys = append(entry.Keys, requirement.KeyPath)
+		case requirement.KeyData != "":
+			entry.Keys = append(entry.Keys, "(inline)")
+		}
+	}
+	entry.Type = strings.Join(types, ",")
+	return entry
+}
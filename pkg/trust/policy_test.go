@@ -0,0 +1,79 @@
+package trust
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequirements(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trust")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	key := filepath.Join(dir, "key.gpg")
+	require.NoError(t, ioutil.WriteFile(key, []byte("key"), 0644))
+
+	requirements, err := NewRequirements(TypeAccept, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Requirement{{Type: "insecureAcceptAnything"}}, requirements)
+
+	requirements, err = NewRequirements(TypeReject, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Requirement{{Type: "reject"}}, requirements)
+
+	requirements, err = NewRequirements(TypeSignedBy, []string{key})
+	require.NoError(t, err)
+	assert.Equal(t, []Requirement{{Type: "signedBy", KeyType: "GPGKeys", KeyPath: key}}, requirements)
+
+	_, err = NewRequirements(TypeAccept, []string{key})
+	assert.Error(t, err)
+	_, err = NewRequirements(TypeSignedBy, nil)
+	assert.Error(t, err)
+	_, err = NewRequirements(TypeSignedBy, []string{filepath.Join(dir, "missing.gpg")})
+	assert.Error(t, err)
+	_, err = NewRequirements("trusted", nil)
+	assert.Error(t, err)
+}
+
+func TestPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trust")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policy.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{
+    "default": [{"type": "insecureAcceptAnything"}],
+    "transports": {
+        "docker-daemon": {"": [{"type": "insecureAcceptAnything"}]},
+        "docker": {
+            "registry.example.com": [{"type": "signedBy", "keyType": "GPGKeys", "keyData": "a2V5", "signedIdentity": {"type": "matchRepository"}}]
+        }
+    }
+}`), 0644))
+
+	policy, err := ReadPolicy(path)
+	require.NoError(t, err)
+	policy.SetRequirements(DefaultScope, []Requirement{{Type: "reject"}})
+	policy.SetRequirements("docker.io", []Requirement{{Type: "insecureAcceptAnything"}})
+	require.NoError(t, WritePolicy(path, policy))
+
+	// The requirements of other scopes are kept
+	policy, err = ReadPolicy(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Scope: DefaultScope, Type: TypeReject},
+		{Scope: "docker.io", Type: TypeAccept},
+		{Scope: "registry.example.com", Type: TypeSignedBy, Keys: []string{"(inline)"}, Sigstore: "https://sigstore.example.com"},
+		{Scope: "docker-daemon:", Type: TypeAccept},
+	}, policy.Entries(map[string]string{"registry.example.com": "https://sigstore.example.com"}))
+	assert.JSONEq(t, `{"type": "matchRepository"}`, string(policy.Transports[DockerTransport]["registry.example.com"][0].SignedIdentity))
+
+	// Invalid policies are not written
+	policy.SetRequirements("quay.io", []Requirement{{Type: "signedBy"}})
+	assert.Error(t, WritePolicy(path, policy))
+	_, err = ReadPolicy(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,192 @@
+package trust
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultRegistriesDir is the default directory of the signature
+	// storage configuration of registries
+	DefaultRegistriesDir = "/etc/containers/registries.d"
+	// defaultRegistriesFile is the file of registries.d the signature
+	// storage of scopes defined in no other file is written to
+	defaultRegistriesFile = "default.yaml"
+)
+
+// registryNamespace is the signature storage of a scope in registries.d
+type registryNamespace struct {
+	SigStore        string `json:"sigstore,omitempty"`
+	SigStoreStaging string `json:"sigstore-staging,omitempty"`
+}
+
+// registryConfiguration is a file of registries.d
+type registryConfiguration struct {
+	DefaultDocker *registryNamespace           `json:"default-docker,omitempty"`
+	Docker        map[string]registryNamespace `json:"docker,omitempty"`
+}
+
+// readRegistriesFile reads a file of registries.d
+func readRegistriesFile(path string) (*registryConfiguration, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &registryConfiguration{}
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return nil, errors.Wrapf(err, "error parsing %s", path)
+	}
+	return config, nil
+}
+
+// registriesFiles returns the paths of the files of registries.d, which are
+// all the YAML files of dir
+func registriesFiles(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "error reading %s", dir)
+	}
+	var paths []string
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".yaml") {
+			paths = append(paths, filepath.Join(dir, info.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// readRegistriesDir reads the files of the registries.d directory dir, merged
+func readRegistriesDir(dir string) (*registryConfiguration, error) {
+	paths, err := registriesFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	merged := &registryConfiguration{Docker: make(map[string]registryNamespace)}
+	for _, path := range paths {
+		config, err := readRegistriesFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if config.DefaultDocker != nil {
+			merged.DefaultDocker = config.DefaultDocker
+		}
+		for scope, namespace := range config.Docker {
+			merged.Docker[scope] = namespace
+		}
+	}
+	return merged, nil
+}
+
+// Sigstores returns the URLs signatures are read from, by scope, of the
+// registries.d directory dir. The scope of default-docker is DefaultScope.
+func Sigstores(dir string) (map[string]string, error) {
+	config, err := readRegistriesDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sigstores := make(map[string]string)
+	if config.DefaultDocker != nil && config.DefaultDocker.SigStore != "" {
+		sigstores[DefaultScope] = config.DefaultDocker.SigStore
+	}
+	for scope, namespace := range config.Docker {
+		if namespace.SigStore != "" {
+			sigstores[scope] = namespace.SigStore
+		}
+	}
+	return sigstores, nil
+}
+
+// WriteSigstore returns the URL the signatures of the images of a docker
+// repository, such as docker.io/library/alpine, are written to: the
+// sigstore-staging, or else the sigstore, of the most specific scope of the
+// registries.d directory dir it is in. It is empty if none is configured.
+func WriteSigstore(dir, repository string) (string, error) {
+	config, err := readRegistriesDir(dir)
+	if err != nil {
+		return "", err
+	}
+	namespace := config.DefaultDocker
+	for scope := repository; ; {
+		if ns, ok := config.Docker[scope]; ok {
+			namespace = &ns
+			break
+		}
+		i := strings.LastIndex(scope, "/")
+		if i < 0 {
+			break
+		}
+		scope = scope[:i]
+	}
+	switch {
+	case namespace == nil:
+		return "", nil
+	case namespace.SigStoreStaging != "":
+		return namespace.SigStoreStaging, nil
+	default:
+		return namespace.SigStore, nil
+	}
+}
+
+// SetSigstore sets the URL signatures of the images of a scope are read from
+// in the registries.d directory dir. As a scope may only be defined in one
+// file, the file defining it is updated, or else default.yaml.
+func SetSigstore(dir, scope, sigstore string) error {
+	paths, err := registriesFiles(dir)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, defaultRegistriesFile)
+	config := &registryConfiguration{}
+	for _, p := range paths {
+		c, err := readRegistriesFile(p)
+		if err != nil {
+			return err
+		}
+		if _, ok := c.Docker[scope]; (scope == DefaultScope && c.DefaultDocker != nil) || (scope != DefaultScope && ok) {
+			path, config = p, c
+			break
+		}
+		if p == path {
+			config = c
+		}
+	}
+
+	if scope == DefaultScope {
+		if config.DefaultDocker == nil {
+			config.DefaultDocker = &registryNamespace{}
+		}
+		config.DefaultDocker.SigStore = sigstore
+	} else {
+		if config.Docker == nil {
+			config.Docker = make(map[string]registryNamespace)
+		}
+		namespace := config.Docker[scope]
+		namespace.SigStore = sigstore
+		config.Docker[scope] = namespace
+	}
+
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "error creating %s", dir)
+	}
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0644); err != nil {
+		return errors.Wrapf(err, "error writing %s", tmpPath)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrapf(err, "error writing %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package trust
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigstores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trust")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	registriesDir := filepath.Join(dir, "registries.d")
+
+	// No registries.d is no signature storage
+	sigstores, err := Sigstores(registriesDir)
+	require.NoError(t, err)
+	assert.Empty(t, sigstores)
+
+	require.NoError(t, os.MkdirAll(registriesDir, 0755))
+	example := filepath.Join(registriesDir, "example.yaml")
+	require.NoError(t, ioutil.WriteFile(example, []byte(`docker:
+  registry.example.com:
+    sigstore: https://sigstore.example.com
+    sigstore-staging: file:///var/lib/sigstore
+`), 0644))
+
+	require.NoError(t, SetSigstore(registriesDir, DefaultScope, "file:///var/lib/containers/sigstore"))
+	require.NoError(t, SetSigstore(registriesDir, "registry.example.com", "https://signatures.example.com"))
+	require.NoError(t, SetSigstore(registriesDir, "quay.io", "https://sigstore.quay.io"))
+
+	sigstores, err = Sigstores(registriesDir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		DefaultScope:           "file:///var/lib/containers/sigstore",
+		"registry.example.com": "https://signatures.example.com",
+		"quay.io":              "https://sigstore.quay.io",
+	}, sigstores)
+
+	// Scopes are updated in the file defining them, others in default.yaml
+	config, err := readRegistriesFile(example)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]registryNamespace{
+		"registry.example.com": {SigStore: "https://signatures.example.com", SigStoreStaging: "file:///var/lib/sigstore"},
+	}, config.Docker)
+	config, err = readRegistriesFile(filepath.Join(registriesDir, "default.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, &registryNamespace{SigStore: "file:///var/lib/containers/sigstore"}, config.DefaultDocker)
+	assert.Contains(t, config.Docker, "quay.io")
+}
+
+func TestWriteSigstore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trust")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sigstore, err := WriteSigstore(dir, "docker.io/library/alpine")
+	require.NoError(t, err)
+	assert.Empty(t, sigstore)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "default.yaml"), []byte(`default-docker:
+  sigstore-staging: file:///var/lib/containers/sigstore
+docker:
+  registry.example.com:
+    sigstore: https://sigstore.example.com
+  registry.example.com/team:
+    sigstore: https://sigstore.example.com/team
+    sigstore-staging: file:///srv/sigstore/team
+`), 0644))
+	for repository, expected := range map[string]string{
+		"docker.io/library/alpine":          "file:///var/lib/containers/sigstore",
+		"registry.example.com/app":          "https://sigstore.example.com",
+		"registry.example.com/team/app":     "file:///srv/sigstore/team",
+		"registry.example.com/teams/app":    "https://sigstore.example.com",
+		"registry.example.com.evil.com/app": "file:///var/lib/containers/sigstore",
+	} {
+		sigstore, err := WriteSigstore(dir, repository)
+		require.NoError(t, err)
+		assert.Equal(t, expected, sigstore, repository)
+	}
+}
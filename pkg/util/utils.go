@@ -5,8 +5,10 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/containers/image/types"
+	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/storage"
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/opencontainers/image-spec/specs-go/v1"
@@ -125,12 +127,41 @@ func GetImageConfig(changes []string) (v1.ImageConfig, error) {
 	}, nil
 }
 
-// ParseIDMapping takes idmappings and subuid and subgid maps and returns a storage mapping
-func ParseIDMapping(UIDMapSlice, GIDMapSlice []string, subUIDMap, subGIDMap string) (*storage.IDMappingOptions, error) {
+// KeepIDUserNS is the user namespace mode where the rootless user keeps its
+// UID and GID in the container
+const KeepIDUserNS = "keep-id"
+
+// AutoUserNS is the user namespace mode where root allocates to the
+// container a range of unused IDs, so that it runs as unprivileged users
+const AutoUserNS = "auto"
+
+// ParseIDMapping takes the user namespace mode, idmappings and subuid and subgid maps and returns a storage mapping
+func ParseIDMapping(usernsMode string, UIDMapSlice, GIDMapSlice []string, subUIDMap, subGIDMap string) (*storage.IDMappingOptions, error) {
 	options := storage.IDMappingOptions{
 		HostUIDMapping: true,
 		HostGIDMapping: true,
 	}
+	if usernsMode == KeepIDUserNS {
+		if len(UIDMapSlice) > 0 || len(GIDMapSlice) > 0 || subUIDMap != "" || subGIDMap != "" {
+			return nil, errors.Errorf("keep-id can not be used with custom ID mappings")
+		}
+		uids, gids, err := rootless.GetKeepIDMappings()
+		if err != nil {
+			return nil, err
+		}
+		options.UIDMap = uids
+		options.GIDMap = gids
+		options.HostUIDMapping = false
+		options.HostGIDMapping = false
+		return &options, nil
+	}
+	if usernsMode == AutoUserNS {
+		// The runtime allocates the ID mappings
+		if len(UIDMapSlice) > 0 || len(GIDMapSlice) > 0 || subUIDMap != "" || subGIDMap != "" {
+			return nil, errors.Errorf("auto can not be used with custom ID mappings")
+		}
+		return &options, nil
+	}
 	if subGIDMap == "" && subUIDMap != "" {
 		subGIDMap = subUIDMap
 	}
@@ -210,3 +241,31 @@ func ParseIDMapping(UIDMapSlice, GIDMapSlice []string, subUIDMap, subGIDMap stri
 	}
 	return &options, nil
 }
+
+// inputTimeLayouts are the layouts of the times users give, from the most
+// to the least precise
+var inputTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// ParseInputTime parses a time given by a user, either as a timestamp, a
+// date, or a duration like 10m meaning that long ago
+func ParseInputTime(inputTime string) (time.Time, error) {
+	for _, layout := range inputTimeLayouts {
+		if t, err := time.ParseInLocation(layout, inputTime, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if unix, err := strconv.ParseInt(inputTime, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	duration, err := time.ParseDuration(inputTime)
+	if err != nil {
+		return time.Time{}, errors.Errorf("unable to interpret time value %q, it must be a timestamp, a date or a duration", inputTime)
+	}
+	return time.Now().Add(-duration), nil
+}
@@ -1,8 +1,11 @@
 package util
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -17,3 +20,37 @@ func TestStringInSlice(t *testing.T) {
 	// string is not in empty slice
 	assert.False(t, StringInSlice("one", []string{}))
 }
+
+func TestParseInputTime(t *testing.T) {
+	tm, err := ParseInputTime("2018-10-01T12:30:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2018, 10, 1, 12, 30, 0, 0, time.UTC), tm.UTC())
+
+	tm, err = ParseInputTime("2018-10-01")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2018, 10, 1, 0, 0, 0, 0, time.Local), tm)
+
+	tm, err = ParseInputTime("1538397000")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1538397000), tm.Unix())
+
+	tm, err = ParseInputTime("10m")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-10*time.Minute), tm, time.Minute)
+
+	_, err = ParseInputTime("yesterday")
+	assert.Error(t, err)
+}
+
+func TestParseIDMappingAuto(t *testing.T) {
+	options, err := ParseIDMapping(AutoUserNS, nil, nil, "", "")
+	require.NoError(t, err)
+	assert.True(t, options.HostUIDMapping)
+	assert.True(t, options.HostGIDMapping)
+	assert.Empty(t, options.UIDMap)
+
+	_, err = ParseIDMapping(AutoUserNS, []string{"0:100000:65536"}, nil, "", "")
+	assert.Error(t, err)
+	_, err = ParseIDMapping(AutoUserNS, nil, nil, "containers", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package varlinkapi
+
+import (
+	"encoding/json"
+	"time"
+
+	iopodman "github.com/containers/libpod/cmd/podman/varlink"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/audit"
+	"github.com/sirupsen/logrus"
+	"github.com/varlink/go/varlink"
+)
+
+// auditedMethods are the methods modifying containers, pods or images, whose
+// calls are recorded in the audit log
+var auditedMethods = map[string]bool{
+	"ReconcileFirewall":       true,
+	"CreateContainer":         true,
+	"StartContainer":          true,
+	"StopContainer":           true,
+	"StopContainers":          true,
+	"RestartContainer":        true,
+	"RestartContainers":       true,
+	"KillContainer":           true,
+	"KillContainers":          true,
+	"UpdateContainer":         true,
+	"RenameContainer":         true,
+	"PauseContainer":          true,
+	"UnpauseContainer":        true,
+	"AttachToContainer":       true,
+	"RemoveContainer":         true,
+	"DeleteStoppedContainers": true,
+	"BuildImage":              true,
+	"CreateImage":             true,
+	"PushImage":               true,
+	"TagImage":                true,
+	"RemoveImage":             true,
+	"DeleteUnusedImages":      true,
+	"Commit":                  true,
+	"ImportImage":             true,
+	"PullImage":               true,
+	"CreatePod":               true,
+	"StartPod":                true,
+	"StopPod":                 true,
+	"RestartPod":              true,
+	"KillPod":                 true,
+	"PausePod":                true,
+	"UnpausePod":              true,
+	"RemovePod":               true,
+}
+
+// AuditedInterface is the varlink interface of libpod, recording the calls of
+// the methods modifying containers, pods or images in the audit log
+type AuditedInterface struct {
+	*iopodman.VarlinkInterface
+	runtime *libpod.Runtime
+}
+
+// VarlinkDispatch dispatches a call to its method and records it in the audit
+// log, with the identity of the caller, once served
+func (i *AuditedInterface) VarlinkDispatch(call varlink.Call, methodname string) error {
+	var auditLog *audit.Log
+	if auditedMethods[methodname] && i.runtime != nil {
+		auditLog = i.runtime.AuditLog()
+	}
+	if auditLog == nil {
+		return i.VarlinkInterface.VarlinkDispatch(call, methodname)
+	}
+	record := audit.Record{
+		Time:      time.Now(),
+		Source:    audit.SourceVarlink,
+		UID:       audit.UnknownUID,
+		Operation: "io.podman." + methodname,
+	}
+	if uid, pid, err := callerCredentials(call); err != nil {
+		logrus.Debugf("Unable to get the credentials of the varlink client: %v", err)
+	} else {
+		record.UID = uid
+		record.PID = pid
+	}
+	var params json.RawMessage
+	if err := call.GetParameters(&params); err == nil {
+		record.Target = callTarget(params)
+	}
+	record.ArgsHash = audit.HashArgs(string(params))
+
+	err := i.VarlinkInterface.VarlinkDispatch(call, methodname)
+	if err := auditLog.Write(record); err != nil {
+		logrus.Errorf("Unable to write audit record of %s: %v", record.Operation, err)
+	}
+	return err
+}
+
+// callTarget returns the name of the container, pod or image a call is applied
+// to, the name parameter of the call or the name of what it creates
+func callTarget(params json.RawMessage) []string {
+	var args struct {
+		Name   string `json:"name"`
+		Create struct {
+			Name string `json:"name"`
+		} `json:"create"`
+	}
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil
+	}
+	if args.Name != "" {
+		return []string{args.Name}
+	}
+	if args.Create.Name != "" {
+		return []string{args.Create.Name}
+	}
+	return nil
+}
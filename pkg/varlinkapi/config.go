@@ -14,7 +14,7 @@ type LibpodAPI struct {
 }
 
 // New creates a new varlink client
-func New(cli *cli.Context, runtime *libpod.Runtime) *iopodman.VarlinkInterface {
+func New(cli *cli.Context, runtime *libpod.Runtime) *AuditedInterface {
 	lp := LibpodAPI{Cli: cli, Runtime: runtime}
-	return iopodman.VarlinkNew(&lp)
+	return &AuditedInterface{VarlinkInterface: iopodman.VarlinkNew(&lp), runtime: runtime}
 }
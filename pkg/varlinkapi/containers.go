@@ -2,6 +2,7 @@ package varlinkapi
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -124,12 +125,17 @@ func (i *LibpodAPI) GetContainerLogs(call iopodman.VarlinkCall, name string) err
 			return call.ReplyGetContainerLogs(logs)
 		}
 	}
-	file, err := os.Open(logPath)
-	if err != nil {
-		return errors.Wrapf(err, "unable to read container log file")
+	// The rotated segments of the log are read before the log file
+	var readers []io.Reader
+	var file *os.File
+	for _, path := range ctr.LogPaths() {
+		if file, err = os.Open(path); err != nil {
+			return errors.Wrapf(err, "unable to read container log file")
+		}
+		defer file.Close()
+		readers = append(readers, file)
 	}
-	defer file.Close()
-	reader := bufio.NewReader(file)
+	reader := bufio.NewReader(io.MultiReader(readers...))
 	if call.WantsMore() {
 		call.Continues = true
 	}
@@ -153,6 +159,18 @@ func (i *LibpodAPI) GetContainerLogs(call iopodman.VarlinkCall, name string) err
 				if state != libpod.ContainerStateRunning && state != libpod.ContainerStatePaused {
 					return call.ReplyErrorOccurred(fmt.Sprintf("%s is no longer running", ctr.ID()))
 				}
+				// Follow the log file, from its start once it is
+				// truncated by a rotation
+				offset, err := file.Seek(0, io.SeekCurrent)
+				if err != nil {
+					return call.ReplyErrorOccurred(err.Error())
+				}
+				if info, err := file.Stat(); err == nil && info.Size() < offset {
+					if _, err := file.Seek(0, io.SeekStart); err != nil {
+						return call.ReplyErrorOccurred(err.Error())
+					}
+				}
+				reader.Reset(file)
 
 			}
 		} else if err != nil {
@@ -205,29 +223,41 @@ func (i *LibpodAPI) GetContainerStats(call iopodman.VarlinkCall, name string) er
 	if err != nil {
 		return call.ReplyContainerNotFound(name)
 	}
-	containerStats, err := ctr.GetContainerStats(&libpod.ContainerStats{})
-	if err != nil {
-		if errors.Cause(err) == libpod.ErrCtrStateInvalid {
-			return call.ReplyNoContainerRunning()
+	if !call.WantsMore() {
+		containerStats, err := ctr.GetContainerStats(&libpod.ContainerStats{})
+		if err != nil {
+			if errors.Cause(err) == libpod.ErrCtrStateInvalid {
+				return call.ReplyNoContainerRunning()
+			}
+			return call.ReplyErrorOccurred(err.Error())
 		}
+		return call.ReplyGetContainerStats(makeContainerStats(ctr.ID(), containerStats))
+	}
+
+	// Stream the stats every second, ending with NoContainerRunning once
+	// the container stops, as GetContainerLogs does when following logs
+	ctx, cancel := context.WithCancel(getContext())
+	defer cancel()
+	statsChan := make(chan *libpod.ContainerStats)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- ctr.StreamStats(ctx, time.Second, statsChan)
+		close(statsChan)
+	}()
+	call.Continues = true
+	for stats := range statsChan {
+		if err := call.ReplyGetContainerStats(makeContainerStats(ctr.ID(), stats)); err != nil {
+			cancel()
+			for range statsChan {
+			}
+			return err
+		}
+	}
+	call.Continues = false
+	if err := <-streamErr; err != nil && errors.Cause(err) != libpod.ErrCtrStateInvalid {
 		return call.ReplyErrorOccurred(err.Error())
 	}
-	cs := iopodman.ContainerStats{
-		Id:           ctr.ID(),
-		Name:         ctr.Name(),
-		Cpu:          containerStats.CPU,
-		Cpu_nano:     int64(containerStats.CPUNano),
-		System_nano:  int64(containerStats.SystemNano),
-		Mem_usage:    int64(containerStats.MemUsage),
-		Mem_limit:    int64(containerStats.MemLimit),
-		Mem_perc:     containerStats.MemPerc,
-		Net_input:    int64(containerStats.NetInput),
-		Net_output:   int64(containerStats.NetOutput),
-		Block_input:  int64(containerStats.BlockInput),
-		Block_output: int64(containerStats.BlockOutput),
-		Pids:         int64(containerStats.PIDs),
-	}
-	return call.ReplyGetContainerStats(cs)
+	return call.ReplyNoContainerRunning()
 }
 
 // ResizeContainerTty ...
@@ -266,6 +296,25 @@ func (i *LibpodAPI) StopContainer(call iopodman.VarlinkCall, name string, timeou
 	return call.ReplyStopContainer(ctr.ID())
 }
 
+// StopContainers stops all running containers matching the given filters,
+// replying with the errors of those it could not stop
+func (i *LibpodAPI) StopContainers(call iopodman.VarlinkCall, filters []string, timeout int64) error {
+	ctrs, err := i.getFilteredContainers(filters, true)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	stopped := []string{}
+	ctrErrs := []iopodman.ContainerErrorData{}
+	for _, ctr := range ctrs {
+		if err := ctr.StopWithTimeout(uint(timeout)); err != nil && err != libpod.ErrCtrStopped {
+			ctrErrs = append(ctrErrs, iopodman.ContainerErrorData{Containerid: ctr.ID(), Reason: err.Error()})
+			continue
+		}
+		stopped = append(stopped, ctr.ID())
+	}
+	return call.ReplyStopContainers(stopped, ctrErrs)
+}
+
 // RestartContainer ...
 func (i *LibpodAPI) RestartContainer(call iopodman.VarlinkCall, name string, timeout int64) error {
 	ctr, err := i.Runtime.LookupContainer(name)
@@ -278,6 +327,25 @@ func (i *LibpodAPI) RestartContainer(call iopodman.VarlinkCall, name string, tim
 	return call.ReplyRestartContainer(ctr.ID())
 }
 
+// RestartContainers restarts all containers matching the given filters,
+// replying with the errors of those it could not restart
+func (i *LibpodAPI) RestartContainers(call iopodman.VarlinkCall, filters []string, timeout int64) error {
+	ctrs, err := i.getFilteredContainers(filters, false)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	restarted := []string{}
+	ctrErrs := []iopodman.ContainerErrorData{}
+	for _, ctr := range ctrs {
+		if err := ctr.RestartWithTimeout(getContext(), uint(timeout)); err != nil {
+			ctrErrs = append(ctrErrs, iopodman.ContainerErrorData{Containerid: ctr.ID(), Reason: err.Error()})
+			continue
+		}
+		restarted = append(restarted, ctr.ID())
+	}
+	return call.ReplyRestartContainers(restarted, ctrErrs)
+}
+
 // KillContainer kills a running container.  If you want to use the default SIGTERM signal, just send a -1
 // for the signal arg.
 func (i *LibpodAPI) KillContainer(call iopodman.VarlinkCall, name string, signal int64) error {
@@ -295,6 +363,29 @@ func (i *LibpodAPI) KillContainer(call iopodman.VarlinkCall, name string, signal
 	return call.ReplyKillContainer(ctr.ID())
 }
 
+// KillContainers kills all running containers matching the given filters, replying with the errors of those
+// it could not kill.  If you want to use the default SIGTERM signal, just send a -1 for the signal arg.
+func (i *LibpodAPI) KillContainers(call iopodman.VarlinkCall, filters []string, signal int64) error {
+	killSignal := uint(syscall.SIGTERM)
+	if signal != -1 {
+		killSignal = uint(signal)
+	}
+	ctrs, err := i.getFilteredContainers(filters, true)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	killed := []string{}
+	ctrErrs := []iopodman.ContainerErrorData{}
+	for _, ctr := range ctrs {
+		if err := ctr.Kill(killSignal); err != nil {
+			ctrErrs = append(ctrErrs, iopodman.ContainerErrorData{Containerid: ctr.ID(), Reason: err.Error()})
+			continue
+		}
+		killed = append(killed, ctr.ID())
+	}
+	return call.ReplyKillContainers(killed, ctrErrs)
+}
+
 // UpdateContainer ...
 func (i *LibpodAPI) UpdateContainer(call iopodman.VarlinkCall) error {
 	return call.ReplyMethodNotImplemented("UpdateContainer")
@@ -349,6 +440,34 @@ func (i *LibpodAPI) WaitContainer(call iopodman.VarlinkCall, name string) error
 
 }
 
+// WaitContainerRemoved ...
+func (i *LibpodAPI) WaitContainerRemoved(call iopodman.VarlinkCall, name string) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	exitCode, err := ctr.WaitRemoved()
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyWaitContainerRemoved(int64(exitCode))
+}
+
+// HealthCheckRun runs the healthcheck of a container once
+func (i *LibpodAPI) HealthCheckRun(call iopodman.VarlinkCall, nameOrID string) error {
+	status, err := i.Runtime.HealthCheck(nameOrID)
+	if err != nil {
+		if status == libpod.HealthCheckContainerNotFound {
+			return call.ReplyContainerNotFound(nameOrID)
+		}
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	if status == libpod.HealthCheckFailure {
+		return call.ReplyHealthCheckRun(libpod.HealthCheckUnhealthy)
+	}
+	return call.ReplyHealthCheckRun(libpod.HealthCheckHealthy)
+}
+
 // RemoveContainer ...
 func (i *LibpodAPI) RemoveContainer(call iopodman.VarlinkCall, name string, force bool) error {
 	ctx := getContext()
@@ -12,6 +12,7 @@ import (
 	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/libpod/image"
 	"github.com/containers/libpod/pkg/inspect"
+	"github.com/containers/libpod/pkg/rootless"
 	cc "github.com/containers/libpod/pkg/spec"
 	"github.com/containers/libpod/pkg/util"
 	"github.com/docker/docker/api/types/container"
@@ -71,7 +72,7 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 		blkioWeight                                              uint16
 	)
 
-	idmappings, err := util.ParseIDMapping(create.Uidmap, create.Gidmap, create.Subuidname, create.Subgidname)
+	idmappings, err := util.ParseIDMapping(create.Userns_mode, create.Uidmap, create.Gidmap, create.Subuidname, create.Subgidname)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +115,11 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 
 	user := create.User
 	if user == "" {
-		user = data.ContainerConfig.User
+		if create.Userns_mode == util.KeepIDUserNS {
+			user = fmt.Sprintf("%d:%d", rootless.GetRootlessUID(), rootless.GetRootlessGID())
+		} else {
+			user = data.ContainerConfig.User
+		}
 	}
 
 	// EXPOSED PORTS
@@ -123,11 +128,19 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 		return nil, err
 	}
 
+	// LABELS
+	// Labels given by the caller override labels inherited from the image
+	labels := cc.MergeLabels(data.ContainerConfig.Labels, create.Labels)
+
 	// NETWORK MODE
 	networkMode := create.Net_mode
 	if networkMode == "" {
 		networkMode = "bridge"
+		if rtc := runtime.GetConfig(); rtc != nil && rtc.NetworkMode != "" {
+			networkMode = rtc.NetworkMode
+		}
 	}
+	networkMode, networkOptions := cc.ParseNetworkMode(networkMode)
 
 	// WORKING DIR
 	workDir := create.Work_dir
@@ -139,6 +152,7 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 	config := &cc.CreateConfig{
 		Runtime:           runtime,
 		BuiltinImgVolumes: data.ContainerConfig.Volumes,
+		ImageLabels:       data.ContainerConfig.Labels,
 		ConmonPidFile:     create.Conmon_pidfile,
 		ImageVolumeType:   create.Image_volume_type,
 		CapAdd:            create.Cap_add,
@@ -159,13 +173,14 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 		Image:             imageName,
 		ImageID:           imageID,
 		Interactive:       create.Interactive,
-		Labels:            create.Labels,
+		Labels:            labels,
 		LogDriver:         create.Log_driver,
 		LogDriverOpt:      create.Log_driver_opt,
 		Name:              create.Name,
 		Network:           networkMode,
 		IpcMode:           container.IpcMode(create.Ipc_mode),
 		NetMode:           container.NetworkMode(networkMode),
+		NetworkOptions:    networkOptions,
 		UtsMode:           container.UTSMode(create.Uts_mode),
 		PidMode:           container.PidMode(create.Pid_mode),
 		Pod:               create.Pod,
@@ -213,6 +228,11 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 		Volumes:     create.Volumes,
 		WorkDir:     workDir,
 	}
+	if config.Privileged {
+		if config.PrivilegedProfile, err = cc.NewPrivilegedProfile(nil); err != nil {
+			return nil, err
+		}
+	}
 
 	return config, nil
 }
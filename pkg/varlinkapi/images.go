@@ -249,7 +249,7 @@ func (i *LibpodAPI) BuildImage(call iopodman.VarlinkCall, config iopodman.BuildI
 func build(runtime *libpod.Runtime, options imagebuildah.BuildOptions, dockerfiles []string) chan error {
 	c := make(chan error)
 	go func() {
-		err := runtime.Build(getContext(), options, dockerfiles...)
+		err := runtime.Build(getContext(), options, nil, dockerfiles...)
 		c <- err
 		close(c)
 	}()
@@ -446,6 +446,10 @@ func (i *LibpodAPI) Commit(call iopodman.VarlinkCall, name, imageName string, ch
 	if err != nil {
 		return call.ReplyErrorOccurred(err.Error())
 	}
+	// Images committed to other transports are not in local storage
+	if newImage == nil {
+		return call.ReplyCommit("")
+	}
 	return call.ReplyCommit(newImage.ID())
 }
 
@@ -0,0 +1,47 @@
+package varlinkapi
+
+import (
+	"time"
+
+	"github.com/containers/libpod/cmd/podman/varlink"
+	"github.com/containers/libpod/libpod"
+)
+
+// ReserveName reserves a name for a container or pod created later
+func (i *LibpodAPI) ReserveName(call iopodman.VarlinkCall, name string) error {
+	reservation, err := i.Runtime.ReserveName(name)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyReserveName(makeNameReservation(reservation))
+}
+
+// ReleaseName releases the reservation of a name
+func (i *LibpodAPI) ReleaseName(call iopodman.VarlinkCall, name, token string, force bool) error {
+	if err := i.Runtime.ReleaseName(name, token, force); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyReleaseName()
+}
+
+// ListNameReservations returns the reservations of names
+func (i *LibpodAPI) ListNameReservations(call iopodman.VarlinkCall) error {
+	reservations, err := i.Runtime.NameReservations()
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	listReservations := make([]iopodman.NameReservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		listReservations = append(listReservations, makeNameReservation(reservation))
+	}
+	return call.ReplyListNameReservations(listReservations)
+}
+
+// makeNameReservation converts a name reservation to its varlink type
+func makeNameReservation(reservation *libpod.NameReservation) iopodman.NameReservation {
+	return iopodman.NameReservation{
+		Name:    reservation.Name,
+		Token:   reservation.Token,
+		Created: reservation.Created.Format(time.RFC3339Nano),
+	}
+}
@@ -0,0 +1,60 @@
+// +build linux
+
+package varlinkapi
+
+import (
+	"net"
+	"reflect"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"github.com/varlink/go/varlink"
+	"golang.org/x/sys/unix"
+)
+
+// callConn returns the connection of the client of a call. The varlink
+// library does not expose it: it is the connection the buffered writer of the
+// call writes replies to.
+func callConn(call varlink.Call) (net.Conn, error) {
+	writer := reflect.ValueOf(&call).Elem().FieldByName("writer")
+	if !writer.IsValid() || writer.Kind() != reflect.Ptr || writer.IsNil() {
+		return nil, errors.Errorf("varlink call has no writer")
+	}
+	wr := writer.Elem().FieldByName("wr")
+	if !wr.IsValid() || wr.Kind() != reflect.Interface || wr.IsNil() {
+		return nil, errors.Errorf("writer of varlink call has no connection")
+	}
+	conn, ok := reflect.NewAt(wr.Type(), unsafe.Pointer(wr.UnsafeAddr())).Elem().Interface().(net.Conn)
+	if !ok {
+		return nil, errors.Errorf("writer of varlink call does not write to a connection")
+	}
+	return conn, nil
+}
+
+// callerCredentials returns the UID and PID of the client of a call over a
+// unix socket
+func callerCredentials(call varlink.Call) (int, int, error) {
+	conn, err := callConn(call)
+	if err != nil {
+		return 0, 0, err
+	}
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, 0, errors.Errorf("varlink client is not connected over a unix socket")
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return 0, 0, err
+	}
+	var cred *unix.Ucred
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return 0, 0, err
+	}
+	if credErr != nil {
+		return 0, 0, credErr
+	}
+	return int(cred.Uid), int(cred.Pid), nil
+}
@@ -0,0 +1,83 @@
+// +build linux
+
+package varlinkapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/varlink/go/varlink"
+)
+
+// credentialsInterface is a varlink interface replying to its calls with the
+// credentials of their client
+type credentialsInterface struct{}
+
+func (credentialsInterface) VarlinkDispatch(call varlink.Call, methodname string) error {
+	uid, pid, err := callerCredentials(call)
+	if err != nil {
+		return call.ReplyError("org.example.credentials.Error", map[string]string{"reason": err.Error()})
+	}
+	return call.Reply(map[string]int{"uid": uid, "pid": pid})
+}
+
+func (credentialsInterface) VarlinkGetName() string {
+	return "org.example.credentials"
+}
+
+func (credentialsInterface) VarlinkGetDescription() string {
+	return "interface org.example.credentials\nmethod Get() -> (uid: int, pid: int)\n"
+}
+
+// TestCallerCredentials checks that the credentials of clients are found in
+// the calls of the vendored varlink library
+func TestCallerCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "varlinkapi")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	address := "unix:" + filepath.Join(dir, "socket")
+
+	service, err := varlink.NewService("Test", "varlinkapi", "1", "https://github.com/containers/libpod")
+	require.NoError(t, err)
+	require.NoError(t, service.RegisterInterface(credentialsInterface{}))
+	go service.Listen(address, 0)
+	defer service.Shutdown()
+
+	var conn *varlink.Connection
+	for i := 0; i < 100; i++ {
+		if conn, err = varlink.NewConnection(address); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var reply struct {
+		UID int `json:"uid"`
+		PID int `json:"pid"`
+	}
+	require.NoError(t, conn.Call("org.example.credentials.Get", json.RawMessage("{}"), &reply))
+	assert.Equal(t, os.Getuid(), reply.UID)
+	assert.Equal(t, os.Getpid(), reply.PID)
+}
+
+func TestCallTarget(t *testing.T) {
+	for _, tc := range []struct {
+		params string
+		target []string
+	}{
+		{`{"name": "web", "timeout": 10}`, []string{"web"}},
+		{`{"create": {"name": "db", "image": "alpine"}}`, []string{"db"}},
+		{`{"filters": ["status=running"]}`, nil},
+		{`[]`, nil},
+	} {
+		assert.Equal(t, tc.target, callTarget(json.RawMessage(tc.params)), tc.params)
+	}
+}
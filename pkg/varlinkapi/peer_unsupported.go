@@ -0,0 +1,14 @@
+// +build !linux
+
+package varlinkapi
+
+import (
+	"github.com/pkg/errors"
+	"github.com/varlink/go/varlink"
+)
+
+// callerCredentials returns an error, the credentials of clients are only
+// available on Linux
+func callerCredentials(call varlink.Call) (int, int, error) {
+	return 0, 0, errors.Errorf("the credentials of varlink clients are only available on Linux")
+}
@@ -218,21 +218,7 @@ func (i *LibpodAPI) GetPodStats(call iopodman.VarlinkCall, name string) error {
 	}
 	containersStats := make([]iopodman.ContainerStats, 0)
 	for ctrID, containerStats := range podStats {
-		cs := iopodman.ContainerStats{
-			Id:           ctrID,
-			Name:         containerStats.Name,
-			Cpu:          containerStats.CPU,
-			Cpu_nano:     int64(containerStats.CPUNano),
-			System_nano:  int64(containerStats.SystemNano),
-			Mem_usage:    int64(containerStats.MemUsage),
-			Mem_limit:    int64(containerStats.MemLimit),
-			Mem_perc:     containerStats.MemPerc,
-			Net_input:    int64(containerStats.NetInput),
-			Net_output:   int64(containerStats.NetOutput),
-			Block_input:  int64(containerStats.BlockInput),
-			Block_output: int64(containerStats.BlockOutput),
-			Pids:         int64(containerStats.PIDs),
-		}
+		cs := makeContainerStats(ctrID, containerStats)
 		containersStats = append(containersStats, cs)
 	}
 	return call.ReplyGetPodStats(pod.ID(), containersStats)
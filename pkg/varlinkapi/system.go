@@ -80,3 +80,11 @@ func (i *LibpodAPI) GetInfo(call iopodman.VarlinkCall) error {
 	podmanInfo.Podman = pmaninfo
 	return call.ReplyGetInfo(podmanInfo)
 }
+
+// ReconcileFirewall adds the firewall rules of all running containers again
+func (i *LibpodAPI) ReconcileFirewall(call iopodman.VarlinkCall) error {
+	if err := i.Runtime.ReconcileFirewall(); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyReconcileFirewall()
+}
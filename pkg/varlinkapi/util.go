@@ -78,6 +78,26 @@ func makeListContainer(containerID string, batchInfo shared.BatchContainerStruct
 	return lc
 }
 
+// makeContainerStats returns the varlink stats of a container
+func makeContainerStats(containerID string, stats *libpod.ContainerStats) iopodman.ContainerStats {
+	return iopodman.ContainerStats{
+		Id:           containerID,
+		Name:         stats.Name,
+		Cpu:          stats.CPU,
+		Cpu_nano:     int64(stats.CPUNano),
+		System_nano:  int64(stats.SystemNano),
+		Mem_usage:    int64(stats.MemUsage),
+		Mem_limit:    int64(stats.MemLimit),
+		Mem_perc:     stats.MemPerc,
+		Net_input:    int64(stats.NetInput),
+		Net_output:   int64(stats.NetOutput),
+		Block_input:  int64(stats.BlockInput),
+		Block_output: int64(stats.BlockOutput),
+		Pids:         int64(stats.PIDs),
+		Pids_limit:   int64(stats.PIDsLimit),
+	}
+}
+
 func makeListPodContainers(containerID string, batchInfo shared.BatchContainerStruct) iopodman.ListPodContainerInfo {
 	lc := iopodman.ListPodContainerInfo{
 		Id:     containerID,
@@ -133,3 +153,19 @@ func handlePodCall(call iopodman.VarlinkCall, pod *libpod.Pod, ctrErrs map[strin
 
 	return nil
 }
+
+// getFilteredContainers returns the containers matching the given ps-style
+// filters. If running is true, only running containers are returned.
+func (i *LibpodAPI) getFilteredContainers(filters []string, running bool) ([]*libpod.Container, error) {
+	filterFuncs, err := shared.ParseContainerFilters(filters, i.Runtime)
+	if err != nil {
+		return nil, err
+	}
+	if running {
+		filterFuncs = append(filterFuncs, func(c *libpod.Container) bool {
+			state, _ := c.State()
+			return state == libpod.ContainerStateRunning
+		})
+	}
+	return i.Runtime.GetContainers(filterFuncs...)
+}
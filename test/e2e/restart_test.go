@@ -136,4 +136,10 @@ var _ = Describe("Podman restart", func() {
 		Expect(timeSince < 10*time.Second).To(BeTrue())
 		Expect(timeSince > 2*time.Second).To(BeTrue())
 	})
+
+	It("podman restart with --latest and --filter", func() {
+		session := podmanTest.Podman([]string{"restart", "--latest", "--filter", "label=app=web"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(125))
+	})
 })
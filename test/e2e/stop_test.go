@@ -89,4 +89,29 @@ var _ = Describe("Podman stop", func() {
 		session.WaitWithDefaultTimeout()
 		Expect(session.ExitCode()).To(Equal(0))
 	})
+	It("podman stop containers matching filter", func() {
+		session := podmanTest.Podman([]string{"run", "-d", "--name", "test1", "--label", "app=web", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		cid1 := session.OutputToString()
+
+		session = podmanTest.RunTopContainer("test2")
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		cid2 := session.OutputToString()
+
+		session = podmanTest.Podman([]string{"stop", "--filter", "label=app=web", "-t", "1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		output := session.OutputToString()
+		Expect(output).To(ContainSubstring(cid1))
+		Expect(output).To(Not(ContainSubstring(cid2)))
+		Expect(podmanTest.NumberOfContainersRunning()).To(Equal(1))
+	})
+
+	It("podman stop with invalid filter", func() {
+		session := podmanTest.Podman([]string{"stop", "--filter", "foo=bar"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(125))
+	})
 })
@@ -72,6 +72,29 @@ func RunUnderSystemdScope(pid int, slice string, unitName string) error {
 	return nil
 }
 
+// SetSystemdUnitCpuset sets the AllowedCPUs and AllowedMemoryNodes properties
+// of a running systemd unit to the given bitmasks, so systemd keeps the cpuset
+// of its cgroup. Empty bitmasks are left unchanged.
+func SetSystemdUnitCpuset(unitName string, cpus, mems []byte) error {
+	var properties []systemdDbus.Property
+	if len(cpus) > 0 {
+		properties = append(properties, newProp("AllowedCPUs", cpus))
+	}
+	if len(mems) > 0 {
+		properties = append(properties, newProp("AllowedMemoryNodes", mems))
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	conn, err := systemdDbus.New()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.SetUnitProperties(unitName, true, properties...)
+}
+
 func newProp(name string, units interface{}) systemdDbus.Property {
 	return systemdDbus.Property{
 		Name:  name,